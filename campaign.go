@@ -0,0 +1,133 @@
+package capsule
+
+import (
+	"context"
+
+	"github.com/smileynet/capsule/internal/campaign"
+	"github.com/smileynet/capsule/internal/state"
+)
+
+// Campaign types. Like the pipeline types, these are stable aliases of the
+// types the CLI uses.
+type (
+	// CampaignConfig holds campaign settings.
+	CampaignConfig = campaign.Config
+	// CampaignCallback receives campaign lifecycle events.
+	CampaignCallback = campaign.Callback
+	// TaskFailureObserver is an optional CampaignCallback extension that
+	// receives a failed task's TaskResult with the phases that ran.
+	TaskFailureObserver = campaign.TaskFailureObserver
+	// TaskSkipObserver is an optional CampaignCallback extension that is
+	// told about each task skipped without running, with its reason.
+	TaskSkipObserver = campaign.TaskSkipObserver
+	// ValidationPhaseObserver is an optional CampaignCallback extension
+	// that receives each validation phase's status.
+	ValidationPhaseObserver = campaign.ValidationPhaseObserver
+	// BreakerTripObserver is an optional CampaignCallback extension that
+	// receives the full BreakerTrip when the circuit breaker stops a campaign.
+	BreakerTripObserver = campaign.BreakerTripObserver
+	// ParentCloseObserver is an optional CampaignCallback extension that is
+	// told when CampaignConfig.CloseParent closed the parent bead.
+	ParentCloseObserver = campaign.ParentCloseObserver
+	// CampaignPipeline runs a task's pipeline; *Pipeline satisfies it.
+	CampaignPipeline = campaign.PipelineRunner
+	// CampaignStateStore persists campaign state between runs.
+	CampaignStateStore = campaign.StateStore
+	// CampaignState is the saved state of a campaign.
+	CampaignState = campaign.State
+	// CampaignStatus is the state of a campaign.
+	CampaignStatus = campaign.CampaignStatus
+	// TaskResult records the outcome of a single task.
+	TaskResult = campaign.TaskResult
+	// TaskStatus is the state of a task within a campaign.
+	TaskStatus = campaign.TaskStatus
+	// Completion summarizes a finished campaign for CampaignConfig.CompleteFunc.
+	Completion = campaign.Completion
+	// CircuitBreaker holds the failure thresholds that stop a campaign.
+	CircuitBreaker = campaign.CircuitBreaker
+	// BreakerMode selects which failures the circuit breaker counts.
+	BreakerMode = campaign.BreakerMode
+	// BreakerTrip describes why the circuit breaker stopped a campaign.
+	BreakerTrip = campaign.BreakerTrip
+	// FailureRecord is one task failure in the circuit breaker's history.
+	FailureRecord = campaign.FailureRecord
+	// FailureCounts tallies task failures by kind.
+	FailureCounts = campaign.FailureCounts
+	// BeadClient reads and updates beads for a campaign.
+	BeadClient = campaign.BeadClient
+	// BeadCommenter is an optional BeadClient extension used to comment on
+	// the parent bead when feature validation fails.
+	BeadCommenter = campaign.BeadCommenter
+	// BeadInfo holds the bead metadata a campaign sequences tasks by.
+	BeadInfo = campaign.BeadInfo
+	// BeadInput holds the fields for a bead filed from a finding.
+	BeadInput = campaign.BeadInput
+	// WorklogAppender records validation results in a parent's worklog.
+	WorklogAppender = campaign.WorklogAppender
+)
+
+// Circuit breaker modes.
+const (
+	BreakerConsecutive = campaign.BreakerConsecutive
+	BreakerTotal       = campaign.BreakerTotal
+)
+
+// Campaign failure modes.
+const (
+	FailureAbort          = campaign.FailureAbort
+	FailureStop           = campaign.FailureStop
+	FailureContinue       = campaign.FailureContinue
+	FailureSkipDependents = campaign.FailureSkipDependents
+)
+
+// Campaign task ordering policies.
+const (
+	OrderPriority         = campaign.OrderPriority
+	OrderTypeThenPriority = campaign.OrderTypeThenPriority
+	OrderAsListed         = campaign.OrderAsListed
+)
+
+// Campaign errors, for use with errors.Is.
+var (
+	ErrNoTasks         = campaign.ErrNoTasks
+	ErrCampaignPaused  = campaign.ErrCampaignPaused
+	ErrCampaignAborted = campaign.ErrCampaignAborted
+	ErrCircuitBroken   = campaign.ErrCircuitBroken
+)
+
+// NewCampaignStore returns a CampaignStateStore that keeps one JSON file
+// per campaign in dir. The CLI uses .capsule/campaigns.
+func NewCampaignStore(dir string) CampaignStateStore {
+	return state.NewFileStore(dir)
+}
+
+// NewCheckpointStore returns a CheckpointStore that keeps one JSON file per
+// bead in dir. The CLI uses .capsule/checkpoints.
+func NewCheckpointStore(dir string) CheckpointStore {
+	return state.NewCheckpointFileStore(dir)
+}
+
+// Campaign runs every ready child of a feature or epic bead through a
+// pipeline, in dependency order, recursing into child features and epics.
+type Campaign struct {
+	runner *campaign.Runner
+}
+
+// NewCampaign returns a Campaign that runs tasks with p, reads and closes
+// beads with beads, and saves its progress to store.
+func NewCampaign(p CampaignPipeline, beads BeadClient, store CampaignStateStore, cfg CampaignConfig, cb CampaignCallback) *Campaign {
+	return &Campaign{runner: campaign.NewRunner(p, beads, store, cfg, cb)}
+}
+
+// Run executes the campaign for parentID. When the campaign stops early the
+// error wraps ErrCampaignPaused, ErrCampaignAborted, or ErrCircuitBroken.
+func (c *Campaign) Run(ctx context.Context, parentID string) error {
+	return c.runner.Run(ctx, parentID)
+}
+
+// Plan returns the tasks Run would start with for parentID, in run order,
+// without running anything. A parent with no ready children returns
+// ErrNoTasks.
+func (c *Campaign) Plan(parentID string) ([]BeadInfo, error) {
+	return c.runner.Plan(parentID)
+}
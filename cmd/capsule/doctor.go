@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/smileynet/capsule"
+	"github.com/smileynet/capsule/internal/config"
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/prompt"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// DoctorCmd checks that capsule can run in the current directory: config,
+// tools, prompts, the worklog template, the .capsule directory, and every
+// registered provider.
+type DoctorCmd struct {
+	SkipProviders bool `help:"Skip the provider checks, which may send each provider a one-line prompt." default:"false"`
+}
+
+// Help lists what a failure means for the exit code.
+func (c *DoctorCmd) Help() string {
+	return `Providers the config uses are required; other registered providers only warn.
+
+Exit codes:
+  0  every required check passed
+  2  a required check failed`
+}
+
+// doctorCheck is one item on the doctor checklist.
+type doctorCheck struct {
+	name     string
+	optional bool   // A failure warns instead of failing doctor.
+	hint     string // How to fix a failure; a *provider.HealthError brings its own.
+	run      func(ctx context.Context) error
+}
+
+// Run executes the checks against the project's layered config.
+func (c *DoctorCmd) Run() error {
+	return c.run(context.Background(), os.Stdout, doctorChecks(c.SkipProviders))
+}
+
+// run prints one line per check with a remediation hint under each failure.
+// A failed required check is an error, so the command exits 2.
+func (c *DoctorCmd) run(ctx context.Context, w io.Writer, checks []doctorCheck) error {
+	var failed, warned int
+	for _, check := range checks {
+		err := check.run(ctx)
+		if err == nil {
+			_, _ = fmt.Fprintf(w, "ok    %s\n", check.name)
+			continue
+		}
+		msg, hint := err.Error(), check.hint
+		var he *provider.HealthError
+		if errors.As(err, &he) {
+			msg = he.Err.Error()
+			if he.Hint != "" {
+				hint = he.Hint
+			}
+		}
+		label := "FAIL"
+		if check.optional {
+			label = "warn"
+			warned++
+		} else {
+			failed++
+		}
+		_, _ = fmt.Fprintf(w, "%-4s  %s: %s\n", label, check.name, msg)
+		if hint != "" {
+			_, _ = fmt.Fprintf(w, "      %s\n", hint)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("doctor: %d required check(s) failed", failed)
+	}
+	if warned > 0 {
+		_, _ = fmt.Fprintf(w, "ready, with %d warning(s)\n", warned)
+		return nil
+	}
+	_, _ = fmt.Fprintln(w, "ready")
+	return nil
+}
+
+// doctorChecks builds the checklist from the layered config. A config that
+// fails to load is reported and the defaults are used for the other checks.
+func doctorChecks(skipProviders bool) []doctorCheck {
+	cfg, cfgErr := loadConfig()
+	if cfgErr == nil {
+		cfgErr = validateConfig(cfg)
+	} else {
+		def := config.DefaultConfig()
+		cfg = &def
+	}
+	phases, phasesErr := loadPipelinePhases(cfg.Pipeline, "")
+
+	checks := []doctorCheck{
+		{
+			name: "config",
+			hint: "run `capsule config validate` to see every problem",
+			run:  func(context.Context) error { return cfgErr },
+		},
+		toolCheck("git", "install git and make sure it is on PATH"),
+		toolCheck("bd", "install beads (https://github.com/steveyegge/beads) and run `bd init`"),
+		{
+			name: "prompts",
+			hint: "add the prompt under .capsule/prompts, or fix pipeline.phases",
+			run: func(context.Context) error {
+				if phasesErr != nil {
+					return phasesErr
+				}
+				return checkPrompts(newPromptLoader(), phases)
+			},
+		},
+		{
+			name: "worklog template",
+			hint: "run `capsule init` to restore templates/worklog.md.template",
+			run: func(context.Context) error {
+				_, err := fs.ReadFile(capsule.OverlayFS("templates", capsule.Templates), "worklog.md.template")
+				return err
+			},
+		},
+		{
+			name: ".capsule writable",
+			hint: "make .capsule a writable directory in the repository root",
+			run:  func(context.Context) error { return checkWritable(".capsule") },
+		},
+	}
+	if skipProviders {
+		return checks
+	}
+	return append(checks, providerChecks(newProviderRegistry(cfg), usedProviders(cfg, phases))...)
+}
+
+// toolCheck reports whether binary is on PATH.
+func toolCheck(binary, hint string) doctorCheck {
+	return doctorCheck{
+		name: binary + " on PATH",
+		hint: hint,
+		run: func(context.Context) error {
+			if _, err := exec.LookPath(binary); err != nil {
+				return fmt.Errorf("%s not found on PATH", binary)
+			}
+			return nil
+		},
+	}
+}
+
+// checkPrompts loads the prompt of every worker and reviewer phase,
+// reporting all that are missing or empty.
+func checkPrompts(loader *prompt.Loader, phases []orchestrator.PhaseDefinition) error {
+	var missing []string
+	for _, p := range phases {
+		if p.Kind == orchestrator.Gate {
+			continue
+		}
+		if _, err := loader.Load(p.PromptName()); err != nil {
+			missing = append(missing, p.PromptName()+".md")
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing or empty: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkWritable creates dir if needed and writes and removes a file in it.
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	err = f.Close()
+	if rmErr := os.Remove(name); err == nil {
+		err = rmErr
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Clean(dir), err)
+	}
+	return nil
+}
+
+// usedProviders returns the providers the config runs: runtime.provider
+// and those phases name.
+func usedProviders(cfg *config.Config, phases []orchestrator.PhaseDefinition) map[string]bool {
+	used := make(map[string]bool)
+	if cfg.Runtime.Provider != "" {
+		used[cfg.Runtime.Provider] = true
+	}
+	for _, p := range phases {
+		if p.Provider != "" {
+			used[p.Provider] = true
+		}
+	}
+	return used
+}
+
+// providerChecks health-checks every registered provider. Providers in used
+// are required; the rest only warn. The scripted provider is checked only
+// when used, since it needs a script to replay.
+func providerChecks(reg *provider.Registry, used map[string]bool) []doctorCheck {
+	names := reg.AvailableProviders()
+	for name := range used {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var checks []doctorCheck
+	for _, name := range names {
+		if name == "scripted" && !used[name] {
+			continue
+		}
+		checks = append(checks, doctorCheck{
+			name:     "provider " + name,
+			optional: !used[name],
+			hint:     "declare it under runtime.providers or pick another provider",
+			run: func(ctx context.Context) error {
+				p, err := reg.NewProvider(name)
+				if err != nil {
+					return err
+				}
+				return p.HealthCheck(ctx)
+			},
+		})
+	}
+	return checks
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/config"
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// stubCheck returns a check that fails with err when it is non-nil.
+func stubCheck(name string, optional bool, err error) doctorCheck {
+	return doctorCheck{
+		name:     name,
+		optional: optional,
+		hint:     "fix " + name,
+		run:      func(context.Context) error { return err },
+	}
+}
+
+func TestDoctorCmd(t *testing.T) {
+	health := &provider.HealthError{Provider: "claude", Err: errors.New("Invalid API key"), Hint: "run `claude login`"}
+
+	tests := []struct {
+		name      string
+		checks    []doctorCheck
+		wantErr   bool
+		wantLines []string
+	}{
+		{
+			name:      "all pass",
+			checks:    []doctorCheck{stubCheck("git on PATH", false, nil), stubCheck("provider codex", true, nil)},
+			wantLines: []string{"ok    git on PATH", "ok    provider codex", "ready"},
+		},
+		{
+			name:   "optional failure warns",
+			checks: []doctorCheck{stubCheck("git on PATH", false, nil), stubCheck("provider codex", true, errors.New("codex not found on PATH"))},
+			wantLines: []string{
+				"ok    git on PATH",
+				"warn  provider codex: codex not found on PATH",
+				"      fix provider codex",
+				"ready, with 1 warning(s)",
+			},
+		},
+		{
+			name:    "required failure uses the health error's hint",
+			checks:  []doctorCheck{stubCheck("bd on PATH", false, errors.New("not found")), stubCheck("provider claude", false, health)},
+			wantErr: true,
+			wantLines: []string{
+				"FAIL  bd on PATH: not found",
+				"      fix bd on PATH",
+				"FAIL  provider claude: Invalid API key",
+				"      run `claude login`",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a checklist
+			// When doctor runs it
+			var buf bytes.Buffer
+			err := (&DoctorCmd{}).run(context.Background(), &buf, tt.checks)
+
+			// Then each check is listed with hints, and only required failures fail
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && exitCode(err) != exitSetup {
+				t.Errorf("exitCode = %d, want %d", exitCode(err), exitSetup)
+			}
+			got := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.wantLines, "\n") {
+				t.Errorf("output:\n%s\nwant:\n%s", buf.String(), strings.Join(tt.wantLines, "\n"))
+			}
+		})
+	}
+}
+
+func TestProviderChecks(t *testing.T) {
+	// Given a registry and a config that uses claude and an undeclared provider
+	reg := provider.NewRegistry()
+	for _, name := range []string{"claude", "codex", "scripted"} {
+		reg.Register(name, func() (provider.Executor, error) { return &provider.MockProvider{NameVal: name}, nil })
+	}
+	cfg := &config.Config{Runtime: config.Runtime{Provider: "claude"}}
+	used := usedProviders(cfg, []orchestrator.PhaseDefinition{{Name: "review", Provider: "typo"}})
+
+	// When the provider checks are built and run
+	checks := providerChecks(reg, used)
+
+	// Then used providers are required, others warn, and scripted is skipped
+	want := []struct {
+		name     string
+		optional bool
+		fails    bool
+	}{
+		{"provider claude", false, false},
+		{"provider codex", true, false},
+		{"provider typo", false, true},
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("got %d checks, want %d", len(checks), len(want))
+	}
+	for i, w := range want {
+		c := checks[i]
+		err := c.run(context.Background())
+		if c.name != w.name || c.optional != w.optional || (err != nil) != w.fails {
+			t.Errorf("check %d = %q optional=%v err=%v, want %q optional=%v fails=%v", i, c.name, c.optional, err, w.name, w.optional, w.fails)
+		}
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	// Given a directory that does not exist yet
+	dir := filepath.Join(t.TempDir(), ".capsule")
+
+	// When it is checked
+	if err := checkWritable(dir); err != nil {
+		t.Fatalf("checkWritable() = %v", err)
+	}
+
+	// Then it is created and left empty
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("dir entries = %v, %v; want empty", entries, err)
+	}
+}
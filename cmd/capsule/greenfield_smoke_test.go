@@ -367,9 +367,16 @@ printf '{"status":"PASS","feedback":"OK","files_changed":[],"summary":"passed"}\
 // setup-template.sh and copies prompts + templates into it.
 func setupGreenfieldProject(t *testing.T, projectRoot string) string {
 	t.Helper()
+	return setupTemplateProject(t, projectRoot, "demo-greenfield")
+}
+
+// setupTemplateProject creates a project from the named template with the
+// repo's prompts and worklog template committed, ready for capsule run.
+func setupTemplateProject(t *testing.T, projectRoot, template string) string {
+	t.Helper()
 
 	cmd := exec.Command(filepath.Join(projectRoot, "scripts", "setup-template.sh"),
-		"--template=demo-greenfield")
+		"--template="+template)
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		t.Fatalf("setup-template.sh failed: %v\n%s", err, out)
@@ -392,6 +399,15 @@ func setupGreenfieldProject(t *testing.T, projectRoot string) string {
 		t.Fatalf("mkdir .capsule: %v", err)
 	}
 
+	// Commit the copied files so capsule run's clean-repo preflight passes.
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-q", "-m", "Add prompts and templates"}} {
+		gitCmd := exec.Command("git", args...)
+		gitCmd.Dir = projectDir
+		if out, err := gitCmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
 	return projectDir
 }
 
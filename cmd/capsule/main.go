@@ -1,16 +1,27 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"log/slog"
+	"math"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
-	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/alecthomas/kong"
@@ -22,11 +33,16 @@ import (
 	"github.com/smileynet/capsule/internal/campaign"
 	"github.com/smileynet/capsule/internal/config"
 	"github.com/smileynet/capsule/internal/dashboard"
+	"github.com/smileynet/capsule/internal/fsutil"
 	"github.com/smileynet/capsule/internal/gate"
+	"github.com/smileynet/capsule/internal/notify"
 	"github.com/smileynet/capsule/internal/orchestrator"
 	"github.com/smileynet/capsule/internal/prompt"
 	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/runlock"
+	"github.com/smileynet/capsule/internal/scaffold"
 	"github.com/smileynet/capsule/internal/state"
+	"github.com/smileynet/capsule/internal/statusapi"
 	"github.com/smileynet/capsule/internal/tui"
 	"github.com/smileynet/capsule/internal/worklog"
 	"github.com/smileynet/capsule/internal/worktree"
@@ -40,80 +56,362 @@ var (
 
 // CLI is the top-level command structure for capsule.
 type CLI struct {
+	LogFlags
 	Version   kong.VersionFlag `help:"Show version." short:"V"`
 	Run       RunCmd           `cmd:"" help:"Run a capsule pipeline."`
 	Campaign  CampaignCmd      `cmd:"" help:"Run a campaign for a feature or epic."`
 	Dashboard DashboardCmd     `cmd:"" default:"withargs" help:"Open interactive dashboard TUI."`
+	Init      InitCmd          `cmd:"" help:"Create the project config, prompts, and templates."`
+	Resume    ResumeCmd        `cmd:"" help:"Resume a paused or failed pipeline from its checkpoint."`
 	Abort     AbortCmd         `cmd:"" help:"Abort a running capsule."`
 	Clean     CleanCmd         `cmd:"" help:"Clean up capsule worktree and artifacts."`
+	Phases    PhasesCmd        `cmd:"" help:"Show the effective pipeline phases."`
+	Config    ConfigCmd        `cmd:"" help:"Check the layered config."`
+	Doctor    DoctorCmd        `cmd:"" help:"Check that tools, prompts, and providers are ready to run pipelines."`
+	Prompts   PromptsCmd       `cmd:"" help:"Manage phase prompt overrides."`
+	Status    StatusCmd        `cmd:"" help:"List in-flight capsules with their checkpoints and campaigns."`
+	Logs      LogsCmd          `cmd:"" help:"Show, list, or prune archived worklogs."`
+}
+
+// LogFlags are the global flags that enable structured debug logging.
+type LogFlags struct {
+	Verbose bool   `help:"Log provider calls, git commands, and checkpoints to stderr (to --log-file only while a TUI is shown)." short:"v"`
+	LogFile string `help:"Append JSON debug logs to this file." type:"path" placeholder:"PATH"`
+}
+
+// logger returns the logger selected by the flags and a func that closes its
+// file. --log-file takes precedence over stderr, and a TUI owns the terminal,
+// so tui suppresses stderr logging. Without either flag logs are discarded.
+func (f *LogFlags) logger(tui bool) (*slog.Logger, func() error, error) {
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	switch {
+	case f.LogFile != "":
+		file, err := os.OpenFile(f.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening log file: %w", err)
+		}
+		return slog.New(slog.NewJSONHandler(file, opts)), file.Close, nil
+	case f.Verbose && !tui:
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), func() error { return nil }, nil
+	default:
+		return slog.New(slog.DiscardHandler), func() error { return nil }, nil
+	}
 }
 
 // RunCmd executes a capsule pipeline for a given bead.
 type RunCmd struct {
-	BeadID   string `arg:"" help:"Bead ID to run."`
-	Provider string `help:"Provider to use for completions." default:"claude"`
-	Timeout  int    `help:"Timeout in seconds." default:"300"`
-	NoTUI    bool   `help:"Force plain text output even if stdout is a TTY." default:"false"`
+	BeadID     string   `arg:"" help:"Bead ID to run."`
+	Provider   string   `help:"Provider to use for completions." default:"claude"`
+	NoTUI      bool     `help:"Force plain text output even if stdout is a TTY." default:"false"`
+	AllowDirty bool     `help:"Run even if the repository has uncommitted changes." default:"false"`
+	Profile    string   `help:"Phase profile from pipeline.profiles in config."`
+	NoOverlap  bool     `help:"Fail instead of warning when other in-flight capsules changed overlapping files." default:"false"`
+	SkipPhases []string `help:"Phases to skip, comma-separated or repeated (--skip-phase review)." sep:"," xor:"phase-selection" aliases:"skip-phase"`
+	OnlyPhases []string `help:"Phases to run, with any gates between them; all others are skipped (--only-phase sign-off)." sep:"," xor:"phase-selection" aliases:"only-phase"`
+
+	FileFindings    bool   `help:"File reviewer findings as child beads of this bead (also pipeline.file_findings)." default:"false"`
+	SaveTranscripts bool   `help:"Save each phase's prompt and raw output under .capsule/logs/<bead-id>/transcripts (also pipeline.save_transcripts)." default:"false"`
+	PerPhaseCommits bool   `help:"Commit the worktree after every phase that passes (also pipeline.per_phase_commits)." default:"false"`
+	SkipHealthCheck bool   `help:"Start without checking that the provider CLI is installed and logged in." default:"false"`
+	DryRun          bool   `help:"Print the phase plan and exit without creating a worktree or calling the provider." default:"false"`
+	BaseBranch      string `help:"Branch to start the worktree from and merge back into (default worktree.base_branch, else the main branch)."`
+	ReuseWorktree   bool   `help:"If an earlier run left this bead's worktree or branch and a checkpoint, resume in it instead of failing." default:"false"`
+	Listen          string `help:"Serve the run's progress as JSON at http://ADDR/status while it runs (also runtime.listen)." placeholder:"HOST:PORT"`
+
+	Output    string `help:"Output format: text, or json for one JSON object per line on stdout (implies --no-tui)." enum:"text,json" default:"text"`
+	Verbosity string `help:"Plain text detail (--no-tui or no TTY): quiet prints one line per finished phase, verbose adds feedback on passes and the feedback each retry runs with." enum:"quiet,normal,verbose" default:"normal"`
+
+	PhaseTimeoutFlags
+	RunTimeout time.Duration `help:"Deadline for the whole run, retries included (e.g. 1h); completed phases are checkpointed when it fires."`
+	MaxCalls   int           `help:"Stop the run after N provider calls, retries included; completed phases are checkpointed. 0 means no limit."`
+	Pregate    pregateFlag   `help:"Run every gate once before the first phase and fail with exit code 2 if a required gate already fails; --pregate=warn continues and tells the first worker instead."`
+
+	notifier    eventNotifier               // Set by Run; nil disables notifications.
+	skip        []string                    // Resolved by Run from SkipPhases or OnlyPhases.
+	forceKill   chan struct{}               // Closed by a second Ctrl+C; nil when unused.
+	tracker     phaseTracker                // Records the running phase for interrupt messages.
+	filer       findingFiler                // Set by Run when findings are filed; nil disables filing.
+	minSeverity string                      // Least severe finding filed (pipeline.finding_min_severity).
+	resume      bool                        // Set after the user retries from the TUI summary.
+	summaries   mergeRecorder               // Set by Run; nil skips recording the merge in summary.json.
+	comments    runSummaryReader            // Set by Run when bead.post_summary_comment is on; nil posts no comment.
+	events      *jsonEmitter                // Set by Run for --output json; nil prints text.
+	output      orchestrator.PipelineOutput // Set by run for the JSON result.
+	bead        worklog.BeadContext         // Set by runPipeline; describes the bead in the merge commit.
+	runs        *runlock.Store              // Set by Run; nil runs without a run lock.
 }
 
 // CampaignCmd runs a campaign for a feature or epic bead.
 type CampaignCmd struct {
-	ParentID string `arg:"" help:"Feature or epic bead ID."`
-	Provider string `help:"Provider to use for completions." default:"claude"`
-	Timeout  int    `help:"Timeout in seconds." default:"300"`
+	ParentID   string `arg:"" help:"Feature or epic bead ID."`
+	Provider   string `help:"Provider to use for completions." default:"claude"`
+	AllowDirty bool   `help:"Run even if the repository has uncommitted changes." default:"false"`
+	Profile    string `help:"Phase profile from pipeline.profiles in config."`
+	NoOverlap  bool   `help:"Fail a task instead of warning when other in-flight capsules changed overlapping files." default:"false"`
+	BaseBranch string `help:"Branch every task starts from and merges back into (default worktree.base_branch, else the main branch)."`
+
+	PerPhaseCommits bool `help:"Commit each task's worktree after every phase that passes (also pipeline.per_phase_commits)." default:"false"`
+
+	SkipHealthCheck bool   `help:"Start without checking that the provider CLI is installed and logged in." default:"false"`
+	Listen          string `help:"Serve the campaign's progress as JSON at http://ADDR/status while it runs (also runtime.listen)." placeholder:"HOST:PORT"`
+
+	PhaseTimeoutFlags
+	TaskTimeout time.Duration `help:"Deadline for each task's pipeline, retries included (e.g. 1h)."`
+	Concurrency int           `help:"Run up to N independent tasks at once, each in its own worktree (default campaign.concurrency)."`
+	MaxCalls    int           `help:"Stop a task after N provider calls, retries included (default campaign.max_provider_calls)."`
+	OnFailure   string        `help:"When a task fails: stop the campaign, continue with every other task, or skip-dependents to skip only the tasks that depend on it (default campaign.failure_mode)." enum:",stop,continue,skip-dependents" default:"" placeholder:"MODE"`
+	Resume      bool          `help:"Continue an interrupted campaign from its saved state, skipping completed tasks." default:"false"`
+	RetryFailed bool          `help:"Resume, and run tasks that failed or were skipped again (implies --resume)." default:"false"`
+	Plan        bool          `help:"Print the tasks the campaign would run, in order, and exit without running them." default:"false"`
+
+	Output    string `help:"Output format: text, or json for one JSON object per line on stdout." enum:"text,json" default:"text"`
+	Verbosity string `help:"Plain text detail: quiet prints one line per finished phase, verbose adds feedback on passes and the feedback each retry runs with." enum:"quiet,normal,verbose" default:"normal"`
+}
+
+// PhaseTimeoutFlags set phase timeouts for run and campaign.
+type PhaseTimeoutFlags struct {
+	PhaseTimeout []string `help:"Timeout for each phase that doesn't set its own (e.g. 10m; default runtime.timeout), or name=duration for one phase, overriding the phases file. Repeatable." placeholder:"[NAME=]DURATION"`
+	Timeout      int      `help:"Deprecated alias for --phase-timeout, in seconds." hidden:""`
+}
+
+// resolve returns the default phase timeout the flags select, or zero when
+// none is given, and the name=duration timeouts by phase. The deprecated
+// --timeout is converted with a warning to w.
+func (f *PhaseTimeoutFlags) resolve(w io.Writer) (time.Duration, map[string]time.Duration, error) {
+	var (
+		def      time.Duration
+		perPhase map[string]time.Duration
+	)
+	for _, v := range f.PhaseTimeout {
+		name, value, named := strings.Cut(v, "=")
+		if !named {
+			value = name
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return 0, nil, fmt.Errorf("--phase-timeout %q: want a positive duration like 10m or name=10m", v)
+		}
+		if !named {
+			def = d
+			continue
+		}
+		if name == "" {
+			return 0, nil, fmt.Errorf("--phase-timeout %q: missing phase name", v)
+		}
+		if perPhase == nil {
+			perPhase = make(map[string]time.Duration)
+		}
+		perPhase[name] = d
+	}
+	if def != 0 || f.Timeout == 0 {
+		return def, perPhase, nil
+	}
+	_, _ = fmt.Fprintln(w, "warning: --timeout is deprecated; use --phase-timeout (e.g. --phase-timeout 5m)")
+	return time.Duration(f.Timeout) * time.Second, perPhase, nil
+}
+
+// pregateFlag is --pregate. Given bare it selects capsule.PregateFail;
+// --pregate=warn selects capsule.PregateWarn.
+type pregateFlag capsule.PregateMode
+
+// Decode implements kong.MapperValue, taking a value only after "=".
+func (f *pregateFlag) Decode(ctx *kong.DecodeContext) error {
+	if ctx.Scan.Peek().Type != kong.FlagValueToken {
+		*f = pregateFlag(capsule.PregateFail)
+		return nil
+	}
+	switch v := fmt.Sprint(ctx.Scan.Pop().Value); v {
+	case "fail", "true":
+		*f = pregateFlag(capsule.PregateFail)
+	case "warn":
+		*f = pregateFlag(capsule.PregateWarn)
+	case "off", "false":
+		*f = pregateFlag(capsule.PregateOff)
+	default:
+		return fmt.Errorf("--pregate=%s: want fail, warn, or off", v)
+	}
+	return nil
+}
+
+// IsBool implements kong.BoolMapperValue so --pregate needs no value.
+func (pregateFlag) IsBool() bool { return true }
+
+// applyTimeouts applies a default phase timeout from the flags to cfg and
+// rejects a negative run or task deadline. It returns the timeout for
+// capsule.WithPhaseTimeout, which is runtime.timeout unless the flags set
+// one, and the per-phase timeouts for applyPhaseTimeouts.
+func applyTimeouts(w io.Writer, cfg *config.Config, flags *PhaseTimeoutFlags, deadline time.Duration) (time.Duration, map[string]time.Duration, error) {
+	if deadline < 0 {
+		return 0, nil, fmt.Errorf("run or task timeout must not be negative, got %v", deadline)
+	}
+	d, perPhase, err := flags.resolve(w)
+	if err != nil {
+		return 0, nil, err
+	}
+	if d != 0 {
+		cfg.Runtime.Timeout = d
+		cfg.Override("runtime.timeout", "--phase-timeout")
+	}
+	return cfg.Runtime.Timeout, perPhase, nil
+}
+
+// applyPhaseTimeouts sets the --phase-timeout name=duration timeouts on
+// phases, then raises runtime.timeout to the longest phase timeout so the
+// provider's own deadline does not cut a phase short. Call it before the
+// provider registry is built.
+func applyPhaseTimeouts(cfg *config.Config, phases []orchestrator.PhaseDefinition, perPhase map[string]time.Duration) error {
+	index := make(map[string]int, len(phases))
+	for i, p := range phases {
+		index[p.Name] = i
+	}
+	names := make([]string, 0, len(perPhase))
+	for name := range perPhase {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		i, ok := index[name]
+		if !ok {
+			return fmt.Errorf("--phase-timeout: unknown phase %q (valid: %s)", name, strings.Join(phaseNames(phases), ", "))
+		}
+		phases[i].Timeout = perPhase[name]
+	}
+	for _, p := range phases {
+		cfg.Runtime.Timeout = max(cfg.Runtime.Timeout, p.Timeout)
+	}
+	return nil
 }
 
-// Run executes the campaign command.
-func (c *CampaignCmd) Run() error {
+// Run executes the campaign command. With --output json every line on
+// stdout is a JSON object, ending with the campaign's result.
+func (c *CampaignCmd) Run(flags *LogFlags) (err error) {
+	if c.Plan {
+		return c.runPlan()
+	}
+	var (
+		events *jsonEmitter
+		done   campaign.Completion
+	)
+	if c.Output == outputJSON {
+		events = newJSONEmitter(os.Stdout)
+		defer func() { events.emitCampaignResult(c.ParentID, done, err) }()
+	}
 	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("campaign: %w", err)
 	}
+	logger, closeLog, err := flags.logger(false)
+	if err != nil {
+		return fmt.Errorf("campaign: %w", err)
+	}
+	defer func() { _ = closeLog() }()
 
 	cfg.Runtime.Provider = c.Provider
-	cfg.Runtime.Timeout = time.Duration(c.Timeout) * time.Second
-
-	if err := cfg.Validate(); err != nil {
+	cfg.Override("runtime.provider", "--provider")
+	phaseTimeout, perPhase, err := applyTimeouts(os.Stderr, cfg, &c.PhaseTimeoutFlags, c.TaskTimeout)
+	if err != nil {
 		return fmt.Errorf("campaign: %w", err)
 	}
+	c.overrideCampaign(cfg)
+	if c.MaxCalls != 0 {
+		cfg.Campaign.MaxProviderCalls = c.MaxCalls
+		cfg.Override("campaign.max_provider_calls", "--max-calls")
+	}
+	overrideListen(cfg, c.Listen)
 
-	// Create provider.
-	reg := provider.NewRegistry()
-	provider.RegisterBuiltins(reg, cfg.Runtime.Timeout)
-	p, err := reg.NewProvider(cfg.Runtime.Provider)
-	if err != nil {
+	if err := validateConfig(cfg); err != nil {
 		return fmt.Errorf("campaign: %w", err)
 	}
+	// A campaign is driven by beads, so without a working bd it fails here
+	// rather than after its first task.
+	if err := bead.NewClient(".").Check(); err != nil {
+		return fmt.Errorf("campaign: %w", beadSetupError(err))
+	}
 
-	// Resolve pipeline phases.
-	phases, err := orchestrator.LoadPhases(cfg.Pipeline.Phases)
+	// A second Ctrl+C closes forceKill so the provider kills its process
+	// group without waiting out the grace period.
+	forceKill := make(chan struct{})
+	providerOpts := []provider.Option{provider.WithForceKill(forceKill), provider.WithLogger(logger)}
+	setup, err := resolvePhasesAndProvider(cfg, c.Profile, cfg.Campaign.ValidationPhases, perPhase, providerOpts...)
 	if err != nil {
-		return fmt.Errorf("campaign: loading phases: %w", err)
+		return fmt.Errorf("campaign: %w", err)
+	}
+	phases, p, providers := setup.phases, setup.provider, setup.providers
+	if err := checkProviderHealth(context.Background(), p, c.SkipHealthCheck); err != nil {
+		return fmt.Errorf("campaign: %w", err)
 	}
+	tracker := &phaseTracker{}
 
 	pauseCheck, stopPause := setupPauseTrigger()
 	defer stopPause()
 
+	verbosity := tui.Verbosity(c.Verbosity)
+	statusCallback := campaignStatusCallback(os.Stdout, cfg.Campaign.Concurrency, verbosity)
+	var cb campaign.Callback = &campaignPlainTextCallback{
+		w:       os.Stdout,
+		status:  plainStatus{verbosity: verbosity},
+		policy:  cfg.Campaign.FailureMode,
+		breaker: describeBreaker(campaignBreaker(cfg.Campaign)),
+	}
+	if events != nil {
+		statusCallback = jsonStatusCallback(events)
+		cb = &campaignJSONCallback{e: events, failureMode: cfg.Campaign.FailureMode, breaker: campaignBreaker(cfg.Campaign)}
+	}
+	var status *statusapi.Tracker
+	if cfg.Runtime.Listen != "" {
+		status = statusapi.NewTracker(statusapi.KindCampaign, c.ParentID)
+		statusCallback = status.StatusCallback(statusCallback)
+		cb = status.Campaign(cb)
+	}
+
 	// Build orchestrator.
-	promptLoader := prompt.NewLoader(capsule.OverlayFS("prompts", capsule.Prompts))
-	wtMgr := worktree.NewManager(".", cfg.Worktree.BaseDir)
-	wlMgr := worklog.NewManager(capsule.OverlayFS("templates", capsule.Templates), "worklog.md.template", ".capsule/logs")
-	gateRunner := gate.NewRunner()
-
-	orch := orchestrator.New(p,
-		orchestrator.WithPromptLoader(promptLoader),
-		orchestrator.WithWorktreeManager(wtMgr),
-		orchestrator.WithWorklogManager(wlMgr),
-		orchestrator.WithGateRunner(gateRunner),
-		orchestrator.WithPhases(phases),
-		orchestrator.WithStatusCallback(plainTextCallback(os.Stdout)),
-		orchestrator.WithPauseRequested(pauseCheck),
+	promptLoader := newPromptLoader()
+	wtMgr := newWorktreeManager(cfg, worktree.WithLogger(logger))
+	baseBranch, err := resolveBaseBranch(c.BaseBranch, cfg, wtMgr)
+	if err != nil {
+		return fmt.Errorf("campaign: %w", err)
+	}
+	if !c.AllowDirty {
+		if err := checkCleanRepo(wtMgr); err != nil {
+			return fmt.Errorf("campaign: %w", err)
+		}
+	}
+	wlMgr := newWorklogManager()
+	gateRunner := gate.NewRunner(gate.WithMaxOutput(cfg.Pipeline.GateOutputMaxBytes))
+
+	orch := capsule.NewPipeline(p,
+		capsule.WithPromptLoader(promptLoader),
+		capsule.WithWorktreeManager(wtMgr),
+		capsule.WithWorklogManager(wlMgr),
+		capsule.WithSummaryWriter(wlMgr),
+		capsule.WithGateRunner(gateRunner),
+		capsule.WithPhases(phases),
+		capsule.WithPhaseResolver(phaseSetResolver(cfg.Pipeline)),
+		capsule.WithProviders(providers),
+		capsule.WithLogDir(".capsule/logs"),
+		capsule.WithTranscriptDir(transcriptDir(cfg.Pipeline.SaveTranscripts)),
+		capsule.WithPerPhaseCommits(c.PerPhaseCommits || cfg.Pipeline.PerPhaseCommits),
+		capsule.WithStatusCallback(tracker.wrap(statusCallback)),
+		capsule.WithPauseRequested(pauseCheck),
+		capsule.WithOverlapCheck(wtMgr, c.NoOverlap),
+		capsule.WithChangeLister(wtMgr),
+		capsule.WithContextFiles(cfg.Pipeline.ContextFiles, cfg.Pipeline.ContextFileMaxBytes),
+		capsule.WithWorkdirs(cfg.Pipeline.Workdirs),
+		capsule.WithProviderFactory(labelProviderFactory(cfg, providerOpts...), cfg.Runtime.Timeout),
+		capsule.WithPhaseTimeout(phaseTimeout),
+		capsule.WithCheckpointStore(newCheckpointStore(cfg, logger)),
+		capsule.WithRunTimeout(c.TaskTimeout),
+		capsule.WithMaxProviderCalls(cfg.Campaign.MaxProviderCalls),
+		capsule.WithTransientRetries(cfg.Runtime.TransientRetries, cfg.Runtime.BackoffMax),
+		capsule.WithLogger(logger),
 	)
+	if cfg.Pipeline.Checkpoint {
+		defer pruneCheckpoints(cfg, bead.NewClient("."), logger)
+	}
 
 	// Build campaign dependencies.
 	bdClient := newCampaignBeadClient(".")
-	stateStore := state.NewFileStore(".capsule/campaigns")
-	cb := &campaignPlainTextCallback{w: os.Stdout}
+	stateStore := capsule.NewCampaignStore(".capsule/campaigns")
 
 	// Construct ConflictResolver to invoke agent pair for conflict resolution
 	conflictResolver := func(beadID string, conflictErr error) error {
@@ -142,33 +440,203 @@ func (c *CampaignCmd) Run() error {
 
 		ctx, cancel := context.WithTimeout(context.Background(), cfg.Runtime.Timeout)
 		defer cancel()
-		return orch.RunConflictResolution(ctx, resolveInput)
+		return orch.ResolveConflicts(ctx, resolveInput)
 	}
 
 	// Construct PostTaskFunc closure that calls postPipelineWithConflictResolver.
+	comments := summaryComments(cfg, wlMgr)
 	postTaskFunc := func(beadID string) error {
-		return postPipelineWithConflictResolver(os.Stderr, beadID, wtMgr, bdClient.client, conflictResolver)
+		in := resolvePostPipelineInput(bdClient.client, beadID)
+		in.Comment = summaryComment(comments, beadID)
+		result, err := postPipelineWithConflictResolver(os.Stderr, in, mergeTarget(wtMgr, baseBranch), bdClient.client, conflictResolver)
+		recordMerge(os.Stderr, wlMgr, beadID, result, err)
+		return err
 	}
 
 	campaignCfg := campaign.Config{
-		FailureMode:      cfg.Campaign.FailureMode,
-		CircuitBreaker:   cfg.Campaign.CircuitBreaker,
-		DiscoveryFiling:  cfg.Campaign.DiscoveryFiling,
-		CrossRunContext:  cfg.Campaign.CrossRunContext,
-		ValidationPhases: cfg.Campaign.ValidationPhases,
-		PostTaskFunc:     postTaskFunc,
-		ConflictResolver: conflictResolver,
+		FailureMode:       cfg.Campaign.FailureMode,
+		Ordering:          cfg.Campaign.Ordering,
+		CircuitBreaker:    campaignBreaker(cfg.Campaign),
+		DiscoveryFiling:   cfg.Campaign.DiscoveryFiling,
+		PickUpDiscoveries: cfg.Campaign.PickUpDiscoveries,
+		MinSeverity:       cfg.Pipeline.FindingMinSeverity,
+		CrossRunContext:   cfg.Campaign.CrossRunContext,
+		ValidationPhases:  cfg.Campaign.ValidationPhases,
+		CloseParent:       cfg.Campaign.CloseParent,
+		Concurrency:       cfg.Campaign.Concurrency,
+		Resume:            c.Resume || c.RetryFailed,
+		RetryFailed:       c.RetryFailed,
+		BaseBranch:        baseBranch,
+		Worklog:           wlMgr,
+		PostTaskFunc:      postTaskFunc,
+		ConflictResolver:  conflictResolver,
+		Log:               logger,
+	}
+	notifyComplete := campaignCompleteFunc(os.Stderr, newNotifier(cfg))
+	campaignCfg.CompleteFunc = func(completion campaign.Completion) {
+		done = completion
+		if comments != nil {
+			commentCampaign(os.Stderr, bdClient, completion)
+		}
+		if notifyComplete != nil {
+			notifyComplete(completion)
+		}
 	}
 
-	runner := campaign.NewRunner(orch, bdClient, stateStore, campaignCfg, cb)
+	runner := capsule.NewCampaign(orch, bdClient, stateStore, campaignCfg, cb)
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, stop := interruptContext(context.Background(), os.Stderr, forceKill, tracker)
 	defer stop()
 
+	if status != nil {
+		srv, err := serveStatus(ctx, os.Stderr, cfg.Runtime.Listen, status, logger)
+		if err != nil {
+			return fmt.Errorf("campaign: %w", err)
+		}
+		defer srv.Close()
+	}
+
 	return runner.Run(ctx, c.ParentID)
 }
 
-// pipelineRunner abstracts orchestrator.RunPipeline for testing.
+// overrideListen applies --listen to cfg when it is set.
+func overrideListen(cfg *config.Config, addr string) {
+	if addr != "" {
+		cfg.Runtime.Listen = addr
+		cfg.Override("runtime.listen", "--listen")
+	}
+}
+
+// serveStatus starts the read-only status endpoint for t on addr until ctx
+// is done, and tells w where to find it.
+func serveStatus(ctx context.Context, w io.Writer, addr string, t *statusapi.Tracker, logger *slog.Logger) (*statusapi.Server, error) {
+	srv, err := statusapi.Start(ctx, addr, t, statusapi.WithLogger(logger))
+	if err != nil {
+		return nil, err
+	}
+	_, _ = fmt.Fprintf(w, "Status: http://%s/status\n", srv.Addr())
+	return srv, nil
+}
+
+// overrideCampaign applies the flags that override campaign config and are
+// shown by --plan as well as used by a run.
+func (c *CampaignCmd) overrideCampaign(cfg *config.Config) {
+	if c.Concurrency != 0 {
+		cfg.Campaign.Concurrency = c.Concurrency
+		cfg.Override("campaign.concurrency", "--concurrency")
+	}
+	if c.OnFailure != "" {
+		cfg.Campaign.FailureMode = c.OnFailure
+		cfg.Override("campaign.failure_mode", "--on-failure")
+	}
+}
+
+// campaignPlanner abstracts capsule.Campaign.Plan for testing.
+type campaignPlanner interface {
+	Plan(parentID string) ([]campaign.BeadInfo, error)
+}
+
+// runPlan loads the config as Run does and prints the campaign's plan. It
+// needs no provider and creates no worktree.
+func (c *CampaignCmd) runPlan() error {
+	cfg, err := loadConfig()
+	if err == nil {
+		c.overrideCampaign(cfg)
+		err = validateConfig(cfg)
+	}
+	var phases []orchestrator.PhaseDefinition
+	if err == nil {
+		phases, err = loadPipelinePhases(cfg.Pipeline, c.Profile)
+	}
+	if err != nil {
+		err = fmt.Errorf("campaign: %w", err)
+		if c.Output == outputJSON {
+			newJSONEmitter(os.Stdout).emitCampaignResult(c.ParentID, campaign.Completion{}, err)
+		}
+		return err
+	}
+	planner := capsule.NewCampaign(nil, newCampaignBeadClient("."), capsule.NewCampaignStore(".capsule/campaigns"), campaign.Config{
+		Ordering:    cfg.Campaign.Ordering,
+		Resume:      c.Resume || c.RetryFailed,
+		RetryFailed: c.RetryFailed,
+	}, nil)
+	return c.plan(os.Stdout, planner, cfg, len(phases))
+}
+
+// plan prints the tasks planner would run, numbered in run order, with the
+// settings that govern the run. phaseCount is the number of phases each
+// task's pipeline has. Without ready tasks it returns the error Run would,
+// so the exit code matches. With --output json the plan is one "plan" event,
+// or a "result" event carrying the error.
+func (c *CampaignCmd) plan(w io.Writer, planner campaignPlanner, cfg *config.Config, phaseCount int) error {
+	tasks, err := planner.Plan(c.ParentID)
+	if err != nil {
+		err = fmt.Errorf("campaign: %w", err)
+		if c.Output == outputJSON {
+			newJSONEmitter(w).emitCampaignResult(c.ParentID, campaign.Completion{}, err)
+		}
+		return err
+	}
+	breaker := campaignBreaker(cfg.Campaign)
+	if c.Output == outputJSON {
+		newJSONEmitter(w).emitCampaignPlan(c.ParentID, tasks, phaseCount, cfg.Campaign, breaker)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "Campaign plan for %s (%d tasks)\n", c.ParentID, len(tasks))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "#\tID\tTITLE\tPRIORITY\tTYPE\tPHASES\tWAITS ON")
+	for i, t := range tasks {
+		phases := strconv.Itoa(phaseCount)
+		if isCampaignParent(t.Type) {
+			phases = "sub-campaign"
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\tP%d\t%s\t%s\t%s\n",
+			i+1, t.ID, t.Title, t.Priority, dashIfEmpty(t.Type), phases, dashIfEmpty(strings.Join(t.BlockedBy, ", ")))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(w, "Ordering: %s\n", cfg.Campaign.Ordering)
+	_, _ = fmt.Fprintf(w, "Failure mode: %s\n", cfg.Campaign.FailureMode)
+	_, _ = fmt.Fprintf(w, "Circuit breaker: %s\n", describeBreaker(breaker))
+	_, _ = fmt.Fprintf(w, "Concurrency: %d\n", max(cfg.Campaign.Concurrency, 1))
+	if cfg.Campaign.ValidationPhases != "" {
+		_, _ = fmt.Fprintf(w, "Validation: %s phases after every task passes\n", cfg.Campaign.ValidationPhases)
+	} else {
+		_, _ = fmt.Fprintln(w, "Validation: none")
+	}
+	_, _ = fmt.Fprintln(w, "Plan only: no worktree created and no provider called.")
+	return nil
+}
+
+// isCampaignParent reports whether a child of this bead type runs as a
+// sub-campaign rather than a pipeline.
+func isCampaignParent(beadType string) bool {
+	return beadType == "feature" || beadType == "epic"
+}
+
+// describeBreaker renders the circuit breaker thresholds for the plan and
+// the campaign start line.
+func describeBreaker(b campaign.CircuitBreaker) string {
+	mode := b.Mode
+	if mode == "" {
+		mode = campaign.BreakerConsecutive
+	}
+	var parts []string
+	if b.Setup > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s setup failures", b.Setup, mode))
+	}
+	if b.Signal > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s signal failures", b.Signal, mode))
+	}
+	if len(parts) == 0 {
+		return "off"
+	}
+	return "trips after " + strings.Join(parts, " or ")
+}
+
+// pipelineRunner abstracts capsule.Pipeline.RunPipeline for testing.
 type pipelineRunner interface {
 	RunPipeline(ctx context.Context, input orchestrator.PipelineInput) (orchestrator.PipelineOutput, error)
 }
@@ -177,22 +645,74 @@ type pipelineRunner interface {
 type beadResolver interface {
 	Resolve(id string) (worklog.BeadContext, error)
 	Close(id string) error
+	Comment(id, text string) error
 }
 
 // mergeOps abstracts worktree merge operations for testing.
 type mergeOps interface {
 	MergeToMain(id, mainBranch, commitMsg string) error
+	MergeMessage(msg worktree.MergeMessage) (string, error)
+	MergeStrategy() worktree.MergeStrategy
 	DetectMainBranch() (string, error)
 	Remove(id string, deleteBranch bool) error
 	Prune() error
 }
 
+// branchVerifier checks that a branch exists. It is satisfied by
+// *worktree.Manager.
+type branchVerifier interface {
+	VerifyBranch(branch string) error
+}
+
+// resolveBaseBranch returns the branch capsules start from and merge back
+// into: flag, else worktree.base_branch. An explicit branch must exist. An
+// empty result leaves worktrees on the pipeline default and merges on the
+// detected main branch.
+func resolveBaseBranch(flag string, cfg *config.Config, wt branchVerifier) (string, error) {
+	branch := cfg.Worktree.BaseBranch
+	if flag != "" {
+		branch = flag
+	}
+	if branch == "" {
+		return "", nil
+	}
+	if err := wt.VerifyBranch(branch); err != nil {
+		return "", fmt.Errorf("base branch: %w", err)
+	}
+	return branch, nil
+}
+
+// baseBranchMerge merges into an explicitly chosen base branch instead of
+// the detected main branch.
+type baseBranchMerge struct {
+	mergeOps
+	branch string
+}
+
+func (b baseBranchMerge) DetectMainBranch() (string, error) {
+	return b.branch, nil
+}
+
+// mergeTarget returns wt, merging into branch when one was chosen.
+func mergeTarget(wt mergeOps, branch string) mergeOps {
+	if branch == "" {
+		return wt
+	}
+	return baseBranchMerge{mergeOps: wt, branch: branch}
+}
+
+// eventNotifier abstracts notify.Notifier for testing.
+type eventNotifier interface {
+	Notify(ctx context.Context, ev notify.Event) error
+}
+
 // loadConfig loads layered config from user and project paths with env overrides.
 func loadConfig() (*config.Config, error) {
-	cfg, err := config.LoadLayered(
-		os.ExpandEnv("$HOME/.config/capsule/config.yaml"),
-		".capsule/config.yaml",
-	)
+	var userConfig string
+	if dir := userConfigDir(); dir != "" {
+		userConfig = filepath.Join(dir, "config.yaml")
+	}
+	cfg, err := config.LoadLayered(userConfig, ".capsule/config.yaml")
 	if err != nil {
 		return nil, err
 	}
@@ -202,326 +722,2022 @@ func loadConfig() (*config.Config, error) {
 	return cfg, nil
 }
 
-// Run executes the run command.
-func (r *RunCmd) Run() error {
-	cfg, err := loadConfig()
+// validateConfig validates cfg, checking the provider names it uses against
+// the built-in providers and those it declares.
+func validateConfig(cfg *config.Config) error {
+	return cfg.Validate(config.WithKnownProviders(newProviderRegistry(cfg).AvailableProviders()...))
+}
+
+// cleanChecker abstracts worktree.Manager.StatusClean for testing.
+type cleanChecker interface {
+	StatusClean() (bool, []string, error)
+}
+
+// dirtyRepoError reports uncommitted changes in the repository root.
+type dirtyRepoError struct {
+	Paths []string
+}
+
+func (e *dirtyRepoError) Error() string {
+	var b strings.Builder
+	b.WriteString("repository has uncommitted changes (commit or stash them, or pass --allow-dirty):")
+	for _, p := range e.Paths {
+		b.WriteString("\n  " + p)
+	}
+	return b.String()
+}
+
+// checkCleanRepo returns a *dirtyRepoError if the repository root has
+// uncommitted changes that would interfere with merging capsule work back.
+func checkCleanRepo(c cleanChecker) error {
+	clean, paths, err := c.StatusClean()
 	if err != nil {
-		return fmt.Errorf("run: %w", err)
+		return err
+	}
+	if !clean {
+		return &dirtyRepoError{Paths: paths}
 	}
+	return nil
+}
 
-	// Apply CLI flag overrides.
-	cfg.Runtime.Provider = r.Provider
-	cfg.Runtime.Timeout = time.Duration(r.Timeout) * time.Second
+// newProviderRegistry registers the built-in providers, the providers
+// declared under runtime.providers, and the offline "scripted" provider,
+// which replays cfg.Runtime.Script. Extra opts are passed to every CLI
+// provider. A declared provider may replace a built-in of the same name.
+func newProviderRegistry(cfg *config.Config, opts ...provider.Option) *provider.Registry {
+	reg := provider.NewRegistry()
+	opts = append([]provider.Option{provider.WithGracePeriod(cfg.Runtime.KillGrace)}, opts...)
+	provider.RegisterBuiltins(reg, cfg.Runtime.Timeout, opts...)
+	for name, p := range cfg.Runtime.Providers {
+		timeout := cfg.Runtime.Timeout
+		if p.Timeout > 0 {
+			timeout = p.Timeout
+		}
+		provider.RegisterCommand(reg, provider.CommandConfig{
+			Name:           name,
+			Binary:         p.Command,
+			PromptFlag:     p.PromptFlag,
+			PromptStdin:    p.PromptStdin,
+			ExtraFlags:     p.Args,
+			StripANSI:      true,
+			StreamProgress: p.Stream,
+		}, timeout, opts...)
+	}
+	provider.RegisterScripted(reg, cfg.Runtime.Script)
+	return reg
+}
 
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("run: %w", err)
+// phaseProviders creates the providers that phases name in their provider
+// field, for capsule.WithProviders. A name that is not registered is an
+// error, so a typo in a phases file fails before any work starts.
+func phaseProviders(reg *provider.Registry, phases []orchestrator.PhaseDefinition) (map[string]orchestrator.Provider, error) {
+	providers := make(map[string]orchestrator.Provider)
+	for _, phase := range phases {
+		if phase.Provider == "" || providers[phase.Provider] != nil {
+			continue
+		}
+		p, err := reg.NewProvider(phase.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("phase %q: %w", phase.Name, err)
+		}
+		providers[phase.Provider] = p
 	}
+	return providers, nil
+}
 
-	// Create provider via registry.
-	reg := provider.NewRegistry()
-	provider.RegisterBuiltins(reg, cfg.Runtime.Timeout)
+// labelProviderFactory creates providers for capsule:provider and
+// capsule:timeout bead labels. Each provider gets its own registry so a
+// label's timeout applies to that bead only. A capsule:timeout label also
+// takes precedence over a declared provider's own timeout.
+func labelProviderFactory(cfg *config.Config, opts ...provider.Option) orchestrator.ProviderFactory {
+	return func(name string, timeout time.Duration) (orchestrator.Provider, error) {
+		beadCfg := *cfg
+		if timeout != cfg.Runtime.Timeout {
+			beadCfg.Runtime.Providers = make(map[string]config.ProviderConfig, len(cfg.Runtime.Providers))
+			for n, p := range cfg.Runtime.Providers {
+				p.Timeout = 0
+				beadCfg.Runtime.Providers[n] = p
+			}
+		}
+		beadCfg.Runtime.Timeout = timeout
+		return newProviderRegistry(&beadCfg, opts...).NewProvider(name)
+	}
+}
 
+// pipelineSetup is the phase list and providers a run, campaign, or
+// dashboard executes with.
+type pipelineSetup struct {
+	phases    []orchestrator.PhaseDefinition
+	registry  *provider.Registry
+	provider  provider.Executor                // runtime.provider.
+	providers map[string]orchestrator.Provider // By name, for phases that set provider.
+}
+
+// resolvePhasesAndProvider resolves the pipeline phases for profile and
+// applies the perPhase timeouts before creating the providers, whose timeout
+// must cover the longest phase. validation, when set, is the phase set
+// campaign validation runs; it is resolved here too, so a bad set fails at
+// setup and its phases' timeouts and providers are covered. opts are passed
+// to every CLI provider.
+func resolvePhasesAndProvider(cfg *config.Config, profile, validation string, perPhase map[string]time.Duration, opts ...provider.Option) (pipelineSetup, error) {
+	phases, err := loadPipelinePhases(cfg.Pipeline, profile)
+	if err != nil {
+		return pipelineSetup{}, fmt.Errorf("loading phases: %w", err)
+	}
+	if err := applyPhaseTimeouts(cfg, phases, perPhase); err != nil {
+		return pipelineSetup{}, err
+	}
+	all := phases
+	if validation != "" {
+		extra, err := loadPhaseSet(cfg.Pipeline, validation)
+		if err != nil {
+			return pipelineSetup{}, fmt.Errorf("loading campaign.validation_phases: %w", err)
+		}
+		if err := applyPhaseTimeouts(cfg, extra, nil); err != nil {
+			return pipelineSetup{}, err
+		}
+		all = append(slices.Clip(phases), extra...)
+	}
+	reg := newProviderRegistry(cfg, opts...)
 	p, err := reg.NewProvider(cfg.Runtime.Provider)
 	if err != nil {
-		return fmt.Errorf("run: %w", err)
+		return pipelineSetup{}, err
+	}
+	providers, err := phaseProviders(reg, all)
+	if err != nil {
+		return pipelineSetup{}, err
+	}
+	return pipelineSetup{phases: phases, registry: reg, provider: p, providers: providers}, nil
+}
+
+// loadPhaseSet resolves spec, a phase set a run names in
+// PipelineInput.Phases, the way the configured phases are resolved: a
+// pipeline profile gets its phases and overrides, pipeline.phases gets
+// pipeline.overrides, and any other preset or phases file is used as is,
+// like a profile that names its own phases.
+func loadPhaseSet(p config.Pipeline, spec string) ([]orchestrator.PhaseDefinition, error) {
+	if _, ok := p.Profiles[spec]; ok {
+		return loadPipelinePhases(p, spec)
+	}
+	if spec == p.Phases {
+		return loadPipelinePhases(p, "")
+	}
+	return orchestrator.LoadPhases(spec)
+}
+
+// phaseSetResolver returns a resolver for PipelineInput.Phases that goes
+// through loadPhaseSet, for capsule.WithPhaseResolver.
+func phaseSetResolver(p config.Pipeline) orchestrator.PhaseResolver {
+	return func(spec string) ([]orchestrator.PhaseDefinition, error) {
+		return loadPhaseSet(p, spec)
+	}
+}
+
+// loadPipelinePhases resolves the phases for profile ("" for the top-level
+// pipeline settings) and applies the config's phase overrides.
+func loadPipelinePhases(p config.Pipeline, profile string) ([]orchestrator.PhaseDefinition, error) {
+	spec, sets, err := p.Resolve(profile)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make([]map[string]orchestrator.PhaseOverride, 0, len(sets))
+	for _, set := range sets {
+		m := make(map[string]orchestrator.PhaseOverride, len(set))
+		for name, o := range set {
+			m[name] = orchestrator.PhaseOverride(o)
+		}
+		overrides = append(overrides, m)
+	}
+	return orchestrator.LoadPhases(spec, overrides...)
+}
+
+// newWorktreeManager builds a worktree.Manager from the worktree config section
+// and any extra opts.
+// The config must already be validated, so an unknown merge strategy cannot occur.
+func newWorktreeManager(cfg *config.Config, opts ...worktree.Option) *worktree.Manager {
+	strategy, _ := worktree.ParseMergeStrategy(cfg.Worktree.MergeStrategy)
+	opts = append([]worktree.Option{
+		worktree.WithMergeStrategy(strategy),
+		worktree.WithMergeMessageTemplate(cfg.Worktree.MergeMessageTemplate),
+		worktree.WithDirTemplate(cfg.Worktree.DirTemplate),
+	}, opts...)
+	return worktree.NewManager(".", cfg.Worktree.BaseDir, opts...)
+}
+
+// userConfigDir returns the directory holding the user's config.yaml and
+// prompts: capsule under os.UserConfigDir (~/.config on Linux,
+// ~/Library/Application Support on macOS, %AppData% on Windows), or "" when
+// neither it nor the home directory is known.
+func userConfigDir() string {
+	configDir, _ := os.UserConfigDir()
+	home, _ := os.UserHomeDir()
+	return pickUserConfigDir(configDir, home, fsutil.IsDir)
+}
+
+// pickUserConfigDir chooses capsule's directory under configDir, falling
+// back to ~/.config/capsule, where capsule kept it on every OS before
+// following the platform's convention, when only that one exists.
+func pickUserConfigDir(configDir, home string, exists func(string) bool) string {
+	var dir, legacy string
+	if configDir != "" {
+		dir = filepath.Join(configDir, "capsule")
+	}
+	if home != "" {
+		legacy = filepath.Join(home, ".config", "capsule")
+	}
+	if dir == "" || (legacy != "" && !exists(dir) && exists(legacy)) {
+		return legacy
+	}
+	return dir
+}
+
+// tildePath shortens a path under home to start with ~, for display.
+func tildePath(path, home string) string {
+	if rel, err := filepath.Rel(home, path); err == nil && home != "" && filepath.IsLocal(rel) {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// Prompt override directories, searched before the embedded defaults.
+const (
+	projectPromptDir = ".capsule/prompts"
+	legacyPromptDir  = "prompts" // Where capsule init writes the prompts.
+)
+
+// newPromptLoader reads each phase prompt from the first of the project's
+// .capsule/prompts, its prompts directory, the prompts directory beside the
+// user config (see userConfigDir), and the embedded defaults that has it.
+func newPromptLoader() *prompt.Loader {
+	sources := []prompt.Source{
+		{Name: projectPromptDir, FS: os.DirFS(projectPromptDir)},
+		{Name: legacyPromptDir, FS: os.DirFS(legacyPromptDir)},
+	}
+	if dir := userConfigDir(); dir != "" {
+		home, _ := os.UserHomeDir()
+		userPrompts := filepath.Join(dir, "prompts")
+		sources = append(sources, prompt.Source{Name: tildePath(userPrompts, home), FS: os.DirFS(userPrompts)})
+	}
+	return prompt.NewLayeredLoader(append(sources, prompt.Source{Name: "embedded", FS: capsule.Prompts})...)
+}
+
+// newWorklogManager builds the worklog.Manager that archives to .capsule/logs,
+// using the embedded worklog template unless the project overrides it.
+func newWorklogManager() *worklog.Manager {
+	return worklog.NewManager(capsule.OverlayFS("templates", capsule.Templates), "worklog.md.template", ".capsule/logs")
+}
+
+// transcriptDir returns where phase transcripts are saved: beside the
+// archived worklogs when enabled, or "" to disable them.
+func transcriptDir(enabled bool) string {
+	if !enabled {
+		return ""
+	}
+	return ".capsule/logs"
+}
+
+// checkpointDir holds the pipeline checkpoints that runs resume from.
+const checkpointDir = ".capsule/checkpoints"
+
+// newCheckpointStore returns the store of phase results that failed runs
+// resume from, or nil when pipeline.checkpoint is off.
+func newCheckpointStore(cfg *config.Config, logger *slog.Logger) orchestrator.CheckpointStore {
+	if !cfg.Pipeline.Checkpoint {
+		return nil
 	}
+	return state.NewCheckpointFileStore(checkpointDir, state.WithCheckpointLogger(logger))
+}
 
-	// Resolve pipeline phases.
-	phases, err := orchestrator.LoadPhases(cfg.Pipeline.Phases)
+// closedCheckpointScan is how many recently closed beads pruneCheckpoints
+// asks bd for.
+const closedCheckpointScan = 200
+
+// closedBeadLister lists recently closed beads.
+type closedBeadLister interface {
+	Closed(limit int) ([]bead.Summary, error)
+}
+
+// pruneCheckpoints removes checkpoints older than
+// pipeline.checkpoint_retention and those of beads bd lists as closed.
+// Pruning is best-effort: failures are logged and never fail the command.
+func pruneCheckpoints(cfg *config.Config, closed closedBeadLister, logger *slog.Logger) {
+	var done func(string) bool
+	if beads, err := closed.Closed(closedCheckpointScan); err != nil {
+		logger.Debug("checkpoint prune: listing closed beads failed", "error", err)
+	} else {
+		ids := make(map[string]bool, len(beads))
+		for _, b := range beads {
+			ids[b.ID] = true
+		}
+		done = func(id string) bool { return ids[id] }
+	}
+	store := state.NewCheckpointFileStore(checkpointDir, state.WithCheckpointLogger(logger))
+	removed, err := store.PruneCheckpoints(cfg.Pipeline.CheckpointRetention, done)
 	if err != nil {
-		return fmt.Errorf("run: loading phases: %w", err)
+		logger.Warn("checkpoint prune failed", "error", err)
 	}
+	if len(removed) > 0 {
+		logger.Debug("checkpoints pruned", "beads", removed)
+	}
+}
 
-	// Create a cancellable context for the pipeline. The cancel func is passed
-	// to the TUI so keyboard abort (q / Ctrl+C) can cancel the pipeline gracefully.
-	pipelineCtx, pipelineCancel := context.WithCancel(context.Background())
-	defer pipelineCancel()
+// newRunLockStore returns the store of locks held by running pipelines,
+// which `capsule abort` uses to stop them.
+func newRunLockStore() *runlock.Store {
+	return runlock.NewStore(".capsule/runs")
+}
 
-	// Resolve bead title early for display header (best-effort).
-	// Note: the bead is resolved again in runPipeline for worklog context.
-	// The duplication is intentional — the header resolve is fire-and-forget
-	// (no warnings), while runPipeline's resolve logs warnings to the writer.
-	bdClient := bead.NewClient(".")
-	beadCtx, _ := bdClient.Resolve(r.BeadID)
+// newNotifier builds a notify.Notifier from the notifications config section.
+// Returns nil when no hook is configured.
+func newNotifier(cfg *config.Config) *notify.Notifier {
+	return notify.New(cfg.Notifications.Command, cfg.Notifications.Webhook,
+		notify.WithTimeout(cfg.Notifications.Timeout))
+}
 
-	// Build display bridge and display.
-	bridge := tui.NewBridge()
-	display := tui.NewDisplay(tui.DisplayOptions{
-		Writer:     os.Stdout,
-		ForcePlain: r.NoTUI,
-		Phases:     phaseNames(phases),
-		CancelFunc: pipelineCancel,
-		BeadID:     r.BeadID,
-		BeadTitle:  beadCtx.TaskTitle,
-	})
+// sendNotification delivers ev, printing a warning to w on failure.
+// Notification is best-effort and never changes the command's outcome.
+func sendNotification(w io.Writer, n eventNotifier, ev notify.Event) {
+	if n == nil {
+		return
+	}
+	if err := n.Notify(context.Background(), ev); err != nil {
+		_, _ = fmt.Fprintf(w, "warning: notification failed: %v\n", err)
+	}
+}
+
+// pipelineEvent builds a notify.Event from a pipeline's outcome.
+func pipelineEvent(beadID string, pipelineErr error, d time.Duration) notify.Event {
+	ev := notify.Event{
+		Kind:     notify.KindPipeline,
+		BeadID:   beadID,
+		Status:   notify.StatusPassed,
+		Success:  pipelineErr == nil,
+		Duration: d,
+	}
+	if pipelineErr == nil {
+		return ev
+	}
+	ev.Status = notify.StatusFailed
+	if errors.Is(pipelineErr, orchestrator.ErrPipelinePaused) {
+		ev.Status = notify.StatusPaused
+	}
+	ev.Error = pipelineErr.Error()
+	var pe *orchestrator.PipelineError
+	if errors.As(pipelineErr, &pe) {
+		ev.FailedPhase = pe.Phase
+	}
+	return ev
+}
+
+// campaignCompleteFunc returns a campaign.Config.CompleteFunc that sends a
+// campaign notification through n, warning to w on failure.
+func campaignCompleteFunc(w io.Writer, n *notify.Notifier) func(campaign.Completion) {
+	if n == nil {
+		return nil
+	}
+	return func(c campaign.Completion) {
+		ev := notify.Event{
+			Kind:     notify.KindCampaign,
+			BeadID:   c.ParentID,
+			Status:   notify.StatusPassed,
+			Success:  c.Success(),
+			Duration: c.Duration,
+		}
+		switch {
+		case errors.Is(c.Err, campaign.ErrCampaignPaused), errors.Is(c.Err, campaign.ErrCampaignAborted):
+			ev.Status = notify.StatusPaused
+		case !c.Success():
+			ev.Status = notify.StatusFailed
+		}
+		if c.Err != nil {
+			ev.Error = c.Err.Error()
+		}
+		sendNotification(w, n, ev)
+	}
+}
+
+// dashboardNotifyFunc adapts a notify.Notifier to dashboard.NotifyFunc.
+// Errors are returned so the dashboard can show them in its status line.
+func dashboardNotifyFunc(n *notify.Notifier) dashboard.NotifyFunc {
+	if n == nil {
+		return nil
+	}
+	return func(ce dashboard.CompletionEvent) error {
+		var ev notify.Event
+		if ce.Campaign {
+			ev = notify.Event{
+				Kind:     notify.KindCampaign,
+				BeadID:   ce.BeadID,
+				Status:   notify.StatusPassed,
+				Success:  ce.Success && ce.Err == nil,
+				Duration: ce.Duration,
+			}
+			if !ev.Success {
+				ev.Status = notify.StatusFailed
+			}
+			if ce.Err != nil {
+				ev.Error = ce.Err.Error()
+			}
+		} else {
+			ev = pipelineEvent(ce.BeadID, ce.Err, ce.Duration)
+			if ce.Err == nil && !ce.Success {
+				ev.Status = notify.StatusFailed
+				ev.Success = false
+			}
+		}
+		if ev.FailedPhase == "" {
+			ev.FailedPhase = ce.FailedPhase
+		}
+		return n.Notify(context.Background(), ev)
+	}
+}
+
+// Help documents the exit codes under capsule run --help.
+func (r *RunCmd) Help() string { return exitCodesHelp }
+
+// Run executes the run command. With --output json every line on stdout is
+// a JSON object, ending with the run's result; text goes to stderr.
+func (r *RunCmd) Run(flags *LogFlags) (err error) {
+	if r.Output == outputJSON {
+		if r.DryRun {
+			return fmt.Errorf("run: --dry-run does not support --output json")
+		}
+		r.NoTUI = true
+		r.events = newJSONEmitter(os.Stdout)
+		defer func() { r.events.emitRunResult(r.BeadID, r.output, err) }()
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+	logger, closeLog, err := flags.logger(tui.UsesTUI(tui.DisplayOptions{Writer: os.Stdout, ForcePlain: r.NoTUI}))
+	if err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+	defer func() { _ = closeLog() }()
+
+	// Apply CLI flag overrides.
+	cfg.Runtime.Provider = r.Provider
+	cfg.Override("runtime.provider", "--provider")
+	phaseTimeout, perPhase, err := applyTimeouts(os.Stderr, cfg, &r.PhaseTimeoutFlags, r.RunTimeout)
+	if err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+	overrideListen(cfg, r.Listen)
+
+	if err := validateConfig(cfg); err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+
+	// A second Ctrl+C closes forceKill so the provider kills its process
+	// group without waiting out the grace period.
+	r.forceKill = make(chan struct{})
+	providerOpts := []provider.Option{provider.WithForceKill(r.forceKill), provider.WithLogger(logger)}
+	setup, err := resolvePhasesAndProvider(cfg, r.Profile, "", perPhase, providerOpts...)
+	if err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+	phases, p, providers := setup.phases, setup.provider, setup.providers
+	if err := checkProviderHealth(context.Background(), p, r.SkipHealthCheck || r.DryRun); err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+	r.skip, err = orchestrator.SkipSet(phases, r.SkipPhases, r.OnlyPhases)
+	if err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+
+	// Refuse to branch from a repository with uncommitted changes: the
+	// merge back to main would conflict with or clobber local edits.
+	wtMgr := newWorktreeManager(cfg, worktree.WithLogger(logger))
+	r.BaseBranch, err = resolveBaseBranch(r.BaseBranch, cfg, wtMgr)
+	if err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+
+	// A dry run plans with the same phases, prompts, and overrides as a real
+	// run, then stops before the repository or the provider is touched.
+	if r.DryRun {
+		planner := capsule.NewPipeline(p,
+			capsule.WithPromptLoader(newPromptLoader()),
+			capsule.WithWorktreeManager(wtMgr),
+			capsule.WithPhases(phases),
+			capsule.WithProviders(providers),
+			capsule.WithChangeLister(wtMgr),
+			capsule.WithContextFiles(cfg.Pipeline.ContextFiles, cfg.Pipeline.ContextFileMaxBytes),
+			capsule.WithWorkdirs(cfg.Pipeline.Workdirs),
+			capsule.WithProviderFactory(labelProviderFactory(cfg, providerOpts...), cfg.Runtime.Timeout),
+			capsule.WithPhaseTimeout(phaseTimeout),
+			capsule.WithLogger(logger),
+		)
+		return r.dryRun(os.Stdout, planner, bead.NewClient("."))
+	}
+
+	if !r.AllowDirty {
+		if err := checkCleanRepo(wtMgr); err != nil {
+			return fmt.Errorf("run: %w", err)
+		}
+	}
+
+	// A crashed or aborted run can leave the worktree or its branch behind,
+	// which would fail worktree creation.
+	var confirm func(string) bool
+	if r.events == nil && isInteractive() {
+		confirm = func(question string) bool { return askYesNo(os.Stdin, os.Stdout, question) }
+	}
+	if err := r.recoverLeftover(os.Stdout, wtMgr, state.NewCheckpointFileStore(checkpointDir), confirm); err != nil {
+		return fmt.Errorf("run: %w", err)
+	}
+
+	// Create a cancellable context for the pipeline. The cancel func is passed
+	// to the TUI so keyboard abort (q / Ctrl+C) can cancel the pipeline gracefully.
+	pipelineCtx, pipelineCancel := context.WithCancel(context.Background())
+	defer pipelineCancel()
+
+	// Resolve bead title early for display header (best-effort).
+	// Note: the bead is resolved again in runPipeline for worklog context.
+	// The duplication is intentional — the header resolve is fire-and-forget
+	// (no warnings), while runPipeline's resolve logs warnings to the writer.
+	bdClient := bead.NewClient(".")
+	if err := bdClient.Check(); err != nil {
+		// A run needs no bead beyond its ID, so it continues without context.
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %v; continuing without bead context\n", beadSetupError(err))
+	}
+	beadCtx, _ := bdClient.Resolve(r.BeadID)
+
+	// Checkpoints let a failed run be retried from the TUI summary. A run
+	// timeout or call budget always checkpoints so a run it cuts short can
+	// be resumed, and a resumed run needs its checkpoint whatever the config
+	// says.
+	if r.RunTimeout > 0 || r.MaxCalls > 0 || r.resume {
+		cfg.Pipeline.Checkpoint = true
+	}
+	checkpoints := newCheckpointStore(cfg, logger)
+	if checkpoints != nil {
+		// Pruned once the run is done, so its own checkpoint is current.
+		defer pruneCheckpoints(cfg, bdClient, logger)
+	}
+
+	// Build display bridge and display. In JSON mode phase updates bypass
+	// the bridge, and text that would share stdout with them goes to stderr.
+	bridge := tui.NewBridge()
+	display := tui.NewDisplay(tui.DisplayOptions{
+		Writer:     os.Stdout,
+		ForcePlain: r.NoTUI,
+		Phases:     displayPhaseNames(phases, r.skip, capsule.PregateMode(r.Pregate)),
+		CancelFunc: pipelineCancel,
+		BeadID:     r.BeadID,
+		BeadTitle:  beadCtx.TaskTitle,
+		Retry:      checkpoints != nil,
+		Verbosity:  tui.Verbosity(r.Verbosity),
+	})
+	statusCallback := bridgeStatusCallback(bridge)
+	out := io.Writer(os.Stdout)
+	if r.events != nil {
+		display = jsonDisplay{}
+		statusCallback = jsonStatusCallback(r.events)
+		out = os.Stderr
+	}
+	if cfg.Runtime.Listen != "" {
+		status := statusapi.NewTracker(statusapi.KindPipeline, r.BeadID)
+		statusCallback = status.StatusCallback(statusCallback)
+		srv, err := serveStatus(pipelineCtx, os.Stderr, cfg.Runtime.Listen, status, logger)
+		if err != nil {
+			return fmt.Errorf("run: %w", err)
+		}
+		defer srv.Close()
+	}
+
+	pauseCheck, stopPause := setupPauseTrigger()
+	defer stopPause()
+
+	// Build orchestrator.
+	promptLoader := newPromptLoader()
+	wlMgr := newWorklogManager()
+	gateRunner := gate.NewRunner(gate.WithMaxOutput(cfg.Pipeline.GateOutputMaxBytes))
+
+	orch := capsule.NewPipeline(p,
+		capsule.WithPromptLoader(promptLoader),
+		capsule.WithWorktreeManager(wtMgr),
+		capsule.WithWorklogManager(wlMgr),
+		capsule.WithSummaryWriter(wlMgr),
+		capsule.WithGateRunner(gateRunner),
+		capsule.WithPhases(phases),
+		capsule.WithProviders(providers),
+		capsule.WithLogDir(".capsule/logs"),
+		capsule.WithTranscriptDir(transcriptDir(r.SaveTranscripts || cfg.Pipeline.SaveTranscripts)),
+		capsule.WithPerPhaseCommits(r.PerPhaseCommits || cfg.Pipeline.PerPhaseCommits),
+		capsule.WithStatusCallback(r.tracker.wrap(statusCallback)),
+		capsule.WithPauseRequested(pauseCheck),
+		capsule.WithCheckpointStore(checkpoints),
+		capsule.WithOverlapCheck(wtMgr, r.NoOverlap),
+		capsule.WithChangeLister(wtMgr),
+		capsule.WithContextFiles(cfg.Pipeline.ContextFiles, cfg.Pipeline.ContextFileMaxBytes),
+		capsule.WithWorkdirs(cfg.Pipeline.Workdirs),
+		capsule.WithProviderFactory(labelProviderFactory(cfg, providerOpts...), cfg.Runtime.Timeout),
+		capsule.WithPhaseTimeout(phaseTimeout),
+		capsule.WithRunTimeout(r.RunTimeout),
+		capsule.WithMaxProviderCalls(r.MaxCalls),
+		capsule.WithPregate(capsule.PregateMode(r.Pregate)),
+		capsule.WithTransientRetries(cfg.Runtime.TransientRetries, cfg.Runtime.BackoffMax),
+		capsule.WithLogger(logger),
+	)
+
+	if n := newNotifier(cfg); n != nil {
+		r.notifier = n
+	}
+	if r.FileFindings || cfg.Pipeline.FileFindings {
+		r.filer = bdClient
+		r.minSeverity = cfg.Pipeline.FindingMinSeverity
+	}
+	r.summaries = wlMgr
+	r.comments = summaryComments(cfg, wlMgr)
+	r.runs = newRunLockStore()
+	return r.run(out, orch, wtMgr, bdClient, display, bridge, pipelineCtx)
+}
+
+// run executes the pipeline with display lifecycle management, enabling testable wiring.
+func (r *RunCmd) run(w io.Writer, runner pipelineRunner, wt mergeOps, bd beadResolver, display tui.Display, bridge *tui.Bridge, pipelineCtx context.Context) error {
+	start := time.Now()
+
+	// Hold the run lock until run returns, retries included: `capsule abort`
+	// cancels the pipeline through it and reads the running phase from it.
+	if r.runs != nil {
+		lock, err := r.runs.Acquire(r.BeadID)
+		if err != nil {
+			return fmt.Errorf("run: %w", err)
+		}
+		defer func() { _ = lock.Release() }()
+		var cancel context.CancelFunc
+		pipelineCtx, cancel = context.WithCancel(pipelineCtx)
+		defer cancel()
+		go lock.Watch(pipelineCtx, cancel)
+		r.tracker.setLock(lock)
+	}
+
+	var (
+		output      orchestrator.PipelineOutput
+		pipelineErr error
+	)
+	for {
+		// Start display goroutine.
+		displayDone := make(chan error, 1)
+		go func() {
+			displayDone <- display.Run(context.Background(), bridge.Events())
+		}()
+
+		// Run the pipeline.
+		output, pipelineErr = r.runPipeline(pipelineCtx, w, runner, bd)
+
+		// Signal display completion.
+		bridge.Findings(output.Findings)
+		if pipelineErr != nil {
+			bridge.Error(pipelineErr)
+		} else {
+			bridge.Done()
+		}
+
+		// Wait for display to finish (so it releases the terminal). Pressing
+		// r on the failure summary resumes the run from its checkpoint.
+		if err := <-displayDone; !errors.Is(err, tui.ErrRetryRequested) || pipelineErr == nil {
+			break
+		}
+		r.resume = true
+		bridge.Restart()
+	}
+	r.output = output
+
+	sendNotification(w, r.notifier, pipelineEvent(r.BeadID, pipelineErr, time.Since(start)))
+
+	if errors.Is(pipelineErr, orchestrator.ErrPipelinePaused) {
+		_, _ = fmt.Fprintf(w, "Pipeline paused. Resume with: capsule resume %s\n", r.BeadID)
+		return pipelineErr
+	}
+
+	r.fileFindings(w, output.Findings)
+
+	if pipelineErr != nil {
+		return pipelineErr
+	}
+
+	// Post-pipeline lifecycle: merge → cleanup → close bead.
+	// Best-effort, except that a merge conflict is returned so scripts can
+	// tell that the passed pipeline still needs a human to merge it.
+	in := postPipelineInput(r.BeadID, r.bead, output)
+	in.Comment = summaryComment(r.comments, r.BeadID)
+	result := postPipeline(w, in, mergeTarget(wt, r.BaseBranch), bd)
+	recordMerge(w, r.summaries, r.BeadID, result, nil)
+	if result.MergeConflict {
+		return fmt.Errorf("%s: pipeline passed but not merged: %w", r.BeadID, worktree.ErrMergeConflict)
+	}
+	return nil
+}
+
+// runPipeline resolves the bead and runs the pipeline, returning its output
+// and any pipeline error.
+func (r *RunCmd) runPipeline(parentCtx context.Context, w io.Writer, runner pipelineRunner, bd beadResolver) (orchestrator.PipelineOutput, error) {
+	// Wrap with OS signal handling so Ctrl+C in non-TUI mode still works.
+	ctx, stop := interruptContext(parentCtx, w, r.forceKill, &r.tracker)
+	defer stop()
+
+	// Resolve bead context for worklog (best-effort; warnings only).
+	beadCtx := r.resolveBeadContext(w, bd)
+	r.bead = beadCtx
+
+	input := orchestrator.PipelineInput{
+		BeadID:     r.BeadID,
+		Title:      beadCtx.TaskTitle,
+		BaseBranch: r.BaseBranch,
+		Bead:       beadCtx,
+		SkipPhases: r.skip,
+		Resume:     r.resume,
+	}
+
+	return runner.RunPipeline(ctx, input)
+}
+
+// worktreeStater reports what exists of a bead's worktree. It is satisfied by
+// *worktree.Manager.
+type worktreeStater interface {
+	State(id string) (worktree.State, error)
+}
+
+// recoverLeftover checks for a worktree or branch an earlier run left for
+// the bead. When the branch survives and a checkpoint exists, the run
+// resumes there if --reuse-worktree is set or confirm (nil when not
+// interactive) approves. Otherwise it fails with what was found and how to
+// clear it, instead of git's error from creating the worktree.
+func (r *RunCmd) recoverLeftover(w io.Writer, wt worktreeStater, checkpoints checkpointLoader, confirm func(question string) bool) error {
+	if r.resume {
+		return nil
+	}
+	st, err := wt.State(r.BeadID)
+	if err != nil {
+		return err
+	}
+	if !st.Exists() {
+		return nil
+	}
+	_, found, err := checkpoints.LoadCheckpoint(r.BeadID)
+	if err != nil {
+		return err
+	}
+	if !found || !st.Resumable() {
+		return fmt.Errorf("%s: %s, left by an earlier run: %w\n  start over with: capsule clean %s",
+			r.BeadID, st, worktree.ErrAlreadyExists, r.BeadID)
+	}
+	if !r.ReuseWorktree && (confirm == nil || !confirm(fmt.Sprintf("%s: %s, with a checkpoint from an earlier run. Resume it?", r.BeadID, st))) {
+		return fmt.Errorf("%s: %s, with a checkpoint from an earlier run: %w\n  resume with: capsule run %s --reuse-worktree\n  or start over with: capsule clean %s",
+			r.BeadID, st, worktree.ErrAlreadyExists, r.BeadID, r.BeadID)
+	}
+	_, _ = fmt.Fprintf(w, "Resuming %s in the worktree left by an earlier run\n", r.BeadID)
+	r.resume = true
+	return nil
+}
+
+// isInteractive reports whether stdin and stdout are both terminals, so the
+// user can answer a prompt.
+func isInteractive() bool {
+	return (isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())) &&
+		(isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()))
+}
+
+// askYesNo prints question to w and reads the answer from in. Only y or
+// yes, in any case, counts as yes.
+func askYesNo(in io.Reader, w io.Writer, question string) bool {
+	_, _ = fmt.Fprintf(w, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// pipelinePlanner previews a pipeline for --dry-run.
+type pipelinePlanner interface {
+	Plan(ctx context.Context, input orchestrator.PipelineInput) ([]orchestrator.PhasePlan, error)
+}
+
+// dryRun prints the phase plan for the bead. A missing bead is only a
+// warning; a phase whose prompt, provider, or condition would fail makes
+// the dry run fail.
+func (r *RunCmd) dryRun(w io.Writer, planner pipelinePlanner, bd beadResolver) error {
+	beadCtx := r.resolveBeadContext(w, bd)
+	input := orchestrator.PipelineInput{
+		BeadID:     r.BeadID,
+		Title:      beadCtx.TaskTitle,
+		BaseBranch: r.BaseBranch,
+		Bead:       beadCtx,
+		SkipPhases: r.skip,
+	}
+	plans, err := planner.Plan(context.Background(), input)
+	if err != nil {
+		return fmt.Errorf("run: dry run: %w", err)
+	}
+
+	header := "Dry run for " + r.BeadID
+	if beadCtx.TaskTitle != "" {
+		header += ": " + beadCtx.TaskTitle
+	}
+	_, _ = fmt.Fprintln(w, header)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "#\tPHASE\tKIND\tRUN\tATTEMPTS\tRETRY TARGET\tPROMPT\tDETAILS")
+	var failed []string
+	for i, pl := range plans {
+		run := "yes"
+		if !pl.Run {
+			run = "no"
+		}
+		size := "-"
+		if pl.Phase.Kind != orchestrator.Gate && pl.Err == nil {
+			size = formatSize(int64(pl.PromptBytes))
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			i+1, pl.Phase.Name, pl.Phase.Kind, run, pl.MaxAttempts, dashIfEmpty(pl.Phase.RetryTarget),
+			size, dashIfEmpty(planDetails(pl)))
+		if pl.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", pl.Phase.Name, pl.Err))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("run: dry run: %d phase(s) would fail:\n  %s", len(failed), strings.Join(failed, "\n  "))
+	}
+	_, _ = fmt.Fprintln(w, "Dry run: no worktree created and no provider called.")
+	return nil
+}
+
+// planDetails summarizes how a planned phase would run for the dry-run table.
+func planDetails(pl orchestrator.PhasePlan) string {
+	var parts []string
+	if pl.SkipReason != "" {
+		parts = append(parts, pl.SkipReason)
+	} else if pl.Phase.Condition != "" {
+		parts = append(parts, "condition met: "+pl.Phase.Condition)
+	}
+	if pl.Phase.Command != "" {
+		parts = append(parts, fmt.Sprintf("command=%q", pl.Phase.Command))
+	}
+	if pl.Provider != "" {
+		parts = append(parts, "provider="+pl.Provider)
+	}
+	if pl.PromptFrom != "" {
+		parts = append(parts, "prompt="+pl.PromptFrom)
+	}
+	if pl.Timeout > 0 {
+		parts = append(parts, "timeout="+pl.Timeout.String())
+	}
+	if pl.Workdir != "" {
+		parts = append(parts, "workdir="+pl.Workdir)
+	}
+	if len(pl.Phase.RequiredArtifacts) > 0 {
+		parts = append(parts, "artifacts="+strings.Join(pl.Phase.RequiredArtifacts, ","))
+	}
+	if pl.Phase.Optional {
+		parts = append(parts, "optional")
+	}
+	if pl.Err != nil {
+		parts = append(parts, "FAILS")
+	}
+	return strings.Join(parts, " ")
+}
+
+// findingFiler creates beads for findings. It is satisfied by *bead.Client.
+type findingFiler interface {
+	Create(in bead.CreateInput) (string, error)
+}
+
+// fileFindings files findings at or above r.minSeverity as child beads of
+// the run's bead, with the campaign severity→priority mapping. Less severe
+// findings were already reported by the display and are only counted.
+func (r *RunCmd) fileFindings(w io.Writer, findings []provider.Finding) {
+	if r.filer == nil || len(findings) == 0 {
+		return
+	}
+	threshold := provider.SeverityPriority(r.minSeverity)
+	var below int
+	for _, f := range findings {
+		priority := provider.SeverityPriority(f.Severity)
+		if priority > threshold {
+			below++
+			continue
+		}
+		id, err := r.filer.Create(bead.CreateInput{
+			Title:       f.Title,
+			Description: f.Description,
+			Type:        "task",
+			Priority:    priority,
+			ParentID:    r.BeadID,
+		})
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "warning: filing finding %q: %v\n", f.Title, err)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "Filed finding %q as %s\n", f.Title, id)
+	}
+	if below > 0 {
+		_, _ = fmt.Fprintf(w, "%d finding(s) below %s severity reported but not filed\n", below, r.minSeverity)
+	}
+}
+
+// resolveBeadContext attempts to resolve bead context, logging warnings on failure.
+func (r *RunCmd) resolveBeadContext(w io.Writer, bd beadResolver) worklog.BeadContext {
+	beadCtx, err := bd.Resolve(r.BeadID)
+	switch {
+	case err == nil, errors.Is(err, bead.ErrNoBeadRepo):
+		// A missing database was reported when the command started.
+	case errors.Is(err, bead.ErrNotFound):
+		_, _ = fmt.Fprintf(w, "warning: bead %q not found (try: bd ready)\n", r.BeadID)
+	default:
+		_, _ = fmt.Fprintf(w, "warning: bead resolve failed: %v\n", err)
+	}
+	return beadCtx
+}
+
+// postPipeline performs merge, cleanup, and bead closing after a successful pipeline.
+// Callable from both RunCmd and DashboardCmd. Failures print warnings to w but are
+// otherwise best-effort; the result records which steps succeeded.
+func postPipeline(w io.Writer, in dashboard.PostPipelineInput, wt mergeOps, bd beadResolver) dashboard.PostPipelineResult {
+	// Without a resolver postPipelineWithConflictResolver never fails.
+	result, _ := postPipelineWithConflictResolver(w, in, wt, bd, nil)
+	return result
+}
+
+// postPipelineInput describes a bead and its pipeline's final phase result
+// for the merge commit message.
+func postPipelineInput(beadID string, beadCtx worklog.BeadContext, output orchestrator.PipelineOutput) dashboard.PostPipelineInput {
+	in := dashboard.PostPipelineInput{BeadID: beadID, Title: beadCtx.TaskTitle, Type: beadCtx.TaskType}
+	if n := len(output.PhaseResults); n > 0 {
+		final := output.PhaseResults[n-1].Signal
+		in.Summary = final.Summary
+		in.FilesChanged = final.FilesChanged
+	}
+	return in
+}
+
+// resolvePostPipelineInput describes beadID for the merge commit message when
+// only its ID is known, as for campaign tasks. Resolution is best-effort: on
+// failure the message fields other than the ID are empty.
+func resolvePostPipelineInput(bd beadResolver, beadID string) dashboard.PostPipelineInput {
+	beadCtx, _ := bd.Resolve(beadID)
+	return postPipelineInput(beadID, beadCtx, orchestrator.PipelineOutput{})
+}
+
+// mergeCommitMessage renders the configured merge commit message for in,
+// warning on w and using the default message when the template fails.
+func mergeCommitMessage(w io.Writer, wt mergeOps, in dashboard.PostPipelineInput) string {
+	msg, err := wt.MergeMessage(worktree.MergeMessage{
+		BeadID:       in.BeadID,
+		Title:        in.Title,
+		Type:         in.Type,
+		Summary:      in.Summary,
+		FilesChanged: in.FilesChanged,
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "warning: merge message template: %v (using %q)\n", err, msg)
+	}
+	return msg
+}
+
+// runSummaryReader reads the summary of a bead's last run. It is satisfied
+// by *worklog.Manager.
+type runSummaryReader interface {
+	ReadRunSummary(beadID string) (worklog.RunSummary, error)
+}
+
+// summaryComments returns the run summaries to comment on closed beads, or
+// nil when bead.post_summary_comment is off.
+func summaryComments(cfg *config.Config, wl *worklog.Manager) runSummaryReader {
+	if !cfg.Bead.PostSummaryComment {
+		return nil
+	}
+	return wl
+}
+
+// summaryComment returns the comment describing beadID's last run, or ""
+// when runs is nil or has no summary for it.
+func summaryComment(runs runSummaryReader, beadID string) string {
+	if runs == nil {
+		return ""
+	}
+	s, err := runs.ReadRunSummary(beadID)
+	if err != nil {
+		return ""
+	}
+	return s.Comment()
+}
+
+// beadCommenter adds comments to beads. It is satisfied by *bead.Client.
+type beadCommenter interface {
+	Comment(id, text string) error
+}
+
+// commentCampaign posts the campaign's outcome on its parent bead, warning
+// on w when bd cannot.
+func commentCampaign(w io.Writer, bd beadCommenter, c campaign.Completion) {
+	if err := bd.Comment(c.ParentID, c.Comment()); err != nil {
+		_, _ = fmt.Fprintf(w, "warning: campaign summary comment on %s failed: %v\n", c.ParentID, err)
+	}
+}
+
+// mergeRecorder adds merge outcomes to run summaries. It is satisfied by
+// *worklog.Manager.
+type mergeRecorder interface {
+	RecordMerge(beadID string, merge worklog.MergeSummary) error
+}
+
+// recordMerge adds the post-pipeline result to the bead's run summary,
+// warning on w when it cannot. err is the conflict resolver's error, if any.
+// A nil rec records nothing.
+func recordMerge(w io.Writer, rec mergeRecorder, beadID string, result dashboard.PostPipelineResult, err error) {
+	if rec == nil {
+		return
+	}
+	merge := worklog.MergeSummary{
+		Status:        worklog.MergeFailed,
+		BranchCleaned: result.BranchCleaned,
+		BeadClosed:    result.BeadClosed,
+	}
+	switch {
+	case result.Merged:
+		merge.Status = worklog.MergeMerged
+	case result.MergeConflict:
+		merge.Status = worklog.MergeConflict
+	}
+	if err != nil {
+		merge.Error = err.Error()
+	}
+	if err := rec.RecordMerge(beadID, merge); err != nil && !errors.Is(err, os.ErrNotExist) {
+		_, _ = fmt.Fprintf(w, "warning: recording merge in run summary: %v\n", err)
+	}
+}
+
+// printMergeConflictHelp prints a merge conflict warning with manual
+// resolution steps matching the merge strategy that hit the conflict.
+func printMergeConflictHelp(w io.Writer, beadID, mainBranch string, strategy worktree.MergeStrategy) {
+	branch := worktree.BranchName(beadID)
+	switch strategy {
+	case worktree.MergeSquash:
+		_, _ = fmt.Fprintf(w, "warning: merge conflict squashing %s into %s\n", branch, mainBranch)
+		_, _ = fmt.Fprintf(w, "  To fix:\n")
+		_, _ = fmt.Fprintf(w, "    git checkout %s\n", mainBranch)
+		_, _ = fmt.Fprintf(w, "    git merge --squash %s\n", branch)
+		_, _ = fmt.Fprintf(w, "    # resolve conflicts, then:\n")
+		_, _ = fmt.Fprintf(w, "    git commit\n")
+	case worktree.MergeRebaseFF:
+		_, _ = fmt.Fprintf(w, "warning: merge conflict rebasing %s onto %s\n", branch, mainBranch)
+		_, _ = fmt.Fprintf(w, "  To fix (from the capsule worktree):\n")
+		_, _ = fmt.Fprintf(w, "    git rebase %s\n", mainBranch)
+		_, _ = fmt.Fprintf(w, "    # resolve conflicts, then:\n")
+		_, _ = fmt.Fprintf(w, "    git rebase --continue\n")
+		_, _ = fmt.Fprintf(w, "  Then from the repository root:\n")
+		_, _ = fmt.Fprintf(w, "    git checkout %s\n", mainBranch)
+		_, _ = fmt.Fprintf(w, "    git merge --ff-only %s\n", branch)
+	default:
+		_, _ = fmt.Fprintf(w, "warning: merge conflict merging %s into %s\n", branch, mainBranch)
+		_, _ = fmt.Fprintf(w, "  To fix:\n")
+		_, _ = fmt.Fprintf(w, "    git checkout %s\n", mainBranch)
+		_, _ = fmt.Fprintf(w, "    git merge --no-ff %s\n", branch)
+		_, _ = fmt.Fprintf(w, "    # resolve conflicts, then:\n")
+	}
+	_, _ = fmt.Fprintf(w, "    capsule clean %s\n", beadID)
+}
+
+// postPipelineWithConflictResolver performs merge with conflict resolution support.
+// When merge conflict occurs and resolver is provided, calls resolver and retries merge.
+// The result records which lifecycle steps succeeded; messages go to w.
+// Returns error if resolver fails, allowing campaign to pause.
+func postPipelineWithConflictResolver(w io.Writer, in dashboard.PostPipelineInput, wt mergeOps, bd beadResolver, resolver func(string, error) error) (dashboard.PostPipelineResult, error) {
+	var result dashboard.PostPipelineResult
+	beadID := in.BeadID
+	mainBranch, err := wt.DetectMainBranch()
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "warning: cannot detect main branch: %v\n", err)
+		return result, nil
+	}
+
+	commitMsg := mergeCommitMessage(w, wt, in)
+	err = wt.MergeToMain(beadID, mainBranch, commitMsg)
+	if err != nil {
+		if errors.Is(err, worktree.ErrMergeConflict) && resolver != nil {
+			if resolveErr := resolver(beadID, err); resolveErr != nil {
+				return result, resolveErr
+			}
+			// Retry merge after successful resolution
+			err = wt.MergeToMain(beadID, mainBranch, commitMsg)
+		}
+		if err != nil {
+			if errors.Is(err, worktree.ErrMergeConflict) {
+				result.MergeConflict = true
+				printMergeConflictHelp(w, beadID, mainBranch, wt.MergeStrategy())
+				return result, nil
+			}
+			_, _ = fmt.Fprintf(w, "warning: merge failed: %v\n", err)
+			return result, nil
+		}
+	}
+	result.Merged = true
+	_, _ = fmt.Fprintf(w, "Merged %s → %s\n", worktree.BranchName(beadID), mainBranch)
+
+	result.BranchCleaned = true
+	if err := wt.Remove(beadID, true); err != nil {
+		result.BranchCleaned = false
+		_, _ = fmt.Fprintf(w, "warning: cleanup failed: %v\n", err)
+	}
+	if err := wt.Prune(); err != nil {
+		_, _ = fmt.Fprintf(w, "warning: prune failed: %v\n", err)
+	}
+
+	if in.Comment != "" {
+		if err := bd.Comment(beadID, in.Comment); err != nil {
+			_, _ = fmt.Fprintf(w, "warning: bead summary comment failed: %v\n", err)
+		}
+	}
+	if err := bd.Close(beadID); err != nil {
+		_, _ = fmt.Fprintf(w, "warning: bead close failed: %v\n", err)
+	} else {
+		result.BeadClosed = true
+		_, _ = fmt.Fprintf(w, "Closed %s\n", beadID)
+	}
+
+	_, _ = fmt.Fprintf(w, "Worklog: .capsule/logs/%s/worklog.md\n", beadID)
+	return result, nil
+}
+
+// dashboardPostPipelineFunc adapts postPipelineWithConflictResolver for the
+// dashboard, capturing its output as result messages instead of writing to
+// the terminal the TUI owns. The outcome is recorded with rec when non-nil,
+// and the bead gets its run summary as a comment when comments is non-nil.
+func dashboardPostPipelineFunc(wt mergeOps, bd beadResolver, resolver func(string, error) error, rec mergeRecorder, comments runSummaryReader) dashboard.PostPipelineFunc {
+	return func(in dashboard.PostPipelineInput) (dashboard.PostPipelineResult, error) {
+		var buf bytes.Buffer
+		in.Comment = summaryComment(comments, in.BeadID)
+		result, err := postPipelineWithConflictResolver(&buf, in, wt, bd, resolver)
+		recordMerge(&buf, rec, in.BeadID, result, err)
+		if out := strings.TrimRight(buf.String(), "\n"); out != "" {
+			result.Messages = strings.Split(out, "\n")
+		}
+		return result, err
+	}
+}
+
+// ResumeCmd continues a paused or failed pipeline from its checkpoint.
+type ResumeCmd struct {
+	BeadID          string `arg:"" help:"Bead ID to resume."`
+	Provider        string `help:"Provider to use for completions." default:"claude"`
+	NoTUI           bool   `help:"Force plain text output even if stdout is a TTY." default:"false"`
+	AllowDirty      bool   `help:"Resume even if the repository has uncommitted changes." default:"false"`
+	Profile         string `help:"Phase profile from pipeline.profiles in config; use the one the run started with."`
+	SkipHealthCheck bool   `help:"Start without checking that the provider CLI is installed and logged in." default:"false"`
+	BaseBranch      string `help:"Branch to merge back into; use the one the run started with (default worktree.base_branch, else the main branch)."`
+
+	PhaseTimeoutFlags
+	RunTimeout time.Duration `help:"Deadline for the resumed run, retries included (e.g. 1h)."`
+	MaxCalls   int           `help:"Stop the resumed run after N provider calls, retries included. 0 means no limit."`
+
+	Verbosity string `help:"Plain text detail (--no-tui or no TTY): quiet, normal, or verbose, as for run." enum:"quiet,normal,verbose" default:"normal"`
+}
+
+// checkpointLoader reads saved pipeline checkpoints.
+type checkpointLoader interface {
+	LoadCheckpoint(beadID string) (orchestrator.PipelineCheckpoint, bool, error)
+}
+
+// Help documents the exit codes under capsule resume --help.
+func (c *ResumeCmd) Help() string { return exitCodesHelp }
+
+// Run executes the resume command.
+func (c *ResumeCmd) Run(flags *LogFlags) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("resume: %w", err)
+	}
+	phases, err := loadPipelinePhases(cfg.Pipeline, c.Profile)
+	if err != nil {
+		return fmt.Errorf("resume: loading phases: %w", err)
+	}
+	if err := c.preflight(os.Stdout, state.NewCheckpointFileStore(checkpointDir), newWorktreeManager(cfg), phases); err != nil {
+		return err
+	}
+
+	run := &RunCmd{
+		BeadID:            c.BeadID,
+		Provider:          c.Provider,
+		NoTUI:             c.NoTUI,
+		AllowDirty:        c.AllowDirty,
+		Profile:           c.Profile,
+		SkipHealthCheck:   c.SkipHealthCheck,
+		BaseBranch:        c.BaseBranch,
+		PhaseTimeoutFlags: c.PhaseTimeoutFlags,
+		RunTimeout:        c.RunTimeout,
+		MaxCalls:          c.MaxCalls,
+		Verbosity:         c.Verbosity,
+		resume:            true,
+	}
+	return run.Run(flags)
+}
+
+// preflight checks that the bead has a checkpoint and a worktree to continue
+// in, and prints the phases the resumed run skips.
+func (c *ResumeCmd) preflight(w io.Writer, checkpoints checkpointLoader, wt worktreeStater, phases []orchestrator.PhaseDefinition) error {
+	cp, found, err := checkpoints.LoadCheckpoint(c.BeadID)
+	if err != nil {
+		return fmt.Errorf("resume: %w", err)
+	}
+	if !found {
+		// Runs only checkpoint with pipeline.checkpoint or --run-timeout.
+		return fmt.Errorf("resume: no checkpoint for %q (enable pipeline.checkpoint or pass --run-timeout to save one)", c.BeadID)
+	}
+	// A branch whose directory is gone is re-attached by the pipeline.
+	st, err := wt.State(c.BeadID)
+	if err != nil {
+		return fmt.Errorf("resume: %w", err)
+	}
+	if !st.Resumable() {
+		return fmt.Errorf("resume: worktree for %q no longer exists (%s); start over with: capsule clean %s && capsule run %s", c.BeadID, st, c.BeadID, c.BeadID)
+	}
+
+	_, _ = fmt.Fprintf(w, "Resuming %s from checkpoint saved %s\n", c.BeadID, cp.SavedAt.Local().Format(time.DateTime))
+	for _, pr := range orchestrator.ResumedPhases(cp, phases) {
+		_, _ = fmt.Fprintf(w, "  Skipping %s (%s in checkpoint)\n", pr.PhaseName, pr.Signal.Status)
+	}
+	return nil
+}
+
+// AbortCmd aborts a running capsule by removing the worktree.
+// The branch is preserved so work can be inspected. Use clean to remove everything.
+type AbortCmd struct {
+	BeadID  string `arg:"" help:"Bead ID to abort."`
+	Timeout int    `help:"Seconds to wait for a running pipeline to stop." default:"30"`
+}
+
+// worktreeOps abstracts worktree operations for testing abort and clean commands.
+type worktreeOps interface {
+	Exists(id string) bool
+	State(id string) (worktree.State, error)
+	Remove(id string, deleteBranch bool) error
+	RemoveForce(id string) error
+	Prune() error
+}
+
+// runCanceller abstracts run locks for stopping a live pipeline on abort.
+type runCanceller interface {
+	Holder(beadID string) (runlock.Holder, bool, error)
+	RequestCancel(beadID string) error
+	Interrupt(beadID string) error
+	Wait(beadID string, timeout time.Duration) error
+}
+
+// abortInterruptGrace is how long abort waits for a pipeline to stop after
+// interrupting a holder that ignored the cancel request.
+const abortInterruptGrace = 5 * time.Second
+
+// Run executes the abort command by stopping any running pipeline and
+// removing the worktree.
+func (a *AbortCmd) Run() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("abort: %w", err)
+	}
+
+	mgr := newWorktreeManager(cfg)
+	return a.run(os.Stdout, mgr, newRunLockStore())
+}
+
+// run executes the abort with the given worktree manager and run locks,
+// enabling testable wiring. A running pipeline is asked to stop first, and
+// interrupted if it does not stop within the timeout; if it still runs the
+// worktree is left in place.
+func (a *AbortCmd) run(w io.Writer, mgr worktreeOps, runs runCanceller) error {
+	holder, running, err := runs.Holder(a.BeadID)
+	if err != nil {
+		return fmt.Errorf("abort: %w", err)
+	}
+	if running {
+		where := ""
+		if holder.Phase != "" {
+			where = ", phase " + holder.Phase
+		}
+		_, _ = fmt.Fprintf(w, "Stopping pipeline for %s (pid %d, started %s%s)...\n",
+			a.BeadID, holder.PID, holder.StartedAt.Local().Format(time.DateTime), where)
+		if err := runs.RequestCancel(a.BeadID); err != nil {
+			return fmt.Errorf("abort: %w", err)
+		}
+		if err := runs.Wait(a.BeadID, time.Duration(a.Timeout)*time.Second); err != nil {
+			if !errors.Is(err, runlock.ErrTimeout) {
+				return fmt.Errorf("abort: %w; worktree left in place", err)
+			}
+			_, _ = fmt.Fprintf(w, "Pipeline did not stop; interrupting pid %d...\n", holder.PID)
+			if ierr := runs.Interrupt(a.BeadID); ierr != nil {
+				return fmt.Errorf("abort: %w; %w; worktree left in place", err, ierr)
+			}
+			if err := runs.Wait(a.BeadID, abortInterruptGrace); err != nil {
+				return fmt.Errorf("abort: %w; worktree left in place", err)
+			}
+		}
+		_, _ = fmt.Fprintf(w, "Pipeline for %s stopped\n", a.BeadID)
+	}
+
+	if !mgr.Exists(a.BeadID) {
+		if running {
+			return nil
+		}
+		return fmt.Errorf("abort: no worktree found for %q", a.BeadID)
+	}
+
+	// Preserve branch for inspection; use clean to remove branch.
+	if err := mgr.Remove(a.BeadID, false); err != nil {
+		return fmt.Errorf("abort: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(w, "Aborted capsule %s (branch preserved)\n", a.BeadID)
+	return nil
+}
+
+// CleanCmd cleans up capsule worktree and artifacts.
+type CleanCmd struct {
+	BeadID string `arg:"" help:"Bead ID to clean."`
+	Force  bool   `help:"Delete the capsule branch even if it has commits that were never merged." default:"false"`
+}
+
+// Run executes the clean command by removing worktree, branch, and pruning.
+func (c *CleanCmd) Run() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("clean: %w", err)
+	}
+
+	mgr := newWorktreeManager(cfg)
+	return c.run(os.Stdout, mgr)
+}
+
+// run executes the clean with the given worktree manager, enabling testable wiring.
+func (c *CleanCmd) run(w io.Writer, mgr worktreeOps) error {
+	// Either half of a worktree a crashed run left behind is cleaned.
+	st, err := mgr.State(c.BeadID)
+	if err != nil {
+		return fmt.Errorf("clean: %w", err)
+	}
+	if !st.Exists() {
+		return fmt.Errorf("clean: no worktree found for %q", c.BeadID)
+	}
+
+	remove := func() error { return mgr.Remove(c.BeadID, true) }
+	if c.Force {
+		remove = func() error { return mgr.RemoveForce(c.BeadID) }
+	}
+	removeErr := remove()
+	if removeErr != nil && !errors.Is(removeErr, worktree.ErrUnmerged) {
+		return fmt.Errorf("clean: %w", removeErr)
+	}
+
+	if err := mgr.Prune(); err != nil {
+		return fmt.Errorf("clean: prune: %w", err)
+	}
+
+	if removeErr != nil {
+		// The worktree is gone; only the branch was kept.
+		return fmt.Errorf("clean: %w; kept the branch (merge it, or run capsule clean --force %s to delete it)", removeErr, c.BeadID)
+	}
+	_, _ = fmt.Fprintf(w, "Cleaned capsule %s\n", c.BeadID)
+	return nil
+}
+
+// InitCmd scaffolds the files a project needs to run capsule.
+type InitCmd struct {
+	Force bool   `help:"Overwrite existing config, prompt, and template files." default:"false"`
+	Check bool   `help:"Report missing pieces of an existing setup instead of creating files." default:"false" xor:"init-mode"`
+	Demo  string `help:"Create a runnable demo project in DIR instead: the setup plus sample code, fixture beads, and scripted responses, committed to a new git repo." type:"path" placeholder:"DIR" xor:"init-mode"`
+}
+
+// Run executes the init command in the current directory.
+func (c *InitCmd) Run() error {
+	files, err := scaffold.Manifest(capsule.ConfigTemplate, capsule.Prompts, capsule.Templates)
+	if err != nil {
+		return fmt.Errorf("init: %w", err)
+	}
+	if c.Demo != "" {
+		files, err = scaffold.Demo(files, capsule.DemoProject)
+		if err != nil {
+			return fmt.Errorf("init: %w", err)
+		}
+		return c.demo(os.Stdout, c.Demo, files, runCommand)
+	}
+	if c.Check {
+		return c.check(os.Stdout, ".", files)
+	}
+	return c.run(os.Stdout, ".", files)
+}
+
+// run writes the manifest under root and lists what changed.
+func (c *InitCmd) run(w io.Writer, root string, files []scaffold.File) error {
+	res, err := scaffold.Init(root, files, c.Force)
+	if err != nil {
+		return fmt.Errorf("init: %w", err)
+	}
+	for _, p := range res.Created {
+		_, _ = fmt.Fprintf(w, "created %s\n", p)
+	}
+	for _, p := range res.Overwritten {
+		_, _ = fmt.Fprintf(w, "overwrote %s\n", p)
+	}
+	if len(res.Ignored) > 0 {
+		_, _ = fmt.Fprintf(w, "added to .gitignore: %s\n", strings.Join(res.Ignored, " "))
+	}
+	return nil
+}
+
+// commandRunner runs the named program in dir and returns its combined
+// output. Tests substitute one that fakes bd.
+type commandRunner func(dir, name string, args ...string) ([]byte, error)
+
+// runCommand is the commandRunner that execs the program.
+func runCommand(dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// demo writes the demo project files under dir, creating it, and commits
+// them to a git repo there with the fixture beads imported by bd. Without
+// bd, or if it fails, the beads are left in scaffold.DemoIssues with a
+// warning naming the commands to import them. It ends by printing the
+// commands that run the demo with the scripted provider.
+func (c *InitCmd) demo(w io.Writer, dir string, files []scaffold.File, run commandRunner) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("init: %w", err)
+	}
+	if err := c.run(w, dir, files); err != nil {
+		return err
+	}
+	if out, err := run(dir, "git", "init", "-q"); err != nil {
+		return fmt.Errorf("init: git init: %w\n%s", err, out)
+	}
+
+	imported := true
+	for _, args := range [][]string{{"init", "--prefix=demo"}, {"import", "-i", scaffold.DemoIssues}} {
+		if out, err := run(dir, "bd", args...); err != nil {
+			_, _ = fmt.Fprintf(w, "warning: bd %s failed: %v\n%s", strings.Join(args, " "), err, out)
+			imported = false
+			break
+		}
+	}
+	if imported {
+		_, _ = fmt.Fprintf(w, "imported beads from %s\n", scaffold.DemoIssues)
+	} else {
+		_, _ = fmt.Fprintf(w, "warning: the demo beads are not imported; install bd and run `bd init --prefix=demo && bd import -i %s` in %s\n", scaffold.DemoIssues, dir)
+	}
+
+	// Commit only when something changed, so --force over an unchanged
+	// demo succeeds.
+	if out, err := run(dir, "git", "add", "-A"); err != nil {
+		return fmt.Errorf("init: git add: %w\n%s", err, out)
+	}
+	status, err := run(dir, "git", "status", "--porcelain")
+	if err != nil {
+		return fmt.Errorf("init: git status: %w\n%s", err, status)
+	}
+	if len(bytes.TrimSpace(status)) > 0 {
+		if out, err := run(dir, "git", "commit", "-q", "-m", "Add capsule demo project"); err != nil {
+			return fmt.Errorf("init: git commit: %w\n%s", err, out)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\nDemo ready. Run it offline with the scripted provider:\n\n  cd %s\n  capsule run demo-1.1.1 --provider scripted\n", dir)
+	return nil
+}
+
+// check lists the missing pieces of the setup under root and validates the
+// project config.
+func (c *InitCmd) check(w io.Writer, root string, files []scaffold.File) error {
+	missing, err := scaffold.Check(root, files)
+	if err != nil {
+		return fmt.Errorf("init: %w", err)
+	}
+	for _, p := range missing {
+		_, _ = fmt.Fprintf(w, "missing %s\n", p)
+	}
+	problems := len(missing)
+	cfg, err := config.Load(filepath.Join(root, scaffold.ConfigPath))
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "invalid %s: %v\n", scaffold.ConfigPath, err)
+		problems++
+	}
+	if problems > 0 {
+		return fmt.Errorf("init: setup incomplete: %d problem(s)", problems)
+	}
+	_, _ = fmt.Fprintln(w, "setup complete")
+	return nil
+}
+
+// ConfigCmd groups the commands that inspect the layered config.
+type ConfigCmd struct {
+	Validate ConfigValidateCmd `cmd:"" help:"Validate the config and every phase set it selects, reporting all problems with where each value was set."`
+}
+
+// ConfigValidateCmd loads the config as run does, with env overrides, and
+// reports every problem in it and in the phases it selects.
+type ConfigValidateCmd struct{}
+
+// Run validates the project's layered config.
+func (c *ConfigValidateCmd) Run() error {
+	return c.run(os.Stdout, loadConfig)
+}
+
+// run prints "ok" or one line per problem, enabling testable output. Any
+// problem is an error, so the command exits 2.
+func (c *ConfigValidateCmd) run(w io.Writer, load func() (*config.Config, error)) error {
+	var problems []config.Problem
+	var ve *config.ValidationError
+	cfg, err := load()
+	switch {
+	case errors.As(err, &ve):
+		problems = ve.Problems
+	case err != nil:
+		return fmt.Errorf("config validate: %w", err)
+	default:
+		if err := validateConfig(cfg); errors.As(err, &ve) {
+			problems = ve.Problems
+		}
+		problems = append(problems, phaseProblems(cfg)...)
+	}
+	if len(problems) == 0 {
+		_, _ = fmt.Fprintln(w, "ok")
+		return nil
+	}
+	for _, p := range problems {
+		_, _ = fmt.Fprintln(w, p)
+	}
+	return fmt.Errorf("config validate: %d problem(s)", len(problems))
+}
+
+// phaseProblems loads the pipeline's phases and those of each profile,
+// reporting what is wrong with them against the config key that selects
+// them. A profile that inherits a problem from pipeline.phases does not
+// report it again.
+func phaseProblems(cfg *config.Config) []config.Problem {
+	var problems []config.Problem
+	seen := make(map[string]bool)
+	check := func(path, profile string) {
+		_, err := loadPipelinePhases(cfg.Pipeline, profile)
+		if err == nil {
+			return
+		}
+		msgs := []string{err.Error()}
+		var pe *orchestrator.PhasesError
+		if errors.As(err, &pe) {
+			msgs = pe.Problems
+		}
+		for _, msg := range msgs {
+			if seen[msg] {
+				continue
+			}
+			seen[msg] = true
+			problems = append(problems, config.Problem{Path: path, Source: cfg.Source(path), Message: msg})
+		}
+	}
+	check("pipeline.phases", "")
+	profiles := make([]string, 0, len(cfg.Pipeline.Profiles))
+	for name := range cfg.Pipeline.Profiles {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+	for _, name := range profiles {
+		check("pipeline.profiles."+name, name)
+	}
+	return problems
+}
 
-	pauseCheck, stopPause := setupPauseTrigger()
-	defer stopPause()
+// PromptsCmd groups the commands that manage phase prompts.
+type PromptsCmd struct {
+	Export PromptsExportCmd `cmd:"" help:"Copy a built-in phase prompt to .capsule/prompts for editing."`
+}
 
-	// Build orchestrator.
-	promptLoader := prompt.NewLoader(capsule.OverlayFS("prompts", capsule.Prompts))
-	wtMgr := worktree.NewManager(".", cfg.Worktree.BaseDir)
-	wlMgr := worklog.NewManager(capsule.OverlayFS("templates", capsule.Templates), "worklog.md.template", ".capsule/logs")
-	gateRunner := gate.NewRunner()
-
-	orch := orchestrator.New(p,
-		orchestrator.WithPromptLoader(promptLoader),
-		orchestrator.WithWorktreeManager(wtMgr),
-		orchestrator.WithWorklogManager(wlMgr),
-		orchestrator.WithGateRunner(gateRunner),
-		orchestrator.WithPhases(phases),
-		orchestrator.WithStatusCallback(bridgeStatusCallback(bridge)),
-		orchestrator.WithPauseRequested(pauseCheck),
-	)
+// PromptsExportCmd writes a phase's embedded default prompt to the project
+// override directory, where it takes precedence over the default.
+type PromptsExportCmd struct {
+	Phase string `arg:"" help:"Phase whose prompt to export, e.g. execute."`
+	Force bool   `help:"Overwrite an existing override." default:"false"`
+}
 
-	return r.run(os.Stdout, orch, wtMgr, bdClient, display, bridge, pipelineCtx)
+// Run exports the prompt to .capsule/prompts.
+func (c *PromptsExportCmd) Run() error {
+	return c.run(os.Stdout, capsule.Prompts, projectPromptDir)
 }
 
-// run executes the pipeline with display lifecycle management, enabling testable wiring.
-func (r *RunCmd) run(w io.Writer, runner pipelineRunner, wt mergeOps, bd beadResolver, display tui.Display, bridge *tui.Bridge, pipelineCtx context.Context) error {
-	// Start display goroutine.
-	displayDone := make(chan error, 1)
-	go func() {
-		displayDone <- display.Run(context.Background(), bridge.Events())
-	}()
+// run copies the prompt for c.Phase from defaults into dir.
+func (c *PromptsExportCmd) run(w io.Writer, defaults fs.FS, dir string) error {
+	name := c.Phase + ".md"
+	data, err := fs.ReadFile(defaults, name)
+	if err != nil || strings.ContainsAny(c.Phase, `/\`) {
+		available, _ := fs.Glob(defaults, "*.md")
+		for i, a := range available {
+			available[i] = strings.TrimSuffix(a, ".md")
+		}
+		return fmt.Errorf("prompts export: no built-in prompt for phase %q (available: %s)", c.Phase, strings.Join(available, ", "))
+	}
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err == nil && !c.Force {
+		return fmt.Errorf("prompts export: %s already exists; pass --force to overwrite it", path)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("prompts export: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("prompts export: %w", err)
+	}
+	_, _ = fmt.Fprintf(w, "wrote %s\n", path)
+	return nil
+}
 
-	// Run the pipeline.
-	pipelineErr := r.runPipeline(pipelineCtx, w, runner, bd)
+// PhasesCmd prints the effective pipeline after profiles and overrides,
+// so users can check what run and campaign will execute.
+type PhasesCmd struct {
+	Profile string `help:"Phase profile from pipeline.profiles in config."`
+}
 
-	// Signal display completion.
-	if pipelineErr != nil {
-		bridge.Error(pipelineErr)
-	} else {
-		bridge.Done()
+// Run loads config and prints the resolved phases.
+func (c *PhasesCmd) Run() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("phases: %w", err)
 	}
+	phases, err := loadPipelinePhases(cfg.Pipeline, c.Profile)
+	if err != nil {
+		return fmt.Errorf("phases: %w", err)
+	}
+	return c.run(os.Stdout, phases)
+}
 
-	// Wait for display to finish (so it releases the terminal).
-	<-displayDone
+// run prints phases as a table, enabling testable output.
+func (c *PhasesCmd) run(w io.Writer, phases []orchestrator.PhaseDefinition) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "#\tPHASE\tKIND\tRETRIES\tRETRY TARGET\tDETAILS")
+	for i, p := range phases {
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%s\n",
+			i+1, p.Name, p.Kind, p.MaxRetries, dashIfEmpty(p.RetryTarget), dashIfEmpty(phaseDetails(p)))
+	}
+	return tw.Flush()
+}
 
-	if errors.Is(pipelineErr, orchestrator.ErrPipelinePaused) {
-		_, _ = fmt.Fprintf(w, "Pipeline paused. Resume with: capsule run %s\n", r.BeadID)
-		return pipelineErr
+// phaseDetails summarizes the optional fields of a phase for PhasesCmd.
+func phaseDetails(p orchestrator.PhaseDefinition) string {
+	var parts []string
+	if p.Prompt != "" {
+		parts = append(parts, "prompt="+p.Prompt)
 	}
+	if p.Command != "" {
+		parts = append(parts, fmt.Sprintf("command=%q", p.Command))
+	}
+	if p.Provider != "" {
+		parts = append(parts, "provider="+p.Provider)
+	}
+	if p.Timeout > 0 {
+		parts = append(parts, "timeout="+p.Timeout.String())
+	}
+	if p.Condition != "" {
+		parts = append(parts, "condition="+p.Condition)
+	}
+	if p.Workdir != "" {
+		parts = append(parts, "workdir="+p.Workdir)
+	}
+	if len(p.RequiredArtifacts) > 0 {
+		parts = append(parts, "artifacts="+strings.Join(p.RequiredArtifacts, ","))
+	}
+	if p.Optional {
+		parts = append(parts, "optional")
+	}
+	return strings.Join(parts, " ")
+}
 
-	if pipelineErr != nil {
-		return pipelineErr
+// dashIfEmpty returns "-" for an empty table cell.
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
 	}
+	return s
+}
 
-	// Post-pipeline lifecycle: merge → cleanup → close bead.
-	// Best-effort: pipeline success is the hard requirement.
-	postPipeline(w, r.BeadID, wt, bd)
-	return nil
+// StatusCmd lists in-flight capsules: bead worktrees, saved checkpoints, and
+// campaigns that have not completed.
+type StatusCmd struct {
+	JSON bool `help:"Print JSON instead of a table."`
 }
 
-// runPipeline resolves the bead and runs the pipeline, returning any pipeline error.
-func (r *RunCmd) runPipeline(parentCtx context.Context, w io.Writer, runner pipelineRunner, bd beadResolver) error {
-	// Wrap with OS signal handling so Ctrl+C in non-TUI mode still works.
-	ctx, stop := signal.NotifyContext(parentCtx, os.Interrupt)
-	defer stop()
+// checkpointLister lists saved pipeline checkpoints for StatusCmd.
+type checkpointLister interface {
+	ListCheckpoints() ([]state.CheckpointInfo, error)
+}
 
-	// Resolve bead context for worklog (best-effort; warnings only).
-	beadCtx := r.resolveBeadContext(w, bd)
+// campaignLister lists saved campaign states for StatusCmd.
+type campaignLister interface {
+	List() ([]campaign.State, error)
+}
 
-	input := orchestrator.PipelineInput{
-		BeadID: r.BeadID,
-		Title:  beadCtx.TaskTitle,
-		Bead:   beadCtx,
-	}
+// worktreeLister lists capsule worktrees for StatusCmd.
+type worktreeLister interface {
+	List() ([]string, error)
+}
 
-	_, pipelineErr := runner.RunPipeline(ctx, input)
-	return pipelineErr
+// capsuleStatus is one in-flight capsule in `capsule status` output.
+type capsuleStatus struct {
+	BeadID       string    `json:"bead_id"`
+	Campaign     string    `json:"campaign,omitempty"`   // Parent bead of the unfinished campaign the bead belongs to.
+	LastPhase    string    `json:"last_phase,omitempty"` // Last phase that passed or was skipped.
+	Phases       int       `json:"phases_completed"`
+	CheckpointAt time.Time `json:"checkpoint_at,omitzero"`
+	Worktree     bool      `json:"worktree"`
+	Warning      string    `json:"warning,omitempty"`
 }
 
-// resolveBeadContext attempts to resolve bead context, logging warnings on failure.
-func (r *RunCmd) resolveBeadContext(w io.Writer, bd beadResolver) worklog.BeadContext {
-	beadCtx, err := bd.Resolve(r.BeadID)
+// campaignStatus is one unfinished campaign in `capsule status` output.
+type campaignStatus struct {
+	ParentID  string    `json:"parent_id"`
+	Status    string    `json:"status"`
+	TasksDone int       `json:"tasks_done"` // Tasks that completed, failed, or were skipped.
+	Tasks     int       `json:"tasks_total"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// statusReport is the full `capsule status` output.
+type statusReport struct {
+	Capsules  []capsuleStatus  `json:"capsules"`
+	Campaigns []campaignStatus `json:"campaigns"`
+}
+
+// Run executes the status command.
+func (c *StatusCmd) Run() error {
+	cfg, err := loadConfig()
 	if err != nil {
-		if errors.Is(err, bead.ErrNotFound) {
-			_, _ = fmt.Fprintf(w, "warning: bead %q not found (try: bd ready)\n", r.BeadID)
-		} else {
-			_, _ = fmt.Fprintf(w, "warning: bead resolve failed: %v\n", err)
-		}
+		return fmt.Errorf("status: %w", err)
 	}
-	return beadCtx
+	return c.run(os.Stdout, os.Stderr, newWorktreeManager(cfg),
+		state.NewCheckpointFileStore(checkpointDir), state.NewFileStore(".capsule/campaigns"))
 }
 
-// postPipeline performs merge, cleanup, and bead closing after a successful pipeline.
-// Callable from both RunCmd and DashboardCmd. Failures print warnings to w but are
-// otherwise best-effort.
-func postPipeline(w io.Writer, beadID string, wt mergeOps, bd beadResolver) {
-	// Detect main branch.
-	mainBranch, err := wt.DetectMainBranch()
+// run gathers the status report and prints it, enabling testable wiring.
+// Campaign files that cannot be read are reported to errW as warnings.
+func (c *StatusCmd) run(w, errW io.Writer, wts worktreeLister, cps checkpointLister, camps campaignLister) error {
+	worktrees, err := wts.List()
 	if err != nil {
-		_, _ = fmt.Fprintf(w, "warning: cannot detect main branch: %v\n", err)
-		return
+		return fmt.Errorf("status: %w", err)
 	}
-
-	// Merge to main.
-	commitMsg := fmt.Sprintf("%s: pipeline complete", beadID)
-	err = wt.MergeToMain(beadID, mainBranch, commitMsg)
+	checkpoints, err := cps.ListCheckpoints()
 	if err != nil {
-		if errors.Is(err, worktree.ErrMergeConflict) {
-			_, _ = fmt.Fprintf(w, "warning: merge conflict merging capsule-%s into %s\n", beadID, mainBranch)
-			_, _ = fmt.Fprintf(w, "  To fix:\n")
-			_, _ = fmt.Fprintf(w, "    git checkout %s\n", mainBranch)
-			_, _ = fmt.Fprintf(w, "    git merge --no-ff capsule-%s\n", beadID)
-			_, _ = fmt.Fprintf(w, "    # resolve conflicts, then:\n")
-			_, _ = fmt.Fprintf(w, "    capsule clean %s\n", beadID)
-			return
+		return fmt.Errorf("status: %w", err)
+	}
+	campaigns, err := camps.List()
+	if err != nil {
+		if campaigns == nil {
+			return fmt.Errorf("status: %w", err)
 		}
-		_, _ = fmt.Fprintf(w, "warning: merge failed: %v\n", err)
-		return
+		_, _ = fmt.Fprintf(errW, "warning: %v\n", err)
 	}
-	_, _ = fmt.Fprintf(w, "Merged capsule-%s → %s\n", beadID, mainBranch)
 
-	// Cleanup: remove worktree and branch.
-	if err := wt.Remove(beadID, true); err != nil {
-		_, _ = fmt.Fprintf(w, "warning: cleanup failed: %v\n", err)
-	}
-	if err := wt.Prune(); err != nil {
-		_, _ = fmt.Fprintf(w, "warning: prune failed: %v\n", err)
+	report := buildStatusReport(worktrees, checkpoints, campaigns)
+	if c.JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("status: %w", err)
+		}
+		return nil
 	}
+	return printStatusReport(w, report)
+}
 
-	// Close bead.
-	if err := bd.Close(beadID); err != nil {
-		_, _ = fmt.Fprintf(w, "warning: bead close failed: %v\n", err)
-	} else {
-		_, _ = fmt.Fprintf(w, "Closed %s\n", beadID)
+// buildStatusReport merges worktrees, checkpoints, and campaign states into
+// one row per bead. Worktrees are matched to beads by worktree.SafeName.
+// Completed campaigns are left out.
+func buildStatusReport(worktrees []string, checkpoints []state.CheckpointInfo, campaigns []campaign.State) statusReport {
+	report := statusReport{Capsules: []capsuleStatus{}, Campaigns: []campaignStatus{}}
+	rows := make(map[string]*capsuleStatus)
+	var order []string
+	row := func(id string) *capsuleStatus {
+		if r, ok := rows[id]; ok {
+			return r
+		}
+		rows[id] = &capsuleStatus{BeadID: id}
+		order = append(order, id)
+		return rows[id]
 	}
 
-	_, _ = fmt.Fprintf(w, "Worklog: .capsule/logs/%s/worklog.md\n", beadID)
-}
-
-// postPipelineWithConflictResolver performs merge with conflict resolution support.
-// When merge conflict occurs and resolver is provided, calls resolver and retries merge.
-// Returns error if resolver fails, allowing campaign to pause.
-func postPipelineWithConflictResolver(w io.Writer, beadID string, wt mergeOps, bd beadResolver, resolver func(string, error) error) error {
-	mainBranch, err := wt.DetectMainBranch()
-	if err != nil {
-		_, _ = fmt.Fprintf(w, "warning: cannot detect main branch: %v\n", err)
-		return nil
+	hasWorktree := make(map[string]bool, len(worktrees))
+	for _, name := range worktrees {
+		hasWorktree[name] = true
+	}
+	for _, cp := range checkpoints {
+		r := row(cp.BeadID)
+		r.LastPhase, r.Phases, r.CheckpointAt, r.Warning = cp.LastPhase, cp.Completed, cp.SavedAt, cp.Warning
+		r.Worktree = hasWorktree[worktree.SafeName(cp.BeadID)]
+		delete(hasWorktree, worktree.SafeName(cp.BeadID))
+	}
+	for _, name := range worktrees {
+		if hasWorktree[name] {
+			row(name).Worktree = true
+		}
 	}
 
-	commitMsg := fmt.Sprintf("%s: pipeline complete", beadID)
-	err = wt.MergeToMain(beadID, mainBranch, commitMsg)
-	if err != nil {
-		if errors.Is(err, worktree.ErrMergeConflict) && resolver != nil {
-			if resolveErr := resolver(beadID, err); resolveErr != nil {
-				return resolveErr
-			}
-			// Retry merge after successful resolution
-			err = wt.MergeToMain(beadID, mainBranch, commitMsg)
+	for _, st := range campaigns {
+		if st.Status == campaign.CampaignCompleted {
+			continue
 		}
-		if err != nil {
-			if errors.Is(err, worktree.ErrMergeConflict) {
-				_, _ = fmt.Fprintf(w, "warning: merge conflict merging capsule-%s into %s\n", beadID, mainBranch)
-				_, _ = fmt.Fprintf(w, "  To fix:\n")
-				_, _ = fmt.Fprintf(w, "    git checkout %s\n", mainBranch)
-				_, _ = fmt.Fprintf(w, "    git merge --no-ff capsule-%s\n", beadID)
-				_, _ = fmt.Fprintf(w, "    # resolve conflicts, then:\n")
-				_, _ = fmt.Fprintf(w, "    capsule clean %s\n", beadID)
-				return nil
+		cs := campaignStatus{ParentID: st.ParentBeadID, Status: string(st.Status), Tasks: len(st.Tasks), StartedAt: st.StartedAt}
+		for _, task := range st.Tasks {
+			if task.Status != campaign.TaskPending && task.Status != campaign.TaskRunning {
+				cs.TasksDone++
+			}
+			if r, ok := rows[task.BeadID]; ok {
+				r.Campaign = st.ParentBeadID
+			} else if task.Status == campaign.TaskRunning {
+				row(task.BeadID).Campaign = st.ParentBeadID
 			}
-			_, _ = fmt.Fprintf(w, "warning: merge failed: %v\n", err)
-			return nil
 		}
+		report.Campaigns = append(report.Campaigns, cs)
 	}
-	_, _ = fmt.Fprintf(w, "Merged capsule-%s → %s\n", beadID, mainBranch)
 
-	if err := wt.Remove(beadID, true); err != nil {
-		_, _ = fmt.Fprintf(w, "warning: cleanup failed: %v\n", err)
-	}
-	if err := wt.Prune(); err != nil {
-		_, _ = fmt.Fprintf(w, "warning: prune failed: %v\n", err)
+	sort.Strings(order)
+	for _, id := range order {
+		report.Capsules = append(report.Capsules, *rows[id])
 	}
+	return report
+}
 
-	if err := bd.Close(beadID); err != nil {
-		_, _ = fmt.Fprintf(w, "warning: bead close failed: %v\n", err)
-	} else {
-		_, _ = fmt.Fprintf(w, "Closed %s\n", beadID)
+// printStatusReport prints the status report as tables.
+func printStatusReport(w io.Writer, report statusReport) error {
+	if len(report.Capsules) == 0 && len(report.Campaigns) == 0 {
+		_, _ = fmt.Fprintln(w, "No capsules in flight.")
+		return nil
 	}
-
-	_, _ = fmt.Fprintf(w, "Worklog: .capsule/logs/%s/worklog.md\n", beadID)
-	return nil
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(report.Capsules) > 0 {
+		_, _ = fmt.Fprintln(tw, "BEAD\tCAMPAIGN\tLAST PHASE\tPHASES\tCHECKPOINT\tWORKTREE\tNOTE")
+		for _, r := range report.Capsules {
+			checkpoint := "-"
+			if !r.CheckpointAt.IsZero() {
+				checkpoint = r.CheckpointAt.Local().Format("2006-01-02 15:04")
+			}
+			worktree := "no"
+			if r.Worktree {
+				worktree = "yes"
+			}
+			note := "-"
+			if r.Warning != "" {
+				note = "warning: " + r.Warning
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+				r.BeadID, dashIfEmpty(r.Campaign), dashIfEmpty(r.LastPhase), r.Phases, checkpoint, worktree, note)
+		}
+	}
+	if len(report.Campaigns) > 0 {
+		if len(report.Capsules) > 0 {
+			_, _ = fmt.Fprintln(tw)
+		}
+		_, _ = fmt.Fprintln(tw, "CAMPAIGN\tSTATUS\tTASKS\tSTARTED")
+		for _, cs := range report.Campaigns {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%s\n",
+				cs.ParentID, cs.Status, cs.TasksDone, cs.Tasks, cs.StartedAt.Local().Format("2006-01-02 15:04"))
+		}
+	}
+	return tw.Flush()
 }
 
-// AbortCmd aborts a running capsule by removing the worktree.
-// The branch is preserved so work can be inspected. Use clean to remove everything.
-type AbortCmd struct {
-	BeadID string `arg:"" help:"Bead ID to abort."`
+// LogsCmd shows, lists, and prunes the worklogs archived under .capsule/logs.
+type LogsCmd struct {
+	BeadID    string `arg:"" optional:"" help:"Bead ID whose archived worklog to print."`
+	Summary   bool   `help:"Print only the archived summary."`
+	List      bool   `help:"List all archived beads with dates and sizes." xor:"logs-mode"`
+	Prune     bool   `help:"Delete archives older than --older-than." xor:"logs-mode"`
+	OlderThan string `help:"Age cutoff for --prune (e.g. 30d, 12h)." default:"30d"`
 }
 
-// worktreeOps abstracts worktree operations for testing abort and clean commands.
-type worktreeOps interface {
-	Exists(id string) bool
-	Remove(id string, deleteBranch bool) error
-	Prune() error
+// archiveStore abstracts worklog.Manager's archive operations for testing the logs command.
+type archiveStore interface {
+	ReadWorklog(beadID string) (string, error)
+	ReadSummary(beadID string) (string, error)
+	List() ([]worklog.ArchiveEntry, error)
+	Prune(cutoff time.Time) ([]string, error)
 }
 
-// Run executes the abort command by removing the worktree.
-func (a *AbortCmd) Run() error {
-	cfg, err := loadConfig()
-	if err != nil {
-		return fmt.Errorf("abort: %w", err)
-	}
-
-	mgr := worktree.NewManager(".", cfg.Worktree.BaseDir)
-	return a.run(os.Stdout, mgr)
+// Run executes the logs command against the project's archive directory.
+func (c *LogsCmd) Run() error {
+	return c.run(os.Stdout, newWorklogManager(), time.Now())
 }
 
-// run executes the abort with the given worktree manager, enabling testable wiring.
-func (a *AbortCmd) run(w io.Writer, mgr worktreeOps) error {
-	if !mgr.Exists(a.BeadID) {
-		return fmt.Errorf("abort: no worktree found for %q", a.BeadID)
+// run executes the logs command with the given archive store and clock, enabling testable wiring.
+func (c *LogsCmd) run(w io.Writer, store archiveStore, now time.Time) error {
+	switch {
+	case c.List:
+		return c.list(w, store)
+	case c.Prune:
+		return c.prune(w, store, now)
+	case c.BeadID == "":
+		return fmt.Errorf("logs: specify a bead ID, --list, or --prune")
 	}
 
-	// Preserve branch for inspection; use clean to remove branch.
-	if err := mgr.Remove(a.BeadID, false); err != nil {
-		return fmt.Errorf("abort: %w", err)
+	read, what := store.ReadWorklog, "worklog"
+	if c.Summary {
+		read, what = store.ReadSummary, "summary"
 	}
-
-	_, _ = fmt.Fprintf(w, "Aborted capsule %s (branch preserved)\n", a.BeadID)
+	content, err := read(c.BeadID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("logs: no archived %s for %q", what, c.BeadID)
+		}
+		return fmt.Errorf("logs: %w", err)
+	}
+	_, _ = io.WriteString(w, content)
 	return nil
 }
 
-// CleanCmd cleans up capsule worktree and artifacts.
-type CleanCmd struct {
-	BeadID string `arg:"" help:"Bead ID to clean."`
+// list prints one row per archived bead; damaged archives carry a warning.
+func (c *LogsCmd) list(w io.Writer, store archiveStore) error {
+	entries, err := store.List()
+	if err != nil {
+		return fmt.Errorf("logs: %w", err)
+	}
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(w, "No archived worklogs.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "BEAD\tMODIFIED\tSIZE\tNOTE")
+	for _, e := range entries {
+		note := "-"
+		if e.Warning != "" {
+			note = "warning: " + e.Warning
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			e.BeadID, e.ModTime.Local().Format("2006-01-02 15:04"), formatSize(e.Size), note)
+	}
+	return tw.Flush()
 }
 
-// Run executes the clean command by removing worktree, branch, and pruning.
-func (c *CleanCmd) Run() error {
-	cfg, err := loadConfig()
+// prune deletes archives older than the --older-than cutoff.
+func (c *LogsCmd) prune(w io.Writer, store archiveStore, now time.Time) error {
+	age, err := parseAge(c.OlderThan)
 	if err != nil {
-		return fmt.Errorf("clean: %w", err)
+		return fmt.Errorf("logs: --older-than: %w", err)
 	}
-
-	mgr := worktree.NewManager(".", cfg.Worktree.BaseDir)
-	return c.run(os.Stdout, mgr)
+	removed, err := store.Prune(now.Add(-age))
+	for _, id := range removed {
+		_, _ = fmt.Fprintf(w, "Pruned %s\n", id)
+	}
+	if err != nil {
+		return fmt.Errorf("logs: %w", err)
+	}
+	_, _ = fmt.Fprintf(w, "Pruned %d archive(s) older than %s\n", len(removed), c.OlderThan)
+	return nil
 }
 
-// run executes the clean with the given worktree manager, enabling testable wiring.
-func (c *CleanCmd) run(w io.Writer, mgr worktreeOps) error {
-	if !mgr.Exists(c.BeadID) {
-		return fmt.Errorf("clean: no worktree found for %q", c.BeadID)
+// parseAge parses a positive duration, accepting a whole-day "Nd" form in
+// addition to time.ParseDuration syntax.
+func parseAge(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
 	}
-
-	if err := mgr.Remove(c.BeadID, true); err != nil {
-		return fmt.Errorf("clean: %w", err)
+	if d <= 0 {
+		return 0, fmt.Errorf("age %q must be positive", s)
 	}
+	return d, nil
+}
 
-	if err := mgr.Prune(); err != nil {
-		return fmt.Errorf("clean: prune: %w", err)
+// formatSize renders a byte count with a binary unit suffix.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
 	}
-
-	_, _ = fmt.Fprintf(w, "Cleaned capsule %s\n", c.BeadID)
-	return nil
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
 // --- Dashboard command ---
 
 // DashboardCmd opens the interactive dashboard TUI.
-type DashboardCmd struct{}
+type DashboardCmd struct {
+	AllowDirty      bool `help:"Dispatch even if the repository has uncommitted changes." default:"false"`
+	SkipHealthCheck bool `help:"Don't check at startup that the provider CLI is installed and logged in." default:"false"`
+}
 
 // teaRunner abstracts Bubble Tea program execution for testing.
 type teaRunner interface {
@@ -529,38 +2745,48 @@ type teaRunner interface {
 }
 
 // Run builds real dependencies and launches the dashboard TUI.
-func (d *DashboardCmd) Run() error {
+func (d *DashboardCmd) Run(flags *LogFlags) error {
 	if !isatty.IsTerminal(os.Stdout.Fd()) && !isatty.IsCygwinTerminal(os.Stdout.Fd()) {
 		return fmt.Errorf("dashboard: requires a terminal (TTY)")
 	}
 
-	if _, err := exec.LookPath("bd"); err != nil {
-		return fmt.Errorf("dashboard: bd is not installed (required for bead management)")
+	if err := bead.NewClient(".").Check(); err != nil {
+		return fmt.Errorf("dashboard: %w", beadSetupError(err))
 	}
 
 	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("dashboard: %w", err)
 	}
-
-	// Create provider via registry.
-	reg := provider.NewRegistry()
-	provider.RegisterBuiltins(reg, cfg.Runtime.Timeout)
-	p, err := reg.NewProvider(cfg.Runtime.Provider)
+	logger, closeLog, err := flags.logger(true)
 	if err != nil {
 		return fmt.Errorf("dashboard: %w", err)
 	}
+	defer func() { _ = closeLog() }()
 
-	// Resolve pipeline phases.
-	phases, err := orchestrator.LoadPhases(cfg.Pipeline.Phases)
+	// Phases without a timeout of their own inherit runtime.timeout, read
+	// before the longest phase timeout raises it.
+	phaseTimeout := cfg.Runtime.Timeout
+	setup, err := resolvePhasesAndProvider(cfg, "", cfg.Campaign.ValidationPhases, nil, provider.WithLogger(logger))
 	if err != nil {
-		return fmt.Errorf("dashboard: loading phases: %w", err)
+		return fmt.Errorf("dashboard: %w", err)
 	}
-
-	bdClient := bead.NewClient(".")
+	phases, reg, p, providers := setup.phases, setup.registry, setup.provider, setup.providers
+
+	// The browser re-reads beads on every cursor move and reload; a short
+	// TTL cache keeps that from running bd each time.
+	bdClient := bead.NewCachedClient(bead.NewClient("."), cfg.Dashboard.BeadCacheTTL)
+	// The dashboard always checkpoints, so it always prunes; in the
+	// background, since bd lists the closed beads.
+	go pruneCheckpoints(cfg, bdClient, logger)
 	lister := &beadListerAdapter{client: bdClient}
 	resolver := &beadResolverAdapter{client: bdClient}
-	wtMgr := worktree.NewManager(".", cfg.Worktree.BaseDir)
+	wtMgr := newWorktreeManager(cfg, worktree.WithLogger(logger))
+	wlMgr := newWorklogManager()
+	baseBranch, err := resolveBaseBranch("", cfg, wtMgr)
+	if err != nil {
+		return fmt.Errorf("dashboard: %w", err)
+	}
 
 	// Construct ConflictResolver to invoke agent pair for conflict resolution
 	conflictResolver := func(beadID string, conflictErr error) error {
@@ -578,12 +2804,15 @@ func (d *DashboardCmd) Run() error {
 		beadContext := fmt.Sprintf("%s: %s\n\n%s", beadID, beadCtx.TaskTitle, beadCtx.TaskDescription)
 
 		// Build orchestrator for conflict resolution
-		orch := orchestrator.New(p,
-			orchestrator.WithPromptLoader(prompt.NewLoader(capsule.OverlayFS("prompts", capsule.Prompts))),
-			orchestrator.WithWorktreeManager(wtMgr),
-			orchestrator.WithWorklogManager(worklog.NewManager(capsule.OverlayFS("templates", capsule.Templates), "worklog.md.template", ".capsule/logs")),
-			orchestrator.WithGateRunner(gate.NewRunner()),
-			orchestrator.WithPhases(phases),
+		orch := capsule.NewPipeline(p,
+			capsule.WithPromptLoader(newPromptLoader()),
+			capsule.WithWorktreeManager(wtMgr),
+			capsule.WithWorklogManager(wlMgr),
+			capsule.WithGateRunner(gate.NewRunner(gate.WithMaxOutput(cfg.Pipeline.GateOutputMaxBytes))),
+			capsule.WithPhases(phases),
+			capsule.WithProviders(providers),
+			capsule.WithLogDir(".capsule/logs"),
+			capsule.WithLogger(logger),
 		)
 
 		// Run conflict resolution
@@ -598,55 +2827,118 @@ func (d *DashboardCmd) Run() error {
 
 		ctx, cancel := context.WithTimeout(context.Background(), cfg.Runtime.Timeout)
 		defer cancel()
-		return orch.RunConflictResolution(ctx, resolveInput)
+		return orch.ResolveConflicts(ctx, resolveInput)
 	}
 
+	comments := summaryComments(cfg, wlMgr)
 	postTaskFunc := func(beadID string) error {
-		return postPipelineWithConflictResolver(os.Stderr, beadID, wtMgr, bdClient, conflictResolver)
+		in := resolvePostPipelineInput(bdClient, beadID)
+		in.Comment = summaryComment(comments, beadID)
+		result, err := postPipelineWithConflictResolver(os.Stderr, in, mergeTarget(wtMgr, baseBranch), bdClient, conflictResolver)
+		recordMerge(os.Stderr, wlMgr, beadID, result, err)
+		return err
 	}
 
 	pauseCheck, stopPause := setupPauseTrigger()
 	defer stopPause()
 
 	pipelineAdapter := &dashboardPipelineAdapter{
-		providerExec: p,
-		registry:     reg,
-		promptLoader: prompt.NewLoader(capsule.OverlayFS("prompts", capsule.Prompts)),
-		wtMgr:        wtMgr,
-		wlMgr:        worklog.NewManager(capsule.OverlayFS("templates", capsule.Templates), "worklog.md.template", ".capsule/logs"),
-		gateRunner:   gate.NewRunner(),
-		phases:       phases,
-		bdClient:     bdClient,
-		pauseCheck:   pauseCheck,
+		providerExec:     p,
+		registry:         reg,
+		promptLoader:     newPromptLoader(),
+		wtMgr:            wtMgr,
+		wlMgr:            wlMgr,
+		gateRunner:       gate.NewRunner(gate.WithMaxOutput(cfg.Pipeline.GateOutputMaxBytes)),
+		phases:           phases,
+		phaseResolver:    phaseSetResolver(cfg.Pipeline),
+		providers:        providers,
+		bdClient:         bdClient,
+		pauseCheck:       pauseCheck,
+		providerFactory:  labelProviderFactory(cfg, provider.WithLogger(logger)),
+		timeout:          cfg.Runtime.Timeout,
+		phaseTimeout:     phaseTimeout,
+		transientRetries: cfg.Runtime.TransientRetries,
+		backoffMax:       cfg.Runtime.BackoffMax,
+		contextFiles:     cfg.Pipeline.ContextFiles,
+		contextFileBytes: cfg.Pipeline.ContextFileMaxBytes,
+		workdirs:         cfg.Pipeline.Workdirs,
+		transcriptDir:    transcriptDir(cfg.Pipeline.SaveTranscripts),
+		perPhaseCommits:  cfg.Pipeline.PerPhaseCommits,
+		baseBranch:       baseBranch,
+		runs:             newRunLockStore(),
+		// Always checkpoint: a run paused with p resumes from its checkpoint.
+		checkpoints: state.NewCheckpointFileStore(checkpointDir, state.WithCheckpointLogger(logger)),
+		logger:      logger,
 	}
 
+	campaignStore := state.NewFileStore(".capsule/campaigns")
 	campaignAdapter := &dashboardCampaignAdapter{
 		beadClient: newCampaignBeadClient("."),
-		stateStore: state.NewFileStore(".capsule/campaigns"),
+		stateStore: campaignStore,
 		campaignCfg: campaign.Config{
-			FailureMode:      cfg.Campaign.FailureMode,
-			CircuitBreaker:   cfg.Campaign.CircuitBreaker,
-			DiscoveryFiling:  cfg.Campaign.DiscoveryFiling,
-			CrossRunContext:  cfg.Campaign.CrossRunContext,
-			ValidationPhases: cfg.Campaign.ValidationPhases,
+			FailureMode:       cfg.Campaign.FailureMode,
+			Ordering:          cfg.Campaign.Ordering,
+			CircuitBreaker:    campaignBreaker(cfg.Campaign),
+			DiscoveryFiling:   cfg.Campaign.DiscoveryFiling,
+			PickUpDiscoveries: cfg.Campaign.PickUpDiscoveries,
+			MinSeverity:       cfg.Pipeline.FindingMinSeverity,
+			CrossRunContext:   cfg.Campaign.CrossRunContext,
+			ValidationPhases:  cfg.Campaign.ValidationPhases,
+			CloseParent:       cfg.Campaign.CloseParent,
+			// The dashboard always continues an interrupted campaign,
+			// retrying the tasks that failed.
+			Resume:           true,
+			RetryFailed:      true,
+			Worklog:          wlMgr,
 			PostTaskFunc:     postTaskFunc,
 			ConflictResolver: conflictResolver,
+			Log:              logger,
 		},
 	}
+	if comments != nil {
+		campaignAdapter.campaignCfg.CompleteFunc = func(c campaign.Completion) {
+			commentCampaign(os.Stderr, bdClient, c)
+		}
+	}
 
-	archiveReader := dashboard.NewFileArchiveReader(".capsule/logs")
-
-	m := dashboard.NewModel(
+	opts := []dashboard.ModelOption{
 		dashboard.WithBeadLister(lister),
 		dashboard.WithBeadResolver(resolver),
-		dashboard.WithPostPipelineFunc(postTaskFunc),
+		dashboard.WithBeadCache(&beadCacheAdapter{client: bdClient}),
+		dashboard.WithBeadCreator(&beadCreatorAdapter{client: bdClient}),
+		dashboard.WithPostPipelineFunc(dashboardPostPipelineFunc(mergeTarget(wtMgr, baseBranch), bdClient, conflictResolver, wlMgr, comments)),
 		dashboard.WithPipelineRunner(pipelineAdapter),
 		dashboard.WithPhaseNames(phaseNames(phases)),
 		dashboard.WithCampaignRunner(campaignAdapter),
-		dashboard.WithArchiveReader(archiveReader),
+		dashboard.WithCampaignTaskStore(&dashboardTaskStore{store: campaignStore}),
+		dashboard.WithArchiveReader(archiveReaderAdapter{wlMgr}),
 		dashboard.WithCampaignValidation(cfg.Campaign.ValidationPhases != ""),
+		dashboard.WithCampaignPolicy(cfg.Campaign.FailureMode, describeBreaker(campaignBreaker(cfg.Campaign))),
 		dashboard.WithProviderNames(reg.AvailableProviders(), cfg.Runtime.Provider),
-	)
+		dashboard.WithNotifyFunc(dashboardNotifyFunc(newNotifier(cfg))),
+		dashboard.WithOverlapCheck(wtMgr.OverlappingChanges),
+		dashboard.WithWorktreeFunc(func(id string) (string, string) {
+			return wtMgr.Path(id), worktree.BranchName(id)
+		}),
+		dashboard.WithWorklogPathFunc(func(id string) string {
+			return wlMgr.Path(wtMgr.Path(id), id)
+		}),
+		dashboard.WithRefreshInterval(cfg.Dashboard.RefreshInterval),
+	}
+	if cfg.Pipeline.Checkpoint {
+		opts = append(opts, dashboard.WithCheckpointResume())
+	}
+	if !d.SkipHealthCheck {
+		opts = append(opts, dashboard.WithHealthCheck(func() error {
+			return p.HealthCheck(context.Background())
+		}))
+	}
+	if !d.AllowDirty {
+		opts = append(opts, dashboard.WithDispatchCheck(func() error {
+			return checkCleanRepo(wtMgr)
+		}))
+	}
+	m := dashboard.NewModel(opts...)
 
 	prog := tea.NewProgram(m, tea.WithAltScreen())
 	return d.run(true, prog)
@@ -666,18 +2958,52 @@ func (d *DashboardCmd) run(isTTY bool, prog teaRunner) error {
 // dashboardPipelineAdapter implements dashboard.PipelineRunner by building
 // a fresh orchestrator per run with the provided statusFn callback.
 type dashboardPipelineAdapter struct {
-	providerExec provider.Executor
-	registry     *provider.Registry // Used for per-dispatch provider creation when input.Provider is set.
-	promptLoader *prompt.Loader
-	wtMgr        *worktree.Manager
-	wlMgr        *worklog.Manager
-	gateRunner   *gate.Runner
-	phases       []orchestrator.PhaseDefinition
-	bdClient     *bead.Client
-	pauseCheck   func() bool
+	providerExec  provider.Executor
+	registry      *provider.Registry // Used for per-dispatch provider creation when input.Provider is set.
+	promptLoader  *prompt.Loader
+	wtMgr         *worktree.Manager
+	wlMgr         *worklog.Manager
+	gateRunner    *gate.Runner
+	phases        []orchestrator.PhaseDefinition
+	phaseResolver orchestrator.PhaseResolver       // Resolves the validation phase set (see loadPhaseSet).
+	providers     map[string]orchestrator.Provider // Providers named by phases (see phaseProviders).
+	bdClient      *bead.CachedClient
+	pauseCheck    func() bool
+	// Applies capsule:provider and capsule:timeout bead labels; timeout is the
+	// provider timeout for beads that override only the provider.
+	providerFactory orchestrator.ProviderFactory
+	timeout         time.Duration
+	phaseTimeout    time.Duration // Timeout for phases that don't set their own; 0 means none.
+	// Transient provider failure retries (runtime.transient_retries, runtime.backoff_max).
+	transientRetries int
+	backoffMax       time.Duration
+	// Files snapshotted into prompt context (pipeline.context_files).
+	contextFiles     []string
+	contextFileBytes int
+	workdirs         map[string]string            // Bead ID prefix → working directory (pipeline.workdirs).
+	transcriptDir    string                       // Phase transcripts root; "" disables them (pipeline.save_transcripts).
+	perPhaseCommits  bool                         // Commit the worktree after each passing phase (pipeline.per_phase_commits).
+	baseBranch       string                       // Branch worktrees start from (worktree.base_branch); empty uses the default.
+	runs             *runlock.Store               // Run locks that let `capsule abort` cancel a dispatch; nil disables them.
+	checkpoints      orchestrator.CheckpointStore // Lets paused and failed runs be resumed; nil disables it.
+	logger           *slog.Logger                 // Structured debug log; nil discards.
 }
 
 func (a *dashboardPipelineAdapter) RunPipeline(ctx context.Context, input dashboard.PipelineInput, statusFn func(dashboard.PhaseUpdateMsg)) (dashboard.PipelineOutput, error) {
+	var tracker phaseTracker
+	if a.runs != nil {
+		lock, err := a.runs.Acquire(input.BeadID)
+		if err != nil {
+			return dashboard.PipelineOutput{}, err
+		}
+		defer func() { _ = lock.Release() }()
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		go lock.Watch(ctx, cancel)
+		tracker.setLock(lock)
+	}
+
 	// Resolve provider: use registry for per-dispatch creation when specified,
 	// otherwise fall back to the default provider.
 	exec := a.providerExec
@@ -691,94 +3017,214 @@ func (a *dashboardPipelineAdapter) RunPipeline(ctx context.Context, input dashbo
 
 	// Build status callback that converts orchestrator updates to dashboard messages.
 	cb := func(su orchestrator.StatusUpdate) {
-		msg := dashboard.PhaseUpdateMsg{
-			Phase:    su.Phase,
-			Status:   dashboard.PhaseStatus(su.Status),
-			Attempt:  su.Attempt,
-			MaxRetry: su.MaxRetry,
-			Duration: su.Duration,
-		}
-		if su.Signal != nil {
-			msg.Summary = su.Signal.Summary
-			msg.FilesChanged = su.Signal.FilesChanged
-			msg.Feedback = su.Signal.Feedback
+		if su.Warning != "" {
+			return // The phase list has no place for warnings.
 		}
-		statusFn(msg)
+		statusFn(phaseUpdateMsg(su))
+	}
+
+	opts := []capsule.Option{
+		capsule.WithPromptLoader(a.promptLoader),
+		capsule.WithWorktreeManager(a.wtMgr),
+		capsule.WithChangeLister(a.wtMgr),
+		capsule.WithWorklogManager(a.wlMgr),
+		capsule.WithSummaryWriter(a.wlMgr),
+		capsule.WithGateRunner(a.gateRunner),
+		capsule.WithPhases(a.phases),
+		capsule.WithPhaseResolver(a.phaseResolver),
+		capsule.WithProviders(a.providers),
+		capsule.WithLogDir(".capsule/logs"),
+		capsule.WithTranscriptDir(a.transcriptDir),
+		capsule.WithPerPhaseCommits(a.perPhaseCommits),
+		capsule.WithStatusCallback(tracker.wrap(cb)),
+		capsule.WithContextFiles(a.contextFiles, a.contextFileBytes),
+		capsule.WithWorkdirs(a.workdirs),
+	}
+	if pause := anyPauseRequested(a.pauseCheck, input.PauseRequested); pause != nil {
+		opts = append(opts, capsule.WithPauseRequested(pause))
+	}
+	if a.checkpoints != nil {
+		opts = append(opts, capsule.WithCheckpointStore(a.checkpoints))
+	}
+	if a.logger != nil {
+		opts = append(opts, capsule.WithLogger(a.logger))
+	}
+	if a.providerFactory != nil {
+		opts = append(opts, capsule.WithProviderFactory(a.providerFactory, a.timeout))
+	}
+	if a.baseBranch != "" {
+		opts = append(opts, capsule.WithBaseBranch(a.baseBranch))
+	}
+	if a.phaseTimeout > 0 {
+		opts = append(opts, capsule.WithPhaseTimeout(a.phaseTimeout))
+	}
+	if a.transientRetries > 0 {
+		opts = append(opts, capsule.WithTransientRetries(a.transientRetries, a.backoffMax))
+	}
+	orch := capsule.NewPipeline(exec, opts...)
+
+	// Resolve bead context (best-effort).
+	beadCtx, _ := a.bdClient.Resolve(input.BeadID)
+
+	orchInput := orchestrator.PipelineInput{
+		BeadID:         input.BeadID,
+		Title:          beadCtx.TaskTitle,
+		Bead:           beadCtx,
+		SiblingContext: input.SiblingContext,
+		Resume:         input.Resume,
+		Phases:         input.Phases,
+	}
+
+	output, err := orch.Run(ctx, orchInput)
+	reports := phaseResultsToReports(output.PhaseResults)
+	if errors.Is(err, orchestrator.ErrPipelinePaused) {
+		err = dashboard.ErrPipelinePaused
+	}
+	if err != nil {
+		// Keep partial reports so callers can show the failing phase.
+		return dashboard.PipelineOutput{PhaseReports: reports, Findings: output.Findings}, err
+	}
+
+	return dashboard.PipelineOutput{
+		Success:      output.Completed,
+		PhaseReports: reports,
+		Findings:     output.Findings,
+	}, nil
+}
+
+// phaseUpdateMsg converts an orchestrator status update to its dashboard
+// message.
+func phaseUpdateMsg(su orchestrator.StatusUpdate) dashboard.PhaseUpdateMsg {
+	msg := dashboard.PhaseUpdateMsg{
+		Phase:            su.Phase,
+		Status:           dashboard.PhaseStatus(su.Status),
+		PhaseIndex:       su.PhaseIndex,
+		PhaseTotal:       su.PhaseTotal,
+		FractionComplete: su.FractionComplete,
+		Attempt:          su.Attempt,
+		MaxRetry:         su.MaxRetry,
+		Duration:         su.Duration,
+		Usage:            su.Usage,
+		Message:          su.Message,
+		MissingArtifacts: su.MissingArtifacts,
 	}
+	if su.Signal != nil {
+		msg.Summary = su.Signal.Summary
+		msg.FilesChanged = su.Signal.FilesChanged
+		msg.Feedback = su.Signal.Feedback
+	}
+	return msg
+}
 
-	opts := []orchestrator.Option{
-		orchestrator.WithPromptLoader(a.promptLoader),
-		orchestrator.WithWorktreeManager(a.wtMgr),
-		orchestrator.WithWorklogManager(a.wlMgr),
-		orchestrator.WithGateRunner(a.gateRunner),
-		orchestrator.WithPhases(a.phases),
-		orchestrator.WithStatusCallback(cb),
+// statusUpdate converts a dashboard phase message back to the orchestrator
+// status update it came from.
+func statusUpdate(beadID string, msg dashboard.PhaseUpdateMsg) orchestrator.StatusUpdate {
+	su := orchestrator.StatusUpdate{
+		BeadID:           beadID,
+		Phase:            msg.Phase,
+		Status:           orchestrator.PhaseStatus(msg.Status),
+		PhaseIndex:       msg.PhaseIndex,
+		PhaseTotal:       msg.PhaseTotal,
+		FractionComplete: msg.FractionComplete,
+		Attempt:          msg.Attempt,
+		MaxRetry:         msg.MaxRetry,
+		Duration:         msg.Duration,
+		Usage:            msg.Usage,
+		Message:          msg.Message,
+		MissingArtifacts: msg.MissingArtifacts,
 	}
-	if a.pauseCheck != nil {
-		opts = append(opts, orchestrator.WithPauseRequested(a.pauseCheck))
+	if msg.Status != dashboard.PhaseRunning && msg.Status != dashboard.PhaseProgress && msg.Status != dashboard.PhaseRetrying {
+		su.Signal = &provider.Signal{
+			Summary:      msg.Summary,
+			Feedback:     msg.Feedback,
+			FilesChanged: msg.FilesChanged,
+		}
 	}
-	orch := orchestrator.New(exec, opts...)
-
-	// Resolve bead context (best-effort).
-	beadCtx, _ := a.bdClient.Resolve(input.BeadID)
+	return su
+}
 
-	orchInput := orchestrator.PipelineInput{
-		BeadID:         input.BeadID,
-		Title:          beadCtx.TaskTitle,
-		Bead:           beadCtx,
-		SiblingContext: input.SiblingContext,
+// anyPauseRequested combines pause checks, reporting a pause when any of
+// them does. Nil checks are ignored; it returns nil when all are nil.
+func anyPauseRequested(checks ...func() bool) func() bool {
+	var active []func() bool
+	for _, c := range checks {
+		if c != nil {
+			active = append(active, c)
+		}
 	}
-
-	output, err := orch.RunPipeline(ctx, orchInput)
-	if err != nil {
-		return dashboard.PipelineOutput{}, err
+	if len(active) == 0 {
+		return nil
+	}
+	return func() bool {
+		for _, c := range active {
+			if c() {
+				return true
+			}
+		}
+		return false
 	}
+}
 
-	// Convert phase results.
-	reports := make([]dashboard.PhaseReport, len(output.PhaseResults))
-	for i, pr := range output.PhaseResults {
+// phaseResultsToReports converts orchestrator phase results to dashboard reports.
+func phaseResultsToReports(results []orchestrator.PhaseResult) []dashboard.PhaseReport {
+	reports := make([]dashboard.PhaseReport, len(results))
+	for i, pr := range results {
 		reports[i] = dashboard.PhaseReport{
 			PhaseName:    pr.PhaseName,
-			Status:       dashboard.PhaseStatus(pr.Signal.Status),
+			Status:       providerStatusToDashboard(pr.Signal.Status),
 			Summary:      pr.Signal.Summary,
 			FilesChanged: pr.Signal.FilesChanged,
 			Feedback:     pr.Signal.Feedback,
 			Duration:     pr.Duration,
+			Usage:        pr.Usage,
 		}
 	}
+	return reports
+}
 
-	return dashboard.PipelineOutput{
-		Success:      output.Completed,
-		PhaseReports: reports,
-	}, nil
+// reportsToPhaseResults converts dashboard reports back to orchestrator phase results.
+func reportsToPhaseResults(reports []dashboard.PhaseReport) []orchestrator.PhaseResult {
+	results := make([]orchestrator.PhaseResult, len(reports))
+	for i, pr := range reports {
+		results[i] = orchestrator.PhaseResult{
+			PhaseName: pr.PhaseName,
+			Signal: provider.Signal{
+				Status:       dashboardStatusToProvider(pr.Status),
+				Summary:      pr.Summary,
+				FilesChanged: pr.FilesChanged,
+				Feedback:     pr.Feedback,
+			},
+			Duration: pr.Duration,
+			Usage:    pr.Usage,
+		}
+	}
+	return results
 }
 
-// beadListerAdapter wraps *bead.Client to implement dashboard.BeadLister.
+// beadListerAdapter wraps *bead.CachedClient to implement dashboard.BeadLister.
 type beadListerAdapter struct {
-	client *bead.Client
+	client *bead.CachedClient
 }
 
 func (a *beadListerAdapter) Ready() ([]dashboard.BeadSummary, error) {
-	summaries, err := a.client.Ready()
-	if err != nil {
-		return nil, err
-	}
-	beads := make([]dashboard.BeadSummary, len(summaries))
-	for i, s := range summaries {
-		beads[i] = dashboard.BeadSummary{
-			ID:       s.ID,
-			Title:    s.Title,
-			Priority: s.Priority,
-			Type:     s.Type,
-		}
-	}
-	return beads, nil
+	summaries, err := a.client.ReadyCached()
+	return toBeadSummaries(summaries), err
 }
 
 func (a *beadListerAdapter) Closed(limit int) ([]dashboard.BeadSummary, error) {
-	summaries, err := a.client.Closed(limit)
-	if err != nil {
-		return nil, err
+	summaries, err := a.client.ClosedCached(limit)
+	return toBeadSummaries(summaries), err
+}
+
+func (a *beadListerAdapter) List(states []string) ([]dashboard.BeadSummary, error) {
+	summaries, err := a.client.ListCached(states)
+	return toBeadSummaries(summaries), err
+}
+
+// toBeadSummaries converts bd list results for the dashboard's bead list.
+func toBeadSummaries(summaries []bead.Summary) []dashboard.BeadSummary {
+	if summaries == nil {
+		return nil
 	}
 	beads := make([]dashboard.BeadSummary, len(summaries))
 	for i, s := range summaries {
@@ -787,25 +3233,29 @@ func (a *beadListerAdapter) Closed(limit int) ([]dashboard.BeadSummary, error) {
 			Title:    s.Title,
 			Priority: s.Priority,
 			Type:     s.Type,
+			Status:   s.Status,
 		}
 	}
-	return beads, nil
+	return beads
 }
 
-// beadResolverAdapter wraps *bead.Client to implement dashboard.BeadResolver.
+// beadResolverAdapter wraps *bead.CachedClient to implement dashboard.BeadResolver.
 type beadResolverAdapter struct {
-	client *bead.Client
+	client *bead.CachedClient
 }
 
 func (a *beadResolverAdapter) Resolve(id string) (dashboard.BeadDetail, error) {
-	ctx, err := a.client.Resolve(id)
+	ctx, err := a.client.ResolveCached(id)
 	if err != nil {
 		return dashboard.BeadDetail{}, err
 	}
-	// Priority and Type are zero-valued: worklog.BeadContext does not carry them.
 	return dashboard.BeadDetail{
 		ID:           ctx.TaskID,
 		Title:        ctx.TaskTitle,
+		Priority:     ctx.TaskPriority,
+		Type:         ctx.TaskType,
+		Status:       ctx.TaskStatus,
+		Labels:       ctx.Labels,
 		Description:  ctx.TaskDescription,
 		Acceptance:   ctx.AcceptanceCriteria,
 		EpicID:       ctx.EpicID,
@@ -815,6 +3265,51 @@ func (a *beadResolverAdapter) Resolve(id string) (dashboard.BeadDetail, error) {
 	}, nil
 }
 
+// archiveReaderAdapter wraps *worklog.Manager to implement dashboard.ArchiveReader.
+type archiveReaderAdapter struct {
+	*worklog.Manager
+}
+
+func (a archiveReaderAdapter) ReadPhaseEntries(beadID string) ([]dashboard.ArchivedPhase, error) {
+	entries, err := a.Manager.ReadPhaseEntries(beadID)
+	if err != nil {
+		return nil, err
+	}
+	phases := make([]dashboard.ArchivedPhase, len(entries))
+	for i, e := range entries {
+		phases[i] = dashboard.ArchivedPhase{Name: e.Name, Status: e.Status, Attempt: e.Attempt, Duration: e.Duration}
+	}
+	return phases, nil
+}
+
+// beadCacheAdapter wraps *bead.CachedClient to implement dashboard.BeadCache.
+type beadCacheAdapter struct {
+	client *bead.CachedClient
+}
+
+func (a *beadCacheAdapter) Invalidate()      { a.client.Invalidate() }
+func (a *beadCacheAdapter) InvalidateLists() { a.client.InvalidateLists() }
+
+func (a *beadCacheAdapter) Stats() (hits, misses int64) {
+	s := a.client.Stats()
+	return s.Hits, s.Misses
+}
+
+// beadCreatorAdapter wraps *bead.CachedClient to implement dashboard.BeadCreator.
+type beadCreatorAdapter struct {
+	client *bead.CachedClient
+}
+
+func (a *beadCreatorAdapter) Create(in dashboard.NewBeadInput) (string, error) {
+	return a.client.Create(bead.CreateInput{
+		Title:       in.Title,
+		Description: in.Description,
+		Type:        in.Type,
+		Priority:    in.Priority,
+		ParentID:    in.ParentID,
+	})
+}
+
 // --- Campaign adapter types ---
 
 // campaignBeadClient adapts bead.Client to campaign.BeadClient.
@@ -834,10 +3329,11 @@ func (c *campaignBeadClient) ReadyChildren(parentID string) ([]campaign.BeadInfo
 	children := make([]campaign.BeadInfo, len(summaries))
 	for i, s := range summaries {
 		children[i] = campaign.BeadInfo{
-			ID:       s.ID,
-			Title:    s.Title,
-			Priority: s.Priority,
-			Type:     s.Type,
+			ID:        s.ID,
+			Title:     s.Title,
+			Priority:  s.Priority,
+			Type:      s.Type,
+			DependsOn: s.DependsOn,
 		}
 	}
 	return children, nil
@@ -852,6 +3348,9 @@ func (c *campaignBeadClient) Show(id string) (campaign.BeadInfo, error) {
 		ID:          id,
 		Title:       ctx.TaskTitle,
 		Description: ctx.TaskDescription,
+		Priority:    ctx.TaskPriority,
+		Type:        ctx.TaskType,
+		Labels:      ctx.Labels,
 	}, nil
 }
 
@@ -859,21 +3358,58 @@ func (c *campaignBeadClient) Close(id string) error {
 	return c.client.Close(id)
 }
 
+func (c *campaignBeadClient) Comment(id, text string) error {
+	return c.client.Comment(id, text)
+}
+
 func (c *campaignBeadClient) Create(input campaign.BeadInput) (string, error) {
-	// TODO: implement bead creation via bd CLI when needed.
-	return "", fmt.Errorf("bead creation not yet implemented")
+	return c.client.Create(bead.CreateInput{
+		Title:       input.Title,
+		Description: input.Description,
+		Type:        input.Type,
+		Priority:    input.Priority,
+		ParentID:    input.ParentID,
+	})
 }
 
+// The CLI's campaign callbacks and bead client implement the optional
+// extensions.
+var (
+	_ campaign.BeadCommenter           = (*campaignBeadClient)(nil)
+	_ campaign.TaskFailureObserver     = (*campaignPlainTextCallback)(nil)
+	_ campaign.TaskFailureObserver     = (*campaignJSONCallback)(nil)
+	_ campaign.TaskFailureObserver     = (*dashboardCampaignCallback)(nil)
+	_ campaign.TaskSkipObserver        = (*campaignPlainTextCallback)(nil)
+	_ campaign.TaskSkipObserver        = (*campaignJSONCallback)(nil)
+	_ campaign.TaskSkipObserver        = (*dashboardCampaignCallback)(nil)
+	_ campaign.ValidationPhaseObserver = (*campaignPlainTextCallback)(nil)
+	_ campaign.ValidationPhaseObserver = (*campaignJSONCallback)(nil)
+	_ campaign.ValidationPhaseObserver = (*dashboardCampaignCallback)(nil)
+	_ campaign.BreakerTripObserver     = (*campaignPlainTextCallback)(nil)
+	_ campaign.BreakerTripObserver     = (*campaignJSONCallback)(nil)
+	_ campaign.BreakerTripObserver     = (*dashboardCampaignCallback)(nil)
+	_ campaign.ParentCloseObserver     = (*campaignPlainTextCallback)(nil)
+	_ campaign.ParentCloseObserver     = (*campaignJSONCallback)(nil)
+	_ campaign.ParentCloseObserver     = (*dashboardCampaignCallback)(nil)
+)
+
 // campaignPlainTextCallback implements campaign.Callback with plain text output.
 type campaignPlainTextCallback struct {
-	w     io.Writer
-	depth int
-	stack []campaignLevel
+	w      io.Writer
+	depth  int
+	stack  []campaignLevel
+	status plainStatus // Prints validation phases; its verbosity is the campaign's.
+
+	policy  string // Failure mode printed when the top-level campaign starts; empty prints nothing.
+	breaker string // Circuit breaker description printed with policy.
 }
 
 func (c *campaignPlainTextCallback) OnCampaignStart(parentID string, tasks []campaign.BeadInfo) {
 	if c.depth == 0 {
 		_, _ = fmt.Fprintf(c.w, "[campaign] %s (%d tasks)\n", parentID, len(tasks))
+		if c.policy != "" {
+			_, _ = fmt.Fprintf(c.w, "[campaign] Failure mode: %s · circuit breaker: %s\n", c.policy, c.breaker)
+		}
 	} else {
 		indent := strings.Repeat("  ", c.depth)
 		c.stack = append(c.stack, campaignLevel{
@@ -898,10 +3434,22 @@ func (c *campaignPlainTextCallback) OnTaskComplete(result campaign.TaskResult) {
 	_, _ = fmt.Fprintf(c.w, "%s[%s] [%s] complete\n", indent, ts, result.BeadID)
 }
 
+func (c *campaignPlainTextCallback) OnTaskFailed(result campaign.TaskResult, err error) {
+	ts := time.Now().Format("15:04:05")
+	indent := strings.Repeat("  ", c.depth)
+	_, _ = fmt.Fprintf(c.w, "%s[%s] [%s] failed: %v\n", indent, ts, result.BeadID, err)
+}
+
+// OnTaskFail reports a failure without its phases. The runner calls
+// OnTaskFailed instead.
 func (c *campaignPlainTextCallback) OnTaskFail(beadID string, err error) {
+	c.OnTaskFailed(campaign.TaskResult{BeadID: beadID}, err)
+}
+
+func (c *campaignPlainTextCallback) OnTaskSkipped(beadID, reason string) {
 	ts := time.Now().Format("15:04:05")
 	indent := strings.Repeat("  ", c.depth)
-	_, _ = fmt.Fprintf(c.w, "%s[%s] [%s] failed: %v\n", indent, ts, beadID, err)
+	_, _ = fmt.Fprintf(c.w, "%s[%s] [%s] skipped: %s\n", indent, ts, beadID, reason)
 }
 
 func (c *campaignPlainTextCallback) OnCampaignPaused(beadID, reason, details string) {
@@ -909,6 +3457,18 @@ func (c *campaignPlainTextCallback) OnCampaignPaused(beadID, reason, details str
 	_, _ = fmt.Fprintf(c.w, "Details: %s\n", details)
 }
 
+func (c *campaignPlainTextCallback) OnCircuitBroken(trip campaign.BreakerTrip) {
+	_, _ = fmt.Fprintf(c.w, "\n⛔ Circuit breaker tripped: %s\n", trip.Reason)
+	_, _ = fmt.Fprintf(c.w, "Failures: %d provider/setup, %d NEEDS_WORK/ERROR\n", trip.Counts.Setup, trip.Counts.Signal)
+	if ids := trip.FailedIDs(); len(ids) > 0 {
+		_, _ = fmt.Fprintf(c.w, "Failed tasks: %s\n", strings.Join(ids, ", "))
+	}
+}
+
+func (c *campaignPlainTextCallback) OnParentClosed(parentID string) {
+	_, _ = fmt.Fprintf(c.w, "[campaign] Closed %s\n", parentID)
+}
+
 func (c *campaignPlainTextCallback) OnDiscoveryFiled(f provider.Finding, newBeadID string) {
 	_, _ = fmt.Fprintf(c.w, "  Filed: %s [P%d]: %s\n", newBeadID, severityToPriorityCLI(f.Severity), f.Title)
 }
@@ -917,8 +3477,24 @@ func (c *campaignPlainTextCallback) OnValidationStart() {
 	_, _ = fmt.Fprintf(c.w, "[campaign] Running feature validation...\n")
 }
 
+// OnValidationPhase reports a validation phase like a pipeline phase,
+// indented beneath the validation line.
+func (c *campaignPlainTextCallback) OnValidationPhase(su orchestrator.StatusUpdate) {
+	if !c.status.throttle.allow(su, time.Now()) {
+		return
+	}
+	var b strings.Builder
+	c.status.write(&b, su)
+	for line := range strings.Lines(b.String()) {
+		_, _ = fmt.Fprintf(c.w, "  %s", line)
+	}
+}
+
 func (c *campaignPlainTextCallback) OnValidationComplete(result campaign.TaskResult) {
 	_, _ = fmt.Fprintf(c.w, "[campaign] Validation %s\n", result.Status)
+	if result.Error != "" {
+		_, _ = fmt.Fprintf(c.w, "  Error: %s\n", result.Error)
+	}
 }
 
 func (c *campaignPlainTextCallback) OnCampaignComplete(s campaign.State) {
@@ -929,9 +3505,26 @@ func (c *campaignPlainTextCallback) OnCampaignComplete(s campaign.State) {
 		_, _ = fmt.Fprintf(c.w, "%s[subcampaign] %s done: %d tasks\n", indent, s.ParentBeadID, len(s.Tasks))
 	} else {
 		_, _ = fmt.Fprintf(c.w, "[campaign] Complete: %d tasks\n", len(s.Tasks))
+		for _, t := range s.Tasks {
+			if t.Status == campaign.TaskSkipped && t.SkipReason != "" {
+				_, _ = fmt.Fprintf(c.w, "[campaign] Skipped %s: %s\n", t.BeadID, t.SkipReason)
+			}
+		}
+		if !s.Usage.IsZero() {
+			_, _ = fmt.Fprintf(c.w, "[campaign] Usage: %s\n", s.Usage)
+		}
+		if s.ParentOpen != "" {
+			_, _ = fmt.Fprintf(c.w, "[campaign] %s left open: %s\n", s.ParentBeadID, s.ParentOpen)
+		}
 	}
 }
 
+// campaignBreaker builds the campaign circuit breaker from config limits.
+func campaignBreaker(c config.Campaign) campaign.CircuitBreaker {
+	setup, signal := c.Breakers()
+	return campaign.CircuitBreaker{Mode: campaign.BreakerMode(c.BreakerMode), Setup: setup, Signal: signal}
+}
+
 func severityToPriorityCLI(severity string) int {
 	switch severity {
 	case "critical":
@@ -945,13 +3538,17 @@ func severityToPriorityCLI(severity string) int {
 	}
 }
 
-// setupPauseTrigger registers SIGUSR1 to flip an atomic bool.
+// setupPauseTrigger registers pauseSignals (SIGUSR1) to flip an atomic bool.
 // The returned function checks whether pause was requested.
 // The returned stop function deregisters the signal and must be deferred.
+// Where there is no pause signal (Windows) pause is never requested.
 func setupPauseTrigger() (check func() bool, stop func()) {
+	if len(pauseSignals) == 0 {
+		return func() bool { return false }, func() {}
+	}
 	var paused atomic.Bool
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGUSR1)
+	signal.Notify(sigCh, pauseSignals...)
 	go func() {
 		if _, ok := <-sigCh; ok {
 			paused.Store(true)
@@ -963,6 +3560,93 @@ func setupPauseTrigger() (check func() bool, stop func()) {
 	}
 }
 
+// phaseTracker records the bead and phase last reported as running, so an
+// interrupt can say what it stopped.
+type phaseTracker struct {
+	mu     sync.Mutex
+	beadID string
+	phase  string
+	lock   *runlock.Lock // When set, running phases are also recorded in the run lock.
+}
+
+// setLock records later running phases in lock as well.
+func (t *phaseTracker) setLock(lock *runlock.Lock) {
+	t.mu.Lock()
+	t.lock = lock
+	t.mu.Unlock()
+}
+
+// wrap returns a StatusCallback that records running phases before calling cb.
+func (t *phaseTracker) wrap(cb orchestrator.StatusCallback) orchestrator.StatusCallback {
+	return func(su orchestrator.StatusUpdate) {
+		if su.Status == orchestrator.PhaseRunning {
+			t.mu.Lock()
+			t.beadID, t.phase = su.BeadID, su.Phase
+			if t.lock != nil {
+				_ = t.lock.SetPhase(su.Phase) // Best-effort: only abort's message reads it.
+			}
+			t.mu.Unlock()
+		}
+		cb(su)
+	}
+}
+
+// String describes the running phase, e.g. "cap-1 (phase execute)".
+func (t *phaseTracker) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case t.phase == "":
+		return "pipeline before its first phase"
+	case t.beadID == "":
+		return "phase " + t.phase
+	default:
+		return fmt.Sprintf("%s (phase %s)", t.beadID, t.phase)
+	}
+}
+
+// interruptContext returns a context cancelled by the first Ctrl+C, which
+// asks the provider to stop gracefully. A second Ctrl+C closes force (when
+// non-nil) so the provider kills its process group at once; the pipeline then
+// fails and the process exits with the pipeline exit code. Each interrupt is
+// reported to w along with where the pipeline was.
+// The returned stop function deregisters the signals and must be deferred.
+func interruptContext(parent context.Context, w io.Writer, force chan struct{}, where fmt.Stringer) (context.Context, func()) {
+	ctx, stopNotify := signal.NotifyContext(parent, os.Interrupt)
+
+	// NotifyContext only acts on the first signal; count them separately.
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		for n := 1; ; n++ {
+			select {
+			case <-sigCh:
+			case <-done:
+				return
+			}
+			if n == 1 {
+				_, _ = fmt.Fprintf(w, "\nInterrupted %s; stopping (press Ctrl+C again to force quit)\n", where)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "\nForce quitting %s\n", where)
+			if force != nil {
+				close(force)
+			}
+			return
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(sigCh)
+			close(done)
+			stopNotify()
+		})
+	}
+}
+
 // --- Dashboard campaign adapter types ---
 
 // dashboardCampaignAdapter implements dashboard.CampaignRunner by building a
@@ -980,8 +3664,13 @@ func (a *dashboardCampaignAdapter) RunCampaign(
 	pipelineFn func(context.Context, dashboard.PipelineInput, func(dashboard.PhaseUpdateMsg)) (dashboard.PipelineOutput, error),
 ) error {
 	cb := &dashboardCampaignCallback{statusFn: statusFn}
+	if a.campaignCfg.Resume {
+		if st, found, err := a.stateStore.Load(parentID); err == nil && found && st.Status != campaign.CampaignCompleted {
+			cb.resumed = &dashboard.CampaignResume{Done: st.Done(), Total: len(st.Tasks)}
+		}
+	}
 	pr := &dashboardCampaignPipelineRunner{pipelineFn: pipelineFn, statusFn: statusFn}
-	runner := campaign.NewRunner(pr, a.beadClient, a.stateStore, a.campaignCfg, cb)
+	runner := capsule.NewCampaign(pr, a.beadClient, a.stateStore, a.campaignCfg, cb)
 	return runner.Run(ctx, parentID)
 }
 
@@ -1002,30 +3691,23 @@ func (r *dashboardCampaignPipelineRunner) RunPipeline(ctx context.Context, input
 	dashInput := dashboard.PipelineInput{
 		BeadID:         input.BeadID,
 		SiblingContext: input.SiblingContext,
+		Phases:         input.Phases,
 	}
 
 	output, err := r.pipelineFn(ctx, dashInput, func(msg dashboard.PhaseUpdateMsg) {
-		if r.statusFn != nil {
+		switch {
+		case input.StatusCallback != nil:
+			input.StatusCallback(statusUpdate(input.BeadID, msg))
+		case r.statusFn != nil:
 			r.statusFn(msg)
 		}
 	})
-	if err != nil {
-		return orchestrator.PipelineOutput{}, err
-	}
 
-	// Convert dashboard output to orchestrator output.
-	results := make([]orchestrator.PhaseResult, len(output.PhaseReports))
-	for i, pr := range output.PhaseReports {
-		results[i] = orchestrator.PhaseResult{
-			PhaseName: pr.PhaseName,
-			Signal: provider.Signal{
-				Status:       dashboardStatusToProvider(pr.Status),
-				Summary:      pr.Summary,
-				FilesChanged: pr.FilesChanged,
-				Feedback:     pr.Feedback,
-			},
-			Duration: pr.Duration,
-		}
+	// Convert dashboard output to orchestrator output. Partial results are
+	// kept on error so the campaign state records the failing phase.
+	results := reportsToPhaseResults(output.PhaseReports)
+	if err != nil {
+		return orchestrator.PipelineOutput{PhaseResults: results}, err
 	}
 
 	return orchestrator.PipelineOutput{
@@ -1034,6 +3716,34 @@ func (r *dashboardCampaignPipelineRunner) RunPipeline(ctx context.Context, input
 	}, nil
 }
 
+// dashboardTaskStore implements dashboard.CampaignTaskStore on top of the
+// persisted campaign state.
+type dashboardTaskStore struct {
+	store campaign.StateStore
+}
+
+func (s *dashboardTaskStore) TaskReports(parentID string) (map[string][]dashboard.PhaseReport, error) {
+	st, found, err := s.store.Load(parentID)
+	if err != nil || !found {
+		return nil, err
+	}
+	reports := make(map[string][]dashboard.PhaseReport, len(st.Tasks))
+	for _, task := range st.Tasks {
+		if len(task.PhaseResults) > 0 {
+			reports[task.BeadID] = phaseResultsToReports(task.PhaseResults)
+		}
+	}
+	return reports, nil
+}
+
+func (s *dashboardTaskStore) RecordTaskPassed(parentID, beadID string, reports []dashboard.PhaseReport) error {
+	return campaign.RecordTaskResult(s.store, parentID, campaign.TaskResult{
+		BeadID:       beadID,
+		Status:       campaign.TaskCompleted,
+		PhaseResults: reportsToPhaseResults(reports),
+	})
+}
+
 // providerStatusToDashboard maps a provider.Status to the corresponding
 // dashboard.PhaseStatus. Unknown statuses map to dashboard.PhaseError.
 func providerStatusToDashboard(s provider.Status) dashboard.PhaseStatus {
@@ -1086,15 +3796,17 @@ type dashboardCampaignCallback struct {
 	taskTotal int
 	depth     int
 	stack     []campaignLevel
+	resumed   *dashboard.CampaignResume // Saved progress of the top-level campaign; nil when it starts fresh.
 }
 
 func (c *dashboardCampaignCallback) OnCampaignStart(parentID string, tasks []campaign.BeadInfo) {
 	infos := make([]dashboard.CampaignTaskInfo, len(tasks))
 	for i, t := range tasks {
 		infos[i] = dashboard.CampaignTaskInfo{
-			BeadID:   t.ID,
-			Title:    t.Title,
-			Priority: t.Priority,
+			BeadID:    t.ID,
+			Title:     t.Title,
+			Priority:  t.Priority,
+			BlockedBy: t.BlockedBy,
 		}
 	}
 
@@ -1105,6 +3817,7 @@ func (c *dashboardCampaignCallback) OnCampaignStart(parentID string, tasks []cam
 		c.statusFn(dashboard.CampaignStartMsg{
 			ParentID: parentID,
 			Tasks:    infos,
+			Resumed:  c.resumed,
 		})
 	} else {
 		// Nested campaign: push current state to stack
@@ -1132,42 +3845,47 @@ func (c *dashboardCampaignCallback) OnTaskStart(beadID string) {
 }
 
 func (c *dashboardCampaignCallback) OnTaskComplete(result campaign.TaskResult) {
+	c.statusFn(c.taskDoneMsg(result, result.Error))
+	c.taskIndex++
+}
+
+func (c *dashboardCampaignCallback) OnTaskFailed(result campaign.TaskResult, err error) {
+	c.statusFn(c.taskDoneMsg(result, err.Error()))
+	c.taskIndex++
+}
+
+// OnTaskFail reports a failure without its phases. The runner calls
+// OnTaskFailed instead.
+func (c *dashboardCampaignCallback) OnTaskFail(beadID string, err error) {
+	c.OnTaskFailed(campaign.TaskResult{BeadID: beadID}, err)
+}
+
+// taskDoneMsg reports a finished task with the phases it ran, so the
+// dashboard can show them after the campaign moves on.
+func (c *dashboardCampaignCallback) taskDoneMsg(result campaign.TaskResult, errText string) dashboard.CampaignTaskDoneMsg {
 	var totalDuration time.Duration
 	for _, pr := range result.PhaseResults {
 		totalDuration += pr.Duration
 	}
-
 	var reports []dashboard.PhaseReport
 	if len(result.PhaseResults) > 0 {
-		reports = make([]dashboard.PhaseReport, len(result.PhaseResults))
-		for i, pr := range result.PhaseResults {
-			reports[i] = dashboard.PhaseReport{
-				PhaseName:    pr.PhaseName,
-				Status:       providerStatusToDashboard(pr.Signal.Status),
-				Summary:      pr.Signal.Summary,
-				Feedback:     pr.Signal.Feedback,
-				FilesChanged: pr.Signal.FilesChanged,
-				Duration:     pr.Duration,
-			}
-		}
+		reports = phaseResultsToReports(result.PhaseResults)
 	}
-
-	c.statusFn(dashboard.CampaignTaskDoneMsg{
+	return dashboard.CampaignTaskDoneMsg{
 		BeadID:       result.BeadID,
 		Index:        c.taskIndex,
 		Success:      result.Status == campaign.TaskCompleted,
 		Duration:     totalDuration,
 		PhaseReports: reports,
-	})
-	c.taskIndex++
+		Error:        errText,
+	}
 }
 
-func (c *dashboardCampaignCallback) OnTaskFail(beadID string, err error) {
-	c.statusFn(dashboard.CampaignTaskDoneMsg{
-		BeadID:  beadID,
-		Index:   c.taskIndex,
-		Success: false,
-		Error:   err.Error(),
+func (c *dashboardCampaignCallback) OnTaskSkipped(beadID, reason string) {
+	c.statusFn(dashboard.CampaignTaskSkippedMsg{
+		BeadID: beadID,
+		Index:  c.taskIndex,
+		Reason: reason,
 	})
 	c.taskIndex++
 }
@@ -1180,6 +3898,20 @@ func (c *dashboardCampaignCallback) OnCampaignPaused(beadID, reason, details str
 	})
 }
 
+func (c *dashboardCampaignCallback) OnCircuitBroken(trip campaign.BreakerTrip) {
+	c.statusFn(dashboard.CampaignCircuitBrokenMsg{
+		Reason:         trip.Reason,
+		Banner:         trip.Summary(),
+		FailedIDs:      trip.FailedIDs(),
+		SetupFailures:  trip.Counts.Setup,
+		SignalFailures: trip.Counts.Signal,
+	})
+}
+
+func (c *dashboardCampaignCallback) OnParentClosed(parentID string) {
+	c.statusFn(dashboard.CampaignParentClosedMsg{ParentID: parentID})
+}
+
 func (c *dashboardCampaignCallback) OnDiscoveryFiled(_ provider.Finding, _ string) {
 	// Discovery filing is silent in dashboard mode.
 }
@@ -1188,6 +3920,13 @@ func (c *dashboardCampaignCallback) OnValidationStart() {
 	c.statusFn(dashboard.CampaignValidationStartMsg{})
 }
 
+func (c *dashboardCampaignCallback) OnValidationPhase(su orchestrator.StatusUpdate) {
+	if su.Warning != "" {
+		return
+	}
+	c.statusFn(phaseUpdateMsg(su))
+}
+
 func (c *dashboardCampaignCallback) OnValidationComplete(result campaign.TaskResult) {
 	var totalDuration time.Duration
 	for _, pr := range result.PhaseResults {
@@ -1209,6 +3948,7 @@ func (c *dashboardCampaignCallback) OnValidationComplete(result campaign.TaskRes
 		Success:      result.Status == campaign.TaskCompleted,
 		Duration:     totalDuration,
 		PhaseReports: reports,
+		Error:        result.Error,
 	})
 }
 
@@ -1248,18 +3988,42 @@ func (c *dashboardCampaignCallback) OnCampaignComplete(s campaign.State) {
 			Passed:     passed,
 			Failed:     failed,
 			Skipped:    skipped,
+			ParentOpen: s.ParentOpen,
+			Usage:      s.Usage,
 		})
 	}
 }
 
+// checkProviderHealth runs the provider's health check before any worktree
+// or worklog exists, unless skip is set. A failure is a setup error.
+func checkProviderHealth(ctx context.Context, p provider.Executor, skip bool) error {
+	if skip {
+		return nil
+	}
+	if err := p.HealthCheck(ctx); err != nil {
+		return fmt.Errorf("%w; pass --skip-health-check to start anyway", err)
+	}
+	return nil
+}
+
 // Exit codes.
 const (
-	exitSuccess  = 0 // No error.
-	exitPipeline = 1 // Pipeline phase failure or context cancellation.
-	exitSetup    = 2 // Config, provider, or wiring error.
-	exitPaused   = 3 // Pipeline or campaign paused via SIGUSR1.
+	exitSuccess       = 0 // No error.
+	exitPipeline      = 1 // Pipeline phase failure or context cancellation.
+	exitSetup         = 2 // Config, provider, or wiring error.
+	exitMergeConflict = 3 // Pipeline passed but its merge conflicted; a human must merge.
+	exitPaused        = 4 // Pipeline or campaign paused via SIGUSR1.
 )
 
+// exitCodesHelp documents the exit codes in --help for scripts.
+const exitCodesHelp = `Exit codes:
+  0  success
+  1  pipeline failed or was cancelled
+  2  setup error (config, provider, bead, or worktree), or --pregate found
+     a required gate failing before any changes
+  3  pipeline passed but the merge conflicted; resolve it by hand
+  4  paused; continue with capsule resume`
+
 // exitCode maps an error to the appropriate exit code.
 func exitCode(err error) int {
 	if err == nil {
@@ -1270,6 +4034,17 @@ func exitCode(err error) int {
 		errors.Is(err, campaign.ErrCampaignAborted) {
 		return exitPaused
 	}
+	if errors.Is(err, worktree.ErrMergeConflict) {
+		return exitMergeConflict
+	}
+	// A repository broken before the run started is a setup problem, as is
+	// a bd that cannot serve beads.
+	if errors.Is(err, orchestrator.ErrBaselineFailed) ||
+		errors.Is(err, bead.ErrBDNotInstalled) ||
+		errors.Is(err, bead.ErrNoBeadRepo) ||
+		errors.Is(err, bead.ErrBDVersionUnsupported) {
+		return exitSetup
+	}
 	var pe *orchestrator.PipelineError
 	if errors.As(err, &pe) {
 		return exitPipeline
@@ -1284,17 +4059,43 @@ func exitCode(err error) int {
 	return exitSetup
 }
 
+// beadSetupError adds the steps that fix a bead.Client.Check failure to
+// err. Other errors are returned unchanged.
+func beadSetupError(err error) error {
+	switch {
+	case errors.Is(err, bead.ErrBDNotInstalled):
+		return fmt.Errorf("%w: install bd from https://github.com/steveyegge/beads and make sure it is on PATH", err)
+	case errors.Is(err, bead.ErrNoBeadRepo):
+		return fmt.Errorf("%w: run `bd init` in the repository root to create one", err)
+	case errors.Is(err, bead.ErrBDVersionUnsupported):
+		return fmt.Errorf("%w: upgrade bd to %s or later", err, bead.MinBDVersion)
+	}
+	return err
+}
+
 // bridgeStatusCallback returns a StatusCallback that converts orchestrator
 // StatusUpdates to tui.StatusUpdateMsg and sends them through the bridge.
 func bridgeStatusCallback(bridge *tui.Bridge) orchestrator.StatusCallback {
 	return func(su orchestrator.StatusUpdate) {
+		if su.Warning != "" {
+			bridge.Warn(su.Warning)
+			return
+		}
 		msg := tui.StatusUpdateMsg{
-			Phase:    su.Phase,
-			Status:   tui.PhaseStatus(su.Status),
-			Progress: su.Progress,
-			Attempt:  su.Attempt,
-			MaxRetry: su.MaxRetry,
-			Duration: su.Duration,
+			Phase:            su.Phase,
+			Status:           tui.PhaseStatus(su.Status),
+			Progress:         su.Progress,
+			PhaseIndex:       su.PhaseIndex,
+			PhaseTotal:       su.PhaseTotal,
+			FractionComplete: su.FractionComplete,
+			Attempt:          su.Attempt,
+			MaxRetry:         su.MaxRetry,
+			Duration:         su.Duration,
+			Usage:            su.Usage,
+			Message:          su.Message,
+			MissingArtifacts: su.MissingArtifacts,
+			SkipRequested:    su.SkipRequested,
+			SkipReason:       su.SkipReason,
 		}
 		if su.Signal != nil {
 			msg.Summary = su.Signal.Summary
@@ -1314,28 +4115,156 @@ func phaseNames(phases []orchestrator.PhaseDefinition) []string {
 	return names
 }
 
+// displayPhaseNames returns the phase names a run displays, led by the
+// baseline checks when pregate will run a gate that isn't skipped.
+func displayPhaseNames(phases []orchestrator.PhaseDefinition, skip []string, pregate capsule.PregateMode) []string {
+	names := phaseNames(phases)
+	if pregate == capsule.PregateOff {
+		return names
+	}
+	for _, p := range phases {
+		if p.Kind == orchestrator.Gate && !slices.Contains(skip, p.Name) {
+			return append([]string{tui.BaselinePhase}, names...)
+		}
+	}
+	return names
+}
+
+// progressInterval is the least time between two progress lines printed
+// for the same phase, so a chatty provider does not flood plain output.
+const progressInterval = 10 * time.Second
+
+// progressThrottle limits progress lines to one per progressInterval for
+// each bead and phase. Its zero value is ready to use.
+type progressThrottle struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// allow reports whether su should be printed. A phase starting again
+// (a retry) may report progress at once.
+func (t *progressThrottle) allow(su orchestrator.StatusUpdate, now time.Time) bool {
+	key := su.BeadID + "/" + su.Phase
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if su.Status != orchestrator.PhaseProgress {
+		delete(t.last, key)
+		return true
+	}
+	if last, ok := t.last[key]; ok && now.Sub(last) < progressInterval {
+		return false
+	}
+	if t.last == nil {
+		t.last = make(map[string]time.Time)
+	}
+	t.last[key] = now
+	return true
+}
+
 // plainTextCallback returns a StatusCallback that prints timestamped phase lines
-// with enriched signal data on phase completion.
-func plainTextCallback(w io.Writer) orchestrator.StatusCallback {
+// with enriched signal data on phase completion. Progress is throttled.
+func plainTextCallback(w io.Writer, verbosity tui.Verbosity) orchestrator.StatusCallback {
+	p := &plainStatus{verbosity: verbosity}
 	return func(su orchestrator.StatusUpdate) {
-		ts := time.Now().Format("15:04:05")
-		retry := ""
-		if su.Attempt > 1 {
-			retry = fmt.Sprintf(" (attempt %d/%d)", su.Attempt, su.MaxRetry)
+		if p.throttle.allow(su, time.Now()) {
+			p.write(w, su)
 		}
-		_, _ = fmt.Fprintf(w, "[%s] [%s] %s %s%s\n", ts, su.Progress, su.Phase, su.Status, retry)
+	}
+}
 
-		// Phase completion report.
-		if su.Signal != nil && su.Status != orchestrator.PhaseRunning {
-			if len(su.Signal.FilesChanged) > 0 {
-				_, _ = fmt.Fprintf(w, "         files: %s\n", strings.Join(su.Signal.FilesChanged, ", "))
-			}
-			if su.Signal.Summary != "" {
-				_, _ = fmt.Fprintf(w, "         summary: %s\n", su.Signal.Summary)
-			}
-			if su.Signal.Feedback != "" && su.Status == orchestrator.PhaseFailed {
-				_, _ = fmt.Fprintf(w, "         feedback: %s\n", su.Signal.Feedback)
-			}
+// plainStatus prints status updates as plain text at a verbosity level.
+// Its zero value prints at tui.VerbosityNormal.
+type plainStatus struct {
+	verbosity tui.Verbosity
+	throttle  progressThrottle
+	feedback  map[string]string // Last failure feedback per bead, shown on its retry when verbose.
+}
+
+// write prints one status update. Quiet prints only finished phases,
+// without signal detail; verbose prints feedback on every phase and the
+// feedback a retry runs with.
+func (p *plainStatus) write(w io.Writer, su orchestrator.StatusUpdate) {
+	ts := time.Now().Format("15:04:05")
+	if su.Warning != "" {
+		_, _ = fmt.Fprintf(w, "[%s] warning: %s\n", ts, su.Warning)
+		return
+	}
+	quiet, verbose := p.verbosity == tui.VerbosityQuiet, p.verbosity == tui.VerbosityVerbose
+	if su.Status == orchestrator.PhaseProgress || su.Status == orchestrator.PhaseRetrying {
+		if !quiet {
+			_, _ = fmt.Fprintf(w, "[%s] %s: %s\n", ts, su.Phase, su.Message)
+		}
+		return
+	}
+	if su.Status == orchestrator.PhaseFailed && su.Signal != nil && su.Signal.Feedback != "" {
+		if p.feedback == nil {
+			p.feedback = make(map[string]string)
+		}
+		p.feedback[su.BeadID] = su.Signal.Feedback
+	}
+	if quiet && su.Status == orchestrator.PhaseRunning {
+		return
+	}
+	retry := ""
+	if su.Attempt > 1 {
+		retry = fmt.Sprintf(" (attempt %d/%d)", su.Attempt, su.MaxRetry)
+	}
+	reason := ""
+	if su.Status == orchestrator.PhaseSkipped && su.SkipReason != "" {
+		reason = " — " + su.SkipReason
+	}
+	progress := su.Progress
+	if su.PhaseTotal > 0 {
+		progress += fmt.Sprintf(" %d%%", int(math.Round(su.FractionComplete*100)))
+	}
+	_, _ = fmt.Fprintf(w, "[%s] [%s] %s %s%s%s\n", ts, progress, su.Phase, su.Status, retry, reason)
+
+	if su.Status == orchestrator.PhaseRunning {
+		if feedback := p.feedback[su.BeadID]; verbose && su.Attempt > 1 && feedback != "" {
+			_, _ = fmt.Fprintf(w, "         retrying with feedback: %s\n", feedback)
+		}
+		delete(p.feedback, su.BeadID)
+		return
+	}
+
+	// Phase completion report.
+	if su.Signal != nil && !quiet {
+		if len(su.Signal.FilesChanged) > 0 {
+			_, _ = fmt.Fprintf(w, "         files: %s\n", strings.Join(su.Signal.FilesChanged, ", "))
+		}
+		if su.Signal.Summary != "" {
+			_, _ = fmt.Fprintf(w, "         summary: %s\n", su.Signal.Summary)
+		}
+		if len(su.MissingArtifacts) > 0 {
+			_, _ = fmt.Fprintf(w, "         artifact check failed: missing %s\n", strings.Join(su.MissingArtifacts, ", "))
+		} else if su.Signal.Feedback != "" && (su.Status == orchestrator.PhaseFailed || verbose) {
+			_, _ = fmt.Fprintf(w, "         feedback: %s\n", su.Signal.Feedback)
+		}
+		if !su.Usage.IsZero() {
+			_, _ = fmt.Fprintf(w, "         usage: %s\n", su.Usage)
+		}
+	}
+}
+
+// campaignStatusCallback prints phase updates for a campaign. When tasks run
+// concurrently, their pipelines report from separate goroutines: each update
+// is written whole and its lines are prefixed with the bead ID.
+func campaignStatusCallback(w io.Writer, concurrency int, verbosity tui.Verbosity) orchestrator.StatusCallback {
+	if concurrency <= 1 {
+		return plainTextCallback(w, verbosity)
+	}
+	var mu sync.Mutex
+	p := &plainStatus{verbosity: verbosity}
+	return func(su orchestrator.StatusUpdate) {
+		if !p.throttle.allow(su, time.Now()) {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		var buf bytes.Buffer
+		p.write(&buf, su)
+		for line := range strings.Lines(buf.String()) {
+			_, _ = fmt.Fprintf(w, "%s %s", su.BeadID, line)
 		}
 	}
 }
@@ -1343,7 +4272,7 @@ func plainTextCallback(w io.Writer) orchestrator.StatusCallback {
 func main() {
 	var cli CLI
 	ctx := kong.Parse(&cli, kong.Vars{"version": version + " " + commit + " " + date})
-	err := ctx.Run()
+	err := ctx.Run(&cli.LogFlags)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %s\n", err)
 		os.Exit(exitCode(err))
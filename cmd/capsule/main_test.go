@@ -3,9 +3,19 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -13,12 +23,19 @@ import (
 	"github.com/alecthomas/kong"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/smileynet/capsule"
 	"github.com/smileynet/capsule/internal/bead"
 	"github.com/smileynet/capsule/internal/campaign"
+	"github.com/smileynet/capsule/internal/config"
 	"github.com/smileynet/capsule/internal/dashboard"
+	"github.com/smileynet/capsule/internal/notify"
 	"github.com/smileynet/capsule/internal/orchestrator"
 	"github.com/smileynet/capsule/internal/prompt"
 	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/runlock"
+	"github.com/smileynet/capsule/internal/scaffold"
+	"github.com/smileynet/capsule/internal/state"
+	"github.com/smileynet/capsule/internal/statusapi"
 	"github.com/smileynet/capsule/internal/tui"
 	"github.com/smileynet/capsule/internal/worklog"
 	"github.com/smileynet/capsule/internal/worktree"
@@ -108,6 +125,28 @@ func TestFeature_GoProjectSkeleton(t *testing.T) {
 		}
 	})
 
+	t.Run("global log flags parse before the command", func(t *testing.T) {
+		// Given: a CLI parser
+		var cli CLI
+		k, err := kong.New(&cli, kong.Vars{"version": "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// When: logging flags are passed
+		if _, err := k.Parse([]string{"-v", "--log-file", "capsule.log", "run", "bead-1"}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Then: both are set on the global flags
+		if !cli.Verbose {
+			t.Error("verbose = false, want true")
+		}
+		if !strings.HasSuffix(cli.LogFile, "capsule.log") {
+			t.Errorf("log file = %q, want path ending in capsule.log", cli.LogFile)
+		}
+	})
+
 	t.Run("run command accepts flags", func(t *testing.T) {
 		// Given: a CLI parser
 		var cli CLI
@@ -120,7 +159,11 @@ func TestFeature_GoProjectSkeleton(t *testing.T) {
 		_, err = k.Parse([]string{
 			"run", "bead-123",
 			"--provider", "claude",
-			"--timeout", "120",
+			"--phase-timeout", "10m",
+			"--phase-timeout", "execute=20m",
+			"--run-timeout", "1h",
+			"--max-calls", "20",
+			"--base-branch", "develop",
 		})
 		if err != nil {
 			t.Fatal(err)
@@ -130,8 +173,120 @@ func TestFeature_GoProjectSkeleton(t *testing.T) {
 		if cli.Run.Provider != "claude" {
 			t.Errorf("provider = %q, want %q", cli.Run.Provider, "claude")
 		}
-		if cli.Run.Timeout != 120 {
-			t.Errorf("timeout = %d, want %d", cli.Run.Timeout, 120)
+		if strings.Join(cli.Run.PhaseTimeout, " ") != "10m execute=20m" {
+			t.Errorf("phase timeout = %q, want [10m execute=20m]", cli.Run.PhaseTimeout)
+		}
+		if cli.Run.RunTimeout != time.Hour {
+			t.Errorf("run timeout = %v, want 1h", cli.Run.RunTimeout)
+		}
+		if cli.Run.MaxCalls != 20 {
+			t.Errorf("max calls = %d, want 20", cli.Run.MaxCalls)
+		}
+		if cli.Run.BaseBranch != "develop" {
+			t.Errorf("base branch = %q, want %q", cli.Run.BaseBranch, "develop")
+		}
+	})
+
+	t.Run("run command parses --pregate with or without a mode", func(t *testing.T) {
+		tests := []struct {
+			args    []string
+			want    capsule.PregateMode
+			wantErr bool
+		}{
+			{args: []string{}, want: capsule.PregateOff},
+			{args: []string{"--pregate"}, want: capsule.PregateFail},
+			{args: []string{"--pregate=warn"}, want: capsule.PregateWarn},
+			{args: []string{"--pregate=fail"}, want: capsule.PregateFail},
+			{args: []string{"--pregate=loud"}, wantErr: true},
+		}
+		for _, tt := range tests {
+			name := strings.Join(tt.args, " ")
+			if name == "" {
+				name = "no flag"
+			}
+			t.Run(name, func(t *testing.T) {
+				// Given: a CLI parser
+				var cli CLI
+				k, err := kong.New(&cli, kong.Vars{"version": "test"})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// When: run is invoked with the flag before the bead ID
+				_, err = k.Parse(append(append([]string{"run"}, tt.args...), "bead-123"))
+
+				// Then: the mode is parsed and the bead ID is not taken as a value
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+				if capsule.PregateMode(cli.Run.Pregate) != tt.want || cli.Run.BeadID != "bead-123" {
+					t.Errorf("pregate = %q, bead = %q; want %q, bead-123", cli.Run.Pregate, cli.Run.BeadID, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("run command parses phase selection lists", func(t *testing.T) {
+		// Given: a CLI parser
+		var cli CLI
+		k, err := kong.New(&cli, kong.Vars{"version": "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// When: run command is invoked with --skip-phases
+		_, err = k.Parse([]string{"run", "bead-123", "--skip-phases", "test-writer,test-review"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Then: the comma-separated list is split
+		if got := strings.Join(cli.Run.SkipPhases, "|"); got != "test-writer|test-review" {
+			t.Errorf("SkipPhases = %q, want %q", got, "test-writer|test-review")
+		}
+	})
+
+	t.Run("run command accepts repeated singular phase flags", func(t *testing.T) {
+		// Given: a CLI parser
+		var cli CLI
+		k, err := kong.New(&cli, kong.Vars{"version": "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// When: run command is invoked with --skip-phase twice
+		_, err = k.Parse([]string{"run", "bead-123", "--skip-phase", "test-writer", "--skip-phase", "test-review"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Then: both phases are collected
+		if got := strings.Join(cli.Run.SkipPhases, "|"); got != "test-writer|test-review" {
+			t.Errorf("SkipPhases = %q, want %q", got, "test-writer|test-review")
+		}
+	})
+
+	t.Run("run command rejects skip and only phases together", func(t *testing.T) {
+		// Given: a CLI parser
+		var cli CLI
+		k, err := kong.New(&cli, kong.Vars{"version": "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// When: both selection flags are given
+		_, err = k.Parse([]string{
+			"run", "bead-123",
+			"--skip-phases", "execute",
+			"--only-phases", "sign-off",
+		})
+
+		// Then: parsing fails
+		if err == nil {
+			t.Fatal("expected error for --skip-phases with --only-phases")
 		}
 	})
 
@@ -193,8 +348,9 @@ func TestFeature_GoProjectSkeleton(t *testing.T) {
 		if cli.Run.Provider != "claude" {
 			t.Errorf("default provider = %q, want %q", cli.Run.Provider, "claude")
 		}
-		if cli.Run.Timeout != 300 {
-			t.Errorf("default timeout = %d, want %d", cli.Run.Timeout, 300)
+		if len(cli.Run.PhaseTimeout) != 0 || cli.Run.RunTimeout != 0 || cli.Run.Timeout != 0 {
+			t.Errorf("default timeouts = %v/%v/%d, want unset so config applies",
+				cli.Run.PhaseTimeout, cli.Run.RunTimeout, cli.Run.Timeout)
 		}
 	})
 
@@ -266,7 +422,7 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 	t.Run("plainTextCallback formats timestamped lines", func(t *testing.T) {
 		// Given a buffer and a plain text callback
 		var buf bytes.Buffer
-		cb := plainTextCallback(&buf)
+		cb := plainTextCallback(&buf, tui.VerbosityNormal)
 
 		// When a status update is sent
 		cb(orchestrator.StatusUpdate{
@@ -291,10 +447,37 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 		}
 	})
 
+	t.Run("plainTextCallback throttles progress per phase", func(t *testing.T) {
+		// Given a buffer and a plain text callback
+		var buf bytes.Buffer
+		cb := plainTextCallback(&buf, tui.VerbosityNormal)
+		progress := func(phase, msg string) orchestrator.StatusUpdate {
+			return orchestrator.StatusUpdate{BeadID: "cap-42", Phase: phase, Status: orchestrator.PhaseProgress, Message: msg}
+		}
+
+		// When progress arrives in a burst, for two phases, and after a retry starts
+		cb(progress("test-writer", "reading"))
+		cb(progress("test-writer", "editing"))
+		cb(progress("execute", "building"))
+		cb(orchestrator.StatusUpdate{BeadID: "cap-42", Phase: "test-writer", Status: orchestrator.PhaseRunning, Attempt: 2, MaxRetry: 3})
+		cb(progress("test-writer", "retrying"))
+
+		// Then the first message of each burst is printed and the rest dropped
+		output := buf.String()
+		for _, want := range []string{"test-writer: reading", "execute: building", "test-writer: retrying"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q, got: %q", want, output)
+			}
+		}
+		if strings.Contains(output, "editing") {
+			t.Errorf("throttled progress was printed, got: %q", output)
+		}
+	})
+
 	t.Run("plainTextCallback shows attempt on retry", func(t *testing.T) {
 		// Given a buffer and a plain text callback
 		var buf bytes.Buffer
-		cb := plainTextCallback(&buf)
+		cb := plainTextCallback(&buf, tui.VerbosityNormal)
 
 		// When a retry status update is sent
 		cb(orchestrator.StatusUpdate{
@@ -316,7 +499,7 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 	t.Run("plainTextCallback shows signal data on completion", func(t *testing.T) {
 		// Given a buffer and a plain text callback
 		var buf bytes.Buffer
-		cb := plainTextCallback(&buf)
+		cb := plainTextCallback(&buf, tui.VerbosityNormal)
 
 		// When a passed update with signal data is sent
 		cb(orchestrator.StatusUpdate{
@@ -347,10 +530,31 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 		}
 	})
 
+	t.Run("plainTextCallback shows usage on completion", func(t *testing.T) {
+		// Given a buffer and a plain text callback
+		var buf bytes.Buffer
+		cb := plainTextCallback(&buf, tui.VerbosityNormal)
+
+		// When a passed update with usage is sent
+		cb(orchestrator.StatusUpdate{
+			Phase:    "execute",
+			Status:   orchestrator.PhasePassed,
+			Progress: "3/6",
+			Attempt:  1,
+			Signal:   &provider.Signal{Status: provider.StatusPass, Summary: "done"},
+			Usage:    provider.Usage{InputTokens: 1234, OutputTokens: 567},
+		})
+
+		// Then output includes the token counts
+		if output := buf.String(); !strings.Contains(output, "usage: 1234 in / 567 out tokens") {
+			t.Errorf("output missing usage, got: %q", output)
+		}
+	})
+
 	t.Run("plainTextCallback shows feedback on failure", func(t *testing.T) {
 		// Given a buffer and a plain text callback
 		var buf bytes.Buffer
-		cb := plainTextCallback(&buf)
+		cb := plainTextCallback(&buf, tui.VerbosityNormal)
 
 		// When a failed update with feedback is sent
 		cb(orchestrator.StatusUpdate{
@@ -376,7 +580,7 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 	t.Run("plainTextCallback omits signal data for running status", func(t *testing.T) {
 		// Given a buffer and a plain text callback
 		var buf bytes.Buffer
-		cb := plainTextCallback(&buf)
+		cb := plainTextCallback(&buf, tui.VerbosityNormal)
 
 		// When a running update is sent (Signal should be nil)
 		cb(orchestrator.StatusUpdate{
@@ -394,95 +598,60 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 		}
 	})
 
-	t.Run("exitCode returns 0 for nil error", func(t *testing.T) {
-		// Given no error
-		// When exitCode is called
-		code := exitCode(nil)
-		// Then it returns 0
-		if code != 0 {
-			t.Errorf("exitCode(nil) = %d, want 0", code)
-		}
-	})
-
-	t.Run("exitCode returns 1 for pipeline error", func(t *testing.T) {
-		// Given a PipelineError
-		err := &orchestrator.PipelineError{Phase: "execute", Attempt: 1, Signal: provider.Signal{Status: provider.StatusError}}
-		// When exitCode is called
-		code := exitCode(err)
-		// Then it returns 1
-		if code != 1 {
-			t.Errorf("exitCode(PipelineError) = %d, want 1", code)
-		}
-	})
-
-	t.Run("exitCode returns 2 for setup error", func(t *testing.T) {
-		// Given a non-pipeline error (setup/config issue)
-		err := fmt.Errorf("config: provider not found")
-		// When exitCode is called
-		code := exitCode(err)
-		// Then it returns 2
-		if code != 2 {
-			t.Errorf("exitCode(generic) = %d, want 2", code)
-		}
-	})
-
-	t.Run("exitCode returns 1 for context cancellation", func(t *testing.T) {
-		// Given a context.Canceled error wrapped in PipelineError
-		err := &orchestrator.PipelineError{Phase: "execute", Err: context.Canceled}
-		// When exitCode is called
-		code := exitCode(err)
-		// Then it returns 1 (pipeline failure, not setup error)
-		if code != 1 {
-			t.Errorf("exitCode(context.Canceled) = %d, want 1", code)
-		}
-	})
-
-	t.Run("exitCode returns 3 for pipeline paused", func(t *testing.T) {
-		// Given ErrPipelinePaused
-		// When exitCode is called
-		code := exitCode(orchestrator.ErrPipelinePaused)
-		// Then it returns 3
-		if code != 3 {
-			t.Errorf("exitCode(ErrPipelinePaused) = %d, want 3", code)
-		}
-	})
+	t.Run("plainTextCallback prints setup warnings", func(t *testing.T) {
+		// Given a buffer and a plain text callback
+		var buf bytes.Buffer
+		cb := plainTextCallback(&buf, tui.VerbosityNormal)
 
-	t.Run("exitCode returns 3 for campaign paused", func(t *testing.T) {
-		// Given ErrCampaignPaused
-		// When exitCode is called
-		code := exitCode(campaign.ErrCampaignPaused)
-		// Then it returns 3
-		if code != 3 {
-			t.Errorf("exitCode(ErrCampaignPaused) = %d, want 3", code)
-		}
-	})
+		// When a warning update is sent
+		cb(orchestrator.StatusUpdate{BeadID: "cap-1", Warning: "cap-2 changed src/a.go"})
 
-	t.Run("exitCode returns 1 for campaign ErrNoTasks", func(t *testing.T) {
-		// Given a campaign.ErrNoTasks error
-		err := campaign.ErrNoTasks
-		// When exitCode is called
-		code := exitCode(err)
-		// Then it returns 1 (runtime failure, not setup error)
-		if code != 1 {
-			t.Errorf("exitCode(ErrNoTasks) = %d, want 1", code)
+		// Then a single warning line is printed
+		output := buf.String()
+		if !strings.Contains(output, "warning: cap-2 changed src/a.go") || strings.Count(output, "\n") != 1 {
+			t.Errorf("output = %q, want one warning line", output)
 		}
 	})
 
-	t.Run("exitCode returns 1 for campaign ErrCircuitBroken", func(t *testing.T) {
-		// Given a campaign.ErrCircuitBroken error
-		err := campaign.ErrCircuitBroken
-		// When exitCode is called
-		code := exitCode(err)
-		// Then it returns 1 (runtime failure, not setup error)
-		if code != 1 {
-			t.Errorf("exitCode(ErrCircuitBroken) = %d, want 1", code)
+	t.Run("exitCode maps errors to exit codes", func(t *testing.T) {
+		tests := []struct {
+			name string
+			err  error
+			want int
+		}{
+			{name: "nil", err: nil, want: 0},
+			{name: "pipeline error", err: &orchestrator.PipelineError{Phase: "execute", Attempt: 1, Signal: provider.Signal{Status: provider.StatusError}}, want: 1},
+			{name: "context cancellation", err: &orchestrator.PipelineError{Phase: "execute", Err: context.Canceled}, want: 1},
+			{name: "campaign ErrNoTasks", err: campaign.ErrNoTasks, want: 1},
+			{name: "campaign ErrCircuitBroken", err: campaign.ErrCircuitBroken, want: 1},
+			{name: "setup error", err: fmt.Errorf("config: provider not found"), want: 2},
+			{name: "baseline failed", err: &orchestrator.PipelineError{Phase: orchestrator.BaselinePhase, Err: fmt.Errorf("%w: test", orchestrator.ErrBaselineFailed)}, want: 2},
+			{name: "bd not installed", err: fmt.Errorf("campaign: %w", bead.ErrBDNotInstalled), want: 2},
+			{name: "no bead repo", err: fmt.Errorf("dashboard: %w", bead.ErrNoBeadRepo), want: 2},
+			{name: "bd version unsupported", err: fmt.Errorf("campaign: %w", bead.ErrBDVersionUnsupported), want: 2},
+			{name: "merge conflict", err: fmt.Errorf("cap-1: pipeline passed but not merged: %w", worktree.ErrMergeConflict), want: 3},
+			{name: "merge conflict error type", err: &worktree.MergeConflictError{Branch: "capsule-cap-1", Into: "main"}, want: 3},
+			{name: "pipeline paused", err: orchestrator.ErrPipelinePaused, want: 4},
+			{name: "campaign paused", err: campaign.ErrCampaignPaused, want: 4},
+			{name: "campaign aborted", err: campaign.ErrCampaignAborted, want: 4},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Given an error a command returned
+				// When exitCode is called
+				got := exitCode(tt.err)
+				// Then it returns the documented exit code
+				if got != tt.want {
+					t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+				}
+			})
 		}
 	})
 
 	t.Run("RunCmd wires pipeline and returns nil on success", func(t *testing.T) {
 		// Given a RunCmd with mocks that succeed
 		var buf bytes.Buffer
-		cmd := &RunCmd{BeadID: "cap-test", Provider: "claude", Timeout: 60}
+		cmd := &RunCmd{BeadID: "cap-test", Provider: "claude"}
 		runner := &mockPipelineRunner{err: nil}
 		wt := &mockMergeOps{mainBranch: "main"}
 		bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskID: "cap-test", TaskTitle: "Test task"}}
@@ -520,11 +689,85 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 		}
 	})
 
+	t.Run("RunCmd passes resolved skip list to pipeline", func(t *testing.T) {
+		// Given a RunCmd with a resolved skip list
+		var buf bytes.Buffer
+		cmd := &RunCmd{BeadID: "cap-test", skip: []string{"test-writer", "test-review"}}
+		runner := &mockPipelineRunner{}
+		bridge := tui.NewBridge()
+		display := tui.NewDisplay(tui.DisplayOptions{Writer: &buf, ForcePlain: true})
+
+		// When run is called
+		err := cmd.run(&buf, runner, &mockMergeOps{mainBranch: "main"}, &mockBeadResolver{}, display, bridge, context.Background())
+
+		// Then the pipeline input carries the skip list
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := strings.Join(runner.input.SkipPhases, ","); got != "test-writer,test-review" {
+			t.Errorf("SkipPhases = %q, want %q", got, "test-writer,test-review")
+		}
+	})
+
+	t.Run("RunCmd starts from and merges into an explicit base branch", func(t *testing.T) {
+		// Given a RunCmd with a base branch other than the detected main
+		var buf bytes.Buffer
+		cmd := &RunCmd{BeadID: "cap-test", BaseBranch: "develop"}
+		runner := &mockPipelineRunner{}
+		wt := &mockMergeOps{mainBranch: "main"}
+		bridge := tui.NewBridge()
+		display := tui.NewDisplay(tui.DisplayOptions{Writer: &buf, ForcePlain: true})
+
+		// When run is called
+		err := cmd.run(&buf, runner, wt, &mockBeadResolver{}, display, bridge, context.Background())
+
+		// Then the pipeline branches from it and the merge lands on it
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if runner.input.BaseBranch != "develop" {
+			t.Errorf("BaseBranch = %q, want %q", runner.input.BaseBranch, "develop")
+		}
+		if wt.mergedInto != "develop" {
+			t.Errorf("merged into %q, want %q", wt.mergedInto, "develop")
+		}
+		if !strings.Contains(buf.String(), "Merged capsule-cap-test → develop") {
+			t.Errorf("output missing merge message, got: %q", buf.String())
+		}
+	})
+
+	t.Run("RunCmd resumes the pipeline when the display requests a retry", func(t *testing.T) {
+		// Given a pipeline that fails once, then passes on resume
+		var buf bytes.Buffer
+		cmd := &RunCmd{BeadID: "cap-test"}
+		runner := &retryPipelineRunner{errs: []error{errors.New("sign-off failed"), nil}}
+		wt := &mockMergeOps{mainBranch: "main"}
+		bridge := tui.NewBridge()
+		display := &retryDisplay{}
+
+		// When the user retries from the failure summary
+		err := cmd.run(&buf, runner, wt, &mockBeadResolver{}, display, bridge, context.Background())
+
+		// Then the second run resumes and the bead merges
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(runner.inputs) != 2 {
+			t.Fatalf("pipeline ran %d times, want 2", len(runner.inputs))
+		}
+		if runner.inputs[0].Resume || !runner.inputs[1].Resume {
+			t.Errorf("Resume = %v, %v; want false, true", runner.inputs[0].Resume, runner.inputs[1].Resume)
+		}
+		if !wt.merged {
+			t.Error("merge was not called after the resumed run passed")
+		}
+	})
+
 	t.Run("RunCmd returns pipeline error on failure", func(t *testing.T) {
 		// Given a RunCmd with a mock runner that fails
 		var buf bytes.Buffer
 		pipeErr := &orchestrator.PipelineError{Phase: "execute", Attempt: 1, Err: fmt.Errorf("broken")}
-		cmd := &RunCmd{BeadID: "cap-fail", Provider: "claude", Timeout: 60}
+		cmd := &RunCmd{BeadID: "cap-fail", Provider: "claude"}
 		runner := &mockPipelineRunner{err: pipeErr}
 		wt := &mockMergeOps{mainBranch: "main"}
 		bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskID: "cap-fail"}}
@@ -548,7 +791,7 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 	t.Run("RunCmd paused skips post-pipeline and shows message", func(t *testing.T) {
 		// Given a RunCmd where the runner returns ErrPipelinePaused
 		var buf bytes.Buffer
-		cmd := &RunCmd{BeadID: "cap-pause", Provider: "claude", Timeout: 60}
+		cmd := &RunCmd{BeadID: "cap-pause", Provider: "claude"}
 		runner := &mockPipelineRunner{err: orchestrator.ErrPipelinePaused}
 		wt := &mockMergeOps{mainBranch: "main"}
 		bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskID: "cap-pause"}}
@@ -574,7 +817,7 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 		if !strings.Contains(output, "Pipeline paused") {
 			t.Errorf("output missing pause message, got: %q", output)
 		}
-		if !strings.Contains(output, "capsule run cap-pause") {
+		if !strings.Contains(output, "capsule resume cap-pause") {
 			t.Errorf("output missing resume hint, got: %q", output)
 		}
 	})
@@ -582,7 +825,7 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 	t.Run("RunCmd warns on bead not found with actionable message", func(t *testing.T) {
 		// Given resolve returns a not-found error (bd available but bead not found)
 		var buf bytes.Buffer
-		cmd := &RunCmd{BeadID: "cap-bad", Provider: "claude", Timeout: 60}
+		cmd := &RunCmd{BeadID: "cap-bad", Provider: "claude"}
 		runner := &mockPipelineRunner{err: nil}
 		wt := &mockMergeOps{mainBranch: "main"}
 		bdMock := &mockBeadResolver{
@@ -613,7 +856,7 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 	t.Run("RunCmd warns generically on other bead resolve failures", func(t *testing.T) {
 		// Given resolve returns a non-not-found error
 		var buf bytes.Buffer
-		cmd := &RunCmd{BeadID: "cap-err", Provider: "claude", Timeout: 60}
+		cmd := &RunCmd{BeadID: "cap-err", Provider: "claude"}
 		runner := &mockPipelineRunner{err: nil}
 		wt := &mockMergeOps{mainBranch: "main"}
 		bdMock := &mockBeadResolver{
@@ -637,10 +880,10 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 		}
 	})
 
-	t.Run("RunCmd prints merge conflict warning", func(t *testing.T) {
+	t.Run("RunCmd prints merge conflict warning and returns ErrMergeConflict", func(t *testing.T) {
 		// Given merge returns ErrMergeConflict
 		var buf bytes.Buffer
-		cmd := &RunCmd{BeadID: "cap-conflict", Provider: "claude", Timeout: 60}
+		cmd := &RunCmd{BeadID: "cap-conflict", Provider: "claude"}
 		runner := &mockPipelineRunner{err: nil}
 		wt := &mockMergeOps{
 			mainBranch: "main",
@@ -653,9 +896,16 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 		// When run is called
 		err := cmd.run(&buf, runner, wt, bd, display, bridge, context.Background())
 
-		// Then no error is returned (best-effort)
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
+		// Then a merge conflict error is returned, which exits 3
+		if !errors.Is(err, worktree.ErrMergeConflict) {
+			t.Fatalf("err = %v, want ErrMergeConflict", err)
+		}
+		if code := exitCode(err); code != exitMergeConflict {
+			t.Errorf("exitCode = %d, want %d", code, exitMergeConflict)
+		}
+		// And the bead is not closed
+		if bd.closed {
+			t.Error("bead was closed despite the merge conflict")
 		}
 		// And the warning is printed
 		output := buf.String()
@@ -666,6 +916,61 @@ func TestFeature_OrchestratorWiring(t *testing.T) {
 			t.Errorf("output missing cleanup suggestion, got: %q", output)
 		}
 	})
+
+	t.Run("RunCmd notifies with failed phase before returning", func(t *testing.T) {
+		// Given a RunCmd with a notifier and a runner that fails in execute
+		var buf bytes.Buffer
+		pipeErr := &orchestrator.PipelineError{Phase: "execute", Attempt: 1, Err: fmt.Errorf("broken")}
+		n := &mockNotifier{}
+		cmd := &RunCmd{BeadID: "cap-notify", notifier: n}
+		runner := &mockPipelineRunner{err: pipeErr}
+		wt := &mockMergeOps{mainBranch: "main"}
+		bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskID: "cap-notify"}}
+		bridge := tui.NewBridge()
+		display := tui.NewDisplay(tui.DisplayOptions{Writer: &buf, ForcePlain: true})
+
+		// When run is called
+		_ = cmd.run(&buf, runner, wt, bd, display, bridge, context.Background())
+
+		// Then one failure event is sent with the failed phase
+		if len(n.events) != 1 {
+			t.Fatalf("notify calls = %d, want 1", len(n.events))
+		}
+		ev := n.events[0]
+		if ev.BeadID != "cap-notify" || ev.Success || ev.Status != notify.StatusFailed || ev.FailedPhase != "execute" {
+			t.Errorf("event = %+v, want failed cap-notify in execute", ev)
+		}
+	})
+
+	t.Run("RunCmd notification failure only warns", func(t *testing.T) {
+		// Given a notifier that always fails
+		var buf bytes.Buffer
+		n := &mockNotifier{err: errors.New("hook exploded")}
+		cmd := &RunCmd{BeadID: "cap-ok", notifier: n}
+		runner := &mockPipelineRunner{}
+		wt := &mockMergeOps{mainBranch: "main"}
+		bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskID: "cap-ok"}}
+		bridge := tui.NewBridge()
+		display := tui.NewDisplay(tui.DisplayOptions{Writer: &buf, ForcePlain: true})
+
+		// When run is called
+		err := cmd.run(&buf, runner, wt, bd, display, bridge, context.Background())
+
+		// Then the run still succeeds and post-pipeline still merges
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !wt.merged {
+			t.Error("merge should still run after a notification failure")
+		}
+		// And a warning is printed
+		if !strings.Contains(buf.String(), "warning: notification failed: hook exploded") {
+			t.Errorf("output missing notification warning, got: %q", buf.String())
+		}
+		if len(n.events) != 1 || !n.events[0].Success || n.events[0].Status != notify.StatusPassed {
+			t.Errorf("events = %+v, want one passed event", n.events)
+		}
+	})
 }
 
 // Compile-time interface satisfaction checks.
@@ -674,8 +979,20 @@ var (
 	_ worktreeOps    = (*mockWorktreeOps)(nil)
 	_ mergeOps       = (*mockMergeOps)(nil)
 	_ beadResolver   = (*mockBeadResolver)(nil)
+	_ eventNotifier  = (*mockNotifier)(nil)
 )
 
+// mockNotifier records notification events for RunCmd testing.
+type mockNotifier struct {
+	err    error
+	events []notify.Event
+}
+
+func (m *mockNotifier) Notify(_ context.Context, ev notify.Event) error {
+	m.events = append(m.events, ev)
+	return m.err
+}
+
 // mockPipelineRunner captures RunPipeline calls for testing.
 type mockPipelineRunner struct {
 	input orchestrator.PipelineInput
@@ -687,25 +1004,99 @@ func (m *mockPipelineRunner) RunPipeline(_ context.Context, input orchestrator.P
 	return orchestrator.PipelineOutput{Completed: m.err == nil}, m.err
 }
 
+// retryPipelineRunner returns errs in order, one per run, recording inputs.
+type retryPipelineRunner struct {
+	inputs []orchestrator.PipelineInput
+	errs   []error
+}
+
+func (m *retryPipelineRunner) RunPipeline(_ context.Context, input orchestrator.PipelineInput) (orchestrator.PipelineOutput, error) {
+	err := m.errs[len(m.inputs)]
+	m.inputs = append(m.inputs, input)
+	return orchestrator.PipelineOutput{Completed: err == nil}, err
+}
+
+// retryDisplay drains events and asks for a retry whenever a run fails,
+// as a user pressing r on the TUI failure summary would.
+type retryDisplay struct{}
+
+func (retryDisplay) Run(_ context.Context, events <-chan tui.DisplayEvent) error {
+	var failed bool
+	for ev := range events {
+		if _, ok := ev.(tui.PipelineErrorMsg); ok {
+			failed = true
+		}
+	}
+	if failed {
+		return tui.ErrRetryRequested
+	}
+	return nil
+}
+
 // mockWorktreeOps stubs worktree operations for abort/clean testing.
 type mockWorktreeOps struct {
 	exists    bool
+	branch    bool // The branch survives without its directory.
 	removeErr error
 	pruneErr  error
 
 	removedID     string
 	removedBranch bool
+	forced        bool
 	pruned        bool
 }
 
 func (m *mockWorktreeOps) Exists(string) bool { return m.exists }
 
+func (m *mockWorktreeOps) State(string) (worktree.State, error) {
+	return worktree.State{Dir: m.exists, Registered: m.exists, Branch: m.exists || m.branch}, nil
+}
+
 func (m *mockWorktreeOps) Remove(id string, deleteBranch bool) error {
 	m.removedID = id
 	m.removedBranch = deleteBranch
 	return m.removeErr
 }
 
+func (m *mockWorktreeOps) RemoveForce(id string) error {
+	m.forced = true
+	return m.Remove(id, true)
+}
+
+// mockRunCanceller implements runCanceller; running reports a live pipeline.
+type mockRunCanceller struct {
+	running        bool
+	phase          string
+	waitErr        error
+	interruptStops bool // Interrupt clears waitErr, as a holder that exits on SIGINT would.
+	interruptErr   error
+	cancelled      bool
+	interrupted    bool
+	waitedWith     time.Duration
+}
+
+func (m *mockRunCanceller) Holder(string) (runlock.Holder, bool, error) {
+	return runlock.Holder{PID: 4242, StartedAt: time.Now(), Phase: m.phase}, m.running, nil
+}
+
+func (m *mockRunCanceller) RequestCancel(string) error {
+	m.cancelled = true
+	return nil
+}
+
+func (m *mockRunCanceller) Interrupt(string) error {
+	m.interrupted = true
+	if m.interruptStops {
+		m.waitErr = nil
+	}
+	return m.interruptErr
+}
+
+func (m *mockRunCanceller) Wait(_ string, timeout time.Duration) error {
+	m.waitedWith = timeout
+	return m.waitErr
+}
+
 func (m *mockWorktreeOps) Prune() error {
 	m.pruned = true
 	return m.pruneErr
@@ -714,17 +1105,23 @@ func (m *mockWorktreeOps) Prune() error {
 // mockMergeOps stubs merge operations for RunCmd testing.
 type mockMergeOps struct {
 	mainBranch string
+	strategy   worktree.MergeStrategy
+	msgTmpl    string // Merge message template; "" uses the default.
 	mergeErr   error
 	removeErr  error
 	pruneErr   error
 
 	merged     bool
+	mergedInto string
+	mergedMsg  string
 	mergeCount int
 	mergeErrs  []error // Sequence of errors to return on successive calls
 }
 
-func (m *mockMergeOps) MergeToMain(string, string, string) error {
+func (m *mockMergeOps) MergeToMain(_, mainBranch, commitMsg string) error {
 	m.merged = true
+	m.mergedInto = mainBranch
+	m.mergedMsg = commitMsg
 	if len(m.mergeErrs) > 0 {
 		err := m.mergeErrs[m.mergeCount]
 		m.mergeCount++
@@ -734,6 +1131,12 @@ func (m *mockMergeOps) MergeToMain(string, string, string) error {
 	return m.mergeErr
 }
 
+func (m *mockMergeOps) MergeStrategy() worktree.MergeStrategy { return m.strategy }
+
+func (m *mockMergeOps) MergeMessage(msg worktree.MergeMessage) (string, error) {
+	return worktree.RenderMergeMessage(m.msgTmpl, msg)
+}
+
 func (m *mockMergeOps) DetectMainBranch() (string, error) {
 	return m.mainBranch, nil
 }
@@ -749,8 +1152,10 @@ type mockBeadResolver struct {
 	ctx        worklog.BeadContext
 	resolveErr error
 	closeErr   error
+	commentErr error
 
-	closed bool
+	closed   bool
+	comments []string // Text of each comment, in order.
 }
 
 func (m *mockBeadResolver) Resolve(string) (worklog.BeadContext, error) {
@@ -762,6 +1167,13 @@ func (m *mockBeadResolver) Close(string) error {
 	return m.closeErr
 }
 
+func (m *mockBeadResolver) Comment(_, text string) error {
+	if m.commentErr == nil {
+		m.comments = append(m.comments, text)
+	}
+	return m.commentErr
+}
+
 func TestFeature_DisplayWiring(t *testing.T) {
 	t.Run("bridgeStatusCallback converts StatusUpdate to StatusUpdateMsg", func(t *testing.T) {
 		// Given a bridge and a bridge status callback
@@ -770,12 +1182,15 @@ func TestFeature_DisplayWiring(t *testing.T) {
 
 		// When a status update with signal data is sent
 		cb(orchestrator.StatusUpdate{
-			BeadID:   "cap-42",
-			Phase:    "test-writer",
-			Status:   orchestrator.PhasePassed,
-			Progress: "1/6",
-			Attempt:  2,
-			MaxRetry: 3,
+			BeadID:           "cap-42",
+			Phase:            "test-writer",
+			Status:           orchestrator.PhasePassed,
+			Progress:         "1/6",
+			Attempt:          2,
+			MaxRetry:         3,
+			PhaseIndex:       1,
+			PhaseTotal:       6,
+			FractionComplete: 1.0 / 6,
 			Signal: &provider.Signal{
 				Status:       provider.StatusPass,
 				FilesChanged: []string{"foo.go", "bar.go"},
@@ -799,6 +1214,9 @@ func TestFeature_DisplayWiring(t *testing.T) {
 		if msg.Progress != "1/6" {
 			t.Errorf("Progress = %q, want %q", msg.Progress, "1/6")
 		}
+		if msg.PhaseIndex != 1 || msg.PhaseTotal != 6 || msg.FractionComplete != 1.0/6 {
+			t.Errorf("position = %d/%d %v, want 1/6 %v", msg.PhaseIndex, msg.PhaseTotal, msg.FractionComplete, 1.0/6)
+		}
 		if msg.Attempt != 2 {
 			t.Errorf("Attempt = %d, want %d", msg.Attempt, 2)
 		}
@@ -877,7 +1295,7 @@ func TestFeature_DisplayWiring(t *testing.T) {
 	t.Run("run wires display lifecycle around pipeline", func(t *testing.T) {
 		// Given a RunCmd with mocks and a plain display
 		var buf bytes.Buffer
-		cmd := &RunCmd{BeadID: "cap-display", Provider: "claude", Timeout: 60}
+		cmd := &RunCmd{BeadID: "cap-display", Provider: "claude"}
 		runner := &mockPipelineRunner{err: nil}
 		wt := &mockMergeOps{mainBranch: "main"}
 		bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskID: "cap-display", TaskTitle: "Test display"}}
@@ -903,7 +1321,7 @@ func TestFeature_DisplayWiring(t *testing.T) {
 		// Given a RunCmd where pipeline fails
 		var buf bytes.Buffer
 		pipeErr := &orchestrator.PipelineError{Phase: "execute", Attempt: 1, Err: fmt.Errorf("broken")}
-		cmd := &RunCmd{BeadID: "cap-fail", Provider: "claude", Timeout: 60}
+		cmd := &RunCmd{BeadID: "cap-fail", Provider: "claude"}
 		runner := &mockPipelineRunner{err: pipeErr}
 		wt := &mockMergeOps{mainBranch: "main"}
 		bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskID: "cap-fail"}}
@@ -943,24 +1361,221 @@ func TestFeature_DisplayWiring(t *testing.T) {
 			t.Errorf("names = %v, want [test-writer test-review execute]", names)
 		}
 	})
+
+	t.Run("displayPhaseNames leads with baseline checks under --pregate", func(t *testing.T) {
+		phases := []orchestrator.PhaseDefinition{
+			{Name: "execute", Kind: orchestrator.Worker},
+			{Name: "test", Kind: orchestrator.Gate},
+		}
+		tests := []struct {
+			name    string
+			skip    []string
+			pregate capsule.PregateMode
+			want    string
+		}{
+			{name: "off", want: "execute test"},
+			{name: "fail", pregate: capsule.PregateFail, want: "baseline execute test"},
+			{name: "warn", pregate: capsule.PregateWarn, want: "baseline execute test"},
+			{name: "every gate skipped", skip: []string{"test"}, pregate: capsule.PregateFail, want: "execute test"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// When the display's phase names are built
+				got := strings.Join(displayPhaseNames(phases, tt.skip, tt.pregate), " ")
+
+				// Then the baseline row leads only when a gate will run
+				if got != tt.want {
+					t.Errorf("displayPhaseNames() = %q, want %q", got, tt.want)
+				}
+			})
+		}
+	})
 }
 
-func TestFeature_AbortCommand(t *testing.T) {
-	t.Run("abort removes worktree and preserves branch", func(t *testing.T) {
-		// Given an abort command and a worktree that exists
-		var buf bytes.Buffer
-		cmd := &AbortCmd{BeadID: "cap-test"}
-		mgr := &mockWorktreeOps{exists: true}
+// mockFindingFiler records the beads it is asked to create.
+type mockFindingFiler struct {
+	created []bead.CreateInput
+	err     error
+}
 
-		// When abort runs
-		err := cmd.run(&buf, mgr)
+func (m *mockFindingFiler) Create(in bead.CreateInput) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.created = append(m.created, in)
+	return fmt.Sprintf("cap-new%d", len(m.created)), nil
+}
 
-		// Then no error is returned
-		if err != nil {
-			t.Fatalf("unexpected error: %v", err)
-		}
-		// And the worktree was removed without deleting the branch
-		if mgr.removedID != "cap-test" {
+// lockProbeRunner reports a running phase through the RunCmd's tracker and
+// records what the run lock shows while the pipeline runs.
+type lockProbeRunner struct {
+	cmd     *RunCmd
+	runs    *runlock.Store
+	err     error
+	holder  runlock.Holder
+	running bool
+}
+
+func (m *lockProbeRunner) RunPipeline(_ context.Context, input orchestrator.PipelineInput) (orchestrator.PipelineOutput, error) {
+	m.cmd.tracker.wrap(func(orchestrator.StatusUpdate) {})(orchestrator.StatusUpdate{
+		BeadID: input.BeadID, Phase: "execute", Status: orchestrator.PhaseRunning,
+	})
+	m.holder, m.running, _ = m.runs.Holder(input.BeadID)
+	return orchestrator.PipelineOutput{Completed: m.err == nil}, m.err
+}
+
+func TestRunCmd_RunLock(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "pipeline passes"},
+		{name: "pipeline fails", err: &orchestrator.PipelineError{Phase: "execute", Attempt: 1, Err: errors.New("broken")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a RunCmd with a run lock store
+			var buf bytes.Buffer
+			runs := runlock.NewStore(t.TempDir())
+			cmd := &RunCmd{BeadID: "cap-lock", runs: runs}
+			runner := &lockProbeRunner{cmd: cmd, runs: runs, err: tt.err}
+			display := tui.NewDisplay(tui.DisplayOptions{Writer: &buf, ForcePlain: true})
+
+			// When the pipeline runs
+			err := cmd.run(&buf, runner, &mockMergeOps{mainBranch: "main"}, &mockBeadResolver{}, display, tui.NewBridge(), context.Background())
+			if (err != nil) != (tt.err != nil) {
+				t.Fatalf("run() error = %v, want %v", err, tt.err)
+			}
+
+			// Then this process held the lock with the running phase recorded
+			if !runner.running || runner.holder.PID != os.Getpid() || runner.holder.Phase != "execute" {
+				t.Errorf("lock during run = %+v (running %v), want this process in phase execute", runner.holder, runner.running)
+			}
+			// And the lock is gone once run returns
+			if _, running, _ := runs.Holder("cap-lock"); running {
+				t.Error("run lock still held after run returned")
+			}
+		})
+	}
+}
+
+func TestRunCmd_RunLockHeldElsewhere(t *testing.T) {
+	// Given another pipeline in this process already holds the bead's lock
+	runs := runlock.NewStore(t.TempDir())
+	lock, err := runs.Acquire("cap-busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = lock.Release() }()
+	var buf bytes.Buffer
+	cmd := &RunCmd{BeadID: "cap-busy", runs: runs}
+	runner := &mockPipelineRunner{}
+	display := tui.NewDisplay(tui.DisplayOptions{Writer: &buf, ForcePlain: true})
+
+	// When a second run starts
+	err = cmd.run(&buf, runner, &mockMergeOps{mainBranch: "main"}, &mockBeadResolver{}, display, tui.NewBridge(), context.Background())
+
+	// Then it fails before running the pipeline
+	if !errors.Is(err, runlock.ErrHeld) {
+		t.Fatalf("run() error = %v, want ErrHeld", err)
+	}
+	if runner.input.BeadID != "" {
+		t.Error("pipeline ran despite the held lock")
+	}
+}
+
+func TestRunCmd_FileFindings(t *testing.T) {
+	findings := []provider.Finding{
+		{Title: "SQL injection", Severity: "critical", Description: "query built by concatenation"},
+		{Title: "Rename helper", Severity: "nit"},
+		{Title: "Missing test", Severity: "minor"},
+	}
+	tests := []struct {
+		name        string
+		minSeverity string
+		filerErr    error
+		wantTitles  []string
+		wantOutput  []string
+	}{
+		{
+			name:        "files findings at or above the threshold",
+			minSeverity: "minor",
+			wantTitles:  []string{"SQL injection", "Missing test"},
+			wantOutput:  []string{`Filed finding "SQL injection" as cap-new1`, "1 finding(s) below minor severity reported but not filed"},
+		},
+		{
+			name:        "critical threshold files only critical",
+			minSeverity: "critical",
+			wantTitles:  []string{"SQL injection"},
+			wantOutput:  []string{"2 finding(s) below critical"},
+		},
+		{
+			name:        "filing failures are warnings",
+			minSeverity: "nit",
+			filerErr:    fmt.Errorf("bd exploded"),
+			wantOutput:  []string{`warning: filing finding "Rename helper": bd exploded`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a run with finding filing enabled
+			var buf bytes.Buffer
+			filer := &mockFindingFiler{err: tt.filerErr}
+			r := &RunCmd{BeadID: "cap-42", filer: filer, minSeverity: tt.minSeverity}
+
+			// When findings are filed
+			r.fileFindings(&buf, findings)
+
+			// Then only findings at or above the threshold become child beads
+			var titles []string
+			for _, in := range filer.created {
+				titles = append(titles, in.Title)
+				if in.ParentID != "cap-42" || in.Type != "task" {
+					t.Errorf("created %+v, want task under cap-42", in)
+				}
+			}
+			if !slices.Equal(titles, tt.wantTitles) {
+				t.Errorf("filed %v, want %v", titles, tt.wantTitles)
+			}
+			if len(filer.created) > 0 && filer.created[0].Priority != 0 {
+				t.Errorf("critical finding priority = %d, want 0", filer.created[0].Priority)
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output = %q, want to contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+
+	t.Run("nothing is filed without a filer", func(t *testing.T) {
+		var buf bytes.Buffer
+		r := &RunCmd{BeadID: "cap-42"}
+		r.fileFindings(&buf, findings)
+		if buf.Len() != 0 {
+			t.Errorf("output = %q, want none", buf.String())
+		}
+	})
+}
+
+func TestFeature_AbortCommand(t *testing.T) {
+	t.Run("abort removes worktree and preserves branch", func(t *testing.T) {
+		// Given an abort command and a worktree that exists
+		var buf bytes.Buffer
+		cmd := &AbortCmd{BeadID: "cap-test"}
+		mgr := &mockWorktreeOps{exists: true}
+
+		// When abort runs
+		err := cmd.run(&buf, mgr, &mockRunCanceller{})
+
+		// Then no error is returned
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// And the worktree was removed without deleting the branch
+		if mgr.removedID != "cap-test" {
 			t.Errorf("removedID = %q, want %q", mgr.removedID, "cap-test")
 		}
 		if mgr.removedBranch {
@@ -979,7 +1594,7 @@ func TestFeature_AbortCommand(t *testing.T) {
 		mgr := &mockWorktreeOps{exists: false}
 
 		// When abort runs
-		err := cmd.run(&buf, mgr)
+		err := cmd.run(&buf, mgr, &mockRunCanceller{})
 
 		// Then an error mentioning "no worktree found" is returned
 		if err == nil {
@@ -997,7 +1612,7 @@ func TestFeature_AbortCommand(t *testing.T) {
 		mgr := &mockWorktreeOps{exists: true, removeErr: fmt.Errorf("lock held")}
 
 		// When abort runs
-		err := cmd.run(&buf, mgr)
+		err := cmd.run(&buf, mgr, &mockRunCanceller{})
 
 		// Then the remove error is returned
 		if err == nil {
@@ -1007,6 +1622,210 @@ func TestFeature_AbortCommand(t *testing.T) {
 			t.Errorf("error = %q, want to contain 'lock held'", err)
 		}
 	})
+
+	t.Run("abort stops a running pipeline before removing the worktree", func(t *testing.T) {
+		// Given a pipeline holding the run lock for the bead
+		var buf bytes.Buffer
+		cmd := &AbortCmd{BeadID: "cap-live", Timeout: 5}
+		mgr := &mockWorktreeOps{exists: true}
+		runs := &mockRunCanceller{running: true, phase: "execute"}
+
+		// When abort runs
+		err := cmd.run(&buf, mgr, runs)
+
+		// Then the pipeline is asked to stop and waited for
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !runs.cancelled || runs.waitedWith != 5*time.Second {
+			t.Errorf("cancelled = %v, waited %v; want cancel and 5s wait", runs.cancelled, runs.waitedWith)
+		}
+		// And then the worktree is removed, reporting each step
+		if mgr.removedID != "cap-live" {
+			t.Errorf("removedID = %q, want %q", mgr.removedID, "cap-live")
+		}
+		if runs.interrupted {
+			t.Error("pipeline interrupted although it stopped on request")
+		}
+		for _, want := range []string{"Stopping pipeline for cap-live (pid 4242", ", phase execute)", "Pipeline for cap-live stopped", "Aborted capsule cap-live"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("output = %q, want to contain %q", buf.String(), want)
+			}
+		}
+	})
+
+	t.Run("abort keeps the worktree when the pipeline does not stop", func(t *testing.T) {
+		// Given a running pipeline that ignores the cancel request
+		var buf bytes.Buffer
+		cmd := &AbortCmd{BeadID: "cap-stuck", Timeout: 1}
+		mgr := &mockWorktreeOps{exists: true}
+		runs := &mockRunCanceller{running: true, waitErr: runlock.ErrTimeout}
+
+		// When abort runs
+		err := cmd.run(&buf, mgr, runs)
+
+		// Then it is interrupted, still times out, and leaves the worktree alone
+		if !errors.Is(err, runlock.ErrTimeout) {
+			t.Fatalf("error = %v, want ErrTimeout", err)
+		}
+		if !runs.interrupted {
+			t.Error("pipeline was not interrupted after ignoring the cancel request")
+		}
+		if mgr.removedID != "" {
+			t.Errorf("worktree %q removed despite running pipeline", mgr.removedID)
+		}
+	})
+
+	t.Run("abort interrupts a pipeline that ignores the cancel request", func(t *testing.T) {
+		// Given a running pipeline that only stops on SIGINT
+		var buf bytes.Buffer
+		cmd := &AbortCmd{BeadID: "cap-deaf", Timeout: 1}
+		mgr := &mockWorktreeOps{exists: true}
+		runs := &mockRunCanceller{running: true, waitErr: runlock.ErrTimeout, interruptStops: true}
+
+		// When abort runs
+		err := cmd.run(&buf, mgr, runs)
+
+		// Then the interrupt stops it and the worktree is removed
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mgr.removedID != "cap-deaf" {
+			t.Errorf("removedID = %q, want %q", mgr.removedID, "cap-deaf")
+		}
+		if !strings.Contains(buf.String(), "interrupting pid 4242") {
+			t.Errorf("output = %q, want interrupt notice", buf.String())
+		}
+	})
+
+	t.Run("abort reports an interrupt that cannot be sent", func(t *testing.T) {
+		// Given a stuck pipeline whose process cannot be signalled
+		var buf bytes.Buffer
+		cmd := &AbortCmd{BeadID: "cap-win", Timeout: 1}
+		mgr := &mockWorktreeOps{exists: true}
+		runs := &mockRunCanceller{running: true, waitErr: runlock.ErrTimeout, interruptErr: errors.ErrUnsupported}
+
+		// When abort runs
+		err := cmd.run(&buf, mgr, runs)
+
+		// Then both failures are reported and the worktree stays
+		if !errors.Is(err, runlock.ErrTimeout) || !errors.Is(err, errors.ErrUnsupported) {
+			t.Fatalf("error = %v, want ErrTimeout and the interrupt error", err)
+		}
+		if mgr.removedID != "" {
+			t.Errorf("worktree %q removed despite running pipeline", mgr.removedID)
+		}
+	})
+
+	t.Run("abort succeeds for a running pipeline without a worktree yet", func(t *testing.T) {
+		// Given a pipeline still in setup, before its worktree exists
+		var buf bytes.Buffer
+		cmd := &AbortCmd{BeadID: "cap-setup", Timeout: 1}
+		mgr := &mockWorktreeOps{exists: false}
+
+		// When abort runs
+		err := cmd.run(&buf, mgr, &mockRunCanceller{running: true})
+
+		// Then stopping the pipeline is enough
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+// stubStater reports a fixed worktree state.
+type stubStater worktree.State
+
+func (s stubStater) State(string) (worktree.State, error) { return worktree.State(s), nil }
+
+func TestRunCmd_RecoverLeftover(t *testing.T) {
+	checkpoint := stubCheckpointLoader{cp: orchestrator.PipelineCheckpoint{BeadID: "cap-1"}, found: true}
+	full := stubStater{Dir: true, Registered: true, Branch: true}
+	yes, no := true, false
+	tests := []struct {
+		name       string
+		state      stubStater
+		loader     stubCheckpointLoader
+		reuse      bool
+		answer     *bool // Prompt answer; nil when not interactive.
+		wantResume bool
+		wantErr    []string
+	}{
+		{name: "nothing left over", loader: checkpoint},
+		{
+			name:    "no checkpoint suggests clean",
+			state:   full,
+			wantErr: []string{"worktree and branch exist", "capsule clean cap-1"},
+		},
+		{
+			name:    "directory without branch suggests clean",
+			state:   stubStater{Dir: true},
+			loader:  checkpoint,
+			reuse:   true,
+			wantErr: []string{"its branch was deleted", "capsule clean cap-1"},
+		},
+		{
+			name:    "checkpoint without flag or terminal suggests reuse",
+			state:   stubStater{Branch: true},
+			loader:  checkpoint,
+			wantErr: []string{"the worktree directory is gone", "--reuse-worktree", "capsule clean cap-1"},
+		},
+		{name: "flag resumes", state: full, loader: checkpoint, reuse: true, wantResume: true},
+		{name: "prompt accepted resumes", state: stubStater{Branch: true}, loader: checkpoint, answer: &yes, wantResume: true},
+		{
+			name:    "prompt declined fails",
+			state:   full,
+			loader:  checkpoint,
+			answer:  &no,
+			wantErr: []string{"--reuse-worktree"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given what an earlier run left and how the user can answer
+			r := &RunCmd{BeadID: "cap-1", ReuseWorktree: tt.reuse}
+			var confirm func(string) bool
+			var prompted bool
+			if tt.answer != nil {
+				confirm = func(string) bool { prompted = true; return *tt.answer }
+			}
+
+			// When the run checks for a leftover worktree
+			err := r.recoverLeftover(io.Discard, tt.state, tt.loader, confirm)
+
+			// Then it resumes, or fails with what to do next
+			if len(tt.wantErr) > 0 {
+				if !errors.Is(err, worktree.ErrAlreadyExists) || exitCode(err) != exitSetup {
+					t.Fatalf("err = %v, want ErrAlreadyExists setup error", err)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error missing %q: %v", want, err)
+					}
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r.resume != tt.wantResume {
+				t.Errorf("resume = %v, want %v", r.resume, tt.wantResume)
+			}
+			if tt.answer != nil && !prompted {
+				t.Error("user was not asked")
+			}
+		})
+	}
+}
+
+func TestAskYesNo(t *testing.T) {
+	for answer, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false} {
+		var out bytes.Buffer
+		if got := askYesNo(strings.NewReader(answer), &out, "Resume?"); got != want {
+			t.Errorf("askYesNo(%q) = %v, want %v", answer, got, want)
+		}
+		if out.String() != "Resume? [y/N] " {
+			t.Errorf("prompt = %q", out.String())
+		}
+	}
 }
 
 func TestFeature_CleanCommand(t *testing.T) {
@@ -1058,6 +1877,23 @@ func TestFeature_CleanCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("clean removes a branch left without its worktree", func(t *testing.T) {
+		// Given a branch an aborted run left behind without its directory
+		var buf bytes.Buffer
+		cmd := &CleanCmd{BeadID: "cap-orphan"}
+		mgr := &mockWorktreeOps{branch: true}
+
+		// When clean runs
+		if err := cmd.run(&buf, mgr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Then the branch is removed
+		if mgr.removedID != "cap-orphan" || !mgr.removedBranch {
+			t.Errorf("removed %q (branch %v), want cap-orphan with its branch", mgr.removedID, mgr.removedBranch)
+		}
+	})
+
 	t.Run("clean returns error when remove fails", func(t *testing.T) {
 		// Given a clean command and a worktree that fails to remove
 		var buf bytes.Buffer
@@ -1076,6 +1912,41 @@ func TestFeature_CleanCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("clean keeps an unmerged branch", func(t *testing.T) {
+		// Given a branch with work that was never merged
+		var buf bytes.Buffer
+		cmd := &CleanCmd{BeadID: "cap-wip"}
+		mgr := &mockWorktreeOps{exists: true, removeErr: fmt.Errorf("%w: capsule-cap-wip has commits that were not merged", worktree.ErrUnmerged)}
+
+		// When clean runs without --force
+		err := cmd.run(&buf, mgr)
+
+		// Then it fails, pointing at --force, after pruning the removed worktree
+		if !errors.Is(err, worktree.ErrUnmerged) || !strings.Contains(err.Error(), "capsule clean --force cap-wip") {
+			t.Errorf("error = %v, want ErrUnmerged with the --force hint", err)
+		}
+		if mgr.forced || !mgr.pruned || strings.Contains(buf.String(), "Cleaned") {
+			t.Errorf("forced = %v, pruned = %v, output = %q; want an unforced remove, a prune, and no success message", mgr.forced, mgr.pruned, buf.String())
+		}
+	})
+
+	t.Run("clean --force deletes an unmerged branch", func(t *testing.T) {
+		// Given --force
+		var buf bytes.Buffer
+		cmd := &CleanCmd{BeadID: "cap-wip", Force: true}
+		mgr := &mockWorktreeOps{exists: true}
+
+		// When clean runs
+		if err := cmd.run(&buf, mgr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Then the branch is force-deleted
+		if !mgr.forced || mgr.removedID != "cap-wip" {
+			t.Errorf("forced = %v, removed %q; want a forced remove of cap-wip", mgr.forced, mgr.removedID)
+		}
+	})
+
 	t.Run("clean returns error when prune fails", func(t *testing.T) {
 		// Given a clean command where prune fails
 		var buf bytes.Buffer
@@ -1134,6 +2005,51 @@ func TestDashboardCampaignPipelineRunner_PropagatesSiblingContext(t *testing.T)
 	}
 }
 
+func TestDashboardCampaignPipelineRunner_RoutesToInputStatusCallback(t *testing.T) {
+	// Given: a runner whose pipelineFn reports one phase update
+	var captured dashboard.PipelineInput
+	pipelineFn := func(_ context.Context, input dashboard.PipelineInput, statusFn func(dashboard.PhaseUpdateMsg)) (dashboard.PipelineOutput, error) {
+		captured = input
+		statusFn(dashboard.PhaseUpdateMsg{Phase: "review", Status: dashboard.PhasePassed, Summary: "ok", Duration: time.Second})
+		return dashboard.PipelineOutput{Success: true}, nil
+	}
+	var forwarded []tea.Msg
+	runner := &dashboardCampaignPipelineRunner{
+		pipelineFn: pipelineFn,
+		statusFn:   func(msg tea.Msg) { forwarded = append(forwarded, msg) },
+	}
+
+	// When: RunPipeline is called with its own phases and status callback
+	var updates []orchestrator.StatusUpdate
+	input := orchestrator.PipelineInput{
+		BeadID:         "cap-feat-validation",
+		Phases:         "minimal",
+		StatusCallback: func(su orchestrator.StatusUpdate) { updates = append(updates, su) },
+	}
+	if _, err := runner.RunPipeline(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then: the phase set reaches the dashboard input
+	if captured.Phases != "minimal" {
+		t.Errorf("Phases = %q, want %q", captured.Phases, "minimal")
+	}
+	// And: the update goes to the input's callback instead of statusFn
+	if len(forwarded) != 0 {
+		t.Errorf("statusFn got %d messages, want 0", len(forwarded))
+	}
+	if len(updates) != 1 {
+		t.Fatalf("updates = %d, want 1", len(updates))
+	}
+	su := updates[0]
+	if su.BeadID != "cap-feat-validation" || su.Phase != "review" || su.Status != orchestrator.PhasePassed {
+		t.Errorf("update = %+v, want passed review for cap-feat-validation", su)
+	}
+	if su.Signal == nil || su.Signal.Summary != "ok" {
+		t.Errorf("update Signal = %+v, want summary %q", su.Signal, "ok")
+	}
+}
+
 func TestDashboardCampaignPipelineRunner_ConvertsPhaseReports(t *testing.T) {
 	// Given: a pipelineFn that returns PhaseReports in its output
 	pipelineFn := func(_ context.Context, _ dashboard.PipelineInput, _ func(dashboard.PhaseUpdateMsg)) (dashboard.PipelineOutput, error) {
@@ -1345,6 +2261,100 @@ func TestDashboardCampaignCallback_OnTaskComplete_EmptyPhaseResults(t *testing.T
 	}
 }
 
+func TestDashboardCampaignCallback_OnTaskFailed_CarriesPhaseReports(t *testing.T) {
+	// Given: a callback and a task that failed in its second phase
+	var captured []tea.Msg
+	cb := &dashboardCampaignCallback{statusFn: func(msg tea.Msg) { captured = append(captured, msg) }, taskIndex: 2}
+	result := campaign.TaskResult{
+		BeadID: "cap-3",
+		Status: campaign.TaskFailed,
+		PhaseResults: []orchestrator.PhaseResult{
+			{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusPass}, Duration: time.Second},
+			{PhaseName: "review", Signal: provider.Signal{Status: provider.StatusNeedsWork, Feedback: "no tests"}, Duration: 2 * time.Second},
+		},
+	}
+
+	// When: OnTaskFailed is called
+	cb.OnTaskFailed(result, errors.New("review: retries exhausted"))
+
+	// Then: the done message carries the phases that ran and the error
+	done, ok := captured[0].(dashboard.CampaignTaskDoneMsg)
+	if !ok {
+		t.Fatalf("captured message is %T, want CampaignTaskDoneMsg", captured[0])
+	}
+	if done.Success || done.Index != 2 || done.Duration != 3*time.Second || done.Error != "review: retries exhausted" {
+		t.Errorf("done = %+v", done)
+	}
+	if len(done.PhaseReports) != 2 || done.PhaseReports[1].Feedback != "no tests" {
+		t.Errorf("PhaseReports = %+v, want execute and review with its feedback", done.PhaseReports)
+	}
+	if cb.taskIndex != 3 {
+		t.Errorf("taskIndex = %d, want 3", cb.taskIndex)
+	}
+}
+
+// readyBeads is a campaign.BeadClient whose parent always has the same
+// ready children.
+type readyBeads struct{ children []campaign.BeadInfo }
+
+func (b readyBeads) ReadyChildren(string) ([]campaign.BeadInfo, error) { return b.children, nil }
+func (b readyBeads) Show(id string) (campaign.BeadInfo, error) {
+	return campaign.BeadInfo{ID: id}, nil
+}
+func (readyBeads) Close(string) error                        { return nil }
+func (readyBeads) Create(campaign.BeadInput) (string, error) { return "", nil }
+func (readyBeads) Comment(string, string) error              { return nil }
+
+func TestDashboardCampaignAdapter_ReportsResume(t *testing.T) {
+	tests := []struct {
+		name  string
+		saved bool
+		want  *dashboard.CampaignResume
+	}{
+		{name: "saved state is resumed", saved: true, want: &dashboard.CampaignResume{Done: 1, Total: 2}},
+		{name: "no saved state starts fresh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a campaign store, holding an interrupted campaign with 1 of 2 tasks done
+			store := state.NewFileStore(t.TempDir())
+			if tt.saved {
+				if err := store.Save(campaign.State{ID: "cap-feat", ParentBeadID: "cap-feat", Status: campaign.CampaignPaused,
+					Tasks: []campaign.TaskResult{
+						{BeadID: "cap-1", Status: campaign.TaskCompleted},
+						{BeadID: "cap-2", Status: campaign.TaskPending},
+					}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			adapter := &dashboardCampaignAdapter{
+				beadClient:  readyBeads{children: []campaign.BeadInfo{{ID: "cap-2"}}},
+				stateStore:  store,
+				campaignCfg: campaign.Config{Resume: true, RetryFailed: true},
+			}
+
+			// When the dashboard runs the campaign
+			var start dashboard.CampaignStartMsg
+			statusFn := func(msg tea.Msg) {
+				if m, ok := msg.(dashboard.CampaignStartMsg); ok {
+					start = m
+				}
+			}
+			pipelineFn := func(context.Context, dashboard.PipelineInput, func(dashboard.PhaseUpdateMsg)) (dashboard.PipelineOutput, error) {
+				return dashboard.PipelineOutput{Success: true}, nil
+			}
+			if err := adapter.RunCampaign(context.Background(), "cap-feat", statusFn, pipelineFn); err != nil {
+				t.Fatalf("RunCampaign() error = %v", err)
+			}
+
+			// Then the start message carries the saved progress only when resuming
+			if (start.Resumed == nil) != (tt.want == nil) || (tt.want != nil && *start.Resumed != *tt.want) {
+				t.Errorf("Resumed = %+v, want %+v", start.Resumed, tt.want)
+			}
+		})
+	}
+}
+
 func TestDashboardCampaignCallback_NestedCampaigns(t *testing.T) {
 	// Given: a callback that captures messages
 	var captured []tea.Msg
@@ -1424,6 +2434,66 @@ func TestDashboardCampaignCallback_NestedCampaigns(t *testing.T) {
 	}
 }
 
+// mockCleanChecker stubs worktree.Manager.StatusClean.
+type mockCleanChecker struct {
+	clean bool
+	paths []string
+	err   error
+}
+
+func (m *mockCleanChecker) StatusClean() (bool, []string, error) { return m.clean, m.paths, m.err }
+
+func TestCheckCleanRepo(t *testing.T) {
+	tests := []struct {
+		name      string
+		checker   *mockCleanChecker
+		wantErr   bool
+		wantDirty bool
+		wantText  []string
+	}{
+		{
+			name:    "clean repository passes",
+			checker: &mockCleanChecker{clean: true},
+		},
+		{
+			name:      "dirty repository lists paths and the override flag",
+			checker:   &mockCleanChecker{paths: []string{"main.go", "docs/notes.md"}},
+			wantErr:   true,
+			wantDirty: true,
+			wantText:  []string{"--allow-dirty", "\n  main.go", "\n  docs/notes.md"},
+		},
+		{
+			name:     "git failure is returned",
+			checker:  &mockCleanChecker{err: errors.New("worktree: git status: exit status 128")},
+			wantErr:  true,
+			wantText: []string{"git status"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When the preflight check runs
+			err := checkCleanRepo(tt.checker)
+
+			// Then the result matches and dirty errors map to a setup exit code
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkCleanRepo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var dre *dirtyRepoError
+			if errors.As(err, &dre) != tt.wantDirty {
+				t.Errorf("errors.As(dirtyRepoError) = %v, want %v", !tt.wantDirty, tt.wantDirty)
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q missing %q", err, want)
+				}
+			}
+			if tt.wantDirty && exitCode(fmt.Errorf("run: %w", err)) != exitSetup {
+				t.Errorf("exitCode = %d, want %d", exitCode(err), exitSetup)
+			}
+		})
+	}
+}
+
 func TestPostPipeline_MergesAndClosesBead(t *testing.T) {
 	// Given: mock worktree and bead resolver that succeed
 	var buf bytes.Buffer
@@ -1431,7 +2501,7 @@ func TestPostPipeline_MergesAndClosesBead(t *testing.T) {
 	bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskID: "cap-pp"}}
 
 	// When: postPipeline is called
-	postPipeline(&buf, "cap-pp", wt, bd)
+	postPipeline(&buf, dashboard.PostPipelineInput{BeadID: "cap-pp"}, wt, bd)
 
 	// Then: merge and close are called
 	if !wt.merged {
@@ -1449,14 +2519,182 @@ func TestPostPipeline_MergesAndClosesBead(t *testing.T) {
 	}
 }
 
-func TestPostPipeline_WarnsOnMergeConflict(t *testing.T) {
-	// Given: mock worktree that returns merge conflict
+func TestPostPipeline_MergeMessageTemplate(t *testing.T) {
+	in := dashboard.PostPipelineInput{
+		BeadID:       "cap-42",
+		Title:        "Add login",
+		Type:         "feature",
+		Summary:      "Implemented login form",
+		FilesChanged: []string{"login.go", "login_test.go"},
+	}
+	tests := []struct {
+		name     string
+		tmpl     string
+		wantMsg  string
+		wantWarn bool
+	}{
+		{name: "default", wantMsg: "cap-42: pipeline complete"},
+		{name: "bead fields", tmpl: "{{.Type}}: {{.Title}} ({{.BeadID}})", wantMsg: "feature: Add login (cap-42)"},
+		{name: "phase fields", tmpl: "{{.Summary}}\n\n{{range .FilesChanged}}- {{.}}\n{{end}}", wantMsg: "Implemented login form\n\n- login.go\n- login_test.go"},
+		{name: "render failure falls back", tmpl: "{{.Missing}}", wantMsg: "cap-42: pipeline complete", wantWarn: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a worktree configured with the template
+			var buf bytes.Buffer
+			wt := &mockMergeOps{mainBranch: "main", msgTmpl: tt.tmpl}
+
+			// When: postPipeline merges the bead
+			postPipeline(&buf, in, wt, &mockBeadResolver{})
+
+			// Then: the commit message is rendered, or the default is used with a warning
+			if wt.mergedMsg != tt.wantMsg {
+				t.Errorf("commit message = %q, want %q", wt.mergedMsg, tt.wantMsg)
+			}
+			if got := strings.Contains(buf.String(), "warning: merge message template"); got != tt.wantWarn {
+				t.Errorf("warning printed = %v, want %v; output: %q", got, tt.wantWarn, buf.String())
+			}
+		})
+	}
+}
+
+func TestPostPipelineInput_UsesBeadAndFinalPhase(t *testing.T) {
+	// Given: a resolved bead and a pipeline whose final phase reported files
+	beadCtx := worklog.BeadContext{TaskTitle: "Fix crash", TaskType: "bug"}
+	output := orchestrator.PipelineOutput{PhaseResults: []orchestrator.PhaseResult{
+		{PhaseName: "execute", Signal: provider.Signal{Summary: "early", FilesChanged: []string{"a.go"}}},
+		{PhaseName: "sign-off", Signal: provider.Signal{Summary: "final", FilesChanged: []string{"b.go"}}},
+	}}
+
+	// When: the post-pipeline input is built
+	in := postPipelineInput("cap-1", beadCtx, output)
+
+	// Then: it carries the bead title and type and the final phase's result
+	want := dashboard.PostPipelineInput{BeadID: "cap-1", Title: "Fix crash", Type: "bug", Summary: "final", FilesChanged: []string{"b.go"}}
+	if !reflect.DeepEqual(in, want) {
+		t.Errorf("input = %+v, want %+v", in, want)
+	}
+}
+
+// stubBranches reports the branches in have as existing.
+type stubBranches struct{ have []string }
+
+func (s stubBranches) VerifyBranch(branch string) error {
+	if !slices.Contains(s.have, branch) {
+		return fmt.Errorf("branch %q: %w", branch, worktree.ErrNoSuchBranch)
+	}
+	return nil
+}
+
+func TestResolveBaseBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		config  string
+		want    string
+		wantErr bool
+	}{
+		{name: "neither set uses the default", want: ""},
+		{name: "config key", config: "develop", want: "develop"},
+		{name: "flag overrides config", flag: "release", config: "develop", want: "release"},
+		{name: "missing branch", flag: "nope", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a repo with main, develop, and release branches
+			cfg := config.DefaultConfig()
+			cfg.Worktree.BaseBranch = tt.config
+			wt := stubBranches{have: []string{"main", "develop", "release"}}
+
+			// When the base branch is resolved
+			got, err := resolveBaseBranch(tt.flag, &cfg, wt)
+
+			// Then it picks the flag, then the config, and rejects a missing branch as a setup error
+			if tt.wantErr {
+				if !errors.Is(err, worktree.ErrNoSuchBranch) || exitCode(err) != exitSetup {
+					t.Errorf("err = %v (exit %d), want ErrNoSuchBranch with exit %d", err, exitCode(err), exitSetup)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("base branch = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickUserConfigDir(t *testing.T) {
+	home := filepath.FromSlash("/home/ada")
+	configDir := filepath.FromSlash("/home/ada/AppData")
+	dir := filepath.Join(configDir, "capsule")
+	legacy := filepath.Join(home, ".config", "capsule")
+
+	tests := []struct {
+		name      string
+		configDir string
+		home      string
+		existing  []string
+		want      string
+	}{
+		{name: "fresh install uses the platform dir", configDir: configDir, home: home, want: dir},
+		{name: "platform dir wins when both exist", configDir: configDir, home: home, existing: []string{dir, legacy}, want: dir},
+		{name: "existing install keeps the old dir", configDir: configDir, home: home, existing: []string{legacy}, want: legacy},
+		{name: "no config dir falls back to home", home: home, want: legacy},
+		{name: "no home uses the platform dir", configDir: configDir, want: dir},
+		{name: "neither known", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given which capsule directories exist
+			exists := func(path string) bool { return slices.Contains(tt.existing, path) }
+
+			// When the user config directory is chosen
+			got := pickUserConfigDir(tt.configDir, tt.home, exists)
+
+			// Then the platform's directory is used unless only the old one exists
+			if got != tt.want {
+				t.Errorf("pickUserConfigDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTildePath(t *testing.T) {
+	home := filepath.FromSlash("/home/ada")
+	tests := []struct {
+		path string
+		home string
+		want string
+	}{
+		{path: "/home/ada/.config/capsule/prompts", home: home, want: "~/.config/capsule/prompts"},
+		{path: "/srv/capsule/prompts", home: home, want: "/srv/capsule/prompts"},
+		{path: "/home/adam/prompts", home: home, want: "/home/adam/prompts"},
+		{path: "/home/ada/prompts", want: "/home/ada/prompts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// When a prompt directory is shown
+			got := tildePath(filepath.FromSlash(tt.path), tt.home)
+
+			// Then only paths under home are shortened, with slashes on every OS
+			if want := tt.want; got != want && got != filepath.FromSlash(want) {
+				t.Errorf("tildePath(%q) = %q, want %q", tt.path, got, want)
+			}
+		})
+	}
+}
+
+func TestPostPipeline_WarnsOnMergeConflict(t *testing.T) {
+	// Given: mock worktree that returns merge conflict
 	var buf bytes.Buffer
 	wt := &mockMergeOps{mainBranch: "main", mergeErr: worktree.ErrMergeConflict}
 	bd := &mockBeadResolver{}
 
 	// When: postPipeline is called
-	postPipeline(&buf, "cap-conflict", wt, bd)
+	postPipeline(&buf, dashboard.PostPipelineInput{BeadID: "cap-conflict"}, wt, bd)
 
 	// Then: merge conflict warning is printed
 	output := buf.String()
@@ -1469,6 +2707,55 @@ func TestPostPipeline_WarnsOnMergeConflict(t *testing.T) {
 	}
 }
 
+func TestPostPipeline_MergeConflictGuidanceMatchesStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy worktree.MergeStrategy
+		want     []string
+		notWant  string
+	}{
+		{
+			name:     "no-ff suggests merge --no-ff",
+			strategy: worktree.MergeNoFF,
+			want:     []string{"git merge --no-ff capsule-cap-mc", "capsule clean cap-mc"},
+			notWant:  "rebase",
+		},
+		{
+			name:     "squash suggests merge --squash and commit",
+			strategy: worktree.MergeSquash,
+			want:     []string{"git merge --squash capsule-cap-mc", "git commit", "capsule clean cap-mc"},
+			notWant:  "--no-ff",
+		},
+		{
+			name:     "rebase-ff suggests rebase --continue and ff-only merge",
+			strategy: worktree.MergeRebaseFF,
+			want:     []string{"git rebase main", "git rebase --continue", "git merge --ff-only capsule-cap-mc", "capsule clean cap-mc"},
+			notWant:  "--no-ff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a worktree using the strategy that reports a conflict
+			var buf bytes.Buffer
+			wt := &mockMergeOps{mainBranch: "main", strategy: tt.strategy, mergeErr: worktree.ErrMergeConflict}
+
+			// When: postPipeline is called
+			postPipeline(&buf, dashboard.PostPipelineInput{BeadID: "cap-mc"}, wt, &mockBeadResolver{})
+
+			// Then: the guidance matches the strategy
+			output := buf.String()
+			for _, w := range tt.want {
+				if !strings.Contains(output, w) {
+					t.Errorf("output missing %q, got: %q", w, output)
+				}
+			}
+			if strings.Contains(output, tt.notWant) {
+				t.Errorf("output should not contain %q, got: %q", tt.notWant, output)
+			}
+		})
+	}
+}
+
 func TestFeature_DashboardCommand(t *testing.T) {
 	t.Run("dashboard subcommand is parsed", func(t *testing.T) {
 		// Given a CLI parser
@@ -1572,7 +2859,7 @@ func TestFeature_CampaignPostTaskFunc(t *testing.T) {
 
 		// Construct PostTaskFunc closure as CampaignCmd.Run does
 		postTaskFunc := func(beadID string) error {
-			postPipeline(io.Discard, beadID, wtMgr, bdClient)
+			postPipeline(io.Discard, dashboard.PostPipelineInput{BeadID: beadID}, wtMgr, bdClient)
 			return nil
 		}
 
@@ -1607,7 +2894,7 @@ func TestFeature_CampaignPostTaskFunc(t *testing.T) {
 
 		// When: PostTaskFunc closure is constructed (as in CampaignCmd.Run)
 		postTaskFunc := func(beadID string) error {
-			postPipeline(io.Discard, beadID, wtMgr, bdClient)
+			postPipeline(io.Discard, dashboard.PostPipelineInput{BeadID: beadID}, wtMgr, bdClient)
 			return nil
 		}
 
@@ -1637,7 +2924,7 @@ func TestFeature_CampaignPostTaskFunc(t *testing.T) {
 
 		// When: PostTaskFunc closure is constructed (as should be done in DashboardCmd.Run)
 		postTaskFunc := func(beadID string) error {
-			postPipeline(io.Discard, beadID, wtMgr, bdClient)
+			postPipeline(io.Discard, dashboard.PostPipelineInput{BeadID: beadID}, wtMgr, bdClient)
 			return nil
 		}
 
@@ -1682,7 +2969,8 @@ func TestFeature_CampaignPostTaskFunc(t *testing.T) {
 
 		// When: PostTaskFunc is called (should write to stderr, not io.Discard)
 		postTaskFunc := func(beadID string) error {
-			return postPipelineWithConflictResolver(&buf, beadID, wtMgr, bdClient, nil)
+			_, err := postPipelineWithConflictResolver(&buf, dashboard.PostPipelineInput{BeadID: beadID}, wtMgr, bdClient, nil)
+			return err
 		}
 
 		err := postTaskFunc("cap-789")
@@ -1718,7 +3006,8 @@ func TestFeature_CampaignPostTaskFunc(t *testing.T) {
 
 		// When: PostTaskFunc is called (should write to stderr, not io.Discard)
 		postTaskFunc := func(beadID string) error {
-			return postPipelineWithConflictResolver(&buf, beadID, wtMgr, bdClient, nil)
+			_, err := postPipelineWithConflictResolver(&buf, dashboard.PostPipelineInput{BeadID: beadID}, wtMgr, bdClient, nil)
+			return err
 		}
 
 		err := postTaskFunc("cap-789")
@@ -1761,7 +3050,8 @@ func TestFeature_CampaignPostTaskFunc(t *testing.T) {
 
 		// When: PostTaskFunc is called with ConflictResolver
 		postTaskFunc := func(beadID string) error {
-			return postPipelineWithConflictResolver(io.Discard, beadID, wtMgr, bdClient, conflictResolver)
+			_, err := postPipelineWithConflictResolver(io.Discard, dashboard.PostPipelineInput{BeadID: beadID}, wtMgr, bdClient, conflictResolver)
+			return err
 		}
 
 		err := postTaskFunc("cap-conflict")
@@ -1802,7 +3092,8 @@ func TestFeature_CampaignPostTaskFunc(t *testing.T) {
 
 		// When: PostTaskFunc is called with ConflictResolver
 		postTaskFunc := func(beadID string) error {
-			return postPipelineWithConflictResolver(io.Discard, beadID, wtMgr, bdClient, conflictResolver)
+			_, err := postPipelineWithConflictResolver(io.Discard, dashboard.PostPipelineInput{BeadID: beadID}, wtMgr, bdClient, conflictResolver)
+			return err
 		}
 
 		err := postTaskFunc("cap-conflict")
@@ -1876,6 +3167,272 @@ func TestFeature_CampaignPostTaskFunc(t *testing.T) {
 			t.Errorf("output missing conflict details: %q", output)
 		}
 	})
+
+	t.Run("campaignPlainTextCallback reports usage and whether the parent was closed", func(t *testing.T) {
+		// Given: a callback for a top-level campaign
+		var buf bytes.Buffer
+		cb := &campaignPlainTextCallback{w: &buf}
+		cb.OnCampaignStart("cap-feat", nil)
+
+		// When: the parent is closed, then a later campaign leaves it open
+		cb.OnParentClosed("cap-feat")
+		cb.OnCampaignComplete(campaign.State{ParentBeadID: "cap-feat", Usage: provider.Usage{InputTokens: 10, OutputTokens: 1}})
+		cb.OnCampaignStart("cap-feat", nil)
+		cb.OnCampaignComplete(campaign.State{ParentBeadID: "cap-feat", ParentOpen: "1 task(s) failed"})
+
+		// Then: both outcomes and the campaign's usage are printed
+		output := buf.String()
+		for _, want := range []string{"Closed cap-feat", "[campaign] Usage: 10 in / 1 out tokens", "cap-feat left open: 1 task(s) failed"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q: %q", want, output)
+			}
+		}
+	})
+
+	t.Run("campaignPlainTextCallback prints the failure policy and skip reasons", func(t *testing.T) {
+		// Given: a callback for a skip-dependents campaign
+		var buf bytes.Buffer
+		cb := &campaignPlainTextCallback{w: &buf, policy: "skip-dependents", breaker: "off"}
+
+		// When: the campaign starts and completes with a task skipped behind a failure
+		cb.OnCampaignStart("cap-feat", nil)
+		cb.OnCampaignComplete(campaign.State{ParentBeadID: "cap-feat", Tasks: []campaign.TaskResult{
+			{BeadID: "cap-2", Status: campaign.TaskFailed},
+			{BeadID: "cap-3", Status: campaign.TaskSkipped, SkipReason: "dependency cap-2 failed"},
+		}})
+
+		// Then: the mode, the circuit breaker, and the skip reason are printed
+		output := buf.String()
+		for _, want := range []string{"[campaign] Failure mode: skip-dependents · circuit breaker: off", "[campaign] Skipped cap-3: dependency cap-2 failed"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q: %q", want, output)
+			}
+		}
+	})
+
+	t.Run("circuit breaker trip is reported by both callbacks", func(t *testing.T) {
+		// Given: plain-text and dashboard callbacks
+		var buf bytes.Buffer
+		plain := &campaignPlainTextCallback{w: &buf}
+		var captured []tea.Msg
+		dash := &dashboardCampaignCallback{statusFn: func(msg tea.Msg) { captured = append(captured, msg) }}
+		trip := campaign.BreakerTrip{
+			Reason: "3 consecutive provider/setup failures (limit 3)",
+			Mode:   campaign.BreakerConsecutive,
+			Count:  3,
+			BeadID: "cap-3",
+			Failures: []campaign.FailureRecord{
+				{BeadID: "cap-1", Kind: campaign.FailureSetup},
+				{BeadID: "cap-2", Kind: campaign.FailureSetup},
+				{BeadID: "cap-3", Kind: campaign.FailureSetup},
+			},
+			Counts: campaign.FailureCounts{Setup: 3, Signal: 1},
+		}
+
+		// When: OnCircuitBroken is called
+		plain.OnCircuitBroken(trip)
+		dash.OnCircuitBroken(trip)
+
+		// Then: the plain-text output explains the reason, counts, and failed tasks
+		output := buf.String()
+		for _, want := range []string{"Circuit breaker tripped", "3 consecutive provider/setup failures", "3 provider/setup, 1 NEEDS_WORK/ERROR", "Failed tasks: cap-1, cap-2, cap-3"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q: %q", want, output)
+			}
+		}
+		// And: the dashboard receives a CampaignCircuitBrokenMsg
+		if len(captured) != 1 {
+			t.Fatalf("captured messages = %d, want 1", len(captured))
+		}
+		msg, ok := captured[0].(dashboard.CampaignCircuitBrokenMsg)
+		if !ok {
+			t.Fatalf("message type = %T, want CampaignCircuitBrokenMsg", captured[0])
+		}
+		if msg.SetupFailures != 3 || msg.SignalFailures != 1 {
+			t.Errorf("counts = (%d, %d), want (3, 1)", msg.SetupFailures, msg.SignalFailures)
+		}
+		if msg.Banner != "stopped after 3 consecutive failures at cap-3" {
+			t.Errorf("banner = %q", msg.Banner)
+		}
+	})
+
+	t.Run("validation phases are reported by both callbacks", func(t *testing.T) {
+		// Given: plain-text and dashboard callbacks
+		var buf bytes.Buffer
+		plain := &campaignPlainTextCallback{w: &buf}
+		var captured []tea.Msg
+		dash := &dashboardCampaignCallback{statusFn: func(msg tea.Msg) { captured = append(captured, msg) }}
+		su := orchestrator.StatusUpdate{
+			BeadID:   "cap-feat-validation",
+			Phase:    "integration-test",
+			Status:   orchestrator.PhaseFailed,
+			Progress: "1/2",
+			Signal:   &provider.Signal{Status: provider.StatusNeedsWork, Feedback: "login flow broken"},
+		}
+
+		// When: OnValidationPhase is called
+		plain.OnValidationPhase(su)
+		dash.OnValidationPhase(su)
+
+		// Then: the plain-text output names the phase and its feedback, indented
+		output := buf.String()
+		for _, want := range []string{"  [", "integration-test failed", "feedback: login flow broken"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q: %q", want, output)
+			}
+		}
+		// And: the dashboard receives the phase update
+		if len(captured) != 1 {
+			t.Fatalf("captured messages = %d, want 1", len(captured))
+		}
+		msg, ok := captured[0].(dashboard.PhaseUpdateMsg)
+		if !ok {
+			t.Fatalf("message type = %T, want PhaseUpdateMsg", captured[0])
+		}
+		if msg.Phase != "integration-test" || msg.Status != dashboard.PhaseFailed || msg.Feedback != "login flow broken" {
+			t.Errorf("msg = %+v, want failed integration-test with feedback", msg)
+		}
+	})
+}
+
+func TestDashboardPostPipelineFunc(t *testing.T) {
+	tests := []struct {
+		name       string
+		wt         *mockMergeOps
+		bd         *mockBeadResolver
+		want       dashboard.PostPipelineResult
+		wantOutput []string
+	}{
+		{
+			name: "merged and closed",
+			wt:   &mockMergeOps{mainBranch: "main"},
+			bd:   &mockBeadResolver{},
+			want: dashboard.PostPipelineResult{Merged: true, BranchCleaned: true, BeadClosed: true},
+			wantOutput: []string{
+				"Merged capsule-cap-1 → main",
+				"Closed cap-1",
+			},
+		},
+		{
+			name: "merge conflict",
+			wt:   &mockMergeOps{mainBranch: "main", mergeErr: worktree.ErrMergeConflict},
+			bd:   &mockBeadResolver{},
+			want: dashboard.PostPipelineResult{MergeConflict: true},
+			wantOutput: []string{
+				"    git checkout main",
+				"    git merge --no-ff capsule-cap-1",
+				"    capsule clean cap-1",
+			},
+		},
+		{
+			name: "cleanup and close fail after merge",
+			wt:   &mockMergeOps{mainBranch: "main", removeErr: errors.New("busy")},
+			bd:   &mockBeadResolver{closeErr: errors.New("bd down")},
+			want: dashboard.PostPipelineResult{Merged: true},
+			wantOutput: []string{
+				"warning: cleanup failed: busy",
+				"warning: bead close failed: bd down",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a dashboard post-pipeline func over mock merge and bead ops
+			fn := dashboardPostPipelineFunc(tt.wt, tt.bd, nil, nil, nil)
+
+			// When it runs for a bead
+			got, err := fn(dashboard.PostPipelineInput{BeadID: "cap-1"})
+
+			// Then the result flags reflect each lifecycle step
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Merged != tt.want.Merged || got.MergeConflict != tt.want.MergeConflict ||
+				got.BranchCleaned != tt.want.BranchCleaned || got.BeadClosed != tt.want.BeadClosed {
+				t.Errorf("result = %+v, want flags %+v", got, tt.want)
+			}
+			// And the lifecycle output is captured as messages
+			for _, want := range tt.wantOutput {
+				if !slices.Contains(got.Messages, want) {
+					t.Errorf("Messages = %q, want line %q", got.Messages, want)
+				}
+			}
+		})
+	}
+}
+
+// stubRunSummaries returns the summaries in runs by bead ID.
+type stubRunSummaries map[string]worklog.RunSummary
+
+func (s stubRunSummaries) ReadRunSummary(beadID string) (worklog.RunSummary, error) {
+	if rs, ok := s[beadID]; ok {
+		return rs, nil
+	}
+	return worklog.RunSummary{}, os.ErrNotExist
+}
+
+func TestDashboardPostPipelineFunc_SummaryComment(t *testing.T) {
+	runs := stubRunSummaries{"cap-1": {BeadID: "cap-1", Status: worklog.RunPassed, Phases: []worklog.PhaseSummary{
+		{Name: "sign-off", Status: "PASS", Attempts: 1, Summary: "All done."},
+	}}}
+	tests := []struct {
+		name         string
+		bd           *mockBeadResolver
+		runs         runSummaryReader
+		wantComments int
+		wantWarning  bool
+	}{
+		{name: "off posts nothing", bd: &mockBeadResolver{}},
+		{name: "on comments before closing", bd: &mockBeadResolver{}, runs: runs, wantComments: 1},
+		{name: "bd without comments warns and still closes", bd: &mockBeadResolver{commentErr: errors.New("unknown command")}, runs: runs, wantWarning: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a passed run's summary and a post-pipeline func
+			fn := dashboardPostPipelineFunc(&mockMergeOps{mainBranch: "main"}, tt.bd, nil, nil, tt.runs)
+
+			// When the bead is merged and closed
+			got, err := fn(dashboard.PostPipelineInput{BeadID: "cap-1"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Then the summary is commented only when enabled, and a failed comment does not stop the close
+			if len(tt.bd.comments) != tt.wantComments {
+				t.Fatalf("comments = %q, want %d", tt.bd.comments, tt.wantComments)
+			}
+			if tt.wantComments > 0 && !strings.Contains(tt.bd.comments[0], "sign-off: All done.") {
+				t.Errorf("comment = %q, want the sign-off summary", tt.bd.comments[0])
+			}
+			if !got.BeadClosed {
+				t.Error("BeadClosed = false, want true")
+			}
+			warned := slices.ContainsFunc(got.Messages, func(m string) bool { return strings.HasPrefix(m, "warning: bead summary comment failed") })
+			if warned != tt.wantWarning {
+				t.Errorf("Messages = %q, want comment warning %v", got.Messages, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestSummaryComments(t *testing.T) {
+	// Given config with summary comments off, then on
+	cfg := config.DefaultConfig()
+	wl := worklog.NewManager(nil, "", t.TempDir())
+
+	// Then no reader is returned while off, so nothing is commented
+	if got := summaryComments(&cfg, wl); got != nil {
+		t.Errorf("summaryComments() = %v with the setting off, want nil", got)
+	}
+	cfg.Bead.PostSummaryComment = true
+	if got := summaryComments(&cfg, wl); got == nil {
+		t.Error("summaryComments() = nil with the setting on, want the worklog")
+	}
+	// And a bead without a summary gets no comment
+	if got := summaryComment(wl, "cap-none"); got != "" {
+		t.Errorf("summaryComment() = %q for a bead without a summary, want empty", got)
+	}
 }
 
 // mockCampaignRunner captures campaign.Config for testing.
@@ -1886,3 +3443,1823 @@ type mockCampaignRunner struct {
 func (m *mockCampaignRunner) Run(ctx context.Context, parentID string) error {
 	return nil
 }
+
+func TestDashboardTaskStore(t *testing.T) {
+	// Given: a stored campaign whose second task failed during execute
+	store := state.NewFileStore(t.TempDir())
+	err := store.Save(campaign.State{
+		ID:           "cap-feat",
+		ParentBeadID: "cap-feat",
+		Tasks: []campaign.TaskResult{
+			{BeadID: "cap-1", Status: campaign.TaskCompleted},
+			{BeadID: "cap-2", Status: campaign.TaskFailed, Error: "boom", PhaseResults: []orchestrator.PhaseResult{
+				{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusNeedsWork, Feedback: "fix it"}},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := &dashboardTaskStore{store: store}
+
+	// When: the task reports are read
+	reports, err := ts.TaskReports("cap-feat")
+	if err != nil {
+		t.Fatalf("TaskReports() error = %v", err)
+	}
+
+	// Then: the failed task's phase results are returned as dashboard reports
+	got := reports["cap-2"]
+	if len(got) != 1 || got[0].Status != dashboard.PhaseFailed || got[0].Feedback != "fix it" {
+		t.Errorf("reports[cap-2] = %+v, want one failed execute report", got)
+	}
+
+	// When: the task is recorded as passed after a retry
+	if err := ts.RecordTaskPassed("cap-feat", "cap-2", []dashboard.PhaseReport{{PhaseName: "execute", Status: dashboard.PhasePassed}}); err != nil {
+		t.Fatalf("RecordTaskPassed() error = %v", err)
+	}
+
+	// Then: the stored state marks it completed
+	st, _, err := store.Load("cap-feat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := st.Tasks[1]; task.Status != campaign.TaskCompleted || task.Error != "" {
+		t.Errorf("task = %+v, want completed without error", task)
+	}
+	if st.Tasks[1].PhaseResults[0].Signal.Status != provider.StatusPass {
+		t.Errorf("stored status = %q, want PASS", st.Tasks[1].PhaseResults[0].Signal.Status)
+	}
+}
+
+func TestPhaseTracker(t *testing.T) {
+	// Given a tracker wrapping a status callback
+	var tracker phaseTracker
+	var calls int
+	cb := tracker.wrap(func(orchestrator.StatusUpdate) { calls++ })
+
+	// Then it reports the pipeline start before any phase runs
+	if got := tracker.String(); got != "pipeline before its first phase" {
+		t.Errorf("String() = %q before any phase", got)
+	}
+
+	// When a phase starts running and then passes
+	cb(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "execute", Status: orchestrator.PhaseRunning})
+	cb(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "execute", Status: orchestrator.PhasePassed})
+
+	// Then the running phase is recorded and every update is forwarded
+	if got, want := tracker.String(), "cap-1 (phase execute)"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if calls != 2 {
+		t.Errorf("wrapped callback called %d times, want 2", calls)
+	}
+}
+
+func TestLoadPipelinePhases(t *testing.T) {
+	five, kiro := 5, "kiro"
+	p := config.Pipeline{
+		Phases:    "default",
+		Overrides: map[string]config.PhaseOverride{"test-review": {MaxRetries: &five}},
+		Profiles: map[string]config.PhaseProfile{
+			"quick": {Phases: "minimal", Overrides: map[string]config.PhaseOverride{"execute": {Provider: &kiro}}},
+		},
+	}
+
+	t.Run("top-level overrides apply without a profile", func(t *testing.T) {
+		// When the default pipeline is loaded
+		phases, err := loadPipelinePhases(p, "")
+		if err != nil {
+			t.Fatalf("loadPipelinePhases: %v", err)
+		}
+
+		// Then test-review carries the override
+		if len(phases) != 6 || phases[1].Name != "test-review" || phases[1].MaxRetries != 5 {
+			t.Errorf("phases = %+v, want 6 phases with test-review max_retries 5", phases)
+		}
+	})
+
+	t.Run("profile with its own phases skips top-level overrides", func(t *testing.T) {
+		// When the quick profile is loaded; the top-level override names
+		// test-review, which the minimal preset lacks
+		phases, err := loadPipelinePhases(p, "quick")
+		if err != nil {
+			t.Fatalf("loadPipelinePhases: %v", err)
+		}
+
+		// Then only the profile's overrides apply
+		if len(phases) != 3 || phases[1].Name != "execute" || phases[1].Provider != "kiro" {
+			t.Errorf("phases = %+v, want minimal preset with execute on kiro", phases)
+		}
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := loadPipelinePhases(p, "full")
+		if !errors.Is(err, config.ErrUnknownProfile) {
+			t.Errorf("loadPipelinePhases() error = %v, want ErrUnknownProfile", err)
+		}
+	})
+}
+
+func TestResolvePhasesAndProvider(t *testing.T) {
+	// Given a profile that moves execute onto kiro with a long timeout
+	kiro := "kiro"
+	cfg := config.DefaultConfig()
+	cfg.Runtime.Provider = "claude"
+	cfg.Runtime.Timeout = time.Minute
+	cfg.Pipeline.Profiles = map[string]config.PhaseProfile{
+		"quick": {Phases: "minimal", Overrides: map[string]config.PhaseOverride{"execute": {Provider: &kiro}}},
+	}
+
+	// When phases and providers are resolved for the profile
+	setup, err := resolvePhasesAndProvider(&cfg, "quick", "", map[string]time.Duration{"execute": time.Hour})
+	if err != nil {
+		t.Fatalf("resolvePhasesAndProvider: %v", err)
+	}
+
+	// Then the profile's phases carry the timeout, which the provider covers
+	if len(setup.phases) != 3 || setup.phases[1].Timeout != time.Hour {
+		t.Errorf("phases = %+v, want minimal preset with execute at 1h", setup.phases)
+	}
+	if cfg.Runtime.Timeout != time.Hour {
+		t.Errorf("runtime.timeout = %v, want 1h", cfg.Runtime.Timeout)
+	}
+	if setup.provider.Name() != "claude" {
+		t.Errorf("provider = %q, want claude", setup.provider.Name())
+	}
+	if _, ok := setup.providers["kiro"]; !ok || len(setup.providers) != 1 {
+		t.Errorf("providers = %v, want kiro only", setup.providers)
+	}
+
+	// And an unknown profile fails before any provider is built
+	_, err = resolvePhasesAndProvider(&cfg, "full", "", nil)
+	if !errors.Is(err, config.ErrUnknownProfile) {
+		t.Errorf("resolvePhasesAndProvider() error = %v, want ErrUnknownProfile", err)
+	}
+}
+
+func TestResolvePhasesAndProvider_Validation(t *testing.T) {
+	// Given validation phases that name a profile moving execute onto codex
+	// with a two-hour timeout
+	codex, twoHours := "codex", 2*time.Hour
+	cfg := config.DefaultConfig()
+	cfg.Runtime.Timeout = time.Minute
+	cfg.Pipeline.Profiles = map[string]config.PhaseProfile{
+		"check": {Phases: "minimal", Overrides: map[string]config.PhaseOverride{"execute": {Provider: &codex, Timeout: &twoHours}}},
+	}
+
+	// When phases and providers are resolved with that validation set
+	setup, err := resolvePhasesAndProvider(&cfg, "", "check", nil)
+	if err != nil {
+		t.Fatalf("resolvePhasesAndProvider: %v", err)
+	}
+
+	// Then the run's phases are unchanged, but the validation phases'
+	// provider and timeout are covered
+	if len(setup.phases) != 6 {
+		t.Errorf("phases = %d, want the 6 default phases", len(setup.phases))
+	}
+	if _, ok := setup.providers["codex"]; !ok {
+		t.Errorf("providers = %v, want codex", setup.providers)
+	}
+	if cfg.Runtime.Timeout != twoHours {
+		t.Errorf("runtime.timeout = %v, want 2h", cfg.Runtime.Timeout)
+	}
+
+	// And a validation set that does not resolve fails at setup
+	_, err = resolvePhasesAndProvider(&cfg, "", filepath.Join(t.TempDir(), "missing.yaml"), nil)
+	if err == nil || !strings.Contains(err.Error(), "campaign.validation_phases") {
+		t.Errorf("resolvePhasesAndProvider() error = %v, want a campaign.validation_phases error", err)
+	}
+}
+
+func TestLoadPhaseSet(t *testing.T) {
+	five, kiro := 5, "kiro"
+	p := config.Pipeline{
+		Phases:    "default",
+		Overrides: map[string]config.PhaseOverride{"test-review": {MaxRetries: &five}},
+		Profiles: map[string]config.PhaseProfile{
+			"quick": {Phases: "minimal", Overrides: map[string]config.PhaseOverride{"execute": {Provider: &kiro}}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		spec  string
+		check func(t *testing.T, phases []orchestrator.PhaseDefinition)
+	}{
+		{
+			name: "profile gets its overrides",
+			spec: "quick",
+			check: func(t *testing.T, phases []orchestrator.PhaseDefinition) {
+				if len(phases) != 3 || phases[1].Provider != "kiro" {
+					t.Errorf("phases = %+v, want minimal preset with execute on kiro", phases)
+				}
+			},
+		},
+		{
+			name: "pipeline.phases gets pipeline.overrides",
+			spec: "default",
+			check: func(t *testing.T, phases []orchestrator.PhaseDefinition) {
+				if len(phases) != 6 || phases[1].MaxRetries != 5 {
+					t.Errorf("phases = %+v, want test-review max_retries 5", phases)
+				}
+			},
+		},
+		{
+			name: "other preset is used as is",
+			spec: "minimal",
+			check: func(t *testing.T, phases []orchestrator.PhaseDefinition) {
+				if len(phases) != 3 || phases[1].Provider != "" {
+					t.Errorf("phases = %+v, want the minimal preset unchanged", phases)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When the phase set is loaded
+			phases, err := loadPhaseSet(p, tt.spec)
+			if err != nil {
+				t.Fatalf("loadPhaseSet(%q): %v", tt.spec, err)
+			}
+
+			// Then it matches the configured resolution
+			tt.check(t, phases)
+		})
+	}
+}
+
+func TestCampaignCmd_OverrideCampaign(t *testing.T) {
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{flag: "", want: "abort"},
+		{flag: "stop", want: "stop"},
+		{flag: "continue", want: "continue"},
+		{flag: "skip-dependents", want: "skip-dependents"},
+	}
+	for _, tt := range tests {
+		t.Run("on-failure="+tt.flag, func(t *testing.T) {
+			// Given the default config, whose failure mode is abort
+			cfg := config.DefaultConfig()
+
+			// When --on-failure is applied
+			(&CampaignCmd{OnFailure: tt.flag}).overrideCampaign(&cfg)
+
+			// Then a set flag replaces the configured mode and a valid mode passes validation
+			if cfg.Campaign.FailureMode != tt.want {
+				t.Errorf("failure mode = %q, want %q", cfg.Campaign.FailureMode, tt.want)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestServeStatus(t *testing.T) {
+	// Given --listen on a free local port
+	cfg := config.DefaultConfig()
+	overrideListen(&cfg, "127.0.0.1:0")
+	if src := cfg.Source("runtime.listen"); src != "--listen" {
+		t.Errorf("source = %q, want --listen", src)
+	}
+
+	// When the status endpoint is started
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var buf bytes.Buffer
+	srv, err := serveStatus(ctx, &buf, cfg.Runtime.Listen, statusapi.NewTracker(statusapi.KindPipeline, "cap-1"), nil)
+	if err != nil {
+		t.Fatalf("serveStatus() error = %v", err)
+	}
+	defer srv.Close()
+
+	// Then its URL is printed and it serves the run's snapshot
+	url := "http://" + srv.Addr() + "/status"
+	if !strings.Contains(buf.String(), url) {
+		t.Errorf("output = %q, want it to contain %s", buf.String(), url)
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET /status: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var snap statusapi.Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if snap.Pipeline == nil || snap.Pipeline.BeadID != "cap-1" {
+		t.Errorf("snapshot = %+v, want cap-1's pipeline", snap)
+	}
+}
+
+// stubCampaignPlanner returns a fixed campaign plan.
+type stubCampaignPlanner struct {
+	tasks []campaign.BeadInfo
+	err   error
+}
+
+func (s stubCampaignPlanner) Plan(string) ([]campaign.BeadInfo, error) { return s.tasks, s.err }
+
+func TestCampaignCmd_Plan(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Campaign.ValidationPhases = "validation"
+	tasks := []campaign.BeadInfo{
+		{ID: "cap-1.2", Title: "Add parser", Priority: 1, Type: "task"},
+		{ID: "cap-1.3", Title: "Wire parser", Priority: 2, Type: "task", BlockedBy: []string{"cap-1.2"}},
+		{ID: "cap-1.4", Title: "Reporting", Priority: 2, Type: "feature"},
+	}
+
+	t.Run("text lists tasks in run order with the settings", func(t *testing.T) {
+		// Given a planner with three tasks
+		// When the plan is printed
+		var buf bytes.Buffer
+		err := (&CampaignCmd{ParentID: "cap-1"}).plan(&buf, stubCampaignPlanner{tasks: tasks}, &cfg, 7)
+
+		// Then each task has its number, priority, phase count, and blockers
+		if err != nil {
+			t.Fatalf("plan() error = %v", err)
+		}
+		out := buf.String()
+		for _, want := range []string{
+			"Campaign plan for cap-1 (3 tasks)",
+			"1  cap-1.2  Add parser   P1        task     7",
+			"2  cap-1.3  Wire parser  P2        task     7             cap-1.2",
+			"3  cap-1.4  Reporting    P2        feature  sub-campaign  -",
+			"Ordering: priority",
+			"Failure mode: " + cfg.Campaign.FailureMode,
+			"Circuit breaker: trips after",
+			"Validation: validation phases after every task passes",
+			"no worktree created and no provider called",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("output missing %q:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("json is one plan event", func(t *testing.T) {
+		// Given JSON output
+		// When the plan is printed
+		var buf bytes.Buffer
+		err := (&CampaignCmd{ParentID: "cap-1", Output: outputJSON}).plan(&buf, stubCampaignPlanner{tasks: tasks}, &cfg, 7)
+
+		// Then it is a single plan event listing the tasks
+		if err != nil {
+			t.Fatalf("plan() error = %v", err)
+		}
+		events := decodeLines(t, &buf)
+		if len(events) != 1 || events[0]["event"] != "plan" || events[0]["validation_phases"] != "validation" || events[0]["ordering"] != "priority" {
+			t.Fatalf("events = %v, want one plan event", events)
+		}
+		got := events[0]["tasks"].([]any)
+		if len(got) != 3 || got[1].(map[string]any)["blocked_by"].([]any)[0] != "cap-1.2" || got[2].(map[string]any)["phases"] != 0.0 {
+			t.Errorf("tasks = %v", got)
+		}
+	})
+
+	t.Run("no ready tasks fails like a run", func(t *testing.T) {
+		// Given a parent without ready children
+		// When the plan is printed as JSON
+		var buf bytes.Buffer
+		err := (&CampaignCmd{ParentID: "cap-1", Output: outputJSON}).plan(&buf, stubCampaignPlanner{err: campaign.ErrNoTasks}, &cfg, 7)
+
+		// Then it returns ErrNoTasks with the pipeline exit code and a result event
+		if !errors.Is(err, campaign.ErrNoTasks) || exitCode(err) != exitPipeline {
+			t.Errorf("plan() error = %v (exit %d), want ErrNoTasks exit %d", err, exitCode(err), exitPipeline)
+		}
+		events := decodeLines(t, &buf)
+		if len(events) != 1 || events[0]["event"] != "result" || events[0]["exit_code"] != float64(exitPipeline) {
+			t.Errorf("events = %v, want a failed result", events)
+		}
+	})
+}
+
+func TestPromptsExportCmd(t *testing.T) {
+	// Given a project without prompt overrides
+	dir := filepath.Join(t.TempDir(), ".capsule", "prompts")
+	want, err := fs.ReadFile(capsule.Prompts, "execute.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// When the execute prompt is exported
+	var buf bytes.Buffer
+	if err := (&PromptsExportCmd{Phase: "execute"}).run(&buf, capsule.Prompts, dir); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	// Then the embedded default is written to the override directory
+	path := filepath.Join(dir, "execute.md")
+	if got, err := os.ReadFile(path); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("exported prompt = %q, %v; want the embedded default", got, err)
+	}
+	if !strings.Contains(buf.String(), "wrote "+path) {
+		t.Errorf("output = %q, want the path written", buf.String())
+	}
+
+	// When it is exported again, without and then with --force
+	if err := os.WriteFile(path, []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err = (&PromptsExportCmd{Phase: "execute"}).run(io.Discard, capsule.Prompts, dir)
+
+	// Then the edited override is kept unless forced
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("second export error = %v, want a --force hint", err)
+	}
+	if err := (&PromptsExportCmd{Phase: "execute", Force: true}).run(io.Discard, capsule.Prompts, dir); err != nil {
+		t.Fatalf("forced export error = %v", err)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, want) {
+		t.Error("forced export did not overwrite the override")
+	}
+
+	// When an unknown phase is exported
+	err = (&PromptsExportCmd{Phase: "deploy"}).run(io.Discard, capsule.Prompts, dir)
+
+	// Then the error lists the built-in prompts
+	if err == nil || !strings.Contains(err.Error(), "available: ") || !strings.Contains(err.Error(), "execute") {
+		t.Errorf("unknown phase error = %v, want the available prompts", err)
+	}
+}
+
+func TestPhasesCmd_Run(t *testing.T) {
+	// Given the thorough preset with a provider override on execute
+	phases := orchestrator.ThoroughPhases()
+	phases[2].Provider = "kiro"
+
+	// When the phases are printed
+	var buf bytes.Buffer
+	if err := (&PhasesCmd{}).run(&buf, phases); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	// Then each phase appears with its kind, retries, and details
+	out := buf.String()
+	for _, want := range []string{
+		"PHASE", "RETRY TARGET",
+		"test-quality", "reviewer", "prompt=test-quality",
+		"provider=kiro",
+		`command="make lint" optional`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if got := strings.Count(out, "\n"); got != len(phases)+1 {
+		t.Errorf("output has %d lines, want %d:\n%s", got, len(phases)+1, out)
+	}
+}
+
+func TestInitCmd_RunThenCheck(t *testing.T) {
+	// Given the embedded manifest and an empty project
+	files, err := scaffold.Manifest(capsule.ConfigTemplate, capsule.Prompts, capsule.Templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+
+	// When --check runs before init
+	var buf bytes.Buffer
+	err = (&InitCmd{}).check(&buf, root, files)
+
+	// Then the missing config is reported and the check fails
+	if err == nil || !strings.Contains(buf.String(), "missing .capsule/config.yaml") {
+		t.Errorf("check before init: err = %v, output:\n%s", err, buf.String())
+	}
+
+	// When init runs
+	buf.Reset()
+	if err := (&InitCmd{}).run(&buf, root, files); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	// Then the created files and ignored artifacts are listed
+	for _, want := range []string{"created .capsule/config.yaml", "created prompts/execute.md", "created templates/worklog.md.template", "added to .gitignore: .capsule/worktrees/"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	// And a second init refuses to overwrite them
+	if err := (&InitCmd{}).run(io.Discard, root, files); !errors.Is(err, scaffold.ErrExists) {
+		t.Errorf("second run error = %v, want ErrExists", err)
+	}
+
+	// And --check passes
+	buf.Reset()
+	if err := (&InitCmd{}).check(&buf, root, files); err != nil {
+		t.Errorf("check after init: %v\n%s", err, buf.String())
+	}
+}
+
+func TestInitCmd_CheckReportsInvalidConfig(t *testing.T) {
+	// Given a scaffolded project whose config has a typo
+	files, err := scaffold.Manifest(capsule.ConfigTemplate, capsule.Prompts, capsule.Templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := (&InitCmd{}).run(io.Discard, root, files); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, scaffold.ConfigPath), []byte("runtime:\n  provdier: claude\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the setup is checked
+	var buf bytes.Buffer
+	err = (&InitCmd{}).check(&buf, root, files)
+
+	// Then the config error is reported
+	if err == nil || !strings.Contains(buf.String(), "invalid .capsule/config.yaml") {
+		t.Errorf("err = %v, output:\n%s", err, buf.String())
+	}
+}
+
+func TestInitCmd_Demo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("skipping: git not on PATH")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Capsule Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "capsule-test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Capsule Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "capsule-test@example.com")
+
+	manifest, err := scaffold.Manifest(capsule.ConfigTemplate, capsule.Prompts, capsule.Templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := scaffold.Demo(manifest, capsule.DemoProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		bdErr      error
+		wantOutput string
+	}{
+		{name: "bd imports the beads", wantOutput: "imported beads from issues.jsonl"},
+		{name: "bd missing", bdErr: exec.ErrNotFound, wantOutput: "warning: the demo beads are not imported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a directory that does not exist yet and a fake bd
+			dir := filepath.Join(t.TempDir(), "demo")
+			var bdCalls []string
+			run := func(dir, name string, args ...string) ([]byte, error) {
+				if name == "bd" {
+					bdCalls = append(bdCalls, strings.Join(args, " "))
+					return nil, tt.bdErr
+				}
+				return runCommand(dir, name, args...)
+			}
+
+			// When the demo is created
+			var buf bytes.Buffer
+			if err := (&InitCmd{}).demo(&buf, dir, files, run); err != nil {
+				t.Fatalf("demo: %v\n%s", err, buf.String())
+			}
+
+			// Then the project, its setup, and its scripted responses are written
+			for _, p := range []string{"AGENTS.md", "src/main.go", "src/go.mod", scaffold.DemoIssues, ".capsule/scripted.yaml", scaffold.ConfigPath, "prompts/execute.md"} {
+				if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(p))); err != nil {
+					t.Errorf("missing %s: %v", p, err)
+				}
+			}
+			// And bd is asked to import the fixtures, or the user is told how
+			if len(bdCalls) == 0 || bdCalls[0] != "init --prefix=demo" {
+				t.Errorf("bd calls = %q, want init first", bdCalls)
+			}
+			if !strings.Contains(buf.String(), tt.wantOutput) {
+				t.Errorf("output missing %q:\n%s", tt.wantOutput, buf.String())
+			}
+			// And everything is committed
+			status, err := runCommand(dir, "git", "status", "--porcelain")
+			if err != nil || len(status) != 0 {
+				t.Errorf("git status = %q, %v; want a clean tree", status, err)
+			}
+			// And the commands to run the demo are printed
+			if want := "capsule run demo-1.1.1 --provider scripted"; !strings.Contains(buf.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, buf.String())
+			}
+
+			// When the demo is created again
+			err = (&InitCmd{}).demo(io.Discard, dir, files, run)
+
+			// Then it refuses to overwrite the files
+			if !errors.Is(err, scaffold.ErrExists) {
+				t.Errorf("second demo error = %v, want ErrExists", err)
+			}
+
+			// And with --force it rewrites them without an empty commit
+			if err := (&InitCmd{Force: true}).demo(io.Discard, dir, files, run); err != nil {
+				t.Errorf("forced demo: %v", err)
+			}
+			commits, err := runCommand(dir, "git", "rev-list", "--count", "HEAD")
+			if err != nil || strings.TrimSpace(string(commits)) != "1" {
+				t.Errorf("commits = %q, %v; want 1", commits, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidateCmd(t *testing.T) {
+	tests := []struct {
+		name      string
+		yaml      string
+		wantErr   bool
+		wantLines []string
+	}{
+		{
+			name:      "valid config",
+			yaml:      "runtime:\n  provider: scripted\n",
+			wantLines: []string{"ok"},
+		},
+		{
+			name:    "config and phase problems",
+			yaml:    "runtime:\n  provider: claud\ncampaign:\n  failure_mode: halt\npipeline:\n  overrides:\n    sign-off:\n      retry_target: nope\n",
+			wantErr: true,
+			wantLines: []string{
+				`config.yaml:4: campaign.failure_mode: must be "abort", "stop", "continue", or "skip-dependents", got "halt"`,
+				`config.yaml:2: runtime.provider: unknown provider "claud"`,
+				`config.yaml:5: pipeline.phases: phases[4] "sign-off": retry_target "nope" not found`,
+			},
+		},
+		{
+			name:      "decode problems",
+			yaml:      "runtime:\n  timeout: soon\n",
+			wantErr:   true,
+			wantLines: []string{`config.yaml:2: runtime.timeout: invalid duration "soon"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a config file
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When it is validated
+			var buf bytes.Buffer
+			err := (&ConfigValidateCmd{}).run(&buf, func() (*config.Config, error) {
+				return config.LoadLayered(path)
+			})
+
+			// Then each problem is printed on its own line and any fails the command
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.wantLines) {
+				t.Fatalf("output:\n%s\nwant %d lines", buf.String(), len(tt.wantLines))
+			}
+			for i, want := range tt.wantLines {
+				if !strings.Contains(lines[i], want) {
+					t.Errorf("line %d = %q, want containing %q", i, lines[i], want)
+				}
+			}
+			if err != nil && exitCode(err) != exitSetup {
+				t.Errorf("exitCode = %d, want %d", exitCode(err), exitSetup)
+			}
+		})
+	}
+}
+
+// mockArchiveStore stubs worklog.Manager's archive operations.
+type mockArchiveStore struct {
+	worklogs  map[string]string
+	summaries map[string]string
+	entries   []worklog.ArchiveEntry
+	cutoff    time.Time
+}
+
+func (m *mockArchiveStore) ReadWorklog(id string) (string, error) {
+	if s, ok := m.worklogs[id]; ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("read %s: %w", id, os.ErrNotExist)
+}
+
+func (m *mockArchiveStore) ReadSummary(id string) (string, error) {
+	if s, ok := m.summaries[id]; ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("read %s: %w", id, os.ErrNotExist)
+}
+
+func (m *mockArchiveStore) List() ([]worklog.ArchiveEntry, error) { return m.entries, nil }
+
+func (m *mockArchiveStore) Prune(cutoff time.Time) ([]string, error) {
+	m.cutoff = cutoff
+	var removed []string
+	for _, e := range m.entries {
+		if e.ModTime.Before(cutoff) {
+			removed = append(removed, e.BeadID)
+		}
+	}
+	return removed, nil
+}
+
+func TestLogsCmd_Run(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	newStore := func() *mockArchiveStore {
+		return &mockArchiveStore{
+			worklogs:  map[string]string{"cap-1": "# Worklog: cap-1\n"},
+			summaries: map[string]string{"cap-1": "All phases passed.\n"},
+			entries: []worklog.ArchiveEntry{
+				{BeadID: "cap-1", ModTime: now.Add(-time.Hour), Size: 2048},
+				{BeadID: "cap-old", ModTime: now.AddDate(0, 0, -45), Size: 12, Warning: "empty worklog"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		cmd        LogsCmd
+		wantOut    []string
+		wantErr    string
+		wantCutoff time.Time
+	}{
+		{name: "worklog", cmd: LogsCmd{BeadID: "cap-1"}, wantOut: []string{"# Worklog: cap-1"}},
+		{name: "summary", cmd: LogsCmd{BeadID: "cap-1", Summary: true}, wantOut: []string{"All phases passed."}},
+		{name: "missing summary", cmd: LogsCmd{BeadID: "cap-2", Summary: true}, wantErr: `no archived summary for "cap-2"`},
+		{name: "list", cmd: LogsCmd{List: true}, wantOut: []string{"BEAD", "cap-1", "2.0 KiB", "cap-old", "12 B", "warning: empty worklog"}},
+		{
+			name:       "prune",
+			cmd:        LogsCmd{Prune: true, OlderThan: "30d"},
+			wantOut:    []string{"Pruned cap-old", "Pruned 1 archive(s) older than 30d"},
+			wantCutoff: now.AddDate(0, 0, -30),
+		},
+		{name: "prune bad age", cmd: LogsCmd{Prune: true, OlderThan: "soon"}, wantErr: `invalid age "soon"`},
+		{name: "no mode", cmd: LogsCmd{}, wantErr: "specify a bead ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given an archive store
+			store := newStore()
+			var buf bytes.Buffer
+
+			// When the logs command runs
+			err := tt.cmd.run(&buf, store, now)
+
+			// Then it prints the expected output or fails descriptively
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("run() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+			if !store.cutoff.Equal(tt.wantCutoff) {
+				t.Errorf("Prune cutoff = %v, want %v", store.cutoff, tt.wantCutoff)
+			}
+		})
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "12h", want: 12 * time.Hour},
+		{in: "0d", wantErr: true},
+		{in: "-1h", wantErr: true},
+		{in: "xd", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseAge(%q) = %v, %v; want %v, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLabelProviderFactory(t *testing.T) {
+	// Given a factory built from config
+	cfg := config.DefaultConfig()
+	factory := labelProviderFactory(&cfg)
+
+	// When a registered provider is requested
+	p, err := factory("kiro", 20*time.Minute)
+
+	// Then it is created
+	if err != nil {
+		t.Fatalf("factory(kiro) error = %v", err)
+	}
+	if p.Name() != "kiro" {
+		t.Errorf("Name() = %q, want kiro", p.Name())
+	}
+
+	// And an unknown provider is reported as such
+	var upe *provider.UnknownProviderError
+	if _, err := factory("nope", time.Minute); !errors.As(err, &upe) {
+		t.Errorf("factory(nope) error = %v, want UnknownProviderError", err)
+	}
+}
+
+func TestPhaseProviders(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Runtime.Providers = map[string]config.ProviderConfig{"cheap-model": {Command: "llm"}}
+
+	tests := []struct {
+		name    string
+		phases  []orchestrator.PhaseDefinition
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "phases without a provider need none",
+			phases: []orchestrator.PhaseDefinition{{Name: "plan"}, {Name: "code"}},
+			want:   []string{},
+		},
+		{
+			name: "reviewers on a declared provider, workers on a built-in",
+			phases: []orchestrator.PhaseDefinition{
+				{Name: "code", Provider: "kiro"},
+				{Name: "code-review", Provider: "cheap-model"},
+				{Name: "test-review", Provider: "cheap-model"},
+				{Name: "merge"},
+			},
+			want: []string{"cheap-model", "kiro"},
+		},
+		{
+			name:    "unknown provider names the phase",
+			phases:  []orchestrator.PhaseDefinition{{Name: "plan"}, {Name: "code-review", Provider: "cheep-model"}},
+			wantErr: `phase "code-review": unknown provider "cheep-model"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a registry with the built-in and declared providers
+			reg := newProviderRegistry(&cfg)
+
+			// When the phases' providers are created
+			providers, err := phaseProviders(reg, tt.phases)
+
+			// Then each named provider is created once, or the unknown one is reported
+			if tt.wantErr != "" {
+				var upe *provider.UnknownProviderError
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !errors.As(err, &upe) {
+					t.Fatalf("phaseProviders() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("phaseProviders() error = %v", err)
+			}
+			got := make([]string, 0, len(providers))
+			for name, p := range providers {
+				if p.Name() != name {
+					t.Errorf("providers[%q].Name() = %q", name, p.Name())
+				}
+				got = append(got, name)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("providers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProviderRegistry_DeclaredProviders(t *testing.T) {
+	// Given a config that declares an extra CLI provider
+	cfg := config.DefaultConfig()
+	cfg.Runtime.Providers = map[string]config.ProviderConfig{
+		"llm": {Command: "llm", Args: []string{"-m", "gpt-4o"}, PromptStdin: true, Timeout: time.Minute},
+	}
+
+	// When the registry is built
+	reg := newProviderRegistry(&cfg)
+
+	// Then the declared provider resolves alongside the built-ins
+	p, err := reg.NewProvider("llm")
+	if err != nil {
+		t.Fatalf("NewProvider(llm) error = %v", err)
+	}
+	if p.Name() != "llm" {
+		t.Errorf("Name() = %q, want llm", p.Name())
+	}
+	want := []string{"claude", "codex", "kiro", "llm", "scripted"}
+	if got := reg.AvailableProviders(); !slices.Equal(got, want) {
+		t.Errorf("AvailableProviders() = %v, want %v", got, want)
+	}
+
+	// And an unknown name lists every registered provider
+	_, err = reg.NewProvider("gemini")
+	if err == nil || !strings.Contains(err.Error(), "claude, codex, kiro, llm, scripted") {
+		t.Errorf("NewProvider(gemini) error = %v, want the available providers", err)
+	}
+}
+
+func TestLogFlags_Logger(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       LogFlags
+		tui         bool
+		wantEnabled bool
+	}{
+		{name: "no flags discard", flags: LogFlags{}, wantEnabled: false},
+		{name: "verbose logs to stderr", flags: LogFlags{Verbose: true}, wantEnabled: true},
+		{name: "verbose under a TUI stays quiet", flags: LogFlags{Verbose: true}, tui: true, wantEnabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given the flags
+			// When the logger is built
+			logger, closeLog, err := tt.flags.logger(tt.tui)
+			if err != nil {
+				t.Fatalf("logger() error = %v", err)
+			}
+			defer func() { _ = closeLog() }()
+
+			// Then debug logging is enabled only when it has somewhere safe to go
+			if got := logger.Enabled(context.Background(), slog.LevelDebug); got != tt.wantEnabled {
+				t.Errorf("debug enabled = %v, want %v", got, tt.wantEnabled)
+			}
+		})
+	}
+
+	t.Run("log file receives JSON even under a TUI", func(t *testing.T) {
+		// Given a log file path
+		path := t.TempDir() + "/capsule.log"
+		flags := LogFlags{Verbose: true, LogFile: path}
+
+		// When a message is logged while a TUI is shown
+		logger, closeLog, err := flags.logger(true)
+		if err != nil {
+			t.Fatalf("logger() error = %v", err)
+		}
+		logger.Debug("provider invocation", "phase", "execute")
+		if err := closeLog(); err != nil {
+			t.Fatalf("close: %v", err)
+		}
+
+		// Then the file holds a JSON record
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"msg":"provider invocation","phase":"execute"`) {
+			t.Errorf("log file = %s, want JSON record", data)
+		}
+	})
+}
+
+func TestApplyTimeouts(t *testing.T) {
+	tests := []struct {
+		name         string
+		flags        PhaseTimeoutFlags
+		deadline     time.Duration
+		wantPhase    time.Duration
+		wantRuntime  time.Duration
+		wantPerPhase map[string]time.Duration
+		wantWarning  bool
+		wantErr      bool
+	}{
+		{name: "no flags inherit config", wantPhase: 5 * time.Minute, wantRuntime: 5 * time.Minute},
+		{name: "phase timeout", flags: PhaseTimeoutFlags{PhaseTimeout: []string{"10m"}}, wantPhase: 10 * time.Minute, wantRuntime: 10 * time.Minute},
+		{name: "deprecated seconds warn", flags: PhaseTimeoutFlags{Timeout: 120}, wantPhase: 2 * time.Minute, wantRuntime: 2 * time.Minute, wantWarning: true},
+		{name: "phase timeout wins over deprecated", flags: PhaseTimeoutFlags{PhaseTimeout: []string{"1m"}, Timeout: 120}, wantPhase: time.Minute, wantRuntime: time.Minute},
+		{
+			name:         "named phases keep the default",
+			flags:        PhaseTimeoutFlags{PhaseTimeout: []string{"execute=20m", "lint=30s", "execute=25m"}},
+			wantPhase:    5 * time.Minute,
+			wantRuntime:  5 * time.Minute,
+			wantPerPhase: map[string]time.Duration{"execute": 25 * time.Minute, "lint": 30 * time.Second},
+		},
+		{name: "invalid duration rejected", flags: PhaseTimeoutFlags{PhaseTimeout: []string{"execute=soon"}}, wantErr: true},
+		{name: "non-positive duration rejected", flags: PhaseTimeoutFlags{PhaseTimeout: []string{"0s"}}, wantErr: true},
+		{name: "missing phase name rejected", flags: PhaseTimeoutFlags{PhaseTimeout: []string{"=5m"}}, wantErr: true},
+		{name: "negative deadline rejected", deadline: -time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a config with the default runtime timeout
+			cfg := config.DefaultConfig()
+			var w bytes.Buffer
+
+			// When the flags are applied
+			got, perPhase, err := applyTimeouts(&w, &cfg, &tt.flags, tt.deadline)
+
+			// Then the phase and provider timeouts follow the flags
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyTimeouts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.wantPhase || cfg.Runtime.Timeout != tt.wantRuntime {
+				t.Errorf("phase = %v, runtime.timeout = %v; want %v, %v", got, cfg.Runtime.Timeout, tt.wantPhase, tt.wantRuntime)
+			}
+			if !maps.Equal(perPhase, tt.wantPerPhase) {
+				t.Errorf("per-phase = %v, want %v", perPhase, tt.wantPerPhase)
+			}
+			if hasWarning := strings.Contains(w.String(), "--timeout is deprecated"); hasWarning != tt.wantWarning {
+				t.Errorf("warning = %q, want warning %v", w.String(), tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestApplyPhaseTimeouts(t *testing.T) {
+	phases := func() []orchestrator.PhaseDefinition {
+		return []orchestrator.PhaseDefinition{
+			{Name: "execute", Kind: orchestrator.Worker},
+			{Name: "review", Kind: orchestrator.Reviewer, Timeout: 15 * time.Minute},
+			{Name: "lint", Kind: orchestrator.Gate, Command: "make lint"},
+		}
+	}
+	tests := []struct {
+		name        string
+		perPhase    map[string]time.Duration
+		wantTimes   []time.Duration
+		wantRuntime time.Duration
+		wantErr     string
+	}{
+		{
+			name:        "phases file timeouts raise the provider timeout",
+			wantTimes:   []time.Duration{0, 15 * time.Minute, 0},
+			wantRuntime: 15 * time.Minute,
+		},
+		{
+			name:        "flags override the phases file",
+			perPhase:    map[string]time.Duration{"review": time.Minute, "lint": 30 * time.Second},
+			wantTimes:   []time.Duration{0, time.Minute, 30 * time.Second},
+			wantRuntime: 5 * time.Minute,
+		},
+		{
+			name:     "unknown phase",
+			perPhase: map[string]time.Duration{"deploy": time.Minute},
+			wantErr:  `unknown phase "deploy" (valid: execute, review, lint)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given phases and a 5m runtime timeout
+			cfg := config.DefaultConfig()
+			ps := phases()
+
+			// When the per-phase timeouts are applied
+			err := applyPhaseTimeouts(&cfg, ps, tt.perPhase)
+
+			// Then phases and the provider timeout reflect them
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyPhaseTimeouts() error = %v", err)
+			}
+			for i, want := range tt.wantTimes {
+				if ps[i].Timeout != want {
+					t.Errorf("%s timeout = %v, want %v", ps[i].Name, ps[i].Timeout, want)
+				}
+			}
+			if cfg.Runtime.Timeout != tt.wantRuntime {
+				t.Errorf("runtime.timeout = %v, want %v", cfg.Runtime.Timeout, tt.wantRuntime)
+			}
+		})
+	}
+}
+
+// stubMergeRecorder captures merge outcomes.
+type stubMergeRecorder struct {
+	beadID string
+	merge  *worklog.MergeSummary
+	err    error
+}
+
+func (s *stubMergeRecorder) RecordMerge(beadID string, merge worklog.MergeSummary) error {
+	s.beadID = beadID
+	s.merge = &merge
+	return s.err
+}
+
+func TestRecordMerge(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      dashboard.PostPipelineResult
+		err         error
+		recErr      error
+		want        worklog.MergeSummary
+		wantWarning bool
+	}{
+		{
+			name:   "merged and cleaned up",
+			result: dashboard.PostPipelineResult{Merged: true, BranchCleaned: true, BeadClosed: true},
+			want:   worklog.MergeSummary{Status: worklog.MergeMerged, BranchCleaned: true, BeadClosed: true},
+		},
+		{
+			name:   "conflict",
+			result: dashboard.PostPipelineResult{MergeConflict: true},
+			want:   worklog.MergeSummary{Status: worklog.MergeConflict},
+		},
+		{
+			name: "merge failed",
+			err:  errors.New("git merge: exit status 128"),
+			want: worklog.MergeSummary{Status: worklog.MergeFailed, Error: "git merge: exit status 128"},
+		},
+		{
+			name:   "no summary to update",
+			result: dashboard.PostPipelineResult{Merged: true},
+			recErr: fmt.Errorf("worklog: %w", os.ErrNotExist),
+			want:   worklog.MergeSummary{Status: worklog.MergeMerged},
+		},
+		{
+			name:        "write fails",
+			result:      dashboard.PostPipelineResult{Merged: true},
+			recErr:      errors.New("disk full"),
+			want:        worklog.MergeSummary{Status: worklog.MergeMerged},
+			wantWarning: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a recorder
+			rec := &stubMergeRecorder{err: tt.recErr}
+			var buf bytes.Buffer
+
+			// When the post-pipeline outcome is recorded
+			recordMerge(&buf, rec, "cap-1", tt.result, tt.err)
+
+			// Then the merge summary matches and only real failures warn
+			if rec.beadID != "cap-1" || rec.merge == nil || *rec.merge != tt.want {
+				t.Errorf("recorded %s %+v, want cap-1 %+v", rec.beadID, rec.merge, tt.want)
+			}
+			if got := strings.Contains(buf.String(), "warning:"); got != tt.wantWarning {
+				t.Errorf("output = %q, want warning %v", buf.String(), tt.wantWarning)
+			}
+		})
+	}
+
+	t.Run("nil recorder", func(t *testing.T) {
+		// Given no recorder, When recording, Then nothing happens
+		recordMerge(io.Discard, nil, "cap-1", dashboard.PostPipelineResult{}, nil)
+	})
+}
+
+func TestCheckProviderHealth(t *testing.T) {
+	notReady := &provider.HealthError{Provider: "claude", Err: errors.New("Invalid API key"), Hint: "run `claude login`"}
+	tests := []struct {
+		name     string
+		checkErr error
+		skip     bool
+		wantErr  bool
+	}{
+		{name: "healthy"},
+		{name: "unhealthy", checkErr: notReady, wantErr: true},
+		{name: "unhealthy but skipped", checkErr: notReady, skip: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a provider whose health check returns tt.checkErr
+			calls := 0
+			p := &provider.MockProvider{NameVal: "claude", HealthCheckFunc: func(context.Context) error {
+				calls++
+				return tt.checkErr
+			}}
+
+			// When the health check runs before the pipeline
+			err := checkProviderHealth(context.Background(), p, tt.skip)
+
+			// Then a failure is a setup error with the remediation and the escape hatch
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkProviderHealth() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.skip && calls != 0 {
+				t.Errorf("health check ran %d times with skip set", calls)
+			}
+			if err == nil {
+				return
+			}
+			if !errors.Is(err, notReady) || exitCode(err) != exitSetup {
+				t.Errorf("err = %v (exit %d), want the HealthError with exit %d", err, exitCode(err), exitSetup)
+			}
+			for _, want := range []string{"claude login", "--skip-health-check"} {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("err = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+// stubPlanner returns canned phase plans and records the input.
+type stubPlanner struct {
+	plans []orchestrator.PhasePlan
+	err   error
+	input orchestrator.PipelineInput
+}
+
+func (s *stubPlanner) Plan(_ context.Context, input orchestrator.PipelineInput) ([]orchestrator.PhasePlan, error) {
+	s.input = input
+	return s.plans, s.err
+}
+
+func TestRunCmd_DryRun(t *testing.T) {
+	plans := []orchestrator.PhasePlan{
+		{Phase: orchestrator.PhaseDefinition{Name: "execute", Kind: orchestrator.Worker}, Provider: "claude", MaxAttempts: 3, Timeout: 5 * time.Minute, Run: true, PromptBytes: 4200},
+		{Phase: orchestrator.PhaseDefinition{Name: "execute-review", Kind: orchestrator.Reviewer, RetryTarget: "execute"}, Provider: "claude", MaxAttempts: 3, Run: true, PromptBytes: 900},
+		{Phase: orchestrator.PhaseDefinition{Name: "lint", Kind: orchestrator.Gate, Command: "make lint", Condition: "files_match:*.go"}, MaxAttempts: 3, SkipReason: "condition not met: files_match:*.go"},
+	}
+	tests := []struct {
+		name       string
+		plans      []orchestrator.PhasePlan
+		resolveErr error
+		wantErr    string
+		wantOut    []string
+	}{
+		{
+			name:  "prints the plan",
+			plans: plans,
+			wantOut: []string{
+				"Dry run for cap-1: Add validation",
+				"execute-review  reviewer  yes  3         execute       900 B",
+				"condition not met: files_match:*.go command=\"make lint\"",
+				"4.1 KiB",
+				"no worktree created and no provider called",
+			},
+		},
+		{
+			name:       "bead not found is only a warning",
+			plans:      plans,
+			resolveErr: bead.ErrNotFound,
+			wantOut:    []string{`warning: bead "cap-1" not found`, "Dry run for cap-1\n"},
+		},
+		{
+			name: "a failing phase fails the dry run",
+			plans: []orchestrator.PhasePlan{
+				{Phase: orchestrator.PhaseDefinition{Name: "custom", Kind: orchestrator.Worker, Prompt: "nope"}, Run: true, Err: errors.New("template not found")},
+			},
+			wantErr: "custom: template not found",
+			wantOut: []string{"FAILS"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a planner and a bead resolver
+			cmd := &RunCmd{BeadID: "cap-1", skip: []string{"sign-off"}}
+			planner := &stubPlanner{plans: tt.plans}
+			bd := &mockBeadResolver{ctx: worklog.BeadContext{TaskTitle: "Add validation"}, resolveErr: tt.resolveErr}
+			if tt.resolveErr != nil {
+				bd.ctx = worklog.BeadContext{}
+			}
+			var buf bytes.Buffer
+
+			// When the dry run executes
+			err := cmd.dryRun(&buf, planner, bd)
+
+			// Then the plan is printed and only phase failures are errors
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("dryRun() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("dryRun() error = %v, want %q", err, tt.wantErr)
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+			if !slices.Equal(planner.input.SkipPhases, []string{"sign-off"}) {
+				t.Errorf("SkipPhases = %v, want [sign-off]", planner.input.SkipPhases)
+			}
+		})
+	}
+}
+
+func TestKongParse_DryRun(t *testing.T) {
+	// Given the CLI parser
+	var cli CLI
+	parser, err := kong.New(&cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// When run is parsed with --dry-run
+	if _, err := parser.Parse([]string{"run", "cap-1", "--dry-run"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Then the flag is set
+	if !cli.Run.DryRun {
+		t.Error("DryRun = false, want true")
+	}
+}
+
+// stubCheckpointLoader implements checkpointLoader with a fixed result.
+type stubCheckpointLoader struct {
+	cp    orchestrator.PipelineCheckpoint
+	found bool
+	err   error
+}
+
+func (s stubCheckpointLoader) LoadCheckpoint(string) (orchestrator.PipelineCheckpoint, bool, error) {
+	return s.cp, s.found, s.err
+}
+
+func TestResumeCmd_Preflight(t *testing.T) {
+	phases := []orchestrator.PhaseDefinition{
+		{Name: "execute", Kind: orchestrator.Worker},
+		{Name: "execute-review", Kind: orchestrator.Reviewer, RetryTarget: "execute"},
+		{Name: "merge", Kind: orchestrator.Worker},
+	}
+	checkpoint := orchestrator.PipelineCheckpoint{
+		BeadID: "cap-1",
+		PhaseResults: []orchestrator.PhaseResult{
+			{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusPass}},
+			{PhaseName: "execute-review", Signal: provider.Signal{Status: provider.StatusSkip}},
+			{PhaseName: "merge", Signal: provider.Signal{Status: provider.StatusError, Feedback: "conflict"}},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		loader       stubCheckpointLoader
+		worktree     bool
+		branch       bool
+		wantErr      string
+		wantOutput   []string
+		wantNoOutput []string
+	}{
+		{
+			name:         "prints phases skipped from the checkpoint",
+			loader:       stubCheckpointLoader{cp: checkpoint, found: true},
+			worktree:     true,
+			wantOutput:   []string{"Resuming cap-1", "Skipping execute (PASS in checkpoint)", "Skipping execute-review (SKIP in checkpoint)"},
+			wantNoOutput: []string{"Skipping merge"},
+		},
+		{
+			name:     "no checkpoint",
+			loader:   stubCheckpointLoader{},
+			worktree: true,
+			wantErr:  "no checkpoint for \"cap-1\"",
+		},
+		{
+			name:     "checkpoint load error",
+			loader:   stubCheckpointLoader{err: errors.New("corrupt")},
+			worktree: true,
+			wantErr:  "corrupt",
+		},
+		{
+			name:     "worktree removed",
+			loader:   stubCheckpointLoader{cp: checkpoint, found: true},
+			worktree: false,
+			wantErr:  "worktree for \"cap-1\" no longer exists",
+		},
+		{
+			name:       "branch survives its directory",
+			loader:     stubCheckpointLoader{cp: checkpoint, found: true},
+			branch:     true,
+			wantOutput: []string{"Resuming cap-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a bead with the checkpoint and worktree state
+			cmd := &ResumeCmd{BeadID: "cap-1"}
+			var buf bytes.Buffer
+
+			// When the resume preflight runs
+			err := cmd.preflight(&buf, tt.loader, &mockWorktreeOps{exists: tt.worktree, branch: tt.branch}, phases)
+
+			// Then it fails with a setup error or lists the skipped phases
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("preflight() error = %v, want containing %q", err, tt.wantErr)
+				}
+				if code := exitCode(err); code != exitSetup {
+					t.Errorf("exitCode() = %d, want %d", code, exitSetup)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("preflight() error = %v", err)
+			}
+			out := buf.String()
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+			for _, unwanted := range tt.wantNoOutput {
+				if strings.Contains(out, unwanted) {
+					t.Errorf("output contains %q:\n%s", unwanted, out)
+				}
+			}
+		})
+	}
+}
+
+func TestKongParse_Resume(t *testing.T) {
+	// Given the CLI parser
+	var cli CLI
+	parser, err := kong.New(&cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// When resume is parsed with a bead ID and flags
+	ctx, err := parser.Parse([]string{"resume", "cap-1", "--provider", "kiro", "--no-tui"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Then the resume command is selected with its flags
+	if ctx.Command() != "resume <bead-id>" {
+		t.Errorf("Command() = %q, want %q", ctx.Command(), "resume <bead-id>")
+	}
+	if cli.Resume.BeadID != "cap-1" || cli.Resume.Provider != "kiro" || !cli.Resume.NoTUI {
+		t.Errorf("Resume = %+v", cli.Resume)
+	}
+}
+
+func TestAnyPauseRequested(t *testing.T) {
+	yes := func() bool { return true }
+	no := func() bool { return false }
+	tests := []struct {
+		name    string
+		checks  []func() bool
+		wantNil bool
+		want    bool
+	}{
+		{name: "no checks", wantNil: true},
+		{name: "only nil checks", checks: []func() bool{nil, nil}, wantNil: true},
+		{name: "signal pause", checks: []func() bool{yes, nil}, want: true},
+		{name: "dashboard pause", checks: []func() bool{no, yes}, want: true},
+		{name: "no pause", checks: []func() bool{no, no}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given the pause checks
+			// When they are combined
+			got := anyPauseRequested(tt.checks...)
+
+			// Then the result pauses when any check does
+			if tt.wantNil {
+				if got != nil {
+					t.Fatal("anyPauseRequested() should be nil")
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("anyPauseRequested() = nil")
+			}
+			if got() != tt.want {
+				t.Errorf("pause = %v, want %v", got(), tt.want)
+			}
+		})
+	}
+}
+
+// stubStatusSources implements worktreeLister, checkpointLister, and
+// campaignLister with fixed results.
+type stubStatusSources struct {
+	worktrees   []string
+	checkpoints []state.CheckpointInfo
+	campaigns   []campaign.State
+	campaignErr error
+}
+
+func (s stubStatusSources) List() ([]string, error) { return s.worktrees, nil }
+
+func (s stubStatusSources) ListCheckpoints() ([]state.CheckpointInfo, error) {
+	return s.checkpoints, nil
+}
+
+// stubCampaignLister adapts stubStatusSources to campaignLister, whose List
+// method collides with worktreeLister's.
+type stubCampaignLister struct{ stubStatusSources }
+
+func (s stubCampaignLister) List() ([]campaign.State, error) { return s.campaigns, s.campaignErr }
+
+func TestStatusCmd_Table(t *testing.T) {
+	saved := time.Date(2026, 10, 16, 14, 2, 0, 0, time.Local)
+	src := stubStatusSources{
+		worktrees: []string{"cap-1", "cap-9"},
+		checkpoints: []state.CheckpointInfo{
+			{BeadID: "cap-1", SavedAt: saved, Completed: 3, LastPhase: "test-review"},
+			{BeadID: "cap-2", SavedAt: saved, Completed: 1, LastPhase: "test-writer"},
+			{BeadID: "cap-3", Warning: "corrupt"},
+		},
+		campaigns: []campaign.State{
+			{ParentBeadID: "feat-1", Status: campaign.CampaignRunning, StartedAt: saved, Tasks: []campaign.TaskResult{
+				{BeadID: "cap-1", Status: campaign.TaskRunning},
+				{BeadID: "cap-4", Status: campaign.TaskCompleted},
+				{BeadID: "cap-5", Status: campaign.TaskRunning},
+				{BeadID: "cap-6", Status: campaign.TaskPending},
+			}},
+			{ParentBeadID: "feat-0", Status: campaign.CampaignCompleted},
+		},
+	}
+
+	// Given worktrees, checkpoints, and campaigns
+	cmd := &StatusCmd{}
+	var out, errOut bytes.Buffer
+
+	// When status runs
+	if err := cmd.run(&out, &errOut, src, src, stubCampaignLister{src}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	// Then each in-flight bead has one row
+	got := out.String()
+	for _, want := range []string{
+		"BEAD", "LAST PHASE", "CHECKPOINT", "WORKTREE",
+		"cap-1  feat-1    test-review  3       2026-10-16 14:02  yes",
+		"cap-2  -         test-writer  1       2026-10-16 14:02  no",
+		"warning: corrupt",
+		"cap-5  feat-1",
+		"cap-9  -         -            0       -                 yes",
+		"feat-1    running  1/4",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	// And completed campaigns and their finished tasks are left out
+	for _, unwanted := range []string{"feat-0", "cap-4", "cap-6"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("output contains %q:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestStatusCmd_JSON(t *testing.T) {
+	// Given one checkpointed bead with a worktree
+	src := stubStatusSources{
+		worktrees:   []string{"cap-1"},
+		checkpoints: []state.CheckpointInfo{{BeadID: "cap-1", Completed: 2, LastPhase: "execute"}},
+	}
+	cmd := &StatusCmd{JSON: true}
+	var out, errOut bytes.Buffer
+
+	// When status runs with --json
+	if err := cmd.run(&out, &errOut, src, src, stubCampaignLister{src}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	// Then the output decodes to the report
+	var report statusReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if len(report.Capsules) != 1 {
+		t.Fatalf("capsules = %+v, want 1", report.Capsules)
+	}
+	if c := report.Capsules[0]; c.BeadID != "cap-1" || c.LastPhase != "execute" || c.Phases != 2 || !c.Worktree {
+		t.Errorf("capsule = %+v", c)
+	}
+	if report.Campaigns == nil {
+		t.Error("campaigns should encode as an empty list, not null")
+	}
+}
+
+func TestStatusCmd_Empty(t *testing.T) {
+	// Given nothing in flight
+	cmd := &StatusCmd{}
+	var out, errOut bytes.Buffer
+
+	// When status runs
+	if err := cmd.run(&out, &errOut, stubStatusSources{}, stubStatusSources{}, stubCampaignLister{}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	// Then it says so
+	if got := out.String(); got != "No capsules in flight.\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestStatusCmd_CampaignReadWarning(t *testing.T) {
+	// Given a campaign file that cannot be parsed alongside a readable one
+	src := stubStatusSources{
+		campaigns:   []campaign.State{{ParentBeadID: "feat-1", Status: campaign.CampaignPaused}},
+		campaignErr: errors.New("state: parsing feat-2.json"),
+	}
+	cmd := &StatusCmd{}
+	var out, errOut bytes.Buffer
+
+	// When status runs
+	if err := cmd.run(&out, &errOut, src, src, stubCampaignLister{src}); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	// Then the readable campaign is listed and the bad file is a warning
+	if !strings.Contains(out.String(), "feat-1") {
+		t.Errorf("output missing feat-1:\n%s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "warning: state: parsing feat-2.json") {
+		t.Errorf("stderr = %q, want warning", errOut.String())
+	}
+}
+
+func TestPlainTextCallback_Verbosity(t *testing.T) {
+	// A writer passes, a reviewer sends it back, and the retry passes.
+	update := func(phase string, status orchestrator.PhaseStatus, attempt int, sig *provider.Signal) orchestrator.StatusUpdate {
+		return orchestrator.StatusUpdate{BeadID: "cap-7", Phase: phase, Status: status, Progress: "1/2", Attempt: attempt, MaxRetry: 3, Signal: sig}
+	}
+	updates := []orchestrator.StatusUpdate{
+		update("test-writer", orchestrator.PhaseRunning, 1, nil),
+		{BeadID: "cap-7", Phase: "test-writer", Status: orchestrator.PhaseProgress, Message: "reading files"},
+		update("test-writer", orchestrator.PhasePassed, 1, &provider.Signal{Summary: "wrote tests", FilesChanged: []string{"a_test.go"}, Feedback: "kept it small"}),
+		update("test-review", orchestrator.PhaseRunning, 1, nil),
+		update("test-review", orchestrator.PhaseFailed, 1, &provider.Signal{Summary: "missing cases", Feedback: "add edge cases"}),
+		update("test-writer", orchestrator.PhaseRunning, 2, nil),
+		update("test-writer", orchestrator.PhasePassed, 2, &provider.Signal{Summary: "added cases", FilesChanged: []string{"a_test.go"}}),
+	}
+	tests := []struct {
+		verbosity tui.Verbosity
+		wantLines int
+		want      string // A line fragment only this level prints.
+	}{
+		{verbosity: tui.VerbosityQuiet, wantLines: 3},
+		{verbosity: tui.VerbosityNormal, wantLines: 13, want: "summary: wrote tests"},
+		{verbosity: tui.VerbosityVerbose, wantLines: 15, want: "retrying with feedback: add edge cases"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.verbosity), func(t *testing.T) {
+			// Given a plain text callback at the verbosity
+			var buf bytes.Buffer
+			cb := plainTextCallback(&buf, tt.verbosity)
+
+			// When the updates are reported
+			for _, su := range updates {
+				cb(su)
+			}
+
+			// Then the level decides how many lines are printed
+			out := buf.String()
+			if got := strings.Count(out, "\n"); got != tt.wantLines {
+				t.Errorf("lines = %d, want %d:\n%s", got, tt.wantLines, out)
+			}
+			if tt.want != "" && !strings.Contains(out, tt.want) {
+				t.Errorf("output missing %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
+
+func TestPlainTextCallback_SkipReason(t *testing.T) {
+	// Given a plain text callback, even at quiet verbosity
+	var buf bytes.Buffer
+	cb := plainTextCallback(&buf, tui.VerbosityQuiet)
+
+	// When a phase is skipped by its condition
+	cb(orchestrator.StatusUpdate{
+		BeadID: "cap-7", Phase: "frontend", Status: orchestrator.PhaseSkipped, Progress: "3/6",
+		SkipReason: "condition not met: diff_match:web/*",
+	})
+
+	// Then the reason follows the status
+	if !strings.Contains(buf.String(), "[3/6] frontend skipped — condition not met: diff_match:web/*\n") {
+		t.Errorf("output missing the skip reason:\n%s", buf.String())
+	}
+}
+
+func TestPlainTextCallback_Percentage(t *testing.T) {
+	// Given a plain text callback
+	var buf bytes.Buffer
+	cb := plainTextCallback(&buf, tui.VerbosityNormal)
+
+	// When a phase passes with its position in the pipeline
+	cb(orchestrator.StatusUpdate{
+		BeadID: "cap-7", Phase: "test-review", Status: orchestrator.PhasePassed,
+		Progress: "2/6", PhaseIndex: 2, PhaseTotal: 6, FractionComplete: 2.0 / 6,
+		Signal: &provider.Signal{Status: provider.StatusPass},
+	})
+
+	// Then the share of the pipeline done follows the progress
+	if !strings.Contains(buf.String(), "[2/6 33%] test-review passed\n") {
+		t.Errorf("output missing the percentage:\n%s", buf.String())
+	}
+}
+
+func TestCampaignStatusCallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		wantPrefix  bool
+	}{
+		{name: "sequential campaign prints plain lines", concurrency: 1},
+		{name: "concurrent campaign prefixes lines with the bead", concurrency: 3, wantPrefix: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a campaign status callback
+			var buf bytes.Buffer
+			cb := campaignStatusCallback(&buf, tt.concurrency, tui.VerbosityNormal)
+
+			// When a phase completes with a summary
+			cb(orchestrator.StatusUpdate{
+				BeadID:   "cap-7",
+				Phase:    "execute",
+				Status:   orchestrator.PhasePassed,
+				Progress: "3/6",
+				Signal:   &provider.Signal{Status: provider.StatusPass, Summary: "Implemented it"},
+			})
+
+			// Then every line carries the bead ID only when tasks run concurrently
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("lines = %q, want status and summary", lines)
+			}
+			for _, line := range lines {
+				if got := strings.HasPrefix(line, "cap-7 "); got != tt.wantPrefix {
+					t.Errorf("line %q prefixed = %v, want %v", line, got, tt.wantPrefix)
+				}
+			}
+		})
+	}
+}
+
+func TestKongParse_CampaignConcurrency(t *testing.T) {
+	// Given the CLI parser
+	var cli CLI
+	parser, err := kong.New(&cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// When campaign is parsed with --concurrency
+	if _, err := parser.Parse([]string{"campaign", "cap-epic", "--concurrency", "3"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Then the limit is set
+	if cli.Campaign.Concurrency != 3 {
+		t.Errorf("Concurrency = %d, want 3", cli.Campaign.Concurrency)
+	}
+}
+
+func TestKongParse_StatusJSON(t *testing.T) {
+	// Given the CLI parser
+	var cli CLI
+	parser, err := kong.New(&cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// When status is parsed with --json
+	ctx, err := parser.Parse([]string{"status", "--json"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// Then the status command is selected with JSON output
+	if ctx.Command() != "status" || !cli.Status.JSON {
+		t.Errorf("Command() = %q, JSON = %v; want status with JSON", ctx.Command(), cli.Status.JSON)
+	}
+}
+
+func TestBeadSetupError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "not installed", err: bead.ErrBDNotInstalled, want: "https://github.com/steveyegge/beads"},
+		{name: "no database", err: fmt.Errorf("bead: bd list: %w", bead.ErrNoBeadRepo), want: "run `bd init`"},
+		{name: "old version", err: bead.ErrBDVersionUnsupported, want: "upgrade bd to " + bead.MinBDVersion},
+		{name: "other", err: errors.New("bead: bd list: database is locked"), want: "database is locked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given an error from bead.Client.Check
+			// When it is described for the user
+			got := beadSetupError(tt.err)
+
+			// Then it keeps the sentinel and says how to fix it
+			if !errors.Is(got, tt.err) {
+				t.Errorf("beadSetupError() = %v, does not wrap %v", got, tt.err)
+			}
+			if !strings.Contains(got.Error(), tt.want) {
+				t.Errorf("beadSetupError() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
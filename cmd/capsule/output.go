@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/smileynet/capsule/internal/campaign"
+	"github.com/smileynet/capsule/internal/config"
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/tui"
+)
+
+// outputJSON is the --output value that prints one JSON object per line.
+const outputJSON = "json"
+
+// jsonEmitter writes events as JSON lines. It is safe for concurrent use, so
+// campaign tasks running in parallel never interleave within a line.
+type jsonEmitter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newJSONEmitter(w io.Writer) *jsonEmitter {
+	return &jsonEmitter{enc: json.NewEncoder(w)}
+}
+
+// emit writes v as one line. Encoding errors are dropped: like the plain
+// text output, progress reporting never fails the run.
+func (e *jsonEmitter) emit(v any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_ = e.enc.Encode(v)
+}
+
+// phaseEvent is a pipeline phase update, provider progress, or setup warning.
+type phaseEvent struct {
+	TS           time.Time      `json:"ts"`
+	Event        string         `json:"event"` // "phase", "progress", "retrying", or "warning".
+	BeadID       string         `json:"bead_id"`
+	Phase        string         `json:"phase"`
+	Status       string         `json:"status"`
+	Attempt      int            `json:"attempt"`
+	DurationMS   int64          `json:"duration_ms"`
+	Summary      string         `json:"summary"`
+	FilesChanged []string       `json:"files_changed"`
+	Feedback     string         `json:"feedback"`
+	Warning      string         `json:"warning,omitempty"`
+	Message      string         `json:"message,omitempty"` // Provider progress or retry wait; set only for "progress" and "retrying".
+	Usage        provider.Usage `json:"usage,omitzero"`
+	Missing      []string       `json:"missing_artifacts,omitempty"` // Set when the artifact check failed the phase.
+	SkipReason   string         `json:"skip_reason,omitempty"`       // Why a skipped phase was skipped, when known.
+}
+
+// jsonStatusCallback returns a StatusCallback that emits each update as a
+// phaseEvent. Signal details are filled in once the phase has finished.
+func jsonStatusCallback(e *jsonEmitter) orchestrator.StatusCallback {
+	return func(su orchestrator.StatusUpdate) {
+		ev := phaseEvent{
+			TS:           time.Now().UTC(),
+			Event:        "phase",
+			BeadID:       su.BeadID,
+			Phase:        su.Phase,
+			Status:       string(su.Status),
+			Attempt:      su.Attempt,
+			DurationMS:   su.Duration.Milliseconds(),
+			FilesChanged: []string{},
+		}
+		switch {
+		case su.Warning != "":
+			ev.Event = "warning"
+			ev.Warning = su.Warning
+		case su.Status == orchestrator.PhaseProgress:
+			ev.Event = "progress"
+			ev.Message = su.Message
+		case su.Status == orchestrator.PhaseRetrying:
+			ev.Event = "retrying"
+			ev.Message = su.Message
+		}
+		ev.Usage = su.Usage
+		ev.Missing = su.MissingArtifacts
+		ev.SkipReason = su.SkipReason
+		if su.Signal != nil {
+			ev.Summary = su.Signal.Summary
+			ev.Feedback = su.Signal.Feedback
+			if len(su.Signal.FilesChanged) > 0 {
+				ev.FilesChanged = su.Signal.FilesChanged
+			}
+		}
+		e.emit(ev)
+	}
+}
+
+// phaseResultJSON is one phase in a terminating result.
+type phaseResultJSON struct {
+	Phase        string         `json:"phase"`
+	Status       string         `json:"status"`
+	Attempt      int            `json:"attempt"`
+	DurationMS   int64          `json:"duration_ms"`
+	Summary      string         `json:"summary"`
+	FilesChanged []string       `json:"files_changed"`
+	Feedback     string         `json:"feedback"`
+	Usage        provider.Usage `json:"usage,omitzero"`
+	SkipReason   string         `json:"skip_reason,omitempty"`
+}
+
+func phaseResultsJSON(results []orchestrator.PhaseResult) []phaseResultJSON {
+	out := make([]phaseResultJSON, len(results))
+	for i, pr := range results {
+		files := pr.Signal.FilesChanged
+		if files == nil {
+			files = []string{}
+		}
+		out[i] = phaseResultJSON{
+			Phase:        pr.PhaseName,
+			Status:       string(pr.Signal.Status),
+			Attempt:      pr.Attempt,
+			DurationMS:   pr.Duration.Milliseconds(),
+			Summary:      pr.Signal.Summary,
+			FilesChanged: files,
+			Feedback:     pr.Signal.Feedback,
+			Usage:        pr.Usage,
+			SkipReason:   pr.SkipReason,
+		}
+	}
+	return out
+}
+
+// runResultEvent is the last line capsule run prints in JSON mode.
+type runResultEvent struct {
+	TS          time.Time          `json:"ts"`
+	Event       string             `json:"event"` // Always "result".
+	BeadID      string             `json:"bead_id"`
+	Success     bool               `json:"success"`
+	ExitCode    int                `json:"exit_code"`
+	Error       string             `json:"error,omitempty"`
+	FailedPhase string             `json:"failed_phase,omitempty"`
+	Phases      []phaseResultJSON  `json:"phases"`
+	Findings    []provider.Finding `json:"findings,omitempty"`
+	Usage       provider.Usage     `json:"usage,omitzero"` // Summed over every phase attempt.
+}
+
+// emitRunResult emits the terminating result of a run. err is what the
+// command returns, so exit_code matches the process exit status.
+func (e *jsonEmitter) emitRunResult(beadID string, output orchestrator.PipelineOutput, err error) {
+	ev := runResultEvent{
+		TS:       time.Now().UTC(),
+		Event:    "result",
+		BeadID:   beadID,
+		Success:  err == nil,
+		ExitCode: exitCode(err),
+		Phases:   phaseResultsJSON(output.PhaseResults),
+		Findings: output.Findings,
+		Usage:    output.TotalUsage(),
+	}
+	if err != nil {
+		ev.Error = err.Error()
+		var pe *orchestrator.PipelineError
+		if errors.As(err, &pe) {
+			ev.FailedPhase = pe.Phase
+		}
+	}
+	e.emit(ev)
+}
+
+// jsonDisplay stands in for the run display in JSON mode. Phase updates go
+// straight from the orchestrator to the emitter, and findings are part of
+// the result, so it only waits for the pipeline to finish.
+type jsonDisplay struct{}
+
+func (jsonDisplay) Run(ctx context.Context, events <-chan tui.DisplayEvent) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			switch ev.(type) {
+			case tui.PipelineDoneMsg, tui.PipelineErrorMsg:
+				return nil
+			}
+		}
+	}
+}
+
+// taskEvent is a campaign lifecycle event. ParentID is the campaign level
+// the event belongs to; BeadID is the task, or the parent for campaign and
+// validation events.
+type taskEvent struct {
+	TS       time.Time               `json:"ts"`
+	Event    string                  `json:"event"`
+	ParentID string                  `json:"parent_id"`
+	BeadID   string                  `json:"bead_id,omitempty"`
+	Status   string                  `json:"status,omitempty"`
+	Tasks    int                     `json:"tasks,omitempty"`
+	Reason   string                  `json:"reason,omitempty"`
+	Error    string                  `json:"error,omitempty"`
+	Phases   []phaseResultJSON       `json:"phases,omitempty"`
+	Finding  *provider.Finding       `json:"finding,omitempty"`
+	Failures *campaign.FailureCounts `json:"failures,omitempty"`
+	Failed   []string                `json:"failed_tasks,omitempty"`
+
+	FailureMode    string       `json:"failure_mode,omitempty"`    // Top-level campaign_start only.
+	CircuitBreaker *breakerJSON `json:"circuit_breaker,omitempty"` // Top-level campaign_start only.
+}
+
+// campaignJSONCallback implements campaign.Callback by emitting taskEvents.
+// It tracks the parent of each nested campaign level.
+type campaignJSONCallback struct {
+	e       *jsonEmitter
+	parents []string
+
+	failureMode string                  // Reported by the top-level campaign_start.
+	breaker     campaign.CircuitBreaker // Reported with failureMode.
+}
+
+func (c *campaignJSONCallback) emit(ev taskEvent) {
+	ev.TS = time.Now().UTC()
+	if len(c.parents) > 0 {
+		ev.ParentID = c.parents[len(c.parents)-1]
+	}
+	c.e.emit(ev)
+}
+
+func (c *campaignJSONCallback) OnCampaignStart(parentID string, tasks []campaign.BeadInfo) {
+	ev := taskEvent{Event: "campaign_start", BeadID: parentID, Tasks: len(tasks)}
+	if len(c.parents) == 0 && c.failureMode != "" {
+		ev.FailureMode = c.failureMode
+		ev.CircuitBreaker = &breakerJSON{Mode: string(c.breaker.Mode), Setup: c.breaker.Setup, Signal: c.breaker.Signal}
+	}
+	c.parents = append(c.parents, parentID)
+	c.emit(ev)
+}
+
+func (c *campaignJSONCallback) OnTaskStart(beadID string) {
+	c.emit(taskEvent{Event: "task_start", BeadID: beadID, Status: string(campaign.TaskRunning)})
+}
+
+func (c *campaignJSONCallback) OnTaskComplete(result campaign.TaskResult) {
+	c.emit(taskEvent{Event: "task_complete", BeadID: result.BeadID, Status: string(result.Status),
+		Phases: phaseResultsJSON(result.PhaseResults)})
+}
+
+func (c *campaignJSONCallback) OnTaskFailed(result campaign.TaskResult, err error) {
+	c.emit(taskEvent{Event: "task_fail", BeadID: result.BeadID, Status: string(campaign.TaskFailed), Error: err.Error(),
+		Phases: phaseResultsJSON(result.PhaseResults)})
+}
+
+// OnTaskFail reports a failure without its phases. The runner calls
+// OnTaskFailed instead.
+func (c *campaignJSONCallback) OnTaskFail(beadID string, err error) {
+	c.OnTaskFailed(campaign.TaskResult{BeadID: beadID}, err)
+}
+
+func (c *campaignJSONCallback) OnTaskSkipped(beadID, reason string) {
+	c.emit(taskEvent{Event: "task_skip", BeadID: beadID, Status: string(campaign.TaskSkipped), Reason: reason})
+}
+
+func (c *campaignJSONCallback) OnCampaignPaused(beadID, reason, details string) {
+	c.emit(taskEvent{Event: "campaign_paused", BeadID: beadID, Reason: reason, Error: details})
+}
+
+func (c *campaignJSONCallback) OnDiscoveryFiled(f provider.Finding, newBeadID string) {
+	c.emit(taskEvent{Event: "discovery_filed", BeadID: newBeadID, Finding: &f})
+}
+
+func (c *campaignJSONCallback) OnValidationStart() {
+	c.emit(taskEvent{Event: "validation_start"})
+}
+
+func (c *campaignJSONCallback) OnValidationPhase(su orchestrator.StatusUpdate) {
+	jsonStatusCallback(c.e)(su)
+}
+
+func (c *campaignJSONCallback) OnValidationComplete(result campaign.TaskResult) {
+	c.emit(taskEvent{Event: "validation_complete", BeadID: result.BeadID, Status: string(result.Status),
+		Error: result.Error, Phases: phaseResultsJSON(result.PhaseResults)})
+}
+
+func (c *campaignJSONCallback) OnCircuitBroken(trip campaign.BreakerTrip) {
+	c.emit(taskEvent{Event: "circuit_breaker", BeadID: trip.BeadID, Reason: trip.Reason, Failures: &trip.Counts, Failed: trip.FailedIDs()})
+}
+
+func (c *campaignJSONCallback) OnParentClosed(parentID string) {
+	c.emit(taskEvent{Event: "parent_closed", BeadID: parentID})
+}
+
+func (c *campaignJSONCallback) OnCampaignComplete(s campaign.State) {
+	c.emit(taskEvent{Event: "campaign_complete", BeadID: s.ParentBeadID, Status: string(s.Status), Tasks: len(s.Tasks), Reason: s.ParentOpen})
+	c.parents = c.parents[:len(c.parents)-1]
+}
+
+// campaignTaskJSON is one top-level task in a campaign result.
+type campaignTaskJSON struct {
+	BeadID string `json:"bead_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Reason string `json:"reason,omitempty"` // Why a skipped task did not run.
+}
+
+// campaignResultEvent is the last line capsule campaign prints in JSON mode.
+type campaignResultEvent struct {
+	TS         time.Time          `json:"ts"`
+	Event      string             `json:"event"` // Always "result".
+	BeadID     string             `json:"bead_id"`
+	Success    bool               `json:"success"`
+	ExitCode   int                `json:"exit_code"`
+	Error      string             `json:"error,omitempty"`
+	DurationMS int64              `json:"duration_ms"`
+	Passed     int                `json:"passed"`
+	Failed     int                `json:"failed"`
+	Skipped    int                `json:"skipped"`
+	Tasks      []campaignTaskJSON `json:"tasks"`
+	Usage      provider.Usage     `json:"usage,omitzero"` // Summed over every pipeline, sub-campaigns included.
+}
+
+// emitCampaignResult emits the terminating result of a campaign. done is
+// zero when the campaign never ran; err is what the command returns.
+func (e *jsonEmitter) emitCampaignResult(parentID string, done campaign.Completion, err error) {
+	ev := campaignResultEvent{
+		TS:         time.Now().UTC(),
+		Event:      "result",
+		BeadID:     parentID,
+		Success:    err == nil && done.Failed == 0,
+		ExitCode:   exitCode(err),
+		DurationMS: done.Duration.Milliseconds(),
+		Passed:     done.Passed,
+		Failed:     done.Failed,
+		Skipped:    done.Skipped,
+		Tasks:      make([]campaignTaskJSON, len(done.Tasks)),
+		Usage:      done.Usage,
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	for i, t := range done.Tasks {
+		ev.Tasks[i] = campaignTaskJSON{BeadID: t.BeadID, Status: string(t.Status), Error: t.Error, Reason: t.SkipReason}
+	}
+	e.emit(ev)
+}
+
+// planTaskJSON is one task in a campaign plan.
+type planTaskJSON struct {
+	BeadID    string   `json:"bead_id"`
+	Title     string   `json:"title"`
+	Priority  int      `json:"priority"`
+	Type      string   `json:"type"`
+	Phases    int      `json:"phases"` // Pipeline phases; 0 for a sub-campaign.
+	BlockedBy []string `json:"blocked_by,omitempty"`
+}
+
+// breakerJSON holds the failure thresholds; 0 disables one.
+type breakerJSON struct {
+	Mode   string `json:"mode"`
+	Setup  int    `json:"setup"`
+	Signal int    `json:"signal"`
+}
+
+// campaignPlanEvent is the only line capsule campaign --plan prints in JSON
+// mode when there is something to run.
+type campaignPlanEvent struct {
+	TS               time.Time      `json:"ts"`
+	Event            string         `json:"event"` // Always "plan".
+	BeadID           string         `json:"bead_id"`
+	Ordering         string         `json:"ordering"`
+	FailureMode      string         `json:"failure_mode"`
+	CircuitBreaker   breakerJSON    `json:"circuit_breaker"`
+	Concurrency      int            `json:"concurrency"`
+	ValidationPhases string         `json:"validation_phases,omitempty"`
+	Tasks            []planTaskJSON `json:"tasks"`
+}
+
+// emitCampaignPlan emits the tasks a campaign would run, in order, with the
+// settings that govern the run.
+func (e *jsonEmitter) emitCampaignPlan(parentID string, tasks []campaign.BeadInfo, phaseCount int, cfg config.Campaign, breaker campaign.CircuitBreaker) {
+	ev := campaignPlanEvent{
+		TS:               time.Now().UTC(),
+		Event:            "plan",
+		BeadID:           parentID,
+		Ordering:         cfg.Ordering,
+		FailureMode:      cfg.FailureMode,
+		CircuitBreaker:   breakerJSON{Mode: string(breaker.Mode), Setup: breaker.Setup, Signal: breaker.Signal},
+		Concurrency:      max(cfg.Concurrency, 1),
+		ValidationPhases: cfg.ValidationPhases,
+		Tasks:            make([]planTaskJSON, len(tasks)),
+	}
+	for i, t := range tasks {
+		phases := phaseCount
+		if isCampaignParent(t.Type) {
+			phases = 0
+		}
+		ev.Tasks[i] = planTaskJSON{BeadID: t.ID, Title: t.Title, Priority: t.Priority, Type: t.Type, Phases: phases, BlockedBy: t.BlockedBy}
+	}
+	e.emit(ev)
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/smileynet/capsule/internal/campaign"
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/tui"
+)
+
+// decodeLines decodes each line of buf as a JSON object.
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var events []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var ev map[string]any
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("line %q is not JSON: %v", line, err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestJSONStatusCallback(t *testing.T) {
+	// Given a JSON status callback
+	var buf bytes.Buffer
+	cb := jsonStatusCallback(newJSONEmitter(&buf))
+
+	// When a phase starts, waits out a rate limit, fails with feedback, and a warning is reported
+	cb(orchestrator.StatusUpdate{BeadID: "cap-7", Phase: "execute", Status: orchestrator.PhaseRunning, Attempt: 1})
+	cb(orchestrator.StatusUpdate{BeadID: "cap-7", Phase: "execute", Status: orchestrator.PhaseRetrying, Attempt: 1, Message: "retrying in 2s (rate limited)"})
+	cb(orchestrator.StatusUpdate{
+		BeadID:   "cap-7",
+		Phase:    "execute",
+		Status:   orchestrator.PhaseFailed,
+		Attempt:  2,
+		Duration: 1500 * time.Millisecond,
+		Signal: &provider.Signal{
+			Status:       provider.StatusNeedsWork,
+			Summary:      "Half done",
+			Feedback:     "Add tests",
+			FilesChanged: []string{"a.go"},
+		},
+	})
+	cb(orchestrator.StatusUpdate{BeadID: "cap-7", Warning: "overlapping files"})
+
+	// Then each update is one JSON object with the phase fields
+	events := decodeLines(t, &buf)
+	if len(events) != 4 {
+		t.Fatalf("events = %d, want 4", len(events))
+	}
+	running, retrying, failed, warning := events[0], events[1], events[2], events[3]
+	if running["event"] != "phase" || running["status"] != "running" || running["summary"] != "" {
+		t.Errorf("running event = %v", running)
+	}
+	if files, ok := running["files_changed"].([]any); !ok || len(files) != 0 {
+		t.Errorf("running files_changed = %v, want []", running["files_changed"])
+	}
+	want := map[string]any{
+		"bead_id": "cap-7", "phase": "execute", "status": "failed", "attempt": 2.0,
+		"duration_ms": 1500.0, "summary": "Half done", "feedback": "Add tests",
+	}
+	for k, v := range want {
+		if failed[k] != v {
+			t.Errorf("failed[%q] = %v, want %v", k, failed[k], v)
+		}
+	}
+	if fmt.Sprint(failed["files_changed"]) != "[a.go]" {
+		t.Errorf("files_changed = %v, want [a.go]", failed["files_changed"])
+	}
+	if retrying["event"] != "retrying" || retrying["status"] != "retrying" || retrying["message"] != "retrying in 2s (rate limited)" {
+		t.Errorf("retrying event = %v", retrying)
+	}
+	if warning["event"] != "warning" || warning["warning"] != "overlapping files" {
+		t.Errorf("warning event = %v", warning)
+	}
+	for _, ev := range events {
+		if _, err := time.Parse(time.RFC3339Nano, fmt.Sprint(ev["ts"])); err != nil {
+			t.Errorf("ts = %v, want RFC 3339: %v", ev["ts"], err)
+		}
+	}
+}
+
+func TestJSONStatusCallback_SkipReason(t *testing.T) {
+	// Given a JSON status callback
+	var buf bytes.Buffer
+	cb := jsonStatusCallback(newJSONEmitter(&buf))
+
+	// When a phase is skipped with a reason and another finishes without one
+	cb(orchestrator.StatusUpdate{BeadID: "cap-7", Phase: "frontend", Status: orchestrator.PhaseSkipped, SkipReason: "no frontend files"})
+	cb(orchestrator.StatusUpdate{BeadID: "cap-7", Phase: "execute", Status: orchestrator.PhasePassed})
+
+	// Then only the skipped phase's event carries skip_reason
+	events := decodeLines(t, &buf)
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2", len(events))
+	}
+	if events[0]["skip_reason"] != "no frontend files" {
+		t.Errorf("skipped event = %v, want skip_reason", events[0])
+	}
+	if _, ok := events[1]["skip_reason"]; ok {
+		t.Errorf("passed event = %v, want no skip_reason", events[1])
+	}
+}
+
+func TestCampaignJSONCallback(t *testing.T) {
+	// Given a campaign JSON callback
+	var buf bytes.Buffer
+	cb := &campaignJSONCallback{e: newJSONEmitter(&buf), failureMode: "skip-dependents", breaker: campaign.CircuitBreaker{Setup: 3}}
+
+	// When a campaign runs a task, a sub-campaign, and a failing task
+	cb.OnCampaignStart("cap-epic", []campaign.BeadInfo{{ID: "cap-1"}, {ID: "cap-feat"}, {ID: "cap-2"}})
+	cb.OnTaskStart("cap-1")
+	cb.OnTaskComplete(campaign.TaskResult{BeadID: "cap-1", Status: campaign.TaskCompleted, PhaseResults: []orchestrator.PhaseResult{
+		{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusPass, Summary: "done"}, Attempt: 1},
+	}})
+	cb.OnCampaignStart("cap-feat", []campaign.BeadInfo{{ID: "cap-3"}})
+	cb.OnTaskSkipped("cap-3", "dependency failed")
+	cb.OnCampaignComplete(campaign.State{ParentBeadID: "cap-feat", Status: campaign.CampaignCompleted})
+	cb.OnTaskFailed(campaign.TaskResult{BeadID: "cap-2", Status: campaign.TaskFailed}, errors.New("boom"))
+	cb.OnCircuitBroken(campaign.BreakerTrip{Reason: "3 consecutive failures", BeadID: "cap-2", Counts: campaign.FailureCounts{Setup: 3}})
+
+	// Then each event names its kind, its bead, and the campaign level it belongs to
+	events := decodeLines(t, &buf)
+	want := []struct{ event, parent, bead string }{
+		{"campaign_start", "cap-epic", "cap-epic"},
+		{"task_start", "cap-epic", "cap-1"},
+		{"task_complete", "cap-epic", "cap-1"},
+		{"campaign_start", "cap-feat", "cap-feat"},
+		{"task_skip", "cap-feat", "cap-3"},
+		{"campaign_complete", "cap-feat", "cap-feat"},
+		{"task_fail", "cap-epic", "cap-2"},
+		{"circuit_breaker", "cap-epic", "cap-2"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %d, want %d", len(events), len(want))
+	}
+	for i, w := range want {
+		ev := events[i]
+		bead, _ := ev["bead_id"].(string)
+		if ev["event"] != w.event || ev["parent_id"] != w.parent || bead != w.bead {
+			t.Errorf("event %d = %v, want %s parent=%s bead=%s", i, ev, w.event, w.parent, w.bead)
+		}
+	}
+	// And only the top-level start reports the failure mode and circuit breaker
+	if events[0]["failure_mode"] != "skip-dependents" || events[0]["circuit_breaker"] == nil {
+		t.Errorf("campaign_start = %v, want failure mode and circuit breaker", events[0])
+	}
+	if _, ok := events[3]["failure_mode"]; ok {
+		t.Errorf("sub-campaign start = %v, want no failure mode", events[3])
+	}
+	if phases, ok := events[2]["phases"].([]any); !ok || len(phases) != 1 {
+		t.Errorf("task_complete phases = %v, want one", events[2]["phases"])
+	}
+	if events[6]["error"] != "boom" {
+		t.Errorf("task_fail error = %v, want boom", events[6]["error"])
+	}
+	if failures, _ := events[7]["failures"].(map[string]any); failures["setup"] != 3.0 {
+		t.Errorf("circuit_breaker failures = %v, want setup 3", events[7]["failures"])
+	}
+}
+
+func TestEmitRunResult(t *testing.T) {
+	output := orchestrator.PipelineOutput{PhaseResults: []orchestrator.PhaseResult{
+		{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusPass, Summary: "done"}, Attempt: 1, Duration: time.Second,
+			Usage: provider.Usage{InputTokens: 100, OutputTokens: 10, CostUSD: 0.5}},
+		{PhaseName: "sign-off", Signal: provider.Signal{Status: provider.StatusNeedsWork, Feedback: "missing docs"}, Attempt: 3,
+			Usage: provider.Usage{InputTokens: 20, OutputTokens: 2, CostUSD: 0.25}},
+	}}
+	tests := []struct {
+		name       string
+		err        error
+		wantExit   float64
+		wantPhase  string
+		wantResult bool
+	}{
+		{name: "success", wantExit: exitSuccess, wantResult: true},
+		{
+			name:      "pipeline failure names the phase",
+			err:       &orchestrator.PipelineError{Phase: "sign-off", Attempt: 3},
+			wantExit:  exitPipeline,
+			wantPhase: "sign-off",
+		},
+		{name: "setup error", err: errors.New("run: no config"), wantExit: exitSetup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a JSON emitter
+			var buf bytes.Buffer
+
+			// When the run result is emitted
+			newJSONEmitter(&buf).emitRunResult("cap-7", output, tt.err)
+
+			// Then it is one result object with the exit code and phase results
+			events := decodeLines(t, &buf)
+			if len(events) != 1 {
+				t.Fatalf("events = %d, want 1", len(events))
+			}
+			ev := events[0]
+			if ev["event"] != "result" || ev["bead_id"] != "cap-7" || ev["success"] != tt.wantResult || ev["exit_code"] != tt.wantExit {
+				t.Errorf("result = %v", ev)
+			}
+			if phase, _ := ev["failed_phase"].(string); phase != tt.wantPhase {
+				t.Errorf("failed_phase = %q, want %q", phase, tt.wantPhase)
+			}
+			if _, hasErr := ev["error"]; hasErr != (tt.err != nil) {
+				t.Errorf("error = %v, want present only on failure", ev["error"])
+			}
+			phases, _ := ev["phases"].([]any)
+			if len(phases) != 2 {
+				t.Fatalf("phases = %v, want 2", ev["phases"])
+			}
+			if last := phases[1].(map[string]any); last["status"] != "NEEDS_WORK" || last["feedback"] != "missing docs" {
+				t.Errorf("phases[1] = %v", last)
+			}
+			// And usage is reported per phase and in total
+			if u, _ := phases[0].(map[string]any)["usage"].(map[string]any); u["input_tokens"] != 100.0 {
+				t.Errorf("phases[0].usage = %v", u)
+			}
+			if u, _ := ev["usage"].(map[string]any); u["input_tokens"] != 120.0 || u["output_tokens"] != 12.0 || u["cost_usd"] != 0.75 {
+				t.Errorf("usage = %v, want the sum of the phases", u)
+			}
+		})
+	}
+}
+
+func TestEmitCampaignResult(t *testing.T) {
+	tests := []struct {
+		name        string
+		done        campaign.Completion
+		err         error
+		wantSuccess bool
+		wantExit    float64
+		wantTasks   int
+	}{
+		{
+			name: "all tasks passed",
+			done: campaign.Completion{Passed: 2, Tasks: []campaign.TaskResult{
+				{BeadID: "cap-1", Status: campaign.TaskCompleted}, {BeadID: "cap-2", Status: campaign.TaskCompleted},
+			}},
+			wantSuccess: true,
+			wantExit:    exitSuccess,
+			wantTasks:   2,
+		},
+		{
+			name: "failed task in continue mode",
+			done: campaign.Completion{Passed: 1, Failed: 1, Tasks: []campaign.TaskResult{
+				{BeadID: "cap-1", Status: campaign.TaskCompleted}, {BeadID: "cap-2", Status: campaign.TaskFailed, Error: "boom"},
+			}},
+			wantExit:  exitSuccess,
+			wantTasks: 2,
+		},
+		{name: "campaign never ran", err: campaign.ErrNoTasks, wantExit: exitPipeline},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a JSON emitter
+			var buf bytes.Buffer
+
+			// When the campaign result is emitted
+			newJSONEmitter(&buf).emitCampaignResult("cap-epic", tt.done, tt.err)
+
+			// Then it reports success, the exit code, and the top-level tasks
+			ev := decodeLines(t, &buf)[0]
+			if ev["event"] != "result" || ev["success"] != tt.wantSuccess || ev["exit_code"] != tt.wantExit {
+				t.Errorf("result = %v", ev)
+			}
+			if tasks, _ := ev["tasks"].([]any); len(tasks) != tt.wantTasks {
+				t.Errorf("tasks = %v, want %d", ev["tasks"], tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestKongParse_OutputJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "run", args: []string{"run", "cap-7", "--output", "json"}},
+		{name: "campaign", args: []string{"campaign", "cap-epic", "--output", "json"}},
+		{name: "unknown format", args: []string{"run", "cap-7", "--output", "yaml"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given the CLI parser
+			var cli CLI
+			parser, err := kong.New(&cli)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// When the command is parsed with --output
+			_, err = parser.Parse(tt.args)
+
+			// Then json is accepted and other formats are rejected
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cli.Run.Output != outputJSON && cli.Campaign.Output != outputJSON {
+				t.Errorf("Output not set: run %q, campaign %q", cli.Run.Output, cli.Campaign.Output)
+			}
+		})
+	}
+}
+
+func TestJSONDisplay_WaitsForPipelineEnd(t *testing.T) {
+	// Given a JSON display and a bridge that reports findings then an error
+	events := make(chan tui.DisplayEvent, 4)
+	events <- tui.FindingsMsg{Findings: []provider.Finding{{Title: "x"}}}
+	events <- tui.PipelineErrorMsg{Err: errors.New("boom")}
+
+	// When the display runs
+	err := jsonDisplay{}.Run(context.Background(), events)
+
+	// Then it returns without an error once the pipeline ends
+	if err != nil {
+		t.Errorf("Run() error = %v, want nil", err)
+	}
+}
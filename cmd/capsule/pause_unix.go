@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// pauseSignals request a graceful pause between phases.
+var pauseSignals = []os.Signal{syscall.SIGUSR1}
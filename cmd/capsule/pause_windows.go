@@ -0,0 +1,9 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// pauseSignals is empty on Windows, which has no SIGUSR1, so runs there
+// cannot be paused between phases.
+var pauseSignals []os.Signal
@@ -0,0 +1,109 @@
+//go:build smoke
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSmoke_ScriptedProvider runs the capsule binary end-to-end against the
+// demo-brownfield template with --provider scripted, so nothing calls an AI
+// CLI: the template's .capsule/scripted.yaml writes the files, the phases run
+// go test, and the merge phase commits. Worktree creation, the worklog, merge,
+// and bead closing are all real.
+func TestSmoke_ScriptedProvider(t *testing.T) {
+	for _, cmd := range []string{"go", "bd", "git"} {
+		if _, err := exec.LookPath(cmd); err != nil {
+			t.Skipf("skipping: %s not on PATH", cmd)
+		}
+	}
+
+	projectRoot := findProjectRoot(t)
+	binary := filepath.Join(projectRoot, "capsule")
+	if _, err := os.Stat(binary); err != nil {
+		cmd := exec.Command("go", "build", "-o", binary, "./cmd/capsule")
+		cmd.Dir = projectRoot
+		if out, buildErr := cmd.CombinedOutput(); buildErr != nil {
+			t.Fatalf("go build failed: %v\n%s", buildErr, out)
+		}
+		t.Cleanup(func() { os.Remove(binary) })
+	}
+
+	t.Run("happy path", func(t *testing.T) {
+		// Given a demo-brownfield project with its scripted responses
+		projectDir := setupTemplateProject(t, projectRoot, "demo-brownfield")
+
+		// When capsule runs the bead with the scripted provider
+		output, exit := runCapsuleBinary(t, binary, projectDir, t.TempDir(), "demo-1.1.1", "--provider", "scripted")
+		t.Log("--- capsule output ---\n" + output)
+
+		// Then every phase passes and the bead is merged and closed
+		if exit != 0 {
+			t.Fatalf("exit code = %d, want 0", exit)
+		}
+		for _, want := range []string{
+			"files: src/validate_test.go",
+			"files: src/validate.go",
+			"Merged capsule-demo-1.1.1",
+			"Closed demo-1.1.1",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q", want)
+			}
+		}
+		// And the implementation is on main with its tests passing
+		goTest := exec.Command("go", "test", "./...")
+		goTest.Dir = filepath.Join(projectDir, "src")
+		if out, err := goTest.CombinedOutput(); err != nil {
+			t.Errorf("go test on main failed: %v\n%s", err, out)
+		}
+		// And the worklog is archived
+		if _, err := os.Stat(filepath.Join(projectDir, ".capsule", "logs", "demo-1.1.1", "worklog.md")); err != nil {
+			t.Errorf("worklog not archived: %v", err)
+		}
+	})
+
+	t.Run("retry path", func(t *testing.T) {
+		// Given the script asks for changes on the first execute review
+		projectDir := setupTemplateProject(t, projectRoot, "demo-brownfield")
+		scriptPath := filepath.Join(projectDir, ".capsule", "scripted.yaml")
+		data, err := os.ReadFile(scriptPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		script := strings.Replace(string(data), "  execute-review:\n",
+			"  execute-review:\n    - status: NEEDS_WORK\n      feedback: Error messages must name the missing part.\n", 1)
+		if err := os.WriteFile(scriptPath, []byte(script), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		for _, args := range [][]string{{"add", "-A"}, {"commit", "-q", "-m", "Script a review retry"}} {
+			gitCmd := exec.Command("git", args...)
+			gitCmd.Dir = projectDir
+			if out, err := gitCmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+
+		// When capsule runs the bead
+		output, exit := runCapsuleBinary(t, binary, projectDir, t.TempDir(), "demo-1.1.1", "--provider", "scripted")
+		t.Log("--- capsule output ---\n" + output)
+
+		// Then execute is retried with the feedback and the run still succeeds
+		if exit != 0 {
+			t.Fatalf("exit code = %d, want 0", exit)
+		}
+		for _, want := range []string{
+			"feedback: Error messages must name the missing part.",
+			"(attempt 2/",
+			"Merged capsule-demo-1.1.1",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output missing %q", want)
+			}
+		}
+	})
+}
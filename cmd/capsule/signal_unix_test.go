@@ -0,0 +1,66 @@
+//go:build !windows
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/orchestrator"
+)
+
+func TestInterruptContext(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping signal test in short mode")
+	}
+
+	// Given an interrupt context tracking a running phase
+	var tracker phaseTracker
+	tracker.wrap(func(orchestrator.StatusUpdate) {})(orchestrator.StatusUpdate{
+		BeadID: "cap-1", Phase: "execute", Status: orchestrator.PhaseRunning,
+	})
+	var buf bytes.Buffer
+	force := make(chan struct{})
+	ctx, stop := interruptContext(context.Background(), &buf, force, &tracker)
+	defer stop()
+
+	// When the process receives a first Ctrl+C
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+
+	// Then the context is cancelled but force-kill is not requested
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled after first interrupt")
+	}
+	select {
+	case <-force:
+		t.Fatal("force closed after first interrupt")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// When a second Ctrl+C arrives
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+
+	// Then force-kill is requested and both interrupts name the phase
+	select {
+	case <-force:
+	case <-time.After(5 * time.Second):
+		t.Fatal("force not closed after second interrupt")
+	}
+	out := buf.String()
+	for _, want := range []string{"Interrupted cap-1 (phase execute)", "Force quitting cap-1 (phase execute)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
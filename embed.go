@@ -1,4 +1,8 @@
-// Package capsule provides embedded runtime resources (prompts, templates)
+// Package capsule runs AI coding pipelines against beads. NewPipeline and
+// NewCampaign are the stable entry points for embedding capsule in other Go
+// programs; see ExamplePipeline_Run.
+//
+// The package also provides embedded runtime resources (prompts, templates)
 // and an overlay filesystem that checks local disk first, falling back to embedded.
 package capsule
 
@@ -15,12 +19,25 @@ var rawPrompts embed.FS
 //go:embed templates/worklog.md.template
 var rawTemplates embed.FS
 
+// ConfigTemplate is the commented project config that `capsule init` writes
+// to .capsule/config.yaml.
+//
+//go:embed templates/config.yaml.template
+var ConfigTemplate []byte
+
+//go:embed templates/demo-brownfield
+var rawDemo embed.FS
+
 // Prompts is the embedded prompts filesystem with the "prompts/" prefix stripped.
 var Prompts = mustSub(rawPrompts, "prompts")
 
 // Templates is the embedded templates filesystem with the "templates/" prefix stripped.
 var Templates = mustSub(rawTemplates, "templates")
 
+// DemoProject is the embedded demo-brownfield template that `capsule init
+// --demo` copies, with the "templates/demo-brownfield/" prefix stripped.
+var DemoProject = mustSub(rawDemo, "templates/demo-brownfield")
+
 func mustSub(fsys fs.FS, dir string) fs.FS {
 	sub, err := fs.Sub(fsys, dir)
 	if err != nil {
@@ -0,0 +1,71 @@
+package capsule_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/smileynet/capsule"
+)
+
+// passProvider answers every prompt with a PASS signal.
+type passProvider struct{}
+
+func (passProvider) Name() string { return "example" }
+
+func (passProvider) Execute(_ context.Context, _, _ string) (capsule.Result, error) {
+	return capsule.Result{Output: `{"status":"PASS","feedback":"looks good","files_changed":[],"summary":"done"}`}, nil
+}
+
+func ExamplePipeline_Run() {
+	p := capsule.NewPipeline(passProvider{},
+		capsule.WithPromptLoader(capsule.NewPromptLoader(capsule.Prompts)),
+		capsule.WithPhases([]capsule.PhaseDefinition{
+			{Name: "execute", Kind: capsule.Worker},
+			{Name: "sign-off", Kind: capsule.Reviewer, MaxRetries: 2, RetryTarget: "execute"},
+		}),
+		capsule.WithStatusCallback(func(su capsule.StatusUpdate) {
+			if su.Status == capsule.PhasePassed {
+				fmt.Println("passed:", su.Phase)
+			}
+		}),
+	)
+
+	out, err := p.Run(context.Background(), capsule.PipelineInput{BeadID: "demo-1", Title: "Add a greeting"})
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println("completed:", out.Completed)
+	// Output:
+	// passed: execute
+	// passed: sign-off
+	// completed: true
+}
+
+// needsWorkProvider always asks for another attempt.
+type needsWorkProvider struct{}
+
+func (needsWorkProvider) Name() string { return "example" }
+
+func (needsWorkProvider) Execute(_ context.Context, _, _ string) (capsule.Result, error) {
+	return capsule.Result{Output: `{"status":"NEEDS_WORK","feedback":"add tests","files_changed":[],"summary":"incomplete"}`}, nil
+}
+
+func ExamplePipeline_Run_failure() {
+	p := capsule.NewPipeline(needsWorkProvider{},
+		capsule.WithPromptLoader(capsule.NewPromptLoader(capsule.Prompts)),
+		capsule.WithPhases([]capsule.PhaseDefinition{
+			{Name: "execute", Kind: capsule.Worker},
+			{Name: "sign-off", Kind: capsule.Reviewer, MaxRetries: 2, RetryTarget: "execute"},
+		}),
+	)
+
+	_, err := p.Run(context.Background(), capsule.PipelineInput{BeadID: "demo-2"})
+	var pe *capsule.PipelineError
+	if errors.As(err, &pe) {
+		fmt.Printf("%s failed on attempt %d: %s\n", pe.Phase, pe.Attempt, pe.Signal.Feedback)
+	}
+	// Output:
+	// execute failed on attempt 1: add tests
+}
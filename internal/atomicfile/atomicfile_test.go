@@ -0,0 +1,53 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile_ReplacesContents(t *testing.T) {
+	// Given a file with existing contents
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// When it is written atomically
+	if err := WriteFile(path, []byte("new")); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// Then the new contents are in place and no temporary file is left behind
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "new" {
+		t.Errorf("contents = %q, want %q", got, "new")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dir has %d entries, want only state.json", len(entries))
+	}
+}
+
+func TestWriteFile_MissingDir(t *testing.T) {
+	// Given a path whose directory does not exist
+	path := filepath.Join(t.TempDir(), "missing", "state.json")
+
+	// When it is written atomically
+	err := WriteFile(path, []byte("data"))
+
+	// Then the write fails without creating the file
+	if err == nil {
+		t.Fatal("WriteFile() error = nil, want error")
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Errorf("Stat() error = %v, want not exist", statErr)
+	}
+}
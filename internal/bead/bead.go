@@ -7,14 +7,29 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
+	"strconv"
 
 	"github.com/smileynet/capsule/internal/worklog"
 )
 
 // Sentinel errors for caller-checkable conditions.
 var (
-	ErrCLINotFound = errors.New("bead: bd CLI not found on PATH")
-	ErrNotFound    = errors.New("bead: issue not found")
+	ErrBDNotInstalled       = errors.New("bead: bd CLI not found on PATH")
+	ErrNoBeadRepo           = errors.New("bead: no beads database found")
+	ErrBDVersionUnsupported = errors.New("bead: bd version unsupported")
+	ErrNotFound             = errors.New("bead: issue not found")
+	ErrCreate               = errors.New("bead: bd create failed")
+)
+
+// MinBDVersion is the oldest bd release Check accepts.
+const MinBDVersion = "0.20.0"
+
+var (
+	// noDatabase matches bd's output when the directory has no beads database.
+	noDatabase = regexp.MustCompile(`(?i)no (?:beads )?database found`)
+	// versionNumber matches the release number in bd --version output.
+	versionNumber = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
 )
 
 // issue is the JSON structure returned by bd show --json.
@@ -27,22 +42,30 @@ type issue struct {
 	Priority     int          `json:"priority"`
 	IssueType    string       `json:"issue_type"`
 	Parent       string       `json:"parent"`
+	Labels       []string     `json:"labels"`
 	Dependencies []dependency `json:"dependencies"`
 }
 
-// dependency is a single dependency entry in the bd JSON output.
+// dependency is a single dependency entry in the bd JSON output. bd list
+// reports edges (issue_id, depends_on_id, type); bd show reports the
+// depended-on issue itself (id, dependency_type).
 type dependency struct {
 	IssueID     string `json:"issue_id"`
 	DependsOnID string `json:"depends_on_id"`
 	Type        string `json:"type"`
+
+	ID             string `json:"id"`
+	DependencyType string `json:"dependency_type"`
 }
 
 // Summary is a minimal view of a bead for listing.
 type Summary struct {
-	ID       string
-	Title    string
-	Priority int
-	Type     string
+	ID        string
+	Title     string
+	Priority  int
+	Type      string
+	Status    string   // bd status: "open", "in_progress", "blocked", or "closed".
+	DependsOn []string // Beads this one is blocked by ("blocks" dependencies).
 }
 
 // Client calls the bd CLI to resolve bead context.
@@ -76,6 +99,10 @@ func (c *Client) Resolve(id string) (worklog.BeadContext, error) {
 		TaskTitle:          task.Title,
 		TaskDescription:    task.Description,
 		AcceptanceCriteria: task.Acceptance,
+		TaskPriority:       task.Priority,
+		TaskType:           task.IssueType,
+		TaskStatus:         task.Status,
+		Labels:             task.Labels,
 	}
 
 	// Walk parent chain: task → feature → epic.
@@ -123,11 +150,69 @@ func (c *Client) Close(id string) error {
 	cmd := exec.Command("bd", "close", id)
 	cmd.Dir = c.Dir
 	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("bead: closing %s: %w\n%s", id, err, bytes.TrimSpace(out))
+		return fmt.Errorf("bead: closing %s: %w\n%s", id, classify(err, out), bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// Comment adds a comment to a bead via bd comments add.
+func (c *Client) Comment(id, text string) error {
+	if err := c.checkBD(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("bd", "comments", "add", id, text)
+	cmd.Dir = c.Dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("bead: commenting on %s: %w\n%s", id, classify(err, out), bytes.TrimSpace(out))
 	}
 	return nil
 }
 
+// CreateInput holds the fields for a new bead.
+type CreateInput struct {
+	Title       string
+	Description string
+	Type        string // bd issue type, e.g. "task" or "bug".
+	Priority    int    // 0 (highest) to 4.
+	ParentID    string // Optional parent bead.
+}
+
+// Create files a new bead via bd create and returns its ID. It returns
+// ErrBDNotInstalled when bd is not on PATH and wraps ErrCreate, with bd's
+// stderr, when bd exits non-zero; that error also wraps ErrNoBeadRepo when
+// bd found no database.
+func (c *Client) Create(in CreateInput) (string, error) {
+	if err := c.checkBD(); err != nil {
+		return "", err
+	}
+
+	args := []string{"create", in.Title, "--type", in.Type, "--priority", strconv.Itoa(in.Priority), "--json"}
+	if in.Description != "" {
+		args = append(args, "--description", in.Description)
+	}
+	if in.ParentID != "" {
+		args = append(args, "--parent", in.ParentID)
+	}
+	cmd := exec.Command("bd", args...)
+	cmd.Dir = c.Dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %w\n%s", ErrCreate, in.Title, classify(err, stderr.Bytes()), bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var created issue
+	if err := json.Unmarshal(out, &created); err != nil {
+		return "", fmt.Errorf("bead: parsing create output: %w", err)
+	}
+	if created.ID == "" {
+		return "", fmt.Errorf("bead: create output has no id: %s", bytes.TrimSpace(out))
+	}
+	return created.ID, nil
+}
+
 // Closed returns up to limit closed beads, most recently closed first.
 func (c *Client) Closed(limit int) ([]Summary, error) {
 	if err := c.checkBD(); err != nil {
@@ -139,7 +224,7 @@ func (c *Client) Closed(limit int) ([]Summary, error) {
 	cmd.Dir = c.Dir
 	out, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("bead: bd list --status=closed: %w", err)
+		return nil, fmt.Errorf("bead: bd list --status=closed: %w", classify(err, nil))
 	}
 
 	var issues []issue
@@ -150,6 +235,31 @@ func (c *Client) Closed(limit int) ([]Summary, error) {
 	return toSummaries(issues), nil
 }
 
+// List returns the beads in each of states, such as "in_progress" and
+// "blocked", in the order the states are given.
+func (c *Client) List(states []string) ([]Summary, error) {
+	if err := c.checkBD(); err != nil {
+		return nil, err
+	}
+
+	var summaries []Summary
+	for _, state := range states {
+		cmd := exec.Command("bd", "list", "--status="+state, "--json")
+		cmd.Dir = c.Dir
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("bead: bd list --status=%s: %w", state, classify(err, nil))
+		}
+
+		var issues []issue
+		if err := json.NewDecoder(bytes.NewReader(out)).Decode(&issues); err != nil {
+			return nil, fmt.Errorf("bead: parsing %s output: %w", state, err)
+		}
+		summaries = append(summaries, toSummaries(issues)...)
+	}
+	return summaries, nil
+}
+
 // ListChildren returns open children of the given parent bead, regardless of
 // blocker status. This is used by campaigns where children blocked by their
 // parent are inherently "ready" within the campaign context. Closed children
@@ -163,7 +273,7 @@ func (c *Client) ListChildren(parentID string) ([]Summary, error) {
 	cmd.Dir = c.Dir
 	out, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("bead: bd list --parent %s: %w", parentID, err)
+		return nil, fmt.Errorf("bead: bd list --parent %s: %w", parentID, classify(err, nil))
 	}
 
 	var issues []issue
@@ -184,7 +294,7 @@ func (c *Client) Ready() ([]Summary, error) {
 	cmd.Dir = c.Dir
 	out, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("bead: bd ready: %w", err)
+		return nil, fmt.Errorf("bead: bd ready: %w", classify(err, nil))
 	}
 
 	var issues []issue
@@ -200,21 +310,42 @@ func toSummaries(issues []issue) []Summary {
 	summaries := make([]Summary, len(issues))
 	for i, iss := range issues {
 		summaries[i] = Summary{
-			ID:       iss.ID,
-			Title:    iss.Title,
-			Priority: iss.Priority,
-			Type:     iss.IssueType,
+			ID:        iss.ID,
+			Title:     iss.Title,
+			Priority:  iss.Priority,
+			Type:      iss.IssueType,
+			Status:    iss.Status,
+			DependsOn: blockers(iss),
 		}
 	}
 	return summaries
 }
 
-// show fetches a single issue by ID.
+// blockers returns the IDs of the beads that block iss. Parent-child and
+// informational links such as "related" do not block.
+func blockers(iss issue) []string {
+	var ids []string
+	for _, dep := range iss.Dependencies {
+		switch {
+		case dep.Type == "blocks" && dep.DependsOnID != iss.ID && (dep.IssueID == "" || dep.IssueID == iss.ID):
+			ids = append(ids, dep.DependsOnID)
+		case dep.DependencyType == "blocks" && dep.ID != "" && dep.ID != iss.ID:
+			ids = append(ids, dep.ID)
+		}
+	}
+	return ids
+}
+
+// show fetches a single issue by ID. Any bd failure other than a missing
+// database is reported as ErrNotFound.
 func (c *Client) show(id string) (issue, error) {
 	cmd := exec.Command("bd", "show", id, "--json")
 	cmd.Dir = c.Dir
 	out, err := cmd.Output()
 	if err != nil {
+		if err := classify(err, nil); errors.Is(err, ErrNoBeadRepo) {
+			return issue{}, err
+		}
 		return issue{}, fmt.Errorf("%w: %s", ErrNotFound, id)
 	}
 
@@ -243,10 +374,70 @@ func (c *Client) extractParentID(iss issue) string {
 	return ""
 }
 
+// Check verifies that bd is ready for this client: installed, at least
+// MinBDVersion, and run where it finds a beads database. It returns an
+// error wrapping ErrBDNotInstalled, ErrBDVersionUnsupported, or
+// ErrNoBeadRepo for those failures. A version bd does not report is
+// accepted.
+func (c *Client) Check() error {
+	if err := c.checkBD(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("bd", "--version")
+	cmd.Dir = c.Dir
+	if out, err := cmd.Output(); err == nil {
+		if v := versionNumber.FindString(string(out)); v != "" && compareVersions(v, MinBDVersion) < 0 {
+			return fmt.Errorf("%w: %s is older than %s", ErrBDVersionUnsupported, v, MinBDVersion)
+		}
+	}
+
+	cmd = exec.Command("bd", "list", "--json", "-n", "1")
+	cmd.Dir = c.Dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if err := classify(err, out); errors.Is(err, ErrNoBeadRepo) {
+			return err
+		}
+		return fmt.Errorf("bead: bd list: %w\n%s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// classify wraps err with ErrNoBeadRepo when bd's output says it found no
+// database, and returns err unchanged otherwise. out is bd's combined
+// output; an *exec.ExitError's captured stderr is checked too.
+func classify(err error, out []byte) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		out = append(out[:len(out):len(out)], exitErr.Stderr...)
+	}
+	if noDatabase.Match(out) {
+		return fmt.Errorf("%w (%w)", ErrNoBeadRepo, err)
+	}
+	return err
+}
+
+// compareVersions compares two dotted release numbers matched by
+// versionNumber, returning -1, 0, or 1.
+func compareVersions(a, b string) int {
+	as, bs := versionNumber.FindStringSubmatch(a), versionNumber.FindStringSubmatch(b)
+	for i := 1; i < len(as) && i < len(bs); i++ {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
 // checkBD verifies that bd is on PATH.
 func (c *Client) checkBD() error {
 	if _, err := exec.LookPath("bd"); err != nil {
-		return ErrCLINotFound
+		return ErrBDNotInstalled
 	}
 	return nil
 }
@@ -2,6 +2,10 @@ package bead
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/smileynet/capsule/internal/worklog"
@@ -77,6 +81,28 @@ func TestExtractParentID(t *testing.T) {
 	}
 }
 
+func TestToSummaries_DependsOn(t *testing.T) {
+	// Given an issue blocked by one bead, parented by another, and related to a third
+	issues := []issue{{
+		ID: "task-2",
+		Dependencies: []dependency{
+			{IssueID: "task-2", DependsOnID: "task-1", Type: "blocks"},
+			{IssueID: "task-2", DependsOnID: "feature-1", Type: "parent-child"},
+			{IssueID: "task-2", DependsOnID: "task-9", Type: "related"},
+			{IssueID: "task-3", DependsOnID: "task-2", Type: "blocks"},
+			{ID: "task-0", DependencyType: "blocks"},
+		},
+	}}
+
+	// When it is summarized
+	got := toSummaries(issues)
+
+	// Then only its own blockers are dependencies, in either bd shape
+	if len(got[0].DependsOn) != 2 || got[0].DependsOn[0] != "task-1" || got[0].DependsOn[1] != "task-0" {
+		t.Errorf("DependsOn = %v, want [task-1 task-0]", got[0].DependsOn)
+	}
+}
+
 func TestResolve_BDAvailable_InvalidBead(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping bd CLI test in short mode")
@@ -195,10 +221,10 @@ func TestClosed_NoBD(t *testing.T) {
 
 	_, err := c.Closed(10)
 	if err == nil {
-		t.Fatal("expected ErrCLINotFound, got nil")
+		t.Fatal("expected ErrBDNotInstalled, got nil")
 	}
-	if !errors.Is(err, ErrCLINotFound) {
-		t.Errorf("error = %v, want ErrCLINotFound", err)
+	if !errors.Is(err, ErrBDNotInstalled) {
+		t.Errorf("error = %v, want ErrBDNotInstalled", err)
 	}
 }
 
@@ -212,10 +238,10 @@ func TestListChildren_NoBD(t *testing.T) {
 
 	_, err := c.ListChildren("some-parent")
 	if err == nil {
-		t.Fatal("expected ErrCLINotFound, got nil")
+		t.Fatal("expected ErrBDNotInstalled, got nil")
 	}
-	if !errors.Is(err, ErrCLINotFound) {
-		t.Errorf("error = %v, want ErrCLINotFound", err)
+	if !errors.Is(err, ErrBDNotInstalled) {
+		t.Errorf("error = %v, want ErrBDNotInstalled", err)
 	}
 }
 
@@ -241,9 +267,314 @@ func TestListChildren_BDAvailable(t *testing.T) {
 func TestCheckBD(t *testing.T) {
 	c := &Client{}
 
-	// checkBD should return either nil or ErrCLINotFound
+	// checkBD should return either nil or ErrBDNotInstalled
 	err := c.checkBD()
-	if err != nil && !errors.Is(err, ErrCLINotFound) {
+	if err != nil && !errors.Is(err, ErrBDNotInstalled) {
 		t.Errorf("checkBD() returned unexpected error: %v", err)
 	}
 }
+
+func TestCreate_NoBD(t *testing.T) {
+	c := &Client{Dir: t.TempDir()}
+
+	// If bd is actually on PATH, skip — this test is for missing-bd fallback.
+	if err := c.checkBD(); err == nil {
+		t.Skip("bd is on PATH; cannot test missing-bd fallback")
+	}
+
+	_, err := c.Create(CreateInput{Title: "Follow up", Type: "task", Priority: 2})
+	if !errors.Is(err, ErrBDNotInstalled) {
+		t.Errorf("error = %v, want ErrBDNotInstalled", err)
+	}
+}
+
+// fakeBD puts a bd shell script with the given body first on PATH. The
+// script's arguments, one per line, are written to the returned file.
+func fakeBD(t *testing.T, body string) (argsFile string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake bd is a shell script")
+	}
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + argsFile + "\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "bd"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func TestCreate_FakeBD(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping bd CLI test in short mode")
+	}
+	tests := []struct {
+		name    string
+		body    string
+		in      CreateInput
+		wantID  string
+		wantErr error
+		errText string
+		args    []string
+	}{
+		{
+			name:   "all fields",
+			body:   `echo '{"id":"cap-42","title":"Follow up"}'`,
+			in:     CreateInput{Title: "Follow up", Description: "Details", Type: "bug", Priority: 1, ParentID: "cap-1"},
+			wantID: "cap-42",
+			args: []string{"create", "Follow up", "--type", "bug", "--priority", "1", "--json",
+				"--description", "Details", "--parent", "cap-1"},
+		},
+		{
+			name:   "no description or parent",
+			body:   `echo '{"id":"cap-43"}'`,
+			in:     CreateInput{Title: "Follow up", Type: "task", Priority: 3},
+			wantID: "cap-43",
+			args:   []string{"create", "Follow up", "--type", "task", "--priority", "3", "--json"},
+		},
+		{
+			name:    "non-zero exit",
+			body:    "echo 'no database found' >&2; exit 1",
+			in:      CreateInput{Title: "Follow up", Type: "task", Priority: 2},
+			wantErr: ErrCreate,
+			errText: "no database found",
+		},
+		{
+			name:    "output without id",
+			body:    `echo '{}'`,
+			in:      CreateInput{Title: "Follow up", Type: "task", Priority: 2},
+			errText: "no id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a fake bd on PATH
+			argsFile := fakeBD(t, tt.body)
+			c := NewClient(t.TempDir())
+
+			// When a bead is created
+			id, err := c.Create(tt.in)
+
+			// Then the new ID or the expected error is returned
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("error = %v, want containing %q", err, tt.errText)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				if errors.Is(err, ErrBDNotInstalled) {
+					t.Errorf("error = %v, must not be ErrBDNotInstalled", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if id != tt.wantID {
+				t.Errorf("id = %q, want %q", id, tt.wantID)
+			}
+
+			// And bd received the bead's fields
+			raw, err := os.ReadFile(argsFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := strings.Split(strings.TrimSuffix(string(raw), "\n"), "\n")
+			if strings.Join(got, "|") != strings.Join(tt.args, "|") {
+				t.Errorf("args = %q, want %q", got, tt.args)
+			}
+		})
+	}
+}
+
+func TestList_FakeBD(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping bd CLI test in short mode")
+	}
+	// Given a fake bd that lists one bead per status
+	fakeBD(t, `case "$2" in
+--status=in_progress) echo '[{"id":"cap-1","title":"Claimed","status":"in_progress","issue_type":"task"}]' ;;
+--status=blocked) echo '[{"id":"cap-2","title":"Waiting","status":"blocked","issue_type":"task"}]' ;;
+*) echo '[]' ;;
+esac`)
+	c := NewClient(t.TempDir())
+
+	// When in-progress and blocked beads are listed
+	got, err := c.List([]string{"in_progress", "blocked"})
+
+	// Then both come back in state order with their status
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "cap-1" || got[0].Status != "in_progress" || got[1].ID != "cap-2" || got[1].Status != "blocked" {
+		t.Errorf("List() = %+v, want cap-1 in_progress then cap-2 blocked", got)
+	}
+}
+
+func TestList_NoBD(t *testing.T) {
+	c := &Client{Dir: t.TempDir()}
+
+	// If bd is actually on PATH, skip — this test is for missing-bd fallback.
+	if err := c.checkBD(); err == nil {
+		t.Skip("bd is on PATH; cannot test missing-bd fallback")
+	}
+
+	if _, err := c.List([]string{"in_progress"}); !errors.Is(err, ErrBDNotInstalled) {
+		t.Errorf("error = %v, want ErrBDNotInstalled", err)
+	}
+}
+
+func TestResolve_FakeBD_TaskFields(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping bd CLI test in short mode")
+	}
+	// Given a fake bd whose show output carries priority, type, status, and labels
+	fakeBD(t, `echo '[{"id":"cap-7","title":"Fix login","description":"Details","acceptance_criteria":"Works","status":"in_progress","priority":1,"issue_type":"bug","labels":["auth","capsule:provider=claude"]}]'`)
+	c := NewClient(t.TempDir())
+
+	// When the bead is resolved
+	ctx, err := c.Resolve("cap-7")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// Then the task's metadata is carried in the context
+	if ctx.TaskTitle != "Fix login" || ctx.AcceptanceCriteria != "Works" {
+		t.Errorf("title/acceptance = %q/%q", ctx.TaskTitle, ctx.AcceptanceCriteria)
+	}
+	if ctx.TaskPriority != 1 {
+		t.Errorf("TaskPriority = %d, want 1", ctx.TaskPriority)
+	}
+	if ctx.TaskType != "bug" {
+		t.Errorf("TaskType = %q, want %q", ctx.TaskType, "bug")
+	}
+	if ctx.TaskStatus != "in_progress" {
+		t.Errorf("TaskStatus = %q, want %q", ctx.TaskStatus, "in_progress")
+	}
+	if strings.Join(ctx.Labels, ",") != "auth,capsule:provider=claude" {
+		t.Errorf("Labels = %q", ctx.Labels)
+	}
+}
+
+func TestCheck_FakeBD(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping bd CLI test in short mode")
+	}
+	tests := []struct {
+		name    string
+		body    string
+		wantErr error // nil with ok false means an unclassified error.
+		ok      bool
+	}{
+		{
+			name: "ready",
+			body: `case "$1" in --version) echo 'bd version 0.30.2 (abc123)' ;; *) echo '[]' ;; esac`,
+			ok:   true,
+		},
+		{
+			name: "version not reported",
+			body: `case "$1" in --version) exit 1 ;; *) echo '[]' ;; esac`,
+			ok:   true,
+		},
+		{
+			name:    "version too old",
+			body:    `case "$1" in --version) echo 'bd version 0.9.4' ;; *) echo '[]' ;; esac`,
+			wantErr: ErrBDVersionUnsupported,
+		},
+		{
+			name:    "no database",
+			body:    `case "$1" in --version) echo 'bd version 0.30.2' ;; *) echo 'Error: no beads database found' >&2; exit 1 ;; esac`,
+			wantErr: ErrNoBeadRepo,
+		},
+		{
+			name: "other failure",
+			body: `case "$1" in --version) echo 'bd version 0.30.2' ;; *) echo 'Error: database is locked' >&2; exit 1 ;; esac`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a fake bd producing this failure shape
+			fakeBD(t, tt.body)
+
+			// When the client checks bd
+			err := NewClient(t.TempDir()).Check()
+
+			// Then the failure is classified by its sentinel
+			switch {
+			case tt.ok:
+				if err != nil {
+					t.Errorf("Check() error = %v, want nil", err)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Check() error = %v, want %v", err, tt.wantErr)
+				}
+			default:
+				if err == nil || errors.Is(err, ErrNoBeadRepo) || errors.Is(err, ErrBDVersionUnsupported) {
+					t.Errorf("Check() error = %v, want an unclassified error", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck_NotInstalled(t *testing.T) {
+	// Given a PATH without bd
+	t.Setenv("PATH", t.TempDir())
+
+	// When the client checks bd
+	err := NewClient(t.TempDir()).Check()
+
+	// Then it reports bd is not installed
+	if !errors.Is(err, ErrBDNotInstalled) {
+		t.Errorf("Check() error = %v, want ErrBDNotInstalled", err)
+	}
+}
+
+func TestClient_FakeBD_NoDatabase(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping bd CLI test in short mode")
+	}
+	// Given a fake bd run outside any beads repository
+	fakeBD(t, `echo 'Error: no beads database found (run bd init)' >&2; exit 1`)
+	c := NewClient(t.TempDir())
+
+	calls := map[string]func() error{
+		"Resolve":      func() error { _, err := c.Resolve("cap-1"); return err },
+		"Close":        func() error { return c.Close("cap-1") },
+		"Comment":      func() error { return c.Comment("cap-1", "note") },
+		"Create":       func() error { _, err := c.Create(CreateInput{Title: "T", Type: "task"}); return err },
+		"Closed":       func() error { _, err := c.Closed(5); return err },
+		"List":         func() error { _, err := c.List([]string{"open"}); return err },
+		"ListChildren": func() error { _, err := c.ListChildren("cap-1"); return err },
+		"Ready":        func() error { _, err := c.Ready(); return err },
+	}
+	for name, call := range calls {
+		// When each method runs
+		err := call()
+
+		// Then its error wraps ErrNoBeadRepo rather than a generic failure
+		if !errors.Is(err, ErrNoBeadRepo) {
+			t.Errorf("%s() error = %v, want ErrNoBeadRepo", name, err)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.20.0", "0.20.0", 0},
+		{"0.9.9", "0.20.0", -1},
+		{"1.0.0", "0.20.0", 1},
+		{"0.20.1", "0.20.0", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package bead
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/smileynet/capsule/internal/worklog"
+)
+
+// Source is the part of Client that CachedClient wraps.
+type Source interface {
+	Resolve(id string) (worklog.BeadContext, error)
+	Ready() ([]Summary, error)
+	Closed(limit int) ([]Summary, error)
+	List(states []string) ([]Summary, error)
+	Close(id string) error
+	Comment(id, text string) error
+	Create(in CreateInput) (string, error)
+}
+
+// CacheStats counts the lookups a CachedClient answered from its cache and
+// those that ran bd.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// CachedClient wraps a Source with a TTL cache for the reads the dashboard
+// repeats on every cursor move and reload. Concurrent lookups of the same
+// key share one bd call. Failed lookups are not cached. Close and Create
+// clear the cache, since either changes what bd lists. Resolve, Ready,
+// Closed, and List bypass the cache. It is safe for concurrent use.
+type CachedClient struct {
+	src Source
+	ttl time.Duration
+	now func() time.Time
+
+	resolved *ttlCache[worklog.BeadContext]
+	ready    *ttlCache[[]Summary]
+	closed   *ttlCache[[]Summary]
+	listed   *ttlCache[[]Summary]
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewCachedClient returns a CachedClient that keeps results for ttl. A ttl
+// of zero keeps nothing but still shares concurrent lookups.
+func NewCachedClient(src Source, ttl time.Duration) *CachedClient {
+	return &CachedClient{
+		src:      src,
+		ttl:      ttl,
+		now:      time.Now,
+		resolved: newTTLCache[worklog.BeadContext](),
+		ready:    newTTLCache[[]Summary](),
+		closed:   newTTLCache[[]Summary](),
+		listed:   newTTLCache[[]Summary](),
+	}
+}
+
+// ResolveCached is Resolve served from the cache while fresh.
+func (c *CachedClient) ResolveCached(id string) (worklog.BeadContext, error) {
+	return lookup(c, c.resolved, id, func() (worklog.BeadContext, error) { return c.src.Resolve(id) })
+}
+
+// ReadyCached is Ready served from the cache while fresh.
+func (c *CachedClient) ReadyCached() ([]Summary, error) {
+	s, err := lookup(c, c.ready, "", c.src.Ready)
+	return slices.Clone(s), err
+}
+
+// ClosedCached is Closed served from the cache while fresh. Each limit is
+// cached separately.
+func (c *CachedClient) ClosedCached(limit int) ([]Summary, error) {
+	s, err := lookup(c, c.closed, strconv.Itoa(limit), func() ([]Summary, error) { return c.src.Closed(limit) })
+	return slices.Clone(s), err
+}
+
+// ListCached is List served from the cache while fresh. Each set of states
+// is cached separately.
+func (c *CachedClient) ListCached(states []string) ([]Summary, error) {
+	s, err := lookup(c, c.listed, strings.Join(states, ","), func() ([]Summary, error) { return c.src.List(states) })
+	return slices.Clone(s), err
+}
+
+// Resolve runs bd without the cache.
+func (c *CachedClient) Resolve(id string) (worklog.BeadContext, error) {
+	return c.src.Resolve(id)
+}
+
+// Ready runs bd without the cache.
+func (c *CachedClient) Ready() ([]Summary, error) {
+	return c.src.Ready()
+}
+
+// Closed runs bd without the cache.
+func (c *CachedClient) Closed(limit int) ([]Summary, error) {
+	return c.src.Closed(limit)
+}
+
+// List runs bd without the cache.
+func (c *CachedClient) List(states []string) ([]Summary, error) {
+	return c.src.List(states)
+}
+
+// Close closes the bead and clears the cache.
+func (c *CachedClient) Close(id string) error {
+	defer c.Invalidate()
+	return c.src.Close(id)
+}
+
+// Comment adds a comment to the bead. Comments are not cached, so the
+// cache is kept.
+func (c *CachedClient) Comment(id, text string) error {
+	return c.src.Comment(id, text)
+}
+
+// Create files a new bead and clears the cache.
+func (c *CachedClient) Create(in CreateInput) (string, error) {
+	defer c.Invalidate()
+	return c.src.Create(in)
+}
+
+// Invalidate drops every cached result. Lookups in flight still return
+// to their callers but are not kept.
+func (c *CachedClient) Invalidate() {
+	c.resolved.clear()
+	c.InvalidateLists()
+}
+
+// InvalidateLists drops the cached Ready, Closed, and List lists, keeping
+// resolved beads.
+func (c *CachedClient) InvalidateLists() {
+	c.ready.clear()
+	c.closed.clear()
+	c.listed.clear()
+}
+
+// Stats returns the hit and miss counts so far.
+func (c *CachedClient) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// ttlCache holds one kind of result by key. An entry whose done channel is
+// open is a lookup in flight.
+type ttlCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry[T]
+}
+
+type cacheEntry[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+	at   time.Time
+}
+
+func newTTLCache[T any]() *ttlCache[T] {
+	return &ttlCache[T]{entries: make(map[string]*cacheEntry[T])}
+}
+
+// clear drops every entry. Entries in flight finish into the old map.
+func (tc *ttlCache[T]) clear() {
+	tc.mu.Lock()
+	tc.entries = make(map[string]*cacheEntry[T])
+	tc.mu.Unlock()
+}
+
+// lookup returns the fresh or in-flight entry for key, counted as a hit,
+// or runs fetch, counted as a miss. A failed fetch is returned to every
+// caller waiting on it and then forgotten.
+func lookup[T any](c *CachedClient, tc *ttlCache[T], key string, fetch func() (T, error)) (T, error) {
+	tc.mu.Lock()
+	if e, ok := tc.entries[key]; ok {
+		select {
+		case <-e.done:
+			if c.now().Sub(e.at) < c.ttl {
+				tc.mu.Unlock()
+				c.hits.Add(1)
+				return e.val, nil
+			}
+		default:
+			tc.mu.Unlock()
+			c.hits.Add(1)
+			<-e.done
+			return e.val, e.err
+		}
+	}
+	e := &cacheEntry[T]{done: make(chan struct{})}
+	tc.entries[key] = e
+	tc.mu.Unlock()
+	c.misses.Add(1)
+
+	e.val, e.err = fetch()
+	e.at = c.now()
+	close(e.done)
+	if e.err != nil {
+		tc.mu.Lock()
+		if tc.entries[key] == e {
+			delete(tc.entries, key)
+		}
+		tc.mu.Unlock()
+	}
+	return e.val, e.err
+}
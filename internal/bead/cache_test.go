@@ -0,0 +1,216 @@
+package bead
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/worklog"
+)
+
+// fakeSource counts bd calls. A resolve blocks on gate when it is set.
+type fakeSource struct {
+	resolves atomic.Int32
+	readies  atomic.Int32
+	closeds  atomic.Int32
+	lists    atomic.Int32
+	gate     chan struct{}
+	err      error
+}
+
+func (f *fakeSource) Resolve(id string) (worklog.BeadContext, error) {
+	f.resolves.Add(1)
+	if f.gate != nil {
+		<-f.gate
+	}
+	return worklog.BeadContext{TaskID: id, TaskTitle: "title " + id}, f.err
+}
+
+func (f *fakeSource) Ready() ([]Summary, error) {
+	f.readies.Add(1)
+	return []Summary{{ID: "cap-1"}}, f.err
+}
+
+func (f *fakeSource) Closed(limit int) ([]Summary, error) {
+	f.closeds.Add(1)
+	return make([]Summary, limit), f.err
+}
+
+func (f *fakeSource) List(states []string) ([]Summary, error) {
+	f.lists.Add(1)
+	summaries := make([]Summary, len(states))
+	for i, state := range states {
+		summaries[i] = Summary{ID: "cap-" + state, Status: state}
+	}
+	return summaries, f.err
+}
+
+func (f *fakeSource) Close(string) error                 { return nil }
+func (f *fakeSource) Comment(string, string) error       { return nil }
+func (f *fakeSource) Create(CreateInput) (string, error) { return "cap-9", nil }
+
+// newTestCache returns a CachedClient on a clock the test advances.
+func newTestCache(src Source, ttl time.Duration) (*CachedClient, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCachedClient(src, ttl)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCachedClient_ServesUntilTTL(t *testing.T) {
+	// Given a cache with a 30s TTL
+	src := &fakeSource{}
+	c, now := newTestCache(src, 30*time.Second)
+
+	// When a bead is resolved three times, the last after the TTL
+	first, _ := c.ResolveCached("cap-1")
+	*now = now.Add(29 * time.Second)
+	second, _ := c.ResolveCached("cap-1")
+	*now = now.Add(time.Second)
+	if _, err := c.ResolveCached("cap-1"); err != nil {
+		t.Fatalf("ResolveCached() error = %v", err)
+	}
+
+	// Then bd runs for the first lookup and again once the entry expired
+	if first.TaskTitle != "title cap-1" || second.TaskTitle != first.TaskTitle {
+		t.Errorf("cached = %+v, want %+v", second, first)
+	}
+	if got := src.resolves.Load(); got != 2 {
+		t.Errorf("bd show calls = %d, want 2", got)
+	}
+	if got := c.Stats(); got != (CacheStats{Hits: 1, Misses: 2}) {
+		t.Errorf("Stats() = %+v, want 1 hit and 2 misses", got)
+	}
+}
+
+func TestCachedClient_ZeroTTLKeepsNothing(t *testing.T) {
+	// Given a cache with no TTL
+	src := &fakeSource{}
+	c, _ := newTestCache(src, 0)
+
+	// When the ready list is read twice
+	_, _ = c.ReadyCached()
+	_, _ = c.ReadyCached()
+
+	// Then bd runs both times
+	if got := src.readies.Load(); got != 2 {
+		t.Errorf("bd ready calls = %d, want 2", got)
+	}
+}
+
+func TestCachedClient_SharesConcurrentLookups(t *testing.T) {
+	// Given a bd show that blocks until released
+	src := &fakeSource{gate: make(chan struct{})}
+	c, _ := newTestCache(src, time.Minute)
+
+	// When two goroutines resolve the same bead while the first call runs
+	var wg sync.WaitGroup
+	results := make([]worklog.BeadContext, 2)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = c.ResolveCached("cap-1")
+		}()
+	}
+	for c.Stats().Hits+c.Stats().Misses < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(src.gate)
+	wg.Wait()
+
+	// Then bd runs once and both get its result
+	if got := src.resolves.Load(); got != 1 {
+		t.Errorf("bd show calls = %d, want 1", got)
+	}
+	if results[0].TaskID != "cap-1" || results[1].TaskID != "cap-1" {
+		t.Errorf("results = %+v, want both cap-1", results)
+	}
+}
+
+func TestCachedClient_ErrorsAreNotCached(t *testing.T) {
+	// Given bd failing
+	src := &fakeSource{err: errors.New("bd list failed")}
+	c, _ := newTestCache(src, time.Minute)
+
+	// When the closed list is read, then read again once bd recovers
+	if _, err := c.ClosedCached(5); err == nil {
+		t.Fatal("ClosedCached() error = nil, want bd's error")
+	}
+	src.err = nil
+	got, err := c.ClosedCached(5)
+
+	// Then the second read runs bd again and succeeds
+	if err != nil || len(got) != 5 {
+		t.Errorf("ClosedCached() = %d beads, %v; want 5, nil", len(got), err)
+	}
+	if calls := src.closeds.Load(); calls != 2 {
+		t.Errorf("bd list calls = %d, want 2", calls)
+	}
+}
+
+func TestCachedClient_ListCachedPerStates(t *testing.T) {
+	// Given a warm cache of the in-progress and blocked beads
+	src := &fakeSource{}
+	c, _ := newTestCache(src, time.Minute)
+	active := []string{"in_progress", "blocked"}
+	_, _ = c.ListCached(active)
+
+	// When the same states, then other states, are listed
+	got, err := c.ListCached(active)
+	_, _ = c.ListCached([]string{"blocked"})
+
+	// Then bd runs once per set of states
+	if err != nil || len(got) != 2 || got[1].Status != "blocked" {
+		t.Errorf("ListCached() = %+v, %v; want both states", got, err)
+	}
+	if calls := src.lists.Load(); calls != 2 {
+		t.Errorf("bd list calls = %d, want 2", calls)
+	}
+
+	// And dropping the lists runs bd again
+	c.InvalidateLists()
+	_, _ = c.ListCached(active)
+	if calls := src.lists.Load(); calls != 3 {
+		t.Errorf("bd list calls after InvalidateLists = %d, want 3", calls)
+	}
+}
+
+func TestCachedClient_Invalidation(t *testing.T) {
+	tests := []struct {
+		name         string
+		invalidate   func(c *CachedClient)
+		wantResolves int32
+		wantReadies  int32
+	}{
+		{name: "Invalidate drops everything", invalidate: (*CachedClient).Invalidate, wantResolves: 2, wantReadies: 2},
+		{name: "InvalidateLists keeps resolved beads", invalidate: (*CachedClient).InvalidateLists, wantResolves: 1, wantReadies: 2},
+		{name: "Close drops everything", invalidate: func(c *CachedClient) { _ = c.Close("cap-1") }, wantResolves: 2, wantReadies: 2},
+		{name: "Create drops everything", invalidate: func(c *CachedClient) { _, _ = c.Create(CreateInput{Title: "x"}) }, wantResolves: 2, wantReadies: 2},
+		{name: "uncached reads keep the cache", invalidate: func(c *CachedClient) { _, _ = c.Ready() }, wantResolves: 1, wantReadies: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a warm cache
+			src := &fakeSource{}
+			c, _ := newTestCache(src, time.Minute)
+			_, _ = c.ResolveCached("cap-1")
+			_, _ = c.ReadyCached()
+
+			// When it is invalidated and read again
+			tt.invalidate(c)
+			_, _ = c.ResolveCached("cap-1")
+			_, _ = c.ReadyCached()
+
+			// Then only the dropped results run bd again
+			if got := src.resolves.Load(); got != tt.wantResolves {
+				t.Errorf("bd show calls = %d, want %d", got, tt.wantResolves)
+			}
+			if got := src.readies.Load(); got != tt.wantReadies {
+				t.Errorf("bd ready calls = %d, want %d", got, tt.wantReadies)
+			}
+		})
+	}
+}
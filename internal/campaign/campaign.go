@@ -6,12 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/smileynet/capsule/internal/orchestrator"
 	"github.com/smileynet/capsule/internal/prompt"
 	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/worklog"
 )
 
 // Sentinel errors for caller-checkable conditions.
@@ -22,6 +26,9 @@ var (
 	ErrCampaignAborted = errors.New("campaign: aborted")
 	ErrMaxDepth        = errors.New("campaign: max recursion depth reached")
 	ErrCycle           = errors.New("campaign: cycle detected")
+	ErrStateNotFound   = errors.New("campaign: state not found")
+	ErrTaskNotFound    = errors.New("campaign: task not found")
+	ErrValidation      = errors.New("campaign: feature validation failed")
 )
 
 // maxCampaignDepth caps recursive campaign nesting (epic → feature → task).
@@ -39,14 +46,18 @@ type BeadInfo struct {
 	Description string
 	Priority    int
 	Type        string
+	Labels      []string // Carries capsule:provider and capsule:timeout overrides to the pipeline.
+	DependsOn   []string // Beads that must finish first; only siblings in the campaign are ordered.
+	BlockedBy   []string // Set by the Runner for OnCampaignStart: unfinished siblings it waits on.
 }
 
 // BeadInput holds the fields needed to create a new bead.
 type BeadInput struct {
-	ParentID string
-	Type     string
-	Title    string
-	Priority int
+	ParentID    string
+	Type        string
+	Title       string
+	Description string
+	Priority    int
 }
 
 // BeadClient abstracts bead CLI operations for campaign use.
@@ -57,6 +68,19 @@ type BeadClient interface {
 	Create(input BeadInput) (string, error)
 }
 
+// BeadCommenter is an optional extension of BeadClient. A BeadClient that
+// implements it is used to comment on the parent bead when feature
+// validation fails.
+type BeadCommenter interface {
+	Comment(id, text string) error
+}
+
+// WorklogAppender records entries in a bead's archived worklog. It is
+// satisfied by *worklog.Manager.
+type WorklogAppender interface {
+	AppendArchived(beadID string, entry worklog.PhaseEntry) error
+}
+
 // StateStore persists campaign state between runs.
 type StateStore interface {
 	Save(state State) error
@@ -64,12 +88,14 @@ type StateStore interface {
 	Remove(id string) error
 }
 
-// Callback receives campaign lifecycle events for display.
+// Callback receives campaign lifecycle events for display. Events are
+// delivered one at a time from the goroutine that called Runner.Run, even
+// when tasks run concurrently, so implementations need no locking.
 type Callback interface {
 	OnCampaignStart(parentID string, tasks []BeadInfo)
 	OnTaskStart(beadID string)
 	OnTaskComplete(result TaskResult)
-	OnTaskFail(beadID string, err error)
+	OnTaskFail(beadID string, err error) // Not called for a TaskFailureObserver.
 	OnCampaignPaused(beadID string, reason string, details string)
 	OnDiscoveryFiled(finding provider.Finding, newBeadID string)
 	OnValidationStart()
@@ -77,6 +103,52 @@ type Callback interface {
 	OnCampaignComplete(state State)
 }
 
+// TaskFailureObserver is an optional extension of Callback. A Callback that
+// implements it is told about a failed task through OnTaskFailed, with the
+// phases that ran before the failure, instead of OnTaskFail.
+type TaskFailureObserver interface {
+	OnTaskFailed(result TaskResult, err error)
+}
+
+// NotifyTaskFailed reports a failed task to cb through OnTaskFailed when cb
+// is a TaskFailureObserver, otherwise through OnTaskFail with its bead ID. A
+// Callback that wraps another forwards the event with it.
+func NotifyTaskFailed(cb Callback, result TaskResult, err error) {
+	if o, ok := cb.(TaskFailureObserver); ok {
+		o.OnTaskFailed(result, err)
+		return
+	}
+	cb.OnTaskFail(result.BeadID, err)
+}
+
+// TaskSkipObserver is an optional extension of Callback. A Callback that
+// implements it is told about each task skipped without running, with the
+// reason it was skipped.
+type TaskSkipObserver interface {
+	OnTaskSkipped(beadID string, reason string)
+}
+
+// ValidationPhaseObserver is an optional extension of Callback. A Callback
+// that implements it receives the status of each validation phase as it
+// runs; otherwise those updates go to the pipeline's own status callback.
+type ValidationPhaseObserver interface {
+	OnValidationPhase(update orchestrator.StatusUpdate)
+}
+
+// BreakerTripObserver is an optional extension of Callback. A Callback that
+// implements it is told when the circuit breaker stops the campaign, with
+// the tripping task and recent failures.
+type BreakerTripObserver interface {
+	OnCircuitBroken(trip BreakerTrip)
+}
+
+// ParentCloseObserver is an optional extension of Callback. A Callback that
+// implements it is told when the top-level parent bead was closed; see
+// Config.CloseParent.
+type ParentCloseObserver interface {
+	OnParentClosed(parentID string)
+}
+
 // CampaignStatus represents the state of a campaign.
 type CampaignStatus string
 
@@ -98,27 +170,201 @@ const (
 	TaskSkipped   TaskStatus = "skipped"
 )
 
+// Failure modes for Config.FailureMode: what a campaign does once a task
+// fails. Tasks that never ran are skipped with a SkipReason.
+const (
+	FailureAbort          = "abort"           // Stop the campaign.
+	FailureStop           = "stop"            // Alias of FailureAbort.
+	FailureContinue       = "continue"        // Run every remaining task, dependents of the failed one included.
+	FailureSkipDependents = "skip-dependents" // Skip the failed task's dependents and descendants; run the rest.
+)
+
+// FailureKind classifies a task failure for circuit breaking.
+type FailureKind string
+
+const (
+	FailureSetup  FailureKind = "setup"  // Provider, setup, or post-task error.
+	FailureSignal FailureKind = "signal" // A phase reported NEEDS_WORK or ERROR.
+)
+
+// FailureCounts tallies task failures by kind.
+type FailureCounts struct {
+	Setup  int `json:"setup"`
+	Signal int `json:"signal"`
+}
+
+// FailureRecord is one task failure in the circuit breaker's history.
+type FailureRecord struct {
+	BeadID string      `json:"bead_id"`
+	Kind   FailureKind `json:"kind"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// BreakerMode selects which failures the circuit breaker counts.
+type BreakerMode string
+
+const (
+	BreakerConsecutive BreakerMode = "consecutive" // Failures since the last task success.
+	BreakerTotal       BreakerMode = "total"       // Every failure in the campaign.
+)
+
+// CircuitBreaker holds the failure thresholds that stop a campaign. Each
+// kind is counted independently; in consecutive mode (the default) a task
+// success resets both. A zero threshold disables that check.
+type CircuitBreaker struct {
+	Mode   BreakerMode // Empty means BreakerConsecutive.
+	Setup  int         // Max provider/setup failures.
+	Signal int         // Max NEEDS_WORK/ERROR failures.
+}
+
+// BreakerTrip describes why the circuit breaker stopped a campaign.
+type BreakerTrip struct {
+	Reason   string          // e.g. "3 consecutive NEEDS_WORK/ERROR failures (limit 3)".
+	Mode     BreakerMode     // How the failures were counted.
+	Count    int             // Failures of the kind that reached its limit.
+	BeadID   string          // Task whose failure tripped the breaker.
+	Failures []FailureRecord // The recent failures that count toward the breaker, oldest first.
+	Counts   FailureCounts   // Total failures by kind.
+}
+
+// Summary renders the trip for a banner, e.g. "stopped after 3
+// consecutive failures at cap-123.5".
+func (t BreakerTrip) Summary() string {
+	return fmt.Sprintf("stopped after %d %s failures at %s", t.Count, t.Mode, t.BeadID)
+}
+
+// FailedIDs returns the IDs of the counted failures, oldest first, each
+// listed once.
+func (t BreakerTrip) FailedIDs() []string {
+	var ids []string
+	for _, f := range t.Failures {
+		if !slices.Contains(ids, f.BeadID) {
+			ids = append(ids, f.BeadID)
+		}
+	}
+	return ids
+}
+
 // Config holds campaign-specific settings.
 type Config struct {
-	Logger           io.Writer                                    // Optional logger for warnings (nil-safe).
-	FailureMode      string                                       // "abort" | "continue"
-	CircuitBreaker   int                                          // Max consecutive failures before stopping.
-	DiscoveryFiling  bool                                         // File findings as new beads.
-	CrossRunContext  bool                                         // Include sibling context in prompts.
-	ValidationPhases string                                       // Phase set name for feature validation.
-	PostTaskFunc     func(beadID string) error                    // Called after successful task completion.
-	ConflictResolver func(beadID string, conflictErr error) error // Called when merge conflict occurs.
+	Logger            io.Writer                                    // Optional logger for warnings (nil-safe).
+	Log               *slog.Logger                                 // Optional structured debug log; nil discards.
+	FailureMode       string                                       // FailureAbort, FailureStop, FailureContinue, or FailureSkipDependents.
+	Ordering          string                                       // OrderPriority (the default when empty), OrderTypeThenPriority, or OrderAsListed.
+	CircuitBreaker    CircuitBreaker                               // Failure thresholds before stopping.
+	DiscoveryFiling   bool                                         // File findings as new beads.
+	PickUpDiscoveries bool                                         // Queue beads filed from findings in this campaign, by Ordering.
+	MinSeverity       string                                       // Least severe finding filed; empty files all.
+	CrossRunContext   bool                                         // Include sibling context in prompts.
+	ValidationPhases  string                                       // Phase set name for feature validation.
+	CloseParent       bool                                         // Close the parent bead once every task completed and validation passed.
+	Concurrency       int                                          // Most task pipelines in flight at once; 0 or 1 runs tasks one at a time.
+	Resume            bool                                         // Continue from saved state instead of starting over.
+	RetryFailed       bool                                         // On resume, run failed and skipped tasks again.
+	BaseBranch        string                                       // Branch every task and validation starts from; empty uses the pipeline default.
+	Worklog           WorklogAppender                              // Optional; receives the parent's validation results.
+	PostTaskFunc      func(beadID string) error                    // Called after successful task completion.
+	ConflictResolver  func(beadID string, conflictErr error) error // Called when merge conflict occurs.
+	CompleteFunc      func(c Completion)                           // Called once when the top-level campaign finishes.
+}
+
+// stopsOnFailure reports whether a failed task stops the campaign.
+func (c Config) stopsOnFailure() bool {
+	return c.FailureMode == FailureAbort || c.FailureMode == FailureStop
+}
+
+// Completion summarizes a finished top-level campaign for Config.CompleteFunc.
+// Err is the error Run returns; task counts and Tasks cover the top-level
+// tasks only; Usage covers every pipeline, sub-campaigns included.
+type Completion struct {
+	ParentID string
+	Duration time.Duration
+	Passed   int
+	Failed   int
+	Skipped  int
+	Tasks    []TaskResult
+	Usage    provider.Usage
+	Err      error
+}
+
+// Success reports whether the campaign finished without error or failed tasks.
+func (c Completion) Success() bool {
+	return c.Err == nil && c.Failed == 0
+}
+
+// Comment renders the completion as a condensed note for the parent bead in
+// bd: the outcome and duration, each task's status, and every file the
+// completed tasks changed.
+func (c Completion) Comment() string {
+	status := "passed"
+	switch {
+	case errors.Is(c.Err, ErrCampaignPaused), errors.Is(c.Err, ErrCampaignAborted):
+		status = "paused"
+	case !c.Success():
+		status = "failed"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "capsule campaign %s in %s: %d passed, %d failed, %d skipped\n",
+		status, c.Duration.Round(time.Second), c.Passed, c.Failed, c.Skipped)
+	if len(c.Tasks) > 0 {
+		b.WriteString("\nTasks:\n")
+	}
+	var files []string
+	for _, t := range c.Tasks {
+		fmt.Fprintf(&b, "- %s: %s", t.BeadID, t.Status)
+		switch {
+		case t.Error != "":
+			fmt.Fprintf(&b, " (%s)", t.Error)
+		case t.SkipReason != "":
+			fmt.Fprintf(&b, " (%s)", t.SkipReason)
+		}
+		b.WriteString("\n")
+		if t.Status != TaskCompleted {
+			continue
+		}
+		for _, pr := range t.PhaseResults {
+			for _, f := range pr.Signal.FilesChanged {
+				if !slices.Contains(files, f) {
+					files = append(files, f)
+				}
+			}
+		}
+	}
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "\nFiles changed: %s\n", strings.Join(files, ", "))
+	}
+	return b.String()
 }
 
 // State holds the complete campaign state for persistence.
 type State struct {
-	ID             string         `json:"id"`
-	ParentBeadID   string         `json:"parent_bead_id"`
-	Tasks          []TaskResult   `json:"tasks"`
-	CurrentTaskIdx int            `json:"current_task_idx"`
-	ConsecFailures int            `json:"consecutive_failures"`
-	StartedAt      time.Time      `json:"started_at"`
-	Status         CampaignStatus `json:"status"`
+	ID             string          `json:"id"`
+	ParentBeadID   string          `json:"parent_bead_id"`
+	Tasks          []TaskResult    `json:"tasks"`
+	CurrentTaskIdx int             `json:"current_task_idx"`
+	ConsecFailures int             `json:"consecutive_failures"`
+	ConsecByKind   FailureCounts   `json:"consecutive_by_kind"`
+	Failures       FailureCounts   `json:"failure_counts"` // Total failures by kind.
+	TripReason     string          `json:"trip_reason,omitempty"`
+	TrippedBy      string          `json:"tripped_by,omitempty"`      // Task whose failure tripped the circuit breaker.
+	RecentFailures []FailureRecord `json:"recent_failures,omitempty"` // Latest task failures, oldest first.
+	Validation     *TaskResult     `json:"validation,omitempty"`      // Feature validation of the parent, once run.
+	Discovered     []string        `json:"discovered,omitempty"`      // Beads filed from findings; queued only with Config.PickUpDiscoveries.
+	ParentOpen     string          `json:"parent_open,omitempty"`     // Why Config.CloseParent left the parent bead open.
+	Usage          provider.Usage  `json:"usage,omitzero"`            // Tokens consumed by this campaign's pipelines, including validation and, at the top level, sub-campaigns.
+	StartedAt      time.Time       `json:"started_at"`
+	Status         CampaignStatus  `json:"status"`
+}
+
+// Done counts the tasks that have completed.
+func (s State) Done() int {
+	n := 0
+	for _, t := range s.Tasks {
+		if t.Status == TaskCompleted {
+			n++
+		}
+	}
+	return n
 }
 
 // TaskResult records the outcome of a single task within a campaign.
@@ -127,29 +373,49 @@ type TaskResult struct {
 	Status       TaskStatus                 `json:"status"`
 	PhaseResults []orchestrator.PhaseResult `json:"phase_results"`
 	Error        string                     `json:"error,omitempty"`
+	SkipReason   string                     `json:"skip_reason,omitempty"` // Set when a failed dependency or the circuit breaker kept the task from running.
 }
 
-// Runner orchestrates a campaign: sequential task execution with circuit breaking,
-// discovery filing, and state persistence.
+// Runner orchestrates a campaign: task execution in dependency order, up to
+// Config.Concurrency pipelines at once, with circuit breaking, discovery
+// filing, and state persistence. The PipelineRunner must be safe for
+// concurrent use when Concurrency is above 1.
 type Runner struct {
 	pipeline PipelineRunner
 	beads    BeadClient
 	store    StateStore
 	config   Config
 	callback Callback
+	top      *State          // Top-level campaign state of the current Run, for CompleteFunc and usage totals.
+	filed    map[string]bool // Finding titles filed during the current Run.
+	log      *slog.Logger
 }
 
 // NewRunner creates a campaign Runner with the given dependencies.
 func NewRunner(pipeline PipelineRunner, beads BeadClient, store StateStore, config Config, callback Callback) *Runner {
+	log := config.Log
+	if log == nil {
+		log = slog.New(slog.DiscardHandler)
+	}
 	return &Runner{
 		pipeline: pipeline,
 		beads:    beads,
 		store:    store,
 		config:   config,
 		callback: callback,
+		log:      log,
 	}
 }
 
+// saveState persists the campaign state, warning rather than failing on error.
+func (r *Runner) saveState(state State) {
+	if err := r.store.Save(state); err != nil {
+		r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
+		return
+	}
+	r.log.Debug("campaign state saved", "campaign", state.ID, "status", state.Status, "task_idx", state.CurrentTaskIdx)
+}
+
 // logWarning writes a warning message to the logger if configured.
 func (r *Runner) logWarning(format string, args ...any) {
 	if r.config.Logger != nil {
@@ -158,11 +424,40 @@ func (r *Runner) logWarning(format string, args ...any) {
 }
 
 // Run executes a campaign for the given parent bead (feature or epic).
-// It discovers ready children, runs pipelines sequentially, handles failures,
+// It discovers ready children, runs their pipelines, handles failures,
 // files discoveries, and runs validation on completion. When a child is a
 // feature or epic, it recurses into a sub-campaign instead of running a pipeline.
 func (r *Runner) Run(ctx context.Context, parentID string) error {
-	return r.runRecursive(ctx, parentID, 0, make(map[string]bool))
+	start := time.Now()
+	r.top = nil
+	r.filed = make(map[string]bool)
+	err := r.runRecursive(ctx, parentID, 0, make(map[string]bool))
+	if r.config.CompleteFunc != nil {
+		r.config.CompleteFunc(r.completion(parentID, time.Since(start), err))
+	}
+	return err
+}
+
+// completion builds a Completion from the top-level campaign state.
+// Counts stay zero when the campaign never started (e.g. no ready tasks).
+func (r *Runner) completion(parentID string, d time.Duration, err error) Completion {
+	c := Completion{ParentID: parentID, Duration: d, Err: err}
+	if r.top == nil {
+		return c
+	}
+	c.Tasks = r.top.Tasks
+	c.Usage = r.top.Usage
+	for _, t := range r.top.Tasks {
+		switch t.Status {
+		case TaskCompleted:
+			c.Passed++
+		case TaskFailed:
+			c.Failed++
+		case TaskSkipped:
+			c.Skipped++
+		}
+	}
+	return c
 }
 
 // runRecursive is the internal recursive implementation of Run.
@@ -175,152 +470,273 @@ func (r *Runner) runRecursive(ctx context.Context, parentID string, depth int, v
 	}
 	visited[parentID] = true
 
-	children, err := r.beads.ReadyChildren(parentID)
+	graph, state, err := r.prepare(parentID)
 	if err != nil {
-		return fmt.Errorf("campaign: listing children of %s: %w", parentID, err)
+		return err
 	}
-	if len(children) == 0 {
-		return ErrNoTasks
+	state.Status = CampaignRunning
+	if depth == 0 {
+		r.top = &state
 	}
 
-	r.callback.OnCampaignStart(parentID, children)
-
-	// Build type map from children for deciding recursion vs pipeline.
-	childTypes := make(map[string]string, len(children))
-	for _, c := range children {
-		childTypes[c.ID] = c.Type
+	r.log.Debug("campaign start", "parent", parentID, "depth", depth, "children", len(graph.info))
+	r.callback.OnCampaignStart(parentID, graph.planned(state))
+
+	loop := &taskLoop{
+		parentID: parentID,
+		depth:    depth,
+		visited:  visited,
+		state:    &state,
+		graph:    graph,
+		limit:    max(r.config.Concurrency, 1),
+		running:  make(map[string]bool),
+		finished: make(map[string]bool),
 	}
-
-	state := r.initOrResumeState(parentID, children)
-	state.Status = CampaignRunning
-
-	for i := state.CurrentTaskIdx; i < len(state.Tasks); i++ {
-		task := &state.Tasks[i]
-		if task.Status == TaskCompleted || task.Status == TaskSkipped {
-			continue
+	loop.outcomes = make(chan taskOutcome, loop.limit)
+	// Tasks that failed in an earlier run stay failed; Config.RetryFailed
+	// has already returned them to pending when they should run again.
+	for _, t := range state.Tasks {
+		if t.Status == TaskFailed {
+			loop.finished[t.BeadID] = true
 		}
+	}
+	loop.advance()
+	if err := r.runTasks(ctx, loop); err != nil {
+		return err
+	}
 
-		if r.config.CircuitBreaker > 0 && state.ConsecFailures >= r.config.CircuitBreaker {
-			state.Status = CampaignFailed
-			if err := r.store.Save(state); err != nil {
-				r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
-			}
-			return ErrCircuitBroken
+	// All tasks done — run feature validation if configured.
+	var valErr error
+	if r.allComplete(state) && r.config.ValidationPhases != "" {
+		r.callback.OnValidationStart()
+		valResult := r.runValidation(ctx, parentID, state)
+		state.Validation = &valResult
+		r.addUsage(&state, depth, orchestrator.PipelineOutput{PhaseResults: valResult.PhaseResults}.TotalUsage())
+		r.recordValidation(parentID, valResult)
+		r.callback.OnValidationComplete(valResult)
+		if valResult.Status != TaskCompleted {
+			valErr = fmt.Errorf("%w: %s: %s", ErrValidation, parentID, valResult.Error)
 		}
+	}
 
-		r.callback.OnTaskStart(task.BeadID)
-		task.Status = TaskRunning
+	// A failed validation leaves the campaign resumable so the next run
+	// validates again, and its error keeps the parent bead open.
+	state.Status = CampaignCompleted
+	if valErr != nil {
+		state.Status = CampaignFailed
+	}
+	if depth == 0 && r.config.CloseParent {
+		r.closeParent(parentID, &state)
+	}
+	r.saveState(state)
+	r.callback.OnCampaignComplete(state)
+	return valErr
+}
 
-		// Feature/epic children recurse; tasks run a pipeline.
-		childType := childTypes[task.BeadID]
-		if childType == "feature" || childType == "epic" {
-			err = r.runRecursive(ctx, task.BeadID, depth+1, visited)
-		} else {
-			var output orchestrator.PipelineOutput
-			input := r.buildPipelineInput(task.BeadID, state)
-			output, err = r.pipeline.RunPipeline(ctx, input)
-			if err == nil {
-				task.PhaseResults = output.PhaseResults
-				r.fileDiscoveries(output, parentID)
-			}
-		}
+// addUsage adds u to a level's state and, for a sub-campaign, to the
+// top-level state too, so the top-level total covers nested pipelines.
+func (r *Runner) addUsage(state *State, depth int, u provider.Usage) {
+	state.Usage = state.Usage.Add(u)
+	if depth > 0 && r.top != nil {
+		r.top.Usage = r.top.Usage.Add(u)
+	}
+}
 
-		if err != nil {
-			if ctx.Err() != nil {
-				task.Status = TaskPending
-				state.Status = CampaignPaused
-				if err := r.store.Save(state); err != nil {
-					r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
-				}
-				return ErrCampaignAborted
-			}
+// Plan returns the tasks Run would start with for parentID, in the order it
+// would run them, without running a pipeline or saving state. With
+// Config.Resume, tasks a saved campaign already finished are left out.
+// Child features and epics are listed as single tasks; Run recurses into
+// them when their turn comes. A parent without ready children returns
+// ErrNoTasks, as Run does.
+func (r *Runner) Plan(parentID string) ([]BeadInfo, error) {
+	graph, state, err := r.prepare(parentID)
+	if err != nil {
+		return nil, err
+	}
+	return graph.planned(state), nil
+}
 
-			if errors.Is(err, orchestrator.ErrPipelinePaused) {
-				task.Status = TaskPending
-				state.Status = CampaignPaused
-				if err := r.store.Save(state); err != nil {
-					r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
-				}
-				return ErrCampaignPaused
-			}
+// prepare lists parentID's ready children and returns them with the
+// campaign state to run, fresh or resumed, its pending tasks ordered after
+// the siblings they depend on and otherwise by Config.Ordering. A resumed
+// campaign also queues children that appeared since it was planned.
+func (r *Runner) prepare(parentID string) (*taskGraph, State, error) {
+	children, err := r.beads.ReadyChildren(parentID)
+	if err != nil {
+		return nil, State{}, fmt.Errorf("campaign: listing children of %s: %w", parentID, err)
+	}
+	if len(children) == 0 {
+		return nil, State{}, ErrNoTasks
+	}
+	state := r.initOrResumeState(parentID, children)
+	children = r.pickUp(state, children)
+	graph := newTaskGraph(children, r.config.Ordering)
+	graph.queueNew(&state, children)
+	if err := graph.orderPending(&state); err != nil {
+		return nil, State{}, err
+	}
+	return graph, state, nil
+}
 
-			task.Status = TaskFailed
-			task.Error = err.Error()
-			state.ConsecFailures++
-			r.callback.OnTaskFail(task.BeadID, err)
+// refreshTasks re-queries the parent's children after a task completes so
+// that children filed since the campaign was planned are queued, and
+// re-sorts the remaining tasks with the latest dependency edges by the same
+// Config.Ordering. Discovery findings are queued only with
+// Config.PickUpDiscoveries (see pickUp). A failed query is a warning; a
+// dependency cycle stops the campaign.
+func (r *Runner) refreshTasks(state *State, parentID string, graph *taskGraph) error {
+	children, err := r.beads.ReadyChildren(parentID)
+	if err != nil {
+		r.logWarning("campaign: warning: refreshing children of %s: %v\n", parentID, err)
+		return nil
+	}
+	children = r.pickUp(*state, children)
+	graph.add(children)
+	if graph.queueNew(state, children) {
+		r.log.Debug("campaign tasks queued", "parent", parentID, "tasks", len(state.Tasks))
+	}
+	return graph.orderPending(state)
+}
 
-			if r.config.FailureMode == "abort" {
-				state.Status = CampaignFailed
-				if err := r.store.Save(state); err != nil {
-					r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
-				}
-				return fmt.Errorf("campaign: task %s failed: %w", task.BeadID, err)
-			}
-			state.CurrentTaskIdx = i + 1
-			if err := r.store.Save(state); err != nil {
-				r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
-			}
+// pickUp returns the children the campaign may queue: all of them with
+// Config.PickUpDiscoveries, otherwise those not filed from its findings.
+func (r *Runner) pickUp(state State, children []BeadInfo) []BeadInfo {
+	if r.config.PickUpDiscoveries || len(state.Discovered) == 0 {
+		return children
+	}
+	return slices.DeleteFunc(slices.Clone(children), func(c BeadInfo) bool {
+		return slices.Contains(state.Discovered, c.ID)
+	})
+}
+
+// RecordTaskResult replaces the persisted result for result.BeadID in the
+// campaign state of parentID. It is used when a single task is re-run outside
+// the campaign loop, such as a retry from the dashboard. A completed result
+// resets the consecutive failure count.
+func RecordTaskResult(store StateStore, parentID string, result TaskResult) error {
+	state, found, err := store.Load(parentID)
+	if err != nil {
+		return fmt.Errorf("campaign: loading state %s: %w", parentID, err)
+	}
+	if !found {
+		return fmt.Errorf("%w: %s", ErrStateNotFound, parentID)
+	}
+	for i := range state.Tasks {
+		if state.Tasks[i].BeadID != result.BeadID {
 			continue
 		}
-
-		task.Status = TaskCompleted
-		state.ConsecFailures = 0
-		r.callback.OnTaskComplete(*task)
-
-		// Call PostTaskFunc after successful task (only for leaf tasks, not recursive entries).
-		if r.config.PostTaskFunc != nil && childType != "feature" && childType != "epic" {
-			if postErr := r.config.PostTaskFunc(task.BeadID); postErr != nil {
-				// Treat PostTaskFunc error as task failure.
-				task.Status = TaskFailed
-				task.Error = postErr.Error()
-				state.ConsecFailures++
-				r.callback.OnTaskFail(task.BeadID, postErr)
-				r.callback.OnCampaignPaused(task.BeadID, "post_task_error", postErr.Error())
-
-				if r.config.FailureMode == "abort" {
-					state.Status = CampaignFailed
-					if err := r.store.Save(state); err != nil {
-						r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
-					}
-					return fmt.Errorf("campaign: task %s failed: %w", task.BeadID, postErr)
-				}
-				state.CurrentTaskIdx = i + 1
-				if err := r.store.Save(state); err != nil {
-					r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
-				}
-				continue
-			}
-		} else {
-			// Fallback to legacy behavior when PostTaskFunc is nil.
-			r.runPostPipeline(task.BeadID)
+		state.Tasks[i] = result
+		if result.Status == TaskCompleted {
+			state.ConsecFailures = 0
+			state.ConsecByKind = FailureCounts{}
 		}
-
-		state.CurrentTaskIdx = i + 1
-		if err := r.store.Save(state); err != nil {
-			r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
+		if err := store.Save(state); err != nil {
+			return fmt.Errorf("campaign: saving state %s: %w", parentID, err)
 		}
+		return nil
 	}
+	return fmt.Errorf("%w: %s in %s", ErrTaskNotFound, result.BeadID, parentID)
+}
 
-	// All tasks done — run feature validation if configured.
-	if r.allComplete(state) && r.config.ValidationPhases != "" {
-		r.callback.OnValidationStart()
-		valResult := r.runValidation(ctx, parentID, state)
-		r.callback.OnValidationComplete(valResult)
+// recentFailureLimit is how many failures State.RecentFailures keeps.
+const recentFailureLimit = 10
+
+// breakerSkipReason is the SkipReason of tasks left unrun when the circuit
+// breaker tripped. A resumed campaign runs them again.
+const breakerSkipReason = "circuit breaker tripped"
+
+// trip reports whether the circuit breaker should stop the campaign and
+// why. In consecutive mode the failures since the last task success are
+// counted; in total mode every failure is.
+func (r *Runner) trip(state State) (BreakerTrip, bool) {
+	cb := r.config.CircuitBreaker
+	mode, counts, counted := cb.Mode, state.ConsecByKind, state.ConsecFailures
+	if mode == BreakerTotal {
+		counts, counted = state.Failures, state.Failures.Setup+state.Failures.Signal
+	} else {
+		mode = BreakerConsecutive
 	}
 
-	state.Status = CampaignCompleted
-	if err := r.store.Save(state); err != nil {
-		r.logWarning("campaign: warning: save state %s: %v\n", state.ID, err)
+	t := BreakerTrip{Mode: mode, Counts: state.Failures}
+	switch {
+	case cb.Setup > 0 && counts.Setup >= cb.Setup:
+		t.Count = counts.Setup
+		t.Reason = fmt.Sprintf("%d %s provider/setup failures (limit %d)", counts.Setup, mode, cb.Setup)
+	case cb.Signal > 0 && counts.Signal >= cb.Signal:
+		t.Count = counts.Signal
+		t.Reason = fmt.Sprintf("%d %s NEEDS_WORK/ERROR failures (limit %d)", counts.Signal, mode, cb.Signal)
+	default:
+		return BreakerTrip{}, false
 	}
-	r.callback.OnCampaignComplete(state)
-	return nil
+	recent := state.RecentFailures
+	t.Failures = slices.Clone(recent[len(recent)-min(counted, len(recent)):])
+	if n := len(recent); n > 0 {
+		t.BeadID = recent[n-1].BeadID
+	}
+	return t, true
+}
+
+// recordFailure counts a task failure of the given kind in state and adds
+// it to the recent failures.
+func recordFailure(state *State, beadID string, kind FailureKind, err error) {
+	state.ConsecFailures++
+	switch kind {
+	case FailureSignal:
+		state.ConsecByKind.Signal++
+		state.Failures.Signal++
+	default:
+		kind = FailureSetup
+		state.ConsecByKind.Setup++
+		state.Failures.Setup++
+	}
+	state.RecentFailures = append(state.RecentFailures, FailureRecord{BeadID: beadID, Kind: kind, Error: err.Error()})
+	if n := len(state.RecentFailures); n > recentFailureLimit {
+		state.RecentFailures = state.RecentFailures[n-recentFailureLimit:]
+	}
+}
+
+// classifyFailure decides whether a task failed because a phase reported
+// NEEDS_WORK or ERROR, or because of a provider or setup error. A
+// PipelineError without Err is a signal failure, as is one whose failing
+// phase attempt recorded a NEEDS_WORK or ERROR signal (retries exhausted).
+func classifyFailure(err error, results []orchestrator.PhaseResult) FailureKind {
+	var pe *orchestrator.PipelineError
+	if !errors.As(err, &pe) {
+		return FailureSetup
+	}
+	if pe.Err == nil {
+		return FailureSignal
+	}
+	if n := len(results); n > 0 {
+		last := results[n-1]
+		if last.PhaseName == pe.Phase && last.Attempt == pe.Attempt &&
+			(last.Signal.Status == provider.StatusNeedsWork || last.Signal.Status == provider.StatusError) {
+			return FailureSignal
+		}
+	}
+	return FailureSetup
 }
 
-// initOrResumeState loads existing state or creates a new one.
+// initOrResumeState loads the saved state of an unfinished campaign when
+// Config.Resume is set, or creates a new one. Completed tasks in a resumed
+// state are not run again; failed and skipped tasks are only with
+// Config.RetryFailed. Sibling context for later tasks comes from the
+// completed tasks' saved phase results.
 func (r *Runner) initOrResumeState(parentID string, children []BeadInfo) State {
 	existing, found, err := r.store.Load(parentID)
 	if err == nil && found && existing.Status != CampaignCompleted {
-		return existing
+		if !r.config.Resume {
+			r.logWarning("campaign: %s has saved state with %d/%d tasks done; starting over\n",
+				parentID, existing.Done(), len(existing.Tasks))
+		} else {
+			if r.config.RetryFailed {
+				retryFailed(&existing)
+			} else if existing.TripReason != "" {
+				resumeTripped(&existing)
+			}
+			r.log.Debug("campaign resume", "parent", parentID, "done", existing.Done(), "tasks", len(existing.Tasks))
+			return existing
+		}
 	}
 
 	tasks := make([]TaskResult, len(children))
@@ -337,15 +753,51 @@ func (r *Runner) initOrResumeState(parentID string, children []BeadInfo) State {
 	}
 }
 
+// retryFailed returns a resumed state's failed and skipped tasks to pending
+// and resets the circuit breaker.
+func retryFailed(state *State) {
+	for i, t := range state.Tasks {
+		if t.Status == TaskFailed || t.Status == TaskSkipped {
+			state.Tasks[i] = TaskResult{BeadID: t.BeadID, Status: TaskPending}
+		}
+	}
+	state.CurrentTaskIdx = 0
+	resetBreaker(state)
+}
+
+// resumeTripped returns the tasks a tripped circuit breaker left unrun to
+// pending and resets the breaker. Failed tasks keep their outcome.
+func resumeTripped(state *State) {
+	for i, t := range state.Tasks {
+		if t.Status == TaskSkipped && t.SkipReason == breakerSkipReason {
+			state.Tasks[i] = TaskResult{BeadID: t.BeadID, Status: TaskPending}
+		}
+	}
+	state.CurrentTaskIdx = 0
+	resetBreaker(state)
+}
+
+// resetBreaker clears the failure counts and trip record so the circuit
+// breaker starts afresh.
+func resetBreaker(state *State) {
+	state.ConsecFailures = 0
+	state.ConsecByKind = FailureCounts{}
+	state.Failures = FailureCounts{}
+	state.RecentFailures = nil
+	state.TripReason = ""
+	state.TrippedBy = ""
+}
+
 // buildPipelineInput creates a PipelineInput for a task, optionally including sibling context.
 func (r *Runner) buildPipelineInput(beadID string, state State) orchestrator.PipelineInput {
-	input := orchestrator.PipelineInput{BeadID: beadID}
+	input := orchestrator.PipelineInput{BeadID: beadID, BaseBranch: r.config.BaseBranch}
 
 	// Look up bead details for the title/description.
 	info, err := r.beads.Show(beadID)
 	if err == nil {
 		input.Title = info.Title
 		input.Description = info.Description
+		input.Bead.Labels = info.Labels
 	}
 
 	// Include sibling context from completed tasks.
@@ -383,25 +835,35 @@ func (r *Runner) buildSiblingContext(state State) []prompt.SiblingContext {
 	return siblings
 }
 
-// fileDiscoveries creates new beads from findings in phase outputs.
-func (r *Runner) fileDiscoveries(output orchestrator.PipelineOutput, parentID string) {
+// fileDiscoveries creates new beads from findings in phase outputs and
+// records them in state. Only findings at or above Config.MinSeverity are
+// filed, and a title already filed during this Run is not filed again.
+func (r *Runner) fileDiscoveries(state *State, output orchestrator.PipelineOutput, parentID string) {
 	if !r.config.DiscoveryFiling {
 		return
 	}
 
+	threshold := severityToPriority(r.config.MinSeverity)
 	for _, pr := range output.PhaseResults {
 		for _, f := range pr.Signal.Findings {
+			priority := severityToPriority(f.Severity)
+			if priority > threshold || r.filed[f.Title] {
+				continue
+			}
 			newID, err := r.beads.Create(BeadInput{
-				ParentID: parentID,
-				Type:     "task",
-				Title:    f.Title,
-				Priority: severityToPriority(f.Severity),
+				ParentID:    parentID,
+				Type:        "task",
+				Title:       f.Title,
+				Description: f.Description,
+				Priority:    priority,
 			})
 			if err != nil {
 				// Log discovery filing failures so users know their findings aren't being persisted.
 				fmt.Fprintf(os.Stderr, "campaign: warning: filing discovery %q: %v\n", f.Title, err)
 				continue
 			}
+			r.filed[f.Title] = true
+			state.Discovered = append(state.Discovered, newID)
 			r.callback.OnDiscoveryFiled(f, newID)
 		}
 	}
@@ -414,6 +876,49 @@ func (r *Runner) runPostPipeline(beadID string) {
 	}
 }
 
+// closeParent closes the top-level parent bead when every task completed
+// and validation passed or is off. Otherwise, or when closing fails (a
+// warning, not a campaign failure), it records why in state.ParentOpen.
+func (r *Runner) closeParent(parentID string, state *State) {
+	state.ParentOpen = parentOpenReason(*state)
+	if state.ParentOpen != "" {
+		return
+	}
+	if err := r.beads.Close(parentID); err != nil {
+		r.logWarning("campaign: warning: close parent %s: %v\n", parentID, err)
+		state.ParentOpen = fmt.Sprintf("closing it failed: %v", err)
+		return
+	}
+	if o, ok := r.callback.(ParentCloseObserver); ok {
+		o.OnParentClosed(parentID)
+	}
+}
+
+// parentOpenReason says why a finished campaign's parent must stay open,
+// or returns "" when every task completed and validation did not fail.
+func parentOpenReason(state State) string {
+	var failed, skipped int
+	for _, t := range state.Tasks {
+		switch t.Status {
+		case TaskFailed:
+			failed++
+		case TaskSkipped:
+			skipped++
+		}
+	}
+	var parts []string
+	if failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d task(s) failed", failed))
+	}
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d task(s) skipped", skipped))
+	}
+	if state.Validation != nil && state.Validation.Status != TaskCompleted {
+		parts = append(parts, "validation failed")
+	}
+	return strings.Join(parts, ", ")
+}
+
 // allComplete returns true when every task has finished (completed or skipped).
 func (r *Runner) allComplete(state State) bool {
 	for _, task := range state.Tasks {
@@ -424,18 +929,32 @@ func (r *Runner) allComplete(state State) bool {
 	return true
 }
 
-// runValidation runs a validation pipeline for the parent bead.
+// validationBeadID returns the ID a parent's validation pipeline runs
+// under, so it gets a worktree, worklog, and checkpoint of its own.
+func validationBeadID(parentID string) string {
+	return parentID + "-validation"
+}
+
+// runValidation runs the ValidationPhases pipeline for the parent bead,
+// reporting its phases to the callback when it is a ValidationPhaseObserver.
+// The result is keyed by the parent's ID.
 func (r *Runner) runValidation(ctx context.Context, parentID string, _ State) TaskResult {
 	input := orchestrator.PipelineInput{
-		BeadID: parentID,
-		Title:  "Feature validation: " + parentID,
+		BeadID:     validationBeadID(parentID),
+		Title:      "Feature validation: " + parentID,
+		BaseBranch: r.config.BaseBranch,
+		Phases:     r.config.ValidationPhases,
+	}
+	if o, ok := r.callback.(ValidationPhaseObserver); ok {
+		input.StatusCallback = o.OnValidationPhase
 	}
 	output, err := r.pipeline.RunPipeline(ctx, input)
 	if err != nil {
 		return TaskResult{
-			BeadID: parentID,
-			Status: TaskFailed,
-			Error:  err.Error(),
+			BeadID:       parentID,
+			Status:       TaskFailed,
+			PhaseResults: output.PhaseResults,
+			Error:        err.Error(),
 		}
 	}
 	return TaskResult{
@@ -445,16 +964,47 @@ func (r *Runner) runValidation(ctx context.Context, parentID string, _ State) Ta
 	}
 }
 
+// recordValidation appends the validation result to the parent's archived
+// worklog and, on failure, comments on the parent bead. Both are best-effort.
+func (r *Runner) recordValidation(parentID string, result TaskResult) {
+	if r.config.Worklog != nil {
+		entries := make([]worklog.PhaseEntry, 0, len(result.PhaseResults)+1)
+		for _, pr := range result.PhaseResults {
+			entries = append(entries, worklog.PhaseEntry{
+				Name:      "validation: " + pr.PhaseName,
+				Status:    string(pr.Signal.Status),
+				Verdict:   pr.Signal.Feedback,
+				Usage:     pr.Usage.String(),
+				Timestamp: pr.Timestamp,
+			})
+		}
+		verdict := "All validation phases passed."
+		if result.Error != "" {
+			verdict = result.Error
+		}
+		entries = append(entries, worklog.PhaseEntry{
+			Name:      "feature validation",
+			Status:    string(result.Status),
+			Verdict:   verdict,
+			Timestamp: time.Now(),
+		})
+		for _, e := range entries {
+			if err := r.config.Worklog.AppendArchived(parentID, e); err != nil {
+				r.logWarning("campaign: warning: recording validation for %s: %v\n", parentID, err)
+				break
+			}
+		}
+	}
+
+	if c, ok := r.beads.(BeadCommenter); ok && result.Status != TaskCompleted {
+		text := "capsule: feature validation failed: " + result.Error
+		if err := c.Comment(parentID, text); err != nil {
+			r.logWarning("campaign: warning: comment on %s: %v\n", parentID, err)
+		}
+	}
+}
+
 // severityToPriority maps finding severity to bead priority.
 func severityToPriority(severity string) int {
-	switch severity {
-	case "critical":
-		return 0
-	case "major":
-		return 1
-	case "minor":
-		return 2
-	default:
-		return 3
-	}
+	return provider.SeverityPriority(severity)
 }
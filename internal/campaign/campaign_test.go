@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/smileynet/capsule/internal/orchestrator"
 	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/worklog"
 )
 
 // --- Test mocks ---
@@ -37,15 +42,22 @@ type mockBeadClient struct {
 	children    []BeadInfo
 	childrenMap map[string][]BeadInfo // Per-parent children for recursive tests.
 	childErr    error
+	later       []BeadInfo // Returned by ReadyChildren after its first call, when set.
+	listed      int
 	showInfo    map[string]BeadInfo
 	showErr     error
 	closed      []string
 	closeErr    error
 	created     []BeadInput
 	createID    string
+	comments    map[string][]string
 }
 
 func (m *mockBeadClient) ReadyChildren(parentID string) ([]BeadInfo, error) {
+	m.listed++
+	if m.later != nil && m.listed > 1 {
+		return m.later, m.childErr
+	}
 	if m.childrenMap != nil {
 		return m.childrenMap[parentID], m.childErr
 	}
@@ -71,6 +83,26 @@ func (m *mockBeadClient) Create(input BeadInput) (string, error) {
 	return m.createID, nil
 }
 
+func (m *mockBeadClient) Comment(id, text string) error {
+	if m.comments == nil {
+		m.comments = make(map[string][]string)
+	}
+	m.comments[id] = append(m.comments[id], text)
+	return nil
+}
+
+type mockWorklog struct {
+	entries map[string][]worklog.PhaseEntry
+}
+
+func (m *mockWorklog) AppendArchived(beadID string, entry worklog.PhaseEntry) error {
+	if m.entries == nil {
+		m.entries = make(map[string][]worklog.PhaseEntry)
+	}
+	m.entries[beadID] = append(m.entries[beadID], entry)
+	return nil
+}
+
 type mockStateStore struct {
 	saved   []State
 	loaded  map[string]State
@@ -101,29 +133,56 @@ type pausedCall struct {
 
 type mockCallback struct {
 	campaignStarted  bool
+	planned          []BeadInfo
+	tasksSkipped     map[string]string
+	trippedCalls     []BreakerTrip
+	parentsClosed    []string
 	tasksStarted     []string
 	tasksCompleted   []TaskResult
 	tasksFailed      []string
+	failedIDs        []string // From OnTaskFail.
 	pausedCalls      []pausedCall
 	discoveriesFiled []string
 	validationStart  bool
+	validationPhases []orchestrator.StatusUpdate
 	validationDone   bool
 	campaignDone     bool
 }
 
-func (m *mockCallback) OnCampaignStart(string, []BeadInfo) { m.campaignStarted = true }
-func (m *mockCallback) OnTaskStart(id string)              { m.tasksStarted = append(m.tasksStarted, id) }
-func (m *mockCallback) OnTaskComplete(r TaskResult)        { m.tasksCompleted = append(m.tasksCompleted, r) }
-func (m *mockCallback) OnTaskFail(id string, _ error)      { m.tasksFailed = append(m.tasksFailed, id) }
+func (m *mockCallback) OnCampaignStart(_ string, tasks []BeadInfo) {
+	m.campaignStarted = true
+	if m.planned == nil {
+		m.planned = tasks
+	}
+}
+func (m *mockCallback) OnTaskStart(id string)       { m.tasksStarted = append(m.tasksStarted, id) }
+func (m *mockCallback) OnTaskComplete(r TaskResult) { m.tasksCompleted = append(m.tasksCompleted, r) }
+func (m *mockCallback) OnTaskFailed(r TaskResult, _ error) {
+	m.tasksFailed = append(m.tasksFailed, r.BeadID)
+}
+func (m *mockCallback) OnTaskFail(id string, _ error) { m.failedIDs = append(m.failedIDs, id) }
+func (m *mockCallback) OnTaskSkipped(id, reason string) {
+	if m.tasksSkipped == nil {
+		m.tasksSkipped = make(map[string]string)
+	}
+	m.tasksSkipped[id] = reason
+}
 func (m *mockCallback) OnCampaignPaused(beadID, reason, details string) {
 	m.pausedCalls = append(m.pausedCalls, pausedCall{beadID, reason, details})
 }
 func (m *mockCallback) OnDiscoveryFiled(f provider.Finding, newID string) {
 	m.discoveriesFiled = append(m.discoveriesFiled, newID)
 }
+func (m *mockCallback) OnCircuitBroken(trip BreakerTrip) {
+	m.trippedCalls = append(m.trippedCalls, trip)
+}
+func (m *mockCallback) OnParentClosed(id string)        { m.parentsClosed = append(m.parentsClosed, id) }
 func (m *mockCallback) OnValidationStart()              { m.validationStart = true }
 func (m *mockCallback) OnValidationComplete(TaskResult) { m.validationDone = true }
-func (m *mockCallback) OnCampaignComplete(State)        { m.campaignDone = true }
+func (m *mockCallback) OnValidationPhase(su orchestrator.StatusUpdate) {
+	m.validationPhases = append(m.validationPhases, su)
+}
+func (m *mockCallback) OnCampaignComplete(State) { m.campaignDone = true }
 
 func passOutput() orchestrator.PipelineOutput {
 	return orchestrator.PipelineOutput{Completed: true}
@@ -146,7 +205,7 @@ func TestRun_HappyPath(t *testing.T) {
 	}
 	store := &mockStateStore{}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "abort", CircuitBreaker: 3}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3}}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -216,7 +275,7 @@ func TestRun_AbortOnFailure(t *testing.T) {
 	}
 	store := &mockStateStore{}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "abort", CircuitBreaker: 3}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3}}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -252,7 +311,7 @@ func TestRun_ContinueOnFailure(t *testing.T) {
 	}
 	store := &mockStateStore{}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "continue", CircuitBreaker: 3}
+	config := Config{FailureMode: "continue", CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3}}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -288,7 +347,7 @@ func TestRun_CircuitBreaker(t *testing.T) {
 	}
 	store := &mockStateStore{}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "continue", CircuitBreaker: 2}
+	config := Config{FailureMode: "continue", CircuitBreaker: CircuitBreaker{Setup: 2, Signal: 2}}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -305,6 +364,200 @@ func TestRun_CircuitBreaker(t *testing.T) {
 	}
 }
 
+func TestRun_CircuitBreakerWithoutTripObserver(t *testing.T) {
+	// Given a callback that implements only Callback, not
+	// BreakerTripObserver, and a breaker that trips after two failures
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{{}, {}},
+		errs:    []error{fmt.Errorf("fail 1"), fmt.Errorf("fail 2")},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}, {ID: "cap-3"}}}
+	cb := &mockCallback{}
+	config := Config{FailureMode: "continue", CircuitBreaker: CircuitBreaker{Setup: 2}}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, config, struct{ Callback }{cb})
+
+	// When the breaker trips
+	err := r.Run(context.Background(), "cap-feature")
+
+	// Then the campaign stops without reporting the trip to the callback
+	if !errors.Is(err, ErrCircuitBroken) {
+		t.Fatalf("Run() error = %v, want ErrCircuitBroken", err)
+	}
+	if len(cb.trippedCalls) != 0 {
+		t.Errorf("OnCircuitBroken calls = %d, want 0", len(cb.trippedCalls))
+	}
+	if len(cb.tasksStarted) != 2 {
+		t.Errorf("tasks started = %d, want 2", len(cb.tasksStarted))
+	}
+}
+
+func TestRun_TaskFailureWithoutFailureObserver(t *testing.T) {
+	// Given a callback that implements only Callback, not
+	// TaskFailureObserver, and a task that fails
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{{}},
+		errs:    []error{fmt.Errorf("fail 1")},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}}}
+	cb := &mockCallback{}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{FailureMode: "continue"}, struct{ Callback }{cb})
+
+	// When the campaign runs
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then the failure is reported through OnTaskFail with the bead ID
+	if len(cb.tasksFailed) != 0 || !slices.Equal(cb.failedIDs, []string{"cap-1"}) {
+		t.Errorf("OnTaskFailed calls = %v, OnTaskFail IDs = %v; want one legacy report for cap-1", cb.tasksFailed, cb.failedIDs)
+	}
+}
+
+func TestRun_CircuitBreakerByFailureKind(t *testing.T) {
+	setupErr := func() error {
+		return &orchestrator.PipelineError{Phase: "execute", Err: errors.New("provider auth expired")}
+	}
+	signalErr := func() error {
+		return &orchestrator.PipelineError{Phase: "test-review", Attempt: 1,
+			Signal: provider.Signal{Status: provider.StatusError, Feedback: "broken"}}
+	}
+	exhausted := orchestrator.PipelineOutput{PhaseResults: []orchestrator.PhaseResult{{
+		PhaseName: "code-review", Attempt: 3,
+		Signal: provider.Signal{Status: provider.StatusNeedsWork},
+	}}}
+	exhaustedErr := func() error {
+		return &orchestrator.PipelineError{Phase: "code-review", Attempt: 3, Err: errors.New("max retries (3) exceeded")}
+	}
+
+	tests := []struct {
+		name        string
+		breaker     CircuitBreaker
+		tasks       int
+		outputs     []orchestrator.PipelineOutput
+		errs        []error
+		wantStarted int
+		wantReason  string // substring; empty means the breaker must not trip
+		wantCounts  FailureCounts
+	}{
+		{
+			name:        "setup failures trip setup threshold",
+			breaker:     CircuitBreaker{Setup: 2, Signal: 5},
+			tasks:       3,
+			outputs:     []orchestrator.PipelineOutput{{}, {}},
+			errs:        []error{setupErr(), setupErr()},
+			wantStarted: 2,
+			wantReason:  "2 consecutive provider/setup failures",
+			wantCounts:  FailureCounts{Setup: 2},
+		},
+		{
+			name:        "signal failures trip signal threshold",
+			breaker:     CircuitBreaker{Setup: 5, Signal: 2},
+			tasks:       3,
+			outputs:     []orchestrator.PipelineOutput{{}, exhausted},
+			errs:        []error{signalErr(), exhaustedErr()},
+			wantStarted: 2,
+			wantReason:  "2 consecutive NEEDS_WORK/ERROR failures",
+			wantCounts:  FailureCounts{Signal: 2},
+		},
+		{
+			name:        "mixed failures count independently",
+			breaker:     CircuitBreaker{Setup: 2, Signal: 2},
+			tasks:       4,
+			outputs:     []orchestrator.PipelineOutput{{}, {}, {}},
+			errs:        []error{setupErr(), signalErr(), fmt.Errorf("plain failure")},
+			wantStarted: 3,
+			wantReason:  "2 consecutive provider/setup failures",
+			wantCounts:  FailureCounts{Setup: 2, Signal: 1},
+		},
+		{
+			name:        "total mode counts failures across successes",
+			breaker:     CircuitBreaker{Mode: BreakerTotal, Setup: 2},
+			tasks:       4,
+			outputs:     []orchestrator.PipelineOutput{{}, passOutput(), {}},
+			errs:        []error{setupErr(), nil, setupErr()},
+			wantStarted: 3,
+			wantReason:  "2 total provider/setup failures",
+			wantCounts:  FailureCounts{Setup: 2},
+		},
+		{
+			name:        "zero threshold disables that kind",
+			breaker:     CircuitBreaker{Setup: 0, Signal: 2},
+			tasks:       3,
+			outputs:     []orchestrator.PipelineOutput{{}, {}, {}},
+			errs:        []error{setupErr(), setupErr(), setupErr()},
+			wantStarted: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given tasks whose pipelines fail with the configured errors
+			children := make([]BeadInfo, tt.tasks)
+			for i := range children {
+				children[i] = BeadInfo{ID: fmt.Sprintf("cap-%d", i+1)}
+			}
+			pipeline := &mockPipeline{outputs: tt.outputs, errs: tt.errs}
+			store := &mockStateStore{}
+			cb := &mockCallback{}
+			config := Config{FailureMode: "continue", CircuitBreaker: tt.breaker}
+			r := NewRunner(pipeline, &mockBeadClient{children: children}, store, config, cb)
+
+			// When Run is called
+			err := r.Run(context.Background(), "cap-feature")
+
+			// Then the expected number of tasks ran
+			if len(cb.tasksStarted) != tt.wantStarted {
+				t.Errorf("tasks started = %d, want %d", len(cb.tasksStarted), tt.wantStarted)
+			}
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(cb.trippedCalls) != 0 {
+					t.Errorf("breaker tripped: %+v", cb.trippedCalls)
+				}
+				return
+			}
+
+			// Then the breaker tripped with the reason and per-kind counts
+			if !errors.Is(err, ErrCircuitBroken) {
+				t.Fatalf("expected ErrCircuitBroken, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantReason) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantReason)
+			}
+			if len(cb.trippedCalls) != 1 {
+				t.Fatalf("OnCircuitBroken calls = %d, want 1", len(cb.trippedCalls))
+			}
+			call := cb.trippedCalls[0]
+			if !strings.Contains(call.Reason, tt.wantReason) {
+				t.Errorf("reason = %q, want it to contain %q", call.Reason, tt.wantReason)
+			}
+			if call.Counts != tt.wantCounts {
+				t.Errorf("counts = %+v, want %+v", call.Counts, tt.wantCounts)
+			}
+			tripper := fmt.Sprintf("cap-%d", tt.wantStarted)
+			if call.BeadID != tripper || len(call.Failures) == 0 || call.Failures[len(call.Failures)-1].BeadID != tripper {
+				t.Errorf("trip = %+v, want it tripped by %s", call, tripper)
+			}
+
+			// Then the persisted state records the trip and skips the rest
+			last := store.saved[len(store.saved)-1]
+			if last.TripReason != call.Reason || last.TrippedBy != tripper {
+				t.Errorf("saved trip = (%q, %q), want (%q, %q)", last.TripReason, last.TrippedBy, call.Reason, tripper)
+			}
+			if last.Status != CampaignFailed {
+				t.Errorf("saved Status = %q, want %q", last.Status, CampaignFailed)
+			}
+			for _, task := range last.Tasks[tt.wantStarted:] {
+				if task.Status != TaskSkipped || task.SkipReason != breakerSkipReason {
+					t.Errorf("task %s = %s (%q), want skipped by the breaker", task.BeadID, task.Status, task.SkipReason)
+				}
+			}
+		})
+	}
+}
+
 func TestRun_CircuitBreakerResets(t *testing.T) {
 	// Given: fail, pass, fail (circuit_breaker=2 — should NOT trip because pass resets)
 	pipeline := &mockPipeline{
@@ -318,7 +571,7 @@ func TestRun_CircuitBreakerResets(t *testing.T) {
 			{ID: "cap-3", Title: "Task 3"},
 		},
 	}
-	config := Config{FailureMode: "continue", CircuitBreaker: 2}
+	config := Config{FailureMode: "continue", CircuitBreaker: CircuitBreaker{Setup: 2, Signal: 2}}
 
 	r := NewRunner(pipeline, beads, &mockStateStore{}, config, &mockCallback{})
 
@@ -359,7 +612,7 @@ func TestRun_DiscoveryFiling(t *testing.T) {
 	cb := &mockCallback{}
 	config := Config{
 		FailureMode:     "abort",
-		CircuitBreaker:  3,
+		CircuitBreaker:  CircuitBreaker{Setup: 3, Signal: 3},
 		DiscoveryFiling: true,
 	}
 
@@ -388,6 +641,50 @@ func TestRun_DiscoveryFiling(t *testing.T) {
 	}
 }
 
+func TestRun_DiscoveryFilingThresholdAndDedup(t *testing.T) {
+	// Given two tasks reporting overlapping findings and a major threshold
+	findings := []provider.Finding{
+		{Title: "SQL injection", Severity: "critical", Description: "unsafe query"},
+		{Title: "Missing nil check", Severity: "minor"},
+		{Title: "Races on cache", Severity: "major"},
+	}
+	output := orchestrator.PipelineOutput{Completed: true, PhaseResults: []orchestrator.PhaseResult{{
+		PhaseName: "execute",
+		Signal:    provider.Signal{Status: provider.StatusPass, Summary: "done", Findings: findings},
+	}}}
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{output, output},
+		errs:    []error{nil, nil},
+	}
+	beads := &mockBeadClient{
+		children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}},
+		createID: "cap-new",
+	}
+	cb := &mockCallback{}
+	config := Config{FailureMode: "abort", DiscoveryFiling: true, MinSeverity: "major"}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, config, cb)
+
+	// When Run is called
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then each finding at or above major is filed once, with its description
+	var titles []string
+	for _, in := range beads.created {
+		titles = append(titles, in.Title)
+	}
+	if want := []string{"SQL injection", "Races on cache"}; !slices.Equal(titles, want) {
+		t.Fatalf("created = %v, want %v", titles, want)
+	}
+	if beads.created[0].Description != "unsafe query" || beads.created[0].ParentID != "cap-feature" {
+		t.Errorf("created[0] = %+v, want description and parent set", beads.created[0])
+	}
+	if len(cb.discoveriesFiled) != 2 {
+		t.Errorf("discoveries filed = %d, want 2", len(cb.discoveriesFiled))
+	}
+}
+
 func TestRun_DiscoveryFilingDisabled(t *testing.T) {
 	// Given discovery filing is disabled
 	pipeline := &mockPipeline{
@@ -447,7 +744,7 @@ func TestRun_Resume(t *testing.T) {
 		},
 	}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "abort", CircuitBreaker: 3}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3}, Resume: true}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -481,7 +778,7 @@ func TestRun_Validation(t *testing.T) {
 	cb := &mockCallback{}
 	config := Config{
 		FailureMode:      "abort",
-		CircuitBreaker:   3,
+		CircuitBreaker:   CircuitBreaker{Setup: 3, Signal: 3},
 		ValidationPhases: "default",
 	}
 
@@ -502,7 +799,288 @@ func TestRun_Validation(t *testing.T) {
 	}
 	// And 2 pipeline calls were made (1 task + 1 validation)
 	if len(pipeline.calls) != 2 {
-		t.Errorf("pipeline calls = %d, want 2", len(pipeline.calls))
+		t.Fatalf("pipeline calls = %d, want 2", len(pipeline.calls))
+	}
+	// And validation ran the validation phases under its own bead ID
+	val := pipeline.calls[1]
+	if val.BeadID != "cap-feature-validation" {
+		t.Errorf("validation BeadID = %q, want %q", val.BeadID, "cap-feature-validation")
+	}
+	if val.Phases != "default" {
+		t.Errorf("validation Phases = %q, want %q", val.Phases, "default")
+	}
+	// And its status updates reach OnValidationPhase
+	if val.StatusCallback == nil {
+		t.Fatal("validation StatusCallback not set")
+	}
+	val.StatusCallback(orchestrator.StatusUpdate{Phase: "test-writer", Status: orchestrator.PhaseFailed})
+	if len(cb.validationPhases) != 1 || cb.validationPhases[0].Phase != "test-writer" {
+		t.Errorf("validation phases = %+v, want one test-writer update", cb.validationPhases)
+	}
+	// And tasks keep the default phases and callback
+	if pipeline.calls[0].Phases != "" || pipeline.calls[0].StatusCallback != nil {
+		t.Errorf("task input = %+v, want no phase or callback override", pipeline.calls[0])
+	}
+}
+
+func TestRun_ValidationWithoutPhaseObserver(t *testing.T) {
+	// Given a callback that implements only Callback, not
+	// ValidationPhaseObserver
+	pipeline := &mockPipeline{outputs: []orchestrator.PipelineOutput{passOutput(), passOutput()}}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}}}
+	cb := &mockCallback{}
+	config := Config{FailureMode: "abort", ValidationPhases: "default"}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, config, struct{ Callback }{cb})
+
+	// When the campaign runs to validation
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then validation still runs and reports to the callback, and its phase
+	// updates go to the pipeline's own status callback
+	if !cb.validationStart || !cb.validationDone {
+		t.Errorf("validation start = %v, done = %v; want both", cb.validationStart, cb.validationDone)
+	}
+	if len(pipeline.calls) != 2 || pipeline.calls[1].StatusCallback != nil {
+		t.Errorf("pipeline calls = %+v, want validation without a StatusCallback override", pipeline.calls)
+	}
+}
+
+func TestRun_ValidationRecorded(t *testing.T) {
+	tests := []struct {
+		name         string
+		valErr       error
+		wantErr      bool
+		wantStatus   CampaignStatus
+		wantValState TaskStatus
+		wantComment  bool
+	}{
+		{name: "passed", wantStatus: CampaignCompleted, wantValState: TaskCompleted},
+		{
+			name:         "failed",
+			valErr:       &orchestrator.PipelineError{Phase: "feature-review", Attempt: 1},
+			wantErr:      true,
+			wantStatus:   CampaignFailed,
+			wantValState: TaskFailed,
+			wantComment:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a passing task followed by a validation pipeline
+			valOutput := orchestrator.PipelineOutput{PhaseResults: []orchestrator.PhaseResult{{
+				PhaseName: "feature-review",
+				Signal:    provider.Signal{Status: provider.StatusPass, Feedback: "looks good"},
+			}}}
+			pipeline := &mockPipeline{
+				outputs: []orchestrator.PipelineOutput{passOutput(), valOutput},
+				errs:    []error{nil, tt.valErr},
+			}
+			beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1", Title: "Task 1"}}}
+			store := &mockStateStore{}
+			wl := &mockWorklog{}
+			config := Config{
+				FailureMode:      "continue",
+				ValidationPhases: "default",
+				Worklog:          wl,
+			}
+			r := NewRunner(pipeline, beads, store, config, &mockCallback{})
+
+			// When Run is called
+			err := r.Run(context.Background(), "cap-feature")
+
+			// Then only a failed validation makes Run fail
+			if got := errors.Is(err, ErrValidation); got != tt.wantErr {
+				t.Fatalf("Run() error = %v, want ErrValidation: %v", err, tt.wantErr)
+			}
+			// And the result is persisted under the state's validation key
+			final := store.saved[len(store.saved)-1]
+			if final.Status != tt.wantStatus {
+				t.Errorf("state status = %q, want %q", final.Status, tt.wantStatus)
+			}
+			if final.Validation == nil || final.Validation.Status != tt.wantValState {
+				t.Fatalf("state validation = %+v, want status %q", final.Validation, tt.wantValState)
+			}
+			if len(final.Validation.PhaseResults) != 1 {
+				t.Errorf("validation phase results = %d, want 1", len(final.Validation.PhaseResults))
+			}
+			// And the parent's worklog gets the phase and overall entries
+			entries := wl.entries["cap-feature"]
+			if len(entries) != 2 || entries[0].Name != "validation: feature-review" || entries[1].Status != string(tt.wantValState) {
+				t.Errorf("worklog entries = %+v", entries)
+			}
+			// And only a failure comments on the parent, which stays open
+			if got := len(beads.comments["cap-feature"]) > 0; got != tt.wantComment {
+				t.Errorf("commented = %v, want %v (%v)", got, tt.wantComment, beads.comments)
+			}
+			if slices.Contains(beads.closed, "cap-feature") {
+				t.Error("parent bead was closed")
+			}
+		})
+	}
+}
+
+func TestRun_ValidationFailureWithoutCommenter(t *testing.T) {
+	// Given a bead client that implements only BeadClient, not
+	// BeadCommenter, and a validation pipeline that fails
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{passOutput(), {}},
+		errs:    []error{nil, &orchestrator.PipelineError{Phase: "feature-review", Attempt: 1}},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}}}
+	store := &mockStateStore{}
+	config := Config{FailureMode: "continue", ValidationPhases: "default"}
+	r := NewRunner(pipeline, struct{ BeadClient }{beads}, store, config, &mockCallback{})
+
+	// When Run is called
+	err := r.Run(context.Background(), "cap-feature")
+
+	// Then validation fails and is recorded without a comment on the parent
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Run() error = %v, want ErrValidation", err)
+	}
+	if len(beads.comments) != 0 {
+		t.Errorf("comments = %v, want none", beads.comments)
+	}
+	final := store.saved[len(store.saved)-1]
+	if final.Validation == nil || final.Validation.Status != TaskFailed {
+		t.Errorf("state validation = %+v, want failed", final.Validation)
+	}
+}
+
+func TestRun_CloseParentOnSuccess(t *testing.T) {
+	failed := &orchestrator.PipelineError{Phase: "feature-review", Attempt: 1}
+	tests := []struct {
+		name        string
+		closeParent bool
+		errs        []error // Task 1, task 2, validation.
+		closeErr    error
+		wantErr     bool
+		wantClosed  bool
+		wantOpen    string
+	}{
+		{name: "every task and validation pass", closeParent: true, errs: []error{nil, nil, nil}, wantClosed: true},
+		{name: "off by default", errs: []error{nil, nil, nil}},
+		{name: "a failed task keeps it open", closeParent: true, errs: []error{errors.New("boom"), nil}, wantOpen: "1 task(s) failed"},
+		{name: "failed validation keeps it open", closeParent: true, errs: []error{nil, nil, failed}, wantErr: true, wantOpen: "validation failed"},
+		{name: "a close error is a warning", closeParent: true, errs: []error{nil, nil, nil}, closeErr: errors.New("bd down"), wantOpen: "closing it failed: bd down"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given two tasks and validation with the configured outcomes
+			pipeline := &mockPipeline{
+				outputs: []orchestrator.PipelineOutput{passOutput(), passOutput(), passOutput()},
+				errs:    tt.errs,
+			}
+			beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}}, closeErr: tt.closeErr}
+			store := &mockStateStore{}
+			cb := &mockCallback{}
+			config := Config{FailureMode: "continue", ValidationPhases: "default", CloseParent: tt.closeParent}
+			r := NewRunner(pipeline, beads, store, config, cb)
+
+			// When Run is called
+			err := r.Run(context.Background(), "cap-feature")
+
+			// Then the parent is closed only after full success
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := slices.Equal(cb.parentsClosed, []string{"cap-feature"}); got != tt.wantClosed {
+				t.Errorf("OnParentClosed calls = %v, want closed %v", cb.parentsClosed, tt.wantClosed)
+			}
+			// And the saved state says why it stayed open
+			if got := store.saved[len(store.saved)-1].ParentOpen; got != tt.wantOpen {
+				t.Errorf("ParentOpen = %q, want %q", got, tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestRun_CloseParentWithoutCloseObserver(t *testing.T) {
+	// Given a callback that implements only Callback, not ParentCloseObserver
+	pipeline := &mockPipeline{outputs: []orchestrator.PipelineOutput{passOutput()}}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}}}
+	cb := &mockCallback{}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{FailureMode: "abort", CloseParent: true}, struct{ Callback }{cb})
+
+	// When the campaign succeeds
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then the parent is still closed, and the campaign completes
+	if !slices.Contains(beads.closed, "cap-feature") || !cb.campaignDone {
+		t.Errorf("closed = %v, done = %v; want cap-feature closed and the campaign complete", beads.closed, cb.campaignDone)
+	}
+}
+
+func TestRun_SumsUsage(t *testing.T) {
+	// Given two tasks and a validation run that all report usage
+	usageOutput := func(in, out int, cost float64) orchestrator.PipelineOutput {
+		return orchestrator.PipelineOutput{PhaseResults: []orchestrator.PhaseResult{{
+			PhaseName: "execute",
+			Signal:    provider.Signal{Status: provider.StatusPass},
+			Usage:     provider.Usage{InputTokens: in, OutputTokens: out, CostUSD: cost},
+		}}}
+	}
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{usageOutput(100, 10, 0.5), usageOutput(200, 20, 0.25), usageOutput(5, 1, 0)},
+		errs:    []error{nil, nil, nil},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}}}
+	store := &mockStateStore{}
+	r := NewRunner(pipeline, beads, store, Config{FailureMode: "continue", ValidationPhases: "default"}, &mockCallback{})
+
+	// When Run is called
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then the saved state sums usage across every pipeline, including validation
+	final := store.saved[len(store.saved)-1]
+	want := provider.Usage{InputTokens: 305, OutputTokens: 31, CostUSD: 0.75}
+	if final.Usage != want {
+		t.Errorf("state usage = %+v, want %+v", final.Usage, want)
+	}
+}
+
+func TestRun_SumsSubCampaignUsageAtTopLevel(t *testing.T) {
+	// Given an epic with a task and a feature whose task reports usage
+	usageOutput := func(in int) orchestrator.PipelineOutput {
+		return orchestrator.PipelineOutput{PhaseResults: []orchestrator.PhaseResult{{
+			PhaseName: "execute",
+			Signal:    provider.Signal{Status: provider.StatusPass},
+			Usage:     provider.Usage{InputTokens: in, Model: "opus"},
+		}}}
+	}
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{usageOutput(10), usageOutput(20)},
+		errs:    []error{nil, nil},
+	}
+	beads := &mockBeadClient{childrenMap: map[string][]BeadInfo{
+		"epic-1":   {{ID: "epic-1.1", Type: "task"}, {ID: "epic-1.2", Type: "feature"}},
+		"epic-1.2": {{ID: "epic-1.2.1", Type: "task"}},
+	}}
+	store := &mockStateStore{}
+	r := NewRunner(pipeline, beads, store, Config{FailureMode: "continue"}, &mockCallback{})
+
+	// When Run is called on the epic
+	if err := r.Run(context.Background(), "epic-1"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then the feature's state has its own usage and the epic's has both
+	got := make(map[string]provider.Usage)
+	for _, s := range store.saved {
+		got[s.ParentBeadID] = s.Usage
+	}
+	if want := (provider.Usage{InputTokens: 20, Model: "opus"}); got["epic-1.2"] != want {
+		t.Errorf("feature usage = %+v, want %+v", got["epic-1.2"], want)
+	}
+	if want := (provider.Usage{InputTokens: 30, Model: "opus"}); got["epic-1"] != want {
+		t.Errorf("epic usage = %+v, want %+v", got["epic-1"], want)
 	}
 }
 
@@ -557,7 +1135,7 @@ func TestRun_CrossRunContext(t *testing.T) {
 	}
 	config := Config{
 		FailureMode:     "abort",
-		CircuitBreaker:  3,
+		CircuitBreaker:  CircuitBreaker{Setup: 3, Signal: 3},
 		CrossRunContext: true,
 	}
 
@@ -589,6 +1167,65 @@ func TestRun_CrossRunContext(t *testing.T) {
 	}
 }
 
+func TestRun_PassesBeadLabels(t *testing.T) {
+	// Given two children, only one of which carries provider labels
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{passOutput(), passOutput()},
+		errs:    []error{nil, nil},
+	}
+	labels := []string{"capsule:provider=kiro", "capsule:timeout=20m"}
+	beads := &mockBeadClient{
+		children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}},
+		showInfo: map[string]BeadInfo{
+			"cap-1": {ID: "cap-1", Title: "Docs tweak"},
+			"cap-2": {ID: "cap-2", Title: "Big refactor", Labels: labels},
+		},
+	}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{FailureMode: "abort"}, &mockCallback{})
+
+	// When Run is called
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then each pipeline input carries only its own bead's labels
+	if len(pipeline.calls) != 2 {
+		t.Fatalf("pipeline calls = %d, want 2", len(pipeline.calls))
+	}
+	if got := pipeline.calls[0].Bead.Labels; len(got) != 0 {
+		t.Errorf("cap-1 labels = %v, want none", got)
+	}
+	if got := pipeline.calls[1].Bead.Labels; !slices.Equal(got, labels) {
+		t.Errorf("cap-2 labels = %v, want %v", got, labels)
+	}
+}
+
+func TestRun_PassesBaseBranch(t *testing.T) {
+	// Given a campaign with a base branch, two children, and validation
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{passOutput(), passOutput(), passOutput()},
+		errs:    []error{nil, nil, nil},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}}}
+	config := Config{FailureMode: "abort", ValidationPhases: "default", BaseBranch: "develop"}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, config, &mockCallback{})
+
+	// When Run is called
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then every task and the validation start from the base branch
+	if len(pipeline.calls) != 3 {
+		t.Fatalf("pipeline calls = %d, want 3", len(pipeline.calls))
+	}
+	for _, in := range pipeline.calls {
+		if in.BaseBranch != "develop" {
+			t.Errorf("%s BaseBranch = %q, want %q", in.BeadID, in.BaseBranch, "develop")
+		}
+	}
+}
+
 func TestRun_ReadyChildrenError(t *testing.T) {
 	// Given ReadyChildren returns an error
 	beads := &mockBeadClient{childErr: fmt.Errorf("bd not found")}
@@ -615,7 +1252,7 @@ func TestRun_PipelinePausedSetsCampaignPaused(t *testing.T) {
 	}
 	store := &mockStateStore{}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "abort", CircuitBreaker: 3}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3}}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -677,7 +1314,7 @@ func TestRun_ResumeFromPausedState(t *testing.T) {
 		},
 	}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "abort", CircuitBreaker: 3}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3}, Resume: true}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -700,6 +1337,123 @@ func TestRun_ResumeFromPausedState(t *testing.T) {
 	}
 }
 
+func TestRun_ResumeOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		resume       bool
+		retryFailed  bool
+		wantStarted  []string
+		wantSiblings []string // Sibling context cap-4 receives.
+	}{
+		{name: "without resume starts over", wantStarted: []string{"cap-2", "cap-3", "cap-4"}, wantSiblings: []string{"cap-2", "cap-3"}},
+		{name: "resume runs only unfinished tasks", resume: true, wantStarted: []string{"cap-4"}, wantSiblings: []string{"cap-1"}},
+		{
+			name: "resume with retry failed reruns failed and skipped tasks", resume: true, retryFailed: true,
+			wantStarted: []string{"cap-2", "cap-3", "cap-4"}, wantSiblings: []string{"cap-1", "cap-2", "cap-3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given saved state where cap-1 completed, cap-2 failed, cap-3 was
+			// skipped behind it, and cap-4 never ran; closed cap-1 is no longer ready
+			pipeline := &mockPipeline{
+				outputs: []orchestrator.PipelineOutput{passOutput(), passOutput(), passOutput()},
+				errs:    []error{nil, nil, nil},
+			}
+			beads := &mockBeadClient{children: []BeadInfo{
+				{ID: "cap-2"},
+				{ID: "cap-3", DependsOn: []string{"cap-2"}},
+				{ID: "cap-4"},
+			}}
+			store := &mockStateStore{loaded: map[string]State{"cap-feature": {
+				ID:             "cap-feature",
+				ParentBeadID:   "cap-feature",
+				Status:         CampaignPaused,
+				CurrentTaskIdx: 1,
+				ConsecFailures: 1,
+				Tasks: []TaskResult{
+					{BeadID: "cap-1", Status: TaskCompleted, PhaseResults: []orchestrator.PhaseResult{
+						{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusPass, Summary: "cap-1 done"}},
+					}},
+					{BeadID: "cap-2", Status: TaskFailed, Error: "boom"},
+					{BeadID: "cap-3", Status: TaskSkipped, SkipReason: "dependency cap-2 failed"},
+					{BeadID: "cap-4", Status: TaskPending},
+				},
+			}}}
+			cb := &mockCallback{}
+			config := Config{FailureMode: "continue", CrossRunContext: true, Resume: tt.resume, RetryFailed: tt.retryFailed}
+			r := NewRunner(pipeline, beads, store, config, cb)
+
+			// When the campaign runs
+			if err := r.Run(context.Background(), "cap-feature"); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			// Then only the expected tasks run
+			if !slices.Equal(cb.tasksStarted, tt.wantStarted) {
+				t.Errorf("started = %v, want %v", cb.tasksStarted, tt.wantStarted)
+			}
+			// And cap-4's sibling context includes completed tasks from the saved state
+			var siblings []string
+			for _, call := range pipeline.calls {
+				if call.BeadID != "cap-4" {
+					continue
+				}
+				for _, sc := range call.SiblingContext {
+					siblings = append(siblings, sc.BeadID)
+				}
+			}
+			if !slices.Equal(siblings, tt.wantSiblings) {
+				t.Errorf("cap-4 siblings = %v, want %v", siblings, tt.wantSiblings)
+			}
+		})
+	}
+}
+
+func TestRun_ResumeAfterCircuitBreaker(t *testing.T) {
+	// Given saved state where cap-1 and cap-2 failed and tripped the breaker,
+	// leaving cap-3 skipped
+	pipeline := &mockPipeline{outputs: []orchestrator.PipelineOutput{passOutput()}, errs: []error{nil}}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-3"}}}
+	store := &mockStateStore{loaded: map[string]State{"cap-feature": {
+		ID:             "cap-feature",
+		ParentBeadID:   "cap-feature",
+		Status:         CampaignFailed,
+		CurrentTaskIdx: 3,
+		ConsecFailures: 2,
+		ConsecByKind:   FailureCounts{Setup: 2},
+		Failures:       FailureCounts{Setup: 2},
+		TripReason:     "2 consecutive provider/setup failures (limit 2)",
+		TrippedBy:      "cap-2",
+		RecentFailures: []FailureRecord{{BeadID: "cap-1", Kind: FailureSetup}, {BeadID: "cap-2", Kind: FailureSetup}},
+		Tasks: []TaskResult{
+			{BeadID: "cap-1", Status: TaskFailed, Error: "boom"},
+			{BeadID: "cap-2", Status: TaskFailed, Error: "boom"},
+			{BeadID: "cap-3", Status: TaskSkipped, SkipReason: breakerSkipReason},
+		},
+	}}}
+	cb := &mockCallback{}
+	config := Config{FailureMode: "continue", CircuitBreaker: CircuitBreaker{Setup: 2}, Resume: true}
+	r := NewRunner(pipeline, beads, store, config, cb)
+
+	// When the campaign resumes
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then the breaker starts afresh and only the skipped task runs
+	if !slices.Equal(cb.tasksStarted, []string{"cap-3"}) {
+		t.Errorf("started = %v, want [cap-3]", cb.tasksStarted)
+	}
+	last := store.saved[len(store.saved)-1]
+	if last.TripReason != "" || last.TrippedBy != "" || last.Failures != (FailureCounts{}) {
+		t.Errorf("saved trip = (%q, %q, %+v), want cleared", last.TripReason, last.TrippedBy, last.Failures)
+	}
+	if last.Tasks[0].Status != TaskFailed || last.Tasks[2].Status != TaskCompleted {
+		t.Errorf("tasks = %+v, want cap-1 failed and cap-3 completed", last.Tasks)
+	}
+}
+
 // --- Recursive campaign tests ---
 
 func TestRun_RecursiveFeature(t *testing.T) {
@@ -728,7 +1482,7 @@ func TestRun_RecursiveFeature(t *testing.T) {
 	}
 	store := &mockStateStore{}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "abort", CircuitBreaker: 5}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 5, Signal: 5}}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -781,7 +1535,7 @@ func TestRun_MaxDepth(t *testing.T) {
 			"depth-0.1.1.1.1": {{ID: "depth-0.1.1.1.1.1", Title: "Level 5 task", Type: "task"}},
 		},
 	}
-	config := Config{FailureMode: "abort", CircuitBreaker: 5}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 5, Signal: 5}}
 	r := NewRunner(&mockPipeline{outputs: []orchestrator.PipelineOutput{passOutput()}}, beads, &mockStateStore{}, config, &mockCallback{})
 
 	// When Run is called
@@ -801,7 +1555,7 @@ func TestRun_CycleDetection(t *testing.T) {
 			"loop-a.1": {{ID: "loop-a", Title: "Cycle back to root", Type: "epic"}},
 		},
 	}
-	config := Config{FailureMode: "abort", CircuitBreaker: 5}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 5, Signal: 5}}
 	r := NewRunner(&mockPipeline{}, beads, &mockStateStore{}, config, &mockCallback{})
 
 	// When Run is called
@@ -829,7 +1583,7 @@ func TestRun_ContextCancelledResetsTask(t *testing.T) {
 	}
 	store := &mockStateStore{}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "abort", CircuitBreaker: 3}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3}}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -883,7 +1637,7 @@ func TestRun_ContextCancelledDoesNotContinue(t *testing.T) {
 	}
 	store := &mockStateStore{}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "continue", CircuitBreaker: 3}
+	config := Config{FailureMode: "continue", CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3}}
 
 	r := NewRunner(pipeline, beads, store, config, cb)
 
@@ -913,7 +1667,7 @@ func TestRun_RecursiveFeatureAbortOnFailure(t *testing.T) {
 		},
 	}
 	cb := &mockCallback{}
-	config := Config{FailureMode: "abort", CircuitBreaker: 5}
+	config := Config{FailureMode: "abort", CircuitBreaker: CircuitBreaker{Setup: 5, Signal: 5}}
 
 	r := NewRunner(pipeline, beads, &mockStateStore{}, config, cb)
 
@@ -950,7 +1704,7 @@ func TestRun_PostTaskFuncCalledAfterSuccess(t *testing.T) {
 	}
 	config := Config{
 		FailureMode:    "abort",
-		CircuitBreaker: 3,
+		CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3},
 		PostTaskFunc:   postTaskFunc,
 	}
 
@@ -992,7 +1746,7 @@ func TestRun_PostTaskFuncNotCalledOnFailure(t *testing.T) {
 	}
 	config := Config{
 		FailureMode:    "abort",
-		CircuitBreaker: 3,
+		CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3},
 		PostTaskFunc:   postTaskFunc,
 	}
 
@@ -1027,7 +1781,7 @@ func TestRun_PostTaskFuncNotCalledForRecursiveEntries(t *testing.T) {
 	}
 	config := Config{
 		FailureMode:    "abort",
-		CircuitBreaker: 5,
+		CircuitBreaker: CircuitBreaker{Setup: 5, Signal: 5},
 		PostTaskFunc:   postTaskFunc,
 	}
 
@@ -1068,7 +1822,7 @@ func TestRun_PostTaskFuncErrorTreatedAsFailure(t *testing.T) {
 	cb := &mockCallback{}
 	config := Config{
 		FailureMode:    "abort",
-		CircuitBreaker: 3,
+		CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3},
 		PostTaskFunc:   postTaskFunc,
 	}
 
@@ -1245,3 +1999,269 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestRun_CompleteFunc(t *testing.T) {
+	tests := []struct {
+		name        string
+		failureMode string
+		errs        []error
+		wantErr     bool
+		wantPassed  int
+		wantFailed  int
+		wantSuccess bool
+	}{
+		{
+			name:        "all tasks pass",
+			failureMode: "abort",
+			errs:        []error{nil, nil},
+			wantPassed:  2,
+			wantSuccess: true,
+		},
+		{
+			name:        "continue mode with a failed task",
+			failureMode: "continue",
+			errs:        []error{fmt.Errorf("boom"), nil},
+			wantPassed:  1,
+			wantFailed:  1,
+		},
+		{
+			name:        "abort mode stops on failure",
+			failureMode: "abort",
+			errs:        []error{fmt.Errorf("boom"), nil},
+			wantErr:     true,
+			wantFailed:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a campaign with a CompleteFunc
+			var calls []Completion
+			pipeline := &mockPipeline{
+				outputs: []orchestrator.PipelineOutput{passOutput(), passOutput()},
+				errs:    tt.errs,
+			}
+			beads := &mockBeadClient{
+				children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}},
+			}
+			config := Config{
+				FailureMode:    tt.failureMode,
+				CircuitBreaker: CircuitBreaker{Setup: 3, Signal: 3},
+				CompleteFunc:   func(c Completion) { calls = append(calls, c) },
+			}
+			r := NewRunner(pipeline, beads, &mockStateStore{}, config, &mockCallback{})
+
+			// When Run is called
+			err := r.Run(context.Background(), "cap-feature")
+
+			// Then CompleteFunc is called once with the outcome
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(calls) != 1 {
+				t.Fatalf("CompleteFunc calls = %d, want 1", len(calls))
+			}
+			c := calls[0]
+			if c.ParentID != "cap-feature" {
+				t.Errorf("ParentID = %q, want %q", c.ParentID, "cap-feature")
+			}
+			if c.Passed != tt.wantPassed || c.Failed != tt.wantFailed {
+				t.Errorf("Passed/Failed = %d/%d, want %d/%d", c.Passed, c.Failed, tt.wantPassed, tt.wantFailed)
+			}
+			if len(c.Tasks) != 2 {
+				t.Errorf("Tasks = %d, want 2", len(c.Tasks))
+			}
+			if c.Success() != tt.wantSuccess {
+				t.Errorf("Success() = %v, want %v", c.Success(), tt.wantSuccess)
+			}
+			if !errors.Is(c.Err, err) {
+				t.Errorf("Completion.Err = %v, want %v", c.Err, err)
+			}
+		})
+	}
+}
+
+func TestRun_CompleteFuncNoTasks(t *testing.T) {
+	// Given a parent with no ready children
+	var calls []Completion
+	config := Config{CompleteFunc: func(c Completion) { calls = append(calls, c) }}
+	r := NewRunner(&mockPipeline{}, &mockBeadClient{}, &mockStateStore{}, config, &mockCallback{})
+
+	// When Run is called
+	err := r.Run(context.Background(), "cap-empty")
+
+	// Then CompleteFunc still fires with the error
+	if len(calls) != 1 || !errors.Is(calls[0].Err, ErrNoTasks) {
+		t.Fatalf("CompleteFunc calls = %+v, want one with ErrNoTasks (Run err = %v)", calls, err)
+	}
+	if calls[0].Success() {
+		t.Error("Success() = true, want false")
+	}
+}
+
+func TestCompletion_Comment(t *testing.T) {
+	// Given a campaign where one task passed, one failed, and one was skipped
+	c := Completion{
+		ParentID: "cap-1",
+		Duration: 125 * time.Second,
+		Passed:   1,
+		Failed:   1,
+		Skipped:  1,
+		Tasks: []TaskResult{
+			{BeadID: "cap-1.1", Status: TaskCompleted, PhaseResults: []orchestrator.PhaseResult{
+				{PhaseName: "execute", Signal: provider.Signal{FilesChanged: []string{"a.go"}}},
+				{PhaseName: "sign-off", Signal: provider.Signal{FilesChanged: []string{"a.go", "b.go"}}},
+			}},
+			{BeadID: "cap-1.2", Status: TaskFailed, Error: "max retries exceeded", PhaseResults: []orchestrator.PhaseResult{
+				{PhaseName: "execute", Signal: provider.Signal{FilesChanged: []string{"c.go"}}},
+			}},
+			{BeadID: "cap-1.3", Status: TaskSkipped, SkipReason: "depends on cap-1.2"},
+		},
+	}
+
+	// When it is rendered as a bead comment
+	comment := c.Comment()
+
+	// Then it has the outcome, every task, and only the completed tasks' files
+	for _, want := range []string{
+		"capsule campaign failed in 2m5s: 1 passed, 1 failed, 1 skipped",
+		"- cap-1.1: completed\n- cap-1.2: failed (max retries exceeded)\n- cap-1.3: skipped (depends on cap-1.2)\n",
+		"Files changed: a.go, b.go\n",
+	} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("Comment() missing %q:\n%s", want, comment)
+		}
+	}
+	if strings.Contains(comment, "c.go") {
+		t.Errorf("Comment() lists a failed task's file:\n%s", comment)
+	}
+}
+
+func TestRun_FailedTaskKeepsPartialResults(t *testing.T) {
+	// Given a task whose pipeline fails after one phase
+	partial := orchestrator.PipelineOutput{PhaseResults: []orchestrator.PhaseResult{
+		{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusNeedsWork, Feedback: "fix it"}},
+	}}
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{partial},
+		errs:    []error{fmt.Errorf("boom")},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}}}
+	store := &mockStateStore{}
+	r := NewRunner(pipeline, beads, store, Config{FailureMode: "continue"}, &mockCallback{})
+
+	// When Run is called
+	_ = r.Run(context.Background(), "cap-feature")
+
+	// Then the saved state records the failed task's phase results
+	if len(store.saved) == 0 {
+		t.Fatal("no state saved")
+	}
+	task := store.saved[len(store.saved)-1].Tasks[0]
+	if task.Status != TaskFailed {
+		t.Fatalf("task status = %q, want %q", task.Status, TaskFailed)
+	}
+	if len(task.PhaseResults) != 1 || task.PhaseResults[0].Signal.Feedback != "fix it" {
+		t.Errorf("PhaseResults = %+v, want the partial execute result", task.PhaseResults)
+	}
+}
+
+func TestRecordTaskResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		parentID string
+		result   TaskResult
+		wantErr  error
+	}{
+		{
+			name:     "replaces the task result",
+			parentID: "cap-feature",
+			result:   TaskResult{BeadID: "cap-2", Status: TaskCompleted},
+		},
+		{
+			name:     "unknown campaign",
+			parentID: "cap-other",
+			result:   TaskResult{BeadID: "cap-2", Status: TaskCompleted},
+			wantErr:  ErrStateNotFound,
+		},
+		{
+			name:     "unknown task",
+			parentID: "cap-feature",
+			result:   TaskResult{BeadID: "cap-9", Status: TaskCompleted},
+			wantErr:  ErrTaskNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a store holding a campaign with a failed task
+			store := &mockStateStore{loaded: map[string]State{"cap-feature": {
+				ID:             "cap-feature",
+				ParentBeadID:   "cap-feature",
+				ConsecFailures: 2,
+				Tasks: []TaskResult{
+					{BeadID: "cap-1", Status: TaskCompleted},
+					{BeadID: "cap-2", Status: TaskFailed, Error: "boom"},
+				},
+			}}}
+
+			// When a retried task result is recorded
+			err := RecordTaskResult(store, tt.parentID, tt.result)
+
+			// Then the error matches, or the updated state is saved
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RecordTaskResult() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(store.saved) != 0 {
+					t.Error("state saved despite error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RecordTaskResult() error = %v", err)
+			}
+			if len(store.saved) != 1 {
+				t.Fatalf("saves = %d, want 1", len(store.saved))
+			}
+			got := store.saved[0]
+			if got.Tasks[1].Status != TaskCompleted || got.Tasks[1].Error != "" {
+				t.Errorf("task = %+v, want completed without error", got.Tasks[1])
+			}
+			if got.ConsecFailures != 0 {
+				t.Errorf("ConsecFailures = %d, want 0", got.ConsecFailures)
+			}
+		})
+	}
+}
+
+func TestRun_LogsTasksAndSaves(t *testing.T) {
+	// Given a campaign with a debug logger
+	var buf strings.Builder
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{passOutput()},
+		errs:    []error{nil},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1", Title: "Task 1"}}}
+	config := Config{
+		FailureMode: "abort",
+		Log:         slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, config, &mockCallback{})
+
+	// When Run is called
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then the campaign start, task outcome, and state saves are logged
+	logs := buf.String()
+	for _, want := range []string{
+		`msg="campaign start" parent=cap-feature depth=0 children=1`,
+		`msg="campaign task finished" bead=cap-1`,
+		`msg="campaign state saved" campaign=cap-feature status=completed`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("logs missing %q:\n%s", want, logs)
+		}
+	}
+}
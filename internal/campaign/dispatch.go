@@ -0,0 +1,273 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smileynet/capsule/internal/orchestrator"
+)
+
+// taskLoop is the scheduling state for one campaign level. Only the
+// goroutine running the campaign touches it: pipelines run on their own
+// goroutines and report back through outcomes, so the state, the circuit
+// breaker counts, and every Callback are handled by one goroutine.
+type taskLoop struct {
+	parentID string
+	depth    int
+	visited  map[string]bool
+	state    *State
+	graph    *taskGraph
+	limit    int             // Most pipelines in flight at once.
+	running  map[string]bool // Tasks whose pipeline is in flight.
+	finished map[string]bool // Tasks that ended during this Run; a failed one is not run again.
+	outcomes chan taskOutcome
+}
+
+// taskOutcome is what a task's pipeline or sub-campaign returned.
+type taskOutcome struct {
+	beadID string
+	output orchestrator.PipelineOutput
+	err    error
+	start  time.Time
+}
+
+// runTasks runs the level's tasks, up to l.limit pipelines at once, in
+// dependency order. A task starts once every sibling it depends on has
+// completed. When the campaign must stop (circuit breaker, abort, pause,
+// or cancellation) no further tasks start, the tasks in flight finish and
+// are recorded, and the first stop error is returned.
+func (r *Runner) runTasks(ctx context.Context, l *taskLoop) error {
+	var stopErr error
+	for {
+		if stopErr == nil {
+			stopErr = r.dispatch(ctx, l)
+		}
+		if len(l.running) == 0 {
+			return stopErr
+		}
+		out := <-l.outcomes
+		delete(l.running, out.beadID)
+		if err := r.finishTask(ctx, l, out); err != nil && stopErr == nil {
+			stopErr = err
+		}
+	}
+}
+
+// dispatch starts tasks until l.limit pipelines are in flight or no task is
+// ready. Tasks blocked by a failed dependency are skipped. A feature or
+// epic child runs its sub-campaign inline once nothing else is in flight,
+// and no later task starts before it finishes.
+func (r *Runner) dispatch(ctx context.Context, l *taskLoop) error {
+	for i := l.state.CurrentTaskIdx; i < len(l.state.Tasks) && len(l.running) < l.limit; i++ {
+		task := &l.state.Tasks[i]
+		if task.Status == TaskCompleted || task.Status == TaskSkipped || l.running[task.BeadID] || l.finished[task.BeadID] {
+			continue
+		}
+
+		if trip, ok := r.trip(*l.state); ok {
+			l.state.Status = CampaignFailed
+			l.state.TripReason = trip.Reason
+			l.state.TrippedBy = trip.BeadID
+			if o, ok := r.callback.(BreakerTripObserver); ok {
+				o.OnCircuitBroken(trip)
+			}
+			r.skipRemaining(l)
+			r.saveState(*l.state)
+			return fmt.Errorf("%w: %s", ErrCircuitBroken, trip.Reason)
+		}
+
+		if reason := r.blockedReason(l, task.BeadID); reason != "" {
+			task.Status = TaskSkipped
+			task.SkipReason = reason
+			r.taskSkipped(task.BeadID, reason)
+			l.finished[task.BeadID] = true
+			l.advance()
+			r.saveState(*l.state)
+			continue
+		}
+		if l.graph.waiting(*l.state, task.BeadID) {
+			continue
+		}
+
+		childType := l.graph.info[task.BeadID].Type
+		if childType == "feature" || childType == "epic" {
+			if len(l.running) > 0 {
+				return nil
+			}
+			r.callback.OnTaskStart(task.BeadID)
+			task.Status = TaskRunning
+			out := taskOutcome{beadID: task.BeadID, start: time.Now()}
+			out.err = r.runRecursive(ctx, task.BeadID, l.depth+1, l.visited)
+			if err := r.finishTask(ctx, l, out); err != nil {
+				return err
+			}
+			// Finishing may have queued and re-sorted tasks; scan again.
+			i = l.state.CurrentTaskIdx - 1
+			continue
+		}
+
+		r.callback.OnTaskStart(task.BeadID)
+		task.Status = TaskRunning
+		input := r.buildPipelineInput(task.BeadID, *l.state)
+		l.running[task.BeadID] = true
+		go func() {
+			out := taskOutcome{beadID: input.BeadID, start: time.Now()}
+			out.output, out.err = r.pipeline.RunPipeline(ctx, input)
+			l.outcomes <- out
+		}()
+	}
+	return nil
+}
+
+// blockedReason returns why the task for id must be skipped, or "" when
+// it may run. Under FailureContinue a failed dependency does not block.
+func (r *Runner) blockedReason(l *taskLoop, id string) string {
+	if r.config.FailureMode == FailureContinue {
+		return ""
+	}
+	return l.graph.blockedReason(*l.state, id)
+}
+
+// skipRemaining marks every task still waiting to start as skipped by the
+// circuit breaker, so the saved state accounts for each task.
+func (r *Runner) skipRemaining(l *taskLoop) {
+	for i := l.state.CurrentTaskIdx; i < len(l.state.Tasks); i++ {
+		task := &l.state.Tasks[i]
+		if task.Status != TaskPending || l.running[task.BeadID] {
+			continue
+		}
+		task.Status = TaskSkipped
+		task.SkipReason = breakerSkipReason
+		r.taskSkipped(task.BeadID, breakerSkipReason)
+		l.finished[task.BeadID] = true
+	}
+	l.advance()
+}
+
+// taskSkipped reports a skipped task to the callback when it is a
+// TaskSkipObserver.
+func (r *Runner) taskSkipped(beadID, reason string) {
+	if o, ok := r.callback.(TaskSkipObserver); ok {
+		o.OnTaskSkipped(beadID, reason)
+	}
+}
+
+// finishTask records a finished task in the level's state: usage,
+// discoveries, failure counts, and the post-task merge for a passing leaf
+// task. It returns an error when the campaign must stop.
+func (r *Runner) finishTask(ctx context.Context, l *taskLoop, out taskOutcome) error {
+	state := l.state
+	task := l.task(out.beadID)
+	childType := l.graph.info[out.beadID].Type
+	leaf := childType != "feature" && childType != "epic"
+	err := out.err
+	if leaf {
+		// Keep partial results on failure so the failing phase can be inspected.
+		task.PhaseResults = out.output.PhaseResults
+		r.addUsage(state, l.depth, out.output.TotalUsage())
+		if err == nil {
+			r.fileDiscoveries(state, out.output, l.parentID)
+		}
+	}
+
+	r.log.Debug("campaign task finished", "bead", out.beadID, "type", childType,
+		"duration", time.Since(out.start), "error", err)
+	if err != nil {
+		if ctx.Err() != nil {
+			task.Status = TaskPending
+			state.Status = CampaignPaused
+			r.saveState(*state)
+			return ErrCampaignAborted
+		}
+
+		if errors.Is(err, orchestrator.ErrPipelinePaused) {
+			task.Status = TaskPending
+			state.Status = CampaignPaused
+			r.saveState(*state)
+			return ErrCampaignPaused
+		}
+
+		task.Status = TaskFailed
+		task.Error = err.Error()
+		recordFailure(state, task.BeadID, classifyFailure(err, out.output.PhaseResults), err)
+		NotifyTaskFailed(r.callback, *task, err)
+
+		if r.config.stopsOnFailure() {
+			state.Status = CampaignFailed
+			r.saveState(*state)
+			return fmt.Errorf("campaign: task %s failed: %w", task.BeadID, err)
+		}
+		l.finished[task.BeadID] = true
+		l.advance()
+		r.saveState(*state)
+		return nil
+	}
+
+	task.Status = TaskCompleted
+	state.ConsecFailures = 0
+	state.ConsecByKind = FailureCounts{}
+	r.callback.OnTaskComplete(*task)
+
+	// Call PostTaskFunc after successful task (only for leaf tasks, not recursive entries).
+	if r.config.PostTaskFunc != nil && leaf {
+		if postErr := r.config.PostTaskFunc(task.BeadID); postErr != nil {
+			// Treat PostTaskFunc error as task failure.
+			task.Status = TaskFailed
+			task.Error = postErr.Error()
+			recordFailure(state, task.BeadID, FailureSetup, postErr)
+			NotifyTaskFailed(r.callback, *task, postErr)
+			r.callback.OnCampaignPaused(task.BeadID, "post_task_error", postErr.Error())
+
+			if r.config.stopsOnFailure() {
+				state.Status = CampaignFailed
+				r.saveState(*state)
+				return fmt.Errorf("campaign: task %s failed: %w", task.BeadID, postErr)
+			}
+			l.finished[task.BeadID] = true
+			l.advance()
+			r.saveState(*state)
+			return nil
+		}
+	} else {
+		// Fallback to legacy behavior when PostTaskFunc is nil.
+		r.runPostPipeline(task.BeadID)
+	}
+
+	l.finished[task.BeadID] = true
+	l.advance()
+	if err := r.refreshTasks(state, l.parentID, l.graph); err != nil {
+		state.Status = CampaignFailed
+		r.saveState(*state)
+		return err
+	}
+	r.saveState(*state)
+	return nil
+}
+
+// task returns the state entry for beadID. Tasks are looked up by ID
+// because refreshing the task list re-sorts it while pipelines run.
+func (l *taskLoop) task(beadID string) *TaskResult {
+	for i := range l.state.Tasks {
+		if l.state.Tasks[i].BeadID == beadID {
+			return &l.state.Tasks[i]
+		}
+	}
+	// Unreachable: tasks are never removed from the state.
+	panic("campaign: unknown task " + beadID)
+}
+
+// advance moves CurrentTaskIdx past the leading tasks that are done, so a
+// resumed campaign starts at the first task that has not finished. A failed
+// task counts as done once it is marked finished.
+func (l *taskLoop) advance() {
+	for l.state.CurrentTaskIdx < len(l.state.Tasks) {
+		t := l.state.Tasks[l.state.CurrentTaskIdx]
+		done := t.Status == TaskCompleted || t.Status == TaskSkipped || (t.Status == TaskFailed && l.finished[t.BeadID])
+		if !done {
+			return
+		}
+		l.state.CurrentTaskIdx++
+	}
+}
@@ -0,0 +1,221 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// gatedPipeline holds each bead's pipeline until the test releases it and
+// records how many ran at once. It is safe for concurrent use.
+type gatedPipeline struct {
+	mu          sync.Mutex
+	gates       map[string]chan error
+	inputs      map[string]orchestrator.PipelineInput
+	inFlight    int
+	maxInFlight int
+	started     chan string
+}
+
+func newGatedPipeline(ids ...string) *gatedPipeline {
+	g := &gatedPipeline{
+		gates:   make(map[string]chan error, len(ids)),
+		inputs:  make(map[string]orchestrator.PipelineInput, len(ids)),
+		started: make(chan string, len(ids)),
+	}
+	for _, id := range ids {
+		g.gates[id] = make(chan error, 1)
+	}
+	return g
+}
+
+func (g *gatedPipeline) RunPipeline(_ context.Context, input orchestrator.PipelineInput) (orchestrator.PipelineOutput, error) {
+	g.mu.Lock()
+	g.inFlight++
+	g.maxInFlight = max(g.maxInFlight, g.inFlight)
+	g.inputs[input.BeadID] = input
+	gate := g.gates[input.BeadID]
+	g.mu.Unlock()
+
+	g.started <- input.BeadID
+	err := <-gate
+
+	g.mu.Lock()
+	g.inFlight--
+	g.mu.Unlock()
+	if err != nil {
+		return orchestrator.PipelineOutput{}, err
+	}
+	return orchestrator.PipelineOutput{Completed: true, PhaseResults: []orchestrator.PhaseResult{
+		{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusPass, Summary: input.BeadID + " done"}},
+	}}, nil
+}
+
+// release lets id's pipeline return err.
+func (g *gatedPipeline) release(id string, err error) { g.gates[id] <- err }
+
+// input returns the PipelineInput id's pipeline received.
+func (g *gatedPipeline) input(id string) orchestrator.PipelineInput {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.inputs[id]
+}
+
+// waitStarted returns the next n beads whose pipelines started, sorted.
+func (g *gatedPipeline) waitStarted(t *testing.T, n int) []string {
+	t.Helper()
+	var ids []string
+	for range n {
+		select {
+		case id := <-g.started:
+			ids = append(ids, id)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for pipelines to start; got %v", ids)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// assertNoneStarted fails if another pipeline starts shortly.
+func (g *gatedPipeline) assertNoneStarted(t *testing.T) {
+	t.Helper()
+	select {
+	case id := <-g.started:
+		t.Fatalf("pipeline for %s started, want none", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// runAsync runs the campaign on its own goroutine and returns its result channel.
+func runAsync(r *Runner, parentID string) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- r.Run(context.Background(), parentID) }()
+	return done
+}
+
+func TestRun_ConcurrencyLimit(t *testing.T) {
+	// Given four independent tasks and a concurrency of 2
+	pipeline := newGatedPipeline("cap-1", "cap-2", "cap-3", "cap-4")
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}, {ID: "cap-3"}, {ID: "cap-4"}}}
+	cb := &mockCallback{}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{Concurrency: 2}, cb)
+
+	// When the campaign runs
+	done := runAsync(r, "cap-feature")
+
+	// Then two pipelines start and the others wait
+	if got := pipeline.waitStarted(t, 2); !slices.Equal(got, []string{"cap-1", "cap-2"}) {
+		t.Fatalf("started = %v, want cap-1 and cap-2", got)
+	}
+	pipeline.assertNoneStarted(t)
+
+	// And each finished task frees a slot for the next
+	pipeline.release("cap-2", nil)
+	if got := pipeline.waitStarted(t, 1); got[0] != "cap-3" {
+		t.Fatalf("started = %v, want cap-3", got)
+	}
+	pipeline.release("cap-1", nil)
+	pipeline.release("cap-3", nil)
+	if got := pipeline.waitStarted(t, 1); got[0] != "cap-4" {
+		t.Fatalf("started = %v, want cap-4", got)
+	}
+	pipeline.release("cap-4", nil)
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if pipeline.maxInFlight != 2 {
+		t.Errorf("max in flight = %d, want 2", pipeline.maxInFlight)
+	}
+	if len(cb.tasksCompleted) != 4 || len(beads.closed) != 4 {
+		t.Errorf("completed = %d, closed = %d; want 4 each", len(cb.tasksCompleted), len(beads.closed))
+	}
+}
+
+func TestRun_ConcurrentTaskWaitsForDependency(t *testing.T) {
+	// Given cap-2 depends on cap-1, cap-3 is independent, and a concurrency of 3
+	pipeline := newGatedPipeline("cap-1", "cap-2", "cap-3")
+	beads := &mockBeadClient{children: []BeadInfo{
+		{ID: "cap-1"},
+		{ID: "cap-2", DependsOn: []string{"cap-1"}},
+		{ID: "cap-3"},
+	}}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{Concurrency: 3, CrossRunContext: true}, &mockCallback{})
+
+	// When the campaign runs
+	done := runAsync(r, "cap-feature")
+
+	// Then only the tasks without unfinished dependencies start
+	if got := pipeline.waitStarted(t, 2); !slices.Equal(got, []string{"cap-1", "cap-3"}) {
+		t.Fatalf("started = %v, want cap-1 and cap-3", got)
+	}
+	pipeline.assertNoneStarted(t)
+
+	// When cap-1 completes while cap-3 is still running
+	pipeline.release("cap-1", nil)
+
+	// Then cap-2 starts with only the sibling that completed before it started
+	if got := pipeline.waitStarted(t, 1); got[0] != "cap-2" {
+		t.Fatalf("started = %v, want cap-2", got)
+	}
+	siblings := pipeline.input("cap-2").SiblingContext
+	if len(siblings) != 1 || siblings[0].BeadID != "cap-1" || siblings[0].Summary != "cap-1 done" {
+		t.Errorf("sibling context = %+v, want only cap-1", siblings)
+	}
+	pipeline.release("cap-3", nil)
+	pipeline.release("cap-2", nil)
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+func TestRun_ConcurrentCircuitBreakerLetsInFlightFinish(t *testing.T) {
+	// Given a breaker that trips on one setup failure and a concurrency of 2
+	pipeline := newGatedPipeline("cap-1", "cap-2", "cap-3")
+	beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}, {ID: "cap-3"}}}
+	store := &mockStateStore{}
+	cb := &mockCallback{}
+	config := Config{Concurrency: 2, FailureMode: "continue", CircuitBreaker: CircuitBreaker{Setup: 1}}
+	r := NewRunner(pipeline, beads, store, config, cb)
+	done := runAsync(r, "cap-feature")
+	pipeline.waitStarted(t, 2)
+
+	// When cap-1 fails while cap-2 is running
+	pipeline.release("cap-1", errors.New("provider crashed"))
+
+	// Then no new task starts
+	pipeline.assertNoneStarted(t)
+
+	// And the in-flight task still finishes and is recorded
+	pipeline.release("cap-2", nil)
+	err := <-done
+	if !errors.Is(err, ErrCircuitBroken) {
+		t.Fatalf("Run() error = %v, want ErrCircuitBroken", err)
+	}
+	if len(cb.trippedCalls) != 1 {
+		t.Errorf("tripped calls = %d, want 1", len(cb.trippedCalls))
+	}
+	if len(cb.tasksCompleted) != 1 || cb.tasksCompleted[0].BeadID != "cap-2" {
+		t.Errorf("completed = %+v, want cap-2", cb.tasksCompleted)
+	}
+	last := store.saved[len(store.saved)-1]
+	statuses := make(map[string]TaskStatus)
+	for _, task := range last.Tasks {
+		statuses[task.BeadID] = task.Status
+	}
+	want := map[string]TaskStatus{"cap-1": TaskFailed, "cap-2": TaskCompleted, "cap-3": TaskSkipped}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("%s status = %q, want %q", id, statuses[id], status)
+		}
+	}
+	if last.Status != CampaignFailed {
+		t.Errorf("campaign status = %q, want %q", last.Status, CampaignFailed)
+	}
+}
@@ -0,0 +1,258 @@
+package campaign
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Ordering policies for Config.Ordering: which ready task runs first.
+// Dependencies between siblings always come first; the policy orders the
+// tasks whose dependencies are done.
+const (
+	OrderPriority         = "priority"           // Highest priority (lowest number) first.
+	OrderTypeThenPriority = "type-then-priority" // Bugs, then tasks, then features and other types; by priority within each.
+	OrderAsListed         = "as-listed"          // The order bd lists the children in.
+)
+
+// typeRank orders bead types for OrderTypeThenPriority. Types not listed
+// run after features.
+var typeRank = map[string]int{"bug": 0, "task": 1, "feature": 2}
+
+// taskGraph holds what the campaign knows about its children beyond the
+// persisted state: their types and dependency edges.
+type taskGraph struct {
+	info     map[string]BeadInfo // Child bead ID → latest metadata from ReadyChildren.
+	ordering string              // Config.Ordering; empty means OrderPriority.
+}
+
+// newTaskGraph builds a graph from the parent's children that orders ready
+// tasks by the given policy.
+func newTaskGraph(children []BeadInfo, ordering string) *taskGraph {
+	g := &taskGraph{info: make(map[string]BeadInfo, len(children)), ordering: ordering}
+	g.add(children)
+	return g
+}
+
+// add records children, replacing earlier metadata for the same bead.
+func (g *taskGraph) add(children []BeadInfo) {
+	for _, c := range children {
+		g.info[c.ID] = c
+	}
+}
+
+// queueNew appends pending tasks for children the state does not have yet,
+// e.g. beads filed after the campaign was planned, and reports whether any
+// were added.
+func (g *taskGraph) queueNew(state *State, children []BeadInfo) bool {
+	added := false
+	for _, c := range children {
+		if !slices.ContainsFunc(state.Tasks, func(t TaskResult) bool { return t.BeadID == c.ID }) {
+			state.Tasks = append(state.Tasks, TaskResult{BeadID: c.ID, Status: TaskPending})
+			added = true
+		}
+	}
+	return added
+}
+
+// orderPending sorts the tasks from state.CurrentTaskIdx on so that every
+// task runs after the siblings it depends on. Among tasks whose siblings
+// are done, the graph's ordering policy picks the next (see before); with
+// OrderAsListed they keep their current order. Dependencies on beads
+// outside the remaining tasks are ignored: finished siblings are already
+// satisfied and other beads are not the campaign's to order. A dependency
+// cycle returns ErrCycle naming the beads involved.
+func (g *taskGraph) orderPending(state *State) error {
+	rest := state.Tasks[state.CurrentTaskIdx:]
+	index := make(map[string]int, len(rest))
+	for i, t := range rest {
+		index[t.BeadID] = i
+	}
+
+	// waiting[i] counts the unsorted tasks that task i depends on.
+	waiting := make([]int, len(rest))
+	dependents := make([][]int, len(rest))
+	for i, t := range rest {
+		for _, dep := range g.info[t.BeadID].DependsOn {
+			if j, ok := index[dep]; ok && j != i {
+				waiting[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	sorted := make([]TaskResult, 0, len(rest))
+	done := make([]bool, len(rest))
+	for len(sorted) < len(rest) {
+		next := -1
+		for i := range rest {
+			if !done[i] && waiting[i] == 0 && (next < 0 || g.before(rest[i].BeadID, rest[next].BeadID)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return fmt.Errorf("%w: %s", ErrCycle, g.describeCycle(rest, done, index))
+		}
+		done[next] = true
+		sorted = append(sorted, rest[next])
+		for _, d := range dependents[next] {
+			waiting[d]--
+		}
+	}
+	copy(rest, sorted)
+	return nil
+}
+
+// before reports whether the ready task a should run before b under the
+// graph's ordering policy. OrderPriority and OrderTypeThenPriority break
+// ties by bead ID (see compareIDs), so the order does not depend on how bd
+// happened to list the children; OrderAsListed never reorders.
+func (g *taskGraph) before(a, b string) bool {
+	ia, ib := g.info[a], g.info[b]
+	switch g.ordering {
+	case OrderAsListed:
+		return false
+	case OrderTypeThenPriority:
+		if ra, rb := rankType(ia.Type), rankType(ib.Type); ra != rb {
+			return ra < rb
+		}
+	}
+	if ia.Priority != ib.Priority {
+		return ia.Priority < ib.Priority
+	}
+	return compareIDs(a, b) < 0
+}
+
+// rankType returns the position of a bead type for OrderTypeThenPriority.
+func rankType(typ string) int {
+	if r, ok := typeRank[typ]; ok {
+		return r
+	}
+	return len(typeRank)
+}
+
+// compareIDs orders bead IDs by their numeric suffix, so cap-9 comes before
+// cap-10 and cap-2 before cap-2.1. IDs without one, such as hash-based IDs,
+// and equal suffixes fall back to comparing the IDs as strings.
+func compareIDs(a, b string) int {
+	na, okA := idNumbers(a)
+	nb, okB := idNumbers(b)
+	if okA && okB {
+		if c := slices.Compare(na, nb); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// idNumbers parses the dotted numbers after the last "-" in a bead ID,
+// e.g. [2 1] for cap-2.1. It reports false when the suffix is not numeric.
+func idNumbers(id string) ([]int, bool) {
+	parts := strings.Split(id[strings.LastIndex(id, "-")+1:], ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		nums[i] = n
+	}
+	return nums, true
+}
+
+// describeCycle follows unsorted dependencies from the first unsorted task
+// until a bead repeats and returns that loop, e.g. "cap-1 → cap-2 → cap-1".
+func (g *taskGraph) describeCycle(rest []TaskResult, done []bool, index map[string]int) string {
+	start := slices.Index(done, false)
+	var path []string
+	seen := make(map[string]int)
+	id := rest[start].BeadID
+	for {
+		if at, ok := seen[id]; ok {
+			return strings.Join(append(path[at:], id), " → ")
+		}
+		seen[id] = len(path)
+		path = append(path, id)
+		for _, dep := range g.info[id].DependsOn {
+			if j, ok := index[dep]; ok && !done[j] {
+				id = dep
+				break
+			}
+		}
+	}
+}
+
+// blockedReason returns why the task for id cannot run: a sibling it
+// depends on, or whose ID it extends (cap-2 for cap-2.1), failed or was
+// itself skipped for a failed dependency. It returns "" when the task may
+// run.
+func (g *taskGraph) blockedReason(state State, id string) string {
+	for _, t := range state.Tasks {
+		if !strings.HasPrefix(id, t.BeadID+".") {
+			continue
+		}
+		switch {
+		case t.Status == TaskFailed:
+			return fmt.Sprintf("ancestor %s failed", t.BeadID)
+		case t.Status == TaskSkipped && t.SkipReason != "":
+			return fmt.Sprintf("ancestor %s was skipped", t.BeadID)
+		}
+	}
+	for _, dep := range g.info[id].DependsOn {
+		for _, t := range state.Tasks {
+			if t.BeadID != dep {
+				continue
+			}
+			switch {
+			case t.Status == TaskFailed:
+				return fmt.Sprintf("dependency %s failed", dep)
+			case t.Status == TaskSkipped && t.SkipReason != "":
+				return fmt.Sprintf("dependency %s was skipped", dep)
+			}
+		}
+	}
+	return ""
+}
+
+// waiting reports whether the task for id depends on a sibling that has not
+// finished yet: one still queued or in flight.
+func (g *taskGraph) waiting(state State, id string) bool {
+	for _, dep := range g.info[id].DependsOn {
+		for _, t := range state.Tasks {
+			if t.BeadID == dep && (t.Status == TaskPending || t.Status == TaskRunning) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// planned returns the tasks still to run, in order, for OnCampaignStart.
+// Tasks that wait on unfinished siblings list them in BlockedBy.
+func (g *taskGraph) planned(state State) []BeadInfo {
+	unfinished := make(map[string]bool)
+	for _, t := range state.Tasks {
+		if t.Status == TaskPending || t.Status == TaskRunning {
+			unfinished[t.BeadID] = true
+		}
+	}
+	var tasks []BeadInfo
+	for _, t := range state.Tasks {
+		if !unfinished[t.BeadID] {
+			continue
+		}
+		info, ok := g.info[t.BeadID]
+		if !ok {
+			info = BeadInfo{ID: t.BeadID}
+		}
+		info.BlockedBy = nil
+		for _, dep := range info.DependsOn {
+			if unfinished[dep] && dep != t.BeadID {
+				info.BlockedBy = append(info.BlockedBy, dep)
+			}
+		}
+		tasks = append(tasks, info)
+	}
+	return tasks
+}
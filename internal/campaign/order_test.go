@@ -0,0 +1,530 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// pipelineOrder returns the bead IDs the pipeline ran, in order.
+func pipelineOrder(p *mockPipeline) []string {
+	ids := make([]string, len(p.calls))
+	for i, c := range p.calls {
+		ids[i] = c.BeadID
+	}
+	return ids
+}
+
+func TestRun_OrdersTasksByDependencies(t *testing.T) {
+	// Given children listed before the tasks they depend on
+	pipeline := &mockPipeline{outputs: []orchestrator.PipelineOutput{passOutput(), passOutput(), passOutput(), passOutput()}}
+	beads := &mockBeadClient{children: []BeadInfo{
+		{ID: "cap-3", DependsOn: []string{"cap-2"}},
+		{ID: "cap-2", DependsOn: []string{"cap-1", "other-9"}},
+		{ID: "cap-4"},
+		{ID: "cap-1"},
+	}}
+	cb := &mockCallback{}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{FailureMode: "abort", Ordering: OrderAsListed}, cb)
+
+	// When the campaign runs in list order
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then each task runs after its dependencies, otherwise keeping list order
+	want := []string{"cap-4", "cap-1", "cap-2", "cap-3"}
+	if got := pipelineOrder(pipeline); !slices.Equal(got, want) {
+		t.Errorf("run order = %v, want %v", got, want)
+	}
+	// And the planned set is in run order and names the siblings each waits on
+	wantBlockedBy := map[string][]string{"cap-2": {"cap-1"}, "cap-3": {"cap-2"}}
+	if len(cb.planned) != 4 {
+		t.Fatalf("planned = %+v, want 4 tasks", cb.planned)
+	}
+	for i, task := range cb.planned {
+		if task.ID != want[i] || !slices.Equal(task.BlockedBy, wantBlockedBy[task.ID]) {
+			t.Errorf("planned[%d] = %s blocked by %v, want %s blocked by %v", i, task.ID, task.BlockedBy, want[i], wantBlockedBy[want[i]])
+		}
+	}
+}
+
+func TestRun_OrdersIndependentTasksByPriority(t *testing.T) {
+	// Given ready children listed out of priority order, one waiting on a
+	// lower-priority sibling
+	pipeline := &mockPipeline{outputs: []orchestrator.PipelineOutput{passOutput(), passOutput(), passOutput(), passOutput()}}
+	beads := &mockBeadClient{children: []BeadInfo{
+		{ID: "cap-1", Priority: 3},
+		{ID: "cap-2", Priority: 1, DependsOn: []string{"cap-4"}},
+		{ID: "cap-3", Priority: 2},
+		{ID: "cap-4", Priority: 3},
+	}}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{FailureMode: "abort"}, &mockCallback{})
+
+	// When the campaign runs
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then ready tasks run highest priority first, ties by ID, and the
+	// dependent runs as soon as its dependency is done
+	want := []string{"cap-3", "cap-1", "cap-4", "cap-2"}
+	if got := pipelineOrder(pipeline); !slices.Equal(got, want) {
+		t.Errorf("run order = %v, want %v", got, want)
+	}
+}
+
+func TestRun_SkipsTasksWhoseDependencyFailed(t *testing.T) {
+	// Given a failing task, a chain that depends on it, and an independent task
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{{}, passOutput()},
+		errs:    []error{errors.New("provider failed"), nil},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{
+		{ID: "cap-1"},
+		{ID: "cap-2", DependsOn: []string{"cap-1"}},
+		{ID: "cap-3", DependsOn: []string{"cap-2"}},
+		{ID: "cap-4"},
+	}}
+	store := &mockStateStore{}
+	cb := &mockCallback{}
+	r := NewRunner(pipeline, beads, store, Config{FailureMode: FailureSkipDependents, CircuitBreaker: CircuitBreaker{Setup: 3}}, cb)
+
+	// When the campaign runs
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then only the failed task and the independent one ran
+	if got := pipelineOrder(pipeline); !slices.Equal(got, []string{"cap-1", "cap-4"}) {
+		t.Errorf("run order = %v, want [cap-1 cap-4]", got)
+	}
+	// And the dependents are skipped with the reason
+	wantSkipped := map[string]string{
+		"cap-2": "dependency cap-1 failed",
+		"cap-3": "dependency cap-2 was skipped",
+	}
+	for id, reason := range wantSkipped {
+		if cb.tasksSkipped[id] != reason {
+			t.Errorf("skip reason for %s = %q, want %q", id, cb.tasksSkipped[id], reason)
+		}
+	}
+	// And the reasons are persisted
+	final := store.saved[len(store.saved)-1]
+	if task := final.Tasks[1]; task.Status != TaskSkipped || task.SkipReason != wantSkipped["cap-2"] {
+		t.Errorf("saved cap-2 = %+v, want skipped with reason", task)
+	}
+}
+
+func TestRun_SkipWithoutSkipObserver(t *testing.T) {
+	// Given a callback that implements only Callback, not TaskSkipObserver,
+	// and a task whose dependency fails
+	pipeline := &mockPipeline{
+		outputs: []orchestrator.PipelineOutput{{}},
+		errs:    []error{errors.New("provider failed")},
+	}
+	beads := &mockBeadClient{children: []BeadInfo{
+		{ID: "cap-1"},
+		{ID: "cap-2", DependsOn: []string{"cap-1"}},
+	}}
+	store := &mockStateStore{}
+	cb := &mockCallback{}
+	r := NewRunner(pipeline, beads, store, Config{FailureMode: FailureSkipDependents}, struct{ Callback }{cb})
+
+	// When the campaign runs
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then the dependent is skipped in the saved state without a callback event
+	if len(cb.tasksSkipped) != 0 {
+		t.Errorf("OnTaskSkipped calls = %v, want none", cb.tasksSkipped)
+	}
+	final := store.saved[len(store.saved)-1]
+	if task := final.Tasks[1]; task.Status != TaskSkipped || task.SkipReason != "dependency cap-1 failed" {
+		t.Errorf("saved cap-2 = %+v, want skipped with reason", task)
+	}
+}
+
+func TestRun_FailureModes(t *testing.T) {
+	tests := []struct {
+		mode        string
+		wantErr     bool
+		wantRun     []string
+		wantStatus  []TaskStatus
+		wantSkipped map[string]string
+	}{
+		{
+			mode:       FailureStop,
+			wantErr:    true,
+			wantRun:    []string{"cap-1", "cap-2"},
+			wantStatus: []TaskStatus{TaskCompleted, TaskFailed, TaskPending, TaskPending},
+		},
+		{
+			mode:       FailureAbort,
+			wantErr:    true,
+			wantRun:    []string{"cap-1", "cap-2"},
+			wantStatus: []TaskStatus{TaskCompleted, TaskFailed, TaskPending, TaskPending},
+		},
+		{
+			mode:       FailureContinue,
+			wantRun:    []string{"cap-1", "cap-2", "cap-3", "cap-4"},
+			wantStatus: []TaskStatus{TaskCompleted, TaskFailed, TaskCompleted, TaskCompleted},
+		},
+		{
+			mode:        FailureSkipDependents,
+			wantRun:     []string{"cap-1", "cap-2", "cap-4"},
+			wantStatus:  []TaskStatus{TaskCompleted, TaskFailed, TaskSkipped, TaskCompleted},
+			wantSkipped: map[string]string{"cap-3": "dependency cap-2 failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			// Given four tasks where cap-2 fails, cap-3 depends on it, and cap-4 is unrelated
+			pipeline := &mockPipeline{
+				outputs: []orchestrator.PipelineOutput{passOutput(), {}, passOutput(), passOutput()},
+				errs:    []error{nil, errors.New("provider failed"), nil, nil},
+			}
+			beads := &mockBeadClient{children: []BeadInfo{
+				{ID: "cap-1"},
+				{ID: "cap-2"},
+				{ID: "cap-3", DependsOn: []string{"cap-2"}},
+				{ID: "cap-4"},
+			}}
+			store := &mockStateStore{}
+			cb := &mockCallback{}
+			r := NewRunner(pipeline, beads, store, Config{FailureMode: tt.mode, CircuitBreaker: CircuitBreaker{Setup: 3}}, cb)
+
+			// When the campaign runs
+			err := r.Run(context.Background(), "cap-feature")
+
+			// Then only a stopping mode returns the failure
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// And the mode decides which tasks after cap-2 run
+			if got := pipelineOrder(pipeline); !slices.Equal(got, tt.wantRun) {
+				t.Errorf("run order = %v, want %v", got, tt.wantRun)
+			}
+			final := store.saved[len(store.saved)-1]
+			for i, want := range tt.wantStatus {
+				if got := final.Tasks[i]; got.Status != want || got.SkipReason != tt.wantSkipped[got.BeadID] {
+					t.Errorf("task %s = %s %q, want %s %q", got.BeadID, got.Status, got.SkipReason, want, tt.wantSkipped[got.BeadID])
+				}
+			}
+			// And every skip is reported with its reason
+			if len(cb.tasksSkipped) != len(tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", cb.tasksSkipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestTaskGraph_BlockedReason_Descendant(t *testing.T) {
+	// Given a failed task, a task whose ID extends it, and one that only shares a prefix
+	g := newTaskGraph([]BeadInfo{{ID: "cap-2"}, {ID: "cap-2.1"}, {ID: "cap-2.1.3"}, {ID: "cap-20"}}, "")
+	state := State{Tasks: []TaskResult{
+		{BeadID: "cap-2", Status: TaskFailed},
+		{BeadID: "cap-2.1", Status: TaskPending},
+		{BeadID: "cap-2.1.3", Status: TaskPending},
+		{BeadID: "cap-20", Status: TaskPending},
+	}}
+
+	// When each is checked
+	// Then only the descendants are blocked
+	for id, want := range map[string]string{
+		"cap-2.1":   "ancestor cap-2 failed",
+		"cap-2.1.3": "ancestor cap-2 failed",
+		"cap-20":    "",
+	} {
+		if got := g.blockedReason(state, id); got != want {
+			t.Errorf("blockedReason(%s) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestRun_DependencyCycleAborts(t *testing.T) {
+	// Given two children that depend on each other
+	pipeline := &mockPipeline{}
+	beads := &mockBeadClient{children: []BeadInfo{
+		{ID: "cap-1", DependsOn: []string{"cap-2"}},
+		{ID: "cap-2", DependsOn: []string{"cap-1"}},
+		{ID: "cap-3"},
+	}}
+	cb := &mockCallback{}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{FailureMode: "abort"}, cb)
+
+	// When the campaign runs
+	err := r.Run(context.Background(), "cap-feature")
+
+	// Then it fails naming the cycle before running anything
+	if !errors.Is(err, ErrCycle) {
+		t.Fatalf("Run() error = %v, want ErrCycle", err)
+	}
+	if !strings.Contains(err.Error(), "cap-1 → cap-2 → cap-1") {
+		t.Errorf("error = %q, want the cycle spelled out", err)
+	}
+	if len(pipeline.calls) != 0 || cb.campaignStarted {
+		t.Errorf("pipeline calls = %d, started = %v; want nothing run", len(pipeline.calls), cb.campaignStarted)
+	}
+}
+
+func TestRun_QueuesChildrenFiledDuringCampaign(t *testing.T) {
+	// Given a campaign where a new child appears after the first task, ahead of
+	// a task that now depends on it
+	pipeline := &mockPipeline{outputs: []orchestrator.PipelineOutput{passOutput(), passOutput(), passOutput()}}
+	beads := &mockBeadClient{
+		children: []BeadInfo{{ID: "cap-1"}, {ID: "cap-2"}},
+		later:    []BeadInfo{{ID: "cap-2", DependsOn: []string{"cap-5"}}, {ID: "cap-5"}},
+	}
+	r := NewRunner(pipeline, beads, &mockStateStore{}, Config{FailureMode: "abort"}, &mockCallback{})
+
+	// When the campaign runs
+	if err := r.Run(context.Background(), "cap-feature"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Then the new child is queued and runs before its dependent
+	if got := pipelineOrder(pipeline); !slices.Equal(got, []string{"cap-1", "cap-5", "cap-2"}) {
+		t.Errorf("run order = %v, want [cap-1 cap-5 cap-2]", got)
+	}
+}
+
+// filingBeadClient is a mockBeadClient whose parents list the beads Create
+// filed under them among their ready children, as bd does. Parents other
+// than cap-epic have a single child, <parent>.1.
+type filingBeadClient struct {
+	mockBeadClient
+}
+
+func (m *filingBeadClient) ReadyChildren(parentID string) ([]BeadInfo, error) {
+	children := []BeadInfo{{ID: parentID + ".1"}}
+	if parentID == "cap-epic" {
+		children = slices.Clone(m.children)
+	}
+	for _, in := range m.created {
+		if in.ParentID == parentID {
+			children = append(children, BeadInfo{ID: m.createID, Type: in.Type, Priority: in.Priority})
+		}
+	}
+	return children, nil
+}
+
+func TestRun_OrderingPolicies(t *testing.T) {
+	// An epic's children of mixed types and priorities, listed by bd in no
+	// particular order. Its features are sub-campaigns of one task each.
+	children := []BeadInfo{
+		{ID: "cap-2", Type: "task", Priority: 2},
+		{ID: "cap-10", Type: "feature", Priority: 1},
+		{ID: "cap-3", Type: "bug", Priority: 3},
+		{ID: "cap-9", Type: "task", Priority: 1},
+		{ID: "cap-4", Type: "feature", Priority: 2},
+	}
+	// The first task to run reports a critical finding, filed as cap-11.
+	finding := orchestrator.PipelineOutput{Completed: true, PhaseResults: []orchestrator.PhaseResult{{
+		PhaseName: "code-review",
+		Signal: provider.Signal{
+			Status:   provider.StatusPass,
+			Findings: []provider.Finding{{Title: "SQL injection", Severity: "critical"}},
+		},
+	}}}
+
+	tests := []struct {
+		ordering    string
+		wantPlanned []string
+		wantRun     []string
+	}{
+		{
+			ordering:    "",
+			wantPlanned: []string{"cap-9", "cap-10", "cap-2", "cap-4", "cap-3"},
+			wantRun:     []string{"cap-9", "cap-11", "cap-10.1", "cap-2", "cap-4.1", "cap-3"},
+		},
+		{
+			ordering:    OrderPriority,
+			wantPlanned: []string{"cap-9", "cap-10", "cap-2", "cap-4", "cap-3"},
+			wantRun:     []string{"cap-9", "cap-11", "cap-10.1", "cap-2", "cap-4.1", "cap-3"},
+		},
+		{
+			ordering:    OrderTypeThenPriority,
+			wantPlanned: []string{"cap-3", "cap-9", "cap-2", "cap-10", "cap-4"},
+			wantRun:     []string{"cap-3", "cap-11", "cap-9", "cap-2", "cap-10.1", "cap-4.1"},
+		},
+		{
+			ordering:    OrderAsListed,
+			wantPlanned: []string{"cap-2", "cap-10", "cap-3", "cap-9", "cap-4"},
+			wantRun:     []string{"cap-2", "cap-10.1", "cap-3", "cap-9", "cap-4.1", "cap-11"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("ordering "+tt.ordering, func(t *testing.T) {
+			// Given a campaign that files discoveries under the epic
+			outputs := []orchestrator.PipelineOutput{finding}
+			for range len(tt.wantRun) - 1 {
+				outputs = append(outputs, passOutput())
+			}
+			pipeline := &mockPipeline{outputs: outputs}
+			beads := &filingBeadClient{mockBeadClient{children: children, createID: "cap-11"}}
+			cb := &mockCallback{}
+			config := Config{FailureMode: "abort", DiscoveryFiling: true, PickUpDiscoveries: true, Ordering: tt.ordering}
+			r := NewRunner(pipeline, beads, &mockStateStore{}, config, cb)
+
+			// When the campaign is planned and then runs
+			plan, err := r.Plan("cap-epic")
+			if err != nil {
+				t.Fatalf("Plan() error = %v", err)
+			}
+			if err := r.Run(context.Background(), "cap-epic"); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			// Then the plan and the announced task list follow the policy
+			var planned, announced []string
+			for _, task := range plan {
+				planned = append(planned, task.ID)
+			}
+			for _, task := range cb.planned {
+				announced = append(announced, task.ID)
+			}
+			if !slices.Equal(planned, tt.wantPlanned) || !slices.Equal(announced, tt.wantPlanned) {
+				t.Errorf("plan = %v, announced = %v, want %v", planned, announced, tt.wantPlanned)
+			}
+			// And the discovered bead is queued by the same policy
+			if got := pipelineOrder(pipeline); !slices.Equal(got, tt.wantRun) {
+				t.Errorf("run order = %v, want %v", got, tt.wantRun)
+			}
+		})
+	}
+}
+
+func TestRun_LeavesDiscoveriesUnlessPickedUp(t *testing.T) {
+	// The first task to run reports a critical finding, filed as cap-11.
+	finding := orchestrator.PipelineOutput{Completed: true, PhaseResults: []orchestrator.PhaseResult{{
+		PhaseName: "code-review",
+		Signal: provider.Signal{
+			Status:   provider.StatusPass,
+			Findings: []provider.Finding{{Title: "SQL injection", Severity: "critical"}},
+		},
+	}}}
+
+	t.Run("filed during the run", func(t *testing.T) {
+		// Given a campaign that files discoveries but does not pick them up
+		pipeline := &mockPipeline{outputs: []orchestrator.PipelineOutput{finding, passOutput()}}
+		beads := &filingBeadClient{mockBeadClient{
+			children: []BeadInfo{{ID: "cap-9", Priority: 1}, {ID: "cap-2", Priority: 2}},
+			createID: "cap-11",
+		}}
+		store := &mockStateStore{}
+		config := Config{FailureMode: "abort", DiscoveryFiling: true}
+		r := NewRunner(pipeline, beads, store, config, &mockCallback{})
+
+		// When the campaign runs
+		if err := r.Run(context.Background(), "cap-epic"); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		// Then the finding is filed but only the planned tasks run
+		if got := pipelineOrder(pipeline); !slices.Equal(got, []string{"cap-9", "cap-2"}) {
+			t.Errorf("run order = %v, want [cap-9 cap-2]", got)
+		}
+		// And the state records the discovered bead
+		last := store.saved[len(store.saved)-1]
+		if !slices.Equal(last.Discovered, []string{"cap-11"}) {
+			t.Errorf("Discovered = %v, want [cap-11]", last.Discovered)
+		}
+	})
+
+	t.Run("filed before a resume", func(t *testing.T) {
+		// Given a paused campaign that filed cap-11, now a ready child
+		pipeline := &mockPipeline{outputs: []orchestrator.PipelineOutput{passOutput()}}
+		beads := &mockBeadClient{children: []BeadInfo{{ID: "cap-9"}, {ID: "cap-2"}, {ID: "cap-11"}}}
+		store := &mockStateStore{loaded: map[string]State{"cap-epic": {
+			ID:           "cap-epic",
+			ParentBeadID: "cap-epic",
+			Tasks:        []TaskResult{{BeadID: "cap-9", Status: TaskCompleted}, {BeadID: "cap-2", Status: TaskPending}},
+			Discovered:   []string{"cap-11"},
+			Status:       CampaignPaused,
+		}}}
+		config := Config{FailureMode: "abort", DiscoveryFiling: true, Resume: true}
+		r := NewRunner(pipeline, beads, store, config, &mockCallback{})
+
+		// When the campaign resumes
+		if err := r.Run(context.Background(), "cap-epic"); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		// Then the discovered bead is not queued
+		if got := pipelineOrder(pipeline); !slices.Equal(got, []string{"cap-2"}) {
+			t.Errorf("run order = %v, want [cap-2]", got)
+		}
+	})
+}
+
+func TestCompareIDs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"cap-9", "cap-10", -1},
+		{"cap-10", "cap-9", 1},
+		{"cap-2", "cap-2.1", -1},
+		{"cap-2.9", "cap-2.10", -1},
+		{"cap-3", "cap-3", 0},
+		{"bd-a3f8", "bd-1", 1}, // Hash-based IDs compare as strings.
+		{"bd-b1", "bd-a9", 1},
+	}
+	for _, tt := range tests {
+		if got := compareIDs(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareIDs(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestPlan_ListsTasksInRunOrderWithoutRunning(t *testing.T) {
+	// Given ready children with a dependency and mixed priorities
+	pipeline := &mockPipeline{}
+	beads := &mockBeadClient{children: []BeadInfo{
+		{ID: "cap-1", Priority: 2, DependsOn: []string{"cap-2"}},
+		{ID: "cap-2", Priority: 2},
+		{ID: "cap-3", Priority: 1, Type: "feature"},
+	}}
+	store := &mockStateStore{}
+	cb := &mockCallback{}
+	r := NewRunner(pipeline, beads, store, Config{FailureMode: "abort"}, cb)
+
+	// When the campaign is planned
+	tasks, err := r.Plan("cap-feature")
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	// Then the tasks come in run order with their blockers
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.ID+"<"+strings.Join(task.BlockedBy, ","))
+	}
+	if want := []string{"cap-3<", "cap-2<", "cap-1<cap-2"}; !slices.Equal(got, want) {
+		t.Errorf("plan = %v, want %v", got, want)
+	}
+	// And nothing ran, was announced, or was saved
+	if len(pipeline.calls) != 0 || cb.campaignStarted || len(store.saved) != 0 {
+		t.Errorf("pipeline calls = %d, started = %v, saves = %d; want none", len(pipeline.calls), cb.campaignStarted, len(store.saved))
+	}
+}
+
+func TestPlan_NoReadyChildren(t *testing.T) {
+	// Given a parent without ready children
+	r := NewRunner(&mockPipeline{}, &mockBeadClient{}, &mockStateStore{}, Config{}, &mockCallback{})
+
+	// When the campaign is planned
+	_, err := r.Plan("cap-feature")
+
+	// Then it fails as Run would
+	if !errors.Is(err, ErrNoTasks) {
+		t.Errorf("Plan() error = %v, want ErrNoTasks", err)
+	}
+}
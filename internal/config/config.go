@@ -6,7 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -14,28 +19,131 @@ import (
 
 // Config holds all capsule configuration.
 type Config struct {
-	Runtime  Runtime  `yaml:"runtime"`
-	Worktree Worktree `yaml:"worktree"`
-	Pipeline Pipeline `yaml:"pipeline"`
-	Campaign Campaign `yaml:"campaign"`
+	Runtime       Runtime       `yaml:"runtime"`
+	Worktree      Worktree      `yaml:"worktree"`
+	Pipeline      Pipeline      `yaml:"pipeline"`
+	Campaign      Campaign      `yaml:"campaign"`
+	Notifications Notifications `yaml:"notifications"`
+	Dashboard     Dashboard     `yaml:"dashboard"`
+	Bead          Bead          `yaml:"bead"`
+
+	sources map[string]string // YAML path → where it was set; see Source.
 }
 
 // Runtime holds provider and execution settings.
 type Runtime struct {
-	Provider string        `yaml:"provider"`
-	Timeout  time.Duration `yaml:"timeout"`
+	Provider  string        `yaml:"provider"`
+	Timeout   time.Duration `yaml:"timeout"`
+	Script    string        `yaml:"script"`     // Response script for the "scripted" provider
+	KillGrace time.Duration `yaml:"kill_grace"` // Time a cancelled provider gets after SIGINT before SIGKILL
+
+	TransientRetries int           `yaml:"transient_retries"` // Retries of a provider call that hit a rate limit or network error
+	BackoffMax       time.Duration `yaml:"backoff_max"`       // Longest wait between transient retries
+
+	Listen string `yaml:"listen"` // host:port for the read-only HTTP status endpoint; empty disables it
+
+	Providers map[string]ProviderConfig `yaml:"providers"` // Extra CLI providers, by name
+}
+
+// ProviderConfig declares a command-line AI tool as a named provider. The
+// prompt is passed as the last argument, after PromptFlag when set, or on
+// stdin when PromptStdin is true. The tool must print the JSON signal on
+// stdout.
+type ProviderConfig struct {
+	Command     string        `yaml:"command"`      // Executable name or path
+	Args        []string      `yaml:"args"`         // Arguments placed before the prompt
+	PromptFlag  string        `yaml:"prompt_flag"`  // Flag that precedes the prompt (e.g. "-p"); "" passes it positionally
+	PromptStdin bool          `yaml:"prompt_stdin"` // Write the prompt to stdin instead of an argument
+	Timeout     time.Duration `yaml:"timeout"`      // Per-invocation timeout; 0 uses runtime.timeout
+	Stream      bool          `yaml:"stream"`       // Stdout is NDJSON; progress lines are reported while the phase runs
 }
 
 // Worktree holds worktree directory settings.
 type Worktree struct {
-	BaseDir string `yaml:"base_dir"`
+	BaseDir       string `yaml:"base_dir"`       // Relative to the repo root, absolute, or ~/...
+	DirTemplate   string `yaml:"dir_template"`   // Go template for each worktree's directory name; "" uses "{{.BeadID}}"
+	BaseBranch    string `yaml:"base_branch"`    // Branch capsules start from and merge into; empty detects main
+	MergeStrategy string `yaml:"merge_strategy"` // "no-ff" (alias "merge") | "squash" | "rebase-ff"
+
+	MergeMessageTemplate string `yaml:"merge_message_template"` // Go template for the merge commit message; "" uses "{{.BeadID}}: pipeline complete"
 }
 
 // Pipeline holds pipeline execution settings.
 type Pipeline struct {
-	Phases     string      `yaml:"phases"`     // "default" | "minimal" | path to YAML
-	Checkpoint bool        `yaml:"checkpoint"` // Enable state checkpointing
-	Retry      RetryConfig `yaml:"retry"`      // Pipeline-wide retry defaults
+	Phases     string                   `yaml:"phases"`     // "default" | "minimal" | path to YAML
+	Checkpoint bool                     `yaml:"checkpoint"` // Enable state checkpointing
+	Retry      RetryConfig              `yaml:"retry"`      // Pipeline-wide retry defaults
+	Overrides  map[string]PhaseOverride `yaml:"overrides"`  // Field changes to phases, by name
+	Profiles   map[string]PhaseProfile  `yaml:"profiles"`   // Named pipelines selected with --profile
+
+	CheckpointRetention time.Duration `yaml:"checkpoint_retention"` // Checkpoints older than this are pruned; 0 keeps them
+
+	ContextFiles        []string `yaml:"context_files"`          // Repo files exposed to prompt templates
+	ContextFileMaxBytes int      `yaml:"context_file_max_bytes"` // Per-file cap for context_files
+
+	GateOutputMaxBytes int `yaml:"gate_output_max_bytes"` // Tail of a failed gate's output kept as feedback
+
+	SaveTranscripts bool `yaml:"save_transcripts"` // Save each phase's prompt and raw output under .capsule/logs
+
+	PerPhaseCommits bool `yaml:"per_phase_commits"` // Commit the worktree after each passing phase
+
+	FileFindings       bool   `yaml:"file_findings"`        // File reviewer findings from capsule run as child beads
+	FindingMinSeverity string `yaml:"finding_min_severity"` // Least severe finding that is filed
+
+	Workdirs map[string]string `yaml:"workdirs"` // Bead ID prefix → default worktree-relative working directory
+}
+
+// PhaseProfile is a named pipeline selectable with --profile.
+type PhaseProfile struct {
+	Phases    string                   `yaml:"phases"`    // Defaults to pipeline.phases
+	Overrides map[string]PhaseOverride `yaml:"overrides"` // Applied after pipeline.overrides when Phases is unset
+}
+
+// PhaseOverride changes selected fields of a phase in the pipeline. Unset
+// fields keep the phase's value. Its fields match orchestrator.PhaseOverride
+// so one converts directly to the other.
+type PhaseOverride struct {
+	Prompt            *string        `yaml:"prompt"`
+	Command           *string        `yaml:"command"`
+	MaxRetries        *int           `yaml:"max_retries"`
+	RetryTarget       *string        `yaml:"retry_target"`
+	Optional          *bool          `yaml:"optional"`
+	Condition         *string        `yaml:"condition"`
+	Provider          *string        `yaml:"provider"`
+	Timeout           *time.Duration `yaml:"timeout"`
+	Workdir           *string        `yaml:"workdir"`
+	RequiredArtifacts *[]string      `yaml:"required_artifacts"`
+}
+
+// ErrUnknownProfile is returned by Pipeline.Resolve for a profile that is
+// not defined under pipeline.profiles.
+var ErrUnknownProfile = errors.New("config: unknown pipeline profile")
+
+// Resolve returns the phases specifier and the override sets, in the order
+// they apply, for the named profile. An empty profile selects the top-level
+// pipeline.phases and pipeline.overrides. A profile that inherits
+// pipeline.phases also inherits pipeline.overrides; one that names its own
+// phases uses only its own overrides.
+func (p Pipeline) Resolve(profile string) (string, []map[string]PhaseOverride, error) {
+	if profile == "" {
+		return p.Phases, []map[string]PhaseOverride{p.Overrides}, nil
+	}
+	prof, ok := p.Profiles[profile]
+	if !ok {
+		names := make([]string, 0, len(p.Profiles))
+		for name := range p.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return "", nil, fmt.Errorf("%w %q (none defined under pipeline.profiles)", ErrUnknownProfile, profile)
+		}
+		return "", nil, fmt.Errorf("%w %q (defined: %s)", ErrUnknownProfile, profile, strings.Join(names, ", "))
+	}
+	if prof.Phases == "" {
+		return p.Phases, []map[string]PhaseOverride{p.Overrides, prof.Overrides}, nil
+	}
+	return prof.Phases, []map[string]PhaseOverride{prof.Overrides}, nil
 }
 
 // RetryConfig holds retry strategy settings.
@@ -48,34 +156,93 @@ type RetryConfig struct {
 
 // Campaign holds campaign orchestration settings.
 type Campaign struct {
-	FailureMode      string `yaml:"failure_mode"`      // "abort" | "continue"
-	CircuitBreaker   int    `yaml:"circuit_breaker"`   // Consecutive failures before stopping
-	DiscoveryFiling  bool   `yaml:"discovery_filing"`  // File findings as new beads
-	CrossRunContext  bool   `yaml:"cross_run_context"` // Include sibling context in prompts
-	ValidationPhases string `yaml:"validation_phases"` // Phase set for feature validation
+	FailureMode       string `yaml:"failure_mode"`            // "abort" (or "stop") | "continue" | "skip-dependents"
+	Ordering          string `yaml:"ordering"`                // "priority" | "type-then-priority" | "as-listed"
+	CircuitBreaker    int    `yaml:"circuit_breaker"`         // Failures before stopping
+	BreakerMode       string `yaml:"circuit_breaker_mode"`    // "consecutive" | "total"
+	BreakerSetup      int    `yaml:"circuit_breaker_setup"`   // Provider/setup failure limit; 0 uses circuit_breaker
+	BreakerSignal     int    `yaml:"circuit_breaker_signal"`  // NEEDS_WORK/ERROR failure limit; 0 uses circuit_breaker
+	DiscoveryFiling   bool   `yaml:"discovery_filing"`        // File findings as new beads
+	PickUpDiscoveries bool   `yaml:"pick_up_discoveries"`     // Run filed findings in the same campaign
+	CrossRunContext   bool   `yaml:"cross_run_context"`       // Include sibling context in prompts
+	ValidationPhases  string `yaml:"validation_phases"`       // Phase set for feature validation
+	CloseParent       bool   `yaml:"close_parent_on_success"` // Close the parent bead after a fully successful campaign
+	Concurrency       int    `yaml:"concurrency"`             // Task pipelines run at once
+	MaxProviderCalls  int    `yaml:"max_provider_calls"`      // Provider calls per task pipeline; 0 means no limit
+}
+
+// Breakers returns the provider/setup and NEEDS_WORK/ERROR failure limits,
+// falling back to CircuitBreaker for a limit that is not set.
+func (c Campaign) Breakers() (setup, signal int) {
+	setup, signal = c.BreakerSetup, c.BreakerSignal
+	if setup == 0 {
+		setup = c.CircuitBreaker
+	}
+	if signal == 0 {
+		signal = c.CircuitBreaker
+	}
+	return setup, signal
+}
+
+// Notifications holds hooks fired when a pipeline or campaign finishes.
+type Notifications struct {
+	Command []string      `yaml:"command"` // Exec hook; each argument is a text/template
+	Webhook string        `yaml:"webhook"` // URL that receives a JSON POST
+	Timeout time.Duration `yaml:"timeout"` // Per-hook timeout
+}
+
+// Dashboard holds settings for the interactive dashboard.
+type Dashboard struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval"` // Reload the bead list this often while browsing; 0 disables
+	BeadCacheTTL    time.Duration `yaml:"bead_cache_ttl"`   // Reuse bd results this long before running bd again; 0 disables
+}
+
+// Bead holds settings for how capsule updates beads in bd.
+type Bead struct {
+	PostSummaryComment bool `yaml:"post_summary_comment"` // Comment a run summary on each bead capsule closes
 }
 
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() Config {
 	return Config{
 		Runtime: Runtime{
-			Provider: "claude",
-			Timeout:  5 * time.Minute,
+			Provider:  "claude",
+			Timeout:   5 * time.Minute,
+			Script:    ".capsule/scripted.yaml",
+			KillGrace: 10 * time.Second,
+
+			TransientRetries: 3,
+			BackoffMax:       time.Minute,
 		},
 		Worktree: Worktree{
-			BaseDir: ".capsule/worktrees",
+			BaseDir:       ".capsule/worktrees",
+			MergeStrategy: "no-ff",
 		},
 		Pipeline: Pipeline{
-			Phases:     "default",
-			Checkpoint: false,
+			Phases:              "default",
+			Checkpoint:          false,
+			CheckpointRetention: 14 * 24 * time.Hour,
 			Retry: RetryConfig{
 				MaxAttempts:   3,
 				BackoffFactor: 1.0,
 			},
+			ContextFiles:        []string{"CONVENTIONS.md", "docs/ARCHITECTURE.md"},
+			ContextFileMaxBytes: 16 * 1024,
+			GateOutputMaxBytes:  8 * 1024,
+			FindingMinSeverity:  "minor",
 		},
 		Campaign: Campaign{
 			FailureMode:    "abort",
+			Ordering:       "priority",
 			CircuitBreaker: 3,
+			BreakerMode:    "consecutive",
+			Concurrency:    1,
+		},
+		Notifications: Notifications{
+			Timeout: 10 * time.Second,
+		},
+		Dashboard: Dashboard{
+			BeadCacheTTL: 30 * time.Second,
 		},
 	}
 }
@@ -99,6 +266,7 @@ func Load(path string) (*Config, error) {
 		return &cfg, nil
 	}
 
+	pos := positionsOf(data, path)
 	dec := yaml.NewDecoder(bytes.NewReader(data))
 	dec.KnownFields(true)
 	if err := dec.Decode(&cfg); err != nil {
@@ -106,19 +274,37 @@ func Load(path string) (*Config, error) {
 		if errors.Is(err, io.EOF) {
 			return &cfg, nil
 		}
-		return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+		problems, err := decodeProblems(err, path, pos)
+		if err != nil {
+			return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+		}
+		return nil, &ValidationError{Problems: problems}
 	}
+	cfg.mergeSources(pos)
 
+	if err := cfg.interpolate(os.LookupEnv); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
 
 // LoadLayered loads config from multiple paths with increasing priority.
-// Later paths override earlier ones. Missing files are skipped.
+// Later paths override earlier ones. Missing files are skipped. After the
+// layers are merged, ${VAR} and ${VAR:-default} references in string values
+// are expanded from the environment. Unknown fields and values of the wrong
+// type in any layer are reported together as a *ValidationError. The config
+// records which file and line set each value, for Validate and Source.
 func LoadLayered(paths ...string) (*Config, error) {
 	cfg := DefaultConfig()
 
+	var problems []Problem
 	for _, path := range paths {
-		layer, err := loadLayer(path)
+		layer, pos, err := loadLayer(path)
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			problems = append(problems, ve.Problems...)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -126,49 +312,272 @@ func LoadLayered(paths ...string) (*Config, error) {
 			continue
 		}
 		cfg.merge(layer)
+		cfg.mergeSources(pos)
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
 	}
 
+	if err := cfg.interpolate(os.LookupEnv); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
 
-// Validate checks that config values are usable.
-func (c *Config) Validate() error {
+// Validate checks that config values are usable. It reports every problem
+// it finds as a *ValidationError, each with the YAML path of the value and,
+// when known, the file and line or override that set it.
+func (c *Config) Validate(opts ...ValidateOption) error {
+	var o validateOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	l := &problemList{c: c}
+
 	if c.Runtime.Provider == "" {
-		return errors.New("config: runtime.provider cannot be empty")
+		l.add("runtime.provider", "cannot be empty")
 	}
 	if c.Runtime.Timeout <= 0 {
-		return fmt.Errorf("config: runtime.timeout must be positive, got %v", c.Runtime.Timeout)
+		l.add("runtime.timeout", "must be positive, got %v", c.Runtime.Timeout)
+	}
+	if c.Runtime.KillGrace < 0 {
+		l.add("runtime.kill_grace", "must be non-negative, got %v", c.Runtime.KillGrace)
+	}
+	if c.Runtime.TransientRetries < 0 {
+		l.add("runtime.transient_retries", "must be non-negative, got %d", c.Runtime.TransientRetries)
+	}
+	if c.Runtime.BackoffMax < 0 {
+		l.add("runtime.backoff_max", "must be non-negative, got %v", c.Runtime.BackoffMax)
+	}
+	if c.Runtime.Listen != "" {
+		if _, _, err := net.SplitHostPort(c.Runtime.Listen); err != nil {
+			l.add("runtime.listen", "must be host:port, got %q", c.Runtime.Listen)
+		}
+	}
+	for _, name := range sortedKeys(c.Runtime.Providers) {
+		p := c.Runtime.Providers[name]
+		path := joinKey("runtime.providers", name)
+		if name == "" {
+			l.add("runtime.providers", "keys must be non-empty provider names")
+			continue
+		}
+		if p.Command == "" {
+			l.add(path+".command", "cannot be empty")
+		}
+		if p.PromptFlag != "" && p.PromptStdin {
+			l.add(path, "cannot set both prompt_flag and prompt_stdin")
+		}
+		if p.Timeout < 0 {
+			l.add(path+".timeout", "must be non-negative, got %v", p.Timeout)
+		}
 	}
 	if c.Worktree.BaseDir == "" {
-		return errors.New("config: worktree.base_dir cannot be empty")
+		l.add("worktree.base_dir", "cannot be empty")
+	}
+	switch c.Worktree.MergeStrategy {
+	case "", "no-ff", "merge", "squash", "rebase-ff":
+		// valid
+	default:
+		l.add("worktree.merge_strategy", "must be \"no-ff\" (or \"merge\"), \"squash\", or \"rebase-ff\", got %q", c.Worktree.MergeStrategy)
+	}
+	if err := validateDirTemplate(c.Worktree.DirTemplate); err != nil {
+		l.add("worktree.dir_template", "%v", err)
+	}
+	if err := validateMergeMessageTemplate(c.Worktree.MergeMessageTemplate); err != nil {
+		l.add("worktree.merge_message_template", "%v", err)
 	}
 	if c.Pipeline.Retry.MaxAttempts < 0 {
-		return fmt.Errorf("config: pipeline.retry.max_attempts must be non-negative, got %d", c.Pipeline.Retry.MaxAttempts)
+		l.add("pipeline.retry.max_attempts", "must be non-negative, got %d", c.Pipeline.Retry.MaxAttempts)
 	}
 	if c.Pipeline.Retry.BackoffFactor < 0 {
-		return fmt.Errorf("config: pipeline.retry.backoff_factor must be non-negative, got %v", c.Pipeline.Retry.BackoffFactor)
+		l.add("pipeline.retry.backoff_factor", "must be non-negative, got %v", c.Pipeline.Retry.BackoffFactor)
 	}
 	// BackoffFactor in (0, 1.0) would shrink timeouts on retry; reject.
 	if c.Pipeline.Retry.BackoffFactor > 0 && c.Pipeline.Retry.BackoffFactor < 1.0 {
-		return fmt.Errorf("config: pipeline.retry.backoff_factor must be 0 (disabled) or >= 1.0, got %v", c.Pipeline.Retry.BackoffFactor)
+		l.add("pipeline.retry.backoff_factor", "must be 0 (disabled) or >= 1.0, got %v", c.Pipeline.Retry.BackoffFactor)
+	}
+	for i, path := range c.Pipeline.ContextFiles {
+		if !filepath.IsLocal(path) {
+			l.add(fmt.Sprintf("pipeline.context_files[%d]", i), "must be a relative path inside the repository, got %q", path)
+		}
+	}
+	if c.Pipeline.CheckpointRetention < 0 {
+		l.add("pipeline.checkpoint_retention", "must be non-negative, got %v", c.Pipeline.CheckpointRetention)
+	}
+	if c.Pipeline.ContextFileMaxBytes < 0 {
+		l.add("pipeline.context_file_max_bytes", "must be non-negative, got %d", c.Pipeline.ContextFileMaxBytes)
+	}
+	if c.Pipeline.GateOutputMaxBytes < 0 {
+		l.add("pipeline.gate_output_max_bytes", "must be non-negative, got %d", c.Pipeline.GateOutputMaxBytes)
+	}
+	for _, prefix := range sortedKeys(c.Pipeline.Workdirs) {
+		if prefix == "" {
+			l.add("pipeline.workdirs", "keys must be non-empty bead ID prefixes")
+			continue
+		}
+		if dir := c.Pipeline.Workdirs[prefix]; !filepath.IsLocal(dir) {
+			l.add(joinKey("pipeline.workdirs", prefix), "must be a relative path inside the repository, got %q", dir)
+		}
+	}
+	switch c.Pipeline.FindingMinSeverity {
+	case "", "critical", "major", "minor", "nit":
+		// valid
+	default:
+		l.add("pipeline.finding_min_severity", "must be \"critical\", \"major\", \"minor\", or \"nit\", got %q", c.Pipeline.FindingMinSeverity)
 	}
 	switch c.Campaign.FailureMode {
-	case "", "abort", "continue":
+	case "", "abort", "stop", "continue", "skip-dependents":
 		// valid
 	default:
-		return fmt.Errorf("config: campaign.failure_mode must be \"abort\" or \"continue\", got %q", c.Campaign.FailureMode)
+		l.add("campaign.failure_mode", "must be \"abort\", \"stop\", \"continue\", or \"skip-dependents\", got %q", c.Campaign.FailureMode)
+	}
+	switch c.Campaign.Ordering {
+	case "", "priority", "type-then-priority", "as-listed":
+		// valid
+	default:
+		l.add("campaign.ordering", "must be \"priority\", \"type-then-priority\", or \"as-listed\", got %q", c.Campaign.Ordering)
 	}
 	if c.Campaign.CircuitBreaker < 0 {
-		return fmt.Errorf("config: campaign.circuit_breaker must be non-negative, got %d", c.Campaign.CircuitBreaker)
+		l.add("campaign.circuit_breaker", "must be non-negative, got %d", c.Campaign.CircuitBreaker)
+	}
+	switch c.Campaign.BreakerMode {
+	case "", "consecutive", "total":
+		// valid
+	default:
+		l.add("campaign.circuit_breaker_mode", "must be \"consecutive\" or \"total\", got %q", c.Campaign.BreakerMode)
+	}
+	if c.Campaign.BreakerSetup < 0 {
+		l.add("campaign.circuit_breaker_setup", "must be non-negative, got %d", c.Campaign.BreakerSetup)
+	}
+	if c.Campaign.BreakerSignal < 0 {
+		l.add("campaign.circuit_breaker_signal", "must be non-negative, got %d", c.Campaign.BreakerSignal)
+	}
+	if c.Campaign.Concurrency < 1 {
+		l.add("campaign.concurrency", "must be at least 1, got %d", c.Campaign.Concurrency)
+	}
+	if c.Campaign.MaxProviderCalls < 0 {
+		l.add("campaign.max_provider_calls", "must be non-negative, got %d", c.Campaign.MaxProviderCalls)
+	}
+	if c.Notifications.Timeout < 0 {
+		l.add("notifications.timeout", "must be non-negative, got %v", c.Notifications.Timeout)
+	}
+	if c.Dashboard.RefreshInterval < 0 {
+		l.add("dashboard.refresh_interval", "must be non-negative, got %v", c.Dashboard.RefreshInterval)
+	}
+	if c.Dashboard.BeadCacheTTL < 0 {
+		l.add("dashboard.bead_cache_ttl", "must be non-negative, got %v", c.Dashboard.BeadCacheTTL)
+	}
+	if o.knownProviders != nil {
+		c.checkProviderNames(l, o.knownProviders)
+	}
+	return l.err()
+}
+
+// checkProviderNames reports provider names that are neither in known nor
+// declared under runtime.providers.
+func (c *Config) checkProviderNames(l *problemList, known map[string]bool) {
+	valid := make([]string, 0, len(known)+len(c.Runtime.Providers))
+	for name := range known {
+		valid = append(valid, name)
+	}
+	for name := range c.Runtime.Providers {
+		if !known[name] {
+			valid = append(valid, name)
+		}
+	}
+	sort.Strings(valid)
+	check := func(path, name string) {
+		if name == "" || known[name] {
+			return
+		}
+		if _, declared := c.Runtime.Providers[name]; declared {
+			return
+		}
+		l.add(path, "unknown provider %q (available: %s)", name, strings.Join(valid, ", "))
+	}
+	check("runtime.provider", c.Runtime.Provider)
+	check("pipeline.retry.escalate_provider", c.Pipeline.Retry.EscalateProvider)
+	checkOverrides := func(path string, overrides map[string]PhaseOverride) {
+		for _, phase := range sortedKeys(overrides) {
+			if p := overrides[phase].Provider; p != nil {
+				check(joinKey(joinKey(path, phase), "provider"), *p)
+			}
+		}
+	}
+	checkOverrides("pipeline.overrides", c.Pipeline.Overrides)
+	for _, name := range sortedKeys(c.Pipeline.Profiles) {
+		checkOverrides(joinKey(joinKey("pipeline.profiles", name), "overrides"), c.Pipeline.Profiles[name].Overrides)
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mergeMessageFields holds a sample value for every field a merge message
+// template may reference (see worktree.MergeMessage).
+var mergeMessageFields = map[string]any{
+	"BeadID":       "cap-1",
+	"Title":        "Title",
+	"Type":         "task",
+	"FilesChanged": []string{"file.go"},
+	"Summary":      "Summary",
+}
+
+// dirTemplateFields are the fields a worktree directory name template may
+// reference (see worktree.DirName).
+var dirTemplateFields = map[string]any{
+	"BeadID": "cap-1",
+	"Date":   "2006-01-02",
+}
+
+// validateDirTemplate checks that text parses, references only fields the
+// directory name provides, and renders a single directory name. An empty
+// template is valid.
+func validateDirTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+	tmpl, err := template.New("dir_template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, dirTemplateFields); err != nil {
+		return err
+	}
+	if name := strings.TrimSpace(b.String()); name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("must render a single directory name, got %q", name)
 	}
 	return nil
 }
 
+// validateMergeMessageTemplate checks that text parses and references only
+// fields the merge message provides. An empty template is valid.
+func validateMergeMessageTemplate(text string) error {
+	if text == "" {
+		return nil
+	}
+	tmpl, err := template.New("merge_message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(io.Discard, mergeMessageFields)
+}
+
 // ApplyEnv applies environment variable overrides to the config.
-// Supported variables: CAPSULE_PROVIDER, CAPSULE_TIMEOUT, CAPSULE_WORKTREE_BASE_DIR.
+// Supported variables: CAPSULE_PROVIDER, CAPSULE_TIMEOUT, CAPSULE_SCRIPT,
+// CAPSULE_WORKTREE_BASE_DIR.
 func (c *Config) ApplyEnv() error {
 	if v := os.Getenv("CAPSULE_PROVIDER"); v != "" {
 		c.Runtime.Provider = v
+		c.Override("runtime.provider", "$CAPSULE_PROVIDER")
 	}
 	if v := os.Getenv("CAPSULE_TIMEOUT"); v != "" {
 		d, err := time.ParseDuration(v)
@@ -176,34 +585,75 @@ func (c *Config) ApplyEnv() error {
 			return fmt.Errorf("config: invalid CAPSULE_TIMEOUT %q: %w", v, err)
 		}
 		c.Runtime.Timeout = d
+		c.Override("runtime.timeout", "$CAPSULE_TIMEOUT")
+	}
+	if v := os.Getenv("CAPSULE_SCRIPT"); v != "" {
+		c.Runtime.Script = v
+		c.Override("runtime.script", "$CAPSULE_SCRIPT")
 	}
 	if v := os.Getenv("CAPSULE_WORKTREE_BASE_DIR"); v != "" {
 		c.Worktree.BaseDir = v
+		c.Override("worktree.base_dir", "$CAPSULE_WORKTREE_BASE_DIR")
 	}
 	return nil
 }
 
 // rawConfig mirrors Config but uses pointers to distinguish set vs unset fields.
 type rawConfig struct {
-	Runtime  *rawRuntime  `yaml:"runtime"`
-	Worktree *rawWorktree `yaml:"worktree"`
-	Pipeline *rawPipeline `yaml:"pipeline"`
-	Campaign *rawCampaign `yaml:"campaign"`
+	Runtime       *rawRuntime       `yaml:"runtime"`
+	Worktree      *rawWorktree      `yaml:"worktree"`
+	Pipeline      *rawPipeline      `yaml:"pipeline"`
+	Campaign      *rawCampaign      `yaml:"campaign"`
+	Notifications *rawNotifications `yaml:"notifications"`
+	Dashboard     *rawDashboard     `yaml:"dashboard"`
+	Bead          *rawBead          `yaml:"bead"`
 }
 
 type rawRuntime struct {
-	Provider *string        `yaml:"provider"`
-	Timeout  *time.Duration `yaml:"timeout"`
+	Provider  *string        `yaml:"provider"`
+	Timeout   *time.Duration `yaml:"timeout"`
+	Script    *string        `yaml:"script"`
+	KillGrace *time.Duration `yaml:"kill_grace"`
+
+	TransientRetries *int           `yaml:"transient_retries"`
+	BackoffMax       *time.Duration `yaml:"backoff_max"`
+
+	Listen *string `yaml:"listen"`
+
+	Providers map[string]ProviderConfig `yaml:"providers"`
 }
 
 type rawWorktree struct {
-	BaseDir *string `yaml:"base_dir"`
+	BaseDir       *string `yaml:"base_dir"`
+	DirTemplate   *string `yaml:"dir_template"`
+	BaseBranch    *string `yaml:"base_branch"`
+	MergeStrategy *string `yaml:"merge_strategy"`
+
+	MergeMessageTemplate *string `yaml:"merge_message_template"`
 }
 
 type rawPipeline struct {
-	Phases     *string         `yaml:"phases"`
-	Checkpoint *bool           `yaml:"checkpoint"`
-	Retry      *rawRetryConfig `yaml:"retry"`
+	Phases     *string                  `yaml:"phases"`
+	Checkpoint *bool                    `yaml:"checkpoint"`
+	Retry      *rawRetryConfig          `yaml:"retry"`
+	Overrides  map[string]PhaseOverride `yaml:"overrides"`
+	Profiles   map[string]PhaseProfile  `yaml:"profiles"`
+
+	CheckpointRetention *time.Duration `yaml:"checkpoint_retention"`
+
+	ContextFiles        []string `yaml:"context_files"`
+	ContextFileMaxBytes *int     `yaml:"context_file_max_bytes"`
+
+	GateOutputMaxBytes *int `yaml:"gate_output_max_bytes"`
+
+	SaveTranscripts *bool `yaml:"save_transcripts"`
+
+	PerPhaseCommits *bool `yaml:"per_phase_commits"`
+
+	FileFindings       *bool   `yaml:"file_findings"`
+	FindingMinSeverity *string `yaml:"finding_min_severity"`
+
+	Workdirs map[string]string `yaml:"workdirs"`
 }
 
 type rawRetryConfig struct {
@@ -214,39 +664,68 @@ type rawRetryConfig struct {
 }
 
 type rawCampaign struct {
-	FailureMode      *string `yaml:"failure_mode"`
-	CircuitBreaker   *int    `yaml:"circuit_breaker"`
-	DiscoveryFiling  *bool   `yaml:"discovery_filing"`
-	CrossRunContext  *bool   `yaml:"cross_run_context"`
-	ValidationPhases *string `yaml:"validation_phases"`
+	FailureMode       *string `yaml:"failure_mode"`
+	Ordering          *string `yaml:"ordering"`
+	CircuitBreaker    *int    `yaml:"circuit_breaker"`
+	BreakerMode       *string `yaml:"circuit_breaker_mode"`
+	BreakerSetup      *int    `yaml:"circuit_breaker_setup"`
+	BreakerSignal     *int    `yaml:"circuit_breaker_signal"`
+	DiscoveryFiling   *bool   `yaml:"discovery_filing"`
+	PickUpDiscoveries *bool   `yaml:"pick_up_discoveries"`
+	CrossRunContext   *bool   `yaml:"cross_run_context"`
+	ValidationPhases  *string `yaml:"validation_phases"`
+	CloseParent       *bool   `yaml:"close_parent_on_success"`
+	Concurrency       *int    `yaml:"concurrency"`
+	MaxProviderCalls  *int    `yaml:"max_provider_calls"`
+}
+
+type rawNotifications struct {
+	Command []string       `yaml:"command"`
+	Webhook *string        `yaml:"webhook"`
+	Timeout *time.Duration `yaml:"timeout"`
+}
+
+type rawDashboard struct {
+	RefreshInterval *time.Duration `yaml:"refresh_interval"`
+	BeadCacheTTL    *time.Duration `yaml:"bead_cache_ttl"`
 }
 
-// loadLayer reads a single config file into a rawConfig for selective merging.
-// Returns nil if the file does not exist. Rejects unknown fields.
-func loadLayer(path string) (*rawConfig, error) {
+type rawBead struct {
+	PostSummaryComment *bool `yaml:"post_summary_comment"`
+}
+
+// loadLayer reads a single config file into a rawConfig for selective
+// merging, along with the position of each key. Returns nil if the file does
+// not exist. Unknown fields and mistyped values are a *ValidationError.
+func loadLayer(path string) (*rawConfig, layerPositions, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return nil, nil
+			return nil, layerPositions{}, nil
 		}
-		return nil, fmt.Errorf("config: reading %s: %w", path, err)
+		return nil, layerPositions{}, fmt.Errorf("config: reading %s: %w", path, err)
 	}
 
 	if len(data) == 0 {
-		return nil, nil
+		return nil, layerPositions{}, nil
 	}
 
+	pos := positionsOf(data, path)
 	var raw rawConfig
 	dec := yaml.NewDecoder(bytes.NewReader(data))
 	dec.KnownFields(true)
 	if err := dec.Decode(&raw); err != nil {
 		if errors.Is(err, io.EOF) {
-			return nil, nil
+			return nil, layerPositions{}, nil
 		}
-		return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+		problems, err := decodeProblems(err, path, pos)
+		if err != nil {
+			return nil, layerPositions{}, fmt.Errorf("config: parsing %s: %w", path, err)
+		}
+		return nil, layerPositions{}, &ValidationError{Problems: problems}
 	}
 
-	return &raw, nil
+	return &raw, pos, nil
 }
 
 // merge applies non-nil fields from a rawConfig layer onto this Config.
@@ -258,11 +737,45 @@ func (c *Config) merge(layer *rawConfig) {
 		if layer.Runtime.Timeout != nil {
 			c.Runtime.Timeout = *layer.Runtime.Timeout
 		}
+		if layer.Runtime.Script != nil {
+			c.Runtime.Script = *layer.Runtime.Script
+		}
+		if layer.Runtime.KillGrace != nil {
+			c.Runtime.KillGrace = *layer.Runtime.KillGrace
+		}
+		if layer.Runtime.TransientRetries != nil {
+			c.Runtime.TransientRetries = *layer.Runtime.TransientRetries
+		}
+		if layer.Runtime.BackoffMax != nil {
+			c.Runtime.BackoffMax = *layer.Runtime.BackoffMax
+		}
+		if layer.Runtime.Listen != nil {
+			c.Runtime.Listen = *layer.Runtime.Listen
+		}
+		// Later layers replace providers by name.
+		for name, p := range layer.Runtime.Providers {
+			if c.Runtime.Providers == nil {
+				c.Runtime.Providers = make(map[string]ProviderConfig)
+			}
+			c.Runtime.Providers[name] = p
+		}
 	}
 	if layer.Worktree != nil {
 		if layer.Worktree.BaseDir != nil {
 			c.Worktree.BaseDir = *layer.Worktree.BaseDir
 		}
+		if layer.Worktree.DirTemplate != nil {
+			c.Worktree.DirTemplate = *layer.Worktree.DirTemplate
+		}
+		if layer.Worktree.BaseBranch != nil {
+			c.Worktree.BaseBranch = *layer.Worktree.BaseBranch
+		}
+		if layer.Worktree.MergeStrategy != nil {
+			c.Worktree.MergeStrategy = *layer.Worktree.MergeStrategy
+		}
+		if layer.Worktree.MergeMessageTemplate != nil {
+			c.Worktree.MergeMessageTemplate = *layer.Worktree.MergeMessageTemplate
+		}
 	}
 	if layer.Pipeline != nil {
 		if layer.Pipeline.Phases != nil {
@@ -271,6 +784,50 @@ func (c *Config) merge(layer *rawConfig) {
 		if layer.Pipeline.Checkpoint != nil {
 			c.Pipeline.Checkpoint = *layer.Pipeline.Checkpoint
 		}
+		if layer.Pipeline.CheckpointRetention != nil {
+			c.Pipeline.CheckpointRetention = *layer.Pipeline.CheckpointRetention
+		}
+		// An explicit list, even an empty one, replaces the previous layer's.
+		if layer.Pipeline.ContextFiles != nil {
+			c.Pipeline.ContextFiles = layer.Pipeline.ContextFiles
+		}
+		if layer.Pipeline.ContextFileMaxBytes != nil {
+			c.Pipeline.ContextFileMaxBytes = *layer.Pipeline.ContextFileMaxBytes
+		}
+		if layer.Pipeline.GateOutputMaxBytes != nil {
+			c.Pipeline.GateOutputMaxBytes = *layer.Pipeline.GateOutputMaxBytes
+		}
+		if layer.Pipeline.SaveTranscripts != nil {
+			c.Pipeline.SaveTranscripts = *layer.Pipeline.SaveTranscripts
+		}
+		if layer.Pipeline.PerPhaseCommits != nil {
+			c.Pipeline.PerPhaseCommits = *layer.Pipeline.PerPhaseCommits
+		}
+		if layer.Pipeline.FileFindings != nil {
+			c.Pipeline.FileFindings = *layer.Pipeline.FileFindings
+		}
+		if layer.Pipeline.FindingMinSeverity != nil {
+			c.Pipeline.FindingMinSeverity = *layer.Pipeline.FindingMinSeverity
+		}
+		// Later layers replace overrides, profiles, and workdirs by name.
+		for name, o := range layer.Pipeline.Overrides {
+			if c.Pipeline.Overrides == nil {
+				c.Pipeline.Overrides = make(map[string]PhaseOverride)
+			}
+			c.Pipeline.Overrides[name] = o
+		}
+		for name, prof := range layer.Pipeline.Profiles {
+			if c.Pipeline.Profiles == nil {
+				c.Pipeline.Profiles = make(map[string]PhaseProfile)
+			}
+			c.Pipeline.Profiles[name] = prof
+		}
+		for prefix, dir := range layer.Pipeline.Workdirs {
+			if c.Pipeline.Workdirs == nil {
+				c.Pipeline.Workdirs = make(map[string]string)
+			}
+			c.Pipeline.Workdirs[prefix] = dir
+		}
 		if layer.Pipeline.Retry != nil {
 			if layer.Pipeline.Retry.MaxAttempts != nil {
 				c.Pipeline.Retry.MaxAttempts = *layer.Pipeline.Retry.MaxAttempts
@@ -290,17 +847,61 @@ func (c *Config) merge(layer *rawConfig) {
 		if layer.Campaign.FailureMode != nil {
 			c.Campaign.FailureMode = *layer.Campaign.FailureMode
 		}
+		if layer.Campaign.Ordering != nil {
+			c.Campaign.Ordering = *layer.Campaign.Ordering
+		}
 		if layer.Campaign.CircuitBreaker != nil {
 			c.Campaign.CircuitBreaker = *layer.Campaign.CircuitBreaker
 		}
+		if layer.Campaign.BreakerMode != nil {
+			c.Campaign.BreakerMode = *layer.Campaign.BreakerMode
+		}
+		if layer.Campaign.BreakerSetup != nil {
+			c.Campaign.BreakerSetup = *layer.Campaign.BreakerSetup
+		}
+		if layer.Campaign.BreakerSignal != nil {
+			c.Campaign.BreakerSignal = *layer.Campaign.BreakerSignal
+		}
 		if layer.Campaign.DiscoveryFiling != nil {
 			c.Campaign.DiscoveryFiling = *layer.Campaign.DiscoveryFiling
 		}
+		if layer.Campaign.PickUpDiscoveries != nil {
+			c.Campaign.PickUpDiscoveries = *layer.Campaign.PickUpDiscoveries
+		}
 		if layer.Campaign.CrossRunContext != nil {
 			c.Campaign.CrossRunContext = *layer.Campaign.CrossRunContext
 		}
 		if layer.Campaign.ValidationPhases != nil {
 			c.Campaign.ValidationPhases = *layer.Campaign.ValidationPhases
 		}
+		if layer.Campaign.CloseParent != nil {
+			c.Campaign.CloseParent = *layer.Campaign.CloseParent
+		}
+		if layer.Campaign.Concurrency != nil {
+			c.Campaign.Concurrency = *layer.Campaign.Concurrency
+		}
+		if layer.Campaign.MaxProviderCalls != nil {
+			c.Campaign.MaxProviderCalls = *layer.Campaign.MaxProviderCalls
+		}
+	}
+	if layer.Notifications != nil {
+		if layer.Notifications.Command != nil {
+			c.Notifications.Command = layer.Notifications.Command
+		}
+		if layer.Notifications.Webhook != nil {
+			c.Notifications.Webhook = *layer.Notifications.Webhook
+		}
+		if layer.Notifications.Timeout != nil {
+			c.Notifications.Timeout = *layer.Notifications.Timeout
+		}
+	}
+	if layer.Dashboard != nil && layer.Dashboard.RefreshInterval != nil {
+		c.Dashboard.RefreshInterval = *layer.Dashboard.RefreshInterval
+	}
+	if layer.Dashboard != nil && layer.Dashboard.BeadCacheTTL != nil {
+		c.Dashboard.BeadCacheTTL = *layer.Dashboard.BeadCacheTTL
+	}
+	if layer.Bead != nil && layer.Bead.PostSummaryComment != nil {
+		c.Bead.PostSummaryComment = *layer.Bead.PostSummaryComment
 	}
 }
@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -19,6 +22,12 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Runtime.Timeout != 5*time.Minute {
 		t.Errorf("default timeout = %v, want %v", cfg.Runtime.Timeout, 5*time.Minute)
 	}
+	if cfg.Runtime.KillGrace != 10*time.Second {
+		t.Errorf("default kill grace = %v, want %v", cfg.Runtime.KillGrace, 10*time.Second)
+	}
+	if cfg.Runtime.TransientRetries != 3 || cfg.Runtime.BackoffMax != time.Minute {
+		t.Errorf("default transient retries = %d up to %v, want 3 up to 1m", cfg.Runtime.TransientRetries, cfg.Runtime.BackoffMax)
+	}
 	if cfg.Worktree.BaseDir != ".capsule/worktrees" {
 		t.Errorf("default base dir = %q, want %q", cfg.Worktree.BaseDir, ".capsule/worktrees")
 	}
@@ -32,6 +41,10 @@ func TestLoad_ValidFile(t *testing.T) {
 runtime:
   provider: openai
   timeout: 10m
+  kill_grace: 3s
+  transient_retries: 5
+  backoff_max: 2m
+  listen: 127.0.0.1:7777
 worktree:
   base_dir: /tmp/worktrees
 `), 0o644); err != nil {
@@ -51,6 +64,15 @@ worktree:
 	if cfg.Runtime.Timeout != 10*time.Minute {
 		t.Errorf("timeout = %v, want %v", cfg.Runtime.Timeout, 10*time.Minute)
 	}
+	if cfg.Runtime.KillGrace != 3*time.Second {
+		t.Errorf("kill grace = %v, want %v", cfg.Runtime.KillGrace, 3*time.Second)
+	}
+	if cfg.Runtime.TransientRetries != 5 || cfg.Runtime.BackoffMax != 2*time.Minute {
+		t.Errorf("transient retries = %d up to %v, want 5 up to 2m", cfg.Runtime.TransientRetries, cfg.Runtime.BackoffMax)
+	}
+	if cfg.Runtime.Listen != "127.0.0.1:7777" {
+		t.Errorf("listen = %q, want %q", cfg.Runtime.Listen, "127.0.0.1:7777")
+	}
 	if cfg.Worktree.BaseDir != "/tmp/worktrees" {
 		t.Errorf("base dir = %q, want %q", cfg.Worktree.BaseDir, "/tmp/worktrees")
 	}
@@ -66,7 +88,7 @@ func TestLoad_MissingFile(t *testing.T) {
 
 	// Then sensible defaults are used
 	want := DefaultConfig()
-	if *cfg != want {
+	if !reflect.DeepEqual(*cfg, want) {
 		t.Errorf("Load(missing) = %+v, want defaults %+v", *cfg, want)
 	}
 }
@@ -184,6 +206,15 @@ func TestApplyEnv(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "CAPSULE_SCRIPT overrides scripted provider script",
+			envs: map[string]string{"CAPSULE_SCRIPT": "demo.yaml"},
+			check: func(t *testing.T, c Config) {
+				if c.Runtime.Script != "demo.yaml" {
+					t.Errorf("script = %q, want %q", c.Runtime.Script, "demo.yaml")
+				}
+			},
+		},
 		{
 			name: "CAPSULE_WORKTREE_BASE_DIR overrides base dir",
 			envs: map[string]string{"CAPSULE_WORKTREE_BASE_DIR": "/custom/dir"},
@@ -270,11 +301,45 @@ func TestValidate(t *testing.T) {
 			modify:  func(c *Config) { c.Runtime.Timeout = 0 },
 			wantErr: true,
 		},
+		{
+			name:    "negative kill grace",
+			modify:  func(c *Config) { c.Runtime.KillGrace = -1 * time.Second },
+			wantErr: true,
+		},
+		{
+			name:   "zero kill grace",
+			modify: func(c *Config) { c.Runtime.KillGrace = 0 },
+		},
 		{
 			name:    "empty base dir",
 			modify:  func(c *Config) { c.Worktree.BaseDir = "" },
 			wantErr: true,
 		},
+		{
+			name: "custom provider",
+			modify: func(c *Config) {
+				c.Runtime.Providers = map[string]ProviderConfig{"llm": {Command: "llm", PromptStdin: true, Timeout: time.Minute}}
+			},
+		},
+		{
+			name:    "custom provider without command",
+			modify:  func(c *Config) { c.Runtime.Providers = map[string]ProviderConfig{"llm": {Args: []string{"-m", "gpt"}}} },
+			wantErr: true,
+		},
+		{
+			name: "custom provider with prompt flag and stdin",
+			modify: func(c *Config) {
+				c.Runtime.Providers = map[string]ProviderConfig{"llm": {Command: "llm", PromptFlag: "-p", PromptStdin: true}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom provider with negative timeout",
+			modify: func(c *Config) {
+				c.Runtime.Providers = map[string]ProviderConfig{"llm": {Command: "llm", Timeout: -time.Second}}
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -309,7 +374,7 @@ func TestLoad_CommentOnlyFile(t *testing.T) {
 
 	// Then defaults are returned (comment-only is treated as empty)
 	want := DefaultConfig()
-	if *cfg != want {
+	if !reflect.DeepEqual(*cfg, want) {
 		t.Errorf("Load(comment-only) = %+v, want defaults %+v", *cfg, want)
 	}
 }
@@ -324,7 +389,7 @@ func TestLoadLayered_AllMissing(t *testing.T) {
 
 	// Then defaults are returned
 	want := DefaultConfig()
-	if *cfg != want {
+	if !reflect.DeepEqual(*cfg, want) {
 		t.Errorf("got %+v, want defaults %+v", *cfg, want)
 	}
 }
@@ -341,6 +406,9 @@ func TestDefaultConfig_PipelineDefaults(t *testing.T) {
 	if cfg.Pipeline.Checkpoint {
 		t.Error("pipeline.checkpoint should default to false")
 	}
+	if cfg.Pipeline.CheckpointRetention != 14*24*time.Hour {
+		t.Errorf("pipeline.checkpoint_retention = %v, want 336h", cfg.Pipeline.CheckpointRetention)
+	}
 	if cfg.Pipeline.Retry.MaxAttempts != 3 {
 		t.Errorf("pipeline.retry.max_attempts = %d, want 3", cfg.Pipeline.Retry.MaxAttempts)
 	}
@@ -358,15 +426,24 @@ func TestDefaultConfig_CampaignDefaults(t *testing.T) {
 	if cfg.Campaign.FailureMode != "abort" {
 		t.Errorf("campaign.failure_mode = %q, want %q", cfg.Campaign.FailureMode, "abort")
 	}
+	if cfg.Campaign.Ordering != "priority" {
+		t.Errorf("campaign.ordering = %q, want %q", cfg.Campaign.Ordering, "priority")
+	}
 	if cfg.Campaign.CircuitBreaker != 3 {
 		t.Errorf("campaign.circuit_breaker = %d, want 3", cfg.Campaign.CircuitBreaker)
 	}
 	if cfg.Campaign.DiscoveryFiling {
 		t.Error("campaign.discovery_filing should default to false")
 	}
+	if cfg.Campaign.PickUpDiscoveries {
+		t.Error("campaign.pick_up_discoveries should default to false")
+	}
 	if cfg.Campaign.CrossRunContext {
 		t.Error("campaign.cross_run_context should default to false")
 	}
+	if cfg.Campaign.Concurrency != 1 {
+		t.Errorf("campaign.concurrency = %d, want 1", cfg.Campaign.Concurrency)
+	}
 }
 
 func TestLoad_PipelineConfig(t *testing.T) {
@@ -377,6 +454,7 @@ func TestLoad_PipelineConfig(t *testing.T) {
 pipeline:
   phases: minimal
   checkpoint: true
+  checkpoint_retention: 72h
   retry:
     max_attempts: 5
     backoff_factor: 1.5
@@ -399,6 +477,9 @@ pipeline:
 	if !cfg.Pipeline.Checkpoint {
 		t.Error("checkpoint should be true")
 	}
+	if cfg.Pipeline.CheckpointRetention != 72*time.Hour {
+		t.Errorf("checkpoint_retention = %v, want 72h", cfg.Pipeline.CheckpointRetention)
+	}
 	if cfg.Pipeline.Retry.MaxAttempts != 5 {
 		t.Errorf("max_attempts = %d, want 5", cfg.Pipeline.Retry.MaxAttempts)
 	}
@@ -420,10 +501,17 @@ func TestLoad_CampaignConfig(t *testing.T) {
 	if err := os.WriteFile(cfgPath, []byte(`
 campaign:
   failure_mode: continue
+  ordering: type-then-priority
   circuit_breaker: 5
+  circuit_breaker_mode: total
+  circuit_breaker_setup: 2
   discovery_filing: true
+  pick_up_discoveries: true
   cross_run_context: true
   validation_phases: thorough
+  close_parent_on_success: true
+  concurrency: 4
+  max_provider_calls: 12
 `), 0o644); err != nil {
 		t.Fatal(err)
 	}
@@ -438,18 +526,39 @@ campaign:
 	if cfg.Campaign.FailureMode != "continue" {
 		t.Errorf("failure_mode = %q, want %q", cfg.Campaign.FailureMode, "continue")
 	}
+	if cfg.Campaign.Ordering != "type-then-priority" {
+		t.Errorf("ordering = %q, want %q", cfg.Campaign.Ordering, "type-then-priority")
+	}
 	if cfg.Campaign.CircuitBreaker != 5 {
 		t.Errorf("circuit_breaker = %d, want 5", cfg.Campaign.CircuitBreaker)
 	}
+	if setup, signal := cfg.Campaign.Breakers(); setup != 2 || signal != 5 {
+		t.Errorf("Breakers() = (%d, %d), want (2, 5)", setup, signal)
+	}
+	if !cfg.Campaign.CloseParent {
+		t.Error("close_parent_on_success = false, want true")
+	}
+	if cfg.Campaign.BreakerMode != "total" {
+		t.Errorf("circuit_breaker_mode = %q, want %q", cfg.Campaign.BreakerMode, "total")
+	}
 	if !cfg.Campaign.DiscoveryFiling {
 		t.Error("discovery_filing should be true")
 	}
+	if !cfg.Campaign.PickUpDiscoveries {
+		t.Error("pick_up_discoveries should be true")
+	}
 	if !cfg.Campaign.CrossRunContext {
 		t.Error("cross_run_context should be true")
 	}
 	if cfg.Campaign.ValidationPhases != "thorough" {
 		t.Errorf("validation_phases = %q, want %q", cfg.Campaign.ValidationPhases, "thorough")
 	}
+	if cfg.Campaign.Concurrency != 4 {
+		t.Errorf("concurrency = %d, want 4", cfg.Campaign.Concurrency)
+	}
+	if cfg.Campaign.MaxProviderCalls != 12 {
+		t.Errorf("max_provider_calls = %d, want 12", cfg.Campaign.MaxProviderCalls)
+	}
 }
 
 func TestLoadLayered_PipelineMerge(t *testing.T) {
@@ -491,6 +600,110 @@ pipeline:
 	}
 }
 
+func TestLoadLayered_PipelineProfiles(t *testing.T) {
+	// Given user config with overrides and two profiles, and a project
+	// config that redefines one profile
+	userDir := t.TempDir()
+	projectDir := t.TempDir()
+
+	userCfg := filepath.Join(userDir, "capsule.yaml")
+	if err := os.WriteFile(userCfg, []byte(`
+pipeline:
+  overrides:
+    test-review:
+      max_retries: 5
+  profiles:
+    quick:
+      phases: minimal
+    full:
+      phases: thorough
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	projectCfg := filepath.Join(projectDir, "capsule.yaml")
+	if err := os.WriteFile(projectCfg, []byte(`
+pipeline:
+  overrides:
+    execute:
+      timeout: 10m
+  profiles:
+    quick:
+      overrides:
+        execute:
+          provider: kiro
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When configs are loaded with layered priority
+	cfg, err := LoadLayered(userCfg, projectCfg)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then overrides merge by phase name and profiles are replaced by name
+	if got := cfg.Pipeline.Overrides["test-review"].MaxRetries; got == nil || *got != 5 {
+		t.Errorf("overrides[test-review].max_retries = %v, want 5", got)
+	}
+	if got := cfg.Pipeline.Overrides["execute"].Timeout; got == nil || *got != 10*time.Minute {
+		t.Errorf("overrides[execute].timeout = %v, want 10m", got)
+	}
+	if got := cfg.Pipeline.Profiles["quick"].Phases; got != "" {
+		t.Errorf("profiles[quick].phases = %q, want replaced by project layer", got)
+	}
+	if got := cfg.Pipeline.Profiles["full"].Phases; got != "thorough" {
+		t.Errorf("profiles[full].phases = %q, want %q", got, "thorough")
+	}
+}
+
+func TestPipeline_Resolve(t *testing.T) {
+	kiro := "kiro"
+	base := map[string]PhaseOverride{"execute": {Provider: &kiro}}
+	quick := map[string]PhaseOverride{"merge": {Provider: &kiro}}
+	p := Pipeline{
+		Phases:    "default",
+		Overrides: base,
+		Profiles: map[string]PhaseProfile{
+			"quick": {Phases: "minimal", Overrides: quick},
+			"tuned": {Overrides: quick},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		profile   string
+		wantSpec  string
+		wantSets  int
+		wantError bool
+	}{
+		{name: "no profile", profile: "", wantSpec: "default", wantSets: 1},
+		{name: "profile with phases", profile: "quick", wantSpec: "minimal", wantSets: 1},
+		{name: "profile inherits phases", profile: "tuned", wantSpec: "default", wantSets: 2},
+		{name: "unknown profile", profile: "full", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When the profile is resolved
+			spec, sets, err := p.Resolve(tt.profile)
+
+			// Then the specifier and override sets match
+			if tt.wantError {
+				if !errors.Is(err, ErrUnknownProfile) {
+					t.Errorf("Resolve() error = %v, want ErrUnknownProfile", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if spec != tt.wantSpec || len(sets) != tt.wantSets {
+				t.Errorf("Resolve() = (%q, %d sets), want (%q, %d sets)", spec, len(sets), tt.wantSpec, tt.wantSets)
+			}
+		})
+	}
+}
+
 func TestValidate_PipelineFields(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -524,24 +737,186 @@ func TestValidate_PipelineFields(t *testing.T) {
 			name:   "backoff_factor 2.0 is valid",
 			modify: func(c *Config) { c.Pipeline.Retry.BackoffFactor = 2.0 },
 		},
+		{
+			name:    "context_files outside the repo",
+			modify:  func(c *Config) { c.Pipeline.ContextFiles = []string{"../CONVENTIONS.md"} },
+			wantErr: true,
+		},
+		{
+			name:    "absolute context_files path",
+			modify:  func(c *Config) { c.Pipeline.ContextFiles = []string{"/etc/passwd"} },
+			wantErr: true,
+		},
+		{
+			name:    "workdirs outside the repo",
+			modify:  func(c *Config) { c.Pipeline.Workdirs = map[string]string{"auth-": "../auth"} },
+			wantErr: true,
+		},
+		{
+			name:    "workdirs with empty prefix",
+			modify:  func(c *Config) { c.Pipeline.Workdirs = map[string]string{"": "services/auth"} },
+			wantErr: true,
+		},
+		{
+			name:   "workdirs inside the repo are valid",
+			modify: func(c *Config) { c.Pipeline.Workdirs = map[string]string{"auth-": "services/auth"} },
+		},
+		{
+			name:    "negative context_file_max_bytes",
+			modify:  func(c *Config) { c.Pipeline.ContextFileMaxBytes = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative gate_output_max_bytes",
+			modify:  func(c *Config) { c.Pipeline.GateOutputMaxBytes = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "unknown finding_min_severity",
+			modify:  func(c *Config) { c.Pipeline.FindingMinSeverity = "blocker" },
+			wantErr: true,
+		},
+		{
+			name:   "finding_min_severity nit is valid",
+			modify: func(c *Config) { c.Pipeline.FindingMinSeverity = "nit" },
+		},
 		{
 			name:    "invalid failure_mode",
 			modify:  func(c *Config) { c.Campaign.FailureMode = "invalid" },
 			wantErr: true,
 		},
+		{
+			name:    "invalid ordering",
+			modify:  func(c *Config) { c.Campaign.Ordering = "newest-first" },
+			wantErr: true,
+		},
 		{
 			name:    "negative circuit_breaker",
 			modify:  func(c *Config) { c.Campaign.CircuitBreaker = -1 },
 			wantErr: true,
 		},
+		{
+			name:    "invalid circuit_breaker_mode",
+			modify:  func(c *Config) { c.Campaign.BreakerMode = "rolling" },
+			wantErr: true,
+		},
+		{
+			name:    "negative circuit_breaker_setup",
+			modify:  func(c *Config) { c.Campaign.BreakerSetup = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative circuit_breaker_signal",
+			modify:  func(c *Config) { c.Campaign.BreakerSignal = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "zero concurrency",
+			modify:  func(c *Config) { c.Campaign.Concurrency = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "negative max_provider_calls",
+			modify:  func(c *Config) { c.Campaign.MaxProviderCalls = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative runtime transient_retries",
+			modify:  func(c *Config) { c.Runtime.TransientRetries = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "negative runtime backoff_max",
+			modify:  func(c *Config) { c.Runtime.BackoffMax = -time.Second },
+			wantErr: true,
+		},
+		{
+			name:    "runtime listen without a port",
+			modify:  func(c *Config) { c.Runtime.Listen = "localhost" },
+			wantErr: true,
+		},
+		{
+			name:   "runtime listen with any port",
+			modify: func(c *Config) { c.Runtime.Listen = "127.0.0.1:0" },
+		},
+		{
+			name:    "negative notifications timeout",
+			modify:  func(c *Config) { c.Notifications.Timeout = -time.Second },
+			wantErr: true,
+		},
+		{
+			name:    "negative dashboard refresh_interval",
+			modify:  func(c *Config) { c.Dashboard.RefreshInterval = -time.Second },
+			wantErr: true,
+		},
+		{
+			name:    "negative dashboard bead_cache_ttl",
+			modify:  func(c *Config) { c.Dashboard.BeadCacheTTL = -time.Second },
+			wantErr: true,
+		},
 		{
 			name:   "continue failure_mode is valid",
 			modify: func(c *Config) { c.Campaign.FailureMode = "continue" },
 		},
+		{
+			name:   "stop failure_mode is valid",
+			modify: func(c *Config) { c.Campaign.FailureMode = "stop" },
+		},
+		{
+			name:   "skip-dependents failure_mode is valid",
+			modify: func(c *Config) { c.Campaign.FailureMode = "skip-dependents" },
+		},
+		{
+			name:   "as-listed ordering is valid",
+			modify: func(c *Config) { c.Campaign.Ordering = "as-listed" },
+		},
 		{
 			name:   "zero max_attempts is valid",
 			modify: func(c *Config) { c.Pipeline.Retry.MaxAttempts = 0 },
 		},
+		{
+			name:   "squash merge_strategy is valid",
+			modify: func(c *Config) { c.Worktree.MergeStrategy = "squash" },
+		},
+		{
+			name:   "rebase-ff merge_strategy is valid",
+			modify: func(c *Config) { c.Worktree.MergeStrategy = "rebase-ff" },
+		},
+		{
+			name:    "unknown merge_strategy",
+			modify:  func(c *Config) { c.Worktree.MergeStrategy = "octopus" },
+			wantErr: true,
+		},
+		{
+			name: "merge_message_template with known fields is valid",
+			modify: func(c *Config) {
+				c.Worktree.MergeMessageTemplate = "{{.Type}}({{.BeadID}}): {{.Title}}\n\n{{.Summary}}{{range .FilesChanged}}\n- {{.}}{{end}}"
+			},
+		},
+		{
+			name:    "merge_message_template that does not parse",
+			modify:  func(c *Config) { c.Worktree.MergeMessageTemplate = "{{.BeadID" },
+			wantErr: true,
+		},
+		{
+			name:    "merge_message_template with unknown field",
+			modify:  func(c *Config) { c.Worktree.MergeMessageTemplate = "{{.Author}}" },
+			wantErr: true,
+		},
+		{
+			name:   "dir_template with bead ID and date is valid",
+			modify: func(c *Config) { c.Worktree.DirTemplate = "{{.BeadID}}-{{.Date}}" },
+		},
+		{
+			name:    "dir_template with unknown field",
+			modify:  func(c *Config) { c.Worktree.DirTemplate = "{{.Title}}" },
+			wantErr: true,
+		},
+		{
+			name:    "dir_template that renders a path",
+			modify:  func(c *Config) { c.Worktree.DirTemplate = "{{.Date}}/{{.BeadID}}" },
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -555,6 +930,133 @@ func TestValidate_PipelineFields(t *testing.T) {
 	}
 }
 
+func TestLoadLayered_MergeStrategy(t *testing.T) {
+	// Given a project config that sets only worktree.merge_strategy
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "project.yaml")
+	if err := os.WriteFile(projectPath, []byte("worktree:\n  merge_strategy: squash\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When layered config is loaded
+	cfg, err := LoadLayered(projectPath)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then the strategy is set and base_dir keeps its default
+	if cfg.Worktree.MergeStrategy != "squash" {
+		t.Errorf("merge_strategy = %q, want %q", cfg.Worktree.MergeStrategy, "squash")
+	}
+	if cfg.Worktree.BaseDir != ".capsule/worktrees" {
+		t.Errorf("base_dir = %q, want default", cfg.Worktree.BaseDir)
+	}
+}
+
+func TestLoadLayered_BaseBranch(t *testing.T) {
+	// Given a project config that sets worktree.base_branch
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "project.yaml")
+	if err := os.WriteFile(projectPath, []byte("worktree:\n  base_branch: develop\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When layered config is loaded
+	cfg, err := LoadLayered(projectPath)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then the base branch is set and the merge strategy keeps its default
+	if cfg.Worktree.BaseBranch != "develop" {
+		t.Errorf("base_branch = %q, want %q", cfg.Worktree.BaseBranch, "develop")
+	}
+	if cfg.Worktree.MergeStrategy != "no-ff" {
+		t.Errorf("merge_strategy = %q, want default", cfg.Worktree.MergeStrategy)
+	}
+}
+
+func TestLoadLayered_Notifications(t *testing.T) {
+	// Given a user config with a notify command and a project config with a webhook
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "user.yaml")
+	projectPath := filepath.Join(dir, "project.yaml")
+	userYAML := "notifications:\n  command: [\"notify-send\", \"{{.BeadID}} {{.Status}}\"]\n"
+	projectYAML := "notifications:\n  webhook: https://example.com/hook\n  timeout: 3s\n"
+	if err := os.WriteFile(userPath, []byte(userYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(projectPath, []byte(projectYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When layered config is loaded
+	cfg, err := LoadLayered(userPath, projectPath)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then the command survives from the user layer and the project adds the webhook
+	want := Notifications{
+		Command: []string{"notify-send", "{{.BeadID}} {{.Status}}"},
+		Webhook: "https://example.com/hook",
+		Timeout: 3 * time.Second,
+	}
+	if !reflect.DeepEqual(cfg.Notifications, want) {
+		t.Errorf("notifications = %+v, want %+v", cfg.Notifications, want)
+	}
+}
+
+func TestLoadLayered_DashboardRefreshInterval(t *testing.T) {
+	// Given a user config that enables auto-refresh and a project config that does not mention it
+	dir := t.TempDir()
+	userPath := filepath.Join(dir, "user.yaml")
+	projectPath := filepath.Join(dir, "project.yaml")
+	if err := os.WriteFile(userPath, []byte("dashboard:\n  refresh_interval: 30s\n  bead_cache_ttl: 5s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(projectPath, []byte("runtime:\n  provider: claude\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When layered config is loaded
+	cfg, err := LoadLayered(userPath, projectPath)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then the user's interval and cache TTL survive
+	if cfg.Dashboard.RefreshInterval != 30*time.Second {
+		t.Errorf("dashboard.refresh_interval = %v, want 30s", cfg.Dashboard.RefreshInterval)
+	}
+	if cfg.Dashboard.BeadCacheTTL != 5*time.Second {
+		t.Errorf("dashboard.bead_cache_ttl = %v, want 5s", cfg.Dashboard.BeadCacheTTL)
+	}
+}
+
+func TestLoadLayered_BeadPostSummaryComment(t *testing.T) {
+	// Given a project config that turns on summary comments
+	dir := t.TempDir()
+	projectPath := filepath.Join(dir, "project.yaml")
+	if err := os.WriteFile(projectPath, []byte("bead:\n  post_summary_comment: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When layered config is loaded
+	cfg, err := LoadLayered(filepath.Join(dir, "missing.yaml"), projectPath)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then the setting is on, though it is off by default
+	if !cfg.Bead.PostSummaryComment {
+		t.Error("bead.post_summary_comment = false, want true")
+	}
+	if DefaultConfig().Bead.PostSummaryComment {
+		t.Error("default bead.post_summary_comment = true, want false")
+	}
+}
+
 func TestLoad_EmptyFile(t *testing.T) {
 	// Given an empty config file
 	dir := t.TempDir()
@@ -571,7 +1073,416 @@ func TestLoad_EmptyFile(t *testing.T) {
 
 	// Then defaults are returned
 	want := DefaultConfig()
-	if *cfg != want {
+	if !reflect.DeepEqual(*cfg, want) {
 		t.Errorf("Load(empty) = %+v, want defaults %+v", *cfg, want)
 	}
 }
+
+func TestLoadLayered_Interpolation(t *testing.T) {
+	// Given a config referencing environment variables in nested structs,
+	// string slices, and phase overrides
+	t.Setenv("CAPSULE_HOME", "/srv/capsule")
+	t.Setenv("NOTIFY_BIN", "notify-send")
+	t.Setenv("EMPTY_VAR", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yaml := `
+runtime:
+  provider: ${PROVIDER_UNSET:-claude}
+worktree:
+  base_dir: ${CAPSULE_HOME}/worktrees
+pipeline:
+  overrides:
+    execute:
+      prompt: ${CAPSULE_HOME}/prompts/execute.md
+notifications:
+  command: ["${NOTIFY_BIN}", "cost: $$5 {{.BeadID}}", "${EMPTY_VAR:-fallback}"]
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the config is loaded
+	cfg, err := LoadLayered(path)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then references are expanded, defaults applied, and $$ kept as a literal dollar
+	if cfg.Runtime.Provider != "claude" {
+		t.Errorf("provider = %q, want %q", cfg.Runtime.Provider, "claude")
+	}
+	if cfg.Worktree.BaseDir != "/srv/capsule/worktrees" {
+		t.Errorf("base_dir = %q, want %q", cfg.Worktree.BaseDir, "/srv/capsule/worktrees")
+	}
+	if got := cfg.Pipeline.Overrides["execute"].Prompt; got == nil || *got != "/srv/capsule/prompts/execute.md" {
+		t.Errorf("overrides[execute].prompt = %v, want expanded path", got)
+	}
+	wantCmd := []string{"notify-send", "cost: $5 {{.BeadID}}", "fallback"}
+	if !reflect.DeepEqual(cfg.Notifications.Command, wantCmd) {
+		t.Errorf("notifications.command = %q, want %q", cfg.Notifications.Command, wantCmd)
+	}
+}
+
+func TestLoadLayered_InterpolationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{name: "unset variable", yaml: "worktree:\n  base_dir: ${CAPSULE_TEST_UNSET}/wt\n", wantErr: "worktree.base_dir: environment variable CAPSULE_TEST_UNSET is not set"},
+		{name: "unset in slice", yaml: "notifications:\n  command: [\"ok\", \"${CAPSULE_TEST_UNSET}\"]\n", wantErr: "notifications.command[1]"},
+		{name: "unterminated", yaml: "worktree:\n  base_dir: ${HOME\n", wantErr: "unterminated"},
+		{name: "invalid name", yaml: "worktree:\n  base_dir: ${1BAD}\n", wantErr: "invalid variable name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a config with a bad reference
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When the config is loaded
+			_, err := LoadLayered(path)
+
+			// Then loading fails and names the field
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadLayered() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	env := map[string]string{"A": "x", "EMPTY": ""}
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"${A}/${A}", "x/x"},
+		{"${MISSING:-def}", "def"},
+		{"${A:-def}", "x"},
+		{"${EMPTY}", ""},
+		{"${EMPTY:-def}", "def"},
+		{"${MISSING:-}", ""},
+		{"$$A $${A}", "$A ${A}"},
+		{"$A and $", "$A and $"},
+	}
+	for _, tt := range tests {
+		got, err := expandEnv(tt.in, lookup)
+		if err != nil || got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestLoadLayered_WorkdirsMergeByPrefix(t *testing.T) {
+	// Given a user config and a project config that both map prefixes
+	dir := t.TempDir()
+	user := filepath.Join(dir, "user.yaml")
+	project := filepath.Join(dir, "project.yaml")
+	if err := os.WriteFile(user, []byte("pipeline:\n  workdirs:\n    auth-: auth\n    web-: web\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(project, []byte("pipeline:\n  workdirs:\n    auth-: services/auth\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When they are layered
+	cfg, err := LoadLayered(user, project)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then the project entry replaces the user's for the same prefix only
+	want := map[string]string{"auth-": "services/auth", "web-": "web"}
+	if !reflect.DeepEqual(cfg.Pipeline.Workdirs, want) {
+		t.Errorf("workdirs = %v, want %v", cfg.Pipeline.Workdirs, want)
+	}
+}
+
+func TestLoadLayered_ProvidersMergeByName(t *testing.T) {
+	// Given a user config and a project config that both declare providers
+	dir := t.TempDir()
+	user := filepath.Join(dir, "user.yaml")
+	project := filepath.Join(dir, "project.yaml")
+	userYAML := "runtime:\n  providers:\n    llm:\n      command: llm\n    aider:\n      command: aider\n      args: [--yes, --message]\n"
+	if err := os.WriteFile(user, []byte(userYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	projectYAML := "runtime:\n  providers:\n    llm:\n      command: llm\n      args: [-m, gpt-4o]\n      prompt_stdin: true\n      timeout: 2m\n"
+	if err := os.WriteFile(project, []byte(projectYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When they are layered
+	cfg, err := LoadLayered(user, project)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then the project entry replaces the user's for the same name only
+	want := map[string]ProviderConfig{
+		"llm":   {Command: "llm", Args: []string{"-m", "gpt-4o"}, PromptStdin: true, Timeout: 2 * time.Minute},
+		"aider": {Command: "aider", Args: []string{"--yes", "--message"}},
+	}
+	if !reflect.DeepEqual(cfg.Runtime.Providers, want) {
+		t.Errorf("providers = %+v, want %+v", cfg.Runtime.Providers, want)
+	}
+}
+
+func TestLoadLayered_ContextFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{name: "defaults", yaml: "pipeline:\n  phases: default\n", want: []string{"CONVENTIONS.md", "docs/ARCHITECTURE.md"}},
+		{name: "replaced", yaml: "pipeline:\n  context_files: [CONTRIBUTING.md]\n", want: []string{"CONTRIBUTING.md"}},
+		{name: "cleared", yaml: "pipeline:\n  context_files: []\n", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a project config
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When it is layered over the defaults
+			cfg, err := LoadLayered(path)
+			if err != nil {
+				t.Fatalf("LoadLayered() error = %v", err)
+			}
+
+			// Then an explicit list replaces the default one
+			if !reflect.DeepEqual(cfg.Pipeline.ContextFiles, tt.want) {
+				t.Errorf("context_files = %q, want %q", cfg.Pipeline.ContextFiles, tt.want)
+			}
+			if cfg.Pipeline.ContextFileMaxBytes != 16*1024 {
+				t.Errorf("context_file_max_bytes = %d, want %d", cfg.Pipeline.ContextFileMaxBytes, 16*1024)
+			}
+		})
+	}
+}
+
+func TestLoadLayered_SaveTranscripts(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want bool
+	}{
+		{name: "default", yaml: "pipeline:\n  phases: default\n", want: false},
+		{name: "enabled", yaml: "pipeline:\n  save_transcripts: true\n", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a project config
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When it is layered over the defaults
+			cfg, err := LoadLayered(path)
+			if err != nil {
+				t.Fatalf("LoadLayered() error = %v", err)
+			}
+
+			// Then transcripts are saved only when enabled
+			if cfg.Pipeline.SaveTranscripts != tt.want {
+				t.Errorf("save_transcripts = %v, want %v", cfg.Pipeline.SaveTranscripts, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLayered_PerPhaseCommits(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want bool
+	}{
+		{name: "default", yaml: "pipeline:\n  phases: default\n", want: false},
+		{name: "enabled", yaml: "pipeline:\n  per_phase_commits: true\n", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a project config
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When it is layered over the defaults
+			cfg, err := LoadLayered(path)
+			if err != nil {
+				t.Fatalf("LoadLayered() error = %v", err)
+			}
+
+			// Then phases are committed only when enabled
+			if cfg.Pipeline.PerPhaseCommits != tt.want {
+				t.Errorf("per_phase_commits = %v, want %v", cfg.Pipeline.PerPhaseCommits, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLayered_GateOutputMaxBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want int
+	}{
+		{name: "default", yaml: "pipeline:\n  phases: default\n", want: 8 * 1024},
+		{name: "set", yaml: "pipeline:\n  gate_output_max_bytes: 2048\n", want: 2048},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a project config
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When it is layered over the defaults
+			cfg, err := LoadLayered(path)
+			if err != nil {
+				t.Fatalf("LoadLayered() error = %v", err)
+			}
+
+			// Then the gate output cap is the configured or default value
+			if cfg.Pipeline.GateOutputMaxBytes != tt.want {
+				t.Errorf("gate_output_max_bytes = %d, want %d", cfg.Pipeline.GateOutputMaxBytes, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsEveryProblemWithSource(t *testing.T) {
+	// Given a user config and a project config that each have problems, and
+	// a project entry that replaces a user provider without a command
+	dir := t.TempDir()
+	user := filepath.Join(dir, "user.yaml")
+	project := filepath.Join(dir, "project.yaml")
+	userYAML := "runtime:\n  timeout: -1s\n  providers:\n    llm:\n      command: llm\n"
+	if err := os.WriteFile(user, []byte(userYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	projectYAML := "runtime:\n  providers:\n    llm:\n      prompt_stdin: true\ncampaign:\n  failure_mode: halt\n"
+	if err := os.WriteFile(project, []byte(projectYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadLayered(user, project)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+	cfg.Runtime.Provider = "claud"
+	cfg.Override("runtime.provider", "--provider")
+
+	// When it is validated against the built-in providers
+	err = cfg.Validate(WithKnownProviders("claude", "scripted"))
+
+	// Then every problem is reported with the file and line, or flag, that set it
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Validate() error = %v, want *ValidationError", err)
+	}
+	var got []string
+	for _, p := range ve.Problems {
+		got = append(got, p.String())
+	}
+	want := []string{
+		user + ":2: runtime.timeout: must be positive, got -1s",
+		project + ":3: runtime.providers.llm.command: cannot be empty",
+		project + ":6: campaign.failure_mode: must be \"abort\", \"stop\", \"continue\", or \"skip-dependents\", got \"halt\"",
+		`--provider: runtime.provider: unknown provider "claud" (available: claude, llm, scripted)`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.HasPrefix(err.Error(), "config: 4 problems:\n  ") {
+		t.Errorf("Error() = %q, want a 4-problem report", err.Error())
+	}
+}
+
+func TestLoadLayered_DecodeProblems(t *testing.T) {
+	// Given two layers with mistyped values and unknown fields
+	dir := t.TempDir()
+	user := filepath.Join(dir, "user.yaml")
+	project := filepath.Join(dir, "project.yaml")
+	if err := os.WriteFile(user, []byte("runtime:\n  provder: codex\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(project, []byte("notifications:\n  timeout: 5x\ncampaign:\n  concurrency: two\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When they are loaded
+	_, err := LoadLayered(user, project)
+
+	// Then each problem across both files is reported with its path
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("LoadLayered() error = %v, want *ValidationError", err)
+	}
+	var got []string
+	for _, p := range ve.Problems {
+		got = append(got, p.Source+" "+p.Path)
+	}
+	want := []string{user + ":2 runtime.provder", project + ":2 notifications.timeout", project + ":4 campaign.concurrency"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems at %q, want %q", got, want)
+	}
+	if msg := ve.Problems[0].Message; msg != `unknown field "provder"` {
+		t.Errorf("unknown field message = %q", msg)
+	}
+	if msg := ve.Problems[1].Message; !strings.HasPrefix(msg, `invalid duration "5x"`) {
+		t.Errorf("duration message = %q", msg)
+	}
+}
+
+func TestConfig_SourceFollowsLayersAndEnv(t *testing.T) {
+	// Given a user and a project config that both set runtime.timeout
+	dir := t.TempDir()
+	user := filepath.Join(dir, "user.yaml")
+	project := filepath.Join(dir, "project.yaml")
+	if err := os.WriteFile(user, []byte("runtime:\n  timeout: 1m\n  script: s.yaml\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(project, []byte("\nruntime:\n  timeout: 2m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadLayered(user, project)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Then each value's source is the last layer that set it
+	for path, want := range map[string]string{
+		"runtime.timeout":    project + ":3",
+		"runtime.script":     user + ":3",
+		"runtime.kill_grace": project + ":2", // Unset: its nearest set parent.
+		"worktree.base_dir":  "",             // Default.
+	} {
+		if got := cfg.Source(path); got != want {
+			t.Errorf("Source(%q) = %q, want %q", path, got, want)
+		}
+	}
+
+	// And an env override replaces it
+	t.Setenv("CAPSULE_TIMEOUT", "3m")
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Source("runtime.timeout"); got != "$CAPSULE_TIMEOUT" {
+		t.Errorf("Source(runtime.timeout) after env = %q", got)
+	}
+}
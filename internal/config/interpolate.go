@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// interpolate expands ${VAR} and ${VAR:-default} references in every string
+// field of c, including strings inside slices, maps, and pointers. "$$" is a
+// literal dollar sign. A reference to an unset variable without a default is
+// an error naming the field.
+func (c *Config) interpolate(lookup func(string) (string, bool)) error {
+	return interpolateValue(reflect.ValueOf(c).Elem(), "", lookup)
+}
+
+// interpolateValue walks v, expanding strings in place. path is the dotted
+// YAML key of v, used in error messages.
+func interpolateValue(v reflect.Value, path string, lookup func(string) (string, bool)) error {
+	switch v.Kind() {
+	case reflect.String:
+		s, err := expandEnv(v.String(), lookup)
+		if err != nil {
+			return fmt.Errorf("config: %s: %w", path, err)
+		}
+		v.SetString(s)
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return interpolateValue(v.Elem(), path, lookup)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			if err := interpolateValue(v.Field(i), joinKey(path, name), lookup); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			if err := interpolateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), lookup); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Map values are not addressable: expand a copy and store it back.
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(k))
+			if err := interpolateValue(elem, joinKey(path, k.String()), lookup); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, elem)
+		}
+	}
+	return nil
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// expandEnv replaces ${VAR} and ${VAR:-default} in s. As in the shell, the
+// default is used when VAR is unset or empty. "$$" yields "$"; any other "$"
+// is kept as is.
+func expandEnv(s string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated ${ in %q", s)
+			}
+			expr := s[i+2 : i+2+end]
+			name, def, hasDefault := strings.Cut(expr, ":-")
+			if !validEnvName(name) {
+				return "", fmt.Errorf("invalid variable name in ${%s}", expr)
+			}
+			val, ok := lookup(name)
+			switch {
+			case hasDefault && val == "":
+				b.WriteString(def)
+			case ok:
+				b.WriteString(val)
+			default:
+				return "", fmt.Errorf("environment variable %s is not set (use ${%s:-default} to allow it)", name, name)
+			}
+			i += 2 + end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), nil
+}
+
+// validEnvName reports whether name is a shell variable name.
+func validEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is one invalid or unreadable config value.
+type Problem struct {
+	Path    string // YAML path of the value, e.g. "runtime.providers.llm.command"; "" when unknown.
+	Source  string // Where the value was set: "file:line", an environment variable, or a flag; "" for defaults.
+	Message string
+}
+
+// String formats the problem as "source: path: message", leaving out an
+// unknown source or path.
+func (p Problem) String() string {
+	s := p.Message
+	if p.Path != "" {
+		s = p.Path + ": " + s
+	}
+	if p.Source != "" {
+		s = p.Source + ": " + s
+	}
+	return s
+}
+
+// ValidationError lists every problem found while loading or validating a
+// config, so all of them can be fixed in one pass.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "config: " + e.Problems[0].String()
+	}
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = "  " + p.String()
+	}
+	return fmt.Sprintf("config: %d problems:\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
+
+// ValidateOption configures Validate.
+type ValidateOption func(*validateOptions)
+
+type validateOptions struct {
+	knownProviders map[string]bool
+}
+
+// WithKnownProviders makes Validate check every provider name the config
+// uses against names and the providers declared under runtime.providers.
+// Without it provider names are not checked.
+func WithKnownProviders(names ...string) ValidateOption {
+	return func(o *validateOptions) {
+		o.knownProviders = make(map[string]bool, len(names))
+		for _, n := range names {
+			o.knownProviders[n] = true
+		}
+	}
+}
+
+// problemList collects problems, looking up where each value was set.
+type problemList struct {
+	c    *Config
+	list []Problem
+}
+
+func (l *problemList) add(path, format string, args ...any) {
+	l.list = append(l.list, Problem{Path: path, Source: l.c.Source(path), Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the collected problems as a *ValidationError, or nil.
+func (l *problemList) err() error {
+	if len(l.list) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: l.list}
+}
+
+// Source returns where the value at path was set: "file:line" for a config
+// file, or the environment variable or flag recorded with Override. A path
+// that was not set itself reports its nearest set parent, so a missing
+// field points at the entry it is missing from. It returns "" for defaults.
+func (c *Config) Source(path string) string {
+	for path != "" {
+		if s, ok := c.sources[path]; ok {
+			return s
+		}
+		path = parentPath(path)
+	}
+	return ""
+}
+
+// Override records that the value at path was set by source, such as a
+// command-line flag, replacing what the config files said about it and
+// everything under it.
+func (c *Config) Override(path, source string) {
+	c.dropSources(path)
+	if c.sources == nil {
+		c.sources = make(map[string]string)
+	}
+	c.sources[path] = source
+}
+
+// dropSources forgets the sources recorded for path and everything under it.
+func (c *Config) dropSources(path string) {
+	for p := range c.sources {
+		if p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			delete(c.sources, p)
+		}
+	}
+}
+
+// mergeSources records the positions of a layer loaded on top of c.
+// Entries the layer replaces whole drop what earlier layers set under them.
+func (c *Config) mergeSources(pos layerPositions) {
+	if len(pos.sources) == 0 {
+		return
+	}
+	for _, path := range pos.replaced {
+		c.dropSources(path)
+	}
+	if c.sources == nil {
+		c.sources = make(map[string]string, len(pos.sources))
+	}
+	for path, src := range pos.sources {
+		c.sources[path] = src
+	}
+}
+
+// parentPath returns the path one level up from path, or "" at the top.
+func parentPath(path string) string {
+	i := strings.LastIndexAny(path, ".[")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+// replacedByName lists the maps whose entries a later layer replaces whole
+// instead of merging field by field (see Config.merge).
+var replacedByName = map[string]bool{
+	"runtime.providers":  true,
+	"pipeline.overrides": true,
+	"pipeline.profiles":  true,
+	"pipeline.workdirs":  true,
+}
+
+// layerPositions is where each key of one config file is.
+type layerPositions struct {
+	sources  map[string]string // YAML path → "file:line".
+	replaced []string          // Paths whose value replaces earlier layers' whole.
+}
+
+// positionsOf maps the YAML path of every key in data to its file and line.
+// Data that does not parse yields no positions.
+func positionsOf(data []byte, file string) layerPositions {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return layerPositions{}
+	}
+	pos := layerPositions{sources: make(map[string]string)}
+	var walk func(n *yaml.Node, path string)
+	walk = func(n *yaml.Node, path string) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key, val := n.Content[i], n.Content[i+1]
+				p := joinKey(path, key.Value)
+				pos.sources[p] = fmt.Sprintf("%s:%d", file, key.Line)
+				if replacedByName[path] || val.Kind == yaml.SequenceNode {
+					pos.replaced = append(pos.replaced, p)
+				}
+				walk(val, p)
+			}
+		case yaml.SequenceNode:
+			for i, item := range n.Content {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				pos.sources[p] = fmt.Sprintf("%s:%d", file, item.Line)
+				walk(item, p)
+			}
+		case yaml.AliasNode:
+			walk(n.Alias, path)
+		}
+	}
+	walk(doc.Content[0], "")
+	return pos
+}
+
+var (
+	typeErrorLine   = regexp.MustCompile(`^line (\d+): (.*)$`)
+	unknownField    = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+	invalidDuration = regexp.MustCompile("^cannot unmarshal !!str `(.*)` into time.Duration$")
+)
+
+// decodeProblems turns the field errors of a YAML decode into problems with
+// their file, line, and, where the line holds a key, YAML path. Other errors
+// (bad syntax) are returned as they are.
+func decodeProblems(err error, file string, pos layerPositions) ([]Problem, error) {
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return nil, err
+	}
+	byLine := make(map[int]string)
+	for path, src := range pos.sources {
+		_, lineText, _ := strings.Cut(strings.TrimPrefix(src, file), ":")
+		line, _ := strconv.Atoi(lineText)
+		// Prefer the deepest path on a line: "a: {b: 1}" reports a.b.
+		if cur, ok := byLine[line]; !ok || len(path) > len(cur) {
+			byLine[line] = path
+		}
+	}
+	problems := make([]Problem, 0, len(te.Errors))
+	for _, msg := range te.Errors {
+		p := Problem{Source: file, Message: msg}
+		if m := typeErrorLine.FindStringSubmatch(msg); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			p.Source = fmt.Sprintf("%s:%d", file, line)
+			p.Path = byLine[line]
+			p.Message = m[2]
+		}
+		switch m := unknownField.FindStringSubmatch(p.Message); {
+		case m != nil:
+			p.Message = fmt.Sprintf("unknown field %q", m[1])
+		case invalidDuration.MatchString(p.Message):
+			p.Message = fmt.Sprintf("invalid duration %q (use e.g. 30s, 5m, or 1h)", invalidDuration.FindStringSubmatch(p.Message)[1])
+		}
+		problems = append(problems, p)
+	}
+	return problems, nil
+}
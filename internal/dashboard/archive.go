@@ -1,67 +1,77 @@
 package dashboard
 
 import (
-	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
+	"time"
 )
 
-// ErrInvalidBeadID indicates a bead ID failed path-safety validation.
-var ErrInvalidBeadID = errors.New("archive: invalid bead id")
-
 // ArchiveReader reads archived pipeline results for a given bead.
+// worklog.Manager implements it over the <archiveDir>/<beadID>/ layout.
 type ArchiveReader interface {
 	ReadWorklog(beadID string) (string, error)
 	ReadSummary(beadID string) (string, error)
+	// ReadPhaseEntries returns the phase attempts recorded in the bead's
+	// archived worklog, or an error when it has none that can be parsed.
+	ReadPhaseEntries(beadID string) ([]ArchivedPhase, error)
 }
 
-// FileArchiveReader reads archived worklog and summary files from a base
-// directory with the layout: <baseDir>/<beadID>/worklog.md and summary.md.
-type FileArchiveReader struct {
-	baseDir string
-}
-
-// NewFileArchiveReader creates a FileArchiveReader rooted at baseDir.
-func NewFileArchiveReader(baseDir string) *FileArchiveReader {
-	return &FileArchiveReader{baseDir: baseDir}
-}
-
-// ReadWorklog returns the contents of <baseDir>/<beadID>/worklog.md.
-// Returns os.ErrNotExist if the file does not exist.
-func (r *FileArchiveReader) ReadWorklog(beadID string) (string, error) {
-	return r.readFile(beadID, "worklog.md")
+// ArchivedPhase is one phase attempt read back from an archived worklog.
+type ArchivedPhase struct {
+	Name     string
+	Status   string // Signal status: PASS, NEEDS_WORK, ERROR, or SKIP.
+	Attempt  int    // 0 for a phase that was not run as a numbered attempt.
+	Duration time.Duration
 }
 
-// ReadSummary returns the contents of <baseDir>/<beadID>/summary.md.
-// Returns os.ErrNotExist if the file does not exist.
-func (r *FileArchiveReader) ReadSummary(beadID string) (string, error) {
-	return r.readFile(beadID, "summary.md")
-}
-
-// validateBeadID checks that beadID is safe for use as a path component.
-// Rejects empty, path traversal (/ \ . ..), null bytes, and flag-like IDs (starting with -).
-func validateBeadID(id string) error {
-	if id == "" {
-		return fmt.Errorf("%w: cannot be empty", ErrInvalidBeadID)
+// formatArchivedPhases lists each archived phase once, in worklog order,
+// with its last attempt's status and the time all its attempts took.
+func formatArchivedPhases(phases []ArchivedPhase) string {
+	type row struct {
+		status   string
+		attempts int
+		duration time.Duration
 	}
-	if strings.HasPrefix(id, "-") {
-		return fmt.Errorf("%w: %q (must not start with -)", ErrInvalidBeadID, id)
+	rows := make(map[string]*row, len(phases))
+	var order []string
+	width := 0
+	for _, p := range phases {
+		r, ok := rows[p.Name]
+		if !ok {
+			r = &row{}
+			rows[p.Name] = r
+			order = append(order, p.Name)
+			width = max(width, len([]rune(p.Name)))
+		}
+		r.status = p.Status
+		r.attempts++
+		r.duration += p.Duration
 	}
-	if strings.ContainsAny(id, "/\\\x00") || id == "." || id == ".." {
-		return fmt.Errorf("%w: %q", ErrInvalidBeadID, id)
+
+	var b strings.Builder
+	b.WriteString("Phases:")
+	for _, name := range order {
+		r := rows[name]
+		fmt.Fprintf(&b, "\n  %s %-*s %s", pipeIndicator(archivedStatus(r.status), ""), width, name, r.status)
+		if r.duration > 0 {
+			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("%.1fs", r.duration.Seconds())))
+		}
+		if r.attempts > 1 {
+			fmt.Fprintf(&b, " %s", pipeRetryStyle.Render(fmt.Sprintf("(%d attempts)", r.attempts)))
+		}
 	}
-	return nil
+	return b.String()
 }
 
-func (r *FileArchiveReader) readFile(beadID, filename string) (string, error) {
-	if err := validateBeadID(beadID); err != nil {
-		return "", err
-	}
-	data, err := os.ReadFile(filepath.Join(r.baseDir, beadID, filename))
-	if err != nil {
-		return "", fmt.Errorf("archive: read %s for %s: %w", filename, beadID, err)
+// archivedStatus maps a worklog signal status to the phase status shown
+// for it.
+func archivedStatus(status string) PhaseStatus {
+	switch status {
+	case "PASS":
+		return PhasePassed
+	case "SKIP":
+		return PhaseSkipped
+	default:
+		return PhaseFailed
 	}
-	return string(data), nil
 }
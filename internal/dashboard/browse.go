@@ -13,6 +13,10 @@ const CursorMarker = "▸ "
 // closedBeadLimit is the maximum number of closed beads to fetch.
 const closedBeadLimit = 50
 
+// heldStates are the bd statuses of open beads bd ready leaves out, listed
+// so that beads a campaign or another user claimed stay visible.
+var heldStates = []string{BeadInProgress, BeadBlocked}
+
 // browseState manages the bead list, cursor, and loading/error states
 // for browse mode's left pane. Shows all beads (open + closed) in a tree.
 type browseState struct {
@@ -22,6 +26,13 @@ type browseState struct {
 	loading     bool
 	err         error
 	expandedIDs map[string]bool // Tracks which nodes are expanded
+	paused      map[string]bool // Beads whose pipeline was paused; enter resumes them.
+	selected    map[string]bool // Beads space queued; enter runs them one after another.
+
+	filter    string      // Substring narrowing the list; "" shows every bead.
+	filtering bool        // The filter input has focus and receives keystrokes.
+	filtered  []*treeNode // Pruned copy of roots while filter is set.
+	sortMode  sortMode
 }
 
 // newBrowseState returns a browseState in the loading state.
@@ -29,43 +40,42 @@ func newBrowseState() browseState {
 	return browseState{
 		loading:     true,
 		expandedIDs: make(map[string]bool),
+		paused:      make(map[string]bool),
+		selected:    make(map[string]bool),
 	}
 }
 
-// initBrowse returns a tea.Cmd that fetches both ready and closed beads,
-// merges them, and wraps the result in a BeadListMsg.
+// initBrowse returns a tea.Cmd that fetches ready, in-progress, blocked,
+// and closed beads, merges them, and wraps the result in a BeadListMsg.
 func initBrowse(lister BeadLister) tea.Cmd {
 	return func() tea.Msg {
 		ready, err := lister.Ready()
 		if err != nil {
 			return BeadListMsg{Err: err}
 		}
-		closed, err := lister.Closed(closedBeadLimit)
-		if err != nil {
-			// Closed fetch failure is non-fatal; show ready beads only.
-			return BeadListMsg{Beads: ready}
-		}
-		// Mark closed beads and merge.
+		// Held and closed fetch failures are non-fatal; the list shows
+		// what did load.
+		held, _ := lister.List(heldStates)
+		closed, _ := lister.Closed(closedBeadLimit)
 		for i := range closed {
 			closed[i].Closed = true
 		}
-		merged := mergeBeads(ready, closed)
-		return BeadListMsg{Beads: merged}
+		return BeadListMsg{Beads: mergeBeads(ready, held, closed)}
 	}
 }
 
-// mergeBeads combines ready and closed bead lists, deduplicating by ID.
-// Ready beads take precedence over closed beads with the same ID.
-func mergeBeads(ready, closed []BeadSummary) []BeadSummary {
-	seen := make(map[string]bool, len(ready))
-	merged := make([]BeadSummary, 0, len(ready)+len(closed))
-	for _, b := range ready {
-		seen[b.ID] = true
-		merged = append(merged, b)
-	}
-	for _, b := range closed {
-		if !seen[b.ID] {
-			merged = append(merged, b)
+// mergeBeads combines bead lists, deduplicating by ID. A bead keeps its
+// entry from the first list it appears in, so ready beads take precedence
+// over held and closed beads with the same ID.
+func mergeBeads(lists ...[]BeadSummary) []BeadSummary {
+	seen := make(map[string]bool)
+	var merged []BeadSummary
+	for _, list := range lists {
+		for _, b := range list {
+			if !seen[b.ID] {
+				seen[b.ID] = true
+				merged = append(merged, b)
+			}
 		}
 	}
 	return merged
@@ -98,7 +108,7 @@ func (bs browseState) applyBeadList(beads []BeadSummary, err error) browseState
 	}
 	bs.err = nil
 	bs.roots = buildTree(beads, bs.expandedIDs)
-	bs.flatNodes = flattenTree(bs.roots)
+	bs = bs.relist()
 	// Clamp cursor to valid range after tree rebuild
 	if bs.cursor >= len(bs.flatNodes) {
 		bs.cursor = len(bs.flatNodes) - 1
@@ -116,11 +126,132 @@ func (bs browseState) applyBeadList(beads []BeadSummary, err error) browseState
 			delete(bs.expandedIDs, id)
 		}
 	}
+	// Drop queued beads that are gone or can no longer run.
+	for _, b := range beads {
+		if !queueable(b) {
+			validIDs[b.ID] = false
+		}
+	}
+	for id := range bs.selected {
+		if !validIDs[id] {
+			delete(bs.selected, id)
+		}
+	}
+	return bs
+}
+
+// relist sorts the tree, re-applies the filter, and rebuilds flatNodes.
+// The cursor is left for the caller to place.
+func (bs browseState) relist() browseState {
+	sortTree(bs.roots, bs.sortMode)
+	bs.filtered = nil
+	if bs.filter != "" {
+		bs.filtered = filterTree(bs.roots, bs.filter)
+	}
+	bs.flatNodes = flattenTree(bs.visibleRoots())
+	return bs
+}
+
+// visibleRoots returns the filtered tree while a filter is set, otherwise
+// the full tree.
+func (bs browseState) visibleRoots() []*treeNode {
+	if bs.filter != "" {
+		return bs.filtered
+	}
+	return bs.roots
+}
+
+// setExpanded expands or collapses node. The state is remembered across
+// refreshes only for the unfiltered tree; clearing a filter restores the
+// expansion the user had before.
+func (bs browseState) setExpanded(node *treeNode, expanded bool) {
+	node.expanded = expanded
+	if bs.filter == "" {
+		bs.expandedIDs[node.Bead.ID] = expanded
+	}
+}
+
+// setFilter applies query and moves the cursor to the first match. Clearing
+// the filter keeps the selected bead under the cursor.
+func (bs browseState) setFilter(query string) browseState {
+	selected := bs.SelectedID()
+	bs.filter = query
+	bs = bs.relist()
+	if query == "" {
+		bs.cursor = 0
+		return bs.selectID(selected)
+	}
+	bs.cursor = 0
+	lower := strings.ToLower(query)
+	for i, fn := range bs.flatNodes {
+		if matchesFilter(fn.Node.Bead, lower) {
+			bs.cursor = i
+			break
+		}
+	}
 	return bs
 }
 
+// handleFilterKey edits the filter while its input has focus. Enter keeps
+// the filter and returns keys to the list; esc clears it.
+func (bs browseState) handleFilterKey(msg tea.KeyMsg) (browseState, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		bs.filtering = false
+		return bs.setFilter(""), nil
+	case tea.KeyEnter:
+		bs.filtering = false
+		return bs, nil
+	case tea.KeyBackspace:
+		if r := []rune(bs.filter); len(r) > 0 {
+			return bs.setFilter(string(r[:len(r)-1])), nil
+		}
+		return bs, nil
+	case tea.KeyUp, tea.KeyDown:
+		bs.filtering = false
+		bs, cmd := bs.handleKey(msg)
+		bs.filtering = true
+		return bs, cmd
+	case tea.KeySpace:
+		return bs.setFilter(bs.filter + " "), nil
+	case tea.KeyRunes:
+		return bs.setFilter(bs.filter + string(msg.Runes)), nil
+	}
+	return bs, nil
+}
+
 func (bs browseState) handleKey(msg tea.KeyMsg) (browseState, tea.Cmd) {
+	if bs.filtering {
+		return bs.handleFilterKey(msg)
+	}
 	switch msg.String() {
+	case "/":
+		bs.filtering = true
+		return bs, nil
+
+	case "esc":
+		if bs.filter != "" {
+			return bs.setFilter(""), nil
+		}
+		bs.selected = make(map[string]bool)
+		return bs, nil
+
+	case " ":
+		if bead, ok := bs.SelectedBead(); ok && queueable(bead) {
+			if bs.selected[bead.ID] {
+				delete(bs.selected, bead.ID)
+			} else {
+				bs.selected[bead.ID] = true
+			}
+		}
+		return bs, nil
+
+	case "s":
+		selected := bs.SelectedID()
+		bs.sortMode = bs.sortMode.next()
+		bs = bs.relist()
+		return bs.selectID(selected), nil
+
 	case "up", "k":
 		if len(bs.flatNodes) > 0 {
 			bs.cursor--
@@ -145,18 +276,16 @@ func (bs browseState) handleKey(msg tea.KeyMsg) (browseState, tea.Cmd) {
 			if isExpandable(node) {
 				if node.expanded {
 					// Collapse: hide children
-					bs.expandedIDs[node.Bead.ID] = false
-					node.expanded = false
-					bs.flatNodes = flattenTree(bs.roots)
+					bs.setExpanded(node, false)
+					bs.flatNodes = flattenTree(bs.visibleRoots())
 					// Clamp cursor after collapse
 					if bs.cursor >= len(bs.flatNodes) {
 						bs.cursor = len(bs.flatNodes) - 1
 					}
 				} else {
 					// Expand: show children
-					bs.expandedIDs[node.Bead.ID] = true
-					node.expanded = true
-					bs.flatNodes = flattenTree(bs.roots)
+					bs.setExpanded(node, true)
+					bs.flatNodes = flattenTree(bs.visibleRoots())
 					// Clamp cursor after expand
 					if bs.cursor >= len(bs.flatNodes) {
 						bs.cursor = len(bs.flatNodes) - 1
@@ -197,7 +326,7 @@ func (bs browseState) handleKey(msg tea.KeyMsg) (browseState, tea.Cmd) {
 		// Collapse all nodes
 		bs.expandedIDs = make(map[string]bool)
 		bs.roots = buildTree(getAllBeads(bs.roots), bs.expandedIDs)
-		bs.flatNodes = flattenTree(bs.roots)
+		bs = bs.relist()
 		// Clamp cursor after collapse
 		if bs.cursor >= len(bs.flatNodes) {
 			bs.cursor = len(bs.flatNodes) - 1
@@ -208,10 +337,14 @@ func (bs browseState) handleKey(msg tea.KeyMsg) (browseState, tea.Cmd) {
 		return bs, nil
 
 	case "enter":
+		if len(bs.selected) > 0 {
+			queued := bs.queuedBeads()
+			return bs, func() tea.Msg { return QueueStartMsg{Beads: queued} }
+		}
 		if len(bs.flatNodes) > 0 && bs.cursor < len(bs.flatNodes) {
 			node := bs.flatNodes[bs.cursor].Node
-			if node.Bead.Closed {
-				return bs, nil // Block dispatch on closed items.
+			if node.Bead.Closed || node.Bead.held() != "" {
+				return bs, nil // Block dispatch on closed, claimed, and blocked items.
 			}
 			selected := node.Bead
 			return bs, func() tea.Msg {
@@ -229,6 +362,24 @@ func (bs browseState) handleKey(msg tea.KeyMsg) (browseState, tea.Cmd) {
 	return bs, nil
 }
 
+// queueable reports whether bead can be selected for a queued run: an open
+// bead, not claimed or blocked, that runs a pipeline rather than a campaign.
+func queueable(bead BeadSummary) bool {
+	return !bead.Closed && bead.held() == "" && bead.Type != "feature" && bead.Type != "epic"
+}
+
+// queuedBeads returns the selected beads in tree order, including any a
+// collapsed parent or the filter hides.
+func (bs browseState) queuedBeads() []DispatchMsg {
+	var queued []DispatchMsg
+	for _, b := range getAllBeads(bs.roots) {
+		if bs.selected[b.ID] {
+			queued = append(queued, DispatchMsg{BeadID: b.ID, BeadType: b.Type, BeadTitle: b.Title})
+		}
+	}
+	return queued
+}
+
 // findParentID returns the parent ID for a given bead ID, or "" if it's a root.
 // Example: "demo-1.1.2" -> "demo-1.1", "demo-1" -> ""
 func findParentID(id string) string {
@@ -249,6 +400,21 @@ func (bs browseState) SelectedID() string {
 	return bs.flatNodes[bs.cursor].Node.Bead.ID
 }
 
+// selectID moves the cursor to the bead with the given ID. The cursor is
+// left unchanged if the bead is not in the visible list.
+func (bs browseState) selectID(id string) browseState {
+	if id == "" {
+		return bs
+	}
+	for i, fn := range bs.flatNodes {
+		if fn.Node.Bead.ID == id {
+			bs.cursor = i
+			break
+		}
+	}
+	return bs
+}
+
 // SelectedBead returns the BeadSummary at the current cursor position.
 func (bs browseState) SelectedBead() (BeadSummary, bool) {
 	if len(bs.flatNodes) == 0 || bs.cursor < 0 || bs.cursor >= len(bs.flatNodes) {
@@ -285,6 +451,9 @@ func (bs browseState) View(width, height int, spinnerView string) string {
 	}
 
 	if len(bs.flatNodes) == 0 {
+		if bs.filter != "" {
+			return fmt.Sprintf("No matches for %q — esc clears the filter", bs.filter)
+		}
 		return "No beads — press r to refresh"
 	}
 
@@ -314,13 +483,22 @@ func (bs browseState) View(width, height int, spinnerView string) string {
 			} else {
 				b.WriteString("▶ ")
 			}
-			// Child count badge [N]
-			openCount := openChildCount(fn.Node)
+			// Child count badge [N], counting children a filter hides.
+			openCount := openChildCount(fn.Node.unfiltered())
 			b.WriteString(fmt.Sprintf("[%d] ", openCount))
 		} else {
 			b.WriteString("• ")
 		}
 
+		// Checkbox on queueable beads once any is selected.
+		if len(bs.selected) > 0 && queueable(bead) {
+			if bs.selected[bead.ID] {
+				b.WriteString(successStyle.Render("[x]") + " ")
+			} else {
+				b.WriteString("[ ] ")
+			}
+		}
+
 		if bead.Closed {
 			// Closed items: dim text with check symbol, no priority badge.
 			line := fmt.Sprintf("%s %s %s", bead.ID, SymbolCheck, bead.Title)
@@ -328,7 +506,7 @@ func (bs browseState) View(width, height int, spinnerView string) string {
 				line += " [" + bead.Type + "]"
 			}
 			if hasChildren {
-				stats := treeProgress(fn.Node)
+				stats := treeProgress(fn.Node.unfiltered())
 				line += fmt.Sprintf(" %d/%d", stats.Closed, stats.Total)
 			}
 			b.WriteString(dimStyle.Render(line))
@@ -338,12 +516,21 @@ func (bs browseState) View(width, height int, spinnerView string) string {
 			b.WriteString(" ")
 			b.WriteString(PriorityBadge(bead.Priority))
 			b.WriteString(" ")
+			if bs.paused[bead.ID] {
+				b.WriteString(pausedStyle.Render(SymbolPaused+" paused") + " ")
+			}
+			switch bead.Status {
+			case BeadInProgress:
+				b.WriteString(activeStyle.Render("["+SymbolInProgress+" in progress]") + " ")
+			case BeadBlocked:
+				b.WriteString(blockedStyle.Render("["+SymbolBlocked+" blocked]") + " ")
+			}
 			b.WriteString(bead.Title)
 			if bead.Type != "" {
 				b.WriteString(" [" + bead.Type + "]")
 			}
 			if hasChildren {
-				stats := treeProgress(fn.Node)
+				stats := treeProgress(fn.Node.unfiltered())
 				progress := fmt.Sprintf(" %d/%d", stats.Closed, stats.Total)
 				if stats.Closed == stats.Total && stats.Total > 0 {
 					progress += " " + successStyle.Render(SymbolCheck)
@@ -0,0 +1,274 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func filterBeads() []BeadSummary {
+	return []BeadSummary{
+		{ID: "cap-001", Title: "Auth feature", Priority: 2, Type: "feature"},
+		{ID: "cap-001.1", Title: "Login form", Priority: 1, Type: "task"},
+		{ID: "cap-001.2", Title: "Logout button", Priority: 3, Type: "bug"},
+		{ID: "cap-002", Title: "Docs cleanup", Priority: 0, Type: "chore"},
+		{ID: "cap-003", Title: "Old migration", Priority: 1, Type: "task", Closed: true},
+	}
+}
+
+func runeKeys(s string) []tea.KeyMsg {
+	keys := make([]tea.KeyMsg, 0, len(s))
+	for _, r := range s {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return keys
+}
+
+// typeFilter opens the filter input and types query into it.
+func typeFilter(bs browseState, query string) browseState {
+	bs, _ = bs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	for _, k := range runeKeys(query) {
+		bs, _ = bs.Update(k)
+	}
+	return bs
+}
+
+func visibleIDs(bs browseState) []string {
+	ids := make([]string, len(bs.flatNodes))
+	for i, fn := range bs.flatNodes {
+		ids[i] = fn.Node.Bead.ID
+	}
+	return ids
+}
+
+func TestBrowse_FilterNarrowsAndKeepsParents(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		want     []string
+		selected string
+	}{
+		{"child match keeps collapsed parent", "logout", []string{"cap-001", "cap-001.2"}, "cap-001.2"},
+		{"matches ID case-insensitively", "CAP-002", []string{"cap-002"}, "cap-002"},
+		{"parent and child both match", "log", []string{"cap-001", "cap-001.1", "cap-001.2"}, "cap-001.1"},
+		{"matches only a closed bead", "migration", []string{"cap-003"}, "cap-003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a loaded tree with cap-001 collapsed
+			bs := newBrowseState()
+			bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+
+			// When: the filter is typed
+			bs = typeFilter(bs, tt.query)
+
+			// Then: only matches and their ancestors are listed
+			if got := strings.Join(visibleIDs(bs), ","); got != strings.Join(tt.want, ",") {
+				t.Errorf("visible = %s, want %s", got, strings.Join(tt.want, ","))
+			}
+			// And: the cursor is on the first match, not its parent
+			if got := bs.SelectedID(); got != tt.selected {
+				t.Errorf("selected = %q, want %q", got, tt.selected)
+			}
+		})
+	}
+}
+
+func TestBrowse_FilterNoMatches(t *testing.T) {
+	// Given: a loaded tree
+	bs := newBrowseState()
+	bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+
+	// When: a filter matching nothing is typed
+	bs = typeFilter(bs, "zzz")
+
+	// Then: the pane says so instead of reporting an empty bead list
+	view := bs.View(60, 20, "")
+	if !strings.Contains(view, `No matches for "zzz"`) {
+		t.Errorf("view = %q, want no-matches message", view)
+	}
+	if strings.Contains(view, "No beads") {
+		t.Errorf("view = %q, must not say No beads", view)
+	}
+	if bs.SelectedID() != "" {
+		t.Errorf("selected = %q, want none", bs.SelectedID())
+	}
+}
+
+func TestBrowse_FilterKeepsUnfilteredCounts(t *testing.T) {
+	// Given: a filter that hides one of cap-001's children
+	bs := newBrowseState()
+	bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+	bs = typeFilter(bs, "logout")
+
+	// When: the list is rendered
+	view := stripANSI(bs.View(80, 20, ""))
+
+	// Then: the parent's badge and progress still count both children
+	if !strings.Contains(view, "[2]") || !strings.Contains(view, "0/2") {
+		t.Errorf("view should count hidden children, got:\n%s", view)
+	}
+}
+
+func TestBrowse_EscClearsFilterAndRestoresExpansion(t *testing.T) {
+	// Given: a filtered list that force-expanded collapsed cap-001
+	bs := newBrowseState()
+	bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+	bs = typeFilter(bs, "docs")
+	bs, _ = bs.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if bs.filtering {
+		t.Fatal("enter should close the filter input")
+	}
+	if bs.filter != "docs" {
+		t.Fatalf("filter = %q, want it kept after enter", bs.filter)
+	}
+
+	// When: esc is pressed in the list
+	bs, _ = bs.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	// Then: every root is listed again with cap-001 still collapsed
+	if got := strings.Join(visibleIDs(bs), ","); got != "cap-001,cap-002,cap-003" {
+		t.Errorf("visible = %s, want cap-001,cap-002,cap-003", got)
+	}
+	// And: the previously selected bead stays selected
+	if bs.SelectedID() != "cap-002" {
+		t.Errorf("selected = %q, want cap-002", bs.SelectedID())
+	}
+}
+
+func TestBrowse_FilterBackspace(t *testing.T) {
+	// Given: a filter matching nothing
+	bs := newBrowseState()
+	bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+	bs = typeFilter(bs, "docsx")
+
+	// When: the last character is deleted
+	bs, _ = bs.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+
+	// Then: the shorter filter is applied
+	if bs.filter != "docs" || bs.SelectedID() != "cap-002" {
+		t.Errorf("filter = %q, selected = %q; want docs, cap-002", bs.filter, bs.SelectedID())
+	}
+}
+
+func TestBrowse_FilterSurvivesRefresh(t *testing.T) {
+	// Given: an applied filter
+	bs := newBrowseState()
+	bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+	bs = typeFilter(bs, "logout")
+
+	// When: the bead list is refreshed
+	bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+
+	// Then: the refreshed list is still filtered
+	if got := strings.Join(visibleIDs(bs), ","); got != "cap-001,cap-001.2" {
+		t.Errorf("visible = %s, want cap-001,cap-001.2", got)
+	}
+}
+
+func TestBrowse_SortCycles(t *testing.T) {
+	// Given: roots with distinct priorities and types
+	bs := newBrowseState()
+	bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+	bs = bs.selectID("cap-003")
+
+	steps := []struct {
+		mode sortMode
+		want string
+	}{
+		{sortByPriority, "cap-002,cap-003,cap-001"},
+		{sortByType, "cap-002,cap-001,cap-003"},
+		{sortByID, "cap-001,cap-002,cap-003"},
+	}
+	for _, step := range steps {
+		// When: s is pressed
+		bs, _ = bs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+		// Then: roots are reordered by the next mode
+		if bs.sortMode != step.mode {
+			t.Fatalf("sortMode = %s, want %s", bs.sortMode, step.mode)
+		}
+		if got := strings.Join(visibleIDs(bs), ","); got != step.want {
+			t.Errorf("%s order = %s, want %s", step.mode, got, step.want)
+		}
+		// And: the selection follows the bead
+		if bs.SelectedID() != "cap-003" {
+			t.Errorf("%s: selected = %q, want cap-003", step.mode, bs.SelectedID())
+		}
+	}
+}
+
+func TestBrowse_SortAppliesToChildren(t *testing.T) {
+	// Given: an expanded parent whose children sort differently by priority
+	bs := newBrowseState()
+	bs.expandedIDs["cap-001"] = true
+	bs, _ = bs.Update(BeadListMsg{Beads: filterBeads()})
+
+	// When: sorting by priority
+	bs, _ = bs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+
+	// Then: children are ordered by priority under their parent
+	if got := strings.Join(visibleIDs(bs), ","); got != "cap-002,cap-003,cap-001,cap-001.1,cap-001.2" {
+		t.Errorf("visible = %s", got)
+	}
+	if last := bs.flatNodes[len(bs.flatNodes)-1]; !strings.HasPrefix(last.Prefix, "└") {
+		t.Errorf("last child prefix = %q, want └", last.Prefix)
+	}
+}
+
+func TestModel_FilterInputCapturesGlobalKeys(t *testing.T) {
+	// Given: a browse model with the filter input open
+	m := newSizedModel(120, 24)
+	m.browse, _ = m.browse.Update(BeadListMsg{Beads: filterBeads()})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+
+	// When: q and r are typed
+	var cmd tea.Cmd
+	for _, k := range runeKeys("qr") {
+		updated, cmd = m.Update(k)
+		m = updated.(Model)
+		if cmd != nil {
+			if _, quit := cmd().(tea.QuitMsg); quit {
+				t.Fatal("q in the filter input must not quit")
+			}
+		}
+	}
+
+	// Then: they become the filter rather than quitting or refreshing
+	if m.browse.filter != "qr" || m.browse.loading {
+		t.Errorf("filter = %q, loading = %v; want qr, false", m.browse.filter, m.browse.loading)
+	}
+	// And: the help bar shows the filter being typed
+	if !strings.Contains(stripANSI(m.View()), "qr▏") {
+		t.Errorf("help bar should show the filter input, got:\n%s", stripANSI(m.View()))
+	}
+}
+
+func TestModel_FilterResolvesFirstMatch(t *testing.T) {
+	// Given: a model with a resolver and the first bead's detail shown
+	m, _ := newResolverModel(120, 24)
+	m.browse, _ = m.browse.Update(BeadListMsg{Beads: filterBeads()})
+	m, _ = m.maybeResolve()
+
+	// When: a filter is typed and applied
+	for _, k := range append([]tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune{'/'}}}, runeKeys("docs")...) {
+		updated, _ := m.Update(k)
+		m = updated.(Model)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	// Then: the first match's detail is awaiting the debounced resolve
+	if m.pendingResolveID != "cap-002" {
+		t.Errorf("pendingResolveID = %q, want cap-002", m.pendingResolveID)
+	}
+	// And: the help bar shows the active filter and sort mode
+	view := stripANSI(m.View())
+	for _, want := range []string{`filter: "docs"`, "clear filter", "sort: id"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("help bar missing %q, got:\n%s", want, view)
+		}
+	}
+}
@@ -15,6 +15,8 @@ type stubLister struct {
 	err         error
 	closedBeads []BeadSummary
 	closedErr   error
+	heldBeads   []BeadSummary
+	heldErr     error
 }
 
 func (s *stubLister) Ready() ([]BeadSummary, error) {
@@ -32,6 +34,21 @@ func (s *stubLister) Closed(limit int) ([]BeadSummary, error) {
 	return result, nil
 }
 
+func (s *stubLister) List(states []string) ([]BeadSummary, error) {
+	if s.heldErr != nil {
+		return nil, s.heldErr
+	}
+	var result []BeadSummary
+	for _, state := range states {
+		for _, b := range s.heldBeads {
+			if b.Status == state {
+				result = append(result, b)
+			}
+		}
+	}
+	return result, nil
+}
+
 func sampleBeads() []BeadSummary {
 	return []BeadSummary{
 		{ID: "cap-001", Title: "First task", Priority: 1, Type: "task"},
@@ -705,6 +722,115 @@ func TestBrowse_InitClosedFetchErrorNonFatal(t *testing.T) {
 	}
 }
 
+func TestBrowse_InitIncludesHeldBeads(t *testing.T) {
+	tests := []struct {
+		name    string
+		heldErr error
+		wantIDs []string
+	}{
+		{name: "held beads between ready and closed", wantIDs: []string{"cap-001", "cap-002", "cap-003", "cap-c01"}},
+		{name: "held fetch error is non-fatal", heldErr: fmt.Errorf("bd list failed"), wantIDs: []string{"cap-001", "cap-c01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a lister with ready, in-progress, blocked, and closed beads
+			lister := &stubLister{
+				beads: []BeadSummary{{ID: "cap-001", Title: "Open"}},
+				heldBeads: []BeadSummary{
+					{ID: "cap-003", Title: "Waiting", Status: BeadBlocked},
+					{ID: "cap-002", Title: "Claimed", Status: BeadInProgress},
+					{ID: "cap-001", Title: "Also ready", Status: BeadInProgress},
+				},
+				heldErr:     tt.heldErr,
+				closedBeads: []BeadSummary{{ID: "cap-c01", Title: "Done"}},
+			}
+
+			// When: initBrowse is called
+			msg := initBrowse(lister)().(BeadListMsg)
+
+			// Then: held beads are listed once, in-progress before blocked
+			if msg.Err != nil {
+				t.Fatalf("unexpected error: %v", msg.Err)
+			}
+			var ids []string
+			for _, b := range msg.Beads {
+				ids = append(ids, b.ID)
+			}
+			if strings.Join(ids, " ") != strings.Join(tt.wantIDs, " ") {
+				t.Errorf("beads = %v, want %v", ids, tt.wantIDs)
+			}
+			if msg.Beads[0].Status != "" {
+				t.Errorf("cap-001 status = %q, want the ready entry kept", msg.Beads[0].Status)
+			}
+		})
+	}
+}
+
+func TestBrowse_HeldBeadsShowBadges(t *testing.T) {
+	// Given: an in-progress bead and a blocked bead
+	bs := newBrowseState()
+	bs, _ = bs.Update(BeadListMsg{Beads: []BeadSummary{
+		{ID: "cap-001", Title: "Claimed", Priority: 1, Status: BeadInProgress},
+		{ID: "cap-002", Title: "Waiting", Priority: 2, Status: BeadBlocked},
+	}})
+
+	// When: the view is rendered
+	plain := stripANSI(bs.View(80, 20, ""))
+
+	// Then: each shows its status badge
+	for _, want := range []string{"[" + SymbolInProgress + " in progress] Claimed", "[" + SymbolBlocked + " blocked] Waiting"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("view missing %q, got:\n%s", want, plain)
+		}
+	}
+}
+
+func TestBrowse_HeldBeadsNotDispatched(t *testing.T) {
+	for _, status := range []string{BeadInProgress, BeadBlocked} {
+		t.Run(status, func(t *testing.T) {
+			// Given: the cursor on a held bead
+			bs := newBrowseState()
+			bs, _ = bs.Update(BeadListMsg{Beads: []BeadSummary{{ID: "cap-001", Title: "Held", Type: "task", Status: status}}})
+
+			// When: enter and space are pressed
+			bs, enter := bs.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			bs, _ = bs.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+
+			// Then: nothing runs and nothing is queued
+			if enter != nil {
+				t.Error("enter on a held bead should not produce a command")
+			}
+			if len(bs.selected) != 0 {
+				t.Errorf("selected = %v, want none", bs.selected)
+			}
+		})
+	}
+}
+
+func TestBrowse_ProgressCountsHeldChildrenAsOpen(t *testing.T) {
+	// Given: a parent with a closed, an in-progress, and a blocked child
+	bs := newBrowseState()
+	bs, _ = bs.Update(BeadListMsg{Beads: []BeadSummary{
+		{ID: "demo-1", Title: "Epic", Type: "epic"},
+		{ID: "demo-1.1", Title: "Task A", Closed: true},
+		{ID: "demo-1.2", Title: "Task B", Status: BeadInProgress},
+		{ID: "demo-1.3", Title: "Task C", Status: BeadBlocked},
+	}})
+
+	// When: the view is rendered
+	plain := stripANSI(bs.View(80, 20, ""))
+
+	// Then: two children count as open and one of three is done
+	if !strings.Contains(plain, "[2] demo-1") || !strings.Contains(plain, "1/3") {
+		t.Errorf("view should show [2] open and 1/3 done, got:\n%s", plain)
+	}
+
+	// And: a campaign on the parent would run none of them
+	if n := countOpenChildren(bs.roots, "demo-1"); n != 0 {
+		t.Errorf("countOpenChildren = %d, want 0", n)
+	}
+}
+
 func TestBrowse_MergeBeadsDeduplicates(t *testing.T) {
 	// Given: a bead that appears in both ready and closed lists
 	ready := []BeadSummary{{ID: "cap-001", Title: "Ready version"}}
@@ -1,15 +1,24 @@
 package dashboard
 
-// Cache stores resolved BeadDetail entries keyed by bead ID.
+// Cache stores resolved BeadDetail entries keyed by bead ID, and the
+// rendered detail text of closed beads keyed by bead ID and pane width.
 // It is not safe for concurrent use; callers must synchronize externally
 // or confine access to a single goroutine (e.g., the Bubble Tea update loop).
 type Cache struct {
-	entries map[string]*BeadDetail
+	entries  map[string]*BeadDetail
+	rendered map[renderKey]string
+}
+
+// renderKey identifies a rendered detail: the bead and the width it was
+// wrapped to.
+type renderKey struct {
+	id    string
+	width int
 }
 
 // NewCache creates an empty cache.
 func NewCache() *Cache {
-	return &Cache{entries: make(map[string]*BeadDetail)}
+	return &Cache{entries: make(map[string]*BeadDetail), rendered: make(map[renderKey]string)}
 }
 
 // Get returns the cached detail for the given ID, or nil and false on miss.
@@ -18,12 +27,40 @@ func (c *Cache) Get(id string) (*BeadDetail, bool) {
 	return d, ok
 }
 
-// Set stores a detail entry in the cache, replacing any existing entry.
+// Set stores a detail entry in the cache, replacing any existing entry
+// and dropping the bead's rendered text.
 func (c *Cache) Set(id string, detail *BeadDetail) {
 	c.entries[id] = detail
+	for k := range c.rendered {
+		if k.id == id {
+			delete(c.rendered, k)
+		}
+	}
+}
+
+// Rendered returns the detail text rendered for id at width, or "" and
+// false on miss.
+func (c *Cache) Rendered(id string, width int) (string, bool) {
+	text, ok := c.rendered[renderKey{id, width}]
+	return text, ok
+}
+
+// SetRendered stores the detail text rendered for id at width.
+func (c *Cache) SetRendered(id string, width int, text string) {
+	c.rendered[renderKey{id, width}] = text
 }
 
 // Invalidate clears all cached entries.
 func (c *Cache) Invalidate() {
 	c.entries = make(map[string]*BeadDetail)
+	c.rendered = make(map[renderKey]string)
+}
+
+// invalidateBeads drops the resolved details and the bd cache after work
+// that may have changed beads.
+func (m Model) invalidateBeads() {
+	m.cache.Invalidate()
+	if m.beadCache != nil {
+		m.beadCache.Invalidate()
+	}
 }
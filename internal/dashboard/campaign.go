@@ -2,11 +2,14 @@ package dashboard
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/smileynet/capsule/internal/progressbar"
 )
 
 // campaignState manages the task queue, embedded pipeline state, and
@@ -14,24 +17,30 @@ import (
 type campaignState struct {
 	parentID      string
 	parentTitle   string
-	provider      string // Provider name shown in header badge (optional).
+	provider      string          // Provider name shown in header badge (optional).
+	resumed       *CampaignResume // Saved progress shown in the header when resuming.
 	tasks         []CampaignTaskInfo
 	taskStatuses  []CampaignTaskStatus
 	taskDurations []time.Duration
-	taskErrors    map[string]string        // Error text keyed by bead ID.
+	taskErrors    map[string]string        // Error text or skip reason keyed by bead ID.
 	taskReports   map[string][]PhaseReport // Phase reports keyed by bead ID.
 	currentIdx    int                      // -1 = no task running
 	selectedIdx   int                      // Cursor for browsing tasks (independent of currentIdx).
 	pipeline      pipelineState
 	completed     int
 	failed        int
+	skipped       int
 
 	pausedBeadID  string // Set when campaign pauses due to conflict
 	pausedReason  string
 	pausedDetails string
 
+	circuitBroken *CampaignCircuitBrokenMsg // set when the circuit breaker stops the campaign
+	parentClosed  bool                      // set when the successful campaign closed its parent bead
+
 	validating       bool                       // true while validation pipeline is running
 	validationResult *CampaignValidationDoneMsg // set on validation completion
+	// The validation row follows the tasks, at selectedIdx == len(tasks).
 
 	subcampaign *subcampaignState // nil when no subcampaign active
 }
@@ -73,14 +82,36 @@ func (cs campaignState) Update(msg tea.Msg) (campaignState, tea.Cmd) {
 		return cs.handleTaskStart(msg), nil
 	case CampaignTaskDoneMsg:
 		return cs.handleTaskDone(msg), nil
+	case CampaignTaskSkippedMsg:
+		return cs.handleTaskSkipped(msg), nil
 	case CampaignPausedMsg:
 		return cs.handlePaused(msg), nil
+	case CampaignCircuitBrokenMsg:
+		cs.circuitBroken = &msg
+		return cs, nil
+	case CampaignValidationStartMsg:
+		// Validation phases stream into the embedded pipeline like a task's.
+		cs.validating = true
+		cs.pipeline = cs.pipeline.reset()
+		return cs, nil
+	case CampaignValidationDoneMsg:
+		cs.validating = false
+		cs.validationResult = &msg
+		return cs, nil
 	case SubCampaignStartMsg:
 		return cs.handleSubCampaignStart(msg), nil
 	case SubCampaignDoneMsg:
+		// Hand the spinner back so its tick chain keeps running.
+		if cs.subcampaign != nil {
+			cs.pipeline.spinner = cs.subcampaign.pipeline.spinner
+		}
 		cs.subcampaign = nil
 		return cs, nil
-	case PhaseUpdateMsg, elapsedTickMsg, spinner.TickMsg:
+	case PhaseUpdateMsg, spinner.TickMsg:
+		// Validation runs its own phase set, listed as its phases report.
+		if u, ok := msg.(PhaseUpdateMsg); ok && cs.validating && cs.subcampaign == nil {
+			cs.pipeline = cs.pipeline.withPhase(u.Phase)
+		}
 		var cmd tea.Cmd
 		if cs.subcampaign != nil {
 			cs.subcampaign.pipeline, cmd = cs.subcampaign.pipeline.Update(msg)
@@ -94,39 +125,88 @@ func (cs campaignState) Update(msg tea.Msg) (campaignState, tea.Cmd) {
 	return cs, nil
 }
 
+// fraction is the share of the campaign done: its finished tasks, plus the
+// running task's share of its pipeline or subcampaign.
+func (cs campaignState) fraction() float64 {
+	if len(cs.tasks) == 0 {
+		return 0
+	}
+	done := float64(cs.completed + cs.failed + cs.skipped)
+	if cs.currentIdx >= 0 && cs.currentIdx < len(cs.tasks) && cs.taskStatuses[cs.currentIdx] == CampaignTaskRunning {
+		if sub := cs.subcampaign; sub != nil {
+			if len(sub.tasks) > 0 {
+				finished := 0
+				for _, st := range sub.statuses {
+					if st != CampaignTaskPending && st != CampaignTaskRunning {
+						finished++
+					}
+				}
+				done += float64(finished) / float64(len(sub.tasks))
+			}
+		} else {
+			done += cs.pipeline.fraction
+		}
+	}
+	return min(done/float64(len(cs.tasks)), 1)
+}
+
 func (cs campaignState) handleKey(msg tea.KeyMsg) campaignState {
 	if len(cs.tasks) == 0 {
 		return cs
 	}
+	rows := len(cs.tasks)
+	if cs.hasValidationRow() {
+		rows++
+	}
 	switch msg.String() {
 	case "up", "k":
 		cs.selectedIdx--
 		if cs.selectedIdx < 0 {
-			cs.selectedIdx = len(cs.tasks) - 1
+			cs.selectedIdx = rows - 1
 		}
 	case "down", "j":
 		cs.selectedIdx++
-		if cs.selectedIdx >= len(cs.tasks) {
+		if cs.selectedIdx >= rows {
 			cs.selectedIdx = 0
 		}
 	}
 	return cs
 }
 
+// hasValidationRow reports whether feature validation has started, which
+// adds its row below the tasks.
+func (cs campaignState) hasValidationRow() bool {
+	return cs.validating || cs.validationResult != nil
+}
+
+// validationSelected reports whether the cursor is on the validation row.
+func (cs campaignState) validationSelected() bool {
+	return cs.hasValidationRow() && cs.selectedIdx == len(cs.tasks)
+}
+
+// anyRunning reports whether a phase of the running task (or subcampaign
+// task) is running.
+func (cs campaignState) anyRunning() bool {
+	if cs.subcampaign != nil {
+		return cs.subcampaign.pipeline.anyRunning()
+	}
+	return cs.pipeline.anyRunning()
+}
+
 func (cs campaignState) handleTaskStart(msg CampaignTaskStartMsg) campaignState {
 	if cs.subcampaign != nil {
 		cs.subcampaign.currentIdx = msg.Index
 		if msg.Index >= 0 && msg.Index < len(cs.subcampaign.statuses) {
 			cs.subcampaign.statuses[msg.Index] = CampaignTaskRunning
 		}
-		cs.subcampaign.pipeline = newPipelineState(nil)
+		cs.subcampaign.pipeline = cs.subcampaign.pipeline.reset()
 		return cs
 	}
 	cs.currentIdx = msg.Index
 	if msg.Index >= 0 && msg.Index < len(cs.taskStatuses) {
 		cs.taskStatuses[msg.Index] = CampaignTaskRunning
 	}
-	cs.pipeline = newPipelineState(nil)
+	cs.pipeline = cs.pipeline.reset()
 	return cs
 }
 
@@ -164,6 +244,33 @@ func (cs campaignState) handleTaskDone(msg CampaignTaskDoneMsg) campaignState {
 	return cs
 }
 
+func (cs campaignState) handleTaskSkipped(msg CampaignTaskSkippedMsg) campaignState {
+	if cs.subcampaign != nil {
+		if msg.Index >= 0 && msg.Index < len(cs.subcampaign.statuses) {
+			cs.subcampaign.statuses[msg.Index] = CampaignTaskSkipped
+		}
+		return cs
+	}
+	if msg.Index >= 0 && msg.Index < len(cs.taskStatuses) {
+		cs.taskStatuses[msg.Index] = CampaignTaskSkipped
+		cs.skipped++
+	}
+	cs.taskErrors[msg.BeadID] = msg.Reason
+	return cs
+}
+
+// startRetry marks the failed task at idx as running again for a single-task
+// retry and resets the embedded pipeline for its live phases.
+func (cs campaignState) startRetry(idx int, phaseNames []string) campaignState {
+	cs.taskStatuses[idx] = CampaignTaskRunning
+	cs.taskDurations[idx] = 0
+	cs.failed--
+	delete(cs.taskErrors, cs.tasks[idx].BeadID)
+	cs.currentIdx = idx
+	cs.pipeline = newPipelineState(phaseNames)
+	return cs
+}
+
 func (cs campaignState) handlePaused(msg CampaignPausedMsg) campaignState {
 	cs.pausedBeadID = msg.BeadID
 	cs.pausedReason = msg.Reason
@@ -183,7 +290,7 @@ func (cs campaignState) handleSubCampaignStart(msg SubCampaignStartMsg) campaign
 		durations:    make([]time.Duration, len(msg.Tasks)),
 		reports:      make(map[string][]PhaseReport),
 		currentIdx:   -1,
-		pipeline:     newPipelineState(nil),
+		pipeline:     cs.pipeline.reset(),
 	}
 	return cs
 }
@@ -197,12 +304,19 @@ func (cs campaignState) View(width, height int) string {
 	var b strings.Builder
 
 	// Header line.
-	done := cs.completed + cs.failed
+	done := cs.completed + cs.failed + cs.skipped
 	header := fmt.Sprintf("%s  %s  %d/%d", cs.parentID, cs.parentTitle, done, len(cs.tasks))
+	if cs.resumed != nil {
+		header += fmt.Sprintf("  (resuming, %d/%d done)", cs.resumed.Done, cs.resumed.Total)
+	}
 	if cs.provider != "" {
 		header += "  [" + cs.provider + "]"
 	}
 	b.WriteString(header)
+	b.WriteString("\n" + progressbar.Render(cs.fraction(), progressbar.Width, progressStyles))
+	if cbm := cs.circuitBroken; cbm != nil && cbm.Banner != "" {
+		b.WriteString("\n" + pipeFailedStyle.Render(SymbolCross+" Circuit breaker "+cbm.Banner))
+	}
 
 	// Task queue.
 	for i, task := range cs.tasks {
@@ -217,10 +331,16 @@ func (cs campaignState) View(width, height int) string {
 		}
 
 		indicator := cs.taskIndicator(status)
-		fmt.Fprintf(&b, "%s %s", indicator, task.Title)
+		if blockers := cs.blockers(task); len(blockers) > 0 && status == CampaignTaskPending {
+			fmt.Fprintf(&b, "%s %s", indicator, pipePendingStyle.Render(task.Title+" (blocked by "+strings.Join(blockers, ", ")+")"))
+		} else {
+			fmt.Fprintf(&b, "%s %s", indicator, task.Title)
+		}
 
 		if cs.taskDurations[i] > 0 {
 			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("%.1fs", cs.taskDurations[i].Seconds())))
+		} else if i == cs.currentIdx && status == CampaignTaskRunning && cs.subcampaign == nil && !cs.pipeline.startedAt.IsZero() {
+			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(formatElapsed(cs.pipeline.elapsed())))
 		}
 
 		// Running task: show indented live phases or subcampaign tasks below.
@@ -242,12 +362,8 @@ func (cs campaignState) View(width, height int) string {
 					pInd := pipeIndicator(phase.Status, cs.pipeline.spinner.View())
 					pName := pipePhaseName(phase.Status, phase.Name)
 					fmt.Fprintf(&b, "      %s %s", pInd, pName)
-					if phase.Status == PhaseRunning && !cs.pipeline.phaseStartedAt.IsZero() && !cs.pipeline.aborting {
-						elapsed := int(time.Since(cs.pipeline.phaseStartedAt).Seconds())
-						fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("(%ds)", elapsed)))
-					}
-					if phase.Duration > 0 {
-						fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("%.1fs", phase.Duration.Seconds())))
+					if t := phase.timing(cs.pipeline.aborting); t != "" {
+						fmt.Fprintf(&b, " %s", t)
 					}
 				}
 			}
@@ -269,11 +385,29 @@ func (cs campaignState) View(width, height int) string {
 	}
 
 	// Validation row (shown after all tasks when validation is active or complete).
+	if cs.hasValidationRow() {
+		b.WriteByte('\n')
+		if cs.validationSelected() {
+			b.WriteString(CursorMarker)
+		} else {
+			b.WriteString("  ")
+		}
+	}
 	if cs.validating {
-		b.WriteString("\n  ")
 		fmt.Fprintf(&b, "%s Feature validation", cs.pipeline.spinner.View())
+		if !cs.pipeline.startedAt.IsZero() {
+			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(formatElapsed(cs.pipeline.elapsed())))
+		}
+		for _, phase := range cs.pipeline.phases {
+			b.WriteByte('\n')
+			pInd := pipeIndicator(phase.Status, cs.pipeline.spinner.View())
+			pName := pipePhaseName(phase.Status, phase.Name)
+			fmt.Fprintf(&b, "      %s %s", pInd, pName)
+			if t := phase.timing(cs.pipeline.aborting); t != "" {
+				fmt.Fprintf(&b, " %s", t)
+			}
+		}
 	} else if cs.validationResult != nil {
-		b.WriteString("\n  ")
 		if cs.validationResult.Success {
 			fmt.Fprintf(&b, "%s Feature validation", pipePassedStyle.Render(SymbolCheck))
 		} else {
@@ -282,6 +416,16 @@ func (cs campaignState) View(width, height int) string {
 		if cs.validationResult.Duration > 0 {
 			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("%.1fs", cs.validationResult.Duration.Seconds())))
 		}
+		if cs.validationSelected() {
+			for _, r := range cs.validationResult.PhaseReports {
+				b.WriteByte('\n')
+				ind := pipeIndicator(r.Status, "")
+				fmt.Fprintf(&b, "      %s %s", ind, r.PhaseName)
+				if r.Duration > 0 {
+					fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("%.1fs", r.Duration.Seconds())))
+				}
+			}
+		}
 	}
 
 	return b.String()
@@ -304,6 +448,19 @@ func (cs campaignState) subcampaignTaskIndicator(status CampaignTaskStatus) stri
 	}
 }
 
+// blockers returns the siblings task still waits on: those in BlockedBy
+// that have not passed.
+func (cs campaignState) blockers(task CampaignTaskInfo) []string {
+	var ids []string
+	for _, id := range task.BlockedBy {
+		i := slices.IndexFunc(cs.tasks, func(t CampaignTaskInfo) bool { return t.BeadID == id })
+		if i < 0 || cs.taskStatuses[i] != CampaignTaskPassed {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 func (cs campaignState) taskIndicator(status CampaignTaskStatus) string {
 	switch status {
 	case CampaignTaskPending:
@@ -325,6 +482,9 @@ func (cs campaignState) taskIndicator(status CampaignTaskStatus) string {
 // For the running task, it delegates to the live pipeline. For completed
 // tasks, it renders stored phase reports. For pending tasks, returns empty.
 func (cs campaignState) ViewReport(width, height int) string {
+	if cs.validationSelected() {
+		return cs.viewValidationReport(width, height)
+	}
 	if len(cs.tasks) == 0 || cs.selectedIdx < 0 || cs.selectedIdx >= len(cs.tasks) {
 		return ""
 	}
@@ -336,6 +496,12 @@ func (cs campaignState) ViewReport(width, height int) string {
 		return cs.pipeline.ViewReport(width, height)
 	}
 
+	// Skipped task: show why it did not run.
+	if status == CampaignTaskSkipped {
+		task := cs.tasks[cs.selectedIdx]
+		return fmt.Sprintf("%s\n\n%s", task.Title, pipeSkippedStyle.Render("Skipped: "+cs.taskErrors[task.BeadID]))
+	}
+
 	// Completed/failed task: render stored phase reports.
 	if status == CampaignTaskPassed || status == CampaignTaskFailed {
 		task := cs.tasks[cs.selectedIdx]
@@ -349,6 +515,22 @@ func (cs campaignState) ViewReport(width, height int) string {
 	return ""
 }
 
+// viewValidationReport renders the right pane for the validation row: the
+// live pipeline while it runs, then its stored phase reports.
+func (cs campaignState) viewValidationReport(width, height int) string {
+	if cs.validating {
+		return cs.pipeline.ViewReport(width, height)
+	}
+	vr := cs.validationResult
+	var b strings.Builder
+	fmt.Fprintf(&b, "Feature validation: %s\n", cs.parentID)
+	if vr.Error != "" {
+		fmt.Fprintf(&b, "\n%s\n", pipeFailedStyle.Render("⚠ "+vr.Error))
+	}
+	b.WriteString(formatPhaseReports(vr.PhaseReports))
+	return b.String()
+}
+
 // formatTaskReport renders the stored phase reports for a completed task.
 func (cs campaignState) formatTaskReport(task CampaignTaskInfo, reports []PhaseReport) string {
 	var b strings.Builder
@@ -359,6 +541,14 @@ func (cs campaignState) formatTaskReport(task CampaignTaskInfo, reports []PhaseR
 		fmt.Fprintf(&b, "\n%s\n", pipeFailedStyle.Render("⚠ "+errText))
 	}
 
+	b.WriteString(formatPhaseReports(reports))
+	return b.String()
+}
+
+// formatPhaseReports renders one status line per phase report, with its
+// duration and summary.
+func formatPhaseReports(reports []PhaseReport) string {
+	var b strings.Builder
 	for _, r := range reports {
 		var renderedStatus string
 		switch r.Status {
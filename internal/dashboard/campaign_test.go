@@ -80,6 +80,38 @@ func TestCampaign_TaskStartMsg_ResetsPipeline(t *testing.T) {
 	}
 }
 
+func TestCampaign_TaskStartMsg_KeepsSpinner(t *testing.T) {
+	// Given: a campaign with a running task and a pending spinner tick
+	cs := newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
+	cs, _ = cs.Update(CampaignTaskStartMsg{BeadID: "cap-001", Index: 0, Total: 3})
+	tick := cs.pipeline.spinner.Tick()
+
+	// When: the next task starts before the tick arrives
+	cs, _ = cs.Update(CampaignTaskStartMsg{BeadID: "cap-002", Index: 1, Total: 3})
+	_, cmd := cs.Update(tick)
+
+	// Then: the tick is still accepted, so the spinner and elapsed counters keep updating
+	if cmd == nil {
+		t.Error("spinner tick should survive a task start")
+	}
+}
+
+func TestCampaign_RunningTaskShowsLiveElapsed(t *testing.T) {
+	// Given: a campaign whose running task started 7 seconds ago
+	cs := newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
+	cs, _ = cs.Update(CampaignTaskStartMsg{BeadID: "cap-001", Index: 0, Total: 3})
+	cs, _ = cs.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
+	cs.pipeline.startedAt = time.Now().Add(-7 * time.Second)
+
+	// When: the view is rendered
+	plain := stripANSI(cs.View(80, 30))
+
+	// Then: the running task row shows a live counter
+	if !strings.Contains(plain, "00:07") {
+		t.Errorf("running task should show elapsed '00:07', got:\n%s", plain)
+	}
+}
+
 func TestCampaign_TaskDoneMsg_Success(t *testing.T) {
 	// Given: a campaign with first task running
 	cs := newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
@@ -221,6 +253,60 @@ func TestCampaign_TaskDoneMsg_Failure(t *testing.T) {
 	}
 }
 
+func TestCampaign_TaskSkippedMsg(t *testing.T) {
+	// Given: a campaign whose first task failed
+	cs := newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
+	cs, _ = cs.Update(CampaignTaskDoneMsg{BeadID: "cap-001", Index: 0, Success: false})
+
+	// When: the second task is skipped for its failed dependency
+	cs, _ = cs.Update(CampaignTaskSkippedMsg{BeadID: "cap-002", Index: 1, Reason: "dependency cap-001 failed"})
+
+	// Then: it counts as done and is marked skipped
+	if cs.skipped != 1 || cs.taskStatuses[1] != CampaignTaskSkipped {
+		t.Errorf("skipped = %d, status = %q; want 1, skipped", cs.skipped, cs.taskStatuses[1])
+	}
+	if plain := stripANSI(cs.View(60, 20)); !strings.Contains(plain, "2/3") {
+		t.Errorf("header should count the skipped task, got:\n%s", plain)
+	}
+	// And: selecting it shows the reason
+	cs.selectedIdx = 1
+	if report := stripANSI(cs.ViewReport(60, 20)); !strings.Contains(report, "Skipped: dependency cap-001 failed") {
+		t.Errorf("report = %q, want skip reason", report)
+	}
+}
+
+func TestCampaign_View_BlockedTask(t *testing.T) {
+	// Given: a campaign whose third task waits on the first two
+	tasks := sampleCampaignTasks()
+	tasks[2].BlockedBy = []string{"cap-001", "cap-002"}
+	cs := newCampaignState("cap-feat", "Feature Title", tasks)
+
+	// When: the view is rendered before it starts
+	plain := stripANSI(cs.View(80, 20))
+
+	// Then: the task names what it waits on
+	if !strings.Contains(plain, "(blocked by cap-001, cap-002)") {
+		t.Errorf("blocked task should name its blockers, got:\n%s", plain)
+	}
+
+	// When: the first blocker passes
+	cs, _ = cs.Update(CampaignTaskStartMsg{BeadID: "cap-001", Index: 0, Total: 3})
+	cs, _ = cs.Update(CampaignTaskDoneMsg{BeadID: "cap-001", Index: 0, Success: true})
+
+	// Then: only the unfinished blocker is named
+	if plain := stripANSI(cs.View(80, 20)); !strings.Contains(plain, "(blocked by cap-002)") {
+		t.Errorf("passed blocker should be dropped, got:\n%s", plain)
+	}
+
+	// When: the task starts
+	cs, _ = cs.Update(CampaignTaskStartMsg{BeadID: "cap-003", Index: 2, Total: 3})
+
+	// Then: it renders normally
+	if plain := stripANSI(cs.View(80, 20)); strings.Contains(plain, "(blocked") {
+		t.Errorf("running task should not be marked blocked, got:\n%s", plain)
+	}
+}
+
 func TestCampaign_PhaseUpdateMsg_ForwardsToEmbeddedPipeline(t *testing.T) {
 	// Given: a campaign with first task running and pipeline initialized
 	cs := newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
@@ -232,7 +318,7 @@ func TestCampaign_PhaseUpdateMsg_ForwardsToEmbeddedPipeline(t *testing.T) {
 	cs, _ = cs.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
 
 	// Then: embedded pipeline state is updated
-	if !cs.pipeline.running {
+	if !cs.anyRunning() {
 		t.Error("embedded pipeline should be running")
 	}
 	if cs.pipeline.phases[0].Status != PhaseRunning {
@@ -367,6 +453,29 @@ func TestCampaign_View_ProgressCounter(t *testing.T) {
 	}
 }
 
+func TestCampaign_View_ProgressBar(t *testing.T) {
+	// Given: a campaign of three tasks with one done and the second halfway
+	// through its pipeline
+	cs := newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
+	cs, _ = cs.Update(CampaignTaskStartMsg{BeadID: "cap-001", Index: 0, Total: 3})
+	cs, _ = cs.Update(CampaignTaskDoneMsg{BeadID: "cap-001", Index: 0, Success: true, Duration: 2 * time.Second})
+	cs, _ = cs.Update(CampaignTaskStartMsg{BeadID: "cap-002", Index: 1, Total: 3})
+	cs.pipeline = newPipelineState([]string{"plan", "code"})
+	cs, _ = cs.Update(PhaseUpdateMsg{Phase: "plan", Status: PhasePassed, PhaseIndex: 1, PhaseTotal: 2, FractionComplete: 0.5})
+
+	// When: the view is rendered
+	lines := strings.Split(stripANSI(cs.View(60, 20)), "\n")
+
+	// Then: the bar under the header counts the running task's half
+	want := strings.Repeat("█", 10) + strings.Repeat("░", 10) + " 50%"
+	if len(lines) < 2 {
+		t.Fatalf("view has %d lines, want a bar under the header", len(lines))
+	}
+	if lines[1] != want {
+		t.Errorf("second line = %q, want %q", lines[1], want)
+	}
+}
+
 func TestCampaign_View_EmptyTasks(t *testing.T) {
 	// Given: a campaign state with no tasks
 	cs := newCampaignState("cap-feat", "Feature Title", nil)
@@ -802,6 +911,76 @@ func TestCampaign_ValidationDone_Failure(t *testing.T) {
 	}
 }
 
+func TestCampaign_ValidationShowsLivePhases(t *testing.T) {
+	// Given: a campaign whose validation has started
+	cs := newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
+	cs, _ = cs.Update(CampaignValidationStartMsg{})
+
+	// When: a validation phase update arrives
+	cs, _ = cs.Update(PhaseUpdateMsg{Phase: "integration-test", Status: PhaseRunning, Attempt: 1, MaxRetry: 2})
+
+	// Then: the phase is listed beneath the validation row
+	plain := stripANSI(cs.View(60, 20))
+	row := strings.Index(plain, "Feature validation")
+	phase := strings.Index(plain, "integration-test")
+	if row < 0 || phase < row {
+		t.Errorf("validation phase should follow the validation row, got:\n%s", plain)
+	}
+}
+
+func TestCampaign_ValidationRowSelectable(t *testing.T) {
+	// Given: a campaign with validation complete and failed
+	tasks := sampleCampaignTasks()
+	cs := newCampaignState("cap-feat", "Feature Title", tasks)
+	cs, _ = cs.Update(CampaignValidationDoneMsg{
+		Error: "validation failed: review",
+		PhaseReports: []PhaseReport{
+			{PhaseName: "integration-test", Status: PhasePassed},
+			{PhaseName: "review", Status: PhaseFailed, Summary: "API undocumented"},
+		},
+	})
+
+	// When: the cursor moves up from the first task
+	cs = cs.handleKey(tea.KeyMsg{Type: tea.KeyUp})
+
+	// Then: the validation row is selected and its report fills the pane
+	if !cs.validationSelected() {
+		t.Fatalf("selectedIdx = %d, want validation row %d", cs.selectedIdx, len(tasks))
+	}
+	report := stripANSI(cs.ViewReport(60, 20))
+	for _, want := range []string{"validation failed: review", "integration-test", "review", "API undocumented"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("validation report missing %q, got:\n%s", want, report)
+		}
+	}
+	// And the row lists its phases
+	if plain := stripANSI(cs.View(60, 20)); !strings.Contains(plain, "integration-test") {
+		t.Errorf("selected validation row should list phases, got:\n%s", plain)
+	}
+
+	// When: the cursor moves down again
+	cs = cs.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+
+	// Then: it wraps to the first task
+	if cs.selectedIdx != 0 {
+		t.Errorf("selectedIdx = %d, want 0", cs.selectedIdx)
+	}
+}
+
+func TestCampaign_NoValidationRowWithoutValidation(t *testing.T) {
+	// Given: a campaign without validation
+	tasks := sampleCampaignTasks()
+	cs := newCampaignState("cap-feat", "Feature Title", tasks)
+
+	// When: the cursor moves up from the first task
+	cs = cs.handleKey(tea.KeyMsg{Type: tea.KeyUp})
+
+	// Then: it wraps to the last task
+	if cs.selectedIdx != len(tasks)-1 {
+		t.Errorf("selectedIdx = %d, want %d", cs.selectedIdx, len(tasks)-1)
+	}
+}
+
 func TestCampaign_ViewHeader_WithProvider(t *testing.T) {
 	// Given: a campaign state with provider set
 	cs := newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
@@ -1110,7 +1289,7 @@ func TestCampaign_PhaseUpdateMsg_RoutesToSubcampaignPipeline(t *testing.T) {
 	cs, _ = cs.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
 
 	// Then: the subcampaign pipeline receives the update, not the main pipeline
-	if !cs.subcampaign.pipeline.running {
+	if !cs.subcampaign.pipeline.anyRunning() {
 		t.Error("subcampaign pipeline should be running")
 	}
 	if cs.subcampaign.pipeline.phases[0].Status != PhaseRunning {
@@ -1120,3 +1299,22 @@ func TestCampaign_PhaseUpdateMsg_RoutesToSubcampaignPipeline(t *testing.T) {
 		t.Errorf("main pipeline should not have received the update, has %d phases", len(cs.pipeline.phases))
 	}
 }
+
+func TestModel_CampaignStartShowsResume(t *testing.T) {
+	// Given a dashboard in campaign mode
+	m := newSizedModel(100, 30)
+	m.mode = ModeCampaign
+
+	// When a campaign starts from saved state
+	updated, _ := m.Update(CampaignStartMsg{
+		ParentID: "cap-feat",
+		Tasks:    []CampaignTaskInfo{{BeadID: "cap-3", Title: "Task 3"}},
+		Resumed:  &CampaignResume{Done: 2, Total: 3},
+	})
+	m = updated.(Model)
+
+	// Then the campaign header says it is resuming and how far it got
+	if view := stripANSI(m.campaign.View(80, 20)); !strings.Contains(view, "(resuming, 2/3 done)") {
+		t.Errorf("View() missing resume note:\n%s", view)
+	}
+}
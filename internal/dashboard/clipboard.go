@@ -0,0 +1,72 @@
+package dashboard
+
+import (
+	"errors"
+	"os"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// errClipboardUnsupported reports a terminal known not to handle OSC 52.
+var errClipboardUnsupported = errors.New("terminal does not support OSC 52 clipboard")
+
+// clipboardDoneMsg reports the outcome of copying Path with y.
+type clipboardDoneMsg struct {
+	Path string
+	Err  error
+}
+
+// copyOSC52 copies text to the system clipboard with an OSC 52 escape
+// sequence, which the terminal forwards even over SSH. Terminals that are
+// known to drop the sequence return errClipboardUnsupported.
+func copyOSC52(text string) error {
+	if !osc52Supported(os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")) {
+		return errClipboardUnsupported
+	}
+	termenv.NewOutput(os.Stdout).Copy(text)
+	return nil
+}
+
+// osc52Supported reports whether a terminal may handle OSC 52. There is no
+// way to query support, so only terminals known to lack it are excluded.
+func osc52Supported(term, program string) bool {
+	switch {
+	case term == "", term == "dumb", term == "linux":
+		return false
+	case program == "Apple_Terminal":
+		return false
+	}
+	return true
+}
+
+// copyCmd returns a tea.Cmd that copies path with copyFn.
+func copyCmd(copyFn func(string) error, path string) tea.Cmd {
+	return func() tea.Msg {
+		return clipboardDoneMsg{Path: path, Err: copyFn(path)}
+	}
+}
+
+// copyBinding returns the y binding for the pipeline and summary help bars:
+// disabled until the worktree path is known, and showing the path itself
+// once copying it has failed.
+func (m Model) copyBinding() key.Binding {
+	b := key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy worktree path"))
+	switch {
+	case m.pipeline.worktreePath == "":
+		b.SetEnabled(false)
+	case m.copyFallback != "":
+		b.SetHelp("worktree", m.copyFallback)
+	}
+	return b
+}
+
+// clearWorktree forgets the finished pipeline's worktree path and branch,
+// so they are not shown or copied once the dashboard is back in browse.
+func (m Model) clearWorktree() Model {
+	m.pipeline.worktreePath = ""
+	m.pipeline.branch = ""
+	m.copyFallback = ""
+	return m
+}
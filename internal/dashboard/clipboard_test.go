@@ -0,0 +1,102 @@
+package dashboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOSC52Supported(t *testing.T) {
+	tests := []struct {
+		term, program string
+		want          bool
+	}{
+		{term: "xterm-256color", want: true},
+		{term: "screen-256color", want: true},
+		{term: "", want: false},
+		{term: "dumb", want: false},
+		{term: "linux", want: false},
+		{term: "xterm-256color", program: "Apple_Terminal", want: false},
+	}
+	for _, tt := range tests {
+		if got := osc52Supported(tt.term, tt.program); got != tt.want {
+			t.Errorf("osc52Supported(%q, %q) = %v, want %v", tt.term, tt.program, got, tt.want)
+		}
+	}
+}
+
+func TestModel_CopyWorktreePath(t *testing.T) {
+	const path = "/repo/.capsule/worktrees/cap-001"
+	tests := []struct {
+		name     string
+		copyErr  error
+		wantHelp string
+	}{
+		{name: "copied", wantHelp: "copy worktree path"},
+		{name: "unsupported terminal prints the path", copyErr: errClipboardUnsupported, wantHelp: path},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a running pipeline whose worktree was reported
+			m := newPipelineModel(120, 30, samplePhaseNames())
+			m.pipeline.beadID = "cap-001"
+			var copied string
+			m.clipboard = func(text string) error {
+				copied = text
+				return tt.copyErr
+			}
+			updated, _ := m.Update(PipelineStartedMsg{BeadID: "cap-001", WorktreePath: path, Branch: "capsule-cap-001"})
+			m = updated.(Model)
+
+			// When: y is pressed and the copy finishes
+			updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+			m = updated.(Model)
+			if cmd == nil {
+				t.Fatal("y should return a copy command")
+			}
+			updated, _ = m.Update(cmd())
+			m = updated.(Model)
+
+			// Then: the path was handed to the clipboard, and the help line
+			// shows the path itself only when copying failed
+			if copied != path {
+				t.Errorf("copied = %q, want %q", copied, path)
+			}
+			if help := stripANSI(m.View()); !strings.Contains(help, tt.wantHelp) {
+				t.Errorf("view should contain %q", tt.wantHelp)
+			}
+		})
+	}
+}
+
+func TestModel_WorktreeClearedOnReturnToBrowse(t *testing.T) {
+	// Given: a finished pipeline in summary mode with a worktree whose copy failed
+	m := newPipelineModel(120, 30, samplePhaseNames())
+	m.mode = ModeSummary
+	m.pipeline.worktreePath = "/repo/.capsule/worktrees/cap-001"
+	m.pipeline.branch = "capsule-cap-001"
+	m.copyFallback = m.pipeline.worktreePath
+	m.clipboard = func(string) error { return errors.New("unexpected copy") }
+
+	// Then: the summary shows the full path and branch
+	if right := stripANSI(m.viewSummaryRight()); !strings.Contains(right, "Worktree: /repo/.capsule/worktrees/cap-001") ||
+		!strings.Contains(right, "Branch: capsule-cap-001") {
+		t.Errorf("summary should show the worktree, got:\n%s", right)
+	}
+
+	// When: the user returns to browse
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	// Then: the path is gone and y no longer copies
+	if m.pipeline.worktreePath != "" || m.pipeline.branch != "" || m.copyFallback != "" {
+		t.Errorf("worktree = %q, branch = %q, fallback = %q; want all empty", m.pipeline.worktreePath, m.pipeline.branch, m.copyFallback)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}}); cmd != nil {
+		if _, ok := cmd().(clipboardDoneMsg); ok {
+			t.Error("y in browse should not copy")
+		}
+	}
+}
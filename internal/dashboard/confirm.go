@@ -2,9 +2,15 @@ package dashboard
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
+// confirmMaxWidth caps the confirmation box width on wide terminals.
+const confirmMaxWidth = 76
+
 // confirmChild represents a child task in the confirmation screen.
 type confirmChild struct {
 	ID    string
@@ -18,39 +24,126 @@ type confirmState struct {
 	beadTitle     string
 	children      []confirmChild
 	hasValidation bool
-	provider      string // Provider name frozen at confirm time.
+	failureMode   string              // Campaign failure mode; empty hides the line.
+	breaker       string              // Campaign circuit breaker description; empty hides the line.
+	provider      string              // Provider name frozen at confirm time.
+	phases        []string            // Phases each pipeline will run.
+	overlaps      map[string][]string // Files changed by other in-flight capsules, by capsule.
+	overlapErr    error               // Set when the overlap check failed.
+	resume        bool                // The bead was paused; confirming resumes it from its checkpoint.
 }
 
-// View renders the confirmation screen for the given dimensions.
+// View renders the confirmation dialog as a bordered box centered in an
+// area of the given dimensions.
 func (cs confirmState) View(width, height int) string {
+	boxWidth := min(width, confirmMaxWidth)
+	box := FocusedBorder().
+		Padding(0, 1).
+		Width(max(boxWidth-borderChrome, 1)).
+		Render(cs.content())
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// content renders the dialog text.
+func (cs confirmState) content() string {
 	var b strings.Builder
 
-	if cs.isCampaign() {
+	switch {
+	case cs.nothingToRun():
+		cs.viewNothingToRun(&b)
+		b.WriteString("\n\n  [Esc] Back")
+		return b.String()
+	case cs.isCampaign():
 		cs.viewCampaign(&b)
-	} else {
+	default:
 		cs.viewPipeline(&b)
 	}
+	cs.viewOverlaps(&b)
 
-	b.WriteString("\n\n  [Enter] Confirm   [Esc] Cancel")
+	b.WriteString("\n\n  [Enter/y] Confirm   [Esc/n] Cancel")
 	return b.String()
 }
 
+func (cs confirmState) isParent() bool {
+	return cs.beadType == "feature" || cs.beadType == "epic"
+}
+
 func (cs confirmState) isCampaign() bool {
-	return (cs.beadType == "feature" || cs.beadType == "epic") && len(cs.children) > 0
+	return cs.isParent() && len(cs.children) > 0
 }
 
-func (cs confirmState) viewPipeline(b *strings.Builder) {
-	fmt.Fprintf(b, "Run pipeline for %s?\n", cs.beadID)
+// nothingToRun reports whether the bead is a feature or epic without open
+// children, so confirming would start a campaign with no tasks.
+func (cs confirmState) nothingToRun() bool {
+	return cs.isParent() && len(cs.children) == 0
+}
+
+func (cs confirmState) viewNothingToRun(b *strings.Builder) {
+	fmt.Fprintf(b, "Nothing to run for %s\n", cs.beadID)
 	fmt.Fprintf(b, "\n  %s\n", cs.beadTitle)
+	fmt.Fprintf(b, "\n  This %s has no open children ready to run.", cs.beadType)
+}
+
+// viewDetails renders the type, provider, and phase lines shared by both views.
+func (cs confirmState) viewDetails(b *strings.Builder) {
+	if cs.beadType != "" {
+		fmt.Fprintf(b, "\n  Type: %s", cs.beadType)
+	}
 	if cs.provider != "" {
-		fmt.Fprintf(b, "\n  Provider: %s\n", cs.provider)
+		fmt.Fprintf(b, "\n  Provider: %s", cs.provider)
+	}
+	if len(cs.phases) > 0 {
+		fmt.Fprintf(b, "\n  Phases: %s", strings.Join(cs.phases, " → "))
+	}
+	b.WriteString("\n")
+}
+
+func (cs confirmState) viewPipeline(b *strings.Builder) {
+	if cs.resume {
+		cs.viewResume(b)
+		return
 	}
+	fmt.Fprintf(b, "Run pipeline for %s?\n", cs.beadID)
+	fmt.Fprintf(b, "\n  %s\n", cs.beadTitle)
+	cs.viewDetails(b)
 	b.WriteString("\n  This will:")
 	b.WriteString("\n  • Create a worktree branch")
 	b.WriteString("\n  • Run pipeline phases")
 	b.WriteString("\n  • Auto-merge to main on success")
 }
 
+// viewResume describes continuing a paused pipeline.
+func (cs confirmState) viewResume(b *strings.Builder) {
+	fmt.Fprintf(b, "Resume paused pipeline for %s?\n", cs.beadID)
+	fmt.Fprintf(b, "\n  %s\n", cs.beadTitle)
+	cs.viewDetails(b)
+	b.WriteString("\n  This will:")
+	b.WriteString("\n  • Continue in the existing worktree")
+	b.WriteString("\n  • Skip phases that already passed")
+	b.WriteString("\n  • Auto-merge to main on success")
+}
+
+// viewOverlaps warns about files other in-flight capsules have changed,
+// since merging this run back may then conflict.
+func (cs confirmState) viewOverlaps(b *strings.Builder) {
+	if cs.overlapErr != nil {
+		fmt.Fprintf(b, "\n\n  ⚠ Overlap check failed: %v", cs.overlapErr)
+		return
+	}
+	if len(cs.overlaps) == 0 {
+		return
+	}
+	names := make([]string, 0, len(cs.overlaps))
+	for name := range cs.overlaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b.WriteString("\n\n  ⚠ May conflict with in-flight capsules:")
+	for _, name := range names {
+		fmt.Fprintf(b, "\n    %s: %s", name, strings.Join(cs.overlaps[name], ", "))
+	}
+}
+
 func (cs confirmState) viewCampaign(b *strings.Builder) {
 	taskCount := len(cs.children)
 	taskWord := "tasks"
@@ -63,8 +156,12 @@ func (cs confirmState) viewCampaign(b *strings.Builder) {
 		fmt.Fprintf(b, "Run campaign for %s? (%d %s)\n", cs.beadID, taskCount, taskWord)
 	}
 	fmt.Fprintf(b, "\n  %s\n", cs.beadTitle)
-	if cs.provider != "" {
-		fmt.Fprintf(b, "\n  Provider: %s\n", cs.provider)
+	cs.viewDetails(b)
+	if cs.failureMode != "" {
+		fmt.Fprintf(b, "  On failure: %s\n", cs.failureMode)
+	}
+	if cs.breaker != "" {
+		fmt.Fprintf(b, "  Circuit breaker: %s\n", cs.breaker)
 	}
 
 	if cs.hasValidation {
@@ -99,7 +196,8 @@ func collectOpenChildren(roots []*treeNode, parentID string) []confirmChild {
 
 	var children []confirmChild
 	for _, child := range parent.Children {
-		if child.Bead.Closed {
+		// Campaigns run only open children, not claimed or blocked ones.
+		if child.Bead.Closed || child.Bead.held() != "" {
 			continue
 		}
 		children = append(children, confirmChild{
@@ -1,8 +1,11 @@
 package dashboard
 
 import (
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestConfirm_ViewPipeline(t *testing.T) {
@@ -13,8 +16,8 @@ func TestConfirm_ViewPipeline(t *testing.T) {
 		beadTitle: "Validate email format",
 	}
 
-	// When: the view is rendered
-	view := cs.View(80, 40)
+	// When: the dialog text is rendered
+	view := cs.content()
 
 	// Then: it shows the pipeline confirmation
 	if !strings.Contains(view, "Run pipeline for cap-001?") {
@@ -26,10 +29,10 @@ func TestConfirm_ViewPipeline(t *testing.T) {
 	if !strings.Contains(view, "Create a worktree branch") {
 		t.Errorf("should show consequences, got:\n%s", view)
 	}
-	if !strings.Contains(view, "[Enter] Confirm") {
+	if !strings.Contains(view, "[Enter/y] Confirm") {
 		t.Errorf("should show confirm hint, got:\n%s", view)
 	}
-	if !strings.Contains(view, "[Esc] Cancel") {
+	if !strings.Contains(view, "[Esc/n] Cancel") {
 		t.Errorf("should show cancel hint, got:\n%s", view)
 	}
 }
@@ -46,8 +49,8 @@ func TestConfirm_ViewCampaign(t *testing.T) {
 		},
 	}
 
-	// When: the view is rendered
-	view := cs.View(80, 40)
+	// When: the dialog text is rendered
+	view := cs.content()
 
 	// Then: it shows the campaign confirmation with task count
 	if !strings.Contains(view, "Run campaign for demo-1? (2 tasks)") {
@@ -76,8 +79,8 @@ func TestConfirm_ViewCampaignWithValidation(t *testing.T) {
 		},
 	}
 
-	// When: the view is rendered
-	view := cs.View(80, 40)
+	// When: the dialog text is rendered
+	view := cs.content()
 
 	// Then: it shows validation text and step numbers
 	if !strings.Contains(view, "(1 task + validation)") {
@@ -103,8 +106,8 @@ func TestConfirm_ViewCampaignNoValidation(t *testing.T) {
 		children:  []confirmChild{{ID: "demo-1.1", Title: "Task"}},
 	}
 
-	// When: the view is rendered
-	view := cs.View(80, 40)
+	// When: the dialog text is rendered
+	view := cs.content()
 
 	// Then: no "Step" text or validation mention
 	if strings.Contains(view, "Step") {
@@ -115,20 +118,84 @@ func TestConfirm_ViewCampaignNoValidation(t *testing.T) {
 	}
 }
 
-func TestConfirm_PipelineForFeatureWithNoChildren(t *testing.T) {
-	// Given: a feature with no children (falls back to pipeline view)
+func TestConfirm_FeatureWithNoChildren_NothingToRun(t *testing.T) {
+	// Given: a feature with no open children
 	cs := confirmState{
 		beadID:    "demo-1",
 		beadType:  "feature",
 		beadTitle: "Empty Feature",
 	}
 
-	// When: the view is rendered
-	view := cs.View(80, 40)
+	// When: the dialog text is rendered
+	view := cs.content()
 
-	// Then: it shows pipeline-style confirmation (no children = not a campaign)
-	if !strings.Contains(view, "Run pipeline for demo-1?") {
-		t.Errorf("should show pipeline prompt for featureless feature, got:\n%s", view)
+	// Then: it says there is nothing to run and offers no confirmation
+	if !strings.Contains(view, "Nothing to run for demo-1") {
+		t.Errorf("should show nothing to run, got:\n%s", view)
+	}
+	if strings.Contains(view, "Confirm") || strings.Contains(view, "Run pipeline") {
+		t.Errorf("should not offer to run anything, got:\n%s", view)
+	}
+}
+
+func TestConfirm_ShowsTypeAndPhases(t *testing.T) {
+	// Given: a task confirmation with resolved phases
+	cs := confirmState{
+		beadID:   "cap-001",
+		beadType: "task",
+		provider: "claude",
+		phases:   []string{"test-writer", "execute", "sign-off"},
+	}
+
+	// When: the dialog text is rendered
+	view := cs.content()
+
+	// Then: type, provider, and the phase list are shown
+	for _, want := range []string{"Type: task", "Provider: claude", "Phases: test-writer → execute → sign-off"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestConfirm_ViewCenteredBox(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+	}{
+		{"wide", 120, 30},
+		{"narrow", 50, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a confirmation
+			cs := confirmState{beadID: "cap-001", beadType: "task", beadTitle: "First task"}
+
+			// When: the box is rendered into an area
+			view := cs.View(tt.width, tt.height)
+
+			// Then: it fills the area exactly and the box fits inside
+			if h := lipgloss.Height(view); h != tt.height {
+				t.Errorf("height = %d, want %d", h, tt.height)
+			}
+			if w := lipgloss.Width(view); w != tt.width {
+				t.Errorf("width = %d, want %d", w, tt.width)
+			}
+			lines := strings.Split(view, "\n")
+			top := -1
+			for i, l := range lines {
+				if strings.Contains(l, "╭") {
+					top = i
+					break
+				}
+			}
+			if top <= 0 {
+				t.Fatalf("box should be vertically centered, top border at line %d:\n%s", top, view)
+			}
+			if indent := strings.Index(lines[top], "╭"); indent <= 0 && tt.width > confirmMaxWidth {
+				t.Errorf("box should be horizontally centered, got indent %d", indent)
+			}
+		})
 	}
 }
 
@@ -214,8 +281,8 @@ func TestConfirm_ViewPipeline_ShowsProvider(t *testing.T) {
 		provider:  "kiro",
 	}
 
-	// When: the view is rendered
-	view := cs.View(80, 40)
+	// When: the dialog text is rendered
+	view := cs.content()
 
 	// Then: the provider name is displayed
 	if !strings.Contains(view, "Provider: kiro") {
@@ -235,8 +302,8 @@ func TestConfirm_ViewCampaign_ShowsProvider(t *testing.T) {
 		},
 	}
 
-	// When: the view is rendered
-	view := cs.View(80, 40)
+	// When: the dialog text is rendered
+	view := cs.content()
 
 	// Then: the provider name is displayed
 	if !strings.Contains(view, "Provider: claude") {
@@ -244,6 +311,30 @@ func TestConfirm_ViewCampaign_ShowsProvider(t *testing.T) {
 	}
 }
 
+func TestConfirm_ViewCampaign_ShowsFailurePolicy(t *testing.T) {
+	// Given: a campaign confirm state with a failure mode and circuit breaker
+	cs := confirmState{
+		beadID:      "demo-1",
+		beadType:    "feature",
+		beadTitle:   "Contact Management Library",
+		failureMode: "skip-dependents",
+		breaker:     "3 consecutive setup failures",
+		children: []confirmChild{
+			{ID: "demo-1.1.1", Title: "Validate email format"},
+		},
+	}
+
+	// When: the dialog text is rendered
+	view := cs.content()
+
+	// Then: both settings are displayed
+	for _, want := range []string{"On failure: skip-dependents", "Circuit breaker: 3 consecutive setup failures"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("campaign view should contain %q, got:\n%s", want, view)
+		}
+	}
+}
+
 func TestConfirm_ViewPipeline_NoProvider(t *testing.T) {
 	// Given: a confirm state with no provider set
 	cs := confirmState{
@@ -252,11 +343,57 @@ func TestConfirm_ViewPipeline_NoProvider(t *testing.T) {
 		beadTitle: "Validate email format",
 	}
 
-	// When: the view is rendered
-	view := cs.View(80, 40)
+	// When: the dialog text is rendered
+	view := cs.content()
 
 	// Then: no provider line is shown
 	if strings.Contains(view, "Provider:") {
 		t.Errorf("should not show provider when empty, got:\n%s", view)
 	}
 }
+
+func TestConfirm_ViewOverlaps(t *testing.T) {
+	tests := []struct {
+		name string
+		cs   confirmState
+		want []string
+		not  []string
+	}{
+		{
+			name: "no overlaps",
+			cs:   confirmState{beadID: "cap-001", beadType: "task"},
+			not:  []string{"⚠"},
+		},
+		{
+			name: "overlaps sorted by capsule",
+			cs: confirmState{beadID: "cap-001", beadType: "task", overlaps: map[string][]string{
+				"cap-003": {"go.mod"},
+				"cap-002": {"src/a.go", "src/b.go"},
+			}},
+			want: []string{"May conflict with in-flight capsules:", "cap-002: src/a.go, src/b.go\n    cap-003: go.mod"},
+		},
+		{
+			name: "failed check",
+			cs:   confirmState{beadID: "cap-001", beadType: "task", overlapErr: errors.New("git failed")},
+			want: []string{"Overlap check failed: git failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When: the view is rendered
+			view := tt.cs.content()
+
+			// Then: the overlap warning matches
+			for _, w := range tt.want {
+				if !strings.Contains(view, w) {
+					t.Errorf("view missing %q, got:\n%s", w, view)
+				}
+			}
+			for _, n := range tt.not {
+				if strings.Contains(view, n) {
+					t.Errorf("view should not contain %q, got:\n%s", n, view)
+				}
+			}
+		})
+	}
+}
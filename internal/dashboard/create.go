@@ -0,0 +1,306 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// createMaxWidth caps the create-bead form width on wide terminals.
+const createMaxWidth = 76
+
+// createDescriptionHeight is the number of description lines shown.
+const createDescriptionHeight = 5
+
+// createTypes are the bead types the form offers, in cycling order.
+var createTypes = []string{"task", "feature", "epic", "bug"}
+
+// createDefaultPriority is the priority a new bead starts with.
+const createDefaultPriority = 2
+
+// errTitleRequired is shown when the form is submitted without a title.
+var errTitleRequired = errors.New("title is required")
+
+// createField identifies a field of the create-bead form.
+type createField int
+
+const (
+	fieldTitle createField = iota
+	fieldType
+	fieldPriority
+	fieldParent
+	fieldDescription
+	createFieldCount
+)
+
+// createState holds the create-bead form opened with n in browse mode.
+type createState struct {
+	title       textinput.Model
+	parent      textinput.Model
+	description textarea.Model
+	typeIdx     int // Index into createTypes.
+	priority    int // 0 (highest) to 4.
+	focus       createField
+	creating    bool  // Submitted; waiting for the BeadCreator.
+	err         error // Validation or bd failure, shown under the fields.
+}
+
+// newCreateState returns a form for a task under parentID ("" for none),
+// with the title focused.
+func newCreateState(parentID string) createState {
+	title := textinput.New()
+	title.Prompt = ""
+	title.Placeholder = "What needs doing"
+	title.Cursor.SetMode(cursor.CursorStatic)
+
+	parent := textinput.New()
+	parent.Prompt = ""
+	parent.Placeholder = "none"
+	parent.Cursor.SetMode(cursor.CursorStatic)
+	parent.SetValue(parentID)
+
+	description := textarea.New()
+	description.Placeholder = "Optional details"
+	description.ShowLineNumbers = false
+	description.Prompt = ""
+	description.SetHeight(createDescriptionHeight)
+	description.Cursor.SetMode(cursor.CursorStatic)
+
+	cs := createState{
+		title:       title,
+		parent:      parent,
+		description: description,
+		priority:    createDefaultPriority,
+	}
+	return cs.focusField(fieldTitle)
+}
+
+// input returns the bead the form describes.
+func (cs createState) input() NewBeadInput {
+	return NewBeadInput{
+		Title:       strings.TrimSpace(cs.title.Value()),
+		Type:        createTypes[cs.typeIdx],
+		Priority:    cs.priority,
+		ParentID:    strings.TrimSpace(cs.parent.Value()),
+		Description: strings.TrimSpace(cs.description.Value()),
+	}
+}
+
+// validate reports why the form cannot be submitted, or nil.
+func (cs createState) validate() error {
+	if cs.input().Title == "" {
+		return errTitleRequired
+	}
+	return nil
+}
+
+// focusField moves focus to f, blurring the text fields that lose it.
+func (cs createState) focusField(f createField) createState {
+	cs.focus = f
+	cs.title.Blur()
+	cs.parent.Blur()
+	cs.description.Blur()
+	switch f {
+	case fieldTitle:
+		cs.title.Focus()
+	case fieldParent:
+		cs.parent.Focus()
+	case fieldDescription:
+		cs.description.Focus()
+	}
+	return cs
+}
+
+// submits reports whether key submits the form: ctrl+s anywhere, or enter
+// outside the multiline description.
+func (cs createState) submits(key string) bool {
+	return key == "ctrl+s" || (key == "enter" && cs.focus != fieldDescription)
+}
+
+// Update edits the form. Tab and shift+tab move between fields, left and
+// right change the type and priority, and other keys go to the focused
+// text field. Submitting and cancelling are handled by the Model.
+func (cs createState) Update(msg tea.KeyMsg) (createState, tea.Cmd) {
+	switch msg.String() {
+	case "tab":
+		return cs.focusField((cs.focus + 1) % createFieldCount), nil
+	case "shift+tab":
+		return cs.focusField((cs.focus + createFieldCount - 1) % createFieldCount), nil
+	}
+
+	var cmd tea.Cmd
+	switch cs.focus {
+	case fieldTitle:
+		cs.title, cmd = cs.title.Update(msg)
+		if cs.err == errTitleRequired && cs.validate() == nil {
+			cs.err = nil
+		}
+	case fieldType:
+		cs.typeIdx = cycleChoice(cs.typeIdx, len(createTypes), msg.String())
+	case fieldPriority:
+		cs.priority = cycleChoice(cs.priority, 5, msg.String())
+	case fieldParent:
+		cs.parent, cmd = cs.parent.Update(msg)
+	case fieldDescription:
+		cs.description, cmd = cs.description.Update(msg)
+	}
+	return cs, cmd
+}
+
+// cycleChoice steps i through [0, n) with left/h and right/l, wrapping
+// around.
+func cycleChoice(i, n int, key string) int {
+	switch key {
+	case "left", "h":
+		return (i + n - 1) % n
+	case "right", "l", " ":
+		return (i + 1) % n
+	}
+	return i
+}
+
+// View renders the form as a bordered box centered in an area of the
+// given dimensions.
+func (cs createState) View(width, height int) string {
+	boxWidth := min(width, createMaxWidth)
+	inner := max(boxWidth-borderChrome-2, 1) // Border and padding.
+	fieldWidth := max(inner-len("  Description  "), 1)
+	cs.title.Width = fieldWidth
+	cs.parent.Width = fieldWidth
+	cs.description.SetWidth(max(inner-2, 1))
+
+	box := FocusedBorder().
+		Padding(0, 1).
+		Width(max(boxWidth-borderChrome, 1)).
+		Render(cs.content(inner))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// content renders the form text, wrapping errors to width.
+func (cs createState) content(width int) string {
+	var b strings.Builder
+	b.WriteString("New bead\n")
+	cs.viewField(&b, fieldTitle, "Title", cs.title.View())
+	cs.viewField(&b, fieldType, "Type", cs.viewChoice(createTypes[cs.typeIdx], fieldType))
+	cs.viewField(&b, fieldPriority, "Priority", cs.viewChoice(PriorityBadge(cs.priority), fieldPriority))
+	cs.viewField(&b, fieldParent, "Parent", cs.parent.View())
+	cs.viewField(&b, fieldDescription, "Description", "")
+	b.WriteString("\n" + indentLines(cs.description.View(), "  "))
+
+	if cs.err != nil {
+		b.WriteString("\n\n" + errorStyle.Width(width).Render(fmt.Sprintf("%s %s", SymbolCross, cs.err)))
+	}
+	if cs.creating {
+		b.WriteString("\n\n  Creating…")
+		return b.String()
+	}
+	b.WriteString("\n\n  [Enter/Ctrl+S] Create   [Tab] Next field   [Esc] Cancel")
+	return b.String()
+}
+
+// viewField writes one labelled field line, marking the focused field.
+func (cs createState) viewField(b *strings.Builder, f createField, label, value string) {
+	marker := " "
+	if cs.focus == f {
+		marker = "▸"
+	}
+	fmt.Fprintf(b, "\n%s %-12s %s", marker, label, value)
+}
+
+// viewChoice renders a select's value, with arrows while it has focus.
+func (cs createState) viewChoice(value string, f createField) string {
+	if cs.focus == f {
+		return "‹ " + value + " ›"
+	}
+	return value
+}
+
+// indentLines prefixes every line of s with indent.
+func indentLines(s, indent string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// createBeadCmd returns a tea.Cmd that files in with creator and wraps the
+// result in a beadCreatedMsg.
+func createBeadCmd(creator BeadCreator, in NewBeadInput) tea.Cmd {
+	return func() tea.Msg {
+		id, err := creator.Create(in)
+		return beadCreatedMsg{ID: id, ParentID: in.ParentID, Err: err}
+	}
+}
+
+// openCreate opens the create-bead form with the selected bead as parent.
+func (m Model) openCreate() (tea.Model, tea.Cmd) {
+	var parentID string
+	if bead, ok := m.browse.SelectedBead(); ok {
+		parentID = bead.ID
+	}
+	m.create = newCreateState(parentID)
+	m.mode = ModeCreate
+	return m, nil
+}
+
+// handleCreateKey routes keys while the create-bead form is open. Esc
+// cancels; submitting validates the form and files the bead, keeping the
+// form open until bd answers.
+func (m Model) handleCreateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.create.creating {
+		return m, nil
+	}
+	switch k := msg.String(); {
+	case k == "esc" || k == "ctrl+c":
+		m.mode = ModeBrowse
+		m.focus = PaneLeft
+		return m, nil
+	case m.create.submits(k):
+		if err := m.create.validate(); err != nil {
+			m.create.err = err
+			m.create = m.create.focusField(fieldTitle)
+			return m, nil
+		}
+		m.create.err = nil
+		m.create.creating = true
+		return m, createBeadCmd(m.creator, m.create.input())
+	}
+	var cmd tea.Cmd
+	m.create, cmd = m.create.Update(msg)
+	return m, cmd
+}
+
+// handleBeadCreated closes the form after a bead was filed, then reloads
+// the bead list with the cursor on the new bead. A failure stays in the
+// form with bd's error.
+func (m Model) handleBeadCreated(msg beadCreatedMsg) (tea.Model, tea.Cmd) {
+	if m.mode != ModeCreate {
+		return m, nil
+	}
+	m.create.creating = false
+	if msg.Err != nil {
+		m.create.err = msg.Err
+		return m, nil
+	}
+	m.mode = ModeBrowse
+	m.focus = PaneLeft
+	m.createdID = msg.ID
+	if msg.ParentID != "" {
+		m.browse.expandedIDs[msg.ParentID] = true
+	}
+	m.browse.loading = true
+	m.browse.err = nil
+	m.statusMsg = fmt.Sprintf("%s Created %s", SymbolCheck, msg.ID)
+	return m, tea.Batch(
+		func() tea.Msg { return RefreshBeadsMsg{} },
+		tea.Tick(statusLineDuration, func(time.Time) tea.Msg { return statusClearMsg{} }),
+	)
+}
@@ -0,0 +1,211 @@
+package dashboard
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stubCreator implements BeadCreator for tests.
+type stubCreator struct {
+	id      string
+	err     error
+	created []NewBeadInput
+}
+
+func (s *stubCreator) Create(in NewBeadInput) (string, error) {
+	s.created = append(s.created, in)
+	return s.id, s.err
+}
+
+// newCreateModel returns a sized model with the sample beads loaded and
+// creator set.
+func newCreateModel(creator BeadCreator) Model {
+	m := NewModel(WithBeadLister(&stubLister{beads: sampleBeads()}), WithBeadCreator(creator))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	updated, _ = updated.Update(BeadListMsg{Beads: sampleBeads()})
+	return updated.(Model)
+}
+
+// pressKeys sends each key to m and returns the model and the last command.
+func pressKeys(m Model, keys ...tea.KeyMsg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		var updated tea.Model
+		updated, cmd = m.Update(k)
+		m = updated.(Model)
+	}
+	return m, cmd
+}
+
+// typed returns the key messages that type s.
+func typed(s string) []tea.KeyMsg {
+	keys := make([]tea.KeyMsg, 0, len(s))
+	for _, r := range s {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return keys
+}
+
+var (
+	keyN     = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}
+	keyTab   = tea.KeyMsg{Type: tea.KeyTab}
+	keyRight = tea.KeyMsg{Type: tea.KeyRight}
+	keyLeft  = tea.KeyMsg{Type: tea.KeyLeft}
+	keyEnter = tea.KeyMsg{Type: tea.KeyEnter}
+	keyEsc   = tea.KeyMsg{Type: tea.KeyEsc}
+	keyCtrlS = tea.KeyMsg{Type: tea.KeyCtrlS}
+)
+
+func TestCreate_OpensWithSelectedBeadAsParent(t *testing.T) {
+	// Given a bead list with cap-001 selected
+	m := newCreateModel(&stubCreator{})
+
+	// When n is pressed
+	m, _ = pressKeys(m, keyN)
+
+	// Then the form opens with the title focused and cap-001 as parent
+	if m.mode != ModeCreate {
+		t.Fatalf("mode = %v, want ModeCreate", m.mode)
+	}
+	in := m.create.input()
+	if in.ParentID != "cap-001" || in.Type != "task" || in.Priority != createDefaultPriority {
+		t.Errorf("input = %+v, want a P2 task under cap-001", in)
+	}
+	if m.create.focus != fieldTitle {
+		t.Errorf("focus = %v, want title", m.create.focus)
+	}
+	if view := m.View(); !containsPlainText(view, "New bead") || !containsPlainText(view, "cap-001") {
+		t.Errorf("View() missing the form:\n%s", view)
+	}
+}
+
+func TestCreate_DisabledWithoutCreator(t *testing.T) {
+	// Given a dashboard without a BeadCreator
+	m := newCreateModel(nil)
+
+	// When n is pressed
+	m, _ = pressKeys(m, keyN)
+
+	// Then browse mode is kept
+	if m.mode != ModeBrowse {
+		t.Errorf("mode = %v, want ModeBrowse", m.mode)
+	}
+}
+
+func TestCreate_EmptyTitleIsRejected(t *testing.T) {
+	// Given an open form with a blank title
+	creator := &stubCreator{id: "cap-004"}
+	m := newCreateModel(creator)
+	m, _ = pressKeys(m, append([]tea.KeyMsg{keyN}, typed("  ")...)...)
+
+	// When it is submitted
+	m, cmd := pressKeys(m, keyEnter)
+
+	// Then it stays open with the error and nothing is filed
+	if cmd != nil {
+		t.Error("submit returned a command, want none")
+	}
+	if m.mode != ModeCreate || !errors.Is(m.create.err, errTitleRequired) {
+		t.Fatalf("mode = %v, err = %v; want the form with %v", m.mode, m.create.err, errTitleRequired)
+	}
+	if !containsPlainText(m.View(), "title is required") {
+		t.Errorf("View() missing the validation error:\n%s", m.View())
+	}
+
+	// And typing a title clears the error
+	m, _ = pressKeys(m, typed("x")...)
+	if m.create.err != nil {
+		t.Errorf("err = %v after typing a title, want nil", m.create.err)
+	}
+}
+
+func TestCreate_SubmitFilesBeadAndSelectsIt(t *testing.T) {
+	// Given an open form
+	creator := &stubCreator{id: "cap-001.1"}
+	m := newCreateModel(creator)
+	m, _ = pressKeys(m, keyN)
+
+	// When every field is filled in and ctrl+s is pressed
+	m, _ = pressKeys(m, typed("Fix login")...)
+	m, _ = pressKeys(m, keyTab, keyRight, keyRight, keyRight) // Type: bug.
+	m, _ = pressKeys(m, keyTab, keyLeft)                      // Priority: 1.
+	m, _ = pressKeys(m, keyTab, keyTab)                       // Keep the parent.
+	m, _ = pressKeys(m, typed("Steps:")...)
+	m, _ = pressKeys(m, keyEnter)
+	m, _ = pressKeys(m, typed("log in")...)
+	m, cmd := pressKeys(m, keyCtrlS)
+	if cmd == nil {
+		t.Fatal("submit returned no command")
+	}
+	if !m.create.creating || !containsPlainText(m.View(), "Creating…") {
+		t.Errorf("creating = %v, want the form waiting for bd", m.create.creating)
+	}
+
+	// Then the creator receives the form's fields
+	msg := cmd()
+	want := NewBeadInput{Title: "Fix login", Type: "bug", Priority: 1, ParentID: "cap-001", Description: "Steps:\nlog in"}
+	if len(creator.created) != 1 || creator.created[0] != want {
+		t.Fatalf("created = %+v, want [%+v]", creator.created, want)
+	}
+
+	// And the form closes and the list reloads
+	updated, reload := m.Update(msg)
+	m = updated.(Model)
+	if m.mode != ModeBrowse || reload == nil || !m.browse.loading {
+		t.Fatalf("mode = %v, loading = %v; want browse reloading", m.mode, m.browse.loading)
+	}
+	if !containsText(m.statusMsg, "Created cap-001.1") {
+		t.Errorf("statusMsg = %q, want it to name the new bead", m.statusMsg)
+	}
+
+	// And the reloaded list puts the cursor on the new bead
+	beads := append(sampleBeads(), BeadSummary{ID: "cap-001.1", Title: "Fix login", Priority: 1, Type: "bug"})
+	updated, _ = m.Update(BeadListMsg{Beads: beads})
+	m = updated.(Model)
+	if bead, ok := m.browse.SelectedBead(); !ok || bead.ID != "cap-001.1" {
+		t.Errorf("selected = %q, want cap-001.1", bead.ID)
+	}
+}
+
+func TestCreate_BdErrorStaysInForm(t *testing.T) {
+	// Given a creator that fails
+	creator := &stubCreator{err: errors.New("bd create failed: no such parent")}
+	m := newCreateModel(creator)
+	m, _ = pressKeys(m, append([]tea.KeyMsg{keyN}, typed("Fix login")...)...)
+
+	// When the form is submitted
+	m, cmd := pressKeys(m, keyEnter)
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+
+	// Then the error is shown inline and the fields are kept
+	if m.mode != ModeCreate || m.create.creating {
+		t.Fatalf("mode = %v, creating = %v; want the form open", m.mode, m.create.creating)
+	}
+	if !containsPlainText(m.View(), "no such parent") {
+		t.Errorf("View() missing the bd error:\n%s", m.View())
+	}
+	if m.create.input().Title != "Fix login" {
+		t.Errorf("title = %q, want it kept", m.create.input().Title)
+	}
+}
+
+func TestCreate_EscCancels(t *testing.T) {
+	// Given an open form with a title typed
+	creator := &stubCreator{id: "cap-004"}
+	m := newCreateModel(creator)
+	m, _ = pressKeys(m, append([]tea.KeyMsg{keyN}, typed("q")...)...)
+
+	// When esc is pressed
+	m, _ = pressKeys(m, keyEsc)
+
+	// Then browse mode returns without filing, and q was typed, not quit
+	if m.mode != ModeBrowse {
+		t.Errorf("mode = %v, want ModeBrowse", m.mode)
+	}
+	if len(creator.created) != 0 {
+		t.Errorf("created = %+v, want none", creator.created)
+	}
+}
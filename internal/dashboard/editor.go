@@ -0,0 +1,135 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// execFunc suspends the program, runs cmd in the terminal, and reports its
+// outcome through fn, as tea.ExecProcess does.
+type execFunc func(cmd *exec.Cmd, fn tea.ExecCallback) tea.Cmd
+
+// editorDoneMsg reports the end of an editor or shell session started with
+// e or E. What names the session for an error's status line.
+type editorDoneMsg struct {
+	What string
+	Err  error
+}
+
+// editorCmd returns the command that opens path in $EDITOR, or vi when it
+// is unset. $EDITOR may carry arguments, as in "code --wait".
+func editorCmd(path string) *exec.Cmd {
+	args := strings.Fields(os.Getenv("EDITOR"))
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// shellCmd returns an interactive shell started in dir: $SHELL, or sh when
+// it is unset ($COMSPEC, or cmd, on Windows).
+func shellCmd(dir string) *exec.Cmd {
+	shell := os.Getenv("SHELL")
+	if runtime.GOOS == "windows" {
+		shell = os.Getenv("COMSPEC")
+		if shell == "" {
+			shell = "cmd"
+		}
+	} else if shell == "" {
+		shell = "sh"
+	}
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+	return cmd
+}
+
+// openWorklog suspends the dashboard to open the pipeline's worklog in
+// $EDITOR. A bead without a worklog yet gets a status message instead.
+func (m Model) openWorklog() (tea.Model, tea.Cmd) {
+	path := m.worklogPathFn(m.pipeline.beadID)
+	if path == "" {
+		return m.editorFailed(editorDoneMsg{What: "worklog", Err: fmt.Errorf("none for %s yet", m.pipeline.beadID)})
+	}
+	return m, m.suspendFor("worklog", editorCmd(path))
+}
+
+// openWorktree suspends the dashboard to open the pipeline's worktree:
+// in $EDITOR when it is set, otherwise in a shell started there. A
+// worktree that was already removed gets a status message instead.
+func (m Model) openWorktree() (tea.Model, tea.Cmd) {
+	dir := m.pipeline.worktreePath
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return m.editorFailed(editorDoneMsg{What: "worktree", Err: fmt.Errorf("%s no longer exists", dir)})
+	}
+	cmd := shellCmd(dir)
+	if os.Getenv("EDITOR") != "" {
+		cmd = editorCmd(dir)
+		cmd.Dir = dir
+	}
+	return m, m.suspendFor("worktree", cmd)
+}
+
+// suspendFor runs cmd in the terminal while the dashboard is suspended. The
+// pipeline keeps running meanwhile: relayEvents queues its updates until
+// the dashboard resumes.
+func (m Model) suspendFor(what string, cmd *exec.Cmd) tea.Cmd {
+	return m.execProcess(cmd, func(err error) tea.Msg {
+		return editorDoneMsg{What: what, Err: err}
+	})
+}
+
+// editorFailed shows why e or E could not open anything on the status line.
+func (m Model) editorFailed(msg editorDoneMsg) (tea.Model, tea.Cmd) {
+	m.statusMsg = fmt.Sprintf("%s %s: %s", SymbolCross, msg.What, msg.Err)
+	return m, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+		return statusClearMsg{}
+	})
+}
+
+// editorBindings returns the e and E bindings for the pipeline and summary
+// help bars: e needs a WorklogPathFunc and E the worktree path.
+func (m Model) editorBindings() (worklog, worktree key.Binding) {
+	worklog = key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit worklog"))
+	worklog.SetEnabled(m.worklogPathFn != nil)
+	worktree = key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "open worktree"))
+	worktree.SetEnabled(m.pipeline.worktreePath != "")
+	return worklog, worktree
+}
+
+// relayEvents forwards every message from in to the returned channel,
+// queueing as many as needed, so the sender never waits on the dashboard.
+// Bubble Tea stops reading while e or E has it suspended, and the pipeline
+// must keep running meanwhile. The returned channel closes after in does
+// and its queue is drained.
+func relayEvents(in <-chan tea.Msg) <-chan tea.Msg {
+	out := make(chan tea.Msg)
+	go func() {
+		defer close(out)
+		var queue []tea.Msg
+		for in != nil || len(queue) > 0 {
+			var send chan<- tea.Msg
+			var next tea.Msg
+			if len(queue) > 0 {
+				send, next = out, queue[0]
+			}
+			select {
+			case msg, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				queue = append(queue, msg)
+			case send <- next:
+				queue = queue[1:]
+			}
+		}
+	}()
+	return out
+}
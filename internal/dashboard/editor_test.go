@@ -0,0 +1,142 @@
+package dashboard
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeExec records the command e or E would run and finishes it with err.
+func fakeExec(ran **exec.Cmd, err error) execFunc {
+	return func(cmd *exec.Cmd, fn tea.ExecCallback) tea.Cmd {
+		*ran = cmd
+		return func() tea.Msg { return fn(err) }
+	}
+}
+
+func TestModel_EditorKeys(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell fallback differs on Windows")
+	}
+	worktree := t.TempDir()
+	worklog := filepath.Join(worktree, "worklog.md")
+	tests := []struct {
+		name     string
+		key      rune
+		editor   string
+		wantArgs []string
+		wantDir  string
+	}{
+		{name: "e opens the worklog in $EDITOR", key: 'e', editor: "code --wait", wantArgs: []string{"code", "--wait", worklog}},
+		{name: "e falls back to vi", key: 'e', wantArgs: []string{"vi", worklog}},
+		{name: "E opens the worktree in $EDITOR", key: 'E', editor: "nano", wantArgs: []string{"nano", worktree}, wantDir: worktree},
+		{name: "E starts $SHELL without an editor", key: 'E', wantArgs: []string{"/bin/zsh"}, wantDir: worktree},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a running pipeline with a known worktree and worklog
+			t.Setenv("EDITOR", tt.editor)
+			t.Setenv("SHELL", "/bin/zsh")
+			m := newPipelineModel(120, 30, samplePhaseNames())
+			m.pipeline.beadID = "cap-001"
+			m.worklogPathFn = func(string) string { return worklog }
+			var ran *exec.Cmd
+			m.execProcess = fakeExec(&ran, nil)
+			updated, _ := m.Update(PipelineStartedMsg{BeadID: "cap-001", WorktreePath: worktree})
+			m = updated.(Model)
+
+			// When: the key is pressed and the session ends
+			updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.key}})
+			m = updated.(Model)
+			if cmd == nil {
+				t.Fatalf("%c should suspend the dashboard", tt.key)
+			}
+			updated, _ = m.Update(cmd())
+			m = updated.(Model)
+
+			// Then: the command ran on the right path, and the dashboard
+			// resumes in the same mode without a status message
+			if ran == nil || !reflect.DeepEqual(ran.Args, tt.wantArgs) {
+				t.Fatalf("ran %v, want %q", ran, tt.wantArgs)
+			}
+			if ran.Dir != tt.wantDir {
+				t.Errorf("dir = %q, want %q", ran.Dir, tt.wantDir)
+			}
+			if m.mode != ModePipeline || m.statusMsg != "" {
+				t.Errorf("mode = %v, status = %q; want pipeline mode, no status", m.mode, m.statusMsg)
+			}
+		})
+	}
+}
+
+func TestModel_EditorKeys_Failures(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        rune
+		worklog    string
+		worktree   string
+		execErr    error
+		wantStatus string
+	}{
+		{name: "no worklog yet", key: 'e', worktree: t.TempDir(), wantStatus: "worklog: none for cap-001 yet"},
+		{name: "editor fails to start", key: 'e', worklog: "worklog.md", worktree: t.TempDir(), execErr: errors.New(`exec: "vi": executable file not found`), wantStatus: `worklog: exec: "vi"`},
+		{name: "worktree removed", key: 'E', worktree: filepath.Join(t.TempDir(), "gone"), wantStatus: "gone no longer exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a finished pipeline's summary
+			m := newPipelineModel(120, 30, samplePhaseNames())
+			m.pipeline.beadID = "cap-001"
+			m.pipeline.worktreePath = tt.worktree
+			m.mode = ModeSummary
+			m.worklogPathFn = func(string) string { return tt.worklog }
+			var ran *exec.Cmd
+			m.execProcess = fakeExec(&ran, tt.execErr)
+
+			// When: the key is pressed
+			updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.key}})
+			m = updated.(Model)
+			if ran != nil {
+				updated, _ = m.Update(cmd())
+				m = updated.(Model)
+			}
+
+			// Then: the failure is a transient status message in the summary
+			if !strings.Contains(m.statusMsg, tt.wantStatus) {
+				t.Errorf("status = %q, want it to contain %q", m.statusMsg, tt.wantStatus)
+			}
+			if m.mode != ModeSummary {
+				t.Errorf("mode = %v, want summary", m.mode)
+			}
+		})
+	}
+}
+
+func TestRelayEvents(t *testing.T) {
+	// Given: a sender with more messages than any channel buffer
+	in := make(chan tea.Msg)
+	out := relayEvents(in)
+	const n = 100
+
+	// When: it sends them all while nobody reads
+	for i := range n {
+		in <- i
+	}
+	close(in)
+
+	// Then: every message arrives in order, and the relay closes after them
+	for i := range n {
+		if got := <-out; got != i {
+			t.Fatalf("message %d = %v", i, got)
+		}
+	}
+	if _, ok := <-out; ok {
+		t.Error("relay should close after its input")
+	}
+}
@@ -12,10 +12,16 @@ func HelpBindings(mode Mode) help.KeyMap {
 		return ConfirmKeyMap()
 	case ModePipeline:
 		return PipelineKeyMap()
-	case ModeSummary, ModeCampaignSummary:
+	case ModeSummary:
 		return SummaryKeyMap()
+	case ModeCampaignSummary:
+		return CampaignSummaryKeyMap()
 	case ModeCampaign:
 		return CampaignKeyMap()
+	case ModeQueueSummary:
+		return QueueSummaryKeyMap()
+	case ModeCreate:
+		return CreateKeyMap()
 	default:
 		return BrowseKeyMap()
 	}
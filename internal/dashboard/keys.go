@@ -13,20 +13,35 @@ type browseKeys struct {
 	Right       key.Binding
 	Left        key.Binding
 	Enter       key.Binding
+	Select      key.Binding // Toggles a task into the queue Enter runs.
 	Tab         key.Binding
 	Provider    key.Binding
 	CollapseAll key.Binding
+	Filter      key.Binding
+	ClearFilter key.Binding // Enabled only while a filter is set.
+	Sort        key.Binding
 	Refresh     key.Binding
+	CacheStats  key.Binding // Enabled when the dashboard has a bd cache.
+	New         key.Binding // Enabled when the dashboard has a BeadCreator.
 	Quit        key.Binding
 }
 
 // ShortHelp returns the browse mode bindings for the help bar.
 func (k browseKeys) ShortHelp() []key.Binding {
-	bindings := []key.Binding{k.Up, k.Down, k.Right, k.Left, k.Enter, k.Tab}
+	// Filter and sort come before tab so their state survives truncation.
+	bindings := []key.Binding{k.Up, k.Down, k.Right, k.Left, k.Enter, k.Select, k.Filter}
+	if k.ClearFilter.Enabled() {
+		bindings = append(bindings, k.ClearFilter)
+	}
+	bindings = append(bindings, k.Sort, k.Tab)
 	if k.Provider.Enabled() {
 		bindings = append(bindings, k.Provider)
 	}
-	return append(bindings, k.CollapseAll, k.Refresh, k.Quit)
+	bindings = append(bindings, k.CollapseAll)
+	if k.New.Enabled() {
+		bindings = append(bindings, k.New)
+	}
+	return append(bindings, k.Refresh, k.Quit)
 }
 
 // FullHelp returns the browse mode bindings grouped for expanded help.
@@ -35,48 +50,142 @@ func (k browseKeys) FullHelp() [][]key.Binding {
 	if k.Provider.Enabled() {
 		row2 = append(row2, k.Provider)
 	}
-	row2 = append(row2, k.CollapseAll, k.Refresh, k.Quit)
+	row2 = append(row2, k.Filter)
+	if k.ClearFilter.Enabled() {
+		row2 = append(row2, k.ClearFilter)
+	}
+	row2 = append(row2, k.Sort, k.CollapseAll, k.Refresh)
+	if k.New.Enabled() {
+		row2 = append(row2, k.New)
+	}
+	if k.CacheStats.Enabled() {
+		row2 = append(row2, k.CacheStats)
+	}
+	row2 = append(row2, k.Quit)
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Right, k.Left, k.Enter},
+		{k.Up, k.Down, k.Right, k.Left, k.Enter, k.Select},
 		row2,
 	}
 }
 
+// withListView labels the filter and sort bindings with the active filter
+// and sort mode, and enables esc to clear a set filter.
+func (k browseKeys) withListView(filter string, mode sortMode) browseKeys {
+	if filter != "" {
+		k.Filter = key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", fmt.Sprintf("filter: %q", filter)),
+		)
+		k.ClearFilter.SetEnabled(true)
+	}
+	k.Sort = key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort: "+mode.String()),
+	)
+	return k
+}
+
+// filterKeys holds key bindings while the browse filter input has focus.
+type filterKeys struct {
+	Query key.Binding // Shows the filter being typed.
+	Apply key.Binding
+	Clear key.Binding
+	Move  key.Binding
+}
+
+// ShortHelp returns the filter input bindings for the help bar.
+func (k filterKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Query, k.Apply, k.Clear, k.Move}
+}
+
+// FullHelp returns the filter input bindings grouped for expanded help.
+func (k filterKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// FilterKeyMap returns the key bindings while the browse filter is being
+// typed, with the filter so far as the first entry.
+func FilterKeyMap(filter string) filterKeys {
+	return filterKeys{
+		Query: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", filter+"▏"),
+		),
+		Apply: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "apply"),
+		),
+		Clear: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear"),
+		),
+		Move: key.NewBinding(
+			key.WithKeys("up", "down"),
+			key.WithHelp("↑/↓", "move"),
+		),
+	}
+}
+
 // pipelineKeys holds key bindings for pipeline mode.
 type pipelineKeys struct {
-	Up   key.Binding
-	Down key.Binding
-	Tab  key.Binding
-	Esc  key.Binding
-	Quit key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	Detail   key.Binding
+	Tab      key.Binding
+	Pause    key.Binding
+	Copy     key.Binding // Set by the model once the worktree path is known.
+	Worklog  key.Binding // Set by the model when a worklog can be opened.
+	Worktree key.Binding // Set by the model once the worktree path is known.
+	Esc      key.Binding
+	Quit     key.Binding
 }
 
 // ShortHelp returns the pipeline mode bindings for the help bar.
 func (k pipelineKeys) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Tab, k.Esc, k.Quit}
+	return []key.Binding{k.Up, k.Down, k.Tab, k.Pause, k.Copy, k.Worklog, k.Worktree, k.Esc, k.Quit}
 }
 
 // FullHelp returns the pipeline mode bindings grouped for expanded help.
 func (k pipelineKeys) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down},
-		{k.Tab, k.Esc, k.Quit},
+		{k.Up, k.Down, k.Detail},
+		{k.Tab, k.Pause, k.Copy, k.Worklog, k.Worktree, k.Esc, k.Quit},
 	}
 }
 
 // summaryKeys holds key bindings for summary mode.
 type summaryKeys struct {
-	AnyKey key.Binding
+	Retry    key.Binding // Shown only for failed runs with a checkpoint store.
+	AnyKey   key.Binding
+	Detail   key.Binding // Set only for pipeline summaries.
+	Copy     key.Binding // Set by the model once the worktree path is known.
+	Worklog  key.Binding // Set by the model when a worklog can be opened.
+	Worktree key.Binding // Set by the model once the worktree path is known.
 }
 
 // ShortHelp returns the summary mode bindings for the help bar.
 func (k summaryKeys) ShortHelp() []key.Binding {
-	return []key.Binding{k.AnyKey}
+	bindings := []key.Binding{k.AnyKey}
+	if k.Retry.Enabled() {
+		bindings = []key.Binding{k.Retry, k.AnyKey}
+	}
+	if len(k.Detail.Keys()) > 0 {
+		bindings = append(bindings, k.Detail)
+	}
+	if len(k.Copy.Keys()) > 0 {
+		bindings = append(bindings, k.Copy)
+	}
+	for _, b := range []key.Binding{k.Worklog, k.Worktree} {
+		if len(b.Keys()) > 0 {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings
 }
 
 // FullHelp returns the summary mode bindings grouped for expanded help.
 func (k summaryKeys) FullHelp() [][]key.Binding {
-	return [][]key.Binding{{k.AnyKey}}
+	return [][]key.Binding{k.ShortHelp()}
 }
 
 // BrowseKeyMap returns the key bindings for browse mode.
@@ -104,6 +213,10 @@ func BrowseKeyMap() browseKeys {
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "run pipeline"),
 		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "queue"),
+		),
 		Tab: key.NewBinding(
 			key.WithKeys("tab"),
 			key.WithHelp("tab", "switch pane"),
@@ -117,10 +230,33 @@ func BrowseKeyMap() browseKeys {
 			key.WithKeys("c"),
 			key.WithHelp("c", "collapse all"),
 		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear filter"),
+			key.WithDisabled(),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort"),
+		),
 		Refresh: key.NewBinding(
 			key.WithKeys("r"),
 			key.WithHelp("r", "refresh"),
 		),
+		CacheStats: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "bd cache stats"),
+			key.WithDisabled(),
+		),
+		New: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "new bead"),
+			key.WithDisabled(),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c"),
 			key.WithHelp("q", "quit"),
@@ -150,10 +286,18 @@ func PipelineKeyMap() pipelineKeys {
 			key.WithKeys("down", "j"),
 			key.WithHelp("↓/j", "down"),
 		),
+		Detail: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "phase detail"),
+		),
 		Tab: key.NewBinding(
 			key.WithKeys("tab"),
 			key.WithHelp("tab", "switch pane"),
 		),
+		Pause: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pause after phase"),
+		),
 		Esc: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "browse"),
@@ -223,17 +367,109 @@ func SummaryKeyMap() summaryKeys {
 	}
 }
 
-// PipelineSummaryKeyMap returns summary key bindings with a context-aware label.
-// When hasPostPipeline is true, the label reflects the lifecycle actions.
-func PipelineSummaryKeyMap(hasPostPipeline bool) summaryKeys {
-	desc := "back to browse"
-	if hasPostPipeline {
-		desc = "back (merge + close)"
+// campaignSummaryKeys holds key bindings for campaign summary mode.
+type campaignSummaryKeys struct {
+	Up    key.Binding
+	Down  key.Binding
+	Retry key.Binding
+	Back  key.Binding
+}
+
+// ShortHelp returns the campaign summary bindings for the help bar.
+func (k campaignSummaryKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Retry, k.Back}
+}
+
+// FullHelp returns the campaign summary bindings grouped for expanded help.
+func (k campaignSummaryKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down},
+		{k.Retry, k.Back},
+	}
+}
+
+// CampaignSummaryKeyMap returns the key bindings for campaign summary mode.
+func CampaignSummaryKeyMap() campaignSummaryKeys {
+	return campaignSummaryKeys{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Retry: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "retry failed task"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "q", "enter", "b"),
+			key.WithHelp("esc/q", "back to browse"),
+		),
 	}
+}
+
+// PipelineSummaryKeyMap returns summary key bindings. Post-pipeline lifecycle
+// runs when the summary is shown, so leaving it only returns to browse. The
+// retry binding starts disabled; enable it for a resumable failed run.
+func PipelineSummaryKeyMap() summaryKeys {
 	return summaryKeys{
+		Retry: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "retry failed phase"),
+			key.WithDisabled(),
+		),
 		AnyKey: key.NewBinding(
 			key.WithKeys("enter", "esc", "b"),
-			key.WithHelp("enter/esc/b", desc),
+			key.WithHelp("enter/esc/b", "back to browse"),
+		),
+		Detail: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "phase detail"),
+		),
+	}
+}
+
+// phaseDetailKeys holds key bindings for the phase detail view.
+type phaseDetailKeys struct {
+	Up   key.Binding
+	Down key.Binding
+	Back key.Binding
+	Quit key.Binding
+}
+
+// ShortHelp returns the phase detail bindings for the help bar.
+func (k phaseDetailKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Back, k.Quit}
+}
+
+// FullHelp returns the phase detail bindings grouped for expanded help.
+func (k phaseDetailKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down},
+		{k.Back, k.Quit},
+	}
+}
+
+// PhaseDetailKeyMap returns the key bindings for the phase detail view.
+func PhaseDetailKeyMap() phaseDetailKeys {
+	return phaseDetailKeys{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k", "pgup"),
+			key.WithHelp("↑/k", "scroll up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j", "pgdown"),
+			key.WithHelp("↓/j", "scroll down"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "d"),
+			key.WithHelp("esc/d", "back"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
 		),
 	}
 }
@@ -258,10 +494,50 @@ func (k confirmKeys) FullHelp() [][]key.Binding {
 func ConfirmKeyMap() confirmKeys {
 	return confirmKeys{
 		Enter: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "confirm"),
+			key.WithKeys("enter", "y"),
+			key.WithHelp("enter/y", "confirm"),
 		),
 		Esc: key.NewBinding(
+			key.WithKeys("esc", "n"),
+			key.WithHelp("esc/n", "cancel"),
+		),
+	}
+}
+
+// createKeys holds key bindings for the create-bead form.
+type createKeys struct {
+	Next   key.Binding
+	Change key.Binding
+	Submit key.Binding
+	Cancel key.Binding
+}
+
+// ShortHelp returns the create form bindings for the help bar.
+func (k createKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Change, k.Submit, k.Cancel}
+}
+
+// FullHelp returns the create form bindings grouped for expanded help.
+func (k createKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// CreateKeyMap returns the key bindings for the create-bead form.
+func CreateKeyMap() createKeys {
+	return createKeys{
+		Next: key.NewBinding(
+			key.WithKeys("tab", "shift+tab"),
+			key.WithHelp("tab", "next field"),
+		),
+		Change: key.NewBinding(
+			key.WithKeys("left", "right"),
+			key.WithHelp("←/→", "change type/priority"),
+		),
+		Submit: key.NewBinding(
+			key.WithKeys("enter", "ctrl+s"),
+			key.WithHelp("enter/ctrl+s", "create"),
+		),
+		Cancel: key.NewBinding(
 			key.WithKeys("esc"),
 			key.WithHelp("esc", "cancel"),
 		),
@@ -300,3 +576,100 @@ func BrowseKeyMapForBead(beadType string, childCount int) browseKeys {
 	}
 	return km
 }
+
+// BrowseKeyMapForHeldBead returns browse key bindings for a bead that is
+// in progress or blocked in bd: Enter does nothing, and its help says why.
+func BrowseKeyMapForHeldBead(label string) browseKeys {
+	km := BrowseKeyMap()
+	km.Enter = key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "can't run: "+label),
+	)
+	return km
+}
+
+// BrowseKeyMapForQueue returns browse key bindings while n beads are
+// selected: Enter runs them as a queue and esc clears the selection.
+func BrowseKeyMapForQueue(n int) browseKeys {
+	km := BrowseKeyMap()
+	km.Enter = key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", fmt.Sprintf("run queue (%d)", n)),
+	)
+	km.ClearFilter = key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "clear queue"),
+	)
+	return km
+}
+
+// queueAbortKeys holds key bindings for the prompt q opens while a queued
+// bead runs.
+type queueAbortKeys struct {
+	Skip   key.Binding
+	Abort  key.Binding
+	Cancel key.Binding
+}
+
+// ShortHelp returns the queue abort prompt bindings for the help bar.
+func (k queueAbortKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Skip, k.Abort, k.Cancel}
+}
+
+// FullHelp returns the queue abort prompt bindings grouped for expanded help.
+func (k queueAbortKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// QueueAbortKeyMap returns the key bindings for the queue abort prompt.
+func QueueAbortKeyMap() queueAbortKeys {
+	return queueAbortKeys{
+		Skip: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "skip bead, continue queue"),
+		),
+		Abort: key.NewBinding(
+			key.WithKeys("a", "q"),
+			key.WithHelp("a", "abort queue"),
+		),
+		Cancel: key.NewBinding(
+			key.WithKeys("esc", "n"),
+			key.WithHelp("esc/n", "keep running"),
+		),
+	}
+}
+
+// queueSummaryKeys holds key bindings for queue summary mode.
+type queueSummaryKeys struct {
+	Up   key.Binding
+	Down key.Binding
+	Back key.Binding
+}
+
+// ShortHelp returns the queue summary bindings for the help bar.
+func (k queueSummaryKeys) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Back}
+}
+
+// FullHelp returns the queue summary bindings grouped for expanded help.
+func (k queueSummaryKeys) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// QueueSummaryKeyMap returns the key bindings for queue summary mode.
+func QueueSummaryKeyMap() queueSummaryKeys {
+	return queueSummaryKeys{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("enter", "esc", "b", "q"),
+			key.WithHelp("enter/esc", "back to browse"),
+		),
+	}
+}
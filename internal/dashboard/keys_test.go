@@ -179,27 +179,39 @@ func TestBrowseKeyMapForBead_FeatureNoChildren(t *testing.T) {
 	}
 }
 
-func TestPipelineSummaryKeyMap_WithPostPipeline(t *testing.T) {
-	// Given: summary key map with post-pipeline configured
-	km := PipelineSummaryKeyMap(true)
-	bindings := km.ShortHelp()
+func TestBrowseKeyMapForHeldBead(t *testing.T) {
+	// Given: a bead in progress elsewhere
+	km := BrowseKeyMapForHeldBead("in progress")
 
-	// Then: the label includes "merge + close"
-	h := bindings[0].Help()
-	if !containsText(h.Desc, "merge + close") {
-		t.Errorf("summary with postPipeline desc = %q, want 'merge + close'", h.Desc)
+	// Then: Enter's label says why it won't run
+	if h := km.Enter.Help(); h.Desc != "can't run: in progress" {
+		t.Errorf("Enter desc = %q, want %q", h.Desc, "can't run: in progress")
 	}
 }
 
-func TestPipelineSummaryKeyMap_WithoutPostPipeline(t *testing.T) {
-	// Given: summary key map without post-pipeline
-	km := PipelineSummaryKeyMap(false)
+func TestPipelineSummaryKeyMap(t *testing.T) {
+	// Given: the summary key map
+	km := PipelineSummaryKeyMap()
 	bindings := km.ShortHelp()
 
 	// Then: the label says "back to browse"
 	h := bindings[0].Help()
 	if h.Desc != "back to browse" {
-		t.Errorf("summary without postPipeline desc = %q, want 'back to browse'", h.Desc)
+		t.Errorf("summary desc = %q, want 'back to browse'", h.Desc)
+	}
+}
+
+func TestPipelineSummaryKeys_RetryDisabledByDefault(t *testing.T) {
+	// Given: the default summary key map
+	km := PipelineSummaryKeyMap()
+
+	// Then: 'r' is hidden until a resumable failure enables it
+	if containsKey(collectKeys(km.ShortHelp()), "r") {
+		t.Error("PipelineSummaryKeyMap should not contain 'r' by default")
+	}
+	km.Retry.SetEnabled(true)
+	if !containsKey(collectKeys(km.ShortHelp()), "r") {
+		t.Error("enabled retry binding should appear in short help")
 	}
 }
 
@@ -233,15 +245,18 @@ func TestBrowseKeys_ProviderEnabledWithProvider(t *testing.T) {
 	}
 }
 
-func TestPipelineKeys_NoProvider(t *testing.T) {
+func TestPipelineKeys_PauseNotProvider(t *testing.T) {
 	// Given: the pipeline key map
 	km := PipelineKeyMap()
 	bindings := km.ShortHelp()
 	allKeys := collectKeys(bindings)
 
-	// Then: 'p' is not included (no provider toggle in pipeline mode)
-	if containsKey(allKeys, "p") {
-		t.Error("PipelineKeyMap should not contain 'p' key")
+	// Then: 'p' pauses the pipeline (no provider toggle in pipeline mode)
+	if !containsKey(allKeys, "p") {
+		t.Errorf("PipelineKeyMap should contain 'p' key, got %v", allKeys)
+	}
+	if h := km.Pause.Help(); !containsText(h.Desc, "pause") {
+		t.Errorf("Pause desc = %q, want 'pause'", h.Desc)
 	}
 }
 
@@ -0,0 +1,121 @@
+package dashboard
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// Styles for archived markdown in the detail pane.
+var (
+	mdHeadingStyle = lipgloss.NewStyle().Bold(true).Foreground(colorActive)
+	mdBoldStyle    = lipgloss.NewStyle().Bold(true)
+	mdItalicStyle  = lipgloss.NewStyle().Italic(true)
+	mdCodeStyle    = lipgloss.NewStyle().Foreground(colorWarning)
+	mdQuoteStyle   = dimStyle
+)
+
+var (
+	mdHeading = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdList    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdQuote   = regexp.MustCompile(`^>\s?(.*)$`)
+	mdRule    = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdInline  = regexp.MustCompile("`[^`]+`|\\*\\*[^*]+\\*\\*|__[^_]+__|\\*[^*\\s][^*]*\\*")
+)
+
+// plainMarkdown reports whether archived markdown should be shown as its
+// source rather than styled: NO_COLOR is set or the terminal has no colors.
+func plainMarkdown() bool {
+	return os.Getenv("NO_COLOR") != "" || lipgloss.ColorProfile() == termenv.Ascii
+}
+
+// renderMarkdown renders the markdown of an archived summary or worklog for
+// a pane width columns wide. Headings, emphasis, inline code, lists, quotes,
+// and rules are styled and their markers dropped; fenced code blocks are
+// indented and kept as written. With plain set the source is only wrapped.
+// A width of zero or less leaves lines unwrapped.
+func renderMarkdown(src string, width int, plain bool) string {
+	var out []string
+	inCode := false
+	for _, line := range strings.Split(strings.TrimRight(src, "\n"), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			if plain {
+				out = append(out, line)
+			}
+			continue
+		}
+		switch {
+		case inCode && plain:
+			out = append(out, line)
+		case inCode:
+			out = append(out, "  "+mdCodeStyle.Render(line))
+		case plain:
+			out = append(out, wrapMarkdown(line, width, "", ""))
+		default:
+			out = append(out, renderMarkdownLine(line, width))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderMarkdownLine styles one line outside a code block.
+func renderMarkdownLine(line string, width int) string {
+	if m := mdHeading.FindStringSubmatch(line); m != nil {
+		return mdHeadingStyle.Render(wrapMarkdown(m[1], width, "", ""))
+	}
+	if mdRule.MatchString(line) {
+		n := len([]rune(archiveSeparator))
+		if width > 0 {
+			n = min(n, width)
+		}
+		return dimStyle.Render(strings.Repeat("─", n))
+	}
+	if m := mdList.FindStringSubmatch(line); m != nil {
+		marker := m[2]
+		if !strings.ContainsAny(marker[len(marker)-1:], ".)") {
+			marker = "•"
+		}
+		prefix := m[1] + marker + " "
+		return wrapMarkdown(renderInline(m[3]), width, prefix, strings.Repeat(" ", ansi.StringWidth(prefix)))
+	}
+	if m := mdQuote.FindStringSubmatch(line); m != nil {
+		return mdQuoteStyle.Render(wrapMarkdown(m[1], width, "│ ", "│ "))
+	}
+	return wrapMarkdown(renderInline(line), width, "", "")
+}
+
+// renderInline styles inline code, bold, and italic spans, dropping their
+// markers.
+func renderInline(s string) string {
+	return mdInline.ReplaceAllStringFunc(s, func(span string) string {
+		switch {
+		case strings.HasPrefix(span, "`"):
+			return mdCodeStyle.Render(span[1 : len(span)-1])
+		case strings.HasPrefix(span, "**"), strings.HasPrefix(span, "__"):
+			return mdBoldStyle.Render(span[2 : len(span)-2])
+		default:
+			return mdItalicStyle.Render(span[1 : len(span)-1])
+		}
+	})
+}
+
+// wrapMarkdown wraps s to width columns, starting the first line with
+// first and the rest with indent, which must be as wide as first.
+func wrapMarkdown(s string, width int, first, indent string) string {
+	if width <= 0 {
+		return first + s
+	}
+	lines := strings.Split(ansi.Wrap(s, max(width-ansi.StringWidth(indent), 1), ""), "\n")
+	lines[0] = first + lines[0]
+	for i := 1; i < len(lines); i++ {
+		lines[i] = indent + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,139 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		width int
+		plain bool
+		want  string
+	}{
+		{
+			name: "headings drop their markers",
+			src:  "# Worklog\n\n## Phase: execute ##",
+			want: "Worklog\n\nPhase: execute",
+		},
+		{
+			name: "bold, italic, and inline code drop their markers",
+			src:  "**Status:** PASS in *12s*, see `main.go`",
+			want: "Status: PASS in 12s, see main.go",
+		},
+		{
+			name: "snake_case words are left alone",
+			src:  "set files_changed and max_retries",
+			want: "set files_changed and max_retries",
+		},
+		{
+			name: "bullets and numbered lists",
+			src:  "- one\n  * nested\n2. second",
+			want: "• one\n  • nested\n2. second",
+		},
+		{
+			name:  "list items wrap under their text",
+			src:   "- alpha beta gamma delta",
+			width: 12,
+			want:  "• alpha beta\n  gamma\n  delta",
+		},
+		{
+			name:  "paragraphs wrap to the width",
+			src:   "alpha beta gamma delta",
+			width: 11,
+			want:  "alpha beta\ngamma delta",
+		},
+		{
+			name:  "code blocks are indented and not wrapped",
+			src:   "```go\nfunc longFunctionName() {}\n```",
+			width: 10,
+			want:  "  func longFunctionName() {}",
+		},
+		{
+			name: "quotes and rules",
+			src:  "> needs work\n---",
+			want: "│ needs work\n" + archiveSeparator,
+		},
+		{
+			name:  "plain keeps the source and wraps it",
+			src:   "## Summary\n\n**alpha** beta gamma\n```\nkeep   this\n```",
+			width: 12,
+			plain: true,
+			want:  "## Summary\n\n**alpha**\nbeta gamma\n```\nkeep   this\n```",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given archived markdown
+			// When it is rendered for the pane
+			got := stripANSI(renderMarkdown(tt.src, tt.width, tt.plain))
+
+			// Then markers are replaced by styling and lines fit the width
+			if got != tt.want {
+				t.Errorf("renderMarkdown() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingArchiveReader counts reads so tests can tell when the archive
+// was rendered again.
+type countingArchiveReader struct {
+	stubArchiveReader
+	reads int
+}
+
+func (c *countingArchiveReader) ReadSummary(beadID string) (string, error) {
+	c.reads++
+	return c.stubArchiveReader.ReadSummary(beadID)
+}
+
+func TestModel_ClosedBeadDetailCachedByWidth(t *testing.T) {
+	// Given a closed bead with a long archived summary, resolved and shown
+	ar := &countingArchiveReader{stubArchiveReader: stubArchiveReader{
+		summaries: map[string]string{"cap-c01": "## Summary\n\n" + strings.Repeat("word ", 40)},
+	}}
+	beads := []BeadSummary{{ID: "cap-c01", Title: "Done task", Priority: 2, Type: "task", Closed: true}}
+	m := NewModel(WithBeadLister(&stubLister{beads: beads}), WithArchiveReader(ar))
+	m.plainMarkdown = false
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	updated, _ = m.Update(BeadListMsg{Beads: beads})
+	m = updated.(Model)
+	m.detailID = "cap-c01"
+	m.resolvingID = "cap-c01"
+	updated, _ = m.Update(BeadResolvedMsg{ID: "cap-c01", Detail: BeadDetail{ID: "cap-c01", Title: "Done task"}})
+	m = updated.(Model)
+	reads := ar.reads
+
+	// When the window is resized to the same width
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(Model)
+
+	// Then the cached rendering is reused
+	if ar.reads != reads {
+		t.Errorf("archive read %d more times on a same-width resize", ar.reads-reads)
+	}
+
+	// When the window narrows
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m = updated.(Model)
+
+	// Then the summary is rendered again, wrapped to the narrower pane
+	if ar.reads == reads {
+		t.Error("archive was not rendered again for the new width")
+	}
+	narrow := stripANSI(m.viewport.View())
+	if strings.Contains(narrow, "## Summary") || !strings.Contains(narrow, "Summary") {
+		t.Errorf("heading not rendered:\n%s", narrow)
+	}
+	for _, line := range strings.Split(narrow, "\n") {
+		if w := len([]rune(strings.TrimRight(line, " "))); w > m.viewport.Width {
+			t.Errorf("line %q is %d wide, pane is %d", line, w, m.viewport.Width)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -39,18 +40,22 @@ type Model struct {
 	width         int
 	height        int
 	viewport      viewport.Model
+	reportView    viewport.Model // Scrolls the campaign right pane; see campaignReportView.
 	help          help.Model
 	browse        browseState
 	browseSpinner spinner.Model
 	pipeline      pipelineState
+	detail        phaseDetailState // Full-screen report of one phase, opened with d.
 	lister        BeadLister
 
 	resolver         BeadResolver
-	cache            *Cache
-	detailID         string // ID currently displayed in right pane
-	resolvingID      string // ID of the bead currently being resolved ("" = idle)
-	resolveErr       error  // last resolve error (nil on success)
-	pendingResolveID string // ID awaiting debounce expiry ("" = no pending debounce)
+	cache            *Cache    // Resolved details, refilled through beadCache after a reload.
+	beadCache        BeadCache // Optional bd cache under lister and resolver.
+	showCacheStats   bool      // Debug footer with beadCache hits and misses, toggled with D.
+	detailID         string    // ID currently displayed in right pane
+	resolvingID      string    // ID of the bead currently being resolved ("" = idle)
+	resolveErr       error     // last resolve error (nil on success)
+	pendingResolveID string    // ID awaiting debounce expiry ("" = no pending debounce)
 
 	runner           PipelineRunner
 	phaseNames       []string
@@ -59,26 +64,59 @@ type Model struct {
 	pipelineOutput   *PipelineOutput
 	pipelineErr      error
 	postPipeline     PostPipelineFunc
+	postRunning      bool                 // Post-pipeline lifecycle is running for the summary's bead.
+	postDone         *PostPipelineDoneMsg // Post-pipeline outcome for the summary's bead.
+	resumeEnabled    bool                 // A checkpoint store is configured, so failed runs can be resumed with r.
 	dispatchedBeadID string
 	lastDispatchedID string // Preserved across returnToBrowse so cursor snaps on next BeadListMsg.
+	createdID        string // Bead filed from the create form; the cursor snaps to it on the next BeadListMsg.
+	dispatchedAt     time.Time
 	aborting         bool
+	pauseFlag        *atomic.Bool // Set by p to pause the foreground pipeline after its running phase.
+	notify           NotifyFunc
+	dispatchCheck    DispatchCheckFunc
+	overlapCheck     OverlapFunc
+	healthCheck      HealthCheckFunc
+	healthErr        error // Startup provider health check failure; shown as a banner.
+	worktreeFn       WorktreeFunc
+	worklogPathFn    WorklogPathFunc
+	clipboard        func(text string) error // Copies the worktree path on y; copyOSC52 unless a test replaces it.
+	execProcess      execFunc                // Suspends the dashboard for e and E; tea.ExecProcess unless a test replaces it.
+	copyFallback     string                  // Worktree path shown in the help line when copying failed.
+	dispatchErr      error                   // Set when dispatchCheck blocked a dispatch; shown in the browse detail pane.
 
 	backgroundMode Mode // Non-zero when pipeline/campaign is running while user is in browse.
 
+	queue queueState // Beads selected in browse mode, run one at a time.
+
 	campaign       campaignState
 	campaignRunner CampaignRunner
 	campaignDone   *CampaignDoneMsg // set on CampaignDoneMsg or synthesized on channel close
 	campaignErr    error            // set on CampaignErrorMsg from runner failure
+	taskStore      CampaignTaskStore
+	retryingID     string // Bead being retried from the campaign summary ("" = none).
 
 	confirm       confirmState
-	hasValidation bool // true when campaign validation phases are configured
+	hasValidation bool   // true when campaign validation phases are configured
+	failureMode   string // Campaign failure mode, shown on the campaign confirmation.
+	breaker       string // Campaign circuit breaker, described for the confirmation.
+
+	create  createState // Create-bead form; see ModeCreate.
+	creator BeadCreator // Files beads from the form; nil disables n.
 
-	archive ArchiveReader
+	archive       ArchiveReader
+	plainMarkdown bool // Show archived markdown as source (NO_COLOR or no color support).
 
 	activeProvider string   // Currently selected provider name (default from config).
 	providerNames  []string // Registered provider names for cycling.
 
 	statusMsg string // Transient status shown between panes and help bar; cleared by statusClearMsg.
+
+	refreshInterval time.Duration // Auto-refresh period for the bead list (0 = off).
+	refreshedAt     time.Time     // Last successful bead list load.
+	refreshDue      time.Time     // Earliest time the next auto-refresh may start.
+	refreshing      bool          // A background reload is in flight.
+	refreshErr      error         // Last background reload failure, shown in the help bar.
 }
 
 // newBrowseSpinner returns a spinner for browse mode loading states.
@@ -95,10 +133,14 @@ func NewModel(opts ...ModelOption) Model {
 		mode:          ModeBrowse,
 		focus:         PaneLeft,
 		viewport:      viewport.New(0, 0),
+		reportView:    viewport.New(0, 0),
 		help:          help.New(),
 		browse:        newBrowseState(),
 		browseSpinner: newBrowseSpinner(),
 		cache:         NewCache(),
+		plainMarkdown: plainMarkdown(),
+		clipboard:     copyOSC52,
+		execProcess:   tea.ExecProcess,
 	}
 	for _, o := range opts {
 		o(&m)
@@ -109,16 +151,34 @@ func NewModel(opts ...ModelOption) Model {
 // ModelOption configures a Model during construction.
 type ModelOption func(*Model)
 
+// WithBeadCache sets the bd cache the BeadLister and BeadResolver read
+// through, so r clears it and D shows its hit and miss counts.
+func WithBeadCache(c BeadCache) ModelOption {
+	return func(m *Model) { m.beadCache = c }
+}
+
 // WithBeadLister sets the BeadLister used to fetch the bead list.
 func WithBeadLister(l BeadLister) ModelOption {
 	return func(m *Model) { m.lister = l }
 }
 
+// WithBeadCreator sets the BeadCreator that files beads from the form n
+// opens in browse mode.
+func WithBeadCreator(c BeadCreator) ModelOption {
+	return func(m *Model) { m.creator = c }
+}
+
 // WithBeadResolver sets the BeadResolver used to fetch bead details.
 func WithBeadResolver(r BeadResolver) ModelOption {
 	return func(m *Model) { m.resolver = r }
 }
 
+// WithCheckpointResume offers r on a failed pipeline summary to resume the
+// run from its checkpoint. Only set it when the runner saves checkpoints.
+func WithCheckpointResume() ModelOption {
+	return func(m *Model) { m.resumeEnabled = true }
+}
+
 // WithPipelineRunner sets the PipelineRunner used to dispatch pipelines.
 func WithPipelineRunner(r PipelineRunner) ModelOption {
 	return func(m *Model) { m.runner = r }
@@ -130,7 +190,7 @@ func WithPhaseNames(names []string) ModelOption {
 }
 
 // WithPostPipelineFunc sets the function called after a pipeline completes
-// and the user returns to browse mode. It runs in a background goroutine.
+// successfully. It runs as a tea.Cmd once the summary is shown.
 func WithPostPipelineFunc(fn PostPipelineFunc) ModelOption {
 	return func(m *Model) { m.postPipeline = fn }
 }
@@ -146,6 +206,13 @@ func WithCampaignValidation(v bool) ModelOption {
 	return func(m *Model) { m.hasValidation = v }
 }
 
+// WithCampaignPolicy sets the failure mode and circuit breaker description
+// the campaign confirmation shows, so it is clear whether the remaining
+// tasks run after one fails.
+func WithCampaignPolicy(failureMode, breaker string) ModelOption {
+	return func(m *Model) { m.failureMode, m.breaker = failureMode, breaker }
+}
+
 // WithArchiveReader sets the ArchiveReader used to fetch archived pipeline
 // results for closed beads.
 func WithArchiveReader(ar ArchiveReader) ModelOption {
@@ -162,6 +229,48 @@ func WithProviderNames(names []string, active string) ModelOption {
 	}
 }
 
+// WithNotifyFunc sets the function called when a dispatched pipeline or
+// campaign finishes. It runs in a background goroutine.
+func WithNotifyFunc(fn NotifyFunc) ModelOption {
+	return func(m *Model) { m.notify = fn }
+}
+
+// WithDispatchCheck sets a preflight check run after the user confirms a
+// dispatch. If it fails, the dashboard stays in browse mode and shows the error.
+func WithDispatchCheck(fn DispatchCheckFunc) ModelOption {
+	return func(m *Model) { m.dispatchCheck = fn }
+}
+
+// WithHealthCheck sets the provider health check run once at startup. If it
+// fails, a banner above the panes explains why dispatches will fail.
+func WithHealthCheck(fn HealthCheckFunc) ModelOption {
+	return func(m *Model) { m.healthCheck = fn }
+}
+
+// WithOverlapCheck sets the function used to warn on the confirmation screen
+// about files other in-flight capsules have changed.
+func WithOverlapCheck(fn OverlapFunc) ModelOption {
+	return func(m *Model) { m.overlapCheck = fn }
+}
+
+// WithCampaignTaskStore sets the store used to show stored phase reports in
+// the campaign summary and to record tasks that pass a retry.
+func WithCampaignTaskStore(ts CampaignTaskStore) ModelOption {
+	return func(m *Model) { m.taskStore = ts }
+}
+
+// WithWorktreeFunc sets the function that resolves a dispatched bead's
+// worktree path and branch for the pipeline header.
+func WithWorktreeFunc(fn WorktreeFunc) ModelOption {
+	return func(m *Model) { m.worktreeFn = fn }
+}
+
+// WithWorklogPathFunc sets the function that finds the worklog e opens in
+// $EDITOR from the pipeline and summary views. Without it e is disabled.
+func WithWorklogPathFunc(fn WorklogPathFunc) ModelOption {
+	return func(m *Model) { m.worklogPathFn = fn }
+}
+
 // listenForEvents returns a tea.Cmd that reads one message from ch.
 // On channel close, it returns channelClosedMsg. Returns nil if ch is nil.
 func listenForEvents(ch <-chan tea.Msg) tea.Cmd {
@@ -178,10 +287,18 @@ func listenForEvents(ch <-chan tea.Msg) tea.Cmd {
 }
 
 // dispatchPipeline runs a pipeline in the calling goroutine, bridging
-// status events to ch via statusFn. It sends PipelineDoneMsg or
-// PipelineErrorMsg on completion and closes ch when done.
-func dispatchPipeline(ctx context.Context, runner PipelineRunner, input PipelineInput, ch chan<- tea.Msg) {
+// status events to ch via statusFn. With worktreeFn set it first sends
+// PipelineStartedMsg. It sends PipelineDoneMsg or PipelineErrorMsg on
+// completion and closes ch when done.
+func dispatchPipeline(ctx context.Context, runner PipelineRunner, worktreeFn WorktreeFunc, input PipelineInput, ch chan<- tea.Msg) {
 	defer close(ch)
+	if worktreeFn != nil {
+		path, branch := worktreeFn(input.BeadID)
+		select {
+		case ch <- PipelineStartedMsg{BeadID: input.BeadID, WorktreePath: path, Branch: branch}:
+		case <-ctx.Done():
+		}
+	}
 	statusFn := func(msg PhaseUpdateMsg) {
 		select {
 		case ch <- msg:
@@ -193,7 +310,7 @@ func dispatchPipeline(ctx context.Context, runner PipelineRunner, input Pipeline
 	// the completion message must reach the receiver so channelClosedMsg
 	// processing can build the correct summary/status.
 	if err != nil {
-		ch <- PipelineErrorMsg{Err: err}
+		ch <- PipelineErrorMsg{Err: err, Output: output}
 		return
 	}
 	ch <- PipelineDoneMsg{Output: output}
@@ -237,9 +354,20 @@ func resolveBeadCmd(resolver BeadResolver, id string) tea.Cmd {
 // formatBeadDetail renders a BeadDetail as plain text for the viewport.
 func formatBeadDetail(d BeadDetail) string {
 	var b strings.Builder
-	fmt.Fprintf(&b, "%s  %s  %s\n", d.ID, PriorityBadge(d.Priority), d.Type)
+	// The header matches the browse list: ID, priority badge, [type].
+	b.WriteString(d.ID + "  " + PriorityBadge(d.Priority))
+	if d.Type != "" {
+		b.WriteString("  [" + d.Type + "]")
+	}
+	if d.Status != "" {
+		b.WriteString("  " + d.Status)
+	}
+	b.WriteByte('\n')
 	b.WriteString(d.Title)
 	b.WriteByte('\n')
+	if len(d.Labels) > 0 {
+		fmt.Fprintf(&b, "Labels: %s\n", strings.Join(d.Labels, ", "))
+	}
 
 	if d.EpicID != "" {
 		fmt.Fprintf(&b, "\nEpic: %s — %s", d.EpicID, d.EpicTitle)
@@ -260,23 +388,35 @@ func formatBeadDetail(d BeadDetail) string {
 }
 
 // renderDetailContent formats a bead detail for the viewport. For closed beads
-// with an archive reader, it appends archived summary and worklog data.
+// with an archive reader, it appends archived summary and worklog data,
+// rendered for the pane width and cached so redraws and repeated resizes
+// to the same width do not render it again.
 func (m Model) renderDetailContent(d BeadDetail) string {
 	if m.archive == nil {
 		return formatBeadDetail(d)
 	}
 	if bead, ok := m.browse.SelectedBead(); ok && bead.Closed {
+		width := m.viewport.Width
+		if text, ok := m.cache.Rendered(d.ID, width); ok {
+			return text
+		}
 		summary, _ := m.archive.ReadSummary(d.ID)
 		worklog, _ := m.archive.ReadWorklog(d.ID)
-		return formatClosedBeadDetail(d, summary, worklog)
+		// A worklog that can't be parsed is still shown as text.
+		phases, _ := m.archive.ReadPhaseEntries(d.ID)
+		text := formatClosedBeadDetail(d, phases, summary, worklog, width, m.plainMarkdown)
+		m.cache.SetRendered(d.ID, width, text)
+		return text
 	}
 	return formatBeadDetail(d)
 }
 
-// formatClosedBeadDetail renders a closed bead's detail with archived summary
-// and worklog below a separator. If both summary and worklog are empty, renders
-// as a normal bead detail without a separator.
-func formatClosedBeadDetail(d BeadDetail, summary, worklog string) string {
+// formatClosedBeadDetail renders a closed bead's detail with archived data
+// below a separator: a table of the phases parsed from the worklog, then
+// the summary and worklog as markdown wrapped to width (see renderMarkdown).
+// If both summary and worklog are empty, renders as a normal bead detail
+// without a separator.
+func formatClosedBeadDetail(d BeadDetail, phases []ArchivedPhase, summary, worklog string, width int, plain bool) string {
 	base := formatBeadDetail(d)
 	if summary == "" && worklog == "" {
 		return base
@@ -286,12 +426,16 @@ func formatClosedBeadDetail(d BeadDetail, summary, worklog string) string {
 	b.WriteString(base)
 	b.WriteString("\n\n" + archiveSeparator + "\n")
 
+	if len(phases) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", formatArchivedPhases(phases))
+	}
+
 	if summary != "" {
-		fmt.Fprintf(&b, "\n%s", summary)
+		fmt.Fprintf(&b, "\n%s", renderMarkdown(summary, width, plain))
 	}
 
 	if worklog != "" {
-		fmt.Fprintf(&b, "\n\nWorklog:\n%s", worklog)
+		fmt.Fprintf(&b, "\n\nWorklog:\n%s", renderMarkdown(worklog, width, plain))
 	}
 
 	return b.String()
@@ -300,10 +444,21 @@ func formatClosedBeadDetail(d BeadDetail, summary, worklog string) string {
 // Init returns the initial command. If a BeadLister was provided,
 // it fires an async fetch for the bead list with spinner animation.
 func (m Model) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.healthCheck != nil {
+		check := m.healthCheck
+		cmds = append(cmds, func() tea.Msg { return healthCheckMsg{Err: check()} })
+	}
 	if m.lister != nil {
-		return tea.Batch(initBrowse(m.lister), m.browseSpinner.Tick)
+		cmds = append(cmds, initBrowse(m.lister), m.browseSpinner.Tick)
+		if m.autoRefreshEnabled() {
+			cmds = append(cmds, refreshTickCmd())
+		}
 	}
-	return nil
+	if len(cmds) == 0 {
+		return nil
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handles incoming messages with mode-based routing.
@@ -316,21 +471,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		_, rightWidth := PaneWidths(msg.Width)
 		m.viewport.Width = max(rightWidth-borderChrome, 0)
 		m.viewport.Height = m.contentHeight()
-		return m, nil
+		m.reportView.Width, m.reportView.Height = m.viewport.Width, m.viewport.Height
+		if detail, ok := m.cache.Get(m.detailID); ok && m.detailID != "" {
+			m.viewport.SetContent(m.renderDetailContent(*detail))
+		}
+		return m.refreshPhaseDetail(), nil
 
 	case BeadListMsg:
 		m.browse, _ = m.browse.Update(msg)
+		m = m.markRefreshed(msg.Err)
 		if m.lastDispatchedID != "" {
-			for i, fn := range m.browse.flatNodes {
-				if fn.Node.Bead.ID == m.lastDispatchedID {
-					m.browse.cursor = i
-					break
-				}
-			}
+			m.browse = m.browse.selectID(m.lastDispatchedID)
 			m.lastDispatchedID = ""
 		}
+		if m.createdID != "" {
+			m.browse = m.browse.selectID(m.createdID)
+			m.createdID = ""
+		}
 		return m.maybeResolve()
 
+	case refreshTickMsg:
+		return m.handleRefreshTick()
+
+	case beadsRefreshedMsg:
+		return m.handleBeadsRefreshed(msg)
+
 	case resolveDebounceMsg:
 		if msg.ID != m.pendingResolveID {
 			return m, nil
@@ -360,7 +525,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case RefreshBeadsMsg:
-		m.cache.Invalidate()
+		m.invalidateBeads()
 		m.pendingResolveID = ""
 		m.resolvingID = ""
 		m.resolveErr = nil
@@ -388,22 +553,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case DispatchMsg:
 		return m.handleDispatch(msg)
 
+	case QueueStartMsg:
+		return m.handleQueueStart(msg)
+
+	case queueCheckMsg:
+		if msg.Err != nil {
+			m.focus = PaneLeft
+			m.dispatchErr = msg.Err
+			return m, nil
+		}
+		return m.startQueue(msg.Start)
+
+	case QueueAdvanceMsg:
+		return m.handleQueueAdvance(msg)
+
+	case healthCheckMsg:
+		m.healthErr = msg.Err
+		m.viewport.Height = m.contentHeight()
+		m.reportView.Height = m.viewport.Height
+		return m, nil
+
+	case overlapCheckMsg:
+		if m.mode == ModeConfirm && m.confirm.beadID == msg.BeadID {
+			m.confirm.overlaps = msg.Overlaps
+			m.confirm.overlapErr = msg.Err
+		}
+		return m, nil
+
+	case dispatchCheckMsg:
+		if m.mode != ModeConfirm {
+			return m, nil // Confirmation was cancelled while the check ran.
+		}
+		m.mode = ModeBrowse
+		if msg.Err != nil {
+			m.focus = PaneLeft
+			m.dispatchErr = msg.Err
+			return m, nil
+		}
+		return m.handleDispatch(msg.Dispatch)
+
 	case CampaignStartMsg:
 		title := msg.ParentTitle
 		if title == "" {
 			title = m.campaign.parentTitle // Preserve title set during dispatch.
 		}
 		m.campaign = newCampaignState(msg.ParentID, title, msg.Tasks)
+		m.campaign.resumed = msg.Resumed
 		return m, listenForEvents(m.eventCh)
 
-	case CampaignTaskStartMsg, CampaignTaskDoneMsg, SubCampaignStartMsg, SubCampaignDoneMsg:
+	case CampaignTaskStartMsg, CampaignTaskDoneMsg, CampaignTaskSkippedMsg, SubCampaignStartMsg, SubCampaignDoneMsg:
 		var cmd tea.Cmd
 		m.campaign, cmd = m.campaign.Update(msg)
 		return m, tea.Batch(cmd, listenForEvents(m.eventCh))
 
 	case CampaignDoneMsg:
 		m.campaignDone = &msg
-		return m, listenForEvents(m.eventCh)
+		return m, tea.Batch(m.notifyCmd(CompletionEvent{
+			BeadID:   msg.ParentID,
+			Campaign: true,
+			Success:  msg.Failed == 0,
+		}), listenForEvents(m.eventCh))
+
+	case CampaignParentClosedMsg:
+		m.statusMsg = fmt.Sprintf("%s Closed %s", SymbolCheck, msg.ParentID)
+		m.campaign.parentClosed = true
+		m.invalidateBeads()
+		cmds := []tea.Cmd{listenForEvents(m.eventCh)}
+		if m.lister != nil {
+			cmds = append(cmds, initBrowse(m.lister))
+		}
+		return m, tea.Batch(cmds...)
 
 	case CampaignPausedMsg:
 		m.statusMsg = fmt.Sprintf("⚠️  Paused: %s in %s", msg.Reason, msg.BeadID)
@@ -415,60 +634,160 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}))
 		return m, tea.Batch(cmds...)
 
+	case CampaignCircuitBrokenMsg:
+		m.statusMsg = fmt.Sprintf("%s Circuit breaker: %s", SymbolCross, msg.Reason)
+		var cmd tea.Cmd
+		m.campaign, cmd = m.campaign.Update(msg)
+		return m, tea.Batch(cmd, listenForEvents(m.eventCh), tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+			return statusClearMsg{}
+		}))
+
 	case CampaignErrorMsg:
 		m.campaignErr = msg.Err
-		return m, listenForEvents(m.eventCh)
+		var cmd tea.Cmd
+		if !m.aborting {
+			cmd = m.notifyCmd(CompletionEvent{BeadID: m.dispatchedBeadID, Campaign: true, Err: msg.Err})
+		}
+		return m, tea.Batch(cmd, listenForEvents(m.eventCh))
 
-	case CampaignValidationStartMsg:
-		m.campaign.validating = true
-		return m, listenForEvents(m.eventCh)
+	case campaignReportsMsg:
+		// Best-effort: a load error leaves the summary with in-memory reports only.
+		for id, reports := range msg.Reports {
+			if len(m.campaign.taskReports[id]) == 0 && len(reports) > 0 {
+				m.campaign.taskReports[id] = reports
+			}
+		}
+		return m, nil
+
+	case taskRetryDoneMsg:
+		return m.handleTaskRetryDone(msg)
+
+	case taskRetryRecordedMsg:
+		if msg.Err != nil {
+			m.statusMsg = fmt.Sprintf("%s %s: retry passed but %s", SymbolCross, msg.BeadID, msg.Err)
+		} else {
+			m.statusMsg = fmt.Sprintf("%s %s: retry passed, campaign state updated", SymbolCheck, msg.BeadID)
+		}
+		return m, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+			return statusClearMsg{}
+		})
 
-	case CampaignValidationDoneMsg:
-		m.campaign.validating = false
-		m.campaign.validationResult = &msg
+	case CampaignValidationStartMsg, CampaignValidationDoneMsg:
+		m.campaign, _ = m.campaign.Update(msg)
 		return m, listenForEvents(m.eventCh)
 
 	case PhaseUpdateMsg:
-		if m.mode == ModeCampaign || m.backgroundMode == ModeCampaign {
-			var cmd tea.Cmd
+		var cmd tea.Cmd
+		if m.mode == ModeCampaign || m.mode == ModeCampaignSummary || m.backgroundMode == ModeCampaign {
 			m.campaign, cmd = m.campaign.Update(msg)
-			return m, tea.Batch(cmd, listenForEvents(m.eventCh))
+		} else {
+			m.pipeline, cmd = m.pipeline.Update(msg)
+			m = m.refreshPhaseDetail()
 		}
-		var cmd tea.Cmd
-		m.pipeline, cmd = m.pipeline.Update(msg)
 		return m, tea.Batch(cmd, listenForEvents(m.eventCh))
 
+	case PipelineStartedMsg:
+		if msg.BeadID == m.pipeline.beadID {
+			m.pipeline.worktreePath = msg.WorktreePath
+			m.pipeline.branch = msg.Branch
+		}
+		return m, listenForEvents(m.eventCh)
+
+	case clipboardDoneMsg:
+		if msg.Err != nil {
+			m.copyFallback = msg.Path
+			return m, nil
+		}
+		m.copyFallback = ""
+		m.statusMsg = fmt.Sprintf("%s copied %s", SymbolCheck, msg.Path)
+		return m, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+			return statusClearMsg{}
+		})
+
 	case PipelineDoneMsg:
 		m.pipelineOutput = &msg.Output
-		return m, listenForEvents(m.eventCh)
+		m.pipeline = m.pipeline.finish()
+		return m, tea.Batch(m.notifyCmd(CompletionEvent{
+			BeadID:      m.dispatchedBeadID,
+			Success:     msg.Output.Success,
+			FailedPhase: firstFailedPhase(msg.Output.PhaseReports),
+		}), listenForEvents(m.eventCh))
 
 	case PipelineErrorMsg:
 		m.pipelineErr = msg.Err
-		return m, listenForEvents(m.eventCh)
+		m.pipelineOutput = &msg.Output
+		m.pipeline = m.pipeline.finish()
+		if m.pipelinePaused() {
+			// Not a completion: the bead is resumed later from browse.
+			m.browse.paused[m.dispatchedBeadID] = true
+			return m, listenForEvents(m.eventCh)
+		}
+		var cmd tea.Cmd
+		if !m.aborting {
+			cmd = m.notifyCmd(CompletionEvent{
+				BeadID:      m.dispatchedBeadID,
+				FailedPhase: firstFailedPhase(msg.Output.PhaseReports),
+				Err:         msg.Err,
+			})
+		}
+		return m, tea.Batch(cmd, listenForEvents(m.eventCh))
+
+	case notifyDoneMsg:
+		if msg.Err == nil {
+			return m, nil
+		}
+		m.statusMsg = fmt.Sprintf("%s notify failed: %s", SymbolCross, msg.Err)
+		return m, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+			return statusClearMsg{}
+		})
 
 	case PostPipelineDoneMsg:
-		if msg.Err != nil {
-			m.statusMsg = fmt.Sprintf("%s %s: post-pipeline failed: %s", SymbolCross, msg.BeadID, msg.Err)
-		} else {
-			m.statusMsg = fmt.Sprintf("%s %s: merged to main, bead closed, worktree removed", SymbolCheck, msg.BeadID)
+		if m.queue.posting && msg.BeadID == m.dispatchedBeadID {
+			return m.handleQueuedPostDone(msg)
 		}
+		if m.mode == ModeSummary && msg.BeadID == m.dispatchedBeadID {
+			m.postRunning = false
+			m.postDone = &msg
+			return m, nil
+		}
+		m.statusMsg = postPipelineStatus(msg)
 		return m, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
 			return statusClearMsg{}
 		})
 
+	case editorDoneMsg:
+		if msg.Err == nil {
+			return m, nil
+		}
+		return m.editorFailed(msg)
+
 	case statusClearMsg:
 		m.statusMsg = ""
 		return m, nil
 
+	case beadCreatedMsg:
+		return m.handleBeadCreated(msg)
+
 	case channelClosedMsg:
 		m.cancelPipeline = nil
 		m.eventCh = nil
+		if m.queue.running() {
+			// Checked first: the queue, not the mode, decides what follows.
+			return m.finishQueuedBead()
+		}
+		if m.mode == ModeCampaignSummary {
+			m.retryingID = "" // Task retry finished; its result arrived in taskRetryDoneMsg.
+			return m, nil
+		}
 		if m.mode == ModeBrowse && m.backgroundMode != 0 {
 			return m.handleBackgroundComplete()
 		}
 		if m.aborting {
 			return m.returnToBrowseAfterAbort()
 		}
+		if m.mode == ModePipeline && m.pipelinePaused() {
+			return m.returnToBrowseAfterPause()
+		}
 		if m.mode == ModeCampaign {
 			if m.campaignDone == nil {
 				m.campaignDone = &CampaignDoneMsg{
@@ -479,47 +798,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			m.mode = ModeCampaignSummary
-			return m, nil
+			return m, m.loadTaskReportsCmd()
 		}
 		m.mode = ModeSummary
-		return m, nil
-
-	case elapsedTickMsg:
-		var cmd tea.Cmd
-		switch {
-		case m.mode == ModePipeline || m.backgroundMode == ModePipeline:
-			m.pipeline, cmd = m.pipeline.Update(msg)
-		case m.mode == ModeCampaign || m.backgroundMode == ModeCampaign:
-			m.campaign, cmd = m.campaign.Update(msg)
-		default:
-			return m, nil
-		}
-		return m, cmd
+		return m.startPostPipeline()
 
 	case spinner.TickMsg:
+		// The spinner tick also redraws the elapsed counters, so a live run
+		// needs no timer of its own.
 		var cmd tea.Cmd
 		var cmds []tea.Cmd
 		// Route spinner ticks to the foreground mode.
 		switch m.mode {
 		case ModePipeline:
 			m.pipeline, cmd = m.pipeline.Update(msg)
-			cmds = append(cmds, cmd)
+			if m.pipeline.endedAt.IsZero() {
+				cmds = append(cmds, cmd)
+			}
 		case ModeCampaign:
 			m.campaign, cmd = m.campaign.Update(msg)
 			cmds = append(cmds, cmd)
+		case ModeCampaignSummary:
+			if m.retryingID != "" {
+				m.campaign, cmd = m.campaign.Update(msg)
+				cmds = append(cmds, cmd)
+			}
 		case ModeBrowse:
 			if m.browse.loading || m.resolvingID != "" {
 				m.browseSpinner, cmd = m.browseSpinner.Update(msg)
 				cmds = append(cmds, cmd)
 			}
-		default:
-			return m, nil
 		}
 		// Also route to background mode to keep spinners alive for re-entry.
 		switch m.backgroundMode {
 		case ModePipeline:
 			m.pipeline, cmd = m.pipeline.Update(msg)
-			cmds = append(cmds, cmd)
+			if m.pipeline.endedAt.IsZero() {
+				cmds = append(cmds, cmd)
+			}
 		case ModeCampaign:
 			m.campaign, cmd = m.campaign.Update(msg)
 			cmds = append(cmds, cmd)
@@ -538,32 +854,81 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKey processes key messages with global and mode-specific routing.
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.phaseDetailShown() {
+		return m.handlePhaseDetailKey(msg)
+	}
+	// d, or enter while the phase list has focus, opens the selected
+	// phase's full report.
+	if m.mode == ModePipeline && m.queue.confirmAbort {
+		return m.handleQueueAbortKey(msg)
+	}
+	if m.mode == ModeQueueSummary {
+		return m.handleQueueSummaryKey(msg)
+	}
+	if m.mode == ModePipeline || m.mode == ModeSummary {
+		if k := msg.String(); k == "d" || (k == "enter" && m.mode == ModePipeline && m.focus == PaneLeft) {
+			return m.openPhaseDetail()
+		}
+	}
+
 	// Summary modes: Enter/Esc/b returns to browse, other keys allow navigation.
 	if m.mode == ModeSummary {
 		switch msg.String() {
 		case "enter", "esc", "b":
 			return m.returnToBrowse()
+		case "r":
+			if m.canResume() {
+				return m.handleResumeDispatch()
+			}
+			return m, nil
 		}
 	}
 	if m.mode == ModeCampaignSummary {
 		switch msg.String() {
-		case "enter", "esc", "b":
+		case "e":
+			return m.handleTaskRetry()
+		case "esc", "q":
+			if m.retryingID != "" {
+				// Cancel the retry; the task is marked failed when it returns.
+				if m.cancelPipeline != nil {
+					m.cancelPipeline()
+				}
+				return m, nil
+			}
+			return m.returnToBrowseFromCampaign()
+		case "enter", "b":
+			if m.retryingID != "" {
+				return m, nil
+			}
 			return m.returnToBrowseFromCampaign()
 		}
 	}
 
-	// Confirm mode: Enter dispatches, Esc/q returns to browse.
+	// Confirm mode: Enter/y dispatches, Esc/q/n returns to browse. With
+	// nothing to run, confirming just closes the dialog.
 	if m.mode == ModeConfirm {
 		switch msg.String() {
-		case "enter":
-			m.mode = ModeBrowse // Temporarily set back before dispatch routing.
-			return m.handleDispatch(DispatchMsg{
+		case "enter", "y":
+			if m.confirm.nothingToRun() {
+				m.mode = ModeBrowse
+				m.focus = PaneLeft
+				return m, nil
+			}
+			dispatch := DispatchMsg{
 				BeadID:    m.confirm.beadID,
 				BeadType:  m.confirm.beadType,
 				BeadTitle: m.confirm.beadTitle,
 				Provider:  m.confirm.provider,
-			})
-		case "esc", "q":
+			}
+			if m.dispatchCheck != nil {
+				check := m.dispatchCheck
+				return m, func() tea.Msg {
+					return dispatchCheckMsg{Dispatch: dispatch, Err: check()}
+				}
+			}
+			m.mode = ModeBrowse // Temporarily set back before dispatch routing.
+			return m.handleDispatch(dispatch)
+		case "esc", "q", "n":
 			m.mode = ModeBrowse
 			m.focus = PaneLeft
 			return m, nil
@@ -571,6 +936,24 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil // Swallow all other keys in confirm mode.
 	}
 
+	if m.mode == ModeCreate {
+		return m.handleCreateKey(msg)
+	}
+
+	// Any key dismisses a blocked-dispatch error.
+	if m.mode == ModeBrowse {
+		m.dispatchErr = nil
+	}
+
+	// The open filter input takes every key but ctrl+c, so q, r, and p
+	// can be typed into it.
+	if m.mode == ModeBrowse && m.browse.filtering && msg.String() != "ctrl+c" {
+		var cmd tea.Cmd
+		m.browse, cmd = m.browse.Update(msg)
+		m, resolveCmd := m.maybeResolve()
+		return m, tea.Batch(cmd, resolveCmd)
+	}
+
 	// Global keys.
 	switch msg.String() {
 	case "esc":
@@ -580,9 +963,11 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "q", "ctrl+c":
 		switch {
 		case m.mode == ModeBrowse && m.backgroundMode != 0:
-			// Abort the background operation, don't quit the app.
+			// Abort the background operation, don't quit the app. A
+			// queue stops with it.
 			if m.cancelPipeline != nil {
 				m.aborting = true
+				m.queue.stopped = m.queue.running()
 				m.cancelPipeline()
 			}
 			return m, nil
@@ -590,6 +975,10 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case (m.mode == ModePipeline || m.mode == ModeCampaign) && m.aborting:
 			return m, tea.Quit
+		case m.mode == ModePipeline && m.cancelPipeline != nil && m.queue.running():
+			// Ask whether to skip this bead or abort the whole queue.
+			m.queue.confirmAbort = true
+			return m, nil
 		case m.mode == ModePipeline && m.cancelPipeline != nil:
 			m.aborting = true
 			m.pipeline.aborting = true
@@ -612,6 +1001,32 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.mode == ModeBrowse && len(m.providerNames) > 1 {
 			return m, func() tea.Msg { return ProviderCycleMsg{} }
 		}
+		if m.mode == ModePipeline {
+			return m.requestPause()
+		}
+	case "y":
+		if (m.mode == ModePipeline || m.mode == ModeSummary) && m.pipeline.worktreePath != "" {
+			return m, copyCmd(m.clipboard, m.pipeline.worktreePath)
+		}
+	case "e":
+		if (m.mode == ModePipeline || m.mode == ModeSummary) && m.worklogPathFn != nil {
+			return m.openWorklog()
+		}
+	case "E":
+		if (m.mode == ModePipeline || m.mode == ModeSummary) && m.pipeline.worktreePath != "" {
+			return m.openWorktree()
+		}
+	case "n":
+		// Not while a run is in the background: its completion expects
+		// browse mode.
+		if m.mode == ModeBrowse && m.creator != nil && m.backgroundMode == 0 {
+			return m.openCreate()
+		}
+	case "D":
+		if m.mode == ModeBrowse && m.beadCache != nil {
+			m.showCacheStats = !m.showCacheStats
+			return m, nil
+		}
 	case "r":
 		if m.mode == ModeBrowse {
 			m.browse.loading = true
@@ -643,14 +1058,19 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.viewport, cmd = m.viewport.Update(msg)
 		return m, cmd
 
-	case m.mode == ModeCampaign && m.focus == PaneLeft:
+	case (m.mode == ModeCampaign || m.mode == ModeCampaignSummary) && m.focus == PaneLeft:
 		var cmd tea.Cmd
+		selected := m.campaign.selectedIdx
 		m.campaign, cmd = m.campaign.Update(msg)
+		if m.campaign.selectedIdx != selected {
+			m.reportView.GotoTop()
+		}
 		return m, cmd
 
-	case m.mode == ModeCampaign && m.focus == PaneRight:
+	case (m.mode == ModeCampaign || m.mode == ModeCampaignSummary) && m.focus == PaneRight:
 		var cmd tea.Cmd
-		m.viewport, cmd = m.viewport.Update(msg)
+		m.reportView = m.campaignReportView()
+		m.reportView, cmd = m.reportView.Update(msg)
 		return m, cmd
 
 	case m.mode == ModeSummary && m.focus == PaneLeft:
@@ -662,16 +1082,6 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		var cmd tea.Cmd
 		m.viewport, cmd = m.viewport.Update(msg)
 		return m, cmd
-
-	case m.mode == ModeCampaignSummary && m.focus == PaneLeft:
-		var cmd tea.Cmd
-		m.campaign, cmd = m.campaign.Update(msg)
-		return m, cmd
-
-	case m.mode == ModeCampaignSummary && m.focus == PaneRight:
-		var cmd tea.Cmd
-		m.viewport, cmd = m.viewport.Update(msg)
-		return m, cmd
 	}
 
 	return m, nil
@@ -691,7 +1101,11 @@ func (m Model) handleConfirmRequest(msg ConfirmRequestMsg) (tea.Model, tea.Cmd)
 		beadType:      msg.BeadType,
 		beadTitle:     msg.BeadTitle,
 		hasValidation: m.hasValidation,
+		failureMode:   m.failureMode,
+		breaker:       m.breaker,
 		provider:      m.activeProvider,
+		phases:        m.phaseNames,
+		resume:        m.browse.paused[msg.BeadID],
 	}
 	// For features/epics, collect open children from the browse tree.
 	if msg.BeadType == "feature" || msg.BeadType == "epic" {
@@ -699,7 +1113,14 @@ func (m Model) handleConfirmRequest(msg ConfirmRequestMsg) (tea.Model, tea.Cmd)
 	}
 	m.confirm = cs
 	m.mode = ModeConfirm
-	return m, nil
+	if m.overlapCheck == nil {
+		return m, nil
+	}
+	check, id := m.overlapCheck, msg.BeadID
+	return m, func() tea.Msg {
+		overlaps, err := check(id)
+		return overlapCheckMsg{BeadID: id, Overlaps: overlaps, Err: err}
+	}
 }
 
 // handleDispatch branches on BeadType: feature/epic → campaign, else → pipeline.
@@ -710,8 +1131,15 @@ func (m Model) handleDispatch(msg DispatchMsg) (tea.Model, tea.Cmd) {
 	return m.handlePipelineDispatch(msg)
 }
 
-// handlePipelineDispatch transitions to pipeline mode and starts the pipeline goroutine.
+// handlePipelineDispatch transitions to pipeline mode and starts the pipeline
+// goroutine. A bead paused earlier resumes from its checkpoint.
 func (m Model) handlePipelineDispatch(msg DispatchMsg) (tea.Model, tea.Cmd) {
+	return m.startPipeline(msg, m.browse.paused[msg.BeadID])
+}
+
+// startPipeline transitions to pipeline mode and runs the pipeline in a
+// goroutine. With resume set, the run continues from the bead's checkpoint.
+func (m Model) startPipeline(msg DispatchMsg, resume bool) (Model, tea.Cmd) {
 	if m.runner == nil {
 		return m, nil
 	}
@@ -722,20 +1150,27 @@ func (m Model) handlePipelineDispatch(msg DispatchMsg) (tea.Model, tea.Cmd) {
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancelPipeline = cancel
 	ch := make(chan tea.Msg, 16)
-	m.eventCh = ch
+	m.eventCh = relayEvents(ch)
 	m.mode = ModePipeline
 	m.focus = PaneLeft
 	m.pipeline = newPipelineState(m.phaseNames)
+	m.detail = phaseDetailState{}
 	m.pipeline.beadID = msg.BeadID
 	m.pipeline.beadTitle = msg.BeadTitle
+	m.pipeline.beadType = msg.BeadType
 	m.pipeline.provider = msg.Provider
 	m.pipelineOutput = nil
 	m.pipelineErr = nil
+	m.postRunning = false
+	m.postDone = nil
 	m.aborting = false
+	m.pauseFlag = new(atomic.Bool)
+	delete(m.browse.paused, msg.BeadID)
 	m.dispatchedBeadID = msg.BeadID
-	input := PipelineInput{BeadID: msg.BeadID, Provider: msg.Provider}
-	go dispatchPipeline(ctx, m.runner, input, ch)
-	return m, tea.Batch(m.pipeline.spinner.Tick, elapsedTickCmd(), listenForEvents(ch))
+	m.dispatchedAt = time.Now()
+	input := PipelineInput{BeadID: msg.BeadID, Provider: msg.Provider, Resume: resume, PauseRequested: m.pauseFlag.Load}
+	go dispatchPipeline(ctx, m.runner, m.worktreeFn, input, ch)
+	return m, tea.Batch(m.pipeline.spinner.Tick, listenForEvents(m.eventCh))
 }
 
 // handleCampaignDispatch transitions to campaign mode and starts the campaign goroutine.
@@ -758,8 +1193,34 @@ func (m Model) handleCampaignDispatch(msg DispatchMsg) (tea.Model, tea.Cmd) {
 	m.campaignDone = nil
 	m.campaignErr = nil
 	m.dispatchedBeadID = msg.BeadID
+	m.dispatchedAt = time.Now()
 	go dispatchCampaign(ctx, m.campaignRunner, m.runner, msg.BeadID, msg.Provider, ch)
-	return m, tea.Batch(m.campaign.pipeline.spinner.Tick, elapsedTickCmd(), listenForEvents(ch))
+	return m, tea.Batch(m.campaign.pipeline.spinner.Tick, listenForEvents(ch))
+}
+
+// notifyCmd returns a tea.Cmd that calls the NotifyFunc with ev, filling in
+// the elapsed time since dispatch. Returns nil when no NotifyFunc is set.
+func (m Model) notifyCmd(ev CompletionEvent) tea.Cmd {
+	if m.notify == nil {
+		return nil
+	}
+	fn := m.notify
+	if !m.dispatchedAt.IsZero() {
+		ev.Duration = time.Since(m.dispatchedAt)
+	}
+	return func() tea.Msg {
+		return notifyDoneMsg{Err: fn(ev)}
+	}
+}
+
+// firstFailedPhase returns the name of the first failed or errored phase report.
+func firstFailedPhase(reports []PhaseReport) string {
+	for _, r := range reports {
+		if r.Status == PhaseFailed || r.Status == PhaseError {
+			return r.PhaseName
+		}
+	}
+	return ""
 }
 
 // maybeResolve checks if the selected bead changed and triggers a resolve
@@ -798,6 +1259,7 @@ func (m Model) maybeResolve() (Model, tea.Cmd) {
 func (m Model) handleBackgroundComplete() (Model, tea.Cmd) {
 	bgMode := m.backgroundMode
 	beadID := m.dispatchedBeadID
+	postInput := m.postPipelineInput()
 	m.lastDispatchedID = beadID // snap cursor on next bead list refresh
 	m.backgroundMode = 0
 	m.aborting = false
@@ -811,13 +1273,15 @@ func (m Model) handleBackgroundComplete() (Model, tea.Cmd) {
 		m.statusMsg = fmt.Sprintf("%s Campaign error: %s", SymbolCross, m.campaignErr)
 	case bgMode == ModeCampaign:
 		m.statusMsg = fmt.Sprintf("%s Background operation complete", SymbolCheck)
+	case m.pipelinePaused():
+		m.statusMsg = pausedStatus(beadID)
 	case m.pipelineErr != nil:
 		m.statusMsg = fmt.Sprintf("%s Pipeline failed: %s", SymbolCross, m.pipelineErr)
 	default:
 		m.statusMsg = fmt.Sprintf("%s Pipeline complete", SymbolCheck)
 	}
 
-	m.cache.Invalidate()
+	m.invalidateBeads()
 	m.campaignDone = nil
 	m.campaignErr = nil
 	var cmds []tea.Cmd
@@ -826,11 +1290,7 @@ func (m Model) handleBackgroundComplete() (Model, tea.Cmd) {
 	// Campaigns handle their own lifecycle, but standalone pipelines need
 	// merge/close/cleanup to run even when they completed in the background.
 	if bgMode != ModeCampaign && m.postPipeline != nil && beadID != "" && m.pipelineErr == nil {
-		ppFn := m.postPipeline
-		cmds = append(cmds, func() tea.Msg {
-			err := ppFn(beadID)
-			return PostPipelineDoneMsg{BeadID: beadID, Err: err}
-		})
+		cmds = append(cmds, postPipelineCmd(m.postPipeline, postInput))
 	}
 
 	if m.lister != nil {
@@ -859,21 +1319,39 @@ func (m Model) sendToBackground() (Model, tea.Cmd) {
 // contentHeight returns the usable height for pane content,
 // accounting for border chrome and the help bar.
 func (m Model) contentHeight() int {
-	return max(m.height-borderChrome-helpBarHeight, 1)
+	h := m.height - borderChrome - helpBarHeight
+	if m.healthErr != nil {
+		h-- // Health banner.
+	}
+	return max(h, 1)
 }
 
 // helpBindings returns context-aware help bindings.
 // In browse mode, the Enter label varies by selected bead type.
-// In confirm mode, only Enter/Esc are shown.
+// In confirm mode, only Enter/Esc are shown, and only Esc when there is nothing to run.
 // In summary mode with postPipeline, the continue label reflects lifecycle actions.
 func (m Model) helpBindings() help.KeyMap {
+	if m.phaseDetailShown() {
+		return PhaseDetailKeyMap()
+	}
 	switch m.mode {
 	case ModeConfirm:
-		return ConfirmKeyMap()
+		km := ConfirmKeyMap()
+		if m.confirm.nothingToRun() {
+			km.Enter.SetEnabled(false)
+		}
+		return km
 	case ModeBrowse:
+		if m.browse.filtering {
+			return FilterKeyMap(m.browse.filter)
+		}
 		var km browseKeys
 		if m.backgroundMode != 0 {
 			km = BrowseKeyMapWithBackground(m.dispatchedBeadID)
+		} else if n := len(m.browse.selected); n > 0 {
+			km = BrowseKeyMapForQueue(n)
+		} else if bead, ok := m.browse.SelectedBead(); ok && !bead.Closed && bead.held() != "" {
+			km = BrowseKeyMapForHeldBead(bead.held())
 		} else if bead, ok := m.browse.SelectedBead(); ok && !bead.Closed {
 			childCount := 0
 			if bead.Type == "feature" || bead.Type == "epic" {
@@ -887,9 +1365,23 @@ func (m Model) helpBindings() help.KeyMap {
 		if len(m.providerNames) > 1 {
 			km.Provider = BrowseKeyMapWithProvider(m.activeProvider).Provider
 		}
-		return km
+		km.CacheStats.SetEnabled(m.beadCache != nil)
+		km.New.SetEnabled(m.creator != nil && m.backgroundMode == 0)
+		return km.withListView(m.browse.filter, m.browse.sortMode)
 	case ModeSummary:
-		return PipelineSummaryKeyMap(m.postPipeline != nil)
+		km := PipelineSummaryKeyMap()
+		km.Retry.SetEnabled(m.canResume())
+		km.Copy = m.copyBinding()
+		km.Worklog, km.Worktree = m.editorBindings()
+		return km
+	case ModePipeline:
+		if m.queue.confirmAbort {
+			return QueueAbortKeyMap()
+		}
+		km := PipelineKeyMap()
+		km.Copy = m.copyBinding()
+		km.Worklog, km.Worktree = m.editorBindings()
+		return km
 	default:
 		return HelpBindings(m.mode)
 	}
@@ -920,13 +1412,39 @@ func (m Model) View() string {
 		Width(rightWidth - borderChrome).
 		Height(contentHeight)
 
-	leftPane := leftStyle.Render(m.viewLeft())
-	rightPane := rightStyle.Render(m.viewRight())
-	panes := lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
+	var panes string
+	switch {
+	case m.mode == ModeConfirm:
+		// The dialog replaces both panes and stays centered across resizes.
+		panes = m.confirm.View(m.width, contentHeight+borderChrome)
+	case m.mode == ModeCreate:
+		panes = m.create.View(m.width, contentHeight+borderChrome)
+	case m.phaseDetailShown():
+		panes = lipgloss.NewStyle().Width(m.width).Height(contentHeight + borderChrome).Render(m.viewPhaseDetail())
+	default:
+		leftPane := leftStyle.Render(m.viewLeft())
+		rightPane := rightStyle.Render(m.viewRight())
+		panes = lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
+	}
 	helpView := m.help.View(m.helpBindings())
+	if indicator := m.refreshIndicator(); indicator != "" && m.mode == ModeBrowse {
+		helpView = lipgloss.JoinHorizontal(lipgloss.Top, helpView, dimStyle.Render("  "+indicator))
+	}
+	if m.showCacheStats && m.beadCache != nil {
+		hits, misses := m.beadCache.Stats()
+		helpView = lipgloss.JoinHorizontal(lipgloss.Top, helpView, dimStyle.Render(fmt.Sprintf("  bd cache: %d hits · %d misses", hits, misses)))
+	}
 
-	if m.statusMsg != "" {
-		statusLine := pipeHeaderStyle.Render(m.statusMsg)
+	if m.healthErr != nil {
+		banner := errorStyle.MaxWidth(m.width).Render(fmt.Sprintf("%s %s — dispatches will fail until this is fixed", SymbolCross, m.healthErr))
+		panes = lipgloss.JoinVertical(lipgloss.Left, banner, panes)
+	}
+	status := m.statusMsg
+	if m.mode == ModePipeline && m.queue.confirmAbort {
+		status = m.queueAbortPrompt()
+	}
+	if status != "" {
+		statusLine := pipeHeaderStyle.Render(status)
 		return lipgloss.JoinVertical(lipgloss.Left, panes, statusLine, helpView)
 	}
 	return lipgloss.JoinVertical(lipgloss.Left, panes, helpView)
@@ -939,12 +1457,12 @@ func (m Model) viewLeft() string {
 	h := m.contentHeight()
 
 	switch m.mode {
-	case ModeConfirm:
-		return m.confirm.View(w, h)
 	case ModePipeline, ModeSummary:
 		return m.pipeline.View(w, h)
 	case ModeCampaign, ModeCampaignSummary:
 		return m.campaign.View(w, h)
+	case ModeQueueSummary:
+		return m.queue.View(w, h)
 	default:
 		return m.browse.View(w, h, m.browseSpinner.View())
 	}
@@ -953,26 +1471,41 @@ func (m Model) viewLeft() string {
 // viewRight renders the right pane content based on mode.
 func (m Model) viewRight() string {
 	switch m.mode {
-	case ModeConfirm:
-		return m.viewBrowseDetail() // Keep showing bead detail during confirmation.
 	case ModePipeline:
 		_, rightWidth := PaneWidths(m.width)
 		return m.pipeline.ViewReport(rightWidth-borderChrome, m.contentHeight())
 	case ModeSummary:
 		return m.viewSummaryRight()
-	case ModeCampaign:
-		_, rightWidth := PaneWidths(m.width)
-		return m.campaign.ViewReport(rightWidth-borderChrome, m.contentHeight())
-	case ModeCampaignSummary:
-		return m.viewCampaignSummaryRight()
+	case ModeCampaign, ModeCampaignSummary:
+		return m.campaignReportView().View()
+	case ModeQueueSummary:
+		return m.queue.ViewDetail()
 	default:
 		return m.viewBrowseDetail()
 	}
 }
 
+// campaignReportView returns the report viewport holding the campaign's
+// right pane, wrapped to the pane width, so long phase reports scroll. The
+// scroll offset is kept while the content under it changes.
+func (m Model) campaignReportView() viewport.Model {
+	vp := m.reportView
+	var content string
+	if m.mode == ModeCampaignSummary {
+		content = m.viewCampaignSummaryRight()
+	} else {
+		content = m.campaign.ViewReport(vp.Width, vp.Height)
+	}
+	vp.SetContent(lipgloss.NewStyle().Width(vp.Width).Render(content))
+	return vp
+}
+
 // viewBrowseDetail renders the right pane in browse mode:
 // loading spinner, error message, or resolved detail viewport.
 func (m Model) viewBrowseDetail() string {
+	if m.dispatchErr != nil && m.mode == ModeBrowse {
+		return fmt.Sprintf("%s Cannot dispatch\n\n%s\n\nPress any key to dismiss.", SymbolCross, m.dispatchErr)
+	}
 	if m.resolvingID != "" {
 		return fmt.Sprintf("%s Loading %s...", m.browseSpinner.View(), m.resolvingID)
 	}
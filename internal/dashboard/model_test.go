@@ -9,6 +9,9 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/smileynet/capsule/internal/provider"
 )
 
 // stubResolver implements BeadResolver for tests.
@@ -35,6 +38,8 @@ func sampleDetail() BeadDetail {
 		Title:        "First task",
 		Priority:     1,
 		Type:         "task",
+		Status:       "in_progress",
+		Labels:       []string{"backend", "capsule:provider=claude"},
 		Description:  "Implement the first feature.",
 		Acceptance:   "Tests pass.",
 		EpicID:       "cap-e01",
@@ -246,7 +251,10 @@ func TestFormatBeadDetail_ContainsAllFields(t *testing.T) {
 	for _, want := range []string{
 		"cap-001",
 		"First task",
-		"task",
+		"P1",
+		"[task]",
+		"in_progress",
+		"Labels: backend, capsule:provider=claude",
 		"Implement the first feature.",
 		"Tests pass.",
 		"cap-e01",
@@ -280,6 +288,9 @@ func TestFormatBeadDetail_OmitsEmptyHierarchy(t *testing.T) {
 	if strings.Contains(text, "Feature:") {
 		t.Errorf("should not contain Feature header for empty feature, got:\n%s", text)
 	}
+	if strings.Contains(text, "Labels:") {
+		t.Errorf("should not contain Labels line without labels, got:\n%s", text)
+	}
 }
 
 func newResolverModel(w, h int) (Model, *stubResolver) {
@@ -847,7 +858,7 @@ func TestDispatchPipeline_SendsEventsAndDone(t *testing.T) {
 	ctx := context.Background()
 
 	// When: dispatchPipeline runs to completion
-	dispatchPipeline(ctx, runner, PipelineInput{BeadID: "cap-001"}, ch)
+	dispatchPipeline(ctx, runner, nil, PipelineInput{BeadID: "cap-001"}, ch)
 
 	// Then: two PhaseUpdateMsgs are sent
 	for i, want := range []PhaseStatus{PhaseRunning, PhasePassed} {
@@ -878,13 +889,43 @@ func TestDispatchPipeline_SendsEventsAndDone(t *testing.T) {
 	}
 }
 
+func TestDispatchPipeline_SendsWorktreeFirst(t *testing.T) {
+	// Given: a runner and a worktree func for the bead
+	runner := &mockRunner{
+		events: []PhaseUpdateMsg{{Phase: "plan", Status: PhaseRunning}},
+		output: PipelineOutput{Success: true},
+	}
+	worktreeFn := func(id string) (string, string) { return "/repo/.capsule/worktrees/" + id, "capsule-" + id }
+	ch := make(chan tea.Msg, 16)
+
+	// When: dispatchPipeline runs
+	dispatchPipeline(context.Background(), runner, worktreeFn, PipelineInput{BeadID: "cap-001"}, ch)
+
+	// Then: PipelineStartedMsg with the path and branch precedes the phase events
+	started, ok := (<-ch).(PipelineStartedMsg)
+	if !ok {
+		t.Fatal("first message should be PipelineStartedMsg")
+	}
+	want := PipelineStartedMsg{BeadID: "cap-001", WorktreePath: "/repo/.capsule/worktrees/cap-001", Branch: "capsule-cap-001"}
+	if started != want {
+		t.Errorf("started = %+v, want %+v", started, want)
+	}
+	if _, ok := (<-ch).(PhaseUpdateMsg); !ok {
+		t.Error("second message should be PhaseUpdateMsg")
+	}
+}
+
 func TestDispatchPipeline_SendsError(t *testing.T) {
-	// Given: a runner that returns an error
-	runner := &mockRunner{err: fmt.Errorf("pipeline failed")}
+	// Given: a runner that returns an error after one phase passed
+	partial := PipelineOutput{PhaseReports: []PhaseReport{
+		{PhaseName: "plan", Status: PhasePassed},
+		{PhaseName: "code", Status: PhaseFailed},
+	}}
+	runner := &mockRunner{output: partial, err: fmt.Errorf("pipeline failed")}
 	ch := make(chan tea.Msg, 16)
 
 	// When: dispatchPipeline runs
-	dispatchPipeline(context.Background(), runner, PipelineInput{}, ch)
+	dispatchPipeline(context.Background(), runner, nil, PipelineInput{}, ch)
 
 	// Then: a PipelineErrorMsg is sent
 	msg := <-ch
@@ -895,6 +936,10 @@ func TestDispatchPipeline_SendsError(t *testing.T) {
 	if errMsg.Err == nil || errMsg.Err.Error() != "pipeline failed" {
 		t.Errorf("unexpected error: %v", errMsg.Err)
 	}
+	// And: it carries the partial output
+	if len(errMsg.Output.PhaseReports) != 2 {
+		t.Errorf("Output.PhaseReports = %d, want 2", len(errMsg.Output.PhaseReports))
+	}
 
 	// And: the channel is closed
 	_, ok = <-ch
@@ -1061,6 +1106,32 @@ func TestModel_PipelineErrorStoresErr(t *testing.T) {
 	}
 }
 
+func TestModel_PipelineErrorKeepsPartialOutput(t *testing.T) {
+	// Given: a model in pipeline mode
+	m := newSizedModel(90, 40)
+	m.mode = ModePipeline
+
+	// When: PipelineErrorMsg arrives with the phases that ran
+	out := PipelineOutput{PhaseReports: []PhaseReport{
+		{PhaseName: "plan", Status: PhasePassed, Summary: "planned"},
+		{PhaseName: "code", Status: PhaseFailed, Feedback: "tests fail"},
+	}}
+	updated, _ := m.Update(PipelineErrorMsg{Err: fmt.Errorf("boom"), Output: out})
+	m = updated.(Model)
+
+	// Then: the partial output backs the phase reports
+	if m.pipelineOutput == nil || len(m.pipelineOutput.PhaseReports) != 2 {
+		t.Fatalf("pipelineOutput = %+v, want 2 reports", m.pipelineOutput)
+	}
+	if r := m.phaseReport("code"); r == nil || r.Feedback != "tests fail" {
+		t.Errorf("phaseReport(code) = %+v, want the failed report", r)
+	}
+	// And: the run still counts as failed
+	if m.pipelineSucceeded() {
+		t.Error("pipelineSucceeded() = true, want false")
+	}
+}
+
 // --- Abort tests ---
 
 func TestModel_PipelineQuitCancels(t *testing.T) {
@@ -1325,9 +1396,9 @@ func TestModel_AbortDoesNotRunPostPipeline(t *testing.T) {
 	lister := &stubLister{beads: sampleBeads()}
 	m := NewModel(
 		WithBeadLister(lister),
-		WithPostPipelineFunc(func(beadID string) error {
+		WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
 			postPipelineCalled = true
-			return nil
+			return PostPipelineResult{}, nil
 		}),
 	)
 	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
@@ -1795,6 +1866,67 @@ func TestModel_CampaignChannelClosedTransitionsToSummary(t *testing.T) {
 	}
 }
 
+func TestModel_CampaignUsageShownInSummary(t *testing.T) {
+	// Given: a campaign that reported its usage
+	m := newCampaignModel(90, 40)
+	m.cancelPipeline = func() {}
+	m.campaignDone = &CampaignDoneMsg{ParentID: "cap-feat", TotalTasks: 3, Passed: 3,
+		Usage: provider.Usage{InputTokens: 900, OutputTokens: 90, CostUSD: 1.5}}
+
+	// When: the campaign ends
+	updated, _ := m.Update(channelClosedMsg{})
+	m = updated.(Model)
+
+	// Then: the summary reports the campaign's total spend
+	if plain := stripANSI(m.View()); !strings.Contains(plain, "Usage: 900 in / 90 out tokens, $1.5000") {
+		t.Errorf("campaign summary missing usage, got:\n%s", plain)
+	}
+}
+
+func TestModel_CampaignParentClosedShownInSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		closed bool
+		done   CampaignDoneMsg
+		want   string
+	}{
+		{
+			name:   "closed after a successful campaign",
+			closed: true,
+			done:   CampaignDoneMsg{ParentID: "cap-feat", TotalTasks: 3, Passed: 3},
+			want:   "Closed cap-feat",
+		},
+		{
+			name: "left open with the reason",
+			done: CampaignDoneMsg{ParentID: "cap-feat", TotalTasks: 3, Passed: 2, Failed: 1, ParentOpen: "1 task(s) failed"},
+			want: "cap-feat left open: 1 task(s) failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a running campaign
+			m := newCampaignModel(90, 40)
+			m.cancelPipeline = func() {}
+			m.eventCh = make(chan tea.Msg, 1)
+
+			// When: the parent is closed (or not) and the campaign ends
+			if tt.closed {
+				updated, _ := m.Update(CampaignParentClosedMsg{ParentID: "cap-feat"})
+				m = updated.(Model)
+			}
+			updated, _ := m.Update(tt.done)
+			m = updated.(Model)
+			updated, _ = m.Update(channelClosedMsg{})
+			m = updated.(Model)
+
+			// Then: the summary says what happened to the parent
+			if plain := stripANSI(m.View()); !strings.Contains(plain, tt.want) {
+				t.Errorf("campaign summary missing %q, got:\n%s", tt.want, plain)
+			}
+		})
+	}
+}
+
 func TestModel_CampaignChannelClosedWhileAbortingGoesToBrowse(t *testing.T) {
 	// Given: a model in campaign mode that is aborting
 	m := newCampaignModel(90, 40)
@@ -1910,9 +2042,9 @@ func TestModel_CampaignSummarySkipsPostPipeline(t *testing.T) {
 	lister := &stubLister{beads: sampleBeads()}
 	m := NewModel(
 		WithBeadLister(lister),
-		WithPostPipelineFunc(func(beadID string) error {
+		WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
 			postPipelineCalled = true
-			return nil
+			return PostPipelineResult{}, nil
 		}),
 	)
 	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
@@ -1936,6 +2068,270 @@ func TestModel_CampaignSummarySkipsPostPipeline(t *testing.T) {
 	}
 }
 
+// stubTaskStore implements CampaignTaskStore for tests.
+type stubTaskStore struct {
+	reports  map[string][]PhaseReport
+	recorded []string
+	err      error
+}
+
+func (s *stubTaskStore) TaskReports(string) (map[string][]PhaseReport, error) {
+	return s.reports, nil
+}
+
+func (s *stubTaskStore) RecordTaskPassed(_, beadID string, _ []PhaseReport) error {
+	s.recorded = append(s.recorded, beadID)
+	return s.err
+}
+
+// newFailedCampaignSummary returns a model in campaign summary mode where
+// cap-001 passed and cap-002 failed, with cap-002 selected.
+func newFailedCampaignSummary(opts ...ModelOption) Model {
+	m := NewModel(append([]ModelOption{WithPhaseNames([]string{"plan"})}, opts...)...)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m.mode = ModeCampaignSummary
+	m.campaign = newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks()[:2])
+	m.campaign, _ = m.campaign.Update(CampaignTaskDoneMsg{BeadID: "cap-001", Index: 0, Success: true})
+	m.campaign, _ = m.campaign.Update(CampaignTaskDoneMsg{BeadID: "cap-002", Index: 1, Error: "pipeline failed"})
+	m.campaign.selectedIdx = 1
+	m.campaignDone = &CampaignDoneMsg{ParentID: "cap-feat", TotalTasks: 2, Passed: 1, Failed: 1}
+	return m
+}
+
+func TestModel_CampaignSummaryLoadsStoredReports(t *testing.T) {
+	// Given: a campaign whose stored state has a report for the failed task
+	store := &stubTaskStore{reports: map[string][]PhaseReport{
+		"cap-002": {{
+			PhaseName:    "execute-review",
+			Status:       PhaseFailed,
+			Feedback:     "missing error handling",
+			FilesChanged: []string{"main.go"},
+		}},
+	}}
+	m := newFailedCampaignSummary(WithCampaignTaskStore(store), WithPipelineRunner(&mockRunner{}))
+	m.mode = ModeCampaign
+	m.cancelPipeline = func() {}
+
+	// When: the campaign channel closes and the stored reports load
+	updated, cmd := m.Update(channelClosedMsg{})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected a command to load stored reports")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	// Then: the right pane shows the failed task's last phase report
+	view := stripANSI(m.viewCampaignSummaryRight())
+	for _, want := range []string{"pipeline failed", "execute-review", "missing error handling", "main.go", "Press e to retry"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("summary should contain %q, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_CampaignSummaryNavigatesAllTasks(t *testing.T) {
+	// Given: a campaign summary with a passed, a failed, and a skipped task
+	m := NewModel(WithPhaseNames([]string{"plan"}))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m.mode = ModeCampaignSummary
+	m.campaign = newCampaignState("cap-feat", "Feature Title", sampleCampaignTasks())
+	m.campaign, _ = m.campaign.Update(CampaignTaskDoneMsg{BeadID: "cap-001", Index: 0, Success: true, PhaseReports: []PhaseReport{
+		{PhaseName: "execute", Status: PhasePassed, Summary: "added the parser"},
+		{PhaseName: "review", Status: PhasePassed, Summary: "looks good"},
+	}})
+	m.campaign, _ = m.campaign.Update(CampaignTaskDoneMsg{BeadID: "cap-002", Index: 1, Error: "pipeline failed", PhaseReports: []PhaseReport{
+		{PhaseName: "execute", Status: PhaseFailed, Feedback: "build broken"},
+	}})
+	m.campaign, _ = m.campaign.Update(CampaignTaskSkippedMsg{BeadID: "cap-003", Index: 2, Reason: "dependency cap-002 failed"})
+	m.campaignDone = &CampaignDoneMsg{ParentID: "cap-feat", TotalTasks: 3, Passed: 1, Failed: 1, Skipped: 1}
+
+	tests := []struct {
+		selected int
+		want     []string
+	}{
+		{selected: 0, want: []string{"cap-001", "execute", "added the parser", "review", "looks good"}},
+		{selected: 1, want: []string{"cap-002", "pipeline failed", "build broken"}},
+		{selected: 2, want: []string{"cap-003", "Skipped: dependency cap-002 failed"}},
+	}
+	for _, tt := range tests {
+		// When: the cursor moves down to each task in turn
+		for m.campaign.selectedIdx != tt.selected {
+			updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+			m = updated.(Model)
+		}
+
+		// Then: the right pane shows that task's frozen results
+		view := stripANSI(m.viewCampaignSummaryRight())
+		for _, want := range tt.want {
+			if !strings.Contains(view, want) {
+				t.Errorf("task %d: summary should contain %q, got:\n%s", tt.selected, want, view)
+			}
+		}
+	}
+}
+
+func TestModel_CampaignReportScrolls(t *testing.T) {
+	// Given: a campaign whose first task finished with more phases than fit
+	m := newCampaignModel(120, 20)
+	reports := make([]PhaseReport, 30)
+	for i := range reports {
+		reports[i] = PhaseReport{PhaseName: fmt.Sprintf("phase-%02d", i), Status: PhasePassed}
+	}
+	m.campaign, _ = m.campaign.Update(CampaignTaskStartMsg{BeadID: "cap-001", Index: 0, Total: 3})
+	m.campaign, _ = m.campaign.Update(CampaignTaskDoneMsg{BeadID: "cap-001", Index: 0, Success: true, PhaseReports: reports})
+	if strings.Contains(stripANSI(m.viewRight()), "phase-29") {
+		t.Fatal("last phase visible before scrolling; make the report longer")
+	}
+
+	// When: the right pane is focused and scrolled to the bottom
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	for range 3 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+		m = updated.(Model)
+	}
+
+	// Then: the end of the report is shown
+	if view := stripANSI(m.viewRight()); !strings.Contains(view, "phase-29") || strings.Contains(view, "phase-00") {
+		t.Errorf("scrolled report should end at phase-29, got:\n%s", view)
+	}
+
+	// When: focus returns to the tasks and the cursor moves
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+
+	// Then: the report scrolls back to the top
+	if m.reportView.YOffset != 0 {
+		t.Errorf("YOffset = %d after selecting another task, want 0", m.reportView.YOffset)
+	}
+}
+
+func TestModel_CampaignSummaryRetryFailedTask(t *testing.T) {
+	tests := []struct {
+		name         string
+		output       PipelineOutput
+		err          error
+		wantStatus   CampaignTaskStatus
+		wantPassed   int
+		wantRecorded bool
+	}{
+		{
+			name:         "retry passes",
+			output:       PipelineOutput{Success: true},
+			wantStatus:   CampaignTaskPassed,
+			wantPassed:   2,
+			wantRecorded: true,
+		},
+		{
+			name:       "retry fails again",
+			err:        fmt.Errorf("still broken"),
+			wantStatus: CampaignTaskFailed,
+			wantPassed: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a campaign summary with a failed task selected
+			var runInput PipelineInput
+			runner := &mockRunner{runFn: func(_ context.Context, in PipelineInput, _ func(PhaseUpdateMsg)) (PipelineOutput, error) {
+				runInput = in
+				return tt.output, tt.err
+			}}
+			var postCalls []string
+			store := &stubTaskStore{}
+			m := newFailedCampaignSummary(
+				WithPipelineRunner(runner),
+				WithCampaignTaskStore(store),
+				WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
+					postCalls = append(postCalls, in.BeadID)
+					return PostPipelineResult{}, nil
+				}),
+			)
+
+			// When: e is pressed
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+			m = updated.(Model)
+
+			// Then: only the selected task is re-dispatched
+			if m.retryingID != "cap-002" {
+				t.Fatalf("retryingID = %q, want %q", m.retryingID, "cap-002")
+			}
+			if m.campaign.taskStatuses[1] != CampaignTaskRunning {
+				t.Errorf("task status = %q, want running", m.campaign.taskStatuses[1])
+			}
+
+			// When: the retry finishes
+			var followUp []tea.Msg
+			for i := 0; i < 10 && m.eventCh != nil; i++ {
+				msg := listenForEvents(m.eventCh)()
+				var cmd tea.Cmd
+				updated, cmd = m.Update(msg)
+				m = updated.(Model)
+				if _, ok := msg.(taskRetryDoneMsg); ok && tt.wantRecorded {
+					followUp = execBatch(t, cmd)
+				}
+			}
+			for _, msg := range followUp {
+				if rec, ok := msg.(taskRetryRecordedMsg); ok {
+					updated, _ = m.Update(rec)
+					m = updated.(Model)
+				}
+			}
+
+			// Then: the task and counts reflect the result and the mode is unchanged
+			if runInput.BeadID != "cap-002" {
+				t.Errorf("pipeline BeadID = %q, want %q", runInput.BeadID, "cap-002")
+			}
+			if m.mode != ModeCampaignSummary || m.retryingID != "" {
+				t.Errorf("mode/retryingID = %d/%q, want summary with no retry", m.mode, m.retryingID)
+			}
+			if m.campaign.taskStatuses[1] != tt.wantStatus {
+				t.Errorf("task status = %q, want %q", m.campaign.taskStatuses[1], tt.wantStatus)
+			}
+			if m.campaignDone.Passed != tt.wantPassed {
+				t.Errorf("campaignDone.Passed = %d, want %d", m.campaignDone.Passed, tt.wantPassed)
+			}
+			// And: a passing retry merges and updates the stored campaign state
+			if got := len(store.recorded) == 1 && len(postCalls) == 1; got != tt.wantRecorded {
+				t.Errorf("recorded = %v, post-pipeline = %v, want recorded %v", store.recorded, postCalls, tt.wantRecorded)
+			}
+		})
+	}
+}
+
+func TestModel_CampaignSummaryRetryIgnoresPassedTask(t *testing.T) {
+	// Given: a campaign summary with a passed task selected
+	m := newFailedCampaignSummary(WithPipelineRunner(&mockRunner{}))
+	m.campaign.selectedIdx = 0
+
+	// When: e is pressed
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	m = updated.(Model)
+
+	// Then: nothing is dispatched
+	if cmd != nil || m.retryingID != "" {
+		t.Errorf("retry of a passed task should be a no-op, retryingID = %q", m.retryingID)
+	}
+}
+
+func TestModel_CampaignSummaryQReturnsToBrowse(t *testing.T) {
+	// Given: a campaign summary with no retry running
+	m := newFailedCampaignSummary()
+
+	// When: q is pressed
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = updated.(Model)
+
+	// Then: the model returns to browse instead of quitting
+	if m.mode != ModeBrowse {
+		t.Errorf("mode = %d, want ModeBrowse (%d)", m.mode, ModeBrowse)
+	}
+}
+
 // --- Campaign view tests ---
 
 func TestModel_CampaignViewLeftShowsCampaignState(t *testing.T) {
@@ -2175,6 +2571,45 @@ func TestModel_CampaignErrorShownInSummary(t *testing.T) {
 	}
 }
 
+func TestModel_CampaignCircuitBreakerShownInSummary(t *testing.T) {
+	// Given: a campaign that the circuit breaker stopped
+	m := newCampaignModel(90, 40)
+	m.eventCh = make(chan tea.Msg, 1)
+	updated, _ := m.Update(CampaignCircuitBrokenMsg{
+		Reason:         "2 consecutive provider/setup failures (limit 2)",
+		Banner:         "stopped after 2 consecutive failures at cap-a2",
+		FailedIDs:      []string{"cap-a1", "cap-a2"},
+		SetupFailures:  2,
+		SignalFailures: 1,
+	})
+	m = updated.(Model)
+
+	// Then: the campaign view shows the banner while in-flight tasks finish
+	if plain := stripANSI(m.campaign.View(90, 40)); !strings.Contains(plain, "Circuit breaker stopped after 2 consecutive failures at cap-a2") {
+		t.Errorf("campaign view missing breaker banner, got:\n%s", plain)
+	}
+
+	updated, _ = m.Update(CampaignErrorMsg{Err: fmt.Errorf("campaign: circuit breaker tripped")})
+	m = updated.(Model)
+
+	// When: the campaign ends and the summary is rendered
+	updated, _ = m.Update(channelClosedMsg{})
+	m = updated.(Model)
+	plain := stripANSI(m.View())
+
+	// Then: the summary explains why the campaign stopped
+	for _, want := range []string{
+		"Campaign Stopped",
+		"2 consecutive provider/setup failures (limit 2)",
+		"Failures: 2 provider/setup, 1 NEEDS_WORK/ERROR",
+		"Failed tasks: cap-a1, cap-a2",
+	} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("campaign summary missing %q, got:\n%s", want, plain)
+		}
+	}
+}
+
 func TestModel_CampaignChannelClosedWithoutDoneMsgGoesToCampaignSummary(t *testing.T) {
 	// Given: a model in campaign mode where the runner errored without sending CampaignDoneMsg
 	m := newCampaignModel(90, 40)
@@ -2204,6 +2639,7 @@ func TestModel_CampaignChannelClosedWithoutDoneMsgGoesToCampaignSummary(t *testi
 type stubArchiveReader struct {
 	summaries map[string]string
 	worklogs  map[string]string
+	phases    map[string][]ArchivedPhase
 }
 
 func (s *stubArchiveReader) ReadSummary(beadID string) (string, error) {
@@ -2220,6 +2656,13 @@ func (s *stubArchiveReader) ReadWorklog(beadID string) (string, error) {
 	return "", fmt.Errorf("not found: %s", beadID)
 }
 
+func (s *stubArchiveReader) ReadPhaseEntries(beadID string) ([]ArchivedPhase, error) {
+	if v, ok := s.phases[beadID]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("no phase entries: %s", beadID)
+}
+
 func TestWithArchiveReader(t *testing.T) {
 	// Given: a stub archive reader
 	ar := &stubArchiveReader{}
@@ -2240,7 +2683,7 @@ func TestFormatClosedBeadDetail_WithSummaryAndWorklog(t *testing.T) {
 	worklog := "# Worklog\n\nPhase 1: passed\nPhase 2: passed"
 
 	// When: formatClosedBeadDetail is called
-	text := formatClosedBeadDetail(detail, summary, worklog)
+	text := formatClosedBeadDetail(detail, nil, summary, worklog, 80, true)
 
 	// Then: the standard detail is present
 	if !strings.Contains(text, "First task") {
@@ -2263,13 +2706,57 @@ func TestFormatClosedBeadDetail_WithSummaryAndWorklog(t *testing.T) {
 	}
 }
 
+func TestFormatClosedBeadDetail_PhaseTable(t *testing.T) {
+	// Given a closed bead whose worklog recorded a retried phase and a skip
+	phases := []ArchivedPhase{
+		{Name: "test-writer", Status: "PASS", Attempt: 1, Duration: 12 * time.Second},
+		{Name: "test-review", Status: "NEEDS_WORK", Attempt: 1, Duration: 4 * time.Second},
+		{Name: "test-writer", Status: "PASS", Attempt: 2, Duration: 8 * time.Second},
+		{Name: "test-review", Status: "PASS", Attempt: 2, Duration: 3 * time.Second},
+		{Name: "lint", Status: "SKIP"},
+	}
+
+	// When the detail is rendered
+	text := formatClosedBeadDetail(sampleDetail(), phases, "All phases passed.", "# Worklog", 80, true)
+
+	// Then one row per phase shows its last status, total time, and attempts
+	want := []string{
+		"Phases:",
+		"✓ test-writer PASS 20.0s (2 attempts)",
+		"✓ test-review PASS 7.0s (2 attempts)",
+		"– lint        SKIP",
+	}
+	for _, line := range want {
+		if !containsPlainText(text, line) {
+			t.Errorf("detail missing %q:\n%s", line, text)
+		}
+	}
+
+	// And the table comes before the raw summary and worklog
+	table, summary := strings.Index(text, "Phases:"), strings.Index(text, "All phases passed.")
+	if table < strings.Index(text, archiveSeparator) || table > summary {
+		t.Errorf("phase table should sit between the separator and the summary:\n%s", text)
+	}
+}
+
+func TestFormatClosedBeadDetail_UnparsedWorklogHasNoTable(t *testing.T) {
+	// Given a closed bead whose worklog could not be parsed into phases
+	// When the detail is rendered
+	text := formatClosedBeadDetail(sampleDetail(), nil, "", "# Old worklog\n\nPhase 1: passed", 80, true)
+
+	// Then the worklog text is shown without a phase table
+	if strings.Contains(text, "Phases:") || !strings.Contains(text, "Phase 1: passed") {
+		t.Errorf("want the raw worklog only, got:\n%s", text)
+	}
+}
+
 func TestFormatClosedBeadDetail_SummaryOnly(t *testing.T) {
 	// Given: a bead detail with summary but no worklog
 	detail := BeadDetail{ID: "cap-001", Title: "Test", Priority: 2, Type: "task"}
 	summary := "All passed."
 
 	// When: formatClosedBeadDetail is called with empty worklog
-	text := formatClosedBeadDetail(detail, summary, "")
+	text := formatClosedBeadDetail(detail, nil, summary, "", 80, true)
 
 	// Then: summary is present but no worklog header
 	if !strings.Contains(text, "All passed.") {
@@ -2285,7 +2772,7 @@ func TestFormatClosedBeadDetail_NoArchive(t *testing.T) {
 	detail := sampleDetail()
 
 	// When: formatClosedBeadDetail is called with empty strings
-	text := formatClosedBeadDetail(detail, "", "")
+	text := formatClosedBeadDetail(detail, nil, "", "", 80, true)
 
 	// Then: it should be equivalent to formatBeadDetail (no separator, no archive sections)
 	if strings.Contains(text, archiveSeparator) {
@@ -2302,6 +2789,9 @@ func newClosedResolverModel(t *testing.T, w, h int) (Model, *stubResolver) {
 		worklogs: map[string]string{
 			"cap-c01": "# Worklog\n\nAll phases passed.",
 		},
+		phases: map[string][]ArchivedPhase{
+			"cap-c01": {{Name: "execute", Status: "PASS", Attempt: 1, Duration: 2 * time.Second}},
+		},
 	}
 	// Unified view: mix of open and closed beads.
 	allBeads := []BeadSummary{
@@ -2355,6 +2845,10 @@ func TestModel_ClosedBeadDetailShowsArchive(t *testing.T) {
 	if !strings.Contains(plain, "All phases passed.") {
 		t.Errorf("should contain archive worklog, got:\n%s", plain)
 	}
+	// And: the phases parsed from it are listed
+	if !strings.Contains(plain, "✓ execute PASS 2.0s") {
+		t.Errorf("should contain the archived phase table, got:\n%s", plain)
+	}
 }
 
 func TestModel_ClosedBeadCacheHitShowsArchive(t *testing.T) {
@@ -2693,6 +3187,190 @@ func TestModel_ConfirmEnter_DispatchesPipeline(t *testing.T) {
 	}
 }
 
+func TestModel_ConfirmYesNoKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      rune
+		wantMode Mode
+	}{
+		{name: "y dispatches", key: 'y', wantMode: ModePipeline},
+		{name: "n cancels", key: 'n', wantMode: ModeBrowse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a model in ModeConfirm for a task with a runner configured
+			m := NewModel(WithPipelineRunner(&mockRunner{}), WithPhaseNames([]string{"plan"}))
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
+			m = updated.(Model)
+			m.mode = ModeConfirm
+			m.confirm = confirmState{beadID: "cap-001", beadType: "task", beadTitle: "First task"}
+
+			// When: the key is pressed
+			updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.key}})
+			m = updated.(Model)
+
+			// Then: the dialog dispatches or cancels
+			if m.mode != tt.wantMode {
+				t.Errorf("mode = %d, want %d", m.mode, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestModel_ConfirmEnter_NothingToRunDoesNotDispatch(t *testing.T) {
+	// Given: a confirmation for a feature with no open children
+	m := NewModel(WithCampaignRunner(&mockCampaignRunner{}))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
+	m = updated.(Model)
+	updated, _ = m.Update(ConfirmRequestMsg{BeadID: "cap-feat", BeadType: "feature", BeadTitle: "Feature"})
+	m = updated.(Model)
+	if !strings.Contains(stripANSI(m.View()), "Nothing to run for cap-feat") {
+		t.Fatalf("dialog should say nothing to run, got:\n%s", stripANSI(m.View()))
+	}
+
+	// When: enter is pressed
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	// Then: the dialog closes without starting a campaign
+	if m.mode != ModeBrowse {
+		t.Errorf("mode = %d, want ModeBrowse (%d)", m.mode, ModeBrowse)
+	}
+	if cmd != nil {
+		t.Error("nothing to run should not produce a dispatch command")
+	}
+}
+
+func TestModel_ConfirmView_FollowsResize(t *testing.T) {
+	// Given: a model showing the confirmation dialog
+	m := newSizedModel(90, 40)
+	updated, _ := m.Update(ConfirmRequestMsg{BeadID: "cap-001", BeadType: "task", BeadTitle: "First task"})
+	m = updated.(Model)
+
+	// When: the terminal is resized
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 140, Height: 30})
+	m = updated.(Model)
+	view := m.View()
+
+	// Then: the view fits the new size and still shows the dialog
+	if h := lipgloss.Height(view); h != 30 {
+		t.Errorf("view height = %d, want 30", h)
+	}
+	if !strings.Contains(stripANSI(view), "Run pipeline for cap-001?") {
+		t.Errorf("dialog missing after resize:\n%s", stripANSI(view))
+	}
+}
+
+func TestModel_ConfirmEnter_DispatchCheckBlocks(t *testing.T) {
+	// Given: a model in ModeConfirm whose dispatch check fails
+	runner := &mockRunner{output: PipelineOutput{Success: true}}
+	m := NewModel(
+		WithPipelineRunner(runner),
+		WithPhaseNames([]string{"plan"}),
+		WithDispatchCheck(func() error { return errors.New("repository has uncommitted changes:\n  main.go") }),
+	)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
+	m = updated.(Model)
+	m.mode = ModeConfirm
+	m.confirm = confirmState{beadID: "cap-001", beadType: "task", beadTitle: "First task"}
+
+	// When: enter is pressed and the check result is delivered
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("enter should run the dispatch check")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	// Then: the dashboard stays in browse mode and shows the error inline
+	if m.mode != ModeBrowse {
+		t.Errorf("mode = %d, want ModeBrowse (%d)", m.mode, ModeBrowse)
+	}
+	if m.cancelPipeline != nil {
+		t.Error("pipeline should not be dispatched")
+	}
+	view := m.View()
+	if !containsPlainText(view, "Cannot dispatch") || !containsPlainText(view, "main.go") {
+		t.Errorf("view missing dispatch error, got:\n%s", stripANSI(view))
+	}
+
+	// When: any key is pressed
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+
+	// Then: the error is dismissed
+	if m.dispatchErr != nil {
+		t.Error("dispatchErr should be cleared by a key press")
+	}
+}
+
+func TestModel_ConfirmEnter_DispatchCheckPasses(t *testing.T) {
+	// Given: a model in ModeConfirm whose dispatch check passes
+	runner := &mockRunner{output: PipelineOutput{Success: true}}
+	m := NewModel(
+		WithPipelineRunner(runner),
+		WithPhaseNames([]string{"plan"}),
+		WithDispatchCheck(func() error { return nil }),
+	)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
+	m = updated.(Model)
+	m.mode = ModeConfirm
+	m.confirm = confirmState{beadID: "cap-001", beadType: "task", beadTitle: "First task"}
+
+	// When: enter is pressed and the check result is delivered
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	// Then: the pipeline is dispatched
+	if m.mode != ModePipeline {
+		t.Errorf("mode = %d, want ModePipeline (%d)", m.mode, ModePipeline)
+	}
+}
+
+func TestModel_ConfirmRequest_ShowsOverlaps(t *testing.T) {
+	// Given: a model with an overlap check reporting another capsule's files
+	var checked string
+	m := NewModel(
+		WithPhaseNames([]string{"plan"}),
+		WithOverlapCheck(func(beadID string) (map[string][]string, error) {
+			checked = beadID
+			return map[string][]string{"cap-002": {"src/a.go", "src/b.go"}}, nil
+		}),
+	)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
+	m = updated.(Model)
+
+	// When: a dispatch confirmation is requested and the check result arrives
+	updated, cmd := m.Update(ConfirmRequestMsg{BeadID: "cap-001", BeadType: "task", BeadTitle: "First task"})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("confirm request should run the overlap check")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	// Then: the confirmation screen warns about the overlapping files
+	if checked != "cap-001" {
+		t.Errorf("overlap check ran for %q, want cap-001", checked)
+	}
+	view := m.View()
+	if !containsPlainText(view, "May conflict with") || !containsPlainText(view, "cap-002: src/a.go,") {
+		t.Errorf("view missing overlap warning, got:\n%s", stripANSI(view))
+	}
+
+	// When: a stale result for another bead arrives
+	updated, _ = m.Update(overlapCheckMsg{BeadID: "cap-009", Overlaps: map[string][]string{"cap-003": {"x.go"}}})
+	m = updated.(Model)
+
+	// Then: it is ignored
+	if _, ok := m.confirm.overlaps["cap-003"]; ok {
+		t.Error("overlaps for another bead should be ignored")
+	}
+}
+
 func TestModel_ConfirmEsc_ReturnsToBrowse(t *testing.T) {
 	// Given: a model in ModeConfirm
 	m := newSizedModel(90, 40)
@@ -2756,7 +3434,7 @@ func TestModel_ConfirmSwallowsOtherKeys(t *testing.T) {
 	}
 }
 
-func TestModel_ConfirmView_ShowsConfirmInLeftPane(t *testing.T) {
+func TestModel_ConfirmView_ShowsConfirmDialog(t *testing.T) {
 	// Given: a model in ModeConfirm
 	m := newSizedModel(90, 40)
 	m.mode = ModeConfirm
@@ -2770,7 +3448,7 @@ func TestModel_ConfirmView_ShowsConfirmInLeftPane(t *testing.T) {
 	if !strings.Contains(plain, "Run pipeline for cap-001?") {
 		t.Errorf("view should show confirm prompt, got:\n%s", plain)
 	}
-	if !strings.Contains(plain, "[Enter] Confirm") {
+	if !strings.Contains(plain, "[Enter/y] Confirm") {
 		t.Errorf("view should show confirm hint, got:\n%s", plain)
 	}
 }
@@ -2896,12 +3574,12 @@ func TestModel_BackgroundPhaseUpdateRoutesToCampaign(t *testing.T) {
 	m = updated.(Model)
 
 	// Then: campaign pipeline is running (message was routed to campaign, not standalone pipeline)
-	if !m.campaign.pipeline.running {
-		t.Error("campaign.pipeline.running should be true after PhaseUpdateMsg routed to campaign")
+	if !m.campaign.pipeline.anyRunning() {
+		t.Error("campaign pipeline should be running after PhaseUpdateMsg routed to campaign")
 	}
 	// And: the standalone pipeline state is unaffected
-	if m.pipeline.running {
-		t.Error("standalone pipeline.running should be false (message should NOT go to standalone pipeline)")
+	if m.pipeline.anyRunning() {
+		t.Error("standalone pipeline should not be running (message should NOT go to standalone pipeline)")
 	}
 	// And: mode stays in browse
 	if m.mode != ModeBrowse {
@@ -3054,9 +3732,9 @@ func TestModel_BackgroundPipelineFiresPostPipeline(t *testing.T) {
 	lister := &stubLister{beads: sampleBeads()}
 	m := NewModel(
 		WithBeadLister(lister),
-		WithPostPipelineFunc(func(beadID string) error {
-			postPipelineBeadID = beadID
-			return nil
+		WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
+			postPipelineBeadID = in.BeadID
+			return PostPipelineResult{}, nil
 		}),
 	)
 	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
@@ -3092,9 +3770,9 @@ func TestModel_BackgroundPipelineSkipsPostPipelineOnError(t *testing.T) {
 	lister := &stubLister{beads: sampleBeads()}
 	m := NewModel(
 		WithBeadLister(lister),
-		WithPostPipelineFunc(func(beadID string) error {
+		WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
 			postPipelineCalled = true
-			return nil
+			return PostPipelineResult{}, nil
 		}),
 	)
 	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
@@ -3126,9 +3804,9 @@ func TestModel_BackgroundCampaignSkipsPostPipeline(t *testing.T) {
 	lister := &stubLister{beads: sampleBeads()}
 	m := NewModel(
 		WithBeadLister(lister),
-		WithPostPipelineFunc(func(beadID string) error {
+		WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
 			postPipelineCalled = true
-			return nil
+			return PostPipelineResult{}, nil
 		}),
 	)
 	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
@@ -3199,7 +3877,7 @@ func TestDispatchPipeline_ErrorDeliveredOnCancel(t *testing.T) {
 	}
 
 	// When: dispatchPipeline runs
-	dispatchPipeline(ctx, runner, PipelineInput{}, ch)
+	dispatchPipeline(ctx, runner, nil, PipelineInput{}, ch)
 
 	// Then: PipelineErrorMsg is delivered despite cancelled context
 	var gotError bool
@@ -3461,3 +4139,198 @@ func TestModel_SubCampaignDoneMsgRoutesInBackground(t *testing.T) {
 		t.Errorf("mode = %d, want ModeBrowse", m.mode)
 	}
 }
+
+func TestModel_NotifyOnCompletion(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  tea.Msg
+		want CompletionEvent
+	}{
+		{
+			name: "pipeline done with failed phase",
+			msg: PipelineDoneMsg{Output: PipelineOutput{
+				Success: false,
+				PhaseReports: []PhaseReport{
+					{PhaseName: "plan", Status: PhasePassed},
+					{PhaseName: "code-review", Status: PhaseFailed},
+				},
+			}},
+			want: CompletionEvent{BeadID: "cap-001", FailedPhase: "code-review"},
+		},
+		{
+			name: "pipeline done successfully",
+			msg:  PipelineDoneMsg{Output: PipelineOutput{Success: true}},
+			want: CompletionEvent{BeadID: "cap-001", Success: true},
+		},
+		{
+			name: "campaign done with no failures",
+			msg:  CampaignDoneMsg{ParentID: "cap-feat", TotalTasks: 2, Passed: 2},
+			want: CompletionEvent{BeadID: "cap-feat", Campaign: true, Success: true},
+		},
+		{
+			name: "campaign done with failures",
+			msg:  CampaignDoneMsg{ParentID: "cap-feat", TotalTasks: 2, Passed: 1, Failed: 1},
+			want: CompletionEvent{BeadID: "cap-feat", Campaign: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a model with a NotifyFunc and a dispatched bead
+			var got []CompletionEvent
+			m := NewModel(WithNotifyFunc(func(ev CompletionEvent) error {
+				got = append(got, ev)
+				return nil
+			}))
+			m.dispatchedBeadID = "cap-001"
+			m.dispatchedAt = time.Now().Add(-time.Minute)
+
+			// When: the completion message is received and commands run
+			_, cmd := m.Update(tt.msg)
+			execBatch(t, cmd)
+
+			// Then: NotifyFunc receives the completion event
+			if len(got) != 1 {
+				t.Fatalf("NotifyFunc calls = %d, want 1", len(got))
+			}
+			ev := got[0]
+			if ev.Duration < time.Minute {
+				t.Errorf("Duration = %v, want >= 1m", ev.Duration)
+			}
+			ev.Duration = 0
+			if ev != tt.want {
+				t.Errorf("event = %+v, want %+v", ev, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_NotifySkippedOnAbort(t *testing.T) {
+	// Given: a model with a NotifyFunc that is aborting a pipeline
+	called := false
+	m := NewModel(WithNotifyFunc(func(CompletionEvent) error {
+		called = true
+		return nil
+	}))
+	m.aborting = true
+
+	// When: the pipeline reports its cancellation error
+	_, cmd := m.Update(PipelineErrorMsg{Err: context.Canceled})
+	execBatch(t, cmd)
+
+	// Then: no notification is sent for a user-initiated abort
+	if called {
+		t.Error("NotifyFunc should not be called on abort")
+	}
+}
+
+func TestModel_NotifyFailureShowsStatus(t *testing.T) {
+	// Given: a model whose notify hook failed
+	m := newSizedModel(90, 40)
+
+	// When: the notify result arrives with an error
+	updated, cmd := m.Update(notifyDoneMsg{Err: errors.New("webhook returned 500")})
+	m = updated.(Model)
+
+	// Then: a transient status line shows the warning
+	if !strings.Contains(m.statusMsg, "notify failed: webhook returned 500") {
+		t.Errorf("statusMsg = %q, want notify warning", m.statusMsg)
+	}
+	if cmd == nil {
+		t.Error("expected a status clear tick")
+	}
+}
+
+func TestModel_SpinnerTick_DrivesElapsedUntilPipelineEnds(t *testing.T) {
+	// Given: a pipeline model with a running phase
+	m := newPipelineModel(90, 40, []string{"plan", "code"})
+	m.eventCh = make(chan tea.Msg, 1)
+	updated, _ := m.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
+	m = updated.(Model)
+
+	// When: the spinner ticks while the pipeline runs
+	updated, cmd := m.Update(m.pipeline.spinner.Tick())
+	m = updated.(Model)
+
+	// Then: the next tick is scheduled
+	if cmd == nil {
+		t.Fatal("spinner tick should schedule another tick while the pipeline runs")
+	}
+
+	// When: the pipeline completes and the next tick arrives
+	updated, _ = m.Update(PipelineDoneMsg{Output: PipelineOutput{Success: true}})
+	m = updated.(Model)
+	updated, cmd = m.Update(m.pipeline.spinner.Tick())
+	m = updated.(Model)
+
+	// Then: ticking stops and the header counter is frozen
+	if cmd != nil {
+		t.Error("spinner tick should not schedule a tick after the pipeline ends")
+	}
+	if m.pipeline.endedAt.IsZero() {
+		t.Error("PipelineDoneMsg should stop the elapsed counter")
+	}
+}
+
+func TestModel_SpinnerTick_ReachesBackgroundPipelineFromOtherModes(t *testing.T) {
+	// Given: a pipeline running in the background behind the confirm dialog
+	m := newPipelineModel(90, 40, []string{"plan"})
+	m.backgroundMode = ModePipeline
+	m.mode = ModeConfirm
+
+	// When: the spinner ticks
+	_, cmd := m.Update(m.pipeline.spinner.Tick())
+
+	// Then: the background tick chain keeps running
+	if cmd == nil {
+		t.Error("spinner tick should keep the background pipeline ticking")
+	}
+}
+
+func TestModel_HealthCheckBanner(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantBanner bool
+	}{
+		{name: "healthy provider", err: nil},
+		{name: "provider not logged in", err: errors.New("provider: claude: not ready: Invalid API key (run `claude login`)"), wantBanner: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a dashboard whose startup health check returns tt.err
+			m := NewModel(
+				WithBeadLister(&stubLister{beads: sampleBeads()}),
+				WithHealthCheck(func() error { return tt.err }),
+			)
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+			m = updated.(Model)
+			var msgs []tea.Msg
+			for _, msg := range execBatch(t, m.Init()) {
+				if _, ok := msg.(healthCheckMsg); ok {
+					msgs = append(msgs, msg)
+				}
+			}
+			if len(msgs) != 1 {
+				t.Fatalf("Init ran the health check %d times, want 1", len(msgs))
+			}
+
+			// When the check result and the bead list arrive
+			updated, _ = m.Update(msgs[0])
+			updated, _ = updated.(Model).Update(BeadListMsg{Beads: sampleBeads()})
+			m = updated.(Model)
+			view := m.View()
+
+			// Then the banner appears only on failure, browsing still works, and the view fits
+			if got := containsPlainText(view, "claude login"); got != tt.wantBanner {
+				t.Errorf("banner shown = %v, want %v:\n%s", got, tt.wantBanner, view)
+			}
+			if !containsPlainText(view, "cap-001") {
+				t.Errorf("bead list missing:\n%s", view)
+			}
+			if lines := strings.Count(view, "\n") + 1; lines > 30 {
+				t.Errorf("view is %d lines, want at most 30", lines)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/smileynet/capsule/internal/prompt"
+	"github.com/smileynet/capsule/internal/provider"
 )
 
 // Mode represents the current dashboard view mode.
@@ -22,6 +23,8 @@ const (
 	ModeCampaign                    // Campaign running with task queue and inline phases.
 	ModeCampaignSummary             // Campaign complete, showing aggregate results.
 	ModeConfirm                     // Confirmation screen before dispatch.
+	ModeQueueSummary                // Bead queue complete, showing each bead's result.
+	ModeCreate                      // Create-bead form opened with n in browse mode.
 )
 
 // Focus represents which pane has keyboard focus.
@@ -39,6 +42,25 @@ type BeadSummary struct {
 	Priority int
 	Type     string
 	Closed   bool
+	Status   string // bd status, e.g. BeadInProgress or BeadBlocked; may be empty.
+}
+
+// bd statuses of open beads that the bead list shows but will not run.
+const (
+	BeadInProgress = "in_progress"
+	BeadBlocked    = "blocked"
+)
+
+// held returns the badge label of a bead that is open but claimed or
+// blocked in bd, or "" for a bead that can run.
+func (b BeadSummary) held() string {
+	switch b.Status {
+	case BeadInProgress:
+		return "in progress"
+	case BeadBlocked:
+		return "blocked"
+	}
+	return ""
 }
 
 // BeadDetail is the resolved detail of a single bead for the right pane.
@@ -47,6 +69,8 @@ type BeadDetail struct {
 	Title        string
 	Priority     int
 	Type         string
+	Status       string
+	Labels       []string
 	Description  string
 	Acceptance   string
 	EpicID       string
@@ -65,16 +89,24 @@ const (
 	PhaseFailed  PhaseStatus = "failed"
 	PhaseError   PhaseStatus = "error"
 	PhaseSkipped PhaseStatus = "skipped"
+	// PhaseProgress carries a running phase's latest provider message in
+	// PhaseUpdateMsg.Message; the phase stays running.
+	PhaseProgress PhaseStatus = "progress"
+	// PhaseRetrying reports a wait before retrying a transient provider
+	// failure, described in PhaseUpdateMsg.Message; the phase stays running.
+	PhaseRetrying PhaseStatus = "retrying"
 )
 
 // PhaseReport stores the result of a completed pipeline phase.
 type PhaseReport struct {
-	PhaseName    string
-	Status       PhaseStatus
-	Summary      string
-	Feedback     string
-	FilesChanged []string
-	Duration     time.Duration
+	PhaseName        string
+	Status           PhaseStatus
+	Summary          string
+	Feedback         string
+	FilesChanged     []string
+	Duration         time.Duration
+	Usage            provider.Usage
+	MissingArtifacts []string // Set when the phase failed its artifact check rather than a review.
 }
 
 // PipelineInput is the input to start a pipeline run.
@@ -82,6 +114,9 @@ type PipelineInput struct {
 	BeadID         string
 	Provider       string
 	SiblingContext []prompt.SiblingContext // Completed sibling tasks for cross-run context.
+	Resume         bool                    // Continue a failed run from its checkpoint in the existing worktree.
+	PauseRequested func() bool             // Checked between phases; true stops the run with ErrPipelinePaused.
+	Phases         string                  // Phase set to run instead of the configured one; empty keeps it.
 }
 
 // PipelineOutput is the result of a completed pipeline run.
@@ -89,6 +124,7 @@ type PipelineOutput struct {
 	Success      bool
 	Error        error
 	PhaseReports []PhaseReport
+	Findings     []provider.Finding // Reviewer findings, deduplicated by title.
 }
 
 // --- Consumer-side interfaces ---
@@ -97,6 +133,8 @@ type PipelineOutput struct {
 type BeadLister interface {
 	Ready() ([]BeadSummary, error)
 	Closed(limit int) ([]BeadSummary, error)
+	// List returns the beads in each of states, with Status set.
+	List(states []string) ([]BeadSummary, error)
 }
 
 // BeadResolver fetches full detail for a single bead.
@@ -104,20 +142,125 @@ type BeadResolver interface {
 	Resolve(id string) (BeadDetail, error)
 }
 
+// NewBeadInput holds the fields of a bead filed from the create form.
+type NewBeadInput struct {
+	Title       string
+	Description string
+	Type        string // "task", "feature", "epic", or "bug".
+	Priority    int    // 0 (highest) to 4.
+	ParentID    string // Optional parent bead.
+}
+
+// BeadCreator files new beads from the create form.
+type BeadCreator interface {
+	Create(in NewBeadInput) (string, error)
+}
+
+// BeadCache is the bd cache behind the BeadLister and BeadResolver. The
+// dashboard drops it on r, drops the lists before an automatic reload, and
+// shows its counters in the debug footer.
+type BeadCache interface {
+	Invalidate()
+	InvalidateLists()
+	Stats() (hits, misses int64)
+}
+
 // PipelineRunner dispatches and runs a pipeline.
 type PipelineRunner interface {
 	RunPipeline(ctx context.Context, input PipelineInput, statusFn func(PhaseUpdateMsg)) (PipelineOutput, error)
 }
 
 // PostPipelineFunc runs post-pipeline lifecycle (merge, cleanup, close bead).
-// Called in a background goroutine after a pipeline completes and the user
-// returns to browse mode. Results are surfaced via PostPipelineDoneMsg and
-// shown as a transient status line in the UI.
-type PostPipelineFunc func(beadID string) error
+// Called as a tea.Cmd once a successful pipeline's summary is shown. Results
+// are surfaced via PostPipelineDoneMsg and rendered in the summary pane, or as
+// a transient status line when the user has already left the summary.
+// A non-nil error means the lifecycle could not run at all.
+type PostPipelineFunc func(in PostPipelineInput) (PostPipelineResult, error)
+
+// PostPipelineInput identifies the bead post-pipeline lifecycle runs for and
+// carries the details its merge commit message can use.
+type PostPipelineInput struct {
+	BeadID       string
+	Title        string   // Bead title, when known.
+	Type         string   // Bead type, when known.
+	Summary      string   // Summary of the final passing phase.
+	FilesChanged []string // Files the final passing phase reported.
+	Comment      string   // Posted on the bead before it is closed; "" posts nothing.
+}
+
+// newPostPipelineInput builds a PostPipelineInput from the bead's details and
+// the final passing phase among reports.
+func newPostPipelineInput(beadID, title, beadType string, reports []PhaseReport) PostPipelineInput {
+	in := PostPipelineInput{BeadID: beadID, Title: title, Type: beadType}
+	for i := len(reports) - 1; i >= 0; i-- {
+		if reports[i].Status == PhasePassed {
+			in.Summary = reports[i].Summary
+			in.FilesChanged = reports[i].FilesChanged
+			break
+		}
+	}
+	return in
+}
+
+// PostPipelineResult describes what post-pipeline lifecycle accomplished.
+type PostPipelineResult struct {
+	Merged        bool     // Branch merged into the main branch.
+	MergeConflict bool     // Merge stopped on a conflict; the branch is left for manual resolution.
+	BranchCleaned bool     // Worktree and branch removed.
+	BeadClosed    bool     // Bead closed.
+	Messages      []string // Lifecycle output lines, including conflict resolution steps.
+}
+
+// DispatchCheckFunc runs before a confirmed dispatch starts. A non-nil error
+// blocks the dispatch and is shown in the browse pane.
+type DispatchCheckFunc func() error
+
+// HealthCheckFunc checks at startup that the configured provider can run
+// phases. A non-nil error is shown as a banner; browsing still works.
+type HealthCheckFunc func() error
+
+// OverlapFunc reports files that other in-flight capsules have changed, keyed
+// by capsule, which a dispatch for beadID may conflict with on merge. The
+// confirmation screen shows them as a warning.
+type OverlapFunc func(beadID string) (map[string][]string, error)
+
+// WorktreeFunc returns the worktree path and branch name a pipeline for
+// beadID runs in. It is called when the pipeline is dispatched, before the
+// worktree necessarily exists.
+type WorktreeFunc func(beadID string) (path, branch string)
+
+// WorklogPathFunc returns the worklog file to open for beadID with e: the
+// worktree's while its pipeline runs, else the archived copy. It returns ""
+// when the bead has no worklog yet.
+type WorklogPathFunc func(beadID string) string
+
+// CompletionEvent describes a finished pipeline or campaign for NotifyFunc.
+type CompletionEvent struct {
+	BeadID      string
+	Campaign    bool // True for campaigns, false for single pipelines.
+	Success     bool
+	Duration    time.Duration // Time since dispatch.
+	FailedPhase string        // First failed phase from the phase reports, if known.
+	Err         error         // Pipeline or campaign error, nil on normal completion.
+}
+
+// NotifyFunc is called in a background goroutine when a dispatched pipeline
+// or campaign finishes. Notification is best-effort: a returned error is
+// shown as a transient status line and does not affect the run.
+type NotifyFunc func(CompletionEvent) error
+
+// CampaignTaskStore reads and updates persisted campaign task results for the
+// campaign summary screen.
+type CampaignTaskStore interface {
+	// TaskReports returns the stored phase reports of parentID's tasks, keyed by bead ID.
+	TaskReports(parentID string) (map[string][]PhaseReport, error)
+	// RecordTaskPassed marks beadID as passed in the stored state of parentID.
+	RecordTaskPassed(parentID, beadID string, reports []PhaseReport) error
+}
 
 // --- tea.Msg types ---
 
-// BeadListMsg carries the result of a BeadLister.Ready() call.
+// BeadListMsg carries the merged bead lists initBrowse fetched.
 type BeadListMsg struct {
 	Beads []BeadSummary
 	Err   error
@@ -132,14 +275,28 @@ type BeadResolvedMsg struct {
 
 // PhaseUpdateMsg carries a status update for a single pipeline phase.
 type PhaseUpdateMsg struct {
-	Phase        string
-	Status       PhaseStatus
-	Attempt      int
-	MaxRetry     int
-	Duration     time.Duration
-	Summary      string
-	FilesChanged []string
-	Feedback     string
+	Phase            string
+	Status           PhaseStatus
+	PhaseIndex       int     // 1-based position of the phase in the pipeline; 0 when unknown.
+	PhaseTotal       int     // Number of phases in the pipeline; 0 when the update carries no position.
+	FractionComplete float64 // Share of the pipeline done, 0 to 1; meaningful only when PhaseTotal > 0.
+	Attempt          int
+	MaxRetry         int
+	Duration         time.Duration
+	Usage            provider.Usage // Tokens the phase attempt consumed (zero while running).
+	Summary          string
+	FilesChanged     []string
+	Feedback         string
+	Message          string   // What the provider is doing; set only for PhaseProgress and PhaseRetrying.
+	MissingArtifacts []string // Required artifact globs that matched no file; set when the artifact check failed the phase.
+}
+
+// PipelineStartedMsg carries where a dispatched pipeline runs. It is sent
+// before the first phase update when a WorktreeFunc is configured.
+type PipelineStartedMsg struct {
+	BeadID       string
+	WorktreePath string
+	Branch       string
 }
 
 // PipelineDoneMsg signals successful pipeline completion.
@@ -147,9 +304,11 @@ type PipelineDoneMsg struct {
 	Output PipelineOutput
 }
 
-// PipelineErrorMsg signals pipeline failure.
+// PipelineErrorMsg signals pipeline failure. Output holds the phases that
+// ran before the failure, the failing one included.
 type PipelineErrorMsg struct {
-	Err error
+	Err    error
+	Output PipelineOutput
 }
 
 // ConfirmRequestMsg signals the user pressed Enter on a bead and wants to
@@ -168,6 +327,19 @@ type DispatchMsg struct {
 	Provider  string // Provider name frozen at confirm time.
 }
 
+// QueueStartMsg signals the user pressed Enter with beads selected in browse
+// mode, to run their pipelines one after another. Beads are in tree order.
+type QueueStartMsg struct {
+	Beads []DispatchMsg
+}
+
+// QueueAdvanceMsg signals that the queued bead at Index has finished,
+// post-pipeline lifecycle included, and the next one may start. An Index
+// other than the running bead's is stale and ignored.
+type QueueAdvanceMsg struct {
+	Index int
+}
+
 // ProviderCycleMsg signals the user pressed 'p' to cycle to the next provider.
 type ProviderCycleMsg struct{}
 
@@ -176,15 +348,65 @@ type ProviderCycleMsg struct{}
 type RefreshBeadsMsg struct{}
 
 // PostPipelineDoneMsg signals that post-pipeline lifecycle completed.
-// Displayed as a transient status line that auto-clears after statusLineDuration.
+// Rendered in the summary pane while it is showing the bead; otherwise
+// displayed as a transient status line that auto-clears after statusLineDuration.
 type PostPipelineDoneMsg struct {
 	BeadID string
+	Result PostPipelineResult
 	Err    error
 }
 
-// elapsedTickMsg is sent every second to update the elapsed time display
-// for running pipeline phases.
-type elapsedTickMsg struct{}
+// dispatchCheckMsg carries the result of a DispatchCheckFunc for a pending dispatch.
+type dispatchCheckMsg struct {
+	Dispatch DispatchMsg
+	Err      error
+}
+
+// queueCheckMsg carries the result of a DispatchCheckFunc for a pending queue.
+type queueCheckMsg struct {
+	Start QueueStartMsg
+	Err   error
+}
+
+// healthCheckMsg carries the result of the startup HealthCheckFunc.
+type healthCheckMsg struct {
+	Err error
+}
+
+// overlapCheckMsg carries the result of an OverlapFunc for the confirmation screen.
+type overlapCheckMsg struct {
+	BeadID   string
+	Overlaps map[string][]string
+	Err      error
+}
+
+// notifyDoneMsg carries the result of a NotifyFunc call.
+type notifyDoneMsg struct {
+	Err error
+}
+
+// campaignReportsMsg carries stored phase reports loaded for the campaign summary.
+type campaignReportsMsg struct {
+	Reports map[string][]PhaseReport
+	Err     error
+}
+
+// taskRetryDoneMsg signals that a single-task retry started from the
+// campaign summary has finished.
+type taskRetryDoneMsg struct {
+	BeadID   string
+	Index    int
+	Duration time.Duration
+	Output   PipelineOutput
+	Err      error
+}
+
+// taskRetryRecordedMsg carries the result of post-pipeline lifecycle and the
+// campaign state update after a successful task retry.
+type taskRetryRecordedMsg struct {
+	BeadID string
+	Err    error
+}
 
 // resolveDebounceMsg fires after the debounce delay. If pendingResolveID
 // still matches ID, the actual resolve is dispatched.
@@ -195,6 +417,13 @@ type resolveDebounceMsg struct {
 // statusClearMsg signals that the transient status line should be cleared.
 type statusClearMsg struct{}
 
+// beadCreatedMsg carries the result of filing a bead from the create form.
+type beadCreatedMsg struct {
+	ID       string
+	ParentID string
+	Err      error
+}
+
 // channelClosedMsg signals that the pipeline event channel has been closed,
 // indicating the pipeline goroutine has finished.
 type channelClosedMsg struct{}
@@ -214,9 +443,10 @@ const (
 
 // CampaignTaskInfo describes a child task in a campaign.
 type CampaignTaskInfo struct {
-	BeadID   string
-	Title    string
-	Priority int
+	BeadID    string
+	Title     string
+	Priority  int
+	BlockedBy []string // Unfinished siblings it waits on; rendered dimmed with their IDs until it starts.
 }
 
 // --- Campaign tea.Msg types ---
@@ -226,6 +456,13 @@ type CampaignStartMsg struct {
 	ParentID    string
 	ParentTitle string
 	Tasks       []CampaignTaskInfo
+	Resumed     *CampaignResume // Set when the campaign continues from saved state.
+}
+
+// CampaignResume is the saved progress a resumed campaign continues from.
+type CampaignResume struct {
+	Done  int // Tasks completed before this run.
+	Total int // Tasks in the saved state.
 }
 
 // CampaignTaskStartMsg signals that a specific task within a campaign is starting.
@@ -245,6 +482,14 @@ type CampaignTaskDoneMsg struct {
 	Error        string
 }
 
+// CampaignTaskSkippedMsg signals that a task was skipped without running
+// because a task it depends on failed.
+type CampaignTaskSkippedMsg struct {
+	BeadID string
+	Index  int
+	Reason string
+}
+
 // CampaignDoneMsg signals that the entire campaign has completed.
 type CampaignDoneMsg struct {
 	ParentID   string
@@ -252,6 +497,14 @@ type CampaignDoneMsg struct {
 	Passed     int
 	Failed     int
 	Skipped    int
+	ParentOpen string         // Why the parent bead was left open when closing it on success is on.
+	Usage      provider.Usage // Tokens consumed by the whole campaign, sub-campaigns included.
+}
+
+// CampaignParentClosedMsg signals that a successful campaign closed its
+// parent bead, so the bead list is refreshed.
+type CampaignParentClosedMsg struct {
+	ParentID string
 }
 
 // SubCampaignStartMsg signals that a nested campaign has started.
@@ -282,6 +535,17 @@ type CampaignPausedMsg struct {
 	Details string
 }
 
+// CampaignCircuitBrokenMsg signals that the campaign circuit breaker stopped
+// the campaign, with the reason, the failed tasks, and total failures of
+// each kind.
+type CampaignCircuitBrokenMsg struct {
+	Reason         string
+	Banner         string   // e.g. "stopped after 3 consecutive failures at cap-123.5".
+	FailedIDs      []string // Tasks whose failures tripped the breaker, oldest first.
+	SetupFailures  int
+	SignalFailures int
+}
+
 // CampaignValidationStartMsg signals that a campaign validation pipeline is starting.
 type CampaignValidationStartMsg struct{}
 
@@ -290,6 +554,7 @@ type CampaignValidationDoneMsg struct {
 	Success      bool
 	Duration     time.Duration
 	PhaseReports []PhaseReport
+	Error        string // Why validation failed; empty on success.
 }
 
 // CampaignRunner dispatches and runs a campaign (sequential child pipelines).
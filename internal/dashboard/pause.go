@@ -0,0 +1,46 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrPipelinePaused is returned by a PipelineRunner whose run stopped
+// between phases because PipelineInput.PauseRequested reported true. The
+// bead's worktree and checkpoint are kept so the run can be resumed.
+var ErrPipelinePaused = errors.New("pipeline paused")
+
+// requestPause asks the foreground pipeline to stop once its running phase
+// finishes. The runner sees the request through PipelineInput.PauseRequested.
+func (m Model) requestPause() (tea.Model, tea.Cmd) {
+	if m.pauseFlag == nil || m.cancelPipeline == nil || m.aborting || m.pipeline.pausing {
+		return m, nil
+	}
+	m.pauseFlag.Store(true)
+	m.pipeline.pausing = true
+	return m, nil
+}
+
+// pipelinePaused reports whether the last pipeline run stopped for a pause.
+func (m Model) pipelinePaused() bool {
+	return errors.Is(m.pipelineErr, ErrPipelinePaused)
+}
+
+// pausedStatus is the status line shown when a pipeline has paused.
+func pausedStatus(beadID string) string {
+	return fmt.Sprintf("%s %s paused; press enter on it to resume", SymbolPaused, beadID)
+}
+
+// returnToBrowseAfterPause leaves a paused pipeline for browse mode instead
+// of the failure summary. Post-pipeline lifecycle does not run, since the
+// pipeline has not finished.
+func (m Model) returnToBrowseAfterPause() (Model, tea.Cmd) {
+	m.statusMsg = pausedStatus(m.dispatchedBeadID)
+	m, cmd := m.returnToBrowse()
+	return m, tea.Batch(cmd, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+		return statusClearMsg{}
+	}))
+}
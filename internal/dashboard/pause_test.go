@@ -0,0 +1,157 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_PauseReturnsToBrowseAndResumes(t *testing.T) {
+	// Given a running pipeline that checks for a pause after its phase
+	inputs := make(chan PipelineInput, 2)
+	release := make(chan struct{})
+	runner := &mockRunner{runFn: func(_ context.Context, in PipelineInput, statusFn func(PhaseUpdateMsg)) (PipelineOutput, error) {
+		inputs <- in
+		statusFn(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
+		<-release
+		statusFn(PhaseUpdateMsg{Phase: "plan", Status: PhasePassed})
+		if in.PauseRequested != nil && in.PauseRequested() {
+			return PipelineOutput{}, ErrPipelinePaused
+		}
+		return PipelineOutput{Success: true}, nil
+	}}
+	var notified bool
+	m := NewModel(
+		WithPipelineRunner(runner),
+		WithBeadLister(&stubLister{beads: sampleBeads()}),
+		WithPhaseNames([]string{"plan", "code"}),
+		WithNotifyFunc(func(CompletionEvent) error { notified = true; return nil }),
+	)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	updated, _ = m.Update(DispatchMsg{BeadID: "cap-001", BeadType: "task", BeadTitle: "First task", Provider: "claude"})
+	m = updated.(Model)
+	if in := <-inputs; in.Resume {
+		t.Fatalf("first dispatch Resume = true, want false")
+	}
+	updated, _ = m.Update(listenForEvents(m.eventCh)())
+	m = updated.(Model)
+
+	// When p is pressed while the phase runs
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = updated.(Model)
+
+	// Then the running phase shows the pending pause
+	if !strings.Contains(stripANSI(m.View()), "pausing") {
+		t.Errorf("view should show the pending pause, got:\n%s", stripANSI(m.View()))
+	}
+
+	// When the phase finishes and the runner pauses
+	close(release)
+	m = drainPipeline(t, m)
+
+	// Then the dashboard returns to browse instead of the failure summary
+	if m.mode != ModeBrowse {
+		t.Fatalf("mode = %d, want ModeBrowse", m.mode)
+	}
+	if !strings.Contains(m.statusMsg, "cap-001 paused") {
+		t.Errorf("statusMsg = %q, want pause notice", m.statusMsg)
+	}
+	if notified {
+		t.Error("a pause should not send a completion notification")
+	}
+
+	// And the bead is marked paused in the list
+	updated, _ = m.Update(BeadListMsg{Beads: sampleBeads()})
+	m = updated.(Model)
+	if !strings.Contains(stripANSI(m.View()), SymbolPaused+" paused") {
+		t.Errorf("browse list should mark the paused bead, got:\n%s", stripANSI(m.View()))
+	}
+
+	// When enter is confirmed on the paused bead
+	updated, _ = m.Update(ConfirmRequestMsg{BeadID: "cap-001", BeadType: "task", BeadTitle: "First task"})
+	m = updated.(Model)
+	if !strings.Contains(m.confirm.content(), "Resume paused pipeline") {
+		t.Errorf("confirm should offer a resume, got:\n%s", m.confirm.content())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	// Then the pipeline resumes from its checkpoint
+	if in := <-inputs; !in.Resume || in.BeadID != "cap-001" {
+		t.Errorf("input = %+v, want resume of cap-001", in)
+	}
+	if m.browse.paused["cap-001"] {
+		t.Error("resumed bead should no longer be marked paused")
+	}
+	m = drainPipeline(t, m)
+	if m.mode != ModeSummary || !m.pipelineSucceeded() {
+		t.Errorf("mode = %d, succeeded = %v; want passing summary", m.mode, m.pipelineSucceeded())
+	}
+}
+
+func TestModel_PauseKeyIgnored(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m Model) Model
+	}{
+		{
+			name: "while aborting",
+			setup: func(m Model) Model {
+				m.aborting = true
+				return m
+			},
+		},
+		{
+			name: "without a running pipeline",
+			setup: func(m Model) Model {
+				m.cancelPipeline = nil
+				return m
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a pipeline-mode model in the given state
+			m := newPipelineModel(90, 40, []string{"plan"})
+			m.cancelPipeline = func() {}
+			m.pauseFlag = new(atomic.Bool)
+			m = tt.setup(m)
+
+			// When p is pressed
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+			m = updated.(Model)
+
+			// Then no pause is requested
+			if m.pauseFlag.Load() || m.pipeline.pausing {
+				t.Error("pause should not be requested")
+			}
+		})
+	}
+}
+
+func TestModel_BackgroundPauseShowsStatus(t *testing.T) {
+	// Given a pipeline running in the background that pauses
+	m := newSizedModel(90, 40)
+	m.mode = ModeBrowse
+	m.backgroundMode = ModePipeline
+	m.dispatchedBeadID = "cap-001"
+	updated, _ := m.Update(PipelineErrorMsg{Err: ErrPipelinePaused})
+	m = updated.(Model)
+
+	// When its channel closes
+	updated, _ = m.Update(channelClosedMsg{})
+	m = updated.(Model)
+
+	// Then the status line reports the pause and the bead is marked paused
+	if !strings.Contains(m.statusMsg, "cap-001 paused") {
+		t.Errorf("statusMsg = %q, want pause notice", m.statusMsg)
+	}
+	if !m.browse.paused["cap-001"] {
+		t.Error("cap-001 should be marked paused")
+	}
+}
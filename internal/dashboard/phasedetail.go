@@ -0,0 +1,156 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// phaseDetailHeaderHeight is the number of lines above the detail viewport:
+// the phase header and a blank separator.
+const phaseDetailHeaderHeight = 2
+
+// phaseDetailState is the full-screen view of one phase's report, opened
+// with d on a phase row in pipeline or summary mode. The report pane
+// truncates long reviewer feedback; this view wraps and scrolls all of it.
+type phaseDetailState struct {
+	open     bool
+	phase    string // Phase whose report is shown.
+	viewport viewport.Model
+}
+
+// phaseDetailShown reports whether the phase detail view replaces the panes.
+func (m Model) phaseDetailShown() bool {
+	return m.detail.open && (m.mode == ModePipeline || m.mode == ModeSummary)
+}
+
+// phaseReport returns the report for the named phase. Live reports from
+// phase updates win; the summary's PipelineOutput fills in phases that have
+// none. It is nil while the phase has not finished.
+func (m Model) phaseReport(name string) *PhaseReport {
+	if r := m.pipeline.reports[name]; r != nil {
+		return r
+	}
+	if m.pipelineOutput != nil {
+		for i := range m.pipelineOutput.PhaseReports {
+			if r := &m.pipelineOutput.PhaseReports[i]; r.PhaseName == name {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// openPhaseDetail shows the selected phase's report full-screen. Phases
+// without a report yet (pending, running, skipped) have nothing to show.
+func (m Model) openPhaseDetail() (tea.Model, tea.Cmd) {
+	name := m.pipeline.SelectedPhase()
+	if m.phaseReport(name) == nil {
+		return m, nil
+	}
+	m.detail = phaseDetailState{open: true, phase: name, viewport: viewport.New(0, 0)}
+	return m.refreshPhaseDetail(), nil
+}
+
+// refreshPhaseDetail re-renders the open detail view for the current size
+// and report, keeping the scroll position. Live phase updates and resizes
+// call it so the view follows a retry of the phase.
+func (m Model) refreshPhaseDetail() Model {
+	if !m.detail.open {
+		return m
+	}
+	report := m.phaseReport(m.detail.phase)
+	if report == nil {
+		return m
+	}
+	vp := &m.detail.viewport
+	vp.Width = m.width
+	vp.Height = max(m.contentHeight()+borderChrome-phaseDetailHeaderHeight, 1)
+	offset := vp.YOffset
+	vp.SetContent(formatPhaseDetail(*report, m.width))
+	vp.SetYOffset(offset)
+	return m
+}
+
+// handlePhaseDetailKey routes keys while the detail view is open: esc or d
+// closes it, q and ctrl+c close it and act as usual, and everything else
+// scrolls.
+func (m Model) handlePhaseDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "d":
+		m.detail = phaseDetailState{}
+		return m, nil
+	case "q", "ctrl+c":
+		m.detail = phaseDetailState{}
+		return m.handleKey(msg)
+	}
+	var cmd tea.Cmd
+	m.detail.viewport, cmd = m.detail.viewport.Update(msg)
+	return m, cmd
+}
+
+// viewPhaseDetail renders the detail header — phase, status, attempt, and
+// duration — above the scrolling report.
+func (m Model) viewPhaseDetail() string {
+	var phase phaseEntry
+	for _, p := range m.pipeline.phases {
+		if p.Name == m.detail.phase {
+			phase = p
+		}
+	}
+	var report PhaseReport
+	if r := m.phaseReport(m.detail.phase); r != nil {
+		report = *r
+	}
+	statusText, statusStyle := "Passed", pipePassedStyle
+	if report.Status == PhaseFailed || report.Status == PhaseError {
+		statusText, statusStyle = "Failed", pipeFailedStyle
+	}
+	header := []string{m.detail.phase, statusStyle.Render(statusText)}
+	if phase.Attempt > 0 {
+		attempt := fmt.Sprintf("attempt %d", phase.Attempt)
+		if phase.MaxRetry > 0 {
+			attempt = fmt.Sprintf("attempt %d/%d", phase.Attempt, phase.MaxRetry)
+		}
+		header = append(header, pipeRetryStyle.Render(attempt))
+	}
+	if report.Duration > 0 {
+		header = append(header, pipeDurationStyle.Render(fmt.Sprintf("%.1fs", report.Duration.Seconds())))
+	}
+	return strings.Join(header, "  ") + "\n\n" + m.detail.viewport.View()
+}
+
+// artifactCheckFailed describes a failed artifact check, so it reads
+// differently from a reviewer's feedback.
+func artifactCheckFailed(missing []string) string {
+	return "Artifact check failed, no file matches:\n  " + strings.Join(missing, "\n  ")
+}
+
+// formatPhaseDetail renders a report's summary, changed files, and
+// feedback in full, word-wrapped to width.
+func formatPhaseDetail(r PhaseReport, width int) string {
+	var sections []string
+	if r.Summary != "" {
+		sections = append(sections, "Summary:\n"+r.Summary)
+	}
+	if len(r.FilesChanged) > 0 {
+		sections = append(sections, "Files changed:\n  "+strings.Join(r.FilesChanged, "\n  "))
+	}
+	if len(r.MissingArtifacts) > 0 {
+		sections = append(sections, artifactCheckFailed(r.MissingArtifacts))
+	}
+	if r.Feedback != "" {
+		sections = append(sections, "Feedback:\n"+r.Feedback)
+	}
+	if len(sections) == 0 {
+		sections = append(sections, pipePendingStyle.Render("No summary or feedback reported."))
+	}
+	text := strings.Join(sections, "\n\n")
+	if width <= 0 {
+		return text
+	}
+	return lipgloss.NewStyle().Width(width).Render(text)
+}
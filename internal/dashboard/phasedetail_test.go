@@ -0,0 +1,155 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newDetailModel returns a sized model running a two-phase pipeline whose
+// first phase failed with long feedback on its second attempt.
+func newDetailModel(t *testing.T, feedback string) Model {
+	t.Helper()
+	m := newSizedModel(60, 30)
+	m.mode = ModePipeline
+	m.pipeline = newPipelineState([]string{"execute", "review"})
+	updated, _ := m.Update(PhaseUpdateMsg{
+		Phase:        "execute",
+		Status:       PhaseFailed,
+		Attempt:      2,
+		MaxRetry:     3,
+		Duration:     4200 * time.Millisecond,
+		Summary:      "Tests still fail",
+		FilesChanged: []string{"main.go"},
+		Feedback:     feedback,
+	})
+	m = updated.(Model)
+	m.pipeline.cursor = 0
+	return m
+}
+
+func pressKey(t *testing.T, m Model, k string) Model {
+	t.Helper()
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	switch k {
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	updated, _ := m.Update(msg)
+	return updated.(Model)
+}
+
+func TestPhaseDetail_OpensOnFailedPhase(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "d", key: "d"},
+		{name: "enter on the phase list", key: "enter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a failed phase with feedback longer than the report pane shows
+			feedback := strings.Repeat("check the error path ", 12) + "END-OF-FEEDBACK"
+			m := newDetailModel(t, feedback)
+
+			// When the key is pressed on the phase row
+			m = pressKey(t, m, tt.key)
+
+			// Then the detail view shows the header and the full, wrapped report
+			if !m.phaseDetailShown() {
+				t.Fatal("phase detail should be shown")
+			}
+			view := stripANSI(m.View())
+			for _, want := range []string{"execute", "Failed", "attempt 2/3", "4.2s", "Summary:", "Tests still fail", "main.go", "Feedback:", "END-OF-FEEDBACK"} {
+				if !strings.Contains(view, want) {
+					t.Errorf("View() missing %q", want)
+				}
+			}
+			for line := range strings.Lines(view) {
+				if w := len([]rune(strings.TrimRight(line, "\n"))); w > 60 {
+					t.Errorf("line width %d exceeds terminal width 60: %q", w, line)
+				}
+			}
+		})
+	}
+}
+
+func TestPhaseDetail_EscReturnsToPanes(t *testing.T) {
+	// Given an open detail view
+	m := pressKey(t, newDetailModel(t, "fix it"), "d")
+
+	// When esc is pressed
+	m = pressKey(t, m, "esc")
+
+	// Then the panes return and the pipeline keeps running
+	if m.phaseDetailShown() {
+		t.Error("phase detail should be closed")
+	}
+	if m.mode != ModePipeline {
+		t.Errorf("mode = %d, want ModePipeline (%d)", m.mode, ModePipeline)
+	}
+}
+
+func TestPhaseDetail_ScrollsLongFeedback(t *testing.T) {
+	// Given a detail view whose feedback is taller than the screen
+	m := pressKey(t, newDetailModel(t, strings.Repeat("line\n", 80)+"LAST"), "d")
+	if strings.Contains(stripANSI(m.View()), "LAST") {
+		t.Fatal("last line should start below the fold")
+	}
+
+	// When the view is scrolled down
+	for range 80 {
+		m = pressKey(t, m, "j")
+	}
+
+	// Then the end of the feedback is visible
+	if !strings.Contains(stripANSI(m.View()), "LAST") {
+		t.Error("View() should show the last line after scrolling")
+	}
+}
+
+func TestPhaseDetail_NoReportIsNoop(t *testing.T) {
+	// Given the cursor on a phase that has not run
+	m := newDetailModel(t, "fix it")
+	m.pipeline.cursor = 1
+
+	// When d is pressed
+	m = pressKey(t, m, "d")
+
+	// Then nothing opens
+	if m.phaseDetailShown() {
+		t.Error("phase detail should not open for a pending phase")
+	}
+}
+
+func TestPhaseDetail_SummaryUsesPipelineOutput(t *testing.T) {
+	// Given a summary whose report comes only from the frozen PipelineOutput
+	m := newSizedModel(60, 30)
+	m.mode = ModeSummary
+	m.pipeline = newPipelineState([]string{"execute"})
+	m.pipelineOutput = &PipelineOutput{PhaseReports: []PhaseReport{
+		{PhaseName: "execute", Status: PhaseFailed, Feedback: "frozen feedback", Duration: time.Second},
+	}}
+
+	// When d is pressed
+	m = pressKey(t, m, "d")
+
+	// Then the detail view shows the frozen report
+	if !m.phaseDetailShown() {
+		t.Fatal("phase detail should be shown in summary mode")
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "frozen feedback") || !strings.Contains(view, "1.0s") {
+		t.Errorf("View() should show the frozen report, got:\n%s", view)
+	}
+
+	// And esc closes the detail without leaving the summary
+	m = pressKey(t, m, "esc")
+	if m.phaseDetailShown() || m.mode != ModeSummary {
+		t.Errorf("after esc: detail shown = %v, mode = %d; want closed, ModeSummary", m.phaseDetailShown(), m.mode)
+	}
+}
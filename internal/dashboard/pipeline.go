@@ -2,35 +2,68 @@ package dashboard
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/smileynet/capsule/internal/progressbar"
+	"github.com/smileynet/capsule/internal/provider"
 )
 
 // phaseEntry tracks the display state of a single pipeline phase.
 type phaseEntry struct {
-	Name     string
-	Status   PhaseStatus
-	Attempt  int
-	MaxRetry int
-	Duration time.Duration
+	Name      string
+	Status    PhaseStatus
+	Attempt   int
+	MaxRetry  int
+	Duration  time.Duration
+	StartedAt time.Time      // When the running update for the current attempt arrived.
+	Activity  string         // Latest progress message while running; cleared when the attempt ends.
+	Usage     provider.Usage // Tokens consumed across the phase's attempts.
+}
+
+// timing renders the phase's live elapsed counter while running, or its
+// final duration once reported. Returns "" when there is nothing to show.
+func (p phaseEntry) timing(aborting bool) string {
+	switch {
+	case p.Status == PhaseRunning && !p.StartedAt.IsZero() && !aborting:
+		return pipeDurationStyle.Render(formatElapsed(time.Since(p.StartedAt)))
+	case p.Duration > 0:
+		return pipeDurationStyle.Render(fmt.Sprintf("%.1fs", p.Duration.Seconds()))
+	}
+	return ""
+}
+
+// formatElapsed renders d as mm:ss.
+func formatElapsed(d time.Duration) string {
+	secs := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
 }
 
 // pipelineState manages the phase list, cursor, reports, and auto-follow for pipeline mode.
 type pipelineState struct {
-	phases         []phaseEntry
-	cursor         int
-	autoFollow     bool
-	spinner        spinner.Model
-	running        bool
-	reports        map[string]*PhaseReport
-	aborting       bool
-	beadID         string    // Bead ID shown in header (optional).
-	beadTitle      string    // Bead title shown in header (optional).
-	provider       string    // Provider name shown in header badge (optional).
-	phaseStartedAt time.Time // Timestamp when the current running phase started.
+	phases       []phaseEntry
+	cursor       int
+	autoFollow   bool
+	spinner      spinner.Model
+	reports      map[string]*PhaseReport
+	aborting     bool
+	pausing      bool           // Pause requested; the run stops after the running phase.
+	beadID       string         // Bead ID shown in header (optional).
+	beadTitle    string         // Bead title shown in header (optional).
+	beadType     string         // Bead type, passed on to post-pipeline lifecycle (optional).
+	provider     string         // Provider name shown in header badge (optional).
+	queueLabel   string         // "Queue 2/3" shown in the header while a bead queue runs (optional).
+	branch       string         // Worktree branch shown under the header (optional).
+	worktreePath string         // Worktree path shown under the header, copied with y (optional).
+	usage        provider.Usage // Tokens consumed across all phase attempts so far.
+	startedAt    time.Time      // When the first phase started running; zero until then.
+	endedAt      time.Time      // Set by finish; freezes the header's elapsed counter.
+	fraction     float64        // Share of the pipeline done, from the latest update that carried it.
 }
 
 // newPipelineState creates a pipelineState for the given phase names.
@@ -50,11 +83,44 @@ func newPipelineState(phaseNames []string) pipelineState {
 	}
 }
 
-// elapsedTickCmd returns a tea.Cmd that fires an elapsedTickMsg after one second.
-func elapsedTickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(time.Time) tea.Msg {
-		return elapsedTickMsg{}
-	})
+// reset returns an empty pipelineState for the next campaign task. It keeps
+// ps's spinner, whose tick chain also redraws the elapsed counters, so the
+// chain carries over instead of being rejected as another spinner's.
+func (ps pipelineState) reset() pipelineState {
+	next := newPipelineState(nil)
+	next.spinner = ps.spinner
+	return next
+}
+
+// withPhase returns ps with a pending entry appended for name unless the
+// phase list already has one.
+func (ps pipelineState) withPhase(name string) pipelineState {
+	for _, p := range ps.phases {
+		if p.Name == name {
+			return ps
+		}
+	}
+	ps.phases = append(slices.Clip(ps.phases), phaseEntry{Name: name, Status: PhasePending})
+	return ps
+}
+
+// finish stops the header's elapsed counter at the current time.
+func (ps pipelineState) finish() pipelineState {
+	if !ps.startedAt.IsZero() && ps.endedAt.IsZero() {
+		ps.endedAt = time.Now()
+	}
+	return ps
+}
+
+// elapsed returns the time since the first phase started, up to finish.
+func (ps pipelineState) elapsed() time.Duration {
+	switch {
+	case ps.startedAt.IsZero():
+		return 0
+	case !ps.endedAt.IsZero():
+		return ps.endedAt.Sub(ps.startedAt)
+	}
+	return time.Since(ps.startedAt)
 }
 
 // Update processes messages for the pipeline state.
@@ -62,11 +128,6 @@ func (ps pipelineState) Update(msg tea.Msg) (pipelineState, tea.Cmd) {
 	switch msg := msg.(type) {
 	case PhaseUpdateMsg:
 		return ps.handlePhaseUpdate(msg), nil
-	case elapsedTickMsg:
-		if ps.running {
-			return ps, elapsedTickCmd()
-		}
-		return ps, nil
 	case tea.KeyMsg:
 		return ps.handleKey(msg), nil
 	case spinner.TickMsg:
@@ -78,9 +139,17 @@ func (ps pipelineState) Update(msg tea.Msg) (pipelineState, tea.Cmd) {
 }
 
 func (ps pipelineState) handlePhaseUpdate(msg PhaseUpdateMsg) pipelineState {
+	if msg.PhaseTotal > 0 {
+		ps.fraction = msg.FractionComplete
+	}
 	for i := range ps.phases {
 		if ps.phases[i].Name == msg.Phase {
+			if msg.Status == PhaseProgress || msg.Status == PhaseRetrying {
+				ps.phases[i].Activity = msg.Message
+				break
+			}
 			ps.phases[i].Status = msg.Status
+			ps.phases[i].Activity = ""
 			if msg.Attempt > 0 {
 				ps.phases[i].Attempt = msg.Attempt
 			}
@@ -90,21 +159,29 @@ func (ps pipelineState) handlePhaseUpdate(msg PhaseUpdateMsg) pipelineState {
 			if msg.Duration > 0 {
 				ps.phases[i].Duration = msg.Duration
 			}
+			ps.phases[i].Usage = ps.phases[i].Usage.Add(msg.Usage)
+			ps.usage = ps.usage.Add(msg.Usage)
 			switch msg.Status {
 			case PhaseRunning:
-				ps.running = true
-				ps.phaseStartedAt = time.Now()
+				// A retry restarts the counter; the final Duration arrives with completion.
+				ps.phases[i].StartedAt = time.Now()
+				if ps.startedAt.IsZero() {
+					ps.startedAt = ps.phases[i].StartedAt
+				}
+				ps.phases[i].Duration = 0
 				if ps.autoFollow {
 					ps.cursor = i
 				}
 			case PhasePassed, PhaseFailed, PhaseError:
 				ps.reports[msg.Phase] = &PhaseReport{
-					PhaseName:    msg.Phase,
-					Status:       msg.Status,
-					Summary:      msg.Summary,
-					Feedback:     msg.Feedback,
-					FilesChanged: msg.FilesChanged,
-					Duration:     msg.Duration,
+					PhaseName:        msg.Phase,
+					Status:           msg.Status,
+					Summary:          msg.Summary,
+					Feedback:         msg.Feedback,
+					FilesChanged:     msg.FilesChanged,
+					Duration:         msg.Duration,
+					Usage:            msg.Usage,
+					MissingArtifacts: msg.MissingArtifacts,
 				}
 			}
 			break
@@ -186,11 +263,25 @@ func (ps pipelineState) View(width, height int) string {
 	// Bead header: muted ID + title line above the phase list.
 	if ps.beadID != "" {
 		header := ps.beadID + "  " + ps.beadTitle
+		if ps.queueLabel != "" {
+			header = ps.queueLabel + "  " + header
+		}
 		if ps.provider != "" {
 			header += "  [" + ps.provider + "]"
 		}
+		if !ps.startedAt.IsZero() {
+			header += "  " + formatElapsed(ps.elapsed())
+		}
 		b.WriteString(pipeHeaderStyle.Render(header))
 		b.WriteByte('\n')
+		if !ps.startedAt.IsZero() {
+			b.WriteString(progressbar.Render(ps.fraction, progressbar.Width, progressStyles))
+			b.WriteByte('\n')
+		}
+		if ps.worktreePath != "" {
+			b.WriteString(pipeHeaderStyle.Render(worktreeLine(ps.branch, ps.worktreePath, width)))
+			b.WriteByte('\n')
+		}
 	}
 
 	for i, phase := range ps.phases {
@@ -212,23 +303,39 @@ func (ps pipelineState) View(width, height int) string {
 			name = pipePhaseName(phase.Status, phase.Name)
 		}
 		fmt.Fprintf(&b, "%s %s", indicator, name)
+		if phase.Status == PhaseRunning && ps.pausing && !ps.aborting {
+			fmt.Fprintf(&b, " %s", pausedStyle.Render(SymbolPaused+" pausing"))
+		}
 
 		if phase.Attempt > 1 {
 			fmt.Fprintf(&b, " %s", pipeRetryStyle.Render(fmt.Sprintf("(%d/%d)", phase.Attempt, phase.MaxRetry)))
 		}
 
-		if phase.Status == PhaseRunning && !ps.phaseStartedAt.IsZero() && !ps.aborting {
-			elapsed := int(time.Since(ps.phaseStartedAt).Seconds())
-			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("(%ds)", elapsed)))
-		}
-
-		if phase.Duration > 0 {
-			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("%.1fs", phase.Duration.Seconds())))
+		if t := phase.timing(ps.aborting); t != "" {
+			fmt.Fprintf(&b, " %s", t)
 		}
 	}
 	return b.String()
 }
 
+// worktreeLine renders the branch and worktree path within width columns.
+// A path too long to fit loses its leading directories, keeping the
+// worktree's own name visible; the summary shows it in full.
+func worktreeLine(branch, path string, width int) string {
+	prefix := ""
+	if branch != "" {
+		prefix = branch + "  "
+	}
+	over := ansi.StringWidth(prefix+path) - width
+	if width <= 0 || over <= 0 {
+		return prefix + path
+	}
+	if over >= ansi.StringWidth(path) {
+		return ansi.Truncate(prefix+path, width, "…")
+	}
+	return prefix + ansi.TruncateLeft(path, over+1, "…")
+}
+
 // ViewReport renders the right-pane content for the currently selected phase.
 func (ps pipelineState) ViewReport(width, height int) string {
 	if len(ps.phases) == 0 || ps.cursor < 0 || ps.cursor >= len(ps.phases) {
@@ -248,7 +355,14 @@ func (ps pipelineState) ViewReport(width, height int) string {
 			fmt.Fprintf(&b, "\n%s %s", pipeFailedStyle.Render("⚠"), pipeFailedStyle.Render("Waiting for cleanup..."))
 		} else {
 			fmt.Fprintf(&b, "%s  %s\n", pipeRunningStyle.Render(phase.Name), pipeRunningStyle.Render("Running"))
-			fmt.Fprintf(&b, "\n%s %s", ps.spinner.View(), pipeRunningStyle.Render("In progress..."))
+			activity := "In progress..."
+			if phase.Activity != "" {
+				activity = phase.Activity
+			}
+			fmt.Fprintf(&b, "\n%s %s", ps.spinner.View(), pipeRunningStyle.Render(activity))
+			if ps.pausing {
+				b.WriteString("\n\n" + pausedStyle.Render("Pausing after this phase; enter on the bead resumes it."))
+			}
 		}
 		return b.String()
 
@@ -294,6 +408,10 @@ func (ps pipelineState) formatReport(r *PhaseReport) string {
 		}
 	}
 
+	if len(r.MissingArtifacts) > 0 {
+		fmt.Fprintf(&b, "\n\n%s", pipeFailedStyle.Render(artifactCheckFailed(r.MissingArtifacts)))
+	}
+
 	// Feedback (typically present for failed/error phases).
 	if r.Feedback != "" {
 		fmt.Fprintf(&b, "\n\nFeedback:\n%s", r.Feedback)
@@ -302,6 +420,16 @@ func (ps pipelineState) formatReport(r *PhaseReport) string {
 	return b.String()
 }
 
+// anyRunning reports whether a phase is running.
+func (ps pipelineState) anyRunning() bool {
+	for _, p := range ps.phases {
+		if p.Status == PhaseRunning {
+			return true
+		}
+	}
+	return false
+}
+
 // SelectedPhase returns the name of the phase at the current cursor position,
 // or "" if the list is empty.
 func (ps pipelineState) SelectedPhase() string {
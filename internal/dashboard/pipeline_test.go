@@ -7,10 +7,11 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 )
 
-// elapsedPattern matches elapsed time display like "(42s)" or "(120s)".
-var elapsedPattern = regexp.MustCompile(`\(\d+s\)`)
+// elapsedPattern matches the live elapsed counter, e.g. "00:42" or "12:05".
+var elapsedPattern = regexp.MustCompile(`\d{2}:\d{2}`)
 
 func samplePhaseNames() []string {
 	return []string{"plan", "code", "test", "review"}
@@ -27,8 +28,8 @@ func TestPipeline_InitialState(t *testing.T) {
 	if !ps.autoFollow {
 		t.Error("autoFollow should be true initially")
 	}
-	if ps.running {
-		t.Error("running should be false initially")
+	if ps.anyRunning() {
+		t.Error("anyRunning() should be false initially")
 	}
 	if len(ps.phases) != 4 {
 		t.Errorf("len(phases) = %d, want 4", len(ps.phases))
@@ -402,9 +403,12 @@ func TestPipeline_PhaseUpdateSetsRunning(t *testing.T) {
 	// When: a phase starts running
 	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
 
-	// Then: the running flag is set
-	if !ps.running {
-		t.Error("running should be true after a phase starts")
+	// Then: the pipeline reports a running phase with a start time
+	if !ps.anyRunning() {
+		t.Error("anyRunning() should be true after a phase starts")
+	}
+	if ps.phases[0].StartedAt.IsZero() {
+		t.Error("StartedAt should be set when a phase starts running")
 	}
 }
 
@@ -546,6 +550,25 @@ func TestPipeline_NoReportForPendingPhase(t *testing.T) {
 	}
 }
 
+func TestPipeline_ViewReportRunningProgress(t *testing.T) {
+	// Given: a running phase that reported progress
+	ps := newPipelineState(samplePhaseNames())
+	ps = ps.handlePhaseUpdate(PhaseUpdateMsg{Phase: "code", Status: PhaseRunning, Attempt: 1})
+	ps = ps.handlePhaseUpdate(PhaseUpdateMsg{Phase: "code", Status: PhaseProgress, Message: "using Edit"})
+	ps.cursor = 1
+
+	// When: the report pane is rendered
+	view := stripANSI(ps.ViewReport(60, 20))
+
+	// Then: the phase is still running and its latest message replaces the placeholder
+	if ps.phases[1].Status != PhaseRunning {
+		t.Errorf("status = %q, want running", ps.phases[1].Status)
+	}
+	if !strings.Contains(view, "using Edit") || strings.Contains(view, "In progress...") {
+		t.Errorf("report should show the progress message, got:\n%s", view)
+	}
+}
+
 func TestPipeline_ViewReportPending(t *testing.T) {
 	// Given: a pipeline state with cursor on a pending phase
 	ps := newPipelineState(samplePhaseNames())
@@ -695,8 +718,8 @@ func TestPipeline_PhaseUpdateIgnoresUnknownPhase(t *testing.T) {
 	if ps.cursor != 0 {
 		t.Errorf("cursor = %d, want 0", ps.cursor)
 	}
-	if ps.running {
-		t.Error("running should remain false for unknown phase")
+	if ps.anyRunning() {
+		t.Error("anyRunning() should remain false for unknown phase")
 	}
 }
 
@@ -723,6 +746,39 @@ func TestPipeline_ViewBeadHeader(t *testing.T) {
 	}
 }
 
+func TestPipeline_ViewWorktreeLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{name: "fits", width: 60, want: "capsule-cap-042  /home/dev/repo/.capsule/worktrees/cap-042"},
+		{name: "long path keeps its tail", width: 40, want: "capsule-cap-042  …sule/worktrees/cap-042"},
+		{name: "no room for the path", width: 12, want: "capsule-cap…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a pipeline state whose worktree is known
+			ps := newPipelineState(samplePhaseNames())
+			ps.beadID = "cap-042"
+			ps.beadTitle = "Fix login bug"
+			ps.branch = "capsule-cap-042"
+			ps.worktreePath = "/home/dev/repo/.capsule/worktrees/cap-042"
+
+			// When: the view is rendered
+			lines := strings.Split(stripANSI(ps.View(tt.width, 20)), "\n")
+
+			// Then: the branch and path sit under the header, truncated to fit
+			if len(lines) < 2 || lines[1] != tt.want {
+				t.Errorf("second line = %q, want %q", lines[1], tt.want)
+			}
+			if w := ansi.StringWidth(lines[1]); w > tt.width {
+				t.Errorf("second line is %d columns, want at most %d", w, tt.width)
+			}
+		})
+	}
+}
+
 func TestPipeline_ViewNoBeadHeader_WhenEmpty(t *testing.T) {
 	// Given: a pipeline state with no bead ID
 	ps := newPipelineState(samplePhaseNames())
@@ -743,19 +799,19 @@ func TestPipeline_ViewNoBeadHeader_WhenEmpty(t *testing.T) {
 }
 
 func TestPipeline_ElapsedTimeShownForRunningPhase(t *testing.T) {
-	// Given: a pipeline state with "code" running, started 42 seconds ago
+	// Given: a pipeline state with "code" running, started 8m42s ago
 	ps := newPipelineState(samplePhaseNames())
 	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhasePassed, Duration: time.Second})
 	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "code", Status: PhaseRunning})
-	ps.phaseStartedAt = time.Now().Add(-42 * time.Second)
+	ps.phases[1].StartedAt = time.Now().Add(-(8*time.Minute + 42*time.Second))
 
 	// When: the view is rendered
 	view := ps.View(60, 20)
 	plain := stripANSI(view)
 
-	// Then: the running phase shows elapsed time in parentheses
-	if !strings.Contains(plain, "(42s)") {
-		t.Errorf("running phase should show elapsed time '(42s)', got:\n%s", plain)
+	// Then: the running phase shows a live mm:ss counter
+	if !strings.Contains(plain, "08:42") {
+		t.Errorf("running phase should show elapsed time '08:42', got:\n%s", plain)
 	}
 }
 
@@ -767,7 +823,7 @@ func TestPipeline_ElapsedTimeNotShownForNonRunningPhase(t *testing.T) {
 	view := ps.View(60, 20)
 	plain := stripANSI(view)
 
-	// Then: no elapsed time parenthetical (e.g. "(0s)") is shown
+	// Then: no elapsed counter is shown
 	for _, line := range strings.Split(plain, "\n") {
 		if matched := elapsedPattern.MatchString(line); matched {
 			t.Errorf("pending phases should not show elapsed time, got line: %q", line)
@@ -775,59 +831,103 @@ func TestPipeline_ElapsedTimeNotShownForNonRunningPhase(t *testing.T) {
 	}
 }
 
-func TestPipeline_ElapsedTimeUpdatesOnPhaseRunning(t *testing.T) {
-	// Given: a pipeline state with "plan" running
+func TestPipeline_ElapsedTimeReplacedByDuration(t *testing.T) {
+	// Given: a running phase
 	ps := newPipelineState(samplePhaseNames())
 	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
+	ps.phases[0].StartedAt = time.Now().Add(-5 * time.Second)
+
+	// When: its completion update arrives
+	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhasePassed, Duration: 4500 * time.Millisecond})
+	plain := stripANSI(ps.View(60, 20))
 
-	// Then: phaseStartedAt should be set (approximately now)
-	if ps.phaseStartedAt.IsZero() {
-		t.Error("phaseStartedAt should be set when a phase starts running")
+	// Then: the final duration replaces the live counter
+	if !strings.Contains(plain, "4.5s") || elapsedPattern.MatchString(plain) {
+		t.Errorf("completed phase should show only its duration, got:\n%s", plain)
 	}
 }
 
-func TestPipeline_ElapsedTimeResetsOnNewRunningPhase(t *testing.T) {
-	// Given: a pipeline state where "plan" ran and now "code" is running
+func TestPipeline_ElapsedTimeRestartsPerAttempt(t *testing.T) {
+	// Given: a phase that ran and failed once
 	ps := newPipelineState(samplePhaseNames())
 	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
-	firstStart := ps.phaseStartedAt
+	firstStart := ps.phases[0].StartedAt
 	time.Sleep(2 * time.Millisecond)
-	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhasePassed, Duration: time.Second})
-	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "code", Status: PhaseRunning})
+	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseFailed, Duration: time.Second})
+
+	// When: the retry starts running
+	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning, Attempt: 2, MaxRetry: 3})
 
-	// Then: phaseStartedAt should be reset to a newer time
-	if !ps.phaseStartedAt.After(firstStart) {
-		t.Error("phaseStartedAt should be reset when a new phase starts running")
+	// Then: the counter restarts and the previous duration is cleared
+	if !ps.phases[0].StartedAt.After(firstStart) {
+		t.Error("StartedAt should be reset when the phase runs again")
+	}
+	if ps.phases[0].Duration != 0 {
+		t.Errorf("Duration = %v, want 0 while running", ps.phases[0].Duration)
 	}
 }
 
-func TestPipeline_ElapsedTickMsg(t *testing.T) {
-	// Given: a running pipeline state
+func TestPipeline_HeaderShowsCumulativeElapsed(t *testing.T) {
+	// Given: a pipeline whose first phase started 7 seconds ago
 	ps := newPipelineState(samplePhaseNames())
+	ps.beadID = "cap-042"
+	ps.beadTitle = "Add widget"
 	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
+	ps.startedAt = time.Now().Add(-7 * time.Second)
 
-	// When: an elapsedTickMsg is received
-	ps, cmd := ps.Update(elapsedTickMsg{})
+	// When: the view is rendered
+	header := strings.SplitN(stripANSI(ps.View(60, 20)), "\n", 2)[0]
 
-	// Then: if still running, a follow-up tick command should be returned
-	if !ps.running {
-		t.Error("running should still be true")
+	// Then: the header carries the whole-pipeline counter
+	if !strings.Contains(header, "00:07") {
+		t.Errorf("header should show cumulative elapsed '00:07', got %q", header)
 	}
-	if cmd == nil {
-		t.Error("elapsedTickMsg should produce a follow-up tick command when running")
+}
+
+func TestPipeline_FinishFreezesElapsed(t *testing.T) {
+	// Given: a pipeline that started 3 seconds ago
+	ps := newPipelineState(samplePhaseNames())
+	ps, _ = ps.Update(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
+	ps.startedAt = time.Now().Add(-3 * time.Second)
+
+	// When: the pipeline finishes
+	ps = ps.finish()
+	frozen := ps.elapsed()
+	time.Sleep(2 * time.Millisecond)
+
+	// Then: elapsed no longer advances
+	if ps.elapsed() != frozen {
+		t.Errorf("elapsed() = %v after finish, want frozen %v", ps.elapsed(), frozen)
+	}
+	if frozen < 3*time.Second {
+		t.Errorf("elapsed() = %v, want at least 3s", frozen)
 	}
 }
 
-func TestPipeline_ElapsedTickMsg_NotRunning(t *testing.T) {
-	// Given: a pipeline state that is not running (all pending)
+func TestPipeline_ElapsedZeroBeforeFirstPhase(t *testing.T) {
+	// Given: a pipeline with no phase started
 	ps := newPipelineState(samplePhaseNames())
 
-	// When: an elapsedTickMsg is received
-	_, cmd := ps.Update(elapsedTickMsg{})
+	// Then: elapsed is zero and finish leaves it unset
+	if ps.elapsed() != 0 || !ps.finish().endedAt.IsZero() {
+		t.Error("elapsed should stay zero until a phase runs")
+	}
+}
 
-	// Then: no follow-up tick is returned
-	if cmd != nil {
-		t.Error("elapsedTickMsg should not produce a tick command when not running")
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "00:00"},
+		{59 * time.Second, "00:59"},
+		{8*time.Minute + 5*time.Second, "08:05"},
+		{75 * time.Minute, "75:00"},
+	}
+	for _, tt := range tests {
+		if got := formatElapsed(tt.d); got != tt.want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", tt.d, got, tt.want)
+		}
 	}
 }
 
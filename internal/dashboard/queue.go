@@ -0,0 +1,323 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// queueItem tracks one bead of a queued run and how its run ended.
+type queueItem struct {
+	Dispatch    DispatchMsg
+	Status      CampaignTaskStatus
+	Err         error  // Pipeline error; nil when the run passed.
+	FailedPhase string // First phase that failed, if any.
+	Duration    time.Duration
+	Post        *PostPipelineDoneMsg // Post-pipeline outcome; nil when it did not run.
+}
+
+// queueState runs the beads selected in browse mode one at a time through
+// the pipeline flow. It is active from QueueStartMsg until the user leaves
+// the queue summary.
+type queueState struct {
+	items        []queueItem
+	current      int  // Index of the running bead; -1 once the queue finishes.
+	cursor       int  // Selected row in the queue summary.
+	posting      bool // Post-pipeline lifecycle is running for the current bead.
+	confirmAbort bool // q was pressed; waiting for the user to skip or abort.
+	skipping     bool // The current bead was cancelled to continue with the rest.
+	stopped      bool // The whole queue was aborted; remaining beads do not run.
+}
+
+// newQueueState returns a queueState for beads, with none started yet.
+func newQueueState(beads []DispatchMsg) queueState {
+	items := make([]queueItem, len(beads))
+	for i, b := range beads {
+		items[i] = queueItem{Dispatch: b, Status: CampaignTaskPending}
+	}
+	return queueState{items: items, current: -1}
+}
+
+// running reports whether a queued bead is running or finishing.
+func (q queueState) running() bool {
+	return q.current >= 0 && q.current < len(q.items)
+}
+
+// label returns the "Queue 2/3" header for the running bead.
+func (q queueState) label() string {
+	return fmt.Sprintf("Queue %d/%d", q.current+1, len(q.items))
+}
+
+// passed returns how many queued beads passed.
+func (q queueState) passed() int {
+	n := 0
+	for _, it := range q.items {
+		if it.Status == CampaignTaskPassed {
+			n++
+		}
+	}
+	return n
+}
+
+// handleQueueStart starts a queued run for the beads selected in browse
+// mode, after the dispatch check passes. Nothing starts while another
+// dispatch is still running.
+func (m Model) handleQueueStart(msg QueueStartMsg) (tea.Model, tea.Cmd) {
+	if len(msg.Beads) == 0 || m.runner == nil {
+		return m, nil
+	}
+	if m.cancelPipeline != nil || m.backgroundMode != 0 {
+		m.statusMsg = fmt.Sprintf("%s %s is still running; the queue can start once it finishes", SymbolCross, m.dispatchedBeadID)
+		return m, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+			return statusClearMsg{}
+		})
+	}
+	for i := range msg.Beads {
+		msg.Beads[i].Provider = m.activeProvider
+	}
+	if m.dispatchCheck != nil {
+		check := m.dispatchCheck
+		return m, func() tea.Msg {
+			return queueCheckMsg{Start: msg, Err: check()}
+		}
+	}
+	return m.startQueue(msg)
+}
+
+// startQueue clears the browse selection and runs the first queued bead.
+func (m Model) startQueue(msg QueueStartMsg) (Model, tea.Cmd) {
+	m.queue = newQueueState(msg.Beads)
+	m.browse.selected = make(map[string]bool)
+	return m.startQueuedBead(0)
+}
+
+// startQueuedBead runs the queued bead at i through the pipeline flow. A
+// queue sent to the background stays there.
+func (m Model) startQueuedBead(i int) (Model, tea.Cmd) {
+	background := m.mode == ModeBrowse && m.backgroundMode == ModePipeline
+	item := &m.queue.items[i]
+	item.Status = CampaignTaskRunning
+	m.queue.current = i
+	m.queue.posting = false
+	m.queue.confirmAbort = false
+	m.queue.skipping = false
+	m, cmd := m.startPipeline(item.Dispatch, m.browse.paused[item.Dispatch.BeadID])
+	m.pipeline.queueLabel = m.queue.label()
+	if background {
+		m.backgroundMode = ModePipeline
+		m.mode = ModeBrowse
+	}
+	return m, cmd
+}
+
+// finishQueuedBead records how the current bead's run ended once its event
+// channel closes. A passed bead runs post-pipeline lifecycle before the
+// queue advances.
+func (m Model) finishQueuedBead() (Model, tea.Cmd) {
+	q := &m.queue
+	item := &q.items[q.current]
+	item.Duration = time.Since(m.dispatchedAt)
+	item.Err = m.pipelineErr
+	if m.pipelineOutput != nil {
+		item.FailedPhase = firstFailedPhase(m.pipelineOutput.PhaseReports)
+	}
+	q.confirmAbort = false
+	m.aborting = false
+	switch {
+	case q.skipping || q.stopped:
+		item.Status = CampaignTaskSkipped
+	case m.pipelineSucceeded():
+		item.Status = CampaignTaskPassed
+	default:
+		item.Status = CampaignTaskFailed
+	}
+	if item.Status == CampaignTaskPassed && m.postPipeline != nil {
+		q.posting = true
+		return m, postPipelineCmd(m.postPipeline, m.postPipelineInput())
+	}
+	return m, queueAdvanceCmd(q.current)
+}
+
+// handleQueuedPostDone records the current bead's post-pipeline outcome
+// and advances the queue.
+func (m Model) handleQueuedPostDone(msg PostPipelineDoneMsg) (Model, tea.Cmd) {
+	m.queue.posting = false
+	m.queue.items[m.queue.current].Post = &msg
+	return m, queueAdvanceCmd(m.queue.current)
+}
+
+// queueAdvanceCmd returns a tea.Cmd that reports the queued bead at i done.
+func queueAdvanceCmd(i int) tea.Cmd {
+	return func() tea.Msg { return QueueAdvanceMsg{Index: i} }
+}
+
+// handleQueueAdvance starts the next queued bead, or shows the queue
+// summary once none are left or the queue was aborted.
+func (m Model) handleQueueAdvance(msg QueueAdvanceMsg) (Model, tea.Cmd) {
+	if !m.queue.running() || msg.Index != m.queue.current || m.cancelPipeline != nil {
+		return m, nil
+	}
+	next := m.queue.current + 1
+	if m.queue.stopped || next >= len(m.queue.items) {
+		return m.finishQueue()
+	}
+	return m.startQueuedBead(next)
+}
+
+// finishQueue shows the queue summary. Beads an abort kept from running are
+// marked skipped. A queue in the background reports on the status line and
+// returns to browse instead.
+func (m Model) finishQueue() (Model, tea.Cmd) {
+	for i := range m.queue.items {
+		if m.queue.items[i].Status == CampaignTaskPending {
+			m.queue.items[i].Status = CampaignTaskSkipped
+		}
+	}
+	last := m.queue.items[m.queue.current].Dispatch.BeadID
+	m.queue.current = -1
+	m.dispatchedBeadID = last
+	if m.mode == ModeBrowse && m.backgroundMode != 0 {
+		status := fmt.Sprintf("%s Queue complete: %d/%d passed", SymbolCheck, m.queue.passed(), len(m.queue.items))
+		m.queue = queueState{}
+		m.statusMsg = status
+		m, cmd := m.returnToBrowseFromQueue()
+		return m, tea.Batch(cmd, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+			return statusClearMsg{}
+		}))
+	}
+	m.mode = ModeQueueSummary
+	m.focus = PaneLeft
+	m.queue.cursor = 0
+	return m, nil
+}
+
+// returnToBrowseFromQueue leaves the queue summary for browse mode.
+// Post-pipeline lifecycle already ran for each bead that passed.
+func (m Model) returnToBrowseFromQueue() (Model, tea.Cmd) {
+	m.queue = queueState{}
+	m.backgroundMode = 0
+	return m.returnToBrowse()
+}
+
+// handleQueueAbortKey answers the prompt q opens while a queued bead runs:
+// s cancels the bead and continues with the rest, a aborts the whole queue,
+// and esc or n keeps it running.
+func (m Model) handleQueueAbortKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "s":
+		m.queue.skipping = true
+	case "a", "q", "ctrl+c":
+		m.queue.stopped = true
+	case "esc", "n":
+		m.queue.confirmAbort = false
+		return m, nil
+	default:
+		return m, nil
+	}
+	m.queue.confirmAbort = false
+	if m.cancelPipeline != nil {
+		m.aborting = true
+		m.pipeline.aborting = true
+		m.cancelPipeline()
+	}
+	return m, nil
+}
+
+// queueAbortPrompt is the status line shown while the abort prompt is open.
+func (m Model) queueAbortPrompt() string {
+	remaining := len(m.queue.items) - m.queue.current - 1
+	return fmt.Sprintf("Abort %s? s skips it and runs the %d remaining, a aborts the queue, esc keeps it running",
+		m.dispatchedBeadID, remaining)
+}
+
+// handleQueueSummaryKey moves through the queue summary and returns to
+// browse mode on enter, esc, b, or q.
+func (m Model) handleQueueSummaryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.queue.cursor > 0 {
+			m.queue.cursor--
+		}
+	case "down", "j":
+		if m.queue.cursor < len(m.queue.items)-1 {
+			m.queue.cursor++
+		}
+	case "enter", "esc", "b", "q":
+		return m.returnToBrowseFromQueue()
+	case "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// View renders the queue summary list: one row per bead with its outcome.
+func (q queueState) View(width, height int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Queue  %d/%d passed", q.passed(), len(q.items))
+	for i, it := range q.items {
+		b.WriteByte('\n')
+		if i == q.cursor {
+			b.WriteString(CursorMarker)
+		} else {
+			b.WriteString("  ")
+		}
+		fmt.Fprintf(&b, "%s %s %s", queueIndicator(it.Status), it.Dispatch.BeadID, it.Dispatch.BeadTitle)
+		if it.Duration > 0 {
+			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("%.1fs", it.Duration.Seconds())))
+		}
+	}
+	return b.String()
+}
+
+// queueIndicator returns the status symbol for a queued bead.
+func queueIndicator(status CampaignTaskStatus) string {
+	switch status {
+	case CampaignTaskPassed:
+		return pipePassedStyle.Render(SymbolCheck)
+	case CampaignTaskFailed:
+		return pipeFailedStyle.Render(SymbolCross)
+	case CampaignTaskSkipped:
+		return pipeSkippedStyle.Render(SymbolSkipped)
+	default:
+		return pipePendingStyle.Render(SymbolPending)
+	}
+}
+
+// ViewDetail renders the right pane of the queue summary: the selected
+// bead's result and post-pipeline outcome.
+func (q queueState) ViewDetail() string {
+	if q.cursor < 0 || q.cursor >= len(q.items) {
+		return ""
+	}
+	it := q.items[q.cursor]
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\n", it.Dispatch.BeadID, it.Dispatch.BeadTitle)
+	switch {
+	case it.Status == CampaignTaskPassed:
+		fmt.Fprintf(&b, "\n%s  Pipeline Passed", pipePassedStyle.Render(SymbolCheck))
+	case it.Status == CampaignTaskSkipped && it.Duration == 0:
+		fmt.Fprintf(&b, "\n%s  Not run: the queue was aborted", pipeSkippedStyle.Render(SymbolSkipped))
+	case it.Status == CampaignTaskSkipped:
+		fmt.Fprintf(&b, "\n%s  Aborted", pipeSkippedStyle.Render(SymbolSkipped))
+	case errors.Is(it.Err, ErrPipelinePaused):
+		fmt.Fprintf(&b, "\n%s  Paused; press enter on it in browse to resume", pausedStyle.Render(SymbolPaused))
+	default:
+		fmt.Fprintf(&b, "\n%s  Pipeline Failed", pipeFailedStyle.Render(SymbolCross))
+		if it.FailedPhase != "" {
+			fmt.Fprintf(&b, "\n\nFailed phase: %s", it.FailedPhase)
+		}
+		if it.Err != nil {
+			fmt.Fprintf(&b, "\nError: %s", it.Err)
+		}
+	}
+	if it.Duration > 0 {
+		fmt.Fprintf(&b, "\n\nDuration: %.1fs", it.Duration.Seconds())
+	}
+	if it.Post != nil {
+		b.WriteString("\n\n" + viewPostPipelineResult(*it.Post))
+	}
+	return b.String()
+}
@@ -0,0 +1,330 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// queueBeads returns two tasks and a feature for queue tests.
+func queueBeads() []BeadSummary {
+	return []BeadSummary{
+		{ID: "cap-001", Title: "First task", Priority: 1, Type: "task"},
+		{ID: "cap-002", Title: "A feature", Priority: 2, Type: "feature"},
+		{ID: "cap-003", Title: "Third task", Priority: 3, Type: "bug"},
+	}
+}
+
+// newQueueModel returns a sized model in browse mode listing queueBeads.
+func newQueueModel(t *testing.T, opts ...ModelOption) Model {
+	t.Helper()
+	m := NewModel(opts...)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	updated, _ = m.Update(BeadListMsg{Beads: queueBeads()})
+	return updated.(Model)
+}
+
+// press sends a key to m and returns the updated model and command.
+func press(m Model, k string) (Model, tea.Cmd) {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	switch k {
+	case " ":
+		msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	updated, cmd := m.Update(msg)
+	return updated.(Model), cmd
+}
+
+// finishQueuedRun pumps the running bead's events, then follows the
+// commands its completion returns until the queue advances.
+func finishQueuedRun(t *testing.T, m Model) Model {
+	t.Helper()
+	var cmd tea.Cmd
+	for i := 0; i < 20; i++ {
+		msg := listenForEvents(m.eventCh)()
+		var updated tea.Model
+		updated, cmd = m.Update(msg)
+		m = updated.(Model)
+		if _, ok := msg.(channelClosedMsg); ok {
+			break
+		}
+	}
+	for i := 0; cmd != nil && i < 5; i++ {
+		msg := cmd()
+		var updated tea.Model
+		updated, cmd = m.Update(msg)
+		m = updated.(Model)
+		if _, ok := msg.(QueueAdvanceMsg); ok {
+			return m
+		}
+	}
+	t.Fatal("queue did not advance")
+	return m
+}
+
+func TestBrowse_SpaceSelectsQueueableBeads(t *testing.T) {
+	// Given: a bead list with two tasks and a feature
+	m := newQueueModel(t)
+
+	// When: space is pressed on every row
+	m, _ = press(m, " ")
+	m, _ = press(m, "down")
+	m, _ = press(m, " ")
+	m, _ = press(m, "down")
+	m, _ = press(m, " ")
+
+	// Then: only the tasks are selected, with checkboxes in the tree
+	if !m.browse.selected["cap-001"] || !m.browse.selected["cap-003"] || m.browse.selected["cap-002"] {
+		t.Errorf("selected = %v, want cap-001 and cap-003", m.browse.selected)
+	}
+	view := stripANSI(m.browse.View(100, 20, ""))
+	if !strings.Contains(view, "[x] cap-001") || !strings.Contains(view, "[x] cap-003") {
+		t.Errorf("view should mark selected beads:\n%s", view)
+	}
+	if strings.Contains(view, "] cap-002") {
+		t.Errorf("feature should have no checkbox:\n%s", view)
+	}
+
+	// When: space is pressed again on a selected bead
+	m, _ = press(m, " ")
+
+	// Then: it is deselected
+	if m.browse.selected["cap-003"] {
+		t.Error("second space should deselect cap-003")
+	}
+}
+
+func TestBrowse_EscClearsQueueSelection(t *testing.T) {
+	// Given: a selected bead
+	m := newQueueModel(t)
+	m, _ = press(m, " ")
+
+	// When: esc is pressed with no filter set
+	m, _ = press(m, "esc")
+
+	// Then: the selection is cleared
+	if len(m.browse.selected) != 0 {
+		t.Errorf("selected = %v, want none", m.browse.selected)
+	}
+}
+
+func TestBrowse_EnterWithSelectionStartsQueue(t *testing.T) {
+	// Given: two tasks selected, last one first
+	m := newQueueModel(t)
+	m, _ = press(m, "down")
+	m, _ = press(m, "down")
+	m, _ = press(m, " ")
+	m, _ = press(m, "down")
+	m, _ = press(m, " ")
+
+	// When: enter is pressed
+	_, cmd := press(m, "enter")
+
+	// Then: a QueueStartMsg lists them in tree order
+	start, ok := cmd().(QueueStartMsg)
+	if !ok {
+		t.Fatalf("enter returned %T, want QueueStartMsg", cmd())
+	}
+	var ids []string
+	for _, b := range start.Beads {
+		ids = append(ids, b.BeadID)
+	}
+	if strings.Join(ids, ",") != "cap-001,cap-003" {
+		t.Errorf("queued %v, want [cap-001 cap-003]", ids)
+	}
+	if start.Beads[1].BeadType != "bug" || start.Beads[1].BeadTitle != "Third task" {
+		t.Errorf("queued bead = %+v, want type and title carried", start.Beads[1])
+	}
+}
+
+func TestQueue_RunsBeadsInOrderAndSummarizes(t *testing.T) {
+	// Given: a runner that passes cap-001 and fails cap-003
+	var mu sync.Mutex
+	var ran []string
+	runner := &mockRunner{runFn: func(_ context.Context, in PipelineInput, statusFn func(PhaseUpdateMsg)) (PipelineOutput, error) {
+		mu.Lock()
+		ran = append(ran, in.BeadID+"/"+in.Provider)
+		mu.Unlock()
+		statusFn(PhaseUpdateMsg{Phase: "plan", Status: PhaseRunning})
+		if in.BeadID == "cap-003" {
+			return PipelineOutput{PhaseReports: []PhaseReport{{PhaseName: "plan", Status: PhaseFailed}}}, errors.New("plan failed")
+		}
+		return PipelineOutput{Success: true}, nil
+	}}
+	var posted []string
+	m := newQueueModel(t,
+		WithPipelineRunner(runner),
+		WithPhaseNames([]string{"plan"}),
+		WithProviderNames([]string{"claude"}, "claude"),
+		WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
+			posted = append(posted, in.BeadID)
+			return PostPipelineResult{Merged: true, BeadClosed: true}, nil
+		}),
+	)
+
+	// When: the queue starts
+	updated, _ := m.Update(QueueStartMsg{Beads: []DispatchMsg{
+		{BeadID: "cap-001", BeadTitle: "First task", BeadType: "task"},
+		{BeadID: "cap-003", BeadTitle: "Third task", BeadType: "bug"},
+	}})
+	m = updated.(Model)
+
+	// Then: the first bead runs with the queue position in the header
+	if m.mode != ModePipeline || m.dispatchedBeadID != "cap-001" {
+		t.Fatalf("mode = %d, bead = %q; want pipeline for cap-001", m.mode, m.dispatchedBeadID)
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "Queue 1/2  cap-001") {
+		t.Errorf("header should show queue position:\n%s", view)
+	}
+
+	// When: the first bead finishes
+	m = finishQueuedRun(t, m)
+
+	// Then: the second bead starts
+	if m.dispatchedBeadID != "cap-003" || m.pipeline.queueLabel != "Queue 2/2" {
+		t.Fatalf("bead = %q, label = %q; want cap-003 at Queue 2/2", m.dispatchedBeadID, m.pipeline.queueLabel)
+	}
+
+	// When: the second bead finishes
+	m = finishQueuedRun(t, m)
+
+	// Then: the queue summary lists both results
+	if m.mode != ModeQueueSummary {
+		t.Fatalf("mode = %d, want ModeQueueSummary", m.mode)
+	}
+	if strings.Join(ran, ",") != "cap-001/claude,cap-003/claude" {
+		t.Errorf("ran %v, want both beads in order with the active provider", ran)
+	}
+	if strings.Join(posted, ",") != "cap-001" {
+		t.Errorf("post-pipeline ran for %v, want only the passing cap-001", posted)
+	}
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "Queue  1/2 passed") {
+		t.Errorf("summary should count passes:\n%s", view)
+	}
+	if !strings.Contains(view, "merged to main") {
+		t.Errorf("summary should show cap-001's post-pipeline outcome:\n%s", view)
+	}
+
+	// When: the cursor moves to the failed bead
+	m, _ = press(m, "down")
+
+	// Then: its failed phase and error are shown
+	detail := stripANSI(m.queue.ViewDetail())
+	if !strings.Contains(detail, "Failed phase: plan") || !strings.Contains(detail, "plan failed") {
+		t.Errorf("detail should describe the failure:\n%s", detail)
+	}
+
+	// When: enter is pressed
+	m, _ = press(m, "enter")
+
+	// Then: browse mode returns with the queue cleared
+	if m.mode != ModeBrowse || m.queue.running() || len(m.queue.items) != 0 {
+		t.Errorf("mode = %d, queue = %+v; want browse with no queue", m.mode, m.queue)
+	}
+}
+
+func TestQueue_IgnoresStaleAdvance(t *testing.T) {
+	// Given: a queue whose second bead is running
+	m := newQueueModel(t, WithPipelineRunner(&mockRunner{output: PipelineOutput{Success: true}}), WithPhaseNames([]string{"plan"}))
+	updated, _ := m.Update(QueueStartMsg{Beads: []DispatchMsg{{BeadID: "cap-001"}, {BeadID: "cap-003"}}})
+	m = finishQueuedRun(t, updated.(Model))
+
+	// When: a second advance for the first bead arrives
+	updated, _ = m.Update(QueueAdvanceMsg{Index: 0})
+	m = updated.(Model)
+
+	// Then: the running bead is unchanged
+	if m.queue.current != 1 || m.dispatchedBeadID != "cap-003" {
+		t.Errorf("current = %d, bead = %q; want the second bead still running", m.queue.current, m.dispatchedBeadID)
+	}
+}
+
+func TestQueue_AbortPrompt(t *testing.T) {
+	// blockingRunner runs until cancelled.
+	blockingRunner := &mockRunner{runFn: func(ctx context.Context, _ PipelineInput, _ func(PhaseUpdateMsg)) (PipelineOutput, error) {
+		<-ctx.Done()
+		return PipelineOutput{}, ctx.Err()
+	}}
+	three := []DispatchMsg{{BeadID: "cap-001"}, {BeadID: "cap-003"}, {BeadID: "cap-004"}}
+
+	tests := []struct {
+		name        string
+		key         string
+		wantMode    Mode
+		wantBead    string
+		wantSkipped []int
+	}{
+		{name: "skip continues with the next bead", key: "s", wantMode: ModePipeline, wantBead: "cap-003", wantSkipped: []int{0}},
+		{name: "abort stops the queue", key: "a", wantMode: ModeQueueSummary, wantSkipped: []int{0, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a queue whose first bead is running
+			m := newQueueModel(t, WithPipelineRunner(blockingRunner), WithPhaseNames([]string{"plan"}))
+			updated, _ := m.Update(QueueStartMsg{Beads: three})
+			m = updated.(Model)
+
+			// When: q is pressed
+			m, _ = press(m, "q")
+
+			// Then: the prompt opens without cancelling the bead
+			if !m.queue.confirmAbort || m.aborting {
+				t.Fatalf("confirmAbort = %v, aborting = %v; want the prompt only", m.queue.confirmAbort, m.aborting)
+			}
+			if view := stripANSI(m.View()); !strings.Contains(view, "s skips it and runs the 2 remaining") {
+				t.Errorf("view should show the prompt:\n%s", view)
+			}
+
+			// When: the user answers
+			m, _ = press(m, tt.key)
+			m = finishQueuedRun(t, m)
+
+			// Then: the queue continues or stops as chosen
+			if m.mode != tt.wantMode {
+				t.Fatalf("mode = %d, want %d", m.mode, tt.wantMode)
+			}
+			if tt.wantBead != "" && m.dispatchedBeadID != tt.wantBead {
+				t.Errorf("running %q, want %q", m.dispatchedBeadID, tt.wantBead)
+			}
+			for _, i := range tt.wantSkipped {
+				if got := m.queue.items[i].Status; got != CampaignTaskSkipped {
+					t.Errorf("item %d status = %q, want skipped", i, got)
+				}
+			}
+			if m.cancelPipeline != nil {
+				m.cancelPipeline()
+			}
+		})
+	}
+}
+
+func TestQueue_EscKeepsRunning(t *testing.T) {
+	// Given: the abort prompt open over a running queued bead
+	var cancelled bool
+	m := newSizedModel(90, 40)
+	m.mode = ModePipeline
+	m.queue = newQueueState([]DispatchMsg{{BeadID: "cap-001"}, {BeadID: "cap-003"}})
+	m.queue.current = 0
+	m.queue.confirmAbort = true
+	m.cancelPipeline = func() { cancelled = true }
+
+	// When: esc is pressed
+	m, _ = press(m, "esc")
+
+	// Then: the prompt closes and the bead keeps running
+	if m.queue.confirmAbort || cancelled || m.mode != ModePipeline {
+		t.Errorf("confirmAbort = %v, cancelled = %v, mode = %d; want the bead still running", m.queue.confirmAbort, cancelled, m.mode)
+	}
+}
@@ -0,0 +1,115 @@
+package dashboard
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// refreshTickInterval is how often the auto-refresh tick fires. It is
+// shorter than any useful refresh interval so the "refreshed Ns ago"
+// indicator stays current between reloads.
+const refreshTickInterval = time.Second
+
+// refreshTickMsg drives auto-refresh; see WithRefreshInterval.
+type refreshTickMsg struct{}
+
+// beadsRefreshedMsg carries the result of a background bead list reload.
+// Unlike BeadListMsg, an error leaves the current list in place.
+type beadsRefreshedMsg struct {
+	Beads []BeadSummary
+	Err   error
+}
+
+// WithRefreshInterval reloads the bead list every d while browse mode is in
+// the foreground. The cursor stays on the selected bead and its detail is
+// kept. Zero, the default, disables auto-refresh.
+func WithRefreshInterval(d time.Duration) ModelOption {
+	return func(m *Model) { m.refreshInterval = d }
+}
+
+// refreshTickCmd returns a tea.Cmd that fires a refreshTickMsg after refreshTickInterval.
+func refreshTickCmd() tea.Cmd {
+	return tea.Tick(refreshTickInterval, func(time.Time) tea.Msg {
+		return refreshTickMsg{}
+	})
+}
+
+// autoRefreshCmd fetches the bead list like initBrowse, but wraps the result
+// in a beadsRefreshedMsg so it is applied without a loading state.
+func autoRefreshCmd(lister BeadLister) tea.Cmd {
+	load := initBrowse(lister)
+	return func() tea.Msg {
+		msg, _ := load().(BeadListMsg)
+		return beadsRefreshedMsg(msg)
+	}
+}
+
+// autoRefreshEnabled reports whether the bead list reloads on a timer.
+func (m Model) autoRefreshEnabled() bool {
+	return m.refreshInterval > 0 && m.lister != nil
+}
+
+// markRefreshed records the outcome of a bead list load and schedules the
+// next automatic reload one interval later.
+func (m Model) markRefreshed(err error) Model {
+	now := time.Now()
+	if err == nil {
+		m.refreshedAt = now
+	}
+	m.refreshErr = err
+	m.refreshDue = now.Add(m.refreshInterval)
+	return m
+}
+
+// handleRefreshTick starts a background reload when one is due. Reloads run
+// only in browse mode with no load in flight, so they never touch pipeline
+// or campaign state.
+func (m Model) handleRefreshTick() (Model, tea.Cmd) {
+	if !m.autoRefreshEnabled() {
+		return m, nil
+	}
+	if m.refreshing || m.mode != ModeBrowse || m.browse.loading || time.Now().Before(m.refreshDue) {
+		return m, refreshTickCmd()
+	}
+	m.refreshing = true
+	if m.beadCache != nil {
+		m.beadCache.InvalidateLists()
+	}
+	return m, tea.Batch(refreshTickCmd(), autoRefreshCmd(m.lister))
+}
+
+// handleBeadsRefreshed applies a background reload. The cursor follows the
+// selected bead by ID, and the detail pane is kept while that bead is still
+// listed. A failed reload keeps the current list and is reported in the help bar.
+func (m Model) handleBeadsRefreshed(msg beadsRefreshedMsg) (Model, tea.Cmd) {
+	m.refreshing = false
+	m = m.markRefreshed(msg.Err)
+	if msg.Err != nil {
+		return m, nil
+	}
+	if m.mode != ModeBrowse || m.browse.loading {
+		return m, nil // The user left browse mode or a manual reload superseded this one.
+	}
+	selected := m.browse.SelectedID()
+	m.browse = m.browse.applyBeadList(msg.Beads, nil)
+	m.browse = m.browse.selectID(selected)
+	m.cache.Invalidate()
+	return m.maybeResolve()
+}
+
+// refreshIndicator returns the auto-refresh status for the help bar, or ""
+// when auto-refresh is off or nothing has loaded yet.
+func (m Model) refreshIndicator() string {
+	if !m.autoRefreshEnabled() {
+		return ""
+	}
+	if m.refreshErr != nil {
+		return fmt.Sprintf("refresh failed: %s", m.refreshErr)
+	}
+	if m.refreshedAt.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("refreshed %s ago", time.Since(m.refreshedAt).Truncate(time.Second))
+}
@@ -0,0 +1,277 @@
+package dashboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newRefreshingModel returns a sized browse model with auto-refresh enabled
+// and beads already loaded.
+func newRefreshingModel(t *testing.T, beads []BeadSummary) Model {
+	t.Helper()
+	m := NewModel(WithBeadLister(&stubLister{beads: beads}), WithRefreshInterval(10*time.Second))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	updated, _ = updated.(Model).Update(BeadListMsg{Beads: beads})
+	return updated.(Model)
+}
+
+func TestRefreshTick_StartsReloadOnlyWhenDue(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m Model) Model
+		wantReload bool
+	}{
+		{
+			name:       "due in browse mode",
+			setup:      func(m Model) Model { m.refreshDue = time.Now().Add(-time.Second); return m },
+			wantReload: true,
+		},
+		{
+			name:  "not yet due",
+			setup: func(m Model) Model { return m },
+		},
+		{
+			name: "pipeline in the foreground",
+			setup: func(m Model) Model {
+				m.refreshDue = time.Now().Add(-time.Second)
+				m.mode = ModePipeline
+				return m
+			},
+		},
+		{
+			name: "manual reload in flight",
+			setup: func(m Model) Model {
+				m.refreshDue = time.Now().Add(-time.Second)
+				m.browse.loading = true
+				return m
+			},
+		},
+		{
+			name: "background reload in flight",
+			setup: func(m Model) Model {
+				m.refreshDue = time.Now().Add(-time.Second)
+				m.refreshing = true
+				return m
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a model with auto-refresh enabled
+			m := tt.setup(newRefreshingModel(t, sampleBeads()))
+			wasRefreshing := m.refreshing
+
+			// When the refresh tick fires
+			updated, cmd := m.Update(refreshTickMsg{})
+			m = updated.(Model)
+
+			// Then a reload starts only when due, and the tick chain continues
+			if started := m.refreshing && !wasRefreshing; started != tt.wantReload {
+				t.Errorf("reload started = %v, want %v", started, tt.wantReload)
+			}
+			if cmd == nil {
+				t.Error("expected the next tick to be scheduled")
+			}
+		})
+	}
+}
+
+func TestRefreshTick_DisabledByDefault(t *testing.T) {
+	// Given a model without a refresh interval
+	m := NewModel(WithBeadLister(&stubLister{beads: sampleBeads()}))
+
+	// When a refresh tick arrives
+	_, cmd := m.Update(refreshTickMsg{})
+
+	// Then nothing is scheduled
+	if cmd != nil {
+		t.Error("expected no command when auto-refresh is off")
+	}
+}
+
+func TestBeadsRefreshed_KeepsCursorOnSelectedBead(t *testing.T) {
+	// Given the cursor on cap-003 with its detail shown
+	m := newRefreshingModel(t, sampleBeads())
+	m.browse = m.browse.selectID("cap-003")
+	m.detailID = "cap-003"
+	m.viewport.SetContent("cap-003 detail")
+	m.refreshing = true
+
+	// When a background reload drops cap-001
+	updated, _ := m.Update(beadsRefreshedMsg{Beads: sampleBeads()[1:]})
+	m = updated.(Model)
+
+	// Then the cursor follows cap-003 and its detail is kept
+	if got := m.browse.SelectedID(); got != "cap-003" {
+		t.Errorf("selected = %q, want cap-003", got)
+	}
+	if m.detailID != "cap-003" || !containsText(m.viewport.View(), "cap-003 detail") {
+		t.Errorf("detail = %q, want cap-003 kept", m.detailID)
+	}
+	if m.refreshing || m.refreshErr != nil {
+		t.Errorf("refreshing = %v, err = %v; want idle without error", m.refreshing, m.refreshErr)
+	}
+}
+
+func TestBeadsRefreshed_SelectedBeadGone(t *testing.T) {
+	// Given the cursor on the last bead
+	m := newRefreshingModel(t, sampleBeads())
+	m.browse = m.browse.selectID("cap-003")
+	m.detailID = "cap-003"
+
+	// When a background reload no longer lists it
+	updated, _ := m.Update(beadsRefreshedMsg{Beads: sampleBeads()[:2]})
+	m = updated.(Model)
+
+	// Then the cursor stays in range and the detail follows the new selection
+	if got := m.browse.SelectedID(); got == "cap-003" || got == "" {
+		t.Errorf("selected = %q, want a remaining bead", got)
+	}
+	if m.detailID != m.browse.SelectedID() {
+		t.Errorf("detailID = %q, want %q", m.detailID, m.browse.SelectedID())
+	}
+}
+
+func TestBeadsRefreshed_ErrorKeepsList(t *testing.T) {
+	// Given a loaded list
+	m := newRefreshingModel(t, sampleBeads())
+	m.refreshing = true
+
+	// When a background reload fails
+	updated, _ := m.Update(beadsRefreshedMsg{Err: errors.New("bd: database locked")})
+	m = updated.(Model)
+
+	// Then the list is untouched and the failure shows in the help bar
+	if len(m.browse.flatNodes) != 3 || m.browse.err != nil {
+		t.Errorf("list replaced: %d nodes, err %v", len(m.browse.flatNodes), m.browse.err)
+	}
+	if !containsPlainText(m.View(), "refresh failed: bd: database locked") {
+		t.Errorf("view missing refresh failure:\n%s", m.View())
+	}
+	if !m.refreshDue.After(time.Now()) {
+		t.Error("expected the next reload to wait a full interval")
+	}
+}
+
+func TestBeadsRefreshed_IgnoredOutsideBrowse(t *testing.T) {
+	// Given a pipeline dispatched while a background reload was in flight
+	m := newRefreshingModel(t, sampleBeads())
+	m.refreshing = true
+	m.mode = ModePipeline
+
+	// When the reload returns
+	updated, _ := m.Update(beadsRefreshedMsg{Beads: sampleBeads()[:1]})
+	m = updated.(Model)
+
+	// Then the browse list is not touched
+	if len(m.browse.flatNodes) != 3 {
+		t.Errorf("flatNodes = %d, want 3", len(m.browse.flatNodes))
+	}
+}
+
+func TestView_RefreshIndicator(t *testing.T) {
+	// Given a model that loaded beads a few seconds ago
+	m := newRefreshingModel(t, sampleBeads())
+	m.refreshedAt = time.Now().Add(-3 * time.Second)
+
+	// When the view renders
+	view := m.View()
+
+	// Then the help bar shows when the list was refreshed
+	if !containsPlainText(view, "refreshed 3s ago") {
+		t.Errorf("view missing refresh indicator:\n%s", view)
+	}
+}
+
+func TestAutoRefreshCmd_WrapsBeadList(t *testing.T) {
+	// Given a lister with ready and closed beads
+	lister := &stubLister{beads: sampleBeads(), closedBeads: []BeadSummary{{ID: "cap-009", Title: "Done"}}}
+
+	// When the background reload runs
+	msg := autoRefreshCmd(lister)()
+
+	// Then it carries the merged list
+	got, ok := msg.(beadsRefreshedMsg)
+	if !ok {
+		t.Fatalf("msg = %T, want beadsRefreshedMsg", msg)
+	}
+	if len(got.Beads) != 4 || got.Err != nil {
+		t.Errorf("beads = %d, err = %v; want 4, nil", len(got.Beads), got.Err)
+	}
+}
+
+// fakeBeadCache records invalidations and reports fixed stats.
+type fakeBeadCache struct {
+	all, lists int
+}
+
+func (f *fakeBeadCache) Invalidate()                 { f.all++ }
+func (f *fakeBeadCache) InvalidateLists()            { f.lists++ }
+func (f *fakeBeadCache) Stats() (hits, misses int64) { return 7, 3 }
+
+func TestBeadCache_Invalidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       tea.Msg
+		setup     func(m Model) Model
+		wantAll   int
+		wantLists int
+	}{
+		{name: "manual reload drops everything", msg: RefreshBeadsMsg{}, wantAll: 1},
+		{
+			name:      "auto refresh drops only the lists",
+			msg:       refreshTickMsg{},
+			setup:     func(m Model) Model { m.refreshDue = time.Now().Add(-time.Second); return m },
+			wantLists: 1,
+		},
+		{name: "auto refresh not yet due keeps the cache", msg: refreshTickMsg{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a refreshing model backed by a bd cache
+			bc := &fakeBeadCache{}
+			m := newRefreshingModel(t, sampleBeads())
+			m.beadCache = bc
+			if tt.setup != nil {
+				m = tt.setup(m)
+			}
+
+			// When the message arrives
+			m.Update(tt.msg)
+
+			// Then the cache is cleared as far as the reload needs
+			if bc.all != tt.wantAll || bc.lists != tt.wantLists {
+				t.Errorf("Invalidate/InvalidateLists calls = %d/%d, want %d/%d", bc.all, bc.lists, tt.wantAll, tt.wantLists)
+			}
+		})
+	}
+}
+
+func TestView_BeadCacheStatsToggle(t *testing.T) {
+	// Given a browse model backed by a bd cache
+	m := NewModel(WithBeadLister(&stubLister{beads: sampleBeads()}), WithBeadCache(&fakeBeadCache{}))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	updated, _ = updated.(Model).Update(BeadListMsg{Beads: sampleBeads()})
+	m = updated.(Model)
+	if strings.Contains(m.View(), "bd cache:") {
+		t.Fatal("cache stats shown before toggling")
+	}
+
+	// When D is pressed, and pressed again
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	shown := updated.(Model).View()
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	hidden := updated.(Model).View()
+
+	// Then the footer shows the counters, then hides them
+	if !strings.Contains(shown, "bd cache: 7 hits · 3 misses") {
+		t.Errorf("view after D missing cache stats:\n%s", shown)
+	}
+	if strings.Contains(hidden, "bd cache:") {
+		t.Error("cache stats still shown after second D")
+	}
+}
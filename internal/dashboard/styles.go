@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/smileynet/capsule/internal/progressbar"
 )
 
 // MinLeftWidth is the minimum character width for the left pane.
@@ -17,6 +19,10 @@ const (
 	SymbolCheck    = "✓"
 	SymbolCross    = "✗"
 	SymbolSkipped  = "–"
+	SymbolPaused   = "⏸"
+
+	SymbolInProgress = "▶"
+	SymbolBlocked    = "⛔"
 )
 
 // --- Semantic color palette (ANSI named colors 0-15 for theme compliance) ---
@@ -38,8 +44,13 @@ var (
 	errorStyle   = lipgloss.NewStyle().Foreground(colorError)
 	dimStyle     = lipgloss.NewStyle().Foreground(colorDim)
 	metaStyle    = lipgloss.NewStyle().Foreground(colorMeta)
+	pausedStyle  = lipgloss.NewStyle().Foreground(colorWarning)
+	blockedStyle = lipgloss.NewStyle().Foreground(colorWarning)
 )
 
+// progressStyles colors header progress bars.
+var progressStyles = progressbar.Styles{Filled: successStyle, Empty: dimStyle, Percent: metaStyle}
+
 // Priority badge colors indexed by priority level (0-4).
 // P0=red, P1=yellow, P2=yellow, P3=blue, P4=gray.
 var priorityColors = [5]lipgloss.AdaptiveColor{
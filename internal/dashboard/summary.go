@@ -1,6 +1,7 @@
 package dashboard
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -25,8 +26,7 @@ func (m Model) viewSummaryRight() string {
 
 	var b strings.Builder
 
-	success := m.pipelineErr == nil && (m.pipelineOutput == nil || m.pipelineOutput.Success)
-	if success {
+	if m.pipelineSucceeded() {
 		fmt.Fprintf(&b, "%s  Pipeline Passed\n", pipePassedStyle.Render(SymbolCheck))
 		fmt.Fprintf(&b, "\n%d/%d phases passed in %.1fs", passed, total, totalDuration.Seconds())
 	} else {
@@ -35,11 +35,38 @@ func (m Model) viewSummaryRight() string {
 			fmt.Fprintf(&b, "\nError: %s", m.pipelineErr)
 		}
 		fmt.Fprintf(&b, "\n\n%d/%d phases passed", passed, total)
+		if m.pipelineOutput != nil {
+			b.WriteString(formatPhaseOutcomes(m.pipelineOutput.PhaseReports))
+		}
+	}
+	if !m.pipeline.usage.IsZero() {
+		fmt.Fprintf(&b, "\nUsage: %s", m.pipeline.usage)
+		b.WriteString(formatPhaseUsage(m.pipeline.phases))
+	}
+	if m.pipelineOutput != nil && len(m.pipelineOutput.Findings) > 0 {
+		b.WriteString("\n\nFindings:")
+		for _, f := range m.pipelineOutput.Findings {
+			fmt.Fprintf(&b, "\n  [%s] %s", f.Severity, f.Title)
+		}
+	}
+
+	if m.pipeline.worktreePath != "" {
+		fmt.Fprintf(&b, "\n\nWorktree: %s", m.pipeline.worktreePath)
+		if m.pipeline.branch != "" {
+			fmt.Fprintf(&b, "\nBranch: %s", m.pipeline.branch)
+		}
 	}
 
-	// "Next:" action text.
-	if m.postPipeline != nil {
-		b.WriteString("\n\nNext: merge to main, close bead, cleanup worktree")
+	// Post-pipeline lifecycle outcome.
+	switch {
+	case m.postRunning:
+		b.WriteString("\n\nMerging to main, closing bead, cleaning up worktree...")
+	case m.postDone != nil:
+		b.WriteString("\n\n" + viewPostPipelineResult(*m.postDone))
+	}
+
+	if m.canResume() {
+		b.WriteString("\n\nNext: press r to retry from the failed phase, or return to browse")
 	} else {
 		b.WriteString("\n\nNext: return to browse")
 	}
@@ -47,6 +74,151 @@ func (m Model) viewSummaryRight() string {
 	return b.String()
 }
 
+// formatPhaseOutcomes lists each phase that ran with its final outcome, in
+// run order, so a failure shows the phases that passed before it. A retried
+// phase is listed once, with its last attempt.
+func formatPhaseOutcomes(reports []PhaseReport) string {
+	last := make(map[string]PhaseReport, len(reports))
+	var order []string
+	for _, r := range reports {
+		if _, seen := last[r.PhaseName]; !seen {
+			order = append(order, r.PhaseName)
+		}
+		last[r.PhaseName] = r
+	}
+	var b strings.Builder
+	for _, name := range order {
+		r := last[name]
+		fmt.Fprintf(&b, "\n  %s %s", pipeIndicator(r.Status, ""), r.PhaseName)
+		if r.Duration > 0 {
+			fmt.Fprintf(&b, " %s", pipeDurationStyle.Render(fmt.Sprintf("%.1fs", r.Duration.Seconds())))
+		}
+	}
+	return b.String()
+}
+
+// formatPhaseUsage lists the usage of each phase that reported any, summed
+// over its attempts, in pipeline order.
+func formatPhaseUsage(phases []phaseEntry) string {
+	var b strings.Builder
+	for _, p := range phases {
+		if !p.Usage.IsZero() {
+			fmt.Fprintf(&b, "\n  %s: %s", p.Name, p.Usage)
+		}
+	}
+	return b.String()
+}
+
+// pipelineSucceeded reports whether the summary's pipeline run passed.
+func (m Model) pipelineSucceeded() bool {
+	return m.pipelineErr == nil && (m.pipelineOutput == nil || m.pipelineOutput.Success)
+}
+
+// canResume reports whether the summary's failed run can be resumed from
+// its checkpoint.
+func (m Model) canResume() bool {
+	return m.resumeEnabled && m.runner != nil && m.mode == ModeSummary &&
+		m.dispatchedBeadID != "" && !m.pipelineSucceeded()
+}
+
+// handleResumeDispatch re-runs the summary's bead from its checkpoint:
+// passed phases are reported done immediately and the failed phase reruns
+// in the existing worktree with its feedback.
+func (m Model) handleResumeDispatch() (tea.Model, tea.Cmd) {
+	return m.startPipeline(DispatchMsg{
+		BeadID:    m.dispatchedBeadID,
+		BeadTitle: m.pipeline.beadTitle,
+		Provider:  m.pipeline.provider,
+	}, true)
+}
+
+// viewPostPipelineResult renders a post-pipeline outcome line followed by
+// the lifecycle's own output, which carries conflict resolution steps.
+func viewPostPipelineResult(msg PostPipelineDoneMsg) string {
+	ok, outcome := postPipelineOutcome(msg)
+	symbol := pipePassedStyle.Render(SymbolCheck)
+	if !ok {
+		symbol = pipeFailedStyle.Render(SymbolCross)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s", symbol, outcome)
+	if len(msg.Result.Messages) > 0 {
+		b.WriteString("\n")
+		for _, line := range msg.Result.Messages {
+			b.WriteString("\n" + line)
+		}
+	}
+	return b.String()
+}
+
+// postPipelineStatus formats a post-pipeline outcome as a status line.
+func postPipelineStatus(msg PostPipelineDoneMsg) string {
+	ok, outcome := postPipelineOutcome(msg)
+	symbol := SymbolCheck
+	if !ok {
+		symbol = SymbolCross
+	}
+	return fmt.Sprintf("%s %s: %s", symbol, msg.BeadID, outcome)
+}
+
+// postPipelineOutcome summarizes a post-pipeline result in one line.
+// ok is false when the branch did not reach main.
+func postPipelineOutcome(msg PostPipelineDoneMsg) (ok bool, outcome string) {
+	r := msg.Result
+	switch {
+	case msg.Err != nil:
+		return false, fmt.Sprintf("post-pipeline failed: %s", msg.Err)
+	case r.MergeConflict:
+		return false, "merge conflict, branch left for manual resolution"
+	case !r.Merged:
+		return false, "merge failed"
+	}
+	parts := []string{"merged to main"}
+	if r.BeadClosed {
+		parts = append(parts, "bead closed")
+	} else {
+		parts = append(parts, "bead not closed")
+	}
+	if r.BranchCleaned {
+		parts = append(parts, "worktree removed")
+	} else {
+		parts = append(parts, "worktree not removed")
+	}
+	return true, strings.Join(parts, ", ")
+}
+
+// startPostPipeline fires post-pipeline lifecycle for the bead whose summary
+// is being shown. Skipped for failed pipelines, since merge/close/cleanup
+// should only run on success.
+func (m Model) startPostPipeline() (Model, tea.Cmd) {
+	success := m.pipelineErr == nil && (m.pipelineOutput == nil || m.pipelineOutput.Success)
+	if m.postPipeline == nil || m.dispatchedBeadID == "" || !success {
+		return m, nil
+	}
+	m.postRunning = true
+	m.postDone = nil
+	return m, postPipelineCmd(m.postPipeline, m.postPipelineInput())
+}
+
+// postPipelineInput describes the dispatched bead and its pipeline outcome
+// for post-pipeline lifecycle.
+func (m Model) postPipelineInput() PostPipelineInput {
+	var reports []PhaseReport
+	if m.pipelineOutput != nil {
+		reports = m.pipelineOutput.PhaseReports
+	}
+	return newPostPipelineInput(m.dispatchedBeadID, m.pipeline.beadTitle, m.pipeline.beadType, reports)
+}
+
+// postPipelineCmd returns a tea.Cmd that runs fn for in and reports the
+// outcome as a PostPipelineDoneMsg.
+func postPipelineCmd(fn PostPipelineFunc, in PostPipelineInput) tea.Cmd {
+	return func() tea.Msg {
+		result, err := fn(in)
+		return PostPipelineDoneMsg{BeadID: in.BeadID, Result: result, Err: err}
+	}
+}
+
 // returnToBrowseAfterAbort transitions from pipeline mode to browse mode
 // after an abort. Unlike returnToBrowse, it skips post-pipeline lifecycle
 // and sticky cursor restore since the pipeline was cancelled.
@@ -56,8 +228,9 @@ func (m Model) returnToBrowseAfterAbort() (Model, tea.Cmd) {
 	m.aborting = false
 	m.backgroundMode = 0
 	m.dispatchedBeadID = ""
-	m.cache.Invalidate()
+	m.invalidateBeads()
 	m.pendingResolveID = ""
+	m = m.clearWorktree()
 
 	if m.lister != nil {
 		return m, tea.Batch(initBrowse(m.lister), m.browseSpinner.Tick)
@@ -71,7 +244,7 @@ func (m Model) returnToBrowseFromCampaign() (Model, tea.Cmd) {
 	m.mode = ModeBrowse
 	m.focus = PaneLeft
 	m.backgroundMode = 0
-	m.cache.Invalidate()
+	m.invalidateBeads()
 	m.pendingResolveID = ""
 	m.lastDispatchedID = m.dispatchedBeadID
 	m.campaignDone = nil
@@ -93,6 +266,17 @@ func (m Model) viewCampaignSummaryRight() string {
 	var b strings.Builder
 
 	switch {
+	case m.campaign.circuitBroken != nil:
+		cbm := m.campaign.circuitBroken
+		fmt.Fprintf(&b, "%s  Campaign Stopped\n", pipeFailedStyle.Render(SymbolCross))
+		fmt.Fprintf(&b, "\nCircuit breaker: %s", cbm.Reason)
+		fmt.Fprintf(&b, "\nFailures: %d provider/setup, %d NEEDS_WORK/ERROR", cbm.SetupFailures, cbm.SignalFailures)
+		if len(cbm.FailedIDs) > 0 {
+			fmt.Fprintf(&b, "\nFailed tasks: %s", strings.Join(cbm.FailedIDs, ", "))
+		}
+		if done.TotalTasks > 0 {
+			fmt.Fprintf(&b, "\n\n%d/%d tasks passed", done.Passed, done.TotalTasks)
+		}
 	case m.campaignErr != nil:
 		fmt.Fprintf(&b, "%s  Campaign Error\n", pipeFailedStyle.Render(SymbolCross))
 		fmt.Fprintf(&b, "\nError: %s", m.campaignErr)
@@ -115,43 +299,235 @@ func (m Model) viewCampaignSummaryRight() string {
 		if vr.Success {
 			fmt.Fprintf(&b, "\n%s Feature validation passed", pipePassedStyle.Render(SymbolCheck))
 		} else {
-			fmt.Fprintf(&b, "\n%s Feature validation failed", pipeFailedStyle.Render(SymbolCross))
+			fmt.Fprintf(&b, "\n%s Feature validation failed (%s left open)", pipeFailedStyle.Render(SymbolCross), done.ParentID)
+			if vr.Error != "" {
+				fmt.Fprintf(&b, "\n  %s", vr.Error)
+			}
 		}
 	}
 
+	switch {
+	case m.campaign.parentClosed:
+		fmt.Fprintf(&b, "\n%s Closed %s", pipePassedStyle.Render(SymbolCheck), done.ParentID)
+	case done.ParentOpen != "":
+		fmt.Fprintf(&b, "\n%s left open: %s", done.ParentID, done.ParentOpen)
+	}
+	if !done.Usage.IsZero() {
+		fmt.Fprintf(&b, "\nUsage: %s", done.Usage)
+	}
+
+	if detail := m.viewSelectedTaskDetail(); detail != "" {
+		b.WriteString("\n\n" + archiveSeparator + "\n\n")
+		b.WriteString(detail)
+	}
+
 	b.WriteString("\n\nNext: return to browse")
 
 	return b.String()
 }
 
+// viewSelectedTaskDetail renders the selected campaign task below the summary:
+// the live pipeline while a retry runs, the phase reports of a finished task,
+// the reason a task was skipped, or the last phase report of a failed
+// validation.
+func (m Model) viewSelectedTaskDetail() string {
+	cs := m.campaign
+	if cs.validationSelected() {
+		vr := cs.validationResult
+		if vr == nil || vr.Success || len(vr.PhaseReports) == 0 {
+			return ""
+		}
+		return "Feature validation\n\n" + formatPhaseReportDetail(vr.PhaseReports[len(vr.PhaseReports)-1])
+	}
+	if cs.selectedIdx < 0 || cs.selectedIdx >= len(cs.tasks) {
+		return ""
+	}
+	task := cs.tasks[cs.selectedIdx]
+
+	switch cs.taskStatuses[cs.selectedIdx] {
+	case CampaignTaskRunning:
+		if task.BeadID != m.retryingID {
+			return ""
+		}
+		_, rightWidth := PaneWidths(m.width)
+		return fmt.Sprintf("Retrying %s\n\n%s", task.BeadID, cs.pipeline.ViewReport(rightWidth-borderChrome, m.contentHeight()))
+	case CampaignTaskPassed:
+		reports := cs.taskReports[task.BeadID]
+		if len(reports) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s  %s\n%s", task.BeadID, task.Title, formatPhaseReports(reports))
+	case CampaignTaskSkipped:
+		return fmt.Sprintf("%s  %s\n\n%s", task.BeadID, task.Title, pipeSkippedStyle.Render("Skipped: "+cs.taskErrors[task.BeadID]))
+	case CampaignTaskFailed:
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s  %s", task.BeadID, task.Title)
+		if errText := cs.taskErrors[task.BeadID]; errText != "" {
+			fmt.Fprintf(&b, "\n\n%s", pipeFailedStyle.Render("⚠ "+errText))
+		}
+		if reports := cs.taskReports[task.BeadID]; len(reports) > 0 {
+			b.WriteString("\n" + formatPhaseReports(reports))
+			b.WriteString("\n\n" + formatPhaseReportDetail(reports[len(reports)-1]))
+		}
+		if m.runner != nil {
+			b.WriteString("\n\nPress e to retry this task.")
+		}
+		return b.String()
+	}
+	return ""
+}
+
+// formatPhaseReportDetail renders a single phase report with its feedback
+// and changed files.
+func formatPhaseReportDetail(r PhaseReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Last phase: %s (%s)", r.PhaseName, r.Status)
+	if r.Summary != "" {
+		fmt.Fprintf(&b, "\n%s", r.Summary)
+	}
+	if r.Feedback != "" {
+		fmt.Fprintf(&b, "\n\nFeedback:\n%s", r.Feedback)
+	}
+	if len(r.FilesChanged) > 0 {
+		b.WriteString("\n\nFiles changed:")
+		for _, f := range r.FilesChanged {
+			fmt.Fprintf(&b, "\n  %s", f)
+		}
+	}
+	return b.String()
+}
+
+// loadTaskReportsCmd returns a tea.Cmd that loads the stored phase reports
+// for the current campaign. Returns nil when no CampaignTaskStore is set.
+func (m Model) loadTaskReportsCmd() tea.Cmd {
+	if m.taskStore == nil || m.campaign.parentID == "" {
+		return nil
+	}
+	store := m.taskStore
+	parentID := m.campaign.parentID
+	return func() tea.Msg {
+		reports, err := store.TaskReports(parentID)
+		return campaignReportsMsg{Reports: reports, Err: err}
+	}
+}
+
+// handleTaskRetry re-dispatches the selected failed campaign task through the
+// pipeline runner. Only that task runs; the rest of the campaign is untouched.
+func (m Model) handleTaskRetry() (tea.Model, tea.Cmd) {
+	cs := m.campaign
+	idx := cs.selectedIdx
+	if m.runner == nil || m.retryingID != "" || idx < 0 || idx >= len(cs.tasks) {
+		return m, nil
+	}
+	if cs.taskStatuses[idx] != CampaignTaskFailed {
+		return m, nil
+	}
+
+	beadID := cs.tasks[idx].BeadID
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelPipeline = cancel
+	ch := make(chan tea.Msg, 16)
+	m.eventCh = ch
+	m.retryingID = beadID
+	m.campaign = cs.startRetry(idx, m.phaseNames)
+	input := PipelineInput{BeadID: beadID, Provider: cs.provider}
+	go dispatchTaskRetry(ctx, m.runner, input, idx, ch)
+	return m, tea.Batch(m.campaign.pipeline.spinner.Tick, listenForEvents(ch))
+}
+
+// dispatchTaskRetry runs a single campaign task's pipeline in the calling
+// goroutine, bridging status events to ch. It sends taskRetryDoneMsg on
+// completion and closes ch when done.
+func dispatchTaskRetry(ctx context.Context, runner PipelineRunner, input PipelineInput, idx int, ch chan<- tea.Msg) {
+	defer close(ch)
+	statusFn := func(msg PhaseUpdateMsg) {
+		select {
+		case ch <- msg:
+		case <-ctx.Done():
+		}
+	}
+	start := time.Now()
+	output, err := runner.RunPipeline(ctx, input, statusFn)
+	ch <- taskRetryDoneMsg{
+		BeadID:   input.BeadID,
+		Index:    idx,
+		Duration: time.Since(start),
+		Output:   output,
+		Err:      err,
+	}
+}
+
+// handleTaskRetryDone records a finished task retry in the campaign view. On
+// success it runs post-pipeline lifecycle and updates the stored campaign state.
+func (m Model) handleTaskRetryDone(msg taskRetryDoneMsg) (tea.Model, tea.Cmd) {
+	success := msg.Err == nil && msg.Output.Success
+	done := CampaignTaskDoneMsg{
+		BeadID:       msg.BeadID,
+		Index:        msg.Index,
+		Success:      success,
+		Duration:     msg.Duration,
+		PhaseReports: msg.Output.PhaseReports,
+	}
+	if msg.Err != nil {
+		done.Error = msg.Err.Error()
+	}
+	m.campaign, _ = m.campaign.Update(done)
+
+	listen := listenForEvents(m.eventCh)
+	if !success {
+		m.statusMsg = fmt.Sprintf("%s %s: retry failed", SymbolCross, msg.BeadID)
+		return m, tea.Batch(listen, tea.Tick(statusLineDuration, func(time.Time) tea.Msg {
+			return statusClearMsg{}
+		}))
+	}
+
+	if m.campaignDone != nil {
+		m.campaignDone.Passed++
+		m.campaignDone.Failed--
+	}
+
+	ppFn := m.postPipeline
+	store := m.taskStore
+	parentID := m.campaign.parentID
+	reports := msg.Output.PhaseReports
+	var title string
+	if msg.Index >= 0 && msg.Index < len(m.campaign.tasks) {
+		title = m.campaign.tasks[msg.Index].Title
+	}
+	postInput := newPostPipelineInput(msg.BeadID, title, "", reports)
+	record := func() tea.Msg {
+		if ppFn != nil {
+			if _, err := ppFn(postInput); err != nil {
+				return taskRetryRecordedMsg{BeadID: msg.BeadID, Err: fmt.Errorf("post-pipeline failed: %w", err)}
+			}
+		}
+		if store != nil {
+			if err := store.RecordTaskPassed(parentID, msg.BeadID, reports); err != nil {
+				return taskRetryRecordedMsg{BeadID: msg.BeadID, Err: fmt.Errorf("saving campaign state failed: %w", err)}
+			}
+		}
+		return taskRetryRecordedMsg{BeadID: msg.BeadID}
+	}
+	return m, tea.Batch(listen, record)
+}
+
 // returnToBrowse transitions from summary mode back to browse mode,
-// invalidating the bead cache and triggering a refresh. If a post-pipeline
-// function is configured, it fires in a background goroutine.
+// invalidating the bead cache and triggering a refresh. Post-pipeline
+// lifecycle still running reports its outcome as a status line.
 func (m Model) returnToBrowse() (Model, tea.Cmd) {
 	m.mode = ModeBrowse
 	m.focus = PaneLeft
-	m.cache.Invalidate()
+	m.invalidateBeads()
 	m.pendingResolveID = ""
 	m.lastDispatchedID = m.dispatchedBeadID
-
-	var cmds []tea.Cmd
-
-	// Fire post-pipeline lifecycle in background if configured.
-	// Skip on pipeline error — merge/close/cleanup should not run for failed pipelines.
-	if m.postPipeline != nil && m.dispatchedBeadID != "" && m.pipelineErr == nil {
-		beadID := m.dispatchedBeadID
-		ppFn := m.postPipeline
-		m.dispatchedBeadID = ""
-		cmds = append(cmds, func() tea.Msg {
-			err := ppFn(beadID)
-			return PostPipelineDoneMsg{BeadID: beadID, Err: err}
-		})
-	}
+	m.dispatchedBeadID = ""
+	m.postRunning = false
+	m.postDone = nil
+	m = m.clearWorktree()
 
 	// Refresh bead list with spinner animation.
 	if m.lister != nil {
-		cmds = append(cmds, initBrowse(m.lister), m.browseSpinner.Tick)
+		return m, tea.Batch(initBrowse(m.lister), m.browseSpinner.Tick)
 	}
-
-	return m, tea.Batch(cmds...)
+	return m, nil
 }
@@ -1,12 +1,15 @@
 package dashboard
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/smileynet/capsule/internal/provider"
 )
 
 func newPassedSummaryModel(w, h int) Model {
@@ -99,6 +102,86 @@ func TestSummary_RightPaneShowsFailSummary(t *testing.T) {
 	}
 }
 
+func TestSummary_RightPaneListsPhasesBeforeFailure(t *testing.T) {
+	// Given: a failed run whose output has a retried phase
+	m := newFailedSummaryModel(90, 40)
+	m.pipelineOutput = &PipelineOutput{PhaseReports: []PhaseReport{
+		{PhaseName: "plan", Status: PhasePassed, Duration: 2 * time.Second},
+		{PhaseName: "code", Status: PhaseFailed},
+		{PhaseName: "code", Status: PhaseError, Duration: 5 * time.Second},
+	}}
+
+	// When: the view is rendered
+	plain := stripANSI(m.viewSummaryRight())
+
+	// Then: the passed phase is checked above the failed one
+	pass := strings.Index(plain, "✓ plan 2.0s")
+	fail := strings.Index(plain, "✗ code 5.0s")
+	if pass < 0 || fail < 0 || pass > fail {
+		t.Errorf("right pane should list ✓ plan above ✗ code, got:\n%s", plain)
+	}
+	// And: the retried phase is listed once
+	if n := strings.Count(plain, "code"); n != 1 {
+		t.Errorf("code listed %d times, want 1:\n%s", n, plain)
+	}
+}
+
+func TestSummary_RightPaneShowsUsage(t *testing.T) {
+	// Given a summary whose phases reported usage, one of them over two attempts
+	m := newPassedSummaryModel(90, 40)
+	for _, u := range []PhaseUpdateMsg{
+		{Phase: "plan", Status: PhasePassed, Usage: provider.Usage{InputTokens: 200, OutputTokens: 100, CostUSD: 0.01}},
+		{Phase: "code", Status: PhaseFailed, Usage: provider.Usage{InputTokens: 500, OutputTokens: 100, CostUSD: 0.02}},
+		{Phase: "code", Status: PhasePassed, Usage: provider.Usage{InputTokens: 500, OutputTokens: 100, CostUSD: 0.02}},
+	} {
+		m.pipeline, _ = m.pipeline.Update(u)
+	}
+
+	// When the view is rendered
+	plain := stripANSI(m.View())
+
+	// Then the total and each phase's usage are shown
+	for _, want := range []string{
+		"Usage: 1200 in / 300 out tokens, $0.0500",
+		"plan: 200 in / 100 out tokens, $0.0100",
+		"code: 1000 in / 200 out tokens, $0.0400",
+	} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("right pane missing %q, got:\n%s", want, plain)
+		}
+	}
+	if strings.Contains(plain, "test:") {
+		t.Errorf("right pane lists a phase without usage:\n%s", plain)
+	}
+}
+
+func TestSummary_RightPaneOmitsZeroUsage(t *testing.T) {
+	// Given a summary whose provider reported no usage
+	m := newPassedSummaryModel(90, 40)
+
+	// When the view is rendered
+	plain := stripANSI(m.View())
+
+	// Then no usage line is shown
+	if strings.Contains(plain, "Usage:") {
+		t.Errorf("right pane should omit usage, got:\n%s", plain)
+	}
+}
+
+func TestSummary_RightPaneShowsFindings(t *testing.T) {
+	// Given a successful pipeline that reported a finding
+	m := newPassedSummaryModel(90, 40)
+	m.pipelineOutput.Findings = []provider.Finding{{Title: "Flaky test", Severity: "major"}}
+
+	// When the view is rendered
+	plain := stripANSI(m.View())
+
+	// Then the findings are listed
+	if !strings.Contains(plain, "Findings:") || !strings.Contains(plain, "[major] Flaky test") {
+		t.Errorf("right pane should list findings, got:\n%s", plain)
+	}
+}
+
 func TestSummary_AnyKeyTransitionsToBrowse(t *testing.T) {
 	// Given: a model in summary mode
 	m := newPassedSummaryModel(90, 40)
@@ -231,12 +314,48 @@ func TestSummary_FullFlowReturnToBrowse(t *testing.T) {
 	}
 }
 
-func TestSummary_ReturnToBrowseFiresPostPipeline(t *testing.T) {
-	// Given: a model in summary mode with PostPipelineFunc configured
-	var calledBeadID string
-	ppFunc := func(beadID string) error {
-		calledBeadID = beadID
-		return nil
+func TestSummary_PostPipelineInputDescribesBead(t *testing.T) {
+	// Given: a passed pipeline for a dispatched bug whose last passing phase reported files
+	var got PostPipelineInput
+	m := NewModel(WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
+		got = in
+		return PostPipelineResult{}, nil
+	}))
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
+	m = updated.(Model)
+	m.mode = ModePipeline
+	m.dispatchedBeadID = "cap-001"
+	m.pipeline = newPipelineState([]string{"execute", "sign-off", "lint"})
+	m.pipeline.beadTitle = "Fix crash"
+	m.pipeline.beadType = "bug"
+	m.pipelineOutput = &PipelineOutput{Success: true, PhaseReports: []PhaseReport{
+		{PhaseName: "execute", Status: PhasePassed, Summary: "early", FilesChanged: []string{"a.go"}},
+		{PhaseName: "sign-off", Status: PhasePassed, Summary: "final", FilesChanged: []string{"b.go"}},
+		{PhaseName: "lint", Status: PhaseSkipped},
+	}}
+
+	// When: the summary is shown and post-pipeline runs
+	_, cmd := m.Update(channelClosedMsg{})
+	if cmd == nil {
+		t.Fatal("expected post-pipeline command")
+	}
+	cmd()
+
+	// Then: the input carries the bead details and the final passing phase
+	if got.BeadID != "cap-001" || got.Title != "Fix crash" || got.Type != "bug" {
+		t.Errorf("input bead = %q %q %q, want cap-001 \"Fix crash\" bug", got.BeadID, got.Title, got.Type)
+	}
+	if got.Summary != "final" || len(got.FilesChanged) != 1 || got.FilesChanged[0] != "b.go" {
+		t.Errorf("input phase = %q %v, want final [b.go]", got.Summary, got.FilesChanged)
+	}
+}
+
+func TestSummary_EnterSummaryFiresPostPipeline(t *testing.T) {
+	// Given: a pipeline that passed, with PostPipelineFunc configured
+	var calls []string
+	ppFunc := func(in PostPipelineInput) (PostPipelineResult, error) {
+		calls = append(calls, in.BeadID)
+		return PostPipelineResult{Merged: true, BranchCleaned: true, BeadClosed: true}, nil
 	}
 	lister := &stubLister{beads: sampleBeads()}
 	m := NewModel(
@@ -245,41 +364,117 @@ func TestSummary_ReturnToBrowseFiresPostPipeline(t *testing.T) {
 	)
 	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
 	m = updated.(Model)
-	m.mode = ModeSummary
+	m.mode = ModePipeline
 	m.dispatchedBeadID = "cap-001"
 	m.pipeline = newPipelineState([]string{"plan"})
 	m.pipeline, _ = m.pipeline.Update(PhaseUpdateMsg{Phase: "plan", Status: PhasePassed, Duration: time.Second})
 	m.pipelineOutput = &PipelineOutput{Success: true}
 
-	// When: any key is pressed to return to browse
-	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	// When: the event channel closes and the summary is shown
+	updated, cmd := m.Update(channelClosedMsg{})
 	m = updated.(Model)
 
-	// Then: the model transitions to browse mode
-	if m.mode != ModeBrowse {
-		t.Errorf("mode = %d, want ModeBrowse", m.mode)
+	// Then: post-pipeline runs for the dispatched bead
+	if m.mode != ModeSummary {
+		t.Fatalf("mode = %d, want ModeSummary", m.mode)
 	}
-	// And: dispatchedBeadID is cleared
-	if m.dispatchedBeadID != "" {
-		t.Errorf("dispatchedBeadID = %q, want empty", m.dispatchedBeadID)
+	if !m.postRunning {
+		t.Error("postRunning should be set while post-pipeline runs")
 	}
-	// And: a batch command is produced containing both postPipeline and refresh
 	if cmd == nil {
-		t.Fatal("expected batch command")
+		t.Fatal("expected post-pipeline command")
 	}
-	batchMsg := cmd()
-	batch, ok := batchMsg.(tea.BatchMsg)
+	done, ok := cmd().(PostPipelineDoneMsg)
 	if !ok {
-		t.Fatalf("expected BatchMsg, got %T", batchMsg)
+		t.Fatalf("expected PostPipelineDoneMsg, got %T", cmd())
+	}
+	if done.BeadID != "cap-001" {
+		t.Errorf("PostPipelineDoneMsg.BeadID = %q, want %q", done.BeadID, "cap-001")
+	}
+
+	// When: the result arrives
+	updated, _ = m.Update(done)
+	m = updated.(Model)
+
+	// Then: the outcome is rendered in the summary pane, not the status line
+	if m.postRunning || m.postDone == nil {
+		t.Fatalf("postRunning = %v, postDone = %v; want result recorded", m.postRunning, m.postDone)
+	}
+	if m.statusMsg != "" {
+		t.Errorf("statusMsg = %q, want empty while summary shows the result", m.statusMsg)
+	}
+	plain := stripANSI(m.viewSummaryRight())
+	if !strings.Contains(plain, "merged to main, bead closed, worktree removed") {
+		t.Errorf("summary should show merge outcome, got:\n%s", plain)
 	}
-	// Execute all cmds in the batch to trigger PostPipelineFunc
-	for _, c := range batch {
-		if c != nil {
-			c()
+
+	// When: the user returns to browse
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// Then: post-pipeline does not run again
+	for _, msg := range execBatch(t, cmd) {
+		if _, ok := msg.(PostPipelineDoneMsg); ok {
+			t.Error("returning to browse should not fire post-pipeline again")
 		}
 	}
-	if calledBeadID != "cap-001" {
-		t.Errorf("PostPipelineFunc called with %q, want %q", calledBeadID, "cap-001")
+	if len(calls) != 1 {
+		t.Errorf("PostPipelineFunc called %d times, want 1", len(calls))
+	}
+}
+
+func TestSummary_PostPipelineConflictShowsResolutionSteps(t *testing.T) {
+	// Given: a passed summary whose post-pipeline merge hit a conflict
+	m := newPassedSummaryModel(90, 40)
+	m.dispatchedBeadID = "cap-001"
+	m.postRunning = true
+	result := PostPipelineResult{
+		MergeConflict: true,
+		Messages: []string{
+			"warning: merge conflict merging capsule-cap-001 into main",
+			"  To fix:",
+			"    git checkout main",
+			"    git merge --no-ff capsule-cap-001",
+			"    # resolve conflicts, then:",
+			"    capsule clean cap-001",
+		},
+	}
+
+	// When: the result arrives and the right pane is rendered
+	updated, _ := m.Update(PostPipelineDoneMsg{BeadID: "cap-001", Result: result})
+	m = updated.(Model)
+	plain := stripANSI(m.viewSummaryRight())
+
+	// Then: the conflict is reported with a cross
+	if !strings.Contains(plain, SymbolCross+"  merge conflict") {
+		t.Errorf("summary should report merge conflict, got:\n%s", plain)
+	}
+	// And: the manual resolution steps are shown
+	for _, want := range []string{"git checkout main", "git merge --no-ff capsule-cap-001", "capsule clean cap-001"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("summary should contain %q, got:\n%s", want, plain)
+		}
+	}
+	// And: the success line is not shown
+	if strings.Contains(plain, "merged to main") {
+		t.Errorf("summary should not claim a merge, got:\n%s", plain)
+	}
+}
+
+func TestSummary_PostPipelineDoneMsg_OtherBeadUsesStatusLine(t *testing.T) {
+	// Given: a summary for cap-002
+	m := newPassedSummaryModel(90, 40)
+	m.dispatchedBeadID = "cap-002"
+
+	// When: a result for a different bead arrives
+	updated, _ := m.Update(PostPipelineDoneMsg{BeadID: "cap-001", Result: PostPipelineResult{MergeConflict: true}})
+	m = updated.(Model)
+
+	// Then: it is shown as a status line, not in the summary
+	if m.postDone != nil {
+		t.Error("postDone should only record the summary's own bead")
+	}
+	if !strings.Contains(m.statusMsg, "cap-001: merge conflict") {
+		t.Errorf("statusMsg = %q, want cap-001 merge conflict", m.statusMsg)
 	}
 }
 
@@ -317,47 +512,49 @@ func TestSummary_ReturnToBrowseWithoutPostPipelineProducesRefreshOnly(t *testing
 	}
 }
 
-func TestSummary_ReturnToBrowseSkipsPostPipelineOnError(t *testing.T) {
-	// Given: a model in summary mode with PostPipelineFunc and a pipeline error
-	var postPipelineCalled bool
-	lister := &stubLister{beads: sampleBeads()}
-	m := NewModel(
-		WithBeadLister(lister),
-		WithPostPipelineFunc(func(beadID string) error {
-			postPipelineCalled = true
-			return nil
-		}),
-	)
-	updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
-	m = updated.(Model)
-	m.mode = ModeSummary
-	m.dispatchedBeadID = "cap-001"
-	m.pipeline = newPipelineState([]string{"plan"})
-	m.pipelineErr = fmt.Errorf("phase failed")
-
-	// When: any key is pressed to return to browse
-	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
-
-	// Then: postPipeline is NOT fired (pipeline had an error)
-	msgs := execBatch(t, cmd)
-	for _, msg := range msgs {
-		if _, ok := msg.(PostPipelineDoneMsg); ok {
-			t.Error("postPipeline should not fire when pipeline had an error")
-		}
-	}
-	if postPipelineCalled {
-		t.Error("PostPipelineFunc should not be called when pipelineErr is set")
-	}
-
-	// But: the batch still contains a bead list refresh
-	var foundRefresh bool
-	for _, msg := range msgs {
-		if _, ok := msg.(BeadListMsg); ok {
-			foundRefresh = true
-		}
-	}
-	if !foundRefresh {
-		t.Fatal("batch should contain BeadListMsg for bead list refresh")
+func TestSummary_EnterSummarySkipsPostPipelineOnError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *PipelineOutput
+		err    error
+	}{
+		{name: "pipeline error", err: fmt.Errorf("phase failed")},
+		{name: "pipeline failed", output: &PipelineOutput{Success: false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given: a finished pipeline that did not succeed, with PostPipelineFunc configured
+			var postPipelineCalled bool
+			m := NewModel(
+				WithBeadLister(&stubLister{beads: sampleBeads()}),
+				WithPostPipelineFunc(func(in PostPipelineInput) (PostPipelineResult, error) {
+					postPipelineCalled = true
+					return PostPipelineResult{}, nil
+				}),
+			)
+			updated, _ := m.Update(tea.WindowSizeMsg{Width: 90, Height: 40})
+			m = updated.(Model)
+			m.mode = ModePipeline
+			m.dispatchedBeadID = "cap-001"
+			m.pipeline = newPipelineState([]string{"plan"})
+			m.pipelineOutput = tt.output
+			m.pipelineErr = tt.err
+
+			// When: the summary is shown
+			updated, cmd := m.Update(channelClosedMsg{})
+			m = updated.(Model)
+
+			// Then: postPipeline is NOT fired
+			if cmd != nil {
+				cmd()
+			}
+			if postPipelineCalled {
+				t.Error("PostPipelineFunc should not be called for a failed pipeline")
+			}
+			if m.postRunning {
+				t.Error("postRunning should stay false for a failed pipeline")
+			}
+		})
 	}
 }
 
@@ -518,7 +715,7 @@ func TestSummary_PostPipelineDoneMsg_SetsStatusLine(t *testing.T) {
 	m := newSizedModel(90, 40)
 
 	// When: a PostPipelineDoneMsg arrives with no error
-	updated, cmd := m.Update(PostPipelineDoneMsg{BeadID: "cap-001"})
+	updated, cmd := m.Update(PostPipelineDoneMsg{BeadID: "cap-001", Result: PostPipelineResult{Merged: true}})
 	m = updated.(Model)
 
 	// Then: statusMsg is set with a success message
@@ -657,17 +854,18 @@ func TestSummary_ReturnToBrowseFromCampaign_ClearsPendingResolveID(t *testing.T)
 	}
 }
 
-func TestSummary_NextText_WithPostPipeline(t *testing.T) {
-	// Given: a model in summary mode with postPipeline configured
+func TestSummary_PostPipelineRunningText(t *testing.T) {
+	// Given: a model in summary mode with post-pipeline running
 	m := newPassedSummaryModel(90, 40)
-	m.postPipeline = func(_ string) error { return nil }
+	m.postPipeline = func(PostPipelineInput) (PostPipelineResult, error) { return PostPipelineResult{}, nil }
+	m.postRunning = true
 
 	// When: the right pane is rendered
 	view := m.viewSummaryRight()
 
-	// Then: "Next: merge to main" text appears
-	if !strings.Contains(view, "Next: merge to main") {
-		t.Errorf("summary should show Next: merge text, got:\n%s", view)
+	// Then: the in-progress lifecycle is shown
+	if !strings.Contains(view, "Merging to main") {
+		t.Errorf("summary should show merge in progress, got:\n%s", view)
 	}
 }
 
@@ -743,16 +941,20 @@ func TestSummary_CampaignSummary_ValidationFailed(t *testing.T) {
 		TotalTasks: 2,
 		Passed:     2,
 	}
-	m.campaign.validationResult = &CampaignValidationDoneMsg{Success: false}
+	m.campaign.validationResult = &CampaignValidationDoneMsg{Success: false, Error: "feature-review: NEEDS_WORK"}
 
 	// When: the right pane is rendered
 	view := m.viewCampaignSummaryRight()
 	plain := stripANSI(view)
 
 	// Then: validation failed text appears
-	if !strings.Contains(plain, "Feature validation failed") {
+	if !strings.Contains(plain, "Feature validation failed (cap-feat left open)") {
 		t.Errorf("campaign summary should show validation failed, got:\n%s", plain)
 	}
+	// And: the failure reason is shown
+	if !strings.Contains(plain, "feature-review: NEEDS_WORK") {
+		t.Errorf("campaign summary should show validation error, got:\n%s", plain)
+	}
 }
 
 func TestSummary_PostPipelineDoneMsg_DescriptiveSuccess(t *testing.T) {
@@ -760,7 +962,10 @@ func TestSummary_PostPipelineDoneMsg_DescriptiveSuccess(t *testing.T) {
 	m := newSizedModel(90, 40)
 
 	// When: a successful PostPipelineDoneMsg is received
-	updated, _ := m.Update(PostPipelineDoneMsg{BeadID: "cap-001"})
+	updated, _ := m.Update(PostPipelineDoneMsg{
+		BeadID: "cap-001",
+		Result: PostPipelineResult{Merged: true, BranchCleaned: true, BeadClosed: true},
+	})
 	m = updated.(Model)
 
 	// Then: statusMsg contains descriptive text
@@ -807,3 +1012,88 @@ func TestSummary_StatusLine_NotRenderedWhenEmpty(t *testing.T) {
 		t.Errorf("browse view should not show status when empty, got:\n%s", plain)
 	}
 }
+
+func TestSummary_RetryResumesFailedRun(t *testing.T) {
+	// Given a failed pipeline summary with checkpoint resume enabled
+	inputs := make(chan PipelineInput, 1)
+	runner := &mockRunner{runFn: func(_ context.Context, in PipelineInput, statusFn func(PhaseUpdateMsg)) (PipelineOutput, error) {
+		inputs <- in
+		statusFn(PhaseUpdateMsg{Phase: "plan", Status: PhasePassed})
+		return PipelineOutput{Success: true}, nil
+	}}
+	m := newFailedSummaryModel(90, 40)
+	m.runner = runner
+	m.phaseNames = []string{"plan", "code", "test"}
+	m.resumeEnabled = true
+	m.dispatchedBeadID = "cap-001"
+	m.pipeline.beadTitle = "Add login"
+	m.pipeline.provider = "claude"
+
+	// Then the summary and help bar offer r
+	if !strings.Contains(stripANSI(m.viewSummaryRight()), "press r to retry") {
+		t.Errorf("summary should offer retry, got:\n%s", stripANSI(m.viewSummaryRight()))
+	}
+	if !strings.Contains(stripANSI(m.View()), "retry failed phase") {
+		t.Error("help bar should show the retry binding")
+	}
+
+	// When r is pressed
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(Model)
+
+	// Then the same bead is re-dispatched as a resume
+	if m.mode != ModePipeline {
+		t.Fatalf("mode = %d, want ModePipeline", m.mode)
+	}
+	in := <-inputs
+	if in.BeadID != "cap-001" || in.Provider != "claude" || !in.Resume {
+		t.Errorf("input = %+v, want resume of cap-001 with claude", in)
+	}
+	if m.pipeline.beadTitle != "Add login" {
+		t.Errorf("beadTitle = %q, want %q", m.pipeline.beadTitle, "Add login")
+	}
+
+	// And phases reported done by the resumed run are checked off
+	m = drainPipeline(t, m)
+	if got := m.pipeline.phases[0].Status; got != PhasePassed {
+		t.Errorf("plan status = %q, want passed", got)
+	}
+}
+
+func TestSummary_RetryHidden(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*Model)
+	}{
+		{name: "no checkpoint store", setup: func(m *Model) { m.resumeEnabled = false }},
+		{name: "pipeline passed", setup: func(m *Model) {
+			m.pipelineErr = nil
+			m.pipelineOutput = &PipelineOutput{Success: true}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a summary that cannot be resumed
+			m := newFailedSummaryModel(90, 40)
+			m.runner = &mockRunner{}
+			m.resumeEnabled = true
+			m.dispatchedBeadID = "cap-001"
+			tt.setup(&m)
+
+			// Then r is not advertised
+			if strings.Contains(stripANSI(m.View()), "retry failed phase") {
+				t.Error("help bar should hide the retry binding")
+			}
+
+			// And pressing r stays on the summary
+			updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+			if got := updated.(Model).mode; got != ModeSummary {
+				t.Errorf("mode = %d, want ModeSummary", got)
+			}
+			if cmd != nil {
+				t.Error("r should produce no command")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package dashboard
 
 import (
 	"sort"
+	"strings"
 )
 
 // treeNode represents a bead and its children in a hierarchical tree.
@@ -10,6 +11,45 @@ type treeNode struct {
 	Children []*treeNode
 	IsLast   bool // true if this is the last child of its parent
 	expanded bool // true if this node's children should be visible
+
+	// source is the unfiltered node a filterTree copy was pruned from, so
+	// child counts and progress still cover hidden children. Nil for
+	// nodes built by buildTree.
+	source *treeNode
+}
+
+// unfiltered returns the node filterTree copied n from, or n itself.
+func (n *treeNode) unfiltered() *treeNode {
+	if n.source != nil {
+		return n.source
+	}
+	return n
+}
+
+// sortMode orders siblings in the browse tree.
+type sortMode int
+
+const (
+	sortByID sortMode = iota
+	sortByPriority
+	sortByType
+)
+
+// String returns the label shown in the help bar.
+func (s sortMode) String() string {
+	switch s {
+	case sortByPriority:
+		return "priority"
+	case sortByType:
+		return "type"
+	default:
+		return "id"
+	}
+}
+
+// next returns the mode the s key cycles to.
+func (s sortMode) next() sortMode {
+	return (s + 1) % 3
 }
 
 // flatNode is a treeNode with pre-computed prefix strings for rendering.
@@ -189,3 +229,57 @@ func openChildCount(node *treeNode) int {
 func isExpandable(node *treeNode) bool {
 	return len(node.Children) > 0
 }
+
+// sortTree orders nodes and every level of their children by mode, ties
+// broken by ID, and re-marks the last child at each level.
+func sortTree(nodes []*treeNode, mode sortMode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i].Bead, nodes[j].Bead
+		switch mode {
+		case sortByPriority:
+			if a.Priority != b.Priority {
+				return a.Priority < b.Priority
+			}
+		case sortByType:
+			if a.Type != b.Type {
+				return a.Type < b.Type
+			}
+		}
+		return a.ID < b.ID
+	})
+	for i, n := range nodes {
+		n.IsLast = i == len(nodes)-1
+		sortTree(n.Children, mode)
+	}
+}
+
+// filterTree returns copies of the nodes whose ID or title contains query,
+// case-insensitively, together with their ancestors. Ancestors are expanded
+// so every match is visible; the original nodes are left untouched.
+func filterTree(nodes []*treeNode, query string) []*treeNode {
+	query = strings.ToLower(query)
+	var kept []*treeNode
+	for _, n := range nodes {
+		children := filterTree(n.Children, query)
+		if len(children) == 0 && !matchesFilter(n.Bead, query) {
+			continue
+		}
+		for i, c := range children {
+			c.IsLast = i == len(children)-1
+		}
+		kept = append(kept, &treeNode{
+			Bead:     n.Bead,
+			Children: children,
+			expanded: len(children) > 0,
+			source:   n.unfiltered(),
+		})
+	}
+	return kept
+}
+
+// matchesFilter reports whether the bead's ID or title contains the
+// lowercased query.
+func matchesFilter(b BeadSummary, query string) bool {
+	return strings.Contains(strings.ToLower(b.ID), query) ||
+		strings.Contains(strings.ToLower(b.Title), query)
+}
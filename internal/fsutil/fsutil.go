@@ -0,0 +1,11 @@
+// Package fsutil holds small file system helpers shared across capsule's
+// packages.
+package fsutil
+
+import "os"
+
+// IsDir reports whether path is an existing directory.
+func IsDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
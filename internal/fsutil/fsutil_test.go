@@ -0,0 +1,35 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsDir(t *testing.T) {
+	// Given a directory and a file in it
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{name: "directory", path: dir, want: true},
+		{name: "file", path: file, want: false},
+		{name: "missing", path: filepath.Join(dir, "missing"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When IsDir is called
+			// Then only an existing directory reports true
+			if got := IsDir(tt.path); got != tt.want {
+				t.Errorf("IsDir(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,32 +3,72 @@ package gate
 
 import (
 	"context"
+	"fmt"
 	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/smileynet/capsule/internal/procgroup"
 	"github.com/smileynet/capsule/internal/provider"
 )
 
+// DefaultMaxOutput caps how much of a failed command's output is kept in
+// the Signal feedback.
+const DefaultMaxOutput = 8 * 1024
+
 // Runner executes shell commands and returns a provider.Signal based on the exit code.
-type Runner struct{}
+type Runner struct {
+	maxOutput int
+}
+
+// Option configures a Runner.
+type Option func(*Runner)
+
+// WithMaxOutput keeps at most n bytes of a failed command's output, dropping
+// the start so the tail, where tools usually summarize, survives. n <= 0
+// uses DefaultMaxOutput.
+func WithMaxOutput(n int) Option {
+	return func(r *Runner) {
+		if n > 0 {
+			r.maxOutput = n
+		}
+	}
+}
 
 // NewRunner creates a Runner.
-func NewRunner() *Runner {
-	return &Runner{}
+func NewRunner(opts ...Option) *Runner {
+	r := &Runner{maxOutput: DefaultMaxOutput}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
-// Run executes command in workDir via sh -c. A zero exit code produces StatusPass;
-// a non-zero exit code produces StatusError with the combined output as feedback.
+// Run executes command in workDir via sh -c (cmd /C on Windows). A zero exit
+// code produces StatusPass; a non-zero exit code produces StatusError with the
+// tail of the combined output as feedback and any file:line:col diagnostics
+// in it as minor findings. Cancelling ctx kills the command together with
+// everything it started, so nothing keeps files in the worktree open.
 func (r *Runner) Run(ctx context.Context, command, workDir string) (provider.Signal, error) {
-	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	name, args := provider.ShellCommand(runtime.GOOS, command)
+	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Dir = workDir
+	procgroup.Set(cmd)
+	cmd.Cancel = func() error {
+		procgroup.Kill(cmd)
+		return nil
+	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		feedback := tail(string(output), r.maxOutput)
 		return provider.Signal{
 			Status:       provider.StatusError,
-			Feedback:     string(output),
+			Feedback:     feedback,
 			Summary:      err.Error(),
 			FilesChanged: []string{},
-			Findings:     []provider.Finding{},
+			Findings:     parseFindings(feedback),
 		}, nil
 	}
 	return provider.Signal{
@@ -39,3 +79,53 @@ func (r *Runner) Run(ctx context.Context, command, workDir string) (provider.Sig
 		Findings:     []provider.Finding{},
 	}, nil
 }
+
+// tail returns the last max bytes of s, cut at a line start when one is
+// close, with a marker saying how much was dropped.
+func tail(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := len(s) - max
+	if i := strings.IndexByte(s[cut:], '\n'); i >= 0 && i < max/4 {
+		cut += i + 1
+	}
+	for cut < len(s) && !utf8.RuneStart(s[cut]) {
+		cut++
+	}
+	return fmt.Sprintf("[... %d bytes truncated ...]\n%s", cut, s[cut:])
+}
+
+// diagnosticPattern matches compiler and linter lines such as
+// "main.go:12:5: undefined: x" or "pkg/a.go:3: missing return".
+var diagnosticPattern = regexp.MustCompile(`^\s*([^\s:][^:]*\.[A-Za-z0-9]+):(\d+)(?::(\d+))?:\s*(.+)$`)
+
+// maxFindings bounds how many diagnostics one gate run reports as findings.
+const maxFindings = 20
+
+// parseFindings turns file:line[:col]: message lines in output into minor
+// findings, one per distinct line.
+func parseFindings(output string) []provider.Finding {
+	findings := []provider.Finding{}
+	seen := make(map[string]bool)
+	for line := range strings.Lines(output) {
+		m := diagnosticPattern.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+		if m == nil {
+			continue
+		}
+		loc := m[1] + ":" + m[2]
+		if m[3] != "" {
+			loc += ":" + m[3]
+		}
+		title := loc + ": " + strings.TrimSpace(m[4])
+		if seen[title] {
+			continue
+		}
+		seen[title] = true
+		findings = append(findings, provider.Finding{Title: title, Severity: "minor", Description: strings.TrimSpace(m[4])})
+		if len(findings) == maxFindings {
+			break
+		}
+	}
+	return findings
+}
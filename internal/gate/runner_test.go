@@ -2,6 +2,8 @@ package gate
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/smileynet/capsule/internal/provider"
@@ -114,3 +116,101 @@ func TestRunner_NormalizesSlices(t *testing.T) {
 		t.Error("Findings should be empty slice, not nil")
 	}
 }
+
+func TestRunner_FailingCommandReportsDiagnostics(t *testing.T) {
+	// Given a failing command that prints a linter diagnostic
+	r := NewRunner()
+
+	// When Run is called
+	signal, err := r.Run(context.Background(), "echo main.go:3:1: x declared and not used&& exit 1", t.TempDir())
+
+	// Then the diagnostic is in Feedback and reported as a minor finding
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(signal.Feedback, "main.go:3:1: x declared and not used") {
+		t.Errorf("Feedback = %q, want the command output", signal.Feedback)
+	}
+	want := []provider.Finding{{Title: "main.go:3:1: x declared and not used", Severity: "minor", Description: "x declared and not used"}}
+	if !slices.Equal(signal.Findings, want) {
+		t.Errorf("Findings = %+v, want %+v", signal.Findings, want)
+	}
+}
+
+func TestRunner_WithMaxOutputKeepsTail(t *testing.T) {
+	// Given a runner capped at 64 bytes and a command printing far more
+	r := NewRunner(WithMaxOutput(64))
+
+	// When the command fails
+	signal, err := r.Run(context.Background(), "echo "+strings.Repeat("a", 200)+"&& echo LAST LINE&& exit 1", t.TempDir())
+
+	// Then Feedback keeps the end of the output behind a truncation marker
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(signal.Feedback, "[... ") || !strings.Contains(signal.Feedback, "LAST LINE") {
+		t.Errorf("Feedback = %q, want a truncation marker and the last line", signal.Feedback)
+	}
+	if strings.Count(signal.Feedback, "a") > 64 {
+		t.Errorf("Feedback kept %d bytes of the head, want at most 64", strings.Count(signal.Feedback, "a"))
+	}
+}
+
+func TestTail(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{name: "short output is unchanged", in: "one\ntwo\n", max: 64, want: "one\ntwo\n"},
+		{name: "cuts at a nearby line start", in: "xxxxxxxxxx\nab\n0123456789abcdef\n", max: 20, want: "[... 14 bytes truncated ...]\n0123456789abcdef\n"},
+		{name: "cuts mid-line without a nearby newline", in: "0123456789abcdef", max: 6, want: "[... 10 bytes truncated ...]\nabcdef"},
+		{name: "never splits a rune", in: "ééé", max: 3, want: "[... 4 bytes truncated ...]\né"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given output and a byte cap
+			// When the tail is taken
+			got := tail(tt.in, tt.max)
+
+			// Then the end of the output survives
+			if got != tt.want {
+				t.Errorf("tail(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFindings(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{name: "line and column", output: "internal/a.go:12:5: undefined: x\n", want: []string{"internal/a.go:12:5: undefined: x"}},
+		{name: "line only, indented", output: "    a_test.go:40: got 1, want 2\n", want: []string{"a_test.go:40: got 1, want 2"}},
+		{name: "duplicates collapse", output: "a.go:1:1: bad\na.go:1:1: bad\n", want: []string{"a.go:1:1: bad"}},
+		{name: "other lines ignored", output: "FAIL\nexit status 1\n--- FAIL: TestX (0.00s)\nmake: *** [lint] Error 1\n", want: []string{}},
+		{name: "CRLF line endings", output: "a.go:2:3: bad\r\n", want: []string{"a.go:2:3: bad"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given gate output
+			// When diagnostics are parsed
+			findings := parseFindings(tt.output)
+
+			// Then each diagnostic becomes one minor finding
+			got := []string{}
+			for _, f := range findings {
+				if f.Severity != "minor" {
+					t.Errorf("Severity = %q, want minor", f.Severity)
+				}
+				got = append(got, f.Title)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("titles = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package gate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+func TestRunner_CancelKillsChildProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+	// Given a gate whose shell starts a long-running child sharing its output
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	r := NewRunner()
+
+	// When the context expires
+	start := time.Now()
+	signal, err := r.Run(ctx, "sleep 30 & wait", t.TempDir())
+
+	// Then the child is killed with the shell instead of holding the run open
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if signal.Status != provider.StatusError {
+		t.Errorf("Status = %q, want %q", signal.Status, provider.StatusError)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run took %v after cancellation, want the child killed", elapsed)
+	}
+}
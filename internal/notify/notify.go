@@ -0,0 +1,202 @@
+// Package notify fires best-effort notifications when a pipeline or campaign
+// finishes. Two sinks are supported: an exec hook whose arguments are
+// expanded as text/template strings, and a webhook that receives a JSON POST.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultTimeout bounds each hook so a hung notifier cannot block shutdown.
+const DefaultTimeout = 10 * time.Second
+
+// Kind identifies what finished.
+type Kind string
+
+const (
+	KindPipeline Kind = "pipeline"
+	KindCampaign Kind = "campaign"
+)
+
+// Status values reported in Event.Status.
+const (
+	StatusPassed = "passed"
+	StatusFailed = "failed"
+	StatusPaused = "paused"
+)
+
+// Event describes a finished pipeline or campaign. Its fields are available
+// to command templates, e.g. "{{.BeadID}} {{.Status}}".
+type Event struct {
+	Kind        Kind
+	BeadID      string
+	Status      string // StatusPassed | StatusFailed | StatusPaused
+	Success     bool
+	Duration    time.Duration
+	FailedPhase string // Empty unless a pipeline phase failed.
+	Error       string // Empty on success.
+}
+
+// payload is the JSON body POSTed to the webhook.
+type payload struct {
+	Kind            Kind    `json:"kind"`
+	BeadID          string  `json:"bead_id"`
+	Status          string  `json:"status"`
+	Success         bool    `json:"success"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	FailedPhase     string  `json:"failed_phase,omitempty"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// Notifier delivers Events to the configured exec hook and webhook.
+// A nil *Notifier is valid and does nothing.
+type Notifier struct {
+	command []string
+	webhook string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// Option configures a Notifier.
+type Option func(*Notifier)
+
+// WithTimeout sets the per-hook timeout. Non-positive values keep DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(n *Notifier) {
+		if d > 0 {
+			n.timeout = d
+		}
+	}
+}
+
+// WithHTTPClient overrides the HTTP client used for webhook delivery.
+func WithHTTPClient(c *http.Client) Option {
+	return func(n *Notifier) { n.client = c }
+}
+
+// New creates a Notifier. It returns nil when neither command nor webhook is
+// set, so callers can skip notification with a single nil check.
+func New(command []string, webhook string, opts ...Option) *Notifier {
+	if len(command) == 0 && webhook == "" {
+		return nil
+	}
+	n := &Notifier{
+		command: command,
+		webhook: webhook,
+		timeout: DefaultTimeout,
+		client:  http.DefaultClient,
+	}
+	for _, o := range opts {
+		o(n)
+	}
+	return n
+}
+
+// Notify runs the exec hook and posts to the webhook. Both sinks are attempted
+// even if one fails; the returned error joins every failure. Callers should
+// treat errors as warnings.
+func (n *Notifier) Notify(ctx context.Context, ev Event) error {
+	if n == nil {
+		return nil
+	}
+	var errs []error
+	if len(n.command) > 0 {
+		if err := n.runCommand(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if n.webhook != "" {
+		if err := n.postWebhook(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ExpandArgs expands each argument as a text/template against ev.
+// References to fields that Event does not have are reported as errors
+// rather than silently rendering "<no value>".
+func ExpandArgs(args []string, ev Event) ([]string, error) {
+	out := make([]string, len(args))
+	for i, a := range args {
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(a)
+		if err != nil {
+			return nil, fmt.Errorf("notify: parsing argument %d %q: %w", i, a, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, ev); err != nil {
+			return nil, fmt.Errorf("notify: expanding argument %d %q: %w", i, a, err)
+		}
+		out[i] = b.String()
+	}
+	return out, nil
+}
+
+// runCommand expands and executes the exec hook with a timeout.
+func (n *Notifier) runCommand(ctx context.Context, ev Event) error {
+	args, err := ExpandArgs(n.command, ev)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	// Don't wait on pipes held open by grandchildren after the kill.
+	cmd.WaitDelay = time.Second
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("notify: command %s timed out after %v", args[0], n.timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("notify: command %s: %w: %s", args[0], err, msg)
+		}
+		return fmt.Errorf("notify: command %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// postWebhook sends ev as JSON to the webhook URL with a timeout.
+func (n *Notifier) postWebhook(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(payload{
+		Kind:            ev.Kind,
+		BeadID:          ev.BeadID,
+		Status:          ev.Status,
+		Success:         ev.Success,
+		DurationSeconds: ev.Duration.Seconds(),
+		FailedPhase:     ev.FailedPhase,
+		Error:           ev.Error,
+	})
+	if err != nil {
+		return fmt.Errorf("notify: encoding webhook payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify: webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify: webhook returned %s", resp.Status)
+	}
+	return nil
+}
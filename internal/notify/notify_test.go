@@ -0,0 +1,220 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandArgs(t *testing.T) {
+	ev := Event{
+		Kind:        KindPipeline,
+		BeadID:      "cap-42",
+		Status:      StatusFailed,
+		Duration:    90 * time.Second,
+		FailedPhase: "test-review",
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "plain arguments pass through",
+			args: []string{"notify-send", "done"},
+			want: []string{"notify-send", "done"},
+		},
+		{
+			name: "fields are expanded",
+			args: []string{"notify-send", "{{.BeadID}} {{.Status}}", "{{.FailedPhase}} after {{.Duration}}"},
+			want: []string{"notify-send", "cap-42 failed", "test-review after 1m30s"},
+		},
+		{
+			name: "empty field expands to empty string",
+			args: []string{"{{.Error}}"},
+			want: []string{""},
+		},
+		{
+			name:    "missing field is an error",
+			args:    []string{"notify-send", "{{.Bead}}"},
+			wantErr: "argument 1",
+		},
+		{
+			name:    "malformed template is an error",
+			args:    []string{"{{.BeadID"},
+			wantErr: "parsing argument 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When args are expanded against the event
+			got, err := ExpandArgs(tt.args, ev)
+
+			// Then the result or error matches
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("ExpandArgs() = %q, want error containing %q", got, tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandArgs() error = %v", err)
+			}
+			if strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+				t.Errorf("ExpandArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew_NothingConfigured(t *testing.T) {
+	// Given no command and no webhook
+	n := New(nil, "")
+
+	// Then the notifier is nil and Notify is a no-op
+	if n != nil {
+		t.Fatalf("New() = %+v, want nil", n)
+	}
+	if err := n.Notify(context.Background(), Event{BeadID: "cap-1"}); err != nil {
+		t.Errorf("nil Notify() error = %v", err)
+	}
+}
+
+func TestNotify_Command(t *testing.T) {
+	// Given a command that writes its arguments to a file
+	out := filepath.Join(t.TempDir(), "out.txt")
+	n := New([]string{"sh", "-c", `printf '%s' "$1" > "$2"`, "sh", "{{.BeadID}} {{.Status}}", out}, "")
+
+	// When Notify is called
+	err := n.Notify(context.Background(), Event{BeadID: "cap-7", Status: StatusPassed})
+
+	// Then the expanded arguments reach the command
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "cap-7 passed" {
+		t.Errorf("command output = %q, want %q", got, "cap-7 passed")
+	}
+}
+
+func TestNotify_CommandFailure(t *testing.T) {
+	// Given a command that exits non-zero
+	n := New([]string{"sh", "-c", "echo boom >&2; exit 3"}, "")
+
+	// When Notify is called
+	err := n.Notify(context.Background(), Event{BeadID: "cap-1"})
+
+	// Then the error includes the command output
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Notify() error = %v, want error containing %q", err, "boom")
+	}
+}
+
+func TestNotify_CommandTimeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping timeout test in short mode")
+	}
+	// Given a command that hangs longer than the timeout
+	n := New([]string{"sleep", "10"}, "", WithTimeout(100*time.Millisecond))
+
+	// When Notify is called
+	start := time.Now()
+	err := n.Notify(context.Background(), Event{BeadID: "cap-1"})
+
+	// Then it returns a timeout error promptly
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Notify() error = %v, want timeout error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Notify() took %v, want it bounded by the timeout", elapsed)
+	}
+}
+
+func TestNotify_Webhook(t *testing.T) {
+	// Given a webhook server that records the request body
+	var got map[string]any
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+	n := New(nil, srv.URL)
+
+	// When Notify is called for a failed pipeline
+	err := n.Notify(context.Background(), Event{
+		Kind:        KindPipeline,
+		BeadID:      "cap-9",
+		Status:      StatusFailed,
+		Duration:    2500 * time.Millisecond,
+		FailedPhase: "execute",
+	})
+
+	// Then the JSON payload carries bead ID, success flag, duration, and failed phase
+	if err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+	if got["bead_id"] != "cap-9" || got["success"] != false || got["failed_phase"] != "execute" {
+		t.Errorf("payload = %v", got)
+	}
+	if got["duration_seconds"] != 2.5 {
+		t.Errorf("duration_seconds = %v, want 2.5", got["duration_seconds"])
+	}
+}
+
+func TestNotify_WebhookErrorStatus(t *testing.T) {
+	// Given a webhook server that rejects the request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	n := New(nil, srv.URL)
+
+	// When Notify is called
+	err := n.Notify(context.Background(), Event{BeadID: "cap-1"})
+
+	// Then a status error is returned
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Notify() error = %v, want 500 status error", err)
+	}
+}
+
+func TestNotify_BothSinksAttempted(t *testing.T) {
+	// Given a failing command and a working webhook
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+	n := New([]string{"{{.Missing}}"}, srv.URL)
+
+	// When Notify is called
+	err := n.Notify(context.Background(), Event{BeadID: "cap-1"})
+
+	// Then the command error is returned and the webhook still fires
+	if err == nil {
+		t.Error("Notify() error = nil, want command template error")
+	}
+	if !called {
+		t.Error("webhook was not called after command failure")
+	}
+}
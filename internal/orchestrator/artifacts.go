@@ -0,0 +1,130 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// A phase's required artifacts are globs, relative to the worktree root,
+// that must each match at least one file once the phase passes. Segments
+// follow path.Match, and a "**" segment matches any number of directories:
+//
+//	docs/design.md       that exact file
+//	docs/*.md            a markdown file directly under docs
+//	**/*_test.go         a test file anywhere in the worktree
+//
+// The .git directory is never searched.
+
+// ErrMissingArtifacts indicates a phase passed without writing a file its
+// required_artifacts promised.
+var ErrMissingArtifacts = errors.New("required artifacts missing")
+
+// validateArtifact checks the syntax of one required artifact glob.
+func validateArtifact(glob string) error {
+	if glob == "" {
+		return errors.New("glob must not be empty")
+	}
+	if !filepath.IsLocal(filepath.FromSlash(glob)) {
+		return fmt.Errorf("glob %q must be relative to the worktree", glob)
+	}
+	for _, seg := range strings.Split(glob, "/") {
+		if _, err := path.Match(seg, "test"); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+	return nil
+}
+
+// missingArtifacts returns the globs that match no file under dir, in the
+// order given. The tree is walked once, and not at all when globs is empty.
+func missingArtifacts(dir string, globs []string) ([]string, error) {
+	if len(globs) == 0 {
+		return nil, nil
+	}
+	pending := make(map[string][]string, len(globs))
+	for _, g := range globs {
+		pending[g] = strings.Split(g, "/")
+	}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		segs := strings.Split(filepath.ToSlash(rel), "/")
+		for g, pattern := range pending {
+			if matchSegments(pattern, segs) {
+				delete(pending, g)
+			}
+		}
+		if len(pending) == 0 {
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("checking required artifacts: %w", err)
+	}
+	var missing []string
+	for _, g := range globs {
+		if _, ok := pending[g]; ok {
+			missing = append(missing, g)
+		}
+	}
+	return missing, nil
+}
+
+// matchSegments reports whether the path segments match the pattern
+// segments, where a "**" pattern segment matches zero or more of them.
+func matchSegments(pattern, segs []string) bool {
+	if len(pattern) == 0 {
+		return len(segs) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pattern[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segs[1:])
+}
+
+// checkArtifacts verifies a passing phase's required artifacts in the
+// worktree. When a glob matches nothing, the signal is downgraded to
+// NEEDS_WORK with feedback listing the missing globs, so the phase is
+// retried like any other rejected work. It returns the missing globs.
+func checkArtifacts(phase PhaseDefinition, wtPath string, signal *provider.Signal) ([]string, error) {
+	if signal.Status != provider.StatusPass || len(phase.RequiredArtifacts) == 0 {
+		return nil, nil
+	}
+	missing, err := missingArtifacts(wtPath, phase.RequiredArtifacts)
+	if err != nil || len(missing) == 0 {
+		return nil, err
+	}
+	signal.Status = provider.StatusNeedsWork
+	signal.Feedback = fmt.Sprintf("artifact check failed: %s reported PASS but no file matches %s. Write the missing files.",
+		phase.Name, strings.Join(missing, ", "))
+	return missing, nil
+}
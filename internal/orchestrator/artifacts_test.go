@@ -0,0 +1,195 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// writeFiles creates each worktree-relative path under dir.
+func writeFiles(t *testing.T, dir string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMissingArtifacts(t *testing.T) {
+	// Given a worktree with a design doc, a nested test file, and git metadata
+	dir := t.TempDir()
+	writeFiles(t, dir, "docs/design.md", "internal/store/store_test.go", ".git/config")
+
+	tests := []struct {
+		globs []string
+		want  []string
+	}{
+		{globs: []string{"docs/design.md"}},
+		{globs: []string{"docs/*.md", "**/*_test.go"}},
+		{globs: []string{"**/store_test.go", "internal/**"}},
+		{globs: []string{"**/config"}, want: []string{"**/config"}},
+		{globs: []string{"*_test.go", "docs/api.md", "docs/*.md"}, want: []string{"*_test.go", "docs/api.md"}},
+		{globs: nil},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.globs, ","), func(t *testing.T) {
+			// When the globs are checked
+			got, err := missingArtifacts(dir, tt.globs)
+
+			// Then only the globs without a matching file are reported, .git excluded
+			if err != nil {
+				t.Fatalf("missingArtifacts() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("missingArtifacts(%q) = %q, want %q", tt.globs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateArtifact(t *testing.T) {
+	tests := []struct {
+		glob    string
+		wantErr string
+	}{
+		{glob: "docs/**/*.md"},
+		{glob: "", wantErr: "must not be empty"},
+		{glob: "../out.txt", wantErr: "relative to the worktree"},
+		{glob: "/etc/passwd", wantErr: "relative to the worktree"},
+		{glob: "docs/[.md", wantErr: "invalid glob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.glob, func(t *testing.T) {
+			// When the glob is validated
+			err := validateArtifact(tt.glob)
+
+			// Then only malformed or escaping globs are rejected
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("validateArtifact(%q) = %v, want nil", tt.glob, err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("validateArtifact(%q) = %v, want error containing %q", tt.glob, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// artifactProvider answers PASS to every call and writes its files before
+// the call numbered in writeOn (1-based) returns.
+type artifactProvider struct {
+	dir     string
+	writeOn map[int][]string
+	prompts []string
+}
+
+func (p *artifactProvider) Name() string { return "mock" }
+
+func (p *artifactProvider) Execute(_ context.Context, prompt, _ string) (provider.Result, error) {
+	p.prompts = append(p.prompts, prompt)
+	for _, f := range p.writeOn[len(p.prompts)] {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(p.dir, f)), 0o755); err != nil {
+			return provider.Result{}, err
+		}
+		if err := os.WriteFile(filepath.Join(p.dir, f), []byte("x"), 0o644); err != nil {
+			return provider.Result{}, err
+		}
+	}
+	return passResponse().result, nil
+}
+
+func TestRunPipeline_RequiredArtifacts(t *testing.T) {
+	tests := []struct {
+		name        string
+		phases      []PhaseDefinition
+		writeOn     map[int][]string
+		wantCalls   int
+		wantErr     error
+		wantMissing string // Phase whose failed update names the missing glob.
+	}{
+		{
+			name: "artifact present passes",
+			phases: []PhaseDefinition{
+				{Name: "design", Kind: Worker, MaxRetries: 2, RequiredArtifacts: []string{"docs/*.md"}},
+			},
+			writeOn:   map[int][]string{1: {"docs/design.md"}},
+			wantCalls: 1,
+		},
+		{
+			name: "reviewer pass without artifact retries its target",
+			phases: []PhaseDefinition{
+				{Name: "execute", Kind: Worker, MaxRetries: 3},
+				{Name: "review", Kind: Reviewer, MaxRetries: 3, RetryTarget: "execute", RequiredArtifacts: []string{"**/*_test.go"}},
+			},
+			writeOn:     map[int][]string{3: {"pkg/a_test.go"}},
+			wantCalls:   4,
+			wantMissing: "review",
+		},
+		{
+			name: "worker without retry target is rerun with the feedback",
+			phases: []PhaseDefinition{
+				{Name: "design", Kind: Worker, MaxRetries: 3, RequiredArtifacts: []string{"docs/design.md"}},
+			},
+			writeOn:     map[int][]string{2: {"docs/design.md"}},
+			wantCalls:   2,
+			wantMissing: "design",
+		},
+		{
+			name: "retries exhausted fail the pipeline",
+			phases: []PhaseDefinition{
+				{Name: "design", Kind: Worker, MaxRetries: 2, RequiredArtifacts: []string{"docs/design.md"}},
+			},
+			wantCalls:   2,
+			wantErr:     ErrMissingArtifacts,
+			wantMissing: "design",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given phases that promise artifacts and a provider that writes them late or never
+			dir := t.TempDir()
+			p := &artifactProvider{dir: dir, writeOn: tt.writeOn}
+			var updates []StatusUpdate
+			o := New(p,
+				WithPromptLoader(&mockPromptLoader{}),
+				WithPhases(tt.phases),
+				WithWorktreeManager(&mockWorktreeMgr{path: dir}),
+				WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+			)
+
+			// When the pipeline runs
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+			// Then a missing artifact is retried like rejected work, or fails once retries run out
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("RunPipeline() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(p.prompts) != tt.wantCalls {
+				t.Errorf("provider calls = %d, want %d", len(p.prompts), tt.wantCalls)
+			}
+			var flagged bool
+			for _, su := range updates {
+				if len(su.MissingArtifacts) == 0 {
+					continue
+				}
+				flagged = true
+				if su.Phase != tt.wantMissing || su.Status != PhaseFailed || !strings.HasPrefix(su.Signal.Feedback, "artifact check failed") {
+					t.Errorf("artifact update = %s %s %q", su.Phase, su.Status, su.Signal.Feedback)
+				}
+			}
+			if flagged != (tt.wantMissing != "") {
+				t.Errorf("artifact check reported = %v, want %v", flagged, tt.wantMissing != "")
+			}
+		})
+	}
+}
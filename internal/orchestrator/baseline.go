@@ -0,0 +1,129 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/smileynet/capsule/internal/prompt"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// PregateMode selects whether a run checks its gates before any phase.
+type PregateMode string
+
+const (
+	// PregateOff runs no baseline checks.
+	PregateOff PregateMode = ""
+	// PregateFail aborts the run when a required gate fails at baseline.
+	PregateFail PregateMode = "fail"
+	// PregateWarn continues the run, giving the first worker phase the
+	// baseline failures as context.
+	PregateWarn PregateMode = "warn"
+)
+
+// BaselinePhase names the pseudo-phase that runs every gate once in the
+// fresh worktree, before any provider phase. It is reported through status
+// callbacks and the worklog but is not a PhaseResult.
+const BaselinePhase = "baseline"
+
+// ErrBaselineFailed indicates that WithPregate found a required gate
+// failing before any phase ran: the repository was already broken. It is
+// wrapped in a PipelineError for BaselinePhase.
+var ErrBaselineFailed = errors.New("gates fail before any changes")
+
+// WithPregate runs every gate phase once in each fresh worktree before the
+// first phase, so a repository that is already broken is told apart from
+// one the run broke. A resumed run has no fresh worktree and skips them.
+func WithPregate(mode PregateMode) Option {
+	return func(o *Orchestrator) { o.pregate = mode }
+}
+
+// runBaseline runs the baseline gate checks selected by WithPregate and
+// records them as one BaselinePhase worklog entry. Gates the caller asked
+// to skip are left out. In PregateFail mode a failing required gate returns
+// an ErrBaselineFailed PipelineError; otherwise the failures, if any, are
+// returned for the first worker's prompt.
+func (o *Orchestrator) runBaseline(ctx context.Context, beadID, wtPath string, requested map[string]bool) (string, error) {
+	if o.pregate == PregateOff {
+		return "", nil
+	}
+	var gates []PhaseDefinition
+	for _, phase := range o.phases {
+		if phase.Kind == Gate && !requested[phase.Name] {
+			gates = append(gates, phase)
+		}
+	}
+	if len(gates) == 0 {
+		return "", nil
+	}
+
+	progress := phaseProgress{total: len(o.phases), phase: BaselinePhase}
+	o.notifyAt(progress, StatusUpdate{BeadID: beadID, Phase: BaselinePhase, Status: PhaseRunning, Attempt: 1, MaxRetry: 1})
+
+	start := time.Now()
+	var failed, required []string
+	var feedback strings.Builder
+	for _, gate := range gates {
+		signal, _, err := o.executePhase(ctx, gate, prompt.Context{BeadID: beadID}, wtPath, 0)
+		if err != nil {
+			return "", &PipelineError{Phase: BaselinePhase, Err: fmt.Errorf("gate %s: %w", gate.Name, err)}
+		}
+		if signal.Status == provider.StatusPass {
+			continue
+		}
+		failed = append(failed, gate.Name)
+		if !gate.Optional {
+			required = append(required, gate.Name)
+		}
+		fmt.Fprintf(&feedback, "%s (%s): %s\n%s\n\n", gate.Name, gate.Command, signal.Summary, strings.TrimSpace(signal.Feedback))
+	}
+	duration := time.Since(start)
+
+	signal := provider.Signal{
+		Status:       provider.StatusPass,
+		Summary:      fmt.Sprintf("%d gates passed", len(gates)),
+		FilesChanged: []string{},
+		Findings:     []provider.Finding{},
+	}
+	if len(failed) > 0 {
+		signal.Status = provider.StatusError
+		signal.Summary = fmt.Sprintf("%d of %d gates failed: %s", len(failed), len(gates), strings.Join(failed, ", "))
+		signal.Feedback = strings.TrimSpace(feedback.String())
+	}
+	o.logPhaseEntry(wtPath, BaselinePhase, 0, signal, provider.Usage{}, duration)
+
+	status := PhasePassed
+	switch {
+	case len(required) > 0 && o.pregate == PregateFail:
+		status = PhaseError
+	case len(failed) > 0:
+		status = PhaseFailed
+	}
+	o.notifyAt(progress, StatusUpdate{
+		BeadID: beadID, Phase: BaselinePhase,
+		Status:  status,
+		Attempt: 1, MaxRetry: 1,
+		Duration: duration, Signal: &signal,
+	})
+	if status == PhaseError {
+		return "", &PipelineError{
+			Phase: BaselinePhase, Signal: signal,
+			Err: fmt.Errorf("%w: %s", ErrBaselineFailed, strings.Join(required, ", ")),
+		}
+	}
+	return signal.Feedback, nil
+}
+
+// withBaseline appends baseline gate failures to a composed worker prompt,
+// so the worker knows which failures it did not cause.
+func withBaseline(composed, baseline string) string {
+	if baseline == "" {
+		return composed
+	}
+	return composed + "\n\n## Baseline gate failures\n\n" +
+		"These gates already failed in the repository before any changes were made:\n\n" +
+		baseline + "\n"
+}
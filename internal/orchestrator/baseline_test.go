@@ -0,0 +1,147 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+func TestRunPipeline_Pregate(t *testing.T) {
+	gatePass := provider.Signal{Status: provider.StatusPass, Summary: "ok", FilesChanged: []string{}, Findings: []provider.Finding{}}
+	gateFail := provider.Signal{
+		Status: provider.StatusError, Summary: "exit status 1", Feedback: "--- FAIL: TestOld",
+		FilesChanged: []string{}, Findings: []provider.Finding{},
+	}
+	tests := []struct {
+		name         string
+		mode         PregateMode
+		lintOptional bool
+		baseline     []provider.Signal // lint, then test.
+		wantErr      bool
+		wantStatus   PhaseStatus
+		wantWarned   bool
+	}{
+		{name: "gates pass", mode: PregateFail, baseline: []provider.Signal{gatePass, gatePass}, wantStatus: PhasePassed},
+		{name: "required gate fails", mode: PregateFail, baseline: []provider.Signal{gatePass, gateFail}, wantErr: true, wantStatus: PhaseError},
+		{name: "optional gate fails", mode: PregateFail, lintOptional: true, baseline: []provider.Signal{gateFail, gatePass}, wantStatus: PhaseFailed, wantWarned: true},
+		{name: "warn mode continues", mode: PregateWarn, baseline: []provider.Signal{gatePass, gateFail}, wantStatus: PhaseFailed, wantWarned: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a pipeline with two workers and two gates, checked at baseline
+			gr := &mockGateRunner{signals: append(tt.baseline, gatePass, gatePass)}
+			sp := &sequenceProvider{responses: nPassResponses(2)}
+			wl := &mockWorklogMgr{}
+			var updates []StatusUpdate
+			o := New(sp,
+				WithPromptLoader(&mockPromptLoader{}),
+				WithWorklogManager(wl),
+				WithPhases([]PhaseDefinition{
+					{Name: "test-writer", Kind: Worker},
+					{Name: "execute", Kind: Worker},
+					{Name: "lint", Kind: Gate, Command: "make lint", Optional: tt.lintOptional},
+					{Name: "test", Kind: Gate, Command: "go test ./..."},
+				}),
+				WithGateRunner(gr),
+				WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+				WithPregate(tt.mode),
+			)
+
+			// When RunPipeline executes
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+			// Then a failing required gate aborts before any provider call
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var pe *PipelineError
+				if !errors.Is(err, ErrBaselineFailed) || !errors.As(err, &pe) || pe.Phase != BaselinePhase {
+					t.Errorf("error = %v, want ErrBaselineFailed in the baseline phase", err)
+				}
+				if !strings.Contains(err.Error(), "test") || len(sp.calls) != 0 {
+					t.Errorf("error = %v after %d provider calls, want it to name the test gate before any call", err, len(sp.calls))
+				}
+			}
+
+			// And the baseline is one worklog entry ahead of the phases
+			if len(wl.entries) == 0 || wl.entries[0].Name != BaselinePhase || wl.entries[0].Attempt != 0 {
+				t.Fatalf("worklog entries = %+v, want a baseline entry first", wl.entries)
+			}
+
+			// And it is reported as its own phase, running then finished
+			var got []PhaseStatus
+			for _, su := range updates {
+				if su.Phase == BaselinePhase {
+					got = append(got, su.Status)
+				}
+			}
+			if len(got) != 2 || got[0] != PhaseRunning || got[1] != tt.wantStatus {
+				t.Errorf("baseline updates = %v, want [running %s]", got, tt.wantStatus)
+			}
+
+			// And only the first worker is told about failures it didn't cause
+			if tt.wantErr {
+				return
+			}
+			warned := strings.Contains(sp.calls[0].prompt, "Baseline gate failures") &&
+				strings.Contains(sp.calls[0].prompt, "--- FAIL: TestOld")
+			if warned != tt.wantWarned {
+				t.Errorf("first worker prompt = %q, want baseline failures %v", sp.calls[0].prompt, tt.wantWarned)
+			}
+			if strings.Contains(sp.calls[1].prompt, "Baseline gate failures") {
+				t.Errorf("second worker prompt = %q, want no baseline failures", sp.calls[1].prompt)
+			}
+		})
+	}
+}
+
+func TestRunPipeline_PregateSkipped(t *testing.T) {
+	tests := []struct {
+		name  string
+		mode  PregateMode
+		input PipelineInput
+	}{
+		{name: "off", mode: PregateOff, input: PipelineInput{BeadID: "cap-1"}},
+		{name: "skipped gate", mode: PregateFail, input: PipelineInput{BeadID: "cap-1", SkipPhases: []string{"test"}}},
+		{name: "resumed worktree", mode: PregateFail, input: PipelineInput{BeadID: "cap-1", Resume: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a pipeline with one gate and only one gate result
+			gr := &mockGateRunner{signals: []provider.Signal{
+				{Status: provider.StatusPass, Summary: "ok", FilesChanged: []string{}, Findings: []provider.Finding{}},
+			}}
+			var updates []StatusUpdate
+			o := New(&sequenceProvider{responses: nPassResponses(1)},
+				WithPromptLoader(&mockPromptLoader{}),
+				WithWorktreeManager(&mockWorktreeMgr{path: t.TempDir()}),
+				WithPhases([]PhaseDefinition{
+					{Name: "execute", Kind: Worker},
+					{Name: "test", Kind: Gate, Command: "go test ./..."},
+				}),
+				WithGateRunner(gr),
+				WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+				WithPregate(tt.mode),
+			)
+
+			// When RunPipeline executes
+			if _, err := o.RunPipeline(context.Background(), tt.input); err != nil {
+				t.Fatalf("RunPipeline() error = %v", err)
+			}
+
+			// Then no baseline checks ran
+			if len(gr.calls) > 1 {
+				t.Errorf("gate ran %d times, want at most once (not at baseline)", len(gr.calls))
+			}
+			for _, su := range updates {
+				if su.Phase == BaselinePhase {
+					t.Errorf("got baseline update %+v, want none", su)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// PhaseCommitter commits the changes in a bead's worktree on its capsule
+// branch. A WorktreeManager that implements it supports WithPerPhaseCommits.
+type PhaseCommitter interface {
+	// CommitAll commits every change in the worktree for id with msg. It
+	// reports false when there was nothing to commit.
+	CommitAll(id, msg string) (bool, error)
+}
+
+// WithPerPhaseCommits commits the worktree after each phase that passes,
+// gates included, so the work of completed phases is kept on the capsule
+// branch if a later phase fails, and each phase can be diffed on its own.
+// It needs a WorktreeManager that implements PhaseCommitter.
+func WithPerPhaseCommits(enabled bool) Option {
+	return func(o *Orchestrator) { o.perPhaseCommits = enabled }
+}
+
+// commitPhase commits the worktree after phase passed in the pipeline, as
+// "<bead-id> [<phase>]: <summary>", when per-phase commits are on. Runs
+// outside the pipeline, such as conflict resolution, are not committed. A
+// phase that changed nothing is logged as a warning and a failed commit is
+// reported as one; neither fails the run, since the final merge still
+// carries the work.
+func (o *Orchestrator) commitPhase(progress phaseProgress, beadID, phase string, signal provider.Signal) {
+	if !o.perPhaseCommits || progress.total == 0 {
+		return
+	}
+	c, ok := o.worktreeMgr.(PhaseCommitter)
+	if !ok {
+		return
+	}
+	summary, _, _ := strings.Cut(strings.TrimSpace(signal.Summary), "\n")
+	if summary == "" {
+		summary = "passed"
+	}
+	committed, err := c.CommitAll(beadID, fmt.Sprintf("%s [%s]: %s", beadID, phase, summary))
+	switch {
+	case err != nil:
+		o.notify(StatusUpdate{BeadID: beadID, Warning: fmt.Sprintf("committing %s: %v", phase, err)})
+	case !committed:
+		o.logger.Warn("phase changed nothing; no commit", "bead", beadID, "phase", phase)
+	default:
+		o.logger.Debug("phase committed", "bead", beadID, "phase", phase)
+	}
+}
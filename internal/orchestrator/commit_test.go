@@ -0,0 +1,144 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// committingWorktreeMgr is a mockWorktreeMgr that records per-phase
+// commits, reporting committed and err for each.
+type committingWorktreeMgr struct {
+	mockWorktreeMgr
+	committed bool
+	err       error
+	messages  []string
+}
+
+func (m *committingWorktreeMgr) CommitAll(_, msg string) (bool, error) {
+	m.messages = append(m.messages, msg)
+	return m.committed, m.err
+}
+
+func TestRunPipeline_PerPhaseCommits(t *testing.T) {
+	tests := []struct {
+		name         string
+		enabled      bool
+		committed    bool
+		err          error
+		wantMessages []string
+		wantWarning  string
+	}{
+		{
+			name:      "commits each passing phase",
+			enabled:   true,
+			committed: true,
+			wantMessages: []string{
+				"cap-1 [worker]: passed",
+				"cap-1 [worker]: passed",
+				"cap-1 [reviewer]: passed",
+				"cap-1 [merge]: passed",
+			},
+		},
+		{
+			name: "disabled",
+		},
+		{
+			name:    "nothing to commit",
+			enabled: true,
+			wantMessages: []string{
+				"cap-1 [worker]: passed",
+				"cap-1 [worker]: passed",
+				"cap-1 [reviewer]: passed",
+				"cap-1 [merge]: passed",
+			},
+		},
+		{
+			name:    "commit fails",
+			enabled: true,
+			err:     errors.New("hook rejected"),
+			wantMessages: []string{
+				"cap-1 [worker]: passed",
+				"cap-1 [worker]: passed",
+				"cap-1 [reviewer]: passed",
+				"cap-1 [merge]: passed",
+			},
+			wantWarning: "committing worker: hook rejected",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a pipeline whose reviewer sends its worker back once
+			wt := &committingWorktreeMgr{mockWorktreeMgr: mockWorktreeMgr{path: t.TempDir()}, committed: tt.committed, err: tt.err}
+			var warnings []string
+			sp := &sequenceProvider{responses: []mockResponse{
+				passResponse(),                      // worker
+				needsWorkResponse("add edge cases"), // reviewer, attempt 1
+				passResponse(),                      // worker retry
+				passResponse(),                      // reviewer, attempt 2
+				passResponse(),                      // merge
+			}}
+			o := New(sp,
+				WithPromptLoader(&mockPromptLoader{}),
+				WithWorktreeManager(wt),
+				WithPhases([]PhaseDefinition{
+					{Name: "worker", Kind: Worker, MaxRetries: 1},
+					{Name: "reviewer", Kind: Reviewer, MaxRetries: 3, RetryTarget: "worker"},
+					{Name: "merge", Kind: Worker, MaxRetries: 1},
+				}),
+				WithPerPhaseCommits(tt.enabled),
+				WithStatusCallback(func(su StatusUpdate) {
+					if su.Warning != "" {
+						warnings = append(warnings, su.Warning)
+					}
+				}),
+			)
+
+			// When RunPipeline executes
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+			// Then the run succeeds whatever the commits did
+			if err != nil {
+				t.Fatalf("RunPipeline() error = %v", err)
+			}
+			// And each passing phase is committed, but not the failed review
+			if !reflect.DeepEqual(wt.messages, tt.wantMessages) {
+				t.Errorf("commits = %q, want %q", wt.messages, tt.wantMessages)
+			}
+			// And only a failed commit is reported as a warning
+			switch {
+			case tt.wantWarning == "" && len(warnings) > 0:
+				t.Errorf("warnings = %q, want none", warnings)
+			case tt.wantWarning != "" && (len(warnings) == 0 || !strings.Contains(warnings[0], tt.wantWarning)):
+				t.Errorf("warnings = %q, want %q first", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestRunConflictResolution_NoPerPhaseCommits(t *testing.T) {
+	// Given per-phase commits and a conflict resolution that passes
+	wt := &committingWorktreeMgr{mockWorktreeMgr: mockWorktreeMgr{path: t.TempDir()}, committed: true}
+	o := New(&sequenceProvider{responses: nPassResponses(2)},
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(wt),
+		WithPhases([]PhaseDefinition{
+			{Name: "execute", Kind: Worker, MaxRetries: 1},
+			{Name: "sign-off", Kind: Reviewer, MaxRetries: 1, RetryTarget: "execute"},
+		}),
+		WithPerPhaseCommits(true),
+	)
+
+	// When the conflict is resolved
+	err := o.RunConflictResolution(context.Background(), ConflictResolutionInput{BeadID: "cap-1", WorktreePath: t.TempDir()})
+
+	// Then nothing is committed: the resolution runs outside the pipeline
+	if err != nil {
+		t.Fatalf("RunConflictResolution() error = %v", err)
+	}
+	if len(wt.messages) != 0 {
+		t.Errorf("commits = %q, want none", wt.messages)
+	}
+}
@@ -0,0 +1,194 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// A phase condition decides whether the phase runs. Conditions are:
+//
+//	files_match:<glob>   a file in the worktree matches glob (non-recursive)
+//	diff_match:<glob>    a path changed since the base branch matches glob
+//	env:<VAR>            environment variable VAR is non-empty
+//	not:<cond>           cond is not met
+//	any:(<cond>, ...)    at least one cond is met
+//	all:(<cond>, ...)    every cond is met
+//
+// The parentheses around an any/all list are optional at the top level and
+// required when a list is nested inside another one.
+
+// condition is a parsed phase condition.
+type condition struct {
+	kind string      // Predicate or combinator name.
+	arg  string      // Glob or variable name of a predicate.
+	subs []condition // Operands of not, any, and all.
+}
+
+// conditionEnv is what conditions are evaluated against.
+type conditionEnv struct {
+	dir       string                      // Directory files_match globs in.
+	changed   func() ([]string, error)    // Paths changed since the base branch; nil when unknown.
+	lookupEnv func(string) (string, bool) // Environment for env; nil means empty.
+}
+
+// evaluateCondition checks whether a phase's condition is met.
+// Empty condition means always run.
+func evaluateCondition(cond string, env conditionEnv) (bool, error) {
+	if cond == "" {
+		return true, nil
+	}
+	c, err := parseCondition(cond)
+	if err != nil {
+		return false, err
+	}
+	met, err := c.eval(env)
+	if err != nil {
+		return false, fmt.Errorf("evaluating condition %q: %w", cond, err)
+	}
+	return met, nil
+}
+
+// parseCondition parses s. Errors name the sub-condition that is invalid.
+func parseCondition(s string) (condition, error) {
+	s = strings.TrimSpace(s)
+	kind, arg, ok := strings.Cut(s, ":")
+	if !ok {
+		return condition{}, fmt.Errorf("unrecognized condition %q (expected <kind>:<argument>)", s)
+	}
+	c := condition{kind: kind, arg: arg}
+	switch kind {
+	case "files_match", "diff_match":
+		if arg == "" {
+			return c, fmt.Errorf("%s condition requires a glob pattern", kind)
+		}
+		if _, err := filepath.Match(arg, "test"); err != nil {
+			return c, fmt.Errorf("invalid glob pattern %q in %q: %w", arg, s, err)
+		}
+	case "env":
+		if arg == "" {
+			return c, errors.New("env condition requires a variable name")
+		}
+	case "not":
+		sub, err := parseCondition(arg)
+		if err != nil {
+			return c, fmt.Errorf("in %q: %w", s, err)
+		}
+		c.subs = []condition{sub}
+	case "any", "all":
+		parts, err := splitConditions(arg)
+		if err != nil {
+			return c, fmt.Errorf("in %q: %w", s, err)
+		}
+		for _, part := range parts {
+			sub, err := parseCondition(part)
+			if err != nil {
+				return c, fmt.Errorf("in %q: %w", s, err)
+			}
+			c.subs = append(c.subs, sub)
+		}
+	default:
+		return c, fmt.Errorf("unrecognized condition %q (expected files_match, diff_match, env, not, any, or all)", s)
+	}
+	return c, nil
+}
+
+// splitConditions splits an any/all operand list on the commas outside
+// parentheses, after removing one pair of enclosing parentheses.
+func splitConditions(list string) ([]string, error) {
+	list = strings.TrimSpace(list)
+	if strings.HasPrefix(list, "(") && strings.HasSuffix(list, ")") {
+		list = list[1 : len(list)-1]
+	}
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %q", list)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in %q", list)
+	}
+	parts = append(parts, list[start:])
+	for _, p := range parts {
+		if strings.TrimSpace(p) == "" {
+			return nil, fmt.Errorf("empty condition in list %q", list)
+		}
+	}
+	return parts, nil
+}
+
+// eval reports whether c is met in env.
+func (c condition) eval(env conditionEnv) (bool, error) {
+	switch c.kind {
+	case "files_match":
+		matches, err := filepath.Glob(filepath.Join(env.dir, c.arg))
+		if err != nil {
+			return false, err
+		}
+		return len(matches) > 0, nil
+	case "diff_match":
+		if env.changed == nil {
+			return false, errors.New("diff_match needs a worktree to diff against the base branch")
+		}
+		paths, err := env.changed()
+		if err != nil {
+			return false, fmt.Errorf("diff_match:%s: %w", c.arg, err)
+		}
+		for _, p := range paths {
+			if diffMatch(c.arg, p) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "env":
+		if env.lookupEnv == nil {
+			return false, nil
+		}
+		v, _ := env.lookupEnv(c.arg)
+		return v != "", nil
+	case "not":
+		met, err := c.subs[0].eval(env)
+		return !met, err
+	case "any", "all":
+		for _, sub := range c.subs {
+			met, err := sub.eval(env)
+			if err != nil {
+				return false, err
+			}
+			if met == (c.kind == "any") {
+				return met, nil
+			}
+		}
+		return c.kind == "all", nil
+	}
+	return false, fmt.Errorf("unrecognized condition %q", c.kind)
+}
+
+// diffMatch reports whether the slash-separated path p matches pattern. A
+// pattern without a slash also matches the file name in any directory, so
+// "*.md" matches "docs/guide.md".
+func diffMatch(pattern, p string) bool {
+	if ok, _ := path.Match(pattern, p); ok {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(p))
+		return ok
+	}
+	return false
+}
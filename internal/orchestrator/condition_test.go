@@ -0,0 +1,236 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+func TestEvaluateCondition_Predicates(t *testing.T) {
+	// Given a worktree with main.go, a diff touching docs, and RUN_DOCS set
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := conditionEnv{
+		dir:     dir,
+		changed: func() ([]string, error) { return []string{"docs/guide.md", "migrations/001.sql"}, nil },
+		lookupEnv: func(name string) (string, bool) {
+			if name == "RUN_DOCS" {
+				return "1", true
+			}
+			return "", false
+		},
+	}
+
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{"files_match:*.go", true},
+		{"diff_match:*.md", true},
+		{"diff_match:docs/*.md", true},
+		{"diff_match:migrations/*.sql", true},
+		{"diff_match:*.go", false},
+		{"diff_match:*/guide.md", true},
+		{"env:RUN_DOCS", true},
+		{"env:UNSET", false},
+		{"not:env:UNSET", true},
+		{"not:files_match:*.go", false},
+		{"any:diff_match:*.go,env:RUN_DOCS", true},
+		{"any:(diff_match:*.go, env:UNSET)", false},
+		{"all:(files_match:*.go, diff_match:*.md)", true},
+		{"all:(files_match:*.go, not:diff_match:*.md)", false},
+		{"all:(any:(env:UNSET, env:RUN_DOCS), not:diff_match:*.py)", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cond, func(t *testing.T) {
+			// When the condition is evaluated
+			got, err := evaluateCondition(tt.cond, env)
+
+			// Then it reports whether the phase runs
+			if err != nil {
+				t.Fatalf("evaluateCondition() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("evaluateCondition(%q) = %v, want %v", tt.cond, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCondition_ErrorsNameSubCondition(t *testing.T) {
+	tests := []struct {
+		cond    string
+		wantMsg string
+	}{
+		{"any:(files_match:*.go, bogus:x)", `"bogus:x"`},
+		{"not:diff_match:", "diff_match condition requires a glob pattern"},
+		{"all:(env:A, any:(files_match:[, env:B))", `invalid glob pattern "["`},
+		{"any:(env:A,)", "empty condition in list"},
+		{"all:(env:A, any:(env:B)", "unbalanced parentheses"},
+		{"env:", "env condition requires a variable name"},
+		{"files_match", "expected <kind>:<argument>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cond, func(t *testing.T) {
+			// When an invalid condition is parsed
+			_, err := parseCondition(tt.cond)
+
+			// Then the error points at the offending part
+			if err == nil {
+				t.Fatal("parseCondition() error = nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %q, want mention of %q", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestEvaluateCondition_DiffMatchErrors(t *testing.T) {
+	// Given no change source
+	// When diff_match is evaluated
+	_, err := evaluateCondition("diff_match:*.md", conditionEnv{})
+
+	// Then it fails rather than silently skipping
+	if err == nil || !strings.Contains(err.Error(), "diff_match needs a worktree") {
+		t.Errorf("error = %v, want missing worktree error", err)
+	}
+
+	// Given a change source that fails
+	env := conditionEnv{changed: func() ([]string, error) { return nil, errors.New("bad revision") }}
+
+	// Then the git error is reported
+	if _, err := evaluateCondition("any:(env:X, diff_match:*.md)", env); err == nil || !strings.Contains(err.Error(), "bad revision") {
+		t.Errorf("error = %v, want bad revision", err)
+	}
+}
+
+// mockChangeLister records the base branch diff_match was evaluated against.
+type mockChangeLister struct {
+	paths []string
+	bases []string
+}
+
+func (m *mockChangeLister) ChangedSince(_, base string) ([]string, error) {
+	m.bases = append(m.bases, base)
+	return m.paths, nil
+}
+
+func TestRunPipeline_DiffMatchUsesInputBaseBranch(t *testing.T) {
+	// Given a docs phase conditioned on markdown changes and a diff without any
+	changes := &mockChangeLister{paths: []string{"main.go"}}
+	sp := &sequenceProvider{responses: nPassResponses(1)}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{path: t.TempDir()}),
+		WithChangeLister(changes),
+		WithPhases([]PhaseDefinition{
+			{Name: "execute", Kind: Worker, MaxRetries: 1},
+			{Name: "docs", Kind: Worker, MaxRetries: 1, Condition: "diff_match:*.md"},
+		}),
+	)
+
+	// When the pipeline runs against a develop base branch
+	out, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1", BaseBranch: "develop"})
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then the diff is taken against develop and the docs phase is skipped
+	if len(changes.bases) != 1 || changes.bases[0] != "develop" {
+		t.Errorf("diff bases = %v, want [develop]", changes.bases)
+	}
+	if len(sp.calls) != 1 {
+		t.Errorf("provider called %d times, want 1", len(sp.calls))
+	}
+	if last := out.PhaseResults[len(out.PhaseResults)-1]; last.PhaseName != "docs" || last.Signal.Summary != "skipped by condition" {
+		t.Errorf("last result = %+v, want docs skipped by condition", last)
+	}
+}
+
+func TestRunPipeline_SkipReasons(t *testing.T) {
+	skipWithReason := mockResponse{result: provider.Result{
+		Output: `{"schema_version":1,"status":"SKIP","reason":"no frontend files","feedback":"nothing to build","files_changed":[],"summary":"skipped"}`,
+	}}
+	tests := []struct {
+		name       string
+		phase      PhaseDefinition
+		responses  []mockResponse
+		wantReason string
+		wantLogged bool // The worklog entry carries the reason.
+	}{
+		{
+			name:       "condition not met",
+			phase:      PhaseDefinition{Name: "frontend", Kind: Worker, MaxRetries: 1, Condition: "diff_match:web/*"},
+			wantReason: "condition not met: diff_match:web/*",
+			wantLogged: true,
+		},
+		{
+			name:       "phase returns SKIP with a reason",
+			phase:      PhaseDefinition{Name: "frontend", Kind: Worker, MaxRetries: 1},
+			responses:  []mockResponse{skipWithReason},
+			wantReason: "no frontend files",
+			wantLogged: true,
+		},
+		{
+			name:       "phase returns SKIP without a reason",
+			phase:      PhaseDefinition{Name: "frontend", Kind: Worker, MaxRetries: 1},
+			responses:  []mockResponse{{result: provider.Result{Output: makeSignalJSON(provider.StatusSkip, "n/a", "skipped")}}},
+			wantReason: "",
+		},
+		{
+			name:       "optional phase errors",
+			phase:      PhaseDefinition{Name: "frontend", Kind: Worker, MaxRetries: 1, Optional: true},
+			responses:  []mockResponse{errorResponse("build broke")},
+			wantReason: "optional phase failed: error occurred",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a pipeline whose frontend phase is skipped
+			wl := &mockWorklogMgr{}
+			var skipped []StatusUpdate
+			o := New(&sequenceProvider{responses: tt.responses},
+				WithPromptLoader(&mockPromptLoader{}),
+				WithWorktreeManager(&mockWorktreeMgr{path: t.TempDir()}),
+				WithWorklogManager(wl),
+				WithChangeLister(&mockChangeLister{paths: []string{"main.go"}}),
+				WithPhases([]PhaseDefinition{tt.phase}),
+				WithStatusCallback(func(su StatusUpdate) {
+					if su.Status == PhaseSkipped {
+						skipped = append(skipped, su)
+					}
+				}),
+			)
+
+			// When the pipeline runs
+			out, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+			if err != nil {
+				t.Fatalf("RunPipeline() error = %v", err)
+			}
+
+			// Then the status update and phase result carry the reason
+			if len(skipped) != 1 || skipped[0].SkipReason != tt.wantReason {
+				t.Fatalf("skipped updates = %+v, want one with reason %q", skipped, tt.wantReason)
+			}
+			if got := out.PhaseResults[0].SkipReason; got != tt.wantReason {
+				t.Errorf("phase result reason = %q, want %q", got, tt.wantReason)
+			}
+
+			// And so does the worklog entry for a skip
+			if tt.wantLogged {
+				if len(wl.entries) != 1 || wl.entries[0].Reason != tt.wantReason {
+					t.Errorf("worklog entries = %+v, want one with reason %q", wl.entries, tt.wantReason)
+				}
+			}
+		})
+	}
+}
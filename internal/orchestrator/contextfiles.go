@@ -0,0 +1,80 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultContextFileBytes caps how much of each context file is exposed to
+// prompt templates.
+const DefaultContextFileBytes = 16 * 1024
+
+// WithContextFiles reads the given repo-relative files from the worktree at
+// pipeline start and exposes them to prompt templates as
+// prompt.Context.ContextFiles, keyed by file name without extension (e.g.
+// "CONVENTIONS" for CONVENTIONS.md). Each file is truncated to maxBytes;
+// maxBytes <= 0 uses DefaultContextFileBytes.
+func WithContextFiles(paths []string, maxBytes int) Option {
+	return func(o *Orchestrator) {
+		o.contextFiles = paths
+		o.contextFileBytes = maxBytes
+	}
+}
+
+// ContextFileKey returns the prompt.Context.ContextFiles key for path.
+func ContextFileKey(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// loadContextFiles snapshots the configured context files in wtPath. Every
+// configured file gets a key, so templates can reference it even when the
+// file is missing; missing files map to "". Unreadable files are reported as
+// status warnings. The snapshot is taken once per pipeline: edits made by
+// later phases are deliberately not reflected in their prompts.
+func (o *Orchestrator) loadContextFiles(beadID, wtPath string) map[string]string {
+	if len(o.contextFiles) == 0 {
+		return nil
+	}
+	maxBytes := o.contextFileBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultContextFileBytes
+	}
+
+	files := make(map[string]string, len(o.contextFiles))
+	for _, path := range o.contextFiles {
+		key := ContextFileKey(path)
+		files[key] = ""
+		if !filepath.IsLocal(path) {
+			o.notify(StatusUpdate{BeadID: beadID, Warning: fmt.Sprintf("context file %q: must be a relative path inside the repository", path)})
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(wtPath, path))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			o.notify(StatusUpdate{BeadID: beadID, Warning: fmt.Sprintf("context file %q: %v", path, err)})
+			continue
+		}
+		files[key] = truncateContext(data, maxBytes)
+	}
+	return files
+}
+
+// truncateContext returns data as a string of at most maxBytes, cut on a rune
+// boundary and marked when anything was dropped.
+func truncateContext(data []byte, maxBytes int) string {
+	if len(data) <= maxBytes {
+		return string(data)
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n[truncated: showing %d of %d bytes]\n", data[:cut], cut, len(data))
+}
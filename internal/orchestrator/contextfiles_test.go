@@ -0,0 +1,103 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/prompt"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+func TestRunPipeline_ContextFiles(t *testing.T) {
+	// Given a worktree with a conventions file, an unreadable context file,
+	// and a first phase that rewrites the conventions file
+	wtPath := t.TempDir()
+	conventions := filepath.Join(wtPath, "CONVENTIONS.md")
+	if err := os.WriteFile(conventions, []byte("use tabs"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(wtPath, "NOTES.md"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]map[string]string)
+	loader := &mockPromptLoader{composeFunc: func(phaseName string, ctx prompt.Context) (string, error) {
+		seen[phaseName] = ctx.ContextFiles
+		return "prompt:" + phaseName, nil
+	}}
+	p := &provider.MockProvider{NameVal: "mock", ExecuteFunc: func(context.Context, string, string) (provider.Result, error) {
+		if err := os.WriteFile(conventions, []byte("use spaces"), 0o644); err != nil {
+			return provider.Result{}, err
+		}
+		return passResponse().result, nil
+	}}
+	var warnings []string
+	o := New(p,
+		WithPromptLoader(loader),
+		WithWorktreeManager(&mockWorktreeMgr{path: wtPath}),
+		WithPhases(twoPhases()),
+		WithContextFiles([]string{"CONVENTIONS.md", "docs/ARCHITECTURE.md", "NOTES.md"}, 0),
+		WithStatusCallback(func(su StatusUpdate) {
+			if su.Warning != "" {
+				warnings = append(warnings, su.Warning)
+			}
+		}),
+	)
+
+	// When the pipeline runs
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"}); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then every phase sees the snapshot taken at pipeline start
+	for _, phase := range []string{"worker", "reviewer"} {
+		files := seen[phase]
+		if files["CONVENTIONS"] != "use tabs" {
+			t.Errorf("%s: CONVENTIONS = %q, want the pre-pipeline contents", phase, files["CONVENTIONS"])
+		}
+		// And missing or unreadable files still have a key
+		for _, key := range []string{"ARCHITECTURE", "NOTES"} {
+			if v, ok := files[key]; !ok || v != "" {
+				t.Errorf("%s: %s = %q, %v; want empty and present", phase, key, v, ok)
+			}
+		}
+	}
+	// And only the unreadable file produced a warning
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "NOTES.md") {
+		t.Errorf("warnings = %q, want one for NOTES.md", warnings)
+	}
+}
+
+func TestTruncateContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		maxBytes int
+		want     string
+	}{
+		{name: "under cap", data: "abc", maxBytes: 3, want: "abc"},
+		{name: "over cap", data: "abcdef", maxBytes: 4, want: "abcd\n[truncated: showing 4 of 6 bytes]\n"},
+		{name: "rune boundary", data: "aé", maxBytes: 2, want: "a\n[truncated: showing 1 of 3 bytes]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateContext([]byte(tt.data), tt.maxBytes); got != tt.want {
+				t.Errorf("truncateContext() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextFileKey(t *testing.T) {
+	for path, want := range map[string]string{
+		"CONVENTIONS.md":       "CONVENTIONS",
+		"docs/ARCHITECTURE.md": "ARCHITECTURE",
+		"STYLE":                "STYLE",
+	} {
+		if got := ContextFileKey(path); got != want {
+			t.Errorf("ContextFileKey(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
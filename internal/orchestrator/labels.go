@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Bead label prefixes that override the run's settings for one bead.
+const (
+	LabelProvider = "capsule:provider=" // e.g. capsule:provider=kiro
+	LabelTimeout  = "capsule:timeout="  // e.g. capsule:timeout=20m
+	LabelWorkdir  = "capsule:dir="      // e.g. capsule:dir=services/auth
+)
+
+// ProviderFactory creates a named provider whose invocations time out after
+// timeout. It backs per-bead label overrides.
+type ProviderFactory func(name string, timeout time.Duration) (Provider, error)
+
+// WithProviderFactory enables per-bead provider overrides from the
+// capsule:provider and capsule:timeout labels in PipelineInput.Bead.Labels.
+// defaultTimeout is used when a bead overrides only the provider.
+func WithProviderFactory(f ProviderFactory, defaultTimeout time.Duration) Option {
+	return func(o *Orchestrator) {
+		o.providerFactory = f
+		o.defaultTimeout = defaultTimeout
+	}
+}
+
+// beadOverrides holds the settings parsed from a bead's labels.
+type beadOverrides struct {
+	Provider string
+	Timeout  time.Duration
+	Workdir  string
+}
+
+// parseBeadLabels extracts bead overrides from labels. Malformed values
+// are reported as warnings and ignored; the last valid label of a kind wins.
+func parseBeadLabels(labels []string) (beadOverrides, []string) {
+	var ov beadOverrides
+	var warnings []string
+	for _, label := range labels {
+		if name, ok := strings.CutPrefix(label, LabelProvider); ok {
+			if name == "" {
+				warnings = append(warnings, fmt.Sprintf("ignoring label %q: empty provider name", label))
+				continue
+			}
+			ov.Provider = name
+		}
+		if v, ok := strings.CutPrefix(label, LabelTimeout); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				warnings = append(warnings, fmt.Sprintf("ignoring label %q: want a positive duration like 20m", label))
+				continue
+			}
+			ov.Timeout = d
+		}
+		if dir, ok := strings.CutPrefix(label, LabelWorkdir); ok {
+			if !filepath.IsLocal(dir) {
+				warnings = append(warnings, fmt.Sprintf("ignoring label %q: want a relative path inside the repository", label))
+				continue
+			}
+			ov.Workdir = filepath.Clean(dir)
+		}
+	}
+	return ov, warnings
+}
+
+// forBead returns a copy of o to run input with: its working directory
+// follows the bead's capsule:dir label or workdir prefix mapping, and its
+// default provider follows the bead's provider label overrides. Problems
+// with the labels are reported as warnings and the defaults are kept.
+func (o *Orchestrator) forBead(input PipelineInput) *Orchestrator {
+	ov, warnings := parseBeadLabels(input.Bead.Labels)
+	for _, w := range warnings {
+		o.notify(StatusUpdate{BeadID: input.BeadID, Warning: w})
+	}
+	bo := *o
+	bo.beadWorkdir = ov.Workdir
+	if bo.beadWorkdir == "" {
+		bo.beadWorkdir = o.prefixWorkdir(input.BeadID)
+	}
+	if ov.Timeout > 0 && bo.phaseTimeout > 0 {
+		bo.phaseTimeout = ov.Timeout
+	}
+	if o.providerFactory == nil || o.provider == nil || (ov.Provider == "" && ov.Timeout == 0) {
+		return &bo
+	}
+
+	name, timeout := o.provider.Name(), o.defaultTimeout
+	if ov.Provider != "" {
+		name = ov.Provider
+	}
+	if ov.Timeout > 0 {
+		timeout = ov.Timeout
+	}
+	p, err := o.providerFactory(name, timeout)
+	if err != nil {
+		o.notify(StatusUpdate{BeadID: input.BeadID,
+			Warning: fmt.Sprintf("bead label override ignored, using provider %q: %v", o.provider.Name(), err)})
+		return &bo
+	}
+	bo.provider = p
+	return &bo
+}
@@ -0,0 +1,158 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/worklog"
+)
+
+func TestParseBeadLabels(t *testing.T) {
+	tests := []struct {
+		name         string
+		labels       []string
+		want         beadOverrides
+		wantWarnings int
+	}{
+		{name: "no labels"},
+		{name: "unrelated labels", labels: []string{"docs", "capsule:other=x"}},
+		{
+			name:   "provider and timeout",
+			labels: []string{"capsule:provider=kiro", "capsule:timeout=20m"},
+			want:   beadOverrides{Provider: "kiro", Timeout: 20 * time.Minute},
+		},
+		{
+			name:   "workdir",
+			labels: []string{"capsule:dir=services/auth/"},
+			want:   beadOverrides{Workdir: "services/auth"},
+		},
+		{
+			name:         "malformed values warn",
+			labels:       []string{"capsule:provider=", "capsule:timeout=soon", "capsule:timeout=-1m", "capsule:dir=../x", "capsule:dir="},
+			wantWarnings: 5,
+		},
+		{
+			name:   "last label wins",
+			labels: []string{"capsule:provider=kiro", "capsule:provider=claude"},
+			want:   beadOverrides{Provider: "claude"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings := parseBeadLabels(tt.labels)
+			if got != tt.want {
+				t.Errorf("parseBeadLabels() = %+v, want %+v", got, tt.want)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %q, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestRunPipeline_BeadLabelOverrides(t *testing.T) {
+	type call struct {
+		name    string
+		timeout time.Duration
+	}
+	factory := func(calls *[]call) ProviderFactory {
+		return func(name string, timeout time.Duration) (Provider, error) {
+			*calls = append(*calls, call{name, timeout})
+			if name == "nope" {
+				return nil, errors.New(`unknown provider "nope"`)
+			}
+			return &provider.MockProvider{NameVal: name}, nil
+		}
+	}
+
+	tests := []struct {
+		name         string
+		labels       []string
+		wantCalls    []call
+		wantProvider string // Recorded in the worklog.
+		wantWarning  string
+	}{
+		{name: "no labels keeps default", wantProvider: "claude"},
+		{
+			name:         "provider label",
+			labels:       []string{"capsule:provider=kiro"},
+			wantCalls:    []call{{"kiro", 5 * time.Minute}},
+			wantProvider: "kiro",
+		},
+		{
+			name:         "timeout label keeps provider",
+			labels:       []string{"capsule:timeout=1h"},
+			wantCalls:    []call{{"claude", time.Hour}},
+			wantProvider: "claude",
+		},
+		{
+			name:         "unknown provider falls back with warning",
+			labels:       []string{"capsule:provider=nope"},
+			wantCalls:    []call{{"nope", 5 * time.Minute}},
+			wantProvider: "claude",
+			wantWarning:  `unknown provider "nope"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given an orchestrator with a provider factory
+			var calls []call
+			var warnings []string
+			wl := &mockWorklogMgr{}
+			o := New(&provider.MockProvider{NameVal: "claude"},
+				WithPromptLoader(&mockPromptLoader{}),
+				WithWorklogManager(wl),
+				WithPhases(nil),
+				WithProviderFactory(factory(&calls), 5*time.Minute),
+				WithStatusCallback(func(su StatusUpdate) {
+					if su.Warning != "" {
+						warnings = append(warnings, su.Warning)
+					}
+				}),
+			)
+
+			// When RunPipeline runs a bead with labels
+			_, err := o.RunPipeline(context.Background(), PipelineInput{
+				BeadID: "cap-1",
+				Bead:   worklog.BeadContext{TaskID: "cap-1", Labels: tt.labels},
+			})
+			if err != nil {
+				t.Fatalf("RunPipeline() error = %v", err)
+			}
+
+			// Then the factory is asked for the overridden provider
+			if len(calls) != len(tt.wantCalls) {
+				t.Fatalf("factory calls = %v, want %v", calls, tt.wantCalls)
+			}
+			for i := range calls {
+				if calls[i] != tt.wantCalls[i] {
+					t.Errorf("factory call %d = %v, want %v", i, calls[i], tt.wantCalls[i])
+				}
+			}
+			// And the effective provider is recorded in the worklog
+			if wl.bead.Provider != tt.wantProvider {
+				t.Errorf("worklog provider = %q, want %q", wl.bead.Provider, tt.wantProvider)
+			}
+			// And a failed override only warns
+			if tt.wantWarning == "" && len(warnings) > 0 {
+				t.Errorf("warnings = %q, want none", warnings)
+			}
+			if tt.wantWarning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tt.wantWarning)) {
+				t.Errorf("warnings = %q, want one containing %q", warnings, tt.wantWarning)
+			}
+		})
+	}
+
+	// And the orchestrator's default provider is unchanged for later beads
+	o := New(&provider.MockProvider{NameVal: "claude"}, WithProviderFactory(factory(new([]call)), time.Minute))
+	_ = o.forBead(PipelineInput{Bead: worklog.BeadContext{Labels: []string{"capsule:provider=kiro"}}})
+	if o.provider.Name() != "claude" {
+		t.Errorf("default provider = %q after override, want claude", o.provider.Name())
+	}
+}
@@ -0,0 +1,60 @@
+package orchestrator
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+func TestWithLogger_LogsPipelineEvents(t *testing.T) {
+	// Given a pipeline with a worker, a conditional gate, and checkpoints
+	var buf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	passGate := provider.Signal{Status: provider.StatusPass, Feedback: "ok", Summary: "tests passed", FilesChanged: []string{}}
+	o := New(&sequenceProvider{responses: nPassResponses(1)},
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{path: t.TempDir()}),
+		WithGateRunner(&mockGateRunner{signals: []provider.Signal{passGate}}),
+		WithCheckpointStore(&mockCheckpointStore{}),
+		WithLogger(logger),
+		WithPhases([]PhaseDefinition{
+			{Name: "execute", Kind: Worker, MaxRetries: 1},
+			{Name: "test", Kind: Gate, Command: "make test"},
+			{Name: "lint", Kind: Gate, Command: "make lint", Condition: "files_match:*.go"},
+		}),
+	)
+
+	// When the pipeline runs
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"}); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then provider calls, gates, conditions, and checkpoints are logged
+	logs := buf.String()
+	for _, want := range []string{
+		`msg="provider call" bead=cap-1 phase=execute attempt=1 provider=mock prompt_bytes=`,
+		`msg=gate phase=test command="make test"`,
+		`msg=condition bead=cap-1 phase=lint condition=files_match:*.go met=false`,
+		`msg="checkpoint saved" bead=cap-1 results=3`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("logs missing %q:\n%s", want, logs)
+		}
+	}
+}
+
+func TestNew_DefaultLoggerDiscards(t *testing.T) {
+	// Given an orchestrator without WithLogger
+	o := New(nil)
+
+	// Then logging is a no-op rather than a nil dereference
+	if o.logger == nil {
+		t.Fatal("default logger is nil")
+	}
+	if o.logger.Enabled(context.Background(), slog.LevelError) {
+		t.Error("default logger should discard every level")
+	}
+}
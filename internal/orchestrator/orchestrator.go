@@ -5,9 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/smileynet/capsule/internal/prompt"
@@ -24,6 +29,13 @@ type Provider interface {
 	Execute(ctx context.Context, prompt, workDir string) (provider.Result, error)
 }
 
+// StreamingProvider is a Provider that reports progress while a phase runs.
+// The orchestrator forwards each message as a PhaseProgress status update.
+type StreamingProvider interface {
+	Provider
+	ExecuteStream(ctx context.Context, prompt, workDir string, onProgress provider.ProgressFunc) (provider.Result, error)
+}
+
 // GateRunner executes shell commands as pipeline gate phases.
 type GateRunner interface {
 	Run(ctx context.Context, command, workDir string) (provider.Signal, error)
@@ -34,6 +46,13 @@ type PromptLoader interface {
 	Compose(phaseName string, ctx prompt.Context) (string, error)
 }
 
+// PromptLocator is implemented by prompt loaders that can report where a
+// phase's prompt comes from, e.g. a project override or the embedded
+// default. PlanPipeline records it in PhasePlan.PromptFrom.
+type PromptLocator interface {
+	Source(phaseName string) (string, error)
+}
+
 // WorktreeManager manages git worktrees for pipeline isolation.
 type WorktreeManager interface {
 	Create(id, baseBranch string) error
@@ -41,6 +60,18 @@ type WorktreeManager interface {
 	Path(id string) string
 }
 
+// OverlapChecker reports files that other in-flight capsules have changed,
+// keyed by capsule, which the capsule for id may conflict with on merge.
+type OverlapChecker interface {
+	OverlappingChanges(id string) (map[string][]string, error)
+}
+
+// ChangeLister reports the paths a bead's worktree has changed since it
+// branched from base, for diff_match phase conditions.
+type ChangeLister interface {
+	ChangedSince(id, base string) ([]string, error)
+}
+
 // WorklogManager tracks phase execution in a worklog.
 type WorklogManager interface {
 	Create(worktreePath string, bead worklog.BeadContext) error
@@ -69,28 +100,65 @@ type PipelineInput struct {
 	Description    string
 	BaseBranch     string
 	Bead           worklog.BeadContext
-	SkipPhases     []string                // Phases to skip (for resume from checkpoint).
+	SkipPhases     []string                // Phases to skip; recorded as StatusSkip results.
 	SiblingContext []prompt.SiblingContext // Completed sibling tasks for cross-run context.
+	// Resume continues a failed run from its checkpoint in the existing
+	// worktree, carrying the failure's feedback into the phase that reruns.
+	Resume bool
+	// Phases, when set, names the phase set this run uses instead of the
+	// configured phases, resolved by the PhaseResolver (by default a preset
+	// or a YAML file, as for LoadPhases).
+	Phases string
+	// StatusCallback, when set, receives this run's status updates instead
+	// of the callback set with WithStatusCallback.
+	StatusCallback StatusCallback
 }
 
 // PhaseResult records the outcome of a single phase execution with timing metadata.
 type PhaseResult struct {
-	PhaseName string          `json:"phase_name"`
-	Signal    provider.Signal `json:"signal"`
-	Attempt   int             `json:"attempt"`
-	Duration  time.Duration   `json:"duration"`
-	Timestamp time.Time       `json:"timestamp"`
+	PhaseName  string          `json:"phase_name"`
+	Signal     provider.Signal `json:"signal"`
+	Attempt    int             `json:"attempt"`
+	Duration   time.Duration   `json:"duration"`
+	Usage      provider.Usage  `json:"usage,omitzero"`
+	Timestamp  time.Time       `json:"timestamp"`
+	SkipReason string          `json:"skip_reason,omitempty"` // Why the phase was skipped; see skipReason.
 }
 
 // PipelineOutput is the result of running a pipeline.
 type PipelineOutput struct {
 	PhaseResults []PhaseResult
+	Findings     []provider.Finding // Findings from all phase signals, deduplicated by title.
 	Completed    bool
 }
 
+// TotalUsage sums token usage across every phase attempt.
+func (p PipelineOutput) TotalUsage() provider.Usage {
+	var total provider.Usage
+	for _, pr := range p.PhaseResults {
+		total = total.Add(pr.Usage)
+	}
+	return total
+}
+
 // ErrPipelinePaused indicates the pipeline was gracefully paused between phases.
 var ErrPipelinePaused = errors.New("pipeline paused")
 
+// ErrBudgetExceeded indicates the pipeline used the provider calls allowed
+// by WithMaxProviderCalls. It is wrapped in a PipelineError naming the phase
+// and attempt that would have made the next call.
+var ErrBudgetExceeded = errors.New("provider call budget exceeded")
+
+// ErrGateFailed indicates a required gate command exited non-zero, or a gate
+// with a retry target still failed after its retries. It is wrapped in a
+// PipelineError whose message lists the gate's first findings.
+var ErrGateFailed = errors.New("gate failed")
+
+// ErrOverlappingChanges indicates that strict overlap checking found other
+// in-flight capsules changing the same files. It is wrapped in a setup
+// PipelineError.
+var ErrOverlappingChanges = errors.New("other capsules changed overlapping files")
+
 // PipelineError indicates a pipeline failure with phase context.
 type PipelineError struct {
 	Phase   string          // Phase that failed.
@@ -118,6 +186,38 @@ func (e *PipelineError) Unwrap() error {
 	return e.Err
 }
 
+// errRunTimeout is the cause of the context deadline set by WithRunTimeout,
+// telling it apart from phase timeouts and caller deadlines.
+var errRunTimeout = errors.New("run timeout")
+
+// RunTimeoutError indicates the pipeline exceeded the WithRunTimeout
+// deadline. Err is the failure the deadline caused in Phase.
+type RunTimeoutError struct {
+	Timeout time.Duration
+	Phase   string // Phase running when the deadline fired.
+	Err     error
+}
+
+func (e *RunTimeoutError) Error() string {
+	return fmt.Sprintf("run timeout exceeded after %s during phase %s", shortDuration(e.Timeout), e.Phase)
+}
+
+func (e *RunTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// shortDuration formats d without zero trailing units: 30m rather than 30m0s.
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
 // RetryStrategy holds resolved retry settings for a phase.
 type RetryStrategy struct {
 	MaxAttempts      int
@@ -135,11 +235,39 @@ type Orchestrator struct {
 	worklogMgr      WorklogManager
 	gateRunner      GateRunner
 	checkpointStore CheckpointStore
+	summaryWriter   SummaryWriter
+	carried         []PhaseResult // Results from the checkpoint a run resumed; set per run.
+	logger          *slog.Logger
+	overlapChecker  OverlapChecker
+	overlapStrict   bool // Fail setup instead of warning when overlaps are found.
+	changeLister    ChangeLister
 	phases          []PhaseDefinition
+	phaseResolver   PhaseResolver // Resolves PipelineInput.Phases.
 	statusCallback  StatusCallback
 	pauseRequested  func() bool // Returns true when a pause has been requested.
 	baseBranch      string
 	retryDefaults   RetryStrategy
+	logDir          string // Per-bead debug artifacts (raw output) go under <logDir>/<bead>/.
+	transcriptDir   string // Phase transcripts go under <transcriptDir>/<bead>/transcripts/; "" disables them.
+	providerFactory ProviderFactory
+	defaultTimeout  time.Duration // Provider timeout for beads that override only the provider.
+	phaseTimeout    time.Duration // Timeout for phases that don't set one; 0 means none.
+	runTimeout      time.Duration // Deadline for a whole RunPipeline call; 0 means none.
+	maxCalls        int           // Provider calls allowed per RunPipeline call; 0 means no limit.
+	pregate         PregateMode   // Baseline gate checks before the first phase; PregateOff skips them.
+	perPhaseCommits bool          // Commit the worktree after each passing phase.
+	calls           *int          // Provider calls made by the current run; set per run, nil outside RunPipeline.
+
+	transientRetries int                               // Retries of a provider call that failed transiently; 0 disables them.
+	backoffMax       time.Duration                     // Longest wait between transient retries; 0 means no cap.
+	backoffBase      time.Duration                     // Wait before the first transient retry.
+	jitter           func(time.Duration) time.Duration // Randomizes each backoff wait.
+
+	contextFiles     []string // Repo-relative files snapshotted into prompt context.
+	contextFileBytes int      // Per-file cap for contextFiles.
+
+	workdirs    map[string]string // Bead ID prefix → default worktree-relative working directory.
+	beadWorkdir string            // Working directory for the current bead's phases; set by forBead.
 }
 
 // Option configures an Orchestrator.
@@ -150,12 +278,16 @@ func New(p Provider, opts ...Option) *Orchestrator {
 	o := &Orchestrator{
 		provider:       p,
 		phases:         DefaultPhases(),
+		phaseResolver:  func(spec string) ([]PhaseDefinition, error) { return LoadPhases(spec) },
 		statusCallback: func(StatusUpdate) {},
 		baseBranch:     "main",
+		logger:         slog.New(slog.DiscardHandler),
 		retryDefaults: RetryStrategy{
 			MaxAttempts:   3,
 			BackoffFactor: 1.0,
 		},
+		backoffBase: defaultBackoffBase,
+		jitter:      halfJitter,
 	}
 	for _, opt := range opts {
 		opt(o)
@@ -183,6 +315,20 @@ func WithPhases(phases []PhaseDefinition) Option {
 	return func(o *Orchestrator) { o.phases = phases }
 }
 
+// PhaseResolver resolves the phase set a PipelineInput names in Phases.
+type PhaseResolver func(spec string) ([]PhaseDefinition, error)
+
+// WithPhaseResolver sets how PipelineInput.Phases is resolved, so a run that
+// names its own phase set gets the same profiles and overrides as the
+// configured phases. The default is LoadPhases without overrides.
+func WithPhaseResolver(r PhaseResolver) Option {
+	return func(o *Orchestrator) {
+		if r != nil {
+			o.phaseResolver = r
+		}
+	}
+}
+
 // WithStatusCallback sets the callback for progress updates.
 func WithStatusCallback(cb StatusCallback) Option {
 	return func(o *Orchestrator) { o.statusCallback = cb }
@@ -210,6 +356,33 @@ func WithProviders(providers map[string]Provider) Option {
 	return func(o *Orchestrator) { o.providers = providers }
 }
 
+// WithLogDir sets the directory for per-bead debug artifacts. When set,
+// provider output that fails signal parsing is saved to
+// <dir>/<bead-id>/raw/<phase>-attempt-<n>.txt for inspection.
+func WithLogDir(dir string) Option {
+	return func(o *Orchestrator) { o.logDir = dir }
+}
+
+// WithTranscriptDir saves a transcript of every phase execution to
+// <dir>/<bead-id>/transcripts/<seq>-<phase>-attempt<n>.txt: the prompt and
+// raw provider output, or a gate's command and output. Transcripts are
+// written before the signal is parsed, so unparseable output is kept too.
+// With dir set to the worklog archive directory they sit beside the
+// archived worklog. An empty dir disables transcripts.
+func WithTranscriptDir(dir string) Option {
+	return func(o *Orchestrator) { o.transcriptDir = dir }
+}
+
+// WithLogger sets the logger for provider calls, gate runs, condition
+// evaluations, and checkpoint saves. The default discards logs.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *Orchestrator) {
+		if l != nil {
+			o.logger = l
+		}
+	}
+}
+
 // WithCheckpointStore enables pipeline checkpointing.
 // When set, phase results are persisted after each phase completes.
 func WithCheckpointStore(s CheckpointStore) Option {
@@ -223,6 +396,44 @@ func WithPauseRequested(fn func() bool) Option {
 	return func(o *Orchestrator) { o.pauseRequested = fn }
 }
 
+// WithOverlapCheck checks for files changed by other in-flight capsules
+// before the worktree is created. Overlaps are reported as a StatusUpdate
+// warning; with strict set, they also fail setup with ErrOverlappingChanges.
+func WithOverlapCheck(c OverlapChecker, strict bool) Option {
+	return func(o *Orchestrator) {
+		o.overlapChecker = c
+		o.overlapStrict = strict
+	}
+}
+
+// WithChangeLister sets the source of a bead's changed paths for diff_match
+// phase conditions. Without one, diff_match conditions fail the phase.
+func WithChangeLister(c ChangeLister) Option {
+	return func(o *Orchestrator) { o.changeLister = c }
+}
+
+// WithPhaseTimeout bounds each phase that doesn't set its own Timeout to d.
+// A bead's capsule:timeout label replaces d for that bead. Zero disables
+// the default.
+func WithPhaseTimeout(d time.Duration) Option {
+	return func(o *Orchestrator) { o.phaseTimeout = d }
+}
+
+// WithRunTimeout bounds each RunPipeline call, retries included, to d. When
+// the deadline fires the pipeline saves a checkpoint and returns a
+// *RunTimeoutError naming the phase it interrupted. Zero disables it.
+func WithRunTimeout(d time.Duration) Option {
+	return func(o *Orchestrator) { o.runTimeout = d }
+}
+
+// WithMaxProviderCalls limits each RunPipeline call, retries included, to n
+// provider calls. Gates don't count. When the budget runs out the pipeline
+// saves a checkpoint and returns ErrBudgetExceeded wrapped in a
+// *PipelineError. Zero disables the limit.
+func WithMaxProviderCalls(n int) Option {
+	return func(o *Orchestrator) { o.maxCalls = n }
+}
+
 // ConflictResolutionInput holds the context needed for conflict resolution.
 type ConflictResolutionInput struct {
 	BeadID        string   // The bead ID that encountered the conflict
@@ -268,7 +479,7 @@ func (o *Orchestrator) RunConflictResolution(ctx context.Context, input Conflict
 	}
 
 	// Run the execute → sign-off pair
-	results, err := o.runPhasePair(ctx, executePh, signOffPh, pCtx, input.WorktreePath, "conflict-resolution", "", 1)
+	results, err := o.runPhasePair(ctx, executePh, signOffPh, pCtx, input.WorktreePath, phaseProgress{label: "conflict-resolution"}, "", 1)
 	if err != nil {
 		return fmt.Errorf("conflict resolution failed: %w", err)
 	}
@@ -287,59 +498,145 @@ func (o *Orchestrator) RunConflictResolution(ctx context.Context, input Conflict
 // RunPipeline executes all pipeline phases for the given bead.
 // It creates a worktree and worklog, executes phases sequentially,
 // retries on NEEDS_WORK, and archives the worklog on completion.
-// Returns PipelineOutput with phase results for the caller to persist if needed.
+// Returns PipelineOutput with phase results and findings for the caller to
+// persist if needed; both are populated on failure as far as the run got.
 func (o *Orchestrator) RunPipeline(ctx context.Context, input PipelineInput) (PipelineOutput, error) {
-	var output PipelineOutput
+	if o.runTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, o.runTimeout, errRunTimeout)
+		defer cancel()
+	}
+	output, err := o.runPipeline(ctx, input)
+	output.Findings = collectFindings(output.PhaseResults)
+	return output, err
+}
 
+// runPipeline implements RunPipeline.
+func (o *Orchestrator) runPipeline(ctx context.Context, input PipelineInput) (output PipelineOutput, err error) {
 	if o.promptLoader == nil {
 		return output, &PipelineError{Phase: "setup", Err: errors.New("promptLoader is required")}
 	}
 
+	if input.Phases != "" || input.StatusCallback != nil {
+		ro := *o
+		if input.Phases != "" {
+			phases, err := o.phaseResolver(input.Phases)
+			if err != nil {
+				return output, &PipelineError{Phase: "setup", Err: fmt.Errorf("loading phases %q: %w", input.Phases, err)}
+			}
+			ro.phases = phases
+		}
+		if input.StatusCallback != nil {
+			ro.statusCallback = input.StatusCallback
+		}
+		o = &ro
+	}
+
+	// Apply bead label overrides and record the effective provider in the worklog.
+	o = o.forBead(input)
+	o.calls = new(int)
+	if o.provider != nil {
+		input.Bead.Provider = o.provider.Name()
+	}
+
 	beadID := input.BeadID
 	baseBranch := input.BaseBranch
 	if baseBranch == "" {
 		baseBranch = o.baseBranch
 	}
 
-	// Build skip set for resume from input and checkpoint.
-	skipSet := make(map[string]bool, len(input.SkipPhases))
+	// Build skip sets: phases the caller asked to skip are recorded as
+	// skipped; phases already passed in a checkpoint are reported as done
+	// without running again.
+	requested := make(map[string]bool, len(input.SkipPhases))
 	for _, name := range input.SkipPhases {
-		skipSet[name] = true
+		requested[name] = true
 	}
-	if o.checkpointStore != nil {
-		if cp, found, err := o.checkpointStore.LoadCheckpoint(beadID); err == nil && found {
-			for _, pr := range cp.PhaseResults {
-				if pr.Signal.Status == provider.StatusPass || pr.Signal.Status == provider.StatusSkip {
-					skipSet[pr.PhaseName] = true
-				}
-			}
+	reuse, attach := o.reuseWorktree(input)
+	plan := o.loadResumePlan(beadID, reuse)
+	o.carried = plan.carried
+
+	// Deferred first so it records the error as finally returned.
+	start := time.Now()
+	defer func() { o.writeSummary(input, start, output.PhaseResults, err) }()
+
+	// A run deadline fails whatever phase is running; checkpoint what has
+	// finished so the run can be resumed, and say what happened.
+	defer func() {
+		if err == nil || !errors.Is(context.Cause(ctx), errRunTimeout) {
+			return
 		}
-	}
+		o.saveCheckpoint(beadID, output)
+		phase := "setup"
+		var pe *PipelineError
+		if errors.As(err, &pe) {
+			phase = pe.Phase
+		}
+		err = &RunTimeoutError{Timeout: o.runTimeout, Phase: phase, Err: err}
+	}()
+
+	// An exhausted call budget stops the phase about to call the provider;
+	// checkpoint what ran and tell the display which phase it stopped.
+	defer func() {
+		var pe *PipelineError
+		if !errors.Is(err, ErrBudgetExceeded) || !errors.As(err, &pe) {
+			return
+		}
+		o.saveCheckpoint(beadID, output)
+		signal := provider.Signal{Status: provider.StatusError, Feedback: pe.Err.Error()}
+		o.notify(StatusUpdate{
+			BeadID: beadID, Phase: pe.Phase,
+			Status: PhaseError, Attempt: pe.Attempt,
+			Signal: &signal,
+		})
+	}()
 
 	// Create worktree.
 	// Note: worktrees are not cleaned up on failure so they can be inspected
 	// for debugging. The CLI layer (cap-9qv.5.3) handles cleanup policy.
+	if err := o.checkOverlaps(beadID); err != nil {
+		return output, &PipelineError{Phase: "setup", Err: err}
+	}
 	var wtPath string
 	if o.worktreeMgr != nil {
-		if err := o.worktreeMgr.Create(beadID, baseBranch); err != nil {
-			return output, &PipelineError{Phase: "setup", Err: fmt.Errorf("creating worktree: %w", err)}
+		switch {
+		case attach:
+			if err := o.worktreeMgr.(WorktreeAttacher).Attach(beadID); err != nil {
+				return output, &PipelineError{Phase: "setup", Err: fmt.Errorf("re-attaching worktree: %w", err)}
+			}
+		case !reuse:
+			if err := o.worktreeMgr.Create(beadID, baseBranch); err != nil {
+				return output, &PipelineError{Phase: "setup", Err: fmt.Errorf("creating worktree: %w", err)}
+			}
 		}
 		wtPath = o.worktreeMgr.Path(beadID)
 	}
 
-	// Create worklog.
-	if o.worklogMgr != nil {
+	// Create worklog. A reused worktree keeps the worklog it already has; a
+	// re-attached one lost it with its directory.
+	if o.worklogMgr != nil && (!reuse || attach) {
 		if err := o.worklogMgr.Create(wtPath, input.Bead); err != nil {
 			return output, &PipelineError{Phase: "setup", Err: fmt.Errorf("creating worklog: %w", err)}
 		}
 	}
 
-	// Build base prompt context from input.
+	// Build base prompt context from input. Context files are read once here,
+	// so every phase sees the files as they were when the pipeline started.
 	basePCtx := prompt.Context{
 		BeadID:         input.BeadID,
 		Title:          input.Title,
 		Description:    input.Description,
 		SiblingContext: input.SiblingContext,
+		ContextFiles:   o.loadContextFiles(beadID, wtPath),
+	}
+
+	// Check the gates before any phase changes the fresh worktree. Failures
+	// that don't abort the run go to the first worker phase that runs.
+	var baseline string
+	if !reuse {
+		if baseline, err = o.runBaseline(ctx, beadID, wtPath, requested); err != nil {
+			return output, err
+		}
 	}
 
 	// Execute phases sequentially.
@@ -350,100 +647,189 @@ func (o *Orchestrator) RunPipeline(ctx context.Context, input PipelineInput) (Pi
 			return output, ErrPipelinePaused
 		}
 
-		// Skip phases for resume.
-		if skipSet[phase.Name] {
+		progress := phaseProgress{index: i + 1, total: len(o.phases), phase: phase.Name}
+
+		// Report phases finished before the checkpoint as done so displays
+		// show them checked off as soon as the resumed run starts.
+		if pr, ok := plan.done[phase.Name]; ok {
+			o.logger.Debug("phase done in checkpoint", "bead", beadID, "phase", phase.Name, "status", pr.Signal.Status)
+			status := PhasePassed
+			if pr.Signal.Status == provider.StatusSkip {
+				status = PhaseSkipped
+			}
+			o.notifyAt(progress, StatusUpdate{
+				BeadID: beadID, Phase: phase.Name,
+				Status:  status,
+				Attempt: max(pr.Attempt, 1), MaxRetry: phase.MaxRetries,
+				Duration: pr.Duration, Signal: &pr.Signal, SkipReason: pr.SkipReason,
+			})
 			continue
 		}
 
-		progress := fmt.Sprintf("%d/%d", i+1, len(o.phases))
+		if requested[phase.Name] {
+			skipSignal := provider.Signal{
+				Status:       provider.StatusSkip,
+				Feedback:     "phase skipped at caller's request",
+				Summary:      "skipped by request",
+				Reason:       "skipped by request",
+				FilesChanged: []string{},
+				Findings:     []provider.Finding{},
+			}
+			o.logPhaseEntry(wtPath, phase.Name, 0, skipSignal, provider.Usage{}, 0)
+			output.PhaseResults = append(output.PhaseResults, PhaseResult{
+				PhaseName:  phase.Name,
+				Signal:     skipSignal,
+				Timestamp:  time.Now(),
+				SkipReason: skipSignal.Reason,
+			})
+			o.saveCheckpoint(beadID, output)
+			o.notifyAt(progress, StatusUpdate{
+				BeadID: beadID, Phase: phase.Name,
+				Status:  PhaseSkipped,
+				Attempt: 1, MaxRetry: phase.MaxRetries,
+				Signal: &skipSignal, SkipRequested: true, SkipReason: skipSignal.Reason,
+			})
+			continue
+		}
 
 		// Evaluate phase condition before execution.
-		met, err := evaluateCondition(phase.Condition, wtPath)
+		met, err := evaluateCondition(phase.Condition, o.conditionEnv(beadID, baseBranch, wtPath))
+		if phase.Condition != "" {
+			o.logger.Debug("condition", "bead", beadID, "phase", phase.Name,
+				"condition", phase.Condition, "met", met, "error", err)
+		}
 		if err != nil {
 			return output, &PipelineError{Phase: phase.Name, Err: err}
 		}
 		if !met {
+			reason := fmt.Sprintf("condition not met: %s", phase.Condition)
 			skipSignal := provider.Signal{
 				Status:       provider.StatusSkip,
-				Feedback:     fmt.Sprintf("condition not met: %s", phase.Condition),
+				Feedback:     reason,
 				Summary:      "skipped by condition",
+				Reason:       reason,
 				FilesChanged: []string{},
 				Findings:     []provider.Finding{},
 			}
+			o.logPhaseEntry(wtPath, phase.Name, 0, skipSignal, provider.Usage{}, 0)
 			output.PhaseResults = append(output.PhaseResults, PhaseResult{
-				PhaseName: phase.Name,
-				Signal:    skipSignal,
-				Timestamp: time.Now(),
+				PhaseName:  phase.Name,
+				Signal:     skipSignal,
+				Timestamp:  time.Now(),
+				SkipReason: reason,
 			})
 			o.saveCheckpoint(beadID, output)
-			o.notify(StatusUpdate{
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: beadID, Phase: phase.Name,
-				Status: PhaseSkipped, Progress: progress,
+				Status:  PhaseSkipped,
 				Attempt: 1, MaxRetry: phase.MaxRetries,
-				Signal: &skipSignal,
+				Signal: &skipSignal, SkipReason: reason,
 			})
 			continue
 		}
 
-		o.notify(StatusUpdate{
+		o.notifyAt(progress, StatusUpdate{
 			BeadID: beadID, Phase: phase.Name,
-			Status: PhaseRunning, Progress: progress,
+			Status:  PhaseRunning,
 			Attempt: 1, MaxRetry: phase.MaxRetries,
 		})
 
+		pCtx := basePCtx
+		pCtx.Feedback = plan.feedback[phase.Name]
+		if phase.Kind == Worker {
+			pCtx.Baseline, baseline = baseline, ""
+		}
+
 		phaseStart := time.Now()
-		signal, err := o.executePhase(ctx, phase, basePCtx, wtPath)
+		signal, usage, err := o.executePhase(ctx, phase, pCtx, wtPath, 1)
 		phaseDuration := time.Since(phaseStart)
 		if err != nil {
 			return output, &PipelineError{Phase: phase.Name, Attempt: 1, Err: err}
 		}
-		o.logPhaseEntry(wtPath, phase.Name, signal)
+		missing, err := checkArtifacts(phase, wtPath, &signal)
+		if err != nil {
+			return output, &PipelineError{Phase: phase.Name, Attempt: 1, Err: err}
+		}
+		o.logPhaseEntry(wtPath, phase.Name, 1, signal, usage, phaseDuration)
+		reason := skipReason(phase, signal)
 
 		output.PhaseResults = append(output.PhaseResults, PhaseResult{
-			PhaseName: phase.Name,
-			Signal:    signal,
-			Attempt:   1,
-			Duration:  phaseDuration,
-			Timestamp: phaseStart,
+			PhaseName:  phase.Name,
+			Signal:     signal,
+			Attempt:    1,
+			Duration:   phaseDuration,
+			Usage:      usage,
+			Timestamp:  phaseStart,
+			SkipReason: reason,
 		})
 		o.saveCheckpoint(beadID, output)
 
-		switch signal.Status {
+		// A failed gate with a retry target is handled like a reviewer's
+		// NEEDS_WORK: its output goes back to the target as feedback.
+		status := signal.Status
+		if retriesTarget(phase, signal) {
+			status = provider.StatusNeedsWork
+		}
+
+		switch status {
 		case provider.StatusPass:
-			o.notify(StatusUpdate{
+			o.commitPhase(progress, beadID, phase.Name, signal)
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: beadID, Phase: phase.Name,
-				Status: PhasePassed, Progress: progress,
+				Status:  PhasePassed,
 				Attempt: 1, MaxRetry: phase.MaxRetries,
-				Duration: phaseDuration, Signal: &signal,
+				Duration: phaseDuration, Usage: usage, Signal: &signal,
 			})
 
 		case provider.StatusSkip:
-			o.notify(StatusUpdate{
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: beadID, Phase: phase.Name,
-				Status: PhaseSkipped, Progress: progress,
+				Status:  PhaseSkipped,
 				Attempt: 1, MaxRetry: phase.MaxRetries,
-				Duration: phaseDuration, Signal: &signal,
+				Duration: phaseDuration, Usage: usage, Signal: &signal,
+				SkipReason: reason,
 			})
 
 		case provider.StatusError:
 			if phase.Optional {
-				o.notify(StatusUpdate{
+				o.notifyAt(progress, StatusUpdate{
 					BeadID: beadID, Phase: phase.Name,
-					Status: PhaseSkipped, Progress: progress,
+					Status:  PhaseSkipped,
 					Attempt: 1, MaxRetry: phase.MaxRetries,
-					Duration: phaseDuration, Signal: &signal,
+					Duration: phaseDuration, Usage: usage, Signal: &signal,
+					SkipReason: reason,
 				})
 				continue
 			}
-			o.notify(StatusUpdate{
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: beadID, Phase: phase.Name,
-				Status: PhaseError, Progress: progress,
+				Status:  PhaseError,
 				Attempt: 1, MaxRetry: phase.MaxRetries,
-				Duration: phaseDuration, Signal: &signal,
+				Duration: phaseDuration, Usage: usage, Signal: &signal,
 			})
-			return output, &PipelineError{Phase: phase.Name, Attempt: 1, Signal: signal}
+			pe := &PipelineError{Phase: phase.Name, Attempt: 1, Signal: signal}
+			if phase.Kind == Gate {
+				pe.Err = gateFailure(signal)
+			}
+			return output, pe
 
 		case provider.StatusNeedsWork:
+			if phase.RetryTarget == "" && len(missing) > 0 {
+				o.notifyAt(progress, StatusUpdate{
+					BeadID: beadID, Phase: phase.Name,
+					Status:  PhaseFailed,
+					Attempt: 1, MaxRetry: phase.MaxRetries,
+					Duration: phaseDuration, Usage: usage, Signal: &signal,
+					MissingArtifacts: missing,
+				})
+				retryResults, err := o.retryArtifacts(ctx, phase, basePCtx, wtPath, progress, signal.Feedback, missing)
+				output.PhaseResults = append(output.PhaseResults, retryResults...)
+				o.saveCheckpoint(beadID, output)
+				if err != nil {
+					return output, err
+				}
+				continue
+			}
 			if phase.RetryTarget == "" {
 				return output, &PipelineError{
 					Phase: phase.Name, Attempt: 1, Signal: signal,
@@ -457,12 +843,19 @@ func (o *Orchestrator) RunPipeline(ctx context.Context, input PipelineInput) (Pi
 					Err: fmt.Errorf("retry target %q not found", phase.RetryTarget),
 				}
 			}
-			o.notify(StatusUpdate{
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: beadID, Phase: phase.Name,
-				Status: PhaseFailed, Progress: progress,
+				Status:  PhaseFailed,
 				Attempt: 1, MaxRetry: phase.MaxRetries,
-				Duration: phaseDuration, Signal: &signal,
+				Duration: phaseDuration, Usage: usage, Signal: &signal,
+				MissingArtifacts: missing,
 			})
+			if requested[target.Name] {
+				return output, &PipelineError{
+					Phase: phase.Name, Attempt: 1, Signal: signal,
+					Err: fmt.Errorf("phase %q returned NEEDS_WORK but its retry target %q was skipped", phase.Name, target.Name),
+				}
+			}
 			retryResults, err := o.runPhasePair(ctx, target, phase, basePCtx, wtPath, progress, signal.Feedback, 2)
 			output.PhaseResults = append(output.PhaseResults, retryResults...)
 			o.saveCheckpoint(beadID, output)
@@ -479,6 +872,11 @@ func (o *Orchestrator) RunPipeline(ctx context.Context, input PipelineInput) (Pi
 		}
 	}
 
+	// A finished run has nothing left to resume.
+	if o.checkpointStore != nil {
+		_ = o.checkpointStore.RemoveCheckpoint(beadID)
+	}
+
 	output.Completed = true
 	return output, nil
 }
@@ -487,16 +885,18 @@ func (o *Orchestrator) RunPipeline(ctx context.Context, input PipelineInput) (Pi
 // executes with feedback, then the reviewer evaluates. Returns PhaseResults
 // for all attempts (worker + reviewer per attempt) and an error on failure.
 func (o *Orchestrator) runPhasePair(ctx context.Context, worker, reviewer PhaseDefinition,
-	basePCtx prompt.Context, wtPath, progress, feedback string, startAttempt int) ([]PhaseResult, error) {
+	basePCtx prompt.Context, wtPath string, progress phaseProgress, feedback string, startAttempt int) ([]PhaseResult, error) {
 
 	rs := o.ResolveRetryStrategy(reviewer)
 	maxAttempts := rs.MaxAttempts
 
 	var results []PhaseResult
+	var lastReview provider.Signal
 
 	for attempt := startAttempt; attempt <= maxAttempts; attempt++ {
 		// Apply backoff to phase timeouts for this attempt.
 		w, r := worker, reviewer
+		w.Timeout, r.Timeout = o.timeoutFor(worker), o.timeoutFor(reviewer)
 		if rs.BackoffFactor > 1.0 {
 			multiplier := math.Pow(rs.BackoffFactor, float64(attempt-1))
 			if w.Timeout > 0 {
@@ -524,104 +924,253 @@ func (o *Orchestrator) runPhasePair(ctx context.Context, worker, reviewer PhaseD
 		workerCtx := basePCtx
 		workerCtx.Feedback = feedback
 
-		o.notify(StatusUpdate{
+		o.notifyAt(progress, StatusUpdate{
 			BeadID: basePCtx.BeadID, Phase: worker.Name,
-			Status: PhaseRunning, Progress: progress,
+			Status:  PhaseRunning,
 			Attempt: attempt, MaxRetry: maxAttempts,
 		})
 
 		workerStart := time.Now()
-		workerSignal, err := o.executePhase(ctx, w, workerCtx, wtPath)
+		workerSignal, workerUsage, err := o.executePhase(ctx, w, workerCtx, wtPath, attempt)
 		workerDuration := time.Since(workerStart)
 		if err != nil {
 			return results, &PipelineError{Phase: worker.Name, Attempt: attempt, Err: err}
 		}
-		o.logPhaseEntry(wtPath, worker.Name, workerSignal)
+		workerMissing, err := checkArtifacts(w, wtPath, &workerSignal)
+		if err != nil {
+			return results, &PipelineError{Phase: worker.Name, Attempt: attempt, Err: err}
+		}
+		o.logPhaseEntry(wtPath, worker.Name, attempt, workerSignal, workerUsage, workerDuration)
 
 		results = append(results, PhaseResult{
 			PhaseName: worker.Name,
 			Signal:    workerSignal,
 			Attempt:   attempt,
 			Duration:  workerDuration,
+			Usage:     workerUsage,
 			Timestamp: workerStart,
 		})
 
 		// Workers return PASS or ERROR. NEEDS_WORK from a worker is treated
 		// as PASS (the reviewer will evaluate the output quality).
 		if workerSignal.Status == provider.StatusError {
-			o.notify(StatusUpdate{
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: basePCtx.BeadID, Phase: worker.Name,
-				Status: PhaseError, Progress: progress,
+				Status:  PhaseError,
 				Attempt: attempt, MaxRetry: maxAttempts,
-				Duration: workerDuration, Signal: &workerSignal,
+				Duration: workerDuration, Usage: workerUsage, Signal: &workerSignal,
 			})
 			return results, &PipelineError{Phase: worker.Name, Attempt: attempt, Signal: workerSignal}
 		}
 
-		o.notify(StatusUpdate{
+		// A worker that passed without its required artifacts is retried
+		// with the check's feedback before the reviewer sees the work.
+		if len(workerMissing) > 0 {
+			o.notifyAt(progress, StatusUpdate{
+				BeadID: basePCtx.BeadID, Phase: worker.Name,
+				Status:  PhaseFailed,
+				Attempt: attempt, MaxRetry: maxAttempts,
+				Duration: workerDuration, Usage: workerUsage, Signal: &workerSignal,
+				MissingArtifacts: workerMissing,
+			})
+			if attempt == maxAttempts {
+				return results, &PipelineError{
+					Phase: worker.Name, Attempt: attempt, Signal: workerSignal,
+					Err: fmt.Errorf("%w after %d attempts: %s", ErrMissingArtifacts, attempt, strings.Join(workerMissing, ", ")),
+				}
+			}
+			feedback = workerSignal.Feedback
+			continue
+		}
+
+		o.commitPhase(progress, basePCtx.BeadID, worker.Name, workerSignal)
+		o.notifyAt(progress, StatusUpdate{
 			BeadID: basePCtx.BeadID, Phase: worker.Name,
-			Status: PhasePassed, Progress: progress,
+			Status:  PhasePassed,
 			Attempt: attempt, MaxRetry: maxAttempts,
-			Duration: workerDuration, Signal: &workerSignal,
+			Duration: workerDuration, Usage: workerUsage, Signal: &workerSignal,
 		})
 
 		// Run reviewer.
-		o.notify(StatusUpdate{
+		o.notifyAt(progress, StatusUpdate{
 			BeadID: basePCtx.BeadID, Phase: reviewer.Name,
-			Status: PhaseRunning, Progress: progress,
+			Status:  PhaseRunning,
 			Attempt: attempt, MaxRetry: maxAttempts,
 		})
 
 		reviewerStart := time.Now()
-		reviewerSignal, err := o.executePhase(ctx, r, basePCtx, wtPath)
+		reviewerSignal, reviewerUsage, err := o.executePhase(ctx, r, basePCtx, wtPath, attempt)
 		reviewerDuration := time.Since(reviewerStart)
 		if err != nil {
 			return results, &PipelineError{Phase: reviewer.Name, Attempt: attempt, Err: err}
 		}
-		o.logPhaseEntry(wtPath, reviewer.Name, reviewerSignal)
+		reviewerMissing, err := checkArtifacts(r, wtPath, &reviewerSignal)
+		if err != nil {
+			return results, &PipelineError{Phase: reviewer.Name, Attempt: attempt, Err: err}
+		}
+		o.logPhaseEntry(wtPath, reviewer.Name, attempt, reviewerSignal, reviewerUsage, reviewerDuration)
 
 		results = append(results, PhaseResult{
 			PhaseName: reviewer.Name,
 			Signal:    reviewerSignal,
 			Attempt:   attempt,
 			Duration:  reviewerDuration,
+			Usage:     reviewerUsage,
 			Timestamp: reviewerStart,
 		})
 
-		switch reviewerSignal.Status {
+		reviewerStatus := reviewerSignal.Status
+		if retriesTarget(reviewer, reviewerSignal) {
+			reviewerStatus = provider.StatusNeedsWork
+		}
+
+		switch reviewerStatus {
 		case provider.StatusPass:
-			o.notify(StatusUpdate{
+			o.commitPhase(progress, basePCtx.BeadID, reviewer.Name, reviewerSignal)
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: basePCtx.BeadID, Phase: reviewer.Name,
-				Status: PhasePassed, Progress: progress,
+				Status:  PhasePassed,
 				Attempt: attempt, MaxRetry: maxAttempts,
-				Duration: reviewerDuration, Signal: &reviewerSignal,
+				Duration: reviewerDuration, Usage: reviewerUsage, Signal: &reviewerSignal,
 			})
 			return results, nil
 
 		case provider.StatusError:
-			o.notify(StatusUpdate{
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: basePCtx.BeadID, Phase: reviewer.Name,
-				Status: PhaseError, Progress: progress,
+				Status:  PhaseError,
 				Attempt: attempt, MaxRetry: maxAttempts,
-				Duration: reviewerDuration, Signal: &reviewerSignal,
+				Duration: reviewerDuration, Usage: reviewerUsage, Signal: &reviewerSignal,
 			})
 			return results, &PipelineError{Phase: reviewer.Name, Attempt: attempt, Signal: reviewerSignal}
 
 		case provider.StatusNeedsWork:
-			o.notify(StatusUpdate{
+			o.notifyAt(progress, StatusUpdate{
 				BeadID: basePCtx.BeadID, Phase: reviewer.Name,
-				Status: PhaseFailed, Progress: progress,
+				Status:  PhaseFailed,
 				Attempt: attempt, MaxRetry: maxAttempts,
-				Duration: reviewerDuration, Signal: &reviewerSignal,
+				Duration: reviewerDuration, Usage: reviewerUsage, Signal: &reviewerSignal,
+				MissingArtifacts: reviewerMissing,
 			})
 			feedback = reviewerSignal.Feedback
+			lastReview = reviewerSignal
 		}
 	}
 
+	err := fmt.Errorf("max retries (%d) exceeded", maxAttempts)
+	if reviewer.Kind == Gate {
+		return results, &PipelineError{
+			Phase: reviewer.Name, Attempt: maxAttempts, Signal: lastReview,
+			Err: fmt.Errorf("%w: %w", err, gateFailure(lastReview)),
+		}
+	}
 	return results, &PipelineError{
 		Phase:   reviewer.Name,
 		Attempt: maxAttempts,
-		Err:     fmt.Errorf("max retries (%d) exceeded", maxAttempts),
+		Err:     err,
+	}
+}
+
+// retriesTarget reports whether signal from phase sends the work back to
+// the phase's retry target: a NEEDS_WORK verdict, or a failed gate. A gate
+// with a retry target is retried even when it is optional.
+func retriesTarget(phase PhaseDefinition, signal provider.Signal) bool {
+	if phase.RetryTarget == "" {
+		return false
+	}
+	return signal.Status == provider.StatusNeedsWork ||
+		(phase.Kind == Gate && signal.Status == provider.StatusError)
+}
+
+// skipReason returns why a phase that returned signal is skipped: the
+// reason the phase gave for a SKIP, or the error an optional phase is
+// skipped over. It returns "" for a phase that is not skipped or a SKIP
+// without a reason.
+func skipReason(phase PhaseDefinition, signal provider.Signal) string {
+	switch {
+	case signal.Status == provider.StatusSkip:
+		return signal.Reason
+	case signal.Status == provider.StatusError && phase.Optional && !retriesTarget(phase, signal):
+		return "optional phase failed: " + signal.Summary
+	}
+	return ""
+}
+
+// retryArtifacts re-runs a phase without a retry target that passed without
+// its required artifacts, feeding it the artifact check's feedback, until
+// the artifacts exist or MaxRetries attempts are used. Attempt 1 has
+// already run and found missing. Returns PhaseResults for the attempts it ran.
+func (o *Orchestrator) retryArtifacts(ctx context.Context, phase PhaseDefinition,
+	basePCtx prompt.Context, wtPath string, progress phaseProgress, feedback string, missing []string) ([]PhaseResult, error) {
+
+	maxAttempts := max(phase.MaxRetries, 1)
+	var results []PhaseResult
+	for attempt := 2; attempt <= maxAttempts; attempt++ {
+		o.notifyAt(progress, StatusUpdate{
+			BeadID: basePCtx.BeadID, Phase: phase.Name,
+			Status:  PhaseRunning,
+			Attempt: attempt, MaxRetry: maxAttempts,
+		})
+
+		pCtx := basePCtx
+		pCtx.Feedback = feedback
+		start := time.Now()
+		signal, usage, err := o.executePhase(ctx, phase, pCtx, wtPath, attempt)
+		duration := time.Since(start)
+		if err != nil {
+			return results, &PipelineError{Phase: phase.Name, Attempt: attempt, Err: err}
+		}
+		if missing, err = checkArtifacts(phase, wtPath, &signal); err != nil {
+			return results, &PipelineError{Phase: phase.Name, Attempt: attempt, Err: err}
+		}
+		o.logPhaseEntry(wtPath, phase.Name, attempt, signal, usage, duration)
+		reason := skipReason(phase, signal)
+		results = append(results, PhaseResult{
+			PhaseName:  phase.Name,
+			Signal:     signal,
+			Attempt:    attempt,
+			Duration:   duration,
+			Usage:      usage,
+			Timestamp:  start,
+			SkipReason: reason,
+		})
+
+		update := StatusUpdate{
+			BeadID: basePCtx.BeadID, Phase: phase.Name,
+			Attempt: attempt, MaxRetry: maxAttempts,
+			Duration: duration, Usage: usage, Signal: &signal,
+			MissingArtifacts: missing, SkipReason: reason,
+		}
+		switch {
+		case signal.Status == provider.StatusPass:
+			o.commitPhase(progress, basePCtx.BeadID, phase.Name, signal)
+			update.Status = PhasePassed
+			o.notifyAt(progress, update)
+			return results, nil
+		case signal.Status == provider.StatusSkip, signal.Status == provider.StatusError && phase.Optional:
+			update.Status = PhaseSkipped
+			o.notifyAt(progress, update)
+			return results, nil
+		case len(missing) > 0:
+			update.Status = PhaseFailed
+			o.notifyAt(progress, update)
+			feedback = signal.Feedback
+		case signal.Status == provider.StatusError:
+			update.Status = PhaseError
+			o.notifyAt(progress, update)
+			return results, &PipelineError{Phase: phase.Name, Attempt: attempt, Signal: signal}
+		default:
+			update.Status = PhaseFailed
+			o.notifyAt(progress, update)
+			return results, &PipelineError{
+				Phase: phase.Name, Attempt: attempt, Signal: signal,
+				Err: fmt.Errorf("phase %q returned NEEDS_WORK but has no retry target", phase.Name),
+			}
+		}
+	}
+	return results, &PipelineError{
+		Phase: phase.Name, Attempt: maxAttempts,
+		Err: fmt.Errorf("%w after %d attempt(s): %s", ErrMissingArtifacts, maxAttempts, strings.Join(missing, ", ")),
 	}
 }
 
@@ -629,41 +1178,101 @@ func (o *Orchestrator) runPhasePair(ctx context.Context, worker, reviewer PhaseD
 // For Gate phases, it delegates to the GateRunner.
 // For Worker and Reviewer phases, it composes a prompt and calls the provider.
 // When PhaseDefinition.Provider is set, the named provider is used instead of the default.
+// The phase runs in its Workdir (or the bead's default directory) inside wtPath.
+// attempt is used only to name debug artifacts when the signal cannot be parsed.
 func (o *Orchestrator) executePhase(ctx context.Context, phase PhaseDefinition,
-	pCtx prompt.Context, wtPath string) (provider.Signal, error) {
+	pCtx prompt.Context, wtPath string, attempt int) (provider.Signal, provider.Usage, error) {
 
-	if phase.Timeout > 0 {
+	if timeout := o.timeoutFor(phase); timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, phase.Timeout)
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
+	// Phases scoped to a subdirectory run there but still report files
+	// relative to the worktree root.
+	rel := o.phaseWorkdir(phase)
+	workDir, err := resolveWorkdir(wtPath, rel)
+	if err != nil {
+		return provider.Signal{}, provider.Usage{}, fmt.Errorf("phase %s: %w", phase.Name, err)
+	}
+	if rel != "" {
+		signal, usage, err := o.executePhaseIn(ctx, phase, pCtx, workDir, attempt)
+		signal.FilesChanged = normalizeFilesChanged(signal.FilesChanged, wtPath, rel)
+		return signal, usage, err
+	}
+	return o.executePhaseIn(ctx, phase, pCtx, workDir, attempt)
+}
+
+// executePhaseIn runs a gate or provider phase in workDir.
+func (o *Orchestrator) executePhaseIn(ctx context.Context, phase PhaseDefinition,
+	pCtx prompt.Context, workDir string, attempt int) (provider.Signal, provider.Usage, error) {
+
 	if phase.Kind == Gate {
-		return o.executeGate(ctx, phase, wtPath)
+		signal, err := o.executeGate(ctx, phase, workDir)
+		o.saveTranscript(pCtx.BeadID, phase.Name, attempt, gateTranscript(phase.Command, signal, err))
+		return signal, provider.Usage{}, err
 	}
 
 	p, err := o.resolveProvider(phase)
 	if err != nil {
-		return provider.Signal{}, err
+		return provider.Signal{}, provider.Usage{}, err
 	}
 
 	promptName := phase.PromptName()
 	composed, err := o.promptLoader.Compose(promptName, pCtx)
 	if err != nil {
-		return provider.Signal{}, fmt.Errorf("composing prompt for %s: %w", phase.Name, err)
+		return provider.Signal{}, provider.Usage{}, fmt.Errorf("composing prompt for %s: %w", phase.Name, err)
+	}
+	composed = withBaseline(composed, pCtx.Baseline)
+
+	if err := o.takeCall(); err != nil {
+		return provider.Signal{}, provider.Usage{}, err
 	}
 
-	result, err := p.Execute(ctx, composed, wtPath)
+	start := time.Now()
+	sent := provider.PhaseMarker(phase.Name) + composed
+	result, err := o.callProviderRetrying(ctx, p, sent, workDir, pCtx.BeadID, phase.Name, attempt)
+	o.logger.Debug("provider call",
+		"bead", pCtx.BeadID, "phase", phase.Name, "attempt", attempt,
+		"provider", p.Name(), "prompt_bytes", len(composed),
+		"duration", time.Since(start), "exit_code", result.ExitCode, "error", err)
+	transcript := o.saveTranscript(pCtx.BeadID, phase.Name, attempt, providerTranscript(sent, result.Output))
 	if err != nil {
-		return provider.Signal{}, fmt.Errorf("executing %s: %w", phase.Name, err)
+		return provider.Signal{}, result.Usage, fmt.Errorf("executing %s: %w", phase.Name, err)
 	}
 
 	signal, err := result.ParseSignal()
 	if err != nil {
-		return provider.Signal{}, fmt.Errorf("parsing signal for %s: %w", phase.Name, err)
+		if transcript != "" {
+			return provider.Signal{}, result.Usage, fmt.Errorf("parsing signal for %s (transcript: %s): %w", phase.Name, transcript, err)
+		}
+		if path := o.saveRawOutput(pCtx.BeadID, phase.Name, attempt, result.Output); path != "" {
+			return provider.Signal{}, result.Usage, fmt.Errorf("parsing signal for %s (raw output: %s): %w", phase.Name, path, err)
+		}
+		return provider.Signal{}, result.Usage, fmt.Errorf("parsing signal for %s: %w", phase.Name, err)
 	}
 
-	return signal, nil
+	return signal, result.Usage, nil
+}
+
+// collectFindings gathers findings from every phase signal in order,
+// keeping the first finding for each title. Retried phases often repeat
+// their findings.
+func collectFindings(results []PhaseResult) []provider.Finding {
+	var findings []provider.Finding
+	seen := make(map[string]bool)
+	for _, pr := range results {
+		for _, f := range pr.Signal.Findings {
+			key := strings.ToLower(strings.TrimSpace(f.Title))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, f)
+		}
+	}
+	return findings
 }
 
 // resolveProvider returns the provider for a phase: the named override if set,
@@ -680,11 +1289,60 @@ func (o *Orchestrator) resolveProvider(phase PhaseDefinition) (Provider, error)
 }
 
 // executeGate runs a gate phase via the GateRunner.
-func (o *Orchestrator) executeGate(ctx context.Context, phase PhaseDefinition, wtPath string) (provider.Signal, error) {
+func (o *Orchestrator) executeGate(ctx context.Context, phase PhaseDefinition, workDir string) (provider.Signal, error) {
 	if o.gateRunner == nil {
 		return provider.Signal{}, fmt.Errorf("gate phase %q requires a GateRunner", phase.Name)
 	}
-	return o.gateRunner.Run(ctx, phase.Command, wtPath)
+	start := time.Now()
+	signal, err := o.gateRunner.Run(ctx, phase.Command, workDir)
+	o.logger.Debug("gate",
+		"phase", phase.Name, "command", phase.Command, "dir", workDir,
+		"status", signal.Status, "duration", time.Since(start), "error", err)
+	return signal, err
+}
+
+// maxGateFindings is how many findings a failed gate's error lists.
+const maxGateFindings = 3
+
+// gateFailure describes a failed gate by its exit status and first few
+// findings, or its last line of output when it reported none, so the error
+// is actionable without the full output in Signal.Feedback.
+func gateFailure(signal provider.Signal) error {
+	var detail []string
+	for _, f := range signal.Findings {
+		if len(detail) == maxGateFindings {
+			detail = append(detail, fmt.Sprintf("and %d more", len(signal.Findings)-maxGateFindings))
+			break
+		}
+		detail = append(detail, f.Title)
+	}
+	if len(detail) == 0 {
+		if line := strings.TrimSpace(lastLine(signal.Feedback)); line != "" {
+			detail = append(detail, line)
+		}
+	}
+	if len(detail) == 0 {
+		return fmt.Errorf("%w (%s)", ErrGateFailed, signal.Summary)
+	}
+	return fmt.Errorf("%w (%s): %s", ErrGateFailed, signal.Summary, strings.Join(detail, "; "))
+}
+
+// lastLine returns the last non-blank line of s.
+func lastLine(s string) string {
+	s = strings.TrimRight(s, " \t\r\n")
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// timeoutFor returns the phase's own timeout, or the WithPhaseTimeout
+// default when it sets none.
+func (o *Orchestrator) timeoutFor(phase PhaseDefinition) time.Duration {
+	if phase.Timeout > 0 {
+		return phase.Timeout
+	}
+	return o.phaseTimeout
 }
 
 // findPhase looks up a phase definition by name.
@@ -706,11 +1364,114 @@ func (o *Orchestrator) isPauseRequested() bool {
 	return o.pauseRequested()
 }
 
+// checkOverlaps warns about files other in-flight capsules have changed.
+// Returns ErrOverlappingChanges in strict mode when any are found; a failed
+// check is only warned about.
+func (o *Orchestrator) checkOverlaps(beadID string) error {
+	if o.overlapChecker == nil {
+		return nil
+	}
+	overlaps, err := o.overlapChecker.OverlappingChanges(beadID)
+	if err != nil {
+		o.notify(StatusUpdate{BeadID: beadID, Warning: fmt.Sprintf("overlap check failed: %v", err)})
+		return nil
+	}
+	if len(overlaps) == 0 {
+		return nil
+	}
+	summary := describeOverlaps(overlaps)
+	o.notify(StatusUpdate{BeadID: beadID, Warning: "other capsules in flight changed files that may conflict on merge: " + summary})
+	if o.overlapStrict {
+		return fmt.Errorf("%w: %s", ErrOverlappingChanges, summary)
+	}
+	return nil
+}
+
+// describeOverlaps formats overlaps as "a (x.go, y.go); b (z.go)" in
+// capsule order.
+func describeOverlaps(overlaps map[string][]string) string {
+	names := make([]string, 0, len(overlaps))
+	for name := range overlaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%s)", name, strings.Join(overlaps[name], ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// callProvider runs prompt on p. A streaming provider's progress is
+// reported as PhaseProgress updates for the phase.
+func (o *Orchestrator) callProvider(ctx context.Context, p Provider, prompt, workDir, beadID, phase string, attempt int) (provider.Result, error) {
+	sp, ok := p.(StreamingProvider)
+	if !ok {
+		return p.Execute(ctx, prompt, workDir)
+	}
+	return sp.ExecuteStream(ctx, prompt, workDir, func(msg string) {
+		o.notify(StatusUpdate{
+			BeadID: beadID, Phase: phase,
+			Status: PhaseProgress, Attempt: attempt,
+			Message: msg,
+		})
+	})
+}
+
+// takeCall counts a provider call against the run's budget, returning
+// ErrBudgetExceeded when none are left.
+func (o *Orchestrator) takeCall() error {
+	if o.calls == nil {
+		return nil
+	}
+	if o.maxCalls > 0 && *o.calls >= o.maxCalls {
+		return fmt.Errorf("%w: all %d calls used", ErrBudgetExceeded, o.maxCalls)
+	}
+	*o.calls++
+	return nil
+}
+
 // notify fires the status callback.
 func (o *Orchestrator) notify(su StatusUpdate) {
 	o.statusCallback(su)
 }
 
+// phaseProgress locates a status update in the pipeline: the phase the
+// main loop is on, numbered from 1, of total. Retries of another phase
+// report the phase that triggered them. Runs outside the pipeline, such as
+// conflict resolution, set only a label.
+type phaseProgress struct {
+	index, total int
+	phase        string
+	label        string // Progress text in place of "index/total".
+}
+
+// fraction is the share of the pipeline done when su is reported: the
+// phases before progress.phase, plus that phase once it passes or is
+// skipped. A retry target's updates count as the retrying phase's, so the
+// fraction never falls back past its boundary.
+func (p phaseProgress) fraction(su StatusUpdate) float64 {
+	if p.total == 0 {
+		return 0
+	}
+	done := max(p.index-1, 0)
+	if su.Phase == p.phase && (su.Status == PhasePassed || su.Status == PhaseSkipped) {
+		done = p.index
+	}
+	return float64(done) / float64(p.total)
+}
+
+// notifyAt fires the status callback for su with its place in the pipeline.
+func (o *Orchestrator) notifyAt(p phaseProgress, su StatusUpdate) {
+	su.Progress = p.label
+	if su.Progress == "" {
+		su.Progress = fmt.Sprintf("%d/%d", p.index, p.total)
+	}
+	su.PhaseIndex, su.PhaseTotal = p.index, p.total
+	su.FractionComplete = p.fraction(su)
+	o.notify(su)
+}
+
 // ResolveRetryStrategy returns the effective retry strategy for a phase.
 // Phase-level MaxRetries override pipeline-level defaults.
 func (o *Orchestrator) ResolveRetryStrategy(phase PhaseDefinition) RetryStrategy {
@@ -721,49 +1482,121 @@ func (o *Orchestrator) ResolveRetryStrategy(phase PhaseDefinition) RetryStrategy
 	return rs
 }
 
-// evaluateCondition checks whether a phase's condition is met.
-// Empty condition means always run. "files_match:<glob>" checks for matching files in dir.
-// The glob is non-recursive (filepath.Glob); patterns like "*.go" match only in dir, not subdirectories.
-func evaluateCondition(condition, dir string) (bool, error) {
-	if condition == "" {
-		return true, nil
-	}
-
-	if strings.HasPrefix(condition, "files_match:") {
-		pattern := strings.TrimPrefix(condition, "files_match:")
-		matches, err := filepath.Glob(filepath.Join(dir, pattern))
-		if err != nil {
-			return false, fmt.Errorf("evaluating condition %q: %w", condition, err)
-		}
-		return len(matches) > 0, nil
+// conditionEnv returns the environment phase conditions are evaluated in:
+// dir for files_match, and the bead's changes since base for diff_match,
+// listed at most once per evaluation.
+func (o *Orchestrator) conditionEnv(beadID, base, dir string) conditionEnv {
+	env := conditionEnv{dir: dir, lookupEnv: os.LookupEnv}
+	if o.changeLister != nil {
+		env.changed = sync.OnceValues(func() ([]string, error) {
+			return o.changeLister.ChangedSince(beadID, base)
+		})
 	}
-
-	return false, fmt.Errorf("unrecognized condition: %q", condition)
+	return env
 }
 
-// saveCheckpoint persists the current pipeline state (best-effort).
+// saveCheckpoint persists the current pipeline state (best-effort), after
+// any results carried over from the checkpoint this run resumed.
 func (o *Orchestrator) saveCheckpoint(beadID string, output PipelineOutput) {
 	if o.checkpointStore == nil {
 		return
 	}
 	// Best-effort: checkpoint failures don't abort the pipeline.
-	_ = o.checkpointStore.SaveCheckpoint(PipelineCheckpoint{
+	results := output.PhaseResults
+	if len(o.carried) > 0 {
+		results = append(slices.Clip(o.carried), results...)
+	}
+	if err := o.checkpointStore.SaveCheckpoint(PipelineCheckpoint{
 		BeadID:       beadID,
-		PhaseResults: output.PhaseResults,
+		PhaseResults: results,
 		SavedAt:      time.Now(),
-	})
+	}); err != nil {
+		o.logger.Warn("checkpoint save failed", "bead", beadID, "error", err)
+		return
+	}
+	o.logger.Debug("checkpoint saved", "bead", beadID, "results", len(results))
+}
+
+// saveRawOutput writes unparseable provider output to
+// <logDir>/<beadID>/raw/<phase>-attempt-<n>.txt and returns the path.
+// Best-effort: returns "" when no log dir is configured or the write fails.
+func (o *Orchestrator) saveRawOutput(beadID, phaseName string, attempt int, output string) string {
+	if o.logDir == "" || beadID == "" || beadID != filepath.Base(beadID) || phaseName != filepath.Base(phaseName) {
+		return ""
+	}
+	dir := filepath.Join(o.logDir, beadID, "raw")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return ""
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-attempt-%d.txt", phaseName, attempt))
+	if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+		return ""
+	}
+	return path
 }
 
-// logPhaseEntry records a phase result in the worklog (best-effort).
-func (o *Orchestrator) logPhaseEntry(wtPath, phaseName string, signal provider.Signal) {
+// saveTranscript writes content to the next numbered transcript file for
+// beadID and returns its path. Numbering continues across the bead's runs,
+// so a resumed run's transcripts sort after the earlier ones. Returns ""
+// when transcripts are disabled or the file cannot be written.
+func (o *Orchestrator) saveTranscript(beadID, phaseName string, attempt int, content string) string {
+	if o.transcriptDir == "" || beadID == "" || beadID != filepath.Base(beadID) || phaseName != filepath.Base(phaseName) {
+		return ""
+	}
+	dir := filepath.Join(o.transcriptDir, beadID, "transcripts")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return ""
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%03d-%s-attempt%d.txt", len(entries)+1, phaseName, attempt))
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return ""
+	}
+	return path
+}
+
+// providerTranscript formats the prompt sent to a provider and its raw output.
+func providerTranscript(prompt, output string) string {
+	return "=== PROMPT ===\n" + prompt + "\n\n=== OUTPUT ===\n" + output + "\n"
+}
+
+// gateTranscript formats a gate command, its result, and its output. A
+// passing gate carries its output in Summary; a failing one in Feedback,
+// with the exit status in Summary.
+func gateTranscript(command string, signal provider.Signal, err error) string {
+	result, output := string(signal.Status), signal.Summary
+	switch {
+	case err != nil:
+		result, output = "error: "+err.Error(), ""
+	case signal.Status != provider.StatusPass:
+		result, output = result+": "+signal.Summary, signal.Feedback
+	}
+	return "=== COMMAND ===\n" + command + "\n\n=== RESULT ===\n" + result + "\n\n=== OUTPUT ===\n" + output + "\n"
+}
+
+// logPhaseEntry records one attempt of a phase in the worklog (best-effort).
+// Every attempt is logged so a retried phase keeps the feedback that caused
+// each retry.
+func (o *Orchestrator) logPhaseEntry(wtPath, phaseName string, attempt int, signal provider.Signal, usage provider.Usage, duration time.Duration) {
 	if o.worklogMgr == nil {
 		return
 	}
-	// Best-effort: worklog failures don't abort the pipeline.
-	_ = o.worklogMgr.AppendPhaseEntry(wtPath, worklog.PhaseEntry{
+	entry := worklog.PhaseEntry{
 		Name:      phaseName,
 		Status:    string(signal.Status),
 		Verdict:   signal.Summary,
+		Usage:     usage.String(),
 		Timestamp: time.Now(),
-	})
+		Attempt:   attempt,
+		Feedback:  signal.Feedback,
+		Duration:  duration,
+	}
+	if signal.Status == provider.StatusSkip {
+		entry.Reason = signal.Reason
+	}
+	// Best-effort: worklog failures don't abort the pipeline.
+	_ = o.worklogMgr.AppendPhaseEntry(wtPath, entry)
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -52,6 +53,19 @@ func (m *sequenceProvider) Execute(_ context.Context, p, workDir string) (provid
 	return resp.result, resp.err
 }
 
+// streamingProvider reports progress messages before each response.
+type streamingProvider struct {
+	sequenceProvider
+	progress []string
+}
+
+func (m *streamingProvider) ExecuteStream(ctx context.Context, p, workDir string, onProgress provider.ProgressFunc) (provider.Result, error) {
+	for _, msg := range m.progress {
+		onProgress(msg)
+	}
+	return m.Execute(ctx, p, workDir)
+}
+
 type mockPromptLoader struct {
 	composeFunc func(phaseName string, ctx prompt.Context) (string, error)
 }
@@ -85,10 +99,12 @@ type mockWorklogMgr struct {
 	entries    []worklog.PhaseEntry
 	archived   bool
 	created    bool
+	bead       worklog.BeadContext
 }
 
-func (m *mockWorklogMgr) Create(string, worklog.BeadContext) error {
+func (m *mockWorklogMgr) Create(_ string, bead worklog.BeadContext) error {
 	m.created = true
+	m.bead = bead
 	return m.createErr
 }
 
@@ -316,6 +332,173 @@ func TestRunPipeline_NilPromptLoader(t *testing.T) {
 	}
 }
 
+func TestRunPipeline_RecordsUsage(t *testing.T) {
+	// Given a provider that reports usage for every phase
+	withUsage := func(r mockResponse, in, out int, cost float64) mockResponse {
+		r.result.Usage = provider.Usage{InputTokens: in, OutputTokens: out, CostUSD: cost}
+		return r
+	}
+	sp := &sequenceProvider{responses: []mockResponse{
+		withUsage(passResponse(), 100, 10, 0.01),
+		withUsage(passResponse(), 200, 20, 0.02),
+	}}
+	wl := &mockWorklogMgr{}
+	var updates []StatusUpdate
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{}),
+		WithWorklogManager(wl),
+		WithPhases(twoPhases()),
+		WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+	)
+
+	// When the pipeline runs
+	output, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then each phase result carries its usage and the total sums them
+	if got := output.PhaseResults[1].Usage.InputTokens; got != 200 {
+		t.Errorf("reviewer InputTokens = %d, want 200", got)
+	}
+	total := output.TotalUsage()
+	if total.InputTokens != 300 || total.OutputTokens != 30 {
+		t.Errorf("TotalUsage() = %+v, want 300 in / 30 out", total)
+	}
+
+	// And completion updates report usage while running updates do not
+	for _, su := range updates {
+		if su.Status == PhaseRunning && !su.Usage.IsZero() {
+			t.Errorf("running update for %s has usage %+v", su.Phase, su.Usage)
+		}
+		if su.Status == PhasePassed && su.Usage.IsZero() {
+			t.Errorf("passed update for %s has no usage", su.Phase)
+		}
+	}
+
+	// And the worklog records usage per phase
+	if len(wl.entries) != 2 || wl.entries[0].Usage != "100 in / 10 out tokens, $0.0100" {
+		t.Errorf("worklog entries = %+v, want usage on each", wl.entries)
+	}
+}
+
+func TestRunPipeline_StreamsProgress(t *testing.T) {
+	// Given a streaming provider that reports two messages per phase
+	sp := &streamingProvider{
+		sequenceProvider: sequenceProvider{responses: []mockResponse{passResponse(), passResponse()}},
+		progress:         []string{"reading", "editing"},
+	}
+	var updates []StatusUpdate
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{}),
+		WithWorklogManager(&mockWorklogMgr{}),
+		WithPhases(twoPhases()),
+		WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+	)
+
+	// When the pipeline runs
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"}); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then each phase reports running, its progress, then its result
+	var got []string
+	for _, su := range updates {
+		entry := su.Phase + " " + string(su.Status)
+		if su.Status == PhaseProgress {
+			entry += " " + su.Message
+			if su.BeadID != "cap-1" || su.Attempt != 1 || su.Signal != nil {
+				t.Errorf("progress update = %+v, want bead cap-1, attempt 1, no signal", su)
+			}
+		}
+		got = append(got, entry)
+	}
+	want := []string{
+		"worker running", "worker progress reading", "worker progress editing", "worker passed",
+		"reviewer running", "reviewer progress reading", "reviewer progress editing", "reviewer passed",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("updates =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCollectFindings(t *testing.T) {
+	finding := func(title, severity string) provider.Finding {
+		return provider.Finding{Title: title, Severity: severity}
+	}
+	tests := []struct {
+		name    string
+		results []PhaseResult
+		want    []provider.Finding
+	}{
+		{name: "no findings", results: []PhaseResult{{PhaseName: "execute"}}},
+		{
+			name: "findings across phases in order",
+			results: []PhaseResult{
+				{Signal: provider.Signal{Findings: []provider.Finding{finding("a", "minor")}}},
+				{Signal: provider.Signal{Findings: []provider.Finding{finding("b", "major")}}},
+			},
+			want: []provider.Finding{finding("a", "minor"), finding("b", "major")},
+		},
+		{
+			name: "repeated titles keep the first",
+			results: []PhaseResult{
+				{Signal: provider.Signal{Findings: []provider.Finding{finding("Flaky test", "minor")}}},
+				{Signal: provider.Signal{Findings: []provider.Finding{finding(" flaky test ", "major"), finding("", "nit")}}},
+			},
+			want: []provider.Finding{finding("Flaky test", "minor")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collectFindings(tt.results)
+			if len(got) != len(tt.want) {
+				t.Fatalf("collectFindings() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("finding[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRunPipeline_FindingsOnFailure(t *testing.T) {
+	// Given a reviewer that reports a finding and then errors
+	sig := provider.Signal{
+		Status:       provider.StatusError,
+		Feedback:     "broken",
+		Summary:      "error",
+		FilesChanged: []string{},
+		Findings:     []provider.Finding{{Title: "Missing docs", Severity: "minor"}},
+	}
+	data, _ := json.Marshal(sig)
+	sp := &sequenceProvider{responses: []mockResponse{
+		passResponse(),
+		{result: provider.Result{Output: string(data)}},
+	}}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{}),
+		WithPhases(twoPhases()),
+	)
+
+	// When the pipeline runs
+	output, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+	// Then the failure still carries the collected findings
+	if err == nil {
+		t.Fatal("expected pipeline error")
+	}
+	if len(output.Findings) != 1 || output.Findings[0].Title != "Missing docs" {
+		t.Errorf("Findings = %+v, want [Missing docs]", output.Findings)
+	}
+}
+
 // --- runPhasePair tests ---
 
 func TestRunPhasePair_HappyPath(t *testing.T) {
@@ -331,7 +514,7 @@ func TestRunPhasePair_HappyPath(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 
 	// Then it succeeds with a PASS signal on the last result
 	if err != nil {
@@ -345,7 +528,13 @@ func TestRunPhasePair_HappyPath(t *testing.T) {
 	}
 	// And both phases executed exactly once
 	if got := len(sp.calls); got != 2 {
-		t.Errorf("provider called %d times, want 2", got)
+		t.Fatalf("provider called %d times, want 2", got)
+	}
+	// And each prompt carries a marker naming its phase
+	for i, want := range []string{"worker", "reviewer"} {
+		if got, ok := provider.PhaseFromPrompt(sp.calls[i].prompt); !ok || got != want {
+			t.Errorf("call %d phase marker = %q, %v; want %q", i, got, ok, want)
+		}
 	}
 }
 
@@ -367,7 +556,7 @@ func TestRunPhasePair_RetryOnNeedsWork(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 
 	// Then it succeeds after retry
 	if err != nil {
@@ -412,7 +601,7 @@ func TestRunPhasePair_FeedbackPassedToWorker(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -443,7 +632,7 @@ func TestRunPhasePair_WorkerError(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 
 	// Then it returns a PipelineError for the worker phase
 	var pe *PipelineError
@@ -482,7 +671,7 @@ func TestRunPhasePair_ReviewerError(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 
 	// Then it returns a PipelineError for the reviewer phase
 	var pe *PipelineError
@@ -511,7 +700,7 @@ func TestRunPhasePair_MaxRetriesExceeded(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 
 	// Then it fails with retries exhausted
 	var pe *PipelineError
@@ -556,7 +745,7 @@ func TestRunPhasePair_UsesResolveRetryStrategy(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 
 	// Then it fails after 2 attempts (from pipeline defaults, not phase MaxRetries=0)
 	var pe *PipelineError
@@ -594,7 +783,7 @@ func TestRunPhasePair_PhaseOverrideTakesPrecedence(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 
 	// Then it fails after 2 attempts (from phase MaxRetries, not pipeline default of 5)
 	var pe *PipelineError
@@ -625,7 +814,7 @@ func TestRunPhasePair_ProviderError(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 
 	// Then partial results are empty (provider error before signal parsed)
 	if len(results) != 0 {
@@ -658,7 +847,7 @@ func TestRunPhasePair_StatusCallbacks(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-42"}
 
 	// When runPhasePair executes
-	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/2", "", 1)
+	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 2}, "", 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -736,7 +925,7 @@ func TestRunPhasePair_BackoffMultipliesTimeout(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes with 2 attempts
-	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -796,7 +985,7 @@ func TestRunPhasePair_BackoffNoEffectWhenNoTimeout(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -847,7 +1036,7 @@ func TestRunPhasePair_EscalateProviderSwitchesAfterN(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When runPhasePair executes
-	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -892,7 +1081,7 @@ func TestRunPhasePair_EscalateProviderNoEffectWhenEmpty(t *testing.T) {
 	reviewer := o.phases[1]
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
-	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	results, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -932,7 +1121,7 @@ func TestRunPhasePair_EscalateProviderUnknownReturnsError(t *testing.T) {
 	reviewer := o.phases[1]
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
-	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", "1/1", "", 1)
+	_, err := o.runPhasePair(context.Background(), worker, reviewer, pCtx, "/tmp/wt", phaseProgress{index: 1, total: 1}, "", 1)
 	if err == nil {
 		t.Fatal("expected error for unknown escalation provider, got nil")
 	}
@@ -988,6 +1177,60 @@ func TestRunPipeline_AllPhasesPass(t *testing.T) {
 	}
 }
 
+func TestRunPipeline_WorklogRecordsEveryAttempt(t *testing.T) {
+	// Given test-review says NEEDS_WORK twice before passing
+	sp := &sequenceProvider{responses: []mockResponse{
+		passResponse(),                    // test-writer (1)
+		needsWorkResponse("add tests"),    // test-review (1)
+		passResponse(),                    // test-writer (2)
+		needsWorkResponse("cover errors"), // test-review (2)
+		passResponse(),                    // test-writer (3)
+		passResponse(),                    // test-review (3)
+		passResponse(),                    // execute
+		passResponse(),                    // execute-review
+		passResponse(),                    // sign-off
+		passResponse(),                    // merge
+	}}
+	wl := &mockWorklogMgr{}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{path: "/tmp/worktrees/cap-1"}),
+		WithWorklogManager(wl),
+	)
+
+	// When RunPipeline executes
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then the worklog has an entry for every worker and reviewer attempt
+	if got := len(wl.entries); got != 10 {
+		t.Fatalf("worklog entries = %d, want 10", got)
+	}
+	want := []struct {
+		name     string
+		attempt  int
+		status   string
+		feedback string
+	}{
+		{"test-writer", 1, "PASS", ""},
+		{"test-review", 1, "NEEDS_WORK", "add tests"},
+		{"test-writer", 2, "PASS", ""},
+		{"test-review", 2, "NEEDS_WORK", "cover errors"},
+		{"test-writer", 3, "PASS", ""},
+		{"test-review", 3, "PASS", ""},
+	}
+	for i, w := range want {
+		e := wl.entries[i]
+		if e.Name != w.name || e.Attempt != w.attempt || e.Status != w.status {
+			t.Errorf("entry %d = %s attempt %d %s, want %s attempt %d %s", i, e.Name, e.Attempt, e.Status, w.name, w.attempt, w.status)
+		}
+		if w.feedback != "" && e.Feedback != w.feedback {
+			t.Errorf("entry %d feedback = %q, want %q", i, e.Feedback, w.feedback)
+		}
+	}
+}
+
 func TestRunPipeline_PhaseErrorAborts(t *testing.T) {
 	// Given execute-review returns ERROR (4th phase)
 	sp := &sequenceProvider{responses: []mockResponse{
@@ -1124,6 +1367,91 @@ func TestRunPipeline_WorktreeCreationFailure(t *testing.T) {
 	}
 }
 
+// overlapStub is an OverlapChecker returning fixed results.
+type overlapStub struct {
+	overlaps map[string][]string
+	err      error
+}
+
+func (s overlapStub) OverlappingChanges(string) (map[string][]string, error) {
+	return s.overlaps, s.err
+}
+
+func TestRunPipeline_OverlapCheck(t *testing.T) {
+	overlapping := map[string][]string{"cap-2": {"src/a.go", "src/b.go"}, "cap-3": {"go.mod"}}
+
+	tests := []struct {
+		name        string
+		checker     overlapStub
+		strict      bool
+		wantWarning string // Substring; empty means no warning.
+		wantErr     bool
+	}{
+		{name: "no overlaps", checker: overlapStub{overlaps: map[string][]string{}}},
+		{
+			name:        "overlaps warn",
+			checker:     overlapStub{overlaps: overlapping},
+			wantWarning: "cap-2 (src/a.go, src/b.go); cap-3 (go.mod)",
+		},
+		{
+			name:        "overlaps fail setup in strict mode",
+			checker:     overlapStub{overlaps: overlapping},
+			strict:      true,
+			wantWarning: "cap-2 (src/a.go, src/b.go)",
+			wantErr:     true,
+		},
+		{
+			name:        "failed check only warns",
+			checker:     overlapStub{err: errors.New("git exploded")},
+			strict:      true,
+			wantWarning: "overlap check failed: git exploded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given an orchestrator with an overlap check and no phases
+			wt := &mockWorktreeMgr{}
+			var warnings []string
+			o := New(&provider.MockProvider{NameVal: "test"},
+				WithPromptLoader(&mockPromptLoader{}),
+				WithWorktreeManager(wt),
+				WithPhases(nil),
+				WithOverlapCheck(tt.checker, tt.strict),
+				WithStatusCallback(func(su StatusUpdate) {
+					if su.Warning != "" {
+						warnings = append(warnings, su.Warning)
+					}
+				}),
+			)
+
+			// When RunPipeline executes
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+			// Then overlaps are warned about, and block setup only in strict mode
+			if tt.wantWarning == "" && len(warnings) > 0 {
+				t.Errorf("warnings = %q, want none", warnings)
+			}
+			if tt.wantWarning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], tt.wantWarning)) {
+				t.Errorf("warnings = %q, want one containing %q", warnings, tt.wantWarning)
+			}
+			var pe *PipelineError
+			if tt.wantErr {
+				if !errors.As(err, &pe) || pe.Phase != "setup" || !errors.Is(err, ErrOverlappingChanges) {
+					t.Fatalf("err = %v, want setup PipelineError wrapping ErrOverlappingChanges", err)
+				}
+				if len(wt.created) != 0 {
+					t.Errorf("worktree created %v, want none", wt.created)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunPipeline() error = %v", err)
+			}
+		})
+	}
+}
+
 func TestRunPipeline_WorklogCreationFailure(t *testing.T) {
 	// Given worklog creation fails
 	wl := &mockWorklogMgr{createErr: fmt.Errorf("template missing")}
@@ -1224,6 +1552,158 @@ func TestRunPipeline_StatusCallbacks(t *testing.T) {
 	}
 }
 
+func TestRunPipeline_FractionComplete(t *testing.T) {
+	// Given a pipeline whose reviewer sends its worker back once and whose
+	// third phase is skipped by its condition
+	var updates []StatusUpdate
+	sp := &sequenceProvider{responses: []mockResponse{
+		passResponse(),                      // worker
+		needsWorkResponse("add edge cases"), // reviewer, attempt 1
+		passResponse(),                      // worker retry
+		passResponse(),                      // reviewer, attempt 2
+		passResponse(),                      // merge
+	}}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{path: t.TempDir()}),
+		WithPhases([]PhaseDefinition{
+			{Name: "worker", Kind: Worker, MaxRetries: 1},
+			{Name: "reviewer", Kind: Reviewer, MaxRetries: 3, RetryTarget: "worker"},
+			{Name: "docs", Kind: Worker, MaxRetries: 1, Condition: "files_match:*.xyz"},
+			{Name: "merge", Kind: Worker, MaxRetries: 1},
+		}),
+		WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+	)
+
+	// When RunPipeline executes
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then every update carries its position, matching Progress
+	var last float64
+	for i, u := range updates {
+		if u.PhaseTotal != 4 || u.Progress != fmt.Sprintf("%d/4", u.PhaseIndex) {
+			t.Errorf("update[%d] %s/%s: index %d/%d, Progress %q", i, u.Phase, u.Status, u.PhaseIndex, u.PhaseTotal, u.Progress)
+		}
+		// And the fraction never falls back, even while the worker retries
+		if u.FractionComplete < last {
+			t.Errorf("update[%d] %s/%s: fraction %v after %v", i, u.Phase, u.Status, u.FractionComplete, last)
+		}
+		last = u.FractionComplete
+	}
+
+	// And the worker's retry counts as the reviewer's, and the skipped
+	// phase advances the fraction like a passed one
+	want := map[string]float64{
+		"worker/running/1":   0,
+		"worker/passed/1":    0.25,
+		"reviewer/running/2": 0.25,
+		"reviewer/failed/2":  0.25,
+		"worker/passed/2":    0.25,
+		"reviewer/passed/2":  0.5,
+		"docs/skipped/3":     0.75,
+		"merge/running/4":    0.75,
+		"merge/passed/4":     1,
+	}
+	for _, u := range updates {
+		key := fmt.Sprintf("%s/%s/%d", u.Phase, u.Status, u.PhaseIndex)
+		if w, ok := want[key]; ok && u.FractionComplete != w {
+			t.Errorf("%s: fraction = %v, want %v", key, u.FractionComplete, w)
+		}
+	}
+	if last != 1 {
+		t.Errorf("final fraction = %v, want 1", last)
+	}
+}
+
+func TestRunPipeline_InputOverridesPhasesAndCallback(t *testing.T) {
+	// Given an orchestrator with a configured callback
+	var configured, perRun []StatusUpdate
+	sp := &sequenceProvider{responses: nPassResponses(3)}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithStatusCallback(func(su StatusUpdate) { configured = append(configured, su) }),
+	)
+
+	// When a run names the minimal phase set and its own callback
+	input := PipelineInput{
+		BeadID:         "cap-1-validation",
+		Phases:         "minimal",
+		StatusCallback: func(su StatusUpdate) { perRun = append(perRun, su) },
+	}
+	out, err := o.RunPipeline(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then the minimal phases ran
+	if got := len(out.PhaseResults); got != 3 {
+		t.Fatalf("phase results = %d, want 3", got)
+	}
+	// And every update went to the per-run callback
+	if len(perRun) != 6 {
+		t.Errorf("per-run updates = %d, want 6", len(perRun))
+	}
+	if len(configured) != 0 {
+		t.Errorf("configured callback got %d updates, want 0", len(configured))
+	}
+
+	// When a later run sets neither
+	configured = nil
+	sp.responses, sp.callIdx = nPassResponses(6), 0
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then the configured phases and callback are used again
+	if len(configured) != 12 {
+		t.Errorf("configured updates = %d, want 12", len(configured))
+	}
+}
+
+func TestRunPipeline_InputPhasesUseResolver(t *testing.T) {
+	// Given an orchestrator whose resolver maps "validation" to one phase
+	var specs []string
+	resolver := func(spec string) ([]PhaseDefinition, error) {
+		specs = append(specs, spec)
+		return []PhaseDefinition{{Name: "validate", Kind: Worker}}, nil
+	}
+	o := New(&sequenceProvider{responses: nPassResponses(1)},
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhaseResolver(resolver),
+	)
+
+	// When a run names that phase set
+	out, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1-validation", Phases: "validation"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then the resolver's phases ran instead of LoadPhases
+	if !slices.Equal(specs, []string{"validation"}) {
+		t.Errorf("resolved specs = %v, want [validation]", specs)
+	}
+	if len(out.PhaseResults) != 1 || out.PhaseResults[0].PhaseName != "validate" {
+		t.Errorf("phase results = %+v, want validate only", out.PhaseResults)
+	}
+}
+
+func TestRunPipeline_UnknownInputPhases(t *testing.T) {
+	// Given a run naming a phase set that does not exist
+	o := New(&sequenceProvider{}, WithPromptLoader(&mockPromptLoader{}))
+	input := PipelineInput{BeadID: "cap-1", Phases: filepath.Join(t.TempDir(), "missing.yaml")}
+
+	// When RunPipeline executes
+	_, err := o.RunPipeline(context.Background(), input)
+
+	// Then it fails during setup
+	var pe *PipelineError
+	if !errors.As(err, &pe) || pe.Phase != "setup" {
+		t.Fatalf("error = %v, want setup PipelineError", err)
+	}
+}
+
 func TestRunPipeline_ContextCancelled(t *testing.T) {
 	// Given a cancelled context
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1341,6 +1821,133 @@ func TestRunPipeline_PromptContextCarriesMetadata(t *testing.T) {
 
 // --- executePhase tests ---
 
+func TestExecutePhase_ParseSignalError_SavesRawOutput(t *testing.T) {
+	// Given the provider returns unparseable output and a log dir is configured
+	logDir := t.TempDir()
+	sp := &sequenceProvider{responses: []mockResponse{
+		{result: provider.Result{Output: "I could not finish the task."}},
+	}}
+	o := New(sp, WithPromptLoader(&mockPromptLoader{}), WithPhases(twoPhases()), WithLogDir(logDir))
+
+	phase := o.phases[0]
+	pCtx := prompt.Context{BeadID: "cap-raw"}
+
+	// When executePhase is called for attempt 2
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 2)
+
+	// Then the raw output is written under <logDir>/<bead>/raw/<phase>-attempt-<n>.txt
+	wantPath := filepath.Join(logDir, "cap-raw", "raw", phase.Name+"-attempt-2.txt")
+	data, readErr := os.ReadFile(wantPath)
+	if readErr != nil {
+		t.Fatalf("raw output not saved: %v", readErr)
+	}
+	if string(data) != "I could not finish the task." {
+		t.Errorf("raw output = %q", data)
+	}
+	// And the error names the file and includes an output excerpt
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), wantPath) {
+		t.Errorf("error = %q, want mention of %s", err, wantPath)
+	}
+	if !strings.Contains(err.Error(), "I could not finish the task.") {
+		t.Errorf("error = %q, want output excerpt", err)
+	}
+}
+
+func TestExecutePhase_ParseSignalError_NamesTranscript(t *testing.T) {
+	// Given the provider returns unparseable output and transcripts are enabled
+	dir := t.TempDir()
+	sp := &sequenceProvider{responses: []mockResponse{
+		{result: provider.Result{Output: "I could not finish the task."}},
+	}}
+	o := New(sp, WithPromptLoader(&mockPromptLoader{}), WithPhases(twoPhases()), WithTranscriptDir(dir))
+
+	phase := o.phases[0]
+	pCtx := prompt.Context{BeadID: "cap-tx"}
+
+	// When executePhase is called for attempt 2
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 2)
+
+	// Then the transcript holds the prompt sent and the raw output
+	wantPath := filepath.Join(dir, "cap-tx", "transcripts", "001-worker-attempt2.txt")
+	data, readErr := os.ReadFile(wantPath)
+	if readErr != nil {
+		t.Fatalf("transcript not saved: %v", readErr)
+	}
+	if !strings.Contains(string(data), "prompt:worker") || !strings.Contains(string(data), "I could not finish the task.") {
+		t.Errorf("transcript = %q, want prompt and output", data)
+	}
+	// And the parse error names the transcript
+	if err == nil || !strings.Contains(err.Error(), "transcript: "+wantPath) {
+		t.Errorf("error = %v, want mention of %s", err, wantPath)
+	}
+}
+
+func TestExecutePhase_TranscriptsNumberedInOrder(t *testing.T) {
+	// Given transcripts are enabled for a worker and a gate phase
+	dir := t.TempDir()
+	sp := &sequenceProvider{responses: []mockResponse{passResponse()}}
+	gr := &mockGateRunner{signals: []provider.Signal{{
+		Status: provider.StatusError, Summary: "exit status 2", Feedback: "main.go:3:1: undefined: x",
+	}}}
+	phases := []PhaseDefinition{
+		{Name: "worker", Kind: Worker},
+		{Name: "lint", Kind: Gate, Command: "make lint"},
+	}
+	o := New(sp, WithPromptLoader(&mockPromptLoader{}), WithGateRunner(gr), WithPhases(phases), WithTranscriptDir(dir))
+	pCtx := prompt.Context{BeadID: "cap-seq"}
+
+	// When both phases execute
+	for _, phase := range phases {
+		if _, _, err := o.executePhase(context.Background(), phase, pCtx, t.TempDir(), 1); err != nil {
+			t.Fatalf("executePhase(%s) error = %v", phase.Name, err)
+		}
+	}
+
+	// Then each execution gets the next sequence number
+	txDir := filepath.Join(dir, "cap-seq", "transcripts")
+	entries, err := os.ReadDir(txDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	want := []string{"001-worker-attempt1.txt", "002-lint-attempt1.txt"}
+	if !slices.Equal(names, want) {
+		t.Fatalf("transcripts = %v, want %v", names, want)
+	}
+	// And the gate transcript holds its command, exit status, and output
+	data, err := os.ReadFile(filepath.Join(txDir, want[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"make lint", "exit status 2", "main.go:3:1: undefined: x"} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("gate transcript missing %q:\n%s", s, data)
+		}
+	}
+}
+
+func TestExecutePhase_NoTranscriptsByDefault(t *testing.T) {
+	// Given transcripts are not enabled
+	sp := &sequenceProvider{responses: []mockResponse{passResponse()}}
+	o := New(sp, WithPromptLoader(&mockPromptLoader{}), WithPhases(twoPhases()))
+
+	// When a phase executes
+	if _, _, err := o.executePhase(context.Background(), o.phases[0], prompt.Context{BeadID: "cap-1"}, "/tmp/wt", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Then no transcript path is produced
+	if got := o.saveTranscript("cap-1", "worker", 1, "x"); got != "" {
+		t.Errorf("saveTranscript() = %q, want empty when disabled", got)
+	}
+}
+
 func TestExecutePhase_PromptError(t *testing.T) {
 	// Given a prompt loader that returns an error
 	pl := &mockPromptLoader{
@@ -1355,7 +1962,7 @@ func TestExecutePhase_PromptError(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When executePhase is called
-	_, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt")
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 1)
 
 	// Then it returns an error mentioning the phase
 	if err == nil {
@@ -1377,7 +1984,7 @@ func TestExecutePhase_ParseSignalError(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When executePhase is called
-	_, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt")
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 1)
 
 	// Then it returns a parse error
 	if err == nil {
@@ -1538,6 +2145,145 @@ func TestRunPipeline_GatePhaseError_Required(t *testing.T) {
 	}
 }
 
+func TestRunPipeline_GatePhaseError_MessageListsFindings(t *testing.T) {
+	finding := func(title string) provider.Finding {
+		return provider.Finding{Title: title, Severity: "minor"}
+	}
+	tests := []struct {
+		name     string
+		feedback string
+		findings []provider.Finding
+		want     string
+	}{
+		{
+			name:     "first three findings",
+			feedback: "a.go:1:1: one\nb.go:2:2: two\nc.go:3:3: three\nd.go:4:4: four\n",
+			findings: []provider.Finding{finding("a.go:1:1: one"), finding("b.go:2:2: two"), finding("c.go:3:3: three"), finding("d.go:4:4: four")},
+			want:     `pipeline: phase "lint" attempt 1: gate failed (exit status 1): a.go:1:1: one; b.go:2:2: two; c.go:3:3: three; and 1 more`,
+		},
+		{
+			name:     "last line without findings",
+			feedback: "building\nmake: *** [lint] Error 1\n\n",
+			findings: []provider.Finding{},
+			want:     `pipeline: phase "lint" attempt 1: gate failed (exit status 1): make: *** [lint] Error 1`,
+		},
+		{
+			name:     "no output",
+			findings: []provider.Finding{},
+			want:     `pipeline: phase "lint" attempt 1: gate failed (exit status 1)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a required gate that fails with the output
+			gr := &mockGateRunner{signals: []provider.Signal{{
+				Status: provider.StatusError, Feedback: tt.feedback, Summary: "exit status 1",
+				FilesChanged: []string{}, Findings: tt.findings,
+			}}}
+			o := New(&sequenceProvider{},
+				WithPromptLoader(&mockPromptLoader{}),
+				WithPhases([]PhaseDefinition{{Name: "lint", Kind: Gate, Command: "make lint"}}),
+				WithGateRunner(gr),
+			)
+
+			// When RunPipeline executes
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+			// Then the error names the gate failure and keeps the signal
+			if !errors.Is(err, ErrGateFailed) {
+				t.Fatalf("error = %v, want ErrGateFailed", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("error = %q, want %q", err.Error(), tt.want)
+			}
+			var pe *PipelineError
+			if errors.As(err, &pe) && pe.Signal.Feedback != tt.feedback {
+				t.Errorf("Signal.Feedback = %q, want the gate output", pe.Signal.Feedback)
+			}
+		})
+	}
+}
+
+func TestRunPipeline_GateRetryTarget(t *testing.T) {
+	gateFail := provider.Signal{
+		Status: provider.StatusError, Feedback: "--- FAIL: TestAdd", Summary: "exit status 1",
+		FilesChanged: []string{}, Findings: []provider.Finding{},
+	}
+	gatePass := provider.Signal{
+		Status: provider.StatusPass, Feedback: "gate passed", Summary: "ok",
+		FilesChanged: []string{}, Findings: []provider.Finding{},
+	}
+	tests := []struct {
+		name        string
+		optional    bool
+		gate        []provider.Signal
+		wantErr     bool
+		wantWorkers int
+	}{
+		{name: "passes on retry", gate: []provider.Signal{gateFail, gatePass}, wantWorkers: 2},
+		{name: "optional gate retries too", optional: true, gate: []provider.Signal{gateFail, gatePass}, wantWorkers: 2},
+		{name: "retries exhausted", gate: []provider.Signal{gateFail, gateFail, gateFail}, wantErr: true, wantWorkers: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a test gate that sends failures back to execute
+			gr := &mockGateRunner{signals: tt.gate}
+			responses := make([]mockResponse, tt.wantWorkers)
+			for i := range responses {
+				responses[i] = passResponse()
+			}
+			sp := &sequenceProvider{responses: responses}
+			var feedback []string
+			var updates []StatusUpdate
+			o := New(sp,
+				WithPromptLoader(&mockPromptLoader{composeFunc: func(name string, ctx prompt.Context) (string, error) {
+					feedback = append(feedback, ctx.Feedback)
+					return "prompt:" + name, nil
+				}}),
+				WithPhases([]PhaseDefinition{
+					{Name: "execute", Kind: Worker, MaxRetries: 3},
+					{Name: "test", Kind: Gate, Command: "go test ./...", MaxRetries: 3, RetryTarget: "execute", Optional: tt.optional},
+				}),
+				WithGateRunner(gr),
+				WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+			)
+
+			// When RunPipeline executes
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+			// Then execute reran with the gate's output until the gate passed or retries ran out
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrGateFailed) {
+				t.Errorf("error = %v, want ErrGateFailed", err)
+			}
+			if len(sp.calls) != tt.wantWorkers || len(gr.calls) != len(tt.gate) {
+				t.Errorf("execute ran %d times, gate %d; want %d and %d", len(sp.calls), len(gr.calls), tt.wantWorkers, len(tt.gate))
+			}
+			for i, fb := range feedback[1:] {
+				if fb != gateFail.Feedback {
+					t.Errorf("execute attempt %d feedback = %q, want the gate output", i+2, fb)
+				}
+			}
+
+			// And the gate's updates carry its attempt and limit
+			var gateAttempts []int
+			for _, su := range updates {
+				if su.Phase == "test" && su.Status != PhaseRunning {
+					gateAttempts = append(gateAttempts, su.Attempt)
+					if su.Status == PhaseSkipped {
+						t.Errorf("gate reported skipped, want it retried")
+					}
+				}
+			}
+			if len(gateAttempts) != len(tt.gate) || gateAttempts[len(gateAttempts)-1] != len(tt.gate) {
+				t.Errorf("gate attempts = %v, want 1..%d", gateAttempts, len(tt.gate))
+			}
+		})
+	}
+}
+
 func TestRunPipeline_GateNoRunner(t *testing.T) {
 	// Given a pipeline with a gate but no GateRunner
 	sp := &sequenceProvider{responses: []mockResponse{
@@ -1650,7 +2396,7 @@ func TestFindPhase_NotFound(t *testing.T) {
 func TestEvaluateCondition_EmptyAlwaysRuns(t *testing.T) {
 	// Given an empty condition string
 	// When evaluateCondition is called
-	ok, err := evaluateCondition("", t.TempDir())
+	ok, err := evaluateCondition("", conditionEnv{dir: t.TempDir()})
 
 	// Then the phase should run (condition met)
 	if err != nil {
@@ -1669,7 +2415,7 @@ func TestEvaluateCondition_FilesMatch_Found(t *testing.T) {
 	}
 
 	// When evaluateCondition checks for *.go files
-	ok, err := evaluateCondition("files_match:*.go", dir)
+	ok, err := evaluateCondition("files_match:*.go", conditionEnv{dir: dir})
 
 	// Then the condition is met
 	if err != nil {
@@ -1688,7 +2434,7 @@ func TestEvaluateCondition_FilesMatch_NotFound(t *testing.T) {
 	}
 
 	// When evaluateCondition checks for *.xyz files
-	ok, err := evaluateCondition("files_match:*.xyz", dir)
+	ok, err := evaluateCondition("files_match:*.xyz", conditionEnv{dir: dir})
 
 	// Then the condition is NOT met
 	if err != nil {
@@ -1702,7 +2448,7 @@ func TestEvaluateCondition_FilesMatch_NotFound(t *testing.T) {
 func TestEvaluateCondition_UnrecognizedCondition(t *testing.T) {
 	// Given an unrecognized condition format
 	// When evaluateCondition is called
-	_, err := evaluateCondition("unknown_check:foo", t.TempDir())
+	_, err := evaluateCondition("unknown_check:foo", conditionEnv{dir: t.TempDir()})
 
 	// Then it returns an error
 	if err == nil {
@@ -1866,7 +2612,7 @@ func TestExecutePhase_UsesNamedProvider(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When executePhase is called
-	signal, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt")
+	signal, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 1)
 
 	// Then it succeeds
 	if err != nil {
@@ -1898,7 +2644,7 @@ func TestExecutePhase_DefaultProviderWhenEmpty(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When executePhase is called
-	_, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt")
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 1)
 
 	// Then it succeeds using the default provider
 	if err != nil {
@@ -1922,7 +2668,7 @@ func TestExecutePhase_UnknownProviderError(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When executePhase is called with a non-existent provider name
-	_, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt")
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 1)
 
 	// Then it returns an error mentioning the unknown provider
 	if err == nil {
@@ -1965,7 +2711,7 @@ func TestExecutePhase_TimeoutSetsDeadline(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When executePhase is called
-	_, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt")
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 1)
 
 	// Then it succeeds
 	if err != nil {
@@ -1997,7 +2743,7 @@ func TestExecutePhase_NoTimeoutNoDeadline(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When executePhase is called with a context that has no deadline
-	_, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt")
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 1)
 
 	// Then it succeeds
 	if err != nil {
@@ -2042,7 +2788,7 @@ func TestExecutePhase_TimeoutAppliesToGate(t *testing.T) {
 	pCtx := prompt.Context{BeadID: "cap-1"}
 
 	// When executePhase is called
-	_, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt")
+	_, _, err := o.executePhase(context.Background(), phase, pCtx, "/tmp/wt", 1)
 
 	// Then it succeeds
 	if err != nil {
@@ -2068,6 +2814,8 @@ type mockCheckpointStore struct {
 	loadCP    PipelineCheckpoint
 	loadFound bool
 	loadErr   error
+
+	removed bool
 }
 
 func (m *mockCheckpointStore) SaveCheckpoint(cp PipelineCheckpoint) error {
@@ -2080,6 +2828,7 @@ func (m *mockCheckpointStore) LoadCheckpoint(string) (PipelineCheckpoint, bool,
 }
 
 func (m *mockCheckpointStore) RemoveCheckpoint(string) error {
+	m.removed = true
 	return nil
 }
 
@@ -2518,6 +3267,82 @@ func TestRunPipeline_ResumeMergesWithInputSkipPhases(t *testing.T) {
 	}
 }
 
+func TestRunPipeline_InputSkipPhasesRecorded(t *testing.T) {
+	// Given a 3-phase pipeline where the caller skips phase-b
+	sp := &sequenceProvider{responses: nPassResponses(2)}
+	var updates []StatusUpdate
+	cb := func(su StatusUpdate) { updates = append(updates, su) }
+
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhases(threePhases()),
+		WithStatusCallback(cb),
+	)
+
+	input := PipelineInput{BeadID: "cap-42", SkipPhases: []string{"phase-b"}}
+
+	// When RunPipeline executes
+	out, err := o.RunPipeline(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then phase-b is not executed
+	if got := len(sp.calls); got != 2 {
+		t.Errorf("provider called %d times, want 2", got)
+	}
+	// And it appears in PhaseResults as intentionally skipped
+	if len(out.PhaseResults) != 3 {
+		t.Fatalf("got %d phase results, want 3", len(out.PhaseResults))
+	}
+	pr := out.PhaseResults[1]
+	if pr.PhaseName != "phase-b" || pr.Signal.Status != provider.StatusSkip {
+		t.Errorf("PhaseResults[1] = %s/%s, want phase-b/SKIP", pr.PhaseName, pr.Signal.Status)
+	}
+	if pr.Signal.Summary != "skipped by request" {
+		t.Errorf("Summary = %q, want %q", pr.Signal.Summary, "skipped by request")
+	}
+	// And a PhaseSkipped callback is emitted for it
+	var foundSkipped bool
+	for _, u := range updates {
+		if u.Phase == "phase-b" && u.Status == PhaseSkipped {
+			foundSkipped = true
+		}
+	}
+	if !foundSkipped {
+		t.Error("expected PhaseSkipped callback for phase-b")
+	}
+	for _, u := range updates {
+		if u.SkipRequested != (u.Phase == "phase-b") {
+			t.Errorf("%s %s SkipRequested = %v", u.Phase, u.Status, u.SkipRequested)
+		}
+	}
+}
+
+func TestRunPipeline_ReviewerWithSkippedTarget(t *testing.T) {
+	// Given a reviewer run alone, its retry target skipped by request
+	sp := &sequenceProvider{responses: []mockResponse{needsWorkResponse("missing tests")}}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhases([]PhaseDefinition{
+			{Name: "execute", Kind: Worker, MaxRetries: 3},
+			{Name: "review", Kind: Reviewer, MaxRetries: 3, RetryTarget: "execute"},
+		}),
+	)
+
+	// When the reviewer returns NEEDS_WORK
+	_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-42", SkipPhases: []string{"execute"}})
+
+	// Then the run fails with the verdict instead of running the skipped worker
+	var pe *PipelineError
+	if !errors.As(err, &pe) || pe.Phase != "review" || !strings.Contains(err.Error(), `retry target "execute" was skipped`) {
+		t.Fatalf("RunPipeline() error = %v, want review failure naming the skipped target", err)
+	}
+	if got := len(sp.calls); got != 1 {
+		t.Errorf("provider called %d times, want 1", got)
+	}
+}
+
 // --- Pause tests ---
 
 func TestRunPipeline_PauseBeforeSecondPhase(t *testing.T) {
@@ -2728,3 +3553,211 @@ func TestRunPipeline_PauseBeforeAnyPhase(t *testing.T) {
 		t.Errorf("checkpoint results = %d, want 0", got)
 	}
 }
+
+// --- Timeout tests ---
+
+// stallingProvider passes the first passes calls, then blocks until the
+// context is done.
+type stallingProvider struct {
+	passes int
+	calls  int
+}
+
+func (s *stallingProvider) Name() string { return "stalling" }
+
+func (s *stallingProvider) Execute(ctx context.Context, _, _ string) (provider.Result, error) {
+	s.calls++
+	if s.calls <= s.passes {
+		return passResponse().result, nil
+	}
+	<-ctx.Done()
+	return provider.Result{}, ctx.Err()
+}
+
+func TestRunPipeline_PhaseTimeoutDefault(t *testing.T) {
+	// Given one phase with its own timeout and one without, and a default
+	sp := &sequenceProvider{responses: nPassResponses(2)}
+	dc := &deadlineCapturingProvider{inner: sp}
+	o := New(dc,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhases([]PhaseDefinition{
+			{Name: "own", Kind: Worker, Timeout: 10 * time.Second},
+			{Name: "default", Kind: Worker},
+		}),
+		WithPhaseTimeout(40*time.Second),
+	)
+
+	// When the pipeline runs
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"}); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then the phase's own timeout wins and the other gets the default
+	want := []time.Duration{10 * time.Second, 40 * time.Second}
+	for i, timeout := range dc.timeouts {
+		if timeout <= 0 || timeout > want[i] || want[i]-timeout > 2*time.Second {
+			t.Errorf("timeout[%d] = %v, want ~%v", i, timeout, want[i])
+		}
+	}
+}
+
+func TestRunPipeline_RunTimeoutExceeded(t *testing.T) {
+	// Given a pipeline whose second phase outlasts the run timeout
+	sp := &stallingProvider{passes: 1}
+	cs := &mockCheckpointStore{}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhases(threePhases()),
+		WithCheckpointStore(cs),
+		WithRunTimeout(50*time.Millisecond),
+	)
+
+	// When the pipeline runs
+	_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+	// Then the error names the timeout and the interrupted phase
+	var rte *RunTimeoutError
+	if !errors.As(err, &rte) {
+		t.Fatalf("error = %v, want *RunTimeoutError", err)
+	}
+	if rte.Phase != "phase-b" {
+		t.Errorf("phase = %q, want phase-b", rte.Phase)
+	}
+	if want := "run timeout exceeded after 50ms during phase phase-b"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error does not wrap context.DeadlineExceeded: %v", err)
+	}
+	// And the finished phase is checkpointed for resume
+	if len(cs.saved) == 0 {
+		t.Fatal("no checkpoint saved")
+	}
+	if got := cs.saved[len(cs.saved)-1].PhaseResults; len(got) != 1 || got[0].PhaseName != "phase-a" {
+		t.Errorf("checkpoint results = %+v, want phase-a only", got)
+	}
+}
+
+func TestRunPipeline_PhaseTimeoutIsNotRunTimeout(t *testing.T) {
+	// Given a phase timeout that fires well inside the run timeout
+	sp := &stallingProvider{}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhases([]PhaseDefinition{{Name: "slow", Kind: Worker, Timeout: 20 * time.Millisecond}}),
+		WithRunTimeout(time.Hour),
+	)
+
+	// When the pipeline runs
+	_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+	// Then it fails as an ordinary phase error
+	var rte *RunTimeoutError
+	if errors.As(err, &rte) {
+		t.Errorf("error = %v, want a phase error, not a run timeout", err)
+	}
+	var pe *PipelineError
+	if !errors.As(err, &pe) || pe.Phase != "slow" {
+		t.Errorf("error = %v, want PipelineError for phase slow", err)
+	}
+}
+
+func TestRunPipeline_MaxProviderCalls(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxCalls    int
+		wantErr     bool
+		wantCalls   int
+		wantPhase   string
+		wantAttempt int
+	}{
+		{name: "budget runs out mid-retry", maxCalls: 3, wantErr: true, wantCalls: 3, wantPhase: "test-review", wantAttempt: 2},
+		{name: "budget runs out on a single phase", maxCalls: 4, wantErr: true, wantCalls: 4, wantPhase: "execute", wantAttempt: 1},
+		{name: "zero means no limit", maxCalls: 0, wantCalls: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given test-review needs one retry and a provider call budget
+			sp := &sequenceProvider{responses: []mockResponse{
+				passResponse(),                 // test-writer (1)
+				needsWorkResponse("add tests"), // test-review (1)
+				passResponse(),                 // test-writer (2)
+				passResponse(),                 // test-review (2)
+				passResponse(),                 // execute
+				passResponse(),                 // execute-review
+				passResponse(),                 // sign-off
+				passResponse(),                 // merge
+			}}
+			cs := &mockCheckpointStore{}
+			wl := &mockWorklogMgr{}
+			var updates []StatusUpdate
+			o := New(sp,
+				WithPromptLoader(&mockPromptLoader{}),
+				WithWorktreeManager(&mockWorktreeMgr{path: "/tmp/worktrees/cap-1"}),
+				WithWorklogManager(wl),
+				WithCheckpointStore(cs),
+				WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+				WithMaxProviderCalls(tt.maxCalls),
+			)
+
+			// When the pipeline runs
+			output, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+			// Then the provider is called no more than the budget allows
+			if got := len(sp.calls); got != tt.wantCalls {
+				t.Errorf("provider calls = %d, want %d", got, tt.wantCalls)
+			}
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			// And the error wraps ErrBudgetExceeded in a PipelineError naming the phase and attempt
+			if !errors.Is(err, ErrBudgetExceeded) {
+				t.Fatalf("error = %v, want ErrBudgetExceeded", err)
+			}
+			var pe *PipelineError
+			if !errors.As(err, &pe) || pe.Phase != tt.wantPhase || pe.Attempt != tt.wantAttempt {
+				t.Errorf("error = %v, want PipelineError for %s attempt %d", err, tt.wantPhase, tt.wantAttempt)
+			}
+			// And the checkpoint and worklog record every call that ran
+			if len(cs.saved) == 0 {
+				t.Fatal("no checkpoint saved")
+			}
+			if got := len(cs.saved[len(cs.saved)-1].PhaseResults); got != tt.wantCalls {
+				t.Errorf("checkpoint results = %d, want %d", got, tt.wantCalls)
+			}
+			if got := len(output.PhaseResults); got != tt.wantCalls {
+				t.Errorf("output results = %d, want %d", got, tt.wantCalls)
+			}
+			if got := len(wl.entries); got != tt.wantCalls {
+				t.Errorf("worklog entries = %d, want %d", got, tt.wantCalls)
+			}
+			// And the last status update reports the budget on the stopped phase
+			last := updates[len(updates)-1]
+			if last.Phase != tt.wantPhase || last.Status != PhaseError || last.Attempt != tt.wantAttempt ||
+				last.Signal == nil || !strings.Contains(last.Signal.Feedback, "budget") {
+				t.Errorf("last update = %+v, want budget error on %s attempt %d", last, tt.wantPhase, tt.wantAttempt)
+			}
+		})
+	}
+}
+
+func TestShortDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Minute, "30m"},
+		{2 * time.Hour, "2h"},
+		{90 * time.Second, "1m30s"},
+		{time.Hour + 30*time.Second, "1h0m30s"},
+		{50 * time.Millisecond, "50ms"},
+	}
+	for _, tt := range tests {
+		if got := shortDuration(tt.d); got != tt.want {
+			t.Errorf("shortDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
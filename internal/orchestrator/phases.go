@@ -31,16 +31,18 @@ func (k PhaseKind) String() string {
 
 // PhaseDefinition describes a single pipeline phase.
 type PhaseDefinition struct {
-	Name        string        // Phase name (also used as prompt template name for Worker/Reviewer).
-	Kind        PhaseKind     // Worker, Reviewer, or Gate.
-	Prompt      string        // Template name override (defaults to Name for Worker/Reviewer).
-	Command     string        // Shell command (required for Gate, ignored otherwise).
-	MaxRetries  int           // Maximum retry attempts for this phase's pair.
-	RetryTarget string        // Phase to re-run on NEEDS_WORK (empty for workers).
-	Optional    bool          // If true, SKIP/ERROR → continue pipeline.
-	Condition   string        // "files_match:<glob>" or empty (always run). Evaluated before phase execution.
-	Provider    string        // Override default provider for this phase (looked up from providers registry).
-	Timeout     time.Duration // Override default timeout for this phase.
+	Name              string        // Phase name (also used as prompt template name for Worker/Reviewer).
+	Kind              PhaseKind     // Worker, Reviewer, or Gate.
+	Prompt            string        // Template name override (defaults to Name for Worker/Reviewer).
+	Command           string        // Shell command (required for Gate, ignored otherwise).
+	MaxRetries        int           // Maximum retry attempts for this phase's pair.
+	RetryTarget       string        // Phase to re-run on NEEDS_WORK or a failed gate (empty for workers).
+	Optional          bool          // If true, SKIP/ERROR → continue pipeline.
+	Condition         string        // See condition.go for the syntax; empty always runs. Evaluated before phase execution.
+	Provider          string        // Override default provider for this phase (looked up from providers registry).
+	Timeout           time.Duration // Override default timeout for this phase.
+	Workdir           string        // Worktree-relative directory to run in (empty uses the bead default or the root).
+	RequiredArtifacts []string      // Worktree-relative globs (see artifacts.go) that must each match a file once the phase passes.
 }
 
 // PromptName returns the prompt template name for this phase.
@@ -62,18 +64,34 @@ const (
 	PhaseFailed  PhaseStatus = "failed"
 	PhaseError   PhaseStatus = "error"
 	PhaseSkipped PhaseStatus = "skipped"
+	// PhaseProgress reports what a running phase is doing; the phase is
+	// still running. Only streaming providers send it.
+	PhaseProgress PhaseStatus = "progress"
+	// PhaseRetrying reports that a provider call failed transiently and is
+	// about to be retried; Message says when and why. The phase is still
+	// running and its attempt does not change.
+	PhaseRetrying PhaseStatus = "retrying"
 )
 
 // StatusUpdate carries progress information for a single phase execution.
 type StatusUpdate struct {
-	BeadID   string           // The bead being processed.
-	Phase    string           // Current phase name.
-	Status   PhaseStatus      // Current phase status.
-	Progress string           // Human-readable progress (e.g. "2/6").
-	Attempt  int              // Current attempt number (1-based).
-	MaxRetry int              // Maximum retries configured.
-	Duration time.Duration    // Phase execution time (populated on completion, zero while running).
-	Signal   *provider.Signal // Populated on phase completion (passed/failed/error), nil while running.
+	BeadID           string           // The bead being processed.
+	Phase            string           // Current phase name.
+	Status           PhaseStatus      // Current phase status.
+	Progress         string           // Human-readable progress (e.g. "2/6"), PhaseIndex/PhaseTotal.
+	PhaseIndex       int              // 1-based position of the phase being run, or of the phase whose retry this is; 0 for baseline checks and updates not tied to the pipeline.
+	PhaseTotal       int              // Number of phases in the pipeline; 0 when the update carries no position.
+	FractionComplete float64          // Share of the pipeline done, 0 to 1: phases before PhaseIndex, plus that phase once it passes or is skipped. Retries never lower it.
+	Attempt          int              // Current attempt number (1-based).
+	MaxRetry         int              // Maximum retries configured.
+	Duration         time.Duration    // Phase execution time (populated on completion, zero while running).
+	Usage            provider.Usage   // Tokens the phase consumed (populated on completion; zero for gates and providers that don't report it).
+	Signal           *provider.Signal // Populated on phase completion (passed/failed/error), nil while running.
+	Warning          string           // Setup notice not tied to a phase; Phase and Status are empty when set.
+	Message          string           // What the provider is doing; set only for PhaseProgress and PhaseRetrying.
+	MissingArtifacts []string         // Required artifact globs that matched no file; set when the artifact check turned a PASS into NEEDS_WORK.
+	SkipRequested    bool             // The caller asked to skip the phase (PipelineInput.SkipPhases); set only for PhaseSkipped.
+	SkipReason       string           // Why the phase was skipped, when known; set only for PhaseSkipped.
 }
 
 // StatusCallback receives phase progress updates.
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,16 +15,18 @@ import (
 
 // phaseYAML is the YAML representation of a PhaseDefinition.
 type phaseYAML struct {
-	Name        string `yaml:"name"`
-	Kind        string `yaml:"kind"`                   // "worker" | "reviewer" | "gate"
-	Prompt      string `yaml:"prompt,omitempty"`       // Template name override
-	Command     string `yaml:"command,omitempty"`      // Shell command for gate
-	MaxRetries  int    `yaml:"max_retries,omitempty"`  // 0 means use pipeline default
-	RetryTarget string `yaml:"retry_target,omitempty"` // Phase to retry on NEEDS_WORK
-	Optional    bool   `yaml:"optional,omitempty"`     // Continue pipeline on failure
-	Condition   string `yaml:"condition,omitempty"`    // "files_match:<glob>" or empty
-	Provider    string `yaml:"provider,omitempty"`     // Per-phase provider override
-	Timeout     string `yaml:"timeout,omitempty"`      // Duration string (e.g. "5m")
+	Name              string   `yaml:"name"`
+	Kind              string   `yaml:"kind"`                         // "worker" | "reviewer" | "gate"
+	Prompt            string   `yaml:"prompt,omitempty"`             // Template name override
+	Command           string   `yaml:"command,omitempty"`            // Shell command for gate
+	MaxRetries        int      `yaml:"max_retries,omitempty"`        // 0 means use pipeline default
+	RetryTarget       string   `yaml:"retry_target,omitempty"`       // Phase to retry on NEEDS_WORK or a failed gate
+	Optional          bool     `yaml:"optional,omitempty"`           // Continue pipeline on failure
+	Condition         string   `yaml:"condition,omitempty"`          // e.g. "files_match:<glob>"; empty always runs
+	Provider          string   `yaml:"provider,omitempty"`           // Per-phase provider override
+	Timeout           string   `yaml:"timeout,omitempty"`            // Duration string (e.g. "5m")
+	Workdir           string   `yaml:"workdir,omitempty"`            // Worktree-relative working directory
+	RequiredArtifacts []string `yaml:"required_artifacts,omitempty"` // Globs that must match a file once the phase passes
 }
 
 // phasesFile is the top-level YAML structure for a phases file.
@@ -31,15 +34,102 @@ type phasesFile struct {
 	Phases []phaseYAML `yaml:"phases"`
 }
 
+// PhaseOverride changes selected fields of a phase that is already in the
+// pipeline, so a config can tune a built-in phase without redefining it.
+// Nil fields keep the phase's value.
+type PhaseOverride struct {
+	Prompt            *string
+	Command           *string
+	MaxRetries        *int
+	RetryTarget       *string
+	Optional          *bool
+	Condition         *string
+	Provider          *string
+	Timeout           *time.Duration
+	Workdir           *string
+	RequiredArtifacts *[]string
+}
+
 // LoadPhases resolves a phases specifier to a slice of PhaseDefinitions.
 // The specifier can be a preset name ("default", "minimal", "thorough")
-// or a path to a YAML file.
-func LoadPhases(specifier string) ([]PhaseDefinition, error) {
-	if phases := PresetPhases(specifier); phases != nil {
+// or a path to a YAML file. Each overrides map, keyed by phase name, is
+// applied in order; the merged list is then validated.
+func LoadPhases(specifier string, overrides ...map[string]PhaseOverride) ([]PhaseDefinition, error) {
+	phases := PresetPhases(specifier)
+	if phases == nil {
+		var err error
+		if phases, err = LoadPhasesFile(specifier); err != nil {
+			return nil, err
+		}
+	}
+	if len(overrides) == 0 {
 		return phases, nil
 	}
 
-	return LoadPhasesFile(specifier)
+	for _, set := range overrides {
+		if err := applyOverrides(phases, set); err != nil {
+			return nil, err
+		}
+	}
+	if err := ValidatePhases(phases); err != nil {
+		return nil, err
+	}
+	return phases, nil
+}
+
+// applyOverrides updates phases in place. Every key must name a phase.
+func applyOverrides(phases []PhaseDefinition, overrides map[string]PhaseOverride) error {
+	index := make(map[string]int, len(phases))
+	valid := make([]string, 0, len(phases))
+	for i, p := range phases {
+		index[p.Name] = i
+		valid = append(valid, p.Name)
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		i, ok := index[name]
+		if !ok {
+			return fmt.Errorf("phases: override for unknown phase %q (valid: %s)", name, strings.Join(valid, ", "))
+		}
+		o, p := overrides[name], &phases[i]
+		if o.Prompt != nil {
+			p.Prompt = *o.Prompt
+		}
+		if o.Command != nil {
+			p.Command = *o.Command
+		}
+		if o.MaxRetries != nil {
+			p.MaxRetries = *o.MaxRetries
+		}
+		if o.RetryTarget != nil {
+			p.RetryTarget = *o.RetryTarget
+		}
+		if o.Optional != nil {
+			p.Optional = *o.Optional
+		}
+		if o.Condition != nil {
+			p.Condition = *o.Condition
+		}
+		if o.Provider != nil {
+			p.Provider = *o.Provider
+		}
+		if o.Timeout != nil {
+			p.Timeout = *o.Timeout
+		}
+		if o.Workdir != nil {
+			p.Workdir = *o.Workdir
+		}
+		if o.RequiredArtifacts != nil {
+			p.RequiredArtifacts = *o.RequiredArtifacts
+		}
+	}
+	return nil
 }
 
 // LoadPhasesFile loads phase definitions from a YAML file.
@@ -65,13 +155,17 @@ func ParsePhasesYAML(data []byte) ([]PhaseDefinition, error) {
 	}
 
 	phases := make([]PhaseDefinition, len(file.Phases))
+	var problems []string
 	for i, py := range file.Phases {
 		pd, err := convertPhaseYAML(py)
 		if err != nil {
-			return nil, fmt.Errorf("phases[%d] %q: %w", i, py.Name, err)
+			problems = append(problems, fmt.Sprintf("phases[%d] %q: %v", i, py.Name, err))
 		}
 		phases[i] = pd
 	}
+	if len(problems) > 0 {
+		return nil, &PhasesError{Problems: problems}
+	}
 
 	if err := ValidatePhases(phases); err != nil {
 		return nil, err
@@ -87,14 +181,16 @@ func convertPhaseYAML(py phaseYAML) (PhaseDefinition, error) {
 	}
 
 	pd := PhaseDefinition{
-		Name:        py.Name,
-		Prompt:      py.Prompt,
-		Command:     py.Command,
-		MaxRetries:  py.MaxRetries,
-		RetryTarget: py.RetryTarget,
-		Optional:    py.Optional,
-		Condition:   py.Condition,
-		Provider:    py.Provider,
+		Name:              py.Name,
+		Prompt:            py.Prompt,
+		Command:           py.Command,
+		MaxRetries:        py.MaxRetries,
+		RetryTarget:       py.RetryTarget,
+		Optional:          py.Optional,
+		Condition:         py.Condition,
+		Provider:          py.Provider,
+		Workdir:           py.Workdir,
+		RequiredArtifacts: py.RequiredArtifacts,
 	}
 
 	switch py.Kind {
@@ -113,72 +209,186 @@ func convertPhaseYAML(py phaseYAML) (PhaseDefinition, error) {
 		if err != nil {
 			return PhaseDefinition{}, fmt.Errorf("invalid timeout %q: %w", py.Timeout, err)
 		}
+		if d <= 0 {
+			return PhaseDefinition{}, fmt.Errorf("invalid timeout %q: must be positive", py.Timeout)
+		}
 		pd.Timeout = d
 	}
 
 	return pd, nil
 }
 
-// ValidatePhases checks phase definitions for consistency errors.
+// PhasesError lists every problem found in a set of phase definitions, each
+// naming the phase by its index and name, e.g.
+// `phases[3] "review": retry_target "lint" not found`.
+type PhasesError struct {
+	Problems []string
+}
+
+func (e *PhasesError) Error() string {
+	if len(e.Problems) == 1 {
+		return "phases: " + e.Problems[0]
+	}
+	return fmt.Sprintf("phases: %d problems:\n  %s", len(e.Problems), strings.Join(e.Problems, "\n  "))
+}
+
+// ValidatePhases checks phase definitions for consistency errors. It
+// reports all of them at once as a *PhasesError.
 func ValidatePhases(phases []PhaseDefinition) error {
+	var problems []string
+	add := func(i int, format string, args ...any) {
+		problems = append(problems, fmt.Sprintf("phases[%d] %q: ", i, phases[i].Name)+fmt.Sprintf(format, args...))
+	}
+
 	names := make(map[string]int, len(phases))
 	for i, p := range phases {
 		if _, exists := names[p.Name]; exists {
-			return fmt.Errorf("phases: duplicate phase name %q", p.Name)
+			add(i, "duplicate phase name")
+			continue
 		}
 		names[p.Name] = i
 	}
 
-	for _, p := range phases {
+	for i, p := range phases {
 		// Gates must have a Command.
-		if p.Kind == Gate {
-			if p.Command == "" {
-				return fmt.Errorf("phases: gate %q must have a command", p.Name)
-			}
+		if p.Kind == Gate && p.Command == "" {
+			add(i, "gate must have a command")
 		}
 
 		// Workers can't have RetryTarget.
 		if p.Kind == Worker && p.RetryTarget != "" {
-			return fmt.Errorf("phases: worker %q cannot have retry_target", p.Name)
+			add(i, "worker cannot have retry_target")
 		}
 
 		// RetryTarget must reference an existing phase.
 		if p.RetryTarget != "" {
 			if _, exists := names[p.RetryTarget]; !exists {
-				return fmt.Errorf("phases: %q retry_target %q not found", p.Name, p.RetryTarget)
+				add(i, "retry_target %q not found", p.RetryTarget)
+			}
+		}
+
+		// Zero inherits the pipeline default; negative is a mistake.
+		if p.Timeout < 0 {
+			add(i, "timeout %v must not be negative", p.Timeout)
+		}
+
+		// Workdir must stay inside the worktree.
+		if p.Workdir != "" && !filepath.IsLocal(p.Workdir) {
+			add(i, "workdir %q must be a relative path inside the worktree", p.Workdir)
+		}
+
+		for _, glob := range p.RequiredArtifacts {
+			if err := validateArtifact(glob); err != nil {
+				add(i, "required_artifacts: %v", err)
 			}
 		}
 
 		// Condition syntax validation.
 		if p.Condition != "" {
 			if err := validateCondition(p.Condition); err != nil {
-				return fmt.Errorf("phases: %q condition: %w", p.Name, err)
+				add(i, "condition: %v", err)
 			}
 		}
 	}
 
 	// Check for cycles in retry target graph.
-	return detectRetryCycles(phases, names)
+	if i, ok := findRetryCycle(phases, names); ok {
+		add(i, "cycle in retry targets involving %q", phases[i].Name)
+	}
+	if len(problems) > 0 {
+		return &PhasesError{Problems: problems}
+	}
+	return nil
 }
 
-// validateCondition checks that a condition string has valid syntax.
-func validateCondition(cond string) error {
-	if !strings.HasPrefix(cond, "files_match:") {
-		return fmt.Errorf("unknown condition syntax %q (expected files_match:<glob>)", cond)
+// ErrSkipAndOnly is returned by SkipSet when both a skip list and an only
+// list are given.
+var ErrSkipAndOnly = errors.New("phases: skip and only lists are mutually exclusive")
+
+// SkipSet resolves a user-facing phase selection into the list of phase names
+// to skip. skip names phases to leave out; only names the phases to keep,
+// along with any gates between them, and skips the rest. The two are
+// mutually exclusive. Every name must exist in phases. With a skip list, a
+// reviewer whose retry target would be skipped while the reviewer itself
+// still runs is rejected, since its NEEDS_WORK verdict would have nothing to
+// retry. An only list may name a reviewer alone; its NEEDS_WORK then fails
+// the run.
+func SkipSet(phases []PhaseDefinition, skip, only []string) ([]string, error) {
+	if len(skip) > 0 && len(only) > 0 {
+		return nil, ErrSkipAndOnly
+	}
+	if len(skip) == 0 && len(only) == 0 {
+		return nil, nil
 	}
-	glob := strings.TrimPrefix(cond, "files_match:")
-	if glob == "" {
-		return errors.New("files_match condition requires a glob pattern")
+
+	known := make(map[string]bool, len(phases))
+	valid := make([]string, 0, len(phases))
+	for _, p := range phases {
+		known[p.Name] = true
+		valid = append(valid, p.Name)
 	}
-	if _, err := filepath.Match(glob, "test"); err != nil {
-		return fmt.Errorf("invalid glob pattern %q: %w", glob, err)
+
+	selected := skip
+	if len(only) > 0 {
+		selected = only
 	}
-	return nil
+	picked := make(map[string]bool, len(selected))
+	for _, name := range selected {
+		if !known[name] {
+			return nil, fmt.Errorf("phases: unknown phase %q (valid: %s)", name, strings.Join(valid, ", "))
+		}
+		picked[name] = true
+	}
+	if len(only) > 0 {
+		// Gates between the first and last kept phase still check the work.
+		first, last := -1, -1
+		for i, p := range phases {
+			if picked[p.Name] {
+				if first < 0 {
+					first = i
+				}
+				last = i
+			}
+		}
+		for _, p := range phases[first:last] {
+			if p.Kind == Gate {
+				picked[p.Name] = true
+			}
+		}
+		return skippedNames(phases, picked, true), nil
+	}
+
+	for _, p := range phases {
+		if p.RetryTarget != "" && !picked[p.Name] && picked[p.RetryTarget] {
+			return nil, fmt.Errorf("phases: %q retries %q, which is skipped; skip %q as well or keep %q",
+				p.Name, p.RetryTarget, p.Name, p.RetryTarget)
+		}
+	}
+	return skippedNames(phases, picked, false), nil
 }
 
-// detectRetryCycles checks for cycles in the retry target graph.
-func detectRetryCycles(phases []PhaseDefinition, names map[string]int) error {
+// skippedNames returns, in pipeline order, the phases not picked when keep
+// is set and the picked ones otherwise.
+func skippedNames(phases []PhaseDefinition, picked map[string]bool, keep bool) []string {
+	var names []string
 	for _, p := range phases {
+		if picked[p.Name] != keep {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// validateCondition checks that a condition string has valid syntax.
+func validateCondition(cond string) error {
+	_, err := parseCondition(cond)
+	return err
+}
+
+// findRetryCycle returns the index of the first phase whose retry targets
+// lead back to it.
+func findRetryCycle(phases []PhaseDefinition, names map[string]int) (int, bool) {
+	for i, p := range phases {
 		if p.RetryTarget == "" {
 			continue
 		}
@@ -186,7 +396,7 @@ func detectRetryCycles(phases []PhaseDefinition, names map[string]int) error {
 		current := p.RetryTarget
 		for current != "" {
 			if visited[current] {
-				return fmt.Errorf("phases: cycle in retry targets involving %q", p.Name)
+				return i, true
 			}
 			visited[current] = true
 			idx, ok := names[current]
@@ -196,5 +406,5 @@ func detectRetryCycles(phases []PhaseDefinition, names map[string]int) error {
 			current = phases[idx].RetryTarget
 		}
 	}
-	return nil
+	return 0, false
 }
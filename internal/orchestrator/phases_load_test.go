@@ -1,10 +1,13 @@
 package orchestrator
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestPresetPhases(t *testing.T) {
@@ -132,6 +135,39 @@ phases:
 	}
 }
 
+func TestParsePhasesYAML_WithWorkdir(t *testing.T) {
+	yaml := `
+phases:
+  - name: test
+    kind: gate
+    command: make test
+    workdir: services/auth
+`
+	phases, err := ParsePhasesYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if phases[0].Workdir != "services/auth" {
+		t.Errorf("Workdir = %q, want %q", phases[0].Workdir, "services/auth")
+	}
+}
+
+func TestParsePhasesYAML_WithRequiredArtifacts(t *testing.T) {
+	yaml := `
+phases:
+  - name: design
+    kind: worker
+    required_artifacts: [docs/design.md, "**/*_test.go"]
+`
+	phases, err := ParsePhasesYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"docs/design.md", "**/*_test.go"}; !slices.Equal(phases[0].RequiredArtifacts, want) {
+		t.Errorf("RequiredArtifacts = %q, want %q", phases[0].RequiredArtifacts, want)
+	}
+}
+
 func TestParsePhasesYAML_DefaultKind(t *testing.T) {
 	// Given YAML without kind (defaults to worker)
 	yaml := `
@@ -193,6 +229,26 @@ func TestParsePhasesYAML_Errors(t *testing.T) {
 			yaml:    "phases:\n  - name: x\n    timeout: notaduration",
 			wantErr: "invalid timeout",
 		},
+		{
+			name:    "invalid timeout names the phase",
+			yaml:    "phases:\n  - name: execute\n  - name: lint\n    kind: gate\n    command: make lint\n    timeout: soon",
+			wantErr: `phases[1] "lint": invalid timeout "soon"`,
+		},
+		{
+			name:    "zero timeout",
+			yaml:    "phases:\n  - name: x\n    timeout: 0s",
+			wantErr: `invalid timeout "0s": must be positive`,
+		},
+		{
+			name:    "negative timeout",
+			yaml:    "phases:\n  - name: x\n    timeout: -5m",
+			wantErr: "must be positive",
+		},
+		{
+			name:    "workdir escapes worktree",
+			yaml:    "phases:\n  - name: x\n    workdir: ../other",
+			wantErr: "must be a relative path inside the worktree",
+		},
 		{
 			name:    "unknown field",
 			yaml:    "phases:\n  - name: x\n    bogus: true",
@@ -222,6 +278,10 @@ func TestValidatePhases_Condition(t *testing.T) {
 		{name: "files_match glob", condition: "files_match:*.go"},
 		{name: "unknown prefix", condition: "env_match:FOO", wantErr: true},
 		{name: "empty glob", condition: "files_match:", wantErr: true},
+		{name: "diff_match glob", condition: "diff_match:docs/*.md"},
+		{name: "env", condition: "env:RUN_DOCS"},
+		{name: "nested combinators", condition: "all:(not:env:CI, any:(diff_match:*.md, files_match:docs))"},
+		{name: "invalid nested glob", condition: "any:(files_match:*.go, diff_match:[)", wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -234,6 +294,48 @@ func TestValidatePhases_Condition(t *testing.T) {
 	}
 }
 
+func TestValidatePhases_NegativeTimeout(t *testing.T) {
+	// Given a phase whose override left a negative timeout
+	phases := []PhaseDefinition{{Name: "execute", Kind: Worker, Timeout: -time.Minute}}
+
+	// When validated
+	err := ValidatePhases(phases)
+
+	// Then the error names the phase
+	if err == nil || !strings.Contains(err.Error(), `phases[0] "execute": timeout -1m0s must not be negative`) {
+		t.Errorf("ValidatePhases() error = %v, want negative timeout error", err)
+	}
+}
+
+func TestValidatePhases_ReportsEveryProblem(t *testing.T) {
+	// Given phases with four unrelated problems
+	phases := []PhaseDefinition{
+		{Name: "execute", Kind: Worker},
+		{Name: "lint", Kind: Gate},
+		{Name: "review", Kind: Reviewer, RetryTarget: "exec"},
+		{Name: "deploy", Kind: Worker, Workdir: "../out"},
+		{Name: "design", Kind: Worker, RequiredArtifacts: []string{"docs/*.md", "../notes.md"}},
+	}
+
+	// When validated
+	err := ValidatePhases(phases)
+
+	// Then all of them are reported, each naming its phase
+	var pe *PhasesError
+	if !errors.As(err, &pe) {
+		t.Fatalf("ValidatePhases() error = %v, want *PhasesError", err)
+	}
+	want := []string{
+		`phases[1] "lint": gate must have a command`,
+		`phases[2] "review": retry_target "exec" not found`,
+		`phases[3] "deploy": workdir "../out" must be a relative path inside the worktree`,
+		`phases[4] "design": required_artifacts: glob "../notes.md" must be relative to the worktree`,
+	}
+	if !slices.Equal(pe.Problems, want) {
+		t.Errorf("problems = %q, want %q", pe.Problems, want)
+	}
+}
+
 func TestValidatePhases_RetryCycle(t *testing.T) {
 	// Given phases with a cycle: a retries b, b retries a
 	phases := []PhaseDefinition{
@@ -249,6 +351,108 @@ func TestValidatePhases_RetryCycle(t *testing.T) {
 	}
 }
 
+func TestSkipSet(t *testing.T) {
+	phases := DefaultPhases()
+	tests := []struct {
+		name    string
+		skip    []string
+		only    []string
+		want    []string
+		wantErr string
+	}{
+		{name: "nothing selected"},
+		{
+			name: "skip list",
+			skip: []string{"test-writer", "test-review"},
+			want: []string{"test-writer", "test-review"},
+		},
+		{
+			name: "only list skips the rest",
+			only: []string{"execute", "sign-off"},
+			want: []string{"test-writer", "test-review", "execute-review", "merge"},
+		},
+		{
+			name:    "both lists",
+			skip:    []string{"execute"},
+			only:    []string{"sign-off"},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "typo lists valid names",
+			skip:    []string{"tset-writer"},
+			wantErr: `unknown phase "tset-writer" (valid: test-writer, test-review, execute, execute-review, sign-off, merge)`,
+		},
+		{
+			name:    "reviewer of skipped worker",
+			skip:    []string{"test-writer"},
+			wantErr: `"test-review" retries "test-writer", which is skipped`,
+		},
+		{
+			name: "only reviewer without its target",
+			only: []string{"sign-off"},
+			want: []string{"test-writer", "test-review", "execute", "execute-review", "merge"},
+		},
+		{
+			name:    "typo in only list",
+			only:    []string{"sign_off"},
+			wantErr: `unknown phase "sign_off"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given the default phases and a selection
+			// When the selection is resolved
+			got, err := SkipSet(phases, tt.skip, tt.only)
+
+			// Then the skip list or error matches
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SkipSet() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SkipSet() error = %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SkipSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipSet_OnlyKeepsGatesBetween(t *testing.T) {
+	phases := []PhaseDefinition{
+		{Name: "lint", Kind: Gate, Command: "make lint"},
+		{Name: "execute", Kind: Worker},
+		{Name: "test", Kind: Gate, Command: "make test"},
+		{Name: "review", Kind: Reviewer, RetryTarget: "execute"},
+		{Name: "vet", Kind: Gate, Command: "go vet ./..."},
+	}
+	tests := []struct {
+		only []string
+		want []string
+	}{
+		{only: []string{"execute", "review"}, want: []string{"lint", "vet"}},
+		{only: []string{"review"}, want: []string{"lint", "execute", "test", "vet"}},
+		{only: []string{"lint", "vet"}, want: []string{"execute", "review"}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.only, ","), func(t *testing.T) {
+			// When only some phases are kept
+			got, err := SkipSet(phases, nil, tt.only)
+
+			// Then gates between the first and last kept phase run too
+			if err != nil {
+				t.Fatalf("SkipSet() error = %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SkipSet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLoadPhasesFile(t *testing.T) {
 	// Given a phases YAML file on disk
 	dir := t.TempDir()
@@ -295,6 +499,89 @@ func TestLoadPhases_FileNotFound(t *testing.T) {
 	}
 }
 
+func TestLoadPhases_Overrides(t *testing.T) {
+	five, empty, kiro, missing := 5, "", "kiro", "no-such-phase"
+	tenMin := 10 * time.Minute
+
+	// Given the default preset and layered overrides
+	phases, err := LoadPhases("default",
+		map[string]PhaseOverride{
+			"test-review": {MaxRetries: &five},
+			"execute":     {Timeout: &tenMin},
+		},
+		map[string]PhaseOverride{
+			"execute": {Provider: &kiro},
+		},
+	)
+	if err != nil {
+		t.Fatalf("LoadPhases: %v", err)
+	}
+
+	// Then only the overridden fields change
+	byName := make(map[string]PhaseDefinition, len(phases))
+	for _, p := range phases {
+		byName[p.Name] = p
+	}
+	if got := byName["test-review"]; got.MaxRetries != 5 || got.RetryTarget != "test-writer" || got.Kind != Reviewer {
+		t.Errorf("test-review = %+v, want max_retries 5 with preset kind and retry target", got)
+	}
+	if got := byName["execute"]; got.Timeout != tenMin || got.Provider != kiro || got.MaxRetries != 3 {
+		t.Errorf("execute = %+v, want both override layers applied", got)
+	}
+	if len(phases) != 6 {
+		t.Errorf("len(phases) = %d, want 6", len(phases))
+	}
+
+	// And the preset itself is untouched
+	if got := DefaultPhases()[1].MaxRetries; got != 3 {
+		t.Errorf("DefaultPhases()[1].MaxRetries = %d, want 3", got)
+	}
+
+	tests := []struct {
+		name      string
+		preset    string // Defaults to "default".
+		overrides map[string]PhaseOverride
+		wantErr   string
+	}{
+		{
+			name:      "unknown phase",
+			overrides: map[string]PhaseOverride{"lint": {Provider: &kiro}},
+			wantErr:   `unknown phase "lint"`,
+		},
+		{
+			name:      "retry target not in pipeline",
+			overrides: map[string]PhaseOverride{"sign-off": {RetryTarget: &missing}},
+			wantErr:   "not found",
+		},
+		{
+			name:      "gate command cleared",
+			preset:    "thorough",
+			overrides: map[string]PhaseOverride{"lint": {Command: &empty}},
+			wantErr:   "must have a command",
+		},
+		{
+			name:      "worker gains retry target",
+			overrides: map[string]PhaseOverride{"execute": {RetryTarget: &missing}},
+			wantErr:   "cannot have retry_target",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When an override produces an invalid pipeline
+			preset := tt.preset
+			if preset == "" {
+				preset = "default"
+			}
+			_, err := LoadPhases(preset, tt.overrides)
+
+			// Then the merged list is rejected
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadPhases() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestPromptName(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -183,7 +184,7 @@ func TestStatusCallback_Invocation(t *testing.T) {
 	cb(want)
 
 	// Then it receives the StatusUpdate
-	if received != want {
+	if !reflect.DeepEqual(received, want) {
 		t.Errorf("callback received %+v, want %+v", received, want)
 	}
 }
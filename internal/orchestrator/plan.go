@@ -0,0 +1,106 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smileynet/capsule/internal/fsutil"
+	"github.com/smileynet/capsule/internal/prompt"
+)
+
+// PhasePlan describes how a phase would run, for dry runs.
+type PhasePlan struct {
+	Phase       PhaseDefinition
+	Provider    string        // Provider name; "" for gates.
+	MaxAttempts int           // Effective attempts for a reviewer's retry loop.
+	Timeout     time.Duration // Effective timeout (0 = provider default).
+	Workdir     string        // Worktree-relative directory the phase runs in ("" = root).
+	Run         bool          // False when the phase would be skipped.
+	SkipReason  string        // Why the phase would be skipped.
+	PromptBytes int           // Length of the composed prompt; 0 for gates.
+	PromptFrom  string        // Where the prompt was read from when the loader reports it (see PromptLocator).
+	Err         error         // The prompt, provider, or condition would fail the run.
+}
+
+// PlanPipeline resolves what RunPipeline would do for input without creating
+// a worktree or worklog, calling a provider, or running a gate: bead label
+// overrides are applied, every prompt is composed, and conditions are
+// evaluated against the bead's worktree if it exists, otherwise the current
+// directory. Problems with a phase are reported in its PhasePlan.Err.
+func (o *Orchestrator) PlanPipeline(ctx context.Context, input PipelineInput) ([]PhasePlan, error) {
+	if o.promptLoader == nil {
+		return nil, errors.New("orchestrator: promptLoader is required")
+	}
+	o = o.forBead(input)
+
+	baseBranch := input.BaseBranch
+	if baseBranch == "" {
+		baseBranch = o.baseBranch
+	}
+	var dir string
+	if o.worktreeMgr != nil {
+		if path := o.worktreeMgr.Path(input.BeadID); fsutil.IsDir(path) {
+			dir = path
+		}
+	}
+	requested := make(map[string]bool, len(input.SkipPhases))
+	for _, name := range input.SkipPhases {
+		requested[name] = true
+	}
+
+	pCtx := prompt.Context{
+		BeadID:         input.BeadID,
+		Title:          input.Title,
+		Description:    input.Description,
+		SiblingContext: input.SiblingContext,
+		ContextFiles:   o.loadContextFiles(input.BeadID, dir),
+	}
+	env := o.conditionEnv(input.BeadID, baseBranch, dir)
+
+	plans := make([]PhasePlan, 0, len(o.phases))
+	for _, phase := range o.phases {
+		if err := ctx.Err(); err != nil {
+			return plans, err
+		}
+		plan := PhasePlan{
+			Phase:       phase,
+			MaxAttempts: o.ResolveRetryStrategy(phase).MaxAttempts,
+			Timeout:     o.timeoutFor(phase),
+			Workdir:     o.phaseWorkdir(phase),
+			Run:         true,
+		}
+		switch met, err := evaluateCondition(phase.Condition, env); {
+		case requested[phase.Name]:
+			plan.Run, plan.SkipReason = false, "skipped by request"
+		case err != nil:
+			plan.Err = err
+		case !met:
+			plan.Run, plan.SkipReason = false, "condition not met: "+phase.Condition
+		}
+		if phase.Kind != Gate {
+			plan.Provider, plan.PromptBytes, plan.Err = o.planPrompt(phase, pCtx, plan.Err)
+			if l, ok := o.promptLoader.(PromptLocator); ok {
+				plan.PromptFrom, _ = l.Source(phase.PromptName())
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// planPrompt resolves a provider phase's provider and composes its prompt,
+// keeping an earlier error if there was one.
+func (o *Orchestrator) planPrompt(phase PhaseDefinition, pCtx prompt.Context, prev error) (string, int, error) {
+	var name string
+	p, err := o.resolveProvider(phase)
+	if err == nil && p != nil {
+		name = p.Name()
+	}
+	composed, cerr := o.promptLoader.Compose(phase.PromptName(), pCtx)
+	if cerr != nil {
+		cerr = fmt.Errorf("composing prompt for %s: %w", phase.Name, cerr)
+	}
+	return name, len(composed), errors.Join(prev, err, cerr)
+}
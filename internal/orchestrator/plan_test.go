@@ -0,0 +1,146 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/prompt"
+)
+
+func TestPlanPipeline(t *testing.T) {
+	// Given a worktree with a Go file, and a pipeline mixing workers,
+	// reviewers, gates, conditions, and a requested skip
+	wt := t.TempDir()
+	if err := os.WriteFile(filepath.Join(wt, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := &sequenceProvider{}
+	wtMgr := &mockWorktreeMgr{path: wt}
+	wlMgr := &mockWorklogMgr{}
+	o := New(p,
+		WithPromptLoader(&mockPromptLoader{composeFunc: func(name string, ctx prompt.Context) (string, error) {
+			if name == "missing" {
+				return "", errors.New("template not found")
+			}
+			return strings.Repeat("x", 10) + ctx.BeadID, nil
+		}}),
+		WithWorktreeManager(wtMgr),
+		WithWorklogManager(wlMgr),
+		WithGateRunner(&mockGateRunner{}),
+		WithRetryDefaults(RetryStrategy{MaxAttempts: 3}),
+		WithPhaseTimeout(5*time.Minute),
+		WithPhases([]PhaseDefinition{
+			{Name: "execute", Kind: Worker},
+			{Name: "execute-review", Kind: Reviewer, RetryTarget: "execute"},
+			{Name: "lint", Kind: Gate, Command: "make lint", Condition: "files_match:*.go", Timeout: time.Minute},
+			{Name: "docs", Kind: Worker, Condition: "files_match:*.md"},
+			{Name: "broken", Kind: Worker, Prompt: "missing"},
+			{Name: "sign-off", Kind: Reviewer, RetryTarget: "execute", MaxRetries: 2},
+		}),
+	)
+
+	// When the pipeline is planned
+	plans, err := o.PlanPipeline(context.Background(), PipelineInput{BeadID: "cap-1", SkipPhases: []string{"sign-off"}})
+	if err != nil {
+		t.Fatalf("PlanPipeline() error = %v", err)
+	}
+
+	// Then every phase is described and nothing ran or was created
+	want := []struct {
+		name        string
+		run         bool
+		skipReason  string
+		attempts    int
+		timeout     time.Duration
+		promptBytes int
+		provider    string
+		wantErr     bool
+	}{
+		{name: "execute", run: true, attempts: 3, timeout: 5 * time.Minute, promptBytes: 15, provider: "mock"},
+		{name: "execute-review", run: true, attempts: 3, timeout: 5 * time.Minute, promptBytes: 15, provider: "mock"},
+		{name: "lint", run: true, attempts: 3, timeout: time.Minute},
+		{name: "docs", skipReason: "condition not met: files_match:*.md", attempts: 3, timeout: 5 * time.Minute, promptBytes: 15, provider: "mock"},
+		{name: "broken", run: true, attempts: 3, timeout: 5 * time.Minute, provider: "mock", wantErr: true},
+		{name: "sign-off", skipReason: "skipped by request", attempts: 2, timeout: 5 * time.Minute, promptBytes: 15, provider: "mock"},
+	}
+	if len(plans) != len(want) {
+		t.Fatalf("plans = %d, want %d", len(plans), len(want))
+	}
+	for i, w := range want {
+		got := plans[i]
+		if got.Phase.Name != w.name || got.Run != w.run || got.SkipReason != w.skipReason ||
+			got.MaxAttempts != w.attempts || got.Timeout != w.timeout ||
+			got.PromptBytes != w.promptBytes || got.Provider != w.provider || (got.Err != nil) != w.wantErr {
+			t.Errorf("plan[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+	if len(p.calls) != 0 || len(wtMgr.created) != 0 || wlMgr.created {
+		t.Errorf("dry run had side effects: %d provider calls, worktrees %v, worklog %v", len(p.calls), wtMgr.created, wlMgr.created)
+	}
+}
+
+func TestPlanPipeline_WithoutWorktreeUsesCurrentDir(t *testing.T) {
+	// Given no worktree for the bead yet
+	o := New(&sequenceProvider{},
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{path: filepath.Join(t.TempDir(), "missing")}),
+		WithPhases([]PhaseDefinition{
+			{Name: "go-only", Kind: Worker, Condition: "files_match:*.go"},
+		}),
+	)
+
+	// When the pipeline is planned from this package's directory
+	plans, err := o.PlanPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+	// Then conditions see the current directory's files
+	if err != nil || len(plans) != 1 {
+		t.Fatalf("PlanPipeline() = %v, %v", plans, err)
+	}
+	if !plans[0].Run {
+		t.Errorf("go-only skipped (%s), want it to run", plans[0].SkipReason)
+	}
+}
+
+func TestPlanPipeline_RecordsPromptSource(t *testing.T) {
+	// Given a layered prompt loader with a project override for execute only
+	project := t.TempDir()
+	defaults := t.TempDir()
+	for dir, names := range map[string][]string{project: {"execute"}, defaults: {"execute", "execute-review"}} {
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte("prompt for "+name), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	loader := prompt.NewLayeredLoader(
+		prompt.Source{Name: ".capsule/prompts", FS: os.DirFS(project)},
+		prompt.Source{Name: "embedded", FS: os.DirFS(defaults)},
+	)
+	o := New(&sequenceProvider{},
+		WithPromptLoader(loader),
+		WithPhases([]PhaseDefinition{
+			{Name: "execute", Kind: Worker},
+			{Name: "execute-review", Kind: Reviewer, RetryTarget: "execute"},
+			{Name: "lint", Kind: Gate, Command: "make lint"},
+		}),
+	)
+
+	// When the pipeline is planned
+	plans, err := o.PlanPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+	if err != nil {
+		t.Fatalf("PlanPipeline() error = %v", err)
+	}
+
+	// Then each provider phase names where its prompt came from
+	want := []string{".capsule/prompts", "embedded", ""}
+	for i, pl := range plans {
+		if pl.PromptFrom != want[i] {
+			t.Errorf("%s: PromptFrom = %q, want %q", pl.Phase.Name, pl.PromptFrom, want[i])
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package orchestrator
+
+import (
+	"os"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// resumePlan describes what a saved checkpoint means for a new run.
+type resumePlan struct {
+	carried  []PhaseResult          // Checkpoint results, kept in later checkpoints.
+	done     map[string]PhaseResult // Last PASS or SKIP result per phase; these phases are skipped.
+	feedback map[string]string      // Phase name → feedback from the failure being retried.
+}
+
+// loadResumePlan reads the bead's checkpoint, if any. Phases that passed or
+// were skipped are marked done. When the checkpoint ends in a failure, its
+// feedback goes to the phase that will fix it: the retry target of a
+// NEEDS_WORK reviewer or a failed gate (which then runs again), or the
+// failed phase itself.
+// A checkpoint only applies when the run continues in the worktree it
+// describes, so it is ignored when a new worktree will be created. Load
+// errors are ignored and yield an empty plan.
+func (o *Orchestrator) loadResumePlan(beadID string, reuse bool) resumePlan {
+	plan := resumePlan{done: make(map[string]PhaseResult), feedback: make(map[string]string)}
+	if o.checkpointStore == nil || (o.worktreeMgr != nil && !reuse) {
+		return plan
+	}
+	cp, found, err := o.checkpointStore.LoadCheckpoint(beadID)
+	if err != nil || !found {
+		return plan
+	}
+	return newResumePlan(cp, o.phases)
+}
+
+// newResumePlan builds the plan for continuing from cp with phases.
+func newResumePlan(cp PipelineCheckpoint, phases []PhaseDefinition) resumePlan {
+	plan := resumePlan{done: make(map[string]PhaseResult), feedback: make(map[string]string)}
+	if len(cp.PhaseResults) == 0 {
+		return plan
+	}
+	plan.carried = cp.PhaseResults
+	for _, pr := range cp.PhaseResults {
+		if pr.Signal.Status == provider.StatusPass || pr.Signal.Status == provider.StatusSkip {
+			plan.done[pr.PhaseName] = pr
+		}
+	}
+
+	last := cp.PhaseResults[len(cp.PhaseResults)-1]
+	if last.Signal.Status != provider.StatusNeedsWork && last.Signal.Status != provider.StatusError {
+		return plan
+	}
+	if last.Signal.Feedback == "" {
+		return plan
+	}
+	fixer := last.PhaseName
+	for _, phase := range phases {
+		if phase.Name == last.PhaseName && retriesTarget(phase, last.Signal) {
+			fixer = phase.RetryTarget
+			delete(plan.done, fixer)
+			break
+		}
+	}
+	plan.feedback[fixer] = last.Signal.Feedback
+	return plan
+}
+
+// ResumedPhases returns the checkpoint results of the phases that a run
+// resumed from cp skips because they already passed or were skipped, in
+// pipeline order.
+func ResumedPhases(cp PipelineCheckpoint, phases []PhaseDefinition) []PhaseResult {
+	plan := newResumePlan(cp, phases)
+	var skipped []PhaseResult
+	for _, phase := range phases {
+		if pr, ok := plan.done[phase.Name]; ok {
+			skipped = append(skipped, pr)
+		}
+	}
+	return skipped
+}
+
+// WorktreeAttacher re-creates a worktree on a bead's surviving capsule
+// branch after its directory was removed, as by an abort or a crash. A
+// WorktreeManager that implements it lets resumed runs continue there.
+type WorktreeAttacher interface {
+	BranchExists(id string) bool
+	Attach(id string) error
+}
+
+// reuseWorktree reports whether a resumed run can continue in the bead's
+// existing worktree instead of creating a new one. attach is set when the
+// directory is gone and must be re-attached to the surviving branch first.
+func (o *Orchestrator) reuseWorktree(input PipelineInput) (reuse, attach bool) {
+	if !input.Resume || o.worktreeMgr == nil {
+		return false, false
+	}
+	info, err := os.Stat(o.worktreeMgr.Path(input.BeadID))
+	if err == nil && info.IsDir() {
+		return true, false
+	}
+	if a, ok := o.worktreeMgr.(WorktreeAttacher); ok && a.BranchExists(input.BeadID) {
+		return true, true
+	}
+	return false, false
+}
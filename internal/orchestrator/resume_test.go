@@ -0,0 +1,329 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/prompt"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+func TestRunPipeline_ResumeFeedsFailureToRetryPhase(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkpoint []PhaseResult
+		wantPhases []string
+		wantFed    string // Phase whose prompt context carries the feedback.
+	}{
+		{
+			name: "reviewer NEEDS_WORK reruns its retry target",
+			checkpoint: []PhaseResult{
+				{PhaseName: "worker", Signal: provider.Signal{Status: provider.StatusPass}},
+				{PhaseName: "reviewer", Signal: provider.Signal{Status: provider.StatusNeedsWork, Feedback: "add tests"}},
+			},
+			wantPhases: []string{"worker", "reviewer"},
+			wantFed:    "worker",
+		},
+		{
+			name: "worker ERROR reruns the worker",
+			checkpoint: []PhaseResult{
+				{PhaseName: "worker", Signal: provider.Signal{Status: provider.StatusError, Feedback: "add tests"}},
+			},
+			wantPhases: []string{"worker", "reviewer"},
+			wantFed:    "worker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a checkpoint that ends in a failure with feedback
+			cs := &mockCheckpointStore{loadFound: true, loadCP: PipelineCheckpoint{BeadID: "cap-1", PhaseResults: tt.checkpoint}}
+			feedback := make(map[string]string)
+			var ran []string
+			pl := &mockPromptLoader{composeFunc: func(phase string, ctx prompt.Context) (string, error) {
+				ran = append(ran, phase)
+				feedback[phase] = ctx.Feedback
+				return "prompt:" + phase, nil
+			}}
+			o := New(&sequenceProvider{responses: nPassResponses(2)},
+				WithPromptLoader(pl),
+				WithPhases(twoPhases()),
+				WithCheckpointStore(cs),
+			)
+
+			// When the pipeline resumes
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1", Resume: true})
+			if err != nil {
+				t.Fatalf("RunPipeline() error = %v", err)
+			}
+
+			// Then the fixing phase reruns with the failure's feedback
+			if len(ran) != len(tt.wantPhases) {
+				t.Fatalf("phases run = %v, want %v", ran, tt.wantPhases)
+			}
+			for i, name := range tt.wantPhases {
+				if ran[i] != name {
+					t.Errorf("phase[%d] = %q, want %q", i, ran[i], name)
+				}
+			}
+			if got := feedback[tt.wantFed]; got != "add tests" {
+				t.Errorf("%s feedback = %q, want %q", tt.wantFed, got, "add tests")
+			}
+			// And phases without a failure to fix get no feedback
+			for phase, fb := range feedback {
+				if phase != tt.wantFed && fb != "" {
+					t.Errorf("%s feedback = %q, want empty", phase, fb)
+				}
+			}
+		})
+	}
+}
+
+func TestNewResumePlan_FailedGateRerunsRetryTarget(t *testing.T) {
+	// Given a checkpoint ending in a failed gate with a retry target
+	cp := PipelineCheckpoint{PhaseResults: []PhaseResult{
+		{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusPass}},
+		{PhaseName: "test", Signal: provider.Signal{Status: provider.StatusError, Feedback: "--- FAIL: TestAdd"}},
+	}}
+	phases := []PhaseDefinition{
+		{Name: "execute", Kind: Worker},
+		{Name: "test", Kind: Gate, Command: "go test ./...", RetryTarget: "execute"},
+	}
+
+	// When the resume plan is built
+	plan := newResumePlan(cp, phases)
+
+	// Then execute reruns with the gate's output
+	if _, done := plan.done["execute"]; done || plan.feedback["execute"] != "--- FAIL: TestAdd" {
+		t.Errorf("plan = %+v, want execute rerun with the gate output", plan)
+	}
+}
+
+func TestRunPipeline_ResumeReusesWorktree(t *testing.T) {
+	// Given an existing worktree and a checkpoint for the bead
+	wt := &mockWorktreeMgr{path: t.TempDir()}
+	wl := &mockWorklogMgr{}
+	cs := &mockCheckpointStore{loadFound: true, loadCP: PipelineCheckpoint{
+		BeadID:       "cap-1",
+		PhaseResults: []PhaseResult{{PhaseName: "phase-a", Signal: provider.Signal{Status: provider.StatusPass}}},
+	}}
+	o := New(&sequenceProvider{responses: nPassResponses(2)},
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(wt),
+		WithWorklogManager(wl),
+		WithPhases(threePhases()),
+		WithCheckpointStore(cs),
+	)
+
+	// When the pipeline resumes
+	_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1", Resume: true})
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then neither the worktree nor the worklog is recreated
+	if len(wt.created) != 0 {
+		t.Errorf("worktree created %d times, want 0", len(wt.created))
+	}
+	if wl.created {
+		t.Error("worklog recreated, want existing worklog kept")
+	}
+	// And checkpoints keep the results carried from the earlier run
+	first := cs.saved[0].PhaseResults
+	if len(first) != 2 || first[0].PhaseName != "phase-a" || first[1].PhaseName != "phase-b" {
+		t.Errorf("first checkpoint = %+v, want phase-a then phase-b", first)
+	}
+}
+
+func TestRunPipeline_ResumeWithoutWorktreeStartsOver(t *testing.T) {
+	// Given a resume request whose worktree has been removed
+	wt := &mockWorktreeMgr{path: t.TempDir() + "/gone"}
+	sp := &sequenceProvider{responses: nPassResponses(3)}
+	cs := &mockCheckpointStore{loadFound: true, loadCP: PipelineCheckpoint{
+		BeadID:       "cap-1",
+		PhaseResults: []PhaseResult{{PhaseName: "phase-a", Signal: provider.Signal{Status: provider.StatusPass}}},
+	}}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(wt),
+		WithPhases(threePhases()),
+		WithCheckpointStore(cs),
+	)
+
+	// When the pipeline resumes
+	_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1", Resume: true})
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then a fresh worktree is created
+	if len(wt.created) != 1 {
+		t.Errorf("worktree created %d times, want 1", len(wt.created))
+	}
+	// And the checkpoint is ignored, since its work is gone
+	if len(sp.calls) != 3 {
+		t.Errorf("provider called %d times, want 3", len(sp.calls))
+	}
+}
+
+// attachingWorktreeMgr is a mockWorktreeMgr whose capsule branch survived
+// its directory, so resumed runs re-attach it.
+type attachingWorktreeMgr struct {
+	mockWorktreeMgr
+	attached []string
+}
+
+func (m *attachingWorktreeMgr) BranchExists(string) bool { return true }
+
+func (m *attachingWorktreeMgr) Attach(id string) error {
+	m.attached = append(m.attached, id)
+	return os.MkdirAll(m.path, 0o755)
+}
+
+func TestRunPipeline_ResumeReattachesSurvivingBranch(t *testing.T) {
+	// Given a checkpoint and a worktree whose directory is gone but whose branch remains
+	wt := &attachingWorktreeMgr{mockWorktreeMgr: mockWorktreeMgr{path: t.TempDir() + "/gone"}}
+	wl := &mockWorklogMgr{}
+	sp := &sequenceProvider{responses: nPassResponses(2)}
+	cs := &mockCheckpointStore{loadFound: true, loadCP: PipelineCheckpoint{
+		BeadID:       "cap-1",
+		PhaseResults: []PhaseResult{{PhaseName: "phase-a", Signal: provider.Signal{Status: provider.StatusPass}}},
+	}}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(wt),
+		WithWorklogManager(wl),
+		WithPhases(threePhases()),
+		WithCheckpointStore(cs),
+	)
+
+	// When the pipeline resumes
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1", Resume: true}); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then the branch is re-attached instead of a new worktree being created
+	if len(wt.attached) != 1 || len(wt.created) != 0 {
+		t.Errorf("attached %v, created %v; want one attach and no create", wt.attached, wt.created)
+	}
+	// And the lost worklog is recreated while the checkpoint still applies
+	if !wl.created {
+		t.Error("worklog not recreated in the re-attached worktree")
+	}
+	if len(sp.calls) != 2 {
+		t.Errorf("provider called %d times, want 2 (phase-a done in checkpoint)", len(sp.calls))
+	}
+}
+
+func TestRunPipeline_ResumeReportsDonePhases(t *testing.T) {
+	// Given a checkpoint where phase-a passed and phase-b was skipped
+	cs := &mockCheckpointStore{loadFound: true, loadCP: PipelineCheckpoint{
+		BeadID: "cap-1",
+		PhaseResults: []PhaseResult{
+			{PhaseName: "phase-a", Attempt: 2, Signal: provider.Signal{Status: provider.StatusPass, Summary: "built"}},
+			{PhaseName: "phase-b", Attempt: 1, Signal: provider.Signal{Status: provider.StatusSkip}},
+		},
+	}}
+	var updates []StatusUpdate
+	o := New(&sequenceProvider{responses: nPassResponses(1)},
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhases(threePhases()),
+		WithCheckpointStore(cs),
+		WithStatusCallback(func(su StatusUpdate) { updates = append(updates, su) }),
+	)
+
+	// When the pipeline resumes
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1", Resume: true}); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then the done phases are reported before anything runs
+	if len(updates) < 2 {
+		t.Fatalf("updates = %d, want at least 2", len(updates))
+	}
+	if u := updates[0]; u.Phase != "phase-a" || u.Status != PhasePassed || u.Attempt != 2 || u.Signal.Summary != "built" {
+		t.Errorf("updates[0] = %+v, want phase-a passed on attempt 2", u)
+	}
+	if u := updates[1]; u.Phase != "phase-b" || u.Status != PhaseSkipped {
+		t.Errorf("updates[1] = %+v, want phase-b skipped", u)
+	}
+}
+
+func TestRunPipeline_CompletedRunRemovesCheckpoint(t *testing.T) {
+	// Given a pipeline with a checkpoint store
+	cs := &mockCheckpointStore{}
+	o := New(&sequenceProvider{responses: nPassResponses(3)},
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhases(threePhases()),
+		WithCheckpointStore(cs),
+	)
+
+	// When it completes
+	if _, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"}); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	// Then the checkpoint is removed so a later run starts fresh
+	if !cs.removed {
+		t.Error("checkpoint not removed after a completed run")
+	}
+}
+
+func TestResumedPhases(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkpoint []PhaseResult
+		want       []string
+	}{
+		{
+			name:       "empty checkpoint skips nothing",
+			checkpoint: nil,
+			want:       nil,
+		},
+		{
+			name: "passed phases are skipped in pipeline order",
+			checkpoint: []PhaseResult{
+				{PhaseName: "reviewer", Signal: provider.Signal{Status: provider.StatusSkip}},
+				{PhaseName: "worker", Signal: provider.Signal{Status: provider.StatusPass}},
+			},
+			want: []string{"worker", "reviewer"},
+		},
+		{
+			name: "retry target of a NEEDS_WORK reviewer runs again",
+			checkpoint: []PhaseResult{
+				{PhaseName: "worker", Signal: provider.Signal{Status: provider.StatusPass}},
+				{PhaseName: "reviewer", Signal: provider.Signal{Status: provider.StatusNeedsWork, Feedback: "add tests"}},
+			},
+			want: nil,
+		},
+		{
+			name: "failed phase is not skipped",
+			checkpoint: []PhaseResult{
+				{PhaseName: "worker", Signal: provider.Signal{Status: provider.StatusPass}},
+				{PhaseName: "reviewer", Signal: provider.Signal{Status: provider.StatusError}},
+			},
+			want: []string{"worker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a checkpoint for the two-phase pipeline
+			cp := PipelineCheckpoint{BeadID: "cap-1", PhaseResults: tt.checkpoint}
+
+			// When the resumed phases are listed
+			got := ResumedPhases(cp, twoPhases())
+
+			// Then they match the phases a resumed run skips
+			if len(got) != len(tt.want) {
+				t.Fatalf("ResumedPhases() = %v, want %v", got, tt.want)
+			}
+			for i, name := range tt.want {
+				if got[i].PhaseName != name {
+					t.Errorf("phase[%d] = %q, want %q", i, got[i].PhaseName, name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/worklog"
+)
+
+// SummaryWriter records a machine-readable summary of every pipeline run.
+type SummaryWriter interface {
+	WriteSummary(s worklog.RunSummary) error
+}
+
+// WithSummaryWriter records a worklog.RunSummary after every run, whether
+// it passed, failed, or paused. Write failures are logged, not returned.
+func WithSummaryWriter(w SummaryWriter) Option {
+	return func(o *Orchestrator) { o.summaryWriter = w }
+}
+
+// writeSummary records the run of input that started at start and ended
+// with results and err. Results carried from a resumed checkpoint come
+// first, so the summary covers every phase of the bead's pipeline.
+func (o *Orchestrator) writeSummary(input PipelineInput, start time.Time, results []PhaseResult, err error) {
+	if o.summaryWriter == nil {
+		return
+	}
+	all := append(append([]PhaseResult(nil), o.carried...), results...)
+	s := worklog.RunSummary{
+		BeadID:    input.BeadID,
+		Title:     input.Title,
+		StartedAt: start,
+		EndedAt:   time.Now(),
+		Status:    worklog.RunPassed,
+		Phases:    summarizePhases(all),
+		Findings:  collectFindings(all),
+	}
+	switch {
+	case errors.Is(err, ErrPipelinePaused):
+		s.Status = worklog.RunPaused
+	case err != nil:
+		s.Status = worklog.RunFailed
+		s.Error = err.Error()
+	}
+	if s.Findings == nil {
+		s.Findings = []provider.Finding{}
+	}
+	if werr := o.summaryWriter.WriteSummary(s); werr != nil {
+		o.logger.Warn("run summary write failed", "bead", input.BeadID, "error", werr)
+	}
+}
+
+// summarizePhases folds results into one entry per phase, in the order the
+// phases first ran, keeping the last attempt's status, files, summary, and
+// skip reason.
+func summarizePhases(results []PhaseResult) []worklog.PhaseSummary {
+	phases := []worklog.PhaseSummary{}
+	index := make(map[string]int)
+	for _, pr := range results {
+		i, ok := index[pr.PhaseName]
+		if !ok {
+			i = len(phases)
+			index[pr.PhaseName] = i
+			phases = append(phases, worklog.PhaseSummary{Name: pr.PhaseName})
+		}
+		p := &phases[i]
+		p.Attempts = max(p.Attempts+1, pr.Attempt)
+		p.DurationMS += pr.Duration.Milliseconds()
+		p.Status = string(pr.Signal.Status)
+		p.Summary = pr.Signal.Summary
+		p.FilesChanged = pr.Signal.FilesChanged
+		if p.FilesChanged == nil {
+			p.FilesChanged = []string{}
+		}
+		p.SkipReason = pr.SkipReason
+		p.Feedback = ""
+		if pr.Signal.Status != provider.StatusPass && pr.Signal.Status != provider.StatusSkip {
+			p.Feedback = pr.Signal.Feedback
+		}
+	}
+	return phases
+}
@@ -0,0 +1,147 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/worklog"
+)
+
+// recordingSummaryWriter captures run summaries.
+type recordingSummaryWriter struct {
+	summaries []worklog.RunSummary
+}
+
+func (r *recordingSummaryWriter) WriteSummary(s worklog.RunSummary) error {
+	r.summaries = append(r.summaries, s)
+	return nil
+}
+
+func TestRunPipeline_WritesSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  []mockResponse
+		pause      bool
+		wantStatus string
+		wantPhases []worklog.PhaseSummary
+	}{
+		{
+			name:       "passing run",
+			responses:  nPassResponses(2),
+			wantStatus: worklog.RunPassed,
+			wantPhases: []worklog.PhaseSummary{
+				{Name: "worker", Status: "PASS", Attempts: 1},
+				{Name: "reviewer", Status: "PASS", Attempts: 1},
+			},
+		},
+		{
+			name: "review retried then failed",
+			responses: []mockResponse{
+				passResponse(), needsWorkResponse("add tests"),
+				passResponse(), needsWorkResponse("still no tests"),
+			},
+			wantStatus: worklog.RunFailed,
+			wantPhases: []worklog.PhaseSummary{
+				{Name: "worker", Status: "PASS", Attempts: 2},
+				{Name: "reviewer", Status: "NEEDS_WORK", Attempts: 2, Feedback: "still no tests"},
+			},
+		},
+		{
+			name:       "paused before the first phase",
+			pause:      true,
+			wantStatus: worklog.RunPaused,
+			wantPhases: []worklog.PhaseSummary{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a worker-reviewer pipeline with a summary writer
+			sw := &recordingSummaryWriter{}
+			o := New(&sequenceProvider{responses: tt.responses},
+				WithPromptLoader(&mockPromptLoader{}),
+				WithPhases([]PhaseDefinition{
+					{Name: "worker", Kind: Worker},
+					{Name: "reviewer", Kind: Reviewer, RetryTarget: "worker", MaxRetries: 2},
+				}),
+				WithSummaryWriter(sw),
+				WithPauseRequested(func() bool { return tt.pause }),
+			)
+
+			// When the pipeline runs
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1", Title: "Add validation"})
+
+			// Then exactly one summary records the outcome of every phase
+			if len(sw.summaries) != 1 {
+				t.Fatalf("summaries = %d, want 1", len(sw.summaries))
+			}
+			s := sw.summaries[0]
+			if s.BeadID != "cap-1" || s.Title != "Add validation" || s.Status != tt.wantStatus {
+				t.Errorf("summary = %s %q %s, want cap-1 %q %s", s.BeadID, s.Title, s.Status, "Add validation", tt.wantStatus)
+			}
+			if (s.Error != "") != (tt.wantStatus == worklog.RunFailed) {
+				t.Errorf("error = %q with status %s (run error %v)", s.Error, s.Status, err)
+			}
+			if s.EndedAt.Before(s.StartedAt) {
+				t.Errorf("ended %v before started %v", s.EndedAt, s.StartedAt)
+			}
+			if len(s.Phases) != len(tt.wantPhases) {
+				t.Fatalf("phases = %+v, want %+v", s.Phases, tt.wantPhases)
+			}
+			for i, want := range tt.wantPhases {
+				got := s.Phases[i]
+				if got.Name != want.Name || got.Status != want.Status || got.Attempts != want.Attempts || got.Feedback != want.Feedback {
+					t.Errorf("phase[%d] = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizePhases_KeepsLastAttemptFiles(t *testing.T) {
+	// Given a worker that changed different files on each attempt
+	results := []PhaseResult{
+		{PhaseName: "execute", Attempt: 1, Signal: provider.Signal{Status: provider.StatusPass, FilesChanged: []string{"a.go"}}},
+		{PhaseName: "execute", Attempt: 2, Signal: provider.Signal{Status: provider.StatusPass, Summary: "added b", FilesChanged: []string{"a.go", "b.go"}}},
+		{PhaseName: "lint", Signal: provider.Signal{Status: provider.StatusSkip, Feedback: "condition not met"}, SkipReason: "condition not met: files_match:*.go"},
+	}
+
+	// When the phases are summarized
+	phases := summarizePhases(results)
+
+	// Then each phase appears once with its last files and summary, no feedback when it passed or skipped, and the skip reason
+	if len(phases) != 2 {
+		t.Fatalf("phases = %+v, want 2", phases)
+	}
+	if got := phases[0].FilesChanged; len(got) != 2 || phases[0].Attempts != 2 || phases[0].Summary != "added b" {
+		t.Errorf("execute = %+v, want 2 attempts, files a.go, b.go, and summary %q", phases[0], "added b")
+	}
+	if phases[1].Feedback != "" || phases[1].FilesChanged == nil || phases[1].Attempts != 1 {
+		t.Errorf("lint = %+v, want 1 attempt, empty files, no feedback", phases[1])
+	}
+	if phases[1].SkipReason != "condition not met: files_match:*.go" {
+		t.Errorf("lint skip reason = %q, want the condition", phases[1].SkipReason)
+	}
+}
+
+func TestWithSummaryWriter_WriteErrorDoesNotFailRun(t *testing.T) {
+	// Given a summary writer that fails
+	o := New(&sequenceProvider{responses: nPassResponses(1)},
+		WithPromptLoader(&mockPromptLoader{}),
+		WithPhases([]PhaseDefinition{{Name: "worker", Kind: Worker}}),
+		WithSummaryWriter(failingSummaryWriter{}),
+	)
+
+	// When the pipeline runs
+	out, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+	// Then the run still succeeds
+	if err != nil || !out.Completed {
+		t.Errorf("RunPipeline() = completed %v, %v; want success", out.Completed, err)
+	}
+}
+
+type failingSummaryWriter struct{}
+
+func (failingSummaryWriter) WriteSummary(worklog.RunSummary) error { return errors.New("disk full") }
@@ -0,0 +1,89 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// defaultBackoffBase is the wait before the first retry of a transient
+// provider failure; each later retry doubles it.
+const defaultBackoffBase = 2 * time.Second
+
+// WithTransientRetries retries a provider call that fails transiently (see
+// provider.IsTransient), such as on a rate limit, up to n times. The waits
+// between calls double from 2s, with jitter, up to maxBackoff; zero
+// maxBackoff leaves them uncapped. These retries repeat the same call and
+// are separate from a phase's MaxRetries, but each counts toward
+// WithMaxProviderCalls. A retry that would outlast the phase timeout is not
+// made, and cancelling the run ends the wait at once. Zero n disables them.
+func WithTransientRetries(n int, maxBackoff time.Duration) Option {
+	return func(o *Orchestrator) {
+		o.transientRetries = n
+		o.backoffMax = maxBackoff
+	}
+}
+
+// callProviderRetrying calls the provider, retrying transient failures as
+// configured by WithTransientRetries. Each wait is announced with a
+// PhaseRetrying status update.
+func (o *Orchestrator) callProviderRetrying(ctx context.Context, p Provider, prompt, workDir, beadID, phase string, attempt int) (provider.Result, error) {
+	for retry := 1; ; retry++ {
+		result, err := o.callProvider(ctx, p, prompt, workDir, beadID, phase, attempt)
+		reason := provider.TransientReason(err)
+		if reason == "" || retry > o.transientRetries || ctx.Err() != nil {
+			return result, err
+		}
+		delay := o.backoff(retry)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return result, err
+		}
+		if berr := o.takeCall(); berr != nil {
+			return result, err
+		}
+		o.logger.Warn("transient provider error; retrying",
+			"bead", beadID, "phase", phase, "attempt", attempt,
+			"retry", retry, "delay", delay, "reason", reason, "error", err)
+		o.notify(StatusUpdate{
+			BeadID: beadID, Phase: phase,
+			Status: PhaseRetrying, Attempt: attempt,
+			Message: fmt.Sprintf("retrying in %s (%s)", delay.Round(time.Second), reason),
+		})
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, fmt.Errorf("waiting to retry after %s: %w", reason, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before the given transient retry (1-based):
+// backoffBase doubled for each earlier retry, capped at backoffMax, then
+// jittered.
+func (o *Orchestrator) backoff(retry int) time.Duration {
+	d := o.backoffBase
+	for range retry - 1 {
+		if o.backoffMax > 0 && d >= o.backoffMax {
+			break
+		}
+		d *= 2
+	}
+	if o.backoffMax > 0 && d > o.backoffMax {
+		d = o.backoffMax
+	}
+	return o.jitter(d)
+}
+
+// halfJitter returns a random wait between half of d and d, so pipelines
+// that hit a rate limit together do not retry together.
+func halfJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d/2 + rand.N(d/2+1)
+}
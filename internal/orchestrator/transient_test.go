@@ -0,0 +1,192 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// rateLimited is a provider failure that provider.IsTransient accepts.
+func rateLimited() mockResponse {
+	return mockResponse{err: &provider.ProviderError{Provider: "mock", Err: errors.New("exit status 1: API Error: 429 rate_limit_error")}}
+}
+
+// newTransientOrchestrator runs one worker phase against sp with n
+// transient retries and near-instant backoff.
+func newTransientOrchestrator(sp Provider, n int, updates *[]StatusUpdate) *Orchestrator {
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{path: "/tmp/worktrees/cap-1"}),
+		WithWorklogManager(&mockWorklogMgr{}),
+		WithPhases([]PhaseDefinition{{Name: "execute", Kind: Worker, MaxRetries: 1}}),
+		WithStatusCallback(func(su StatusUpdate) { *updates = append(*updates, su) }),
+		WithTransientRetries(n, time.Minute),
+	)
+	o.backoffBase = time.Millisecond
+	o.jitter = func(d time.Duration) time.Duration { return d }
+	return o
+}
+
+func TestRunPipeline_TransientRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		retries     int
+		responses   []mockResponse
+		wantErr     bool
+		wantCalls   int
+		wantRetries int
+	}{
+		{name: "rate limit then pass", retries: 3, responses: []mockResponse{rateLimited(), rateLimited(), passResponse()}, wantCalls: 3, wantRetries: 2},
+		{name: "retries run out", retries: 1, responses: []mockResponse{rateLimited(), rateLimited()}, wantErr: true, wantCalls: 2, wantRetries: 1},
+		{name: "disabled", retries: 0, responses: []mockResponse{rateLimited()}, wantErr: true, wantCalls: 1},
+		{name: "ordinary failure is not retried", retries: 3, responses: []mockResponse{{err: errors.New("exit status 2: bad flag")}}, wantErr: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a provider that fails with the responses in order
+			sp := &sequenceProvider{responses: tt.responses}
+			var updates []StatusUpdate
+			o := newTransientOrchestrator(sp, tt.retries, &updates)
+
+			// When the pipeline runs
+			_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+			// Then transient failures are retried within the same attempt
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if len(sp.calls) != tt.wantCalls {
+				t.Errorf("provider calls = %d, want %d", len(sp.calls), tt.wantCalls)
+			}
+			var retrying []StatusUpdate
+			for _, su := range updates {
+				if su.Status == PhaseRetrying {
+					retrying = append(retrying, su)
+				}
+			}
+			if len(retrying) != tt.wantRetries {
+				t.Fatalf("retrying updates = %d, want %d", len(retrying), tt.wantRetries)
+			}
+			// And each wait is announced on attempt 1 with its reason
+			for _, su := range retrying {
+				if su.Phase != "execute" || su.Attempt != 1 || !strings.Contains(su.Message, "(rate limited)") {
+					t.Errorf("retrying update = %+v, want execute attempt 1 rate limited", su)
+				}
+			}
+		})
+	}
+}
+
+func TestRunPipeline_TransientRetryCountsTowardBudget(t *testing.T) {
+	// Given a budget of two provider calls and a provider that keeps hitting a rate limit
+	sp := &sequenceProvider{responses: []mockResponse{rateLimited(), rateLimited(), rateLimited()}}
+	var updates []StatusUpdate
+	o := newTransientOrchestrator(sp, 5, &updates)
+	o.maxCalls = 2
+
+	// When the pipeline runs
+	_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+	// Then the retries stop when the budget does
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(sp.calls) != 2 {
+		t.Errorf("provider calls = %d, want 2", len(sp.calls))
+	}
+}
+
+func TestRunPipeline_TransientBackoffInterruptedByCancel(t *testing.T) {
+	// Given a rate-limited provider and an hour-long backoff
+	sp := &sequenceProvider{responses: []mockResponse{rateLimited(), passResponse()}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var updates []StatusUpdate
+	o := newTransientOrchestrator(sp, 3, &updates)
+	o.backoffBase = time.Hour
+	o.statusCallback = func(su StatusUpdate) {
+		// When the run is cancelled while waiting to retry
+		if su.Status == PhaseRetrying {
+			cancel()
+		}
+	}
+
+	start := time.Now()
+	_, err := o.RunPipeline(ctx, PipelineInput{BeadID: "cap-1"})
+
+	// Then the wait ends at once with the cancellation and no second call
+	if time.Since(start) > 10*time.Second {
+		t.Errorf("RunPipeline took %s, want the backoff interrupted", time.Since(start))
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if len(sp.calls) != 1 {
+		t.Errorf("provider calls = %d, want 1", len(sp.calls))
+	}
+}
+
+func TestRunPipeline_TransientRetrySkippedPastPhaseTimeout(t *testing.T) {
+	// Given a phase timeout shorter than the first backoff
+	sp := &sequenceProvider{responses: []mockResponse{rateLimited(), passResponse()}}
+	var updates []StatusUpdate
+	o := newTransientOrchestrator(sp, 3, &updates)
+	o.backoffBase = time.Hour
+	o.phaseTimeout = time.Minute
+
+	// When the pipeline runs
+	_, err := o.RunPipeline(context.Background(), PipelineInput{BeadID: "cap-1"})
+
+	// Then the phase fails with the rate limit instead of outwaiting its timeout
+	if err == nil || !provider.IsTransient(err) {
+		t.Errorf("err = %v, want the transient provider error", err)
+	}
+	if len(sp.calls) != 1 {
+		t.Errorf("provider calls = %d, want 1", len(sp.calls))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name  string
+		max   time.Duration
+		retry int
+		want  time.Duration
+	}{
+		{name: "first retry", max: time.Minute, retry: 1, want: 2 * time.Second},
+		{name: "doubles", max: time.Minute, retry: 3, want: 8 * time.Second},
+		{name: "capped", max: 30 * time.Second, retry: 5, want: 30 * time.Second},
+		{name: "uncapped", retry: 6, want: 64 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given the default base and no jitter
+			o := New(nil, WithTransientRetries(10, tt.max))
+			o.jitter = func(d time.Duration) time.Duration { return d }
+
+			// When the wait before a retry is computed
+			got := o.backoff(tt.retry)
+
+			// Then it doubles from the base up to the cap
+			if got != tt.want {
+				t.Errorf("backoff(%d) = %s, want %s", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHalfJitter(t *testing.T) {
+	// When a wait is jittered many times
+	for range 100 {
+		got := halfJitter(10 * time.Second)
+
+		// Then it stays between half the wait and the whole wait
+		if got < 5*time.Second || got > 10*time.Second {
+			t.Fatalf("halfJitter(10s) = %s, want within [5s, 10s]", got)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidWorkdir indicates a phase working directory is missing or
+// resolves outside the worktree.
+var ErrInvalidWorkdir = errors.New("invalid workdir")
+
+// WithWorkdirs sets default working directories for beads by ID prefix, e.g.
+// {"auth-": "services/auth"}. The longest matching prefix wins. Directories
+// are relative to the worktree root; a bead's capsule:dir label and a phase's
+// Workdir take precedence.
+func WithWorkdirs(prefixes map[string]string) Option {
+	return func(o *Orchestrator) { o.workdirs = prefixes }
+}
+
+// prefixWorkdir returns the configured directory for the longest prefix of
+// beadID, or "" when none matches.
+func (o *Orchestrator) prefixWorkdir(beadID string) string {
+	var best, dir string
+	for prefix, d := range o.workdirs {
+		if strings.HasPrefix(beadID, prefix) && len(prefix) > len(best) {
+			best, dir = prefix, d
+		}
+	}
+	return dir
+}
+
+// phaseWorkdir returns the worktree-relative directory a phase runs in:
+// the phase's own Workdir, else the bead default, else "" for the root.
+func (o *Orchestrator) phaseWorkdir(phase PhaseDefinition) string {
+	if phase.Workdir != "" {
+		return phase.Workdir
+	}
+	return o.beadWorkdir
+}
+
+// resolveWorkdir joins rel onto wtPath and checks the result is an existing
+// directory inside the worktree, following symlinks. An empty rel returns
+// wtPath unchanged.
+func resolveWorkdir(wtPath, rel string) (string, error) {
+	if rel == "" {
+		return wtPath, nil
+	}
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%w: %q is not a relative path inside the worktree", ErrInvalidWorkdir, rel)
+	}
+	dir := filepath.Join(wtPath, rel)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %w", ErrInvalidWorkdir, rel, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%w: %q is not a directory", ErrInvalidWorkdir, rel)
+	}
+
+	root := wtPath
+	if root == "" {
+		root = "."
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("%w: resolving worktree: %w", ErrInvalidWorkdir, err)
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %w", ErrInvalidWorkdir, rel, err)
+	}
+	if r, err := filepath.Rel(realRoot, realDir); err != nil || (r != "." && !filepath.IsLocal(r)) {
+		return "", fmt.Errorf("%w: %q resolves outside the worktree", ErrInvalidWorkdir, rel)
+	}
+	return dir, nil
+}
+
+// normalizeFilesChanged rewrites paths reported by a phase that ran in the
+// worktree-relative directory rel so they are relative to the worktree root,
+// keeping worklogs and merge summaries consistent across phases. Relative
+// paths are taken as relative to rel unless they already start with it;
+// absolute paths inside wtPath are made root-relative. Paths that would
+// leave the worktree are kept as reported.
+func normalizeFilesChanged(files []string, wtPath, rel string) []string {
+	if len(files) == 0 {
+		return files
+	}
+	prefix := filepath.ToSlash(filepath.Clean(rel)) + "/"
+	out := make([]string, 0, len(files))
+	for _, f := range files {
+		var p string
+		switch {
+		case filepath.IsAbs(f):
+			r, err := filepath.Rel(wtPath, f)
+			if err != nil || !filepath.IsLocal(r) {
+				out = append(out, f)
+				continue
+			}
+			p = r
+		case rel == "" || strings.HasPrefix(filepath.ToSlash(filepath.Clean(f)), prefix):
+			p = f
+		default:
+			p = filepath.Join(rel, f)
+		}
+		p = filepath.Clean(p)
+		if !filepath.IsLocal(p) {
+			out = append(out, f)
+			continue
+		}
+		out = append(out, filepath.ToSlash(p))
+	}
+	return out
+}
@@ -0,0 +1,252 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/worklog"
+)
+
+func TestResolveWorkdir(t *testing.T) {
+	wt := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(wt, "services", "auth"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, "README.md"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(t.TempDir(), filepath.Join(wt, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		rel     string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty uses root", rel: "", want: wt},
+		{name: "subdirectory", rel: "services/auth", want: filepath.Join(wt, "services", "auth")},
+		{name: "dot-dot escape", rel: "../other", wantErr: true},
+		{name: "hidden escape", rel: "services/../../other", wantErr: true},
+		{name: "absolute path", rel: "/tmp", wantErr: true},
+		{name: "missing directory", rel: "services/billing", wantErr: true},
+		{name: "file not directory", rel: "README.md", wantErr: true},
+		{name: "symlink outside worktree", rel: "escape", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveWorkdir(wt, tt.rel)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidWorkdir) {
+					t.Fatalf("resolveWorkdir(%q) error = %v, want ErrInvalidWorkdir", tt.rel, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveWorkdir(%q) error = %v", tt.rel, err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveWorkdir(%q) = %q, want %q", tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeFilesChanged(t *testing.T) {
+	wt := "/repo/.capsule/worktrees/cap-1"
+	tests := []struct {
+		name  string
+		rel   string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "relative to workdir",
+			rel:   "services/auth",
+			files: []string{"handler.go", "internal/token.go"},
+			want:  []string{"services/auth/handler.go", "services/auth/internal/token.go"},
+		},
+		{
+			name:  "already root-relative",
+			rel:   "services/auth",
+			files: []string{"services/auth/handler.go"},
+			want:  []string{"services/auth/handler.go"},
+		},
+		{
+			name:  "absolute inside worktree",
+			rel:   "services/auth",
+			files: []string{wt + "/services/auth/handler.go"},
+			want:  []string{"services/auth/handler.go"},
+		},
+		{
+			name:  "sibling package via dot-dot",
+			rel:   "services/auth",
+			files: []string{"../shared/log.go"},
+			want:  []string{"services/shared/log.go"},
+		},
+		{
+			name:  "outside worktree kept as reported",
+			rel:   "services",
+			files: []string{"../../etc/passwd", "/etc/hosts"},
+			want:  []string{"../../etc/passwd", "/etc/hosts"},
+		},
+		{
+			name:  "empty list",
+			rel:   "services/auth",
+			files: []string{},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeFilesChanged(tt.files, wt, tt.rel)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("normalizeFilesChanged(%q, %q) = %q, want %q", tt.files, tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefixWorkdir(t *testing.T) {
+	o := New(nil, WithWorkdirs(map[string]string{
+		"auth-":     "services/auth",
+		"auth-web-": "web/auth",
+	}))
+
+	tests := []struct {
+		beadID string
+		want   string
+	}{
+		{beadID: "auth-12", want: "services/auth"},
+		{beadID: "auth-web-3", want: "web/auth"},
+		{beadID: "cap-1", want: ""},
+	}
+	for _, tt := range tests {
+		if got := o.prefixWorkdir(tt.beadID); got != tt.want {
+			t.Errorf("prefixWorkdir(%q) = %q, want %q", tt.beadID, got, tt.want)
+		}
+	}
+}
+
+func TestRunPipeline_PhaseWorkdir(t *testing.T) {
+	filesResponse := func(files ...string) mockResponse {
+		data, _ := json.Marshal(provider.Signal{Status: provider.StatusPass, Feedback: "ok", Summary: "done", FilesChanged: files})
+		return mockResponse{result: provider.Result{Output: string(data)}}
+	}
+	passGate := provider.Signal{Status: provider.StatusPass, Feedback: "ok", Summary: "tests passed", FilesChanged: []string{}}
+
+	tests := []struct {
+		name        string
+		labels      []string
+		workdirs    map[string]string
+		phaseDir    string
+		wantWorker  string
+		wantGate    string
+		wantChanged []string
+	}{
+		{
+			name:        "no scoping runs at the root",
+			wantWorker:  "",
+			wantGate:    "",
+			wantChanged: []string{"handler.go"},
+		},
+		{
+			name:        "bead label scopes every phase",
+			labels:      []string{"capsule:dir=services/auth"},
+			wantWorker:  "services/auth",
+			wantGate:    "services/auth",
+			wantChanged: []string{"services/auth/handler.go"},
+		},
+		{
+			name:        "prefix mapping scopes every phase",
+			workdirs:    map[string]string{"cap-": "services/auth"},
+			wantWorker:  "services/auth",
+			wantGate:    "services/auth",
+			wantChanged: []string{"services/auth/handler.go"},
+		},
+		{
+			name:        "phase workdir beats bead default",
+			labels:      []string{"capsule:dir=services/auth"},
+			phaseDir:    "services",
+			wantWorker:  "services/auth",
+			wantGate:    "services",
+			wantChanged: []string{"services/auth/handler.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a worktree with a service subdirectory
+			wt := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(wt, "services", "auth"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			sp := &sequenceProvider{responses: []mockResponse{filesResponse("handler.go")}}
+			gr := &mockGateRunner{signals: []provider.Signal{passGate}}
+			o := New(sp,
+				WithPromptLoader(&mockPromptLoader{}),
+				WithWorktreeManager(&mockWorktreeMgr{path: wt}),
+				WithGateRunner(gr),
+				WithWorkdirs(tt.workdirs),
+				WithPhases([]PhaseDefinition{
+					{Name: "execute", Kind: Worker, MaxRetries: 1},
+					{Name: "test", Kind: Gate, Command: "make test", Workdir: tt.phaseDir},
+				}),
+			)
+
+			// When the pipeline runs
+			output, err := o.RunPipeline(context.Background(), PipelineInput{
+				BeadID: "cap-1",
+				Bead:   worklog.BeadContext{Labels: tt.labels},
+			})
+			if err != nil {
+				t.Fatalf("RunPipeline() error = %v", err)
+			}
+
+			// Then the provider and gate run in the scoped directories
+			if got := sp.calls[0].workDir; got != filepath.Join(wt, tt.wantWorker) {
+				t.Errorf("worker workDir = %q, want %q", got, filepath.Join(wt, tt.wantWorker))
+			}
+			if got := gr.calls[0].workDir; got != filepath.Join(wt, tt.wantGate) {
+				t.Errorf("gate workDir = %q, want %q", got, filepath.Join(wt, tt.wantGate))
+			}
+			// And files changed stay relative to the worktree root
+			if got := output.PhaseResults[0].Signal.FilesChanged; !slices.Equal(got, tt.wantChanged) {
+				t.Errorf("FilesChanged = %q, want %q", got, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestRunPipeline_MissingWorkdirFailsPhase(t *testing.T) {
+	// Given a bead scoped to a directory the worktree doesn't have
+	sp := &sequenceProvider{}
+	o := New(sp,
+		WithPromptLoader(&mockPromptLoader{}),
+		WithWorktreeManager(&mockWorktreeMgr{path: t.TempDir()}),
+		WithPhases([]PhaseDefinition{{Name: "execute", Kind: Worker, MaxRetries: 1}}),
+	)
+
+	// When the pipeline runs
+	_, err := o.RunPipeline(context.Background(), PipelineInput{
+		BeadID: "cap-1",
+		Bead:   worklog.BeadContext{Labels: []string{"capsule:dir=services/billing"}},
+	})
+
+	// Then the phase fails without calling the provider
+	if !errors.Is(err, ErrInvalidWorkdir) {
+		t.Fatalf("RunPipeline() error = %v, want ErrInvalidWorkdir", err)
+	}
+	if len(sp.calls) != 0 {
+		t.Errorf("provider called %d times, want 0", len(sp.calls))
+	}
+}
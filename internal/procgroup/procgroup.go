@@ -0,0 +1,13 @@
+// Package procgroup starts subprocesses so that they can be interrupted and
+// killed together with every process they spawn. On Unix the command leads
+// its own process group; Windows has no process groups, so the tree under
+// the command is ended with taskkill.
+package procgroup
+
+import "strconv"
+
+// taskkillArgs returns the taskkill arguments that forcibly end pid and
+// every process it started.
+func taskkillArgs(pid int) []string {
+	return []string{"/T", "/F", "/PID", strconv.Itoa(pid)}
+}
@@ -0,0 +1,18 @@
+package procgroup
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTaskkillArgs(t *testing.T) {
+	// Given a process ID
+	// When the taskkill arguments are built
+	got := taskkillArgs(4242)
+
+	// Then they force-kill the whole tree rooted at that process
+	want := []string{"/T", "/F", "/PID", "4242"}
+	if !slices.Equal(got, want) {
+		t.Errorf("taskkillArgs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package procgroup
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// Set starts cmd in its own process group so that signals reach every
+// process it spawns, not just cmd itself.
+func Set(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// Interrupt sends SIGINT to the process group led by cmd.
+func Interrupt(cmd *exec.Cmd) {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+}
+
+// Kill sends SIGKILL to the process group led by cmd.
+func Kill(cmd *exec.Cmd) {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package procgroup
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// Set starts cmd in a new process group so console Ctrl+C events sent to
+// capsule are not delivered to it; Kill ends it explicitly.
+func Set(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// Interrupt is a no-op on Windows: console interrupts cannot be sent to a
+// single child, so the caller's grace period simply elapses before Kill.
+func Interrupt(*exec.Cmd) {}
+
+// Kill ends cmd and every process it started with taskkill, so no child
+// keeps files in the worktree open. If taskkill fails, cmd alone is killed.
+func Kill(cmd *exec.Cmd) {
+	if err := exec.Command("taskkill", taskkillArgs(cmd.Process.Pid)...).Run(); err != nil {
+		_ = cmd.Process.Kill()
+	}
+}
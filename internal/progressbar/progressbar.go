@@ -0,0 +1,31 @@
+// Package progressbar renders the text progress bar shown in the headers of
+// the pipeline TUI and the dashboard.
+package progressbar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Width is the number of cells in a header progress bar.
+const Width = 20
+
+// Styles colors the parts of a bar.
+type Styles struct {
+	Filled  lipgloss.Style
+	Empty   lipgloss.Style
+	Percent lipgloss.Style
+}
+
+// Render renders fraction, clamped to 0..1, as a bar of width cells
+// followed by its percentage.
+func Render(fraction float64, width int, styles Styles) string {
+	fraction = min(max(fraction, 0), 1)
+	filled := int(fraction * float64(width))
+	return styles.Filled.Render(strings.Repeat("█", filled)) +
+		styles.Empty.Render(strings.Repeat("░", width-filled)) +
+		styles.Percent.Render(fmt.Sprintf(" %d%%", int(math.Round(fraction*100))))
+}
@@ -0,0 +1,28 @@
+package progressbar
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction float64
+		want     string
+	}{
+		{name: "empty", fraction: 0, want: "░░░░░░░░░░ 0%"},
+		{name: "partial", fraction: 0.345, want: "███░░░░░░░ 35%"},
+		{name: "full", fraction: 1, want: "██████████ 100%"},
+		{name: "below zero", fraction: -0.5, want: "░░░░░░░░░░ 0%"},
+		{name: "above one", fraction: 1.5, want: "██████████ 100%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When a fraction is rendered with unstyled parts
+			got := Render(tt.fraction, 10, Styles{})
+
+			// Then the bar is filled in proportion and ends with the clamped percentage
+			if got != tt.want {
+				t.Errorf("Render(%v) = %q, want %q", tt.fraction, got, tt.want)
+			}
+		})
+	}
+}
@@ -28,37 +28,91 @@ type Context struct {
 	Description    string
 	Feedback       string
 	SiblingContext []SiblingContext
+	// ContextFiles holds project files such as CONVENTIONS.md, keyed by file
+	// name without extension: {{.ContextFiles.CONVENTIONS}}. Configured files
+	// that do not exist map to "".
+	ContextFiles map[string]string
+	// Baseline lists gate failures found before any changes were made
+	// (--pregate=warn). The orchestrator appends it to the first worker
+	// phase's composed prompt.
+	Baseline string
 	// Conflict resolution fields
 	ConflictFiles string // Newline-separated list of conflicting files
 	ConflictDiff  string // Full git diff output for conflicts
 	BeadContext   string // Task description and context for conflict resolution
 }
 
-// Loader reads prompt templates from a filesystem.
+// Source is one place prompt templates are read from.
+type Source struct {
+	Name string // Where the prompts live, e.g. ".capsule/prompts" or "embedded".
+	FS   fs.FS
+}
+
+// Loader reads prompt templates from one or more sources.
 type Loader struct {
-	fsys fs.FS
+	sources []Source
 }
 
 // NewLoader creates a Loader that reads prompts from the given filesystem.
 func NewLoader(fsys fs.FS) *Loader {
-	return &Loader{fsys: fsys}
+	return NewLayeredLoader(Source{FS: fsys})
+}
+
+// NewLayeredLoader creates a Loader that reads each prompt from the first
+// source that has it, so earlier sources override later ones.
+func NewLayeredLoader(sources ...Source) *Loader {
+	return &Loader{sources: sources}
 }
 
 // Load reads the prompt file for the named phase.
-// The file must exist at <phaseName>.md in the filesystem and be non-empty.
+// The file must exist at <phaseName>.md in a source and be non-empty.
 func (l *Loader) Load(phaseName string) (string, error) {
+	text, _, err := l.read(phaseName)
+	return text, err
+}
+
+// Source returns the name of the source the named phase's prompt is read
+// from, or an error if no source has it or the file is empty.
+func (l *Loader) Source(phaseName string) (string, error) {
+	_, source, err := l.read(phaseName)
+	return source, err
+}
+
+// read returns the named phase's prompt and the name of the first source
+// that has it. An empty file is an error rather than falling through to a
+// later source. When no source has it, the error names every source searched.
+func (l *Loader) read(phaseName string) (string, string, error) {
 	if strings.ContainsAny(phaseName, `/\`) {
-		return "", fmt.Errorf("prompt: invalid phase name %q", phaseName)
+		return "", "", fmt.Errorf("prompt: invalid phase name %q", phaseName)
 	}
 
-	data, err := fs.ReadFile(l.fsys, phaseName+".md")
-	if err != nil {
-		return "", fmt.Errorf("prompt: loading %s: %w", phaseName, err)
+	file := phaseName + ".md"
+	var searched []string
+	var lastErr error
+	for _, src := range l.sources {
+		data, err := fs.ReadFile(src.FS, file)
+		if errors.Is(err, fs.ErrNotExist) {
+			if src.Name != "" {
+				searched = append(searched, src.Name)
+			}
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("prompt: loading %s: %w", phaseName, err)
+		}
+		if len(data) == 0 {
+			return "", "", fmt.Errorf("%w: %s", ErrEmpty, phaseName)
+		}
+		return string(data), src.Name, nil
+	}
+	if lastErr == nil {
+		lastErr = fs.ErrNotExist
 	}
-	if len(data) == 0 {
-		return "", fmt.Errorf("%w: %s", ErrEmpty, phaseName)
+	if len(searched) > 0 {
+		return "", "", fmt.Errorf("prompt: loading %s: %s not found in %s: %w", phaseName, file, strings.Join(searched, ", "), lastErr)
 	}
-	return string(data), nil
+	return "", "", fmt.Errorf("prompt: loading %s: %w", phaseName, lastErr)
 }
 
 // Compose loads a prompt template and interpolates ctx into it.
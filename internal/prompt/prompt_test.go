@@ -242,3 +242,89 @@ func TestCompose_MissingKeyError(t *testing.T) {
 		t.Fatal("Compose(missing key) should return error with missingkey=error")
 	}
 }
+
+func TestCompose_InterpolatesContextFiles(t *testing.T) {
+	// Given: a template rendering a context file only when it has content
+	dir := t.TempDir()
+	tmpl := `# Execute
+{{with .ContextFiles.CONVENTIONS}}## Conventions
+{{.}}
+{{end}}{{with .ContextFiles.ARCHITECTURE}}## Architecture
+{{.}}
+{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "execute.md"), []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When: Compose is called with one present and one missing file
+	l := NewLoader(os.DirFS(dir))
+	got, err := l.Compose("execute", Context{ContextFiles: map[string]string{
+		"CONVENTIONS":  "Wrap errors with %w.",
+		"ARCHITECTURE": "",
+	}})
+	if err != nil {
+		t.Fatalf("Compose() error = %v", err)
+	}
+
+	// Then: only the present file is rendered
+	want := "# Execute\n## Conventions\nWrap errors with %w.\n"
+	if got != want {
+		t.Errorf("Compose() = %q, want %q", got, want)
+	}
+}
+
+func TestLayeredLoader(t *testing.T) {
+	// Given project, user, and default prompt directories
+	project, user, defaults := t.TempDir(), t.TempDir(), t.TempDir()
+	files := map[string]map[string]string{
+		project:  {"execute.md": "project execute", "empty.md": ""},
+		user:     {"execute.md": "user execute", "sign-off.md": "user sign-off"},
+		defaults: {"execute.md": "default execute", "sign-off.md": "default sign-off", "summary.md": "default summary", "empty.md": "default empty"},
+	}
+	for dir, entries := range files {
+		for name, content := range entries {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	l := NewLayeredLoader(
+		Source{Name: ".capsule/prompts", FS: os.DirFS(project)},
+		Source{Name: "~/.config/capsule/prompts", FS: os.DirFS(user)},
+		Source{Name: "embedded", FS: os.DirFS(defaults)},
+	)
+
+	tests := []struct {
+		phase      string
+		want       string
+		wantSource string
+		wantErr    string
+	}{
+		{phase: "execute", want: "project execute", wantSource: ".capsule/prompts"},
+		{phase: "sign-off", want: "user sign-off", wantSource: "~/.config/capsule/prompts"},
+		{phase: "summary", want: "default summary", wantSource: "embedded"},
+		{phase: "empty", wantErr: "empty prompt file"},
+		{phase: "nope", wantErr: "nope.md not found in .capsule/prompts, ~/.config/capsule/prompts, embedded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phase, func(t *testing.T) {
+			// When the phase's prompt is loaded and located
+			got, err := l.Load(tt.phase)
+			source, serr := l.Source(tt.phase)
+
+			// Then the first source that has it wins
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) || serr == nil {
+					t.Fatalf("Load() error = %v, Source() error = %v; want %q", err, serr, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || serr != nil {
+				t.Fatalf("Load() error = %v, Source() error = %v", err, serr)
+			}
+			if got != tt.want || source != tt.wantSource {
+				t.Errorf("got %q from %q, want %q from %q", got, source, tt.want, tt.wantSource)
+			}
+		})
+	}
+}
@@ -2,13 +2,21 @@ package provider
 
 import "time"
 
-// ClaudePreset returns the built-in CommandConfig for Claude Code.
+// ClaudePreset returns the built-in CommandConfig for Claude Code. Its
+// stream-json output reports each assistant turn while the phase runs and
+// ends with the result envelope.
 func ClaudePreset() CommandConfig {
 	return CommandConfig{
 		Name:            "claude",
 		Binary:          "claude",
 		PromptFlag:      "-p",
 		PermissionFlags: []string{"--dangerously-skip-permissions"},
+		ExtraFlags:      []string{"--output-format", "stream-json", "--verbose"},
+		JSONEnvelope:    true,
+		StreamProgress:  true,
+		VersionArgs:     []string{"--version"},
+		HealthPrompt:    "Reply with the word OK.",
+		LoginHint:       "run `claude login`",
 	}
 }
 
@@ -21,15 +29,57 @@ func KiroPreset() CommandConfig {
 		PermissionFlags: []string{"--trust-all-tools"},
 		ExtraFlags:      []string{"--no-interactive", "--wrap", "never"},
 		StripANSI:       true,
+		VersionArgs:     []string{"--version"},
+		LoginHint:       "run `kiro-cli login`",
+	}
+}
+
+// CodexPreset returns the built-in CommandConfig for the OpenAI Codex CLI.
+// The prompt goes to `codex exec` on stdin, which keeps long prompts off the
+// command line.
+func CodexPreset() CommandConfig {
+	return CommandConfig{
+		Name:            "codex",
+		Binary:          "codex",
+		Subcommand:      "exec",
+		PromptStdin:     true,
+		PermissionFlags: []string{"--dangerously-bypass-approvals-and-sandbox"},
+		ExtraFlags:      []string{"--skip-git-repo-check", "--color", "never"},
+		StripANSI:       true,
+		VersionArgs:     []string{"--version"},
+		LoginHint:       "run `codex login`",
 	}
 }
 
 // RegisterBuiltins registers the built-in provider presets on the given registry.
-func RegisterBuiltins(reg *Registry, timeout time.Duration) {
+// Extra opts are applied to every preset after the timeout.
+func RegisterBuiltins(reg *Registry, timeout time.Duration, opts ...Option) {
+	opts = append([]Option{WithTimeout(timeout)}, opts...)
 	reg.Register("claude", func() (Executor, error) {
-		return NewGenericProvider(ClaudePreset(), WithTimeout(timeout)), nil
+		return NewGenericProvider(ClaudePreset(), opts...), nil
 	})
 	reg.Register("kiro", func() (Executor, error) {
-		return NewGenericProvider(KiroPreset(), WithTimeout(timeout)), nil
+		return NewGenericProvider(KiroPreset(), opts...), nil
+	})
+	reg.Register("codex", func() (Executor, error) {
+		return NewGenericProvider(CodexPreset(), opts...), nil
+	})
+}
+
+// RegisterCommand registers cfg as a provider named cfg.Name, replacing any
+// provider already registered under that name. Extra opts are applied after
+// the timeout.
+func RegisterCommand(reg *Registry, cfg CommandConfig, timeout time.Duration, opts ...Option) {
+	opts = append([]Option{WithTimeout(timeout)}, opts...)
+	reg.Register(cfg.Name, func() (Executor, error) {
+		return NewGenericProvider(cfg, opts...), nil
+	})
+}
+
+// RegisterScripted registers the offline "scripted" provider, which replays
+// the script at path. The script is read when the provider is created.
+func RegisterScripted(reg *Registry, path string) {
+	reg.Register("scripted", func() (Executor, error) {
+		return NewScriptedProvider(path)
 	})
 }
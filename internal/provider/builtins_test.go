@@ -12,14 +12,14 @@ func TestRegisterBuiltins(t *testing.T) {
 	// When RegisterBuiltins is called
 	RegisterBuiltins(reg, 5*time.Minute)
 
-	// Then both claude and kiro are available
+	// Then claude, codex, and kiro are available
 	available := reg.AvailableProviders()
-	if len(available) != 2 {
-		t.Fatalf("AvailableProviders() len = %d, want 2", len(available))
+	if len(available) != 3 {
+		t.Fatalf("AvailableProviders() len = %d, want 3", len(available))
 	}
 
-	// And both create valid providers with correct names
-	for _, name := range []string{"claude", "kiro"} {
+	// And each creates a valid provider with the correct name
+	for _, name := range []string{"claude", "codex", "kiro"} {
 		p, err := reg.NewProvider(name)
 		if err != nil {
 			t.Fatalf("NewProvider(%q) error: %v", name, err)
@@ -29,3 +29,49 @@ func TestRegisterBuiltins(t *testing.T) {
 		}
 	}
 }
+
+func TestRegisterCommand(t *testing.T) {
+	// Given a registry with the built-ins
+	reg := NewRegistry()
+	RegisterBuiltins(reg, 5*time.Minute)
+
+	// When a command provider is registered, one under a built-in's name
+	RegisterCommand(reg, CommandConfig{Name: "llm", Binary: "llm", PromptStdin: true}, time.Minute)
+	RegisterCommand(reg, CommandConfig{Name: "claude", Binary: "claude-wrapper"}, time.Minute)
+
+	// Then it resolves by name and replaces the built-in
+	p, err := reg.NewProvider("llm")
+	if err != nil {
+		t.Fatalf("NewProvider(llm) error: %v", err)
+	}
+	if gp := p.(*GenericProvider); gp.timeout != time.Minute || !gp.config.PromptStdin {
+		t.Errorf("llm provider = timeout %v, config %+v", gp.timeout, gp.config)
+	}
+	p, err = reg.NewProvider("claude")
+	if err != nil {
+		t.Fatalf("NewProvider(claude) error: %v", err)
+	}
+	if got := p.(*GenericProvider).config.Binary; got != "claude-wrapper" {
+		t.Errorf("claude binary = %q, want claude-wrapper", got)
+	}
+	if n := len(reg.AvailableProviders()); n != 4 {
+		t.Errorf("AvailableProviders() len = %d, want 4", n)
+	}
+}
+
+func TestRegisterScripted(t *testing.T) {
+	// Given a registry with the scripted provider pointing at a script
+	reg := NewRegistry()
+	RegisterScripted(reg, writeScript(t, "phases: {}\n"))
+
+	// When the provider is created
+	p, err := reg.NewProvider("scripted")
+
+	// Then it is a scripted provider
+	if err != nil {
+		t.Fatalf("NewProvider(scripted) error: %v", err)
+	}
+	if p.Name() != "scripted" {
+		t.Errorf("Name() = %q, want %q", p.Name(), "scripted")
+	}
+}
@@ -3,24 +3,40 @@ package provider
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os/exec"
 	"regexp"
+	"strings"
 	"time"
+
+	"github.com/smileynet/capsule/internal/procgroup"
 )
 
 // defaultTimeout is used when no timeout option is provided.
 const defaultTimeout = 5 * time.Minute
 
+// defaultGracePeriod is how long a cancelled CLI has to exit after SIGINT
+// before its process group is killed.
+const defaultGracePeriod = 10 * time.Second
+
 // CommandConfig parameterizes CLI invocation for any AI CLI tool.
 type CommandConfig struct {
 	Name            string   // provider name for logs/errors
 	Binary          string   // executable name
 	Subcommand      string   // optional subcommand (e.g. "chat" for Kiro)
 	PromptFlag      string   // how prompt is passed ("-p" for Claude, "" for positional)
+	PromptStdin     bool     // write the prompt to stdin instead of passing it as an argument
 	PermissionFlags []string // headless/trust flags
 	ExtraFlags      []string // additional flags (e.g. --wrap never)
 	StripANSI       bool     // whether to strip ANSI escape codes from output
+	JSONEnvelope    bool     // output is Claude's --output-format json envelope; unwrap the text and usage
+	StreamProgress  bool     // stdout is NDJSON read while the CLI runs; progress lines are reported as they arrive
+
+	VersionArgs  []string // cheap invocation that proves the binary runs (e.g. --version); nil skips it
+	HealthPrompt string   // tiny prompt sent by HealthCheck to prove the CLI is authenticated; "" skips it
+	LoginHint    string   // remediation shown when the HealthPrompt probe fails
 }
 
 // Verify GenericProvider satisfies Executor at compile time.
@@ -30,7 +46,13 @@ var _ Executor = (*GenericProvider)(nil)
 type GenericProvider struct {
 	config     CommandConfig
 	timeout    time.Duration
+	grace      time.Duration
+	force      <-chan struct{}
+	logger     *slog.Logger
 	cmdBuilder func(ctx context.Context, prompt, workDir string) *exec.Cmd
+
+	lookPath      func(file string) (string, error)
+	healthBuilder func(ctx context.Context, args []string) *exec.Cmd
 }
 
 // Option configures a GenericProvider.
@@ -41,11 +63,35 @@ func WithTimeout(d time.Duration) Option {
 	return func(p *GenericProvider) { p.timeout = d }
 }
 
+// WithGracePeriod sets how long a cancelled CLI has to exit after SIGINT
+// before its process group is killed.
+func WithGracePeriod(d time.Duration) Option {
+	return func(p *GenericProvider) { p.grace = d }
+}
+
+// WithForceKill sets a channel that, once closed, kills a running CLI's
+// process group immediately instead of waiting out the grace period.
+func WithForceKill(force <-chan struct{}) Option {
+	return func(p *GenericProvider) { p.force = force }
+}
+
+// WithLogger sets the logger that records each CLI invocation: prompt
+// length, duration, and exit status. The default discards logs.
+func WithLogger(l *slog.Logger) Option {
+	return func(p *GenericProvider) {
+		if l != nil {
+			p.logger = l
+		}
+	}
+}
+
 // NewGenericProvider creates a GenericProvider from config and options.
 func NewGenericProvider(cfg CommandConfig, opts ...Option) *GenericProvider {
 	p := &GenericProvider{
 		config:  cfg,
 		timeout: defaultTimeout,
+		grace:   defaultGracePeriod,
+		logger:  slog.New(slog.DiscardHandler),
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -53,6 +99,12 @@ func NewGenericProvider(cfg CommandConfig, opts ...Option) *GenericProvider {
 	if p.cmdBuilder == nil {
 		p.cmdBuilder = p.defaultCmdBuilder
 	}
+	if p.lookPath == nil {
+		p.lookPath = exec.LookPath
+	}
+	if p.healthBuilder == nil {
+		p.healthBuilder = p.defaultHealthBuilder
+	}
 	return p
 }
 
@@ -62,6 +114,14 @@ func (p *GenericProvider) Name() string { return p.config.Name }
 // Execute runs the CLI with the given prompt in workDir.
 // It captures stdout for signal parsing and returns stderr in errors.
 func (p *GenericProvider) Execute(ctx context.Context, prompt, workDir string) (Result, error) {
+	return p.ExecuteStream(ctx, prompt, workDir, nil)
+}
+
+// ExecuteStream runs the CLI like Execute. When the provider streams
+// progress (CommandConfig.StreamProgress), each progress line is passed to
+// onProgress as the CLI prints it and left out of the result's output;
+// otherwise onProgress is never called.
+func (p *GenericProvider) ExecuteStream(ctx context.Context, prompt, workDir string, onProgress ProgressFunc) (Result, error) {
 	start := time.Now()
 
 	ctx, cancel := context.WithTimeout(ctx, p.timeout)
@@ -69,12 +129,20 @@ func (p *GenericProvider) Execute(ctx context.Context, prompt, workDir string) (
 
 	cmd := p.cmdBuilder(ctx, prompt, workDir)
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	var stdout fmt.Stringer
+	var stderr bytes.Buffer
+	if p.config.StreamProgress {
+		pw := &progressWriter{onProgress: onProgress, strip: p.config.StripANSI}
+		cmd.Stdout, stdout = pw, pw
+	} else {
+		var buf bytes.Buffer
+		cmd.Stdout, stdout = &buf, &buf
+	}
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := p.run(ctx, cmd)
 	duration := time.Since(start)
+	p.logInvocation(cmd, prompt, duration, err, ctx.Err() == context.DeadlineExceeded)
 
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
@@ -88,6 +156,7 @@ func (p *GenericProvider) Execute(ctx context.Context, prompt, workDir string) (
 		return Result{}, &ProviderError{
 			Provider: p.config.Name,
 			Err:      fmt.Errorf("%w: %s", err, stderr.String()),
+			Output:   tail(stdout.String(), errorOutputBytes),
 		}
 	}
 
@@ -95,20 +164,90 @@ func (p *GenericProvider) Execute(ctx context.Context, prompt, workDir string) (
 	if p.config.StripANSI {
 		output = stripANSI(output)
 	}
+	var usage Usage
+	if p.config.JSONEnvelope {
+		if text, u, ok := parseClaudeEnvelope(output); ok {
+			output, usage = text, u
+		}
+	}
 
 	return Result{
 		Output:   output,
 		ExitCode: 0,
 		Duration: duration,
+		Usage:    usage,
 	}, nil
 }
 
-// defaultCmdBuilder creates the CLI command from config fields.
-func (p *GenericProvider) defaultCmdBuilder(ctx context.Context, prompt, workDir string) *exec.Cmd {
+// logInvocation records a finished CLI invocation at debug level.
+func (p *GenericProvider) logInvocation(cmd *exec.Cmd, prompt string, d time.Duration, err error, timedOut bool) {
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		exitCode = -1
+	}
+	attrs := []any{
+		"provider", p.config.Name,
+		"binary", p.config.Binary,
+		"dir", cmd.Dir,
+		"prompt_bytes", len(prompt),
+		"duration", d,
+		"exit_code", exitCode,
+	}
+	if timedOut {
+		attrs = append(attrs, "timeout", p.timeout)
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	p.logger.Debug("provider invocation", attrs...)
+}
+
+// run starts cmd in its own process group and waits for it. When ctx is done
+// the group gets SIGINT, then SIGKILL after the grace period or as soon as
+// the force channel closes, so no CLI process outlives the call. On Windows
+// the process tree is ended with taskkill instead.
+func (p *GenericProvider) run(ctx context.Context, cmd *exec.Cmd) error {
+	procgroup.Set(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		procgroup.Interrupt(cmd)
+	case <-p.force:
+	}
+
+	grace := time.NewTimer(p.grace)
+	defer grace.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-grace.C:
+	case <-p.force:
+	}
+	procgroup.Kill(cmd)
+	return <-done
+}
+
+// defaultCmdBuilder creates the CLI command from config fields. The command
+// is not bound to ctx: run handles cancellation for the whole process group.
+func (p *GenericProvider) defaultCmdBuilder(_ context.Context, prompt, workDir string) *exec.Cmd {
 	args := buildArgs(p.config, prompt)
-	cmd := exec.CommandContext(ctx, p.config.Binary, args...)
+	cmd := exec.Command(p.config.Binary, args...)
 	cmd.Dir = workDir
 	cmd.WaitDelay = time.Second
+	if p.config.PromptStdin {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
 	return cmd
 }
 
@@ -120,9 +259,12 @@ func buildArgs(cfg CommandConfig, prompt string) []string {
 	}
 	args = append(args, cfg.PermissionFlags...)
 	args = append(args, cfg.ExtraFlags...)
-	if cfg.PromptFlag != "" {
+	switch {
+	case cfg.PromptStdin:
+		// defaultCmdBuilder writes the prompt to the command's stdin.
+	case cfg.PromptFlag != "":
 		args = append(args, cfg.PromptFlag, prompt)
-	} else {
+	default:
 		args = append(args, prompt)
 	}
 	return args
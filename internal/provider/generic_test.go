@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/exec"
 	"slices"
@@ -35,6 +37,37 @@ func TestHelperProcess(t *testing.T) {
 		time.Sleep(5 * time.Second)
 		fmt.Println(`{"status":"PASS","feedback":"ok","files_changed":[],"summary":"ok"}`)
 		os.Exit(0)
+	case "claude_json":
+		fmt.Println(`{"type":"result","subtype":"success","result":"Done.\n{\"status\":\"PASS\",\"feedback\":\"ok\",\"files_changed\":[],\"summary\":\"ok\"}","total_cost_usd":0.0421,"usage":{"input_tokens":100,"cache_read_input_tokens":900,"output_tokens":50}}`)
+		os.Exit(0)
+	case "claude_stream":
+		fmt.Println(`{"type":"system","subtype":"init"}`)
+		fmt.Println(`{"type":"assistant","message":{"content":[{"type":"text","text":"Reading the tests\nthen fixing"}]}}`)
+		fmt.Println(`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Edit"}]}}`)
+		fmt.Println(`{"type":"result","subtype":"success","result":"{\"status\":\"PASS\",\"feedback\":\"ok\",\"files_changed\":[],\"summary\":\"ok\"}","total_cost_usd":0.01,"usage":{"input_tokens":10,"output_tokens":5}}`)
+		os.Exit(0)
+	case "ndjson_progress":
+		fmt.Println(`{"event":"progress","message":"writing tests"}`)
+		fmt.Println(`{"event":"progress","message":"{\"status\":\"ERROR\",\"feedback\":\"x\",\"summary\":\"x\"}"}`)
+		fmt.Print(`{"status":"PASS","feedback":"ok","files_changed":[],"summary":"Done"}`)
+		os.Exit(0)
+	case "version":
+		fmt.Println("2.0.1 (Claude Code)")
+		os.Exit(0)
+	case "probe_ok":
+		fmt.Println(`{"type":"result","subtype":"success","is_error":false,"result":"OK"}`)
+		os.Exit(0)
+	case "probe_not_logged_in":
+		fmt.Println(`{"type":"result","subtype":"success","is_error":true,"result":"Invalid API key · Please run /login"}`)
+		os.Exit(0)
+	case "stdin_prompt":
+		prompt, _ := io.ReadAll(os.Stdin)
+		if strings.TrimSpace(string(prompt)) != "test prompt" {
+			fmt.Fprintf(os.Stderr, "unexpected stdin %q\n", prompt)
+			os.Exit(3)
+		}
+		fmt.Println(`{"status":"PASS","feedback":"read stdin","files_changed":[],"summary":"Done"}`)
+		os.Exit(0)
 	case "ansi_output":
 		fmt.Println("\x1b[32mThinking...\x1b[0m")
 		fmt.Println(`{"status":"PASS","feedback":"All good","files_changed":[],"summary":"Done"}`)
@@ -64,6 +97,7 @@ func TestGenericProvider_Name(t *testing.T) {
 	}{
 		{"claude preset", ClaudePreset(), "claude"},
 		{"kiro preset", KiroPreset(), "kiro"},
+		{"codex preset", CodexPreset(), "codex"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -263,6 +297,182 @@ func TestGenericProvider_ErrorIncludesProviderName(t *testing.T) {
 	}
 }
 
+func TestGenericProvider_LogsInvocation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{mode: "success", want: []string{"provider=claude", "prompt_bytes=6", "exit_code=0"}},
+		{mode: "error_exit", want: []string{"provider=claude", "exit_code=1", "error="}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			// Given a provider with a logger
+			var buf strings.Builder
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			p := NewGenericProvider(ClaudePreset(), WithTimeout(5*time.Second), WithLogger(logger))
+			p.cmdBuilder = func(ctx context.Context, prompt, workDir string) *exec.Cmd {
+				return helperCommand(ctx, tt.mode)
+			}
+
+			// When Execute is called
+			_, _ = p.Execute(context.Background(), "prompt", t.TempDir())
+
+			// Then the invocation is logged with its exit status
+			logs := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(logs, want) {
+					t.Errorf("logs missing %q:\n%s", want, logs)
+				}
+			}
+		})
+	}
+}
+
+func TestGenericProvider_ExecuteJSONEnvelope(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+
+	// Given a claude provider whose CLI prints a JSON result envelope
+	p := NewGenericProvider(ClaudePreset(), WithTimeout(5*time.Second))
+	p.cmdBuilder = func(ctx context.Context, prompt, workDir string) *exec.Cmd {
+		return helperCommand(ctx, "claude_json")
+	}
+
+	// When Execute is called
+	result, err := p.Execute(context.Background(), "prompt", t.TempDir())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// Then the response text is unwrapped and usage is reported
+	if sig, err := result.ParseSignal(); err != nil || sig.Status != StatusPass {
+		t.Errorf("ParseSignal() = %+v, %v; want PASS", sig, err)
+	}
+	want := Usage{InputTokens: 1000, OutputTokens: 50, CostUSD: 0.0421}
+	if result.Usage != want {
+		t.Errorf("Usage = %+v, want %+v", result.Usage, want)
+	}
+}
+
+func TestGenericProvider_ExecuteStream(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess tests in short mode")
+	}
+
+	tests := []struct {
+		name         string
+		config       CommandConfig
+		mode         string
+		wantProgress []string
+		wantFeedback string
+	}{
+		{
+			name:         "claude stream-json reports turns and unwraps the result",
+			config:       ClaudePreset(),
+			mode:         "claude_stream",
+			wantProgress: []string{"Reading the tests", "using Edit"},
+			wantFeedback: "ok",
+		},
+		{
+			name:         "progress events are reported and left out of the signal",
+			config:       CommandConfig{Name: "custom", StreamProgress: true},
+			mode:         "ndjson_progress",
+			wantProgress: []string{"writing tests", `{"status":"ERROR","feedback":"x","summary":"x"}`},
+			wantFeedback: "ok",
+		},
+		{
+			name:         "non-streaming provider reports nothing",
+			config:       CommandConfig{Name: "custom"},
+			mode:         "success",
+			wantFeedback: "All good",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a provider whose CLI prints the mode's output
+			p := NewGenericProvider(tt.config, WithTimeout(5*time.Second))
+			p.cmdBuilder = func(ctx context.Context, prompt, workDir string) *exec.Cmd {
+				return helperCommand(ctx, tt.mode)
+			}
+
+			// When ExecuteStream is called
+			var progress []string
+			result, err := p.ExecuteStream(context.Background(), "prompt", t.TempDir(), func(msg string) {
+				progress = append(progress, msg)
+			})
+			if err != nil {
+				t.Fatalf("ExecuteStream() error = %v", err)
+			}
+
+			// Then progress arrives in order and the signal comes from the final output
+			if !slices.Equal(progress, tt.wantProgress) {
+				t.Errorf("progress = %q, want %q", progress, tt.wantProgress)
+			}
+			sig, err := result.ParseSignal()
+			if err != nil || sig.Feedback != tt.wantFeedback {
+				t.Errorf("ParseSignal() = %+v, %v; want feedback %q", sig, err, tt.wantFeedback)
+			}
+		})
+	}
+}
+
+func TestGenericProvider_ExecutePromptStdin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess tests in short mode")
+	}
+
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr bool
+	}{
+		{name: "prompt is read from stdin", mode: "stdin_prompt"},
+		{name: "non-zero exit returns ProviderError", mode: "error_exit", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a stdin provider whose binary is the re-exec helper
+			t.Setenv("GO_TEST_HELPER_PROCESS", "1")
+			t.Setenv("GO_TEST_HELPER_MODE", tt.mode)
+			p := NewGenericProvider(CommandConfig{
+				Name:        "codex",
+				Binary:      os.Args[0],
+				ExtraFlags:  []string{"-test.run=^TestHelperProcess$"},
+				PromptStdin: true,
+			}, WithTimeout(5*time.Second))
+
+			// When Execute is called through the default command builder
+			result, err := p.Execute(context.Background(), "test prompt", t.TempDir())
+
+			// Then a failing CLI is reported and a passing one yields its signal
+			if tt.wantErr {
+				var pe *ProviderError
+				if !errors.As(err, &pe) {
+					t.Fatalf("expected *ProviderError, got %T: %v", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sig, err := result.ParseSignal()
+			if err != nil {
+				t.Fatalf("ParseSignal error: %v", err)
+			}
+			if sig.Status != StatusPass || sig.Feedback != "read stdin" {
+				t.Errorf("signal = %+v, want PASS from stdin", sig)
+			}
+		})
+	}
+}
+
 func TestBuildArgs(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -274,7 +484,7 @@ func TestBuildArgs(t *testing.T) {
 			name:   "claude preset uses prompt flag",
 			config: ClaudePreset(),
 			prompt: "test prompt",
-			want:   []string{"--dangerously-skip-permissions", "-p", "test prompt"},
+			want:   []string{"--dangerously-skip-permissions", "--output-format", "stream-json", "--verbose", "-p", "test prompt"},
 		},
 		{
 			name:   "kiro preset uses subcommand and positional prompt",
@@ -282,6 +492,12 @@ func TestBuildArgs(t *testing.T) {
 			prompt: "test prompt",
 			want:   []string{"chat", "--trust-all-tools", "--no-interactive", "--wrap", "never", "test prompt"},
 		},
+		{
+			name:   "codex preset sends the prompt on stdin",
+			config: CodexPreset(),
+			prompt: "test prompt",
+			want:   []string{"exec", "--dangerously-bypass-approvals-and-sandbox", "--skip-git-repo-check", "--color", "never"},
+		},
 		{
 			name: "minimal config with only binary and positional prompt",
 			config: CommandConfig{
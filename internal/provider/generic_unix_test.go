@@ -0,0 +1,120 @@
+//go:build !windows
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestGenericProvider_CancelKillsProcessGroup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+
+	// A CLI that ignores SIGINT and leaves a grandchild writing to the worktree.
+	const script = `trap '' INT; (trap '' INT; while :; do sleep 0.05; done) & echo $! > child.pid; wait`
+
+	tests := []struct {
+		name    string
+		grace   time.Duration
+		force   bool
+		maxWait time.Duration
+	}{
+		{name: "kills group after grace period", grace: 200 * time.Millisecond, maxWait: 3 * time.Second},
+		{name: "force kill skips grace period", grace: time.Hour, force: true, maxWait: 3 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a provider running the stubborn CLI
+			dir := t.TempDir()
+			force := make(chan struct{})
+			p := NewGenericProvider(ClaudePreset(), WithGracePeriod(tt.grace), WithForceKill(force))
+			p.cmdBuilder = func(_ context.Context, _, workDir string) *exec.Cmd {
+				cmd := exec.Command("sh", "-c", script)
+				cmd.Dir = workDir
+				return cmd
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			errCh := make(chan error, 1)
+			go func() {
+				_, err := p.Execute(ctx, "prompt", dir)
+				errCh <- err
+			}()
+			pid := waitForPID(t, filepath.Join(dir, "child.pid"))
+
+			// When the context is cancelled (and force-killed, if requested)
+			start := time.Now()
+			cancel()
+			if tt.force {
+				close(force)
+			}
+
+			// Then Execute returns a ProviderError within the expected time
+			select {
+			case err := <-errCh:
+				var pe *ProviderError
+				if !errors.As(err, &pe) {
+					t.Errorf("expected *ProviderError, got %T: %v", err, err)
+				}
+			case <-time.After(tt.maxWait):
+				t.Fatalf("Execute did not return within %v", tt.maxWait)
+			}
+			if tt.force && time.Since(start) > tt.maxWait {
+				t.Errorf("force kill took %v", time.Since(start))
+			}
+
+			// And the grandchild process is gone
+			deadline := time.Now().Add(time.Second)
+			for processAlive(pid) {
+				if time.Now().After(deadline) {
+					t.Fatalf("grandchild %d still running", pid)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+// processAlive reports whether pid is running. Zombies count as exited: a
+// killed grandchild may be reparented to an init that never reaps it.
+func processAlive(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return true // No procfs; trust kill(0).
+	}
+	// The state field follows the parenthesized command name.
+	if i := strings.LastIndexByte(string(stat), ')'); i >= 0 && i+2 < len(stat) {
+		return stat[i+2] != 'Z'
+	}
+	return true
+}
+
+// waitForPID polls path until it holds a process ID.
+func waitForPID(t *testing.T, path string) int {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil {
+			if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+				return pid
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("no pid written to %s", path)
+	return 0
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// healthTimeout bounds each health check invocation, so a hung CLI cannot
+// stall startup.
+const healthTimeout = 30 * time.Second
+
+// HealthError reports that a provider is not ready to run phases.
+type HealthError struct {
+	Provider string
+	Err      error
+	Hint     string // How to fix it, e.g. "run `claude login`"; may be empty.
+}
+
+func (e *HealthError) Error() string {
+	msg := fmt.Sprintf("provider: %s: not ready: %s", e.Provider, e.Err)
+	if e.Hint != "" {
+		msg += " (" + e.Hint + ")"
+	}
+	return msg
+}
+
+func (e *HealthError) Unwrap() error {
+	return e.Err
+}
+
+// NoHealthCheck is embedded by providers that have nothing to check; its
+// HealthCheck always reports healthy.
+type NoHealthCheck struct{}
+
+// HealthCheck returns nil.
+func (NoHealthCheck) HealthCheck(context.Context) error { return nil }
+
+// HealthCheck verifies the CLI is on PATH, runs its VersionArgs, and sends
+// the HealthPrompt to prove it is authenticated. Each step is skipped when
+// its CommandConfig field is empty.
+func (p *GenericProvider) HealthCheck(ctx context.Context) error {
+	binary := p.config.Binary
+	installHint := fmt.Sprintf("install %s and make sure it is on PATH", binary)
+	if _, err := p.lookPath(binary); err != nil {
+		return &HealthError{Provider: p.config.Name, Err: fmt.Errorf("%s not found on PATH", binary), Hint: installHint}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	if len(p.config.VersionArgs) > 0 {
+		if _, err := p.runHealth(ctx, p.config.VersionArgs); err != nil {
+			return &HealthError{Provider: p.config.Name, Err: err, Hint: installHint}
+		}
+	}
+	if p.config.HealthPrompt == "" {
+		return nil
+	}
+	out, err := p.runHealth(ctx, buildArgs(p.config, p.config.HealthPrompt))
+	if err == nil && p.config.JSONEnvelope {
+		err = envelopeError(out)
+	}
+	if err != nil {
+		return &HealthError{Provider: p.config.Name, Err: err, Hint: p.config.LoginHint}
+	}
+	return nil
+}
+
+// runHealth runs the CLI with args and returns its stdout. Failures include
+// the first line of stderr, where CLIs explain what is wrong.
+func (p *GenericProvider) runHealth(ctx context.Context, args []string) (string, error) {
+	start := time.Now()
+	cmd := p.healthBuilder(ctx, args)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	p.logger.Debug("provider health check", "provider", p.config.Name, "binary", p.config.Binary, "duration", time.Since(start), "error", err)
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s did not respond within %s", p.config.Binary, healthTimeout)
+	}
+	if err != nil {
+		if line, _, _ := strings.Cut(strings.TrimSpace(stderr.String()), "\n"); line != "" {
+			return "", fmt.Errorf("%w: %s", err, line)
+		}
+		if line, _, _ := strings.Cut(strings.TrimSpace(stdout.String()), "\n"); line != "" {
+			return "", fmt.Errorf("%w: %s", err, line)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// defaultHealthBuilder creates a health check command bound to ctx.
+func (p *GenericProvider) defaultHealthBuilder(ctx context.Context, args []string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, p.config.Binary, args...)
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+// envelopeError returns the error a Claude JSON envelope reports, such as
+// "Invalid API key · Please run /login", or nil if it reports success.
+func envelopeError(output string) error {
+	env, ok := decodeEnvelope(output)
+	if !ok {
+		return nil // Not an envelope; the CLI answered, which is all we can check.
+	}
+	if !env.IsError {
+		return nil
+	}
+	if env.Result != nil && *env.Result != "" {
+		return errors.New(*env.Result)
+	}
+	return errors.New("provider reported an error")
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGenericProvider_HealthCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+
+	tests := []struct {
+		name        string
+		cfg         CommandConfig
+		missing     bool   // Binary is not on PATH.
+		versionMode string // Helper mode for the version invocation.
+		probeMode   string // Helper mode for the auth probe.
+		wantErr     string // Substring of the error; "" means healthy.
+		wantHint    string
+	}{
+		{
+			name:        "claude installed and logged in",
+			cfg:         ClaudePreset(),
+			versionMode: "version",
+			probeMode:   "probe_ok",
+		},
+		{
+			name:     "binary missing",
+			cfg:      ClaudePreset(),
+			missing:  true,
+			wantErr:  "claude not found on PATH",
+			wantHint: "install claude",
+		},
+		{
+			name:        "version fails",
+			cfg:         ClaudePreset(),
+			versionMode: "error_exit",
+			wantErr:     "API key invalid",
+			wantHint:    "install claude",
+		},
+		{
+			name:        "not logged in",
+			cfg:         ClaudePreset(),
+			versionMode: "version",
+			probeMode:   "probe_not_logged_in",
+			wantErr:     "Invalid API key",
+			wantHint:    "claude login",
+		},
+		{
+			name:        "probe exits non-zero",
+			cfg:         ClaudePreset(),
+			versionMode: "version",
+			probeMode:   "error_exit",
+			wantErr:     "API key invalid",
+			wantHint:    "claude login",
+		},
+		{
+			name:        "no probe configured",
+			cfg:         KiroPreset(),
+			versionMode: "version",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a provider whose CLI behaves as described
+			p := NewGenericProvider(tt.cfg)
+			p.lookPath = func(file string) (string, error) {
+				if tt.missing {
+					return "", exec.ErrNotFound
+				}
+				return "/usr/bin/" + file, nil
+			}
+			p.healthBuilder = func(ctx context.Context, args []string) *exec.Cmd {
+				if len(args) == 1 && args[0] == "--version" {
+					return helperCommand(ctx, tt.versionMode)
+				}
+				return helperCommand(ctx, tt.probeMode)
+			}
+
+			// When the health check runs
+			err := p.HealthCheck(context.Background())
+
+			// Then it reports the problem with a hint, or nothing
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("HealthCheck() = %v, want nil", err)
+				}
+				return
+			}
+			var he *HealthError
+			if !errors.As(err, &he) {
+				t.Fatalf("HealthCheck() = %v, want *HealthError", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(he.Hint, tt.wantHint) {
+				t.Errorf("HealthCheck() = %q (hint %q), want %q with hint %q", err, he.Hint, tt.wantErr, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestHealthError_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *HealthError
+		want string
+	}{
+		{
+			name: "with hint",
+			err:  &HealthError{Provider: "claude", Err: errors.New("Invalid API key"), Hint: "run `claude login`"},
+			want: "provider: claude: not ready: Invalid API key (run `claude login`)",
+		},
+		{
+			name: "without hint",
+			err:  &HealthError{Provider: "kiro", Err: errors.New("exit status 1")},
+			want: "provider: kiro: not ready: exit status 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptedProvider_HealthCheckIsNoOp(t *testing.T) {
+	// Given the offline scripted provider
+	var e Executor = &ScriptedProvider{}
+
+	// When its health is checked, Then it is always healthy
+	if err := e.HealthCheck(context.Background()); err != nil {
+		t.Errorf("HealthCheck() = %v, want nil", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Status represents the outcome of a pipeline phase.
@@ -26,14 +27,40 @@ type Finding struct {
 	Description string `json:"description"`
 }
 
+// Severities lists finding severities from most to least severe.
+var Severities = []string{"critical", "major", "minor", "nit"}
+
+// SeverityPriority maps a finding severity to a bead priority (0 highest).
+// Unknown severities rank with "nit".
+func SeverityPriority(severity string) int {
+	switch severity {
+	case "critical":
+		return 0
+	case "major":
+		return 1
+	case "minor":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// SignalSchemaVersion is the signal format this build understands. A
+// signal without schema_version predates versioning and parses as version
+// 0; a newer version parses too, with the fields this build does not know
+// ignored.
+const SignalSchemaVersion = 1
+
 // Signal is the structured output produced by a pipeline phase.
 type Signal struct {
-	Status       Status    `json:"status"`
-	Feedback     string    `json:"feedback"`
-	FilesChanged []string  `json:"files_changed"`
-	Summary      string    `json:"summary"`
-	CommitHash   string    `json:"commit_hash,omitempty"`
-	Findings     []Finding `json:"findings,omitempty"`
+	SchemaVersion int       `json:"schema_version,omitempty"` // Format version the phase wrote; 0 when it did not say.
+	Status        Status    `json:"status"`
+	Feedback      string    `json:"feedback"`
+	FilesChanged  []string  `json:"files_changed"`
+	Summary       string    `json:"summary"`
+	Reason        string    `json:"reason,omitempty"` // Why the phase returned SKIP.
+	CommitHash    string    `json:"commit_hash,omitempty"`
+	Findings      []Finding `json:"findings,omitempty"`
 }
 
 // Result holds the raw output from a provider execution.
@@ -41,6 +68,7 @@ type Result struct {
 	Output   string
 	ExitCode int
 	Duration time.Duration
+	Usage    Usage // Zero when the provider does not report usage.
 }
 
 // ParseSignal extracts the Signal from this result's output.
@@ -53,8 +81,9 @@ var _ Executor = (*MockProvider)(nil)
 
 // MockProvider is a test double that satisfies any Provider-shaped interface.
 type MockProvider struct {
-	NameVal     string
-	ExecuteFunc func(ctx context.Context, prompt, workDir string) (Result, error)
+	NameVal         string
+	ExecuteFunc     func(ctx context.Context, prompt, workDir string) (Result, error)
+	HealthCheckFunc func(ctx context.Context) error
 }
 
 // Name returns the configured provider name.
@@ -68,45 +97,44 @@ func (m *MockProvider) Execute(ctx context.Context, prompt, workDir string) (Res
 	return m.ExecuteFunc(ctx, prompt, workDir)
 }
 
+// HealthCheck delegates to HealthCheckFunc, reporting healthy if it is nil.
+func (m *MockProvider) HealthCheck(ctx context.Context) error {
+	if m.HealthCheckFunc == nil {
+		return nil
+	}
+	return m.HealthCheckFunc(ctx)
+}
+
 // ParseSignal extracts the last valid Signal JSON from phase output.
-// It strips markdown code fences before scanning for JSON objects.
+// Providers often wrap the signal in markdown fences, prefix it with prose,
+// nest it in another object, or pretty-print it across lines, so the parser
+// strips fence lines and then scans for every JSON object, keeping the last
+// one that carries the required fields. A candidate with a recognized
+// status wins over a later one without. Status values are matched
+// case-insensitively. Fields the Signal does not declare are ignored, so
+// signals from newer schema versions still parse.
 func ParseSignal(output string) (Signal, error) {
 	// Strip markdown code fence lines.
 	var cleaned []string
 	for _, line := range strings.Split(output, "\n") {
-		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
 			continue
 		}
 		cleaned = append(cleaned, line)
 	}
 
-	// Scan for the last valid JSON object containing a signal.
-	var lastSignal *Signal
-	for _, line := range cleaned {
-		line = strings.TrimSpace(line)
-		if !strings.HasPrefix(line, "{") {
-			continue
-		}
-		var s Signal
-		if err := json.Unmarshal([]byte(line), &s); err != nil {
-			continue
-		}
-		// Must have all required fields to be considered a signal.
-		if s.Status != "" && s.Feedback != "" && s.Summary != "" {
-			lastSignal = &s
-		}
-	}
-
+	lastSignal := lastSignalObject(strings.Join(cleaned, "\n"))
 	if lastSignal == nil {
-		return Signal{}, &SignalParseError{Reason: "no valid signal JSON found in output"}
+		return Signal{}, &SignalParseError{Reason: "no valid signal JSON found in output", Output: output}
 	}
 
 	// Validate status value.
-	switch lastSignal.Status {
-	case StatusPass, StatusNeedsWork, StatusError, StatusSkip:
-		// valid
-	default:
-		return Signal{}, &SignalParseError{Reason: fmt.Sprintf("invalid status value: %q", lastSignal.Status)}
+	if !knownStatus(lastSignal.Status) {
+		return Signal{}, &SignalParseError{
+			Reason: fmt.Sprintf("invalid status value: %q", lastSignal.Status),
+			Output: output,
+		}
 	}
 
 	// Ensure slices are never nil (normalize to empty slices).
@@ -120,19 +148,87 @@ func ParseSignal(output string) (Signal, error) {
 	return *lastSignal, nil
 }
 
+// lastSignalObject returns the last JSON object in text that has the
+// required signal fields and a recognized status, else the last one with
+// the required fields, or nil if none has them. Its status is normalized.
+// Objects are tried from the last '{' backwards, so a signal at the end of a
+// long transcript is found without decoding the rest. A '{' that does not
+// start a decodable object (prose, code, truncated JSON) is skipped, and a
+// signal nested inside another object is found at its own brace.
+func lastSignalObject(text string) *Signal {
+	var last *Signal
+	for i := strings.LastIndexByte(text, '{'); i >= 0; i = strings.LastIndexByte(text[:i], '{') {
+		var s Signal
+		if err := json.NewDecoder(strings.NewReader(text[i:])).Decode(&s); err != nil {
+			continue
+		}
+		// Must have all required fields to be considered a signal.
+		if s.Status == "" || s.Feedback == "" || s.Summary == "" {
+			continue
+		}
+		s.Status = normalizeStatus(s.Status)
+		if knownStatus(s.Status) {
+			return &s
+		}
+		if last == nil {
+			last = &s
+		}
+	}
+	return last
+}
+
+// knownStatus reports whether s is one of the canonical Status values.
+func knownStatus(s Status) bool {
+	switch s {
+	case StatusPass, StatusNeedsWork, StatusError, StatusSkip:
+		return true
+	}
+	return false
+}
+
+// normalizeStatus maps lenient status spellings ("pass", "needs-work") to
+// the canonical upper-case Status values.
+func normalizeStatus(s Status) Status {
+	norm := strings.ToUpper(strings.TrimSpace(string(s)))
+	return Status(strings.ReplaceAll(norm, "-", "_"))
+}
+
+// excerptLen is how many characters of raw output SignalParseError shows
+// from each end of the output.
+const excerptLen = 200
+
 // SignalParseError indicates the phase output could not be parsed into a Signal.
 type SignalParseError struct {
 	Reason string
+	Output string // Raw phase output, used for the error excerpt and debugging.
 }
 
 func (e *SignalParseError) Error() string {
-	return "provider: signal parse: " + e.Reason
+	msg := "provider: signal parse: " + e.Reason
+	out := e.Output
+	switch {
+	case strings.TrimSpace(out) == "":
+		return msg
+	case len(out) <= 2*excerptLen:
+		return fmt.Sprintf("%s (output: %q)", msg, out)
+	default:
+		head, tail := excerptLen, len(out)-excerptLen
+		// Cut on rune boundaries so multi-byte characters stay whole.
+		for head > 0 && !utf8.RuneStart(out[head]) {
+			head--
+		}
+		for tail < len(out) && !utf8.RuneStart(out[tail]) {
+			tail++
+		}
+		return fmt.Sprintf("%s (output head: %q ... tail: %q)", msg, out[:head], out[tail:])
+	}
 }
 
 // ProviderError wraps an error from a specific provider.
 type ProviderError struct {
 	Provider string
 	Err      error
+	Output   string // Tail of the CLI's stdout, which some CLIs report API errors on; not part of Error.
 }
 
 func (e *ProviderError) Error() string {
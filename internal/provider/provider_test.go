@@ -3,6 +3,8 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 )
@@ -126,6 +128,109 @@ Found 3 acceptance criteria.
 				Findings:     []Finding{},
 			},
 		},
+		{
+			name:   "fenced signal with language tag and leading prose",
+			output: "Here is my result:\n\n```json\n" + `{"status":"PASS","feedback":"done","files_changed":["a.go"],"summary":"ok"}` + "\n```\n",
+			want: Signal{
+				Status:       StatusPass,
+				Feedback:     "done",
+				FilesChanged: []string{"a.go"},
+				Summary:      "ok",
+			},
+		},
+		{
+			name:   "signal prefixed with prose on same line",
+			output: `Here is my result: {"status":"PASS","feedback":"done","files_changed":[],"summary":"ok"}`,
+			want: Signal{
+				Status:       StatusPass,
+				Feedback:     "done",
+				FilesChanged: []string{},
+				Summary:      "ok",
+			},
+		},
+		{
+			name:   "signal followed by trailing text",
+			output: `{"status":"NEEDS_WORK","feedback":"fix it","files_changed":[],"summary":"issues"}` + "\nLet me know if you need anything else.",
+			want: Signal{
+				Status:       StatusNeedsWork,
+				Feedback:     "fix it",
+				FilesChanged: []string{},
+				Summary:      "issues",
+			},
+		},
+		{
+			name: "pretty-printed multi-line signal",
+			output: `Result:
+{
+  "status": "PASS",
+  "feedback": "multi",
+  "files_changed": ["x.go"],
+  "summary": "pretty",
+  "findings": [{"title": "t", "severity": "nit", "description": "d"}]
+}`,
+			want: Signal{
+				Status:       StatusPass,
+				Feedback:     "multi",
+				FilesChanged: []string{"x.go"},
+				Summary:      "pretty",
+				Findings:     []Finding{{Title: "t", Severity: "nit", Description: "d"}},
+			},
+		},
+		{
+			name:   "unbalanced brace in prose before signal",
+			output: "Use a map like {key: value\n" + `{"status":"PASS","feedback":"ok","files_changed":[],"summary":"done"}`,
+			want: Signal{
+				Status:       StatusPass,
+				Feedback:     "ok",
+				FilesChanged: []string{},
+				Summary:      "done",
+			},
+		},
+		{
+			name:   "lowercase status",
+			output: `{"status":"pass","feedback":"ok","files_changed":[],"summary":"done"}`,
+			want: Signal{
+				Status:       StatusPass,
+				Feedback:     "ok",
+				FilesChanged: []string{},
+				Summary:      "done",
+			},
+		},
+		{
+			name:   "lowercase needs_work status",
+			output: `{"status":"needs_work","feedback":"fix","files_changed":[],"summary":"issues"}`,
+			want: Signal{
+				Status:       StatusNeedsWork,
+				Feedback:     "fix",
+				FilesChanged: []string{},
+				Summary:      "issues",
+			},
+		},
+		{
+			name:   "null files_changed normalizes to empty slice",
+			output: `{"status":"PASS","feedback":"ok","files_changed":null,"summary":"done"}`,
+			want: Signal{
+				Status:       StatusPass,
+				Feedback:     "ok",
+				FilesChanged: []string{},
+				Summary:      "done",
+			},
+		},
+		{
+			name:   "missing files_changed is tolerated",
+			output: `{"status":"PASS","feedback":"ok","summary":"done"}`,
+			want: Signal{
+				Status:       StatusPass,
+				Feedback:     "ok",
+				FilesChanged: []string{},
+				Summary:      "done",
+			},
+		},
+		{
+			name:    "truncated signal",
+			output:  "Working...\n" + `{"status":"PASS","feedback":"ok","files_cha`,
+			wantErr: true,
+		},
 		{
 			name: "multiple JSON objects picks last",
 			output: `{"status":"ERROR","feedback":"first","files_changed":[],"summary":"first"}
@@ -193,6 +298,196 @@ some log output
 	}
 }
 
+func TestParseSignal_MessyOutputs(t *testing.T) {
+	const sig = `{"status":"PASS","feedback":"ok","files_changed":["a.go"],"summary":"done"}`
+	tests := []struct {
+		name        string
+		output      string
+		wantStatus  Status
+		wantSummary string
+		wantErr     string // Substring of the error; "" expects success.
+	}{
+		{
+			name:        "prose intro then json fence",
+			output:      "Here is the signal:\n\n```json\n" + sig + "\n```\n",
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name:        "fence without language tag and closing remark",
+			output:      "```\n" + sig + "\n```\nLet me know if you need anything else!",
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name:        "tilde fence",
+			output:      "~~~json\n" + sig + "\n~~~",
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name:        "inline fence on one line",
+			output:      "Signal: ```json " + sig + "```",
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name: "code snippet with braces before the signal",
+			output: "I changed the handler:\n```go\nfunc f() { return }\nm := map[string]int{\"a\": 1}\n```\n" +
+				sig,
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name:        "signal restated at the end wins",
+			output:      `{"status":"NEEDS_WORK","feedback":"draft","files_changed":[],"summary":"draft"}` + "\nOn reflection:\n" + sig,
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name:        "later object with unrecognized status loses to a recognized one",
+			output:      sig + "\nExample of the format: " + `{"status":"<PASS|NEEDS_WORK>","feedback":"...","summary":"..."}`,
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name:        "signal nested in a wrapper object",
+			output:      `{"result":` + sig + `,"cost_usd":0.01}`,
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name:        "pretty-printed inside fence with CRLF",
+			output:      "```json\r\n{\r\n  \"status\": \"needs-work\",\r\n  \"feedback\": \"fix it\",\r\n  \"summary\": \"issues\"\r\n}\r\n```\r\n",
+			wantStatus:  StatusNeedsWork,
+			wantSummary: "issues",
+		},
+		{
+			name:        "truncated restatement after a complete signal",
+			output:      sig + "\nTo recap: {\"status\":\"PASS\",\"feedback\":\"o",
+			wantStatus:  StatusPass,
+			wantSummary: "done",
+		},
+		{
+			name:    "only an unrecognized status",
+			output:  "Result:\n" + `{"status":"MAYBE","feedback":"hm","summary":"unsure"}`,
+			wantErr: `invalid status value: "MAYBE"`,
+		},
+		{
+			name:    "prose only includes the start of the output",
+			output:  "I could not finish the task because the tests kept timing out." + strings.Repeat(" more", 100),
+			wantErr: "I could not finish the task",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given messy provider output
+			// When ParseSignal is called
+			got, err := ParseSignal(tt.output)
+
+			// Then the signal is found, or the error explains why not
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Status != tt.wantStatus || got.Summary != tt.wantSummary {
+				t.Errorf("got %q/%q, want %q/%q", got.Status, got.Summary, tt.wantStatus, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestParseSignal_LargeTranscript(t *testing.T) {
+	// Given a long transcript: an echoed earlier signal, thousands of event
+	// lines, and an unterminated deeply nested object before the final signal
+	var b strings.Builder
+	b.WriteString(`{"status":"NEEDS_WORK","feedback":"old","files_changed":[],"summary":"earlier"}` + "\n")
+	for i := range 5000 {
+		fmt.Fprintf(&b, `{"type":"tool_use","id":%d,"input":{"path":"f%d.go"}}`+"\n", i, i)
+	}
+	b.WriteString(strings.Repeat(`{"a":`, 20000) + "\n")
+	b.WriteString(`{"status":"PASS","feedback":"ok","files_changed":[],"summary":"final"}`)
+
+	// When ParseSignal is called
+	got, err := ParseSignal(b.String())
+
+	// Then the final signal is returned
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != StatusPass || got.Summary != "final" {
+		t.Errorf("got %q/%q, want PASS/final", got.Status, got.Summary)
+	}
+}
+
+func TestParseSignal_SchemaVersions(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		wantVersion int
+		wantStatus  Status
+		wantReason  string
+	}{
+		{
+			name:       "unversioned signal without reason",
+			output:     `{"status":"PASS","feedback":"ok","files_changed":[],"summary":"done"}`,
+			wantStatus: StatusPass,
+		},
+		{
+			name:       "unversioned SKIP without reason",
+			output:     `{"status":"SKIP","feedback":"nothing to do","files_changed":[],"summary":"skipped"}`,
+			wantStatus: StatusSkip,
+		},
+		{
+			name:        "current version SKIP with reason",
+			output:      `{"schema_version":1,"status":"SKIP","reason":"no frontend files","feedback":"nothing to do","files_changed":[],"summary":"skipped"}`,
+			wantVersion: 1,
+			wantStatus:  StatusSkip,
+			wantReason:  "no frontend files",
+		},
+		{
+			name:       "unversioned signal with reason",
+			output:     `{"status":"SKIP","reason":"docs only","feedback":"nothing to do","files_changed":[],"summary":"skipped"}`,
+			wantStatus: StatusSkip,
+			wantReason: "docs only",
+		},
+		{
+			name:        "future version with unknown fields",
+			output:      `{"schema_version":3,"status":"PASS","feedback":"ok","files_changed":[],"summary":"done","confidence":0.9,"artifacts":[{"path":"a.go"}],"meta":{"model":"x"}}`,
+			wantVersion: 3,
+			wantStatus:  StatusPass,
+		},
+		{
+			name:        "future version with unknown fields and reason",
+			output:      "Done.\n```json\n" + `{"schema_version":2,"status":"skip","reason":"already migrated","feedback":"n/a","files_changed":[],"summary":"skipped","tags":["db"]}` + "\n```",
+			wantVersion: 2,
+			wantStatus:  StatusSkip,
+			wantReason:  "already migrated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a signal from an older, current, or newer schema
+			// When ParseSignal is called
+			got, err := ParseSignal(tt.output)
+
+			// Then it parses, keeping the fields this build knows
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.SchemaVersion != tt.wantVersion || got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("got version %d, status %q, reason %q; want %d, %q, %q",
+					got.SchemaVersion, got.Status, got.Reason, tt.wantVersion, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
 // --- Error type tests ---
 
 func TestErrorTypes(t *testing.T) {
@@ -212,6 +507,53 @@ func TestErrorTypes(t *testing.T) {
 		}
 	})
 
+	t.Run("SignalParseError includes short output", func(t *testing.T) {
+		// Given a SignalParseError with short raw output
+		err := &SignalParseError{Reason: "no signal", Output: "hello"}
+
+		// When Error() is called
+		// Then the whole output is quoted
+		want := `provider: signal parse: no signal (output: "hello")`
+		if err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("SignalParseError excerpts long output", func(t *testing.T) {
+		// Given a SignalParseError with output longer than two excerpts
+		out := strings.Repeat("a", 200) + strings.Repeat("m", 100) + strings.Repeat("z", 200)
+		err := &SignalParseError{Reason: "no signal", Output: out}
+
+		// When Error() is called
+		msg := err.Error()
+
+		// Then the first and last 200 chars appear but the middle does not
+		if !strings.Contains(msg, strings.Repeat("a", 200)) || !strings.Contains(msg, strings.Repeat("z", 200)) {
+			t.Errorf("Error() missing head/tail excerpt: %q", msg)
+		}
+		if strings.Contains(msg, "m") {
+			t.Errorf("Error() should omit the middle of the output: %q", msg)
+		}
+	})
+
+	t.Run("SignalParseError excerpts keep multi-byte characters whole", func(t *testing.T) {
+		// Given long non-ASCII output whose excerpt cuts fall inside a character
+		out := "x" + strings.Repeat("é", 300) + "y"
+		err := &SignalParseError{Reason: "no signal", Output: out}
+
+		// When Error() is called
+		msg := err.Error()
+
+		// Then the excerpts stop short of the split characters instead of
+		// quoting their stray bytes
+		if strings.Contains(msg, `\x`) {
+			t.Errorf("Error() quotes a split character: %q", msg)
+		}
+		if !strings.Contains(msg, `head: "x`+strings.Repeat("é", 99)+`"`) || !strings.Contains(msg, `tail: "`+strings.Repeat("é", 99)+`y"`) {
+			t.Errorf("Error() head/tail = %q, want 99 whole characters each", msg)
+		}
+	})
+
 	t.Run("ProviderError", func(t *testing.T) {
 		// Given a ProviderError wrapping a cause
 		cause := errors.New("connection refused")
@@ -11,6 +11,10 @@ import (
 type Executor interface {
 	Name() string
 	Execute(ctx context.Context, prompt, workDir string) (Result, error)
+	// HealthCheck reports whether the provider can run phases, before any
+	// worktree or worklog is created. Providers with nothing to check embed
+	// NoHealthCheck.
+	HealthCheck(ctx context.Context) error
 }
 
 // Factory creates a provider instance.
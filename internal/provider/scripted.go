@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// phaseMarkerPrefix starts the comment line that PhaseMarker emits.
+const phaseMarkerPrefix = "<!-- capsule-phase: "
+
+// PhaseMarker returns the line the orchestrator prepends to a composed prompt
+// so that providers can tell which phase they are executing.
+func PhaseMarker(phase string) string {
+	return phaseMarkerPrefix + phase + " -->\n"
+}
+
+// PhaseFromPrompt returns the phase named by a PhaseMarker on the first line
+// of prompt. Returns false when the prompt carries no marker.
+func PhaseFromPrompt(prompt string) (string, bool) {
+	line, _, _ := strings.Cut(prompt, "\n")
+	rest, ok := strings.CutPrefix(line, phaseMarkerPrefix)
+	if !ok {
+		return "", false
+	}
+	phase, ok := strings.CutSuffix(rest, " -->")
+	if !ok || phase == "" {
+		return "", false
+	}
+	return phase, true
+}
+
+// Script is the canned input for a ScriptedProvider. YAML and JSON are both
+// accepted.
+type Script struct {
+	// Phases maps a phase name to its responses, one per call. The last
+	// response repeats once the list is exhausted.
+	Phases map[string][]ScriptStep `yaml:"phases"`
+	// Sequence holds responses by call order for prompts without a phase marker.
+	Sequence []ScriptStep `yaml:"sequence"`
+}
+
+// ScriptStep is one canned provider response.
+type ScriptStep struct {
+	Status       Status            `yaml:"status"` // Defaults to PASS.
+	Feedback     string            `yaml:"feedback"`
+	Summary      string            `yaml:"summary"`
+	Reason       string            `yaml:"reason"`        // Why a SKIP step skipped.
+	FilesChanged []string          `yaml:"files_changed"` // Defaults to the names in Files.
+	Findings     []Finding         `yaml:"findings"`
+	Files        map[string]string `yaml:"files"`     // Written relative to the work dir before responding.
+	Commands     []string          `yaml:"commands"`  // Run with sh -c (cmd /C on Windows) in the work dir after Files are written.
+	Output       string            `yaml:"output"`    // Raw output; replaces the generated signal when set.
+	ExitCode     int               `yaml:"exit_code"` // Reported exit code.
+	Delay        time.Duration     `yaml:"delay"`     // Wait before responding, e.g. "2s", to mimic a slow provider.
+}
+
+// Verify ScriptedProvider satisfies Executor at compile time.
+var _ Executor = (*ScriptedProvider)(nil)
+
+// ScriptedProvider replays canned responses from a Script instead of calling
+// an AI CLI, so a pipeline can run end to end offline. Phases are matched by
+// the PhaseMarker in the prompt; phases missing from the script get a PASS
+// signal and a warning.
+type ScriptedProvider struct {
+	NoHealthCheck // Offline: there is no CLI to check.
+
+	script Script
+	warn   io.Writer
+
+	mu    sync.Mutex
+	calls map[string]int // Calls per phase.
+	seq   int            // Calls without a phase marker.
+}
+
+// NewScriptedProvider loads the script at path.
+func NewScriptedProvider(path string) (*ScriptedProvider, error) {
+	if path == "" {
+		return nil, fmt.Errorf("provider: scripted: no script path configured (set runtime.script)")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("provider: scripted: reading script: %w", err)
+	}
+	var script Script
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&script); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("provider: scripted: parsing %s: %w", path, err)
+	}
+	return &ScriptedProvider{script: script, warn: os.Stderr, calls: make(map[string]int)}, nil
+}
+
+// Name returns "scripted".
+func (p *ScriptedProvider) Name() string { return "scripted" }
+
+// Execute returns the next scripted response for the prompt's phase, after
+// writing the step's files and running its commands in workDir.
+func (p *ScriptedProvider) Execute(ctx context.Context, prompt, workDir string) (Result, error) {
+	start := time.Now()
+	phase, step := p.next(prompt)
+
+	if step.Delay > 0 {
+		t := time.NewTimer(step.Delay)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return Result{Duration: time.Since(start)}, fmt.Errorf("provider: scripted: %s: %w", phase, ctx.Err())
+		}
+	}
+
+	for name, content := range step.Files {
+		if !filepath.IsLocal(name) {
+			return Result{}, fmt.Errorf("provider: scripted: %s: file %q escapes the work dir", phase, name)
+		}
+		path := filepath.Join(workDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return Result{}, fmt.Errorf("provider: scripted: %s: %w", phase, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return Result{}, fmt.Errorf("provider: scripted: %s: %w", phase, err)
+		}
+	}
+
+	var log bytes.Buffer
+	for _, command := range step.Commands {
+		name, args := ShellCommand(runtime.GOOS, command)
+		cmd := exec.CommandContext(ctx, name, args...)
+		cmd.Dir = workDir
+		cmd.Stdout = &log
+		cmd.Stderr = &log
+		if err := cmd.Run(); err != nil {
+			return Result{Output: log.String(), Duration: time.Since(start)},
+				fmt.Errorf("provider: scripted: %s: command %q: %w", phase, command, err)
+		}
+	}
+
+	output := step.Output
+	if output == "" {
+		status := step.Status
+		if status == "" {
+			status = StatusPass
+		}
+		// Signals need non-empty feedback and summary to parse.
+		feedback, summary := step.Feedback, step.Summary
+		if feedback == "" {
+			feedback = fmt.Sprintf("Scripted %s response.", status)
+		}
+		if summary == "" {
+			summary = "Scripted " + phase
+		}
+		files := step.FilesChanged
+		if files == nil {
+			files = slices.Sorted(maps.Keys(step.Files))
+		}
+		if files == nil {
+			files = []string{}
+		}
+		sig, err := json.Marshal(Signal{
+			SchemaVersion: SignalSchemaVersion,
+			Status:        status,
+			Feedback:      feedback,
+			FilesChanged:  files,
+			Summary:       summary,
+			Reason:        step.Reason,
+			Findings:      step.Findings,
+		})
+		if err != nil {
+			return Result{}, fmt.Errorf("provider: scripted: %s: %w", phase, err)
+		}
+		output = log.String() + string(sig) + "\n"
+	}
+
+	return Result{Output: output, ExitCode: step.ExitCode, Duration: time.Since(start)}, nil
+}
+
+// next selects the response for prompt and advances the call counters.
+// The returned name identifies the phase (or call number) for messages.
+func (p *ScriptedProvider) next(prompt string) (string, ScriptStep) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if phase, ok := PhaseFromPrompt(prompt); ok {
+		steps := p.script.Phases[phase]
+		if len(steps) == 0 {
+			_, _ = fmt.Fprintf(p.warn, "provider: scripted: warning: no script for phase %q; returning PASS\n", phase)
+			return phase, ScriptStep{Feedback: "No scripted response; passing by default."}
+		}
+		n := p.calls[phase]
+		p.calls[phase]++
+		return phase, steps[min(n, len(steps)-1)]
+	}
+
+	name := fmt.Sprintf("call %d", p.seq+1)
+	if p.seq >= len(p.script.Sequence) {
+		_, _ = fmt.Fprintf(p.warn, "provider: scripted: warning: no script for %s; returning PASS\n", name)
+		p.seq++
+		return name, ScriptStep{Feedback: "No scripted response; passing by default."}
+	}
+	step := p.script.Sequence[p.seq]
+	p.seq++
+	return name, step
+}
@@ -0,0 +1,273 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScript writes content to a script file in a temp dir and returns its path.
+func writeScript(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPhaseFromPrompt(t *testing.T) {
+	tests := []struct {
+		name      string
+		prompt    string
+		wantPhase string
+		wantOK    bool
+	}{
+		{name: "marker", prompt: PhaseMarker("execute") + "# Execute Phase", wantPhase: "execute", wantOK: true},
+		{name: "no marker", prompt: "# Execute Phase", wantOK: false},
+		{name: "empty phase", prompt: PhaseMarker("") + "body", wantOK: false},
+		{name: "marker not on first line", prompt: "intro\n" + PhaseMarker("execute"), wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := PhaseFromPrompt(tt.prompt)
+			if got != tt.wantPhase || ok != tt.wantOK {
+				t.Errorf("PhaseFromPrompt() = (%q, %v), want (%q, %v)", got, ok, tt.wantPhase, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewScriptedProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    func(t *testing.T) string
+		wantErr string
+	}{
+		{name: "empty path", path: func(*testing.T) string { return "" }, wantErr: "no script path"},
+		{name: "missing file", path: func(t *testing.T) string { return filepath.Join(t.TempDir(), "nope.yaml") }, wantErr: "reading script"},
+		{name: "unknown field", path: func(t *testing.T) string { return writeScript(t, "phasez: {}\n") }, wantErr: "parsing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewScriptedProvider(tt.path(t))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewScriptedProvider() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScriptedProvider_Execute(t *testing.T) {
+	// Given a script with per-phase responses and a call-order sequence
+	path := writeScript(t, `
+phases:
+  execute:
+    - status: NEEDS_WORK
+      feedback: first try
+    - status: PASS
+      summary: second try
+      files:
+        src/out.txt: hello
+      commands:
+        - echo ran >> src/log.txt
+sequence:
+  - status: ERROR
+    feedback: unmarked
+`)
+	p, err := NewScriptedProvider(path)
+	if err != nil {
+		t.Fatalf("NewScriptedProvider: %v", err)
+	}
+	var warn bytes.Buffer
+	p.warn = &warn
+	workDir := t.TempDir()
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		prompt     string
+		wantStatus Status
+		wantText   string // substring of Feedback or Summary
+	}{
+		{name: "first phase call", prompt: PhaseMarker("execute") + "p", wantStatus: StatusNeedsWork, wantText: "first try"},
+		{name: "second phase call", prompt: PhaseMarker("execute") + "p", wantStatus: StatusPass, wantText: "second try"},
+		{name: "last response repeats", prompt: PhaseMarker("execute") + "p", wantStatus: StatusPass, wantText: "second try"},
+		{name: "unknown phase passes", prompt: PhaseMarker("sign-off") + "p", wantStatus: StatusPass, wantText: "passing by default"},
+		{name: "unmarked uses sequence", prompt: "no marker", wantStatus: StatusError, wantText: "unmarked"},
+		{name: "sequence exhausted passes", prompt: "no marker", wantStatus: StatusPass, wantText: "passing by default"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When Execute is called
+			result, err := p.Execute(ctx, tt.prompt, workDir)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+
+			// Then the output carries the scripted signal
+			sig, err := result.ParseSignal()
+			if err != nil {
+				t.Fatalf("ParseSignal: %v", err)
+			}
+			if sig.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", sig.Status, tt.wantStatus)
+			}
+			if !strings.Contains(sig.Feedback+sig.Summary, tt.wantText) {
+				t.Errorf("signal = %+v, want text %q", sig, tt.wantText)
+			}
+		})
+	}
+
+	// Then files were written and commands ran once per PASS execute call
+	if data, err := os.ReadFile(filepath.Join(workDir, "src", "out.txt")); err != nil || string(data) != "hello" {
+		t.Errorf("src/out.txt = %q, %v; want %q", data, err, "hello")
+	}
+	if data, _ := os.ReadFile(filepath.Join(workDir, "src", "log.txt")); strings.Count(string(data), "ran") != 2 {
+		t.Errorf("src/log.txt = %q, want 2 runs", data)
+	}
+	// And unscripted calls were warned about
+	if got := strings.Count(warn.String(), "warning"); got != 2 {
+		t.Errorf("warnings = %d, want 2:\n%s", got, warn.String())
+	}
+}
+
+func TestScriptedProvider_ExecuteFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{
+			name:    "file outside work dir",
+			script:  "phases:\n  execute:\n    - files:\n        ../escape.txt: x\n",
+			wantErr: "escapes the work dir",
+		},
+		{
+			name:    "failing command",
+			script:  "phases:\n  execute:\n    - commands: [\"exit 3\"]\n",
+			wantErr: `command "exit 3"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a script whose step cannot be applied
+			p, err := NewScriptedProvider(writeScript(t, tt.script))
+			if err != nil {
+				t.Fatalf("NewScriptedProvider: %v", err)
+			}
+
+			// When Execute is called
+			_, err = p.Execute(context.Background(), PhaseMarker("execute"), t.TempDir())
+
+			// Then it fails with a descriptive error
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Execute() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScriptedProvider_FilesChangedDefaultsToFiles(t *testing.T) {
+	// Given a step that writes files without listing files_changed
+	p, err := NewScriptedProvider(writeScript(t, "phases:\n  execute:\n    - files:\n        src/b.go: b\n        src/a.go: a\n"))
+	if err != nil {
+		t.Fatalf("NewScriptedProvider: %v", err)
+	}
+
+	// When Execute is called
+	result, err := p.Execute(context.Background(), PhaseMarker("execute"), t.TempDir())
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	// Then the signal reports the written files
+	sig, err := result.ParseSignal()
+	if err != nil {
+		t.Fatalf("ParseSignal: %v", err)
+	}
+	if want := []string{"src/a.go", "src/b.go"}; !slices.Equal(sig.FilesChanged, want) {
+		t.Errorf("FilesChanged = %v, want %v", sig.FilesChanged, want)
+	}
+}
+
+func TestScriptedProvider_SkipReason(t *testing.T) {
+	// Given a step that skips with a reason
+	p, err := NewScriptedProvider(writeScript(t, "phases:\n  execute:\n    - status: SKIP\n      reason: nothing to do\n"))
+	if err != nil {
+		t.Fatalf("NewScriptedProvider: %v", err)
+	}
+
+	// When Execute is called
+	result, err := p.Execute(context.Background(), PhaseMarker("execute"), t.TempDir())
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	// Then the signal carries the reason and the current schema version
+	sig, err := result.ParseSignal()
+	if err != nil {
+		t.Fatalf("ParseSignal: %v", err)
+	}
+	if sig.Status != StatusSkip || sig.Reason != "nothing to do" || sig.SchemaVersion != SignalSchemaVersion {
+		t.Errorf("signal = %+v, want SKIP (nothing to do) at version %d", sig, SignalSchemaVersion)
+	}
+}
+
+func TestScriptedProvider_Delay(t *testing.T) {
+	// Given a step with a delay
+	p, err := NewScriptedProvider(writeScript(t, "phases:\n  execute:\n    - delay: 50ms\n"))
+	if err != nil {
+		t.Fatalf("NewScriptedProvider: %v", err)
+	}
+
+	// When Execute is called
+	result, err := p.Execute(context.Background(), PhaseMarker("execute"), t.TempDir())
+
+	// Then it responds after the delay
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Duration < 50*time.Millisecond {
+		t.Errorf("Duration = %v, want at least 50ms", result.Duration)
+	}
+
+	// When the context is cancelled during the delay
+	p, err = NewScriptedProvider(writeScript(t, "phases:\n  execute:\n    - delay: 1h\n"))
+	if err != nil {
+		t.Fatalf("NewScriptedProvider: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = p.Execute(ctx, PhaseMarker("execute"), t.TempDir())
+
+	// Then it stops waiting with the context's error
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Execute() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestScriptedProvider_DemoScript(t *testing.T) {
+	// Given the demo script shipped with the demo-brownfield template
+	p, err := NewScriptedProvider(filepath.Join("..", "..", "templates", "demo-brownfield", "scripted.yaml"))
+	if err != nil {
+		t.Fatalf("NewScriptedProvider: %v", err)
+	}
+
+	// Then it scripts every default phase
+	for _, phase := range []string{"test-writer", "test-review", "execute", "execute-review", "sign-off", "merge"} {
+		if len(p.script.Phases[phase]) == 0 {
+			t.Errorf("demo script has no response for phase %q", phase)
+		}
+	}
+}
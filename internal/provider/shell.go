@@ -0,0 +1,9 @@
+package provider
+
+// ShellCommand returns the shell invocation that runs command on goos.
+func ShellCommand(goos, command string) (string, []string) {
+	if goos == "windows" {
+		return "cmd", []string{"/C", command}
+	}
+	return "sh", []string{"-c", command}
+}
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestShellCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{goos: "linux", wantName: "sh", wantArgs: []string{"-c", "go test ./..."}},
+		{goos: "darwin", wantName: "sh", wantArgs: []string{"-c", "go test ./..."}},
+		{goos: "windows", wantName: "cmd", wantArgs: []string{"/C", "go test ./..."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			// Given a command
+			// When the shell invocation is built for the OS
+			name, args := ShellCommand(tt.goos, "go test ./...")
+
+			// Then it uses that OS's shell
+			if name != tt.wantName || !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("ShellCommand(%q) = %s %v, want %s %v", tt.goos, name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ProgressFunc receives the progress messages a streaming provider reports
+// while a phase runs.
+type ProgressFunc func(message string)
+
+// maxProgressLen caps a progress message, in runes, so one long line of
+// model output does not flood a status line.
+const maxProgressLen = 120
+
+// streamLine is one NDJSON line from a streaming CLI. Capsule's own progress
+// lines look like {"event":"progress","message":"..."}; Claude's stream-json
+// lines carry a type and, for assistant turns, a message object instead.
+type streamLine struct {
+	Event   string          `json:"event"`
+	Type    string          `json:"type"`
+	Message json.RawMessage `json:"message"`
+}
+
+// assistantMessage is the message object of a Claude stream-json assistant line.
+type assistantMessage struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+		Name string `json:"name"`
+	} `json:"content"`
+}
+
+// parseStreamLine classifies one line of a streaming CLI's stdout. progress
+// is the message to report, "" for none. keep reports whether the line
+// belongs to the final output, which is what the signal is parsed from:
+// progress events and Claude's intermediate turns are dropped, while the
+// signal JSON, Claude's result envelope, and plain text are kept. Plain text
+// is also reported as progress.
+func parseStreamLine(line string) (progress string, keep bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return "", true
+	}
+	if !strings.HasPrefix(trimmed, "{") {
+		return truncateProgress(trimmed), true
+	}
+	var sl streamLine
+	if err := json.Unmarshal([]byte(trimmed), &sl); err != nil {
+		return "", true // Partial or pretty-printed JSON; leave it to the signal parser.
+	}
+	switch {
+	case sl.Event == "progress":
+		var msg string
+		_ = json.Unmarshal(sl.Message, &msg)
+		return truncateProgress(msg), false
+	case sl.Type == "assistant":
+		return assistantProgress(sl.Message), false
+	case sl.Type == "system" || sl.Type == "user":
+		return "", false
+	}
+	return "", true
+}
+
+// assistantProgress summarizes a Claude assistant turn: the first line of
+// its text, or the tool it calls.
+func assistantProgress(raw json.RawMessage) string {
+	var msg assistantMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return ""
+	}
+	for _, c := range msg.Content {
+		switch c.Type {
+		case "text":
+			for line := range strings.Lines(c.Text) {
+				if line = strings.TrimSpace(line); line != "" {
+					return truncateProgress(line)
+				}
+			}
+		case "tool_use":
+			if c.Name != "" {
+				return "using " + c.Name
+			}
+		}
+	}
+	return ""
+}
+
+// truncateProgress shortens s to maxProgressLen runes.
+func truncateProgress(s string) string {
+	r := []rune(s)
+	if len(r) <= maxProgressLen {
+		return s
+	}
+	return string(r[:maxProgressLen-1]) + "…"
+}
+
+// progressWriter collects a streaming CLI's stdout. Each complete line is
+// reported to onProgress as it arrives and kept in the final output unless
+// parseStreamLine drops it.
+type progressWriter struct {
+	onProgress ProgressFunc
+	strip      bool // Strip ANSI escape codes before parsing a line.
+	out        bytes.Buffer
+	partial    []byte
+}
+
+// Write buffers b and handles every line it completes.
+func (w *progressWriter) Write(b []byte) (int, error) {
+	w.partial = append(w.partial, b...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(string(w.partial[:i+1]))
+		w.partial = w.partial[i+1:]
+	}
+	return len(b), nil
+}
+
+// line handles one line, trailing newline included.
+func (w *progressWriter) line(raw string) {
+	text := strings.TrimRight(raw, "\r\n")
+	if w.strip {
+		text = stripANSI(text)
+	}
+	msg, keep := parseStreamLine(text)
+	if keep {
+		w.out.WriteString(raw)
+	}
+	if msg != "" && w.onProgress != nil {
+		w.onProgress(msg)
+	}
+}
+
+// String flushes a final line without a newline and returns the kept output.
+func (w *progressWriter) String() string {
+	if len(w.partial) > 0 {
+		w.line(string(w.partial))
+		w.partial = nil
+	}
+	return w.out.String()
+}
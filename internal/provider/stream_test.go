@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStreamLine(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		wantProgress string
+		wantKeep     bool
+	}{
+		{name: "blank line", line: "  ", wantKeep: true},
+		{name: "plain text is progress and output", line: "Running tests...", wantProgress: "Running tests...", wantKeep: true},
+		{name: "progress event", line: `{"event":"progress","message":"half done"}`, wantProgress: "half done"},
+		{name: "signal is kept", line: `{"status":"PASS","feedback":"ok","summary":"ok"}`, wantKeep: true},
+		{name: "claude result envelope is kept", line: `{"type":"result","result":"x"}`, wantKeep: true},
+		{name: "claude system line is dropped", line: `{"type":"system","subtype":"init"}`},
+		{name: "claude assistant text", line: `{"type":"assistant","message":{"content":[{"type":"text","text":"\n  First line\nSecond"}]}}`, wantProgress: "First line"},
+		{name: "claude tool call", line: `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash"}]}}`, wantProgress: "using Bash"},
+		{name: "unparseable JSON is kept for the signal parser", line: `{"status": "PASS",`, wantKeep: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When the line is parsed
+			progress, keep := parseStreamLine(tt.line)
+
+			// Then it is reported and kept as expected
+			if progress != tt.wantProgress || keep != tt.wantKeep {
+				t.Errorf("parseStreamLine(%q) = (%q, %v), want (%q, %v)", tt.line, progress, keep, tt.wantProgress, tt.wantKeep)
+			}
+		})
+	}
+}
+
+func TestProgressWriter_SplitsWrites(t *testing.T) {
+	// Given a writer fed lines split across writes, the last without a newline
+	var got []string
+	w := &progressWriter{onProgress: func(msg string) { got = append(got, msg) }}
+	for _, chunk := range []string{`{"event":"progr`, `ess","message":"one"}` + "\nplain ", "text\n", `{"status":"PASS"}`} {
+		_, _ = w.Write([]byte(chunk))
+	}
+
+	// When the output is read
+	out := w.String()
+
+	// Then each line was handled once, whole
+	if strings.Join(got, "|") != "one|plain text" {
+		t.Errorf("progress = %q, want [one plain text]", got)
+	}
+	if out != "plain text\n{\"status\":\"PASS\"}" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestTruncateProgress(t *testing.T) {
+	long := strings.Repeat("é", maxProgressLen+10)
+	if got := []rune(truncateProgress(long)); len(got) != maxProgressLen || got[len(got)-1] != '…' {
+		t.Errorf("truncateProgress kept %d runes, want %d ending in …", len(got), maxProgressLen)
+	}
+	if got := truncateProgress("short"); got != "short" {
+		t.Errorf("truncateProgress(short) = %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// errorOutputBytes is how much of a failed CLI's stdout ProviderError keeps.
+const errorOutputBytes = 2048
+
+// transientPatterns maps text that CLIs print for failures worth retrying
+// to the reason shown while waiting. They are matched case-insensitively
+// against the error and the CLI's output, in order.
+var transientPatterns = []struct {
+	text   string
+	reason string
+}{
+	{"rate limit", "rate limited"},
+	{"rate_limit", "rate limited"},
+	{"too many requests", "rate limited"},
+	{"error: 429", "rate limited"},
+	{"status 429", "rate limited"},
+	{"overloaded", "overloaded"},
+	{"error: 529", "overloaded"},
+	{"error: 503", "service unavailable"},
+	{"status 503", "service unavailable"},
+	{"service unavailable", "service unavailable"},
+	{"econnreset", "connection reset"},
+	{"connection reset", "connection reset"},
+	{"socket hang up", "connection reset"},
+	{"econnrefused", "connection refused"},
+	{"etimedout", "network timeout"},
+	{"request timed out", "network timeout"},
+	{"connection error", "network error"},
+	{"network error", "network error"},
+}
+
+// permanentPatterns mark failures that look transient but will not clear in
+// time for a retry, such as a plan's usage limit, which resets in hours.
+var permanentPatterns = []string{"usage limit"}
+
+// IsTransient reports whether err is a provider failure that is likely to
+// succeed if the same call is retried shortly: a rate limit, an overloaded
+// API, or a dropped connection. Cancellation and a call that used up its
+// whole timeout are not transient.
+func IsTransient(err error) bool {
+	return TransientReason(err) != ""
+}
+
+// TransientReason returns a short description of why err is transient,
+// such as "rate limited", or "" when it is not.
+func TransientReason(err error) string {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ""
+	}
+	var te *TimeoutError
+	if errors.As(err, &te) {
+		return ""
+	}
+	text := strings.ToLower(err.Error())
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		text += "\n" + strings.ToLower(pe.Output)
+	}
+	for _, p := range permanentPatterns {
+		if strings.Contains(text, p) {
+			return ""
+		}
+	}
+	for _, p := range transientPatterns {
+		if strings.Contains(text, p.text) {
+			return p.reason
+		}
+	}
+	return ""
+}
+
+// tail returns the last n bytes of s.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
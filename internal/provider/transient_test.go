@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTransientReason(t *testing.T) {
+	exit := errors.New("exit status 1")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "rate limit on stderr", err: &ProviderError{Provider: "claude", Err: fmt.Errorf("%w: API Error: 429 rate_limit_error", exit)}, want: "rate limited"},
+		{name: "overloaded on stdout", err: &ProviderError{Provider: "claude", Err: fmt.Errorf("%w: ", exit), Output: `{"is_error":true,"result":"API Error: 529 {\"type\":\"overloaded_error\"}"}`}, want: "overloaded"},
+		{name: "connection reset", err: &ProviderError{Provider: "codex", Err: fmt.Errorf("%w: read tcp: ECONNRESET", exit)}, want: "connection reset"},
+		{name: "request timed out", err: &ProviderError{Provider: "claude", Err: fmt.Errorf("%w: Request timed out.", exit)}, want: "network timeout"},
+		{name: "wrapped by the orchestrator", err: fmt.Errorf("executing execute: %w", &ProviderError{Provider: "claude", Err: errors.New("Too Many Requests")}), want: "rate limited"},
+		{name: "usage limit resets too late", err: &ProviderError{Provider: "claude", Err: exit, Output: "Claude AI usage limit reached|1760000000"}, want: ""},
+		{name: "ordinary failure", err: &ProviderError{Provider: "claude", Err: fmt.Errorf("%w: invalid flag", exit)}, want: ""},
+		{name: "number in output is not a status", err: &ProviderError{Provider: "claude", Err: exit, Output: "edited line 429"}, want: ""},
+		{name: "whole call timed out", err: &TimeoutError{Provider: "claude", Duration: time.Minute}, want: ""},
+		{name: "cancelled", err: fmt.Errorf("rate limit: %w", context.Canceled), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When the provider error is classified
+			got := TransientReason(tt.err)
+
+			// Then only failures a retry can fix are transient
+			if got != tt.want {
+				t.Errorf("TransientReason() = %q, want %q", got, tt.want)
+			}
+			if IsTransient(tt.err) != (tt.want != "") {
+				t.Errorf("IsTransient() = %v, want %v", IsTransient(tt.err), tt.want != "")
+			}
+		})
+	}
+}
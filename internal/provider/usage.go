@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Usage reports the tokens a provider call consumed and its estimated cost.
+// Providers that cannot report usage leave it zero.
+type Usage struct {
+	InputTokens  int     `json:"input_tokens,omitempty"` // Includes cache reads and writes.
+	OutputTokens int     `json:"output_tokens,omitempty"`
+	CostUSD      float64 `json:"cost_usd,omitempty"` // Provider's estimate; zero when unknown.
+	Model        string  `json:"model,omitempty"`    // Model that answered; MixedModels for a sum over several.
+}
+
+// MixedModels is the Model of a sum of usage from different models.
+const MixedModels = "mixed"
+
+// Add returns the sum of u and v. The model is kept when both agree or
+// one is unknown, and is MixedModels otherwise.
+func (u Usage) Add(v Usage) Usage {
+	model := u.Model
+	switch {
+	case model == "":
+		model = v.Model
+	case v.Model != "" && v.Model != model:
+		model = MixedModels
+	}
+	return Usage{
+		InputTokens:  u.InputTokens + v.InputTokens,
+		OutputTokens: u.OutputTokens + v.OutputTokens,
+		CostUSD:      u.CostUSD + v.CostUSD,
+		Model:        model,
+	}
+}
+
+// IsZero reports whether no usage was recorded.
+func (u Usage) IsZero() bool {
+	return u == Usage{}
+}
+
+// String formats u as "1234 in / 567 out tokens, $0.0421", omitting the cost
+// when unknown. Zero usage formats as "".
+func (u Usage) String() string {
+	if u.IsZero() {
+		return ""
+	}
+	s := fmt.Sprintf("%d in / %d out tokens", u.InputTokens, u.OutputTokens)
+	if u.CostUSD > 0 {
+		s += fmt.Sprintf(", $%.4f", u.CostUSD)
+	}
+	return s
+}
+
+// claudeEnvelope is the object Claude Code prints with --output-format json.
+type claudeEnvelope struct {
+	Type         string  `json:"type"`
+	IsError      bool    `json:"is_error"`
+	Result       *string `json:"result"`
+	TotalCostUSD float64 `json:"total_cost_usd"`
+	Usage        struct {
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	} `json:"usage"`
+	ModelUsage map[string]struct {
+		CostUSD float64 `json:"costUSD"`
+	} `json:"modelUsage"`
+}
+
+// model returns the model that cost the most in the envelope's per-model
+// breakdown, so a response that used a small helper model is attributed to
+// the main one. Ties go to the first name in sort order.
+func (e claudeEnvelope) model() string {
+	var (
+		best string
+		cost = -1.0
+	)
+	for name, mu := range e.ModelUsage {
+		if mu.CostUSD > cost || (mu.CostUSD == cost && name < best) {
+			best, cost = name, mu.CostUSD
+		}
+	}
+	return best
+}
+
+// decodeEnvelope decodes Claude Code's result envelope from output: the
+// whole output for --output-format json, or its last line for stream-json.
+func decodeEnvelope(output string) (claudeEnvelope, bool) {
+	output = strings.TrimSpace(output)
+	var env claudeEnvelope
+	if err := json.Unmarshal([]byte(output), &env); err == nil {
+		return env, true
+	}
+	if i := strings.LastIndexByte(output, '\n'); i >= 0 {
+		env = claudeEnvelope{}
+		if err := json.Unmarshal([]byte(output[i+1:]), &env); err == nil {
+			return env, true
+		}
+	}
+	return claudeEnvelope{}, false
+}
+
+// parseClaudeEnvelope unwraps Claude Code's JSON result envelope into the
+// response text and its usage. ok is false when output is not an envelope,
+// in which case callers should use the output as is.
+func parseClaudeEnvelope(output string) (text string, usage Usage, ok bool) {
+	env, decoded := decodeEnvelope(output)
+	if !decoded || env.Type != "result" || env.Result == nil {
+		return "", Usage{}, false
+	}
+	usage = Usage{
+		InputTokens:  env.Usage.InputTokens + env.Usage.CacheCreationInputTokens + env.Usage.CacheReadInputTokens,
+		OutputTokens: env.Usage.OutputTokens,
+		CostUSD:      env.TotalCostUSD,
+		Model:        env.model(),
+	}
+	return *env.Result, usage, true
+}
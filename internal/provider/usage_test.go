@@ -0,0 +1,75 @@
+package provider
+
+import "testing"
+
+func TestParseClaudeEnvelope(t *testing.T) {
+	tests := []struct {
+		name      string
+		output    string
+		wantText  string
+		wantUsage Usage
+		wantOK    bool
+	}{
+		{
+			name:      "result envelope",
+			output:    `{"type":"result","result":"hello","total_cost_usd":0.5,"usage":{"input_tokens":10,"cache_creation_input_tokens":5,"output_tokens":3}}` + "\n",
+			wantText:  "hello",
+			wantUsage: Usage{InputTokens: 15, OutputTokens: 3, CostUSD: 0.5},
+			wantOK:    true,
+		},
+		{
+			name:      "model that cost the most",
+			output:    `{"type":"result","result":"hi","usage":{"output_tokens":1},"modelUsage":{"claude-haiku":{"costUSD":0.01},"claude-sonnet":{"costUSD":0.2}}}`,
+			wantText:  "hi",
+			wantUsage: Usage{OutputTokens: 1, Model: "claude-sonnet"},
+			wantOK:    true,
+		},
+		{name: "plain text", output: "Thinking...\n{\"status\":\"PASS\"}"},
+		{name: "signal object is not an envelope", output: `{"status":"PASS","feedback":"ok","summary":"ok"}`},
+		{name: "envelope without result", output: `{"type":"result","usage":{"input_tokens":1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, usage, ok := parseClaudeEnvelope(tt.output)
+			if ok != tt.wantOK || text != tt.wantText || usage != tt.wantUsage {
+				t.Errorf("parseClaudeEnvelope() = (%q, %+v, %v), want (%q, %+v, %v)",
+					text, usage, ok, tt.wantText, tt.wantUsage, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestUsage_String(t *testing.T) {
+	tests := []struct {
+		usage Usage
+		want  string
+	}{
+		{Usage{}, ""},
+		{Usage{InputTokens: 1234, OutputTokens: 567}, "1234 in / 567 out tokens"},
+		{Usage{InputTokens: 1, OutputTokens: 2, CostUSD: 0.0421}, "1 in / 2 out tokens, $0.0421"},
+	}
+	for _, tt := range tests {
+		if got := tt.usage.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.usage, got, tt.want)
+		}
+	}
+
+	// Add sums every field
+	sum := Usage{InputTokens: 1, OutputTokens: 2, CostUSD: 0.5}.Add(Usage{InputTokens: 10, OutputTokens: 20, CostUSD: 0.25})
+	if sum != (Usage{InputTokens: 11, OutputTokens: 22, CostUSD: 0.75}) {
+		t.Errorf("Add() = %+v", sum)
+	}
+
+	// Add keeps a shared or single known model and marks different ones mixed
+	for _, tt := range []struct{ a, b, want string }{
+		{"opus", "opus", "opus"},
+		{"", "opus", "opus"},
+		{"opus", "", "opus"},
+		{"opus", "sonnet", MixedModels},
+	} {
+		if got := (Usage{Model: tt.a}).Add(Usage{Model: tt.b}).Model; got != tt.want {
+			t.Errorf("Add(%q, %q).Model = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
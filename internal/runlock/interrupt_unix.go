@@ -0,0 +1,11 @@
+//go:build !windows
+
+package runlock
+
+import "os"
+
+// interrupt sends p an interrupt signal, which a pipeline handles by
+// stopping and checkpointing what has finished.
+func interrupt(p *os.Process) error {
+	return p.Signal(os.Interrupt)
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package runlock
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// interrupt ends p and every process it started with taskkill, since
+// Windows cannot send an interrupt to another console process. If taskkill
+// fails, p alone is killed.
+func interrupt(p *os.Process) error {
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(p.Pid)).Run(); err != nil {
+		return p.Kill()
+	}
+	return nil
+}
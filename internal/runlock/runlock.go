@@ -0,0 +1,257 @@
+// Package runlock records which process is running a pipeline for a bead so
+// that another process can ask it to stop.
+//
+// A running pipeline holds <dir>/<name>.lock, a JSON file with its PID,
+// start time, and running phase, where name is the bead ID made safe as a
+// file name by worktree.SafeName. Cancellation is requested by writing
+// <dir>/<name>.cancel, which the holder polls for; a holder that does not
+// respond can be sent an interrupt instead. Locks left behind by processes
+// that are no longer alive are removed when next read.
+package runlock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/smileynet/capsule/internal/worktree"
+)
+
+// DefaultPollInterval is how often holders check for a cancel request and
+// waiters check for the lock to be released.
+const DefaultPollInterval = 250 * time.Millisecond
+
+var (
+	// ErrHeld indicates another live process already holds the lock.
+	ErrHeld = errors.New("runlock: pipeline already running")
+	// ErrTimeout indicates the holder did not release the lock in time.
+	ErrTimeout = errors.New("runlock: timed out waiting for pipeline to stop")
+	// ErrInvalidID indicates the bead ID cannot be used as a file name.
+	ErrInvalidID = errors.New("runlock: invalid bead ID")
+)
+
+// Holder describes the process holding a run lock.
+type Holder struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+	Phase     string    `json:"phase,omitempty"` // Running phase; empty before the first one starts.
+}
+
+// Store manages run locks under a directory.
+type Store struct {
+	dir          string
+	pollInterval time.Duration
+}
+
+// NewStore creates a Store that keeps lock files under dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, pollInterval: DefaultPollInterval}
+}
+
+// Lock is a held run lock. Release it when the pipeline finishes.
+type Lock struct {
+	store  *Store
+	beadID string
+
+	mu       sync.Mutex
+	holder   Holder
+	released bool // Set by Release so a late SetPhase can't recreate the file.
+}
+
+// Acquire takes the run lock for beadID on behalf of the current process.
+// A lock left by a process that is no longer alive is replaced. Returns an
+// error wrapping ErrHeld when a live process holds it.
+func (s *Store) Acquire(beadID string) (*Lock, error) {
+	lockPath, cancelPath, err := s.paths(beadID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("runlock: creating directory: %w", err)
+	}
+
+	holder := Holder{PID: os.Getpid(), StartedAt: time.Now().UTC()}
+	data, err := json.Marshal(holder)
+	if err != nil {
+		return nil, fmt.Errorf("runlock: marshaling: %w", err)
+	}
+
+	// Two tries: the first may find a stale lock, which Holder removes.
+	for range 2 {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			h, running, herr := s.Holder(beadID)
+			if herr != nil {
+				return nil, herr
+			}
+			if running {
+				return nil, fmt.Errorf("%w: %s (pid %d, started %s)",
+					ErrHeld, beadID, h.PID, h.StartedAt.Local().Format(time.DateTime))
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("runlock: creating %s: %w", lockPath, err)
+		}
+		_, werr := f.Write(data)
+		if cerr := f.Close(); werr == nil {
+			werr = cerr
+		}
+		if werr != nil {
+			_ = os.Remove(lockPath)
+			return nil, fmt.Errorf("runlock: writing %s: %w", lockPath, werr)
+		}
+		// A cancel marker from an earlier run must not stop this one.
+		_ = os.Remove(cancelPath)
+		return &Lock{store: s, beadID: beadID, holder: holder}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrHeld, beadID)
+}
+
+// Holder reports the process holding the lock for beadID. running is false
+// when no lock exists; a lock whose process is no longer alive is removed
+// and also reported as not running.
+func (s *Store) Holder(beadID string) (h Holder, running bool, err error) {
+	lockPath, cancelPath, err := s.paths(beadID)
+	if err != nil {
+		return Holder{}, false, err
+	}
+	data, err := os.ReadFile(lockPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return Holder{}, false, nil
+	}
+	if err != nil {
+		return Holder{}, false, fmt.Errorf("runlock: reading %s: %w", lockPath, err)
+	}
+	// An unreadable lock can't name a live holder; treat it as stale.
+	if json.Unmarshal(data, &h) == nil && processAlive(h.PID) {
+		return h, true, nil
+	}
+	if err := os.Remove(lockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Holder{}, false, fmt.Errorf("runlock: removing stale %s: %w", lockPath, err)
+	}
+	_ = os.Remove(cancelPath)
+	return Holder{}, false, nil
+}
+
+// RequestCancel asks the holder of beadID's lock to stop its pipeline.
+func (s *Store) RequestCancel(beadID string) error {
+	_, cancelPath, err := s.paths(beadID)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(cancelPath, nil, 0o644); err != nil {
+		return fmt.Errorf("runlock: writing %s: %w", cancelPath, err)
+	}
+	return nil
+}
+
+// Interrupt sends an interrupt signal to the live holder of beadID's lock,
+// for a holder that does not respond to RequestCancel. It does nothing when
+// no live process holds the lock. Windows cannot deliver the signal, so
+// there the holder and the processes it started are killed instead.
+func (s *Store) Interrupt(beadID string) error {
+	h, running, err := s.Holder(beadID)
+	if err != nil || !running {
+		return err
+	}
+	p, err := os.FindProcess(h.PID)
+	if err != nil {
+		return fmt.Errorf("runlock: finding pid %d: %w", h.PID, err)
+	}
+	if err := interrupt(p); err != nil {
+		return fmt.Errorf("runlock: interrupting pid %d: %w", h.PID, err)
+	}
+	return nil
+}
+
+// Wait blocks until the lock for beadID is released or its holder exits.
+// Returns an error wrapping ErrTimeout if that takes longer than timeout.
+func (s *Store) Wait(beadID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, running, err := s.Holder(beadID)
+		if err != nil {
+			return err
+		}
+		if !running {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: %s after %s", ErrTimeout, beadID, timeout)
+		}
+		time.Sleep(s.pollInterval)
+	}
+}
+
+// Watch calls cancel once another process requests cancellation with
+// RequestCancel. It polls until ctx is done, so run it in a goroutine.
+func (l *Lock) Watch(ctx context.Context, cancel func()) {
+	_, cancelPath, _ := l.store.paths(l.beadID)
+	ticker := time.NewTicker(l.store.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := os.Stat(cancelPath); err == nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// SetPhase records the running phase in the lock file. The file is replaced
+// by rename, so readers never see it half written.
+func (l *Lock) SetPhase(phase string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return nil
+	}
+	lockPath, _, _ := l.store.paths(l.beadID)
+	l.holder.Phase = phase
+	data, err := json.Marshal(l.holder)
+	if err != nil {
+		return fmt.Errorf("runlock: marshaling: %w", err)
+	}
+	tmp := lockPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("runlock: writing %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, lockPath); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("runlock: replacing %s: %w", lockPath, err)
+	}
+	return nil
+}
+
+// Release removes the lock and any pending cancel request.
+func (l *Lock) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.released = true
+	lockPath, cancelPath, _ := l.store.paths(l.beadID)
+	_ = os.Remove(cancelPath)
+	if err := os.Remove(lockPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("runlock: removing %s: %w", lockPath, err)
+	}
+	return nil
+}
+
+// paths returns the lock and cancel marker paths for beadID, named like its
+// worktree so that IDs such as "JIRA/ABC-1" work. It rejects an empty ID.
+func (s *Store) paths(beadID string) (lockPath, cancelPath string, err error) {
+	if beadID == "" {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidID, beadID)
+	}
+	base := filepath.Join(s.dir, worktree.SafeName(beadID))
+	return base + ".lock", base + ".cancel", nil
+}
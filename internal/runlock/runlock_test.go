@@ -0,0 +1,311 @@
+package runlock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s := NewStore(filepath.Join(t.TempDir(), "runs"))
+	s.pollInterval = 5 * time.Millisecond
+	return s
+}
+
+// writeLock writes a lock file for beadID naming pid as its holder.
+func writeLock(t *testing.T, s *Store, beadID string, pid int) {
+	t.Helper()
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(Holder{PID: pid, StartedAt: time.Now()})
+	if err := os.WriteFile(filepath.Join(s.dir, beadID+".lock"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// deadPID returns the PID of a process that has already exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+	p, err := os.StartProcess("/bin/true", []string{"true"}, &os.ProcAttr{})
+	if err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	if _, err := p.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	return p.Pid
+}
+
+func TestAcquire_WritesHolderAndRelease(t *testing.T) {
+	// Given an empty store
+	s := newTestStore(t)
+
+	// When the lock is acquired
+	lock, err := s.Acquire("cap-1")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// Then the current process is the running holder
+	h, running, err := s.Holder("cap-1")
+	if err != nil || !running || h.PID != os.Getpid() {
+		t.Fatalf("Holder() = %+v, %v, %v; want this process running", h, running, err)
+	}
+
+	// And releasing removes the lock
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, running, _ := s.Holder("cap-1"); running {
+		t.Error("lock still held after Release")
+	}
+}
+
+func TestAcquire_HeldByLiveProcess(t *testing.T) {
+	// Given a lock held by this (live) process
+	s := newTestStore(t)
+	if _, err := s.Acquire("cap-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the lock is acquired again
+	_, err := s.Acquire("cap-1")
+
+	// Then it fails naming the holder
+	if !errors.Is(err, ErrHeld) {
+		t.Fatalf("Acquire() error = %v, want ErrHeld", err)
+	}
+}
+
+func TestAcquire_ReplacesStaleLock(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(t *testing.T, s *Store)
+	}{
+		{name: "dead process", write: func(t *testing.T, s *Store) { writeLock(t, s, "cap-1", deadPID(t)) }},
+		{name: "corrupt file", write: func(t *testing.T, s *Store) {
+			if err := os.MkdirAll(s.dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(s.dir, "cap-1.lock"), []byte("{"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a stale lock and a leftover cancel request
+			s := newTestStore(t)
+			tt.write(t, s)
+			if err := s.RequestCancel("cap-1"); err != nil {
+				t.Fatal(err)
+			}
+
+			// When the lock is acquired
+			lock, err := s.Acquire("cap-1")
+
+			// Then the stale lock is replaced and the old cancel request cleared
+			if err != nil {
+				t.Fatalf("Acquire() error = %v", err)
+			}
+			defer func() { _ = lock.Release() }()
+			if _, err := os.Stat(filepath.Join(s.dir, "cap-1.cancel")); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("cancel marker survived Acquire: %v", err)
+			}
+		})
+	}
+}
+
+func TestHolder_RemovesStaleLock(t *testing.T) {
+	// Given a lock left by a process that has exited
+	s := newTestStore(t)
+	writeLock(t, s, "cap-1", deadPID(t))
+
+	// When the holder is read
+	_, running, err := s.Holder("cap-1")
+
+	// Then it is not running and the lock file is gone
+	if err != nil || running {
+		t.Fatalf("Holder() running = %v, err = %v; want not running", running, err)
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, "cap-1.lock")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("stale lock not removed: %v", err)
+	}
+}
+
+func TestWatch_CancelsOnRequest(t *testing.T) {
+	// Given a held lock being watched
+	s := newTestStore(t)
+	lock, err := s.Acquire("cap-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go lock.Watch(ctx, func() {
+		cancel()
+		_ = lock.Release()
+	})
+
+	// When another process requests cancellation and waits
+	if err := s.RequestCancel("cap-1"); err != nil {
+		t.Fatal(err)
+	}
+	err = s.Wait("cap-1", time.Second)
+
+	// Then the holder cancels and releases within the timeout
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("watched context was not cancelled")
+	}
+}
+
+func TestWait_Timeout(t *testing.T) {
+	// Given a held lock nobody releases
+	s := newTestStore(t)
+	if _, err := s.Acquire("cap-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// When waiting with a short timeout
+	err := s.Wait("cap-1", 20*time.Millisecond)
+
+	// Then it times out
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Wait() error = %v, want ErrTimeout", err)
+	}
+}
+
+func TestLock_SetPhase(t *testing.T) {
+	// Given a held lock
+	s := newTestStore(t)
+	lock, err := s.Acquire("cap-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// When the running phase is recorded
+	if err := lock.SetPhase("execute"); err != nil {
+		t.Fatalf("SetPhase() error = %v", err)
+	}
+
+	// Then readers see it alongside the original holder
+	h, running, err := s.Holder("cap-1")
+	if err != nil || !running || h.PID != os.Getpid() || h.Phase != "execute" {
+		t.Fatalf("Holder() = %+v, %v, %v; want this process in phase execute", h, running, err)
+	}
+
+	// And a phase reported after Release does not recreate the lock
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := lock.SetPhase("sign-off"); err != nil {
+		t.Fatalf("SetPhase() after Release error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, "cap-1.lock")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock recreated after Release: %v", err)
+	}
+}
+
+func TestInterrupt_StaleLockIsRemovedSilently(t *testing.T) {
+	// Given a lock left by a process that has exited
+	s := newTestStore(t)
+	writeLock(t, s, "cap-1", deadPID(t))
+
+	// When the holder is interrupted
+	err := s.Interrupt("cap-1")
+
+	// Then nothing is signalled and the stale lock is cleaned up
+	if err != nil {
+		t.Fatalf("Interrupt() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(s.dir, "cap-1.lock")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("stale lock not removed: %v", err)
+	}
+}
+
+func TestInterrupt_SignalsLiveHolder(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping subprocess test in short mode")
+	}
+	// Given a lock held by a live process
+	p, err := os.StartProcess("/bin/sleep", []string{"sleep", "30"}, &os.ProcAttr{})
+	if err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	defer func() { _ = p.Kill() }()
+	s := newTestStore(t)
+	writeLock(t, s, "cap-1", p.Pid)
+
+	// When the holder is interrupted
+	if err := s.Interrupt("cap-1"); err != nil {
+		t.Fatalf("Interrupt() error = %v", err)
+	}
+
+	// Then the process exits and the lock is reported stale
+	if _, err := p.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Wait("cap-1", time.Second); err != nil {
+		t.Errorf("Wait() after interrupt error = %v", err)
+	}
+}
+
+func TestStore_InvalidID(t *testing.T) {
+	s := newTestStore(t)
+	if _, err := s.Acquire(""); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Acquire(\"\") error = %v, want ErrInvalidID", err)
+	}
+	if err := s.RequestCancel(""); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("RequestCancel(\"\") error = %v, want ErrInvalidID", err)
+	}
+}
+
+func TestStore_IDWithSlash(t *testing.T) {
+	for _, id := range []string{"JIRA/ABC-1", "../x", ".."} {
+		t.Run(id, func(t *testing.T) {
+			// Given a bead ID that is not a plain file name
+			s := newTestStore(t)
+
+			// When a lock is acquired and a cancel requested for it
+			l, err := s.Acquire(id)
+			if err != nil {
+				t.Fatalf("Acquire() error = %v", err)
+			}
+			if err := s.RequestCancel(id); err != nil {
+				t.Errorf("RequestCancel() error = %v", err)
+			}
+
+			// Then both files are inside the store's directory
+			entries, err := os.ReadDir(s.dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 2 {
+				t.Errorf("store dir has %d entries, want the lock and cancel marker", len(entries))
+			}
+
+			// And releasing the lock lets it be acquired again
+			if err := l.Release(); err != nil {
+				t.Fatalf("Release() error = %v", err)
+			}
+			l, err = s.Acquire(id)
+			if err != nil {
+				t.Fatalf("Acquire() after release error = %v", err)
+			}
+			_ = l.Release()
+		})
+	}
+}
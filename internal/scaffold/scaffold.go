@@ -0,0 +1,273 @@
+// Package scaffold creates and checks the files a project needs to run
+// capsule: the project config, the prompt templates, the worklog template,
+// and .gitignore entries for the artifacts capsule writes under .capsule/.
+//
+// Init and Check share one manifest, so a project that `capsule init`
+// created always passes `capsule init --check`.
+package scaffold
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrExists is returned by Init when manifest files already exist and
+// overwriting was not requested.
+var ErrExists = errors.New("scaffold: files already exist")
+
+// ConfigPath is where the project config is written, relative to the root.
+const ConfigPath = ".capsule/config.yaml"
+
+// Ignored lists the .gitignore entries for capsule's generated artifacts.
+// The project config is deliberately absent: it is meant to be committed.
+var Ignored = []string{
+	".capsule/worktrees/",
+	".capsule/logs/",
+	".capsule/campaigns/",
+	".capsule/checkpoints/",
+	".capsule/runs/",
+}
+
+// File is one file of the scaffold manifest.
+type File struct {
+	Path string // Slash-separated, relative to the project root.
+	Data []byte
+}
+
+// Manifest lists the files Init writes: config at ConfigPath, every file of
+// prompts under prompts/, and every file of templates under templates/.
+// The directories match those the prompt and worklog loaders overlay on
+// their embedded copies.
+func Manifest(config []byte, prompts, templates fs.FS) ([]File, error) {
+	files := []File{{Path: ConfigPath, Data: config}}
+	for _, src := range []struct {
+		dir  string
+		fsys fs.FS
+	}{{"prompts", prompts}, {"templates", templates}} {
+		err := fs.WalkDir(src.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(src.fsys, name)
+			if err != nil {
+				return err
+			}
+			files = append(files, File{Path: path.Join(src.dir, name), Data: data})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scaffold: reading embedded %s: %w", src.dir, err)
+		}
+	}
+	return files, nil
+}
+
+// DemoIssues is the demo project's bead fixtures file, relative to its
+// root, for `bd import`.
+const DemoIssues = "issues.jsonl"
+
+// demoPaths maps demo template files to where a demo project keeps them.
+// An empty path leaves the file out. The template stores src/go.mod as
+// go.mod.template, since a go.mod would make src a separate module, which
+// go:embed skips.
+var demoPaths = map[string]string{
+	"capsule.yaml":        ConfigPath,
+	"scripted.yaml":       ".capsule/scripted.yaml",
+	"src/go.mod.template": "src/go.mod",
+	"test-fixtures.sh":    "",
+}
+
+// Demo lists the files of a demo project: files, the manifest, followed by
+// every file of the demo template project. A template file replaces the
+// manifest file at the same path, so a template's capsule.yaml replaces the
+// default config.
+func Demo(files []File, project fs.FS) ([]File, error) {
+	var extra []File
+	replaced := make(map[string][]byte)
+	err := fs.WalkDir(project, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		dest, ok := demoPaths[name]
+		if !ok {
+			dest = name
+		}
+		if dest == "" {
+			return nil
+		}
+		data, err := fs.ReadFile(project, name)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(files, func(f File) bool { return f.Path == dest }) {
+			replaced[dest] = data
+			return nil
+		}
+		extra = append(extra, File{Path: dest, Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scaffold: reading demo project: %w", err)
+	}
+
+	out := make([]File, 0, len(files)+len(extra))
+	for _, f := range files {
+		if data, ok := replaced[f.Path]; ok {
+			f.Data = data
+		}
+		out = append(out, f)
+	}
+	return append(out, extra...), nil
+}
+
+// Result reports what Init changed.
+type Result struct {
+	Created     []string // Manifest files that did not exist.
+	Overwritten []string // Manifest files replaced because force was set.
+	Ignored     []string // Entries appended to .gitignore.
+}
+
+// Init writes files under root and adds the Ignored entries that .gitignore
+// lacks. Unless force is set, it refuses to replace any existing file and
+// returns ErrExists naming them before writing anything.
+func Init(root string, files []File, force bool) (Result, error) {
+	var res Result
+	var existing []string
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(f.Path))); err == nil {
+			existing = append(existing, f.Path)
+		}
+	}
+	if len(existing) > 0 && !force {
+		return res, fmt.Errorf("%w: %s (use --force to overwrite)", ErrExists, strings.Join(existing, ", "))
+	}
+
+	for _, f := range files {
+		dest := filepath.Join(root, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return res, fmt.Errorf("scaffold: %w", err)
+		}
+		if err := os.WriteFile(dest, f.Data, 0o644); err != nil {
+			return res, fmt.Errorf("scaffold: %w", err)
+		}
+		if slices.Contains(existing, f.Path) {
+			res.Overwritten = append(res.Overwritten, f.Path)
+		} else {
+			res.Created = append(res.Created, f.Path)
+		}
+	}
+
+	added, err := ensureIgnored(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return res, err
+	}
+	res.Ignored = added
+	return res, nil
+}
+
+// Check reports the pieces of the manifest missing under root: files that
+// do not exist and Ignored entries that .gitignore lacks. A nil result
+// means the project is fully set up.
+func Check(root string, files []File) ([]string, error) {
+	var missing []string
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(f.Path))); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("scaffold: %w", err)
+			}
+			missing = append(missing, f.Path)
+		}
+	}
+	lines, err := readLines(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range Ignored {
+		if !ignores(lines, entry) {
+			missing = append(missing, ".gitignore: "+entry)
+		}
+	}
+	return missing, nil
+}
+
+// ensureIgnored appends the Ignored entries missing from the .gitignore at
+// path, creating it if needed, and returns the entries it added.
+func ensureIgnored(path string) ([]string, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	var added []string
+	for _, entry := range Ignored {
+		if !ignores(lines, entry) {
+			added = append(added, entry)
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("scaffold: %w", err)
+	}
+	var b bytes.Buffer
+	b.Write(existing)
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		b.WriteByte('\n')
+	}
+	b.WriteString("# capsule artifacts\n")
+	for _, entry := range added {
+		b.WriteString(entry + "\n")
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("scaffold: %w", err)
+	}
+	return added, nil
+}
+
+// readLines returns the trimmed lines of the file at path, or nil when it
+// does not exist.
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scaffold: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		lines = append(lines, strings.TrimSpace(sc.Text()))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("scaffold: reading %s: %w", path, err)
+	}
+	return lines, nil
+}
+
+// ignores reports whether lines contain entry or a broader rule that covers
+// it: the same path without the trailing slash or a leading slash, or the
+// whole .capsule directory.
+func ignores(lines []string, entry string) bool {
+	dir := strings.TrimSuffix(entry, "/")
+	for _, l := range lines {
+		l = strings.TrimPrefix(l, "/")
+		switch l {
+		case entry, dir, ".capsule/", ".capsule":
+			return true
+		}
+	}
+	return false
+}
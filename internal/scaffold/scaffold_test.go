@@ -0,0 +1,236 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/smileynet/capsule"
+	"github.com/smileynet/capsule/internal/config"
+)
+
+func testManifest(t *testing.T) []File {
+	t.Helper()
+	files, err := Manifest([]byte("runtime:\n"),
+		fstest.MapFS{"execute.md": {Data: []byte("execute prompt")}},
+		fstest.MapFS{"worklog.md.template": {Data: []byte("worklog")}},
+	)
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+	return files
+}
+
+func TestManifest_Paths(t *testing.T) {
+	// Given embedded config, prompts, and templates
+	// When the manifest is built
+	files := testManifest(t)
+
+	// Then each file lands where capsule looks for it
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	want := []string{".capsule/config.yaml", "prompts/execute.md", "templates/worklog.md.template"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestDemo_Paths(t *testing.T) {
+	// Given a demo template with its own config, scripted responses, an
+	// embeddable go.mod, and a fixture test script
+	project := fstest.MapFS{
+		"AGENTS.md":           {Data: []byte("agents")},
+		"capsule.yaml":        {Data: []byte("runtime:\n  provider: scripted\n")},
+		"issues.jsonl":        {Data: []byte("{}\n")},
+		"scripted.yaml":       {Data: []byte("phases:\n")},
+		"src/go.mod.template": {Data: []byte("module example.com/demo\n")},
+		"src/main.go":         {Data: []byte("package main\n")},
+		"test-fixtures.sh":    {Data: []byte("#!/bin/sh\n")},
+	}
+
+	// When the demo files are listed
+	files, err := Demo(testManifest(t), project)
+	if err != nil {
+		t.Fatalf("Demo() error = %v", err)
+	}
+
+	// Then the manifest comes first, with the template's config, and each
+	// template file lands where a capsule project keeps it
+	got := make(map[string]string)
+	var paths []string
+	for _, f := range files {
+		got[f.Path] = string(f.Data)
+		paths = append(paths, f.Path)
+	}
+	want := []string{
+		".capsule/config.yaml", "prompts/execute.md", "templates/worklog.md.template",
+		"AGENTS.md", "issues.jsonl", ".capsule/scripted.yaml", "src/go.mod", "src/main.go",
+	}
+	if !slices.Equal(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	if got[ConfigPath] != "runtime:\n  provider: scripted\n" {
+		t.Errorf("config = %q, want the template's", got[ConfigPath])
+	}
+}
+
+func TestInit_CreatesFilesAndIgnores(t *testing.T) {
+	// Given an empty project
+	root := t.TempDir()
+
+	// When Init runs
+	res, err := Init(root, testManifest(t), false)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	// Then every manifest file is created
+	if len(res.Created) != 3 || len(res.Overwritten) != 0 {
+		t.Errorf("result = %+v, want 3 created", res)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "prompts", "execute.md"))
+	if err != nil || string(data) != "execute prompt" {
+		t.Errorf("prompts/execute.md = %q, %v", data, err)
+	}
+	// And .gitignore covers the artifacts but not the config
+	gitignore, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range Ignored {
+		if !strings.Contains(string(gitignore), entry+"\n") {
+			t.Errorf(".gitignore missing %q", entry)
+		}
+	}
+	if strings.Contains(string(gitignore), "config") {
+		t.Errorf(".gitignore ignores the config:\n%s", gitignore)
+	}
+	// And the result passes Check
+	missing, err := Check(root, testManifest(t))
+	if err != nil || len(missing) != 0 {
+		t.Errorf("Check() = %v, %v; want nothing missing", missing, err)
+	}
+}
+
+func TestInit_ExistingFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		force    bool
+		wantErr  error
+		wantData string
+	}{
+		{name: "refuses without force", force: false, wantErr: ErrExists, wantData: "custom"},
+		{name: "overwrites with force", force: true, wantData: "execute prompt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a project with a customized prompt
+			root := t.TempDir()
+			prompt := filepath.Join(root, "prompts", "execute.md")
+			if err := os.MkdirAll(filepath.Dir(prompt), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(prompt, []byte("custom"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When Init runs
+			res, err := Init(root, testManifest(t), tt.force)
+
+			// Then it fails or overwrites as requested
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Init() error = %v, want %v", err, tt.wantErr)
+			}
+			data, _ := os.ReadFile(prompt)
+			if string(data) != tt.wantData {
+				t.Errorf("execute.md = %q, want %q", data, tt.wantData)
+			}
+			// And a refusal writes nothing at all
+			if tt.wantErr != nil {
+				if _, err := os.Stat(filepath.Join(root, ConfigPath)); !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("config written despite refusal: %v", err)
+				}
+			} else if !slices.Equal(res.Overwritten, []string{"prompts/execute.md"}) {
+				t.Errorf("overwritten = %v, want prompts/execute.md", res.Overwritten)
+			}
+		})
+	}
+}
+
+func TestInit_KeepsExistingGitignore(t *testing.T) {
+	// Given a .gitignore that already ignores some artifacts
+	root := t.TempDir()
+	existing := "node_modules\n/.capsule/worktrees\n.capsule/logs/"
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When Init runs
+	res, err := Init(root, testManifest(t), false)
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	// Then only the missing entries are appended after the existing lines
+	want := []string{".capsule/campaigns/", ".capsule/checkpoints/", ".capsule/runs/"}
+	if !slices.Equal(res.Ignored, want) {
+		t.Errorf("ignored = %v, want %v", res.Ignored, want)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if !strings.HasPrefix(string(data), existing+"\n# capsule artifacts\n") {
+		t.Errorf(".gitignore = %q, want existing lines kept", data)
+	}
+}
+
+func TestCheck_ReportsMissingPieces(t *testing.T) {
+	// Given a project with only the config
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".capsule"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ConfigPath), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the setup is checked
+	missing, err := Check(root, testManifest(t))
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	// Then the prompt, template, and every .gitignore entry are reported
+	if len(missing) != 2+len(Ignored) {
+		t.Fatalf("missing = %v, want %d entries", missing, 2+len(Ignored))
+	}
+	if missing[0] != "prompts/execute.md" || missing[1] != "templates/worklog.md.template" {
+		t.Errorf("missing = %v, want prompt and template first", missing)
+	}
+}
+
+func TestEmbeddedConfigTemplate_Loads(t *testing.T) {
+	// Given the config template written by capsule init
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, capsule.ConfigTemplate, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When it is loaded as a project config
+	cfg, err := config.LoadLayered(path)
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	// Then it parses to the defaults
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if cfg.Runtime.Provider != "claude" || cfg.Pipeline.Phases != "default" {
+		t.Errorf("config = %+v, want defaults", cfg)
+	}
+}
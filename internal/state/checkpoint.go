@@ -5,23 +5,61 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/smileynet/capsule/internal/atomicfile"
 	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/provider"
 )
 
+// checkpointSuffix ends every checkpoint file name.
+const checkpointSuffix = ".checkpoint.json"
+
+// Timing of the per-bead lock file that serializes checkpoint writers.
+const (
+	checkpointLockTimeout = 5 * time.Second       // How long a writer waits for another.
+	checkpointLockStale   = 30 * time.Second      // Age at which a lock is taken to be left by a crashed writer.
+	checkpointLockPoll    = 10 * time.Millisecond // How often a waiting writer retries.
+)
+
+// ErrCheckpointLocked indicates another writer held a bead's checkpoint lock
+// for longer than a writer waits.
+var ErrCheckpointLocked = errors.New("checkpoint: locked by another writer")
+
 // Compile-time check: CheckpointFileStore satisfies orchestrator.CheckpointStore.
 var _ orchestrator.CheckpointStore = (*CheckpointFileStore)(nil)
 
-// CheckpointFileStore persists pipeline checkpoints as JSON files under a base directory.
+// CheckpointFileStore persists pipeline checkpoints as JSON files under a
+// base directory. Each file is replaced by rename, so a reader never sees a
+// partial write, and writers of one bead's checkpoint take
+// <bead-id>.checkpoint.json.lock so processes saving and removing it at the
+// same time apply in order.
 type CheckpointFileStore struct {
 	baseDir string
+	logger  *slog.Logger
+}
+
+// CheckpointOption configures a CheckpointFileStore.
+type CheckpointOption func(*CheckpointFileStore)
+
+// WithCheckpointLogger sets the logger that reports unreadable checkpoints.
+func WithCheckpointLogger(l *slog.Logger) CheckpointOption {
+	return func(s *CheckpointFileStore) { s.logger = l }
 }
 
 // NewCheckpointFileStore creates a CheckpointFileStore that saves checkpoints under baseDir.
-func NewCheckpointFileStore(baseDir string) *CheckpointFileStore {
-	return &CheckpointFileStore{baseDir: baseDir}
+func NewCheckpointFileStore(baseDir string, opts ...CheckpointOption) *CheckpointFileStore {
+	s := &CheckpointFileStore{baseDir: baseDir, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for _, o := range opts {
+		o(s)
+	}
+	return s
 }
 
 // SaveCheckpoint writes the pipeline checkpoint to a JSON file named by the bead ID.
@@ -40,7 +78,12 @@ func (s *CheckpointFileStore) SaveCheckpoint(cp orchestrator.PipelineCheckpoint)
 		return fmt.Errorf("checkpoint: marshaling: %w", err)
 	}
 
-	if err := os.WriteFile(p, data, 0o644); err != nil {
+	unlock, err := lockCheckpoint(p)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if err := atomicfile.WriteFile(p, data); err != nil {
 		return fmt.Errorf("checkpoint: writing %s: %w", p, err)
 	}
 	return nil
@@ -48,7 +91,22 @@ func (s *CheckpointFileStore) SaveCheckpoint(cp orchestrator.PipelineCheckpoint)
 
 // LoadCheckpoint reads a pipeline checkpoint for the given bead ID.
 // Returns (checkpoint, true, nil) if found, (zero, false, nil) if not found.
+// A file that is not a valid checkpoint is logged as a warning and also
+// reported as not found, so a run starts over instead of failing.
 func (s *CheckpointFileStore) LoadCheckpoint(beadID string) (orchestrator.PipelineCheckpoint, bool, error) {
+	cp, found, err := s.load(beadID)
+	var se *json.SyntaxError
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &se) || errors.As(err, &te) {
+		s.logger.Warn("ignoring unreadable checkpoint", "bead", beadID, "error", err)
+		return orchestrator.PipelineCheckpoint{}, false, nil
+	}
+	return cp, found, err
+}
+
+// load reads the checkpoint for beadID, reporting a file that does not
+// parse as an error.
+func (s *CheckpointFileStore) load(beadID string) (orchestrator.PipelineCheckpoint, bool, error) {
 	p, err := s.path(beadID)
 	if err != nil {
 		return orchestrator.PipelineCheckpoint{}, false, err
@@ -75,17 +133,156 @@ func (s *CheckpointFileStore) RemoveCheckpoint(beadID string) error {
 	if err != nil {
 		return err
 	}
+	unlock, err := lockCheckpoint(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil // No directory, so no checkpoint.
+	}
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("checkpoint: removing %s: %w", p, err)
 	}
 	return nil
 }
 
+// PruneCheckpoints removes checkpoints saved more than maxAge ago and those
+// of beads for which done reports true, such as beads already closed. A
+// maxAge of zero or less keeps checkpoints of any age, and done may be nil.
+// A file that cannot be read is aged by its modification time. It returns
+// the bead IDs removed, sorted; failures are joined into the error and do
+// not stop the others.
+func (s *CheckpointFileStore) PruneCheckpoints(maxAge time.Duration, done func(beadID string) bool) ([]string, error) {
+	entries, err := os.ReadDir(s.baseDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("checkpoint: reading %s: %w", s.baseDir, err)
+	}
+
+	var removed []string
+	var errs []error
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), checkpointSuffix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		if !s.expired(id, e, maxAge) && (done == nil || !done(id)) {
+			continue
+		}
+		if err := s.RemoveCheckpoint(id); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, id)
+	}
+	sort.Strings(removed)
+	return removed, errors.Join(errs...)
+}
+
+// expired reports whether the checkpoint for id was saved more than maxAge ago.
+func (s *CheckpointFileStore) expired(id string, e os.DirEntry, maxAge time.Duration) bool {
+	if maxAge <= 0 {
+		return false
+	}
+	saved := time.Time{}
+	if cp, found, err := s.load(id); err == nil && found {
+		saved = cp.SavedAt
+	} else if fi, err := e.Info(); err == nil {
+		saved = fi.ModTime()
+	}
+	return !saved.IsZero() && time.Since(saved) > maxAge
+}
+
+// CheckpointInfo describes a saved checkpoint without its phase results.
+type CheckpointInfo struct {
+	BeadID    string
+	SavedAt   time.Time
+	Completed int    // Distinct phases that passed or were skipped.
+	LastPhase string // Last phase that passed or was skipped; "" if none has.
+	Warning   string // Set when the file could not be read; only BeadID is filled in.
+}
+
+// ListCheckpoints returns every saved checkpoint, sorted by bead ID. A
+// missing directory yields an empty list; unreadable files are listed with
+// a warning instead of failing the listing.
+func (s *CheckpointFileStore) ListCheckpoints() ([]CheckpointInfo, error) {
+	entries, err := os.ReadDir(s.baseDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []CheckpointInfo{}, nil
+		}
+		return nil, fmt.Errorf("checkpoint: reading %s: %w", s.baseDir, err)
+	}
+
+	infos := []CheckpointInfo{}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), checkpointSuffix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		cp, _, err := s.load(id)
+		if err != nil {
+			infos = append(infos, CheckpointInfo{BeadID: id, Warning: err.Error()})
+			continue
+		}
+		infos = append(infos, checkpointInfo(id, cp))
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].BeadID < infos[j].BeadID })
+	return infos, nil
+}
+
+// checkpointInfo summarizes cp, saved under the bead ID id.
+func checkpointInfo(id string, cp orchestrator.PipelineCheckpoint) CheckpointInfo {
+	info := CheckpointInfo{BeadID: id, SavedAt: cp.SavedAt}
+	done := make(map[string]bool)
+	for _, pr := range cp.PhaseResults {
+		if pr.Signal.Status != provider.StatusPass && pr.Signal.Status != provider.StatusSkip {
+			continue
+		}
+		if !done[pr.PhaseName] {
+			done[pr.PhaseName] = true
+			info.Completed++
+		}
+		info.LastPhase = pr.PhaseName
+	}
+	return info
+}
+
 // path returns the filesystem path for a checkpoint file.
 // It rejects IDs that are empty, dot-segments, or contain path separators.
 func (s *CheckpointFileStore) path(id string) (string, error) {
 	if id == "" || id == "." || id == ".." || id != filepath.Base(id) {
 		return "", fmt.Errorf("%w: %q", ErrInvalidID, id)
 	}
-	return filepath.Join(s.baseDir, id+".checkpoint.json"), nil
+	return filepath.Join(s.baseDir, id+checkpointSuffix), nil
+}
+
+// lockCheckpoint takes the lock file for the checkpoint at p, waiting up to
+// checkpointLockTimeout for another writer to finish. A lock older than
+// checkpointLockStale was left by a writer that crashed and is taken over.
+// The returned func releases the lock.
+func lockCheckpoint(p string) (unlock func(), err error) {
+	lockPath := p + ".lock"
+	deadline := time.Now().Add(checkpointLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("checkpoint: locking %s: %w", p, err)
+		}
+		if fi, err := os.Stat(lockPath); err == nil && time.Since(fi.ModTime()) > checkpointLockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s", ErrCheckpointLocked, p)
+		}
+		time.Sleep(checkpointLockPoll)
+	}
 }
@@ -1,8 +1,14 @@
 package state
 
 import (
+	"bytes"
 	"errors"
+	"log/slog"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -192,3 +198,206 @@ func TestCheckpointFileStore_OverwriteOnSave(t *testing.T) {
 		t.Errorf("PhaseResults len = %d, want 2", got)
 	}
 }
+
+func TestCheckpointFileStore_ListCheckpoints(t *testing.T) {
+	// Given two saved checkpoints and a corrupt one
+	dir := filepath.Join(t.TempDir(), "checkpoints")
+	store := NewCheckpointFileStore(dir)
+	saved := time.Date(2026, 10, 16, 14, 2, 0, 0, time.UTC)
+	for _, cp := range []orchestrator.PipelineCheckpoint{
+		{
+			BeadID: "cap-2",
+			PhaseResults: []orchestrator.PhaseResult{
+				{PhaseName: "test-writer", Signal: provider.Signal{Status: provider.StatusPass}},
+				{PhaseName: "test-review", Signal: provider.Signal{Status: provider.StatusNeedsWork}},
+				{PhaseName: "test-writer", Signal: provider.Signal{Status: provider.StatusPass}},
+				{PhaseName: "test-review", Signal: provider.Signal{Status: provider.StatusPass}},
+				{PhaseName: "execute", Signal: provider.Signal{Status: provider.StatusError}},
+			},
+			SavedAt: saved,
+		},
+		{BeadID: "cap-1", SavedAt: saved},
+	} {
+		if err := store.SaveCheckpoint(cp); err != nil {
+			t.Fatalf("SaveCheckpoint() error = %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cap-3.checkpoint.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the checkpoints are listed
+	infos, err := store.ListCheckpoints()
+	if err != nil {
+		t.Fatalf("ListCheckpoints() error = %v", err)
+	}
+
+	// Then each checkpoint is summarized in bead ID order
+	if len(infos) != 3 {
+		t.Fatalf("ListCheckpoints() = %+v, want 3 entries", infos)
+	}
+	if got := infos[0]; got.BeadID != "cap-1" || got.Completed != 0 || got.LastPhase != "" || !got.SavedAt.Equal(saved) {
+		t.Errorf("infos[0] = %+v", got)
+	}
+	if got := infos[1]; got.BeadID != "cap-2" || got.Completed != 2 || got.LastPhase != "test-review" || got.Warning != "" {
+		t.Errorf("infos[1] = %+v, want 2 completed, last test-review", got)
+	}
+	// And the corrupt file is listed with a warning
+	if got := infos[2]; got.BeadID != "cap-3" || got.Warning == "" {
+		t.Errorf("infos[2] = %+v, want warning", got)
+	}
+}
+
+func TestCheckpointFileStore_ListCheckpointsMissingDir(t *testing.T) {
+	// Given a store whose directory does not exist
+	store := NewCheckpointFileStore(filepath.Join(t.TempDir(), "missing"))
+
+	// When the checkpoints are listed
+	infos, err := store.ListCheckpoints()
+
+	// Then the list is empty
+	if err != nil || len(infos) != 0 {
+		t.Errorf("ListCheckpoints() = %v, %v; want empty, nil", infos, err)
+	}
+}
+
+func TestCheckpointFileStore_LoadCorruptIsNotFound(t *testing.T) {
+	// Given a checkpoint file left half written by an older version
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cap-1.checkpoint.json"), []byte(`{"bead_id": "cap-1", "phase_res`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	store := NewCheckpointFileStore(dir, WithCheckpointLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	// When it is loaded
+	_, found, err := store.LoadCheckpoint("cap-1")
+
+	// Then it is reported as not found, with a warning
+	if err != nil || found {
+		t.Errorf("LoadCheckpoint() = found %v, err %v; want not found, nil", found, err)
+	}
+	if !strings.Contains(logs.String(), "level=WARN") || !strings.Contains(logs.String(), "cap-1") {
+		t.Errorf("logs = %q, want a warning naming the bead", logs.String())
+	}
+}
+
+func TestCheckpointFileStore_ConcurrentSaves(t *testing.T) {
+	// Given many writers saving the same bead's checkpoint at once
+	dir := t.TempDir()
+	store := NewCheckpointFileStore(dir)
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results := make([]orchestrator.PhaseResult, i+1)
+			errs <- store.SaveCheckpoint(orchestrator.PipelineCheckpoint{BeadID: "cap-1", PhaseResults: results})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Then every save succeeds, one whole checkpoint remains, and no lock
+	// or temporary file is left behind
+	for err := range errs {
+		if err != nil {
+			t.Errorf("SaveCheckpoint() error = %v", err)
+		}
+	}
+	if _, found, err := store.load("cap-1"); err != nil || !found {
+		t.Errorf("load() = found %v, err %v; want a valid checkpoint", found, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "cap-1.checkpoint.json" {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("dir = %q, want only cap-1.checkpoint.json", names)
+	}
+}
+
+func TestCheckpointFileStore_StaleLockIsTakenOver(t *testing.T) {
+	// Given a lock left by a writer that crashed a minute ago
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "cap-1.checkpoint.json.lock")
+	if err := os.WriteFile(lock, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// When a checkpoint is saved
+	err := NewCheckpointFileStore(dir).SaveCheckpoint(orchestrator.PipelineCheckpoint{BeadID: "cap-1"})
+
+	// Then the save goes ahead and releases the lock
+	if err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+	if _, err := os.Stat(lock); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lock still present: %v", err)
+	}
+}
+
+func TestCheckpointFileStore_PruneCheckpoints(t *testing.T) {
+	// Given checkpoints saved today, 20 days ago, for a closed bead, and a
+	// corrupt file last written 20 days ago
+	dir := t.TempDir()
+	store := NewCheckpointFileStore(dir)
+	now := time.Now()
+	for id, saved := range map[string]time.Time{
+		"cap-fresh":  now,
+		"cap-old":    now.Add(-20 * 24 * time.Hour),
+		"cap-closed": now,
+	} {
+		if err := store.SaveCheckpoint(orchestrator.PipelineCheckpoint{BeadID: id, SavedAt: saved}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	corrupt := filepath.Join(dir, "cap-corrupt.checkpoint.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := now.Add(-20 * 24 * time.Hour)
+	if err := os.Chtimes(corrupt, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		done   func(string) bool
+		want   []string
+	}{
+		{name: "retention off keeps everything", maxAge: 0},
+		{name: "closed bead", done: func(id string) bool { return id == "cap-closed" }, want: []string{"cap-closed"}},
+		{name: "older than retention", maxAge: 14 * 24 * time.Hour, want: []string{"cap-corrupt", "cap-old"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When checkpoints are pruned
+			removed, err := store.PruneCheckpoints(tt.maxAge, tt.done)
+
+			// Then only expired checkpoints and those of done beads are removed
+			if err != nil {
+				t.Fatalf("PruneCheckpoints() error = %v", err)
+			}
+			if !slices.Equal(removed, tt.want) {
+				t.Errorf("removed = %q, want %q", removed, tt.want)
+			}
+		})
+	}
+	if _, found, _ := store.LoadCheckpoint("cap-fresh"); !found {
+		t.Error("cap-fresh should survive pruning")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/smileynet/capsule/internal/campaign"
 )
@@ -78,6 +80,37 @@ func (s *FileStore) Remove(id string) error {
 	return nil
 }
 
+// List returns every saved campaign state, sorted by parent bead ID. A
+// missing directory yields an empty list. Files that cannot be read are
+// skipped and reported in the returned error, alongside the states that
+// could be.
+func (s *FileStore) List() ([]campaign.State, error) {
+	entries, err := os.ReadDir(s.baseDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []campaign.State{}, nil
+		}
+		return nil, fmt.Errorf("state: reading %s: %w", s.baseDir, err)
+	}
+
+	states := []campaign.State{}
+	var errs []error
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		st, _, err := s.Load(id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		states = append(states, st)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].ParentBeadID < states[j].ParentBeadID })
+	return states, errors.Join(errs...)
+}
+
 // ErrInvalidID indicates a campaign ID is empty or contains path traversal components.
 var ErrInvalidID = errors.New("state: invalid campaign ID")
 
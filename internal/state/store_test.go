@@ -2,7 +2,9 @@ package state
 
 import (
 	"errors"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -166,3 +168,41 @@ func TestFileStore_ValidIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestFileStore_List(t *testing.T) {
+	// Given two saved campaigns and a corrupt state file
+	dir := filepath.Join(t.TempDir(), "campaigns")
+	store := NewFileStore(dir)
+	for _, id := range []string{"feat-2", "feat-1"} {
+		if err := store.Save(campaign.State{ID: id, ParentBeadID: id, Status: campaign.CampaignRunning}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "feat-3.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the campaigns are listed
+	states, err := store.List()
+
+	// Then the readable states are returned in order and the corrupt file is reported
+	if len(states) != 2 || states[0].ParentBeadID != "feat-1" || states[1].ParentBeadID != "feat-2" {
+		t.Errorf("List() states = %+v, want feat-1, feat-2", states)
+	}
+	if err == nil || !strings.Contains(err.Error(), "feat-3.json") {
+		t.Errorf("List() error = %v, want parse error naming feat-3.json", err)
+	}
+}
+
+func TestFileStore_ListMissingDir(t *testing.T) {
+	// Given a store whose directory does not exist
+	store := NewFileStore(filepath.Join(t.TempDir(), "missing"))
+
+	// When the campaigns are listed
+	states, err := store.List()
+
+	// Then the list is empty
+	if err != nil || len(states) != 0 {
+		t.Errorf("List() = %v, %v; want empty, nil", states, err)
+	}
+}
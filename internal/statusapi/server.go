@@ -0,0 +1,130 @@
+package statusapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// shutdownTimeout bounds how long Close waits for in-flight requests.
+const shutdownTimeout = 2 * time.Second
+
+// DefaultHost is the host Start binds when addr leaves it out, as in ":7777".
+const DefaultHost = "127.0.0.1"
+
+// Server serves a Tracker's snapshot read-only:
+//
+//	GET /status   the Snapshot as JSON
+//	GET /healthz  "ok"
+type Server struct {
+	srv       *http.Server
+	ln        net.Listener
+	done      chan struct{}
+	closeOnce sync.Once
+	logger    *slog.Logger
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithLogger sets the logger that warns when the server binds a
+// non-loopback address. The default discards logs.
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Server) {
+		if l != nil {
+			s.logger = l
+		}
+	}
+}
+
+// Start listens on addr and serves t until ctx is done or Close is called.
+// Binding happens before Start returns, so a busy or invalid address is
+// reported here rather than lost in the background. An addr without a host
+// binds DefaultHost; any host other than loopback is logged as a warning,
+// since the endpoint has no authentication.
+func Start(ctx context.Context, addr string, t *Tracker, opts ...Option) (*Server, error) {
+	s := &Server{
+		srv:    &http.Server{Handler: Handler(t), ReadHeaderTimeout: 5 * time.Second},
+		done:   make(chan struct{}),
+		logger: slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("status endpoint: %w", err)
+	}
+	if host == "" {
+		host = DefaultHost
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, fmt.Errorf("status endpoint: %w", err)
+	}
+	s.ln = ln
+	if !isLoopback(host) {
+		s.logger.Warn("status endpoint is reachable beyond this machine", "addr", ln.Addr().String())
+	}
+	go func() {
+		_ = s.srv.Serve(ln)
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// isLoopback reports whether host only accepts connections from this machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Handler returns the read-only HTTP handler for t.
+func Handler(t *Tracker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(t.Snapshot())
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// Addr returns the address the server listens on, with the port resolved
+// when addr asked for port 0.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops the server, giving in-flight requests a moment to finish.
+// It is safe to call more than once.
+func (s *Server) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := s.srv.Shutdown(ctx); errors.Is(err, context.DeadlineExceeded) {
+			_ = s.srv.Close()
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package statusapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/orchestrator"
+)
+
+func TestHandler_Status(t *testing.T) {
+	// Given a tracker with a running phase
+	tr := NewTracker(KindPipeline, "cap-1")
+	tr.StatusCallback(nil)(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "execute", Status: orchestrator.PhaseRunning, Attempt: 1})
+
+	// When GET /status is requested
+	rec := httptest.NewRecorder()
+	Handler(tr).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	// Then the snapshot is returned as JSON
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+		t.Fatalf("decode: %v\n%s", err, rec.Body)
+	}
+	if s.Kind != KindPipeline || s.Pipeline == nil || s.Pipeline.Phase != "execute" {
+		t.Errorf("snapshot = %+v, want cap-1 in execute", s)
+	}
+}
+
+func TestHandler_Routes(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "healthz", method: http.MethodGet, path: "/healthz", wantCode: http.StatusOK, wantBody: "ok\n"},
+		{name: "status is read-only", method: http.MethodPost, path: "/status", wantCode: http.StatusMethodNotAllowed},
+		{name: "unknown path", method: http.MethodGet, path: "/tasks", wantCode: http.StatusNotFound},
+	}
+
+	h := Handler(NewTracker(KindPipeline, "cap-1"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When the path is requested
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			// Then the expected response is returned
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestStart_ServesUntilContextDone(t *testing.T) {
+	// Given a server started on a free local port
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv, err := Start(ctx, "127.0.0.1:0", NewTracker(KindPipeline, "cap-1"))
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer srv.Close()
+
+	// When /healthz is requested
+	resp, err := http.Get("http://" + srv.Addr() + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	// Then it answers
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "ok" {
+		t.Errorf("GET /healthz = %d %q, want 200 ok", resp.StatusCode, body)
+	}
+
+	// And once ctx is cancelled the port stops accepting connections
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", srv.Addr())
+		if err != nil {
+			break
+		}
+		_ = conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("server still accepting connections after ctx was cancelled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStart_BindAddress(t *testing.T) {
+	tests := []struct {
+		name         string
+		addr         string
+		wantLoopback bool
+	}{
+		{name: "no host binds loopback", addr: ":0", wantLoopback: true},
+		{name: "loopback", addr: "127.0.0.1:0", wantLoopback: true},
+		{name: "localhost", addr: "localhost:0", wantLoopback: true},
+		{name: "all interfaces", addr: "0.0.0.0:0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a logger that records warnings
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+			// When a server is started on the address
+			srv, err := Start(context.Background(), tt.addr, NewTracker(KindPipeline, "cap-1"), WithLogger(logger))
+			if err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			defer srv.Close()
+
+			// Then it listens on loopback only when asked to
+			host, _, err := net.SplitHostPort(srv.Addr())
+			if err != nil {
+				t.Fatalf("SplitHostPort(%q): %v", srv.Addr(), err)
+			}
+			if got := net.ParseIP(host).IsLoopback(); got != tt.wantLoopback {
+				t.Errorf("Addr() = %q, loopback = %v, want %v", srv.Addr(), got, tt.wantLoopback)
+			}
+
+			// And only a non-loopback bind is warned about
+			warned := strings.Contains(buf.String(), "level=WARN")
+			if warned == tt.wantLoopback {
+				t.Errorf("warned = %v, want %v; log:\n%s", warned, !tt.wantLoopback, buf.String())
+			}
+		})
+	}
+}
+
+func TestStart_AddressInUse(t *testing.T) {
+	// Given a port already taken
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	// When a server is started on it
+	_, err = Start(context.Background(), ln.Addr().String(), NewTracker(KindPipeline, "cap-1"))
+
+	// Then the bind error is returned
+	if err == nil || !strings.Contains(err.Error(), "status endpoint") {
+		t.Errorf("Start() error = %v, want a status endpoint bind error", err)
+	}
+}
+
+func TestServer_CloseTwice(t *testing.T) {
+	// Given a running server
+	srv, err := Start(context.Background(), "127.0.0.1:0", NewTracker(KindPipeline, "cap-1"))
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// When it is closed twice, then neither call panics
+	srv.Close()
+	srv.Close()
+}
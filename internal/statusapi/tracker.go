@@ -0,0 +1,380 @@
+// Package statusapi serves a running pipeline's or campaign's progress as
+// JSON over HTTP, so it can be checked without attaching to the terminal.
+// A Tracker keeps the snapshot, fed by wrapping the status callbacks the
+// run already has; a Server exposes it read-only.
+package statusapi
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smileynet/capsule/internal/campaign"
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// recentPhases is how many finished phases a pipeline snapshot keeps.
+const recentPhases = 20
+
+// Kind identifies what a snapshot describes.
+type Kind string
+
+const (
+	KindPipeline Kind = "pipeline"
+	KindCampaign Kind = "campaign"
+)
+
+// Snapshot is the body of GET /status.
+type Snapshot struct {
+	Kind      Kind      `json:"kind"`
+	StartedAt time.Time `json:"started_at"`
+	ElapsedMS int64     `json:"elapsed_ms"`
+	Pipeline  *Pipeline `json:"pipeline,omitempty"` // Set for a run.
+	Campaign  *Campaign `json:"campaign,omitempty"` // Set for a campaign.
+}
+
+// Pipeline is one bead's pipeline: the phase it is in and the phases it
+// has finished, most recent last.
+type Pipeline struct {
+	BeadID         string        `json:"bead_id"`
+	Phase          string        `json:"phase"`
+	Status         string        `json:"status"`
+	Attempt        int           `json:"attempt"`
+	PhaseElapsedMS int64         `json:"phase_elapsed_ms"`  // Time in the running phase; 0 once it finished.
+	Message        string        `json:"message,omitempty"` // Latest provider progress or retry wait.
+	Phases         []PhaseResult `json:"phases"`
+}
+
+// PhaseResult is a finished phase.
+type PhaseResult struct {
+	Phase      string         `json:"phase"`
+	Status     string         `json:"status"`
+	Attempt    int            `json:"attempt"`
+	DurationMS int64          `json:"duration_ms"`
+	Summary    string         `json:"summary,omitempty"`
+	SkipReason string         `json:"skip_reason,omitempty"`
+	Usage      provider.Usage `json:"usage,omitzero"`
+}
+
+// Campaign is a campaign's tasks, sub-campaigns' included, in the order
+// they were announced.
+type Campaign struct {
+	ParentID   string    `json:"parent_id"`
+	Status     string    `json:"status"`           // running, completed, failed, or paused.
+	Reason     string    `json:"reason,omitempty"` // Why the campaign paused or its circuit breaker tripped.
+	Tasks      []Task    `json:"tasks"`
+	Validation *Pipeline `json:"validation,omitempty"` // Feature validation of the parent, once started.
+}
+
+// Task is one campaign task. Pipeline is set once the task has started.
+type Task struct {
+	BeadID   string    `json:"bead_id"`
+	ParentID string    `json:"parent_id"` // The campaign level that runs it.
+	Title    string    `json:"title"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Reason   string    `json:"reason,omitempty"` // Why it was skipped.
+	Pipeline *Pipeline `json:"pipeline,omitempty"`
+}
+
+// Tracker keeps the latest Snapshot of a run. It is safe for concurrent
+// use, so concurrent campaign tasks may report through it.
+type Tracker struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	kind      Kind
+	beadID    string // The run's bead, or the campaign's parent.
+	startedAt time.Time
+	pipelines map[string]*pipelineState // By bead ID.
+	campaign  *Campaign
+	tasks     map[string]int // Task index by bead ID.
+}
+
+// pipelineState is a Pipeline with the running phase's start time.
+type pipelineState struct {
+	Pipeline
+	phaseStart time.Time
+}
+
+// NewTracker returns a Tracker for a run of the given kind, started now.
+// beadID is the bead a pipeline runs, or the parent of a campaign.
+func NewTracker(kind Kind, beadID string) *Tracker {
+	return newTracker(kind, beadID, time.Now)
+}
+
+func newTracker(kind Kind, beadID string, now func() time.Time) *Tracker {
+	t := &Tracker{
+		now:       now,
+		kind:      kind,
+		beadID:    beadID,
+		startedAt: now(),
+		pipelines: make(map[string]*pipelineState),
+		tasks:     make(map[string]int),
+	}
+	if kind == KindCampaign {
+		t.campaign = &Campaign{ParentID: beadID, Status: string(campaign.CampaignRunning), Tasks: []Task{}}
+	} else {
+		t.pipelines[beadID] = &pipelineState{Pipeline: Pipeline{BeadID: beadID, Status: string(orchestrator.PhasePending), Phases: []PhaseResult{}}}
+	}
+	return t
+}
+
+// StatusCallback returns a callback that records each update, then passes
+// it to next when next is non-nil.
+func (t *Tracker) StatusCallback(next orchestrator.StatusCallback) orchestrator.StatusCallback {
+	return func(su orchestrator.StatusUpdate) {
+		t.record(su)
+		if next != nil {
+			next(su)
+		}
+	}
+}
+
+// record applies a status update to its bead's pipeline.
+func (t *Tracker) record(su orchestrator.StatusUpdate) {
+	if su.Warning != "" || su.Phase == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.pipelines[su.BeadID]
+	if p == nil {
+		p = &pipelineState{Pipeline: Pipeline{BeadID: su.BeadID, Phases: []PhaseResult{}}}
+		t.pipelines[su.BeadID] = p
+	}
+	switch su.Status {
+	case orchestrator.PhaseProgress, orchestrator.PhaseRetrying:
+		p.Message = su.Message
+		return
+	case orchestrator.PhaseRunning:
+		p.Phase, p.Status, p.Attempt = su.Phase, string(su.Status), su.Attempt
+		p.Message = ""
+		p.phaseStart = t.now()
+		return
+	case orchestrator.PhasePending:
+		return
+	}
+	p.Phase, p.Status, p.Message = su.Phase, string(su.Status), ""
+	if su.Attempt > 0 {
+		p.Attempt = su.Attempt
+	}
+	p.phaseStart = time.Time{}
+	r := PhaseResult{
+		Phase:      su.Phase,
+		Status:     string(su.Status),
+		Attempt:    su.Attempt,
+		DurationMS: su.Duration.Milliseconds(),
+		SkipReason: su.SkipReason,
+		Usage:      su.Usage,
+	}
+	if su.Signal != nil {
+		r.Summary = su.Signal.Summary
+	}
+	p.Phases = append(p.Phases, r)
+	if len(p.Phases) > recentPhases {
+		p.Phases = p.Phases[len(p.Phases)-recentPhases:]
+	}
+}
+
+// Snapshot returns a copy of the current state.
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	s := Snapshot{Kind: t.kind, StartedAt: t.startedAt, ElapsedMS: now.Sub(t.startedAt).Milliseconds()}
+	if t.campaign == nil {
+		s.Pipeline = t.pipelines[t.beadID].snapshot(now)
+		return s
+	}
+	c := *t.campaign
+	c.Tasks = make([]Task, len(t.campaign.Tasks))
+	for i, task := range t.campaign.Tasks {
+		if p := t.pipelines[task.BeadID]; p != nil {
+			task.Pipeline = p.snapshot(now)
+		}
+		c.Tasks[i] = task
+	}
+	if c.Validation != nil {
+		if p := t.pipelines[c.Validation.BeadID]; p != nil {
+			c.Validation = p.snapshot(now)
+		} else {
+			v := *c.Validation
+			c.Validation = &v
+		}
+	}
+	s.Campaign = &c
+	return s
+}
+
+// snapshot copies the pipeline, timing the running phase against now.
+func (p *pipelineState) snapshot(now time.Time) *Pipeline {
+	c := p.Pipeline
+	c.Phases = append([]PhaseResult{}, p.Phases...)
+	if !p.phaseStart.IsZero() {
+		c.PhaseElapsedMS = now.Sub(p.phaseStart).Milliseconds()
+	}
+	return &c
+}
+
+// Campaign returns a campaign.Callback that records each event, then
+// passes it to next when next is non-nil.
+func (t *Tracker) Campaign(next campaign.Callback) campaign.Callback {
+	return &campaignRecorder{t: t, next: next}
+}
+
+// The recorder sees every optional event and forwards those next observes.
+var (
+	_ campaign.TaskFailureObserver     = (*campaignRecorder)(nil)
+	_ campaign.TaskSkipObserver        = (*campaignRecorder)(nil)
+	_ campaign.ValidationPhaseObserver = (*campaignRecorder)(nil)
+	_ campaign.BreakerTripObserver     = (*campaignRecorder)(nil)
+	_ campaign.ParentCloseObserver     = (*campaignRecorder)(nil)
+)
+
+// campaignRecorder records campaign events in a Tracker.
+type campaignRecorder struct {
+	t    *Tracker
+	next campaign.Callback
+}
+
+// setTask applies fn to a known task.
+func (t *Tracker) setTask(beadID string, fn func(*Task)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if i, ok := t.tasks[beadID]; ok {
+		fn(&t.campaign.Tasks[i])
+	}
+}
+
+// setCampaign applies fn to the campaign.
+func (t *Tracker) setCampaign(fn func(*Campaign)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn(t.campaign)
+}
+
+func (r *campaignRecorder) OnCampaignStart(parentID string, tasks []campaign.BeadInfo) {
+	r.t.setCampaign(func(c *Campaign) {
+		for _, task := range tasks {
+			if _, ok := r.t.tasks[task.ID]; ok {
+				continue
+			}
+			r.t.tasks[task.ID] = len(c.Tasks)
+			c.Tasks = append(c.Tasks, Task{BeadID: task.ID, ParentID: parentID, Title: task.Title, Status: string(campaign.TaskPending)})
+		}
+	})
+	if r.next != nil {
+		r.next.OnCampaignStart(parentID, tasks)
+	}
+}
+
+func (r *campaignRecorder) OnTaskStart(beadID string) {
+	r.t.setTask(beadID, func(task *Task) { task.Status = string(campaign.TaskRunning) })
+	if r.next != nil {
+		r.next.OnTaskStart(beadID)
+	}
+}
+
+func (r *campaignRecorder) OnTaskComplete(result campaign.TaskResult) {
+	r.t.setTask(result.BeadID, func(task *Task) { task.Status = string(campaign.TaskCompleted) })
+	if r.next != nil {
+		r.next.OnTaskComplete(result)
+	}
+}
+
+func (r *campaignRecorder) OnTaskFailed(result campaign.TaskResult, err error) {
+	r.t.setTask(result.BeadID, func(task *Task) {
+		task.Status = string(campaign.TaskFailed)
+		task.Error = result.Error
+		if err != nil {
+			task.Error = err.Error()
+		}
+	})
+	if r.next != nil {
+		campaign.NotifyTaskFailed(r.next, result, err)
+	}
+}
+
+func (r *campaignRecorder) OnTaskFail(beadID string, err error) {
+	r.OnTaskFailed(campaign.TaskResult{BeadID: beadID}, err)
+}
+
+func (r *campaignRecorder) OnTaskSkipped(beadID, reason string) {
+	r.t.setTask(beadID, func(task *Task) {
+		task.Status = string(campaign.TaskSkipped)
+		task.Reason = reason
+	})
+	if o, ok := r.next.(campaign.TaskSkipObserver); ok {
+		o.OnTaskSkipped(beadID, reason)
+	}
+}
+
+func (r *campaignRecorder) OnCampaignPaused(beadID, reason, details string) {
+	r.t.setCampaign(func(c *Campaign) {
+		c.Status = string(campaign.CampaignPaused)
+		c.Reason = reason
+	})
+	if r.next != nil {
+		r.next.OnCampaignPaused(beadID, reason, details)
+	}
+}
+
+func (r *campaignRecorder) OnDiscoveryFiled(finding provider.Finding, newBeadID string) {
+	if r.next != nil {
+		r.next.OnDiscoveryFiled(finding, newBeadID)
+	}
+}
+
+func (r *campaignRecorder) OnValidationStart() {
+	r.t.setCampaign(func(c *Campaign) {
+		c.Validation = &Pipeline{Status: string(orchestrator.PhasePending), Phases: []PhaseResult{}}
+	})
+	if r.next != nil {
+		r.next.OnValidationStart()
+	}
+}
+
+func (r *campaignRecorder) OnValidationPhase(update orchestrator.StatusUpdate) {
+	r.t.record(update)
+	if update.BeadID != "" {
+		// Validation runs under its own bead ID, e.g. cap-1-validation.
+		r.t.setCampaign(func(c *Campaign) {
+			if c.Validation != nil {
+				c.Validation.BeadID = update.BeadID
+			}
+		})
+	}
+	if o, ok := r.next.(campaign.ValidationPhaseObserver); ok {
+		o.OnValidationPhase(update)
+	}
+}
+
+func (r *campaignRecorder) OnValidationComplete(result campaign.TaskResult) {
+	if r.next != nil {
+		r.next.OnValidationComplete(result)
+	}
+}
+
+func (r *campaignRecorder) OnCircuitBroken(trip campaign.BreakerTrip) {
+	r.t.setCampaign(func(c *Campaign) { c.Reason = trip.Reason })
+	if o, ok := r.next.(campaign.BreakerTripObserver); ok {
+		o.OnCircuitBroken(trip)
+	}
+}
+
+func (r *campaignRecorder) OnParentClosed(parentID string) {
+	if o, ok := r.next.(campaign.ParentCloseObserver); ok {
+		o.OnParentClosed(parentID)
+	}
+}
+
+func (r *campaignRecorder) OnCampaignComplete(state campaign.State) {
+	r.t.setCampaign(func(c *Campaign) {
+		if state.ParentBeadID == c.ParentID {
+			c.Status = string(state.Status)
+		}
+	})
+	if r.next != nil {
+		r.next.OnCampaignComplete(state)
+	}
+}
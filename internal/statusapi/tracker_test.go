@@ -0,0 +1,252 @@
+package statusapi
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/campaign"
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// fakeClock is a settable time source.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newClock() *fakeClock {
+	return &fakeClock{t: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+}
+
+func TestTracker_PipelineSnapshot(t *testing.T) {
+	// Given a pipeline tracker that saw one phase pass and another start
+	clock := newClock()
+	tr := newTracker(KindPipeline, "cap-1", clock.now)
+	var forwarded []orchestrator.StatusUpdate
+	cb := tr.StatusCallback(func(su orchestrator.StatusUpdate) { forwarded = append(forwarded, su) })
+	cb(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "test-writer", Status: orchestrator.PhaseRunning, Attempt: 1})
+	clock.advance(3 * time.Second)
+	cb(orchestrator.StatusUpdate{
+		BeadID: "cap-1", Phase: "test-writer", Status: orchestrator.PhasePassed, Attempt: 1,
+		Duration: 3 * time.Second, Signal: &provider.Signal{Summary: "tests written"},
+	})
+	cb(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "execute", Status: orchestrator.PhaseRunning, Attempt: 2})
+	cb(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "execute", Status: orchestrator.PhaseProgress, Message: "editing main.go"})
+	cb(orchestrator.StatusUpdate{Warning: "worktree reused"})
+	clock.advance(2 * time.Second)
+
+	// When a snapshot is taken
+	s := tr.Snapshot()
+
+	// Then it shows the running phase, its elapsed time, and the finished phase
+	if s.Kind != KindPipeline || s.ElapsedMS != 5000 || s.Campaign != nil {
+		t.Fatalf("snapshot = %+v, want a 5s pipeline", s)
+	}
+	p := s.Pipeline
+	if p.BeadID != "cap-1" || p.Phase != "execute" || p.Status != "running" || p.Attempt != 2 {
+		t.Errorf("pipeline = %+v, want cap-1 running execute attempt 2", p)
+	}
+	if p.PhaseElapsedMS != 2000 || p.Message != "editing main.go" {
+		t.Errorf("phase elapsed = %d, message = %q; want 2000, the progress message", p.PhaseElapsedMS, p.Message)
+	}
+	want := PhaseResult{Phase: "test-writer", Status: "passed", Attempt: 1, DurationMS: 3000, Summary: "tests written"}
+	if len(p.Phases) != 1 || p.Phases[0] != want {
+		t.Errorf("phases = %+v, want [%+v]", p.Phases, want)
+	}
+
+	// And every update, the warning included, reached the wrapped callback
+	if len(forwarded) != 5 {
+		t.Errorf("forwarded %d updates, want 5", len(forwarded))
+	}
+}
+
+func TestTracker_KeepsRecentPhases(t *testing.T) {
+	// Given more finished phases than a snapshot keeps
+	tr := NewTracker(KindPipeline, "cap-1")
+	cb := tr.StatusCallback(nil)
+	for i := range recentPhases + 5 {
+		cb(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "execute", Status: orchestrator.PhaseFailed, Attempt: i + 1})
+	}
+
+	// When a snapshot is taken
+	phases := tr.Snapshot().Pipeline.Phases
+
+	// Then only the most recent are kept
+	if len(phases) != recentPhases || phases[len(phases)-1].Attempt != recentPhases+5 {
+		t.Errorf("kept %d phases ending at attempt %d, want %d ending at %d",
+			len(phases), phases[len(phases)-1].Attempt, recentPhases, recentPhases+5)
+	}
+}
+
+func TestTracker_SnapshotIsACopy(t *testing.T) {
+	// Given a snapshot taken after one finished phase
+	tr := NewTracker(KindPipeline, "cap-1")
+	cb := tr.StatusCallback(nil)
+	cb(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "execute", Status: orchestrator.PhasePassed})
+	s := tr.Snapshot()
+
+	// When another phase finishes
+	cb(orchestrator.StatusUpdate{BeadID: "cap-1", Phase: "review", Status: orchestrator.PhasePassed})
+
+	// Then the earlier snapshot is unchanged
+	if len(s.Pipeline.Phases) != 1 {
+		t.Errorf("earlier snapshot has %d phases, want 1", len(s.Pipeline.Phases))
+	}
+}
+
+// recordingCallback implements campaign.Callback, recording event names.
+type recordingCallback struct{ events []string }
+
+func (r *recordingCallback) OnCampaignStart(string, []campaign.BeadInfo) {
+	r.events = append(r.events, "start")
+}
+func (r *recordingCallback) OnTaskStart(string) { r.events = append(r.events, "task-start") }
+func (r *recordingCallback) OnTaskComplete(campaign.TaskResult) {
+	r.events = append(r.events, "task-complete")
+}
+func (r *recordingCallback) OnTaskFailed(campaign.TaskResult, error) {
+	r.events = append(r.events, "task-failed")
+}
+func (r *recordingCallback) OnTaskFail(string, error) { r.events = append(r.events, "task-fail") }
+func (r *recordingCallback) OnTaskSkipped(string, string) {
+	r.events = append(r.events, "task-skipped")
+}
+func (r *recordingCallback) OnCampaignPaused(string, string, string) {
+	r.events = append(r.events, "paused")
+}
+func (r *recordingCallback) OnDiscoveryFiled(provider.Finding, string) {
+	r.events = append(r.events, "discovery")
+}
+func (r *recordingCallback) OnValidationStart() { r.events = append(r.events, "validation-start") }
+func (r *recordingCallback) OnValidationPhase(orchestrator.StatusUpdate) {
+	r.events = append(r.events, "validation-phase")
+}
+func (r *recordingCallback) OnValidationComplete(campaign.TaskResult) {
+	r.events = append(r.events, "validation-complete")
+}
+func (r *recordingCallback) OnCircuitBroken(campaign.BreakerTrip) {
+	r.events = append(r.events, "circuit-broken")
+}
+func (r *recordingCallback) OnParentClosed(string) { r.events = append(r.events, "parent-closed") }
+func (r *recordingCallback) OnCampaignComplete(campaign.State) {
+	r.events = append(r.events, "complete")
+}
+
+func TestTracker_CampaignSnapshot(t *testing.T) {
+	// Given a campaign tracker wrapping a display callback
+	tr := NewTracker(KindCampaign, "cap-1")
+	next := &recordingCallback{}
+	cb := tr.Campaign(next)
+	status := tr.StatusCallback(nil)
+
+	// When tasks start, run, finish, and validation begins
+	cb.OnCampaignStart("cap-1", []campaign.BeadInfo{
+		{ID: "cap-1.1", Title: "First"},
+		{ID: "cap-1.2", Title: "Second"},
+		{ID: "cap-1.3", Title: "Third"},
+	})
+	cb.OnTaskStart("cap-1.1")
+	status(orchestrator.StatusUpdate{BeadID: "cap-1.1", Phase: "execute", Status: orchestrator.PhasePassed, Attempt: 1})
+	cb.OnTaskComplete(campaign.TaskResult{BeadID: "cap-1.1", Status: campaign.TaskCompleted})
+	cb.OnTaskStart("cap-1.2")
+	status(orchestrator.StatusUpdate{BeadID: "cap-1.2", Phase: "execute", Status: orchestrator.PhaseRunning, Attempt: 1})
+	cb.(campaign.TaskFailureObserver).OnTaskFailed(campaign.TaskResult{BeadID: "cap-1.2"}, errors.New("execute: NEEDS_WORK"))
+	cb.(campaign.TaskSkipObserver).OnTaskSkipped("cap-1.3", "depends on failed cap-1.2")
+	cb.OnValidationStart()
+	cb.(campaign.ValidationPhaseObserver).OnValidationPhase(orchestrator.StatusUpdate{BeadID: "cap-1-validation", Phase: "feature-review", Status: orchestrator.PhaseRunning, Attempt: 1})
+	cb.OnCampaignComplete(campaign.State{ParentBeadID: "cap-1.2", Status: campaign.CampaignCompleted})
+	cb.OnCampaignComplete(campaign.State{ParentBeadID: "cap-1", Status: campaign.CampaignFailed})
+
+	// Then the snapshot lists each task with its status and pipeline
+	s := tr.Snapshot()
+	if s.Kind != KindCampaign || s.Pipeline != nil {
+		t.Fatalf("snapshot = %+v, want a campaign", s)
+	}
+	c := s.Campaign
+	if c.ParentID != "cap-1" || c.Status != "failed" {
+		t.Errorf("campaign = %s %s, want cap-1 failed (a sub-campaign's completion ignored)", c.ParentID, c.Status)
+	}
+	want := []struct{ id, status, errMsg, reason string }{
+		{"cap-1.1", "completed", "", ""},
+		{"cap-1.2", "failed", "execute: NEEDS_WORK", ""},
+		{"cap-1.3", "skipped", "", "depends on failed cap-1.2"},
+	}
+	if len(c.Tasks) != len(want) {
+		t.Fatalf("tasks = %+v, want %d", c.Tasks, len(want))
+	}
+	for i, w := range want {
+		got := c.Tasks[i]
+		if got.BeadID != w.id || got.Status != w.status || got.Error != w.errMsg || got.Reason != w.reason {
+			t.Errorf("task %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if p := c.Tasks[0].Pipeline; p == nil || len(p.Phases) != 1 {
+		t.Errorf("cap-1.1 pipeline = %+v, want its finished phase", p)
+	}
+	if c.Tasks[2].Pipeline != nil {
+		t.Errorf("cap-1.3 pipeline = %+v, want none for a task that never ran", c.Tasks[2].Pipeline)
+	}
+
+	// And validation embeds the parent's pipeline
+	if v := c.Validation; v == nil || v.BeadID != "cap-1-validation" || v.Phase != "feature-review" || v.Status != "running" {
+		t.Errorf("validation = %+v, want cap-1-validation running feature-review", v)
+	}
+
+	// And every event reached the wrapped callback
+	if len(next.events) != 10 {
+		t.Errorf("forwarded %v, want 10 events", next.events)
+	}
+}
+
+func TestTracker_CampaignPausedAndTripped(t *testing.T) {
+	// Given a running campaign whose display callback implements none of
+	// the optional observer interfaces
+	tr := NewTracker(KindCampaign, "cap-1")
+	next := &recordingCallback{}
+	cb := tr.Campaign(struct{ campaign.Callback }{next})
+
+	// When a task fails, its circuit breaker trips, and it pauses
+	campaign.NotifyTaskFailed(cb, campaign.TaskResult{BeadID: "cap-1.3"}, errors.New("boom"))
+	cb.(campaign.BreakerTripObserver).OnCircuitBroken(campaign.BreakerTrip{Reason: "3 consecutive failures (limit 3)"})
+	cb.OnCampaignPaused("cap-1.4", "interrupted", "")
+
+	// Then the snapshot shows it paused with the latest reason
+	c := tr.Snapshot().Campaign
+	if c.Status != "paused" || c.Reason != "interrupted" {
+		t.Errorf("campaign = %s (%s), want paused (interrupted)", c.Status, c.Reason)
+	}
+	// And the failure reached the display callback through OnTaskFail, and
+	// the trip, which it does not observe, was not forwarded
+	if !slices.Equal(next.events, []string{"task-fail", "paused"}) {
+		t.Errorf("forwarded %v, want [task-fail paused]", next.events)
+	}
+}
+
+func TestTracker_ConcurrentUse(t *testing.T) {
+	// Given a campaign tracker
+	tr := NewTracker(KindCampaign, "cap-1")
+	status := tr.StatusCallback(nil)
+
+	// When pipelines report while snapshots are taken
+	var wg sync.WaitGroup
+	for _, id := range []string{"cap-1.1", "cap-1.2", "cap-1.3"} {
+		wg.Go(func() {
+			for range 50 {
+				status(orchestrator.StatusUpdate{BeadID: id, Phase: "execute", Status: orchestrator.PhasePassed})
+				_ = tr.Snapshot()
+			}
+		})
+	}
+	wg.Wait()
+
+	// Then every pipeline kept its recent phases (run with -race to check locking)
+	for _, id := range []string{"cap-1.1", "cap-1.2", "cap-1.3"} {
+		if n := len(tr.pipelines[id].Phases); n != recentPhases {
+			t.Errorf("%s kept %d phases, want %d", id, n, recentPhases)
+		}
+	}
+}
@@ -2,18 +2,22 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mattn/go-isatty"
+
+	"github.com/smileynet/capsule/internal/provider"
 )
 
 // DisplayEvent is an event sent to a Display via the update channel.
-// Implemented by StatusUpdateMsg, PipelineDoneMsg, and PipelineErrorMsg.
+// Implemented by StatusUpdateMsg, WarningMsg, FindingsMsg, PipelineDoneMsg, and PipelineErrorMsg.
 type DisplayEvent interface {
 	isDisplayEvent()
 }
@@ -21,16 +25,31 @@ type DisplayEvent interface {
 // Verify at compile time that message types implement DisplayEvent.
 var (
 	_ DisplayEvent = StatusUpdateMsg{}
+	_ DisplayEvent = WarningMsg{}
+	_ DisplayEvent = FindingsMsg{}
 	_ DisplayEvent = PipelineDoneMsg{}
 	_ DisplayEvent = PipelineErrorMsg{}
 	_ DisplayEvent = OutputMsg{}
 )
 
+// ErrRetryRequested is returned by Display.Run when the user asked to resume
+// a failed run from its checkpoint.
+var ErrRetryRequested = errors.New("tui: retry requested")
+
 // Display renders pipeline status updates.
 type Display interface {
 	Run(ctx context.Context, events <-chan DisplayEvent) error
 }
 
+// Verbosity sets how much detail plain text output prints.
+type Verbosity string
+
+const (
+	VerbosityQuiet   Verbosity = "quiet"   // One line per finished phase, without signal detail.
+	VerbosityNormal  Verbosity = "normal"  // Running lines, progress, and signal detail; feedback on failures only.
+	VerbosityVerbose Verbosity = "verbose" // Feedback on every phase, and the feedback each retry runs with.
+)
+
 // DisplayOptions configures display creation.
 type DisplayOptions struct {
 	Writer     io.Writer          // Output destination (default: os.Stdout).
@@ -39,6 +58,8 @@ type DisplayOptions struct {
 	CancelFunc context.CancelFunc // Called by TUI on abort keypress (ignored by PlainDisplay).
 	BeadID     string             // Optional bead ID for header display.
 	BeadTitle  string             // Optional bead title for header display.
+	Retry      bool               // Offer r to resume a failed run (TUI only); needs a checkpoint store.
+	Verbosity  Verbosity          // Plain text detail; empty means VerbosityNormal.
 }
 
 // NewDisplay returns a TUI display when stdout is a TTY, or a plain text
@@ -48,8 +69,8 @@ func NewDisplay(opts DisplayOptions) Display {
 		opts.Writer = os.Stdout
 	}
 
-	if opts.ForcePlain || !isTTY(opts.Writer) {
-		return &PlainDisplay{w: opts.Writer}
+	if !UsesTUI(opts) {
+		return &PlainDisplay{w: opts.Writer, verbosity: opts.Verbosity}
 	}
 
 	return &TUIDisplay{
@@ -58,9 +79,20 @@ func NewDisplay(opts DisplayOptions) Display {
 		cancelFunc: opts.CancelFunc,
 		beadID:     opts.BeadID,
 		beadTitle:  opts.BeadTitle,
+		retry:      opts.Retry,
+		verbosity:  opts.Verbosity,
 	}
 }
 
+// UsesTUI reports whether NewDisplay returns the interactive TUI for opts
+// rather than plain text output.
+func UsesTUI(opts DisplayOptions) bool {
+	if opts.Writer == nil {
+		opts.Writer = os.Stdout
+	}
+	return !opts.ForcePlain && isTTY(opts.Writer)
+}
+
 // isTTY reports whether w is connected to a terminal.
 func isTTY(w io.Writer) bool {
 	f, ok := w.(*os.File)
@@ -85,12 +117,32 @@ func (b *Bridge) Events() <-chan DisplayEvent {
 	return b.ch
 }
 
+// Restart opens a fresh event channel after Done or Error so a resumed run
+// can report through the same bridge. Call it only once the previous
+// display has returned.
+func (b *Bridge) Restart() {
+	b.ch = make(chan DisplayEvent, 16)
+}
+
 // Send delivers a StatusUpdateMsg to the display.
 // It blocks if the channel buffer (16) is full.
 func (b *Bridge) Send(msg StatusUpdateMsg) {
 	b.ch <- msg
 }
 
+// Warn delivers a WarningMsg to the display.
+func (b *Bridge) Warn(text string) {
+	b.ch <- WarningMsg{Text: text}
+}
+
+// Findings delivers the pipeline's collected findings to the display.
+// Call it before Done or Error; it sends nothing when there are none.
+func (b *Bridge) Findings(findings []provider.Finding) {
+	if len(findings) > 0 {
+		b.ch <- FindingsMsg{Findings: findings}
+	}
+}
+
 // Done signals successful pipeline completion and closes the channel.
 func (b *Bridge) Done() {
 	b.ch <- PipelineDoneMsg{}
@@ -105,9 +157,17 @@ func (b *Bridge) Error(err error) {
 
 // PlainDisplay renders status updates as timestamped text lines.
 type PlainDisplay struct {
-	w io.Writer
+	w          io.Writer
+	verbosity  Verbosity
+	progressAt map[string]time.Time // When each phase last printed a progress line.
+	feedback   string               // Feedback of the last failed phase, shown on its retry when verbose.
+	usage      provider.Usage       // Tokens consumed across all phase attempts, printed when the run ends.
 }
 
+// progressInterval is the least time between two progress lines PlainDisplay
+// prints for the same phase.
+const progressInterval = 10 * time.Second
+
 // Run loops over events, printing each status update as a text line.
 // Returns the pipeline error if the pipeline failed, or context error if cancelled.
 func (d *PlainDisplay) Run(ctx context.Context, events <-chan DisplayEvent) error {
@@ -122,26 +182,88 @@ func (d *PlainDisplay) Run(ctx context.Context, events <-chan DisplayEvent) erro
 			switch msg := ev.(type) {
 			case StatusUpdateMsg:
 				d.renderUpdate(msg)
+			case WarningMsg:
+				_, _ = fmt.Fprintf(d.w, "[%s] warning: %s\n", time.Now().Format("15:04:05"), msg.Text)
+			case FindingsMsg:
+				_, _ = fmt.Fprintln(d.w, "\nFindings:")
+				for _, f := range msg.Findings {
+					_, _ = fmt.Fprintf(d.w, "  %s\n", FormatFinding(f))
+				}
 			case OutputMsg:
 				// Detail output is TUI-only; ignored in plain text mode.
 			case PipelineDoneMsg:
+				d.renderUsage()
 				return nil
 			case PipelineErrorMsg:
+				d.renderUsage()
 				return msg.Err
 			}
 		}
 	}
 }
 
+// renderUsage prints the run's total usage, if the provider reported any.
+func (d *PlainDisplay) renderUsage() {
+	if !d.usage.IsZero() {
+		_, _ = fmt.Fprintf(d.w, "Usage: %s\n", d.usage)
+	}
+}
+
 func (d *PlainDisplay) renderUpdate(su StatusUpdateMsg) {
-	ts := time.Now().Format("15:04:05")
+	now := time.Now()
+	ts := now.Format("15:04:05")
+	quiet := d.verbosity == VerbosityQuiet
+	if su.Status == StatusProgress {
+		if quiet {
+			return
+		}
+		if last, ok := d.progressAt[su.Phase]; ok && now.Sub(last) < progressInterval {
+			return
+		}
+		if d.progressAt == nil {
+			d.progressAt = make(map[string]time.Time)
+		}
+		d.progressAt[su.Phase] = now
+		_, _ = fmt.Fprintf(d.w, "[%s] %s: %s\n", ts, su.Phase, su.Message)
+		return
+	}
+	if su.Status == StatusRetrying {
+		if !quiet {
+			_, _ = fmt.Fprintf(d.w, "[%s] %s: %s\n", ts, su.Phase, su.Message)
+		}
+		return
+	}
+	delete(d.progressAt, su.Phase)
+	d.usage = d.usage.Add(su.Usage)
+	failed := su.Status == StatusFailed || su.Status == StatusError
+	if failed && su.Feedback != "" {
+		d.feedback = su.Feedback
+	}
+	if quiet && su.Status == StatusRunning {
+		return
+	}
 	retry := ""
 	if su.Attempt > 1 {
 		retry = fmt.Sprintf(" (attempt %d/%d)", su.Attempt, su.MaxRetry)
 	}
-	_, _ = fmt.Fprintf(d.w, "[%s] [%s] %s %s%s\n", ts, su.Progress, su.Phase, su.Status, retry)
+	reason := ""
+	if su.Status == StatusSkipped && su.SkipReason != "" {
+		reason = " — " + su.SkipReason
+	}
+	progress := su.Progress
+	if su.PhaseTotal > 0 {
+		progress += fmt.Sprintf(" %d%%", int(math.Round(su.FractionComplete*100)))
+	}
+	_, _ = fmt.Fprintf(d.w, "[%s] [%s] %s %s%s%s\n", ts, progress, phaseLabel(su.Phase), su.Status, retry, reason)
 
 	if su.Status == StatusRunning {
+		if d.verbosity == VerbosityVerbose && su.Attempt > 1 && d.feedback != "" {
+			_, _ = fmt.Fprintf(d.w, "         retrying with feedback: %s\n", d.feedback)
+		}
+		d.feedback = ""
+		return
+	}
+	if quiet {
 		return
 	}
 
@@ -151,10 +273,17 @@ func (d *PlainDisplay) renderUpdate(su StatusUpdateMsg) {
 	if su.Summary != "" {
 		_, _ = fmt.Fprintf(d.w, "         summary: %s\n", su.Summary)
 	}
-	// Feedback is only meaningful for failed/error phases (NEEDS_WORK from orchestrator).
-	if su.Feedback != "" && (su.Status == StatusFailed || su.Status == StatusError) {
+	// Feedback is only meaningful for failed/error phases (NEEDS_WORK from
+	// orchestrator) unless everything is asked for. A failed artifact check
+	// replaces it, since the check wrote the feedback.
+	if len(su.MissingArtifacts) > 0 {
+		_, _ = fmt.Fprintf(d.w, "         artifact check failed: missing %s\n", strings.Join(su.MissingArtifacts, ", "))
+	} else if su.Feedback != "" && (failed || d.verbosity == VerbosityVerbose) {
 		_, _ = fmt.Fprintf(d.w, "         feedback: %s\n", su.Feedback)
 	}
+	if !su.Usage.IsZero() {
+		_, _ = fmt.Fprintf(d.w, "         usage: %s\n", su.Usage)
+	}
 }
 
 // TUIDisplay renders status updates using a Bubble Tea terminal UI.
@@ -165,10 +294,13 @@ type TUIDisplay struct {
 	cancelFunc context.CancelFunc
 	beadID     string
 	beadTitle  string
+	retry      bool
+	verbosity  Verbosity // For the plain text fallback.
 }
 
 // Run starts the Bubble Tea program and feeds events from the channel.
 // If the TUI fails to initialize, it falls back to plain text output.
+// Returns ErrRetryRequested when the user pressed r on a failure summary.
 func (d *TUIDisplay) Run(ctx context.Context, events <-chan DisplayEvent) error {
 	var opts []ModelOption
 	if d.cancelFunc != nil {
@@ -177,6 +309,9 @@ func (d *TUIDisplay) Run(ctx context.Context, events <-chan DisplayEvent) error
 	if d.beadID != "" {
 		opts = append(opts, WithBeadHeader(d.beadID, d.beadTitle))
 	}
+	if d.retry {
+		opts = append(opts, WithRetry())
+	}
 	model := NewModel(d.phases, opts...)
 	p := tea.NewProgram(model, tea.WithOutput(d.w))
 
@@ -202,13 +337,16 @@ func (d *TUIDisplay) Run(ctx context.Context, events <-chan DisplayEvent) error
 		}
 	}()
 
-	_, err := p.Run()
+	final, err := p.Run()
 	if err != nil {
 		close(stop)
 		// Fall back to plain text for remaining events from the original channel.
-		plain := &PlainDisplay{w: d.w}
+		plain := &PlainDisplay{w: d.w, verbosity: d.verbosity}
 		return plain.Run(ctx, events)
 	}
 
+	if fm, ok := final.(Model); ok && fm.RetryRequested() {
+		return ErrRetryRequested
+	}
 	return nil
 }
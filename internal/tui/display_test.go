@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/smileynet/capsule/internal/provider"
 )
 
 // --- isTTY ---
@@ -87,6 +89,22 @@ func TestBridge_ErrorSendsPipelineErrorAndCloses(t *testing.T) {
 	}
 }
 
+func TestBridge_RestartReopensAfterError(t *testing.T) {
+	b := NewBridge()
+	go b.Error(errors.New("phase failed"))
+	for range b.Events() {
+	}
+
+	// A resumed run reports through the same bridge.
+	b.Restart()
+	go b.Send(StatusUpdateMsg{Phase: "execute", Status: StatusPassed})
+
+	got := <-b.Events()
+	if su, ok := got.(StatusUpdateMsg); !ok || su.Phase != "execute" {
+		t.Fatalf("after Restart got %#v, want execute StatusUpdateMsg", got)
+	}
+}
+
 func TestBridge_MultipleEvents(t *testing.T) {
 	b := NewBridge()
 
@@ -144,6 +162,42 @@ func TestPlainDisplay_RendersStatusUpdate(t *testing.T) {
 	}
 }
 
+func TestPlainDisplay_RendersWarning(t *testing.T) {
+	var buf bytes.Buffer
+	d := &PlainDisplay{w: &buf}
+
+	b := NewBridge()
+	go func() {
+		b.Warn("cap-7 changed src/a.go")
+		b.Done()
+	}()
+
+	if err := d.Run(context.Background(), b.Events()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "warning: cap-7 changed src/a.go") {
+		t.Errorf("output = %q, want warning line", buf.String())
+	}
+}
+
+func TestPlainDisplay_RendersFindings(t *testing.T) {
+	var buf bytes.Buffer
+	d := &PlainDisplay{w: &buf}
+
+	b := NewBridge()
+	go func() {
+		b.Findings([]provider.Finding{{Title: "Missing docs", Severity: "minor", Description: "README is stale"}})
+		b.Done()
+	}()
+
+	if err := d.Run(context.Background(), b.Events()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Findings:\n  [minor] Missing docs: README is stale\n") {
+		t.Errorf("output = %q, want findings section", buf.String())
+	}
+}
+
 func TestPlainDisplay_RendersRetryInfo(t *testing.T) {
 	var buf bytes.Buffer
 	d := &PlainDisplay{w: &buf}
@@ -170,6 +224,26 @@ func TestPlainDisplay_RendersRetryInfo(t *testing.T) {
 	}
 }
 
+func TestPlainDisplay_RendersSkipReason(t *testing.T) {
+	// Given a phase skipped with a reason
+	var buf bytes.Buffer
+	d := &PlainDisplay{w: &buf}
+	ch := make(chan DisplayEvent, 2)
+	ch <- StatusUpdateMsg{Phase: "frontend", Status: StatusSkipped, Progress: "3/6", SkipReason: "no frontend files"}
+	ch <- PipelineDoneMsg{}
+	close(ch)
+
+	// When it is rendered
+	if err := d.Run(context.Background(), ch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then the reason follows the status
+	if !strings.Contains(buf.String(), "[3/6] frontend skipped — no frontend files\n") {
+		t.Errorf("output missing the skip reason:\n%s", buf.String())
+	}
+}
+
 func TestPlainDisplay_RendersSignalData(t *testing.T) {
 	var buf bytes.Buffer
 	d := &PlainDisplay{w: &buf}
@@ -199,6 +273,40 @@ func TestPlainDisplay_RendersSignalData(t *testing.T) {
 	}
 }
 
+func TestPlainDisplay_RendersUsage(t *testing.T) {
+	// Given two finished phases that reported usage and one that did not
+	var buf bytes.Buffer
+	d := &PlainDisplay{w: &buf}
+	ch := make(chan DisplayEvent, 4)
+	ch <- StatusUpdateMsg{Phase: "plan", Status: StatusPassed, Progress: "1/3", Usage: provider.Usage{InputTokens: 100, OutputTokens: 10, CostUSD: 0.01}}
+	ch <- StatusUpdateMsg{Phase: "lint", Status: StatusPassed, Progress: "2/3"}
+	ch <- StatusUpdateMsg{Phase: "code", Status: StatusPassed, Progress: "3/3", Usage: provider.Usage{InputTokens: 200, OutputTokens: 20, CostUSD: 0.02}}
+	ch <- PipelineDoneMsg{}
+	close(ch)
+
+	// When the run is rendered
+	if err := d.Run(context.Background(), ch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Then each phase's usage is printed under it and the total at the end
+	out := buf.String()
+	for _, want := range []string{
+		"usage: 100 in / 10 out tokens, $0.0100",
+		"usage: 200 in / 20 out tokens, $0.0200",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "usage:"); n != 2 {
+		t.Errorf("printed %d phase usage lines, want 2:\n%s", n, out)
+	}
+	if !strings.HasSuffix(out, "Usage: 300 in / 30 out tokens, $0.0300\n") {
+		t.Errorf("output should end with the total usage:\n%s", out)
+	}
+}
+
 func TestPlainDisplay_RendersFeedbackOnFailure(t *testing.T) {
 	var buf bytes.Buffer
 	d := &PlainDisplay{w: &buf}
@@ -364,3 +472,54 @@ func TestNewDisplay_DefaultsWriterToStdout(t *testing.T) {
 		t.Error("default Writer should be os.Stdout")
 	}
 }
+
+func TestPlainDisplay_Verbosity(t *testing.T) {
+	// A writer passes, a reviewer sends it back, and the retry passes.
+	updates := []StatusUpdateMsg{
+		{Phase: "test-writer", Status: StatusRunning, Progress: "1/2", Attempt: 1, MaxRetry: 3},
+		{Phase: "test-writer", Status: StatusProgress, Message: "reading files"},
+		{Phase: "test-writer", Status: StatusRetrying, Message: "retrying in 2s (rate limited)"},
+		{Phase: "test-writer", Status: StatusPassed, Progress: "1/2", Attempt: 1, MaxRetry: 3, Summary: "wrote tests", FilesChanged: []string{"a_test.go"}, Feedback: "kept it small"},
+		{Phase: "test-review", Status: StatusRunning, Progress: "2/2", Attempt: 1, MaxRetry: 3},
+		{Phase: "test-review", Status: StatusFailed, Progress: "2/2", Attempt: 1, MaxRetry: 3, Summary: "missing cases", Feedback: "add edge cases"},
+		{Phase: "test-writer", Status: StatusRunning, Progress: "1/2", Attempt: 2, MaxRetry: 3},
+		{Phase: "test-writer", Status: StatusPassed, Progress: "1/2", Attempt: 2, MaxRetry: 3, Summary: "added cases", FilesChanged: []string{"a_test.go"}},
+	}
+	tests := []struct {
+		verbosity Verbosity
+		wantLines int
+		want      string // A line fragment only this level prints.
+	}{
+		{verbosity: VerbosityQuiet, wantLines: 3},
+		{verbosity: "", wantLines: 14, want: "test-writer: retrying in 2s (rate limited)"},
+		{verbosity: VerbosityNormal, wantLines: 14, want: "summary: wrote tests"},
+		{verbosity: VerbosityVerbose, wantLines: 16, want: "retrying with feedback: add edge cases"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.verbosity), func(t *testing.T) {
+			// Given a plain display at the verbosity
+			var buf bytes.Buffer
+			d := &PlainDisplay{w: &buf, verbosity: tt.verbosity}
+			ch := make(chan DisplayEvent, len(updates)+1)
+			for _, su := range updates {
+				ch <- su
+			}
+			ch <- PipelineDoneMsg{}
+			close(ch)
+
+			// When the updates are rendered
+			if err := d.Run(context.Background(), ch); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Then the level decides how many lines are printed
+			out := buf.String()
+			if got := strings.Count(out, "\n"); got != tt.wantLines {
+				t.Errorf("lines = %d, want %d:\n%s", got, tt.wantLines, out)
+			}
+			if tt.want != "" && !strings.Contains(out, tt.want) {
+				t.Errorf("output missing %q:\n%s", tt.want, out)
+			}
+		})
+	}
+}
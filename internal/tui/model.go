@@ -3,12 +3,16 @@ package tui
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/smileynet/capsule/internal/progressbar"
+	"github.com/smileynet/capsule/internal/provider"
 )
 
 // detailHeaderHeight is the number of lines reserved for the phase list and
@@ -27,8 +31,26 @@ const (
 	StatusFailed  PhaseStatus = "failed"
 	StatusError   PhaseStatus = "error"
 	StatusSkipped PhaseStatus = "skipped"
+	// StatusProgress carries a running phase's latest provider message in
+	// StatusUpdateMsg.Message; the phase stays running.
+	StatusProgress PhaseStatus = "progress"
+	// StatusRetrying reports a wait before retrying a transient provider
+	// failure, described in StatusUpdateMsg.Message; the phase stays running.
+	StatusRetrying PhaseStatus = "retrying"
 )
 
+// BaselinePhase mirrors orchestrator.BaselinePhase, the pseudo-phase that
+// checks every gate before the first phase when --pregate is set.
+const BaselinePhase = "baseline"
+
+// phaseLabel returns the name a phase is shown under.
+func phaseLabel(name string) string {
+	if name == BaselinePhase {
+		return "baseline checks"
+	}
+	return name
+}
+
 // Lipgloss styles for phase status display.
 var (
 	passedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
@@ -36,19 +58,28 @@ var (
 	runningStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
 	pendingStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 	skippedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	flaggedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
 	durationStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
 	retryStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
 	detailStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
 	headerStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 )
 
+// progressStyles colors the header's progress bar.
+var progressStyles = progressbar.Styles{Filled: passedStyle, Empty: pendingStyle, Percent: durationStyle}
+
 // PhaseState tracks the display state of a single pipeline phase.
 type PhaseState struct {
-	Name     string
-	Status   PhaseStatus
-	Attempt  int
-	MaxRetry int
-	Duration time.Duration
+	Name             string
+	Status           PhaseStatus
+	Attempt          int
+	MaxRetry         int
+	Duration         time.Duration
+	StartedAt        time.Time // When the running update for the current attempt arrived.
+	Activity         string    // Latest progress message while running; cleared when the attempt ends.
+	MissingArtifacts []string  // Globs the last attempt's artifact check found no file for; cleared when the phase runs again.
+	SkipRequested    bool      // Skipped by --skip-phases or --only-phases rather than by a condition or checkpoint.
+	SkipReason       string    // Why the phase was skipped, when known.
 }
 
 // elapsedTickMsg is sent every second to update the elapsed time display
@@ -64,22 +95,29 @@ func elapsedTickCmd() tea.Cmd {
 
 // Model is the Bubble Tea model for pipeline phase status display.
 type Model struct {
-	phases         []PhaseState
-	spinner        spinner.Model
-	currentIdx     int // Tracks active phase index for future scroll/focus support.
-	done           bool
-	aborting       bool
-	err            error
-	cancelFunc     context.CancelFunc // Called on first abort keypress; nil means immediate quit.
-	startTime      time.Time          // Records model creation for future elapsed-time display.
-	phaseStartedAt time.Time          // Timestamp when the current running phase started.
-	width          int                // Terminal width from WindowSizeMsg; 0 means not yet received.
-	height         int                // Terminal height from WindowSizeMsg; 0 means not yet received.
-	detailVisible  bool               // Whether the detail panel is shown.
-	detailContent  string             // Raw output content for the detail panel.
-	viewport       viewport.Model     // Scrollable viewport for the detail panel.
-	beadID         string             // Bead ID shown in header (optional).
-	beadTitle      string             // Bead title shown in header (optional).
+	phases        []PhaseState
+	spinner       spinner.Model
+	currentIdx    int // Tracks active phase index for future scroll/focus support.
+	done          bool
+	aborting      bool
+	err           error
+	cancelFunc    context.CancelFunc // Called on first abort keypress; nil means immediate quit.
+	startTime     time.Time          // Records model creation for future elapsed-time display.
+	ticking       bool               // An elapsedTickMsg is pending; at most one tick chain runs at a time.
+	width         int                // Terminal width from WindowSizeMsg; 0 means not yet received.
+	height        int                // Terminal height from WindowSizeMsg; 0 means not yet received.
+	detailVisible bool               // Whether the detail panel is shown.
+	detailContent string             // Raw output content for the detail panel.
+	usage         provider.Usage     // Tokens consumed across all phase attempts so far.
+	viewport      viewport.Model     // Scrollable viewport for the detail panel.
+	beadID        string             // Bead ID shown in header (optional).
+	beadTitle     string             // Bead title shown in header (optional).
+	warnings      []string           // Notices shown above the phase list.
+	findings      []provider.Finding // Reviewer findings shown in the summary footer.
+	retryEnabled  bool               // Offer r on the failure summary; requires a checkpoint store.
+	retry         bool               // The user pressed r; the caller should resume the run.
+	fraction      float64            // Share of the pipeline done, from the latest update that carried it.
+	fractionKnown bool               // An update carried the pipeline's position; the header shows a progress bar.
 }
 
 // ModelOption configures the Model.
@@ -102,21 +140,51 @@ func WithBeadHeader(id, title string) ModelOption {
 	}
 }
 
+// WithRetry keeps the summary open after a failure and offers r to resume
+// the run from its checkpoint. Only set it when a checkpoint store is
+// configured; without one the key is neither shown nor handled.
+func WithRetry() ModelOption {
+	return func(m *Model) {
+		m.retryEnabled = true
+	}
+}
+
+// RetryRequested reports whether the user asked to resume the failed run.
+func (m Model) RetryRequested() bool {
+	return m.retry
+}
+
 // StatusUpdateMsg bridges orchestrator status updates to the TUI.
 type StatusUpdateMsg struct {
-	Phase        string
-	Status       PhaseStatus
-	Attempt      int
-	MaxRetry     int
-	Duration     time.Duration
-	Progress     string   // Human-readable progress (e.g. "2/6").
-	Summary      string   // Phase summary text.
-	FilesChanged []string // Files modified in this phase.
-	Feedback     string   // Feedback for retries (shown on failure).
+	Phase            string
+	Status           PhaseStatus
+	Attempt          int
+	MaxRetry         int
+	Duration         time.Duration
+	Usage            provider.Usage // Tokens the phase attempt consumed (zero while running).
+	Progress         string         // Human-readable progress (e.g. "2/6").
+	PhaseIndex       int            // 1-based position of the phase in the pipeline; 0 when unknown.
+	PhaseTotal       int            // Number of phases in the pipeline; 0 when the update carries no position.
+	FractionComplete float64        // Share of the pipeline done, 0 to 1; meaningful only when PhaseTotal > 0.
+	Summary          string         // Phase summary text.
+	FilesChanged     []string       // Files modified in this phase.
+	Feedback         string         // Feedback for retries (shown on failure).
+	Message          string         // What the provider is doing; set only for StatusProgress and StatusRetrying.
+	MissingArtifacts []string       // Required artifact globs that matched no file; set when the artifact check failed the phase.
+	SkipRequested    bool           // The user asked to skip the phase; set only for StatusSkipped.
+	SkipReason       string         // Why the phase was skipped, when known; set only for StatusSkipped.
 }
 
 func (StatusUpdateMsg) isDisplayEvent() {}
 
+// WarningMsg carries a notice not tied to a phase, such as files that other
+// in-flight capsules have changed.
+type WarningMsg struct {
+	Text string
+}
+
+func (WarningMsg) isDisplayEvent() {}
+
 // PipelineDoneMsg signals that the pipeline completed successfully.
 type PipelineDoneMsg struct{}
 
@@ -136,6 +204,23 @@ type OutputMsg struct {
 
 func (OutputMsg) isDisplayEvent() {}
 
+// FindingsMsg carries the findings collected over the whole pipeline. It is
+// sent once, just before the pipeline's done or error event.
+type FindingsMsg struct {
+	Findings []provider.Finding
+}
+
+func (FindingsMsg) isDisplayEvent() {}
+
+// FormatFinding renders a finding as "[severity] title: description".
+func FormatFinding(f provider.Finding) string {
+	s := fmt.Sprintf("[%s] %s", f.Severity, f.Title)
+	if f.Description != "" {
+		s += ": " + f.Description
+	}
+	return s
+}
+
 // NewModel creates a Model initialized with the given phase names.
 func NewModel(phaseNames []string, opts ...ModelOption) Model {
 	s := spinner.New()
@@ -158,18 +243,29 @@ func NewModel(phaseNames []string, opts ...ModelOption) Model {
 	return m
 }
 
-// Init starts the spinner and elapsed-time ticks.
+// Init starts the spinner. Elapsed-time ticks start when a phase runs.
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.spinner.Tick, elapsedTickCmd())
+	return m.spinner.Tick
 }
 
 // Update handles incoming messages.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case StatusUpdateMsg:
+		if msg.PhaseTotal > 0 {
+			m.fraction, m.fractionKnown = msg.FractionComplete, true
+		}
 		for i := range m.phases {
 			if m.phases[i].Name == msg.Phase {
+				if msg.Status == StatusProgress || msg.Status == StatusRetrying {
+					m.phases[i].Activity = msg.Message
+					break
+				}
 				m.phases[i].Status = msg.Status
+				m.phases[i].Activity = ""
+				m.phases[i].MissingArtifacts = msg.MissingArtifacts
+				m.phases[i].SkipRequested = msg.SkipRequested
+				m.phases[i].SkipReason = msg.SkipReason
 				if msg.Attempt > 0 {
 					m.phases[i].Attempt = msg.Attempt
 				}
@@ -179,19 +275,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if msg.Duration > 0 {
 					m.phases[i].Duration = msg.Duration
 				}
+				m.usage = m.usage.Add(msg.Usage)
 				if msg.Status == StatusRunning {
+					// A retry restarts the counter; the final Duration arrives with completion.
 					m.currentIdx = i
-					m.phaseStartedAt = time.Now()
+					m.phases[i].StartedAt = time.Now()
+					m.phases[i].Duration = 0
 				}
 				break
 			}
 		}
+		if m.ticking || !m.anyRunning() {
+			return m, nil
+		}
+		m.ticking = true
+		return m, elapsedTickCmd()
+
+	case WarningMsg:
+		m.warnings = append(m.warnings, msg.Text)
 		return m, nil
 
 	case elapsedTickMsg:
-		if !m.phaseStartedAt.IsZero() && !m.done {
+		// The tick itself triggers the re-render; keep ticking only while a
+		// phase is running so the summary screen stops redrawing.
+		if m.anyRunning() && !m.done {
 			return m, elapsedTickCmd()
 		}
+		m.ticking = false
+		return m, nil
+
+	case FindingsMsg:
+		m.findings = msg.Findings
 		return m, nil
 
 	case OutputMsg:
@@ -208,12 +322,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case PipelineErrorMsg:
 		m.done = true
 		m.err = msg.Err
+		if m.canRetry() {
+			// Stay on the summary until the user retries or quits.
+			return m, nil
+		}
 		return m, tea.Quit
 
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "q", "ctrl+c":
 			if m.done {
+				if m.canRetry() {
+					// The failure summary was held open for retry.
+					return m, tea.Quit
+				}
 				return m, nil
 			}
 			if m.aborting || m.cancelFunc == nil {
@@ -223,6 +345,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.aborting = true
 			m.cancelFunc()
 			return m, nil
+		case "r":
+			if m.canRetry() {
+				m.retry = true
+				return m, tea.Quit
+			}
+			return m, nil
 		case "d":
 			if !m.done {
 				m.detailVisible = !m.detailVisible
@@ -259,26 +387,42 @@ func (m Model) View() string {
 	if m.beadID != "" {
 		s += headerStyle.Render(m.beadID+"  "+m.beadTitle) + "\n"
 	}
+	if m.fractionKnown {
+		s += "  " + progressbar.Render(m.fraction, progressbar.Width, progressStyles) + "\n"
+	}
+
+	for _, w := range m.warnings {
+		s += runningStyle.Render("  ⚠ "+w) + "\n"
+	}
 
 	for _, phase := range m.phases {
 		indicator := styledIndicator(phase.Status, m.spinner.View())
-		name := styledPhaseName(phase.Status, phase.Name)
+		name := styledPhaseName(phase.Status, phaseLabel(phase.Name))
+		if phase.SkipRequested {
+			indicator = flaggedStyle.Render("–")
+			name += flaggedStyle.Render(" skipped (flag)")
+		} else if phase.Status == StatusSkipped && phase.SkipReason != "" {
+			name += skippedStyle.Render(" skipped — " + phase.SkipReason)
+		}
 		line := fmt.Sprintf("  %s %s", indicator, name)
 
 		if phase.Attempt > 1 {
 			line += retryStyle.Render(fmt.Sprintf(" (%d/%d)", phase.Attempt, phase.MaxRetry))
 		}
 
-		if phase.Status == StatusRunning && !m.phaseStartedAt.IsZero() && !m.aborting {
-			elapsed := int(time.Since(m.phaseStartedAt).Seconds())
-			line += durationStyle.Render(fmt.Sprintf(" (%ds)", elapsed))
-		}
-
-		if phase.Duration > 0 {
+		if phase.Status == StatusRunning && !phase.StartedAt.IsZero() && !m.aborting {
+			line += durationStyle.Render(" " + formatElapsed(time.Since(phase.StartedAt)))
+		} else if phase.Duration > 0 {
 			line += durationStyle.Render(fmt.Sprintf(" %.1fs", phase.Duration.Seconds()))
 		}
 
 		s += line + "\n"
+		if phase.Status == StatusRunning && phase.Activity != "" && !m.aborting {
+			s += detailStyle.Render("      "+phase.Activity) + "\n"
+		}
+		if len(phase.MissingArtifacts) > 0 {
+			s += failedStyle.Render("      artifact check failed: missing "+strings.Join(phase.MissingArtifacts, ", ")) + "\n"
+		}
 	}
 
 	if m.aborting && !m.done {
@@ -326,14 +470,52 @@ func (m Model) renderFooter() string {
 		}
 		footer += "\n"
 	}
+	if !m.usage.IsZero() {
+		footer += durationStyle.Render("  Usage: "+m.usage.String()) + "\n"
+	}
+	if len(m.findings) > 0 {
+		footer += "\n  Findings:\n"
+		for _, f := range m.findings {
+			footer += "    " + FormatFinding(f) + "\n"
+		}
+	}
+	if m.canRetry() {
+		footer += "\n" + detailStyle.Render("  r retry from failed phase · q quit") + "\n"
+	}
 
 	return footer
 }
 
-// phaseCounts returns the number of passed phases and total phases.
+// canRetry reports whether the finished run failed and can be resumed.
+// Aborted runs are not offered a retry: the user asked to stop.
+func (m Model) canRetry() bool {
+	return m.retryEnabled && m.done && m.err != nil && !m.aborting
+}
+
+// anyRunning reports whether a phase is running, i.e. the elapsed counter needs ticks.
+func (m Model) anyRunning() bool {
+	for _, p := range m.phases {
+		if p.Status == StatusRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// formatElapsed renders d as mm:ss.
+func formatElapsed(d time.Duration) string {
+	secs := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d", secs/60, secs%60)
+}
+
+// phaseCounts returns the number of passed phases and total phases,
+// leaving out the baseline checks.
 func (m Model) phaseCounts() (passed, total int) {
-	total = len(m.phases)
 	for _, p := range m.phases {
+		if p.Name == BaselinePhase {
+			continue
+		}
+		total++
 		if p.Status == StatusPassed {
 			passed++
 		}
@@ -9,6 +9,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+
+	"github.com/smileynet/capsule/internal/provider"
 )
 
 func TestNewModel_InitializesPhases(t *testing.T) {
@@ -75,6 +77,30 @@ func TestModel_Update_StatusUpdateMsg_Running(t *testing.T) {
 	}
 }
 
+func TestModel_Update_StatusUpdateMsg_Progress(t *testing.T) {
+	// Given a running phase
+	m := NewModel([]string{"test-writer"})
+	newModel, _ := m.Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusRunning, Attempt: 1})
+
+	// When progress arrives
+	newModel, _ = newModel.Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusProgress, Message: "Reading the tests"})
+	updated := newModel.(Model)
+
+	// Then the phase stays running and shows the message beneath it
+	if updated.phases[0].Status != StatusRunning {
+		t.Errorf("phase status = %q, want %q", updated.phases[0].Status, StatusRunning)
+	}
+	if !strings.Contains(updated.View(), "Reading the tests") {
+		t.Errorf("View() missing progress message:\n%s", updated.View())
+	}
+
+	// And the message is gone once the phase finishes
+	newModel, _ = updated.Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusPassed})
+	if view := newModel.(Model).View(); strings.Contains(view, "Reading the tests") {
+		t.Errorf("View() still shows progress after the phase passed:\n%s", view)
+	}
+}
+
 func TestModel_Update_StatusUpdateMsg_Transitions(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -229,6 +255,58 @@ func TestModel_View_BeadHeader(t *testing.T) {
 	}
 }
 
+func TestModel_View_ProgressBar(t *testing.T) {
+	// Given a model with a bead header and no updates yet
+	m := NewModel([]string{"test-writer", "test-review"}, WithBeadHeader("cap-042", "Fix login bug"))
+
+	// Then no progress bar is shown
+	if strings.Contains(m.View(), "%") {
+		t.Errorf("view before any update should have no progress bar:\n%s", m.View())
+	}
+
+	// When the first of two phases passes
+	updated, _ := m.Update(StatusUpdateMsg{
+		Phase: "test-writer", Status: StatusPassed,
+		PhaseIndex: 1, PhaseTotal: 2, FractionComplete: 0.5,
+	})
+	// And the next reports progress without a position
+	updated, _ = updated.(Model).Update(StatusUpdateMsg{Phase: "test-review", Status: StatusProgress, Message: "reading"})
+
+	// Then the header is followed by a half-filled bar
+	lines := strings.Split(updated.(Model).View(), "\n")
+	want := strings.Repeat("█", 10) + strings.Repeat("░", 10) + " 50%"
+	if len(lines) < 2 || !strings.Contains(lines[1], want) {
+		t.Errorf("second line should be the progress bar %q, got:\n%s", want, strings.Join(lines, "\n"))
+	}
+}
+
+func TestModel_View_Warning(t *testing.T) {
+	m := NewModel([]string{"test-writer"}, WithBeadHeader("cap-042", "Fix login bug"))
+
+	updated, _ := m.Update(WarningMsg{Text: "cap-7 changed src/a.go"})
+	view := updated.(Model).View()
+
+	lines := strings.Split(view, "\n")
+	if len(lines) < 2 || !strings.Contains(lines[1], "cap-7 changed src/a.go") {
+		t.Errorf("warning should follow the header, got:\n%s", view)
+	}
+}
+
+func TestModel_View_BaselineChecks(t *testing.T) {
+	// Given a pipeline with baseline checks before its phases
+	m := NewModel([]string{BaselinePhase, "execute"})
+
+	// When the baseline fails
+	updated, _ := m.Update(StatusUpdateMsg{Phase: BaselinePhase, Status: StatusFailed})
+	view := updated.(Model).View()
+
+	// Then it is shown as baseline checks above the phases
+	lines := strings.Split(view, "\n")
+	if len(lines) < 2 || !strings.Contains(lines[0], "baseline checks") || !strings.Contains(lines[0], "✗") {
+		t.Errorf("first line should show failed baseline checks, got:\n%s", view)
+	}
+}
+
 func TestModel_View_NoBeadHeader_WhenEmpty(t *testing.T) {
 	m := NewModel([]string{"test-writer"})
 
@@ -274,6 +352,42 @@ func TestModel_View_StatusIndicators(t *testing.T) {
 	}
 }
 
+func TestModel_View_SkipRequested(t *testing.T) {
+	// Given a phase skipped by a condition and one skipped by --skip-phases
+	m := NewModel([]string{"lint", "test-writer"})
+	updated, _ := m.Update(StatusUpdateMsg{Phase: "lint", Status: StatusSkipped})
+	updated, _ = updated.(Model).Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusSkipped, SkipRequested: true})
+
+	// When the view renders
+	lines := strings.Split(updated.(Model).View(), "\n")
+
+	// Then only the user-skipped phase is labelled
+	if strings.Contains(lines[0], "(flag)") {
+		t.Errorf("condition skip labelled as flagged: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "test-writer skipped (flag)") {
+		t.Errorf("flagged skip = %q, want it labelled", lines[1])
+	}
+}
+
+func TestModel_View_SkipReason(t *testing.T) {
+	// Given a phase skipped with a reason and one skipped without
+	m := NewModel([]string{"frontend", "docs"})
+	updated, _ := m.Update(StatusUpdateMsg{Phase: "frontend", Status: StatusSkipped, SkipReason: "no frontend files"})
+	updated, _ = updated.(Model).Update(StatusUpdateMsg{Phase: "docs", Status: StatusSkipped})
+
+	// When the view renders
+	lines := strings.Split(updated.(Model).View(), "\n")
+
+	// Then the reason follows the skipped phase's name, and no reason adds nothing
+	if !strings.Contains(lines[0], "frontend skipped — no frontend files") {
+		t.Errorf("skipped phase = %q, want its reason", lines[0])
+	}
+	if strings.Contains(lines[1], "skipped") {
+		t.Errorf("skip without reason = %q, want the bare name", lines[1])
+	}
+}
+
 func TestModel_View_WithRetryInfo(t *testing.T) {
 	m := NewModel([]string{"test-writer"})
 	m.phases[0].Status = StatusRunning
@@ -415,6 +529,58 @@ func TestModel_View_SummaryFooter_TotalDuration(t *testing.T) {
 	}
 }
 
+func TestModel_View_SummaryFooter_Usage(t *testing.T) {
+	// Given a retried phase that reported usage on each attempt
+	var model tea.Model = NewModel([]string{"phase1"})
+	for _, in := range []int{100, 250} {
+		model, _ = model.Update(StatusUpdateMsg{Phase: "phase1", Status: StatusRunning})
+		model, _ = model.Update(StatusUpdateMsg{
+			Phase: "phase1", Status: StatusPassed, Duration: time.Second,
+			Usage: provider.Usage{InputTokens: in, OutputTokens: 10},
+		})
+	}
+	m := model.(Model)
+	m.done = true
+
+	// When the view is rendered
+	view := m.View()
+
+	// Then the footer shows usage summed across attempts
+	if !strings.Contains(view, "Usage: 350 in / 20 out tokens") {
+		t.Errorf("footer should show total usage, got:\n%s", view)
+	}
+}
+
+func TestModel_View_SummaryFooter_NoUsage(t *testing.T) {
+	// Given a finished pipeline whose provider reported no usage
+	m := NewModel([]string{"phase1"})
+	m.phases[0].Status = StatusPassed
+	m.done = true
+
+	// When the view is rendered
+	view := m.View()
+
+	// Then no usage line is shown
+	if strings.Contains(view, "Usage:") {
+		t.Errorf("footer should omit usage when none was reported, got:\n%s", view)
+	}
+}
+
+func TestModel_View_SummaryFooter_Findings(t *testing.T) {
+	// Given a finished pipeline that collected a finding
+	var model tea.Model = NewModel([]string{"phase1"})
+	model, _ = model.Update(FindingsMsg{Findings: []provider.Finding{{Title: "Flaky test", Severity: "major"}}})
+	model, _ = model.Update(PipelineDoneMsg{})
+
+	// When the view is rendered
+	view := model.(Model).View()
+
+	// Then the footer lists the finding
+	if !strings.Contains(view, "Findings:") || !strings.Contains(view, "[major] Flaky test") {
+		t.Errorf("footer should list findings, got:\n%s", view)
+	}
+}
+
 // --- Abort tests ---
 
 func TestModel_Update_KeyMsg_Q_WithCancel_SetsAborting(t *testing.T) {
@@ -565,6 +731,72 @@ func TestModel_Update_PipelineErrorMsg_ClearsAborting(t *testing.T) {
 	}
 }
 
+// --- Retry tests ---
+
+func TestModel_Retry(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []ModelOption
+		aborting   bool
+		done       DisplayEvent
+		wantOpen   bool // Summary stays open after the run ends.
+		wantRetry  bool // Pressing r requests a retry.
+		wantFooter bool // Footer advertises r.
+	}{
+		{name: "failure with retry enabled", opts: []ModelOption{WithRetry()}, done: PipelineErrorMsg{Err: errors.New("boom")}, wantOpen: true, wantRetry: true, wantFooter: true},
+		{name: "failure without checkpoint store", done: PipelineErrorMsg{Err: errors.New("boom")}},
+		{name: "success with retry enabled", opts: []ModelOption{WithRetry()}, done: PipelineDoneMsg{}},
+		{name: "aborted run", opts: []ModelOption{WithRetry(), WithCancelFunc(func() {})}, aborting: true, done: PipelineErrorMsg{Err: context.Canceled}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a model whose run has ended
+			m := NewModel([]string{"execute", "sign-off"}, tt.opts...)
+			m.aborting = tt.aborting
+			next, cmd := m.Update(tt.done)
+			m = next.(Model)
+
+			// Then the summary stays open only when a retry is possible
+			if open := cmd == nil; open != tt.wantOpen {
+				t.Errorf("summary open = %v, want %v", open, tt.wantOpen)
+			}
+			if got := strings.Contains(m.View(), "r retry"); got != tt.wantFooter {
+				t.Errorf("footer shows retry = %v, want %v", got, tt.wantFooter)
+			}
+
+			// When r is pressed
+			next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+			m = next.(Model)
+
+			// Then a retry is requested and the program quits, or nothing happens
+			if m.RetryRequested() != tt.wantRetry {
+				t.Errorf("RetryRequested() = %v, want %v", m.RetryRequested(), tt.wantRetry)
+			}
+			if tt.wantRetry && cmd == nil {
+				t.Error("r should produce a quit Cmd")
+			}
+		})
+	}
+}
+
+func TestModel_Retry_QuitFromSummary(t *testing.T) {
+	// Given a failure summary held open for retry
+	m := NewModel([]string{"execute"}, WithRetry())
+	next, _ := m.Update(PipelineErrorMsg{Err: errors.New("boom")})
+
+	// When q is pressed
+	next, cmd := next.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	// Then the program quits without requesting a retry
+	if cmd == nil {
+		t.Error("q should produce a quit Cmd")
+	}
+	if next.(Model).RetryRequested() {
+		t.Error("q should not request a retry")
+	}
+}
+
 func TestModel_Update_WindowSizeMsg(t *testing.T) {
 	m := NewModel([]string{"test-writer"})
 
@@ -706,41 +938,66 @@ func TestModel_Update_WindowSizeMsg_ResizesViewport(t *testing.T) {
 
 // --- Elapsed time ticker tests ---
 
-func TestModel_Update_StatusUpdateMsg_Running_SetsPhaseStartedAt(t *testing.T) {
+func TestModel_Update_StatusUpdateMsg_Running_SetsStartedAt(t *testing.T) {
 	m := NewModel([]string{"test-writer", "test-review"})
 	msg := StatusUpdateMsg{Phase: "test-writer", Status: StatusRunning}
 
-	newModel, _ := m.Update(msg)
+	newModel, cmd := m.Update(msg)
 	updated := newModel.(Model)
 
-	if updated.phaseStartedAt.IsZero() {
-		t.Error("phaseStartedAt should be set when a phase starts running")
+	if updated.phases[0].StartedAt.IsZero() {
+		t.Error("StartedAt should be set when a phase starts running")
+	}
+	if cmd == nil || !updated.ticking {
+		t.Error("a running phase should start the elapsed tick")
 	}
 }
 
-func TestModel_Update_StatusUpdateMsg_Running_ResetsPhaseStartedAt(t *testing.T) {
-	m := NewModel([]string{"test-writer", "test-review"})
-	m.Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusRunning})
+func TestModel_Update_StatusUpdateMsg_Running_RestartsPerAttempt(t *testing.T) {
+	m := NewModel([]string{"test-writer"})
+	newModel, _ := m.Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusRunning})
+	m = newModel.(Model)
+	first := m.phases[0].StartedAt
 	time.Sleep(2 * time.Millisecond)
+	newModel, _ = m.Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusFailed, Duration: time.Second})
+	m = newModel.(Model)
 
-	newModel, _ := m.Update(StatusUpdateMsg{Phase: "test-review", Status: StatusRunning})
+	newModel, cmd := m.Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusRunning, Attempt: 2, MaxRetry: 3})
 	updated := newModel.(Model)
 
-	// phaseStartedAt should be reset (not zero)
-	if updated.phaseStartedAt.IsZero() {
-		t.Error("phaseStartedAt should be set for new running phase")
+	if !updated.phases[0].StartedAt.After(first) {
+		t.Error("StartedAt should be reset when the phase runs again")
+	}
+	if updated.phases[0].Duration != 0 {
+		t.Errorf("Duration = %v, want 0 while running", updated.phases[0].Duration)
+	}
+	if cmd != nil {
+		t.Error("a second running update should not start another tick chain")
 	}
 }
 
 func TestModel_View_ElapsedTime_ForRunningPhase(t *testing.T) {
 	m := NewModel([]string{"test-writer"})
 	m.phases[0].Status = StatusRunning
-	m.phaseStartedAt = time.Now().Add(-42 * time.Second)
+	m.phases[0].StartedAt = time.Now().Add(-(8*time.Minute + 42*time.Second))
 
 	view := m.View()
 
-	if !strings.Contains(view, "(42s)") {
-		t.Errorf("running phase should show elapsed time '(42s)', got:\n%s", view)
+	if !strings.Contains(view, "08:42") {
+		t.Errorf("running phase should show elapsed time '08:42', got:\n%s", view)
+	}
+}
+
+func TestModel_View_ElapsedTime_ReplacedByDuration(t *testing.T) {
+	m := NewModel([]string{"test-writer"})
+	m.phases[0].Status = StatusRunning
+	m.phases[0].StartedAt = time.Now().Add(-5 * time.Second)
+
+	newModel, _ := m.Update(StatusUpdateMsg{Phase: "test-writer", Status: StatusPassed, Duration: 4500 * time.Millisecond})
+	view := newModel.(Model).View()
+
+	if !strings.Contains(view, "4.5s") || strings.Contains(view, "00:0") {
+		t.Errorf("completed phase should show only its duration, got:\n%s", view)
 	}
 }
 
@@ -750,7 +1007,7 @@ func TestModel_View_ElapsedTime_NotShownForPendingPhase(t *testing.T) {
 
 	view := m.View()
 
-	if strings.Contains(view, "s)") {
+	if strings.Contains(view, "00:") {
 		t.Errorf("pending phase should not show elapsed time, got:\n%s", view)
 	}
 }
@@ -758,7 +1015,8 @@ func TestModel_View_ElapsedTime_NotShownForPendingPhase(t *testing.T) {
 func TestModel_Update_ElapsedTickMsg_ReturnsTickWhenRunning(t *testing.T) {
 	m := NewModel([]string{"test-writer"})
 	m.phases[0].Status = StatusRunning
-	m.phaseStartedAt = time.Now()
+	m.phases[0].StartedAt = time.Now()
+	m.ticking = true
 
 	_, cmd := m.Update(elapsedTickMsg{})
 
@@ -769,22 +1027,29 @@ func TestModel_Update_ElapsedTickMsg_ReturnsTickWhenRunning(t *testing.T) {
 
 func TestModel_Update_ElapsedTickMsg_NoTickWhenNotRunning(t *testing.T) {
 	m := NewModel([]string{"test-writer"})
+	m.ticking = true
 
-	_, cmd := m.Update(elapsedTickMsg{})
+	newModel, cmd := m.Update(elapsedTickMsg{})
 
 	if cmd != nil {
 		t.Error("elapsedTickMsg should not produce a tick when no phase is running")
 	}
+	if newModel.(Model).ticking {
+		t.Error("ticking should be cleared when no phase is running")
+	}
 }
 
-func TestModel_Init_ReturnsElapsedTick(t *testing.T) {
+func TestModel_Init_StartsSpinnerOnly(t *testing.T) {
 	m := NewModel([]string{"test-writer"})
 	cmd := m.Init()
 
-	// Init should return a batch that includes both the spinner tick and elapsed tick.
+	// Init starts the spinner; elapsed ticks wait for a running phase.
 	if cmd == nil {
 		t.Fatal("Init() should return a non-nil Cmd")
 	}
+	if m.ticking {
+		t.Error("ticking should be false before any phase runs")
+	}
 }
 
 // TestModel_Teatest_AbortFlow verifies the abort lifecycle through the full Bubble Tea program.
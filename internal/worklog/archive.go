@@ -0,0 +1,172 @@
+package worklog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// worklogHeader starts every worklog instantiated from the template.
+const worklogHeader = "# Worklog"
+
+// ArchiveEntry describes one bead's directory in the archive.
+type ArchiveEntry struct {
+	BeadID  string
+	ModTime time.Time // Newest modification time of any file in the directory.
+	Size    int64     // Total size of all files in the directory, in bytes.
+	Warning string    // Non-empty when the worklog is missing, empty, or looks partially written.
+}
+
+// ReadWorklog returns the contents of <archiveDir>/<beadID>/worklog.md.
+// Returns an error wrapping os.ErrNotExist if the file does not exist.
+func (m *Manager) ReadWorklog(beadID string) (string, error) {
+	return m.readArchived(beadID, "worklog.md")
+}
+
+// ReadSummary returns the bead's run summary as text: summary.json rendered
+// by RunSummary.Text when the last run wrote one, otherwise the contents of
+// <archiveDir>/<beadID>/summary.md. Returns an error wrapping
+// os.ErrNotExist if neither exists.
+func (m *Manager) ReadSummary(beadID string) (string, error) {
+	if s, err := m.ReadRunSummary(beadID); err == nil {
+		return s.Text(), nil
+	}
+	return m.readArchived(beadID, "summary.md")
+}
+
+// Path returns the worklog file to read for beadID: worklog.md in
+// worktreePath while a run keeps it there, else the archived copy. It
+// returns "" when neither exists.
+func (m *Manager) Path(worktreePath, beadID string) string {
+	candidates := []string{filepath.Join(worktreePath, "worklog.md")}
+	if validateBeadID(beadID) == nil {
+		candidates = append(candidates, filepath.Join(m.archiveDir, beadID, "worklog.md"))
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+func (m *Manager) readArchived(beadID, filename string) (string, error) {
+	if err := validateBeadID(beadID); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(m.archiveDir, beadID, filename))
+	if err != nil {
+		return "", fmt.Errorf("worklog: read %s for %s: %w", filename, beadID, err)
+	}
+	return string(data), nil
+}
+
+// AppendArchived appends entry to <archiveDir>/<beadID>/worklog.md, creating
+// the file with a worklog header when it does not exist. It records results
+// for beads that have no worktree of their own, such as a campaign's parent.
+func (m *Manager) AppendArchived(beadID string, entry PhaseEntry) error {
+	if err := validateBeadID(beadID); err != nil {
+		return err
+	}
+	dir := filepath.Join(m.archiveDir, beadID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("worklog: creating archive dir %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, "worklog.md")
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		header := fmt.Sprintf("%s: %s\n", worklogHeader, beadID)
+		if err := os.WriteFile(path, []byte(header), 0o644); err != nil {
+			return fmt.Errorf("worklog: writing %s: %w", path, err)
+		}
+	}
+	return AppendPhaseEntry(dir, entry)
+}
+
+// List returns an entry for every bead directory in the archive, sorted by
+// bead ID. A missing archive directory yields no entries. Damaged archives are
+// listed with a Warning rather than failing the whole listing.
+func (m *Manager) List() ([]ArchiveEntry, error) {
+	dirs, err := os.ReadDir(m.archiveDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("worklog: listing %s: %w", m.archiveDir, err)
+	}
+
+	var entries []ArchiveEntry
+	for _, d := range dirs {
+		if !d.IsDir() || validateBeadID(d.Name()) != nil {
+			continue
+		}
+		entries = append(entries, m.inspect(d.Name()))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].BeadID < entries[j].BeadID })
+	return entries, nil
+}
+
+// inspect totals a bead directory and checks its worklog for damage.
+func (m *Manager) inspect(beadID string) ArchiveEntry {
+	entry := ArchiveEntry{BeadID: beadID}
+	dir := filepath.Join(m.archiveDir, beadID)
+
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(entry.ModTime) {
+			entry.ModTime = info.ModTime()
+		}
+		if !d.IsDir() {
+			entry.Size += info.Size()
+		}
+		return nil
+	})
+	if walkErr != nil {
+		entry.Warning = fmt.Sprintf("unreadable: %v", walkErr)
+		return entry
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "worklog.md"))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		entry.Warning = "no worklog.md"
+	case err != nil:
+		entry.Warning = fmt.Sprintf("unreadable worklog: %v", err)
+	case len(strings.TrimSpace(string(data))) == 0:
+		entry.Warning = "empty worklog"
+	case !strings.HasPrefix(string(data), worklogHeader):
+		entry.Warning = "missing worklog header (partially written?)"
+	}
+	return entry
+}
+
+// Prune deletes archived bead directories whose newest file is older than
+// cutoff and returns the removed bead IDs.
+func (m *Manager) Prune(cutoff time.Time) ([]string, error) {
+	entries, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, e := range entries {
+		if e.ModTime.IsZero() || !e.ModTime.Before(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(m.archiveDir, e.BeadID)); err != nil {
+			return removed, fmt.Errorf("worklog: pruning %s: %w", e.BeadID, err)
+		}
+		removed = append(removed, e.BeadID)
+	}
+	return removed, nil
+}
@@ -0,0 +1,250 @@
+package worklog
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeArchived writes files under archiveDir/beadID and stamps them with modTime.
+func writeArchived(t *testing.T, archiveDir, beadID string, modTime time.Time, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(archiveDir, beadID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(path, modTime, modTime)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManager_ReadArchived(t *testing.T) {
+	// Given an archive with a worklog and summary for one bead
+	archiveDir := t.TempDir()
+	writeArchived(t, archiveDir, "cap-abc123", time.Now(), map[string]string{
+		"worklog.md": "# Worklog: cap-abc123\n",
+		"summary.md": "## Summary\n\nAll phases passed.\n",
+	})
+	mgr := NewManager(nil, "", archiveDir)
+
+	// When the worklog and summary are read
+	worklog, err := mgr.ReadWorklog("cap-abc123")
+	if err != nil {
+		t.Fatalf("ReadWorklog() error = %v", err)
+	}
+	summary, err := mgr.ReadSummary("cap-abc123")
+	if err != nil {
+		t.Fatalf("ReadSummary() error = %v", err)
+	}
+
+	// Then the archived contents are returned
+	if worklog != "# Worklog: cap-abc123\n" {
+		t.Errorf("ReadWorklog() = %q", worklog)
+	}
+	if summary != "## Summary\n\nAll phases passed.\n" {
+		t.Errorf("ReadSummary() = %q", summary)
+	}
+
+	// And a missing bead reports os.ErrNotExist
+	if _, err := mgr.ReadWorklog("nonexistent"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadWorklog(nonexistent) error = %v, want os.ErrNotExist", err)
+	}
+	if _, err := mgr.ReadSummary("nonexistent"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadSummary(nonexistent) error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestManager_Path(t *testing.T) {
+	// Given an archived worklog for one bead
+	archiveDir := t.TempDir()
+	writeArchived(t, archiveDir, "cap-1", time.Now(), map[string]string{"worklog.md": "# Worklog\n"})
+	mgr := NewManager(nil, "", archiveDir)
+	worktree := t.TempDir()
+	archived := filepath.Join(archiveDir, "cap-1", "worklog.md")
+
+	// When the worktree has no worklog
+	// Then the archived copy is returned
+	if got := mgr.Path(worktree, "cap-1"); got != archived {
+		t.Errorf("Path() = %q, want %q", got, archived)
+	}
+
+	// When the worktree has its own worklog
+	live := filepath.Join(worktree, "worklog.md")
+	if err := os.WriteFile(live, []byte("# Worklog\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Then it wins over the archive
+	if got := mgr.Path(worktree, "cap-1"); got != live {
+		t.Errorf("Path() = %q, want %q", got, live)
+	}
+
+	// And a bead with neither has no path
+	if got := mgr.Path(t.TempDir(), "cap-2"); got != "" {
+		t.Errorf("Path() for missing worklog = %q, want empty", got)
+	}
+}
+
+func TestManager_ReadArchived_InvalidBeadID(t *testing.T) {
+	mgr := NewManager(nil, "", t.TempDir())
+
+	tests := []struct {
+		name   string
+		beadID string
+	}{
+		{"empty", ""},
+		{"path traversal slash", "../escape"},
+		{"path traversal backslash", `dir\escape`},
+		{"dot", "."},
+		{"dot-dot", ".."},
+		{"flag-like", "-malicious"},
+		{"null byte", "cap\x00evil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := mgr.ReadWorklog(tt.beadID); !errors.Is(err, ErrInvalidID) {
+				t.Errorf("ReadWorklog(%q) error = %v, want ErrInvalidID", tt.beadID, err)
+			}
+			if _, err := mgr.ReadSummary(tt.beadID); !errors.Is(err, ErrInvalidID) {
+				t.Errorf("ReadSummary(%q) error = %v, want ErrInvalidID", tt.beadID, err)
+			}
+		})
+	}
+}
+
+func TestManager_List(t *testing.T) {
+	// Given an archive with healthy, damaged, and output-only bead directories
+	archiveDir := t.TempDir()
+	old := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	writeArchived(t, archiveDir, "cap-ok", old, map[string]string{
+		"worklog.md":            "# Worklog: cap-ok\n",
+		"raw/execute-attempt-1": "12345",
+	})
+	writeArchived(t, archiveDir, "cap-empty", old, map[string]string{"worklog.md": "\n"})
+	writeArchived(t, archiveDir, "cap-partial", old, map[string]string{"worklog.md": "### execute\n- Status: PASS\n"})
+	writeArchived(t, archiveDir, "cap-raw-only", old, map[string]string{"raw/execute-attempt-1": "x"})
+	if err := os.WriteFile(filepath.Join(archiveDir, "stray.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mgr := NewManager(nil, "", archiveDir)
+
+	// When List is called
+	entries, err := mgr.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	// Then every bead directory is listed in order, damaged ones with a warning
+	want := map[string]string{
+		"cap-empty":    "empty worklog",
+		"cap-ok":       "",
+		"cap-partial":  "missing worklog header (partially written?)",
+		"cap-raw-only": "no worklog.md",
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.BeadID)
+		if e.Warning != want[e.BeadID] {
+			t.Errorf("%s: Warning = %q, want %q", e.BeadID, e.Warning, want[e.BeadID])
+		}
+		if !e.ModTime.Equal(old) {
+			t.Errorf("%s: ModTime = %v, want %v", e.BeadID, e.ModTime, old)
+		}
+	}
+	if wantIDs := []string{"cap-empty", "cap-ok", "cap-partial", "cap-raw-only"}; !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("List() IDs = %v, want %v", ids, wantIDs)
+	}
+	// And sizes include nested output files
+	if entries[1].Size != int64(len("# Worklog: cap-ok\n")+5) {
+		t.Errorf("cap-ok Size = %d", entries[1].Size)
+	}
+}
+
+func TestManager_List_MissingArchiveDir(t *testing.T) {
+	// Given a manager whose archive directory does not exist
+	mgr := NewManager(nil, "", filepath.Join(t.TempDir(), "missing"))
+
+	// When List is called
+	entries, err := mgr.List()
+
+	// Then no entries and no error are returned
+	if err != nil || len(entries) != 0 {
+		t.Errorf("List() = %v, %v; want empty, nil", entries, err)
+	}
+}
+
+func TestManager_Prune(t *testing.T) {
+	// Given an archive with one old and one recent bead
+	archiveDir := t.TempDir()
+	now := time.Now()
+	writeArchived(t, archiveDir, "cap-old", now.Add(-40*24*time.Hour), map[string]string{"worklog.md": "# Worklog\n"})
+	writeArchived(t, archiveDir, "cap-new", now.Add(-time.Hour), map[string]string{"worklog.md": "# Worklog\n"})
+	mgr := NewManager(nil, "", archiveDir)
+
+	// When pruning archives older than 30 days
+	removed, err := mgr.Prune(now.Add(-30 * 24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+
+	// Then only the old bead is removed
+	if !reflect.DeepEqual(removed, []string{"cap-old"}) {
+		t.Errorf("Prune() removed = %v, want [cap-old]", removed)
+	}
+	if _, err := os.Stat(filepath.Join(archiveDir, "cap-old")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("cap-old still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(archiveDir, "cap-new", "worklog.md")); err != nil {
+		t.Errorf("cap-new was removed: %v", err)
+	}
+}
+
+func TestManager_AppendArchived(t *testing.T) {
+	// Given an archive with no directory for the bead yet
+	archiveDir := t.TempDir()
+	mgr := NewManager(nil, "", archiveDir)
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// When two entries are appended
+	for _, status := range []string{"PASS", "failed"} {
+		entry := PhaseEntry{Name: "feature validation", Status: status, Verdict: "v", Timestamp: ts}
+		if err := mgr.AppendArchived("cap-feature", entry); err != nil {
+			t.Fatalf("AppendArchived() error = %v", err)
+		}
+	}
+
+	// Then the worklog starts with a header and holds both entries
+	got, err := mgr.ReadWorklog("cap-feature")
+	if err != nil {
+		t.Fatalf("ReadWorklog() error = %v", err)
+	}
+	want := "# Worklog: cap-feature\n" +
+		"\n### feature validation\n\n- Status: PASS\n- Verdict: v\n- Timestamp: 2025-01-02T03:04:05Z\n" +
+		"\n### feature validation\n\n- Status: failed\n- Verdict: v\n- Timestamp: 2025-01-02T03:04:05Z\n"
+	if got != want {
+		t.Errorf("worklog =\n%s\nwant\n%s", got, want)
+	}
+
+	// And an invalid bead ID is rejected
+	if err := mgr.AppendArchived("../x", PhaseEntry{}); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("AppendArchived(../x) error = %v, want ErrInvalidID", err)
+	}
+}
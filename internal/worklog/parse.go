@@ -0,0 +1,138 @@
+package worklog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoPhaseEntries indicates a worklog has no phase entries that
+// AppendPhaseEntry could have written, such as one from an older template.
+var ErrNoPhaseEntries = errors.New("worklog: no phase entries")
+
+// attemptLine matches the first line of an attempt written by formatAttempt.
+var attemptLine = regexp.MustCompile(`^- Attempt (\d+): (\S+)(?: \((.+)\))?$`)
+
+// ParseWorklog reads back the phase entries AppendPhaseEntry wrote to a
+// worklog, in the order they appear: every attempt of a phase under its
+// heading, then the next phase. Text the template or an agent wrote is
+// skipped. Feedback is not recorded for standalone entries, and durations
+// and timestamps keep the precision they were written with. It returns
+// ErrNoPhaseEntries when there are none.
+func ParseWorklog(data string) ([]PhaseEntry, error) {
+	var (
+		entries []PhaseEntry
+		section string
+		cur     *PhaseEntry
+		nested  bool    // cur is an attempt; its details are nested bullets.
+		field   *string // Nested field that indented lines continue.
+	)
+	flush := func() {
+		// Template and agent text can look like a standalone entry; only
+		// entries with a timestamp were written by AppendPhaseEntry.
+		if cur != nil && !cur.Timestamp.IsZero() {
+			entries = append(entries, *cur)
+		}
+		cur, nested, field = nil, false, nil
+	}
+
+	for n, line := range strings.Split(data, "\n") {
+		switch {
+		case strings.HasPrefix(line, "#"):
+			flush()
+			section = ""
+			if name, ok := strings.CutPrefix(line, "### "); ok {
+				section = strings.TrimSpace(name)
+			}
+
+		case section == "":
+
+		case attemptLine.MatchString(line):
+			flush()
+			m := attemptLine.FindStringSubmatch(line)
+			attempt, _ := strconv.Atoi(m[1])
+			cur, nested = &PhaseEntry{Name: section, Status: m[2], Attempt: attempt}, true
+			if m[3] != "" {
+				d, err := time.ParseDuration(m[3])
+				if err != nil {
+					return nil, fmt.Errorf("worklog: line %d: duration %q: %w", n+1, m[3], err)
+				}
+				cur.Duration = d
+			}
+
+		case nested && strings.HasPrefix(line, "  - "):
+			key, value, _ := strings.Cut(line[len("  - "):], ": ")
+			var err error
+			field, err = setField(cur, key, value)
+			if err != nil {
+				return nil, fmt.Errorf("worklog: line %d: %w", n+1, err)
+			}
+
+		case nested && field != nil && strings.HasPrefix(line, "    "):
+			*field += "\n" + line[len("    "):]
+
+		case !nested && strings.HasPrefix(line, "- "):
+			key, value, _ := strings.Cut(line[len("- "):], ": ")
+			if key == "Status" {
+				flush()
+				cur = &PhaseEntry{Name: section}
+			}
+			if cur == nil {
+				continue
+			}
+			if _, err := setField(cur, key, value); err != nil {
+				return nil, fmt.Errorf("worklog: line %d: %w", n+1, err)
+			}
+
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	if len(entries) == 0 {
+		return nil, ErrNoPhaseEntries
+	}
+	return entries, nil
+}
+
+// setField sets the entry field a "Key: value" bullet records and returns
+// it when later lines may continue it. Unknown keys are ignored.
+func setField(entry *PhaseEntry, key, value string) (*string, error) {
+	switch key {
+	case "Status":
+		entry.Status = value
+	case "Verdict":
+		entry.Verdict = value
+		return &entry.Verdict, nil
+	case "Reason":
+		entry.Reason = value
+		return &entry.Reason, nil
+	case "Feedback":
+		entry.Feedback = value
+		return &entry.Feedback, nil
+	case "Usage":
+		entry.Usage = value
+	case "Timestamp":
+		ts, err := time.Parse(timestampLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("timestamp %q: %w", value, err)
+		}
+		entry.Timestamp = ts
+	}
+	return nil, nil
+}
+
+// ReadPhaseEntries parses the phase entries from the bead's archived
+// worklog. It returns an error wrapping os.ErrNotExist when there is no
+// archived worklog, and ErrNoPhaseEntries when it has no entries.
+func (m *Manager) ReadPhaseEntries(beadID string) ([]PhaseEntry, error) {
+	data, err := m.readArchived(beadID, "worklog.md")
+	if err != nil {
+		return nil, err
+	}
+	return ParseWorklog(data)
+}
@@ -0,0 +1,132 @@
+package worklog
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// generatedLine matches the creation time the template stamps on a worklog.
+var generatedLine = regexp.MustCompile(`(?m)^Generated: .*$`)
+
+func TestParseWorklog_Golden(t *testing.T) {
+	// Given a worklog written by the current template and Manager, with
+	// retried, interleaved, and standalone entries
+	at := func(sec int) time.Time { return time.Date(2026, 1, 2, 3, 4, sec, 0, time.UTC) }
+	appended := []PhaseEntry{
+		{Name: "test-writer", Status: "PASS", Verdict: "tests written", Usage: "1.2k in / 300 out", Timestamp: at(10), Attempt: 1, Duration: 12300 * time.Millisecond},
+		{Name: "test-review", Status: "NEEDS_WORK", Verdict: "missing cases", Timestamp: at(20), Attempt: 1, Duration: 4 * time.Second,
+			Feedback: "Missing edge cases:\n- empty input\n\n- unicode"},
+		{Name: "test-writer", Status: "PASS", Verdict: "cases added", Timestamp: at(30), Attempt: 2, Duration: 8500 * time.Millisecond},
+		{Name: "test-review", Status: "PASS", Verdict: "looks good", Timestamp: at(40), Attempt: 2, Duration: 3200 * time.Millisecond},
+		{Name: "lint", Status: "SKIP", Verdict: "skipped by condition", Reason: "condition not met: files_match:*.ts", Timestamp: at(41)},
+		{Name: "execute", Status: "PASS", Verdict: "implemented", Timestamp: at(50), Attempt: 1, Duration: 62500 * time.Millisecond},
+	}
+	mgr := NewManager(os.DirFS(filepath.Join("..", "..", "templates")), "worklog.md.template", t.TempDir())
+	wt := t.TempDir()
+	bead := BeadContext{TaskID: "cap-1.1", TaskTitle: "Validate email", TaskDescription: "Add ValidateEmail.", AcceptanceCriteria: "- rejects empty input"}
+	if err := mgr.Create(wt, bead); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, e := range appended {
+		if err := mgr.AppendPhaseEntry(wt, e); err != nil {
+			t.Fatalf("AppendPhaseEntry() error = %v", err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(wt, "worklog.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := generatedLine.ReplaceAll(data, []byte("Generated: 2026-01-02T03:04:00Z"))
+
+	// When it is compared with the golden file
+	golden := filepath.Join("testdata", "worklog.golden.md")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file (go test -run Golden -update writes it): %v", err)
+	}
+
+	// Then the worklog matches it
+	if string(got) != string(want) {
+		t.Errorf("worklog differs from %s (go test -run Golden -update rewrites it):\n%s", golden, got)
+	}
+
+	// And parsing it gives back every entry, grouped by phase heading
+	entries, err := ParseWorklog(string(want))
+	if err != nil {
+		t.Fatalf("ParseWorklog() error = %v", err)
+	}
+	wantEntries := []PhaseEntry{appended[0], appended[2], appended[1], appended[3], appended[4], appended[5]}
+	if !reflect.DeepEqual(entries, wantEntries) {
+		t.Errorf("ParseWorklog() =\n%+v\nwant\n%+v", entries, wantEntries)
+	}
+}
+
+func TestParseWorklog_Unparsable(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error // nil means any error other than ErrNoPhaseEntries.
+	}{
+		{name: "empty", data: "", wantErr: ErrNoPhaseEntries},
+		{
+			name:    "older template",
+			data:    "# Worklog: cap-1\n\n## Phase Log\n\n### Phase 1: test-writer\n\n_Status: complete_\n\n**Files created:**\n- `a_test.go`\n- Status: done\n",
+			wantErr: ErrNoPhaseEntries,
+		},
+		{name: "bad timestamp", data: "### execute\n\n- Attempt 1: PASS (2s)\n  - Timestamp: yesterday\n"},
+		{name: "bad duration", data: "### execute\n\n- Attempt 1: PASS (soon)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When the worklog is parsed
+			entries, err := ParseWorklog(tt.data)
+
+			// Then it fails, so callers fall back to the raw text
+			if err == nil || entries != nil {
+				t.Fatalf("ParseWorklog() = %+v, %v; want an error", entries, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && errors.Is(err, ErrNoPhaseEntries) {
+				t.Errorf("error = %v, want a parse error", err)
+			}
+		})
+	}
+}
+
+func TestManager_ReadPhaseEntries(t *testing.T) {
+	// Given an archive with one bead's worklog
+	archiveDir := t.TempDir()
+	mgr := NewManager(nil, "", archiveDir)
+	entry := PhaseEntry{Name: "execute", Status: "PASS", Verdict: "done", Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Attempt: 1, Duration: time.Second}
+	if err := mgr.AppendArchived("cap-1", entry); err != nil {
+		t.Fatal(err)
+	}
+
+	// When its phase entries are read
+	entries, err := mgr.ReadPhaseEntries("cap-1")
+
+	// Then the entry comes back
+	if err != nil || len(entries) != 1 || entries[0] != entry {
+		t.Errorf("ReadPhaseEntries() = %+v, %v; want [%+v]", entries, err, entry)
+	}
+
+	// And a bead without an archive reports it as missing
+	if _, err := mgr.ReadPhaseEntries("cap-2"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ReadPhaseEntries(cap-2) error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,191 @@
+package worklog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/smileynet/capsule/internal/atomicfile"
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+// summaryFile is the machine-readable run summary in a bead's archive.
+const summaryFile = "summary.json"
+
+// Run statuses recorded in RunSummary.Status.
+const (
+	RunPassed = "passed"
+	RunFailed = "failed"
+	RunPaused = "paused"
+)
+
+// RunSummary describes one pipeline run for CI and other tools. It is
+// written to <archiveDir>/<beadID>/summary.json after every run, whether it
+// passed or not; the merge outcome is added once the branch has landed.
+type RunSummary struct {
+	BeadID    string             `json:"bead_id"`
+	Title     string             `json:"title,omitempty"`
+	StartedAt time.Time          `json:"started_at"`
+	EndedAt   time.Time          `json:"ended_at"`
+	Status    string             `json:"status"`          // RunPassed, RunFailed, or RunPaused.
+	Error     string             `json:"error,omitempty"` // Why the run failed.
+	Phases    []PhaseSummary     `json:"phases"`
+	Findings  []provider.Finding `json:"findings"`
+	Merge     *MergeSummary      `json:"merge,omitempty"`
+}
+
+// PhaseSummary is the outcome of one phase across its attempts.
+type PhaseSummary struct {
+	Name         string   `json:"name"`
+	Status       string   `json:"status"` // Signal status of the last attempt.
+	Attempts     int      `json:"attempts"`
+	DurationMS   int64    `json:"duration_ms"` // Total across attempts.
+	FilesChanged []string `json:"files_changed"`
+	Summary      string   `json:"summary,omitempty"`     // The last attempt's signal summary.
+	Feedback     string   `json:"feedback,omitempty"`    // Set when the last attempt did not pass.
+	SkipReason   string   `json:"skip_reason,omitempty"` // Why the phase was skipped, when known.
+}
+
+// Merge outcomes recorded in MergeSummary.Status.
+const (
+	MergeMerged   = "merged"
+	MergeConflict = "conflict"
+	MergeFailed   = "failed"
+)
+
+// MergeSummary is what happened after a passing run: the merge to the main
+// branch and the cleanup that follows it.
+type MergeSummary struct {
+	Status        string `json:"status"` // MergeMerged, MergeConflict, or MergeFailed.
+	BranchCleaned bool   `json:"branch_cleaned"`
+	BeadClosed    bool   `json:"bead_closed"`
+	Error         string `json:"error,omitempty"`
+}
+
+// WriteSummary writes s to <archiveDir>/<beadID>/summary.json, replacing
+// any earlier summary for the bead. The file is written to a temporary name
+// and renamed, so readers never see a partial summary.
+func (m *Manager) WriteSummary(s RunSummary) error {
+	if err := validateBeadID(s.BeadID); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("worklog: encoding summary for %s: %w", s.BeadID, err)
+	}
+	dir := filepath.Join(m.archiveDir, s.BeadID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("worklog: creating archive dir %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, summaryFile)
+	if err := atomicfile.WriteFile(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("worklog: writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// RecordMerge adds the merge outcome to the bead's run summary.
+func (m *Manager) RecordMerge(beadID string, merge MergeSummary) error {
+	s, err := m.ReadRunSummary(beadID)
+	if err != nil {
+		return err
+	}
+	s.Merge = &merge
+	return m.WriteSummary(s)
+}
+
+// ReadRunSummary returns the summary of the bead's last run. Returns an
+// error wrapping os.ErrNotExist if none was written.
+func (m *Manager) ReadRunSummary(beadID string) (RunSummary, error) {
+	data, err := m.readArchived(beadID, summaryFile)
+	if err != nil {
+		return RunSummary{}, err
+	}
+	var s RunSummary
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return RunSummary{}, fmt.Errorf("worklog: parsing %s for %s: %w", summaryFile, beadID, err)
+	}
+	return s, nil
+}
+
+// Text renders the summary for people, one line per phase.
+func (s RunSummary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Run %s in %s", s.Status, s.EndedAt.Sub(s.StartedAt).Round(time.Second))
+	if s.Error != "" {
+		fmt.Fprintf(&b, ": %s", s.Error)
+	}
+	b.WriteString("\n")
+	for _, p := range s.Phases {
+		attempts := "attempt"
+		if p.Attempts != 1 {
+			attempts += "s"
+		}
+		fmt.Fprintf(&b, "  %-16s %-10s %d %s, %s\n", p.Name, p.Status, p.Attempts, attempts,
+			(time.Duration(p.DurationMS) * time.Millisecond).Round(time.Second))
+		if p.Feedback != "" {
+			fmt.Fprintf(&b, "    %s\n", p.Feedback)
+		}
+		if p.SkipReason != "" {
+			fmt.Fprintf(&b, "    %s\n", p.SkipReason)
+		}
+	}
+	if len(s.Findings) > 0 {
+		fmt.Fprintf(&b, "Findings (%d):\n", len(s.Findings))
+		for _, f := range s.Findings {
+			fmt.Fprintf(&b, "  [%s] %s\n", f.Severity, f.Title)
+		}
+	}
+	if s.Merge != nil {
+		fmt.Fprintf(&b, "Merge: %s", s.Merge.Status)
+		if s.Merge.BranchCleaned {
+			b.WriteString(", branch cleaned")
+		}
+		if s.Merge.BeadClosed {
+			b.WriteString(", bead closed")
+		}
+		if s.Merge.Error != "" {
+			fmt.Fprintf(&b, " (%s)", s.Merge.Error)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Comment renders the summary as a condensed note for the bead in bd: the
+// status of each phase, every file changed, the total duration, and the
+// final phase's summary.
+func (s RunSummary) Comment() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "capsule run %s in %s\n", s.Status, s.EndedAt.Sub(s.StartedAt).Round(time.Second))
+	if len(s.Phases) > 0 {
+		b.WriteString("\nPhases:\n")
+	}
+	var files []string
+	for _, p := range s.Phases {
+		fmt.Fprintf(&b, "- %s: %s", p.Name, p.Status)
+		if p.Attempts > 1 {
+			fmt.Fprintf(&b, " (%d attempts)", p.Attempts)
+		}
+		if p.SkipReason != "" {
+			fmt.Fprintf(&b, " — %s", p.SkipReason)
+		}
+		b.WriteString("\n")
+		for _, f := range p.FilesChanged {
+			if !slices.Contains(files, f) {
+				files = append(files, f)
+			}
+		}
+	}
+	if len(files) > 0 {
+		fmt.Fprintf(&b, "\nFiles changed: %s\n", strings.Join(files, ", "))
+	}
+	if n := len(s.Phases); n > 0 && s.Phases[n-1].Summary != "" {
+		fmt.Fprintf(&b, "\n%s: %s\n", s.Phases[n-1].Name, s.Phases[n-1].Summary)
+	}
+	return b.String()
+}
@@ -0,0 +1,189 @@
+package worklog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smileynet/capsule/internal/provider"
+)
+
+func sampleRunSummary() RunSummary {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return RunSummary{
+		BeadID:    "cap-1",
+		Title:     "Add validation",
+		StartedAt: start,
+		EndedAt:   start.Add(95 * time.Second),
+		Status:    RunFailed,
+		Error:     "pipeline: phase \"execute-review\" attempt 3: max retries (3) exceeded",
+		Phases: []PhaseSummary{
+			{Name: "execute", Status: "PASS", Attempts: 3, DurationMS: 60000, FilesChanged: []string{"a.go"}},
+			{Name: "execute-review", Status: "NEEDS_WORK", Attempts: 3, DurationMS: 35000, FilesChanged: []string{}, Feedback: "missing test"},
+		},
+		Findings: []provider.Finding{{Title: "No test", Severity: "major"}},
+	}
+}
+
+func TestManager_WriteSummary_RoundTrip(t *testing.T) {
+	// Given a manager and a run summary
+	dir := t.TempDir()
+	m := NewManager(nil, "", dir)
+	want := sampleRunSummary()
+
+	// When it is written and read back
+	if err := m.WriteSummary(want); err != nil {
+		t.Fatalf("WriteSummary() error = %v", err)
+	}
+	got, err := m.ReadRunSummary("cap-1")
+	if err != nil {
+		t.Fatalf("ReadRunSummary() error = %v", err)
+	}
+
+	// Then nothing is lost
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("round trip = %s, want %s", gotJSON, wantJSON)
+	}
+	// And only summary.json is left behind, no temporary file
+	entries, err := os.ReadDir(filepath.Join(dir, "cap-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "summary.json" {
+		t.Errorf("archive entries = %v, want only summary.json", entries)
+	}
+}
+
+func TestManager_RecordMerge(t *testing.T) {
+	// Given a written run summary
+	m := NewManager(nil, "", t.TempDir())
+	s := sampleRunSummary()
+	s.Status = RunPassed
+	if err := m.WriteSummary(s); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the merge outcome is recorded
+	if err := m.RecordMerge("cap-1", MergeSummary{Status: MergeMerged, BranchCleaned: true, BeadClosed: true}); err != nil {
+		t.Fatalf("RecordMerge() error = %v", err)
+	}
+
+	// Then the summary carries it alongside the run
+	got, err := m.ReadRunSummary("cap-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Merge == nil || got.Merge.Status != MergeMerged || !got.Merge.BeadClosed {
+		t.Errorf("merge = %+v, want merged and closed", got.Merge)
+	}
+	if len(got.Phases) != 2 {
+		t.Errorf("phases = %d, want the run's 2 kept", len(got.Phases))
+	}
+}
+
+func TestManager_RecordMerge_NoSummary(t *testing.T) {
+	// Given no run summary for the bead
+	m := NewManager(nil, "", t.TempDir())
+
+	// When a merge is recorded
+	err := m.RecordMerge("cap-1", MergeSummary{Status: MergeMerged})
+
+	// Then it reports the summary as missing
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestManager_ReadSummary_PrefersJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		writeJSON bool
+		want      string
+	}{
+		{name: "markdown only", files: map[string]string{"summary.md": "## Summary\n\nDone."}, want: "## Summary"},
+		{name: "json wins over markdown", files: map[string]string{"summary.md": "## Summary"}, writeJSON: true, want: "Run failed in 1m35s"},
+		{name: "corrupt json falls back", files: map[string]string{"summary.md": "## Summary", "summary.json": "{"}, want: "## Summary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given an archive with the files
+			dir := t.TempDir()
+			m := NewManager(nil, "", dir)
+			writeArchived(t, dir, "cap-1", time.Now(), tt.files)
+			if tt.writeJSON {
+				if err := m.WriteSummary(sampleRunSummary()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// When the summary is read
+			got, err := m.ReadSummary("cap-1")
+			if err != nil {
+				t.Fatalf("ReadSummary() error = %v", err)
+			}
+
+			// Then the preferred source is shown
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("ReadSummary() = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunSummary_Text(t *testing.T) {
+	// Given a failed run whose review asked for more work
+	s := sampleRunSummary()
+	s.Phases = append(s.Phases, PhaseSummary{Name: "frontend", Status: "SKIP", Attempts: 1, SkipReason: "no frontend files"})
+	s.Merge = &MergeSummary{Status: MergeConflict}
+
+	// When it is rendered
+	text := s.Text()
+
+	// Then each phase, the feedback, skip reason, findings, and merge appear
+	for _, want := range []string{
+		"Run failed in 1m35s: pipeline",
+		"execute          PASS       3 attempts, 1m0s",
+		"    missing test",
+		"frontend         SKIP       1 attempt, 0s\n    no frontend files",
+		"Findings (1):\n  [major] No test",
+		"Merge: conflict",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestRunSummary_Comment(t *testing.T) {
+	// Given a passed run whose phases changed overlapping files
+	s := sampleRunSummary()
+	s.Status = RunPassed
+	s.Error = ""
+	s.Phases = []PhaseSummary{
+		{Name: "execute", Status: "PASS", Attempts: 2, FilesChanged: []string{"a.go", "a_test.go"}},
+		{Name: "docs", Status: "SKIP", Attempts: 1, SkipReason: "no docs changed"},
+		{Name: "sign-off", Status: "PASS", Attempts: 1, FilesChanged: []string{"a.go", "README.md"}, Summary: "Validation added and documented."},
+	}
+
+	// When it is rendered as a bead comment
+	comment := s.Comment()
+
+	// Then it has the duration, each phase's status and skip reason, the files once each, and the final summary
+	for _, want := range []string{
+		"capsule run passed in 1m35s",
+		"- execute: PASS (2 attempts)\n- docs: SKIP — no docs changed\n- sign-off: PASS\n",
+		"Files changed: a.go, a_test.go, README.md\n",
+		"sign-off: Validation added and documented.",
+	} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("Comment() missing %q:\n%s", want, comment)
+		}
+	}
+}
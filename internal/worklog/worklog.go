@@ -52,7 +52,7 @@ var (
 )
 
 // validateBeadID checks that beadID is safe for use as a path component.
-// Rejects empty, path traversal (/ \ . ..), and flag-like IDs (starting with -).
+// Rejects empty, path traversal (/ \ . ..), null bytes, and flag-like IDs (starting with -).
 func validateBeadID(id string) error {
 	if id == "" {
 		return fmt.Errorf("%w: cannot be empty", ErrInvalidID)
@@ -60,7 +60,7 @@ func validateBeadID(id string) error {
 	if strings.HasPrefix(id, "-") {
 		return fmt.Errorf("%w: %q (must not start with -)", ErrInvalidID, id)
 	}
-	if strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+	if strings.ContainsAny(id, "/\\\x00") || id == "." || id == ".." {
 		return fmt.Errorf("%w: %q", ErrInvalidID, id)
 	}
 	return nil
@@ -78,6 +78,11 @@ type BeadContext struct {
 	TaskTitle          string
 	TaskDescription    string
 	AcceptanceCriteria string
+	TaskPriority       int    // 0 (critical) to 4 (backlog).
+	TaskType           string // bd issue type: task, bug, feature, chore.
+	TaskStatus         string
+	Labels             []string
+	Provider           string // Effective provider for the run, recorded in the worklog header.
 }
 
 // PhaseEntry records the result of a single pipeline phase.
@@ -85,9 +90,20 @@ type PhaseEntry struct {
 	Name      string
 	Status    string
 	Verdict   string
+	Usage     string // Formatted token usage; omitted from the entry when empty.
 	Timestamp time.Time
+
+	// Attempt numbers a retried phase's runs from 1. Attempts of one phase
+	// are nested under a single heading; zero writes a standalone entry.
+	Attempt  int
+	Feedback string        // Reviewer feedback; omitted when empty.
+	Duration time.Duration // Omitted when zero.
+	Reason   string        // Why the phase was skipped; omitted when empty.
 }
 
+// timestampLayout is how phase entries record their timestamps, in UTC.
+const timestampLayout = "2006-01-02T15:04:05Z"
+
 // templateData holds all fields available to the worklog Go template.
 type templateData struct {
 	BeadContext
@@ -144,13 +160,78 @@ func AppendPhaseEntry(worktreePath string, entry PhaseEntry) error {
 		return fmt.Errorf("worklog: reading %s: %w", worklogPath, err)
 	}
 
-	ts := entry.Timestamp.UTC().Format("2006-01-02T15:04:05Z")
-	text := fmt.Sprintf("\n### %s\n\n- Status: %s\n- Verdict: %s\n- Timestamp: %s\n",
-		entry.Name, entry.Status, entry.Verdict, ts)
+	if entry.Attempt > 0 {
+		return os.WriteFile(worklogPath, appendAttempt(existing, entry), 0o644)
+	}
+
+	ts := entry.Timestamp.UTC().Format(timestampLayout)
+	text := fmt.Sprintf("\n### %s\n\n- Status: %s\n- Verdict: %s\n", entry.Name, entry.Status, entry.Verdict)
+	if entry.Reason != "" {
+		text += fmt.Sprintf("- Reason: %s\n", entry.Reason)
+	}
+	if entry.Usage != "" {
+		text += fmt.Sprintf("- Usage: %s\n", entry.Usage)
+	}
+	text += fmt.Sprintf("- Timestamp: %s\n", ts)
 
 	return os.WriteFile(worklogPath, append(existing, []byte(text)...), 0o644)
 }
 
+// appendAttempt adds entry as a nested bullet at the end of the section
+// headed "### <entry.Name>", starting the section when the phase has not
+// logged an attempt yet. Worker and reviewer attempts interleave, so a
+// retry's bullet usually lands before the sections that follow it.
+func appendAttempt(existing []byte, entry PhaseEntry) []byte {
+	text := formatAttempt(entry)
+	heading := "\n### " + entry.Name + "\n"
+	start := bytes.LastIndex(existing, []byte(heading))
+	if start < 0 {
+		return append(existing, []byte(heading+"\n"+text)...)
+	}
+	end := len(existing)
+	if next := bytes.Index(existing[start+len(heading):], []byte("\n#")); next >= 0 {
+		end = start + len(heading) + next + 1
+	}
+	// Insert after the section's last line, before any blank separator.
+	section := bytes.TrimRight(existing[:end], "\n")
+	rest := bytes.TrimPrefix(existing[len(section):], []byte("\n"))
+	out := make([]byte, 0, len(existing)+len(text)+1)
+	out = append(out, section...)
+	out = append(out, '\n')
+	out = append(out, text...)
+	return append(out, rest...)
+}
+
+// formatAttempt renders one attempt as a bullet with its details nested
+// below it. Multi-line feedback stays inside its bullet.
+func formatAttempt(entry PhaseEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "- Attempt %d: %s", entry.Attempt, entry.Status)
+	if entry.Duration > 0 {
+		fmt.Fprintf(&b, " (%s)", entry.Duration.Round(100*time.Millisecond))
+	}
+	b.WriteString("\n")
+	if entry.Verdict != "" {
+		fmt.Fprintf(&b, "  - Verdict: %s\n", indentLines(entry.Verdict))
+	}
+	if entry.Reason != "" {
+		fmt.Fprintf(&b, "  - Reason: %s\n", indentLines(entry.Reason))
+	}
+	if entry.Feedback != "" {
+		fmt.Fprintf(&b, "  - Feedback: %s\n", indentLines(entry.Feedback))
+	}
+	if entry.Usage != "" {
+		fmt.Fprintf(&b, "  - Usage: %s\n", entry.Usage)
+	}
+	fmt.Fprintf(&b, "  - Timestamp: %s\n", entry.Timestamp.UTC().Format(timestampLayout))
+	return b.String()
+}
+
+// indentLines indents every line after the first to sit under a nested bullet.
+func indentLines(s string) string {
+	return strings.ReplaceAll(strings.TrimRight(s, "\n"), "\n", "\n    ")
+}
+
 // Archive copies worktreePath/worklog.md to archiveDir/<beadID>/worklog.md.
 // The archive subdirectory is created if it does not exist.
 func Archive(worktreePath, archiveDir, beadID string) error {
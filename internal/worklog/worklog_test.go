@@ -240,6 +240,84 @@ func TestAppendPhaseEntry(t *testing.T) {
 	}
 }
 
+func TestAppendPhaseEntry_Usage(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage string
+		want  bool
+	}{
+		{name: "reported usage is written", usage: "100 in / 10 out tokens", want: true},
+		{name: "no usage omits the line", usage: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a worktree with an existing worklog.md
+			worktreeDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(worktreeDir, "worklog.md"), []byte("# Worklog\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When an entry is appended
+			entry := PhaseEntry{Name: "execute", Status: "PASS", Verdict: "done", Usage: tt.usage, Timestamp: time.Now()}
+			if err := AppendPhaseEntry(worktreeDir, entry); err != nil {
+				t.Fatalf("AppendPhaseEntry() error = %v", err)
+			}
+
+			// Then the usage line appears only when usage was reported
+			data, err := os.ReadFile(filepath.Join(worktreeDir, "worklog.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(string(data), "- Usage: "); got != tt.want {
+				t.Errorf("usage line present = %v, want %v in:\n%s", got, tt.want, data)
+			}
+			if tt.want && !strings.Contains(string(data), "- Usage: "+tt.usage+"\n") {
+				t.Errorf("worklog missing usage %q:\n%s", tt.usage, data)
+			}
+		})
+	}
+}
+
+func TestAppendPhaseEntry_SkipReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		attempt int
+		want    string
+	}{
+		{name: "standalone entry", attempt: 0, want: "- Verdict: skipped by condition\n- Reason: condition not met: files_match:web/*\n"},
+		{name: "attempt entry", attempt: 1, want: "  - Verdict: skipped by condition\n  - Reason: condition not met: files_match:web/*\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a worktree with an existing worklog.md
+			worktreeDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(worktreeDir, "worklog.md"), []byte("# Worklog\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// When a skipped phase's entry is appended with its reason
+			entry := PhaseEntry{
+				Name: "frontend", Status: "SKIP", Verdict: "skipped by condition",
+				Reason: "condition not met: files_match:web/*", Attempt: tt.attempt, Timestamp: time.Now(),
+			}
+			if err := AppendPhaseEntry(worktreeDir, entry); err != nil {
+				t.Fatalf("AppendPhaseEntry() error = %v", err)
+			}
+
+			// Then the reason follows the verdict
+			data, err := os.ReadFile(filepath.Join(worktreeDir, "worklog.md"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("worklog missing %q:\n%s", tt.want, data)
+			}
+		})
+	}
+}
+
 func TestAppendPhaseEntry_MissingWorklog(t *testing.T) {
 	// Given a worktree without worklog.md
 	worktreeDir := t.TempDir()
@@ -301,6 +379,62 @@ func TestAppendPhaseEntry_MultipleEntries(t *testing.T) {
 	}
 }
 
+func TestAppendPhaseEntry_NestsAttempts(t *testing.T) {
+	// Given a worklog
+	worktreeDir := t.TempDir()
+	worklogPath := filepath.Join(worktreeDir, "worklog.md")
+	if err := os.WriteFile(worklogPath, []byte("# Worklog\n\n## Phase Log\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2025, 6, 15, 10, 30, 0, 0, time.UTC)
+
+	// When a worker/reviewer pair logs two attempts, interleaved as they run
+	entries := []PhaseEntry{
+		{Name: "execute", Status: "PASS", Verdict: "wrote code", Attempt: 1, Duration: 2 * time.Second, Timestamp: ts},
+		{Name: "execute-review", Status: "NEEDS_WORK", Verdict: "incomplete", Feedback: "handle nil input\nadd a test", Attempt: 1, Timestamp: ts},
+		{Name: "execute", Status: "PASS", Verdict: "fixed", Attempt: 2, Timestamp: ts},
+		{Name: "execute-review", Status: "PASS", Verdict: "good", Attempt: 2, Duration: 1500 * time.Millisecond, Timestamp: ts},
+	}
+	for _, e := range entries {
+		if err := AppendPhaseEntry(worktreeDir, e); err != nil {
+			t.Fatalf("AppendPhaseEntry(%s, %d) error = %v", e.Name, e.Attempt, err)
+		}
+	}
+
+	// Then each phase has one heading with its attempts nested under it
+	data, err := os.ReadFile(worklogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Worklog
+
+## Phase Log
+
+### execute
+
+- Attempt 1: PASS (2s)
+  - Verdict: wrote code
+  - Timestamp: 2025-06-15T10:30:00Z
+- Attempt 2: PASS
+  - Verdict: fixed
+  - Timestamp: 2025-06-15T10:30:00Z
+
+### execute-review
+
+- Attempt 1: NEEDS_WORK
+  - Verdict: incomplete
+  - Feedback: handle nil input
+    add a test
+  - Timestamp: 2025-06-15T10:30:00Z
+- Attempt 2: PASS (1.5s)
+  - Verdict: good
+  - Timestamp: 2025-06-15T10:30:00Z
+`
+	if string(data) != want {
+		t.Errorf("worklog =\n%s\nwant\n%s", data, want)
+	}
+}
+
 func TestArchive(t *testing.T) {
 	// Given a worktree with a worklog.md
 	worktreeDir := t.TempDir()
@@ -0,0 +1,86 @@
+package worktree
+
+import (
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// WithLogger sets the logger that records every git command the Manager
+// runs, with its exit code and duration. The default discards logs.
+func WithLogger(l *slog.Logger) Option {
+	return func(m *Manager) {
+		if l != nil {
+			m.logger = l
+		}
+	}
+}
+
+// gitCmd is a git command that logs its outcome when run.
+type gitCmd struct {
+	*exec.Cmd
+	logger *slog.Logger
+}
+
+// git returns a git command with the given arguments that runs in dir.
+func (m *Manager) git(dir string, args ...string) gitCmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	return gitCmd{Cmd: cmd, logger: m.logger}
+}
+
+// Run runs the command and logs its exit code.
+func (c gitCmd) Run() error {
+	start := time.Now()
+	err := c.Cmd.Run()
+	c.log(start, err)
+	return err
+}
+
+// Output runs the command, logs its exit code, and returns its stdout.
+func (c gitCmd) Output() ([]byte, error) {
+	start := time.Now()
+	out, err := c.Cmd.Output()
+	c.log(start, err)
+	return out, err
+}
+
+// CombinedOutput runs the command, logs its exit code, and returns its
+// combined stdout and stderr.
+func (c gitCmd) CombinedOutput() ([]byte, error) {
+	start := time.Now()
+	out, err := c.Cmd.CombinedOutput()
+	c.log(start, err)
+	return out, err
+}
+
+// log records the command's arguments, directory, exit code, and duration.
+// A command that could not start is logged with exit code -1 and the error.
+func (c gitCmd) log(start time.Time, err error) {
+	attrs := []any{
+		"args", strings.Join(c.Args[1:], " "),
+		"dir", c.Dir,
+		"exit_code", exitCode(err),
+		"duration", time.Since(start),
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		attrs = append(attrs, "error", err)
+	}
+	c.logger.Debug("git", attrs...)
+}
+
+// exitCode returns the exit code of a finished command: 0 on success, the
+// process exit code on failure, or -1 when the command did not run.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
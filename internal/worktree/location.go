@@ -0,0 +1,193 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/smileynet/capsule/internal/fsutil"
+)
+
+// DefaultBaseDir is the base directory, relative to the repository root,
+// that worktrees live under unless worktree.base_dir says otherwise.
+// Worktrees left there after base_dir moves are still found.
+const DefaultBaseDir = ".capsule/worktrees"
+
+// DefaultDirTemplate is the worktree directory name template used when none
+// is configured.
+const DefaultDirTemplate = "{{.BeadID}}"
+
+// DirName holds the fields a worktree directory name template can use.
+type DirName struct {
+	BeadID string // SafeName of the bead ID.
+	Date   string // Creation date, YYYY-MM-DD.
+}
+
+// WithDirTemplate sets the template that names each worktree's directory
+// under the base directory. The template must already be valid; one that
+// fails to render falls back to DefaultDirTemplate.
+func WithDirTemplate(text string) Option {
+	return func(m *Manager) { m.dirTemplate = text }
+}
+
+// RenderDirName renders text as a worktree directory name. An empty text
+// renders DefaultDirTemplate. The result must be a single path component.
+func RenderDirName(text string, d DirName) (string, error) {
+	if text == "" {
+		return d.BeadID, nil
+	}
+	tmpl, err := template.New("dir_template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("worktree: parsing dir template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("worktree: rendering dir template: %w", err)
+	}
+	name := strings.TrimSpace(b.String())
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("worktree: dir template rendered %q, which is not a single directory name", name)
+	}
+	return name, nil
+}
+
+// resolveBaseDir returns the absolute directory worktrees live under:
+// baseDir with a leading ~ expanded to the home directory, joined to
+// repoRoot when it is relative.
+func resolveBaseDir(repoRoot, baseDir string) string {
+	if baseDir == "~" || strings.HasPrefix(baseDir, "~/") || strings.HasPrefix(baseDir, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			baseDir = filepath.Join(home, baseDir[1:])
+		}
+	}
+	dir := filepath.FromSlash(baseDir)
+	if !rooted(dir) {
+		dir = filepath.Join(repoRoot, dir)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return dir
+}
+
+// rooted reports whether dir names its own root rather than a path under
+// the repository. Besides absolute paths, on Windows that is a path on
+// another drive (D:capsules) or at the root of the current one (\capsules),
+// which filepath.Abs resolves.
+func rooted(dir string) bool {
+	return filepath.IsAbs(dir) || filepath.VolumeName(dir) != "" || strings.HasPrefix(dir, string(filepath.Separator))
+}
+
+// relBaseDir returns dir relative to repoRoot in slash form, or "" when dir
+// is outside the repository.
+func relBaseDir(repoRoot, dir string) string {
+	root, err := filepath.Abs(repoRoot)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// dirName returns the directory name the configured template gives the
+// worktree named name if it were created now.
+func (m *Manager) dirName(name string) string {
+	if m.dirTemplate == "" {
+		return name
+	}
+	dir, err := RenderDirName(m.dirTemplate, DirName{BeadID: name, Date: m.now().Format(time.DateOnly)})
+	if err != nil {
+		m.logger.Warn("worktree dir template failed; using the bead ID", "error", err)
+		return name
+	}
+	return dir
+}
+
+// pathForName returns where the worktree named name lives. A new worktree
+// goes where the base directory and template put it today; an existing one
+// is found wherever git has it checked out, and unregistered directories
+// under the base directory or DefaultBaseDir are found by name, so
+// worktrees created before base_dir or dir_template changed can still be
+// resumed and removed.
+func (m *Manager) pathForName(name string) string {
+	want := filepath.Join(m.dir, m.dirName(name))
+	if fsutil.IsDir(want) {
+		return want
+	}
+	if branches, err := m.registeredBranches(); err == nil {
+		if path, ok := branches["capsule-"+name]; ok {
+			return path
+		}
+	}
+	for _, dir := range []string{m.dir, m.legacyDir} {
+		if path := filepath.Join(dir, name); fsutil.IsDir(path) {
+			return path
+		}
+	}
+	return want
+}
+
+// registeredBranches maps each branch checked out in a git worktree to the
+// worktree's path.
+func (m *Manager) registeredBranches() (map[string]string, error) {
+	out, err := m.git(m.repoRoot, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("worktree: git worktree list: %w", err)
+	}
+	return parseWorktreeBranches(out), nil
+}
+
+// parseWorktreeBranches maps the branches in "git worktree list --porcelain"
+// output to their worktree paths. Detached worktrees are left out.
+func parseWorktreeBranches(out []byte) map[string]string {
+	branches := make(map[string]string)
+	var path string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if p, ok := strings.CutPrefix(line, "worktree "); ok {
+			path = filepath.Clean(filepath.FromSlash(p))
+		} else if ref, ok := strings.CutPrefix(line, "branch refs/heads/"); ok && path != "" {
+			branches[ref] = path
+		}
+	}
+	return branches
+}
+
+// checkSameFilesystem verifies, once per Manager, that files can be renamed
+// between dir and the repository's .capsule directory. A base directory on
+// another filesystem lets git worktree add succeed but breaks renames across
+// the two, so it is reported up front as ErrCrossDevice. Base directories
+// inside the repository are not checked. Callers must hold m.mu.
+func (m *Manager) checkSameFilesystem(dir string) error {
+	if m.fsChecked || m.relDir != "" {
+		return nil
+	}
+	capsuleDir := filepath.Join(m.repoRoot, ".capsule")
+	if err := os.MkdirAll(capsuleDir, 0o755); err != nil {
+		return fmt.Errorf("worktree: mkdir %s: %w", capsuleDir, err)
+	}
+	f, err := os.CreateTemp(dir, ".capsule-rename-check-*")
+	if err != nil {
+		return fmt.Errorf("worktree: base directory %s is not writable: %w", dir, err)
+	}
+	_ = f.Close()
+	dst := filepath.Join(capsuleDir, filepath.Base(f.Name()))
+	if err := os.Rename(f.Name(), dst); err != nil {
+		_ = os.Remove(f.Name())
+		if errors.Is(err, syscall.EXDEV) {
+			return fmt.Errorf("%w: %s is not on the same filesystem as %s; set worktree.base_dir to a directory on the repository's filesystem", ErrCrossDevice, dir, capsuleDir)
+		}
+		return fmt.Errorf("worktree: renaming from base directory %s: %w", dir, err)
+	}
+	_ = os.Remove(dst)
+	m.fsChecked = true
+	return nil
+}
@@ -0,0 +1,221 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewManager_ResolvesBaseDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	root := filepath.FromSlash("/repo")
+	outside := filepath.Join(t.TempDir(), "capsules")
+
+	tests := []struct {
+		name    string
+		baseDir string
+		want    string
+	}{
+		{name: "relative to repo root", baseDir: ".capsule/worktrees", want: filepath.Join(root, ".capsule", "worktrees", "task-1")},
+		{name: "absolute outside repo", baseDir: outside, want: filepath.Join(outside, "task-1")},
+		{name: "home directory", baseDir: "~/capsules", want: filepath.Join(home, "capsules", "task-1")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a manager for the base directory
+			m := NewManager(root, tt.baseDir)
+
+			// When Path is called
+			got := m.Path("task-1")
+
+			// Then the worktree goes under the resolved absolute directory
+			want, _ := filepath.Abs(tt.want)
+			if got != want {
+				t.Errorf("Path() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRooted(t *testing.T) {
+	tests := []struct {
+		dir         string
+		wantWindows bool
+		wantOther   bool
+	}{
+		{dir: ".capsule/worktrees"},
+		{dir: "../capsules"},
+		{dir: "/srv/capsules", wantWindows: true, wantOther: true},
+		{dir: `C:\capsules`, wantWindows: true},
+		{dir: "C:/capsules", wantWindows: true},
+		{dir: "D:capsules", wantWindows: true},
+		{dir: `\\server\share\capsules`, wantWindows: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			// When a configured base_dir is checked on this OS
+			got := rooted(filepath.FromSlash(tt.dir))
+
+			// Then only paths with their own root are kept off the repo root
+			want := tt.wantOther
+			if runtime.GOOS == "windows" {
+				want = tt.wantWindows
+			}
+			if got != want {
+				t.Errorf("rooted(%q) = %v, want %v on %s", tt.dir, got, want, runtime.GOOS)
+			}
+		})
+	}
+}
+
+func TestRenderDirName(t *testing.T) {
+	d := DirName{BeadID: "cap-1", Date: "2026-10-16"}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty uses bead ID", text: "", want: "cap-1"},
+		{name: "bead ID and date", text: "{{.BeadID}}-{{.Date}}", want: "cap-1-2026-10-16"},
+		{name: "unknown field", text: "{{.Title}}", wantErr: true},
+		{name: "path separator", text: "{{.Date}}/{{.BeadID}}", wantErr: true},
+		{name: "renders empty", text: "{{if false}}x{{end}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When the template is rendered
+			got, err := RenderDirName(tt.text, d)
+
+			// Then it yields the directory name or an error
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderDirName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RenderDirName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirTemplate_OutsideRepo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	// Given a base directory outside the repo and a dated dir template
+	repoDir := t.TempDir()
+	initGitRepo(t, repoDir)
+	baseDir := filepath.Join(t.TempDir(), "capsules")
+	m := NewManager(repoDir, baseDir, WithDirTemplate("{{.BeadID}}-{{.Date}}"))
+	day := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return day }
+
+	// When a worktree is created and the date then changes
+	if err := m.Create("task-1", "HEAD"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	created := filepath.Join(baseDir, "task-1-2026-10-16")
+	m.now = func() time.Time { return day.AddDate(0, 0, 1) }
+
+	// Then Path, Exists, and List still resolve the directory it was created in
+	if got := m.Path("task-1"); absPath(got) != absPath(created) {
+		t.Errorf("Path() = %q, want %q", got, created)
+	}
+	if !m.Exists("task-1") {
+		t.Error("Exists() = false, want true")
+	}
+	ids, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if !slices.Equal(ids, []string{"task-1"}) {
+		t.Errorf("List() = %v, want [task-1]", ids)
+	}
+	if clean, dirty, err := m.StatusClean(); err != nil || !clean {
+		t.Errorf("StatusClean() = %v, %v, %v; want clean", clean, dirty, err)
+	}
+
+	// And Remove deletes it and its branch
+	if err := m.Remove("task-1", true); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := os.Stat(created); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("worktree directory still exists: %v", err)
+	}
+	if m.BranchExists("task-1") {
+		t.Error("branch still exists after Remove")
+	}
+}
+
+func TestOldLayoutAfterBaseDirMoves(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	// Given a worktree created under the default base directory, and a
+	// stale directory a crash left there without a registration
+	repoDir := t.TempDir()
+	initGitRepo(t, repoDir)
+	old := NewManager(repoDir, DefaultBaseDir)
+	if err := old.Create("task-1", "HEAD"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	oldPath := filepath.Join(repoDir, DefaultBaseDir, "task-1")
+	stale := filepath.Join(repoDir, DefaultBaseDir, "task-2")
+	if err := os.MkdirAll(stale, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// When base_dir now points outside the repository
+	m := NewManager(repoDir, filepath.Join(t.TempDir(), "capsules"))
+
+	// Then both are still found and removed
+	if got := m.Path("task-1"); absPath(got) != absPath(oldPath) {
+		t.Errorf("Path() = %q, want %q", got, oldPath)
+	}
+	if err := m.Remove("task-1", true); err != nil {
+		t.Fatalf("Remove task-1: %v", err)
+	}
+	if err := m.Remove("task-2", false); err != nil {
+		t.Fatalf("Remove task-2: %v", err)
+	}
+	for _, dir := range []string{oldPath, stale} {
+		if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s still exists: %v", dir, err)
+		}
+	}
+}
+
+func TestParseWorktreeBranches(t *testing.T) {
+	// Given porcelain output with a branch, a detached worktree, and CRLFs
+	out := []byte("worktree /repo\r\nHEAD abc\r\nbranch refs/heads/main\r\n\r\n" +
+		"worktree /elsewhere/task-1-2026-10-16\r\nHEAD def\r\nbranch refs/heads/capsule-task-1\r\n\r\n" +
+		"worktree /elsewhere/detached\r\nHEAD 123\r\ndetached\r\n")
+
+	// When it is parsed
+	got := parseWorktreeBranches(out)
+
+	// Then each branch maps to its worktree path
+	want := map[string]string{
+		"main":           filepath.FromSlash("/repo"),
+		"capsule-task-1": filepath.FromSlash("/elsewhere/task-1-2026-10-16"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for branch, path := range want {
+		if got[branch] != path {
+			t.Errorf("branches[%q] = %q, want %q", branch, got[branch], path)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultMergeMessageTemplate is the commit message template used when none
+// is configured.
+const DefaultMergeMessageTemplate = "{{.BeadID}}: pipeline complete"
+
+// MergeMessage holds the fields a merge commit message template can use.
+type MergeMessage struct {
+	BeadID       string
+	Title        string   // Bead title.
+	Type         string   // Bead type (task, bug, feature, ...).
+	Summary      string   // Summary reported by the final phase.
+	FilesChanged []string // Files reported by the final phase.
+}
+
+// ParseMergeMessageTemplate parses a merge commit message template. An empty
+// string selects DefaultMergeMessageTemplate.
+func ParseMergeMessageTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultMergeMessageTemplate
+	}
+	tmpl, err := template.New("merge_message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("worktree: parsing merge message template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// WithMergeMessageTemplate sets the template MergeMessage renders. The
+// template must already be valid; an invalid one makes MergeMessage return
+// an error along with the default message.
+func WithMergeMessageTemplate(text string) Option {
+	return func(m *Manager) { m.mergeMessage = text }
+}
+
+// MergeMessage renders the configured merge commit message for msg. If the
+// template fails to parse or render, it returns the default message and the
+// error so callers can warn and carry on.
+func (m *Manager) MergeMessage(msg MergeMessage) (string, error) {
+	return RenderMergeMessage(m.mergeMessage, msg)
+}
+
+// RenderMergeMessage renders text as a merge commit message for msg. An
+// empty text renders DefaultMergeMessageTemplate. On failure the default
+// message is returned with the error.
+func RenderMergeMessage(text string, msg MergeMessage) (string, error) {
+	fallback := msg.BeadID + ": pipeline complete"
+	tmpl, err := ParseMergeMessageTemplate(text)
+	if err != nil {
+		return fallback, err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, msg); err != nil {
+		return fallback, fmt.Errorf("worktree: rendering merge message: %w", err)
+	}
+	out := strings.TrimSpace(b.String())
+	if out == "" {
+		return fallback, errors.New("worktree: merge message template rendered an empty message")
+	}
+	return out, nil
+}
@@ -2,13 +2,21 @@
 package worktree
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/smileynet/capsule/internal/fsutil"
 )
 
 // Sentinel errors for caller-checkable conditions.
@@ -17,6 +25,9 @@ var (
 	ErrNotFound      = errors.New("worktree: not found")
 	ErrInvalidID     = errors.New("worktree: invalid id")
 	ErrMergeConflict = errors.New("worktree: merge conflict")
+	ErrNoSuchBranch  = errors.New("worktree: no such branch")
+	ErrUnmerged      = errors.New("worktree: branch has unmerged work")
+	ErrCrossDevice   = errors.New("worktree: base directory is on a different filesystem")
 )
 
 // MergeConflictError is returned by MergeToMain when a merge conflict occurs.
@@ -25,48 +36,190 @@ var (
 type MergeConflictError struct {
 	Branch        string
 	Into          string
+	Strategy      MergeStrategy
 	ConflictFiles []string
 	ConflictDiff  string
 }
 
 func (e *MergeConflictError) Error() string {
+	if e.Strategy == MergeRebaseFF {
+		return fmt.Sprintf("%v: rebasing %s onto %s", ErrMergeConflict, e.Branch, e.Into)
+	}
 	return fmt.Sprintf("%v: merging %s into %s", ErrMergeConflict, e.Branch, e.Into)
 }
 
 func (e *MergeConflictError) Unwrap() error { return ErrMergeConflict }
 
-// validateID checks that id is safe for use as a path component and git argument.
-// Rejects empty, path traversal (/ \ . ..), and flag-like IDs (starting with -).
+// MergeStrategy selects how a capsule branch lands on the main branch.
+type MergeStrategy string
+
+const (
+	// MergeNoFF creates a merge commit with git merge --no-ff (default).
+	MergeNoFF MergeStrategy = "no-ff"
+	// MergeSquash collapses the branch into a single commit on main.
+	MergeSquash MergeStrategy = "squash"
+	// MergeRebaseFF rebases the branch onto main, then fast-forwards main.
+	MergeRebaseFF MergeStrategy = "rebase-ff"
+)
+
+// ParseMergeStrategy converts a config value to a MergeStrategy.
+// An empty string or "merge" selects MergeNoFF.
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch MergeStrategy(s) {
+	case "", "merge", MergeNoFF:
+		return MergeNoFF, nil
+	case MergeSquash, MergeRebaseFF:
+		return MergeStrategy(s), nil
+	default:
+		return "", fmt.Errorf("worktree: unknown merge strategy %q (must be no-ff, merge, squash, or rebase-ff)", s)
+	}
+}
+
+// maxNameLen caps the length of a sanitized worktree name, keeping paths and
+// branch names well within filesystem and ref limits.
+const maxNameLen = 64
+
+// validateID checks that id is non-empty. Any other ID is usable: SafeName
+// maps it to a name that is safe as a path component and git ref.
 func validateID(id string) error {
 	if id == "" {
 		return fmt.Errorf("%w: cannot be empty", ErrInvalidID)
 	}
-	if strings.HasPrefix(id, "-") {
-		return fmt.Errorf("%w: %q (must not start with -)", ErrInvalidID, id)
+	return nil
+}
+
+// SafeName maps a bead ID to a name usable as a directory and in a git
+// branch name. IDs made of ASCII letters, digits, '-', '_', and '.' that do
+// not start with '-' or '.' are returned unchanged. Any other ID has each
+// unsafe character replaced by '-', is truncated, and gets a short hash of
+// the original ID appended, so distinct IDs never share a name.
+func SafeName(id string) string {
+	if isSafeName(id) {
+		return id
+	}
+	var b strings.Builder
+	for _, r := range id {
+		if isSafeRune(r) && r != '.' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
 	}
-	if strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
-		return fmt.Errorf("%w: %q", ErrInvalidID, id)
+	base := strings.Trim(b.String(), "-")
+	if len(base) > maxNameLen-9 {
+		base = strings.TrimRight(base[:maxNameLen-9], "-")
 	}
-	return nil
+	sum := sha256.Sum256([]byte(id))
+	suffix := hex.EncodeToString(sum[:4])
+	if base == "" {
+		return suffix
+	}
+	return base + "-" + suffix
 }
 
-// Manager manages git worktrees under a base directory within a repository.
+// BranchName returns the capsule branch name for a bead ID.
+func BranchName(id string) string {
+	return "capsule-" + SafeName(id)
+}
+
+// isSafeName reports whether id can be used verbatim as a worktree name.
+func isSafeName(id string) bool {
+	if id == "" || len(id) > maxNameLen {
+		return false
+	}
+	if id[0] == '-' || id[0] == '.' || strings.HasSuffix(id, ".") ||
+		strings.HasSuffix(id, ".lock") || strings.Contains(id, "..") {
+		return false
+	}
+	for _, r := range id {
+		if !isSafeRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSafeRune reports whether r is an ASCII letter, digit, '-', '_', or '.'.
+func isSafeRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+		r == '-' || r == '_' || r == '.'
+}
+
+// isLegacyName reports whether id could have been used verbatim as a
+// worktree name before IDs were sanitized.
+func isLegacyName(id string) bool {
+	return id != "" && id != "." && id != ".." &&
+		!strings.HasPrefix(id, "-") && !strings.ContainsAny(id, `/\`)
+}
+
+// Manager manages git worktrees under a base directory, inside the
+// repository or anywhere else on its filesystem.
 type Manager struct {
-	repoRoot string
-	baseDir  string
+	repoRoot      string
+	dir           string // Absolute base directory.
+	relDir        string // Base directory relative to repoRoot in slash form; "" when outside it.
+	legacyDir     string // Absolute DefaultBaseDir, searched for worktrees created before base_dir moved.
+	dirTemplate   string // Worktree directory name template; "" uses DefaultDirTemplate.
+	now           func() time.Time
+	fsChecked     bool // checkSameFilesystem passed.
+	mergeStrategy MergeStrategy
+	mergeMessage  string // Merge commit message template; "" uses DefaultMergeMessageTemplate.
+	logger        *slog.Logger
+	removeBackoff []time.Duration // Waits before retrying a remove that hit a transient failure.
+
+	// mu serializes commands that write the shared repository (worktree
+	// add/remove/prune and merges), which concurrent campaign tasks would
+	// otherwise race on git's lock files.
+	mu sync.Mutex
 }
 
-// NewManager creates a Manager that manages worktrees under baseDir relative to repoRoot.
-func NewManager(repoRoot, baseDir string) *Manager {
-	return &Manager{
-		repoRoot: repoRoot,
-		baseDir:  baseDir,
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithMergeStrategy sets the strategy MergeToMain uses to land capsule branches.
+func WithMergeStrategy(s MergeStrategy) Option {
+	return func(m *Manager) {
+		if s != "" {
+			m.mergeStrategy = s
+		}
 	}
 }
 
+// NewManager creates a Manager that manages worktrees under baseDir. A
+// relative baseDir is resolved against repoRoot; a leading ~ is expanded to
+// the home directory.
+func NewManager(repoRoot, baseDir string, opts ...Option) *Manager {
+	dir := resolveBaseDir(repoRoot, baseDir)
+	m := &Manager{
+		repoRoot:      repoRoot,
+		dir:           dir,
+		relDir:        relBaseDir(repoRoot, dir),
+		legacyDir:     resolveBaseDir(repoRoot, DefaultBaseDir),
+		now:           time.Now,
+		mergeStrategy: MergeNoFF,
+		logger:        slog.New(slog.DiscardHandler),
+		removeBackoff: []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, time.Second, 2 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// MergeStrategy returns the strategy MergeToMain uses.
+func (m *Manager) MergeStrategy() MergeStrategy {
+	return m.mergeStrategy
+}
+
 // Create creates a new git worktree for the given ID, branching from baseBranch.
-// The worktree is placed at <repoRoot>/<baseDir>/<id>/ on branch capsule-<id>.
+// The worktree is placed in the base directory, in a directory named by the
+// dir template (<name> by default), on branch capsule-<name>, where name is
+// SafeName(id). Creation fails if a worktree directory or branch with that
+// name already exists, or with ErrCrossDevice if a base directory outside
+// the repository is on another filesystem.
 func (m *Manager) Create(id, baseBranch string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if err := validateID(id); err != nil {
 		return err
 	}
@@ -74,15 +227,20 @@ func (m *Manager) Create(id, baseBranch string) error {
 	if _, err := os.Stat(wtPath); err == nil {
 		return fmt.Errorf("worktree %q: %w", id, ErrAlreadyExists)
 	}
+	branchName := m.branchName(id)
+	if m.branchExists(branchName) {
+		return fmt.Errorf("worktree %q: branch %s: %w", id, branchName, ErrAlreadyExists)
+	}
 
 	parentDir := filepath.Dir(wtPath)
 	if err := os.MkdirAll(parentDir, 0o755); err != nil {
 		return fmt.Errorf("worktree: mkdir %s: %w", parentDir, err)
 	}
+	if err := m.checkSameFilesystem(parentDir); err != nil {
+		return err
+	}
 
-	branchName := "capsule-" + id
-	cmd := exec.Command("git", "worktree", "add", "-b", branchName, wtPath, baseBranch)
-	cmd.Dir = m.repoRoot
+	cmd := m.git(m.repoRoot, "worktree", "add", "-b", branchName, wtPath, baseBranch)
 	if out, err := cmd.CombinedOutput(); err != nil {
 		// Best-effort cleanup of partial directory.
 		_ = os.RemoveAll(wtPath)
@@ -94,73 +252,170 @@ func (m *Manager) Create(id, baseBranch string) error {
 
 // Remove removes the git worktree for the given ID using --force,
 // which discards any uncommitted changes in the worktree.
-// If deleteBranch is true, the capsule-<id> branch is also deleted.
+// If deleteBranch is true, the capsule branch is also deleted, but only
+// when its work has landed (see deleteBranch); otherwise the branch is kept
+// and an error wrapping ErrUnmerged is returned.
 func (m *Manager) Remove(id string, deleteBranch bool) error {
+	return m.remove(id, deleteBranch, false)
+}
+
+// RemoveForce removes the worktree for id like Remove and deletes its
+// capsule branch even when the branch holds work that never landed.
+func (m *Manager) RemoveForce(id string) error {
+	return m.remove(id, true, true)
+}
+
+func (m *Manager) remove(id string, deleteBranch, force bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if err := validateID(id); err != nil {
 		return err
 	}
+	// Resolve both names before removal: once the directory is gone a legacy
+	// worktree's branch name can no longer be detected.
 	wtPath := m.worktreePath(id)
+	branchName := m.branchName(id)
 	if _, err := os.Stat(wtPath); errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("worktree %q: %w", id, ErrNotFound)
+		// A crash or abort can leave the branch without its directory.
+		if !deleteBranch || !m.branchExists(branchName) {
+			return fmt.Errorf("worktree %q: %w", id, ErrNotFound)
+		}
+	} else if m.isRegistered(wtPath) {
+		if err := m.removeWorktree(wtPath); err != nil {
+			return err
+		}
+	} else if err := os.RemoveAll(wtPath); err != nil {
+		// Git does not track the directory, so git worktree remove would refuse it.
+		return fmt.Errorf("worktree: removing untracked directory %s: %w", wtPath, err)
+	}
+
+	if deleteBranch && m.branchExists(branchName) {
+		return m.deleteBranch(id, branchName, force)
 	}
 
-	cmd := exec.Command("git", "worktree", "remove", "--force", wtPath)
-	cmd.Dir = m.repoRoot
+	return nil
+}
+
+// deleteBranch deletes the capsule branch for id. git branch -d only deletes
+// a branch merged into HEAD, which a squash merge never is, so the branch is
+// also force-deleted when its work is on HEAD anyway: HEAD has the same
+// files, or a local branch has a squash commit with the bead's
+// Capsule-Bead trailer made no earlier than the branch's last commit.
+// Otherwise it returns ErrUnmerged unless force is set.
+func (m *Manager) deleteBranch(id, branchName string, force bool) error {
+	if !force {
+		if m.git(m.repoRoot, "branch", "-d", branchName).Run() == nil {
+			return nil
+		}
+		if !m.sameTreeAsHead(branchName) && !m.squashMerged(id, branchName) {
+			return fmt.Errorf("%w: %s has commits that were not merged", ErrUnmerged, branchName)
+		}
+	}
+	cmd := m.git(m.repoRoot, "branch", "-D", branchName)
 	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("worktree: git worktree remove: %w\n%s", err, strings.TrimSpace(string(out)))
+		return fmt.Errorf("worktree: git branch -D %s: %w\n%s", branchName, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// sameTreeAsHead reports whether branchName's files match HEAD's.
+func (m *Manager) sameTreeAsHead(branchName string) bool {
+	return m.git(m.repoRoot, "diff", "--quiet", "HEAD", branchName, "--").Run() == nil
+}
+
+// squashMerged reports whether a local branch other than branchName has a
+// squash commit for id (see squashMerge) at least as recent as
+// branchName's tip, so no commit on the branch came after the squash.
+func (m *Manager) squashMerged(id, branchName string) bool {
+	out, err := m.git(m.repoRoot, "log", "--exclude=refs/heads/"+branchName, "--branches",
+		"--fixed-strings", "--grep=Capsule-Bead: "+id, "--format=%ct%x00%B%x00").Output()
+	if err != nil {
+		return false
+	}
+	tipOut, err := m.git(m.repoRoot, "log", "-1", "--format=%ct", branchName).Output()
+	if err != nil {
+		return false
 	}
+	tip, err := strconv.ParseInt(strings.TrimSpace(string(tipOut)), 10, 64)
+	if err != nil {
+		return false
+	}
+	fields := strings.Split(string(out), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		if !slices.Contains(strings.Split(fields[i+1], "\n"), "Capsule-Bead: "+id) {
+			continue // --grep also matches longer IDs with the same prefix.
+		}
+		if at, err := strconv.ParseInt(strings.TrimSpace(fields[i]), 10, 64); err == nil && at >= tip {
+			return true
+		}
+	}
+	return false
+}
 
-	if deleteBranch {
-		branchName := "capsule-" + id
-		cmd := exec.Command("git", "branch", "-D", branchName)
-		cmd.Dir = m.repoRoot
-		if out, err := cmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("worktree: git branch -D %s: %w\n%s", branchName, err, strings.TrimSpace(string(out)))
+// removeWorktree runs git worktree remove, retrying with backoff while the
+// failure looks transient: on Windows a process that just exited, or an
+// antivirus scan, can hold files in the worktree open for a moment.
+func (m *Manager) removeWorktree(wtPath string) error {
+	for attempt := 0; ; attempt++ {
+		out, err := m.git(m.repoRoot, "worktree", "remove", "--force", wtPath).CombinedOutput()
+		if err == nil {
+			return nil
+		}
+		if attempt >= len(m.removeBackoff) || !transientRemoveFailure(string(out)) {
+			return fmt.Errorf("worktree: git worktree remove: %w\n%s", err, strings.TrimSpace(string(out)))
 		}
+		m.logger.Debug("retrying worktree remove", "path", wtPath, "attempt", attempt+1, "output", strings.TrimSpace(string(out)))
+		time.Sleep(m.removeBackoff[attempt])
 	}
+}
 
-	return nil
+// transientRemoveFailure reports whether git worktree remove output points
+// at files that were still in use rather than a lasting problem.
+func transientRemoveFailure(out string) bool {
+	out = strings.ToLower(out)
+	for _, s := range []string{
+		"permission denied",
+		"access is denied",
+		"directory not empty",
+		"being used by another process",
+		"device or resource busy",
+	} {
+		if strings.Contains(out, s) {
+			return true
+		}
+	}
+	return false
 }
 
 // Prune removes stale git worktree tracking entries whose directories
 // no longer exist. Call after bulk Remove operations or manual cleanup.
 func (m *Manager) Prune() error {
-	cmd := exec.Command("git", "worktree", "prune")
-	cmd.Dir = m.repoRoot
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cmd := m.git(m.repoRoot, "worktree", "prune")
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("worktree: git worktree prune: %w\n%s", err, strings.TrimSpace(string(out)))
 	}
 	return nil
 }
 
-// List returns the IDs of all worktrees managed under the base directory.
-// Only directories that are actual git worktrees are included; stale
-// directories left by failed operations are excluded.
-// The returned IDs are sorted alphabetically.
+// List returns the IDs of all capsule worktrees: every git worktree other
+// than the repository's own checkout that is on a capsule-<name> branch,
+// wherever its directory is. Stale directories left by failed operations
+// are excluded. The returned IDs are sorted alphabetically.
 func (m *Manager) List() ([]string, error) {
-	dir := filepath.Join(m.repoRoot, m.baseDir)
-	entries, err := os.ReadDir(dir)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return []string{}, nil
-		}
-		return nil, fmt.Errorf("worktree: reading %s: %w", dir, err)
-	}
-
-	registered, err := m.registeredWorktrees()
+	branches, err := m.registeredBranches()
 	if err != nil {
 		return nil, err
 	}
-
+	root := absPath(m.repoRoot)
 	ids := []string{}
-	for _, e := range entries {
-		if !e.IsDir() {
+	for branch, path := range branches {
+		name, ok := strings.CutPrefix(branch, "capsule-")
+		if !ok || name == "" || path == root {
 			continue
 		}
-		absPath := filepath.Join(dir, e.Name())
-		if registered[absPath] {
-			ids = append(ids, e.Name())
-		}
+		ids = append(ids, name)
 	}
 	sort.Strings(ids)
 	return ids, nil
@@ -169,20 +424,332 @@ func (m *Manager) List() ([]string, error) {
 // registeredWorktrees returns a set of absolute paths that git considers
 // active worktrees, parsed from "git worktree list --porcelain".
 func (m *Manager) registeredWorktrees() (map[string]bool, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = m.repoRoot
+	cmd := m.git(m.repoRoot, "worktree", "list", "--porcelain")
 	out, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("worktree: git worktree list: %w", err)
 	}
 
+	return parseWorktreeList(out), nil
+}
+
+// parseWorktreeList returns the worktree paths in "git worktree list
+// --porcelain" output as OS paths. Git prints forward slashes even on
+// Windows, so the paths are converted before they are compared with paths
+// built by filepath.Join.
+func parseWorktreeList(out []byte) map[string]bool {
 	registered := make(map[string]bool)
 	for _, line := range strings.Split(string(out), "\n") {
-		if strings.HasPrefix(line, "worktree ") {
-			registered[strings.TrimPrefix(line, "worktree ")] = true
+		line = strings.TrimSuffix(line, "\r")
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			registered[filepath.Clean(filepath.FromSlash(path))] = true
+		}
+	}
+	return registered
+}
+
+// StatusClean reports whether the repository root has no uncommitted changes,
+// returning the dirty paths when it does. Untracked files count as dirty
+// unless gitignored. Paths under .capsule/, the worktree base directory, and
+// .beads/ are skipped because capsule and bd write there themselves.
+func (m *Manager) StatusClean() (bool, []string, error) {
+	cmd := m.git(m.repoRoot, "status", "--porcelain", "-z")
+	out, err := cmd.Output()
+	if err != nil {
+		return false, nil, fmt.Errorf("worktree: git status: %w", err)
+	}
+
+	skip := []string{".capsule/", ".beads/"}
+	if m.relDir != "" {
+		skip = append(skip, m.relDir+"/")
+	}
+	var dirty []string
+	for _, path := range porcelainPaths(out) {
+		if hasAnyPrefix(path, skip) {
+			continue
 		}
+		dirty = append(dirty, path)
 	}
-	return registered, nil
+	return len(dirty) == 0, dirty, nil
+}
+
+// porcelainPaths extracts the paths from "git status --porcelain -z" output.
+func porcelainPaths(out []byte) []string {
+	var paths []string
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		// Renames and copies are followed by a separate original-path entry.
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++
+		}
+		paths = append(paths, entry[3:])
+	}
+	return paths
+}
+
+// OverlappingChanges reports files that other in-flight capsules have changed
+// relative to the main branch, keyed by worktree name (the bead ID for plain
+// IDs). A capsule's changes are the commits on its branch plus uncommitted
+// edits in its worktree. When the capsule for id already has changes of its
+// own, only the shared paths are reported; before it has any, every path
+// another capsule changed may conflict and is reported. Files capsule writes
+// itself (worklog.md, .capsule/, .beads/) are ignored. An empty map means no
+// overlap.
+func (m *Manager) OverlappingChanges(id string) (map[string][]string, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	names, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	overlaps := make(map[string][]string)
+	self := m.name(id)
+	if len(names) == 0 || (len(names) == 1 && names[0] == self) {
+		return overlaps, nil
+	}
+	mainBranch, err := m.DetectMainBranch()
+	if err != nil {
+		return nil, err
+	}
+
+	var own map[string]bool
+	if slices.Contains(names, self) {
+		files, err := m.changedFiles(self, mainBranch)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) > 0 {
+			own = make(map[string]bool, len(files))
+			for _, f := range files {
+				own[f] = true
+			}
+		}
+	}
+
+	for _, name := range names {
+		if name == self {
+			continue
+		}
+		files, err := m.changedFiles(name, mainBranch)
+		if err != nil {
+			return nil, err
+		}
+		if own != nil {
+			files = slices.DeleteFunc(files, func(f string) bool { return !own[f] })
+		}
+		if len(files) > 0 {
+			overlaps[name] = files
+		}
+	}
+	return overlaps, nil
+}
+
+// ChangedSince returns the sorted paths the worktree for id has changed
+// since it branched from base: commits on its branch (git diff
+// base...HEAD) plus uncommitted edits. Files capsule writes itself are
+// skipped, as in OverlappingChanges.
+func (m *Manager) ChangedSince(id, base string) ([]string, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	return m.changedFiles(m.name(id), base)
+}
+
+// CommitAll stages every change in the worktree for id and commits it on
+// the capsule branch with msg. Files capsule writes itself are left out, as
+// in ChangedSince. It reports false, without committing, when there is
+// nothing to commit.
+func (m *Manager) CommitAll(id, msg string) (bool, error) {
+	if err := validateID(id); err != nil {
+		return false, err
+	}
+	dir := m.worktreePath(id)
+
+	add := m.git(dir, "add", "-A", "--", ".", ":(exclude)worklog.md", ":(exclude).capsule", ":(exclude).beads")
+	if out, err := add.CombinedOutput(); err != nil {
+		return false, fmt.Errorf("worktree: git add in %s: %w\n%s", id, err, strings.TrimSpace(string(out)))
+	}
+
+	staged := m.git(dir, "diff", "--cached", "--quiet")
+	if staged.Run() == nil {
+		return false, nil
+	}
+
+	commit := m.git(dir, "commit", "-q", "-m", msg)
+	if out, err := commit.CombinedOutput(); err != nil {
+		return false, fmt.Errorf("worktree: git commit in %s: %w\n%s", id, err, strings.TrimSpace(string(out)))
+	}
+	return true, nil
+}
+
+// changedFiles returns the sorted paths the capsule in worktree name has
+// changed relative to mainBranch, skipping files capsule writes itself.
+func (m *Manager) changedFiles(name, mainBranch string) ([]string, error) {
+	diff := m.git(m.repoRoot, "diff", "--name-only", "-z", mainBranch+"...capsule-"+name)
+	committed, err := diff.Output()
+	if err != nil {
+		return nil, fmt.Errorf("worktree: git diff %s...capsule-%s: %w", mainBranch, name, err)
+	}
+	status := m.git(m.pathForName(name), "status", "--porcelain", "-z", "--untracked-files=all")
+	uncommitted, err := status.Output()
+	if err != nil {
+		return nil, fmt.Errorf("worktree: git status in %s: %w", name, err)
+	}
+
+	skip := []string{".capsule/", ".beads/"}
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string) {
+		if path == "" || path == "worklog.md" || hasAnyPrefix(path, skip) || seen[path] {
+			return
+		}
+		seen[path] = true
+		files = append(files, path)
+	}
+	for _, path := range strings.Split(string(committed), "\x00") {
+		add(path)
+	}
+	for _, path := range porcelainPaths(uncommitted) {
+		add(path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// hasAnyPrefix reports whether s starts with any of prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// State describes which parts of a bead's capsule worktree exist. A run
+// killed mid-pipeline, or an abort, can leave one part without the other.
+type State struct {
+	Dir        bool // The worktree directory exists.
+	Registered bool // Git tracks the directory as a worktree.
+	Branch     bool // The capsule branch exists.
+}
+
+// Exists reports whether any part of the worktree remains.
+func (s State) Exists() bool {
+	return s.Dir || s.Branch
+}
+
+// Resumable reports whether a run can continue on the capsule branch: in the
+// existing worktree, or in one re-attached with Attach when the directory is
+// gone.
+func (s State) Resumable() bool {
+	return s.Branch && (!s.Dir || s.Registered)
+}
+
+// String describes the state for error messages.
+func (s State) String() string {
+	switch {
+	case s.Dir && s.Registered && s.Branch:
+		return "worktree and branch exist"
+	case s.Dir && s.Branch:
+		return "branch exists but git does not track the worktree directory"
+	case s.Dir:
+		return "worktree directory exists but its branch was deleted"
+	case s.Branch:
+		return "branch exists but the worktree directory is gone"
+	default:
+		return "no worktree"
+	}
+}
+
+// State reports which parts of the worktree for id exist.
+func (m *Manager) State(id string) (State, error) {
+	if err := validateID(id); err != nil {
+		return State{}, err
+	}
+	var st State
+	wtPath := m.worktreePath(id)
+	if fi, err := os.Stat(wtPath); err == nil && fi.IsDir() {
+		st.Dir = true
+		registered, err := m.registeredWorktrees()
+		if err != nil {
+			return State{}, err
+		}
+		st.Registered = registered[absPath(wtPath)]
+	}
+	st.Branch = m.branchExists(m.branchName(id))
+	return st, nil
+}
+
+// BranchExists reports whether the capsule branch for id exists.
+func (m *Manager) BranchExists(id string) bool {
+	return validateID(id) == nil && m.branchExists(m.branchName(id))
+}
+
+// Attach re-creates the worktree directory for id on its existing capsule
+// branch, for a run that continues after the directory was removed. It fails
+// with ErrNoSuchBranch if the branch is gone and ErrAlreadyExists if the
+// directory is still there.
+func (m *Manager) Attach(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := validateID(id); err != nil {
+		return err
+	}
+	wtPath := m.worktreePath(id)
+	if _, err := os.Stat(wtPath); err == nil {
+		return fmt.Errorf("worktree %q: %w", id, ErrAlreadyExists)
+	}
+	branchName := m.branchName(id)
+	if !m.branchExists(branchName) {
+		return fmt.Errorf("worktree %q: branch %s: %w", id, branchName, ErrNoSuchBranch)
+	}
+
+	// Drop the stale registration of the removed directory, which would
+	// otherwise keep the branch marked as checked out.
+	if out, err := m.git(m.repoRoot, "worktree", "prune").CombinedOutput(); err != nil {
+		return fmt.Errorf("worktree: git worktree prune: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	if err := os.MkdirAll(filepath.Dir(wtPath), 0o755); err != nil {
+		return fmt.Errorf("worktree: mkdir %s: %w", filepath.Dir(wtPath), err)
+	}
+	if err := m.checkSameFilesystem(filepath.Dir(wtPath)); err != nil {
+		return err
+	}
+	cmd := m.git(m.repoRoot, "worktree", "add", wtPath, branchName)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(wtPath)
+		return fmt.Errorf("worktree: git worktree add: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// isRegistered reports whether git tracks wtPath as a worktree. A failed
+// lookup counts as registered so callers fall back to git's own checks.
+func (m *Manager) isRegistered(wtPath string) bool {
+	registered, err := m.registeredWorktrees()
+	if err != nil {
+		return true
+	}
+	return registered[absPath(wtPath)]
+}
+
+// absPath returns path made absolute with symlinks resolved, as git reports
+// worktree paths, or path unchanged if that fails.
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		return real
+	}
+	return abs
 }
 
 // Path returns the absolute path for a worktree with the given ID.
@@ -201,80 +768,208 @@ func (m *Manager) Exists(id string) bool {
 
 // worktreePath returns the absolute path for a worktree with the given ID.
 func (m *Manager) worktreePath(id string) string {
-	return filepath.Join(m.repoRoot, m.baseDir, id)
+	return m.pathForName(m.name(id))
+}
+
+// branchName returns the capsule branch for a worktree with the given ID.
+func (m *Manager) branchName(id string) string {
+	return "capsule-" + m.name(id)
+}
+
+// name resolves the on-disk name for id. Worktrees created before IDs were
+// sanitized used the raw ID; when such a directory exists it is used so older
+// worktrees can still be found and removed.
+func (m *Manager) name(id string) string {
+	safe := SafeName(id)
+	if safe != id && isLegacyName(id) {
+		for _, dir := range []string{m.dir, m.legacyDir} {
+			if fsutil.IsDir(filepath.Join(dir, id)) {
+				return id
+			}
+		}
+	}
+	return safe
+}
+
+// branchExists reports whether a local branch with the given name exists.
+func (m *Manager) branchExists(branch string) bool {
+	cmd := m.git(m.repoRoot, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return cmd.Run() == nil
+}
+
+// VerifyBranch checks that branch is a local branch capsules can start
+// from and merge back into, returning an error wrapping ErrNoSuchBranch
+// if it is not.
+func (m *Manager) VerifyBranch(branch string) error {
+	if !m.branchExists(branch) {
+		return fmt.Errorf("branch %q: %w", branch, ErrNoSuchBranch)
+	}
+	return nil
 }
 
-// MergeToMain merges the capsule-<id> branch into mainBranch with --no-ff.
-// Returns ErrMergeConflict if the merge encounters conflicts.
+// MergeToMain lands the capsule branch for id on mainBranch using the
+// configured MergeStrategy. Returns a *MergeConflictError (wrapping
+// ErrMergeConflict) if the strategy encounters conflicts.
 // On any failure, restores the previously checked-out branch.
 func (m *Manager) MergeToMain(id, mainBranch, commitMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if err := validateID(id); err != nil {
 		return err
 	}
 
 	// Remember current branch so we can restore on failure.
-	cur := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	cur.Dir = m.repoRoot
+	cur := m.git(m.repoRoot, "rev-parse", "--abbrev-ref", "HEAD")
 	curOut, err := cur.Output()
 	if err != nil {
 		return fmt.Errorf("worktree: detecting current branch: %w", err)
 	}
 	origBranch := strings.TrimSpace(string(curOut))
 
+	branchName := m.branchName(id)
+
+	// Rebase runs before checking out main: the capsule branch is checked
+	// out in its worktree, so that is where the rebase must happen.
+	if m.mergeStrategy == MergeRebaseFF {
+		if err := m.rebaseOnto(id, mainBranch); err != nil {
+			return err
+		}
+	}
+
 	// Checkout main branch.
-	checkout := exec.Command("git", "checkout", mainBranch, "-q")
-	checkout.Dir = m.repoRoot
+	checkout := m.git(m.repoRoot, "checkout", mainBranch, "-q")
 	if out, err := checkout.CombinedOutput(); err != nil {
 		return fmt.Errorf("worktree: git checkout %s: %w\n%s", mainBranch, err, strings.TrimSpace(string(out)))
 	}
 
-	// Merge with --no-ff.
-	branchName := "capsule-" + id
-	merge := exec.Command("git", "merge", "--no-ff", branchName, "-m", commitMsg)
-	merge.Dir = m.repoRoot
+	switch m.mergeStrategy {
+	case MergeSquash:
+		err = m.squashMerge(id, mainBranch, commitMsg)
+	case MergeRebaseFF:
+		err = m.fastForward(branchName)
+	default:
+		err = m.noFFMerge(branchName, mainBranch, commitMsg)
+	}
+	if err != nil {
+		m.restoreBranch(origBranch)
+		return err
+	}
+	return nil
+}
+
+// noFFMerge merges branchName into the checked-out main branch with --no-ff.
+func (m *Manager) noFFMerge(branchName, mainBranch, commitMsg string) error {
+	merge := m.git(m.repoRoot, "merge", "--no-ff", branchName, "-m", commitMsg)
+	out, mergeErr := merge.CombinedOutput()
+	if mergeErr == nil {
+		return nil
+	}
+	outStr := string(out)
+	if strings.Contains(outStr, "CONFLICT") {
+		// Capture conflict info before aborting.
+		conflictErr := m.conflictError(m.repoRoot, branchName, mainBranch, MergeNoFF)
+
+		abort := m.git(m.repoRoot, "merge", "--abort")
+		_ = abort.Run()
+		return conflictErr
+	}
+	return fmt.Errorf("worktree: git merge: %w\n%s", mergeErr, strings.TrimSpace(outStr))
+}
+
+// squashMerge stages the capsule branch as a single change on the checked-out
+// main branch and commits it with commitMsg plus a Capsule-Bead trailer.
+func (m *Manager) squashMerge(id, mainBranch, commitMsg string) error {
+	branchName := m.branchName(id)
+	merge := m.git(m.repoRoot, "merge", "--squash", branchName)
 	out, mergeErr := merge.CombinedOutput()
 	if mergeErr != nil {
 		outStr := string(out)
-		isConflict := strings.Contains(outStr, "CONFLICT")
-
-		// On conflict, capture conflict info before aborting.
-		if isConflict {
-			conflictFiles := m.captureConflictFiles()
-			conflictDiff := m.captureConflictDiff()
-
-			abort := exec.Command("git", "merge", "--abort")
-			abort.Dir = m.repoRoot
-			_ = abort.Run()
-
-			// Restore original branch.
-			restore := exec.Command("git", "checkout", origBranch, "-q")
-			restore.Dir = m.repoRoot
-			_ = restore.Run()
-
-			return &MergeConflictError{
-				Branch:        branchName,
-				Into:          mainBranch,
-				ConflictFiles: conflictFiles,
-				ConflictDiff:  conflictDiff,
-			}
+		if strings.Contains(outStr, "CONFLICT") {
+			conflictErr := m.conflictError(m.repoRoot, branchName, mainBranch, MergeSquash)
+
+			// A squash merge leaves no MERGE_HEAD, so --abort is unavailable.
+			reset := m.git(m.repoRoot, "reset", "--merge")
+			_ = reset.Run()
+			return conflictErr
 		}
+		return fmt.Errorf("worktree: git merge --squash: %w\n%s", mergeErr, strings.TrimSpace(outStr))
+	}
+
+	// Nothing staged means the branch carries no changes; there is nothing to commit.
+	staged := m.git(m.repoRoot, "diff", "--cached", "--quiet")
+	if staged.Run() == nil {
+		return nil
+	}
+
+	commit := m.git(m.repoRoot, "commit", "-q", "-m", commitMsg, "-m", "Capsule-Bead: "+id)
+	if out, err := commit.CombinedOutput(); err != nil {
+		return fmt.Errorf("worktree: git commit: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// rebaseOnto rebases the capsule branch for id onto mainBranch inside the
+// capsule worktree. On conflict the rebase is aborted, leaving the branch
+// unchanged.
+func (m *Manager) rebaseOnto(id, mainBranch string) error {
+	branchName := m.branchName(id)
+	dir := m.worktreePath(id)
+	args := []string{"rebase", mainBranch}
+	if _, err := os.Stat(dir); err != nil {
+		// No worktree: name the branch so git checks it out in repoRoot.
+		dir = m.repoRoot
+		args = append(args, branchName)
+	}
+
+	rebase := m.git(dir, args...)
+	out, rebaseErr := rebase.CombinedOutput()
+	if rebaseErr == nil {
+		return nil
+	}
+	outStr := string(out)
+	if strings.Contains(outStr, "CONFLICT") {
+		conflictErr := m.conflictError(dir, branchName, mainBranch, MergeRebaseFF)
 
-		// Restore original branch.
-		restore := exec.Command("git", "checkout", origBranch, "-q")
-		restore.Dir = m.repoRoot
-		_ = restore.Run()
-		return fmt.Errorf("worktree: git merge: %w\n%s", mergeErr, strings.TrimSpace(outStr))
+		abort := m.git(dir, "rebase", "--abort")
+		_ = abort.Run()
+		return conflictErr
+	}
+	return fmt.Errorf("worktree: git rebase %s: %w\n%s", mainBranch, rebaseErr, strings.TrimSpace(outStr))
+}
+
+// fastForward advances the checked-out main branch to branchName.
+func (m *Manager) fastForward(branchName string) error {
+	merge := m.git(m.repoRoot, "merge", "--ff-only", branchName)
+	if out, err := merge.CombinedOutput(); err != nil {
+		return fmt.Errorf("worktree: git merge --ff-only %s: %w\n%s", branchName, err, strings.TrimSpace(string(out)))
 	}
 	return nil
 }
 
+// conflictError builds a MergeConflictError from the conflict state in dir.
+// Must be called while the conflict is active (before aborting).
+func (m *Manager) conflictError(dir, branchName, mainBranch string, strategy MergeStrategy) *MergeConflictError {
+	return &MergeConflictError{
+		Branch:        branchName,
+		Into:          mainBranch,
+		Strategy:      strategy,
+		ConflictFiles: m.captureConflictFiles(dir),
+		ConflictDiff:  m.captureConflictDiff(dir),
+	}
+}
+
+// restoreBranch checks out branch in repoRoot (best-effort).
+func (m *Manager) restoreBranch(branch string) {
+	restore := m.git(m.repoRoot, "checkout", branch, "-q")
+	_ = restore.Run()
+}
+
 // DetectMainBranch determines the main branch name.
 // Checks git symbolic-ref refs/remotes/origin/HEAD first,
 // then falls back to checking if "main" or "master" branches exist.
 func (m *Manager) DetectMainBranch() (string, error) {
 	// Try origin/HEAD.
-	cmd := exec.Command("git", "symbolic-ref", "refs/remotes/origin/HEAD")
-	cmd.Dir = m.repoRoot
+	cmd := m.git(m.repoRoot, "symbolic-ref", "refs/remotes/origin/HEAD")
 	if out, err := cmd.Output(); err == nil {
 		ref := strings.TrimSpace(string(out))
 		// refs/remotes/origin/main → main
@@ -285,15 +980,13 @@ func (m *Manager) DetectMainBranch() (string, error) {
 	}
 
 	// Fallback: check if "main" branch exists.
-	cmd = exec.Command("git", "rev-parse", "--verify", "refs/heads/main")
-	cmd.Dir = m.repoRoot
+	cmd = m.git(m.repoRoot, "rev-parse", "--verify", "refs/heads/main")
 	if err := cmd.Run(); err == nil {
 		return "main", nil
 	}
 
 	// Fallback: check if "master" branch exists.
-	cmd = exec.Command("git", "rev-parse", "--verify", "refs/heads/master")
-	cmd.Dir = m.repoRoot
+	cmd = m.git(m.repoRoot, "rev-parse", "--verify", "refs/heads/master")
 	if err := cmd.Run(); err == nil {
 		return "master", nil
 	}
@@ -301,12 +994,11 @@ func (m *Manager) DetectMainBranch() (string, error) {
 	return "", errors.New("worktree: cannot detect main branch")
 }
 
-// captureConflictFiles returns files with merge conflicts in repoRoot.
+// captureConflictFiles returns files with merge conflicts in dir.
 // Must be called while a merge conflict is active (before --abort).
 // Returns nil on any error (best-effort).
-func (m *Manager) captureConflictFiles() []string {
-	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=U")
-	cmd.Dir = m.repoRoot
+func (m *Manager) captureConflictFiles(dir string) []string {
+	cmd := m.git(dir, "diff", "--name-only", "--diff-filter=U")
 	out, err := cmd.Output()
 	if err != nil {
 		return nil
@@ -318,12 +1010,11 @@ func (m *Manager) captureConflictFiles() []string {
 	return lines
 }
 
-// captureConflictDiff returns the diff for conflicted files in repoRoot.
+// captureConflictDiff returns the diff for conflicted files in dir.
 // Must be called while a merge conflict is active (before --abort).
 // Returns empty string on any error (best-effort).
-func (m *Manager) captureConflictDiff() string {
-	cmd := exec.Command("git", "diff", "--diff-filter=U")
-	cmd.Dir = m.repoRoot
+func (m *Manager) captureConflictDiff(dir string) string {
+	cmd := m.git(dir, "diff", "--diff-filter=U")
 	out, err := cmd.Output()
 	if err != nil {
 		return ""
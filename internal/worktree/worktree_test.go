@@ -2,12 +2,16 @@ package worktree
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 // initGitRepo creates a bare-minimum git repo in dir with one commit.
@@ -65,16 +69,24 @@ func TestCreate(t *testing.T) {
 			wantErr:    ErrInvalidID,
 		},
 		{
-			name:       "rejects path traversal",
+			name:       "sanitizes path traversal",
 			id:         "../escape",
 			baseBranch: "HEAD",
-			wantErr:    ErrInvalidID,
 		},
 		{
-			name:       "rejects flag-like id",
+			name:       "sanitizes flag-like id",
 			id:         "--version",
 			baseBranch: "HEAD",
-			wantErr:    ErrInvalidID,
+		},
+		{
+			name:       "sanitizes id with slash and colon",
+			id:         "jira:PROJ/123",
+			baseBranch: "HEAD",
+		},
+		{
+			name:       "sanitizes id with spaces and unicode",
+			id:         "fix café bug",
+			baseBranch: "HEAD",
 		},
 	}
 
@@ -106,14 +118,17 @@ func TestCreate(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			// Then directory exists at expected path
-			wtPath := filepath.Join(repoDir, baseDir, tt.id)
+			// Then directory exists directly under baseDir at the safe name
+			wtPath := filepath.Join(repoDir, baseDir, SafeName(tt.id))
 			if _, err := os.Stat(wtPath); errors.Is(err, os.ErrNotExist) {
 				t.Errorf("worktree dir does not exist: %s", wtPath)
 			}
+			if m.Path(tt.id) != wtPath {
+				t.Errorf("Path(%q) = %q, want %q", tt.id, m.Path(tt.id), wtPath)
+			}
 
-			// Then git branch capsule-<id> exists
-			branchName := "capsule-" + tt.id
+			// Then git branch capsule-<name> exists
+			branchName := BranchName(tt.id)
 			cmd := exec.Command("git", "branch", "--list", branchName)
 			cmd.Dir = repoDir
 			out, err := cmd.Output()
@@ -161,6 +176,40 @@ func TestRemove(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:         "removes branch left without its directory",
+			id:           "task-1",
+			deleteBranch: true,
+			setup: func(t *testing.T, m *Manager) {
+				t.Helper()
+				mustCreate(t, m, "task-1")
+				if err := m.Remove("task-1", false); err != nil {
+					t.Fatalf("setup Remove: %v", err)
+				}
+			},
+		},
+		{
+			name:         "removes directory git does not track",
+			id:           "task-1",
+			deleteBranch: true,
+			setup: func(t *testing.T, m *Manager) {
+				t.Helper()
+				writeFile(t, filepath.Join(m.Path("task-1"), "partial.txt"), "left by a crash")
+			},
+		},
+		{
+			name:         "branch without directory is not found when keeping branches",
+			id:           "task-1",
+			deleteBranch: false,
+			setup: func(t *testing.T, m *Manager) {
+				t.Helper()
+				mustCreate(t, m, "task-1")
+				if err := m.Remove("task-1", false); err != nil {
+					t.Fatalf("setup Remove: %v", err)
+				}
+			},
+			wantErr: ErrNotFound,
+		},
 		{
 			name:    "not found error",
 			id:      "nonexistent",
@@ -221,6 +270,125 @@ func TestRemove(t *testing.T) {
 	}
 }
 
+func TestRemove_UnmergedBranchGuard(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	// commit writes name in dir and commits it, dated at unix time when
+	// it is not zero.
+	commit := func(t *testing.T, dir, name string, when int64) {
+		t.Helper()
+		writeFile(t, filepath.Join(dir, name), name+"\n")
+		for _, args := range [][]string{{"add", name}, {"commit", "-q", "-m", "add " + name}} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1")
+			if when != 0 {
+				date := fmt.Sprintf("@%d +0000", when)
+				cmd.Env = append(cmd.Env, "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+			}
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		setup    func(t *testing.T, repoDir string, m *Manager)
+		force    bool
+		wantErr  error
+	}{
+		{
+			name: "merged branch is deleted",
+			setup: func(t *testing.T, repoDir string, m *Manager) {
+				commit(t, m.Path("task-1"), "work.txt", 0)
+				if err := m.MergeToMain("task-1", "main", "done"); err != nil {
+					t.Fatalf("MergeToMain: %v", err)
+				}
+			},
+		},
+		{
+			name:     "squash-merged branch is deleted",
+			strategy: MergeSquash,
+			setup: func(t *testing.T, repoDir string, m *Manager) {
+				commit(t, m.Path("task-1"), "work.txt", 0)
+				commit(t, repoDir, "main.txt", 0)
+				if err := m.MergeToMain("task-1", "main", "done"); err != nil {
+					t.Fatalf("MergeToMain: %v", err)
+				}
+			},
+		},
+		{
+			name: "branch with HEAD's files is deleted",
+			setup: func(t *testing.T, repoDir string, m *Manager) {
+				commit(t, m.Path("task-1"), "work.txt", 0)
+				commit(t, repoDir, "work.txt", 0)
+			},
+		},
+		{
+			name:     "work committed after a squash merge is kept",
+			strategy: MergeSquash,
+			setup: func(t *testing.T, repoDir string, m *Manager) {
+				commit(t, m.Path("task-1"), "work.txt", 0)
+				commit(t, repoDir, "main.txt", 0)
+				if err := m.MergeToMain("task-1", "main", "done"); err != nil {
+					t.Fatalf("MergeToMain: %v", err)
+				}
+				commit(t, m.Path("task-1"), "more.txt", time.Now().Add(time.Hour).Unix())
+			},
+			wantErr: ErrUnmerged,
+		},
+		{
+			name: "unmerged branch is kept",
+			setup: func(t *testing.T, repoDir string, m *Manager) {
+				commit(t, m.Path("task-1"), "work.txt", 0)
+			},
+			wantErr: ErrUnmerged,
+		},
+		{
+			name: "unmerged branch is deleted with force",
+			setup: func(t *testing.T, repoDir string, m *Manager) {
+				commit(t, m.Path("task-1"), "work.txt", 0)
+			},
+			force: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a capsule branch with committed work
+			repoDir := t.TempDir()
+			initGitRepo(t, repoDir)
+			m := NewManager(repoDir, ".capsule/worktrees", WithMergeStrategy(tt.strategy))
+			mustCreate(t, m, "task-1")
+			tt.setup(t, repoDir, m)
+
+			// When the worktree and branch are removed
+			var err error
+			if tt.force {
+				err = m.RemoveForce("task-1")
+			} else {
+				err = m.Remove("task-1", true)
+			}
+
+			// Then the worktree is gone either way, and the branch only when
+			// its work has landed or removal was forced
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Remove() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, statErr := os.Stat(m.Path("task-1")); !errors.Is(statErr, os.ErrNotExist) {
+				t.Errorf("worktree still exists: %v", statErr)
+			}
+			if got, want := m.BranchExists("task-1"), tt.wantErr != nil; got != want {
+				t.Errorf("branch exists = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 func TestList(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping git worktree test in short mode")
@@ -354,6 +522,65 @@ func TestPath(t *testing.T) {
 	}
 }
 
+func TestPath_UsesOSSeparator(t *testing.T) {
+	// Given a base directory written with forward slashes, as in config
+	m := NewManager(filepath.FromSlash("/repo"), ".capsule/worktrees")
+
+	// When Path is called
+	got := m.Path("task-1")
+
+	// Then every separator is the OS separator
+	want := filepath.FromSlash("/repo/.capsule/worktrees/task-1")
+	if got != want {
+		t.Errorf("Path() = %q, want %q", got, want)
+	}
+	if filepath.Separator != '/' && strings.Contains(got, "/") {
+		t.Errorf("Path() = %q contains forward slashes on %c-separated OS", got, filepath.Separator)
+	}
+}
+
+func TestParseWorktreeList(t *testing.T) {
+	// Given porcelain output as git prints it, with forward slashes and,
+	// on Windows, CRLF line endings
+	out := []byte("worktree /repo\r\nHEAD abc\r\nbranch refs/heads/main\r\n\r\n" +
+		"worktree /repo/.capsule/worktrees/task-1\r\nHEAD def\r\n")
+
+	// When it is parsed
+	got := parseWorktreeList(out)
+
+	// Then the paths match those built with filepath.Join
+	for _, want := range []string{
+		filepath.Join(filepath.FromSlash("/repo")),
+		filepath.Join(filepath.FromSlash("/repo"), ".capsule/worktrees", "task-1"),
+	} {
+		if !got[want] {
+			t.Errorf("registered = %v, missing %q", got, want)
+		}
+	}
+	if len(got) != 2 {
+		t.Errorf("registered = %v, want 2 paths", got)
+	}
+}
+
+func TestTransientRemoveFailure(t *testing.T) {
+	tests := []struct {
+		out  string
+		want bool
+	}{
+		{"error: failed to delete 'C:/repo/.capsule/worktrees/t': Permission denied", true},
+		{"fatal: unable to remove: Access is denied.", true},
+		{"error: failed to delete '.capsule/worktrees/t': Directory not empty", true},
+		{"The process cannot access the file because it is being used by another process.", true},
+		{"fatal: '.capsule/worktrees/t' is not a working tree", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := transientRemoveFailure(tt.out); got != tt.want {
+			t.Errorf("transientRemoveFailure(%q) = %v, want %v", tt.out, got, tt.want)
+		}
+	}
+}
+
 func TestMergeToMain(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping git worktree test in short mode")
@@ -520,6 +747,192 @@ func TestMergeToMain_Conflict(t *testing.T) {
 	}
 }
 
+// setupDivergedBranch creates a worktree for id whose branch and main both
+// commit to path. When conflict is true both sides edit the same file.
+func setupDivergedBranch(t *testing.T, repoDir string, m *Manager, id string, conflict bool) {
+	t.Helper()
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "HOME="+repoDir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s\n%s", args, err, out)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(filepath.Join(repoDir, "shared.txt"), "base\n")
+	run(repoDir, "add", "shared.txt")
+	run(repoDir, "commit", "-m", "add shared file")
+
+	if err := m.Create(id, "HEAD"); err != nil {
+		t.Fatalf("setup Create: %v", err)
+	}
+	wtPath := m.Path(id)
+	write(filepath.Join(wtPath, "shared.txt"), "branch\n")
+	write(filepath.Join(wtPath, "branch.txt"), "one\n")
+	run(wtPath, "add", ".")
+	run(wtPath, "commit", "-m", "branch commit 1")
+	write(filepath.Join(wtPath, "branch.txt"), "two\n")
+	run(wtPath, "add", ".")
+	run(wtPath, "commit", "-m", "branch commit 2")
+
+	mainFile := "main.txt"
+	if conflict {
+		mainFile = "shared.txt"
+	}
+	write(filepath.Join(repoDir, mainFile), "main\n")
+	run(repoDir, "add", mainFile)
+	run(repoDir, "commit", "-m", "main commit")
+}
+
+// gitOutput runs git in dir and returns trimmed stdout.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestMergeToMain_Strategies(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	tests := []struct {
+		name        string
+		strategy    MergeStrategy
+		wantParents int    // Parent count of main's tip commit.
+		wantSubject string // Subject of main's tip commit.
+		wantBody    string // Substring expected in main's tip commit body.
+	}{
+		{name: "no-ff creates merge commit", strategy: MergeNoFF, wantParents: 2, wantSubject: "pipeline done"},
+		{name: "squash creates single commit with trailer", strategy: MergeSquash, wantParents: 1, wantSubject: "pipeline done", wantBody: "Capsule-Bead: task-s"},
+		{name: "rebase-ff fast-forwards rebased branch", strategy: MergeRebaseFF, wantParents: 1, wantSubject: "branch commit 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a capsule branch and main that have diverged without conflict
+			repoDir := t.TempDir()
+			initGitRepo(t, repoDir)
+			m := NewManager(repoDir, ".capsule/worktrees", WithMergeStrategy(tt.strategy))
+			setupDivergedBranch(t, repoDir, m, "task-s", false)
+
+			// When merging to main
+			if err := m.MergeToMain("task-s", "main", "pipeline done"); err != nil {
+				t.Fatalf("MergeToMain() error = %v", err)
+			}
+
+			// Then main's tip has the expected shape
+			parents := strings.Fields(gitOutput(t, repoDir, "log", "-1", "--format=%P", "main"))
+			if len(parents) != tt.wantParents {
+				t.Errorf("tip parents = %d, want %d", len(parents), tt.wantParents)
+			}
+			if got := gitOutput(t, repoDir, "log", "-1", "--format=%s", "main"); got != tt.wantSubject {
+				t.Errorf("tip subject = %q, want %q", got, tt.wantSubject)
+			}
+			if tt.wantBody != "" {
+				if got := gitOutput(t, repoDir, "log", "-1", "--format=%B", "main"); !strings.Contains(got, tt.wantBody) {
+					t.Errorf("tip body = %q, want substring %q", got, tt.wantBody)
+				}
+			}
+			// And main contains both sides' changes
+			for _, f := range []string{"main.txt", "branch.txt", "shared.txt"} {
+				if _, err := os.Stat(filepath.Join(repoDir, f)); err != nil {
+					t.Errorf("expected %s on main: %v", f, err)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeToMain_StrategyConflict(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	for _, strategy := range []MergeStrategy{MergeSquash, MergeRebaseFF} {
+		t.Run(string(strategy), func(t *testing.T) {
+			// Given a capsule branch and main that edit the same file
+			repoDir := t.TempDir()
+			initGitRepo(t, repoDir)
+			m := NewManager(repoDir, ".capsule/worktrees", WithMergeStrategy(strategy))
+			setupDivergedBranch(t, repoDir, m, "task-c", true)
+			mainBefore := gitOutput(t, repoDir, "rev-parse", "main")
+			branchBefore := gitOutput(t, repoDir, "rev-parse", "capsule-task-c")
+
+			// When merging to main
+			err := m.MergeToMain("task-c", "main", "should conflict")
+
+			// Then a MergeConflictError tagged with the strategy is returned
+			var mce *MergeConflictError
+			if !errors.As(err, &mce) {
+				t.Fatalf("expected *MergeConflictError, got %v", err)
+			}
+			if !errors.Is(err, ErrMergeConflict) {
+				t.Errorf("error should wrap ErrMergeConflict")
+			}
+			if mce.Strategy != strategy {
+				t.Errorf("Strategy = %q, want %q", mce.Strategy, strategy)
+			}
+			if !slices.Contains(mce.ConflictFiles, "shared.txt") {
+				t.Errorf("ConflictFiles = %v, want shared.txt", mce.ConflictFiles)
+			}
+
+			// And neither branch moved, and no operation is left in progress
+			if got := gitOutput(t, repoDir, "rev-parse", "main"); got != mainBefore {
+				t.Errorf("main moved from %s to %s", mainBefore, got)
+			}
+			if got := gitOutput(t, repoDir, "rev-parse", "capsule-task-c"); got != branchBefore {
+				t.Errorf("capsule branch moved from %s to %s", branchBefore, got)
+			}
+			if got := gitOutput(t, repoDir, "status", "--porcelain", "--untracked-files=no"); got != "" {
+				t.Errorf("repo not clean after conflict: %q", got)
+			}
+			if got := gitOutput(t, m.Path("task-c"), "status", "--porcelain", "--untracked-files=no"); got != "" {
+				t.Errorf("worktree not clean after conflict: %q", got)
+			}
+		})
+	}
+}
+
+func TestParseMergeStrategy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    MergeStrategy
+		wantErr bool
+	}{
+		{input: "", want: MergeNoFF},
+		{input: "no-ff", want: MergeNoFF},
+		{input: "merge", want: MergeNoFF},
+		{input: "squash", want: MergeSquash},
+		{input: "rebase-ff", want: MergeRebaseFF},
+		{input: "octopus", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMergeStrategy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDetectMainBranch(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping git worktree test in short mode")
@@ -539,6 +952,39 @@ func TestDetectMainBranch(t *testing.T) {
 	}
 }
 
+func TestVerifyBranch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	repoDir := t.TempDir()
+	initGitRepo(t, repoDir)
+	m := NewManager(repoDir, ".capsule/worktrees")
+
+	tests := []struct {
+		branch  string
+		wantErr bool
+	}{
+		{branch: "main", wantErr: false},
+		{branch: "develop", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			// Given a repo whose only branch is main
+			// When the branch is verified
+			err := m.VerifyBranch(tt.branch)
+
+			// Then only an existing branch passes
+			if tt.wantErr != errors.Is(err, ErrNoSuchBranch) {
+				t.Errorf("VerifyBranch(%q) = %v, want ErrNoSuchBranch: %v", tt.branch, err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("VerifyBranch(%q) = %v, want nil", tt.branch, err)
+			}
+		})
+	}
+}
+
 func TestExists(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping git worktree test in short mode")
@@ -589,3 +1035,504 @@ func TestExists(t *testing.T) {
 		})
 	}
 }
+
+func TestState(t *testing.T) {
+	tests := []struct {
+		name          string
+		setup         func(t *testing.T, m *Manager)
+		want          State
+		wantResumable bool
+	}{
+		{name: "nothing", want: State{}},
+		{
+			name:          "worktree and branch",
+			setup:         func(t *testing.T, m *Manager) { mustCreate(t, m, "task-1") },
+			want:          State{Dir: true, Registered: true, Branch: true},
+			wantResumable: true,
+		},
+		{
+			name: "branch without directory",
+			setup: func(t *testing.T, m *Manager) {
+				mustCreate(t, m, "task-1")
+				if err := os.RemoveAll(m.Path("task-1")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:          State{Branch: true},
+			wantResumable: true,
+		},
+		{
+			name: "directory without branch",
+			setup: func(t *testing.T, m *Manager) {
+				writeFile(t, filepath.Join(m.Path("task-1"), "partial.txt"), "left by a crash")
+			},
+			want: State{Dir: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a repo in which a run left some of its worktree behind
+			repoDir := t.TempDir()
+			initGitRepo(t, repoDir)
+			m := NewManager(repoDir, ".capsule/worktrees")
+			if tt.setup != nil {
+				tt.setup(t, m)
+			}
+
+			// When its state is queried
+			got, err := m.State("task-1")
+			if err != nil {
+				t.Fatalf("State: %v", err)
+			}
+
+			// Then each part is reported, and only a surviving branch is resumable
+			if got != tt.want {
+				t.Errorf("State = %+v, want %+v", got, tt.want)
+			}
+			if got.Resumable() != tt.wantResumable {
+				t.Errorf("Resumable = %v, want %v", got.Resumable(), tt.wantResumable)
+			}
+			if got.Exists() != (tt.want != State{}) {
+				t.Errorf("Exists = %v for %+v", got.Exists(), got)
+			}
+		})
+	}
+}
+
+func TestAttach(t *testing.T) {
+	// Given a worktree with a commit whose directory was deleted by a crash
+	repoDir := t.TempDir()
+	initGitRepo(t, repoDir)
+	m := NewManager(repoDir, ".capsule/worktrees")
+	mustCreate(t, m, "task-1")
+	writeFile(t, filepath.Join(m.Path("task-1"), "work.txt"), "done so far")
+	gitOutput(t, m.Path("task-1"), "add", "work.txt")
+	gitOutput(t, m.Path("task-1"), "commit", "-q", "-m", "work")
+	if err := os.RemoveAll(m.Path("task-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// When the worktree is re-attached
+	if err := m.Attach("task-1"); err != nil {
+		t.Fatalf("Attach: %v", err)
+	}
+
+	// Then the branch is checked out again with its committed work
+	if got := strings.TrimSpace(gitOutput(t, m.Path("task-1"), "rev-parse", "--abbrev-ref", "HEAD")); got != "capsule-task-1" {
+		t.Errorf("checked out %q, want capsule-task-1", got)
+	}
+	if _, err := os.Stat(filepath.Join(m.Path("task-1"), "work.txt")); err != nil {
+		t.Errorf("committed work missing: %v", err)
+	}
+
+	// And attaching again, or a bead without a branch, fails
+	if err := m.Attach("task-1"); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("second Attach error = %v, want ErrAlreadyExists", err)
+	}
+	if err := m.Attach("task-2"); !errors.Is(err, ErrNoSuchBranch) {
+		t.Errorf("Attach without branch error = %v, want ErrNoSuchBranch", err)
+	}
+}
+
+func TestStatusClean(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git status test in short mode")
+	}
+
+	tests := []struct {
+		name      string
+		setup     func(t *testing.T, dir string, m *Manager)
+		wantClean bool
+		wantPaths []string
+	}{
+		{
+			name:      "fresh repo is clean",
+			wantClean: true,
+		},
+		{
+			name: "modified tracked file is dirty",
+			setup: func(t *testing.T, dir string, _ *Manager) {
+				writeFile(t, filepath.Join(dir, "a.txt"), "one\n")
+				gitOutput(t, dir, "add", "a.txt")
+				gitOutput(t, dir, "commit", "-m", "add a")
+				writeFile(t, filepath.Join(dir, "a.txt"), "two\n")
+			},
+			wantPaths: []string{"a.txt"},
+		},
+		{
+			name: "untracked file is dirty",
+			setup: func(t *testing.T, dir string, _ *Manager) {
+				writeFile(t, filepath.Join(dir, "new.txt"), "x\n")
+			},
+			wantPaths: []string{"new.txt"},
+		},
+		{
+			name: "gitignored file is clean",
+			setup: func(t *testing.T, dir string, _ *Manager) {
+				writeFile(t, filepath.Join(dir, ".gitignore"), "*.log\n")
+				gitOutput(t, dir, "add", ".gitignore")
+				gitOutput(t, dir, "commit", "-m", "ignore logs")
+				writeFile(t, filepath.Join(dir, "debug.log"), "x\n")
+			},
+			wantClean: true,
+		},
+		{
+			name: "capsule worktrees, logs, and bead data are clean",
+			setup: func(t *testing.T, dir string, m *Manager) {
+				if err := m.Create("task-1", "HEAD"); err != nil {
+					t.Fatalf("Create: %v", err)
+				}
+				writeFile(t, filepath.Join(dir, ".capsule", "logs", "task-1", "worklog.md"), "x\n")
+				writeFile(t, filepath.Join(dir, ".beads", "issues.jsonl"), "{}\n")
+			},
+			wantClean: true,
+		},
+		{
+			name: "renamed file reports new path",
+			setup: func(t *testing.T, dir string, _ *Manager) {
+				writeFile(t, filepath.Join(dir, "old.txt"), "x\n")
+				gitOutput(t, dir, "add", "old.txt")
+				gitOutput(t, dir, "commit", "-m", "add old")
+				gitOutput(t, dir, "mv", "old.txt", "new name.txt")
+			},
+			wantPaths: []string{"new name.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a repository in the described state
+			dir := t.TempDir()
+			initGitRepo(t, dir)
+			m := NewManager(dir, ".capsule/worktrees")
+			if tt.setup != nil {
+				tt.setup(t, dir, m)
+			}
+
+			// When StatusClean is called
+			clean, paths, err := m.StatusClean()
+
+			// Then the clean flag and dirty paths match
+			if err != nil {
+				t.Fatalf("StatusClean() error = %v", err)
+			}
+			if clean != tt.wantClean {
+				t.Errorf("clean = %v, want %v (paths %q)", clean, tt.wantClean, paths)
+			}
+			if !slices.Equal(paths, tt.wantPaths) {
+				t.Errorf("paths = %q, want %q", paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+// writeFile writes content to path, creating parent directories.
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeName(t *testing.T) {
+	long := strings.Repeat("a", 120)
+
+	tests := []struct {
+		name      string
+		id        string
+		want      string // exact result; empty means the sanitized form is checked
+		wantTrunc bool
+	}{
+		{name: "plain id unchanged", id: "cap-9f0", want: "cap-9f0"},
+		{name: "dotted child id unchanged", id: "cap-9f0.1", want: "cap-9f0.1"},
+		{name: "slash", id: "team/cap-1"},
+		{name: "colon", id: "jira:PROJ-123"},
+		{name: "spaces", id: "fix the bug"},
+		{name: "unicode", id: "café-ñ-日本"},
+		{name: "path traversal", id: "../escape"},
+		{name: "leading dash", id: "--version"},
+		{name: "only unsafe characters", id: "日本"},
+		{name: "over 100 characters", id: long, wantTrunc: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When SafeName is called
+			got := SafeName(tt.id)
+
+			// Then the result is stable
+			if again := SafeName(tt.id); again != got {
+				t.Errorf("SafeName(%q) not deterministic: %q then %q", tt.id, got, again)
+			}
+			if tt.want != "" {
+				if got != tt.want {
+					t.Errorf("SafeName(%q) = %q, want %q", tt.id, got, tt.want)
+				}
+				return
+			}
+
+			// Then the result is a safe name distinct from the raw id
+			if got == tt.id {
+				t.Errorf("SafeName(%q) returned the id unchanged", tt.id)
+			}
+			if !isSafeName(got) {
+				t.Errorf("SafeName(%q) = %q, which is not safe", tt.id, got)
+			}
+			if tt.wantTrunc && len(got) > maxNameLen {
+				t.Errorf("SafeName(%q) has length %d, want <= %d", tt.id, len(got), maxNameLen)
+			}
+
+			// Then the name is a valid git branch component
+			cmd := exec.Command("git", "check-ref-format", "--branch", "capsule-"+got)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("capsule-%s is not a valid branch: %v\n%s", got, err, out)
+			}
+		})
+	}
+}
+
+func TestSafeName_DistinctIDs(t *testing.T) {
+	// Given IDs that sanitize to the same characters
+	ids := []string{"a/b", "a:b", "a b", "a-b", "a.b", strings.Repeat("x", 100) + "1", strings.Repeat("x", 100) + "2"}
+
+	// When each is mapped
+	seen := make(map[string]string)
+	for _, id := range ids {
+		name := SafeName(id)
+		// Then no two IDs share a name
+		if prev, ok := seen[name]; ok {
+			t.Errorf("SafeName(%q) and SafeName(%q) both = %q", prev, id, name)
+		}
+		seen[name] = id
+	}
+}
+
+func TestLegacyWorktreeName(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	// Given a worktree created under the raw ID before sanitization
+	repoDir := t.TempDir()
+	initGitRepo(t, repoDir)
+	baseDir := ".capsule/worktrees"
+	m := NewManager(repoDir, baseDir)
+	id := "café-1"
+	legacyPath := filepath.Join(repoDir, baseDir, id)
+	cmd := exec.Command("git", "worktree", "add", "-b", "capsule-"+id, legacyPath, "HEAD")
+	cmd.Dir = repoDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("git rejects legacy branch name: %v\n%s", err, out)
+	}
+
+	// When Exists and Path are called with the raw ID
+	// Then the legacy worktree is found
+	if !m.Exists(id) {
+		t.Fatalf("Exists(%q) = false, want true", id)
+	}
+	if got := m.Path(id); got != legacyPath {
+		t.Errorf("Path(%q) = %q, want %q", id, got, legacyPath)
+	}
+
+	// When Remove is called
+	if err := m.Remove(id, true); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	// Then the directory and branch are gone
+	if _, err := os.Stat(legacyPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("legacy worktree still exists: %v", err)
+	}
+	if m.branchExists("capsule-" + id) {
+		t.Errorf("legacy branch still exists")
+	}
+}
+
+func TestOverlappingChanges(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	// commitIn writes and commits files in a worktree.
+	commitIn := func(t *testing.T, dir string, files ...string) {
+		t.Helper()
+		for _, f := range files {
+			writeFile(t, filepath.Join(dir, f), f+"\n")
+		}
+		gitOutput(t, dir, append([]string{"add"}, files...)...)
+		gitOutput(t, dir, "commit", "-m", "work")
+	}
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, m *Manager)
+		want  map[string][]string
+	}{
+		{
+			name: "no other capsules",
+			want: map[string][]string{},
+		},
+		{
+			name: "other capsule without changes",
+			setup: func(t *testing.T, m *Manager) {
+				mustCreate(t, m, "other")
+				writeFile(t, filepath.Join(m.Path("other"), "worklog.md"), "log\n")
+			},
+			want: map[string][]string{},
+		},
+		{
+			name: "committed and uncommitted changes before own changes",
+			setup: func(t *testing.T, m *Manager) {
+				mustCreate(t, m, "other")
+				commitIn(t, m.Path("other"), "src/a.go")
+				writeFile(t, filepath.Join(m.Path("other"), "src", "b.go"), "b\n")
+			},
+			want: map[string][]string{"other": {"src/a.go", "src/b.go"}},
+		},
+		{
+			name: "only shared paths once own changes exist",
+			setup: func(t *testing.T, m *Manager) {
+				mustCreate(t, m, "other")
+				commitIn(t, m.Path("other"), "src/a.go", "src/b.go")
+				mustCreate(t, m, "third")
+				commitIn(t, m.Path("third"), "docs/readme.md")
+				mustCreate(t, m, "task-1")
+				writeFile(t, filepath.Join(m.Path("task-1"), "src", "b.go"), "mine\n")
+			},
+			want: map[string][]string{"other": {"src/b.go"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a repository with in-flight capsules in the described state
+			dir := t.TempDir()
+			initGitRepo(t, dir)
+			m := NewManager(dir, ".capsule/worktrees")
+			if tt.setup != nil {
+				tt.setup(t, m)
+			}
+
+			// When overlaps are checked for task-1
+			got, err := m.OverlappingChanges("task-1")
+
+			// Then the other capsules' overlapping paths are reported
+			if err != nil {
+				t.Fatalf("OverlappingChanges() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OverlappingChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangedSince(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	// Given a worktree with a committed file, an uncommitted file, and a worklog
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	m := NewManager(dir, ".capsule/worktrees")
+	mustCreate(t, m, "task-1")
+	wt := m.Path("task-1")
+	writeFile(t, filepath.Join(wt, "docs", "guide.md"), "guide\n")
+	gitOutput(t, wt, "add", "docs/guide.md")
+	gitOutput(t, wt, "commit", "-m", "docs")
+	writeFile(t, filepath.Join(wt, "main.go"), "package main\n")
+	writeFile(t, filepath.Join(wt, "worklog.md"), "log\n")
+
+	// When the changes since main are listed
+	got, err := m.ChangedSince("task-1", "main")
+
+	// Then both changes are reported without capsule's own files
+	if err != nil {
+		t.Fatalf("ChangedSince() error = %v", err)
+	}
+	if want := []string{"docs/guide.md", "main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedSince() = %v, want %v", got, want)
+	}
+}
+
+func TestCommitAll(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	// Given a worktree with a new source file and a worklog
+	dir := t.TempDir()
+	initGitRepo(t, dir)
+	m := NewManager(dir, ".capsule/worktrees")
+	mustCreate(t, m, "task-1")
+	wt := m.Path("task-1")
+	writeFile(t, filepath.Join(wt, "main.go"), "package main\n")
+	writeFile(t, filepath.Join(wt, "worklog.md"), "log\n")
+
+	// When the worktree's changes are committed
+	committed, err := m.CommitAll("task-1", "task-1 [execute]: add main")
+
+	// Then the source file is committed on the capsule branch without the worklog
+	if err != nil || !committed {
+		t.Fatalf("CommitAll() = %v, %v, want true, nil", committed, err)
+	}
+	if got := gitOutput(t, wt, "log", "-1", "--format=%s"); got != "task-1 [execute]: add main" {
+		t.Errorf("last commit subject = %q", got)
+	}
+	if got := gitOutput(t, wt, "show", "--name-only", "--format=", "HEAD"); got != "main.go" {
+		t.Errorf("committed files = %q, want main.go", got)
+	}
+
+	// When there is nothing left to commit
+	committed, err = m.CommitAll("task-1", "task-1 [sign-off]: approved")
+
+	// Then no commit is made
+	if err != nil || committed {
+		t.Errorf("CommitAll() with no changes = %v, %v, want false, nil", committed, err)
+	}
+	if got := gitOutput(t, wt, "rev-list", "--count", "main..HEAD"); got != "1" {
+		t.Errorf("commits on branch = %s, want 1", got)
+	}
+}
+
+// mustCreate creates a worktree for id from main.
+func mustCreate(t *testing.T, m *Manager, id string) {
+	t.Helper()
+	if err := m.Create(id, "main"); err != nil {
+		t.Fatalf("Create(%q): %v", id, err)
+	}
+}
+
+func TestWithLogger_LogsGitCommands(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping git worktree test in short mode")
+	}
+
+	// Given a manager with a logger
+	repoDir := t.TempDir()
+	initGitRepo(t, repoDir)
+	var buf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	m := NewManager(repoDir, ".capsule/worktrees", WithLogger(logger))
+
+	// When Create checks for the branch (exits 1) and adds the worktree (exits 0)
+	if err := m.Create("task-1", "HEAD"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Then each command is logged with its exit code
+	logs := buf.String()
+	for _, want := range []string{
+		`args="rev-parse --verify --quiet refs/heads/capsule-task-1" dir=` + repoDir + " exit_code=1",
+		`args="worktree add -b capsule-task-1`,
+		"exit_code=0",
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("logs missing %q:\n%s", want, logs)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package capsule
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	"github.com/smileynet/capsule/internal/gate"
+	"github.com/smileynet/capsule/internal/orchestrator"
+	"github.com/smileynet/capsule/internal/prompt"
+	"github.com/smileynet/capsule/internal/provider"
+	"github.com/smileynet/capsule/internal/worklog"
+)
+
+// The types below are the stable public API for embedding capsule in other
+// Go programs. They alias the internal types the CLI itself uses, so values
+// pass between the two without conversion. Fields and methods may be added
+// in minor releases; existing ones are not removed or changed. Interfaces
+// that callers implement, such as CampaignCallback, gain new events only
+// through optional interfaces like BreakerTripObserver, so existing
+// implementations keep compiling. Everything under internal/ remains free
+// to change.
+
+// Pipeline types.
+type (
+	// PhaseDefinition describes a single pipeline phase.
+	PhaseDefinition = orchestrator.PhaseDefinition
+	// PhaseResolver resolves the phase set a PipelineInput names in Phases.
+	PhaseResolver = orchestrator.PhaseResolver
+	// PhaseKind distinguishes workers, reviewers, and gates.
+	PhaseKind = orchestrator.PhaseKind
+	// PhaseStatus is the state of a phase execution in a StatusUpdate.
+	PhaseStatus = orchestrator.PhaseStatus
+	// PipelineInput provides the context needed to run a pipeline.
+	PipelineInput = orchestrator.PipelineInput
+	// PipelineOutput is the result of running a pipeline.
+	PipelineOutput = orchestrator.PipelineOutput
+	// PhaseResult records the outcome of a single phase attempt.
+	PhaseResult = orchestrator.PhaseResult
+	// PhasePlan describes how a phase would run, for Pipeline.Plan.
+	PhasePlan = orchestrator.PhasePlan
+	// PipelineError indicates a pipeline failure with phase context.
+	PipelineError = orchestrator.PipelineError
+	// RunTimeoutError indicates the pipeline exceeded WithRunTimeout.
+	RunTimeoutError = orchestrator.RunTimeoutError
+	// RetryStrategy holds retry settings for a phase.
+	RetryStrategy = orchestrator.RetryStrategy
+	// StatusUpdate carries progress for a single phase execution.
+	StatusUpdate = orchestrator.StatusUpdate
+	// StatusCallback receives phase progress updates.
+	StatusCallback = orchestrator.StatusCallback
+	// ConflictResolutionInput holds the context for Pipeline.ResolveConflicts.
+	ConflictResolutionInput = orchestrator.ConflictResolutionInput
+	// PregateMode selects whether a run checks its gates before any phase.
+	PregateMode = orchestrator.PregateMode
+	// PipelineCheckpoint holds the state of a pipeline for pause and resume.
+	PipelineCheckpoint = orchestrator.PipelineCheckpoint
+	// BeadContext carries bead details into the worklog and prompts.
+	BeadContext = worklog.BeadContext
+	// WorklogEntry is a phase entry appended to a worklog.
+	WorklogEntry = worklog.PhaseEntry
+	// RunSummary is the machine-readable summary of a pipeline run.
+	RunSummary = worklog.RunSummary
+	// PromptContext is the data a PromptLoader interpolates into a prompt.
+	PromptContext = prompt.Context
+	// SiblingContext summarizes a completed sibling task for cross-run context.
+	SiblingContext = prompt.SiblingContext
+)
+
+// Provider types.
+type (
+	// Signal is the structured verdict a phase reports.
+	Signal = provider.Signal
+	// SignalStatus is a Signal's verdict: PASS, NEEDS_WORK, ERROR, or SKIP.
+	SignalStatus = provider.Status
+	// Finding is an issue a phase reports in its Signal.
+	Finding = provider.Finding
+	// Usage counts the tokens a provider call consumed.
+	Usage = provider.Usage
+	// Result is the raw output of a provider call.
+	Result = provider.Result
+)
+
+// Extension points. Implement these to replace the defaults.
+type (
+	// Provider executes AI completions against a configured backend.
+	Provider = orchestrator.Provider
+	// StreamingProvider is a Provider that reports progress while a phase runs.
+	StreamingProvider = orchestrator.StreamingProvider
+	// ProviderFactory builds a provider by name for capsule:provider bead labels.
+	ProviderFactory = orchestrator.ProviderFactory
+	// GateRunner executes shell commands as gate phases.
+	GateRunner = orchestrator.GateRunner
+	// PromptLoader composes prompts for pipeline phases.
+	PromptLoader = orchestrator.PromptLoader
+	// WorktreeManager manages git worktrees for pipeline isolation.
+	WorktreeManager = orchestrator.WorktreeManager
+	// WorklogManager tracks phase execution in a worklog.
+	WorklogManager = orchestrator.WorklogManager
+	// SummaryWriter records a RunSummary after every run.
+	SummaryWriter = orchestrator.SummaryWriter
+	// CheckpointStore persists pipeline state for pause and resume.
+	CheckpointStore = orchestrator.CheckpointStore
+	// OverlapChecker reports files other in-flight capsules have changed.
+	OverlapChecker = orchestrator.OverlapChecker
+	// ChangeLister reports the paths a bead's worktree has changed.
+	ChangeLister = orchestrator.ChangeLister
+	// WorktreeAttacher re-attaches a resumed bead's worktree to its surviving branch.
+	WorktreeAttacher = orchestrator.WorktreeAttacher
+	// PhaseCommitter commits a bead's worktree for WithPerPhaseCommits.
+	PhaseCommitter = orchestrator.PhaseCommitter
+)
+
+// Phase kinds.
+const (
+	Worker   = orchestrator.Worker
+	Reviewer = orchestrator.Reviewer
+	Gate     = orchestrator.Gate
+)
+
+// Phase statuses reported in a StatusUpdate.
+const (
+	PhasePending  = orchestrator.PhasePending
+	PhaseRunning  = orchestrator.PhaseRunning
+	PhasePassed   = orchestrator.PhasePassed
+	PhaseFailed   = orchestrator.PhaseFailed
+	PhaseError    = orchestrator.PhaseError
+	PhaseSkipped  = orchestrator.PhaseSkipped
+	PhaseProgress = orchestrator.PhaseProgress
+	PhaseRetrying = orchestrator.PhaseRetrying
+)
+
+// Pregate modes for WithPregate.
+const (
+	PregateOff  = orchestrator.PregateOff
+	PregateFail = orchestrator.PregateFail
+	PregateWarn = orchestrator.PregateWarn
+)
+
+// BaselinePhase is the Phase of StatusUpdates for WithPregate's checks.
+const BaselinePhase = orchestrator.BaselinePhase
+
+// Signal statuses.
+const (
+	StatusPass      = provider.StatusPass
+	StatusNeedsWork = provider.StatusNeedsWork
+	StatusError     = provider.StatusError
+	StatusSkip      = provider.StatusSkip
+)
+
+// Pipeline errors, for use with errors.Is.
+var (
+	ErrPipelinePaused     = orchestrator.ErrPipelinePaused
+	ErrBudgetExceeded     = orchestrator.ErrBudgetExceeded
+	ErrGateFailed         = orchestrator.ErrGateFailed
+	ErrOverlappingChanges = orchestrator.ErrOverlappingChanges
+	ErrBaselineFailed     = orchestrator.ErrBaselineFailed
+)
+
+// DefaultPhases returns the standard 6-phase pipeline in execution order.
+func DefaultPhases() []PhaseDefinition {
+	return orchestrator.DefaultPhases()
+}
+
+// NewPromptLoader returns a PromptLoader that reads <phase>.md templates
+// from fsys. Pass Prompts for the built-in templates, or OverlayFS to let
+// files on disk override them.
+func NewPromptLoader(fsys fs.FS) PromptLoader {
+	return prompt.NewLoader(fsys)
+}
+
+// NewGateRunner returns the GateRunner the CLI uses, which runs gate
+// commands through the shell and keeps the last maxOutput bytes of a failed
+// command's output as feedback (0 uses 8 KiB).
+func NewGateRunner(maxOutput int) GateRunner {
+	return gate.NewRunner(gate.WithMaxOutput(maxOutput))
+}
+
+// Option configures a Pipeline.
+type Option = orchestrator.Option
+
+// WithPromptLoader sets the prompt loader. A Pipeline needs one to run any
+// worker or reviewer phase.
+func WithPromptLoader(l PromptLoader) Option { return orchestrator.WithPromptLoader(l) }
+
+// WithWorktreeManager sets the worktree manager. Without one, phases run in
+// the current directory.
+func WithWorktreeManager(m WorktreeManager) Option { return orchestrator.WithWorktreeManager(m) }
+
+// WithWorklogManager sets the worklog manager.
+func WithWorklogManager(m WorklogManager) Option { return orchestrator.WithWorklogManager(m) }
+
+// WithSummaryWriter records a RunSummary after every run. Write failures
+// are logged, not returned.
+func WithSummaryWriter(w SummaryWriter) Option { return orchestrator.WithSummaryWriter(w) }
+
+// WithPhases overrides DefaultPhases.
+func WithPhases(phases []PhaseDefinition) Option { return orchestrator.WithPhases(phases) }
+
+// WithPhaseResolver sets how PipelineInput.Phases is resolved. The default
+// takes a preset name or a phases file and applies no overrides.
+func WithPhaseResolver(r PhaseResolver) Option { return orchestrator.WithPhaseResolver(r) }
+
+// WithStatusCallback sets the callback for progress updates.
+func WithStatusCallback(cb StatusCallback) Option { return orchestrator.WithStatusCallback(cb) }
+
+// WithGateRunner sets the gate runner for gate phases.
+func WithGateRunner(r GateRunner) Option { return orchestrator.WithGateRunner(r) }
+
+// WithRetryDefaults sets the pipeline-wide retry defaults.
+func WithRetryDefaults(rs RetryStrategy) Option { return orchestrator.WithRetryDefaults(rs) }
+
+// WithBaseBranch sets the branch worktrees are created from. The default is main.
+func WithBaseBranch(branch string) Option { return orchestrator.WithBaseBranch(branch) }
+
+// WithProviders registers named providers for PhaseDefinition.Provider.
+func WithProviders(providers map[string]Provider) Option {
+	return orchestrator.WithProviders(providers)
+}
+
+// WithProviderFactory builds providers named by capsule:provider bead labels.
+func WithProviderFactory(f ProviderFactory, defaultTimeout time.Duration) Option {
+	return orchestrator.WithProviderFactory(f, defaultTimeout)
+}
+
+// WithLogDir sets the directory for per-bead debug artifacts.
+func WithLogDir(dir string) Option { return orchestrator.WithLogDir(dir) }
+
+// WithTranscriptDir saves each phase's prompt and raw output, or a gate's
+// command and output, under <dir>/<bead-id>/transcripts/.
+func WithTranscriptDir(dir string) Option { return orchestrator.WithTranscriptDir(dir) }
+
+// WithLogger sets the logger. The default discards logs.
+func WithLogger(l *slog.Logger) Option { return orchestrator.WithLogger(l) }
+
+// WithCheckpointStore enables checkpointing after each phase.
+func WithCheckpointStore(s CheckpointStore) Option { return orchestrator.WithCheckpointStore(s) }
+
+// WithPauseRequested stops the pipeline between phases with
+// ErrPipelinePaused once fn returns true.
+func WithPauseRequested(fn func() bool) Option { return orchestrator.WithPauseRequested(fn) }
+
+// WithOverlapCheck warns about, or with strict set fails on, files other
+// in-flight capsules have changed.
+func WithOverlapCheck(c OverlapChecker, strict bool) Option {
+	return orchestrator.WithOverlapCheck(c, strict)
+}
+
+// WithChangeLister sets the source of changed paths for diff_match conditions.
+func WithChangeLister(c ChangeLister) Option { return orchestrator.WithChangeLister(c) }
+
+// WithContextFiles exposes the named repo-relative files to prompt
+// templates, each truncated to maxBytes (0 uses 16 KiB).
+func WithContextFiles(paths []string, maxBytes int) Option {
+	return orchestrator.WithContextFiles(paths, maxBytes)
+}
+
+// WithWorkdirs maps bead ID prefixes to worktree-relative directories.
+func WithWorkdirs(prefixes map[string]string) Option { return orchestrator.WithWorkdirs(prefixes) }
+
+// WithPhaseTimeout bounds each phase that doesn't set its own Timeout.
+func WithPhaseTimeout(d time.Duration) Option { return orchestrator.WithPhaseTimeout(d) }
+
+// WithRunTimeout bounds each Run, retries included. Zero disables it.
+func WithRunTimeout(d time.Duration) Option { return orchestrator.WithRunTimeout(d) }
+
+// WithMaxProviderCalls limits each Run to n provider calls. Zero disables it.
+func WithMaxProviderCalls(n int) Option { return orchestrator.WithMaxProviderCalls(n) }
+
+// WithPregate runs every gate once in each fresh worktree before the first
+// phase. PregateFail aborts when a required gate already fails;
+// PregateWarn gives the failures to the first worker instead.
+func WithPregate(mode PregateMode) Option { return orchestrator.WithPregate(mode) }
+
+// WithPerPhaseCommits commits the worktree after each phase that passes.
+// The WorktreeManager must implement PhaseCommitter.
+func WithPerPhaseCommits(enabled bool) Option { return orchestrator.WithPerPhaseCommits(enabled) }
+
+// WithTransientRetries retries provider calls that fail on a rate limit,
+// overload, or dropped connection up to n times, backing off exponentially
+// up to maxBackoff. Zero n disables it.
+func WithTransientRetries(n int, maxBackoff time.Duration) Option {
+	return orchestrator.WithTransientRetries(n, maxBackoff)
+}
+
+// Pipeline runs a bead through its phases: workers produce changes,
+// reviewers check them and send work back, and gates run shell commands.
+// A Pipeline is safe for concurrent Runs of different beads.
+type Pipeline struct {
+	orch *orchestrator.Orchestrator
+}
+
+// NewPipeline returns a Pipeline that calls p for every phase without its
+// own provider.
+func NewPipeline(p Provider, opts ...Option) *Pipeline {
+	return &Pipeline{orch: orchestrator.New(p, opts...)}
+}
+
+// Run executes the pipeline for input.BeadID. On failure the error is
+// usually a *PipelineError, and the output still holds the results of
+// every phase that ran.
+func (p *Pipeline) Run(ctx context.Context, input PipelineInput) (PipelineOutput, error) {
+	return p.orch.RunPipeline(ctx, input)
+}
+
+// RunPipeline is Run under the name CampaignPipeline requires, so a
+// *Pipeline can drive a Campaign.
+func (p *Pipeline) RunPipeline(ctx context.Context, input PipelineInput) (PipelineOutput, error) {
+	return p.Run(ctx, input)
+}
+
+// Plan reports what Run would do for input without touching the
+// repository or calling a provider.
+func (p *Pipeline) Plan(ctx context.Context, input PipelineInput) ([]PhasePlan, error) {
+	return p.orch.PlanPipeline(ctx, input)
+}
+
+// ResolveConflicts runs the execute and sign-off phases against a merge
+// conflict in the bead's worktree, returning nil once they resolve it.
+func (p *Pipeline) ResolveConflicts(ctx context.Context, input ConflictResolutionInput) error {
+	return p.orch.RunConflictResolution(ctx, input)
+}
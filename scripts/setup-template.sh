@@ -65,6 +65,11 @@ fi
 # --- Copy template files ---
 cp "$TEMPLATE_DIR/AGENTS.md" "$TARGET_DIR/AGENTS.md"
 [ -d "$TEMPLATE_DIR/src" ] && cp -r "$TEMPLATE_DIR/src" "$TARGET_DIR/src"
+# Templates embedded in the binary keep go.mod under another name, since a
+# go.mod would make src a separate module that go:embed skips.
+if [ -f "$TARGET_DIR/src/go.mod.template" ]; then
+    mv "$TARGET_DIR/src/go.mod.template" "$TARGET_DIR/src/go.mod"
+fi
 [ -f "$TEMPLATE_DIR/README.md" ] && cp "$TEMPLATE_DIR/README.md" "$TARGET_DIR/README.md"
 
 if [ -f "$TEMPLATE_DIR/capsule.yaml" ]; then
@@ -72,6 +77,12 @@ if [ -f "$TEMPLATE_DIR/capsule.yaml" ]; then
     cp "$TEMPLATE_DIR/capsule.yaml" "$TARGET_DIR/.capsule/config.yaml"
 fi
 
+# Offline responses for `--provider scripted` (default runtime.script path).
+if [ -f "$TEMPLATE_DIR/scripted.yaml" ]; then
+    mkdir -p "$TARGET_DIR/.capsule"
+    cp "$TEMPLATE_DIR/scripted.yaml" "$TARGET_DIR/.capsule/scripted.yaml"
+fi
+
 # --- Initialize beads and import fixtures ---
 (
     cd "$TARGET_DIR"
@@ -28,12 +28,13 @@ else
   fail "templates/demo-brownfield/ or src/ missing"
 fi
 
-# Test 2: go.mod exists with module declaration
+# Test 2: go.mod exists with module declaration (as go.mod.template, so the
+# template can be embedded in the binary)
 echo "[2/6] go.mod validity"
 # Given: the template src/ directory
 # When: checking for go.mod with module declaration
 # Then: file exists and contains a module line
-if [ -f "$TEMPLATE_DIR/src/go.mod" ] && grep -q "^module " "$TEMPLATE_DIR/src/go.mod"; then
+if [ -f "$TEMPLATE_DIR/src/go.mod.template" ] && grep -q "^module " "$TEMPLATE_DIR/src/go.mod.template"; then
   pass "go.mod exists with module declaration"
 else
   fail "go.mod missing or no module declaration"
@@ -94,7 +95,7 @@ echo "[E1] Module path safety"
 # Given: the template go.mod file
 # When: checking the module path
 # Then: uses example.com (safe namespace)
-if grep -q "example.com/" "$TEMPLATE_DIR/src/go.mod" 2>/dev/null; then
+if grep -q "example.com/" "$TEMPLATE_DIR/src/go.mod.template" 2>/dev/null; then
   pass "Module path uses example.com (safe namespace)"
 else
   fail "Module path may conflict with real modules"